@@ -8,21 +8,39 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"scriberr/internal/api"
+	"scriberr/internal/audio"
 	"scriberr/internal/auth"
+	"scriberr/internal/batch"
+	"scriberr/internal/companion"
 	"scriberr/internal/config"
 	"scriberr/internal/database"
+	"scriberr/internal/documents"
 	"scriberr/internal/embeddings"
+	"scriberr/internal/joblog"
 	"scriberr/internal/llm"
+	"scriberr/internal/llmstats"
+	"scriberr/internal/mailin"
+	"scriberr/internal/notify"
+	"scriberr/internal/podcasts"
 	"scriberr/internal/queue"
 	"scriberr/internal/rag"
+	"scriberr/internal/resummarize"
+	"scriberr/internal/scheduler"
+	"scriberr/internal/speakers"
+	"scriberr/internal/storage"
+	"scriberr/internal/tagging"
+	"scriberr/internal/topics"
+	"scriberr/internal/tracing"
 	"scriberr/internal/transcription"
 	"scriberr/internal/transcription/adapters"
 	"scriberr/internal/transcription/registry"
 	"scriberr/internal/vectordb"
+	"scriberr/internal/workflow"
 	"scriberr/pkg/logger"
 
 	_ "scriberr/api-docs" // Import generated Swagger docs
@@ -79,6 +97,30 @@ func main() {
 	logger.Startup("config", "Loading configuration")
 	cfg := config.Load()
 
+	// Export OpenTelemetry traces of the transcription and RAG pipeline, if a collector is configured
+	if cfg.OTLPEndpoint != "" {
+		headers, err := tracing.ParseHeaders(cfg.OTLPHeaders)
+		if err != nil {
+			logger.Error("Invalid OTEL_EXPORTER_OTLP_HEADERS", "error", err)
+			os.Exit(1)
+		}
+		if err := tracing.Init(tracing.Config{
+			Endpoint:    cfg.OTLPEndpoint,
+			Headers:     headers,
+			ServiceName: cfg.TracingServiceName,
+			SampleRatio: cfg.TracingSampleRatio,
+		}); err != nil {
+			logger.Error("Failed to set up tracing", "error", err)
+			os.Exit(1)
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			tracing.Shutdown(ctx)
+		}()
+		logger.Info("Tracing enabled", "endpoint", cfg.OTLPEndpoint, "sample_ratio", cfg.TracingSampleRatio)
+	}
+
 	// Register adapters with config-based paths
 	registerAdapters(cfg)
 
@@ -90,6 +132,17 @@ func main() {
 	}
 	defer database.Close()
 
+	// Keep the lines logged about each job, for its processing log
+	jobLogs := joblog.NewStore(cfg.JobLogMaxEntries)
+	jobLogs.Start()
+	defer jobLogs.Stop()
+
+	if migrated, err := tagging.MigrateJobTags(); err != nil {
+		logger.Warn("Failed to migrate transcription tags", "error", err)
+	} else if migrated > 0 {
+		logger.Info("Migrated transcription tags to the tags table", "transcriptions", migrated)
+	}
+
 	// Initialize authentication service
 	logger.Startup("auth", "Setting up authentication")
 	authService := auth.NewAuthService(cfg.JWTSecret)
@@ -105,6 +158,49 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Keep audio files and transcripts in object storage when configured
+	var files *storage.Files
+	if cfg.StorageBackend == "s3" {
+		store, err := storage.NewS3Store(storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			PathStyle:       cfg.S3PathStyle,
+		})
+		if err != nil {
+			logger.Error("Failed to configure object storage", "error", err)
+			os.Exit(1)
+		}
+		files = storage.NewFiles(store, cfg.S3Prefix)
+		unifiedProcessor.GetUnifiedService().SetFileStore(files)
+		logger.Info("Object storage enabled", "endpoint", cfg.S3Endpoint, "bucket", cfg.S3Bucket)
+	} else if cfg.StorageBackend != "local" {
+		logger.Error("Unknown storage backend", "backend", cfg.StorageBackend)
+		os.Exit(1)
+	}
+
+	// Preprocess audio before transcription as AUDIO_PREPROCESS says, unless a profile chooses otherwise
+	preprocessSteps, err := audio.ParsePreprocessSteps(cfg.AudioPreprocess)
+	if err != nil {
+		logger.Error("Invalid AUDIO_PREPROCESS", "error", err)
+		os.Exit(1)
+	}
+	unifiedProcessor.GetUnifiedService().SetDefaultPreprocessing(preprocessSteps)
+	unifiedProcessor.GetUnifiedService().SetSilenceDetection(cfg.SilenceThresholdDb, cfg.SkipSilenceMinSeconds)
+	if cfg.SpeakerIdentification {
+		unifiedProcessor.GetUnifiedService().SetSpeakerIdentifier(speakers.NewMatcher(cfg.SpeakerMatchThreshold))
+	}
+	if cfg.LanguageDetection {
+		languageRoutes, err := transcription.ParseLanguageModels(cfg.LanguageModels)
+		if err != nil {
+			logger.Error("Invalid LANGUAGE_MODELS", "error", err)
+			os.Exit(1)
+		}
+		unifiedProcessor.GetUnifiedService().SetLanguageDetection(cfg.LanguageOverrideConfidence, languageRoutes)
+	}
+
 	// Initialize quick transcription service
 	logger.Startup("quick-transcription", "Initializing quick transcription service")
 	quickTranscriptionService, err := transcription.NewQuickTranscriptionService(cfg, unifiedProcessor)
@@ -115,30 +211,193 @@ func main() {
 
 	// Initialize task queue
 	logger.Startup("queue", "Starting background processing")
-	taskQueue := queue.NewTaskQueue(2, unifiedProcessor) // 2 workers
+	taskQueue := queue.NewTaskQueue(cfg.QueueWorkers, unifiedProcessor)
 	taskQueue.Start()
 	defer taskQueue.Stop()
 
+	// Requeue or fail jobs whose worker died or hung
+	watchdog := queue.NewWatchdog(taskQueue, queue.WatchdogConfig{
+		HeartbeatTimeout: time.Duration(cfg.StuckHeartbeatSeconds) * time.Second,
+		DurationMultiple: cfg.StuckJobMultiple,
+		MinDuration:      time.Duration(cfg.StuckJobMinMinutes) * time.Minute,
+		Action:           cfg.StuckJobAction,
+		MaxRequeues:      cfg.StuckJobMaxRequeues,
+	}, notify.NewWebhookNotifier(cfg.NotifyWebhookURL))
+	watchdog.Start(queue.WatchdogInterval)
+	defer watchdog.Stop()
+
 	// Initialize RAG services
 	var ragService *rag.RAGService
-	if cfg.OllamaURL != "" && cfg.ChromaDBURL != "" {
+	var workflowEngine *workflow.Engine
+	var documentIngester *documents.Ingester
+	var topicService *topics.Service
+	var resummarizer *resummarize.Service
+	var companionService *companion.Service
+	var llmRegistry *llm.Registry
+	if cfg.FakeProviders || (cfg.OllamaURL != "" && cfg.ChromaDBURL != "") {
 		logger.Startup("rag", "Initializing RAG services")
-		vectorDB := vectordb.NewChromaDBClient(cfg.ChromaDBURL)
-		embeddingService := embeddings.NewOllamaEmbeddingService(cfg.OllamaURL, cfg.EmbeddingModel)
-		llmService := llm.NewOllamaService(cfg.OllamaURL)
-		ragService = rag.NewRAGService(vectorDB, embeddingService, llmService)
+		var vectorDB vectordb.Store = vectordb.NewChromaDBClient(cfg.ChromaDBURL)
+		if cfg.FakeProviders {
+			// Nothing survives a restart, which is what tests and UI development want
+			vectorDB = vectordb.NewMemoryStore()
+		}
+		// EMBEDDING_URL points the http provider at its service, or Ollama embeddings at another server
+		embeddingURL := cfg.EmbeddingURL
+		if embeddingURL == "" && !strings.EqualFold(cfg.EmbeddingProvider, embeddings.ProviderHTTP) {
+			embeddingURL = cfg.OllamaURL
+		}
+		embeddingService, err := embeddings.NewService(cfg.EmbeddingProvider, embeddingURL, cfg.EmbeddingModel, cfg.EmbeddingAPIKey)
+		if err != nil {
+			logger.Error("Failed to initialize embedding provider", "error", err)
+			os.Exit(1)
+		}
+		pricing, err := llmstats.ParsePricing(cfg.LLMPricing)
+		if err != nil {
+			logger.Error("Invalid LLM_PRICING", "error", err)
+			os.Exit(1)
+		}
+		usageRecorder := llmstats.NewRecorder(time.Duration(cfg.LLMMetricsRetentionDays)*24*time.Hour, pricing)
+		embeddingProvider := strings.ToLower(cfg.EmbeddingProvider)
+		if embeddingProvider == "" {
+			embeddingProvider = embeddings.ProviderOllama
+		}
+		embeddingService = usageRecorder.WrapEmbeddings(embeddingProvider, embeddingService)
+		llmRegistry, err = buildLLMRegistry(cfg, usageRecorder)
+		if err != nil {
+			logger.Error("Failed to initialize LLM providers", "error", err)
+			os.Exit(1)
+		}
+		summaryLLM, summaryModel, _ := llmRegistry.For(llm.FeatureSummary)
+		chatLLM, chatModel, _ := llmRegistry.For(llm.FeatureChat)
+		ragService = rag.NewRAGService(vectorDB, embeddingService, chatLLM)
+		ragService.SetMaxDistance(float32(cfg.RAGMaxDistance))
+		ragService.SetConfidenceWeight(cfg.RAGConfidenceWeight)
+		ragService.SetStandingContextTokens(cfg.StandingContextMaxTokens)
+		ragService.SetEmbedRedacted(cfg.EmbedRedacted)
+		if cfg.ChunkerURL != "" {
+			ragService.SetChunker(rag.NewHTTPChunker(cfg.ChunkerURL, cfg.ChunkerAPIKey, time.Duration(cfg.ChunkerTimeoutSeconds)*time.Second), cfg.ChunkerFallback)
+			logger.Info("Using custom chunking service", "url", cfg.ChunkerURL, "fallback", cfg.ChunkerFallback)
+		}
+		routes, err := rag.ParseRoutes(cfg.RAGCollectionRoutes)
+		if err != nil {
+			logger.Error("Invalid RAG_COLLECTION_ROUTES", "error", err)
+			os.Exit(1)
+		}
+		ragService.SetRoutes(routes)
+		if !rag.IsCrossLanguageMode(cfg.RAGCrossLanguage) {
+			logger.Error("Invalid RAG_CROSS_LANGUAGE, expected off, annotate or translate", "value", cfg.RAGCrossLanguage)
+			os.Exit(1)
+		}
 		
-		// Set up post-processing hook for auto-summarization
-		llmModel := getEnv("OLLAMA_MODEL", "llama3.2")
-		postHook := transcription.NewPostProcessingHook(ragService, llmService, llmModel)
-		unifiedProcessor.GetUnifiedService().SetPostProcessingHook(postHook)
-		logger.Info("RAG services initialized", "ollama_url", cfg.OllamaURL, "chromadb_url", cfg.ChromaDBURL)
+		// Set up the post-processing workflow (summarize, index, notify) run after each transcription
+		workflowEngine = workflow.NewEngine(cfg.PostProcessingWorkflow)
+		if err := workflow.RegisterBuiltins(workflowEngine, summaryLLM, summaryModel, cfg.SummaryFormat, ragService, notify.NewWebhookNotifier(cfg.NotifyWebhookURL), cfg.TranslationLanguage, cfg.PublicURL, cfg.WebhookAllowPrivate, cfg.IndexTranslations, cfg.AutoTags, cfg.ExtractActionItems, cfg.ExtractEntities, cfg.SpeakerAnalytics, cfg.AutoChapters, cfg.RedactPII, cfg.CorrectVocabulary); err != nil {
+			logger.Error("Failed to register workflows", "error", err)
+			os.Exit(1)
+		}
+		if cfg.WorkflowsFile != "" {
+			if err := workflowEngine.LoadDefinitions(cfg.WorkflowsFile); err != nil {
+				logger.Error("Failed to load workflow definitions", "error", err)
+				os.Exit(1)
+			}
+		}
+		if cfg.DisabledWorkflowSteps != "" {
+			var disabled []string
+			for _, name := range strings.Split(cfg.DisabledWorkflowSteps, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					disabled = append(disabled, name)
+				}
+			}
+			if err := workflowEngine.DisableSteps(disabled); err != nil {
+				logger.Error("Failed to disable workflow steps", "error", err)
+				os.Exit(1)
+			}
+		}
+		workflowEngine.SetRetryPolicy(workflow.RetryPolicy{
+			MaxAttempts: cfg.WorkflowMaxAttempts,
+			BaseDelay:   time.Duration(cfg.WorkflowRetrySeconds) * time.Second,
+		})
+		if err := workflowEngine.RecoverInterrupted(); err != nil {
+			logger.Warn("Failed to recover interrupted workflow runs", "error", err)
+		}
+		workflowEngine.StartRetries(workflow.RetryPollInterval)
+		defer workflowEngine.Stop()
+		unifiedProcessor.GetUnifiedService().SetPostProcessingHook(workflowEngine)
+		documentIngester = documents.NewIngester(ragService, summaryLLM, summaryModel)
+		topicService = topics.NewService(ragService, summaryLLM, summaryModel)
+		topicService.Start(time.Duration(cfg.TopicRefreshHours) * time.Hour)
+		defer topicService.Stop()
+		resummarizer = resummarize.NewService(summaryLLM, summaryModel, ragService)
+		resummarizer.Start(time.Duration(cfg.ResummarizeIntervalHours)*time.Hour, resummarize.Options{
+			BatchSize:      cfg.ResummarizeBatchSize,
+			Delay:          time.Duration(cfg.ResummarizeDelaySeconds) * time.Second,
+			IncludeUnknown: true,
+		})
+		defer resummarizer.Stop()
+		companionService = companion.NewService(ragService, &companion.QuickTranscriber{Service: quickTranscriptionService}, summaryLLM, summaryModel, chatLLM, chatModel)
+		companionService.Start(time.Duration(cfg.CompanionSummarySeconds) * time.Second)
+		defer companionService.Stop()
+		logger.Info("RAG services initialized", "ollama_url", cfg.OllamaURL, "chromadb_url", cfg.ChromaDBURL, "embedding_provider", cfg.EmbeddingProvider, "workflow", cfg.PostProcessingWorkflow)
 	} else {
 		logger.Warn("RAG services not initialized - missing OllamaURL or ChromaDBURL")
 	}
 
 	// Initialize API handlers
 	handler := api.NewHandler(cfg, authService, taskQueue, unifiedProcessor, quickTranscriptionService, ragService)
+	handler.SetWorkflowEngine(workflowEngine)
+	handler.SetDocumentIngester(documentIngester)
+	handler.SetTopicService(topicService)
+	handler.SetResummarizer(resummarizer)
+	handler.SetCompanionService(companionService)
+	handler.SetLLMRegistry(llmRegistry)
+	if files != nil {
+		handler.SetFileStore(files)
+	}
+	handler.SetWatchdog(watchdog)
+	handler.SetJobLogStore(jobLogs)
+
+	// Poll podcast subscriptions for new episodes
+	podcastService := podcasts.NewService()
+	handler.SetPodcastService(podcastService)
+	podcastService.Start(time.Duration(cfg.PodcastPollMinutes) * time.Minute)
+	defer podcastService.Stop()
+
+	// Read emailed voice memos, if a mailbox is configured
+	if cfg.EmailInIMAPAddr != "" {
+		imap := mailin.IMAPConfig{
+			Addr:     cfg.EmailInIMAPAddr,
+			TLS:      cfg.EmailInIMAPTLS,
+			Username: cfg.EmailInUsername,
+			Password: cfg.EmailInPassword,
+			Mailbox:  cfg.EmailInMailbox,
+		}
+		emailIn := mailin.NewService(imap.Open, strings.Split(cfg.EmailInAllowedSenders, ","), cfg.PublicURL)
+		if cfg.SMTPAddr != "" {
+			from := cfg.SMTPFrom
+			if from == "" {
+				from = cfg.EmailInUsername
+			}
+			emailIn.SetSender(&mailin.SMTPSender{Addr: cfg.SMTPAddr, Username: cfg.SMTPUsername, Password: cfg.SMTPPassword, From: from})
+		}
+		handler.SetEmailInService(emailIn)
+		emailIn.Start(time.Duration(cfg.EmailInPollSeconds) * time.Second)
+		defer emailIn.Stop()
+		logger.Info("Email-in enabled", "mailbox", cfg.EmailInMailbox, "replies", cfg.SMTPAddr != "")
+	}
+
+	// Run scheduled maintenance (dropzone scans, RAG backfills, retention cleanups)
+	taskScheduler := scheduler.NewService()
+	handler.SetScheduler(taskScheduler)
+	taskScheduler.Start(scheduler.CheckInterval)
+	defer taskScheduler.Stop()
+
+	// Run batch operations (bulk retag, move, delete, re-summarize, re-index) in the background
+	batches := batch.NewService()
+	if err := batches.CloseInterrupted(); err != nil {
+		logger.Warn("Failed to close interrupted batch operations", "error", err)
+	}
+	handler.SetBatchService(batches)
+	defer batches.Stop()
 
 	// Set up router
 	router := api.SetupRoutes(handler, authService)
@@ -186,6 +445,11 @@ func main() {
 
 // registerAdapters registers all transcription and diarization adapters with config-based paths
 func registerAdapters(cfg *config.Config) {
+	if cfg.FakeProviders {
+		registerFakeAdapters()
+		return
+	}
+
 	logger.Info("Registering adapters with environment path", "whisperx_env", cfg.WhisperXEnv)
 
 	// Shared environment path for NVIDIA models (NeMo-based)
@@ -208,6 +472,66 @@ func registerAdapters(cfg *config.Config) {
 	logger.Info("Adapter registration complete")
 }
 
+// registerFakeAdapters registers the fake transcriber and diarizer under every model ID,
+// so jobs of any model family complete without Python or a GPU
+func registerFakeAdapters() {
+	logger.Info("Registering fake adapters")
+	for _, modelID := range []string{"whisperx", "parakeet", "canary"} {
+		registry.RegisterTranscriptionAdapter(modelID, adapters.NewFakeTranscriptionAdapter(modelID))
+	}
+	for _, modelID := range []string{"pyannote", "sortformer"} {
+		registry.RegisterDiarizationAdapter(modelID, adapters.NewFakeDiarizationAdapter(modelID))
+	}
+}
+
+// buildLLMRegistry registers every LLM provider that has settings and binds the summary
+// and chat features to their configured provider and model, followed by their fallbacks.
+// Every call through the registry is reported to observer for the provider metrics and usage.
+func buildLLMRegistry(cfg *config.Config, observer llm.CallObserver) (*llm.Registry, error) {
+	settings := map[string]llm.ProviderConfig{
+		llm.ProviderOllama:    {BaseURL: cfg.OllamaURL},
+		llm.ProviderOpenAI:    {BaseURL: cfg.OpenAIBaseURL, APIKey: cfg.OpenAIAPIKey},
+		llm.ProviderAnthropic: {BaseURL: cfg.AnthropicBaseURL, APIKey: cfg.AnthropicAPIKey},
+	}
+
+	llmRegistry := llm.NewRegistry()
+	for name, providerConfig := range settings {
+		if providerConfig.BaseURL == "" && providerConfig.APIKey == "" {
+			continue
+		}
+		service, err := llm.NewProvider(name, providerConfig)
+		if err != nil {
+			return nil, err
+		}
+		llmRegistry.Register(name, service)
+	}
+	if cfg.FakeProviders {
+		llmRegistry.Register(llm.ProviderFake, llm.NewFakeService())
+	}
+
+	features := []struct {
+		name, provider, model, fallbacks string
+	}{
+		{llm.FeatureSummary, cfg.SummaryLLMProvider, cfg.SummaryLLMModel, cfg.SummaryLLMFallbacks},
+		{llm.FeatureChat, cfg.ChatLLMProvider, cfg.ChatLLMModel, cfg.ChatLLMFallbacks},
+	}
+	for _, feature := range features {
+		fallbacks, err := llm.ParseChain(feature.fallbacks)
+		if err != nil {
+			return nil, err
+		}
+		chain := append([]llm.Binding{{Provider: feature.provider, Model: feature.model}}, fallbacks...)
+		if err := llmRegistry.BindChain(feature.name, chain); err != nil {
+			return nil, err
+		}
+	}
+	llmRegistry.SetAttemptTimeout(time.Duration(cfg.LLMAttemptTimeoutSeconds) * time.Second)
+	llmRegistry.SetObserver(observer)
+
+	logger.Info("LLM providers configured", "providers", llmRegistry.Providers(), "chains", llmRegistry.Chains())
+	return llmRegistry, nil
+}
+
 // getEnv gets an environment variable with a default value
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -0,0 +1,139 @@
+// Package analytics computes conversation metrics, such as talk time and interruptions per
+// speaker, from the timed segments of a transcript.
+package analytics
+
+import (
+	"math"
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+)
+
+// MinInterruptionOverlap is how many seconds a speaker has to start before the previous
+// speaker's segment ends for it to count as an interruption. Smaller overlaps are usually
+// alignment noise at a turn change.
+const MinInterruptionOverlap = 0.5
+
+// UnknownSpeaker names the speaker of segments without a diarization label
+const UnknownSpeaker = "Unknown"
+
+// Thresholds of the sentiment labels, on the -1 to 1 score scale
+const (
+	positiveThreshold = 0.25
+	negativeThreshold = -0.25
+)
+
+// Speakers computes each speaker's talk time, turns, words and interruptions, in the order
+// they first spoke. names maps diarization labels (upper-case) to display names. It also
+// returns the duration covered by the segments.
+func Speakers(segments []interfaces.TranscriptSegment, names map[string]string) ([]models.SpeakerAnalytics, float64) {
+	if len(segments) == 0 {
+		return []models.SpeakerAnalytics{}, 0
+	}
+
+	var stats []*models.SpeakerAnalytics
+	byLabel := map[string]*models.SpeakerAnalytics{}
+	first, last := segments[0].Start, segments[0].End
+	var total, turnStart float64
+	previous := -1
+	for i, segment := range segments {
+		label := SegmentLabel(segment)
+		speaker, ok := byLabel[label]
+		if !ok {
+			speaker = &models.SpeakerAnalytics{Speaker: DisplayName(label, names)}
+			if label != "" {
+				speaker.Label = label
+			}
+			byLabel[label] = speaker
+			stats = append(stats, speaker)
+		}
+
+		duration := math.Max(segment.End-segment.Start, 0)
+		speaker.TalkTime += duration
+		speaker.Segments++
+		speaker.Words += len(strings.Fields(segment.Text))
+		total += duration
+		first = math.Min(first, segment.Start)
+		last = math.Max(last, segment.End)
+
+		if previous < 0 || SegmentLabel(segments[previous]) != label {
+			speaker.Turns++
+			turnStart = segment.Start
+			if previous >= 0 && label != "" && SegmentLabel(segments[previous]) != "" &&
+				segments[previous].End-segment.Start >= MinInterruptionOverlap {
+				speaker.Interruptions++
+				byLabel[SegmentLabel(segments[previous])].Interrupted++
+			}
+		}
+		speaker.LongestTurn = math.Max(speaker.LongestTurn, segment.End-turnStart)
+		previous = i
+	}
+
+	result := make([]models.SpeakerAnalytics, len(stats))
+	for i, speaker := range stats {
+		if total > 0 {
+			speaker.TalkShare = round(speaker.TalkTime / total)
+		}
+		if speaker.TalkTime > 0 {
+			speaker.WordsPerMinute = round(float64(speaker.Words) / speaker.TalkTime * 60)
+		}
+		speaker.TalkTime = round(speaker.TalkTime)
+		speaker.LongestTurn = round(speaker.LongestTurn)
+		result[i] = *speaker
+	}
+	return result, round(math.Max(last-first, 0))
+}
+
+// AddSentiment sets each speaker's sentiment to the mean score of their segments
+func AddSentiment(speakers []models.SpeakerAnalytics, segments []models.SegmentSentiment) {
+	sums := map[string]float64{}
+	counts := map[string]int{}
+	for _, segment := range segments {
+		sums[segment.Speaker] += segment.Score
+		counts[segment.Speaker]++
+	}
+	for i := range speakers {
+		if count := counts[speakers[i].Speaker]; count > 0 {
+			mean := round(sums[speakers[i].Speaker] / float64(count))
+			speakers[i].Sentiment = &mean
+		}
+	}
+}
+
+// SentimentLabel names a sentiment score
+func SentimentLabel(score float64) string {
+	switch {
+	case score >= positiveThreshold:
+		return models.SentimentPositive
+	case score <= negativeThreshold:
+		return models.SentimentNegative
+	default:
+		return models.SentimentNeutral
+	}
+}
+
+// SegmentLabel returns a segment's diarization label in upper case, empty if it has none
+func SegmentLabel(segment interfaces.TranscriptSegment) string {
+	if segment.Speaker == nil {
+		return ""
+	}
+	return strings.ToUpper(strings.TrimSpace(*segment.Speaker))
+}
+
+// DisplayName returns the mapped name of a diarization label, the label itself if it isn't
+// mapped, or UnknownSpeaker for segments without one
+func DisplayName(label string, names map[string]string) string {
+	if label == "" {
+		return UnknownSpeaker
+	}
+	if name := names[label]; name != "" {
+		return name
+	}
+	return label
+}
+
+// round keeps two decimals
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,64 @@
+package analytics
+
+import (
+	"testing"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+)
+
+func segment(start, end float64, speaker, text string) interfaces.TranscriptSegment {
+	s := interfaces.TranscriptSegment{Start: start, End: end, Text: text}
+	if speaker != "" {
+		s.Speaker = &speaker
+	}
+	return s
+}
+
+func TestSpeakers(t *testing.T) {
+	segments := []interfaces.TranscriptSegment{
+		segment(0, 10, "SPEAKER_00", "one two three four five"),
+		segment(10, 16, "SPEAKER_00", "six seven eight"),
+		segment(15, 20, "SPEAKER_01", "I have to jump in here"), // starts 1s before the previous segment ends
+		segment(19.8, 30, "speaker_00", "go on"),                // 0.2s overlap is not an interruption
+		segment(31, 32, "", "mm"),
+	}
+	speakers, duration := Speakers(segments, map[string]string{"SPEAKER_01": "Dana"})
+
+	if duration != 32 {
+		t.Errorf("expected a duration of 32s, got %v", duration)
+	}
+	if len(speakers) != 3 {
+		t.Fatalf("expected 3 speakers, got %d", len(speakers))
+	}
+	host, dana, unknown := speakers[0], speakers[1], speakers[2]
+	if host.Speaker != "SPEAKER_00" || dana.Speaker != "Dana" || dana.Label != "SPEAKER_01" || unknown.Speaker != UnknownSpeaker {
+		t.Errorf("unexpected speakers %q, %q (%q), %q", host.Speaker, dana.Speaker, dana.Label, unknown.Speaker)
+	}
+	if host.TalkTime != 26.2 || host.Segments != 3 || host.Turns != 2 || host.LongestTurn != 16 || host.Words != 10 {
+		t.Errorf("unexpected host stats %+v", host)
+	}
+	if dana.Interruptions != 1 || host.Interrupted != 1 || host.Interruptions != 0 || dana.Interrupted != 0 {
+		t.Errorf("expected Dana to interrupt once, got %+v and %+v", host, dana)
+	}
+	if dana.TalkShare != 0.16 || dana.WordsPerMinute != 72 {
+		t.Errorf("unexpected share %v or pace %v", dana.TalkShare, dana.WordsPerMinute)
+	}
+}
+
+func TestAddSentiment(t *testing.T) {
+	speakers := []models.SpeakerAnalytics{{Speaker: "Dana"}, {Speaker: "Lee"}}
+	AddSentiment(speakers, []models.SegmentSentiment{
+		{Speaker: "Dana", Score: 0.5},
+		{Speaker: "Dana", Score: -0.1},
+	})
+	if speakers[0].Sentiment == nil || *speakers[0].Sentiment != 0.2 {
+		t.Errorf("expected a mean of 0.2, got %v", speakers[0].Sentiment)
+	}
+	if speakers[1].Sentiment != nil {
+		t.Errorf("expected no sentiment without segments")
+	}
+	if SentimentLabel(0.2) != models.SentimentNeutral || SentimentLabel(0.6) != models.SentimentPositive || SentimentLabel(-0.3) != models.SentimentNegative {
+		t.Errorf("unexpected sentiment labels")
+	}
+}
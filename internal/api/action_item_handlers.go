@@ -0,0 +1,296 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/export"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// UpdateActionItemRequest marks an action item as done or open again
+type UpdateActionItemRequest struct {
+	Completed bool `json:"completed"`
+}
+
+// ActionItemResponse is an action item with the title of its transcription
+type ActionItemResponse struct {
+	models.ActionItem
+	TranscriptionTitle string `json:"transcription_title,omitempty"`
+}
+
+// SpeakerCommitment is something a participant said they would do, from a summary with the
+// per-speaker layout
+type SpeakerCommitment struct {
+	TranscriptionID    string `json:"transcription_id"`
+	TranscriptionTitle string `json:"transcription_title,omitempty"`
+	Speaker            string `json:"speaker"`
+	Commitment         string `json:"commitment"`
+}
+
+// visibleActionItems limits an action item query to items from the caller's transcriptions
+// and from transcriptions without an owner, which every user can see
+func visibleActionItems(db *gorm.DB, userID *uint) *gorm.DB {
+	if userID == nil {
+		return db.Where("action_items.user_id IS NULL")
+	}
+	return db.Where("action_items.user_id = ? OR action_items.user_id IS NULL", *userID)
+}
+
+// findActionItems lists the caller's action items filtered by the status, transcription_id
+// and owner query parameters, grouped by transcription (newest first) in the order they were
+// mentioned. It writes the error response and returns false if it can't.
+func findActionItems(c *gin.Context) ([]models.ActionItem, map[string]string, bool) {
+	query := visibleActionItems(database.DB.Model(&models.ActionItem{}), currentUserID(c)).
+		Joins("JOIN transcription_jobs ON transcription_jobs.id = action_items.transcription_id")
+	switch c.DefaultQuery("status", "all") {
+	case "open":
+		query = query.Where("action_items.completed = ?", false)
+	case "completed":
+		query = query.Where("action_items.completed = ?", true)
+	case "all":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be open, completed or all"})
+		return nil, nil, false
+	}
+	if id := c.Query("transcription_id"); id != "" {
+		query = query.Where("action_items.transcription_id = ?", id)
+	}
+	if owner := strings.TrimSpace(c.Query("owner")); owner != "" {
+		query = query.Where("LOWER(action_items.owner) = ?", strings.ToLower(owner))
+	}
+
+	var items []models.ActionItem
+	if err := query.Order("transcription_jobs.created_at DESC, action_items.transcription_id, action_items.source_time ASC, action_items.created_at ASC").
+		Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list action items"})
+		return nil, nil, false
+	}
+
+	titles := map[string]string{}
+	var ids []string
+	for _, item := range items {
+		if _, ok := titles[item.TranscriptionID]; !ok {
+			titles[item.TranscriptionID] = ""
+			ids = append(ids, item.TranscriptionID)
+		}
+	}
+	if len(ids) > 0 {
+		var jobs []models.TranscriptionJob
+		database.DB.Select("id", "title").Where("id IN ?", ids).Find(&jobs)
+		for _, job := range jobs {
+			if job.Title != nil {
+				titles[job.ID] = *job.Title
+			}
+		}
+	}
+	return items, titles, true
+}
+
+// actionItemResponses attaches transcription titles to action items
+func actionItemResponses(items []models.ActionItem, titles map[string]string) []ActionItemResponse {
+	result := make([]ActionItemResponse, len(items))
+	for i, item := range items {
+		result[i] = ActionItemResponse{ActionItem: item, TranscriptionTitle: titles[item.TranscriptionID]}
+	}
+	return result
+}
+
+// ListActionItems returns the action items extracted from the caller's transcriptions
+// @Summary List action items
+// @Description List the action items extracted from the caller's transcriptions by the extract_action_items workflow step, grouped by transcription (newest first) in the order they were mentioned
+// @Tags action-items
+// @Produce json
+// @Param status query string false "open, completed or all (default all)"
+// @Param transcription_id query string false "Only items from this transcription"
+// @Param owner query string false "Only items owned by this person (case-insensitive)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/action-items [get]
+func (h *Handler) ListActionItems(c *gin.Context) {
+	items, titles, ok := findActionItems(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"action_items": actionItemResponses(items, titles)})
+}
+
+// ListTranscriptionActionItems returns the action items extracted from one transcription
+// @Summary List a transcription's action items
+// @Description List the action items extracted from a transcription, in the order they were mentioned
+// @Tags action-items
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/action-items [get]
+func (h *Handler) ListTranscriptionActionItems(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	var items []models.ActionItem
+	if err := database.DB.Where("transcription_id = ?", job.ID).Order("source_time ASC, created_at ASC").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list action items"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"transcription_id": job.ID, "action_items": items})
+}
+
+// ListSpeakerCommitments returns the commitments listed in per-speaker summaries
+// @Summary List speaker commitments
+// @Description List what each participant committed to, from the summaries of the caller's transcriptions generated with a per-speaker template, grouped by transcription (newest first)
+// @Tags action-items
+// @Produce json
+// @Param speaker query string false "Only commitments of this speaker (case-insensitive)"
+// @Param transcription_id query string false "Only commitments from this transcription"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/action-items/commitments [get]
+func (h *Handler) ListSpeakerCommitments(c *gin.Context) {
+	query := database.DB.Model(&models.TranscriptionJob{}).Select("id", "title", "structured_summary").
+		Where("structured_summary LIKE ?", `%"speakers":%`)
+	if userID := currentUserID(c); userID != nil {
+		query = query.Where("user_id = ? OR user_id IS NULL", *userID)
+	} else {
+		query = query.Where("user_id IS NULL")
+	}
+	if id := c.Query("transcription_id"); id != "" {
+		query = query.Where("id = ?", id)
+	}
+
+	var jobs []models.TranscriptionJob
+	if err := query.Order("created_at DESC").Find(&jobs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list commitments"})
+		return
+	}
+
+	speaker := strings.TrimSpace(c.Query("speaker"))
+	commitments := []SpeakerCommitment{}
+	for _, job := range jobs {
+		if job.StructuredSummary == nil {
+			continue
+		}
+		var title string
+		if job.Title != nil {
+			title = *job.Title
+		}
+		for _, section := range job.StructuredSummary.Commitments(speaker) {
+			for _, commitment := range section.Commitments {
+				commitments = append(commitments, SpeakerCommitment{
+					TranscriptionID:    job.ID,
+					TranscriptionTitle: title,
+					Speaker:            section.Speaker,
+					Commitment:         commitment,
+				})
+			}
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"commitments": commitments})
+}
+
+// UpdateActionItem marks an action item as completed or open
+// @Summary Complete an action item
+// @Description Mark an action item as completed, or as open again. Completed items stay completed when the action items of their transcription are extracted again.
+// @Tags action-items
+// @Accept json
+// @Produce json
+// @Param id path string true "Action item ID"
+// @Param request body UpdateActionItemRequest true "Completion state"
+// @Success 200 {object} models.ActionItem
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/action-items/{id} [put]
+func (h *Handler) UpdateActionItem(c *gin.Context) {
+	var req UpdateActionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var item models.ActionItem
+	if err := visibleActionItems(database.DB, currentUserID(c)).Where("id = ?", c.Param("id")).First(&item).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Action item not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get action item"})
+		}
+		return
+	}
+
+	if req.Completed != item.Completed {
+		item.Completed = req.Completed
+		item.CompletedAt = nil
+		if req.Completed {
+			now := time.Now()
+			item.CompletedAt = &now
+		}
+		if err := database.DB.Model(&item).Select("completed", "completed_at").Updates(&item).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update action item"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// ExportActionItems downloads the caller's action items
+// @Summary Export action items
+// @Description Download the action items of the caller's transcriptions as CSV or as Markdown task lists, with the same filters as listing them
+// @Tags action-items
+// @Produce text/csv
+// @Produce text/markdown
+// @Param format query string false "csv or markdown (default csv)"
+// @Param status query string false "open, completed or all (default all)"
+// @Param transcription_id query string false "Only items from this transcription"
+// @Param owner query string false "Only items owned by this person (case-insensitive)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/action-items/export [get]
+func (h *Handler) ExportActionItems(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", export.FormatCSV))
+	if format == "md" {
+		format = export.FormatMarkdown
+	}
+	if format != export.FormatCSV && format != export.FormatMarkdown {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or markdown"})
+		return
+	}
+
+	items, titles, ok := findActionItems(c)
+	if !ok {
+		return
+	}
+
+	name := "action-items-" + time.Now().Format("2006-01-02")
+	if format == export.FormatMarkdown {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.md"`, name))
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", export.ActionItemsMarkdown(items, titles))
+		return
+	}
+	data, err := export.ActionItemsCSV(items, titles)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render action items"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, name))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+}
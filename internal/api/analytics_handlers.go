@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetTranscriptionAnalytics returns the conversation analytics of a transcription
+// @Summary Get conversation analytics
+// @Description Get the per-speaker talk time, turns, interruptions and sentiment of a transcription, and the sentiment of each segment, as computed by the speaker_analytics workflow step
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Success 200 {object} models.ConversationAnalytics
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/analytics [get]
+func (h *Handler) GetTranscriptionAnalytics(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	var result models.ConversationAnalytics
+	if err := database.DB.Where("transcription_id = ?", job.ID).First(&result).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Analytics have not been computed for this transcription"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get analytics"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,315 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/archive"
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/tagging"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ArchiveImportResult describes what an archive import restored
+type ArchiveImportResult struct {
+	Imported []string `json:"imported"` // IDs of the restored transcriptions
+	Skipped  []string `json:"skipped"`  // IDs of transcriptions that exist here already
+	Audio    int      `json:"audio"`    // Restored transcriptions that came with their audio
+}
+
+// ExportArchive streams the caller's transcriptions as a backup archive
+// @Summary Export a backup archive
+// @Description Download transcriptions as a zip archive that /transcription/archive/import restores on another instance: a manifest.json listing them, and for each its record with the transcript, summaries, notes, speaker names, chapters, action items, translations and correction history, plus its audio when audio is true. Without ids, every transcription of the caller's is included. The archive is streamed as it is written, so a failure partway through ends it early; the manifest is written last, and an archive without one can't be imported.
+// @Tags transcription
+// @Produce application/zip
+// @Param ids query string false "Comma-separated IDs of the transcriptions to include (default all)"
+// @Param audio query bool false "Include the audio files (default false)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/archive [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ExportArchive(c *gin.Context) {
+	audio := false
+	if value := c.Query("audio"); value != "" {
+		var err error
+		if audio, err = strconv.ParseBool(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "audio must be true or false"})
+			return
+		}
+	}
+	var ids []string
+	for _, value := range c.QueryArray("ids") {
+		for _, id := range strings.Split(value, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	// Transcriptions are loaded one at a time, so a large library isn't held in memory
+	query := scopeToOwner(database.DB.Model(&models.TranscriptionJob{}), currentUserID(c)).
+		Where("id NOT LIKE 'track_%'")
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	var jobIDs []string
+	if err := query.Order("created_at ASC").Pluck("id", &jobIDs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list transcriptions"})
+		return
+	}
+	if len(ids) > 0 {
+		found := make(map[string]bool, len(jobIDs))
+		for _, id := range jobIDs {
+			found[id] = true
+		}
+		for _, id := range ids {
+			if !found[id] {
+				c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Transcription %s not found", id)})
+				return
+			}
+		}
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="scriberr-archive-%s.zip"`, time.Now().UTC().Format("2006-01-02")))
+	c.Header("Content-Type", "application/zip")
+	c.Status(http.StatusOK)
+	writer := archive.NewWriter(c.Writer, audio)
+	for _, id := range jobIDs {
+		record, err := archiveRecord(id)
+		if err != nil {
+			logger.ErrorContext(c.Request.Context(), "Failed to archive transcription", "job_id", id, "error", err)
+			return
+		}
+		audioPath := ""
+		if audio {
+			audioPath = h.archiveAudioPath(c.Request.Context(), &record.Job)
+		}
+		if err := writer.Add(record, audioPath); err != nil {
+			logger.ErrorContext(c.Request.Context(), "Failed to archive transcription", "job_id", id, "error", err)
+			return
+		}
+	}
+	if err := writer.Close(); err != nil {
+		logger.ErrorContext(c.Request.Context(), "Failed to finish archive", "error", err)
+	}
+}
+
+// archiveRecord loads everything an archive keeps about a transcription
+func archiveRecord(jobID string) (*archive.Record, error) {
+	record := &archive.Record{}
+	if err := database.DB.Where("id = ?", jobID).First(&record.Job).Error; err != nil {
+		return nil, err
+	}
+	queries := []struct {
+		column string
+		dest   interface{}
+		order  string
+	}{
+		{"transcription_id", &record.Summaries, "created_at ASC"},
+		{"transcription_id", &record.Notes, "start_time ASC, created_at ASC"},
+		{"transcription_id", &record.Chapters, "position ASC"},
+		{"transcription_id", &record.ActionItems, "source_time ASC, created_at ASC"},
+		{"transcription_job_id", &record.Translations, "created_at ASC"},
+		{"transcription_id", &record.Revisions, "id ASC"},
+		{"transcription_id", &record.Versions, "kind ASC, version ASC"},
+	}
+	for _, query := range queries {
+		if err := database.DB.Where(query.column+" = ?", jobID).Order(query.order).Find(query.dest).Error; err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	record.SpeakerNames, err = jobSpeakerNames(jobID)
+	return record, err
+}
+
+// archiveAudioPath returns the local path of a transcription's audio, fetched from object
+// storage if need be, or "" when it has none. Multi-track recordings are archived mixed down.
+func (h *Handler) archiveAudioPath(ctx context.Context, job *models.TranscriptionJob) string {
+	audioPath := job.AudioPath
+	if job.IsMultiTrack && job.MergedAudioPath != nil && *job.MergedAudioPath != "" {
+		audioPath = *job.MergedAudioPath
+	}
+	if audioPath == "" {
+		return ""
+	}
+	h.fetchStoredFile(ctx, audioPath)
+	if _, err := os.Stat(audioPath); err != nil {
+		logger.WarnContext(ctx, "Archiving transcription without its audio", "job_id", job.ID, "error", err)
+		return ""
+	}
+	return audioPath
+}
+
+// ImportArchive restores the transcriptions in a backup archive
+// @Summary Import a backup archive
+// @Description Restore the transcriptions in an archive from /transcription/archive as the caller's, keeping their IDs. Transcriptions that already exist here are skipped, so an archive can be imported again after a partial failure. Audio in the archive is restored next to the uploads; transcriptions without it keep their transcript but can't be transcribed again, and those exported before they finished are marked failed. Multi-track recordings are restored as their mixed-down audio. Templates, projects and users of the other instance are not carried over, and restored transcriptions are not added to the RAG index until a backfill.
+// @Tags transcription
+// @Accept multipart/form-data
+// @Produce json
+// @Param archive formData file true "Archive from /transcription/archive"
+// @Success 200 {object} ArchiveImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/archive/import [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ImportArchive(c *gin.Context) {
+	header, err := c.FormFile("archive")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "archive file is required"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read archive"})
+		return
+	}
+	defer file.Close()
+	reader, err := archive.NewReader(file, header.Size)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := currentUserID(c)
+	result := ArchiveImportResult{Imported: []string{}, Skipped: []string{}}
+	for _, entry := range reader.Manifest.Transcriptions {
+		var count int64
+		if err := database.DB.Model(&models.TranscriptionJob{}).Where("id = ?", entry.ID).Count(&count).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check transcription " + entry.ID})
+			return
+		}
+		if count > 0 {
+			result.Skipped = append(result.Skipped, entry.ID)
+			continue
+		}
+		record, err := reader.Record(entry)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "imported": result.Imported})
+			return
+		}
+
+		audioPath := ""
+		if ext := reader.AudioExt(entry); ext != "" {
+			audioPath = filepath.Join(h.config.UploadDir, entry.ID+ext)
+			if err := reader.ExtractAudio(entry, audioPath); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "imported": result.Imported})
+				return
+			}
+			if h.files != nil {
+				if err := h.files.Upload(c.Request.Context(), audioPath); err != nil {
+					os.Remove(audioPath)
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store audio of " + entry.ID, "imported": result.Imported})
+					return
+				}
+			}
+		}
+
+		if err := restoreRecord(record, userID, audioPath); err != nil {
+			if audioPath != "" {
+				os.Remove(audioPath)
+				h.removeStoredFile(audioPath)
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to restore transcription %s: %v", entry.ID, err), "imported": result.Imported})
+			return
+		}
+		if h.files != nil && record.Job.Transcript != nil {
+			if err := h.files.SaveTranscript(c.Request.Context(), record.Job.ID, *record.Job.Transcript); err != nil {
+				logger.ErrorContext(c.Request.Context(), "Failed to store transcript", "job_id", record.Job.ID, "error", err)
+			}
+		}
+		result.Imported = append(result.Imported, entry.ID)
+		if audioPath != "" {
+			result.Audio++
+		}
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// restoreRecord saves an archived transcription and its data as the user's. References to
+// records of the other instance, such as templates, are dropped.
+func restoreRecord(record *archive.Record, userID *uint, audioPath string) error {
+	job := &record.Job
+	tags := job.Tags
+	job.UserID = userID
+	job.Tags = nil
+	job.AudioPath = audioPath
+	job.IsMultiTrack, job.MultiTrackFiles = false, nil
+	job.AupFilePath, job.MultiTrackFolder, job.MergedAudioPath = nil, nil, nil
+	job.MergeStatus, job.MergeError = "none", nil
+	job.JobTemplateID, job.SummaryTemplateID, job.ProjectID = nil, nil, nil
+	job.LegalHoldBy = nil
+	job.WorkerID, job.ProcessingStartedAt, job.HeartbeatAt, job.StuckRequeues = nil, nil, nil, 0
+	if job.Status != models.StatusCompleted && job.Status != models.StatusFailed {
+		if audioPath != "" {
+			job.Status = models.StatusUploaded
+		} else {
+			message := "Imported from an archive before it was transcribed, without its audio"
+			job.Status, job.ErrorMessage = models.StatusFailed, &message
+		}
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("MultiTrackFiles").Create(job).Error; err != nil {
+			return err
+		}
+		for i := range record.Summaries {
+			record.Summaries[i].ID, record.Summaries[i].TranscriptionID, record.Summaries[i].TemplateID = "", job.ID, nil
+		}
+		for i := range record.Notes {
+			record.Notes[i].ID, record.Notes[i].TranscriptionID = uuid.New().String(), job.ID
+		}
+		for i := range record.Chapters {
+			record.Chapters[i].ID, record.Chapters[i].TranscriptionID, record.Chapters[i].UserID = "", job.ID, userID
+		}
+		for i := range record.ActionItems {
+			record.ActionItems[i].ID, record.ActionItems[i].TranscriptionID, record.ActionItems[i].UserID = "", job.ID, userID
+		}
+		for i := range record.Translations {
+			record.Translations[i].ID, record.Translations[i].TranscriptionJobID, record.Translations[i].Indexed = "", job.ID, false
+		}
+		for i := range record.Revisions {
+			record.Revisions[i].ID, record.Revisions[i].TranscriptionID, record.Revisions[i].EditedBy = 0, job.ID, nil
+		}
+		for i := range record.Versions {
+			record.Versions[i].ID, record.Versions[i].TranscriptionID, record.Versions[i].CreatedBy = 0, job.ID, nil
+		}
+		for _, rows := range []interface{}{&record.Summaries, &record.Notes, &record.Chapters, &record.ActionItems, &record.Translations, &record.Revisions, &record.Versions} {
+			if err := tx.CreateInBatches(rows, 100).Error; err != nil && !errors.Is(err, gorm.ErrEmptySlice) {
+				return err
+			}
+		}
+		for speaker, name := range record.SpeakerNames {
+			mapping := models.SpeakerMapping{TranscriptionJobID: job.ID, OriginalSpeaker: speaker, CustomName: name}
+			if err := tx.Omit("TranscriptionJob").Create(&mapping).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(tags) > 0 {
+		if _, err := tagging.SetJobTags(job, tags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
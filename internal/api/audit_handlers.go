@@ -0,0 +1,327 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/audit"
+	"scriberr/internal/config"
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxAuditBody caps how much of a request or response body is read for audit details
+	maxAuditBody = 64 << 10
+	// maxAuditValue caps each recorded detail, such as a search query
+	maxAuditValue = 500
+)
+
+// auditRoute is how requests to a route are recorded in the audit log
+type auditRoute struct {
+	action string
+	// fields are the JSON body fields and query parameters recorded with the action; secrets
+	// such as passwords are never listed
+	fields []string
+	// created is set for routes that answer with the record they created, whose id becomes
+	// the entry's target
+	created bool
+}
+
+// auditRoutes are the routes recorded under an action of their own, keyed by method and route.
+// Every other request that changes data is recorded as models.AuditChange.
+var auditRoutes = map[string]auditRoute{
+	"POST /api/v1/auth/login":           {action: models.AuditLogin, fields: []string{"username"}},
+	"POST /api/v1/auth/logout":          {action: models.AuditLogout},
+	"POST /api/v1/auth/register":        {action: models.AuditRegister, fields: []string{"username"}},
+	"POST /api/v1/auth/change-password": {action: models.AuditPasswordChange},
+	"POST /api/v1/auth/change-username": {action: models.AuditUsernameChange, fields: []string{"newUsername"}},
+	"POST /api/v1/api-keys/":            {action: models.AuditAPIKeyCreate, fields: []string{"name", "scopes"}, created: true},
+	"DELETE /api/v1/api-keys/:id":       {action: models.AuditAPIKeyRevoke},
+	"POST /api/v1/admin/users":          {action: models.AuditUserCreate, fields: []string{"username", "role"}, created: true},
+	"PUT /api/v1/admin/users/:id":       {action: models.AuditUserUpdate, fields: []string{"username", "role"}},
+	"DELETE /api/v1/admin/users/:id":    {action: models.AuditUserDelete, fields: []string{"transfer_to"}},
+
+	"POST /api/v1/transcription/upload":                 {action: models.AuditUpload, created: true},
+	"POST /api/v1/transcription/upload-video":           {action: models.AuditUpload, created: true},
+	"POST /api/v1/transcription/upload-multitrack":      {action: models.AuditUpload, created: true},
+	"POST /api/v1/transcription/upload-multichannel":    {action: models.AuditUpload, created: true},
+	"POST /api/v1/transcription/upload-url/complete":    {action: models.AuditUpload, created: true},
+	"POST /api/v1/transcription/uploads":                {action: models.AuditUpload, fields: []string{"filename"}, created: true},
+	"POST /api/v1/transcription/from-url":               {action: models.AuditUpload, fields: []string{"url"}, created: true},
+	"POST /api/v1/transcription/from-media":             {action: models.AuditUpload, fields: []string{"url"}, created: true},
+	"POST /api/v1/transcription/youtube":                {action: models.AuditUpload, fields: []string{"url"}, created: true},
+	"POST /api/v1/transcription/submit":                 {action: models.AuditUpload, created: true},
+	"POST /api/v1/transcription/import-transcript":      {action: models.AuditUpload, created: true},
+	"POST /api/v1/transcription/archive/import":         {action: models.AuditUpload},
+	"POST /api/v1/transcription/quick":                  {action: models.AuditUpload, created: true},
+	"POST /api/v1/documents":                            {action: models.AuditUpload, created: true},
+	"POST /v1/audio/transcriptions":                     {action: models.AuditUpload},
+	"DELETE /api/v1/transcription/:id":                  {action: models.AuditDelete},
+	"DELETE /api/v1/transcription/:id/audio":            {action: models.AuditAudioDelete},
+	"GET /api/v1/transcription/:id/audio":               {action: models.AuditExport},
+	"GET /api/v1/transcription/:id/export":              {action: models.AuditExport, fields: []string{"format"}},
+	"GET /api/v1/transcription/:id/export/bilingual":    {action: models.AuditExport, fields: []string{"format", "language"}},
+	"GET /api/v1/transcription/:id/export/chapters":     {action: models.AuditExport, fields: []string{"format"}},
+	"GET /api/v1/transcription/archive":                 {action: models.AuditExport},
+	"GET /api/v1/action-items/export":                   {action: models.AuditExport, fields: []string{"format"}},
+	"POST /api/v1/transcription/:id/shares":             {action: models.AuditShareCreate, fields: []string{"include_audio", "expires_at"}, created: true},
+	"DELETE /api/v1/transcription/:id/shares/:share_id": {action: models.AuditShareRevoke},
+	"GET /api/v1/shared/:token":                         {action: models.AuditShareView},
+	"POST /api/v1/downloads":                            {action: models.AuditDownloadCreate, fields: []string{"path"}},
+	"GET /api/v1/transcription/:id/audio/url":           {action: models.AuditDownloadCreate},
+	"GET /api/v1/downloads/:token":                      {action: models.AuditDownload},
+	"PUT /api/v1/admin/transcription/:id/legal-hold":    {action: models.AuditLegalHoldChange, fields: []string{"hold", "reason"}},
+
+	"POST /api/v1/rag/chat":                           {action: models.AuditQuery, fields: []string{"query"}},
+	"POST /api/v1/rag/search":                         {action: models.AuditQuery, fields: []string{"query"}},
+	"POST /v1/chat/completions":                       {action: models.AuditQuery, fields: []string{"model"}},
+	"POST /mcp":                                       {action: models.AuditQuery, fields: []string{"method", "params"}},
+	"GET /api/v1/search":                              {action: models.AuditQuery, fields: []string{"q"}},
+	"POST /api/v1/chat/sessions/:session_id/messages": {action: models.AuditQuery, fields: []string{"content"}},
+	"POST /api/v1/transcription/:id/range/ask":        {action: models.AuditQuery, fields: []string{"question"}},
+	"POST /api/v1/companion/sessions/:id/ask":         {action: models.AuditQuery, fields: []string{"question"}},
+}
+
+// auditSkipped are writes too frequent to be worth recording: token refreshes, the parts of
+// resumable uploads (the upload is recorded when it starts) and live companion audio
+var auditSkipped = map[string]bool{
+	"POST /api/v1/auth/refresh":                    true,
+	"PATCH /api/v1/transcription/uploads/:id":      true,
+	"POST /api/v1/companion/sessions/:id/audio":    true,
+	"POST /api/v1/companion/sessions/:id/segments": true,
+}
+
+// auditTargetParams are the route parameters naming the record a request is about, in order
+var auditTargetParams = []string{"id", "transcription_id", "session_id", "note_id"}
+
+// newAuditRecorder builds the audit log recorder with the configured retention
+func newAuditRecorder(cfg *config.Config) *audit.Recorder {
+	if cfg == nil {
+		return audit.NewRecorder(0)
+	}
+	return audit.NewRecorder(time.Duration(cfg.AuditLogRetentionDays) * 24 * time.Hour)
+}
+
+// setAuditTarget names the record a request is about, for requests whose route doesn't
+func setAuditTarget(c *gin.Context, id string) {
+	c.Set("audit_target", id)
+}
+
+// auditResponseWriter keeps the start of a response, to read the id of what it created
+type auditResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditResponseWriter) Write(data []byte) (int, error) {
+	if room := maxAuditBody - w.body.Len(); room > 0 {
+		if len(data) < room {
+			room = len(data)
+		}
+		w.body.Write(data[:room])
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// auditLog records security-relevant and data-changing requests in the audit log: the routes
+// in auditRoutes, and every other POST, PUT, PATCH or DELETE. It runs before authentication
+// and records once the request is answered, when the caller is known.
+func (h *Handler) auditLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.Request.Method + " " + c.FullPath()
+		route, listed := auditRoutes[key]
+		if !listed {
+			if c.FullPath() == "" || auditSkipped[key] || c.Request.Method == http.MethodGet ||
+				c.Request.Method == http.MethodHead || c.Request.Method == http.MethodOptions {
+				c.Next()
+				return
+			}
+			route = auditRoute{action: models.AuditChange}
+		}
+
+		details := map[string]interface{}{}
+		if len(route.fields) > 0 {
+			auditFields(c, route.fields, details)
+		}
+		var writer *auditResponseWriter
+		if route.created {
+			writer = &auditResponseWriter{ResponseWriter: c.Writer}
+			c.Writer = writer
+		}
+
+		c.Next()
+
+		entry := &models.AuditLog{
+			AuthType:  c.GetString("auth_type"),
+			Username:  c.GetString("username"),
+			UserID:    currentUserID(c),
+			Action:    route.action,
+			Method:    c.Request.Method,
+			Route:     c.FullPath(),
+			TargetID:  c.GetString("audit_target"),
+			Status:    c.Writer.Status(),
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		}
+		if id, ok := c.Get("api_key_id"); ok {
+			if keyID, ok := id.(uint); ok {
+				entry.APIKeyID = &keyID
+			}
+		}
+		targetParam := ""
+		for _, param := range c.Params {
+			if param.Key == "token" {
+				continue // Share and download tokens are credentials
+			}
+			if entry.TargetID == "" && isAuditTargetParam(param.Key) {
+				entry.TargetID, targetParam = param.Value, param.Key
+			} else if param.Value != entry.TargetID {
+				details[param.Key] = param.Value
+			}
+		}
+		if writer != nil && entry.Status < http.StatusBadRequest {
+			var created struct {
+				ID json.RawMessage `json:"id"`
+			}
+			if json.Unmarshal(writer.body.Bytes(), &created) == nil && len(created.ID) > 0 {
+				// What was created becomes the target, and what it was created under a detail
+				if targetParam != "" {
+					details[targetParam] = entry.TargetID
+				}
+				entry.TargetID = strings.Trim(string(created.ID), `"`)
+			}
+		}
+		if form := c.Request.MultipartForm; form != nil {
+			var files []string
+			for _, headers := range form.File {
+				for _, header := range headers {
+					files = append(files, header.Filename)
+				}
+			}
+			if len(files) > 0 {
+				details["files"] = files
+			}
+		}
+		if len(details) > 0 {
+			entry.Details = details
+		}
+		h.auditRecorder.Record(entry)
+	}
+}
+
+// isAuditTargetParam reports whether a route parameter names the record a request is about
+func isAuditTargetParam(name string) bool {
+	for _, param := range auditTargetParams {
+		if param == name {
+			return true
+		}
+	}
+	return false
+}
+
+// auditFields copies fields from the query and JSON body into details. The body is read ahead
+// and put back for the handler.
+func auditFields(c *gin.Context, fields []string, details map[string]interface{}) {
+	query := c.Request.URL.Query()
+	var body map[string]interface{}
+	if c.Request.Body != nil && strings.HasPrefix(c.ContentType(), "application/json") {
+		data, _ := io.ReadAll(io.LimitReader(c.Request.Body, maxAuditBody))
+		c.Request.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(data), c.Request.Body), c.Request.Body}
+		_ = json.Unmarshal(data, &body)
+	}
+	for _, field := range fields {
+		var value interface{}
+		if v := query.Get(field); v != "" {
+			value = v
+		} else if v, ok := body[field]; ok && v != nil {
+			value = v
+		} else {
+			continue
+		}
+		if s, ok := value.(string); ok && len(s) > maxAuditValue {
+			value = s[:maxAuditValue] + "..."
+		}
+		details[field] = value
+	}
+}
+
+// ListAuditLog pages through the audit log
+// @Summary List the audit log
+// @Description Page through the audit log, newest first: who uploaded, deleted, exported, shared or queried what and when, logins, API key and account changes, and every other request that changed data, with the caller's IP address and the response status. Entries are kept for AUDIT_LOG_RETENTION_DAYS. Admins only.
+// @Tags admin
+// @Produce json
+// @Param user_id query int false "Only actions by this user"
+// @Param action query string false "Only this action, e.g. transcription.deleted"
+// @Param target_id query string false "Only actions on this record"
+// @Param since query string false "Start of the window, as a duration before until (e.g. 720h) or an RFC 3339 time (default 30 days)"
+// @Param until query string false "End of the window, RFC 3339 (default now)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Entries per page" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/audit-log [get]
+func (h *Handler) ListAuditLog(c *gin.Context) {
+	since, until, ok := statsWindow(c, 30*24*time.Hour)
+	if !ok {
+		return
+	}
+	filter := audit.Query{Action: c.Query("action"), TargetID: c.Query("target_id"), Since: since, Until: until}
+	if raw := c.Query("user_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+			return
+		}
+		userID := uint(id)
+		filter.UserID = &userID
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 1000 {
+		limit = 50
+	}
+
+	query := filter.Apply(database.DB.Model(&models.AuditLog{}))
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count audit log entries"})
+		return
+	}
+	entries := []models.AuditLog{}
+	if err := query.Order("created_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list audit log entries"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"entries":        entries,
+		"retention_days": int(h.auditRecorder.Retention().Hours() / 24),
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
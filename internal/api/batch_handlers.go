@@ -0,0 +1,552 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/batch"
+	"scriberr/internal/database"
+	"scriberr/internal/folders"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/projects"
+	"scriberr/internal/quota"
+	"scriberr/internal/tagging"
+	"scriberr/internal/workflow"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Built-in batch actions
+const (
+	BatchRetag       = "retag"
+	BatchMove        = "move"
+	BatchDelete      = "delete"
+	BatchResummarize = "resummarize"
+	BatchReindex     = "reindex"
+)
+
+// BatchRequest starts a batch operation on the transcriptions listed in ids or matching filter
+type BatchRequest struct {
+	Action string             `json:"action" binding:"required"`
+	IDs    []string           `json:"ids,omitempty"`
+	Filter *BatchFilter       `json:"filter,omitempty"`
+	Params models.BatchParams `json:"params"`
+}
+
+// BatchFilter selects transcriptions by the criteria of a smart folder, plus the transcriptions
+// of a smart folder or project
+type BatchFilter struct {
+	models.SmartFolderFilter
+	FolderID  string `json:"folder_id,omitempty"`
+	ProjectID string `json:"project_id,omitempty"` // "none" for transcriptions in no project
+	// Subprojects also selects the transcriptions of the projects in ProjectID
+	Subprojects bool `json:"subprojects,omitempty"`
+}
+
+// empty reports whether the filter has no criteria and would select every transcription
+func (f *BatchFilter) empty() bool {
+	filter := f.SmartFolderFilter
+	return f.FolderID == "" && f.ProjectID == "" && len(filter.Tags) == 0 && len(filter.Speakers) == 0 &&
+		filter.Text == "" && filter.Status == "" && filter.CreatedAfter == nil && filter.CreatedBefore == nil
+}
+
+// SetBatchService sets the batch operation service and registers the built-in actions with it
+func (h *Handler) SetBatchService(service *batch.Service) {
+	h.batches = service
+	if service == nil {
+		return
+	}
+	service.Register(batch.Action{
+		Name:        BatchRetag,
+		Description: "Replace the tags of each transcription with tags, or add add_tags and remove remove_tags, keeping the others",
+		Apply:       h.batchRetag,
+		Validate: func(params *models.BatchParams) error {
+			if params.Tags == nil && len(params.AddTags) == 0 && len(params.RemoveTags) == 0 {
+				return errors.New("give tags, or add_tags, remove_tags or both")
+			}
+			if params.Tags != nil && (len(params.AddTags) > 0 || len(params.RemoveTags) > 0) {
+				return errors.New("tags replaces every tag and can't be combined with add_tags or remove_tags")
+			}
+			return nil
+		},
+	})
+	service.Register(batch.Action{
+		Name:        BatchMove,
+		Description: "File each transcription in the project project_id, or take it out of its project without one",
+		Apply:       h.batchMove,
+	})
+	service.Register(batch.Action{
+		Name:        BatchDelete,
+		Description: "Delete each transcription with its audio and vector store documents; those under legal hold or being transcribed are skipped",
+		Apply:       h.batchDelete,
+	})
+	service.Register(batch.Action{
+		Name:        BatchResummarize,
+		Description: "Generate the summary of each completed transcription again, with model, format and template_id overriding the defaults, and re-index those in RAG",
+		Apply:       h.batchResummarize,
+		Validate: func(params *models.BatchParams) error {
+			if params.Format != "" && params.Format != workflow.SummaryFormatText && params.Format != workflow.SummaryFormatStructured {
+				return errors.New("format must be text or structured")
+			}
+			return nil
+		},
+	})
+	service.Register(batch.Action{
+		Name:        BatchReindex,
+		Description: "Index each transcription's current transcript and summary in RAG again",
+		Apply:       h.batchReindex,
+	})
+}
+
+// batchJob loads a transcription of a batch operation
+func batchJob(jobID string) (*models.TranscriptionJob, error) {
+	var job models.TranscriptionJob
+	if err := database.DB.Where("id = ?", jobID).First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("Transcription not found")
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (h *Handler) batchRetag(ctx context.Context, op *models.BatchOperation, jobID string) error {
+	job, err := batchJob(jobID)
+	if err != nil {
+		return err
+	}
+	names := op.Params.Tags
+	if names == nil {
+		names = append(names, job.Tags...)
+		names = append(names, op.Params.AddTags...)
+		kept := names[:0]
+		for _, name := range names {
+			removed := false
+			for _, remove := range op.Params.RemoveTags {
+				if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(remove)) {
+					removed = true
+					break
+				}
+			}
+			if !removed {
+				kept = append(kept, name)
+			}
+		}
+		names = kept
+	}
+	if _, err := tagging.SetJobTags(job, names); err != nil {
+		return fmt.Errorf("failed to update tags: %w", err)
+	}
+	h.refreshRAGMetadata(job.ID)
+	return nil
+}
+
+func (h *Handler) batchMove(ctx context.Context, op *models.BatchOperation, jobID string) error {
+	job, err := batchJob(jobID)
+	if err != nil {
+		return err
+	}
+	return database.DB.Model(job).Update("project_id", op.Params.ProjectID).Error
+}
+
+func (h *Handler) batchDelete(ctx context.Context, op *models.BatchOperation, jobID string) error {
+	job, err := batchJob(jobID)
+	if err != nil {
+		return err
+	}
+	if job.LegalHold {
+		return batch.Skip("Transcription is under legal hold")
+	}
+	if job.Status == models.StatusProcessing {
+		return batch.Skip("Transcription is being processed")
+	}
+	if err := h.deleteIndexedJob(ctx, job.ID); err != nil {
+		if errors.Is(err, errLegalHold) {
+			return batch.Skip("Transcription is under legal hold")
+		}
+		return err
+	}
+	return nil
+}
+
+func (h *Handler) batchResummarize(ctx context.Context, op *models.BatchOperation, jobID string) error {
+	if h.llmRegistry == nil {
+		return errors.New("LLM providers not initialized")
+	}
+	service, model, err := h.llmRegistry.For(llm.FeatureSummary)
+	if err != nil {
+		return err
+	}
+	if op.Params.Model != "" {
+		model = op.Params.Model
+	}
+	format := op.Params.Format
+	if format == "" && h.config != nil {
+		format = h.config.SummaryFormat
+	}
+
+	job, err := batchJob(jobID)
+	if err != nil {
+		return err
+	}
+	if job.Status != models.StatusCompleted {
+		return batch.Skip("Transcription is not completed")
+	}
+	transcript, err := workflow.TranscriptText(job)
+	if err != nil {
+		return batch.Skip(err.Error())
+	}
+	templateID := ""
+	if op.Params.TemplateID != nil {
+		templateID = *op.Params.TemplateID
+	}
+	template, err := workflow.ResolveSummaryTemplate(job, templateID)
+	if err != nil {
+		return err
+	}
+
+	// Each summary counts against the quota of whoever started the operation
+	user, err := limitedUser(op.UserID)
+	if err != nil {
+		return err
+	}
+	if user != nil {
+		if err := h.quotas.Check(user, models.QuotaLLMCalls); err != nil {
+			return err
+		}
+	}
+	_, err = workflow.GenerateSummary(ctx, h.observeLLM(llm.FeatureSummary, "", service), job, transcript, workflow.SummaryOptions{
+		Model:       model,
+		Temperature: 0.7,
+		Format:      format,
+		Template:    template,
+		Source:      "batch",
+		RequestedBy: op.UserID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate summary: %w", err)
+	}
+	if user != nil {
+		if err := quota.Add(user.ID, models.QuotaLLMCalls, 1); err != nil {
+			return fmt.Errorf("summary saved, but failed to count it against the quota: %w", err)
+		}
+	}
+	if _, err := h.reindexIfIndexed(job.ID); err != nil {
+		return fmt.Errorf("summary saved, but failed to re-index it: %w", err)
+	}
+	return nil
+}
+
+func (h *Handler) batchReindex(ctx context.Context, op *models.BatchOperation, jobID string) error {
+	if h.ragService == nil {
+		return errors.New("RAG service not initialized")
+	}
+	job, err := batchJob(jobID)
+	if err != nil {
+		return err
+	}
+	if job.Transcript == nil || strings.TrimSpace(*job.Transcript) == "" {
+		return batch.Skip("Transcription has no transcript")
+	}
+	return h.storeJobInRAG(job)
+}
+
+// requireBatches writes an error response when batch operations aren't running
+func (h *Handler) requireBatches(c *gin.Context) bool {
+	if h.batches == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Batch operations not initialized"})
+		return false
+	}
+	return true
+}
+
+// batchScope returns the transcriptions the caller may include in a batch operation: their
+// own, and for admins also those without an owner, as listed by ListJobs
+func batchScope(c *gin.Context) *gorm.DB {
+	query := database.DB.Model(&models.TranscriptionJob{}).Where("transcription_jobs.id NOT LIKE 'track_%'")
+	if userID := currentUserID(c); isAdmin(c) && userID != nil {
+		return query.Where("(transcription_jobs.user_id = ? OR transcription_jobs.user_id IS NULL)", *userID)
+	}
+	return scopeToOwner(query, currentUserID(c))
+}
+
+// selectBatchIDs resolves the transcriptions of a batch request, writing an error response if
+// it can't. Listed IDs the caller can't see are returned as failed results.
+func selectBatchIDs(c *gin.Context, req *BatchRequest) ([]string, []models.BatchItemResult, bool) {
+	if req.Filter == nil {
+		seen := make(map[string]bool, len(req.IDs))
+		ids := make([]string, 0, len(req.IDs))
+		for _, id := range req.IDs {
+			if id = strings.TrimSpace(id); id != "" && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) > batch.MaxItems {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A batch operation can include at most %d transcriptions", batch.MaxItems)})
+			return nil, nil, false
+		}
+		var visible []string
+		if err := batchScope(c).Where("transcription_jobs.id IN ?", ids).Pluck("transcription_jobs.id", &visible).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to select transcriptions"})
+			return nil, nil, false
+		}
+		found := make(map[string]bool, len(visible))
+		for _, id := range visible {
+			found[id] = true
+		}
+		selected := ids[:0]
+		var missing []models.BatchItemResult
+		for _, id := range ids {
+			if found[id] {
+				selected = append(selected, id)
+			} else {
+				missing = append(missing, models.BatchItemResult{TranscriptionID: id, Status: models.BatchItemFailed, Message: "Transcription not found"})
+			}
+		}
+		return selected, missing, true
+	}
+
+	filter := req.Filter
+	if err := folders.Validate(&filter.SmartFolderFilter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	if filter.empty() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "filter must have at least one criterion"})
+		return nil, nil, false
+	}
+	query := folders.Apply(batchScope(c), filter.SmartFolderFilter)
+	if filter.FolderID != "" {
+		folder, ok := loadFolder(c, filter.FolderID)
+		if !ok {
+			return nil, nil, false
+		}
+		query = folders.Apply(query, folder.Filter)
+	}
+	if filter.ProjectID == "none" {
+		query = query.Where("transcription_jobs.project_id IS NULL")
+	} else if filter.ProjectID != "" {
+		project, ok := loadProject(c, filter.ProjectID, http.StatusNotFound)
+		if !ok {
+			return nil, nil, false
+		}
+		ids := []string{project.ID}
+		if filter.Subprojects {
+			var err error
+			if ids, err = projects.Subtree(project.ID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get subprojects"})
+				return nil, nil, false
+			}
+		}
+		query = query.Where("transcription_jobs.project_id IN ?", ids)
+	}
+
+	var ids []string
+	if err := query.Order("transcription_jobs.created_at ASC").Limit(batch.MaxItems+1).Pluck("transcription_jobs.id", &ids).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to select transcriptions"})
+		return nil, nil, false
+	}
+	if len(ids) > batch.MaxItems {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("The filter matches more than %d transcriptions; narrow it down", batch.MaxItems)})
+		return nil, nil, false
+	}
+	return ids, nil, true
+}
+
+// CreateBatchOperation starts an action on many transcriptions in the background
+// @Summary Start a batch operation
+// @Description Retag, move, delete, re-summarize or re-index many transcriptions at once, listed in ids or selected by filter (smart folder criteria, plus folder_id, project_id and subprojects), up to 5000. The operation runs in the background, one transcription at a time; poll it for per-transcription results. Listed IDs that don't exist or belong to someone else are reported as failed.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param request body BatchRequest true "Action, transcriptions and params"
+// @Success 202 {object} models.BatchOperation
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/batch [post]
+func (h *Handler) CreateBatchOperation(c *gin.Context) {
+	if !h.requireBatches(c) {
+		return
+	}
+	var req BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if (len(req.IDs) == 0) == (req.Filter == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Give either ids or filter"})
+		return
+	}
+
+	op := &models.BatchOperation{UserID: currentUserID(c), Action: req.Action, Params: req.Params}
+	if err := h.batches.Validate(op); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	switch req.Action {
+	case BatchMove:
+		if op.Params.ProjectID != nil && *op.Params.ProjectID == "" {
+			op.Params.ProjectID = nil
+		}
+		if op.Params.ProjectID != nil {
+			if _, ok := loadProject(c, *op.Params.ProjectID, http.StatusBadRequest); !ok {
+				return
+			}
+		}
+	case BatchResummarize:
+		if h.llmRegistry == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "LLM providers not initialized"})
+			return
+		}
+		if _, _, err := h.llmRegistry.For(llm.FeatureSummary); err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+		if op.Params.TemplateID != nil && *op.Params.TemplateID != "" {
+			if _, ok := loadSummaryTemplate(c, *op.Params.TemplateID); !ok {
+				return
+			}
+		}
+	case BatchReindex:
+		if h.ragService == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "RAG service not initialized"})
+			return
+		}
+	}
+
+	ids, missing, ok := selectBatchIDs(c, &req)
+	if !ok {
+		return
+	}
+	if len(ids) == 0 && len(missing) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No transcriptions match the filter"})
+		return
+	}
+	op.Results = missing
+	op.Failed = len(missing)
+	if err := h.batches.Start(op, ids); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, op)
+}
+
+// loadBatchOperation loads a batch operation the caller started, writing an error response if
+// it can't. Admins may load every operation.
+func loadBatchOperation(c *gin.Context) (*models.BatchOperation, bool) {
+	query := database.DB.Where("id = ?", c.Param("batch_id"))
+	if !isAdmin(c) {
+		query = scopeToOwner(query, currentUserID(c))
+	}
+	var op models.BatchOperation
+	if err := query.First(&op).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Batch operation not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get batch operation"})
+		}
+		return nil, false
+	}
+	return &op, true
+}
+
+// ListBatchOperations lists the caller's batch operations
+// @Summary List batch operations
+// @Description List the batch operations the caller started, newest first, with their counts but without per-transcription results
+// @Tags transcription
+// @Produce json
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Operations per page (default 20, at most 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/batch [get]
+func (h *Handler) ListBatchOperations(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	var total int64
+	if err := scopeToOwner(database.DB.Model(&models.BatchOperation{}), currentUserID(c)).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count batch operations"})
+		return
+	}
+	ops := []models.BatchOperation{}
+	if err := scopeToOwner(database.DB, currentUserID(c)).Omit("results").Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&ops).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list batch operations"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"operations": ops,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetBatchOperation returns a batch operation with its per-transcription results
+// @Summary Get a batch operation
+// @Description Get a batch operation's status, counts and the outcome for each transcription done so far: succeeded, failed or skipped, with why. Results of a running operation are saved every few transcriptions.
+// @Tags transcription
+// @Produce json
+// @Param batch_id path string true "Batch operation ID"
+// @Success 200 {object} models.BatchOperation
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/batch/{batch_id} [get]
+func (h *Handler) GetBatchOperation(c *gin.Context) {
+	op, ok := loadBatchOperation(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, op)
+}
+
+// CancelBatchOperation stops a running batch operation
+// @Summary Cancel a batch operation
+// @Description Stop a running batch operation after the transcription it is working on. Transcriptions already done stay done.
+// @Tags transcription
+// @Produce json
+// @Param batch_id path string true "Batch operation ID"
+// @Success 202 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/batch/{batch_id}/cancel [post]
+func (h *Handler) CancelBatchOperation(c *gin.Context) {
+	if !h.requireBatches(c) {
+		return
+	}
+	op, ok := loadBatchOperation(c)
+	if !ok {
+		return
+	}
+	if err := h.batches.Cancel(op.ID); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Batch operation is not running"})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Batch operation cancelling"})
+}
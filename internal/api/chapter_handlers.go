@@ -0,0 +1,106 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/export"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListTranscriptionChapters returns the chapters of a transcription
+// @Summary List chapters
+// @Description Get the chapters of a transcription in order, with their titles, summaries and start and end times in seconds, as detected by the generate_chapters workflow step
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Success 200 {array} models.Chapter
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/chapters [get]
+func (h *Handler) ListTranscriptionChapters(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	chapters, err := jobChapters(job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get chapters"})
+		return
+	}
+	c.JSON(http.StatusOK, chapters)
+}
+
+// ExportChapters downloads the chapters of a transcription in a chapter-aware format
+// @Summary Export chapters
+// @Description Download the chapters of a transcription as YouTube description timestamps (youtube), Markdown show notes (markdown), a WebVTT chapters track (webvtt), an FFmpeg metadata file for embedding chapter markers into the audio (ffmetadata) or a Podcasting 2.0 JSON chapters file (podcast)
+// @Tags transcription
+// @Produce plain
+// @Param id path string true "Transcription ID"
+// @Param format query string false "youtube, markdown, webvtt, ffmetadata or podcast (default markdown)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/export/chapters [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ExportChapters(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", export.ChaptersMarkdown))
+	if format == "md" {
+		format = export.ChaptersMarkdown
+	}
+	known := false
+	for _, candidate := range export.ChapterFormats {
+		known = known || candidate == format
+	}
+	if !known {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be one of " + strings.Join(export.ChapterFormats, ", ")})
+		return
+	}
+
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	chapters, err := jobChapters(job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get chapters"})
+		return
+	}
+	if len(chapters) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Chapters have not been generated for this transcription"})
+		return
+	}
+
+	title := ""
+	if job.Title != nil {
+		title = *job.Title
+	}
+	data, contentType, extension, err := export.Chapters(format, title, chapters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render chapters"})
+		return
+	}
+
+	name := title
+	if name == "" {
+		name = job.ID
+	}
+	name = strings.Trim(unsafeFilename.ReplaceAllString(name+".chapters", "_"), "_")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, extension))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// jobChapters returns the chapters of a transcription in order
+func jobChapters(jobID string) ([]models.Chapter, error) {
+	chapters := []models.Chapter{}
+	err := database.DB.Where("transcription_id = ?", jobID).Order("position").Find(&chapters).Error
+	return chapters, err
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -61,8 +62,11 @@ type ChatSessionWithMessages struct {
 	Messages []ChatMessageResponse `json:"messages"`
 }
 
-// getLLMService returns a provider-agnostic LLM service based on active config
+// getLLMService returns a provider-agnostic LLM service based on active config, or the fake provider in fake mode
 func (h *Handler) getLLMService() (llm.Service, string, error) {
+	if h.config != nil && h.config.FakeProviders {
+		return llm.NewFakeService(), llm.ProviderFake, nil
+	}
 	var cfg models.LLMConfig
 	if err := database.DB.Where("is_active = ?", true).First(&cfg).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -70,20 +74,18 @@ func (h *Handler) getLLMService() (llm.Service, string, error) {
 		}
 		return nil, "", fmt.Errorf("failed to get LLM config: %w", err)
 	}
-	switch strings.ToLower(cfg.Provider) {
-	case "openai":
-		if cfg.APIKey == nil || *cfg.APIKey == "" {
-			return nil, cfg.Provider, fmt.Errorf("OpenAI API key not configured")
-		}
-		return llm.NewOpenAIService(*cfg.APIKey), cfg.Provider, nil
-	case "ollama":
-		if cfg.BaseURL == nil || *cfg.BaseURL == "" {
-			return nil, cfg.Provider, fmt.Errorf("Ollama base URL not configured")
-		}
-		return llm.NewOllamaService(*cfg.BaseURL), cfg.Provider, nil
-	default:
-		return nil, cfg.Provider, fmt.Errorf("unsupported LLM provider: %s", cfg.Provider)
+	settings := llm.ProviderConfig{}
+	if cfg.BaseURL != nil {
+		settings.BaseURL = *cfg.BaseURL
 	}
+	if cfg.APIKey != nil {
+		settings.APIKey = *cfg.APIKey
+	}
+	svc, err := llm.NewProvider(cfg.Provider, settings)
+	if err != nil {
+		return nil, cfg.Provider, err
+	}
+	return svc, cfg.Provider, nil
 }
 
 // @Summary Get available chat models
@@ -103,10 +105,7 @@ func (h *Handler) GetChatModels(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	models, err := svc.GetModels(ctx)
+	models, err := svc.GetModels(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch models: " + err.Error()})
 		return
@@ -145,6 +144,10 @@ func (h *Handler) CreateChatSession(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transcription"})
 		return
 	}
+	if !canAccessJob(c, transcription.UserID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcription not found"})
+		return
+	}
 
 	if transcription.Status != models.StatusCompleted || transcription.Transcript == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Transcription must be completed to create a chat session"})
@@ -380,35 +383,71 @@ func (h *Handler) SendChatMessage(c *gin.Context) {
 		return
 	}
 
+	turn, status, err := h.beginChatTurn(sessionID, req.Content)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Set up streaming response
+	c.Header("Content-Type", "text/plain")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("Access-Control-Allow-Origin", "*")
+
+	// Stream the response
+	ctx := c.Request.Context()
+	err = turn.streamReply(ctx, func(content string) {
+		c.Writer.WriteString(content)
+		c.Writer.Flush()
+	})
+	switch {
+	case err == nil:
+	case ctx.Err() != nil:
+		c.Writer.WriteString("\nRequest timeout")
+		c.Writer.Flush()
+	default:
+		c.Writer.WriteString("\nError: " + err.Error())
+		c.Writer.Flush()
+	}
+}
+
+// chatTurn is a user message saved to a chat session, waiting for the assistant's reply
+type chatTurn struct {
+	session  models.ChatSession
+	svc      llm.Service
+	messages []llm.ChatMessage // The conversation so far, with the transcript as system message
+}
+
+// beginChatTurn saves a user message to a chat session and builds the conversation to answer
+// it. On failure it returns the HTTP status that fits the error.
+func (h *Handler) beginChatTurn(sessionID, content string) (*chatTurn, int, error) {
 	// Get chat session
-	var session models.ChatSession
-	if err := database.DB.Preload("Transcription").Where("id = ?", sessionID).First(&session).Error; err != nil {
+	turn := &chatTurn{}
+	if err := database.DB.Preload("Transcription").Where("id = ?", sessionID).First(&turn.session).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Chat session not found"})
-			return
+			return nil, http.StatusNotFound, errors.New("Chat session not found")
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get chat session"})
-		return
+		return nil, http.StatusInternalServerError, errors.New("Failed to get chat session")
 	}
 
 	// Get LLM service
-	svc, _, err := h.getLLMService()
+	svc, provider, err := h.getLLMService()
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+		return nil, http.StatusBadRequest, err
 	}
+	turn.svc = h.observeLLM(llm.FeatureTranscriptChat, provider, svc)
 
 	// Save user message
 	userMessage := models.ChatMessage{
 		SessionID:     sessionID,
 		ChatSessionID: sessionID,
 		Role:          "user",
-		Content:       req.Content,
+		Content:       content,
 	}
 
 	if err := database.DB.Create(&userMessage).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save message"})
-		return
+		return nil, http.StatusInternalServerError, errors.New("Failed to save message")
 	}
 
 	// Check if this is the first user message and update session title
@@ -416,21 +455,18 @@ func (h *Handler) SendChatMessage(c *gin.Context) {
 	database.DB.Model(&models.ChatMessage{}).Where("chat_session_id = ? AND role = ?", sessionID, "user").Count(&messageCount)
 	if messageCount == 1 {
 		// Generate a title based on the first message
-		title := generateChatTitle(req.Content)
-		database.DB.Model(&session).Update("title", title)
+		title := generateChatTitle(content)
+		database.DB.Model(&turn.session).Update("title", title)
 	}
 
 	// Get conversation history
 	var messages []models.ChatMessage
 	database.DB.Where("chat_session_id = ?", sessionID).Order("created_at ASC").Find(&messages)
 
-	// Build OpenAI messages including transcript context
-	var openaiMessages []llm.ChatMessage
-
 	// Add system message with transcript context
-	if session.Transcription.Transcript != nil {
-		systemContent := fmt.Sprintf("You are a helpful assistant analyzing this transcript. Please answer questions and provide insights based on the following transcript:\n\n%s", *session.Transcription.Transcript)
-		openaiMessages = append(openaiMessages, llm.ChatMessage{
+	if turn.session.Transcription.Transcript != nil {
+		systemContent := fmt.Sprintf("You are a helpful assistant analyzing this transcript. Please answer questions and provide insights based on the following transcript:\n\n%s", *turn.session.Transcription.Transcript)
+		turn.messages = append(turn.messages, llm.ChatMessage{
 			Role:    "system",
 			Content: systemContent,
 		})
@@ -438,24 +474,19 @@ func (h *Handler) SendChatMessage(c *gin.Context) {
 
 	// Add conversation history
 	for _, msg := range messages {
-		openaiMessages = append(openaiMessages, llm.ChatMessage{
+		turn.messages = append(turn.messages, llm.ChatMessage{
 			Role:    msg.Role,
 			Content: msg.Content,
 		})
 	}
+	return turn, http.StatusOK, nil
+}
 
-	// Set up streaming response
-	c.Header("Content-Type", "text/plain")
-	c.Header("Cache-Control", "no-cache")
-	c.Header("Connection", "keep-alive")
-	c.Header("Access-Control-Allow-Origin", "*")
-
-	// Stream the response
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
-
+// streamReply streams the assistant's reply to write as it arrives and saves it once complete.
+// If the provider can't stream for this model, the whole reply is requested and written at once.
+func (t *chatTurn) streamReply(ctx context.Context, write func(string)) error {
 	// Use model defaults: do not set temperature explicitly
-	contentChan, errorChan := svc.ChatCompletionStream(ctx, session.Model, openaiMessages, 0.0)
+	contentChan, errorChan := t.svc.ChatCompletionStream(ctx, t.session.Model, t.messages, 0.0)
 
 	var assistantResponse strings.Builder
 	for {
@@ -463,29 +494,11 @@ func (h *Handler) SendChatMessage(c *gin.Context) {
 		case content, ok := <-contentChan:
 			if !ok {
 				// Channel closed, save complete response and return
-				if assistantResponse.Len() > 0 {
-					assistantMessage := models.ChatMessage{
-						SessionID:     sessionID,
-						ChatSessionID: sessionID,
-						Role:          "assistant",
-						Content:       assistantResponse.String(),
-					}
-					database.DB.Create(&assistantMessage)
-
-					// Update session updated_at, message count, and last activity
-					now := time.Now()
-					database.DB.Model(&session).Updates(map[string]interface{}{
-						"updated_at":       now,
-						"last_activity_at": now,
-						"message_count":    gorm.Expr("message_count + ?", 2), // +2 for user + assistant message
-					})
-				}
-				return
+				t.saveReply(assistantResponse.String())
+				return nil
 			}
 
-			// Write content to response
-			c.Writer.WriteString(content)
-			c.Writer.Flush()
+			write(content)
 			assistantResponse.WriteString(content)
 
 		case err := <-errorChan:
@@ -493,51 +506,52 @@ func (h *Handler) SendChatMessage(c *gin.Context) {
 				// If streaming is not supported for this model/org, fall back to non-streaming
 				errStr := err.Error()
 				if strings.Contains(errStr, "\"param\": \"stream\"") || strings.Contains(errStr, "unsupported_value") || strings.Contains(errStr, "must be verified to stream") {
-					resp, err2 := svc.ChatCompletion(ctx, session.Model, openaiMessages, 0.0)
-					if err2 != nil || resp == nil || len(resp.Choices) == 0 {
-						c.Writer.WriteString("\nError: " + err2.Error())
-						c.Writer.Flush()
-						return
+					resp, err := t.svc.ChatCompletion(ctx, t.session.Model, t.messages, 0.0)
+					if err != nil {
+						return err
+					}
+					if resp == nil || len(resp.Choices) == 0 {
+						return errors.New("no response from LLM")
 					}
 					content := resp.Choices[0].Message.Content
-					c.Writer.WriteString(content)
-					c.Writer.Flush()
+					write(content)
 					assistantResponse.WriteString(content)
-
-					if assistantResponse.Len() > 0 {
-						assistantMessage := models.ChatMessage{
-							SessionID:     sessionID,
-							ChatSessionID: sessionID,
-							Role:          "assistant",
-							Content:       assistantResponse.String(),
-						}
-						database.DB.Create(&assistantMessage)
-
-						// Update session updated_at, message count, and last activity
-						now := time.Now()
-						database.DB.Model(&session).Updates(map[string]interface{}{
-							"updated_at":       now,
-							"last_activity_at": now,
-							"message_count":    gorm.Expr("message_count + ?", 2), // +2 for user + assistant message
-						})
-					}
-					return
+					t.saveReply(assistantResponse.String())
+					return nil
 				}
 
 				// Otherwise, return the error to the client
-				c.Writer.WriteString("\nError: " + err.Error())
-				c.Writer.Flush()
-				return
+				return err
 			}
 
 		case <-ctx.Done():
-			c.Writer.WriteString("\nRequest timeout")
-			c.Writer.Flush()
-			return
+			return ctx.Err()
 		}
 	}
 }
 
+// saveReply stores the assistant's reply and bumps the session's activity
+func (t *chatTurn) saveReply(reply string) {
+	if reply == "" {
+		return
+	}
+	assistantMessage := models.ChatMessage{
+		SessionID:     t.session.ID,
+		ChatSessionID: t.session.ID,
+		Role:          "assistant",
+		Content:       reply,
+	}
+	database.DB.Create(&assistantMessage)
+
+	// Update session updated_at, message count, and last activity
+	now := time.Now()
+	database.DB.Model(&t.session).Updates(map[string]interface{}{
+		"updated_at":       now,
+		"last_activity_at": now,
+		"message_count":    gorm.Expr("message_count + ?", 2), // +2 for user + assistant message
+	})
+}
+
 // @Summary Update chat session title
 // @Description Update the title of a chat session
 // @Tags chat
@@ -772,14 +786,14 @@ Return only the title, nothing else.`
 	}
 
 	// Use configured LLM service
-	svc, _, err := h.getLLMService()
+	svc, provider, err := h.getLLMService()
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	svc = h.observeLLM(llm.FeatureTranscriptChat, provider, svc)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 	// Use slightly higher temperature for more creative titles
 	// Use model defaults: do not set temperature explicitly
 	resp, err := svc.ChatCompletion(ctx, session.Model, chatMsgs, 0.0)
@@ -0,0 +1,367 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/companion"
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxCompanionChunkBytes caps the size of one uploaded chunk of meeting audio
+const maxCompanionChunkBytes = 50 << 20
+
+// SetCompanionService sets the meeting companion service (nil when RAG is not configured)
+func (h *Handler) SetCompanionService(service *companion.Service) {
+	h.companionService = service
+}
+
+// CompanionSessionRequest starts a meeting companion session
+type CompanionSessionRequest struct {
+	Title       string `json:"title"`
+	ProfileName string `json:"profile_name"` // Transcription profile for audio chunks; quick transcription defaults otherwise
+}
+
+// CompanionSegmentsRequest adds segments transcribed by the client, timed from the start of the meeting
+type CompanionSegmentsRequest struct {
+	Segments []interfaces.TranscriptSegment `json:"segments" binding:"required,min=1"`
+}
+
+// CompanionAskRequest asks a question during a meeting
+type CompanionAskRequest struct {
+	Question       string `json:"question" binding:"required"`
+	IncludeLibrary bool   `json:"include_library"` // Search the caller's earlier transcriptions as well
+}
+
+// requireCompanion writes a 503 response and returns false if the companion service is not set up
+func (h *Handler) requireCompanion(c *gin.Context) bool {
+	if h.companionService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Meeting companion requires RAG to be configured"})
+		return false
+	}
+	return true
+}
+
+// companionError writes the response for a companion service error
+func companionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, companion.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+	case errors.Is(err, companion.ErrEnded):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, companion.ErrBusy):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// CreateCompanionSession starts a meeting companion session
+// @Summary Start a meeting companion session
+// @Description Start transcribing a meeting while it happens. Send audio chunks or client-side transcript segments as the meeting goes, poll the session for the transcript and running notes, and ask questions about what was said so far.
+// @Tags companion
+// @Accept json
+// @Produce json
+// @Param request body CompanionSessionRequest false "Session options"
+// @Success 201 {object} companion.Session
+// @Failure 400 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/companion/sessions [post]
+func (h *Handler) CreateCompanionSession(c *gin.Context) {
+	if !h.requireCompanion(c) {
+		return
+	}
+	var req CompanionSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	params := defaultQuickParams()
+	if req.ProfileName != "" {
+		var profile models.TranscriptionProfile
+		if err := database.DB.Where("name = ?", req.ProfileName).First(&profile).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Profile '%s' not found", req.ProfileName)})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load profile"})
+			return
+		}
+		params = profile.Parameters
+	}
+
+	c.JSON(http.StatusCreated, h.companionService.Create(currentUserID(c), req.Title, params))
+}
+
+// ListCompanionSessions returns the caller's companion sessions
+// @Summary List meeting companion sessions
+// @Description List the caller's active and recently ended companion sessions, newest first, without their transcripts
+// @Tags companion
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/companion/sessions [get]
+func (h *Handler) ListCompanionSessions(c *gin.Context) {
+	if !h.requireCompanion(c) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"sessions": h.companionService.List(currentUserID(c))})
+}
+
+// GetCompanionSession polls a companion session
+// @Summary Poll a meeting companion session
+// @Description Get a session's status, running notes and transcript. Pass the cursor of the previous poll as since to get only the segments added after it.
+// @Tags companion
+// @Produce json
+// @Param id path string true "Session ID"
+// @Param since query int false "Segment cursor from the previous poll (default 0, the whole transcript)"
+// @Success 200 {object} companion.Session
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/companion/sessions/{id} [get]
+func (h *Handler) GetCompanionSession(c *gin.Context) {
+	if !h.requireCompanion(c) {
+		return
+	}
+	since := 0
+	if value := c.Query("since"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be a non-negative integer"})
+			return
+		}
+		since = parsed
+	}
+	session, err := h.companionService.Get(c.Param("id"), currentUserID(c), since)
+	if err != nil {
+		companionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// AddCompanionAudio queues a chunk of meeting audio
+// @Summary Send a chunk of meeting audio
+// @Description Queue a chunk of meeting audio for transcription. Chunks are transcribed one at a time in the order they arrive; poll the session for the resulting segments.
+// @Tags companion
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Session ID"
+// @Param audio formData file true "Audio chunk"
+// @Param offset formData number false "Seconds from the start of the meeting to the start of the chunk (default: the end of the transcript so far)"
+// @Success 202 {object} companion.Session
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/companion/sessions/{id}/audio [post]
+func (h *Handler) AddCompanionAudio(c *gin.Context) {
+	if !h.requireCompanion(c) {
+		return
+	}
+	file, header, err := c.Request.FormFile("audio")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Audio file is required"})
+		return
+	}
+	defer file.Close()
+
+	var offset *float64
+	if value := c.PostForm("offset"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative number of seconds"})
+			return
+		}
+		offset = &parsed
+	}
+
+	audio, err := io.ReadAll(io.LimitReader(file, maxCompanionChunkBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read audio"})
+		return
+	}
+	if len(audio) > maxCompanionChunkBytes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Audio chunks are limited to %d MB", maxCompanionChunkBytes>>20)})
+		return
+	}
+
+	session, err := h.companionService.AddAudio(c.Param("id"), currentUserID(c), audio, header.Filename, offset)
+	if err != nil {
+		companionError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, session)
+}
+
+// AddCompanionSegments adds transcript segments recognized by the client
+// @Summary Send transcript segments
+// @Description Add segments transcribed by the client, such as by a streaming recognizer, timed from the start of the meeting. The response holds the segments just added.
+// @Tags companion
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID"
+// @Param request body CompanionSegmentsRequest true "Segments"
+// @Success 200 {object} companion.Session
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/companion/sessions/{id}/segments [post]
+func (h *Handler) AddCompanionSegments(c *gin.Context) {
+	if !h.requireCompanion(c) {
+		return
+	}
+	var req CompanionSegmentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for _, segment := range req.Segments {
+		if segment.Start < 0 || segment.End < segment.Start {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "segments need 0 <= start <= end"})
+			return
+		}
+	}
+
+	session, err := h.companionService.AddSegments(c.Param("id"), currentUserID(c), req.Segments)
+	if err != nil {
+		companionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// SummarizeCompanionSession brings a session's notes up to date
+// @Summary Update the running notes
+// @Description Fold the segments added since the last update into the session's running notes now, instead of waiting for the next periodic update
+// @Tags companion
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} companion.Session
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/companion/sessions/{id}/summarize [post]
+func (h *Handler) SummarizeCompanionSession(c *gin.Context) {
+	if !h.requireCompanion(c) {
+		return
+	}
+	session, err := h.companionService.Summarize(c.Request.Context(), c.Param("id"), currentUserID(c))
+	if err != nil {
+		companionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// AskCompanion answers a question during a meeting
+// @Summary Ask about the meeting
+// @Description Answer a question from the meeting's transcript so far and its running notes, and with include_library from the caller's earlier transcriptions too
+// @Tags companion
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID"
+// @Param request body CompanionAskRequest true "Question"
+// @Success 200 {object} companion.Answer
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/companion/sessions/{id}/ask [post]
+func (h *Handler) AskCompanion(c *gin.Context) {
+	if !h.requireCompanion(c) {
+		return
+	}
+	var req CompanionAskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	question := strings.TrimSpace(req.Question)
+	if question == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "question is required"})
+		return
+	}
+
+	answer, err := h.companionService.Ask(c.Request.Context(), c.Param("id"), currentUserID(c), question, req.IncludeLibrary)
+	if err != nil {
+		companionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, answer)
+}
+
+// EndCompanionSession ends a companion session
+// @Summary End a meeting companion session
+// @Description Stop taking audio and segments. Chunks already sent are still transcribed and the notes are updated one last time; poll until the status is ended for the final transcript and notes. The session's temporary index is then dropped.
+// @Tags companion
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 202 {object} companion.Session
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/companion/sessions/{id}/end [post]
+func (h *Handler) EndCompanionSession(c *gin.Context) {
+	if !h.requireCompanion(c) {
+		return
+	}
+	session, err := h.companionService.End(c.Param("id"), currentUserID(c))
+	if err != nil {
+		companionError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, session)
+}
+
+// DeleteCompanionSession discards a companion session
+// @Summary Discard a meeting companion session
+// @Description Discard a session, its transcript and notes, without transcribing the chunks still queued
+// @Tags companion
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/companion/sessions/{id} [delete]
+func (h *Handler) DeleteCompanionSession(c *gin.Context) {
+	if !h.requireCompanion(c) {
+		return
+	}
+	if err := h.companionService.Delete(c.Param("id"), currentUserID(c)); err != nil {
+		companionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Session deleted"})
+}
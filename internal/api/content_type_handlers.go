@@ -0,0 +1,93 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contentTypeError is returned for a content type a recording can't have
+const contentTypeError = "content_type must be meeting, voice_memo or podcast"
+
+// contentTypeFromForm reads the content type of an upload, a meeting unless given, writing an
+// error response if it is unknown
+func contentTypeFromForm(c *gin.Context) (string, bool) {
+	contentType := strings.TrimSpace(getFormValueWithDefault(c, "content_type", models.ContentMeeting))
+	if !models.IsRecordingContentType(contentType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": contentTypeError})
+		return "", false
+	}
+	return contentType, true
+}
+
+// ContentTypeRequest sets the content type of a transcription
+type ContentTypeRequest struct {
+	ContentType string `json:"content_type" binding:"required"` // meeting, voice_memo or podcast
+}
+
+// UpdateTranscriptionContentType changes what kind of recording a transcription is
+// @Summary Update transcription content type
+// @Description Set whether a transcription is a meeting, voice memo or podcast. The content type decides the RAG collection the transcription is stored in, how it is chunked and how it is introduced to the LLM in chat, so an indexed transcription is indexed again.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body ContentTypeRequest true "Content type"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/content-type [put]
+func (h *Handler) UpdateTranscriptionContentType(c *gin.Context) {
+	var req ContentTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !models.IsRecordingContentType(req.ContentType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": contentTypeError})
+		return
+	}
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+
+	// Whether the transcription is indexed is looked up where its current content type lives
+	indexed := false
+	if h.ragService != nil && job.ContentType != req.ContentType {
+		var err error
+		if indexed, err = h.ragService.IsIndexed(job.ID); err != nil {
+			logger.ErrorContext(c.Request.Context(), "Failed to check whether transcription is indexed", "job_id", job.ID, "error", err)
+		}
+	}
+
+	if err := database.DB.Model(job).Update("content_type", req.ContentType).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update content type"})
+		return
+	}
+	job.ContentType = req.ContentType
+
+	reindexed := false
+	if indexed {
+		if err := h.storeJobInRAG(job); err != nil {
+			// The audit reports the transcription as missing from its new collection
+			logger.ErrorContext(c.Request.Context(), "Failed to re-index transcription under its new content type", "job_id", job.ID, "content_type", req.ContentType, "error", err)
+		} else {
+			reindexed = true
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":           job.ID,
+		"content_type": job.ContentType,
+		"reindexed":    reindexed,
+	})
+}
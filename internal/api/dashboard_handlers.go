@@ -0,0 +1,72 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"scriberr/internal/dashboard"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAdminStats aggregates transcription activity for the admin dashboard
+// @Summary Get dashboard statistics
+// @Description Aggregate the transcriptions created in a window: job counts, hours of audio processed, average processing time, per-model usage and the most used tags, plus a time series of the same for charts (every bucket is listed, including empty ones). Storage usage of the upload directory and database covers everything kept, not just the window.
+// @Tags admin
+// @Produce json
+// @Param since query string false "Start of the window, as a duration before until (e.g. 720h) or an RFC 3339 time (default 30 days)"
+// @Param until query string false "End of the window, RFC 3339 (default now)"
+// @Param bucket query string false "Time series bucket width, e.g. 1h or 24h (default hourly for windows up to 48h, then daily, then weekly)"
+// @Param top_tags query int false "How many tags to list (default 10)"
+// @Success 200 {object} dashboard.Report
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/stats [get]
+func (h *Handler) GetAdminStats(c *gin.Context) {
+	since, until, ok := statsWindow(c, 30*24*time.Hour)
+	if !ok {
+		return
+	}
+
+	bucket := dashboard.DefaultBucket(until.Sub(since))
+	if raw := c.Query("bucket"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bucket must be a duration"})
+			return
+		}
+		bucket = parsed
+	}
+
+	topTags := dashboard.DefaultTopTags
+	if raw := c.Query("top_tags"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "top_tags must be between 1 and 100"})
+			return
+		}
+		topTags = parsed
+	}
+
+	report, err := dashboard.Summarize(dashboard.Query{
+		Since:        since,
+		Until:        until,
+		Bucket:       bucket,
+		TopTags:      topTags,
+		UploadDir:    h.config.UploadDir,
+		DatabasePath: h.config.DatabasePath,
+	})
+	if err != nil {
+		if errors.Is(err, dashboard.ErrInvalidQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
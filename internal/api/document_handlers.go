@@ -0,0 +1,269 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/documents"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxDocumentSize caps uploaded document files
+const maxDocumentSize = 50 << 20
+
+// SetDocumentIngester enables document uploads for RAG
+func (h *Handler) SetDocumentIngester(ingester *documents.Ingester) {
+	h.documentIngester = ingester
+}
+
+// ingestDocument indexes a document in the background
+func (h *Handler) ingestDocument(documentID string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		// Errors are recorded on the document itself
+		_ = h.documentIngester.Ingest(ctx, documentID)
+	}()
+}
+
+// findDocument loads a document owned by the caller, writing a 404 if there is none
+func (h *Handler) findDocument(c *gin.Context) (*models.Document, bool) {
+	var doc models.Document
+	if err := scopeToOwner(database.DB, currentUserID(c)).Where("id = ?", c.Param("id")).First(&doc).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get document"})
+		}
+		return nil, false
+	}
+	return &doc, true
+}
+
+// UploadDocument uploads a text document for RAG, optionally linked to a recording
+// @Summary Upload a document
+// @Description Upload a text document (.txt, .md or .pdf) such as an agenda or notes. Its text is extracted, summarized, chunked and indexed into the caller's RAG collection so chat can combine it with transcripts.
+// @Tags documents
+// @Accept multipart/form-data
+// @Produce json
+// @Param document formData file true "Document file"
+// @Param title formData string false "Document title (defaults to the file name)"
+// @Param transcription_id formData string false "Recording to link the document to"
+// @Success 202 {object} models.Document
+// @Failure 400 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/documents [post]
+func (h *Handler) UploadDocument(c *gin.Context) {
+	if h.documentIngester == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Document indexing requires RAG to be configured"})
+		return
+	}
+
+	file, header, err := c.Request.FormFile("document")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Document file is required"})
+		return
+	}
+	defer file.Close()
+
+	if !documents.IsSupported(header.Filename) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unsupported document type, expected one of %s", strings.Join(documents.SupportedExtensions, ", "))})
+		return
+	}
+	if header.Size > maxDocumentSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Document is too large (max 50MB)"})
+		return
+	}
+
+	var transcriptionID *string
+	if id := strings.TrimSpace(c.PostForm("transcription_id")); id != "" {
+		var count int64
+		if err := database.DB.Model(&models.TranscriptionJob{}).Where("id = ?", id).Count(&count).Error; err != nil || count == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Linked transcription not found"})
+			return
+		}
+		transcriptionID = &id
+	}
+
+	uploadDir := filepath.Join(h.config.UploadDir, "documents")
+	if err := os.MkdirAll(uploadDir, 0755); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload directory"})
+		return
+	}
+
+	documentID := uuid.New().String()
+	filePath := filepath.Join(uploadDir, documentID+strings.ToLower(filepath.Ext(header.Filename)))
+	dst, err := os.Create(filePath)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
+		return
+	}
+	size, err := io.Copy(dst, file)
+	dst.Close()
+	if err != nil {
+		os.Remove(filePath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
+		return
+	}
+
+	// Extract up front so unreadable files are rejected instead of failing later
+	text, err := documents.ExtractText(filePath)
+	if err != nil {
+		os.Remove(filePath)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read document: " + err.Error()})
+		return
+	}
+
+	title := strings.TrimSpace(c.PostForm("title"))
+	if title == "" {
+		title = strings.TrimSuffix(header.Filename, filepath.Ext(header.Filename))
+	}
+
+	doc := models.Document{
+		ID:              documentID,
+		UserID:          currentUserID(c),
+		TranscriptionID: transcriptionID,
+		Title:           title,
+		FileName:        header.Filename,
+		ContentType:     header.Header.Get("Content-Type"),
+		FilePath:        filePath,
+		Size:            size,
+		Content:         &text,
+		Status:          models.DocumentProcessing,
+	}
+	if err := database.DB.Create(&doc).Error; err != nil {
+		os.Remove(filePath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save document"})
+		return
+	}
+
+	h.ingestDocument(doc.ID)
+	c.JSON(http.StatusAccepted, doc)
+}
+
+// ListDocuments lists the caller's uploaded documents
+// @Summary List documents
+// @Description List uploaded documents, optionally only those linked to a recording. Extracted text is omitted.
+// @Tags documents
+// @Produce json
+// @Param transcription_id query string false "Only documents linked to this recording"
+// @Success 200 {array} models.Document
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/documents [get]
+func (h *Handler) ListDocuments(c *gin.Context) {
+	query := scopeToOwner(database.DB, currentUserID(c)).Omit("content")
+	if id := c.Query("transcription_id"); id != "" {
+		query = query.Where("transcription_id = ?", id)
+	}
+
+	var docs []models.Document
+	if err := query.Order("created_at DESC").Find(&docs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list documents"})
+		return
+	}
+	c.JSON(http.StatusOK, docs)
+}
+
+// GetDocument returns an uploaded document with its extracted text and summary
+// @Summary Get a document
+// @Tags documents
+// @Produce json
+// @Param id path string true "Document ID"
+// @Success 200 {object} models.Document
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/documents/{id} [get]
+func (h *Handler) GetDocument(c *gin.Context) {
+	doc, ok := h.findDocument(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, doc)
+}
+
+// ReindexDocument summarizes and indexes a document again
+// @Summary Re-index a document
+// @Tags documents
+// @Produce json
+// @Param id path string true "Document ID"
+// @Success 202 {object} models.Document
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/documents/{id}/reindex [post]
+func (h *Handler) ReindexDocument(c *gin.Context) {
+	if h.documentIngester == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Document indexing requires RAG to be configured"})
+		return
+	}
+	doc, ok := h.findDocument(c)
+	if !ok {
+		return
+	}
+
+	doc.Status = models.DocumentProcessing
+	doc.Error = nil
+	if err := database.DB.Model(doc).Updates(map[string]interface{}{"status": doc.Status, "error": nil}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update document"})
+		return
+	}
+
+	h.ingestDocument(doc.ID)
+	c.JSON(http.StatusAccepted, doc)
+}
+
+// DeleteDocument deletes an uploaded document and removes it from RAG
+// @Summary Delete a document
+// @Tags documents
+// @Param id path string true "Document ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/documents/{id} [delete]
+func (h *Handler) DeleteDocument(c *gin.Context) {
+	doc, ok := h.findDocument(c)
+	if !ok {
+		return
+	}
+
+	// Vector store cleanup is best-effort; the RAG audit removes anything left behind
+	if h.documentIngester != nil {
+		if err := h.documentIngester.Remove(doc); err != nil {
+			logger.ErrorContext(c.Request.Context(), "Failed to remove document from RAG", "document_id", doc.ID, "error", err)
+		}
+	}
+	if err := os.Remove(doc.FilePath); err != nil && !os.IsNotExist(err) {
+		logger.ErrorContext(c.Request.Context(), "Failed to delete document file", "document_id", doc.ID, "path", doc.FilePath, "error", err)
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("document_id = ?", doc.ID).Delete(&models.RAGAnswerSource{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(doc).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete document"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Document deleted successfully"})
+}
@@ -0,0 +1,265 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// downloadTarget is an API endpoint a signed URL can stand for. The pattern's first
+// submatch, if any, is the transcription ID.
+type downloadTarget struct {
+	pattern *regexp.Regexp
+	handler func(h *Handler, c *gin.Context)
+}
+
+// downloadTargets are the audio and export endpoints that signed URLs can download
+var downloadTargets = []downloadTarget{
+	{regexp.MustCompile(`^/api/v1/transcription/([^/]+)/audio$`), (*Handler).GetAudioFile},
+	{regexp.MustCompile(`^/api/v1/transcription/([^/]+)/export$`), (*Handler).ExportTranscript},
+	{regexp.MustCompile(`^/api/v1/transcription/([^/]+)/export/bilingual$`), (*Handler).ExportBilingual},
+	{regexp.MustCompile(`^/api/v1/transcription/([^/]+)/export/chapters$`), (*Handler).ExportChapters},
+	{regexp.MustCompile(`^/api/v1/action-items/export$`), (*Handler).ExportActionItems},
+}
+
+// CreateDownloadLinkRequest asks for a signed URL for an audio or export download
+type CreateDownloadLinkRequest struct {
+	// Path is the API request to sign, with its query, e.g. /api/v1/transcription/ID/export/chapters?format=youtube
+	Path       string `json:"path" binding:"required"`
+	TTLSeconds int    `json:"ttl_seconds,omitempty"`
+	OneTime    bool   `json:"one_time,omitempty"`
+	// Scan checks the transcript for sensitive passages first; it is always done when SHARE_SCAN is set
+	Scan bool `json:"scan,omitempty"`
+	// ConfirmedPassages are the IDs of flagged passages the caller confirms can be shared
+	ConfirmedPassages []string `json:"confirmed_passages,omitempty"`
+}
+
+// DownloadLinkResponse is a new signed URL
+type DownloadLinkResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+	OneTime   bool      `json:"one_time"`
+	Scanned   bool      `json:"scanned"` // The transcript was scanned and every flagged passage confirmed
+}
+
+// CreateDownloadLink signs a short-lived URL for an audio or export download
+// @Summary Create a signed download URL
+// @Description Create a URL that downloads a transcription's audio, a subtitle, document, bilingual or chapters export, or the action items export without API credentials, so it can be handed to a browser or media player. The link expires after ttl_seconds (SIGNED_URL_TTL_SECONDS by default, at most SIGNED_URL_MAX_TTL_SECONDS), and a one-time link works for a single request. The download runs as the caller. The URL is absolute when PUBLIC_URL is set. With scan, or always when SHARE_SCAN is set, a transcription's transcript is first scanned for personal data and the terms in SHARE_SENSITIVE_TERMS; if anything is found, the answer is 409 with the flagged passages, and the link is only signed once each passage's ID is sent back in confirmed_passages.
+// @Tags downloads
+// @Accept json
+// @Produce json
+// @Param request body CreateDownloadLinkRequest true "Request to sign"
+// @Success 201 {object} DownloadLinkResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} ShareScanResponse
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/downloads [post]
+func (h *Handler) CreateDownloadLink(c *gin.Context) {
+	var req CreateDownloadLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	target, err := url.Parse(req.Path)
+	if err != nil || target.Scheme != "" || target.Host != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "path must be an API path such as /api/v1/transcription/ID/audio"})
+		return
+	}
+	_, match := findDownloadTarget(target.Path)
+	if match == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only audio and export downloads can be signed"})
+		return
+	}
+	var job *models.TranscriptionJob
+	if len(match) > 1 {
+		var found models.TranscriptionJob
+		if err := database.DB.Where("id = ?", match[1]).Limit(1).Find(&found).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transcription"})
+			return
+		}
+		if found.ID == "" || !canAccessJob(c, found.UserID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transcription not found"})
+			return
+		}
+		job = &found
+	}
+
+	// Nothing is signed until every sensitive passage in the transcript has been confirmed
+	var scanned bool
+	var confirmed []string
+	if job != nil {
+		var ok bool
+		if scanned, confirmed, ok = h.confirmShare(c, job, req.Scan, req.ConfirmedPassages); !ok {
+			return
+		}
+	}
+
+	ttl, maxTTL := 300, 86400
+	if h.config != nil && h.config.SignedURLTTLSeconds > 0 {
+		ttl = h.config.SignedURLTTLSeconds
+	}
+	if h.config != nil && h.config.SignedURLMaxTTLSeconds > 0 {
+		maxTTL = h.config.SignedURLMaxTTLSeconds
+	}
+	if req.TTLSeconds < 0 || req.TTLSeconds > maxTTL {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("ttl_seconds must be between 1 and %d", maxTTL)})
+		return
+	}
+	if req.TTLSeconds > 0 {
+		ttl = req.TTLSeconds
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create download link"})
+		return
+	}
+	token := hex.EncodeToString(secret)
+	now := time.Now()
+	// Forget links that expired a day ago; until then they answer 410 rather than 404
+	database.DB.Where("expires_at < ?", now.Add(-24*time.Hour)).Delete(&models.DownloadLink{})
+	link := models.DownloadLink{
+		TokenHash:         hashDownloadToken(token),
+		UserID:            currentUserID(c),
+		Path:              target.Path,
+		Query:             target.RawQuery,
+		OneTime:           req.OneTime,
+		ExpiresAt:         now.Add(time.Duration(ttl) * time.Second),
+		Scanned:           scanned,
+		ConfirmedPassages: confirmed,
+	}
+	if err := database.DB.Create(&link).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create download link"})
+		return
+	}
+
+	linkURL := "/api/v1/downloads/" + token
+	if h.config != nil && h.config.PublicURL != "" {
+		linkURL = strings.TrimRight(h.config.PublicURL, "/") + linkURL
+	}
+	c.JSON(http.StatusCreated, DownloadLinkResponse{URL: linkURL, ExpiresAt: link.ExpiresAt, OneTime: link.OneTime, Scanned: link.Scanned})
+}
+
+// Download serves the download a signed URL stands for
+// @Summary Download with a signed URL
+// @Description Serve the audio or export a signed URL was created for, without API credentials. Audio supports range requests, except through one-time links, which work for a single request.
+// @Tags downloads
+// @Produce octet-stream
+// @Param token path string true "Signed URL token"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Router /api/v1/downloads/{token} [get]
+func (h *Handler) Download(c *gin.Context) {
+	var link models.DownloadLink
+	if err := database.DB.Where("token_hash = ?", hashDownloadToken(c.Param("token"))).Limit(1).Find(&link).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get download link"})
+		return
+	}
+	if link.ID == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Download link not found"})
+		return
+	}
+	now := time.Now()
+	if now.After(link.ExpiresAt) {
+		c.JSON(http.StatusGone, gin.H{"error": "Download link has expired"})
+		return
+	}
+	target, match := findDownloadTarget(link.Path)
+	if target == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Download link not found"})
+		return
+	}
+	if !authorizeDownload(c, &link, match) {
+		return
+	}
+	if link.OneTime {
+		// Claim the link atomically so two concurrent requests can't both use it
+		result := database.DB.Model(&models.DownloadLink{}).Where("id = ? AND used_at IS NULL", link.ID).Update("used_at", now)
+		if result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to use download link"})
+			return
+		}
+		if result.RowsAffected == 0 {
+			c.JSON(http.StatusGone, gin.H{"error": "Download link has already been used"})
+			return
+		}
+	}
+
+	if len(match) > 1 {
+		c.Params = gin.Params{{Key: "id", Value: match[1]}}
+	} else {
+		c.Params = nil
+	}
+	c.Request.URL.RawQuery = link.Query
+	c.Set("auth_type", "signed_url")
+	// The link is the credential, so keep it out of caches and referrers
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+	target.handler(h, c)
+}
+
+// authorizeDownload sets up the caller as the link's creator, with their current role, and
+// checks that they can still open the transcription it downloads: the handlers are called
+// directly, without the route's access checks. It answers 404 if not, as for a missing link.
+func authorizeDownload(c *gin.Context, link *models.DownloadLink, match []string) bool {
+	// Links signed with an API key without an owner download as an admin, as the key does
+	role := models.RoleAdmin
+	if link.UserID != nil {
+		var user models.User
+		if err := database.DB.Select("id", "role").Where("id = ?", *link.UserID).Limit(1).Find(&user).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get download link"})
+			return false
+		}
+		if user.ID == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Download link not found"})
+			return false
+		}
+		c.Set("user_id", user.ID)
+		role = user.Role
+	}
+	c.Set("role", role)
+
+	if len(match) > 1 {
+		var owners []struct{ UserID *uint }
+		if err := database.DB.Raw(jobOwnerQuery, match[1]).Scan(&owners).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transcription"})
+			return false
+		}
+		if len(owners) > 0 && !canAccessJob(c, owners[0].UserID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Download link not found"})
+			return false
+		}
+	}
+	return true
+}
+
+// findDownloadTarget returns the download target for path, with its pattern's submatches
+func findDownloadTarget(path string) (*downloadTarget, []string) {
+	for i := range downloadTargets {
+		if match := downloadTargets[i].pattern.FindStringSubmatch(path); match != nil {
+			return &downloadTargets[i], match
+		}
+	}
+	return nil, nil
+}
+
+// hashDownloadToken returns the SHA-256 of a signed URL token, as stored
+func hashDownloadToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
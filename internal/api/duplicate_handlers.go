@@ -0,0 +1,425 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/duplicates"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/scheduler"
+	"scriberr/internal/tagging"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// DuplicatePair is a flagged pair of transcriptions with their titles
+type DuplicatePair struct {
+	models.DuplicateCandidate
+	FirstTitle  *string `json:"first_title,omitempty"`
+	SecondTitle *string `json:"second_title,omitempty"`
+}
+
+// MergeDuplicatesRequest says which transcription of a pair to keep
+type MergeDuplicatesRequest struct {
+	// Keep is the ID of the transcription to keep, by default the one created first
+	Keep string `json:"keep,omitempty"`
+	// Delete deletes the other transcription instead of keeping it out of RAG
+	Delete bool `json:"delete,omitempty"`
+}
+
+// duplicateOptions reads the thresholds of a duplicate scan from a schedule's params
+func duplicateOptions(params map[string]interface{}) (duplicates.Options, error) {
+	opts := duplicates.DefaultOptions()
+	var err error
+	if opts.MinSimilarity, err = scheduler.FloatParam(params, "min_similarity", opts.MinSimilarity); err != nil {
+		return opts, err
+	}
+	if opts.MinOverlap, err = scheduler.FloatParam(params, "min_overlap", opts.MinOverlap); err != nil {
+		return opts, err
+	}
+	return opts, opts.Validate()
+}
+
+func (h *Handler) runDuplicateScan(ctx context.Context, params map[string]interface{}) (map[string]interface{}, error) {
+	if h.ragService == nil {
+		return nil, errors.New("RAG service not initialized")
+	}
+	opts, err := duplicateOptions(params)
+	if err != nil {
+		return nil, err
+	}
+	collections, err := rag.Collections()
+	if err != nil {
+		return nil, err
+	}
+	report, err := duplicates.Scan(ctx, h.ragService, collections, opts)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"collections": report.Collections,
+		"compared":    report.Compared,
+		"found":       report.Found,
+		"new":         report.New,
+		"cleared":     report.Cleared,
+	}, nil
+}
+
+// loadDuplicate loads a flagged pair among the caller's transcriptions, writing an error
+// response if it can't
+func loadDuplicate(c *gin.Context) (*models.DuplicateCandidate, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid duplicate ID"})
+		return nil, false
+	}
+	collection, err := rag.ResolveCollection(currentUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	var candidate models.DuplicateCandidate
+	if err := database.DB.Where("id = ? AND collection = ?", id, collection).First(&candidate).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Duplicate not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get duplicate"})
+		}
+		return nil, false
+	}
+	return &candidate, true
+}
+
+// withTitles attaches the titles of the transcriptions in each pair
+func withTitles(candidates []models.DuplicateCandidate) []DuplicatePair {
+	var ids []string
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.FirstID, candidate.SecondID)
+	}
+	titles := map[string]*string{}
+	if len(ids) > 0 {
+		var jobs []models.TranscriptionJob
+		database.DB.Select("id", "title").Where("id IN ?", ids).Find(&jobs)
+		for _, job := range jobs {
+			titles[job.ID] = job.Title
+		}
+	}
+	pairs := make([]DuplicatePair, len(candidates))
+	for i, candidate := range candidates {
+		pairs[i] = DuplicatePair{DuplicateCandidate: candidate, FirstTitle: titles[candidate.FirstID], SecondTitle: titles[candidate.SecondID]}
+	}
+	return pairs
+}
+
+// ListDuplicates lists the near-duplicate pairs found among the caller's transcriptions
+// @Summary List near-duplicate transcriptions
+// @Description List pairs of the caller's transcriptions whose embeddings are nearly the same, such as one meeting recorded by two people or an edited recording uploaded again, most similar first. Pairs are found by the duplicate_scan maintenance action or POST /duplicates/scan.
+// @Tags rag
+// @Produce json
+// @Param status query string false "pending (default), ignored, merged or all"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Pairs per page (default 20, at most 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/duplicates [get]
+func (h *Handler) ListDuplicates(c *gin.Context) {
+	status := c.DefaultQuery("status", models.DuplicatePending)
+	switch status {
+	case models.DuplicatePending, models.DuplicateIgnored, models.DuplicateMerged, "all":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending, ignored, merged or all"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	collection, err := rag.ResolveCollection(currentUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := func() *gorm.DB {
+		query := database.DB.Model(&models.DuplicateCandidate{}).Where("collection = ?", collection)
+		if status != "all" {
+			query = query.Where("status = ?", status)
+		}
+		return query
+	}
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count duplicates"})
+		return
+	}
+	candidates := []models.DuplicateCandidate{}
+	if err := query().Order("similarity DESC, id ASC").Offset((page - 1) * limit).Limit(limit).Find(&candidates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list duplicates"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"duplicates": withTitles(candidates),
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// ScanDuplicates looks for near-duplicates among the caller's transcriptions now
+// @Summary Scan for near-duplicate transcriptions
+// @Description Compare the embeddings of every pair of the caller's indexed transcriptions and flag those at least min_similarity alike (default 0.95) whose transcripts share at least min_overlap of their three-word sequences (default 0.3), as pending. Pairs already merged or ignored stay so; pending pairs no longer alike enough are dropped.
+// @Tags rag
+// @Accept json
+// @Produce json
+// @Param request body duplicates.Options false "Thresholds"
+// @Success 200 {object} duplicates.Report
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/duplicates/scan [post]
+func (h *Handler) ScanDuplicates(c *gin.Context) {
+	if h.ragService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "RAG service not initialized"})
+		return
+	}
+	opts := duplicates.DefaultOptions()
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&opts); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if err := opts.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	collection, err := rag.ResolveCollection(currentUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	report, err := duplicates.ScanCollection(c.Request.Context(), h.ragService, collection, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan for duplicates: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// IgnoreDuplicate marks a flagged pair as not duplicates
+// @Summary Ignore a near-duplicate pair
+// @Description Mark a pending pair as not duplicates; later scans won't flag it again
+// @Tags rag
+// @Produce json
+// @Param id path int true "Duplicate ID"
+// @Success 200 {object} models.DuplicateCandidate
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/duplicates/{id}/ignore [post]
+func (h *Handler) IgnoreDuplicate(c *gin.Context) {
+	candidate, ok := loadDuplicate(c)
+	if !ok {
+		return
+	}
+	if candidate.Status != models.DuplicatePending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Duplicate is already " + candidate.Status})
+		return
+	}
+	if err := duplicates.Resolve(database.DB, candidate, models.DuplicateIgnored, nil, currentUserID(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to ignore duplicate"})
+		return
+	}
+	c.JSON(http.StatusOK, candidate)
+}
+
+// MergeDuplicate keeps one transcription of a flagged pair and retires the other
+// @Summary Merge a near-duplicate pair
+// @Description Keep one transcription of a pending pair (keep, by default the one created first), adding the other's tags to it. The other is removed from RAG and marked as its duplicate so it isn't indexed again, or deleted with delete. Transcriptions under legal hold or being transcribed can't be deleted.
+// @Tags rag
+// @Accept json
+// @Produce json
+// @Param id path int true "Duplicate ID"
+// @Param request body MergeDuplicatesRequest false "Transcription to keep"
+// @Success 200 {object} models.DuplicateCandidate
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/duplicates/{id}/merge [post]
+func (h *Handler) MergeDuplicate(c *gin.Context) {
+	var req MergeDuplicatesRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	candidate, ok := loadDuplicate(c)
+	if !ok {
+		return
+	}
+	if candidate.Status != models.DuplicatePending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Duplicate is already " + candidate.Status})
+		return
+	}
+	keepID, otherID := candidate.FirstID, candidate.SecondID
+	switch req.Keep {
+	case "", candidate.FirstID:
+	case candidate.SecondID:
+		keepID, otherID = otherID, keepID
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "keep must be one of the pair's transcriptions"})
+		return
+	}
+
+	var kept, other models.TranscriptionJob
+	if err := database.DB.Where("id = ?", keepID).First(&kept).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcription not found"})
+		return
+	}
+	if err := database.DB.Where("id = ?", otherID).First(&other).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcription not found"})
+		return
+	}
+	if req.Delete {
+		if rejectIfOnHold(c, &other) {
+			return
+		}
+		if other.Status == models.StatusProcessing {
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot delete a transcription that is being processed"})
+			return
+		}
+	}
+
+	// The kept transcription takes on the other's tags, so merging loses none
+	if len(other.Tags) > 0 {
+		if _, err := tagging.SetJobTags(&kept, append(append([]string{}, kept.Tags...), other.Tags...)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge tags"})
+			return
+		}
+		h.refreshRAGMetadata(kept.ID)
+	}
+
+	if req.Delete {
+		// Deleting the transcription also drops its pairs, this one included
+		if err := h.deleteIndexedJob(c.Request.Context(), other.ID); err != nil {
+			if errors.Is(err, errLegalHold) {
+				c.JSON(http.StatusConflict, gin.H{"error": "Transcription is under legal hold"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete duplicate: " + err.Error()})
+			return
+		}
+		now := time.Now()
+		candidate.Status, candidate.KeptID, candidate.ResolvedBy, candidate.ResolvedAt = models.DuplicateMerged, &kept.ID, currentUserID(c), &now
+		logger.InfoContext(c.Request.Context(), "Merged duplicate", "job_id", kept.ID, "deleted_id", other.ID)
+		c.JSON(http.StatusOK, candidate)
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&other).Update("duplicate_of", kept.ID).Error; err != nil {
+			return err
+		}
+		// The other's remaining pairs are moot once it is out of the index
+		if err := tx.Where("status = ? AND id <> ? AND (first_id = ? OR second_id = ?)", models.DuplicatePending, candidate.ID, other.ID, other.ID).
+			Delete(&models.DuplicateCandidate{}).Error; err != nil {
+			return err
+		}
+		return duplicates.Resolve(tx, candidate, models.DuplicateMerged, &kept.ID, currentUserID(c))
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge duplicate"})
+		return
+	}
+	if h.ragService != nil {
+		if err := h.ragService.DeleteTranscription(c.Request.Context(), other.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Duplicate merged, but failed to remove it from RAG: " + err.Error()})
+			return
+		}
+	}
+	logger.InfoContext(c.Request.Context(), "Merged duplicate", "job_id", kept.ID, "duplicate_id", other.ID)
+	c.JSON(http.StatusOK, candidate)
+}
+
+// ReopenDuplicate undoes merging or ignoring a pair
+// @Summary Reopen a near-duplicate pair
+// @Description Put an ignored or merged pair back to pending. A transcription the merge kept out of RAG is indexed again; one it deleted can't be brought back.
+// @Tags rag
+// @Produce json
+// @Param id path int true "Duplicate ID"
+// @Success 200 {object} models.DuplicateCandidate
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/duplicates/{id}/reopen [post]
+func (h *Handler) ReopenDuplicate(c *gin.Context) {
+	candidate, ok := loadDuplicate(c)
+	if !ok {
+		return
+	}
+	if candidate.Status == models.DuplicatePending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Duplicate is already pending"})
+		return
+	}
+
+	var released *models.TranscriptionJob
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if candidate.Status == models.DuplicateMerged && candidate.KeptID != nil {
+			var other models.TranscriptionJob
+			otherID := candidate.FirstID
+			if otherID == *candidate.KeptID {
+				otherID = candidate.SecondID
+			}
+			err := tx.Where("id = ? AND duplicate_of = ?", otherID, *candidate.KeptID).First(&other).Error
+			if err == nil {
+				if err := tx.Model(&other).Update("duplicate_of", nil).Error; err != nil {
+					return err
+				}
+				released = &other
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+		candidate.Status, candidate.KeptID, candidate.ResolvedBy, candidate.ResolvedAt = models.DuplicatePending, nil, nil, nil
+		return tx.Model(candidate).Updates(map[string]interface{}{"status": models.DuplicatePending, "kept_id": nil, "resolved_by": nil, "resolved_at": nil}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reopen duplicate"})
+		return
+	}
+	if released != nil && h.ragService != nil {
+		if err := h.storeJobInRAG(released); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Duplicate reopened, but failed to index %s again: %v", released.ID, err)})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, candidate)
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"scriberr/internal/database"
+	"scriberr/internal/mailin"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SetEmailInService enables email-in: the audio attachments of emails the service reads are
+// queued as voice memos, owned by EMAIL_IN_OWNER
+func (h *Handler) SetEmailInService(service *mailin.Service) {
+	h.emailIn = service
+	service.SetImporter(h.importEmailAttachment)
+}
+
+// importEmailAttachment saves an emailed voice memo and queues it like an upload
+func (h *Handler) importEmailAttachment(email *models.InboundEmail, attachment mailin.Attachment, title string) (string, error) {
+	ctx := context.Background()
+	if err := h.resourceGuard.Check(ctx, int64(len(attachment.Data))); err != nil {
+		return "", err
+	}
+	var userID *uint
+	if h.config.EmailInOwner != "" {
+		var owner models.User
+		if err := database.DB.Where("username = ?", h.config.EmailInOwner).First(&owner).Error; err != nil {
+			return "", fmt.Errorf("EMAIL_IN_OWNER %q not found: %w", h.config.EmailInOwner, err)
+		}
+		userID = &owner.ID
+	}
+
+	if err := os.MkdirAll(h.config.UploadDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	jobID := uuid.New().String()
+	filePath := filepath.Join(h.config.UploadDir, jobID+filepath.Ext(attachment.Filename))
+	if err := os.WriteFile(filePath, attachment.Data, 0644); err != nil {
+		return "", fmt.Errorf("failed to save attachment: %w", err)
+	}
+	if h.files != nil {
+		if err := h.files.Upload(ctx, filePath); err != nil {
+			os.Remove(filePath)
+			return "", fmt.Errorf("failed to store file: %w", err)
+		}
+	}
+
+	job := models.TranscriptionJob{
+		ID:          jobID,
+		UserID:      userID,
+		AudioPath:   filePath,
+		Title:       &title,
+		Status:      models.StatusUploaded,
+		ContentType: models.ContentVoiceMemo,
+		Source:      &models.MediaSource{URL: "mailto:" + email.From, Title: email.Subject, Uploader: email.From},
+	}
+	if err := database.DB.Create(&job).Error; err != nil {
+		os.Remove(filePath)
+		h.removeStoredFile(filePath)
+		return "", fmt.Errorf("failed to create job: %w", err)
+	}
+	h.autoTranscribe(userID, &job, "", nil)
+
+	// Started after the job is saved for the last time so the report isn't overwritten
+	go checkAudioQuality(job.ID, filePath)
+	logger.Info("Queued email attachment", "filename", attachment.Filename, "from", email.From, "job_id", job.ID)
+	return job.ID, nil
+}
+
+// requireEmailIn writes an error response if email-in isn't enabled
+func (h *Handler) requireEmailIn(c *gin.Context) bool {
+	if h.emailIn == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Email-in not enabled"})
+		return false
+	}
+	return true
+}
+
+// ListInboundEmails pages through the emails read from the email-in mailbox
+// @Summary List emailed voice memos
+// @Description Page through the emails read from the email-in mailbox, newest first, with the transcriptions of their attachments and whether the sender was replied to: processing (waiting for the transcriptions), replied, completed (no reply sent, as the email was automatic or SMTP_ADDR isn't set) or failed (the reply couldn't be sent, with the error).
+// @Tags admin
+// @Produce json
+// @Param status query string false "Only emails with this status"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Emails per page" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/email-in [get]
+func (h *Handler) ListInboundEmails(c *gin.Context) {
+	if !h.requireEmailIn(c) {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 1000 {
+		limit = 50
+	}
+
+	query := database.DB.Model(&models.InboundEmail{})
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count emails"})
+		return
+	}
+	emails := []models.InboundEmail{}
+	if err := query.Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&emails).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list emails"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"emails": emails,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// CheckEmailIn checks the email-in mailbox now
+// @Summary Check the email-in mailbox
+// @Description Read new emails from the email-in mailbox and reply to those whose transcriptions are done right away, rather than at the next EMAIL_IN_POLL_SECONDS. Returns what the check did, along with the error if the mailbox couldn't be read.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} mailin.CheckResult
+// @Failure 502 {object} map[string]interface{}
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/email-in/check [post]
+func (h *Handler) CheckEmailIn(c *gin.Context) {
+	if !h.requireEmailIn(c) {
+		return
+	}
+	result, err := h.emailIn.Check(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error(), "result": result})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// EntitySummary is a named entity with how often and in how many transcriptions it was mentioned
+type EntitySummary struct {
+	Name           string `json:"name"`
+	Type           string `json:"type"`
+	Mentions       int64  `json:"mentions"`
+	Transcriptions int64  `json:"transcriptions"`
+}
+
+// EntityTranscription is a transcription mentioning an entity, with the times it came up
+type EntityTranscription struct {
+	TranscriptionID    string    `json:"transcription_id"`
+	TranscriptionTitle string    `json:"transcription_title,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+	Name               string    `json:"name"`
+	Type               string    `json:"type"`
+	Times              []float64 `json:"times"` // Seconds into the recording, empty if the transcript has no timings
+}
+
+// visibleEntityMentions limits an entity query to mentions in the caller's transcriptions and
+// in transcriptions without an owner, which every user can see
+func visibleEntityMentions(db *gorm.DB, userID *uint) *gorm.DB {
+	if userID == nil {
+		return db.Where("entity_mentions.user_id IS NULL")
+	}
+	return db.Where("entity_mentions.user_id = ? OR entity_mentions.user_id IS NULL", *userID)
+}
+
+// entityTypeFilter applies the type query parameter, writing a 400 response if it isn't a known type
+func entityTypeFilter(c *gin.Context, query *gorm.DB) (*gorm.DB, bool) {
+	entityType := strings.ToLower(strings.TrimSpace(c.Query("type")))
+	if entityType == "" {
+		return query, true
+	}
+	for _, known := range models.EntityTypes {
+		if entityType == known {
+			return query.Where("entity_mentions.type = ?", entityType), true
+		}
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "type must be one of " + strings.Join(models.EntityTypes, ", ")})
+	return nil, false
+}
+
+// ListEntities browses and searches the entities mentioned in the caller's transcriptions
+// @Summary List entities
+// @Description List the people, organizations, products and locations found in the caller's transcriptions by the extract_entities workflow step, those mentioned in the most transcriptions first
+// @Tags entities
+// @Produce json
+// @Param q query string false "Only entities whose name contains this text (case-insensitive)"
+// @Param type query string false "person, organization, product or location"
+// @Param limit query int false "Maximum number of entities (default 50, max 200)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/entities [get]
+func (h *Handler) ListEntities(c *gin.Context) {
+	limit := 50
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 200 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 200"})
+			return
+		}
+		limit = parsed
+	}
+
+	query, ok := entityTypeFilter(c, visibleEntityMentions(database.DB.Model(&models.EntityMention{}), currentUserID(c)))
+	if !ok {
+		return
+	}
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		query = query.Where("LOWER(entity_mentions.name) LIKE ?", "%"+strings.ToLower(q)+"%")
+	}
+
+	entities := []EntitySummary{}
+	err := query.Select("MIN(entity_mentions.name) AS name, entity_mentions.type AS type, COUNT(*) AS mentions, " +
+		"COUNT(DISTINCT entity_mentions.transcription_id) AS transcriptions").
+		Group("entity_mentions.type, LOWER(entity_mentions.name)").
+		Order("transcriptions DESC, mentions DESC, name ASC").
+		Limit(limit).
+		Scan(&entities).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list entities"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"entities": entities})
+}
+
+// ListEntityTranscriptions returns the transcriptions mentioning an entity
+// @Summary List an entity's transcriptions
+// @Description List every transcription of the caller's where an entity was mentioned, newest first, with the times it came up
+// @Tags entities
+// @Produce json
+// @Param name query string true "Entity name (case-insensitive)"
+// @Param type query string false "person, organization, product or location"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/entities/transcriptions [get]
+func (h *Handler) ListEntityTranscriptions(c *gin.Context) {
+	name := strings.Join(strings.Fields(c.Query("name")), " ")
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+	query, ok := entityTypeFilter(c, visibleEntityMentions(database.DB, currentUserID(c)))
+	if !ok {
+		return
+	}
+
+	var mentions []models.EntityMention
+	if err := query.Where("LOWER(entity_mentions.name) = ?", strings.ToLower(name)).
+		Order("entity_mentions.source_time ASC").Find(&mentions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list transcriptions"})
+		return
+	}
+
+	byJob := map[string]*EntityTranscription{}
+	var ids []string
+	for _, mention := range mentions {
+		entry, ok := byJob[mention.TranscriptionID]
+		if !ok {
+			entry = &EntityTranscription{TranscriptionID: mention.TranscriptionID, Name: mention.Name, Type: mention.Type, Times: []float64{}}
+			byJob[mention.TranscriptionID] = entry
+			ids = append(ids, mention.TranscriptionID)
+		}
+		if mention.SourceTime != nil {
+			entry.Times = append(entry.Times, *mention.SourceTime)
+		}
+	}
+
+	transcriptions := []EntityTranscription{}
+	if len(ids) > 0 {
+		var jobs []models.TranscriptionJob
+		if err := database.DB.Select("id", "title", "created_at").Where("id IN ?", ids).Order("created_at DESC").Find(&jobs).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list transcriptions"})
+			return
+		}
+		for _, job := range jobs {
+			entry := byJob[job.ID]
+			entry.CreatedAt = job.CreatedAt
+			if job.Title != nil {
+				entry.TranscriptionTitle = *job.Title
+			}
+			transcriptions = append(transcriptions, *entry)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"name": name, "transcriptions": transcriptions})
+}
+
+// ListTranscriptionEntities returns the entities mentioned in one transcription
+// @Summary List a transcription's entities
+// @Description List the named entities found in a transcription, one entry per mention in the order they came up
+// @Tags entities
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param type query string false "person, organization, product or location"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/entities [get]
+func (h *Handler) ListTranscriptionEntities(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	query, ok := entityTypeFilter(c, database.DB.Where("transcription_id = ?", job.ID))
+	if !ok {
+		return
+	}
+	var mentions []models.EntityMention
+	if err := query.Order("source_time ASC, type ASC, name ASC").Find(&mentions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list entities"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"transcription_id": job.ID, "entities": mentions})
+}
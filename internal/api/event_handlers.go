@@ -0,0 +1,257 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/events"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultEventPollLimit is the page size when the caller doesn't pass one
+	defaultEventPollLimit = 100
+	// maxEventPollWait caps how long a poll may wait for new events, in seconds
+	maxEventPollWait = 30
+	// eventPollInterval is how often a waiting poll checks for new events
+	eventPollInterval = 500 * time.Millisecond
+	// eventStreamKeepAlive is how often an idle event stream sends a comment, so proxies
+	// don't close it
+	eventStreamKeepAlive = 15 * time.Second
+)
+
+// PollEvents returns the caller's events after a cursor
+// @Summary Poll domain events
+// @Description Return the caller's events (job.created, job.completed, job.failed, summary.ready, index.updated) with a sequence greater than cursor, oldest first. Pass next_cursor back as cursor on the next poll; events are kept, so a consumer that was offline picks up where it left off. With wait, the request blocks until an event arrives or the wait runs out.
+// @Tags events
+// @Produce json
+// @Param cursor query int false "Sequence of the last event already processed (default 0)"
+// @Param limit query int false "Maximum events to return (default 100, max 500)"
+// @Param types query string false "Comma-separated event types to return"
+// @Param wait query int false "Seconds to wait for new events when there are none (max 30)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/events/poll [get]
+func (h *Handler) PollEvents(c *gin.Context) {
+	var cursor uint
+	if raw := c.Query("cursor"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cursor must be a non-negative integer"})
+			return
+		}
+		cursor = uint(parsed)
+	}
+
+	limit := defaultEventPollLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > events.MaxPollLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+			return
+		}
+		limit = parsed
+	}
+
+	wait := 0
+	if raw := c.Query("wait"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 || parsed > maxEventPollWait {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "wait must be between 0 and 30"})
+			return
+		}
+		wait = parsed
+	}
+
+	types := eventTypes(c)
+	userID := currentUserID(c)
+	deadline := time.Now().Add(time.Duration(wait) * time.Second)
+	for {
+		// Fetch one extra event to tell whether another poll would return more
+		found, err := events.Poll(scopeToOwner(database.DB, userID), cursor, limit+1, types)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to poll events"})
+			return
+		}
+		if len(found) > 0 || !time.Now().Before(deadline) {
+			respondWithEvents(c, found, cursor, limit)
+			return
+		}
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-time.After(eventPollInterval):
+		}
+	}
+}
+
+// respondWithEvents writes a page of events and the cursor to poll from next
+func respondWithEvents(c *gin.Context, found []models.Event, cursor uint, limit int) {
+	hasMore := len(found) > limit
+	if hasMore {
+		found = found[:limit]
+	}
+	if len(found) > 0 {
+		cursor = found[len(found)-1].Sequence
+	}
+	if found == nil {
+		found = []models.Event{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"events":      found,
+		"next_cursor": cursor,
+		"has_more":    hasMore,
+	})
+}
+
+// StreamEvents streams the caller's events as Server-Sent Events
+// @Summary Stream domain events
+// @Description Stream the caller's events as Server-Sent Events as they are recorded: job status changes (job.created, job.started, job.completed, job.failed), transcription progress (job.progress, with stage and percent), post-processing milestones (workflow.step_finished, workflow.finished, summary.ready, index.updated) and the rest of the event log. Each message's id is the event's sequence and its event name the event type; data is the event as JSON. The stream starts with events recorded after the request, or after cursor or the Last-Event-ID header, so a client that reconnects misses nothing.
+// @Tags events
+// @Produce text/event-stream
+// @Param cursor query int false "Sequence of the last event already processed (default: stream new events only)"
+// @Param types query string false "Comma-separated event types to stream"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/events [get]
+func (h *Handler) StreamEvents(c *gin.Context) {
+	cursor, given, ok := streamCursor(c)
+	if !ok {
+		return
+	}
+	if !given {
+		var err error
+		if cursor, err = latestEventSequence(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read the event log"})
+			return
+		}
+	}
+	userID := currentUserID(c)
+	streamEvents(c, func() *gorm.DB { return scopeToOwner(database.DB, userID) }, cursor, eventTypes(c))
+}
+
+// StreamJobEvents streams the events of one transcription as Server-Sent Events
+// @Summary Stream a transcription's events
+// @Description Stream the events of one transcription as Server-Sent Events, so its status, transcription progress and post-processing can be followed without polling. The stream opens with a snapshot message holding the transcription's current status, then replays the transcription's events from the start (or after cursor or the Last-Event-ID header) and follows new ones. Messages are formatted as in GET /api/v1/events.
+// @Tags events
+// @Produce text/event-stream
+// @Param id path string true "Transcription ID"
+// @Param cursor query int false "Sequence of the last event already processed (default 0)"
+// @Param types query string false "Comma-separated event types to stream"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/events [get]
+func (h *Handler) StreamJobEvents(c *gin.Context) {
+	cursor, _, ok := streamCursor(c)
+	if !ok {
+		return
+	}
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	snapshot := gin.H{"transcription_id": job.ID, "status": job.Status}
+	if job.ErrorMessage != nil {
+		snapshot["error"] = *job.ErrorMessage
+	}
+	streamEvents(c, func() *gorm.DB { return database.DB.Where("subject_id = ?", job.ID) }, cursor, eventTypes(c), snapshot)
+}
+
+// streamEvents writes the events query finds after cursor as Server-Sent Events until the
+// client goes away, checking for new ones every eventPollInterval. Messages given as
+// initial are sent first, as snapshot events without an id.
+func streamEvents(c *gin.Context, query func() *gorm.DB, cursor uint, types []string, initial ...gin.H) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Keep nginx from buffering the stream
+	c.Status(http.StatusOK)
+
+	for _, message := range initial {
+		data, _ := json.Marshal(message)
+		fmt.Fprintf(c.Writer, "event: snapshot\ndata: %s\n\n", data)
+	}
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	lastWrite := time.Now()
+	for {
+		found, err := events.Poll(query(), cursor, events.MaxPollLimit, types)
+		if err != nil {
+			fmt.Fprintf(c.Writer, "event: error\ndata: {\"error\":\"Failed to poll events\"}\n\n")
+			c.Writer.Flush()
+			return
+		}
+		for _, event := range found {
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.Sequence, event.Type, data)
+			cursor = event.Sequence
+		}
+		if len(found) > 0 {
+			lastWrite = time.Now()
+		} else if time.Since(lastWrite) >= eventStreamKeepAlive {
+			fmt.Fprint(c.Writer, ": keep-alive\n\n")
+			lastWrite = time.Now()
+		}
+		c.Writer.Flush()
+		if len(found) == events.MaxPollLimit {
+			continue // More are waiting
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventPollInterval):
+		}
+	}
+}
+
+// streamCursor reads where an event stream starts from the Last-Event-ID header a
+// reconnecting EventSource sends, or else the cursor parameter. given is false when
+// neither was passed.
+func streamCursor(c *gin.Context) (cursor uint, given bool, ok bool) {
+	raw := c.GetHeader("Last-Event-ID")
+	if raw == "" {
+		raw = c.Query("cursor")
+	}
+	if raw == "" {
+		return 0, false, true
+	}
+	parsed, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cursor must be a non-negative integer"})
+		return 0, false, false
+	}
+	return uint(parsed), true, true
+}
+
+// eventTypes returns the event types a request filters on, from its comma-separated types parameter
+func eventTypes(c *gin.Context) []string {
+	var types []string
+	for _, t := range strings.Split(c.Query("types"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
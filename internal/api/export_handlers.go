@@ -0,0 +1,300 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/export"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unsafeFilename matches runs of characters kept out of download file names
+var unsafeFilename = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ExportBilingual downloads a transcript side by side or interleaved with one of its translations
+// @Summary Export a bilingual transcript
+// @Description Download the transcript together with a stored translation, aligned segment by segment using the segment timestamps. The side_by_side layout puts the original and the translation in adjacent table columns; the interleaved layout follows each original segment with its translation. Speakers are shown by their custom names.
+// @Tags transcription
+// @Produce text/markdown
+// @Produce application/vnd.openxmlformats-officedocument.wordprocessingml.document
+// @Param id path string true "Job ID"
+// @Param language query string false "Language of the translation (default the most recent translation)"
+// @Param format query string false "markdown or docx (default markdown)"
+// @Param layout query string false "side_by_side or interleaved (default side_by_side)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/export/bilingual [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ExportBilingual(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", export.FormatMarkdown))
+	if format == "md" {
+		format = export.FormatMarkdown
+	}
+	if format != export.FormatMarkdown && format != export.FormatDOCX {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be markdown or docx"})
+		return
+	}
+	layout := c.DefaultQuery("layout", export.LayoutSideBySide)
+	if layout != export.LayoutSideBySide && layout != export.LayoutInterleaved {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "layout must be side_by_side or interleaved"})
+		return
+	}
+
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+
+	query := database.DB.Where("transcription_job_id = ?", job.ID)
+	if language := c.Query("language"); language != "" {
+		query = query.Where("language = ?", language)
+	}
+	var translation models.Translation
+	if err := query.Order("created_at DESC").Limit(1).Find(&translation).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get translation"})
+		return
+	}
+	if translation.ID == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No translation found for this transcription"})
+		return
+	}
+
+	original := export.TranscriptSegments(job)
+	if len(original) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcript not available"})
+		return
+	}
+
+	speakerNames, err := jobSpeakerNames(job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get speaker mappings"})
+		return
+	}
+
+	title := ""
+	if job.Title != nil {
+		title = *job.Title
+	}
+	bilingual := export.NewBilingual(title, translation.Language, original, translation.Segments, speakerNames)
+
+	name := title
+	if name == "" {
+		name = job.ID
+	}
+	name = strings.Trim(unsafeFilename.ReplaceAllString(name+"."+translation.Language, "_"), "_")
+
+	if format == export.FormatDOCX {
+		data, err := bilingual.DOCX(layout)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render document"})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.docx"`, name))
+		c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.wordprocessingml.document", data)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.md"`, name))
+	c.Data(http.StatusOK, "text/markdown; charset=utf-8", bilingual.Markdown(layout))
+}
+
+// ExportTranscript downloads a transcript as subtitles or as a document
+// @Summary Export a transcript
+// @Description Download the transcript as SRT or WebVTT subtitles, or as a Markdown, Word or PDF document to share.
+// @Description Subtitles: each segment becomes one or more cues of at most max_lines lines of max_line_length characters, split between words and at the end of a sentence where one is near, and timed by the word timestamps when the segment still has them. With speakers, a cue starts with the speaker's name whenever the speaker changes. Transcripts without timestamps can't be exported as subtitles.
+// @Description Documents: the summary, chapters, action items and transcript, with each speaker's paragraphs under their name and start time, laid out by an export template: the one named by template, or else the caller's default, or else the built-in layout with every section.
+// @Tags transcription
+// @Produce plain
+// @Produce text/markdown
+// @Produce application/vnd.openxmlformats-officedocument.wordprocessingml.document
+// @Produce application/pdf
+// @Param id path string true "Job ID"
+// @Param format query string false "srt, vtt, markdown, docx or pdf (default srt)"
+// @Param template query string false "Export template ID, for documents (default the caller's default template)"
+// @Param speakers query bool false "Prefix cues with the speaker's name when it changes, for subtitles (default true)"
+// @Param max_line_length query int false "Characters per line, 20 to 80, for subtitles (default 42)"
+// @Param max_lines query int false "Lines per cue, 1 to 3, for subtitles (default 2)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/export [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ExportTranscript(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", export.FormatSRT))
+	switch format {
+	case "webvtt":
+		format = export.FormatVTT
+	case "md":
+		format = export.FormatMarkdown
+	}
+	switch format {
+	case export.FormatSRT, export.FormatVTT:
+		exportSubtitles(c, format)
+	case export.FormatMarkdown, export.FormatDOCX, export.FormatPDF:
+		exportDocument(c, format)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be srt, vtt, markdown, docx or pdf"})
+	}
+}
+
+// exportSubtitles downloads a transcript as SRT or WebVTT subtitles
+func exportSubtitles(c *gin.Context, format string) {
+	opts := export.SubtitleOptions{Speakers: true}
+	if value := c.Query("speakers"); value != "" {
+		speakers, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "speakers must be true or false"})
+			return
+		}
+		opts.Speakers = speakers
+	}
+	var err error
+	if opts.MaxLineLength, err = strconv.Atoi(c.DefaultQuery("max_line_length", strconv.Itoa(export.DefaultSubtitleLineLength))); err != nil || opts.MaxLineLength < 20 || opts.MaxLineLength > 80 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "max_line_length must be between 20 and 80"})
+		return
+	}
+	if opts.MaxLines, err = strconv.Atoi(c.DefaultQuery("max_lines", strconv.Itoa(export.DefaultSubtitleLines))); err != nil || opts.MaxLines < 1 || opts.MaxLines > 3 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "max_lines must be between 1 and 3"})
+		return
+	}
+
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	segments := export.TranscriptSegments(job)
+	if len(segments) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcript not available"})
+		return
+	}
+	timed := false
+	for _, segment := range segments {
+		timed = timed || segment.End > segment.Start
+	}
+	if !timed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Transcript has no timestamps to make subtitles from"})
+		return
+	}
+	if opts.Speakers {
+		if opts.SpeakerNames, err = jobSpeakerNames(job.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get speaker mappings"})
+			return
+		}
+	}
+
+	data, contentType, extension, err := export.Subtitles(format, segments, export.TranscriptWords(job), opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render subtitles"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, exportName(job), extension))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// exportDocument downloads a transcript with its summary, chapters and action items as a
+// Markdown, Word or PDF document laid out by an export template
+func exportDocument(c *gin.Context, format string) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	template, ok := exportTemplateFor(c, c.Query("template"))
+	if !ok {
+		return
+	}
+
+	document := export.Document{
+		Title:      exportTitle(job),
+		Date:       job.CreatedAt,
+		Sections:   export.DocumentSections,
+		Timestamps: true,
+		Speakers:   true,
+		Segments:   export.TranscriptSegments(job),
+	}
+	if template != nil {
+		document.Header = template.Header
+		document.Footer = template.Footer
+		document.Organization = template.Organization
+		document.AccentColor = template.AccentColor
+		document.Sections = template.Sections
+		document.Timestamps = template.Timestamps
+		document.Speakers = template.Speakers
+	}
+	if job.Summary != nil {
+		document.Summary = *job.Summary
+	}
+	var err error
+	if document.Chapters, err = jobChapters(job.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get chapters"})
+		return
+	}
+	if err := database.DB.Where("transcription_id = ?", job.ID).Order("source_time ASC, created_at ASC").Find(&document.ActionItems).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get action items"})
+		return
+	}
+	if document.Speakers {
+		if document.SpeakerNames, err = jobSpeakerNames(job.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get speaker mappings"})
+			return
+		}
+	}
+
+	name := exportName(job)
+	switch format {
+	case export.FormatDOCX:
+		data, err := document.DOCX()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render document"})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.docx"`, name))
+		c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.wordprocessingml.document", data)
+	case export.FormatPDF:
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, name))
+		c.Data(http.StatusOK, "application/pdf", document.PDF())
+	default:
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.md"`, name))
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", document.Markdown())
+	}
+}
+
+// exportTitle is the title of a transcription, or its audio file name when it has none
+func exportTitle(job *models.TranscriptionJob) string {
+	if job.Title != nil && *job.Title != "" {
+		return *job.Title
+	}
+	return filepath.Base(job.AudioPath)
+}
+
+// exportName is the file name of a transcription's downloads, without the extension
+func exportName(job *models.TranscriptionJob) string {
+	name := job.ID
+	if job.Title != nil && *job.Title != "" {
+		name = *job.Title
+	}
+	return strings.Trim(unsafeFilename.ReplaceAllString(name, "_"), "_")
+}
+
+// jobSpeakerNames returns the custom names given to a transcription's speaker labels
+func jobSpeakerNames(jobID string) (map[string]string, error) {
+	var mappings []models.SpeakerMapping
+	if err := database.DB.Where("transcription_job_id = ?", jobID).Find(&mappings).Error; err != nil {
+		return nil, err
+	}
+	speakerNames := make(map[string]string, len(mappings))
+	for _, mapping := range mappings {
+		speakerNames[mapping.OriginalSpeaker] = mapping.CustomName
+	}
+	return speakerNames, nil
+}
@@ -0,0 +1,272 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/export"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// accentColor matches a hex RGB color, with or without its #
+var accentColor = regexp.MustCompile(`^#?[0-9A-Fa-f]{6}$`)
+
+// ExportTemplateRequest represents a request to create or update an export template
+type ExportTemplateRequest struct {
+	Name         string   `json:"name" binding:"required"`
+	Header       string   `json:"header"`
+	Footer       string   `json:"footer"`
+	Organization string   `json:"organization"`
+	AccentColor  string   `json:"accent_color"` // Hex RGB, e.g. #1F6FEB
+	Sections     []string `json:"sections"`     // Defaults to summary, chapters, action_items, transcript
+	Timestamps   *bool    `json:"timestamps,omitempty"`
+	Speakers     *bool    `json:"speakers,omitempty"`
+	IsDefault    bool     `json:"is_default"`
+}
+
+// loadExportTemplate loads an export template owned by the caller, writing an error response if it can't
+func loadExportTemplate(c *gin.Context, id string) (*models.ExportTemplate, bool) {
+	var template models.ExportTemplate
+	if err := scopeToOwner(database.DB, currentUserID(c)).Where("id = ?", id).First(&template).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Export template not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get export template"})
+		}
+		return nil, false
+	}
+	return &template, true
+}
+
+// bindExportTemplateRequest parses and validates an export template request, writing an error
+// response if it is invalid. excludeID is the template being updated, whose name may stay.
+func bindExportTemplateRequest(c *gin.Context, excludeID string) (*ExportTemplateRequest, bool) {
+	var req ExportTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return nil, false
+	}
+	var count int64
+	if err := scopeToOwner(database.DB.Model(&models.ExportTemplate{}), currentUserID(c)).
+		Where("LOWER(name) = LOWER(?) AND id != ?", req.Name, excludeID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check export template name"})
+		return nil, false
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("An export template named %q already exists", req.Name)})
+		return nil, false
+	}
+
+	req.Header = strings.TrimSpace(req.Header)
+	req.Footer = strings.TrimSpace(req.Footer)
+	req.Organization = strings.TrimSpace(req.Organization)
+	if req.AccentColor = strings.TrimSpace(req.AccentColor); req.AccentColor != "" {
+		if !accentColor.MatchString(req.AccentColor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "accent_color must be a hex color like #1F6FEB"})
+			return nil, false
+		}
+		req.AccentColor = "#" + strings.ToUpper(strings.TrimPrefix(req.AccentColor, "#"))
+	}
+
+	if req.Sections == nil {
+		req.Sections = export.DocumentSections
+	}
+	known := map[string]bool{}
+	for _, section := range export.DocumentSections {
+		known[section] = true
+	}
+	seen := map[string]bool{}
+	sections := []string{}
+	for _, section := range req.Sections {
+		section = strings.ToLower(strings.TrimSpace(section))
+		if !known[section] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown section %q: sections must be %s", section, strings.Join(export.DocumentSections, ", "))})
+			return nil, false
+		}
+		if !seen[section] {
+			seen[section] = true
+			sections = append(sections, section)
+		}
+	}
+	if len(sections) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sections must not be empty"})
+		return nil, false
+	}
+	req.Sections = sections
+	return &req, true
+}
+
+// apply copies the request's settings onto a template
+func (req *ExportTemplateRequest) apply(template *models.ExportTemplate) {
+	template.Name = req.Name
+	template.Header = req.Header
+	template.Footer = req.Footer
+	template.Organization = req.Organization
+	template.AccentColor = req.AccentColor
+	template.Sections = req.Sections
+	template.Timestamps = req.Timestamps == nil || *req.Timestamps
+	template.Speakers = req.Speakers == nil || *req.Speakers
+	template.IsDefault = req.IsDefault
+}
+
+// saveExportTemplate saves a template, making it the caller's only default when it is one
+func saveExportTemplate(c *gin.Context, template *models.ExportTemplate) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if template.IsDefault {
+			if err := scopeToOwner(tx.Model(&models.ExportTemplate{}), currentUserID(c)).
+				Where("id != ? AND is_default = ?", template.ID, true).Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Save(template).Error
+	})
+}
+
+// exportTemplateFor returns the export template an export uses: the one id names, or else the
+// caller's default, or else nil for the built-in layout. It writes an error response if it can't.
+func exportTemplateFor(c *gin.Context, id string) (*models.ExportTemplate, bool) {
+	if id != "" {
+		return loadExportTemplate(c, id)
+	}
+	var template models.ExportTemplate
+	err := scopeToOwner(database.DB, currentUserID(c)).Where("is_default = ?", true).First(&template).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, true
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get export template"})
+		return nil, false
+	}
+	return &template, true
+}
+
+// ListExportTemplates returns the caller's export templates
+// @Summary List export templates
+// @Description List the caller's export templates, which lay out documents exported from /transcription/{id}/export
+// @Tags export-templates
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/export-templates [get]
+func (h *Handler) ListExportTemplates(c *gin.Context) {
+	templates := []models.ExportTemplate{}
+	if err := scopeToOwner(database.DB, currentUserID(c)).Order("name ASC").Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list export templates"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+// CreateExportTemplate saves a new export template
+// @Summary Create an export template
+// @Description Save the layout and branding of exported documents: a header and footer repeated on every page, in which {{title}} and {{date}} are replaced by the transcription's, an organization name above the title, an accent color for the title and headings, which sections appear in which order, and whether transcript paragraphs show their time and speaker (both default true). The default template is used by exports that don't name one; making a template the default unsets the previous one.
+// @Tags export-templates
+// @Accept json
+// @Produce json
+// @Param request body ExportTemplateRequest true "Export template"
+// @Success 201 {object} models.ExportTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/export-templates [post]
+func (h *Handler) CreateExportTemplate(c *gin.Context) {
+	req, ok := bindExportTemplateRequest(c, "")
+	if !ok {
+		return
+	}
+
+	template := models.ExportTemplate{UserID: currentUserID(c)}
+	req.apply(&template)
+	if err := saveExportTemplate(c, &template); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create export template"})
+		return
+	}
+	c.JSON(http.StatusCreated, template)
+}
+
+// GetExportTemplate returns one of the caller's export templates
+// @Summary Get an export template
+// @Tags export-templates
+// @Produce json
+// @Param id path string true "Export template ID"
+// @Success 200 {object} models.ExportTemplate
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/export-templates/{id} [get]
+func (h *Handler) GetExportTemplate(c *gin.Context) {
+	template, ok := loadExportTemplate(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// UpdateExportTemplate replaces an export template's settings
+// @Summary Update an export template
+// @Tags export-templates
+// @Accept json
+// @Produce json
+// @Param id path string true "Export template ID"
+// @Param request body ExportTemplateRequest true "Export template"
+// @Success 200 {object} models.ExportTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/export-templates/{id} [put]
+func (h *Handler) UpdateExportTemplate(c *gin.Context) {
+	template, ok := loadExportTemplate(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	req, ok := bindExportTemplateRequest(c, template.ID)
+	if !ok {
+		return
+	}
+
+	req.apply(template)
+	if err := saveExportTemplate(c, template); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update export template"})
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteExportTemplate deletes an export template
+// @Summary Delete an export template
+// @Description Delete an export template. Deleting the default template makes exports use the built-in layout.
+// @Tags export-templates
+// @Param id path string true "Export template ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/export-templates/{id} [delete]
+func (h *Handler) DeleteExportTemplate(c *gin.Context) {
+	template, ok := loadExportTemplate(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	if err := database.DB.Delete(template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete export template"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Export template deleted"})
+}
@@ -0,0 +1,272 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/folders"
+	"scriberr/internal/models"
+	"scriberr/internal/projects"
+	"scriberr/internal/tagging"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SmartFolderRequest represents a request to create or update a smart folder
+type SmartFolderRequest struct {
+	Name   string                   `json:"name" binding:"required"`
+	Filter models.SmartFolderFilter `json:"filter"`
+}
+
+// UpdateTagsRequest represents a request to replace a transcription's tags
+type UpdateTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// loadFolder loads a smart folder owned by the caller, writing an error response if it can't
+func loadFolder(c *gin.Context, folderID string) (*models.SmartFolder, bool) {
+	var folder models.SmartFolder
+	if err := scopeToOwner(database.DB, currentUserID(c)).Where("id = ?", folderID).First(&folder).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get folder"})
+		}
+		return nil, false
+	}
+	return &folder, true
+}
+
+// retrievalScope returns the transcriptions a RAG query may draw on: those in a smart folder,
+// in a project or the projects in it, and carrying every given tag, or nil when none of these
+// limits it
+func retrievalScope(c *gin.Context, folderID, projectID string, tags []string) ([]string, bool) {
+	tags = folders.NormalizeTerms(tags)
+	if folderID == "" && projectID == "" && len(tags) == 0 {
+		return nil, true
+	}
+	var ids []string
+	if folderID != "" || len(tags) > 0 {
+		folder := &models.SmartFolder{}
+		if folderID != "" {
+			var ok bool
+			if folder, ok = loadFolder(c, folderID); !ok {
+				return nil, false
+			}
+		}
+		folder.Filter.Tags = folders.NormalizeTerms(append(folder.Filter.Tags, tags...))
+		var err error
+		if ids, err = folders.MatchingJobIDs(folder); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return nil, false
+		}
+	}
+
+	if projectID != "" {
+		project, ok := loadProject(c, projectID, http.StatusNotFound)
+		if !ok {
+			return nil, false
+		}
+		subtree, err := projects.Subtree(project.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		query := database.DB.Model(&models.TranscriptionJob{}).Where("project_id IN ?", subtree)
+		if folderID != "" || len(tags) > 0 {
+			query = query.Where("id IN ?", ids)
+		}
+		ids = nil
+		if err := query.Pluck("id", &ids).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get project transcriptions"})
+			return nil, false
+		}
+	}
+	if ids == nil {
+		// Nothing matches, which is different from no limit
+		ids = []string{}
+	}
+	return ids, true
+}
+
+// bindFolderRequest parses and validates a smart folder request
+func bindFolderRequest(c *gin.Context) (*SmartFolderRequest, bool) {
+	var req SmartFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return nil, false
+	}
+	if err := folders.Validate(&req.Filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return &req, true
+}
+
+// ListSmartFolders returns the caller's smart folders with the number of transcriptions in each
+// @Summary List smart folders
+// @Description List saved filters shown as virtual folders, each with its current transcription count
+// @Tags folders
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/folders [get]
+func (h *Handler) ListSmartFolders(c *gin.Context) {
+	var saved []models.SmartFolder
+	if err := scopeToOwner(database.DB, currentUserID(c)).Order("name ASC").Find(&saved).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list folders"})
+		return
+	}
+
+	result := make([]gin.H, 0, len(saved))
+	for _, folder := range saved {
+		var count int64
+		folders.Apply(database.DB.Model(&models.TranscriptionJob{}), folder.Filter).
+			Where("transcription_jobs.id NOT LIKE 'track_%'").
+			Count(&count)
+		result = append(result, gin.H{
+			"id":         folder.ID,
+			"name":       folder.Name,
+			"filter":     folder.Filter,
+			"count":      count,
+			"created_at": folder.CreatedAt,
+			"updated_at": folder.UpdatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"folders": result})
+}
+
+// CreateSmartFolder saves a new smart folder
+// @Summary Create a smart folder
+// @Description Save a filter over tags, speakers, text, status and dates as a virtual folder. Its contents stay up to date as transcriptions change.
+// @Tags folders
+// @Accept json
+// @Produce json
+// @Param request body SmartFolderRequest true "Folder name and filter"
+// @Success 201 {object} models.SmartFolder
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/folders [post]
+func (h *Handler) CreateSmartFolder(c *gin.Context) {
+	req, ok := bindFolderRequest(c)
+	if !ok {
+		return
+	}
+
+	folder := models.SmartFolder{
+		UserID: currentUserID(c),
+		Name:   req.Name,
+		Filter: req.Filter,
+	}
+	if err := database.DB.Create(&folder).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create folder"})
+		return
+	}
+	c.JSON(http.StatusCreated, folder)
+}
+
+// UpdateSmartFolder changes a smart folder's name and filter
+// @Summary Update a smart folder
+// @Tags folders
+// @Accept json
+// @Produce json
+// @Param id path string true "Folder ID"
+// @Param request body SmartFolderRequest true "Folder name and filter"
+// @Success 200 {object} models.SmartFolder
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/folders/{id} [put]
+func (h *Handler) UpdateSmartFolder(c *gin.Context) {
+	folder, ok := loadFolder(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	req, ok := bindFolderRequest(c)
+	if !ok {
+		return
+	}
+
+	folder.Name = req.Name
+	folder.Filter = req.Filter
+	if err := database.DB.Save(folder).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update folder"})
+		return
+	}
+	c.JSON(http.StatusOK, folder)
+}
+
+// DeleteSmartFolder deletes a smart folder; the transcriptions in it are not affected
+// @Summary Delete a smart folder
+// @Tags folders
+// @Param id path string true "Folder ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/folders/{id} [delete]
+func (h *Handler) DeleteSmartFolder(c *gin.Context) {
+	folder, ok := loadFolder(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	if err := database.DB.Delete(folder).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete folder"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Folder deleted successfully"})
+}
+
+// UpdateTranscriptionTags replaces the tags of a transcription
+// @Summary Update transcription tags
+// @Description Replace the tags of a transcription. Tags are trimmed and de-duplicated case-insensitively; tags that don't exist yet are created.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param request body UpdateTagsRequest true "Tags"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/tags [put]
+func (h *Handler) UpdateTranscriptionTags(c *gin.Context) {
+	jobID := c.Param("id")
+
+	var req UpdateTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var job models.TranscriptionJob
+	if err := database.DB.Where("id = ?", jobID).First(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transcription not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transcription"})
+		return
+	}
+
+	tags, err := tagging.SetJobTags(&job, req.Tags)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tags"})
+		return
+	}
+	h.refreshRAGMetadata(job.ID)
+
+	c.JSON(http.StatusOK, gin.H{"id": job.ID, "tags": tags})
+}
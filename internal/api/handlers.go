@@ -7,6 +7,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,14 +18,34 @@ import (
 	"strings"
 	"time"
 
+	"scriberr/internal/audio"
+	"scriberr/internal/audit"
 	"scriberr/internal/auth"
+	"scriberr/internal/batch"
+	"scriberr/internal/companion"
 	"scriberr/internal/config"
 	"scriberr/internal/database"
+	"scriberr/internal/documents"
+	"scriberr/internal/events"
+	"scriberr/internal/folders"
+	"scriberr/internal/joblog"
+	"scriberr/internal/llm"
+	"scriberr/internal/mailin"
 	"scriberr/internal/models"
+	"scriberr/internal/podcasts"
 	"scriberr/internal/processing"
+	"scriberr/internal/projects"
 	"scriberr/internal/queue"
+	"scriberr/internal/quota"
 	"scriberr/internal/rag"
+	"scriberr/internal/resources"
+	"scriberr/internal/resummarize"
+	"scriberr/internal/scheduler"
+	"scriberr/internal/storage"
+	"scriberr/internal/topics"
 	"scriberr/internal/transcription"
+	"scriberr/internal/versions"
+	"scriberr/internal/workflow"
 	"scriberr/pkg/logger"
 
 	"github.com/gin-gonic/gin"
@@ -41,6 +62,22 @@ type Handler struct {
 	quickTranscription  *transcription.QuickTranscriptionService
 	multiTrackProcessor *processing.MultiTrackProcessor
 	ragService          *rag.RAGService
+	workflowEngine      *workflow.Engine
+	documentIngester    *documents.Ingester
+	topicService        *topics.Service
+	resummarizer        *resummarize.Service
+	scheduler           *scheduler.Service
+	batches             *batch.Service
+	llmRegistry         *llm.Registry
+	companionService    *companion.Service
+	resourceGuard       *resources.Guard
+	quotas              *quota.Service
+	auditRecorder       *audit.Recorder
+	jobLogs             *joblog.Store
+	files               *storage.Files
+	watchdog            *queue.Watchdog
+	podcasts            *podcasts.Service
+	emailIn             *mailin.Service
 }
 
 // NewHandler creates a new handler
@@ -53,9 +90,17 @@ func NewHandler(cfg *config.Config, authService *auth.AuthService, taskQueue *qu
 		quickTranscription:  quickTranscription,
 		multiTrackProcessor: processing.NewMultiTrackProcessor(),
 		ragService:          ragService,
+		resourceGuard:       newResourceGuard(cfg),
+		quotas:              newQuotaService(cfg),
+		auditRecorder:       newAuditRecorder(cfg),
 	}
 }
 
+// SetWorkflowEngine sets the post-processing workflow engine (nil when post-processing is disabled)
+func (h *Handler) SetWorkflowEngine(engine *workflow.Engine) {
+	h.workflowEngine = engine
+}
+
 // SubmitJobRequest represents the submit job request
 type SubmitJobRequest struct {
 	Title       *string               `json:"title,omitempty"`
@@ -75,6 +120,7 @@ type LoginResponse struct {
 	User  struct {
 		ID       uint   `json:"id"`
 		Username string `json:"username"`
+		Role     string `json:"role"` // admin, member or viewer
 	} `json:"user"`
 }
 
@@ -108,6 +154,11 @@ type ChangeUsernameRequest struct {
 type CreateAPIKeyRequest struct {
 	Name        string `json:"name" binding:"required,min=1,max=100"`
 	Description string `json:"description,omitempty"`
+	// Scopes limits the key to read, upload and/or rag_chat; without scopes it can do everything
+	Scopes    []string   `json:"scopes,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Unset for a key that works until revoked
+	// RateLimitPerMinute gives the key a rate limit of its own, which applies on top of its user's
+	RateLimitPerMinute *int `json:"rate_limit_per_minute,omitempty"`
 }
 
 // CreateAPIKeyResponse represents the create API key response
@@ -135,7 +186,7 @@ type YouTubeDownloadResponse struct {
 
 // LLMConfigRequest represents the LLM configuration request
 type LLMConfigRequest struct {
-	Provider string  `json:"provider" binding:"required,oneof=ollama openai"`
+	Provider string  `json:"provider" binding:"required,oneof=ollama openai anthropic"`
 	BaseURL  *string `json:"base_url,omitempty"`
 	APIKey   *string `json:"api_key,omitempty"`
 	IsActive bool    `json:"is_active"`
@@ -162,6 +213,12 @@ type APIKeyListResponse struct {
 	CreatedAt   string `json:"created_at"`
 	UpdatedAt   string `json:"updated_at"`
 	LastUsed    string `json:"last_used,omitempty"`
+	// Scopes is empty for keys that can do everything their user can
+	Scopes    []string `json:"scopes"`
+	ExpiresAt string   `json:"expires_at,omitempty"`
+	Expired   bool     `json:"expired"`
+	// RateLimitPerMinute is set for keys with a rate limit of their own
+	RateLimitPerMinute *int `json:"rate_limit_per_minute,omitempty"`
 }
 
 // APIKeysWrapper wraps the API keys list response
@@ -188,15 +245,28 @@ func transformAPIKeyForList(apiKey models.APIKey) APIKeyListResponse {
 		description = *apiKey.Description
 	}
 
+	scopes := apiKey.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	expiresAt := ""
+	if apiKey.ExpiresAt != nil {
+		expiresAt = apiKey.ExpiresAt.Format(time.RFC3339)
+	}
+
 	return APIKeyListResponse{
-		ID:          apiKey.ID,
-		Name:        apiKey.Name,
-		Description: description,
-		KeyPreview:  keyPreview,
-		IsActive:    apiKey.IsActive,
-		CreatedAt:   apiKey.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   apiKey.UpdatedAt.Format(time.RFC3339),
-		LastUsed:    lastUsed,
+		ID:                 apiKey.ID,
+		Name:               apiKey.Name,
+		Description:        description,
+		KeyPreview:         keyPreview,
+		IsActive:           apiKey.IsActive,
+		CreatedAt:          apiKey.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:          apiKey.UpdatedAt.Format(time.RFC3339),
+		LastUsed:           lastUsed,
+		Scopes:             scopes,
+		ExpiresAt:          expiresAt,
+		Expired:            apiKey.ExpiresAt != nil && !apiKey.ExpiresAt.After(time.Now()),
+		RateLimitPerMinute: apiKey.RateLimitPerMinute,
 	}
 }
 
@@ -207,6 +277,15 @@ func transformAPIKeyForList(apiKey models.APIKey) APIKeyListResponse {
 // @Produce json
 // @Param audio formData file true "Audio file"
 // @Param title formData string false "Job title"
+// @Param initial_prompt formData string false "Initial prompt text to bias recognition"
+// @Param participants formData string false "Comma-separated participant names added to the initial prompt"
+// @Param agenda formData string false "Meeting agenda added to the initial prompt"
+// @Param glossary formData string false "Comma-separated glossary terms added to the initial prompt"
+// @Param vocabulary formData string false "Comma-separated terms added to the job's vocabulary"
+// @Param content_type formData string false "meeting, voice_memo or podcast; decides the RAG collection, chunking and chat prompts" default(meeting)
+// @Param project_id formData string false "Project to file the transcription in; gives it the project's content type unless content_type is set, its tags and its post-processing settings"
+// @Param priority formData int false "Queue priority from -10 to 10; higher is transcribed first" default(0)
+// @Param template formData string false "Name of a job template whose profile, engine, model, diarization, tags and webhooks to use"
 // @Success 200 {object} models.TranscriptionJob
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -251,29 +330,98 @@ func (h *Handler) UploadAudio(c *gin.Context) {
 	// Create job record with "uploaded" status (not queued for transcription)
 	job := models.TranscriptionJob{
 		ID:        jobID,
+		UserID:    currentUserID(c),
 		AudioPath: filePath,
 		Status:    models.StatusUploaded, // New status for uploaded but not transcribed
 	}
 
+	// Job-specific prompt context (participants, agenda, glossary) to bias recognition
+	jobPrompt := initialPromptFromForm(c)
+	job.Parameters.InitialPrompt = mergeInitialPrompt(nil, jobPrompt)
+
+	var ok bool
+	if job.SummaryTemplateID, ok = summaryTemplateFromForm(c); !ok {
+		os.Remove(filePath)
+		return
+	}
+	if job.ContentType, ok = contentTypeFromForm(c); !ok {
+		os.Remove(filePath)
+		return
+	}
+	if job.Priority, ok = priorityFromForm(c); !ok {
+		os.Remove(filePath)
+		return
+	}
+	template, ok := jobTemplateFromForm(c)
+	if !ok {
+		os.Remove(filePath)
+		return
+	}
+	if template != nil {
+		job.JobTemplateID = &template.ID
+		job.WebhookURLs = template.WebhookURLs
+	}
+	project, ok := projectFromForm(c)
+	if !ok {
+		os.Remove(filePath)
+		return
+	}
+	project.apply(c, &job)
+	jobTerms, ok := vocabularyFromForm(c)
+	if !ok {
+		os.Remove(filePath)
+		return
+	}
+
 	if title := c.PostForm("title"); title != "" {
 		job.Title = &title
 	}
 
+	if !h.storeFile(c, filePath) {
+		return
+	}
+
 	// Save to database
 	if err := database.DB.Create(&job).Error; err != nil {
 		os.Remove(filePath) // Clean up file
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
 		return
 	}
+	var tags []string
+	if template != nil {
+		tags = template.Tags
+	}
+	project.tag(&job, tags)
+	if err := saveJobVocabulary(&job, jobTerms); err != nil {
+		logger.Warn("Failed to save job vocabulary", "job_id", jobID, "error", err)
+	}
+
+	// Auto-transcribe: Get the template's profile, the default profile or the system default
+	h.autoTranscribe(currentUserID(c), &job, jobPrompt, template)
 
-	// Auto-transcribe: Get default profile or use system default
+	// Started after the job is saved for the last time so the report isn't overwritten
+	go checkAudioQuality(jobID, filePath)
+
+	c.JSON(http.StatusOK, job)
+}
+
+// autoTranscribe queues a new upload for transcription with the profile of its job template,
+// if any, or else the default profile of its owner, the system default or the first profile,
+// with the template's engine, model and diarization applied. Without any profile the job
+// stays uploaded.
+func (h *Handler) autoTranscribe(userID *uint, job *models.TranscriptionJob, jobPrompt string, template *models.JobTemplate) {
 	var profile models.TranscriptionProfile
 	var profileFound bool
 
+	if template != nil && template.ProfileID != nil {
+		err := database.DB.Where("id = ?", *template.ProfileID).First(&profile).Error
+		profileFound = (err == nil)
+	}
+
 	// Try to get user's default profile if authenticated
-	if userID, exists := c.Get("user_id"); exists {
+	if !profileFound && userID != nil {
 		var user models.User
-		if err := database.DB.First(&user, userID).Error; err == nil && user.DefaultProfileID != nil {
+		if err := database.DB.First(&user, *userID).Error; err == nil && user.DefaultProfileID != nil {
 			err = database.DB.Where("id = ?", *user.DefaultProfileID).First(&profile).Error
 			profileFound = (err == nil)
 		}
@@ -281,34 +429,49 @@ func (h *Handler) UploadAudio(c *gin.Context) {
 
 	// If no user default or user default not found, try to find a system default
 	if !profileFound {
-		err = database.DB.Where("is_default = ?", true).First(&profile).Error
+		err := database.DB.Where("is_default = ?", true).First(&profile).Error
 		profileFound = (err == nil)
 	}
 
 	// If still no profile found, use the first available profile
 	if !profileFound {
-		err = database.DB.Order("created_at ASC").First(&profile).Error
+		err := database.DB.Order("created_at ASC").First(&profile).Error
 		profileFound = (err == nil)
 	}
 
 	// If we found a profile, update the job and queue it for auto-transcription
 	if profileFound {
 		job.Parameters = profile.Parameters
-		job.Diarization = profile.Parameters.Diarize
+		job.Parameters.InitialPrompt = mergeInitialPrompt(profile.Parameters.InitialPrompt, jobPrompt)
+		if template != nil {
+			if template.ModelFamily != nil {
+				job.Parameters.ModelFamily = *template.ModelFamily
+			}
+			if template.Model != nil {
+				job.Parameters.Model = *template.Model
+			}
+			if template.Diarize != nil {
+				job.Parameters.Diarize = *template.Diarize
+			}
+		}
+		// Each track of a multi-track recording is its own speaker, so it isn't diarized
+		if job.IsMultiTrack {
+			job.Parameters.IsMultiTrackEnabled = true
+			job.Parameters.Diarize = false
+		}
+		job.Diarization = job.Parameters.Diarize
 		job.Status = models.StatusPending
 
 		// Update the job in database
-		if err := database.DB.Save(&job).Error; err == nil {
+		if err := database.DB.Save(job).Error; err == nil {
 			// Enqueue the job for transcription
-			if err := h.taskQueue.EnqueueJob(jobID); err != nil {
+			if err := h.taskQueue.EnqueueJob(job.ID); err != nil {
 				// If enqueueing fails, revert status but don't fail the upload
 				job.Status = models.StatusUploaded
-				database.DB.Save(&job)
+				database.DB.Save(job)
 			}
 		}
 	}
-
-	c.JSON(http.StatusOK, job)
 }
 
 // @Summary Upload video file for transcription
@@ -318,6 +481,13 @@ func (h *Handler) UploadAudio(c *gin.Context) {
 // @Produce json
 // @Param video formData file true "Video file"
 // @Param title formData string false "Job title"
+// @Param initial_prompt formData string false "Initial prompt text to bias recognition"
+// @Param participants formData string false "Comma-separated participant names added to the initial prompt"
+// @Param agenda formData string false "Meeting agenda added to the initial prompt"
+// @Param glossary formData string false "Comma-separated glossary terms added to the initial prompt"
+// @Param content_type formData string false "meeting, voice_memo or podcast; decides the RAG collection, chunking and chat prompts" default(meeting)
+// @Param project_id formData string false "Project to file the transcription in; gives it the project's content type unless content_type is set, its tags and its post-processing settings"
+// @Param priority formData int false "Queue priority from -10 to 10; higher is transcribed first" default(0)
 // @Success 200 {object} models.TranscriptionJob
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -390,20 +560,49 @@ func (h *Handler) UploadVideo(c *gin.Context) {
 	// Create job record with "uploaded" status (not queued for transcription)
 	job := models.TranscriptionJob{
 		ID:        jobID,
+		UserID:    currentUserID(c),
 		AudioPath: audioPath,
 		Status:    models.StatusUploaded, // Same status as audio uploads
 	}
 
+	jobPrompt := initialPromptFromForm(c)
+	job.Parameters.InitialPrompt = mergeInitialPrompt(nil, jobPrompt)
+
+	var ok bool
+	if job.SummaryTemplateID, ok = summaryTemplateFromForm(c); !ok {
+		os.Remove(audioPath)
+		return
+	}
+	if job.ContentType, ok = contentTypeFromForm(c); !ok {
+		os.Remove(audioPath)
+		return
+	}
+	if job.Priority, ok = priorityFromForm(c); !ok {
+		os.Remove(audioPath)
+		return
+	}
+	project, ok := projectFromForm(c)
+	if !ok {
+		os.Remove(audioPath)
+		return
+	}
+	project.apply(c, &job)
+
 	if title := c.PostForm("title"); title != "" {
 		job.Title = &title
 	}
 
+	if !h.storeFile(c, audioPath) {
+		return
+	}
+
 	// Save to database
 	if err := database.DB.Create(&job).Error; err != nil {
 		os.Remove(audioPath) // Clean up audio file
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
 		return
 	}
+	project.tag(&job, nil)
 
 	// Check for auto-transcription if user is authenticated via JWT (same logic as audio upload)
 	if userID, exists := c.Get("user_id"); exists {
@@ -433,6 +632,7 @@ func (h *Handler) UploadVideo(c *gin.Context) {
 			// If we found a profile, update the job and queue it
 			if profileFound {
 				job.Parameters = profile.Parameters
+				job.Parameters.InitialPrompt = mergeInitialPrompt(profile.Parameters.InitialPrompt, jobPrompt)
 				job.Diarization = profile.Parameters.Diarize
 				job.Status = models.StatusPending
 
@@ -449,6 +649,9 @@ func (h *Handler) UploadVideo(c *gin.Context) {
 		}
 	}
 
+	// Started after the job is saved for the last time so the report isn't overwritten
+	go checkAudioQuality(jobID, audioPath)
+
 	c.JSON(http.StatusOK, job)
 }
 
@@ -595,6 +798,7 @@ func (h *Handler) UploadMultiTrack(c *gin.Context) {
 	// Create transcription job record
 	job := models.TranscriptionJob{
 		ID:               jobID,
+		UserID:           currentUserID(c),
 		Title:            &title,
 		AudioPath:        firstTrackPath, // Point to first track initially
 		Status:           models.StatusUploaded,
@@ -786,6 +990,16 @@ func (h *Handler) GetTrackProgress(c *gin.Context) {
 // @Param vad_offset formData number false "VAD offset" default(0.363)
 // @Param min_speakers formData int false "Minimum speakers for diarization"
 // @Param max_speakers formData int false "Maximum speakers for diarization"
+// @Param initial_prompt formData string false "Initial prompt text to bias recognition"
+// @Param participants formData string false "Comma-separated participant names added to the initial prompt"
+// @Param agenda formData string false "Meeting agenda added to the initial prompt"
+// @Param glossary formData string false "Comma-separated glossary terms added to the initial prompt"
+// @Param auto_enhance formData boolean false "Enhance the audio before transcription if the quality check flags it as poor"
+// @Param preprocess formData string false "Comma-separated preprocessing steps applied before transcription: trim_silence, skip_silence, denoise, normalize, resample; none for none (default: AUDIO_PREPROCESS)"
+// @Param vocabulary formData string false "Comma-separated terms added to the job's vocabulary"
+// @Param content_type formData string false "meeting, voice_memo or podcast; decides the RAG collection, chunking and chat prompts" default(meeting)
+// @Param project_id formData string false "Project to file the transcription in; gives it the project's content type unless content_type is set, its tags and its post-processing settings"
+// @Param priority formData int false "Queue priority from -10 to 10; higher is transcribed first" default(0)
 // @Success 200 {object} models.TranscriptionJob
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -842,6 +1056,13 @@ func (h *Handler) SubmitJob(c *gin.Context) {
 		VadOnset:    getFormFloatWithDefault(c, "vad_onset", 0.500),
 		VadOffset:   getFormFloatWithDefault(c, "vad_offset", 0.363),
 		Diarize:     diarize,
+		AutoEnhance: getFormBoolWithDefault(c, "auto_enhance", false),
+		Preprocess:  c.PostForm("preprocess"),
+	}
+	if _, err := audio.ParsePreprocessSteps(params.Preprocess); err != nil {
+		os.Remove(filePath)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
 	if lang := c.PostForm("language"); lang != "" {
@@ -864,6 +1085,34 @@ func (h *Handler) SubmitJob(c *gin.Context) {
 		params.HfToken = &hfToken
 	}
 
+	params.InitialPrompt = mergeInitialPrompt(nil, initialPromptFromForm(c))
+
+	summaryTemplateID, ok := summaryTemplateFromForm(c)
+	if !ok {
+		os.Remove(filePath)
+		return
+	}
+	contentType, ok := contentTypeFromForm(c)
+	if !ok {
+		os.Remove(filePath)
+		return
+	}
+	priority, ok := priorityFromForm(c)
+	if !ok {
+		os.Remove(filePath)
+		return
+	}
+	project, ok := projectFromForm(c)
+	if !ok {
+		os.Remove(filePath)
+		return
+	}
+	jobTerms, ok := vocabularyFromForm(c)
+	if !ok {
+		os.Remove(filePath)
+		return
+	}
+
 	// Parse and validate diarization model
 	diarizeModel := getFormValueWithDefault(c, "diarize_model", "pyannote")
 	if diarizeModel != "pyannote" && diarizeModel != "nvidia_sortformer" {
@@ -874,23 +1123,36 @@ func (h *Handler) SubmitJob(c *gin.Context) {
 
 	// Create job
 	job := models.TranscriptionJob{
-		ID:          jobID,
-		AudioPath:   filePath,
-		Status:      models.StatusPending,
-		Diarization: diarize,
-		Parameters:  params,
-	}
+		ID:                jobID,
+		UserID:            currentUserID(c),
+		AudioPath:         filePath,
+		Status:            models.StatusPending,
+		Diarization:       diarize,
+		Parameters:        params,
+		SummaryTemplateID: summaryTemplateID,
+		ContentType:       contentType,
+		Priority:          priority,
+	}
+	project.apply(c, &job)
 
 	if title := c.PostForm("title"); title != "" {
 		job.Title = &title
 	}
 
+	if !h.storeFile(c, filePath) {
+		return
+	}
+
 	// Save to database
 	if err := database.DB.Create(&job).Error; err != nil {
 		os.Remove(filePath) // Clean up file
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
 		return
 	}
+	project.tag(&job, nil)
+	if err := saveJobVocabulary(&job, jobTerms); err != nil {
+		logger.Warn("Failed to save job vocabulary", "job_id", jobID, "error", err)
+	}
 
 	// Enqueue job
 	if err := h.taskQueue.EnqueueJob(jobID); err != nil {
@@ -898,6 +1160,8 @@ func (h *Handler) SubmitJob(c *gin.Context) {
 		return
 	}
 
+	go checkAudioQuality(jobID, filePath)
+
 	c.JSON(http.StatusOK, job)
 }
 
@@ -928,7 +1192,7 @@ func (h *Handler) GetJobStatus(c *gin.Context) {
 }
 
 // @Summary Get transcript
-// @Description Get the transcript for a completed transcription job
+// @Description Get the transcript for a completed transcription job, with its chapters if they have been generated
 // @Tags transcription
 // @Produce json
 // @Param id path string true "Job ID"
@@ -969,23 +1233,35 @@ func (h *Handler) GetTranscript(c *gin.Context) {
 		return
 	}
 
+	chapters, err := jobChapters(job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get chapters"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"job_id":     job.ID,
 		"title":      job.Title,
 		"transcript": transcript,
+		"chapters":   chapters,
 		"created_at": job.CreatedAt,
 		"updated_at": job.UpdatedAt,
 	})
 }
 
 // @Summary List all transcription records
-// @Description Get a list of all transcription jobs with optional search and filtering
+// @Description Get a list of the caller's transcription jobs with optional search and filtering. Admins also see transcriptions without an owner, such as dropzone uploads.
 // @Tags transcription
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param status query string false "Filter by status"
 // @Param q query string false "Search in title and audio filename"
+// @Param folder query string false "Only transcriptions in this smart folder"
+// @Param project query string false "Only transcriptions filed in this project, or none for those in no project"
+// @Param subprojects query bool false "With project, also the transcriptions of the projects in it"
+// @Param tag query []string false "Only transcriptions with every one of these tags (repeat for several)" collectionFormat(multi)
+// @Param all query bool false "Admins only: list every user's transcriptions"
 // @Success 200 {object} map[string]interface{}
 // @Router /api/v1/transcription/list [get]
 // @Security ApiKeyAuth
@@ -1010,11 +1286,51 @@ func (h *Handler) ListJobs(c *gin.Context) {
 	// Filter out temporary track jobs (they have IDs starting with "track_")
 	query = query.Where("id NOT LIKE 'track_%'")
 
+	// Users see their own transcriptions; admins also those without an owner, or with all every user's
+	if userID := currentUserID(c); !isAdmin(c) || (userID == nil && c.Query("all") != "true") {
+		query = scopeToOwner(query, userID)
+	} else if c.Query("all") != "true" {
+		query = query.Where("(user_id = ? OR user_id IS NULL)", *userID)
+	}
+
 	// Apply status filter
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
 
+	// Restrict to a smart folder's transcriptions
+	if folderID := c.Query("folder"); folderID != "" {
+		folder, ok := loadFolder(c, folderID)
+		if !ok {
+			return
+		}
+		query = folders.Apply(query, folder.Filter)
+	}
+
+	// Restrict to a project's transcriptions, and with subprojects to those of the projects in it
+	if projectID := c.Query("project"); projectID == "none" {
+		query = query.Where("project_id IS NULL")
+	} else if projectID != "" {
+		project, ok := loadProject(c, projectID, http.StatusNotFound)
+		if !ok {
+			return
+		}
+		ids := []string{project.ID}
+		if c.Query("subprojects") == "true" {
+			var err error
+			if ids, err = projects.Subtree(project.ID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get subprojects"})
+				return
+			}
+		}
+		query = query.Where("project_id IN ?", ids)
+	}
+
+	// Restrict to transcriptions carrying every requested tag
+	if tags := c.QueryArray("tag"); len(tags) > 0 {
+		query = folders.Apply(query, models.SmartFolderFilter{Tags: folders.NormalizeTerms(tags)})
+	}
+
 	// Apply search filter - search in title and audio_path
 	if search != "" {
 		searchPattern := "%" + search + "%"
@@ -1052,6 +1368,7 @@ func (h *Handler) ListJobs(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Job ID"
 // @Param parameters body models.WhisperXParams true "Transcription parameters"
+// @Param priority query int false "Queue priority from -10 to 10; higher is transcribed first (default: keep the job's)"
 // @Success 200 {object} models.TranscriptionJob
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
@@ -1076,6 +1393,10 @@ func (h *Handler) StartTranscription(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot start transcription: job is currently processing or pending"})
 		return
 	}
+	if job.AudioPath == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot start transcription: the audio has been deleted"})
+		return
+	}
 
 	// Parse transcription parameters from request body
 	var requestParams models.WhisperXParams
@@ -1138,6 +1459,13 @@ func (h *Handler) StartTranscription(c *gin.Context) {
 		"diarize_model", requestParams.DiarizeModel,
 		"language", requestParams.Language)
 
+	// Keep the job's stored initial prompt unless the request sets one; an empty string clears it
+	if requestParams.InitialPrompt == nil {
+		requestParams.InitialPrompt = job.Parameters.InitialPrompt
+	} else if strings.TrimSpace(*requestParams.InitialPrompt) == "" {
+		requestParams.InitialPrompt = nil
+	}
+
 	// Validate NVIDIA-specific constraints
 	if requestParams.ModelFamily == "nvidia_parakeet" || requestParams.ModelFamily == "nvidia_canary" {
 		// Both NVIDIA models support multiple European languages
@@ -1167,12 +1495,24 @@ func (h *Handler) StartTranscription(c *gin.Context) {
 		return
 	}
 
+	priority, ok := parsePriority(c, c.Query("priority"), job.Priority)
+	if !ok {
+		return
+	}
+
 	// Update job with parameters
 	job.Parameters = requestParams
 	job.Diarization = requestParams.Diarize
+	job.Priority = priority
 	job.Status = models.StatusPending
 
-	// Clear previous results for re-transcription
+	// Clear previous results for re-transcription, keeping them as versions
+	for _, kind := range []string{models.VersionKindTranscript, models.VersionKindSummary} {
+		if err := versions.Baseline(database.DB, job.ID, kind); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to keep the previous " + kind})
+			return
+		}
+	}
 	job.Transcript = nil
 	job.Summary = nil
 	job.ErrorMessage = nil
@@ -1290,6 +1630,7 @@ func (h *Handler) UpdateTranscriptionTitle(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update title"})
 		return
 	}
+	h.refreshRAGMetadata(job.ID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"id":         job.ID,
@@ -1308,6 +1649,7 @@ func (h *Handler) UpdateTranscriptionTitle(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /api/v1/transcription/{id} [delete]
 // @Security ApiKeyAuth
 // @Security BearerAuth
@@ -1324,12 +1666,29 @@ func (h *Handler) DeleteJob(c *gin.Context) {
 		return
 	}
 
+	if rejectIfOnHold(c, &job) {
+		return
+	}
+
 	// Prevent deletion of jobs that are currently processing
 	if job.Status == models.StatusProcessing {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot delete job that is currently processing"})
 		return
 	}
 
+	if err := h.deleteJob(&job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Job deleted successfully"})
+}
+
+// deleteJob deletes a transcription with its files and everything recorded about it. The
+// error says which part failed, fit for an API response.
+func (h *Handler) deleteJob(job *models.TranscriptionJob) error {
+	jobID := job.ID
+
 	// Delete the audio file from filesystem
 	if job.AudioPath != "" {
 		if err := os.Remove(job.AudioPath); err != nil && !os.IsNotExist(err) {
@@ -1352,6 +1711,9 @@ func (h *Handler) DeleteJob(c *gin.Context) {
 		}
 	}
 
+	// Delete the copies in object storage
+	h.removeStoredJob(job)
+
 	// Delete any transcript files
 	if job.Transcript != nil {
 		// Remove transcript directory if it exists (assume it's in data/transcripts)
@@ -1373,64 +1735,173 @@ func (h *Handler) DeleteJob(c *gin.Context) {
 	// Delete related records in order (children first)
 	if err := tx.Where("transcription_job_id = ?", jobID).Delete(&models.TranscriptionJobExecution{}).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete job execution records"})
-		return
+		return errors.New("Failed to delete job execution records")
 	}
 
 	if err := tx.Where("transcription_job_id = ?", jobID).Delete(&models.SpeakerMapping{}).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete speaker mappings"})
-		return
+		return errors.New("Failed to delete speaker mappings")
+	}
+
+	if err := tx.Where("transcription_job_id = ?", jobID).Delete(&models.JobSpeaker{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete job speakers")
+	}
+
+	if err := tx.Where("transcription_id = ?", jobID).Delete(&models.TranscriptVersion{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete transcript versions")
+	}
+
+	if err := tx.Where("transcription_id = ?", jobID).Delete(&models.TranscriptRevision{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete transcript revisions")
+	}
+
+	if err := tx.Where("transcription_id = ?", jobID).Delete(&models.VocabularyTerm{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete vocabulary terms")
 	}
 
 	if err := tx.Where("transcription_job_id = ?", jobID).Delete(&models.MultiTrackFile{}).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete multi-track files"})
-		return
+		return errors.New("Failed to delete multi-track files")
 	}
 
 	if err := tx.Where("transcription_id = ?", jobID).Delete(&models.Note{}).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete notes"})
-		return
+		return errors.New("Failed to delete notes")
+	}
+
+	if err := tx.Where("transcription_job_id = ?", jobID).Delete(&models.Translation{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete translations")
+	}
+
+	if err := tx.Where("transcription_id = ?", jobID).Delete(&models.ActionItem{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete action items")
+	}
+
+	if err := tx.Where("transcription_id = ?", jobID).Delete(&models.TranscriptionTag{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete tag links")
+	}
+
+	if err := tx.Where("transcription_id = ?", jobID).Delete(&models.EntityMention{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete entity mentions")
+	}
+
+	if err := tx.Where("transcription_id = ?", jobID).Delete(&models.ConversationAnalytics{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete analytics")
+	}
+
+	if err := tx.Where("transcription_id = ?", jobID).Delete(&models.Chapter{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete chapters")
+	}
+
+	if err := tx.Where("transcription_id = ?", jobID).Delete(&models.Redaction{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete redacted transcript")
+	}
+
+	if err := tx.Where("transcription_id = ?", jobID).Delete(&models.ActionItemDelivery{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete action item deliveries")
+	}
+
+	if err := tx.Where("transcription_id = ?", jobID).Delete(&models.WatchlistMatch{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete watchlist matches")
+	}
+
+	if err := tx.Where("first_id = ? OR second_id = ?", jobID, jobID).Delete(&models.DuplicateCandidate{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete duplicate candidates")
+	}
+
+	// Duplicates merged into this transcription are on their own again
+	if err := tx.Model(&models.TranscriptionJob{}).Where("duplicate_of = ?", jobID).Update("duplicate_of", nil).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to release merged duplicates")
+	}
+
+	// Share links and their access logs
+	if err := tx.Where("share_link_id IN (?)", tx.Model(&models.ShareLink{}).Select("id").Where("transcription_id = ?", jobID)).Delete(&models.ShareLinkAccess{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete share link accesses")
+	}
+	if err := tx.Where("transcription_id = ?", jobID).Delete(&models.ShareLink{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete share links")
+	}
+
+	// Excerpts of the transcript kept as sources of chat answers
+	if err := tx.Where("transcription_id = ?", jobID).Delete(&models.RAGAnswerSource{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete answer sources")
+	}
+
+	// Delete workflow runs and their steps
+	if err := tx.Where("run_id IN (?)", tx.Model(&models.WorkflowRun{}).Select("id").Where("transcription_id = ?", jobID)).Delete(&models.WorkflowStep{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete workflow steps")
+	}
+	if err := tx.Where("transcription_id = ?", jobID).Delete(&models.WorkflowRun{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete workflow runs")
+	}
+	if err := tx.Where("job_id = ?", jobID).Delete(&models.JobLogEntry{}).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to delete job log")
+	}
+
+	// Keep documents linked to this recording as standalone documents
+	if err := tx.Model(&models.Document{}).Where("transcription_id = ?", jobID).Update("transcription_id", nil).Error; err != nil {
+		tx.Rollback()
+		return errors.New("Failed to unlink documents")
 	}
 
 	// Delete chat sessions and their messages
 	var chatSessions []models.ChatSession
 	if err := tx.Where("transcription_id = ?", jobID).Find(&chatSessions).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find chat sessions"})
-		return
+		return errors.New("Failed to find chat sessions")
 	}
 
 	for _, session := range chatSessions {
 		if err := tx.Where("chat_session_id = ?", session.ID).Delete(&models.ChatMessage{}).Error; err != nil {
 			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete chat messages"})
-			return
+			return errors.New("Failed to delete chat messages")
 		}
 	}
 
 	if err := tx.Where("transcription_id = ?", jobID).Delete(&models.ChatSession{}).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete chat sessions"})
-		return
+		return errors.New("Failed to delete chat sessions")
 	}
 
 	// Finally delete the main job record
-	if err := tx.Delete(&job).Error; err != nil {
+	if err := tx.Delete(job).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete job from database"})
-		return
+		return errors.New("Failed to delete job from database")
+	}
+
+	// Synced clients learn of the deletion from the event log
+	if err := events.Append(tx, models.EventJobDeleted, jobID, job.UserID, nil); err != nil {
+		tx.Rollback()
+		return errors.New("Failed to record the deletion")
 	}
 
 	// Commit the transaction
 	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit deletion transaction"})
-		return
+		return errors.New("Failed to commit deletion transaction")
 	}
+	return nil
 
-	c.JSON(http.StatusOK, gin.H{"message": "Job deleted successfully"})
 }
 
 // @Summary Get transcription record by ID
@@ -1577,6 +2048,9 @@ func (h *Handler) GetAudioFile(c *gin.Context) {
 		return
 	}
 
+	// Another node may have received the upload, in which case it's in object storage
+	h.fetchStoredFile(c.Request.Context(), audioPath)
+
 	// Check if file exists on filesystem
 	if _, err := os.Stat(audioPath); os.IsNotExist(err) {
 		fmt.Printf("DEBUG: Audio file does not exist on disk: %s\n", audioPath)
@@ -1655,6 +2129,7 @@ func (h *Handler) Login(c *gin.Context) {
 	response := LoginResponse{Token: token}
 	response.User.ID = user.ID
 	response.User.Username = user.Username
+	response.User.Role = user.Role
 
 	logger.AuthEvent("login", req.Username, c.ClientIP(), true)
 	c.JSON(http.StatusOK, response)
@@ -1747,10 +2222,11 @@ func (h *Handler) Register(c *gin.Context) {
 		return
 	}
 
-	// Create user
+	// Create user; the first account administers the instance
 	user := models.User{
 		Username: req.Username,
 		Password: hashedPassword,
+		Role:     models.RoleAdmin,
 	}
 
 	if err := database.DB.Create(&user).Error; err != nil {
@@ -1776,6 +2252,7 @@ func (h *Handler) Register(c *gin.Context) {
 	response := LoginResponse{Token: token}
 	response.User.ID = user.ID
 	response.User.Username = user.Username
+	response.User.Role = user.Role
 
 	c.JSON(http.StatusCreated, response)
 }
@@ -1988,7 +2465,7 @@ func (h *Handler) ChangeUsername(c *gin.Context) {
 }
 
 // @Summary List API keys
-// @Description Get all API keys for the current user (without exposing the actual keys)
+// @Description Get all API keys for the current user (without exposing the actual keys). Admins see every user's keys.
 // @Tags api-keys
 // @Produce json
 // @Success 200 {object} APIKeysWrapper
@@ -1996,7 +2473,11 @@ func (h *Handler) ChangeUsername(c *gin.Context) {
 // @Router /api/v1/api-keys [get]
 func (h *Handler) ListAPIKeys(c *gin.Context) {
 	var apiKeys []models.APIKey
-	if err := database.DB.Where("is_active = ?", true).Find(&apiKeys).Error; err != nil {
+	query := database.DB.Where("is_active = ?", true)
+	if !isAdmin(c) {
+		query = scopeToOwner(query, currentUserID(c))
+	}
+	if err := query.Find(&apiKeys).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch API keys"})
 		return
 	}
@@ -2011,7 +2492,7 @@ func (h *Handler) ListAPIKeys(c *gin.Context) {
 }
 
 // @Summary Create API key
-// @Description Create a new API key for external API access
+// @Description Create a new API key for external API access. Scopes limit what the key can do: read allows GET requests, upload allows uploading and importing recordings and checking their status, and rag_chat allows RAG chat and search; a key with several scopes can do what each allows, and one without scopes can do everything. The key stops working at expires_at, if given. rate_limit_per_minute gives the key a rate limit of its own, which applies on top of its user's (0 is unlimited).
 // @Tags api-keys
 // @Accept json
 // @Produce json
@@ -2027,15 +2508,41 @@ func (h *Handler) CreateAPIKey(c *gin.Context) {
 		return
 	}
 
+	var scopes []string
+	seen := map[string]bool{}
+	for _, scope := range req.Scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !models.IsValidAPIKeyScope(scope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown scope %q: scopes are read, upload and rag_chat", scope)})
+			return
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+		return
+	}
+	if req.RateLimitPerMinute != nil && *req.RateLimitPerMinute < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "rate_limit_per_minute can't be negative"})
+		return
+	}
+
 	// Generate a secure API key
 	apiKey := generateSecureAPIKey(32)
 
 	// Create the API key record
 	newKey := models.APIKey{
-		Key:         apiKey,
-		Name:        req.Name,
-		Description: &req.Description,
-		IsActive:    true,
+		Key:                apiKey,
+		Name:               req.Name,
+		Description:        &req.Description,
+		IsActive:           true,
+		UserID:             currentUserID(c),
+		Scopes:             scopes,
+		ExpiresAt:          req.ExpiresAt,
+		RateLimitPerMinute: req.RateLimitPerMinute,
 	}
 
 	if err := database.DB.Create(&newKey).Error; err != nil {
@@ -2064,9 +2571,13 @@ func (h *Handler) DeleteAPIKey(c *gin.Context) {
 		return
 	}
 
-	// Check if the API key exists
+	// Check if the API key exists; only admins can delete other users' keys
 	var apiKey models.APIKey
-	if err := database.DB.First(&apiKey, uint(id)).Error; err != nil {
+	query := database.DB
+	if !isAdmin(c) {
+		query = scopeToOwner(query, currentUserID(c))
+	}
+	if err := query.First(&apiKey, uint(id)).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
 		return
 	}
@@ -2134,10 +2645,14 @@ func (h *Handler) SaveLLMConfig(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Base URL is required for Ollama provider"})
 		return
 	}
-	if req.Provider == "openai" && (req.APIKey == nil || *req.APIKey == "") {
+	if req.Provider == "openai" && (req.APIKey == nil || *req.APIKey == "") && (req.BaseURL == nil || *req.BaseURL == "") {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "API key is required for OpenAI provider"})
 		return
 	}
+	if req.Provider == "anthropic" && (req.APIKey == nil || *req.APIKey == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "API key is required for Anthropic provider"})
+		return
+	}
 
 	// Check if there's an existing active configuration
 	var existingConfig models.LLMConfig
@@ -2253,6 +2768,26 @@ func (h *Handler) HealthCheck(c *gin.Context) {
 }
 
 // Helper functions
+// currentUserID returns the authenticated user's ID, or nil for requests not tied to a user
+func currentUserID(c *gin.Context) *uint {
+	value, exists := c.Get("user_id")
+	if !exists {
+		return nil
+	}
+	if id, ok := value.(uint); ok {
+		return &id
+	}
+	return nil
+}
+
+// scopeToOwner limits a query on a user-owned table to the caller's records
+func scopeToOwner(db *gorm.DB, userID *uint) *gorm.DB {
+	if userID == nil {
+		return db.Where("user_id IS NULL")
+	}
+	return db.Where("user_id = ?", *userID)
+}
+
 func getFormValueWithDefault(c *gin.Context, key, defaultValue string) string {
 	if value := c.PostForm(key); value != "" {
 		return value
@@ -2329,6 +2864,10 @@ func (h *Handler) CreateProfile(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Profile name is required"})
 		return
 	}
+	if _, err := audio.ParsePreprocessSteps(profile.Parameters.Preprocess); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Check if profile name already exists
 	var existingProfile models.TranscriptionProfile
@@ -2409,6 +2948,10 @@ func (h *Handler) UpdateProfile(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Profile name is required"})
 		return
 	}
+	if _, err := audio.ParsePreprocessSteps(updatedProfile.Parameters.Preprocess); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Check if profile name already exists (excluding current profile)
 	var nameCheck models.TranscriptionProfile
@@ -2551,64 +3094,11 @@ func (h *Handler) SubmitQuickTranscription(c *gin.Context) {
 		}
 	} else {
 		// Use default parameters with all required fields
-		params = models.WhisperXParams{
-			// Model parameters
-			Model:          "small",
-			ModelCacheOnly: false,
-
-			// Device and computation
-			Device:      "cpu",
-			DeviceIndex: 0,
-			BatchSize:   8,
-			ComputeType: "float32",
-			Threads:     0,
-
-			// Output settings
-			OutputFormat: "all",
-			Verbose:      true,
-
-			// Task and language
-			Task: "transcribe",
-
-			// Alignment settings
-			InterpolateMethod:    "nearest",
-			NoAlign:              false,
-			ReturnCharAlignments: false,
-
-			// VAD (Voice Activity Detection) settings
-			VadMethod: "pyannote",
-			VadOnset:  0.5,
-			VadOffset: 0.363,
-			ChunkSize: 30,
-
-			// Diarization settings
-			Diarize:           false,
-			DiarizeModel:      "pyannote/speaker-diarization-3.1",
-			SpeakerEmbeddings: false,
-
-			// Transcription quality settings
-			Temperature:                    0,
-			BestOf:                         5,
-			BeamSize:                       5,
-			Patience:                       1.0,
-			LengthPenalty:                  1.0,
-			SuppressNumerals:               false,
-			ConditionOnPreviousText:        false,
-			Fp16:                           true,
-			TemperatureIncrementOnFallback: 0.2,
-			CompressionRatioThreshold:      2.4,
-			LogprobThreshold:               -1.0,
-			NoSpeechThreshold:              0.6,
-
-			// Output formatting
-			HighlightWords:    false,
-			SegmentResolution: "sentence",
-			PrintProgress:     false,
-		}
+		params = defaultQuickParams()
 	}
 
 	// Submit quick transcription job
-	job, err := h.quickTranscription.SubmitQuickJob(file, header.Filename, params)
+	job, err := h.quickTranscription.SubmitQuickJob(file, header.Filename, params, currentUserID(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to submit quick transcription: %v", err)})
 		return
@@ -2617,6 +3107,65 @@ func (h *Handler) SubmitQuickTranscription(c *gin.Context) {
 	c.JSON(http.StatusOK, job)
 }
 
+// defaultQuickParams returns the transcription parameters used for quick transcriptions
+// submitted without a profile or parameters
+func defaultQuickParams() models.WhisperXParams {
+	return models.WhisperXParams{
+		// Model parameters
+		Model:          "small",
+		ModelCacheOnly: false,
+
+		// Device and computation
+		Device:      "cpu",
+		DeviceIndex: 0,
+		BatchSize:   8,
+		ComputeType: "float32",
+		Threads:     0,
+
+		// Output settings
+		OutputFormat: "all",
+		Verbose:      true,
+
+		// Task and language
+		Task: "transcribe",
+
+		// Alignment settings
+		InterpolateMethod:    "nearest",
+		NoAlign:              false,
+		ReturnCharAlignments: false,
+
+		// VAD (Voice Activity Detection) settings
+		VadMethod: "pyannote",
+		VadOnset:  0.5,
+		VadOffset: 0.363,
+		ChunkSize: 30,
+
+		// Diarization settings
+		Diarize:           false,
+		DiarizeModel:      "pyannote/speaker-diarization-3.1",
+		SpeakerEmbeddings: false,
+
+		// Transcription quality settings
+		Temperature:                    0,
+		BestOf:                         5,
+		BeamSize:                       5,
+		Patience:                       1.0,
+		LengthPenalty:                  1.0,
+		SuppressNumerals:               false,
+		ConditionOnPreviousText:        false,
+		Fp16:                           true,
+		TemperatureIncrementOnFallback: 0.2,
+		CompressionRatioThreshold:      2.4,
+		LogprobThreshold:               -1.0,
+		NoSpeechThreshold:              0.6,
+
+		// Output formatting
+		HighlightWords:    false,
+		SegmentResolution: "sentence",
+		PrintProgress:     false,
+	}
+}
+
 // @Summary Get quick transcription status
 // @Description Get the current status of a quick transcription job
 // @Tags transcription
@@ -2649,7 +3198,7 @@ func (h *Handler) GetQuickTranscriptionStatus(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param request body YouTubeDownloadRequest true "YouTube download request"
-// @Success 200 {object} models.Transcription
+// @Success 200 {object} models.TranscriptionJob
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/transcription/youtube [post]
@@ -2687,7 +3236,7 @@ func (h *Handler) DownloadFromYouTube(c *gin.Context) {
 	} else {
 		// Get title from yt-dlp
 		titleStart := time.Now()
-		cmd := exec.Command(h.config.UVPath, "run", "--native-tls", "--project", h.config.WhisperXEnv, "python", "-m", "yt_dlp", "--get-title", req.URL)
+		cmd := h.ytDlpCommand(c.Request.Context(), "--get-title", req.URL)
 		titleBytes, err := cmd.Output()
 		if err != nil {
 			title = "YouTube Audio"
@@ -2702,7 +3251,7 @@ func (h *Handler) DownloadFromYouTube(c *gin.Context) {
 	logger.Info("Starting YouTube download", "url", req.URL, "job_id", jobID)
 	downloadStart := time.Now()
 
-	ytDlpCmd := exec.Command(h.config.UVPath, "run", "--native-tls", "--project", h.config.WhisperXEnv, "python", "-m", "yt_dlp",
+	ytDlpCmd := h.ytDlpCommand(c.Request.Context(),
 		"--extract-audio",
 		"--audio-format", "mp3",
 		"--audio-quality", "0", // best quality
@@ -2756,8 +3305,10 @@ func (h *Handler) DownloadFromYouTube(c *gin.Context) {
 	// Create transcription record
 	job := models.TranscriptionJob{
 		ID:        jobID,
+		UserID:    currentUserID(c),
 		AudioPath: actualFilePath,
 		Status:    models.StatusUploaded,
+		Source:    &models.MediaSource{URL: req.URL},
 	}
 
 	// Set title
@@ -2890,6 +3441,7 @@ func (h *Handler) SetUserDefaultProfile(c *gin.Context) {
 type UserSettingsResponse struct {
 	AutoTranscriptionEnabled bool    `json:"auto_transcription_enabled"`
 	DefaultProfileID         *string `json:"default_profile_id,omitempty"`
+	DefaultSummaryTemplateID *string `json:"default_summary_template_id,omitempty"`
 }
 
 // UpdateUserSettingsRequest represents the request to update user settings
@@ -2922,6 +3474,7 @@ func (h *Handler) GetUserSettings(c *gin.Context) {
 	response := UserSettingsResponse{
 		AutoTranscriptionEnabled: user.AutoTranscriptionEnabled,
 		DefaultProfileID:         user.DefaultProfileID,
+		DefaultSummaryTemplateID: user.DefaultSummaryTemplateID,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -2972,6 +3525,7 @@ func (h *Handler) UpdateUserSettings(c *gin.Context) {
 	response := UserSettingsResponse{
 		AutoTranscriptionEnabled: user.AutoTranscriptionEnabled,
 		DefaultProfileID:         user.DefaultProfileID,
+		DefaultSummaryTemplateID: user.DefaultSummaryTemplateID,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -3137,6 +3691,7 @@ func (h *Handler) UpdateSpeakerMappings(c *gin.Context) {
 	}
 
 	tx.Commit()
+	h.refreshRAGMetadata(jobID)
 
 	// Convert to response format
 	response := make([]SpeakerMappingResponse, len(updatedMappings))
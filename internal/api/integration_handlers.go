@@ -0,0 +1,280 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/integrations"
+	"scriberr/internal/models"
+	"scriberr/internal/workflow"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TaskIntegrationRequest represents a request to create or update a task integration
+type TaskIntegrationRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Provider string `json:"provider" binding:"required,oneof=todoist linear webhook"`
+	// Token is the Todoist API token, the Linear API key or the webhook signing secret; leave it
+	// out of an update to keep the saved one
+	Token       *string           `json:"token,omitempty"`
+	Target      string            `json:"target"` // Todoist project ID (empty for the Inbox) or Linear team ID
+	WebhookURL  string            `json:"webhook_url"`
+	Assignees   map[string]string `json:"assignees"` // Owner name as said in recordings → task manager user ID
+	AutoDeliver bool              `json:"auto_deliver"`
+}
+
+// TaskIntegrationResponse is a task integration without its token
+type TaskIntegrationResponse struct {
+	models.TaskIntegration
+	HasToken bool `json:"has_token"`
+}
+
+// DeliverActionItemsRequest represents a request to deliver a transcription's action items
+type DeliverActionItemsRequest struct {
+	IntegrationID string `json:"integration_id" binding:"required"`
+}
+
+func integrationResponse(integration models.TaskIntegration) TaskIntegrationResponse {
+	return TaskIntegrationResponse{TaskIntegration: integration, HasToken: integration.Token != ""}
+}
+
+// loadIntegration loads a task integration owned by the caller, writing an error response if it can't
+func loadIntegration(c *gin.Context, integrationID string) (*models.TaskIntegration, bool) {
+	var integration models.TaskIntegration
+	if err := scopeToOwner(database.DB, currentUserID(c)).Where("id = ?", integrationID).First(&integration).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Integration not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get integration"})
+		}
+		return nil, false
+	}
+	return &integration, true
+}
+
+// applyIntegrationRequest parses a task integration request into integration and validates the
+// result, writing an error response if it is invalid
+func applyIntegrationRequest(c *gin.Context, integration *models.TaskIntegration) bool {
+	var req TaskIntegrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return false
+	}
+	req.WebhookURL = strings.TrimSpace(req.WebhookURL)
+	if req.WebhookURL != "" {
+		u, err := url.Parse(req.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "webhook_url must be an http or https URL"})
+			return false
+		}
+	}
+	assignees := make(map[string]string, len(req.Assignees))
+	for owner, account := range req.Assignees {
+		owner, account = strings.Join(strings.Fields(owner), " "), strings.TrimSpace(account)
+		if owner != "" && account != "" {
+			assignees[owner] = account
+		}
+	}
+
+	integration.Name = req.Name
+	integration.Provider = req.Provider
+	if req.Token != nil {
+		integration.Token = strings.TrimSpace(*req.Token)
+	}
+	integration.Target = strings.TrimSpace(req.Target)
+	integration.WebhookURL = req.WebhookURL
+	integration.Assignees = assignees
+	integration.AutoDeliver = req.AutoDeliver
+	// Only the settings are checked here; nothing is sent until delivery
+	if _, err := integrations.New(integration.Provider, integration.Token, integration.Target, integration.WebhookURL, false); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+// ListTaskIntegrations returns the caller's task integrations
+// @Summary List task integrations
+// @Description List the task managers the caller delivers action items to. Tokens are not returned.
+// @Tags integrations
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/integrations [get]
+func (h *Handler) ListTaskIntegrations(c *gin.Context) {
+	var saved []models.TaskIntegration
+	if err := scopeToOwner(database.DB, currentUserID(c)).Order("name ASC").Find(&saved).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list integrations"})
+		return
+	}
+	result := make([]TaskIntegrationResponse, len(saved))
+	for i, integration := range saved {
+		result[i] = integrationResponse(integration)
+	}
+	c.JSON(http.StatusOK, gin.H{"integrations": result})
+}
+
+// CreateTaskIntegration saves a new task integration
+// @Summary Create a task integration
+// @Description Connect Todoist (API token, optional project ID as target), Linear (API key and team ID as target) or a generic webhook (URL, optional signing secret as token). Assignees maps action item owners, as named in recordings, to user IDs in the task manager. With auto_deliver, the deliver_action_items workflow step delivers the action items of new transcriptions.
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Param request body TaskIntegrationRequest true "Integration"
+// @Success 201 {object} TaskIntegrationResponse
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/integrations [post]
+func (h *Handler) CreateTaskIntegration(c *gin.Context) {
+	integration := models.TaskIntegration{UserID: currentUserID(c)}
+	if !applyIntegrationRequest(c, &integration) {
+		return
+	}
+	if err := database.DB.Create(&integration).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create integration"})
+		return
+	}
+	c.JSON(http.StatusCreated, integrationResponse(integration))
+}
+
+// UpdateTaskIntegration changes a task integration
+// @Summary Update a task integration
+// @Description Replace a task integration's settings. The saved token is kept when token is left out.
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Param id path string true "Integration ID"
+// @Param request body TaskIntegrationRequest true "Integration"
+// @Success 200 {object} TaskIntegrationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/integrations/{id} [put]
+func (h *Handler) UpdateTaskIntegration(c *gin.Context) {
+	integration, ok := loadIntegration(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	if !applyIntegrationRequest(c, integration) {
+		return
+	}
+	if err := database.DB.Save(integration).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update integration"})
+		return
+	}
+	c.JSON(http.StatusOK, integrationResponse(*integration))
+}
+
+// DeleteTaskIntegration deletes a task integration and its delivery records; tasks already
+// created in the task manager are not affected
+// @Summary Delete a task integration
+// @Tags integrations
+// @Param id path string true "Integration ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/integrations/{id} [delete]
+func (h *Handler) DeleteTaskIntegration(c *gin.Context) {
+	integration, ok := loadIntegration(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("integration_id = ?", integration.ID).Delete(&models.ActionItemDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(integration).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete integration"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Integration deleted"})
+}
+
+// DeliverTranscriptionActionItems delivers a transcription's action items to a task integration
+// @Summary Deliver action items to a task manager
+// @Description Create a task for each open action item of the transcription in one of the caller's task integrations, linked back to the recording and the moment the item was mentioned. Items already delivered to it are skipped and failed ones are retried; each delivery carries its own status.
+// @Tags action-items
+// @Accept json
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param request body DeliverActionItemsRequest true "Integration to deliver to"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/action-items/deliver [post]
+func (h *Handler) DeliverTranscriptionActionItems(c *gin.Context) {
+	var req DeliverActionItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	integration, ok := loadIntegration(c, req.IntegrationID)
+	if !ok {
+		return
+	}
+
+	var publicURL string
+	var allowPrivate bool
+	if h.config != nil {
+		publicURL, allowPrivate = h.config.PublicURL, h.config.WebhookAllowPrivate
+	}
+	deliveries, err := workflow.DeliverActionItems(c.Request.Context(), publicURL, allowPrivate, integration, job)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "Failed to deliver action items", "job_id", job.ID, "integration_id", integration.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to deliver action items"})
+		return
+	}
+	if deliveries == nil {
+		deliveries = []models.ActionItemDelivery{}
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+// ListActionItemDeliveries returns where a transcription's action items were delivered
+// @Summary List a transcription's action item deliveries
+// @Description List the tasks created from the transcription's action items in task managers, and the deliveries that failed
+// @Tags action-items
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Success 200 {array} models.ActionItemDelivery
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/action-items/deliveries [get]
+func (h *Handler) ListActionItemDeliveries(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	deliveries := []models.ActionItemDelivery{}
+	if err := database.DB.Where("transcription_id = ?", job.ID).Order("created_at ASC").Find(&deliveries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list deliveries"})
+		return
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"os"
+
+	"scriberr/internal/database"
+	"scriberr/internal/events"
+	"scriberr/internal/models"
+	"scriberr/internal/versions"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// loadJob fetches the job in the id path parameter, writing a 404 or 500 response if that fails
+func loadJob(c *gin.Context) (*models.TranscriptionJob, bool) {
+	var job models.TranscriptionJob
+	if err := database.DB.Where("id = ?", c.Param("id")).First(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transcription not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transcription"})
+		return nil, false
+	}
+	return &job, true
+}
+
+// DeleteJobSummary removes a transcription's summaries and keeps everything else
+// @Summary Delete a transcription's summary
+// @Description Delete every stored summary of a transcription, keeping the transcript, audio and other data. If the transcription is in the RAG index, it is re-indexed without the summary.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/summary [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteJobSummary(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	if rejectIfOnHold(c, job) {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// The deleted summary stays restorable from the job's versions
+		if err := versions.Baseline(tx, job.ID, models.VersionKindSummary); err != nil {
+			return err
+		}
+		if err := tx.Where("transcription_id = ?", job.ID).Delete(&models.Summary{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(job).Updates(map[string]interface{}{"summary": nil, "structured_summary": nil, "summary_model": nil}).Error; err != nil {
+			return err
+		}
+		return events.Append(tx, models.EventSummaryDeleted, job.ID, job.UserID, nil)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete summary"})
+		return
+	}
+	job.Summary = nil
+	job.StructuredSummary = nil
+
+	// The summary is embedded in the transcription's RAG entry, so replace that entry too
+	reindexed := false
+	if h.ragService != nil && job.Transcript != nil {
+		indexed, err := h.ragService.IsIndexed(job.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Summary deleted, but failed to check the RAG index: " + err.Error()})
+			return
+		}
+		if indexed {
+			if err := h.storeJobInRAG(job); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Summary deleted, but failed to re-index without it: " + err.Error()})
+				return
+			}
+			reindexed = true
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Summary deleted", "reindexed": reindexed})
+}
+
+// DeleteJobRAGData removes a transcription from the RAG index and keeps everything else
+// @Summary Delete a transcription's RAG data
+// @Description Remove a transcription's summary entry and transcript chunks from the vector store, keeping the transcription itself. Uploaded documents linked to the recording are kept. A later re-index or backfill adds the transcription again.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/rag [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteJobRAGData(c *gin.Context) {
+	if h.ragService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "RAG service not initialized"})
+		return
+	}
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	if rejectIfOnHold(c, job) {
+		return
+	}
+
+	if err := h.ragService.DeleteTranscription(c.Request.Context(), job.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "RAG data deleted"})
+}
+
+// DeleteJobAudio removes a transcription's audio files and keeps everything else
+// @Summary Delete a transcription's audio
+// @Description Delete the audio files of a transcription (including multi-track files and merged audio), keeping the transcript, summary and other data. The transcription can't be re-run afterwards.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/audio [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) DeleteJobAudio(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	if rejectIfOnHold(c, job) {
+		return
+	}
+	if job.Status != models.StatusCompleted && job.Status != models.StatusFailed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cannot delete audio of a job that hasn't finished transcribing"})
+		return
+	}
+
+	if err := h.deleteJobAudio(job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Audio deleted"})
+}
+
+// deleteJobAudio removes a transcription's audio files, multi-track folder and stored copies
+// included, and clears its audio paths
+func (h *Handler) deleteJobAudio(job *models.TranscriptionJob) error {
+	paths := []string{job.AudioPath}
+	if job.MergedAudioPath != nil {
+		paths = append(paths, *job.MergedAudioPath)
+	}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			logger.Error("Failed to delete audio file", "job_id", job.ID, "path", path, "error", err)
+			return errors.New("Failed to delete audio file")
+		}
+		if err := h.removeStoredFile(path); err != nil {
+			logger.Error("Failed to delete stored audio file", "job_id", job.ID, "path", path, "error", err)
+			return errors.New("Failed to delete audio file")
+		}
+	}
+	if job.IsMultiTrack && job.MultiTrackFolder != nil && *job.MultiTrackFolder != "" {
+		if err := os.RemoveAll(*job.MultiTrackFolder); err != nil {
+			logger.Error("Failed to delete multi-track folder", "job_id", job.ID, "path", *job.MultiTrackFolder, "error", err)
+			return errors.New("Failed to delete multi-track audio")
+		}
+	}
+
+	if err := database.DB.Model(job).Updates(map[string]interface{}{"audio_path": "", "merged_audio_path": nil}).Error; err != nil {
+		return errors.New("Failed to update transcription")
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/joblog"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetJobLogStore sets the store keeping each job's processing log (nil if logs aren't kept)
+func (h *Handler) SetJobLogStore(store *joblog.Store) {
+	h.jobLogs = store
+}
+
+// GetJobLogs returns a transcription's processing log
+// @Summary Get a transcription's processing log
+// @Description Get the lines logged while processing a transcription, oldest first: queueing, each transcription stage, post-processing steps and the requests that changed it, each with its level, component and request ID. The newest JOB_LOG_MAX_ENTRIES lines of each job are kept. Pass the last line's id as after_id to follow the log as it grows.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param level query string false "Least severe level returned: debug, info, warn or error"
+// @Param component query string false "Only lines from this component, e.g. workflow"
+// @Param after_id query int false "Only lines after this one"
+// @Param limit query int false "Most lines returned" default(500)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/logs [get]
+func (h *Handler) GetJobLogs(c *gin.Context) {
+	jobID := c.Param("id")
+	var job models.TranscriptionJob
+	if err := database.DB.Select("id").Where("id = ?", jobID).First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		return
+	}
+
+	query := joblog.Query{Level: c.Query("level"), Component: c.Query("component")}
+	if query.Level != "" && !joblog.ValidLevel(query.Level) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid level; use debug, info, warn or error"})
+		return
+	}
+	if raw := c.Query("after_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid after_id"})
+			return
+		}
+		query.AfterID = uint(id)
+	}
+	query.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "500"))
+	if query.Limit < 1 || query.Limit > 5000 {
+		query.Limit = 500
+	}
+
+	// Lines logged moments ago may still be waiting to be written
+	if h.jobLogs != nil {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+		h.jobLogs.Flush(ctx)
+		cancel()
+	}
+	entries, err := joblog.List(jobID, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job log"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"job_id": jobID, "entries": entries})
+}
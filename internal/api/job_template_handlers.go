@@ -0,0 +1,285 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/tagging"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxTemplateWebhooks is the most webhooks a job template can notify
+const maxTemplateWebhooks = 10
+
+// jobTemplateModelFamilies are the transcription engines a job template can pick
+var jobTemplateModelFamilies = map[string]bool{
+	"whisper":         true,
+	"nvidia_parakeet": true,
+	"nvidia_canary":   true,
+}
+
+// JobTemplateRequest represents a request to create or update a job template
+type JobTemplateRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Description *string  `json:"description,omitempty"`
+	ProfileID   *string  `json:"profile_id,omitempty"`   // Defaults to the default profile
+	ModelFamily *string  `json:"model_family,omitempty"` // whisper, nvidia_parakeet or nvidia_canary
+	Model       *string  `json:"model,omitempty"`
+	Diarize     *bool    `json:"diarize,omitempty"`
+	Tags        []string `json:"tags"`
+	WebhookURLs []string `json:"webhook_urls"`
+}
+
+// loadJobTemplate loads a job template owned by the caller, writing an error response if it can't
+func loadJobTemplate(c *gin.Context) (*models.JobTemplate, bool) {
+	var template models.JobTemplate
+	if err := scopeToOwner(database.DB, currentUserID(c)).Where("id = ?", c.Param("id")).First(&template).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job template not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job template"})
+		}
+		return nil, false
+	}
+	return &template, true
+}
+
+// jobTemplateFromForm loads the caller's job template named by the template form field, if
+// any, writing an error response if there is no such template or its profile was deleted
+func jobTemplateFromForm(c *gin.Context) (*models.JobTemplate, bool) {
+	name := strings.TrimSpace(c.PostForm("template"))
+	if name == "" {
+		return nil, true
+	}
+	var template models.JobTemplate
+	err := scopeToOwner(database.DB, currentUserID(c)).Where("LOWER(name) = LOWER(?)", name).First(&template).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Job template %q not found", name)})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job template"})
+		}
+		return nil, false
+	}
+	if template.ProfileID != nil {
+		if err := database.DB.Where("id = ?", *template.ProfileID).First(&models.TranscriptionProfile{}).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("The profile of job template %q no longer exists", template.Name)})
+			return nil, false
+		}
+	}
+	return &template, true
+}
+
+// bindJobTemplateRequest parses and validates a job template request, writing an error
+// response if it is invalid. excludeID is the template being updated, whose name may stay.
+func bindJobTemplateRequest(c *gin.Context, excludeID string) (*JobTemplateRequest, bool) {
+	var req JobTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return nil, false
+	}
+	var count int64
+	if err := scopeToOwner(database.DB.Model(&models.JobTemplate{}), currentUserID(c)).
+		Where("LOWER(name) = LOWER(?) AND id != ?", req.Name, excludeID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check job template name"})
+		return nil, false
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("A job template named %q already exists", req.Name)})
+		return nil, false
+	}
+
+	if req.ProfileID != nil && *req.ProfileID != "" {
+		if err := database.DB.Where("id = ?", *req.ProfileID).First(&models.TranscriptionProfile{}).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Profile not found"})
+			return nil, false
+		}
+	} else {
+		req.ProfileID = nil
+	}
+	if req.ModelFamily != nil && !jobTemplateModelFamilies[*req.ModelFamily] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "model_family must be whisper, nvidia_parakeet or nvidia_canary"})
+		return nil, false
+	}
+	if req.Model != nil && strings.TrimSpace(*req.Model) == "" {
+		req.Model = nil
+	}
+
+	req.Tags = tagging.Normalize(req.Tags)
+	if req.Tags == nil {
+		req.Tags = []string{}
+	}
+	webhooks, ok := webhookURLs(c, req.WebhookURLs)
+	if !ok {
+		return nil, false
+	}
+	req.WebhookURLs = webhooks
+	return &req, true
+}
+
+// webhookURLs trims a list of webhooks and drops the empty ones, writing an error response
+// if one isn't an http or https URL or there are more than maxTemplateWebhooks
+func webhookURLs(c *gin.Context, urls []string) ([]string, bool) {
+	webhooks := []string{}
+	for _, webhook := range urls {
+		webhook = strings.TrimSpace(webhook)
+		if webhook == "" {
+			continue
+		}
+		u, err := url.Parse(webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "webhook_urls must be http or https URLs"})
+			return nil, false
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if len(webhooks) > maxTemplateWebhooks {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d webhook_urls are allowed", maxTemplateWebhooks)})
+		return nil, false
+	}
+	return webhooks, true
+}
+
+// apply copies the request's settings onto a template
+func (req *JobTemplateRequest) apply(template *models.JobTemplate) {
+	template.Name = req.Name
+	template.Description = req.Description
+	template.ProfileID = req.ProfileID
+	template.ModelFamily = req.ModelFamily
+	template.Model = req.Model
+	template.Diarize = req.Diarize
+	template.Tags = req.Tags
+	template.WebhookURLs = req.WebhookURLs
+}
+
+// ListJobTemplates returns the caller's job templates
+// @Summary List job templates
+// @Description List the caller's job templates, which uploads pick by name with the template field
+// @Tags job-templates
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/job-templates [get]
+func (h *Handler) ListJobTemplates(c *gin.Context) {
+	templates := []models.JobTemplate{}
+	if err := scopeToOwner(database.DB, currentUserID(c)).Order("name ASC").Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list job templates"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+// CreateJobTemplate saves a new job template
+// @Summary Create a job template
+// @Description Save a named set of transcription settings: the profile jobs start from (the default profile when unset), the engine, model and diarization to use instead of the profile's, tags to add and webhooks to notify when post-processing completes. Uploads to /transcription/upload pick it by name with the template field.
+// @Tags job-templates
+// @Accept json
+// @Produce json
+// @Param request body JobTemplateRequest true "Job template"
+// @Success 201 {object} models.JobTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/job-templates [post]
+func (h *Handler) CreateJobTemplate(c *gin.Context) {
+	req, ok := bindJobTemplateRequest(c, "")
+	if !ok {
+		return
+	}
+
+	template := models.JobTemplate{UserID: currentUserID(c)}
+	req.apply(&template)
+	if err := database.DB.Create(&template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job template"})
+		return
+	}
+	c.JSON(http.StatusCreated, template)
+}
+
+// GetJobTemplate returns one of the caller's job templates
+// @Summary Get a job template
+// @Tags job-templates
+// @Produce json
+// @Param id path string true "Job template ID"
+// @Success 200 {object} models.JobTemplate
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/job-templates/{id} [get]
+func (h *Handler) GetJobTemplate(c *gin.Context) {
+	template, ok := loadJobTemplate(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// UpdateJobTemplate replaces a job template's settings
+// @Summary Update a job template
+// @Description Replace a job template's settings. Jobs already uploaded with it keep the settings they got.
+// @Tags job-templates
+// @Accept json
+// @Produce json
+// @Param id path string true "Job template ID"
+// @Param request body JobTemplateRequest true "Job template"
+// @Success 200 {object} models.JobTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/job-templates/{id} [put]
+func (h *Handler) UpdateJobTemplate(c *gin.Context) {
+	template, ok := loadJobTemplate(c)
+	if !ok {
+		return
+	}
+	req, ok := bindJobTemplateRequest(c, template.ID)
+	if !ok {
+		return
+	}
+
+	req.apply(template)
+	if err := database.DB.Save(template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job template"})
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteJobTemplate deletes a job template
+// @Summary Delete a job template
+// @Description Delete a job template. Jobs already uploaded with it are left as they are.
+// @Tags job-templates
+// @Param id path string true "Job template ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/job-templates/{id} [delete]
+func (h *Handler) DeleteJobTemplate(c *gin.Context) {
+	template, ok := loadJobTemplate(c)
+	if !ok {
+		return
+	}
+	if err := database.DB.Delete(template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete job template"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Job template deleted"})
+}
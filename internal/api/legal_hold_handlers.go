@@ -0,0 +1,192 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/events"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// LegalHoldRequest places or releases a legal hold
+type LegalHoldRequest struct {
+	Hold   *bool  `json:"hold" binding:"required"`
+	Reason string `json:"reason"` // Required when placing a hold, e.g. a case reference
+}
+
+// LegalHoldResponse is a transcription's hold state and every change made to it
+type LegalHoldResponse struct {
+	TranscriptionID string         `json:"transcription_id"`
+	LegalHold       bool           `json:"legal_hold"`
+	Reason          *string        `json:"reason,omitempty"`
+	PlacedBy        *uint          `json:"placed_by,omitempty"`
+	PlacedAt        *time.Time     `json:"placed_at,omitempty"`
+	History         []models.Event `json:"history"`
+}
+
+// rejectIfOnHold writes a 409 response and returns true if a job is under legal hold
+func rejectIfOnHold(c *gin.Context, job *models.TranscriptionJob) bool {
+	if !job.LegalHold {
+		return false
+	}
+	c.JSON(http.StatusConflict, gin.H{"error": "Transcription is under legal hold"})
+	return true
+}
+
+// legalHoldResponse describes a job's hold along with the recorded hold changes, oldest first
+func legalHoldResponse(job *models.TranscriptionJob) (LegalHoldResponse, error) {
+	response := LegalHoldResponse{
+		TranscriptionID: job.ID,
+		LegalHold:       job.LegalHold,
+		Reason:          job.LegalHoldReason,
+		PlacedBy:        job.LegalHoldBy,
+		PlacedAt:        job.LegalHoldAt,
+		History:         []models.Event{},
+	}
+	err := database.DB.Where("subject_id = ? AND type IN ?", job.ID, []string{models.EventLegalHoldPlaced, models.EventLegalHoldReleased}).
+		Order("sequence ASC").Find(&response.History).Error
+	return response, err
+}
+
+// GetLegalHold returns a transcription's legal hold and its history
+// @Summary Get a transcription's legal hold
+// @Description Get whether a transcription is under legal hold, and every time a hold was placed or released on it
+// @Tags admin
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Success 200 {object} LegalHoldResponse
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/transcription/{id}/legal-hold [get]
+func (h *Handler) GetLegalHold(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	response, err := legalHoldResponse(job)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load legal hold history"})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// SetLegalHold places or releases a legal hold on a transcription
+// @Summary Place or release a legal hold
+// @Description Place a legal hold on a transcription, which blocks deleting it or any of its data until the hold is released, or release it. Every change is recorded as a legal_hold.placed or legal_hold.released event.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param request body LegalHoldRequest true "Hold state"
+// @Success 200 {object} LegalHoldResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/transcription/{id}/legal-hold [put]
+func (h *Handler) SetLegalHold(c *gin.Context) {
+	var req LegalHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if *req.Hold && reason == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required to place a legal hold"})
+		return
+	}
+
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+
+	userID := currentUserID(c)
+	var eventType string
+	updates := map[string]interface{}{"legal_hold": *req.Hold}
+	if *req.Hold {
+		now := time.Now()
+		updates["legal_hold_reason"] = reason
+		updates["legal_hold_by"] = userID
+		updates["legal_hold_at"] = now
+		job.LegalHoldReason, job.LegalHoldBy, job.LegalHoldAt = &reason, userID, &now
+		eventType = models.EventLegalHoldPlaced
+	} else {
+		if !job.LegalHold {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Transcription is not under legal hold"})
+			return
+		}
+		updates["legal_hold_reason"] = nil
+		updates["legal_hold_by"] = nil
+		updates["legal_hold_at"] = nil
+		job.LegalHoldReason, job.LegalHoldBy, job.LegalHoldAt = nil, nil, nil
+		eventType = models.EventLegalHoldReleased
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.TranscriptionJob{}).Where("id = ?", job.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		// The event is the audit record, so it is written with the change rather than best-effort
+		data := map[string]interface{}{"changed_by": userID}
+		if reason != "" {
+			data["reason"] = reason
+		}
+		return events.Append(tx, eventType, job.ID, job.UserID, data)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update legal hold"})
+		return
+	}
+	job.LegalHold = *req.Hold
+
+	response, err := legalHoldResponse(job)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load legal hold history"})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ListLegalHolds returns the transcriptions under legal hold
+// @Summary List legal holds
+// @Description List every transcription under legal hold, most recently placed first
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/legal-holds [get]
+func (h *Handler) ListLegalHolds(c *gin.Context) {
+	var jobs []models.TranscriptionJob
+	if err := database.DB.Select("id", "user_id", "title", "status", "created_at", "legal_hold", "legal_hold_reason", "legal_hold_by", "legal_hold_at").
+		Where("legal_hold = ?", true).Order("legal_hold_at DESC").Find(&jobs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list legal holds"})
+		return
+	}
+
+	holds := make([]gin.H, len(jobs))
+	for i, job := range jobs {
+		holds[i] = gin.H{
+			"transcription_id": job.ID,
+			"user_id":          job.UserID,
+			"title":            job.Title,
+			"status":           job.Status,
+			"created_at":       job.CreatedAt,
+			"reason":           job.LegalHoldReason,
+			"placed_by":        job.LegalHoldBy,
+			"placed_at":        job.LegalHoldAt,
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"legal_holds": holds})
+}
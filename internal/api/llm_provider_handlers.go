@@ -0,0 +1,136 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"scriberr/internal/llm"
+	"scriberr/internal/llmstats"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetLLMRegistry exposes the server-side LLM providers, fallback chains and provider health
+func (h *Handler) SetLLMRegistry(registry *llm.Registry) {
+	h.llmRegistry = registry
+}
+
+// observeLLM wraps an LLM service configured in the UI so its calls are recorded under
+// feature alongside the registry's, for metrics and usage
+func (h *Handler) observeLLM(feature, provider string, svc llm.Service) llm.Service {
+	if h.llmRegistry == nil {
+		return svc
+	}
+	return h.llmRegistry.Observe(feature, provider, svc)
+}
+
+// GetLLMProviders returns the configured providers, each feature's fallback chain and provider health
+// @Summary Get LLM providers and health
+// @Description List the LLM providers configured on the server, the ordered fallback chain used for summaries and for RAG chat, and the health of each provider that has been called. A provider is unhealthy after repeated consecutive failures and is tried after the healthy ones until retry_at.
+// @Tags llm
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/llm/providers [get]
+func (h *Handler) GetLLMProviders(c *gin.Context) {
+	if h.llmRegistry == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "LLM providers not initialized"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"providers": h.llmRegistry.Providers(),
+		"chains":    h.llmRegistry.Chains(),
+		"health":    h.llmRegistry.Health().Snapshot(),
+	})
+}
+
+// GetLLMMetrics aggregates latency, errors and token usage per LLM provider
+// @Summary Get LLM provider metrics
+// @Description Aggregate the recorded LLM and embedding calls (including failed attempts and fallbacks) per provider: call and error counts, error rate, share of successful traffic, calls served as a fallback, latency percentiles and token counts, plus a per-provider time series.
+// @Tags llm
+// @Produce json
+// @Param since query string false "Start of the window, as a duration before until (e.g. 24h) or an RFC 3339 time (default 24h)"
+// @Param until query string false "End of the window, RFC 3339 (default now)"
+// @Param bucket query string false "Time series bucket width, e.g. 15m or 1h (default depends on the window)"
+// @Param feature query string false "Only calls for this feature (summary, chat, summarize, transcript_chat or embedding)"
+// @Param provider query string false "Only calls to this provider"
+// @Success 200 {object} llmstats.Report
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/llm/metrics [get]
+func (h *Handler) GetLLMMetrics(c *gin.Context) {
+	since, until, ok := statsWindow(c, 24*time.Hour)
+	if !ok {
+		return
+	}
+
+	bucket := defaultMetricsBucket(until.Sub(since))
+	if raw := c.Query("bucket"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bucket must be a duration"})
+			return
+		}
+		bucket = parsed
+	}
+
+	report, err := llmstats.Summarize(llmstats.Query{
+		Since:    since,
+		Until:    until,
+		Bucket:   bucket,
+		Feature:  c.Query("feature"),
+		Provider: c.Query("provider"),
+	})
+	if err != nil {
+		if errors.Is(err, llmstats.ErrInvalidQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// statsWindow reads the since and until query parameters of the statistics endpoints,
+// defaulting to the window of length fallback ending now. It responds with 400 and
+// reports false when either is malformed.
+func statsWindow(c *gin.Context, fallback time.Duration) (time.Time, time.Time, bool) {
+	until := time.Now()
+	if raw := c.Query("until"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "until must be an RFC 3339 time"})
+			return time.Time{}, time.Time{}, false
+		}
+		until = parsed
+	}
+
+	since := until.Add(-fallback)
+	if raw := c.Query("since"); raw != "" {
+		if window, err := time.ParseDuration(raw); err == nil && window > 0 {
+			since = until.Add(-window)
+		} else if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+			since = parsed
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be a positive duration or an RFC 3339 time"})
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	return since, until, true
+}
+
+// defaultMetricsBucket picks a time series bucket width that gives at most about 100 points
+func defaultMetricsBucket(window time.Duration) time.Duration {
+	for _, bucket := range []time.Duration{5 * time.Minute, 15 * time.Minute, time.Hour, 6 * time.Hour, 24 * time.Hour} {
+		if window/bucket <= 100 {
+			return bucket
+		}
+	}
+	return 7 * 24 * time.Hour
+}
@@ -0,0 +1,454 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/export"
+	"scriberr/internal/mcp"
+	"scriberr/internal/models"
+	"scriberr/internal/quota"
+	"scriberr/internal/rag"
+	"scriberr/internal/search"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Search modes of the search_transcripts tool
+const (
+	MCPSearchSemantic = "semantic"
+	MCPSearchKeyword  = "keyword"
+)
+
+// maxMCPMessage caps the size of an MCP message
+const maxMCPMessage = 1 << 20
+
+// mcpInstructions tell the client's model what Scriberr's tools are for
+const mcpInstructions = "Scriberr holds the user's transcribed recordings: meetings, calls, voice memos and podcasts. " +
+	"Use search_transcripts or list_transcriptions to find recordings, get_transcript and get_summary to read them, " +
+	"and rag_query for an answer drawn from all of them with its sources."
+
+// MCPSearchResult is a transcript passage found by the search_transcripts tool
+type MCPSearchResult struct {
+	TranscriptionID string   `json:"transcription_id"`
+	Title           string   `json:"title"`
+	Start           *float64 `json:"start,omitempty"` // Seconds into the recording
+	End             *float64 `json:"end,omitempty"`
+	Speaker         string   `json:"speaker,omitempty"`
+	Snippet         string   `json:"snippet"`
+}
+
+// MCPTranscription is a recording listed by the list_transcriptions tool
+type MCPTranscription struct {
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	CreatedAt  time.Time `json:"created_at"`
+	Tags       []string  `json:"tags,omitempty"`
+	HasSummary bool      `json:"has_summary"`
+}
+
+// MCPSource is a recording a rag_query answer drew on
+type MCPSource struct {
+	TranscriptionID string `json:"transcription_id"`
+	Title           string `json:"title"`
+}
+
+// MCP answers Model Context Protocol messages
+// @Summary Model Context Protocol endpoint
+// @Description Serve the caller's library to MCP clients such as Claude Desktop over the streamable HTTP transport, one JSON-RPC message per request. The tools are search_transcripts, list_transcriptions, get_transcript, get_summary and rag_query, and see only the caller's transcriptions. Notifications are answered with 202 and no body. API keys scoped to read can use every tool but rag_query, and keys scoped to rag_chat only search_transcripts and rag_query.
+// @Tags mcp
+// @Accept json
+// @Produce json
+// @Success 200 {object} mcp.Response
+// @Success 202
+// @Failure 400 {object} mcp.Response
+// @Router /mcp [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) MCP(c *gin.Context) {
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxMCPMessage+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, mcp.ErrorResponse(nil, mcp.CodeParseError, "Failed to read message"))
+		return
+	}
+	if len(data) > maxMCPMessage {
+		c.JSON(http.StatusRequestEntityTooLarge, mcp.ErrorResponse(nil, mcp.CodeInvalidRequest, "Message too large"))
+		return
+	}
+	req, errResp := mcp.ParseRequest(data)
+	if errResp != nil {
+		c.JSON(http.StatusBadRequest, errResp)
+		return
+	}
+
+	resp := mcp.NewServer("scriberr", "1.0.0", mcpInstructions, h.mcpTools(c)).Handle(c.Request.Context(), req)
+	if resp == nil {
+		c.Status(http.StatusAccepted)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// MCPStream refuses the server-to-client event stream of the streamable HTTP transport,
+// which clients may open but Scriberr has nothing to send on
+// @Summary Model Context Protocol event stream
+// @Description Always 405: Scriberr sends no messages of its own, so MCP clients only POST to /mcp.
+// @Tags mcp
+// @Failure 405
+// @Router /mcp [get]
+func (h *Handler) MCPStream(c *gin.Context) {
+	c.Header("Allow", http.MethodPost)
+	c.Status(http.StatusMethodNotAllowed)
+}
+
+// mcpTools are the tools of a caller's MCP requests, which act as that caller
+func (h *Handler) mcpTools(c *gin.Context) []mcp.Tool {
+	tools := []mcp.Tool{}
+	if apiKeyAllows(c, models.APIKeyScopeRead, models.APIKeyScopeRAGChat) {
+		tools = append(tools, mcp.Tool{
+			Name:        "search_transcripts",
+			Description: "Find passages of the user's recordings. Semantic search (the default when available) matches meaning; keyword search matches words, \"quoted phrases\", prefix* terms, OR and -excluded terms. Returns the recording, time and speaker of each passage.",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"query": map[string]interface{}{"type": "string", "description": "What to look for"},
+				"mode":  map[string]interface{}{"type": "string", "enum": []string{MCPSearchSemantic, MCPSearchKeyword}},
+				"limit": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": maxSearchResults, "default": 10},
+			}, "query"),
+			Call: func(ctx context.Context, args json.RawMessage) (*mcp.ToolResult, error) {
+				return h.mcpSearch(ctx, c, args)
+			},
+		})
+	}
+	if apiKeyAllows(c, models.APIKeyScopeRead) {
+		idSchema := mcp.ObjectSchema(map[string]interface{}{
+			"id": map[string]interface{}{"type": "string", "description": "Transcription ID"},
+		}, "id")
+		tools = append(tools,
+			mcp.Tool{
+				Name:        "list_transcriptions",
+				Description: "List the user's completed recordings, newest first, with their IDs, titles, dates and tags. Optionally only those whose title contains query.",
+				InputSchema: mcp.ObjectSchema(map[string]interface{}{
+					"query": map[string]interface{}{"type": "string", "description": "Text the title contains"},
+					"limit": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 100, "default": 20},
+				}),
+				Call: func(ctx context.Context, args json.RawMessage) (*mcp.ToolResult, error) {
+					return mcpListTranscriptions(c, args)
+				},
+			},
+			mcp.Tool{
+				Name:        "get_transcript",
+				Description: "Read the full transcript of a recording, with the time and speaker of each paragraph.",
+				InputSchema: idSchema,
+				Call: func(ctx context.Context, args json.RawMessage) (*mcp.ToolResult, error) {
+					return mcpGetTranscript(c, args)
+				},
+			},
+			mcp.Tool{
+				Name:        "get_summary",
+				Description: "Read the summary of a recording, in Markdown.",
+				InputSchema: idSchema,
+				Call: func(ctx context.Context, args json.RawMessage) (*mcp.ToolResult, error) {
+					return mcpGetSummary(c, args)
+				},
+			},
+		)
+	}
+	if h.ragService != nil && apiKeyAllows(c, models.APIKeyScopeRAGChat) {
+		tools = append(tools, mcp.Tool{
+			Name:        "rag_query",
+			Description: "Answer a question from the user's recordings, with the recordings the answer is based on. Says so when none of them are relevant.",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"query": map[string]interface{}{"type": "string", "description": "The question"},
+			}, "query"),
+			Call: func(ctx context.Context, args json.RawMessage) (*mcp.ToolResult, error) {
+				return h.mcpRAGQuery(ctx, c, args)
+			},
+		})
+	}
+	return tools
+}
+
+// apiKeyAllows reports whether the caller may do what any of scopes allow: callers without
+// a scoped API key may do everything
+func apiKeyAllows(c *gin.Context, scopes ...string) bool {
+	value, scoped := c.Get("api_key_scopes")
+	if !scoped {
+		return true
+	}
+	keyScopes, _ := value.([]string)
+	for _, keyScope := range keyScopes {
+		for _, scope := range scopes {
+			if keyScope == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mcpArgs decodes the arguments of a tool call
+func mcpArgs(args json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(args, v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+func (h *Handler) mcpSearch(ctx context.Context, c *gin.Context, args json.RawMessage) (*mcp.ToolResult, error) {
+	var params struct {
+		Query string `json:"query"`
+		Mode  string `json:"mode"`
+		Limit int    `json:"limit"`
+	}
+	if err := mcpArgs(args, &params); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(params.Query) == "" {
+		return nil, errors.New("query is required")
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+	if params.Limit < 1 || params.Limit > maxSearchResults {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxSearchResults)
+	}
+	if params.Mode == "" {
+		params.Mode = MCPSearchKeyword
+		if h.ragService != nil {
+			params.Mode = MCPSearchSemantic
+		}
+	}
+
+	results := []MCPSearchResult{}
+	switch params.Mode {
+	case MCPSearchSemantic:
+		if h.ragService == nil {
+			return nil, errors.New("semantic search isn't set up on this server; use keyword mode")
+		}
+		hits, err := h.ragService.Search(ctx, currentUserID(c), params.Query, params.Limit, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, hit := range hits {
+			start, end := hit.Start, hit.End
+			results = append(results, MCPSearchResult{
+				TranscriptionID: hit.TranscriptionID,
+				Start:           &start,
+				End:             &end,
+				Speaker:         hit.Speaker,
+				Snippet:         hit.Snippet,
+			})
+		}
+	case MCPSearchKeyword:
+		hits, _, err := search.Transcripts(currentUserID(c), search.Options{Query: params.Query, Limit: params.Limit})
+		if err != nil {
+			return nil, err
+		}
+		// Snippets are marked up for HTML, which is noise to a model
+		plain := strings.NewReplacer("<mark>", "", "</mark>", "")
+		for _, hit := range hits {
+			speaker := hit.Speaker
+			if hit.SpeakerName != "" {
+				speaker = hit.SpeakerName
+			}
+			results = append(results, MCPSearchResult{
+				TranscriptionID: hit.TranscriptionID,
+				Start:           hit.Start,
+				End:             hit.End,
+				Speaker:         speaker,
+				Snippet:         html.UnescapeString(plain.Replace(hit.Snippet)),
+			})
+		}
+	default:
+		return nil, errors.New("mode must be semantic or keyword")
+	}
+
+	ids := make([]string, len(results))
+	for i, result := range results {
+		ids[i] = result.TranscriptionID
+	}
+	titles, err := transcriptionTitles(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Title = titles[results[i].TranscriptionID]
+	}
+	return mcp.JSONResult(gin.H{"query": params.Query, "mode": params.Mode, "results": results})
+}
+
+func mcpListTranscriptions(c *gin.Context, args json.RawMessage) (*mcp.ToolResult, error) {
+	var params struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}
+	if err := mcpArgs(args, &params); err != nil {
+		return nil, err
+	}
+	if params.Limit == 0 {
+		params.Limit = 20
+	}
+	if params.Limit < 1 || params.Limit > 100 {
+		return nil, errors.New("limit must be between 1 and 100")
+	}
+
+	// Like the transcription list: admins also see transcriptions without an owner
+	query := database.DB.Where("id NOT LIKE 'track_%' AND status = ?", models.StatusCompleted)
+	if userID := currentUserID(c); !isAdmin(c) || userID == nil {
+		query = scopeToOwner(query, userID)
+	} else {
+		query = query.Where("(user_id = ? OR user_id IS NULL)", *userID)
+	}
+	if params.Query != "" {
+		query = query.Where("title LIKE ?", "%"+params.Query+"%")
+	}
+	var jobs []models.TranscriptionJob
+	if err := query.Order("created_at DESC").Limit(params.Limit).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	listed := make([]MCPTranscription, len(jobs))
+	for i := range jobs {
+		listed[i] = MCPTranscription{
+			ID:         jobs[i].ID,
+			Title:      exportTitle(&jobs[i]),
+			CreatedAt:  jobs[i].CreatedAt,
+			Tags:       jobs[i].Tags,
+			HasSummary: jobs[i].Summary != nil && *jobs[i].Summary != "",
+		}
+	}
+	return mcp.JSONResult(gin.H{"transcriptions": listed})
+}
+
+// mcpJob loads the transcription in a tool call's id argument, if the caller may see it
+func mcpJob(c *gin.Context, args json.RawMessage) (*models.TranscriptionJob, error) {
+	var params struct {
+		ID string `json:"id"`
+	}
+	if err := mcpArgs(args, &params); err != nil {
+		return nil, err
+	}
+	if params.ID == "" {
+		return nil, errors.New("id is required")
+	}
+	notFound := fmt.Errorf("transcription %s not found", params.ID)
+	var job models.TranscriptionJob
+	if err := database.DB.Where("id = ?", params.ID).First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, notFound
+		}
+		return nil, err
+	}
+	if !canAccessJob(c, job.UserID) {
+		return nil, notFound
+	}
+	setAuditTarget(c, job.ID)
+	return &job, nil
+}
+
+func mcpGetTranscript(c *gin.Context, args json.RawMessage) (*mcp.ToolResult, error) {
+	job, err := mcpJob(c, args)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != models.StatusCompleted {
+		return nil, fmt.Errorf("transcription isn't completed, current status: %s", job.Status)
+	}
+	segments := export.TranscriptSegments(job)
+	if len(segments) == 0 {
+		return nil, errors.New("transcript not available")
+	}
+	speakerNames, err := jobSpeakerNames(job.ID)
+	if err != nil {
+		return nil, err
+	}
+	document := export.Document{
+		Title:        exportTitle(job),
+		Date:         job.CreatedAt,
+		Sections:     []string{export.SectionTranscript},
+		Timestamps:   true,
+		Speakers:     true,
+		Segments:     segments,
+		SpeakerNames: speakerNames,
+	}
+	return mcp.TextResult(string(document.Markdown())), nil
+}
+
+func mcpGetSummary(c *gin.Context, args json.RawMessage) (*mcp.ToolResult, error) {
+	job, err := mcpJob(c, args)
+	if err != nil {
+		return nil, err
+	}
+	// As for the summary endpoint: a structured summary, then the latest saved one, then the job's
+	if job.StructuredSummary != nil {
+		return mcp.TextResult(job.StructuredSummary.Markdown()), nil
+	}
+	var summaries []models.Summary
+	if err := database.DB.Where("transcription_id = ?", job.ID).Order("created_at DESC").Limit(1).Find(&summaries).Error; err != nil {
+		return nil, err
+	}
+	if len(summaries) > 0 {
+		return mcp.TextResult(summaries[0].Content), nil
+	}
+	if job.Summary != nil && *job.Summary != "" {
+		return mcp.TextResult(*job.Summary), nil
+	}
+	return nil, errors.New("this transcription has no summary")
+}
+
+func (h *Handler) mcpRAGQuery(ctx context.Context, c *gin.Context, args json.RawMessage) (*mcp.ToolResult, error) {
+	var params struct {
+		Query string `json:"query"`
+	}
+	if err := mcpArgs(args, &params); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(params.Query) == "" {
+		return nil, errors.New("query is required")
+	}
+
+	// Each answer counts against the LLM request quota, like a RAG chat
+	user, err := limitedUser(currentUserID(c))
+	if err != nil {
+		return nil, err
+	}
+	if user != nil {
+		if err := h.quotas.Check(user, models.QuotaLLMCalls); err != nil {
+			return nil, err
+		}
+	}
+	result, err := h.ragService.Chat(ctx, currentUserID(c), params.Query, h.config.ChatLLMModel, 0.7, rag.ChatOptions{
+		Mode:          rag.ModeAbstractive,
+		CrossLanguage: h.config.RAGCrossLanguage,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if user != nil {
+		if err := quota.Add(user.ID, models.QuotaLLMCalls, 1); err != nil {
+			return nil, fmt.Errorf("answered, but failed to count the answer against the quota: %w", err)
+		}
+	}
+
+	titles, err := transcriptionTitles(result.Sources)
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]MCPSource, len(result.Sources))
+	for i, id := range result.Sources {
+		sources[i] = MCPSource{TranscriptionID: id, Title: titles[id]}
+	}
+	return mcp.JSONResult(gin.H{
+		"answer":              result.Answer,
+		"sources":             sources,
+		"no_relevant_context": result.NoRelevantContext,
+	})
+}
@@ -0,0 +1,290 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/netguard"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mediaImportHeartbeat is how often a running yt-dlp import is reported alive, so converting
+// a long recording after the download isn't taken for a stalled import
+const mediaImportHeartbeat = 30 * time.Second
+
+// ytDlpProgressPrefix starts the lines of download progress yt-dlp is asked to print
+const ytDlpProgressPrefix = "scriberr-progress"
+
+// ytDlpInfo is the part of yt-dlp's --dump-single-json output that is read
+type ytDlpInfo struct {
+	ID             string  `json:"id"`
+	Title          string  `json:"title"`
+	Channel        string  `json:"channel"`
+	ChannelURL     string  `json:"channel_url"`
+	Uploader       string  `json:"uploader"`
+	UploaderURL    string  `json:"uploader_url"`
+	UploadDate     string  `json:"upload_date"` // YYYYMMDD
+	Duration       float64 `json:"duration"`
+	WebpageURL     string  `json:"webpage_url"`
+	ExtractorKey   string  `json:"extractor_key"`
+	IsLive         bool    `json:"is_live"`
+	Filesize       int64   `json:"filesize"`
+	FilesizeApprox int64   `json:"filesize_approx"`
+}
+
+// source returns the media source recorded on the import and its transcription
+func (info ytDlpInfo) source(pageURL string) *models.MediaSource {
+	source := &models.MediaSource{
+		URL:             pageURL,
+		Site:            info.ExtractorKey,
+		MediaID:         info.ID,
+		Title:           info.Title,
+		Channel:         info.Channel,
+		ChannelURL:      info.ChannelURL,
+		Uploader:        info.Uploader,
+		DurationSeconds: info.Duration,
+	}
+	if info.WebpageURL != "" {
+		source.URL = info.WebpageURL
+	}
+	if source.Channel == "" {
+		source.Channel = info.Uploader
+	}
+	if source.ChannelURL == "" {
+		source.ChannelURL = info.UploaderURL
+	}
+	if date, err := time.Parse("20060102", info.UploadDate); err == nil {
+		source.UploadDate = date.Format("2006-01-02")
+	}
+	return source
+}
+
+// ytDlpCommand runs yt-dlp: the YTDLP_PATH binary if set, otherwise the module installed in
+// the WhisperX environment
+func (h *Handler) ytDlpCommand(ctx context.Context, args ...string) *exec.Cmd {
+	if h.config.YtDlpPath != "" {
+		return exec.CommandContext(ctx, h.config.YtDlpPath, args...)
+	}
+	uvArgs := append([]string{"run", "--native-tls", "--project", h.config.WhisperXEnv, "python", "-m", "yt_dlp"}, args...)
+	return exec.CommandContext(ctx, h.config.UVPath, uvArgs...)
+}
+
+// checkPublicHost returns netguard.ErrPrivateAddress if a host resolves to a loopback, private or
+// link-local address. yt-dlp makes its own connections, so the page's host is checked up front.
+func checkPublicHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if netguard.IsPrivateIP(addr.IP) {
+			return netguard.ErrPrivateAddress
+		}
+	}
+	return nil
+}
+
+// ytDlpError picks the line of yt-dlp's stderr that explains a failure
+func ytDlpError(stderr string, err error) error {
+	lines := strings.Split(strings.TrimSpace(stderr), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); strings.HasPrefix(line, "ERROR:") {
+			return fmt.Errorf("yt-dlp failed: %s", strings.TrimSpace(strings.TrimPrefix(line, "ERROR:")))
+		}
+	}
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return fmt.Errorf("yt-dlp failed: %s", last)
+	}
+	return fmt.Errorf("yt-dlp failed: %w", err)
+}
+
+// ImportFromMedia extracts the audio of a video or track page and queues it for transcription
+// @Summary Transcribe a YouTube video or other media page
+// @Description Extract the audio of a page yt-dlp supports, such as a YouTube, Vimeo or SoundCloud URL, and queue it like an upload to /transcription/upload. The audio is extracted in the background: poll GET /transcription/from-url/{id} for its progress and, once complete, the transcription ID, which is the import ID. The transcription records the page, channel and upload date as its source and is named after the video unless a title is given. Live streams, playlists and audio over URL_IMPORT_MAX_MB are refused.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param request body ImportURLRequest true "Page to import"
+// @Success 202 {object} models.URLImport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 507 {object} map[string]interface{}
+// @Router /api/v1/transcription/from-media [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ImportFromMedia(c *gin.Context) {
+	urlImport, ok := bindURLImport(c)
+	if !ok {
+		return
+	}
+	if !h.config.URLImportAllowPrivate {
+		parsed, _ := url.Parse(urlImport.URL)
+		if err := checkPublicHost(c.Request.Context(), parsed.Hostname()); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	urlImport.Extractor = models.URLImportYtDlp
+	if err := database.DB.Create(urlImport).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create import"})
+		return
+	}
+
+	go h.runURLImport(*urlImport)
+	c.JSON(http.StatusAccepted, urlImport)
+}
+
+// extractMediaImport reads a page's metadata with yt-dlp, then extracts its audio into the
+// upload directory, returning the file and its name. The metadata is saved as the import's
+// source.
+func (h *Handler) extractMediaImport(ctx context.Context, urlImport *models.URLImport) (string, string, error) {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		ticker := time.NewTicker(mediaImportHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				database.DB.Model(&models.URLImport{}).Where("id = ?", urlImport.ID).Update("updated_at", time.Now())
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var stderr bytes.Buffer
+	cmd := h.ytDlpCommand(ctx, "--dump-single-json", "--no-playlist", "--skip-download", urlImport.URL)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", "", ytDlpError(stderr.String(), err)
+	}
+	var info ytDlpInfo
+	if err := json.Unmarshal(output, &info); err != nil {
+		return "", "", fmt.Errorf("failed to read yt-dlp metadata: %w", err)
+	}
+	if info.IsLive {
+		return "", "", fmt.Errorf("live streams can't be imported")
+	}
+	urlImport.Source = info.source(urlImport.URL)
+	maxBytes := h.urlImportMaxBytes()
+	size := info.Filesize
+	if size == 0 {
+		size = info.FilesizeApprox
+	}
+	if size > 0 {
+		urlImport.TotalBytes = &size
+	}
+	if err := database.DB.Model(urlImport).Select("source", "total_bytes").Updates(urlImport).Error; err != nil {
+		return "", "", fmt.Errorf("failed to save metadata: %w", err)
+	}
+	if size > maxBytes {
+		return "", "", fmt.Errorf("file is %d bytes, over the %d byte limit", size, maxBytes)
+	}
+	if size > 0 {
+		if err := h.resourceGuard.Check(ctx, size); err != nil {
+			return "", "", err
+		}
+	}
+
+	if err := os.MkdirAll(h.config.UploadDir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	filePath := filepath.Join(h.config.UploadDir, urlImport.ID+".mp3")
+	stderr.Reset()
+	cmd = h.ytDlpCommand(ctx,
+		"--no-playlist",
+		"--extract-audio",
+		"--audio-format", "mp3",
+		"--audio-quality", "0", // best quality
+		"--max-filesize", strconv.FormatInt(maxBytes, 10),
+		"--quiet", "--progress", "--newline",
+		"--progress-template", "download:"+ytDlpProgressPrefix+" %(progress.downloaded_bytes)s %(progress.total_bytes)s %(progress.total_bytes_estimate)s",
+		"--output", filepath.Join(h.config.UploadDir, urlImport.ID+".%(ext)s"),
+		urlImport.URL,
+	)
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to start yt-dlp: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return "", "", fmt.Errorf("failed to start yt-dlp: %w", err)
+	}
+	saveMediaProgress(urlImport.ID, stdout)
+	if err := cmd.Wait(); err != nil {
+		removeMediaFiles(h.config.UploadDir, urlImport.ID)
+		return "", "", ytDlpError(stderr.String(), err)
+	}
+
+	// yt-dlp skips files over --max-filesize without failing, leaving no audio behind
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		removeMediaFiles(h.config.UploadDir, urlImport.ID)
+		return "", "", fmt.Errorf("yt-dlp didn't extract any audio; it may be over the %d byte limit", maxBytes)
+	}
+	if fileInfo.Size() > maxBytes {
+		os.Remove(filePath)
+		return "", "", fmt.Errorf("file is over the %d byte limit", maxBytes)
+	}
+	database.DB.Model(&models.URLImport{}).Where("id = ?", urlImport.ID).Update("downloaded_bytes", fileInfo.Size())
+
+	name := info.Title
+	if name == "" {
+		name = urlImport.ID
+	}
+	return filePath, name + ".mp3", nil
+}
+
+// saveMediaProgress reads yt-dlp's progress lines until it exits, saving the bytes downloaded
+// and the expected total at most once per urlImportProgressInterval
+func saveMediaProgress(importID string, stdout io.Reader) {
+	var saved time.Time
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 || fields[0] != ytDlpProgressPrefix || time.Since(saved) < urlImportProgressInterval {
+			continue
+		}
+		downloaded, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			continue
+		}
+		updates := map[string]interface{}{"downloaded_bytes": int64(downloaded)}
+		// The total is NA while yt-dlp only has an estimate
+		for _, field := range fields[2:] {
+			if total, err := strconv.ParseFloat(field, 64); err == nil && total > 0 {
+				updates["total_bytes"] = int64(total)
+				break
+			}
+		}
+		saved = time.Now()
+		database.DB.Model(&models.URLImport{}).Where("id = ?", importID).Updates(updates)
+	}
+}
+
+// removeMediaFiles removes what a failed yt-dlp import left in the upload directory, such as
+// partial downloads
+func removeMediaFiles(uploadDir, importID string) {
+	matches, _ := filepath.Glob(filepath.Join(uploadDir, importID+".*"))
+	for _, match := range matches {
+		os.Remove(match)
+	}
+}
@@ -0,0 +1,212 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"scriberr/internal/audio"
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxRecordingChannels caps the channels of a multi-channel upload, each transcribed on its own
+const maxRecordingChannels = 32
+
+// channelSpeakers names the speaker on each channel from a comma-separated list, "Channel N"
+// for those not named, returning an error for a list of the wrong length or repeated names
+func channelSpeakers(list string, channels int) ([]string, error) {
+	names := make([]string, channels)
+	if strings.TrimSpace(list) != "" {
+		given := strings.Split(list, ",")
+		if len(given) != channels {
+			return nil, fmt.Errorf("speakers names %d channels but the recording has %d", len(given), channels)
+		}
+		for i, name := range given {
+			// Names become file names
+			names[i] = strings.NewReplacer("/", "-", "\\", "-").Replace(strings.TrimSpace(name))
+		}
+	}
+
+	seen := map[string]bool{}
+	for i := range names {
+		if names[i] == "" {
+			names[i] = fmt.Sprintf("Channel %d", i+1)
+		}
+		key := strings.ToLower(names[i])
+		if seen[key] {
+			return nil, fmt.Errorf("speaker %q is named twice", names[i])
+		}
+		seen[key] = true
+	}
+	return names, nil
+}
+
+// @Summary Upload a multi-channel recording
+// @Description Upload a recording with a participant on each channel, such as the per-participant tracks of a podcast recorder or a conferencing export. Each channel is transcribed on its own and the transcripts are merged into one timeline with the channel's speaker, rather than guessing speakers by diarization. The recording is queued with the default profile in multi-track mode.
+// @Tags transcription
+// @Accept multipart/form-data
+// @Produce json
+// @Param audio formData file true "Audio file with two or more channels"
+// @Param speakers formData string false "Comma-separated speaker names, one per channel in order (default: Channel 1, Channel 2, ...)"
+// @Param title formData string false "Job title (default: the file name)"
+// @Param initial_prompt formData string false "Initial prompt text to bias recognition"
+// @Param participants formData string false "Comma-separated participant names added to the initial prompt"
+// @Param agenda formData string false "Meeting agenda added to the initial prompt"
+// @Param glossary formData string false "Comma-separated glossary terms added to the initial prompt"
+// @Param content_type formData string false "meeting, voice_memo or podcast; decides the RAG collection, chunking and chat prompts" default(meeting)
+// @Param project_id formData string false "Project to file the transcription in; gives it the project's content type unless content_type is set, its tags and its post-processing settings"
+// @Param priority formData int false "Queue priority from -10 to 10; higher is transcribed first" default(0)
+// @Param template formData string false "Name of a job template whose profile, engine, model, tags and webhooks to use"
+// @Success 200 {object} models.TranscriptionJob
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/upload-multichannel [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UploadMultiChannel(c *gin.Context) {
+	file, header, err := c.Request.FormFile("audio")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Audio file is required"})
+		return
+	}
+	defer file.Close()
+
+	jobID := uuid.New().String()
+	multiTrackFolder := filepath.Join(h.config.UploadDir, jobID)
+	tracksFolder := filepath.Join(multiTrackFolder, "tracks")
+	if err := os.MkdirAll(tracksFolder, 0755); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload directory"})
+		return
+	}
+	created := false
+	defer func() {
+		if !created {
+			os.RemoveAll(multiTrackFolder)
+		}
+	}()
+
+	// The recording itself is kept for playback, its channels mixed by the player
+	recordingPath := filepath.Join(multiTrackFolder, "recording"+filepath.Ext(header.Filename))
+	dst, err := os.Create(recordingPath)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
+		return
+	}
+	_, err = io.Copy(dst, file)
+	dst.Close()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
+		return
+	}
+
+	title := c.PostForm("title")
+	if title == "" {
+		title = strings.TrimSuffix(header.Filename, filepath.Ext(header.Filename))
+	}
+	job := models.TranscriptionJob{
+		ID:               jobID,
+		UserID:           currentUserID(c),
+		Title:            &title,
+		AudioPath:        recordingPath,
+		Status:           models.StatusUploaded,
+		IsMultiTrack:     true,
+		MultiTrackFolder: &multiTrackFolder,
+		MergedAudioPath:  &recordingPath,
+		MergeStatus:      "completed", // Nothing to merge
+	}
+	jobPrompt := initialPromptFromForm(c)
+	job.Parameters.InitialPrompt = mergeInitialPrompt(nil, jobPrompt)
+	var ok bool
+	if job.SummaryTemplateID, ok = summaryTemplateFromForm(c); !ok {
+		return
+	}
+	if job.ContentType, ok = contentTypeFromForm(c); !ok {
+		return
+	}
+	if job.Priority, ok = priorityFromForm(c); !ok {
+		return
+	}
+	template, ok := jobTemplateFromForm(c)
+	if !ok {
+		return
+	}
+	if template != nil {
+		job.JobTemplateID = &template.ID
+		job.WebhookURLs = template.WebhookURLs
+	}
+	project, ok := projectFromForm(c)
+	if !ok {
+		return
+	}
+	project.apply(c, &job)
+
+	splitter := audio.NewChannelSplitter()
+	channels, err := splitter.Channels(c.Request.Context(), recordingPath)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Failed to read the recording: %v", err)})
+		return
+	}
+	if channels < 2 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The recording has a single channel; upload it to /transcription/upload and use diarization instead"})
+		return
+	}
+	if channels > maxRecordingChannels {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("The recording has %d channels; at most %d are supported", channels, maxRecordingChannels)})
+		return
+	}
+	speakers, err := channelSpeakers(c.PostForm("speakers"), channels)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	trackPaths, err := splitter.Split(c.Request.Context(), recordingPath, tracksFolder, speakers)
+	if err != nil {
+		logger.Error("Failed to split channels", "job_id", jobID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to split the recording into channels"})
+		return
+	}
+
+	if err := database.DB.Create(&job).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
+		return
+	}
+	for i, trackPath := range trackPaths {
+		track := models.MultiTrackFile{
+			TranscriptionJobID: jobID,
+			FileName:           filepath.Base(trackPath), // The speaker's name
+			FilePath:           trackPath,
+			TrackIndex:         i,
+			Gain:               1.0,
+		}
+		if err := database.DB.Create(&track).Error; err != nil {
+			database.DB.Where("transcription_job_id = ?", jobID).Delete(&models.MultiTrackFile{})
+			database.DB.Delete(&job)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create track file records"})
+			return
+		}
+	}
+	created = true
+
+	var tags []string
+	if template != nil {
+		tags = template.Tags
+	}
+	project.tag(&job, tags)
+	h.autoTranscribe(currentUserID(c), &job, jobPrompt, template)
+	go checkAudioQuality(jobID, recordingPath)
+
+	if err := database.DB.Preload("MultiTrackFiles").First(&job, "id = ?", jobID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load complete job"})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
@@ -1,7 +1,6 @@
 package api
 
 import (
-	"log"
 	"net/http"
 	"time"
 
@@ -11,6 +10,7 @@ import (
 
 	"scriberr/internal/database"
 	"scriberr/internal/models"
+	"scriberr/pkg/logger"
 )
 
 // NoteCreateRequest is the payload for creating a note
@@ -86,25 +86,25 @@ func (h *Handler) ListNotes(c *gin.Context) {
 func (h *Handler) CreateNote(c *gin.Context) {
 	transcriptionID := c.Param("id")
 	if transcriptionID == "" {
-		log.Printf("notes.CreateNote: missing transcription ID")
+		logger.DebugContext(c.Request.Context(), "Note rejected: missing transcription ID")
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Transcription ID is required"})
 		return
 	}
 
 	var req NoteCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		log.Printf("notes.CreateNote: invalid payload for transcription %s: %v", transcriptionID, err)
+		logger.DebugContext(c.Request.Context(), "Note rejected: invalid payload", "job_id", transcriptionID, "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload", "details": err.Error()})
 		return
 	}
 
 	if req.EndWordIndex < req.StartWordIndex {
-		log.Printf("notes.CreateNote: invalid indices (start=%d end=%d) for transcription %s", req.StartWordIndex, req.EndWordIndex, transcriptionID)
+		logger.DebugContext(c.Request.Context(), "Note rejected: invalid word indices", "job_id", transcriptionID, "start", req.StartWordIndex, "end", req.EndWordIndex)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "end_word_index must be >= start_word_index", "start_word_index": req.StartWordIndex, "end_word_index": req.EndWordIndex})
 		return
 	}
 	if req.EndTime < req.StartTime {
-		log.Printf("notes.CreateNote: invalid times (start=%.3f end=%.3f) for transcription %s", req.StartTime, req.EndTime, transcriptionID)
+		logger.DebugContext(c.Request.Context(), "Note rejected: invalid times", "job_id", transcriptionID, "start_time", req.StartTime, "end_time", req.EndTime)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "end_time must be >= start_time", "start_time": req.StartTime, "end_time": req.EndTime})
 		return
 	}
@@ -113,11 +113,11 @@ func (h *Handler) CreateNote(c *gin.Context) {
 	var job models.TranscriptionJob
 	if err := database.DB.Where("id = ?", transcriptionID).First(&job).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			log.Printf("notes.CreateNote: transcription %s not found", transcriptionID)
+			logger.DebugContext(c.Request.Context(), "Note rejected: transcription not found", "job_id", transcriptionID)
 			c.JSON(http.StatusNotFound, gin.H{"error": "Transcription not found"})
 			return
 		}
-		log.Printf("notes.CreateNote: failed to fetch transcription %s: %v", transcriptionID, err)
+		logger.ErrorContext(c.Request.Context(), "Failed to fetch transcription for note", "job_id", transcriptionID, "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transcription"})
 		return
 	}
@@ -136,12 +136,12 @@ func (h *Handler) CreateNote(c *gin.Context) {
 	}
 
 	if err := database.DB.Create(&n).Error; err != nil {
-		log.Printf("notes.CreateNote: DB error creating note for transcription %s (start=%d end=%d startTime=%.3f endTime=%.3f): %v", transcriptionID, n.StartWordIndex, n.EndWordIndex, n.StartTime, n.EndTime, err)
+		logger.ErrorContext(c.Request.Context(), "Failed to create note", "job_id", transcriptionID, "start", n.StartWordIndex, "end", n.EndWordIndex, "start_time", n.StartTime, "end_time", n.EndTime, "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create note"})
 		return
 	}
 
-	log.Printf("notes.CreateNote: created note %s for transcription %s (start=%d end=%d startTime=%.3f endTime=%.3f quoteLen=%d)", n.ID, transcriptionID, n.StartWordIndex, n.EndWordIndex, n.StartTime, n.EndTime, len(n.Quote))
+	logger.InfoContext(c.Request.Context(), "Created note", "note_id", n.ID, "job_id", transcriptionID, "start", n.StartWordIndex, "end", n.EndWordIndex, "quote_len", len(n.Quote))
 	// Tests expect 200 on creation
 	c.JSON(http.StatusOK, n)
 }
@@ -0,0 +1,680 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/export"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Response formats of the OpenAI-compatible transcription endpoint
+const (
+	OpenAIFormatJSON        = "json"
+	OpenAIFormatText        = "text"
+	OpenAIFormatSRT         = "srt"
+	OpenAIFormatVTT         = "vtt"
+	OpenAIFormatVerboseJSON = "verbose_json"
+)
+
+// OpenAIDefaultModel is the model name OpenAI's clients send by default; it transcribes with
+// the quick transcription defaults
+const OpenAIDefaultModel = "whisper-1"
+
+// openAIModelFamilies are the model families a request can name, optionally followed by
+// :model, as in "whisper:large-v3" or "nvidia_parakeet"
+var openAIModelFamilies = map[string]bool{"whisper": true, "nvidia_parakeet": true, "nvidia_canary": true}
+
+// whisperModelSizes are the Whisper models a request can name on their own
+var whisperModelSizes = map[string]bool{
+	"tiny": true, "tiny.en": true, "base": true, "base.en": true, "small": true, "small.en": true,
+	"medium": true, "medium.en": true, "large": true, "large-v1": true, "large-v2": true, "large-v3": true,
+}
+
+// OpenAIError is an error in the shape OpenAI's API returns, which its client libraries parse
+type OpenAIError struct {
+	Error OpenAIErrorDetail `json:"error"`
+}
+
+// OpenAIErrorDetail describes what went wrong
+type OpenAIErrorDetail struct {
+	Message string  `json:"message"`
+	Type    string  `json:"type"`
+	Param   *string `json:"param"`
+	Code    *string `json:"code"`
+}
+
+// OpenAITranscription is the json response: the transcript's text
+type OpenAITranscription struct {
+	Text string `json:"text"`
+}
+
+// OpenAIVerboseTranscription is the verbose_json response
+type OpenAIVerboseTranscription struct {
+	Task     string                  `json:"task"`
+	Language string                  `json:"language"`
+	Duration float64                 `json:"duration"`
+	Text     string                  `json:"text"`
+	Segments []OpenAISegment         `json:"segments,omitempty"`
+	Words    []OpenAITranscribedWord `json:"words,omitempty"`
+}
+
+// OpenAISegment is a timed segment of a verbose_json response. Scores Scriberr's engines
+// don't report are zero.
+type OpenAISegment struct {
+	ID               int     `json:"id"`
+	Seek             int     `json:"seek"`
+	Start            float64 `json:"start"`
+	End              float64 `json:"end"`
+	Text             string  `json:"text"`
+	Tokens           []int   `json:"tokens"`
+	Temperature      float64 `json:"temperature"`
+	AvgLogprob       float64 `json:"avg_logprob"`
+	CompressionRatio float64 `json:"compression_ratio"`
+	NoSpeechProb     float64 `json:"no_speech_prob"`
+}
+
+// OpenAITranscribedWord is a timed word of a verbose_json response
+type OpenAITranscribedWord struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// openAIError writes an error response OpenAI's client libraries understand. param names the
+// form field at fault and code is a machine-readable reason; either may be empty.
+func openAIError(c *gin.Context, status int, message, param, code string) {
+	detail := OpenAIErrorDetail{Message: message, Type: "invalid_request_error"}
+	if status >= http.StatusInternalServerError {
+		detail.Type = "server_error"
+	}
+	if param != "" {
+		detail.Param = &param
+	}
+	if code != "" {
+		detail.Code = &code
+	}
+	c.JSON(status, OpenAIError{Error: detail})
+}
+
+// openAITranscriptionParams resolves the model of a transcription request: OpenAIDefaultModel
+// for the quick transcription defaults, a transcription profile by name, a model family with
+// an optional :model, or a Whisper model size. It reports false if model is none of these.
+func openAITranscriptionParams(model string) (models.WhisperXParams, bool, error) {
+	params := defaultQuickParams()
+	if model == "" || model == OpenAIDefaultModel {
+		return params, true, nil
+	}
+
+	var profile models.TranscriptionProfile
+	result := database.DB.Where("name = ?", model).Limit(1).Find(&profile)
+	if result.Error != nil {
+		return params, false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return profile.Parameters, true, nil
+	}
+
+	family, size, hasSize := strings.Cut(model, ":")
+	switch {
+	case openAIModelFamilies[family]:
+		params.ModelFamily = family
+		if hasSize {
+			if family == "whisper" && !whisperModelSizes[size] {
+				return params, false, nil
+			}
+			params.Model = size
+		}
+	case whisperModelSizes[model]:
+		params.ModelFamily = "whisper"
+		params.Model = model
+	default:
+		return params, false, nil
+	}
+	return params, true, nil
+}
+
+// @Summary Transcribe audio (OpenAI-compatible)
+// @Description Transcribe an audio file the way OpenAI's /v1/audio/transcriptions does, so OpenAI client libraries and tools can use Scriberr as their backend. The request waits for the transcript, which isn't kept. API keys may also be sent as "Authorization: Bearer <key>".
+// @Tags openai
+// @Accept multipart/form-data
+// @Produce json
+// @Produce plain
+// @Param file formData file true "Audio file"
+// @Param model formData string false "whisper-1 for the defaults, a transcription profile's name, a model family such as whisper:large-v3 or nvidia_parakeet, or a Whisper model size" default(whisper-1)
+// @Param language formData string false "Language of the audio as an ISO 639-1 code"
+// @Param prompt formData string false "Text to bias recognition with, used as the initial prompt"
+// @Param temperature formData number false "Sampling temperature between 0 and 1"
+// @Param response_format formData string false "json, text, srt, vtt or verbose_json" default(json)
+// @Param timestamp_granularities[] formData []string false "segment and/or word; the timestamps verbose_json includes" collectionFormat(multi)
+// @Success 200 {object} OpenAIVerboseTranscription
+// @Failure 400 {object} OpenAIError
+// @Failure 500 {object} OpenAIError
+// @Failure 503 {object} OpenAIError
+// @Router /v1/audio/transcriptions [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateOpenAITranscription(c *gin.Context) {
+	if h.quickTranscription == nil {
+		openAIError(c, http.StatusServiceUnavailable, "Transcription is not available", "", "")
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		openAIError(c, http.StatusBadRequest, "An audio file is required", "file", "")
+		return
+	}
+	defer file.Close()
+
+	format := c.DefaultPostForm("response_format", OpenAIFormatJSON)
+	switch format {
+	case OpenAIFormatJSON, OpenAIFormatText, OpenAIFormatSRT, OpenAIFormatVTT, OpenAIFormatVerboseJSON:
+	default:
+		openAIError(c, http.StatusBadRequest, fmt.Sprintf("Unsupported response_format %q: use json, text, srt, vtt or verbose_json", format), "response_format", "")
+		return
+	}
+	segmentTimes, wordTimes := true, false
+	if granularities := c.PostFormArray("timestamp_granularities[]"); len(granularities) > 0 {
+		segmentTimes = false
+		for _, granularity := range granularities {
+			switch granularity {
+			case "segment":
+				segmentTimes = true
+			case "word":
+				wordTimes = true
+			default:
+				openAIError(c, http.StatusBadRequest, fmt.Sprintf("Unsupported timestamp granularity %q: use segment or word", granularity), "timestamp_granularities", "")
+				return
+			}
+		}
+	}
+
+	model := strings.TrimSpace(c.PostForm("model"))
+	params, found, err := openAITranscriptionParams(model)
+	if err != nil {
+		openAIError(c, http.StatusInternalServerError, "Failed to load the transcription profile", "", "")
+		return
+	}
+	if !found {
+		openAIError(c, http.StatusBadRequest, fmt.Sprintf("The model %q does not exist: use %s, a transcription profile's name, a model family or a Whisper model size", model, OpenAIDefaultModel), "model", "model_not_found")
+		return
+	}
+	if language := strings.TrimSpace(c.PostForm("language")); language != "" {
+		params.Language = &language
+	}
+	if prompt := strings.TrimSpace(c.PostForm("prompt")); prompt != "" {
+		params.InitialPrompt = &prompt
+	}
+	if value := c.PostForm("temperature"); value != "" {
+		temperature, err := strconv.ParseFloat(value, 64)
+		if err != nil || temperature < 0 || temperature > 1 {
+			openAIError(c, http.StatusBadRequest, "temperature must be a number between 0 and 1", "temperature", "")
+			return
+		}
+		params.Temperature = temperature
+	}
+
+	submitted, err := h.quickTranscription.SubmitQuickJob(file, header.Filename, params, currentUserID(c))
+	if err != nil {
+		openAIError(c, http.StatusInternalServerError, fmt.Sprintf("Failed to submit transcription: %v", err), "", "")
+		return
+	}
+	job, err := h.quickTranscription.WaitQuickJob(c.Request.Context(), submitted.ID)
+	if err != nil {
+		openAIError(c, http.StatusInternalServerError, fmt.Sprintf("Transcription was interrupted: %v", err), "", "")
+		return
+	}
+	defer h.quickTranscription.RemoveQuickJob(job.ID)
+	if job.Status != models.StatusCompleted || job.Transcript == nil {
+		message := "Transcription failed"
+		if job.ErrorMessage != nil {
+			message += ": " + *job.ErrorMessage
+		}
+		openAIError(c, http.StatusInternalServerError, message, "", "")
+		return
+	}
+
+	result := &models.TranscriptionJob{Transcript: job.Transcript}
+	segments := export.TranscriptSegments(result)
+	var transcript interfaces.TranscriptResult
+	_ = json.Unmarshal([]byte(*job.Transcript), &transcript)
+	text := strings.TrimSpace(transcript.Text)
+	if text == "" {
+		parts := make([]string, 0, len(segments))
+		for _, segment := range segments {
+			parts = append(parts, strings.TrimSpace(segment.Text))
+		}
+		text = strings.Join(parts, " ")
+	}
+	duration := 0.0
+	if len(segments) > 0 {
+		duration = segments[len(segments)-1].End
+	}
+
+	switch format {
+	case OpenAIFormatJSON:
+		c.JSON(http.StatusOK, OpenAITranscription{Text: text})
+	case OpenAIFormatText:
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(text+"\n"))
+	case OpenAIFormatSRT, OpenAIFormatVTT:
+		data, contentType, _, err := export.Subtitles(format, segments, export.TranscriptWords(result), export.SubtitleOptions{})
+		if err != nil {
+			openAIError(c, http.StatusInternalServerError, "Failed to render subtitles", "", "")
+			return
+		}
+		c.Data(http.StatusOK, contentType, data)
+	case OpenAIFormatVerboseJSON:
+		language := transcript.Language
+		if language == "" && params.Language != nil {
+			language = *params.Language
+		}
+		verbose := OpenAIVerboseTranscription{
+			Task:     "transcribe",
+			Language: strings.ToLower(rag.LanguageName(language)),
+			Duration: duration,
+			Text:     text,
+		}
+		if segmentTimes {
+			verbose.Segments = make([]OpenAISegment, 0, len(segments))
+			for i, segment := range segments {
+				verbose.Segments = append(verbose.Segments, OpenAISegment{
+					ID:          i,
+					Start:       segment.Start,
+					End:         segment.End,
+					Text:        segment.Text,
+					Tokens:      []int{},
+					Temperature: params.Temperature,
+				})
+			}
+		}
+		if wordTimes {
+			words := export.TranscriptWords(result)
+			verbose.Words = make([]OpenAITranscribedWord, 0, len(words))
+			for _, word := range words {
+				verbose.Words = append(verbose.Words, OpenAITranscribedWord{Word: word.Word, Start: word.Start, End: word.End})
+			}
+		}
+		c.JSON(http.StatusOK, verbose)
+	}
+}
+
+// OpenAIRAGModel is the chat model that answers from the caller's transcriptions through RAG.
+// Chat completions for other models are passed on to the LLM configured in the UI.
+const OpenAIRAGModel = "scriberr-rag"
+
+// OpenAIChatMessage is a message of a chat completion request. Its content is either a string
+// or a list of content parts, of which the text parts are read.
+type OpenAIChatMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content" swaggertype:"string"`
+}
+
+// Text returns the text of the message
+func (m OpenAIChatMessage) Text() string {
+	var text string
+	if err := json.Unmarshal(m.Content, &text); err == nil {
+		return text
+	}
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(m.Content, &parts); err != nil {
+		return ""
+	}
+	texts := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part.Type == "text" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// OpenAIChatRequest is a chat completion request. Fields OpenAI's API has beyond these are ignored.
+type OpenAIChatRequest struct {
+	Model       string              `json:"model" binding:"required"`
+	Messages    []OpenAIChatMessage `json:"messages" binding:"required,min=1"`
+	Temperature *float64            `json:"temperature,omitempty"`
+	Stream      bool                `json:"stream,omitempty"`
+}
+
+// OpenAIChatCompletion is a chat completion, or with stream set one chunk of it
+type OpenAIChatCompletion struct {
+	ID      string             `json:"id"`
+	Object  string             `json:"object"`
+	Created int64              `json:"created"`
+	Model   string             `json:"model"`
+	Choices []OpenAIChatChoice `json:"choices"`
+	Usage   *OpenAIUsage       `json:"usage,omitempty"`
+	// Sources are the IDs of the transcriptions a scriberr-rag answer drew on
+	Sources []string `json:"sources,omitempty"`
+}
+
+// OpenAIChatChoice is the reply of a chat completion: the whole message, or in a chunk the
+// part of it that arrived
+type OpenAIChatChoice struct {
+	Index        int              `json:"index"`
+	Message      *OpenAIChatReply `json:"message,omitempty"`
+	Delta        *OpenAIChatReply `json:"delta,omitempty"`
+	FinishReason *string          `json:"finish_reason"`
+}
+
+// OpenAIChatReply is the assistant's reply, or a part of it
+type OpenAIChatReply struct {
+	Role    string `json:"role,omitempty"`
+	Content string `json:"content"`
+}
+
+// OpenAIUsage counts the tokens of a chat completion. Providers that don't report them have
+// them estimated.
+type OpenAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// OpenAIModel is a model listed by /v1/models
+type OpenAIModel struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+}
+
+// scopedAPIKey reports whether the request is authenticated with an API key limited by scopes,
+// which may use the RAG model but not the LLM behind it
+func scopedAPIKey(c *gin.Context) bool {
+	_, scoped := c.Get("api_key_scopes")
+	return scoped
+}
+
+// @Summary List models (OpenAI-compatible)
+// @Description List the models OpenAI-compatible clients can use: scriberr-rag, which answers from the caller's transcriptions, whisper-1 for transcription, and the models of the LLM configured in the UI, which chat completions are passed on to. API keys with scopes only see scriberr-rag and whisper-1.
+// @Tags openai
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /v1/models [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ListOpenAIModels(c *gin.Context) {
+	var listed []OpenAIModel
+	if h.ragService != nil {
+		listed = append(listed, OpenAIModel{ID: OpenAIRAGModel, Object: "model", OwnedBy: "scriberr"})
+	}
+	if h.quickTranscription != nil {
+		listed = append(listed, OpenAIModel{ID: OpenAIDefaultModel, Object: "model", OwnedBy: "scriberr"})
+	}
+	if !scopedAPIKey(c) {
+		// The LLM is optional, so a missing or unreachable one only leaves its models out
+		if svc, provider, err := h.getLLMService(); err == nil {
+			names, err := svc.GetModels(c.Request.Context())
+			if err != nil {
+				logger.Warn("Failed to list the LLM's models", "provider", provider, "error", err)
+			}
+			for _, name := range names {
+				if name != OpenAIRAGModel {
+					listed = append(listed, OpenAIModel{ID: name, Object: "model", OwnedBy: provider})
+				}
+			}
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"object": "list", "data": listed})
+}
+
+// @Summary Create a chat completion (OpenAI-compatible)
+// @Description Answer a conversation the way OpenAI's /v1/chat/completions does, so OpenAI-compatible chat clients can use Scriberr. The model scriberr-rag answers the last user message from the caller's transcriptions through RAG, with the earlier messages as the conversation so far, and lists the transcriptions it drew on in sources. Other models are passed on to the LLM configured in the UI. With stream set, the reply is sent as server-sent events; scriberr-rag sends its answer in one chunk. API keys may also be sent as "Authorization: Bearer <key>".
+// @Tags openai
+// @Accept json
+// @Produce json
+// @Param request body OpenAIChatRequest true "Chat completion request"
+// @Success 200 {object} OpenAIChatCompletion
+// @Failure 400 {object} OpenAIError
+// @Failure 403 {object} OpenAIError
+// @Failure 500 {object} OpenAIError
+// @Failure 503 {object} OpenAIError
+// @Router /v1/chat/completions [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateOpenAIChatCompletion(c *gin.Context) {
+	var req OpenAIChatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		openAIError(c, http.StatusBadRequest, err.Error(), "", "")
+		return
+	}
+	if req.Temperature != nil && (*req.Temperature < 0 || *req.Temperature > 2) {
+		openAIError(c, http.StatusBadRequest, "temperature must be between 0 and 2", "temperature", "")
+		return
+	}
+	messages := make([]llm.ChatMessage, 0, len(req.Messages))
+	for _, message := range req.Messages {
+		messages = append(messages, llm.ChatMessage{Role: message.Role, Content: message.Text()})
+	}
+	completion := OpenAIChatCompletion{
+		ID:      "chatcmpl-" + uuid.New().String(),
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   req.Model,
+	}
+
+	if req.Model == OpenAIRAGModel {
+		h.ragChatCompletion(c, req, messages, completion)
+		return
+	}
+	if scopedAPIKey(c) {
+		openAIError(c, http.StatusForbidden, fmt.Sprintf("This API key's scopes only allow the %s model", OpenAIRAGModel), "model", "model_not_allowed")
+		return
+	}
+	h.proxyChatCompletion(c, req, messages, completion)
+}
+
+// ragChatCompletion answers the last user message from the caller's transcriptions
+func (h *Handler) ragChatCompletion(c *gin.Context, req OpenAIChatRequest, messages []llm.ChatMessage, completion OpenAIChatCompletion) {
+	if h.ragService == nil {
+		openAIError(c, http.StatusServiceUnavailable, "RAG service not initialized", "", "")
+		return
+	}
+	last := -1
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" && strings.TrimSpace(messages[i].Content) != "" {
+			last = i
+			break
+		}
+	}
+	if last < 0 {
+		openAIError(c, http.StatusBadRequest, "The messages have no question from the user", "messages", "")
+		return
+	}
+	// The RAG prompt has instructions of its own, so only the conversation is kept
+	var history []llm.ChatMessage
+	for _, message := range messages[:last] {
+		if message.Role == "user" || message.Role == "assistant" {
+			history = append(history, message)
+		}
+	}
+
+	temperature := 0.7
+	if req.Temperature != nil {
+		temperature = *req.Temperature
+	}
+	opts := rag.ChatOptions{Mode: rag.ModeAbstractive, CrossLanguage: h.config.RAGCrossLanguage, History: history}
+	result, err := h.ragService.Chat(c.Request.Context(), currentUserID(c), messages[last].Content, h.config.ChatLLMModel, temperature, opts)
+	if err != nil {
+		openAIError(c, http.StatusInternalServerError, err.Error(), "", "")
+		return
+	}
+
+	answer := result.Answer
+	if titles := sourceTitles(result.Sources); len(titles) > 0 {
+		// Chat clients only show the reply, so it names the recordings it came from
+		answer += "\n\nSources: " + strings.Join(titles, "; ")
+	}
+	completion.Sources = result.Sources
+	if req.Stream {
+		stream := newOpenAIStream(c, completion)
+		stream.send(answer)
+		stream.finish("stop")
+		return
+	}
+	prompt := 0
+	for _, message := range messages[:last+1] {
+		prompt += llm.EstimateTokens(message.Content)
+	}
+	completion.Usage = &OpenAIUsage{PromptTokens: prompt, CompletionTokens: llm.EstimateTokens(answer)}
+	completion.Usage.TotalTokens = completion.Usage.PromptTokens + completion.Usage.CompletionTokens
+	stop := "stop"
+	completion.Choices = []OpenAIChatChoice{{Message: &OpenAIChatReply{Role: "assistant", Content: answer}, FinishReason: &stop}}
+	c.JSON(http.StatusOK, completion)
+}
+
+// proxyChatCompletion passes the conversation on to the LLM configured in the UI
+func (h *Handler) proxyChatCompletion(c *gin.Context, req OpenAIChatRequest, messages []llm.ChatMessage, completion OpenAIChatCompletion) {
+	svc, provider, err := h.getLLMService()
+	if err != nil {
+		openAIError(c, http.StatusServiceUnavailable, fmt.Sprintf("No LLM to pass %q on to: %v", req.Model, err), "model", "")
+		return
+	}
+	svc = h.observeLLM(llm.FeatureChat, provider, svc)
+	temperature := 0.0 // The provider's default
+	if req.Temperature != nil {
+		temperature = *req.Temperature
+	}
+	ctx := c.Request.Context()
+
+	if !req.Stream {
+		response, err := svc.ChatCompletion(ctx, req.Model, messages, temperature)
+		if err != nil {
+			openAIError(c, http.StatusBadGateway, fmt.Sprintf("The LLM failed: %v", err), "", "")
+			return
+		}
+		for i, choice := range response.Choices {
+			reason := choice.FinishReason
+			if reason == "" {
+				reason = "stop"
+			}
+			completion.Choices = append(completion.Choices, OpenAIChatChoice{
+				Index:        i,
+				Message:      &OpenAIChatReply{Role: "assistant", Content: choice.Message.Content},
+				FinishReason: &reason,
+			})
+		}
+		completion.Usage = &OpenAIUsage{
+			PromptTokens:     response.Usage.PromptTokens,
+			CompletionTokens: response.Usage.CompletionTokens,
+			TotalTokens:      response.Usage.TotalTokens,
+		}
+		c.JSON(http.StatusOK, completion)
+		return
+	}
+
+	contentChan, errorChan := svc.ChatCompletionStream(ctx, req.Model, messages, temperature)
+	stream := newOpenAIStream(c, completion)
+	for {
+		select {
+		case content, ok := <-contentChan:
+			if !ok {
+				stream.finish("stop")
+				return
+			}
+			stream.send(content)
+		case err, ok := <-errorChan:
+			if !ok {
+				errorChan = nil // Closed without an error; the content channel ends the reply
+				continue
+			}
+			if err != nil {
+				stream.fail(fmt.Sprintf("The LLM failed: %v", err))
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// transcriptionTitles returns the titles of transcriptions, or their audio file names when untitled
+func transcriptionTitles(ids []string) (map[string]string, error) {
+	titles := map[string]string{}
+	if len(ids) == 0 {
+		return titles, nil
+	}
+	var jobs []models.TranscriptionJob
+	if err := database.DB.Select("id", "title", "audio_path").Where("id IN ?", ids).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	for i := range jobs {
+		titles[jobs[i].ID] = exportTitle(&jobs[i])
+	}
+	return titles, nil
+}
+
+// sourceTitles returns the titles of transcriptions in order, for naming a reply's sources
+func sourceTitles(ids []string) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+	byID, _ := transcriptionTitles(ids)
+	titles := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if title, ok := byID[id]; ok {
+			titles = append(titles, title)
+		}
+	}
+	return titles
+}
+
+// openAIStream writes a chat completion as the server-sent event chunks OpenAI's clients read
+type openAIStream struct {
+	c     *gin.Context
+	chunk OpenAIChatCompletion
+	role  string // Sent with the first chunk only
+}
+
+func newOpenAIStream(c *gin.Context, completion OpenAIChatCompletion) *openAIStream {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+	completion.Object = "chat.completion.chunk"
+	return &openAIStream{c: c, chunk: completion, role: "assistant"}
+}
+
+// send writes the next part of the reply
+func (s *openAIStream) send(content string) {
+	s.write(OpenAIChatChoice{Delta: &OpenAIChatReply{Role: s.role, Content: content}})
+	s.role = ""
+}
+
+// finish ends the reply with reason and closes the stream
+func (s *openAIStream) finish(reason string) {
+	s.write(OpenAIChatChoice{Delta: &OpenAIChatReply{Role: s.role}, FinishReason: &reason})
+	s.c.Writer.WriteString("data: [DONE]\n\n")
+	s.c.Writer.Flush()
+}
+
+// fail ends the stream with an error, as OpenAI does when a reply breaks off
+func (s *openAIStream) fail(message string) {
+	data, _ := json.Marshal(OpenAIError{Error: OpenAIErrorDetail{Message: message, Type: "server_error"}})
+	s.c.Writer.WriteString("data: " + string(data) + "\n\n")
+	s.c.Writer.Flush()
+}
+
+func (s *openAIStream) write(choice OpenAIChatChoice) {
+	s.chunk.Choices = []OpenAIChatChoice{choice}
+	data, _ := json.Marshal(s.chunk)
+	s.c.Writer.WriteString("data: " + string(data) + "\n\n")
+	s.c.Writer.Flush()
+}
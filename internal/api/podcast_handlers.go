@@ -0,0 +1,497 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/podcasts"
+	"scriberr/internal/queue"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Episode statuses that come before the episode's transcription has one
+const (
+	episodeAvailable   = "available"   // Not transcribed
+	episodeDownloading = "downloading" // Audio being downloaded
+	episodeFailed      = "failed"      // Download failed
+)
+
+// PodcastSettings are the per-feed settings of a podcast subscription
+type PodcastSettings struct {
+	AutoTranscribe *bool   `json:"auto_transcribe,omitempty"` // Defaults to true
+	JobTemplateID  *string `json:"job_template_id,omitempty"`
+	Priority       int     `json:"priority,omitempty"`
+	Paused         bool    `json:"paused"`
+}
+
+// SubscribePodcastRequest subscribes to a podcast feed
+type SubscribePodcastRequest struct {
+	URL      string `json:"url" binding:"required"`
+	Backfill int    `json:"backfill,omitempty"` // How many of the latest episodes to transcribe right away
+	PodcastSettings
+}
+
+// PodcastPollResponse is a feed and what polling it found
+type PodcastPollResponse struct {
+	Feed models.PodcastFeed   `json:"feed"`
+	Poll *podcasts.PollResult `json:"poll"`
+}
+
+// PodcastEpisodeResponse is an episode with the state of its transcription
+type PodcastEpisodeResponse struct {
+	models.PodcastEpisode
+	// Status is available, downloading or failed before the transcription exists, then the
+	// transcription's status
+	Status          string  `json:"status"`
+	TranscriptionID *string `json:"transcription_id,omitempty"`
+	Error           *string `json:"error,omitempty"`
+}
+
+// SetPodcastService enables podcast subscriptions. Feeds are fetched with the URL import
+// client, and episodes are downloaded as URL imports.
+func (h *Handler) SetPodcastService(service *podcasts.Service) {
+	h.podcasts = service
+	client := h.urlImportClient()
+	client.Timeout = time.Minute
+	service.SetClient(client)
+	service.SetImporter(h.importEpisode)
+}
+
+// importEpisode starts downloading an episode as a podcast transcription
+func (h *Handler) importEpisode(feed *models.PodcastFeed, episode *models.PodcastEpisode) (string, error) {
+	urlImport := models.URLImport{
+		UserID:        feed.UserID,
+		URL:           episode.AudioURL,
+		Status:        models.URLImportDownloading,
+		ContentType:   models.ContentPodcast,
+		Priority:      feed.Priority,
+		JobTemplateID: feed.JobTemplateID,
+		Source: &models.MediaSource{
+			URL:             episode.AudioURL,
+			Title:           episode.Title,
+			Channel:         feed.Title,
+			ChannelURL:      feed.URL,
+			DurationSeconds: float64(episode.DurationSeconds),
+		},
+	}
+	if episode.PublishedAt != nil {
+		urlImport.Source.UploadDate = episode.PublishedAt.Format("2006-01-02")
+	}
+	if episode.Title != "" {
+		title := episode.Title
+		urlImport.Title = &title
+	}
+	if err := database.DB.Create(&urlImport).Error; err != nil {
+		return "", err
+	}
+	go h.runURLImport(urlImport)
+	return urlImport.ID, nil
+}
+
+// loadPodcastFeed loads a podcast feed owned by the caller, writing an error response if it can't
+func loadPodcastFeed(c *gin.Context) (*models.PodcastFeed, bool) {
+	var feed models.PodcastFeed
+	if err := scopeToOwner(database.DB, currentUserID(c)).Where("id = ?", c.Param("id")).First(&feed).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Podcast not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get podcast"})
+		}
+		return nil, false
+	}
+	return &feed, true
+}
+
+// requirePodcasts writes an error response if podcast subscriptions aren't enabled
+func (h *Handler) requirePodcasts(c *gin.Context) bool {
+	if h.podcasts == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Podcast subscriptions not enabled"})
+		return false
+	}
+	return true
+}
+
+// apply validates the settings and copies them onto a feed, writing an error response if
+// they are invalid
+func (s PodcastSettings) apply(c *gin.Context, feed *models.PodcastFeed) bool {
+	if s.Priority < queue.MinPriority || s.Priority > queue.MaxPriority {
+		c.JSON(http.StatusBadRequest, gin.H{"error": priorityError})
+		return false
+	}
+	if s.JobTemplateID != nil && *s.JobTemplateID == "" {
+		s.JobTemplateID = nil
+	}
+	if s.JobTemplateID != nil {
+		var count int64
+		if err := scopeToOwner(database.DB.Model(&models.JobTemplate{}), currentUserID(c)).Where("id = ?", *s.JobTemplateID).Count(&count).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job template"})
+			return false
+		}
+		if count == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Job template not found"})
+			return false
+		}
+	}
+	feed.AutoTranscribe = s.AutoTranscribe == nil || *s.AutoTranscribe
+	feed.JobTemplateID = s.JobTemplateID
+	feed.Priority = s.Priority
+	feed.Paused = s.Paused
+	return true
+}
+
+// episodeResponses adds the state of their transcriptions to episodes
+func episodeResponses(episodes []models.PodcastEpisode) ([]PodcastEpisodeResponse, error) {
+	var importIDs []string
+	for _, episode := range episodes {
+		if episode.ImportID != nil {
+			importIDs = append(importIDs, *episode.ImportID)
+		}
+	}
+	imports := map[string]models.URLImport{}
+	jobs := map[string]models.JobStatus{}
+	if len(importIDs) > 0 {
+		var found []models.URLImport
+		if err := database.DB.Where("id IN ?", importIDs).Find(&found).Error; err != nil {
+			return nil, err
+		}
+		for _, urlImport := range found {
+			imports[urlImport.ID] = urlImport
+		}
+		var statuses []models.TranscriptionJob
+		if err := database.DB.Select("id", "status").Where("id IN ?", importIDs).Find(&statuses).Error; err != nil {
+			return nil, err
+		}
+		for _, job := range statuses {
+			jobs[job.ID] = job.Status
+		}
+	}
+
+	responses := make([]PodcastEpisodeResponse, 0, len(episodes))
+	for _, episode := range episodes {
+		response := PodcastEpisodeResponse{PodcastEpisode: episode, Status: episodeAvailable}
+		if episode.ImportID != nil {
+			urlImport, imported := imports[*episode.ImportID]
+			status, transcribed := jobs[*episode.ImportID]
+			switch {
+			case transcribed:
+				response.Status = string(status)
+				response.TranscriptionID = episode.ImportID
+			case imported && urlImport.Status == models.URLImportDownloading:
+				response.Status = episodeDownloading
+			case imported && urlImport.Status == models.URLImportFailed:
+				response.Status = episodeFailed
+				response.Error = urlImport.Error
+			}
+			// A deleted transcription leaves the episode available to transcribe again
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+// ListPodcastFeeds returns the caller's podcast subscriptions
+// @Summary List podcast subscriptions
+// @Description List the podcast feeds the caller is subscribed to, with when each was last polled and why the last poll failed, if it did
+// @Tags podcasts
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/podcasts [get]
+func (h *Handler) ListPodcastFeeds(c *gin.Context) {
+	feeds := []models.PodcastFeed{}
+	if err := scopeToOwner(database.DB, currentUserID(c)).Order("title ASC").Find(&feeds).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list podcasts"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"podcasts": feeds})
+}
+
+// SubscribePodcast subscribes to a podcast feed
+// @Summary Subscribe to a podcast
+// @Description Subscribe to a podcast's RSS feed. The feed is read right away: its episodes are listed, and with auto_transcribe (the default) the latest backfill episodes (up to 20) are downloaded and transcribed as podcasts. Episodes published later are transcribed as the feed is polled every PODCAST_POLL_MINUTES.
+// @Tags podcasts
+// @Accept json
+// @Produce json
+// @Param request body SubscribePodcastRequest true "Feed and settings"
+// @Success 201 {object} PodcastPollResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/podcasts [post]
+func (h *Handler) SubscribePodcast(c *gin.Context) {
+	if !h.requirePodcasts(c) {
+		return
+	}
+	var req SubscribePodcastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	parsed, err := url.Parse(strings.TrimSpace(req.URL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url must be an http or https URL"})
+		return
+	}
+	if req.Backfill < 0 || req.Backfill > podcasts.MaxBackfill {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "backfill must be between 0 and " + strconv.Itoa(podcasts.MaxBackfill)})
+		return
+	}
+	feed := models.PodcastFeed{UserID: currentUserID(c), URL: parsed.String(), Title: parsed.String()}
+	if !req.PodcastSettings.apply(c, &feed) {
+		return
+	}
+	var count int64
+	if err := scopeToOwner(database.DB.Model(&models.PodcastFeed{}), feed.UserID).Where("url = ?", feed.URL).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check subscriptions"})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Already subscribed to this podcast"})
+		return
+	}
+
+	if err := database.DB.Create(&feed).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to subscribe"})
+		return
+	}
+	result, err := h.podcasts.Poll(c.Request.Context(), &feed, req.Backfill)
+	if err != nil {
+		database.DB.Where("feed_id = ?", feed.ID).Delete(&models.PodcastEpisode{})
+		database.DB.Delete(&feed)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read feed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, PodcastPollResponse{Feed: feed, Poll: result})
+}
+
+// GetPodcastFeed returns one of the caller's podcast subscriptions
+// @Summary Get a podcast subscription
+// @Tags podcasts
+// @Produce json
+// @Param id path string true "Podcast ID"
+// @Success 200 {object} models.PodcastFeed
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/podcasts/{id} [get]
+func (h *Handler) GetPodcastFeed(c *gin.Context) {
+	feed, ok := loadPodcastFeed(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, feed)
+}
+
+// UpdatePodcastFeed replaces a podcast subscription's settings
+// @Summary Update a podcast subscription
+// @Description Replace a podcast's settings: whether new episodes are transcribed, the job template, queue priority and whether the feed is polled. Episodes already transcribed are left as they are.
+// @Tags podcasts
+// @Accept json
+// @Produce json
+// @Param id path string true "Podcast ID"
+// @Param request body PodcastSettings true "Settings"
+// @Success 200 {object} models.PodcastFeed
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/podcasts/{id} [put]
+func (h *Handler) UpdatePodcastFeed(c *gin.Context) {
+	feed, ok := loadPodcastFeed(c)
+	if !ok {
+		return
+	}
+	var req PodcastSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.apply(c, feed) {
+		return
+	}
+	if err := database.DB.Select("auto_transcribe", "job_template_id", "priority", "paused").Updates(feed).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update podcast"})
+		return
+	}
+	c.JSON(http.StatusOK, feed)
+}
+
+// UnsubscribePodcast deletes a podcast subscription
+// @Summary Unsubscribe from a podcast
+// @Description Delete a podcast subscription and its episode list. Transcriptions of its episodes are kept.
+// @Tags podcasts
+// @Param id path string true "Podcast ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/podcasts/{id} [delete]
+func (h *Handler) UnsubscribePodcast(c *gin.Context) {
+	feed, ok := loadPodcastFeed(c)
+	if !ok {
+		return
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("feed_id = ?", feed.ID).Delete(&models.PodcastEpisode{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(feed).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unsubscribe"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Unsubscribed"})
+}
+
+// RefreshPodcastFeed polls a podcast feed now
+// @Summary Check a podcast for new episodes
+// @Description Poll a podcast's feed now instead of waiting for PODCAST_POLL_MINUTES, also when the feed is paused. New episodes are transcribed if the feed transcribes automatically.
+// @Tags podcasts
+// @Produce json
+// @Param id path string true "Podcast ID"
+// @Success 200 {object} PodcastPollResponse
+// @Failure 404 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/podcasts/{id}/refresh [post]
+func (h *Handler) RefreshPodcastFeed(c *gin.Context) {
+	if !h.requirePodcasts(c) {
+		return
+	}
+	feed, ok := loadPodcastFeed(c)
+	if !ok {
+		return
+	}
+	result, err := h.podcasts.Poll(c.Request.Context(), feed, 0)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to read feed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, PodcastPollResponse{Feed: *feed, Poll: result})
+}
+
+// ListPodcastEpisodes pages through a podcast's episodes
+// @Summary List a podcast's episodes
+// @Description Page through a podcast's episodes, newest first, with the state of each one's transcription: available (not transcribed), downloading, failed (with the error), or the status of its transcription along with the transcription_id.
+// @Tags podcasts
+// @Produce json
+// @Param id path string true "Podcast ID"
+// @Param q query string false "Only episodes whose title contains this"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Episodes per page" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/podcasts/{id}/episodes [get]
+func (h *Handler) ListPodcastEpisodes(c *gin.Context) {
+	feed, ok := loadPodcastFeed(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 1000 {
+		limit = 50
+	}
+
+	query := database.DB.Model(&models.PodcastEpisode{}).Where("feed_id = ?", feed.ID)
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		query = query.Where("LOWER(title) LIKE ?", "%"+strings.ToLower(q)+"%")
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count episodes"})
+		return
+	}
+	var episodes []models.PodcastEpisode
+	if err := query.Order("published_at IS NULL, published_at DESC, created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&episodes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list episodes"})
+		return
+	}
+	responses, err := episodeResponses(episodes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get episode transcriptions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"episodes": responses,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// TranscribePodcastEpisode downloads and transcribes one episode of a podcast
+// @Summary Transcribe a podcast episode
+// @Description Download and transcribe an episode that wasn't transcribed automatically, such as an older one or one of a feed without auto_transcribe, or retry one whose download failed. The feed's job template and priority are used.
+// @Tags podcasts
+// @Produce json
+// @Param id path string true "Podcast ID"
+// @Param episode_id path string true "Episode ID"
+// @Success 202 {object} PodcastEpisodeResponse
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/podcasts/{id}/episodes/{episode_id}/transcribe [post]
+func (h *Handler) TranscribePodcastEpisode(c *gin.Context) {
+	feed, ok := loadPodcastFeed(c)
+	if !ok {
+		return
+	}
+	var episode models.PodcastEpisode
+	if err := database.DB.Where("id = ? AND feed_id = ?", c.Param("episode_id"), feed.ID).First(&episode).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Episode not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get episode"})
+		}
+		return
+	}
+	current, err := episodeResponses([]models.PodcastEpisode{episode})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get episode transcription"})
+		return
+	}
+	if current[0].TranscriptionID != nil || current[0].Status == episodeDownloading {
+		c.JSON(http.StatusConflict, gin.H{"error": "Episode is already being transcribed or was transcribed", "status": current[0].Status, "transcription_id": current[0].TranscriptionID})
+		return
+	}
+
+	importID, err := h.importEpisode(feed, &episode)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start download"})
+		return
+	}
+	episode.ImportID = &importID
+	if err := database.DB.Model(&episode).Update("import_id", importID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save episode"})
+		return
+	}
+	c.JSON(http.StatusAccepted, PodcastEpisodeResponse{PodcastEpisode: episode, Status: episodeDownloading})
+}
@@ -0,0 +1,551 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/projects"
+	"scriberr/internal/tagging"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ProjectRequest represents a request to create or update a project
+type ProjectRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	Description *string `json:"description,omitempty"`
+	// ParentID is the project to create the project in; empty creates it at the top level.
+	// It is ignored on update: projects are moved with POST /projects/{id}/move.
+	ParentID *string `json:"parent_id,omitempty"`
+	models.ProjectSettings
+}
+
+// MoveProjectRequest moves a project into another one, or to the top level without parent_id
+type MoveProjectRequest struct {
+	ParentID *string `json:"parent_id"`
+}
+
+// FileTranscriptionsRequest files transcriptions in a project
+type FileTranscriptionsRequest struct {
+	TranscriptionIDs []string `json:"transcription_ids" binding:"required"`
+}
+
+// TranscriptionProjectRequest files a transcription in a project, or takes it out of its
+// project without project_id
+type TranscriptionProjectRequest struct {
+	ProjectID *string `json:"project_id"`
+}
+
+// ProjectNode is a project in the project tree, with the projects in it
+type ProjectNode struct {
+	models.Project
+	TranscriptionCount int64          `json:"transcription_count"` // Transcriptions filed directly in the project
+	Children           []*ProjectNode `json:"children"`
+}
+
+// maxFiledTranscriptions caps the transcriptions filed in a project by one request
+const maxFiledTranscriptions = 500
+
+// loadProject loads a project owned by the caller, writing an error response if it can't.
+// status is the response status when there is no such project.
+func loadProject(c *gin.Context, projectID string, status int) (*models.Project, bool) {
+	var project models.Project
+	if err := scopeToOwner(database.DB, currentUserID(c)).Where("id = ?", projectID).First(&project).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(status, gin.H{"error": "Project not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get project"})
+		}
+		return nil, false
+	}
+	return &project, true
+}
+
+// checkProjectName writes an error response if another of the parent's projects has the name
+func checkProjectName(c *gin.Context, name string, parentID *string, excludeID string) bool {
+	query := scopeToOwner(database.DB.Model(&models.Project{}), currentUserID(c)).
+		Where("LOWER(name) = LOWER(?) AND id != ?", name, excludeID)
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check project name"})
+		return false
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("A project named %q already exists here", name)})
+		return false
+	}
+	return true
+}
+
+// bindProjectRequest parses and validates a project request, writing an error response if
+// it is invalid
+func bindProjectRequest(c *gin.Context) (*ProjectRequest, bool) {
+	var req ProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return nil, false
+	}
+	if req.ParentID != nil && *req.ParentID == "" {
+		req.ParentID = nil
+	}
+
+	if req.SummaryTemplateID != nil && *req.SummaryTemplateID == "" {
+		req.SummaryTemplateID = nil
+	}
+	if req.SummaryTemplateID != nil {
+		if _, ok := loadSummaryTemplate(c, *req.SummaryTemplateID); !ok {
+			return nil, false
+		}
+	}
+	req.ContentType = strings.TrimSpace(req.ContentType)
+	if req.ContentType != "" && !models.IsRecordingContentType(req.ContentType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": contentTypeError})
+		return nil, false
+	}
+	req.Tags = tagging.Normalize(req.Tags)
+	if req.Tags == nil {
+		req.Tags = []string{}
+	}
+	webhooks, ok := webhookURLs(c, req.WebhookURLs)
+	if !ok {
+		return nil, false
+	}
+	req.WebhookURLs = webhooks
+	if (req.AudioRetentionDays != nil && *req.AudioRetentionDays < 0) || (req.RetentionDays != nil && *req.RetentionDays < 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "audio_retention_days and retention_days can't be negative"})
+		return nil, false
+	}
+	return &req, true
+}
+
+// projectFromForm loads the caller's project named by the project_id form field, if any, with
+// the settings it gives new recordings, writing an error response if there is no such project
+func projectFromForm(c *gin.Context) (*formProject, bool) {
+	id := strings.TrimSpace(c.PostForm("project_id"))
+	if id == "" {
+		return nil, true
+	}
+	project, ok := loadProject(c, id, http.StatusBadRequest)
+	if !ok {
+		return nil, false
+	}
+	settings, err := projects.Settings(project.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get project settings"})
+		return nil, false
+	}
+	return &formProject{ID: project.ID, Settings: settings}, true
+}
+
+// formProject is the project an upload is filed in
+type formProject struct {
+	ID       string
+	Settings models.ProjectSettings
+}
+
+// apply files a new job in the project, giving it the project's content type unless the
+// upload has its own. It does nothing without a project.
+func (p *formProject) apply(c *gin.Context, job *models.TranscriptionJob) {
+	if p == nil {
+		return
+	}
+	job.ProjectID = &p.ID
+	if p.Settings.ContentType != "" && strings.TrimSpace(c.PostForm("content_type")) == "" {
+		job.ContentType = p.Settings.ContentType
+	}
+}
+
+// tag adds the project's tags to a new job, along with any others it is given
+func (p *formProject) tag(job *models.TranscriptionJob, tags []string) {
+	if p != nil {
+		tags = append(append([]string{}, tags...), p.Settings.Tags...)
+	}
+	if len(tags) == 0 {
+		return
+	}
+	if _, err := tagging.SetJobTags(job, tags); err != nil {
+		logger.Warn("Failed to tag job", "job_id", job.ID, "error", err)
+	}
+}
+
+// ListProjects returns the caller's projects as a tree
+// @Summary List projects
+// @Description List the caller's projects as a tree: the top-level projects, each with the projects in it under children and the number of transcriptions filed directly in it, ordered by name
+// @Tags projects
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/projects [get]
+func (h *Handler) ListProjects(c *gin.Context) {
+	var all []models.Project
+	if err := scopeToOwner(database.DB, currentUserID(c)).Order("LOWER(name) ASC").Find(&all).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list projects"})
+		return
+	}
+
+	var counts []struct {
+		ProjectID string
+		Count     int64
+	}
+	ids := make([]string, len(all))
+	for i, project := range all {
+		ids[i] = project.ID
+	}
+	if len(ids) > 0 {
+		if err := database.DB.Model(&models.TranscriptionJob{}).
+			Select("project_id, COUNT(*) AS count").
+			Where("project_id IN ? AND id NOT LIKE 'track_%'", ids).
+			Group("project_id").Scan(&counts).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count transcriptions"})
+			return
+		}
+	}
+
+	nodes := make(map[string]*ProjectNode, len(all))
+	for _, project := range all {
+		nodes[project.ID] = &ProjectNode{Project: project, Children: []*ProjectNode{}}
+	}
+	for _, count := range counts {
+		nodes[count.ProjectID].TranscriptionCount = count.Count
+	}
+	roots := []*ProjectNode{}
+	for _, project := range all {
+		node := nodes[project.ID]
+		if project.ParentID != nil && nodes[*project.ParentID] != nil {
+			parent := nodes[*project.ParentID]
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"projects": roots})
+}
+
+// CreateProject creates a project
+// @Summary Create a project
+// @Description Create a project, a folder to file transcriptions in, at the top level or in the project parent_id. Its settings are given to the transcriptions filed in it: summaries are written with summary_template_id unless the transcription names a template, webhook_urls are notified when post-processing completes, and audio_retention_days and retention_days replace the retention cleanup's own rules (0 keeps audio or transcriptions forever). Recordings uploaded with its project_id also get its content_type, unless the upload gives one, and its tags. A project inherits each setting it leaves empty from the project it is in.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param request body ProjectRequest true "Project"
+// @Success 201 {object} models.Project
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/projects [post]
+func (h *Handler) CreateProject(c *gin.Context) {
+	req, ok := bindProjectRequest(c)
+	if !ok {
+		return
+	}
+	if req.ParentID != nil {
+		if _, ok := loadProject(c, *req.ParentID, http.StatusBadRequest); !ok {
+			return
+		}
+	}
+	if !checkProjectName(c, req.Name, req.ParentID, "") {
+		return
+	}
+
+	project := models.Project{
+		UserID:          currentUserID(c),
+		ParentID:        req.ParentID,
+		Name:            req.Name,
+		Description:     req.Description,
+		ProjectSettings: req.ProjectSettings,
+	}
+	if err := database.DB.Create(&project).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create project"})
+		return
+	}
+	c.JSON(http.StatusCreated, project)
+}
+
+// GetProject returns a project with its path and the settings it gives its transcriptions
+// @Summary Get a project
+// @Description Get a project, the projects it is in from the top level down under path, and under settings the settings its transcriptions get, including those it inherits
+// @Tags projects
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/projects/{id} [get]
+func (h *Handler) GetProject(c *gin.Context) {
+	project, ok := loadProject(c, c.Param("id"), http.StatusNotFound)
+	if !ok {
+		return
+	}
+	path, err := projects.Path(project.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get project path"})
+		return
+	}
+	settings, err := projects.Settings(project.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get project settings"})
+		return
+	}
+	var count int64
+	if err := database.DB.Model(&models.TranscriptionJob{}).Where("project_id = ? AND id NOT LIKE 'track_%'", project.ID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count transcriptions"})
+		return
+	}
+
+	// The path runs from the top level down to the project's parent
+	parents := []gin.H{}
+	for i := len(path) - 1; i > 0; i-- {
+		parents = append(parents, gin.H{"id": path[i].ID, "name": path[i].Name})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"project":             project,
+		"path":                parents,
+		"settings":            settings,
+		"transcription_count": count,
+	})
+}
+
+// UpdateProject changes a project's name, description and settings
+// @Summary Update a project
+// @Description Replace a project's name, description and settings. Settings apply to the post-processing of its transcriptions from then on; content types and tags already given to uploads are kept.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param request body ProjectRequest true "Project"
+// @Success 200 {object} models.Project
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/projects/{id} [put]
+func (h *Handler) UpdateProject(c *gin.Context) {
+	project, ok := loadProject(c, c.Param("id"), http.StatusNotFound)
+	if !ok {
+		return
+	}
+	req, ok := bindProjectRequest(c)
+	if !ok {
+		return
+	}
+	if !checkProjectName(c, req.Name, project.ParentID, project.ID) {
+		return
+	}
+
+	project.Name = req.Name
+	project.Description = req.Description
+	project.ProjectSettings = req.ProjectSettings
+	if err := database.DB.Save(project).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update project"})
+		return
+	}
+	c.JSON(http.StatusOK, project)
+}
+
+// MoveProject moves a project, with everything in it, into another project or to the top level
+// @Summary Move a project
+// @Description Move a project, with its subprojects and transcriptions, into the project parent_id, or to the top level without it. A project can't be moved into itself or one of its own subprojects.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param request body MoveProjectRequest true "New parent"
+// @Success 200 {object} models.Project
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/projects/{id}/move [post]
+func (h *Handler) MoveProject(c *gin.Context) {
+	project, ok := loadProject(c, c.Param("id"), http.StatusNotFound)
+	if !ok {
+		return
+	}
+	var req MoveProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ParentID != nil && *req.ParentID == "" {
+		req.ParentID = nil
+	}
+	if req.ParentID != nil {
+		parent, ok := loadProject(c, *req.ParentID, http.StatusBadRequest)
+		if !ok {
+			return
+		}
+		inside, err := projects.Contains(project.ID, parent.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check project hierarchy"})
+			return
+		}
+		if inside {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A project can't be moved into itself or one of its subprojects"})
+			return
+		}
+	}
+	if !checkProjectName(c, project.Name, req.ParentID, project.ID) {
+		return
+	}
+
+	project.ParentID = req.ParentID
+	if err := database.DB.Model(project).Update("parent_id", req.ParentID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move project"})
+		return
+	}
+	c.JSON(http.StatusOK, project)
+}
+
+// DeleteProject deletes a project, moving what was in it to its parent
+// @Summary Delete a project
+// @Description Delete a project. Its subprojects and transcriptions are not deleted but moved to the project it was in, or to the top level.
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/projects/{id} [delete]
+func (h *Handler) DeleteProject(c *gin.Context) {
+	project, ok := loadProject(c, c.Param("id"), http.StatusNotFound)
+	if !ok {
+		return
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Project{}).Where("parent_id = ?", project.ID).Update("parent_id", project.ParentID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.TranscriptionJob{}).Where("project_id = ?", project.ID).Update("project_id", project.ParentID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(project).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete project"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Project deleted successfully"})
+}
+
+// FileTranscriptions files transcriptions in a project
+// @Summary File transcriptions in a project
+// @Description Move the caller's transcriptions into a project, out of whichever project they were in. Their summaries and notifications follow the project's settings from then on; their tags and content type are kept.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param request body FileTranscriptionsRequest true "Transcriptions"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/projects/{id}/transcriptions [post]
+func (h *Handler) FileTranscriptions(c *gin.Context) {
+	project, ok := loadProject(c, c.Param("id"), http.StatusNotFound)
+	if !ok {
+		return
+	}
+	var req FileTranscriptionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, id := range req.TranscriptionIDs {
+		if id = strings.TrimSpace(id); id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 || len(ids) > maxFiledTranscriptions {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("transcription_ids must name between 1 and %d transcriptions", maxFiledTranscriptions)})
+		return
+	}
+
+	var found []string
+	if err := scopeToOwner(database.DB.Model(&models.TranscriptionJob{}), currentUserID(c)).
+		Where("id IN ? AND id NOT LIKE 'track_%'", ids).Pluck("id", &found).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transcriptions"})
+		return
+	}
+	if len(found) != len(ids) {
+		present := make(map[string]bool, len(found))
+		for _, id := range found {
+			present[id] = true
+		}
+		for _, id := range ids {
+			if !present[id] {
+				c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Transcription %s not found", id)})
+				return
+			}
+		}
+	}
+	if err := database.DB.Model(&models.TranscriptionJob{}).Where("id IN ?", ids).Update("project_id", project.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to file transcriptions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"project_id": project.ID, "filed": len(ids)})
+}
+
+// UpdateTranscriptionProject files a transcription in a project or takes it out of one
+// @Summary Move a transcription to a project
+// @Description File a transcription in the project project_id, or take it out of its project without it. Its summaries and notifications follow the project's settings from then on; its tags and content type are kept.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param request body TranscriptionProjectRequest true "Project"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/project [put]
+func (h *Handler) UpdateTranscriptionProject(c *gin.Context) {
+	var req TranscriptionProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	if req.ProjectID != nil && *req.ProjectID == "" {
+		req.ProjectID = nil
+	}
+	if req.ProjectID != nil {
+		if _, ok := loadProject(c, *req.ProjectID, http.StatusBadRequest); !ok {
+			return
+		}
+	}
+	if err := database.DB.Model(job).Update("project_id", req.ProjectID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move transcription"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": job.ID, "project_id": req.ProjectID})
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// InitialPromptRequest carries the context used to bias recognition for a job
+type InitialPromptRequest struct {
+	InitialPrompt string `json:"initial_prompt"`
+	Participants  string `json:"participants"`
+	Agenda        string `json:"agenda"`
+	Glossary      string `json:"glossary"`
+}
+
+// Compose builds the prompt text passed to the transcription engine.
+// Participants and glossary terms are written as plain sentences so the model
+// picks up their spelling; free-form prompt text is appended last.
+func (r InitialPromptRequest) Compose() string {
+	var parts []string
+	if v := joinPromptTerms(r.Participants); v != "" {
+		parts = append(parts, "Participants: "+v+".")
+	}
+	if v := strings.TrimSpace(r.Agenda); v != "" {
+		parts = append(parts, "Agenda: "+strings.TrimSuffix(v, ".")+".")
+	}
+	if v := joinPromptTerms(r.Glossary); v != "" {
+		parts = append(parts, "Glossary: "+v+".")
+	}
+	if v := strings.TrimSpace(r.InitialPrompt); v != "" {
+		parts = append(parts, v)
+	}
+	return strings.Join(parts, " ")
+}
+
+// joinPromptTerms normalizes a comma or newline separated list into "a, b, c"
+func joinPromptTerms(list string) string {
+	fields := strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == '\n' || r == ';' })
+	terms := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if t := strings.TrimSpace(f); t != "" {
+			terms = append(terms, t)
+		}
+	}
+	return strings.Join(terms, ", ")
+}
+
+// initialPromptFromForm reads the initial prompt fields of a multipart upload
+func initialPromptFromForm(c *gin.Context) string {
+	return InitialPromptRequest{
+		InitialPrompt: c.PostForm("initial_prompt"),
+		Participants:  c.PostForm("participants"),
+		Agenda:        c.PostForm("agenda"),
+		Glossary:      c.PostForm("glossary"),
+	}.Compose()
+}
+
+// mergeInitialPrompt appends job-specific prompt text to a profile's prompt
+func mergeInitialPrompt(base *string, jobPrompt string) *string {
+	if jobPrompt == "" {
+		return base
+	}
+	if base == nil || strings.TrimSpace(*base) == "" {
+		return &jobPrompt
+	}
+	merged := strings.TrimSpace(*base) + " " + jobPrompt
+	return &merged
+}
+
+// UpdateInitialPrompt sets the prompt used to bias recognition on the next transcription run
+// @Summary Update transcription initial prompt
+// @Description Set the initial prompt (participant names, agenda, glossary terms) used when the job is next transcribed. Not allowed while the job is pending or processing.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body InitialPromptRequest true "Initial prompt"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/transcription/{id}/initial-prompt [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UpdateInitialPrompt(c *gin.Context) {
+	jobID := c.Param("id")
+
+	var req InitialPromptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var job models.TranscriptionJob
+	if err := database.DB.Where("id = ?", jobID).First(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		return
+	}
+
+	if job.Status == models.StatusPending || job.Status == models.StatusProcessing {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot change the initial prompt while the job is pending or processing"})
+		return
+	}
+
+	// An empty prompt clears it
+	var prompt *string
+	if composed := req.Compose(); composed != "" {
+		prompt = &composed
+	}
+
+	if err := database.DB.Model(&job).Update("initial_prompt", prompt).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update initial prompt"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":             job.ID,
+		"initial_prompt": prompt,
+	})
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"scriberr/internal/audio"
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/transcription"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// qualityCheckTimeout bounds how long a single audio quality analysis may run
+const qualityCheckTimeout = 5 * time.Minute
+
+// checkAudioQuality analyzes a freshly uploaded file in the background and stores the report on the job
+func checkAudioQuality(jobID, audioPath string) {
+	ctx, cancel := context.WithTimeout(context.Background(), qualityCheckTimeout)
+	defer cancel()
+
+	if _, err := transcription.AnalyzeJobAudioQuality(ctx, jobID, audioPath); err != nil {
+		logger.Warn("Audio quality check failed", "job_id", jobID, "error", err)
+	}
+}
+
+// GetAudioQuality returns the audio quality report for a job
+// @Summary Get audio quality report
+// @Description Get the upload-time audio quality analysis (SNR estimate, clipping, bitrate) and any warnings. Pass refresh=true to re-run the analysis.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param refresh query bool false "Re-run the analysis"
+// @Success 200 {object} audio.QualityReport
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/quality [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetAudioQuality(c *gin.Context) {
+	jobID := c.Param("id")
+
+	var job models.TranscriptionJob
+	if err := database.DB.Where("id = ?", jobID).First(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		return
+	}
+
+	if job.AudioQuality != nil && c.Query("refresh") != "true" {
+		var report audio.QualityReport
+		if err := json.Unmarshal([]byte(*job.AudioQuality), &report); err == nil {
+			c.JSON(http.StatusOK, report)
+			return
+		}
+	}
+
+	audioPath := job.AudioPath
+	if job.IsMultiTrack && job.MergedAudioPath != nil {
+		audioPath = *job.MergedAudioPath
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), qualityCheckTimeout)
+	defer cancel()
+
+	report, err := transcription.AnalyzeJobAudioQuality(ctx, job.ID, audioPath)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to analyze audio quality: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
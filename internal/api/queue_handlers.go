@@ -0,0 +1,251 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+
+	"github.com/gin-gonic/gin"
+)
+
+// priorityError is returned for a priority out of range
+var priorityError = fmt.Sprintf("priority must be between %d and %d", queue.MinPriority, queue.MaxPriority)
+
+// priorityFromForm reads the queue priority of an upload, 0 unless given, writing an error
+// response if it is out of range
+func priorityFromForm(c *gin.Context) (int, bool) {
+	return parsePriority(c, c.PostForm("priority"), 0)
+}
+
+// parsePriority parses a queue priority, fallback when raw is empty, writing an error response
+// if it is out of range
+func parsePriority(c *gin.Context, raw string, fallback int) (int, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return fallback, true
+	}
+	priority, err := strconv.Atoi(raw)
+	if err != nil || priority < queue.MinPriority || priority > queue.MaxPriority {
+		c.JSON(http.StatusBadRequest, gin.H{"error": priorityError})
+		return 0, false
+	}
+	return priority, true
+}
+
+// QueueEntry is a transcription waiting in the queue
+type QueueEntry struct {
+	queue.QueuedJob
+	Title     *string    `json:"title,omitempty"`
+	UserID    *uint      `json:"user_id,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// ListQueue returns the transcriptions waiting for a worker, next first
+// @Summary List the transcription queue
+// @Description List the transcriptions waiting for a worker in the order they will run, with each one's position (1 is next) and priority, along with whether the queue is paused and the queue statistics. Jobs run by priority, highest first, and by submission within a priority.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/queue [get]
+func (h *Handler) ListQueue(c *gin.Context) {
+	queued := h.taskQueue.Queued()
+	ids := make([]string, len(queued))
+	for i, job := range queued {
+		ids[i] = job.ID
+	}
+	var jobs []models.TranscriptionJob
+	if len(ids) > 0 {
+		database.DB.Select("id", "title", "user_id", "created_at").Where("id IN ?", ids).Find(&jobs)
+	}
+	byID := make(map[string]models.TranscriptionJob, len(jobs))
+	for _, job := range jobs {
+		byID[job.ID] = job
+	}
+
+	entries := make([]QueueEntry, len(queued))
+	for i, queuedJob := range queued {
+		entries[i] = QueueEntry{QueuedJob: queuedJob}
+		if job, ok := byID[queuedJob.ID]; ok {
+			entries[i].Title = job.Title
+			entries[i].UserID = job.UserID
+			entries[i].CreatedAt = &job.CreatedAt
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":   entries,
+		"paused": h.taskQueue.IsPaused(),
+		"stats":  h.taskQueue.GetQueueStats(),
+	})
+}
+
+// PauseQueue stops workers from starting queued transcriptions
+// @Summary Pause the transcription queue
+// @Description Stop workers from starting queued transcriptions. Transcriptions already running finish, and new ones can still be queued. The queue stays paused until resumed or the server restarts.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/queue/pause [post]
+func (h *Handler) PauseQueue(c *gin.Context) {
+	h.taskQueue.Pause()
+	c.JSON(http.StatusOK, gin.H{"paused": true})
+}
+
+// ResumeQueue lets workers start queued transcriptions again
+// @Summary Resume the transcription queue
+// @Description Let workers start queued transcriptions again after a pause
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/queue/resume [post]
+func (h *Handler) ResumeQueue(c *gin.Context) {
+	h.taskQueue.Resume()
+	c.JSON(http.StatusOK, gin.H{"paused": false})
+}
+
+// QueueConcurrencyRequest sets how many transcriptions run at once
+type QueueConcurrencyRequest struct {
+	Workers int `json:"workers" binding:"required"` // 1 to 32
+}
+
+// SetQueueConcurrency changes how many transcriptions run at once
+// @Summary Set queue concurrency
+// @Description Set how many transcriptions run at once, turning off auto-scaling until the server restarts (QUEUE_WORKERS sets it at startup). Lowering it lets running transcriptions finish first.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body QueueConcurrencyRequest true "Worker count"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/queue/concurrency [put]
+func (h *Handler) SetQueueConcurrency(c *gin.Context) {
+	var req QueueConcurrencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.taskQueue.SetConcurrency(req.Workers); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, h.taskQueue.GetQueueStats())
+}
+
+// QueueMoveRequest puts a queued transcription at a place in the queue
+type QueueMoveRequest struct {
+	Position int `json:"position" binding:"required,min=1"` // 1 runs next
+}
+
+// MoveQueuedJob puts a queued transcription at another place in the queue
+// @Summary Reorder the transcription queue
+// @Description Move a queued transcription to a position in the queue, 1 being next; positions past the end move it to the end. It takes the priority of the transcription it lands in front of, so it keeps its place as new transcriptions are queued.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body QueueMoveRequest true "New position"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/queue/{id}/move [post]
+func (h *Handler) MoveQueuedJob(c *gin.Context) {
+	var req QueueMoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	moved, err := h.taskQueue.Move(c.Param("id"), req.Position)
+	if errors.Is(err, queue.ErrNotQueued) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Transcription is not waiting in the queue"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, moved)
+}
+
+// PriorityRequest sets the queue priority of a transcription
+type PriorityRequest struct {
+	Priority *int `json:"priority" binding:"required"` // -10 to 10, higher runs first
+}
+
+// UpdateTranscriptionPriority changes when a transcription runs
+// @Summary Update transcription priority
+// @Description Set a transcription's queue priority, from -10 to 10; higher runs first and the default is 0. A queued transcription moves to its new place right away, behind others of the same priority queued before it.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body PriorityRequest true "Priority"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/priority [put]
+func (h *Handler) UpdateTranscriptionPriority(c *gin.Context) {
+	var req PriorityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if *req.Priority < queue.MinPriority || *req.Priority > queue.MaxPriority {
+		c.JSON(http.StatusBadRequest, gin.H{"error": priorityError})
+		return
+	}
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	if err := h.taskQueue.SetPriority(job.ID, *req.Priority); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": job.ID, "priority": *req.Priority, "position": h.taskQueue.Position(job.ID)})
+}
+
+// GetQueuePosition reports where a transcription is in the queue
+// @Summary Get queue position
+// @Description Get a transcription's status, priority and, while it waits, its position in the queue (1 is next) out of the transcriptions queued. Position is 0 when it isn't waiting.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/queue [get]
+func (h *Handler) GetQueuePosition(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"id":       job.ID,
+		"status":   job.Status,
+		"priority": job.Priority,
+		"position": h.taskQueue.Position(job.ID),
+		"queued":   len(h.taskQueue.Queued()),
+		"paused":   h.taskQueue.IsPaused(),
+	})
+}
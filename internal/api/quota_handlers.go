@@ -0,0 +1,218 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/auth"
+	"scriberr/internal/config"
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/quota"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newQuotaService builds the rate limiter and quota checks from the configured defaults
+func newQuotaService(cfg *config.Config) *quota.Service {
+	if cfg == nil {
+		return quota.NewService(quota.Limits{}, 0)
+	}
+	return quota.NewService(quota.Limits{
+		RequestsPerMinute: cfg.RateLimitPerMinute,
+		AudioMinutes:      cfg.QuotaAudioMinutes,
+		LLMCalls:          cfg.QuotaLLMCalls,
+	}, cfg.RateLimitAnonymousPerMinute)
+}
+
+// limitedUser loads the limits set for a user; it returns nil for a nil ID or a user that
+// doesn't exist
+func limitedUser(userID *uint) (*models.User, error) {
+	if userID == nil {
+		return nil, nil
+	}
+	var user models.User
+	result := database.DB.Select("id", "rate_limit_per_minute", "quota_audio_minutes", "quota_llm_calls").
+		Where("id = ?", *userID).Limit(1).Find(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+// rateBucket is a rate limit a request is counted against
+type rateBucket struct {
+	subject   string
+	perMinute int
+}
+
+// rateLimit turns away requests over their caller's rate limit with 429 and a Retry-After.
+// It runs before authentication, so it identifies the caller from their credentials itself.
+// Requests without valid ones count against their IP address, which keeps logins and shared
+// link passwords from being guessed at speed; authentication rejects them afterwards.
+func (h *Handler) rateLimit(authService *auth.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, bucket := range h.rateBuckets(c, authService) {
+			allowed, wait := h.quotas.Allow(bucket.subject, bucket.perMinute)
+			if allowed {
+				continue
+			}
+			retryAfter := int(math.Max(math.Ceil(wait.Seconds()), 1))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":                 "Rate limit exceeded",
+				"rate_limit_per_minute": bucket.perMinute,
+				"retry_after":           retryAfter,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateBuckets returns the rate limits a request counts against: its user's, and its API
+// key's if the key has a limit of its own. API keys without an owner count against their own,
+// and requests without valid credentials against their IP address's.
+func (h *Handler) rateBuckets(c *gin.Context, authService *auth.AuthService) []rateBucket {
+	apiKey := c.GetHeader("X-API-Key")
+	token := ""
+	if parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2); len(parts) == 2 && parts[0] == "Bearer" {
+		token = parts[1]
+	}
+	// WebSocket clients pass their credentials in the query, as QueryTokenAuthMiddleware takes
+	// them. The query is read uncached, since signed downloads replace it after this runs.
+	if query := c.Request.URL.Query().Get("token"); query != "" && apiKey == "" && token == "" {
+		if _, err := authService.ValidateToken(query); err == nil {
+			token = query
+		} else {
+			apiKey = query
+		}
+	}
+
+	if apiKey != "" {
+		var key models.APIKey
+		result := database.DB.Select("id", "user_id", "rate_limit_per_minute").
+			Where("key = ? AND is_active = ? AND (expires_at IS NULL OR expires_at > ?)", apiKey, true, time.Now()).
+			Limit(1).Find(&key)
+		if result.Error == nil && result.RowsAffected > 0 {
+			var buckets []rateBucket
+			if key.RateLimitPerMinute != nil {
+				buckets = append(buckets, rateBucket{fmt.Sprintf("key:%d", key.ID), *key.RateLimitPerMinute})
+			}
+			if key.UserID == nil {
+				if key.RateLimitPerMinute == nil {
+					buckets = append(buckets, rateBucket{fmt.Sprintf("key:%d", key.ID), h.quotas.DefaultRateLimit()})
+				}
+				return buckets
+			}
+			return append(buckets, h.userRateBucket(*key.UserID)...)
+		}
+	}
+	if token != "" {
+		if claims, err := authService.ValidateToken(token); err == nil {
+			return h.userRateBucket(claims.UserID)
+		}
+	}
+	return []rateBucket{{"ip:" + c.ClientIP(), h.quotas.AnonymousRateLimit()}}
+}
+
+// userRateBucket returns the rate limit of a user, if they exist
+func (h *Handler) userRateBucket(userID uint) []rateBucket {
+	user, err := limitedUser(&userID)
+	if err != nil || user == nil {
+		return nil
+	}
+	return []rateBucket{{fmt.Sprintf("user:%d", userID), h.quotas.LimitsFor(user).RequestsPerMinute}}
+}
+
+// requireQuota turns away requests from users who have used up their monthly quota of metric,
+// with 429 and a Retry-After of when the quota starts over. LLM requests that succeed are
+// counted against the quota. It must run after authentication; API keys without an owner
+// aren't metered.
+func (h *Handler) requireQuota(metric string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := limitedUser(currentUserID(c))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check quota"})
+			return
+		}
+		if user == nil {
+			c.Next()
+			return
+		}
+
+		if err := h.quotas.Check(user, metric); err != nil {
+			var qerr *quota.Error
+			if !errors.As(err, &qerr) {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check quota"})
+				return
+			}
+			retryAfter := int(math.Max(math.Ceil(time.Until(qerr.ResetsAt).Seconds()), 1))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":     qerr.Error(),
+				"metric":    qerr.Metric,
+				"used":      qerr.Used,
+				"limit":     qerr.Limit,
+				"resets_at": qerr.ResetsAt,
+			})
+			return
+		}
+
+		c.Next()
+
+		if metric == models.QuotaLLMCalls && c.Writer.Status() < http.StatusBadRequest {
+			if err := quota.Add(user.ID, metric, 1); err != nil {
+				logger.Error("Failed to record LLM request against quota", "user_id", user.ID, "error", err)
+			}
+		}
+	}
+}
+
+// GetQuotaStatus reports the caller's rate limit and use of their monthly quotas
+// @Summary Get rate limit and quota status
+// @Description Report the caller's rate limit and, for this calendar month (UTC), how many minutes of audio they have had transcribed and LLM requests they have made against their quotas. Limits are the instance defaults (RATE_LIMIT_PER_MINUTE, QUOTA_AUDIO_MINUTES_PER_MONTH, QUOTA_LLM_CALLS_PER_MONTH) unless an admin set the user's own; an API key with a rate limit of its own reports that one. A limit of 0 is unlimited. Requests over a limit get 429 with a Retry-After header.
+// @Tags usage
+// @Produce json
+// @Success 200 {object} quota.Status
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/usage/quota [get]
+func (h *Handler) GetQuotaStatus(c *gin.Context) {
+	user, err := limitedUser(currentUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load limits"})
+		return
+	}
+
+	var rateLimit *int
+	if apiKey := c.GetString("api_key"); apiKey != "" {
+		var key models.APIKey
+		if err := database.DB.Select("id", "user_id", "rate_limit_per_minute").Where("key = ?", apiKey).Limit(1).Find(&key).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load limits"})
+			return
+		}
+		rateLimit = key.RateLimitPerMinute
+		if rateLimit == nil && key.UserID == nil {
+			defaultLimit := h.quotas.DefaultRateLimit()
+			rateLimit = &defaultLimit
+		}
+	}
+
+	status, err := h.quotas.Status(user, rateLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load quota usage"})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -8,17 +9,21 @@ import (
 
 	"scriberr/internal/database"
 	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/tracing"
 	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
 
 	"github.com/gin-gonic/gin"
 )
 
 // BackfillRAG processes all completed transcriptions and stores them in RAG
 // @Summary Backfill RAG with existing transcriptions
-// @Description Process all completed transcriptions and store them in the RAG system
+// @Description Process all completed transcriptions and store them in the RAG system. Admins only.
 // @Tags rag
 // @Produce json
 // @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
 // @Security BearerAuth
@@ -47,26 +52,7 @@ func (h *Handler) BackfillRAG(c *gin.Context) {
 			continue
 		}
 
-		// Extract text from JSON transcript
-		transcriptText, err := extractTextFromTranscript(*job.Transcript)
-		if err != nil {
-			// Fallback: use raw transcript if JSON parsing fails
-			transcriptText = *job.Transcript
-		}
-
-		if strings.TrimSpace(transcriptText) == "" {
-			failed++
-			continue
-		}
-
-		// Get summary if available
-		summary := ""
-		if job.Summary != nil {
-			summary = *job.Summary
-		}
-
-		// Store in RAG
-		if err := h.ragService.StoreSummary(job.ID, summary, transcriptText); err != nil {
+		if err := h.storeJobInRAG(&job); err != nil {
 			failed++
 			continue
 		}
@@ -81,6 +67,205 @@ func (h *Handler) BackfillRAG(c *gin.Context) {
 	})
 }
 
+// RepairRAGGaps backfills only the completed transcriptions that are missing from the vector store
+// @Summary Repair RAG index gaps
+// @Description Detect completed transcriptions that have no documents in the vector store and index only those. Admins only.
+// @Tags rag
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/rag/repair [post]
+func (h *Handler) RepairRAGGaps(c *gin.Context) {
+	if h.ragService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "RAG service not initialized"})
+		return
+	}
+
+	missing, err := h.ragService.FindMissing(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to detect index gaps: " + err.Error()})
+		return
+	}
+
+	var jobs []models.TranscriptionJob
+	if len(missing) > 0 {
+		if err := database.DB.Where("id IN ?", missing).Find(&jobs).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transcriptions"})
+			return
+		}
+	}
+
+	repaired := []string{}
+	failedIDs := []string{}
+	for _, job := range jobs {
+		if err := h.storeJobInRAG(&job); err != nil {
+			failedIDs = append(failedIDs, job.ID)
+			continue
+		}
+		repaired = append(repaired, job.ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Repair completed",
+		"missing":      len(missing),
+		"processed":    len(repaired),
+		"failed":       len(failedIDs),
+		"repaired_ids": repaired,
+		"failed_ids":   failedIDs,
+	})
+}
+
+// AuditRAG cross-checks completed transcriptions against the vector store
+// @Summary Audit RAG index consistency
+// @Description Compare completed transcriptions and uploaded documents with the vector store and report missing, orphaned and stale entries. Pass repair=true to re-index missing and stale entries and delete orphaned ones. Admins only.
+// @Tags rag
+// @Produce json
+// @Param repair query bool false "Repair discrepancies after auditing"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/rag/audit [post]
+func (h *Handler) AuditRAG(c *gin.Context) {
+	if h.ragService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "RAG service not initialized"})
+		return
+	}
+
+	report, err := h.ragService.Audit(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to audit RAG index: " + err.Error()})
+		return
+	}
+
+	response := gin.H{
+		"report":     report,
+		"consistent": report.Consistent(),
+	}
+
+	if c.Query("repair") != "true" || report.Consistent() {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	// Re-index missing and stale transcriptions
+	reindexIDs := append(append([]string{}, report.Missing...), report.Stale...)
+	var jobs []models.TranscriptionJob
+	if len(reindexIDs) > 0 {
+		if err := database.DB.Where("id IN ?", reindexIDs).Find(&jobs).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transcriptions"})
+			return
+		}
+	}
+
+	reindexed := []string{}
+	failedIDs := []string{}
+	for _, job := range jobs {
+		if err := h.storeJobInRAG(&job); err != nil {
+			failedIDs = append(failedIDs, job.ID)
+			continue
+		}
+		reindexed = append(reindexed, job.ID)
+	}
+
+	// Re-index uploaded documents whose chunks are missing
+	reindexedDocuments := []string{}
+	if h.documentIngester != nil {
+		for _, id := range report.MissingDocuments {
+			if err := h.documentIngester.Ingest(c.Request.Context(), id); err != nil {
+				failedIDs = append(failedIDs, id)
+				continue
+			}
+			reindexedDocuments = append(reindexedDocuments, id)
+		}
+	}
+
+	// Drop documents that no longer belong to a completed transcription or uploaded document
+	removed := []rag.OrphanedDocument{}
+	for _, orphan := range report.Orphaned {
+		if err := h.ragService.RemoveOrphan(orphan); err != nil {
+			if orphan.DocumentID != "" {
+				failedIDs = append(failedIDs, orphan.DocumentID)
+			} else {
+				failedIDs = append(failedIDs, orphan.TranscriptionID)
+			}
+			continue
+		}
+		removed = append(removed, orphan)
+	}
+
+	response["repair"] = gin.H{
+		"reindexed_ids":          reindexed,
+		"reindexed_document_ids": reindexedDocuments,
+		"removed":                removed,
+		"failed_ids":             failedIDs,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// storeJobInRAG extracts the transcript text of a completed job and stores it (with its summary) in RAG
+func (h *Handler) storeJobInRAG(job *models.TranscriptionJob) error {
+	if job.Transcript == nil || *job.Transcript == "" {
+		return fmt.Errorf("job %s has no transcript", job.ID)
+	}
+
+	// Extract text from JSON transcript
+	transcriptText, err := extractTextFromTranscript(*job.Transcript)
+	if err != nil {
+		// Fallback: use raw transcript if JSON parsing fails
+		transcriptText = *job.Transcript
+	}
+
+	if strings.TrimSpace(transcriptText) == "" {
+		return fmt.Errorf("job %s has empty transcript text", job.ID)
+	}
+
+	// Get summary if available
+	summary := ""
+	if job.Summary != nil {
+		summary = *job.Summary
+	}
+
+	// Re-indexing joins the job's trace, alongside its processing
+	return h.ragService.StoreSummary(tracing.ForJob(context.Background(), job.ID), job.ID, summary, transcriptText)
+}
+
+// reindexIfIndexed replaces a transcription's RAG entries with its current transcript and
+// summary after either changed, if it is indexed. It reports whether it re-indexed.
+func (h *Handler) reindexIfIndexed(jobID string) (bool, error) {
+	if h.ragService == nil {
+		return false, nil
+	}
+	indexed, err := h.ragService.IsIndexed(jobID)
+	if err != nil || !indexed {
+		return false, err
+	}
+	var job models.TranscriptionJob
+	if err := database.DB.Where("id = ?", jobID).First(&job).Error; err != nil {
+		return false, err
+	}
+	if err := h.storeJobInRAG(&job); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// refreshRAGMetadata updates the title, tags and speaker names stored with a transcription's
+// RAG entries after they were edited. Failures are only logged: the edit itself succeeded, and
+// the audit will report the entries as stale.
+func (h *Handler) refreshRAGMetadata(jobID string) {
+	if h.ragService == nil {
+		return
+	}
+	if err := h.ragService.UpdateMetadata(jobID); err != nil {
+		logger.Error("Failed to update RAG metadata", "job_id", jobID, "error", err)
+	}
+}
+
 // extractTextFromTranscript extracts the text content from a JSON transcript (same logic as post-processing)
 func extractTextFromTranscript(transcriptJSON string) (string, error) {
 	// Try to parse as TranscriptResult JSON
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/rag/eval"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxEvalK caps the number of results retrieved per evaluation question
+const maxEvalK = 50
+
+// RAGEvalCaseRequest represents a retrieval evaluation case to store
+type RAGEvalCaseRequest struct {
+	Question        string   `json:"question" binding:"required"`
+	ExpectedSources []string `json:"expected_sources" binding:"required"` // Transcription IDs that should be retrieved
+}
+
+// ListRAGEvalCases returns the stored retrieval evaluation cases
+// @Summary List RAG evaluation cases
+// @Description List the caller's stored question/expected-source pairs used to evaluate retrieval. Admins only.
+// @Tags rag
+// @Produce json
+// @Success 200 {array} models.RAGEvalCase
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/rag/eval/cases [get]
+func (h *Handler) ListRAGEvalCases(c *gin.Context) {
+	var cases []models.RAGEvalCase
+	if err := scopeToOwner(database.DB, currentUserID(c)).Order("created_at ASC").Find(&cases).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list evaluation cases"})
+		return
+	}
+	c.JSON(http.StatusOK, cases)
+}
+
+// CreateRAGEvalCase stores a retrieval evaluation case
+// @Summary Create a RAG evaluation case
+// @Description Store a question together with the transcriptions that should be retrieved for it. Admins only.
+// @Tags rag
+// @Accept json
+// @Produce json
+// @Param request body RAGEvalCaseRequest true "Evaluation case"
+// @Success 201 {object} models.RAGEvalCase
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/rag/eval/cases [post]
+func (h *Handler) CreateRAGEvalCase(c *gin.Context) {
+	var req RAGEvalCaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	question := strings.TrimSpace(req.Question)
+	sources := make([]string, 0, len(req.ExpectedSources))
+	for _, id := range req.ExpectedSources {
+		if id = strings.TrimSpace(id); id != "" {
+			sources = append(sources, id)
+		}
+	}
+	if question == "" || len(sources) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "question and at least one expected source are required"})
+		return
+	}
+
+	var count int64
+	if err := database.DB.Model(&models.TranscriptionJob{}).Where("id IN ?", sources).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate expected sources"})
+		return
+	}
+	if int(count) != len(sources) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expected_sources must be existing transcription IDs"})
+		return
+	}
+
+	evalCase := models.RAGEvalCase{
+		UserID:          currentUserID(c),
+		Question:        question,
+		ExpectedSources: sources,
+	}
+	if err := database.DB.Create(&evalCase).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save evaluation case"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, evalCase)
+}
+
+// DeleteRAGEvalCase removes a retrieval evaluation case
+// @Summary Delete a RAG evaluation case
+// @Description Delete one of the caller's evaluation cases. Admins only.
+// @Tags rag
+// @Param case_id path string true "Evaluation case ID"
+// @Success 204
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/rag/eval/cases/{case_id} [delete]
+func (h *Handler) DeleteRAGEvalCase(c *gin.Context) {
+	result := scopeToOwner(database.DB, currentUserID(c)).Where("id = ?", c.Param("case_id")).Delete(&models.RAGEvalCase{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete evaluation case"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Evaluation case not found"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RunRAGEval evaluates retrieval against the stored cases
+// @Summary Run RAG retrieval evaluation
+// @Description Run every stored question against the current retrieval configuration and report recall@k, MRR and per-question ranks. Admins only.
+// @Tags rag
+// @Produce json
+// @Param k query int false "Results retrieved per question (default 5)"
+// @Success 200 {object} eval.Report
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/rag/eval [post]
+func (h *Handler) RunRAGEval(c *gin.Context) {
+	if h.ragService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "RAG service not initialized"})
+		return
+	}
+
+	k := eval.DefaultK
+	if raw := c.Query("k"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxEvalK {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "k must be between 1 and 50"})
+			return
+		}
+		k = parsed
+	}
+
+	userID := currentUserID(c)
+	var stored []models.RAGEvalCase
+	if err := scopeToOwner(database.DB, userID).Order("created_at ASC").Find(&stored).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load evaluation cases"})
+		return
+	}
+	if len(stored) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No evaluation cases stored"})
+		return
+	}
+
+	cases := make([]eval.Case, len(stored))
+	for i, sc := range stored {
+		cases[i] = eval.Case{ID: sc.ID, Question: sc.Question, ExpectedSources: sc.ExpectedSources}
+	}
+
+	retrieve := func(ctx context.Context, question string, k int) ([]string, error) {
+		docs, err := h.ragService.Retrieve(ctx, userID, question, k)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]string, len(docs))
+		for i, doc := range docs {
+			ids[i] = doc.TranscriptionID
+		}
+		return ids, nil
+	}
+
+	ctx := c.Request.Context()
+
+	report := eval.Run(ctx, retrieve, cases, k)
+	report.EmbeddingModel = h.ragService.EmbeddingModel()
+
+	c.JSON(http.StatusOK, report)
+}
@@ -1,28 +1,49 @@
 package api
 
 import (
-	"context"
+	"errors"
+	"fmt"
 	"net/http"
-	"time"
+	"strconv"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // RAGChatRequest represents a RAG chat request
 type RAGChatRequest struct {
 	Query     string  `json:"query" binding:"required"`
-	Model     string  `json:"model" binding:"required"`
+	Model     string  `json:"model,omitempty"` // Defaults to the configured chat model
 	Temperature float64 `json:"temperature,omitempty"`
+	Verify      bool    `json:"verify,omitempty"` // Check the answer against the retrieved context
+	FolderID    string  `json:"folder_id,omitempty"` // Only use transcriptions in this smart folder
+	ProjectID   string  `json:"project_id,omitempty"` // Only use transcriptions in this project and the projects in it
+	Tags        []string `json:"tags,omitempty"`     // Only use transcriptions with all of these tags
+	// Mode is "abstractive" (default) for an answer written by the LLM, or "extractive" for one
+	// made only of sentences quoted from the transcripts, with citations
+	Mode string `json:"mode,omitempty"`
+	// Collections limits the search to these collections, by route name (see GET /rag/collections); empty searches all of them
+	Collections []string `json:"collections,omitempty"`
+	// CrossLanguage is how excerpts in another language than the query are handled: "off",
+	// "annotate" (mark their language) or "translate"; defaults to RAG_CROSS_LANGUAGE
+	CrossLanguage string `json:"cross_language,omitempty"`
+	// QueryLanguage is the language of the query, e.g. "de" or "German"; detected when translating without it
+	QueryLanguage string `json:"query_language,omitempty" binding:"max=64"`
 }
 
 // RAGChat handles RAG-enhanced chat queries
 // @Summary RAG chat query
-// @Description Query across all transcriptions using RAG
+// @Description Query across the caller's transcriptions using RAG. Returns the transcriptions used as sources, an answer_id for paging through every retrieved excerpt, an explicit "no relevant transcripts found" answer when nothing relevant is retrieved, and, with verify set, a groundedness check of the answer. In extractive mode the answer consists only of sentences quoted word for word from the retrieved excerpts, each followed by the number of its excerpt, and the quotes are returned with their recordings and time ranges. Set collections to search only some of the caller's collections, e.g. only podcasts; by default every collection is searched and the best matches are merged. With cross_language, excerpts in another language than the query are marked with their language (annotate) or translated into the query's language (translate) before they go into the prompt, so answers stay in one language.
 // @Tags rag
 // @Accept json
 // @Produce json
 // @Param request body RAGChatRequest true "RAG chat request"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
@@ -40,28 +61,366 @@ func (h *Handler) RAGChat(c *gin.Context) {
 		return
 	}
 
+	if req.Model == "" {
+		req.Model = h.config.ChatLLMModel
+	}
 	if req.Temperature == 0 {
 		req.Temperature = 0.7
 	}
+	if req.Mode == "" {
+		req.Mode = rag.ModeAbstractive
+	}
+	if req.Mode != rag.ModeAbstractive && req.Mode != rag.ModeExtractive {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be abstractive or extractive"})
+		return
+	}
+
+	if req.CrossLanguage == "" {
+		req.CrossLanguage = h.config.RAGCrossLanguage
+	}
+	if req.CrossLanguage != "" && !rag.IsCrossLanguageMode(req.CrossLanguage) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cross_language must be off, annotate or translate"})
+		return
+	}
+
+	for _, name := range req.Collections {
+		if !h.ragService.HasRoute(name) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown collection %q", name)})
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+
+	opts := rag.ChatOptions{
+		Verify:        req.Verify,
+		Mode:          req.Mode,
+		Collections:   req.Collections,
+		CrossLanguage: req.CrossLanguage,
+		QueryLanguage: req.QueryLanguage,
+	}
+	ids, ok := retrievalScope(c, req.FolderID, req.ProjectID, req.Tags)
+	if !ok {
+		return
+	}
+	opts.TranscriptionIDs = ids
+
+	result, err := h.ragService.Chat(ctx, currentUserID(c), req.Query, req.Model, req.Temperature, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := gin.H{
+		"response":            result.Answer,
+		"query":               req.Query,
+		"sources":             result.Sources,
+		"no_relevant_context": result.NoRelevantContext,
+		"mode":                req.Mode,
+	}
+	if req.Mode == rag.ModeExtractive && !result.NoRelevantContext {
+		response["quotes"] = result.Quotes
+	}
+	if result.QueryLanguage != "" {
+		response["query_language"] = result.QueryLanguage
+		response["translated_excerpts"] = result.TranslatedExcerpts
+	}
+	if len(result.DocumentSources) > 0 {
+		response["document_sources"] = result.DocumentSources
+	}
+	if result.Verification != nil {
+		response["verification"] = result.Verification
+	}
+	if !result.NoRelevantContext {
+		// Keeping the full source list is secondary to answering, so a failure only loses the paging
+		if answer, err := rag.SaveAnswer(currentUserID(c), req.Query, result); err != nil {
+			logger.ErrorContext(c.Request.Context(), "Failed to save the sources of an answer", "error", err)
+		} else {
+			response["answer_id"] = answer.ID
+			response["source_count"] = answer.SourceCount
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// AnswerSource is one excerpt retrieved for a chat answer
+type AnswerSource struct {
+	models.RAGAnswerSource
+	Title *string `json:"title,omitempty"` // Title of the source transcription
+}
+
+// ListAnswerSources pages through every excerpt retrieved for a chat answer
+// @Summary List the sources of a chat answer
+// @Description Page through all the excerpts retrieved for a RAG chat answer, best match first, with their text, distance, recording, time range and speaker. in_context marks the excerpts that were given to the LLM; the rest support the answer without being quoted.
+// @Tags rag
+// @Produce json
+// @Param answer_id path string true "Answer ID returned by the chat endpoint"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/rag/answers/{answer_id}/sources [get]
+func (h *Handler) ListAnswerSources(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	var answer models.RAGAnswer
+	if err := scopeToOwner(database.DB, currentUserID(c)).Where("id = ?", c.Param("answer_id")).First(&answer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Answer not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get answer"})
+		return
+	}
+
+	query := database.DB.Model(&models.RAGAnswerSource{}).Where("answer_id = ?", answer.ID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count sources"})
+		return
+	}
+	var rows []models.RAGAnswerSource
+	if err := query.Order("rank").Offset((page - 1) * limit).Limit(limit).Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list sources"})
+		return
+	}
+
+	jobIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.TranscriptionID != "" {
+			jobIDs = append(jobIDs, row.TranscriptionID)
+		}
+	}
+	titles := map[string]*string{}
+	if len(jobIDs) > 0 {
+		var jobs []models.TranscriptionJob
+		database.DB.Select("id", "title").Where("id IN ?", jobIDs).Find(&jobs)
+		for _, job := range jobs {
+			titles[job.ID] = job.Title
+		}
+	}
+	sources := make([]AnswerSource, len(rows))
+	for i, row := range rows {
+		sources[i] = AnswerSource{RAGAnswerSource: row, Title: titles[row.TranscriptionID]}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"answer_id": answer.ID,
+		"query":     answer.Query,
+		"sources":   sources,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// RAGSearchRequest represents a semantic search request
+type RAGSearchRequest struct {
+	Query    string   `json:"query" binding:"required"`
+	Limit    int      `json:"limit,omitempty"`     // Number of results, default 10
+	FolderID string   `json:"folder_id,omitempty"` // Only search transcriptions in this smart folder
+	ProjectID string  `json:"project_id,omitempty"` // Only search transcriptions in this project and the projects in it
+	Tags     []string `json:"tags,omitempty"`      // Only search transcriptions with all of these tags
+}
+
+// RAGSearchResult is a matching transcript passage
+type RAGSearchResult struct {
+	rag.SearchHit
+	Title       *string `json:"title,omitempty"`
+	SpeakerName string  `json:"speaker_name,omitempty"` // Custom name mapped to the speaker label, if any
+}
+
+// maxSearchResults caps the number of results a search can return
+const maxSearchResults = 50
+
+// RAGSearch finds transcript passages matching a query without generating an answer
+// @Summary Semantic search over transcripts
+// @Description Return the transcript passages most similar to a natural-language query, best match first, with their recording, time range, speaker and a snippet. No LLM is involved.
+// @Tags rag
+// @Accept json
+// @Produce json
+// @Param request body RAGSearchRequest true "Search request"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/rag/search [post]
+func (h *Handler) RAGSearch(c *gin.Context) {
+	var req RAGSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if h.ragService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "RAG service not initialized"})
+		return
+	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Minute)
-	defer cancel()
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if req.Limit < 1 || req.Limit > maxSearchResults {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 50"})
+		return
+	}
 
-	response, err := h.ragService.Chat(ctx, req.Query, req.Model, req.Temperature)
+	scope, ok := retrievalScope(c, req.FolderID, req.ProjectID, req.Tags)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	hits, err := h.ragService.Search(ctx, currentUserID(c), req.Query, req.Limit, scope)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Attach recording titles and mapped speaker names
+	jobIDs := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		jobIDs = append(jobIDs, hit.TranscriptionID)
+	}
+	titles := map[string]*string{}
+	speakerNames := map[string]string{}
+	if len(jobIDs) > 0 {
+		var jobs []models.TranscriptionJob
+		database.DB.Select("id", "title").Where("id IN ?", jobIDs).Find(&jobs)
+		for _, job := range jobs {
+			titles[job.ID] = job.Title
+		}
+		var mappings []models.SpeakerMapping
+		database.DB.Where("transcription_job_id IN ?", jobIDs).Find(&mappings)
+		for _, mapping := range mappings {
+			speakerNames[mapping.TranscriptionJobID+"/"+mapping.OriginalSpeaker] = mapping.CustomName
+		}
+	}
+
+	results := make([]RAGSearchResult, len(hits))
+	for i, hit := range hits {
+		results[i] = RAGSearchResult{
+			SearchHit:   hit,
+			Title:       titles[hit.TranscriptionID],
+			SpeakerName: speakerNames[hit.TranscriptionID+"/"+hit.Speaker],
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"response": response,
-		"query":    req.Query,
+		"query":   req.Query,
+		"results": results,
+	})
+}
+
+// maxRelatedResults caps the number of related transcriptions returned
+const maxRelatedResults = 20
+
+// GetRelatedTranscriptions returns the transcriptions most similar to a given one
+// @Summary Get related transcriptions
+// @Description Embed a transcription's summary (or its transcript, if it has no summary) and return the most similar other transcriptions, best match first
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param limit query int false "Number of results (default 5, max 20)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/related [get]
+func (h *Handler) GetRelatedTranscriptions(c *gin.Context) {
+	if h.ragService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "RAG service not initialized"})
+		return
+	}
+
+	limit := 5
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxRelatedResults {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 20"})
+			return
+		}
+		limit = parsed
+	}
+
+	jobID := c.Param("id")
+	var count int64
+	if err := database.DB.Model(&models.TranscriptionJob{}).Where("id = ?", jobID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transcription"})
+		return
+	}
+	if count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcription not found"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	related, err := h.ragService.Related(ctx, currentUserID(c), jobID, limit)
+	if err != nil {
+		if errors.Is(err, rag.ErrNothingToCompare) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Attach titles and dates for display
+	ids := make([]string, len(related))
+	for i, r := range related {
+		ids[i] = r.TranscriptionID
+	}
+	jobs := map[string]models.TranscriptionJob{}
+	if len(ids) > 0 {
+		var found []models.TranscriptionJob
+		database.DB.Select("id", "title", "created_at").Where("id IN ?", ids).Find(&found)
+		for _, job := range found {
+			jobs[job.ID] = job
+		}
+	}
+
+	results := make([]gin.H, 0, len(related))
+	for _, r := range related {
+		job, ok := jobs[r.TranscriptionID]
+		if !ok {
+			// Deleted since it was indexed; the RAG audit cleans these up
+			continue
+		}
+		results = append(results, gin.H{
+			"transcription_id": r.TranscriptionID,
+			"title":            job.Title,
+			"created_at":       job.CreatedAt,
+			"distance":         r.Distance,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transcription_id": jobID,
+		"related":          results,
 	})
 }
 
 // RAGStats returns statistics about the RAG system
 // @Summary Get RAG statistics
-// @Description Get statistics about transcripts stored in RAG
+// @Description Get statistics about the caller's transcripts stored in RAG
 // @Tags rag
 // @Produce json
 // @Success 200 {object} map[string]interface{}
@@ -79,10 +438,9 @@ func (h *Handler) RAGStats(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
-	stats, err := h.ragService.GetStats(ctx)
+	stats, err := h.ragService.GetStats(ctx, currentUserID(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -90,3 +448,45 @@ func (h *Handler) RAGStats(c *gin.Context) {
 
 	c.JSON(http.StatusOK, stats)
 }
+
+// ListRAGCollections returns the caller's collections and the content types routed to each
+// @Summary List RAG collections
+// @Description List the caller's RAG collections by route name, with the vector store collection behind each and the content types stored in it, and how each content type is chunked. Route names are what the chat's collections field takes. Content types are routed with RAG_COLLECTION_ROUTES; those not routed live in the default collection.
+// @Tags rag
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/rag/collections [get]
+func (h *Handler) ListRAGCollections(c *gin.Context) {
+	if h.ragService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "RAG service not initialized"})
+		return
+	}
+	base, err := rag.ResolveCollection(currentUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	collections := []gin.H{}
+	for _, route := range h.ragService.Routes() {
+		collection := base
+		if route.Name != rag.DefaultRoute {
+			collection = base + "_" + route.Name
+		}
+		collections = append(collections, gin.H{
+			"name":          route.Name,
+			"collection":    collection,
+			"content_types": route.ContentTypes,
+		})
+	}
+	profiles := gin.H{}
+	for _, contentType := range rag.ContentTypes {
+		profile := rag.Profile(contentType)
+		profiles[contentType] = gin.H{"label": profile.Label, "chunk_size": profile.ChunkSize}
+	}
+	c.JSON(http.StatusOK, gin.H{"collections": collections, "content_types": profiles})
+}
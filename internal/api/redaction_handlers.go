@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/workflow"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RedactedTranscriptResponse is a transcript with its personal data redacted
+type RedactedTranscriptResponse struct {
+	models.Redaction
+	Transcript json.RawMessage `json:"transcript" swaggertype:"object"` // Same format as the original transcript, without word timings
+}
+
+// GetRedactedTranscript returns the redacted variant of a transcript
+// @Summary Get the redacted transcript
+// @Description Get the transcript with email addresses, phone numbers, payment card numbers and names replaced by placeholders, as made by the redact_pii workflow step, with how many of each were replaced
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Success 200 {object} RedactedTranscriptResponse
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/redacted [get]
+func (h *Handler) GetRedactedTranscript(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	var redaction models.Redaction
+	if err := database.DB.Where("transcription_id = ?", job.ID).Limit(1).Find(&redaction).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get redacted transcript"})
+		return
+	}
+	if redaction.ID == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcript has not been redacted"})
+		return
+	}
+	c.JSON(http.StatusOK, RedactedTranscriptResponse{Redaction: redaction, Transcript: json.RawMessage(redaction.Transcript)})
+}
+
+// RedactTranscription redacts a transcript on demand
+// @Summary Redact a transcript
+// @Description Find the personal data in a completed transcript, email addresses, phone numbers and payment card numbers by pattern and names with the summary LLM, and save the redacted transcript, replacing an earlier one. When transcriptions are embedded redacted (EMBED_REDACTED), an indexed transcription is re-indexed with the names found.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Success 201 {object} RedactedTranscriptResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/redact [post]
+func (h *Handler) RedactTranscription(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	if rejectIfOnHold(c, job) {
+		return
+	}
+
+	if h.llmRegistry == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "LLM providers not initialized"})
+		return
+	}
+	service, model, err := h.llmRegistry.For(llm.FeatureSummary)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if job.Status != models.StatusCompleted {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Transcription is not completed"})
+		return
+	}
+
+	start := time.Now()
+	redaction, err := workflow.RedactJob(c.Request.Context(), service, model, job)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "Failed to redact transcript", "job_id", job.ID, "model", model, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to redact transcript: " + err.Error()})
+		return
+	}
+	logger.InfoContext(c.Request.Context(), "Redacted transcript", "job_id", job.ID, "model", model, "counts", redaction.Counts, "duration_ms", time.Since(start).Milliseconds())
+
+	// The stored entries were redacted with the names known when they were indexed
+	if h.ragService != nil && h.ragService.EmbedsRedacted() {
+		indexed, err := h.ragService.IsIndexed(job.ID)
+		if err == nil && indexed {
+			err = h.storeJobInRAG(job)
+		}
+		if err != nil {
+			logger.ErrorContext(c.Request.Context(), "Failed to re-index redacted transcription", "job_id", job.ID, "error", err)
+		}
+	}
+	c.JSON(http.StatusCreated, RedactedTranscriptResponse{Redaction: *redaction, Transcript: json.RawMessage(redaction.Transcript)})
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"scriberr/internal/config"
+	"scriberr/internal/notify"
+	"scriberr/internal/resources"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memoryRetryAfter is the Retry-After, in seconds, of requests turned away for lack of memory
+const memoryRetryAfter = 60
+
+// ResourceStatusResponse reports free disk space and memory against the guardrail thresholds
+type ResourceStatusResponse struct {
+	resources.Usage
+	MinFreeDisk   uint64 `json:"min_free_disk"`   // Bytes; 0 when the disk check is off
+	MinFreeMemory uint64 `json:"min_free_memory"` // Bytes; 0 when the memory check is off
+	Accepting     bool   `json:"accepting"`       // Whether uploads and transcriptions are accepted right now
+}
+
+// newResourceGuard builds the disk and memory guard from the configured thresholds
+func newResourceGuard(cfg *config.Config) *resources.Guard {
+	if cfg == nil {
+		return nil
+	}
+	return resources.NewGuard(cfg.UploadDir, cfg.MinFreeDiskMB, cfg.MinFreeMemoryMB, notify.NewWebhookNotifier(cfg.NotifyWebhookURL))
+}
+
+// requireResources rejects a request, before its body is read, when free disk space would
+// fall below MIN_FREE_DISK_MB once the request body is written, or available memory is below
+// MIN_FREE_MEMORY_MB: with 507 for disk space and 429 with a Retry-After for memory
+func (h *Handler) requireResources() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.checkResources(c, c.Request.ContentLength) {
+			c.Next()
+		}
+	}
+}
+
+// checkResources reports whether there is room to write size bytes, aborting with an error
+// response if not
+func (h *Handler) checkResources(c *gin.Context, size int64) bool {
+	err := h.resourceGuard.Check(c.Request.Context(), size)
+	var rerr *resources.Error
+	if !errors.As(err, &rerr) {
+		return true
+	}
+	if rerr.Resource == resources.ResourceMemory {
+		c.Header("Retry-After", strconv.Itoa(memoryRetryAfter))
+	}
+	c.AbortWithStatusJSON(rerr.StatusCode(), gin.H{
+		"error":           rerr.Error(),
+		"resource":        rerr.Resource,
+		"available_bytes": rerr.Available,
+		"required_bytes":  rerr.Required,
+	})
+	return false
+}
+
+// GetResourceStatus reports free disk space and memory
+// @Summary Get resource status
+// @Description Report the free disk space of the upload directory's file system and the available memory, against the MIN_FREE_DISK_MB and MIN_FREE_MEMORY_MB thresholds below which uploads and transcriptions are rejected. Sizes are in bytes; what the platform can't measure is 0.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} ResourceStatusResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/resources [get]
+func (h *Handler) GetResourceStatus(c *gin.Context) {
+	response := ResourceStatusResponse{Accepting: true}
+	if h.resourceGuard == nil {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+	response.Usage = h.resourceGuard.Usage()
+	response.MinFreeDisk, response.MinFreeMemory = h.resourceGuard.Thresholds()
+	response.Accepting = h.resourceGuard.Accepts(response.Usage)
+	c.JSON(http.StatusOK, response)
+}
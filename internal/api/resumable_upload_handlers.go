@@ -0,0 +1,375 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// uploadOffsetHeader carries the offset a part of a resumable upload starts at
+const uploadOffsetHeader = "Upload-Offset"
+
+// uploadLocks serializes the parts sent to one resumable upload, by session ID
+var uploadLocks sync.Map
+
+// CreateResumableUploadRequest starts a resumable upload
+type CreateResumableUploadRequest struct {
+	Filename    string  `json:"filename" binding:"required"`
+	Size        int64   `json:"size" binding:"required"` // Total size of the file, in bytes
+	Title       *string `json:"title,omitempty"`
+	ContentType string  `json:"content_type,omitempty"` // meeting (default), voice_memo or podcast
+	Priority    int     `json:"priority,omitempty"`
+	InitialPromptRequest
+}
+
+// ResumableUploadResponse is the state of a resumable upload
+type ResumableUploadResponse struct {
+	models.UploadSession
+	// Transcription is the transcription created by the part that completed the upload
+	Transcription *models.TranscriptionJob `json:"transcription,omitempty"`
+}
+
+// partialUploadPath returns where a resumable upload is assembled
+func (h *Handler) partialUploadPath(sessionID string) string {
+	return filepath.Join(h.config.UploadDir, "partial", sessionID+".part")
+}
+
+// uploadExpiry returns how long an unfinished resumable upload is kept after its last part
+func (h *Handler) uploadExpiry() time.Duration {
+	if h.config.ResumableUploadExpiryHours <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(h.config.ResumableUploadExpiryHours) * time.Hour
+}
+
+// loadUploadSession loads the caller's resumable upload named in the path, writing an error
+// response if there is none or it expired
+func loadUploadSession(c *gin.Context) (*models.UploadSession, bool) {
+	var session models.UploadSession
+	err := scopeToOwner(database.DB, currentUserID(c)).
+		Where("id = ? AND expires_at > ?", c.Param("id"), time.Now()).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Upload not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get upload"})
+		return nil, false
+	}
+	return &session, true
+}
+
+// lockUploadSession loads and locks the caller's resumable upload named in the path, writing
+// an error response if there is none. The upload is looked up before a lock is made for it,
+// so requests for unknown IDs leave no locks behind, and loaded again once locked.
+func lockUploadSession(c *gin.Context) (*models.UploadSession, func(), bool) {
+	if _, ok := loadUploadSession(c); !ok {
+		return nil, nil, false
+	}
+	value, _ := uploadLocks.LoadOrStore(c.Param("id"), &sync.Mutex{})
+	lock := value.(*sync.Mutex)
+	lock.Lock()
+	session, ok := loadUploadSession(c)
+	if !ok {
+		lock.Unlock()
+		return nil, nil, false
+	}
+	return session, lock.Unlock, true
+}
+
+// removeExpiredUploads deletes resumable uploads that weren't continued in time, and their parts
+func (h *Handler) removeExpiredUploads() {
+	var expired []models.UploadSession
+	if err := database.DB.Select("id").Where("expires_at <= ?", time.Now()).Find(&expired).Error; err != nil {
+		logger.Error("Failed to list expired uploads", "error", err)
+		return
+	}
+	for _, session := range expired {
+		os.Remove(h.partialUploadPath(session.ID))
+		database.DB.Delete(&models.UploadSession{}, "id = ?", session.ID)
+		uploadLocks.Delete(session.ID)
+	}
+}
+
+// CreateResumableUpload starts a resumable upload
+// @Summary Start a resumable upload
+// @Description Start uploading a large audio file in parts. Send the parts in order with PATCH /transcription/uploads/{id}; once all size bytes have arrived, a transcription is created and queued like an upload to /transcription/upload. An upload that gets no part for RESUMABLE_UPLOAD_EXPIRY_HOURS is discarded.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param request body CreateResumableUploadRequest true "File to upload"
+// @Success 201 {object} ResumableUploadResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 507 {object} map[string]interface{}
+// @Router /api/v1/transcription/uploads [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateResumableUpload(c *gin.Context) {
+	var req CreateResumableUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Size <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "size must be positive"})
+		return
+	}
+	contentType := strings.TrimSpace(req.ContentType)
+	if contentType == "" {
+		contentType = models.ContentMeeting
+	}
+	if !models.IsRecordingContentType(contentType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": contentTypeError})
+		return
+	}
+	if req.Priority < queue.MinPriority || req.Priority > queue.MaxPriority {
+		c.JSON(http.StatusBadRequest, gin.H{"error": priorityError})
+		return
+	}
+	// The whole file must fit, not just this request
+	if !h.checkResources(c, req.Size) {
+		return
+	}
+	h.removeExpiredUploads()
+
+	session := models.UploadSession{
+		UserID:      currentUserID(c),
+		Filename:    filepath.Base(req.Filename),
+		Size:        req.Size,
+		ContentType: contentType,
+		Priority:    req.Priority,
+		ExpiresAt:   time.Now().Add(h.uploadExpiry()),
+	}
+	if req.Title != nil && *req.Title != "" {
+		session.Title = req.Title
+	}
+	if prompt := req.InitialPromptRequest.Compose(); prompt != "" {
+		session.InitialPrompt = &prompt
+	}
+	if err := database.DB.Create(&session).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload"})
+		return
+	}
+
+	partialPath := h.partialUploadPath(session.ID)
+	if err := os.MkdirAll(filepath.Dir(partialPath), 0755); err != nil {
+		database.DB.Delete(&session)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload directory"})
+		return
+	}
+	file, err := os.Create(partialPath)
+	if err != nil {
+		database.DB.Delete(&session)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload"})
+		return
+	}
+	file.Close()
+
+	c.Header(uploadOffsetHeader, "0")
+	c.JSON(http.StatusCreated, ResumableUploadResponse{UploadSession: session})
+}
+
+// GetResumableUpload reports how much of a resumable upload has arrived
+// @Summary Get a resumable upload
+// @Description Get the state of a resumable upload: how many bytes have been received, which is where to resume after a dropped connection, and the transcription once it is complete. The Upload-Offset header carries the bytes received too.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Upload ID"
+// @Success 200 {object} ResumableUploadResponse
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/uploads/{id} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetResumableUpload(c *gin.Context) {
+	session, ok := loadUploadSession(c)
+	if !ok {
+		return
+	}
+	c.Header(uploadOffsetHeader, strconv.FormatInt(session.Received, 10))
+	c.JSON(http.StatusOK, ResumableUploadResponse{UploadSession: *session})
+}
+
+// UploadResumablePart appends a part to a resumable upload
+// @Summary Upload part of a resumable upload
+// @Description Append the request body to a resumable upload. The Upload-Offset header must give the bytes received so far, so that a part is never written twice; on a mismatch nothing is written and the response carries the bytes received. If the connection drops, what arrived is kept: get the upload and resume from its received count. The part that completes the file creates the transcription, returned along with the upload.
+// @Tags transcription
+// @Accept application/octet-stream
+// @Produce json
+// @Param id path string true "Upload ID"
+// @Param Upload-Offset header int true "Bytes received so far"
+// @Success 200 {object} ResumableUploadResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]interface{}
+// @Failure 413 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/uploads/{id} [patch]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) UploadResumablePart(c *gin.Context) {
+	session, unlock, ok := lockUploadSession(c)
+	if !ok {
+		return
+	}
+	defer unlock()
+	if session.TranscriptionID != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Upload already completed", "transcription_id": *session.TranscriptionID})
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader(uploadOffsetHeader), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload-Offset header is required"})
+		return
+	}
+	if offset != session.Received {
+		c.JSON(http.StatusConflict, gin.H{"error": "Upload-Offset doesn't match the bytes received", "received": session.Received})
+		return
+	}
+	remaining := session.Size - session.Received
+	if c.Request.ContentLength > remaining {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Part is larger than the %d bytes left to upload", remaining)})
+		return
+	}
+
+	partialPath := h.partialUploadPath(session.ID)
+	file, err := os.OpenFile(partialPath, os.O_WRONLY, 0644)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open upload"})
+		return
+	}
+	// Bytes past what was recorded are from a part that failed midway, and are sent again
+	var written int64
+	err = file.Truncate(session.Received)
+	if err == nil {
+		_, err = file.Seek(session.Received, io.SeekStart)
+	}
+	if err == nil {
+		written, err = io.Copy(file, io.LimitReader(c.Request.Body, remaining))
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	// Whatever arrived is kept, so the client can resume after it
+	session.Received += written
+	session.ExpiresAt = time.Now().Add(h.uploadExpiry())
+	if dbErr := database.DB.Model(session).Updates(map[string]interface{}{
+		"received":   session.Received,
+		"expires_at": session.ExpiresAt,
+	}).Error; dbErr != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save upload progress"})
+		return
+	}
+	c.Header(uploadOffsetHeader, strconv.FormatInt(session.Received, 10))
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "Failed to write upload part", "upload_id", session.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save upload part", "received": session.Received})
+		return
+	}
+
+	response := ResumableUploadResponse{UploadSession: *session}
+	if session.Received == session.Size {
+		job, ok := h.completeResumableUpload(c, session)
+		if !ok {
+			return
+		}
+		response.UploadSession = *session
+		response.Transcription = job
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// completeResumableUpload creates and queues the transcription of a fully received upload,
+// writing an error response if that fails. The upload is left as it was on failure, so that
+// an empty part at its final offset tries again.
+func (h *Handler) completeResumableUpload(c *gin.Context, session *models.UploadSession) (*models.TranscriptionJob, bool) {
+	partialPath := h.partialUploadPath(session.ID)
+	filePath := filepath.Join(h.config.UploadDir, session.ID+filepath.Ext(session.Filename))
+	if err := os.Rename(partialPath, filePath); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
+		return nil, false
+	}
+	if h.files != nil {
+		if err := h.files.Upload(c.Request.Context(), filePath); err != nil {
+			logger.ErrorContext(c.Request.Context(), "Failed to store file", "path", filePath, "error", err)
+			os.Rename(filePath, partialPath)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store file"})
+			return nil, false
+		}
+	}
+
+	job := models.TranscriptionJob{
+		ID:          session.ID,
+		UserID:      session.UserID,
+		AudioPath:   filePath,
+		Title:       session.Title,
+		Status:      models.StatusUploaded,
+		ContentType: session.ContentType,
+		Priority:    session.Priority,
+	}
+	job.Parameters.InitialPrompt = session.InitialPrompt
+	if err := database.DB.Create(&job).Error; err != nil {
+		h.removeStoredFile(filePath)
+		os.Rename(filePath, partialPath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
+		return nil, false
+	}
+	session.TranscriptionID = &job.ID
+	if err := database.DB.Model(session).Update("transcription_id", job.ID).Error; err != nil {
+		logger.ErrorContext(c.Request.Context(), "Failed to record the transcription of upload", "upload_id", session.ID, "job_id", job.ID, "error", err)
+	}
+
+	jobPrompt := ""
+	if session.InitialPrompt != nil {
+		jobPrompt = *session.InitialPrompt
+	}
+	h.autoTranscribe(session.UserID, &job, jobPrompt, nil)
+
+	// Started after the job is saved for the last time so the report isn't overwritten
+	go checkAudioQuality(job.ID, filePath)
+	return &job, true
+}
+
+// CancelResumableUpload discards a resumable upload
+// @Summary Cancel a resumable upload
+// @Description Discard a resumable upload and the parts received so far. A transcription already created from it is kept.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Upload ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/uploads/{id} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CancelResumableUpload(c *gin.Context) {
+	session, unlock, ok := lockUploadSession(c)
+	if !ok {
+		return
+	}
+	defer unlock()
+	if err := database.DB.Delete(session).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel upload"})
+		return
+	}
+	os.Remove(h.partialUploadPath(session.ID))
+	uploadLocks.Delete(session.ID)
+	c.JSON(http.StatusOK, gin.H{"message": "Upload cancelled"})
+}
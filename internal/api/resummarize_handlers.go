@@ -0,0 +1,190 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/resummarize"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetResummarizer enables rewriting summaries made by older models
+func (h *Handler) SetResummarizer(service *resummarize.Service) {
+	h.resummarizer = service
+}
+
+// ResummarizeRequest configures a re-summarization run; every field is optional
+type ResummarizeRequest struct {
+	BatchSize    int  `json:"batch_size,omitempty"`    // Defaults to 20, at most 500
+	DelaySeconds *int `json:"delay_seconds,omitempty"` // Pause between transcriptions; defaults to RESUMMARIZE_DELAY_SECONDS
+	// IncludeUnknown also rewrites summaries whose model wasn't recorded
+	IncludeUnknown bool `json:"include_unknown,omitempty"`
+	// Compare has the LLM judge each new summary against the old one for the report
+	Compare bool `json:"compare,omitempty"`
+}
+
+// ListResummarizeCandidates returns the transcriptions whose summaries are outdated
+// @Summary List outdated summaries
+// @Description List completed transcriptions whose summary was written by a model other than the current default summary model, least recently updated first. These are what a re-summarization run picks. Transcriptions under legal hold are left out.
+// @Tags summaries
+// @Produce json
+// @Param include_unknown query bool false "Also list summaries whose model wasn't recorded"
+// @Param limit query int false "Most transcriptions to list" default(100)
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/resummarize/candidates [get]
+func (h *Handler) ListResummarizeCandidates(c *gin.Context) {
+	if h.resummarizer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Re-summarization requires RAG to be configured"})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if limit < 1 || limit > 1000 {
+		limit = 100
+	}
+	jobs, err := h.resummarizer.Candidates(limit, c.Query("include_unknown") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	candidates := make([]gin.H, 0, len(jobs))
+	for _, job := range jobs {
+		candidates = append(candidates, gin.H{
+			"transcription_id": job.ID,
+			"title":            job.Title,
+			"summary_model":    job.SummaryModel,
+			"updated_at":       job.UpdatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"model": h.resummarizer.Model(), "candidates": candidates, "count": len(candidates)})
+}
+
+// StartResummarize starts summarizing outdated summaries again
+// @Summary Re-summarize outdated summaries
+// @Description Summarize a batch of transcriptions again with the current default summary model, oldest first and pausing between transcriptions, keeping each summary's format and template. Indexed transcriptions are re-indexed. Runs in the background; poll the run for its report, which can include an LLM verdict on whether each new summary improves on the old one.
+// @Tags summaries
+// @Accept json
+// @Produce json
+// @Param request body ResummarizeRequest false "Run options"
+// @Success 202 {object} models.ResummarizeRun
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/resummarize [post]
+func (h *Handler) StartResummarize(c *gin.Context) {
+	if h.resummarizer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Re-summarization requires RAG to be configured"})
+		return
+	}
+	var req ResummarizeRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.BatchSize < 0 || req.BatchSize > resummarize.MaxBatchSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "batch_size must be between 1 and 500"})
+		return
+	}
+	delay := resummarize.DefaultDelay
+	if h.config != nil {
+		delay = time.Duration(h.config.ResummarizeDelaySeconds) * time.Second
+	}
+	if req.DelaySeconds != nil {
+		if *req.DelaySeconds < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "delay_seconds must not be negative"})
+			return
+		}
+		delay = time.Duration(*req.DelaySeconds) * time.Second
+	}
+
+	run, err := h.resummarizer.RunInBackground(resummarize.Options{
+		BatchSize:      req.BatchSize,
+		Delay:          delay,
+		IncludeUnknown: req.IncludeUnknown,
+		Compare:        req.Compare,
+		Trigger:        "api",
+	})
+	if errors.Is(err, resummarize.ErrRunInProgress) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, run)
+}
+
+// ListResummarizeRuns returns past re-summarization runs, newest first
+// @Summary List re-summarization runs
+// @Description List re-summarization runs with their counts, newest first. Fetch a run for its per-transcription report.
+// @Tags summaries
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Runs per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/resummarize/runs [get]
+func (h *Handler) ListResummarizeRuns(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	var total int64
+	if err := database.DB.Model(&models.ResummarizeRun{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count re-summarization runs"})
+		return
+	}
+	runs := []models.ResummarizeRun{}
+	if err := database.DB.Omit("results").Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&runs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list re-summarization runs"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"runs": runs,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetResummarizeRun returns a re-summarization run with its report
+// @Summary Get a re-summarization run
+// @Description Get a re-summarization run with, for each transcription, the old and new model, the old and new summary length, any error and, when compared, the verdict on the new summary
+// @Tags summaries
+// @Produce json
+// @Param id path string true "Run ID"
+// @Success 200 {object} models.ResummarizeRun
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/resummarize/runs/{id} [get]
+func (h *Handler) GetResummarizeRun(c *gin.Context) {
+	var run models.ResummarizeRun
+	if err := database.DB.Where("id = ?", c.Param("id")).First(&run).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Re-summarization run not found"})
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
@@ -1,7 +1,11 @@
 package api
 
 import (
+	"time"
+
 	"scriberr/internal/auth"
+	"scriberr/internal/config"
+	"scriberr/internal/models"
 	"scriberr/internal/web"
 	"scriberr/pkg/logger"
 	"scriberr/pkg/middleware"
@@ -23,9 +27,15 @@ func SetupRoutes(handler *Handler, authService *auth.AuthService) *gin.Engine {
 	// Add recovery middleware
 	router.Use(gin.Recovery())
 	
+	// Tag each request with an ID for its log lines
+	router.Use(middleware.RequestIDMiddleware())
+
 	// Add custom logger middleware
 	router.Use(logger.GinLogger())
 
+	// Add tracing middleware, which does nothing unless an OTLP endpoint is configured
+	router.Use(middleware.TracingMiddleware())
+
 	// Add compression middleware first for maximum benefit
 	router.Use(middleware.CompressionMiddleware())
 
@@ -33,7 +43,7 @@ func SetupRoutes(handler *Handler, authService *auth.AuthService) *gin.Engine {
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, X-Request-ID")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -43,6 +53,16 @@ func SetupRoutes(handler *Handler, authService *auth.AuthService) *gin.Engine {
 		c.Next()
 	})
 
+	// Request timeouts per endpoint class
+	timeouts := requestTimeouts(handler.config)
+
+	// Disk space and memory checks for uploads and transcriptions
+	requireResources := handler.requireResources()
+
+	// Monthly quotas of audio minutes for uploads and transcriptions, and of LLM requests
+	requireAudioQuota := handler.requireQuota(models.QuotaAudioMinutes)
+	requireLLMQuota := handler.requireQuota(models.QuotaLLMCalls)
+
 	// Health check endpoint (no auth required)
 	router.GET("/health", handler.HealthCheck)
 
@@ -51,6 +71,8 @@ func SetupRoutes(handler *Handler, authService *auth.AuthService) *gin.Engine {
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
+	// Per-user, per-API-key and anonymous per-IP rate limits, and the audit log of what was done
+	v1.Use(handler.rateLimit(authService), handler.auditLog())
 	{
 		// Authentication routes (no auth required)
 		auth := v1.Group("/auth")
@@ -83,33 +105,102 @@ func SetupRoutes(handler *Handler, authService *auth.AuthService) *gin.Engine {
 
 		// Transcription routes (require authentication)
 		transcription := v1.Group("/transcription")
-		transcription.Use(middleware.AuthMiddleware(authService))
+		transcription.Use(middleware.AuthMiddleware(authService), requireJobAccess("id", jobOwnerQuery))
 		{
 			// File upload routes - disable compression for these
 			uploadRoutes := transcription.Group("")
 			uploadRoutes.Use(middleware.NoCompressionMiddleware())
 			{
-				uploadRoutes.POST("/upload", handler.UploadAudio)
-				uploadRoutes.POST("/upload-video", handler.UploadVideo)
-				uploadRoutes.POST("/upload-multitrack", handler.UploadMultiTrack)
+				uploadRoutes.POST("/upload", requireResources, requireAudioQuota, handler.UploadAudio)
+				uploadRoutes.POST("/upload-video", requireResources, requireAudioQuota, handler.UploadVideo)
+				uploadRoutes.POST("/upload-multitrack", requireResources, requireAudioQuota, handler.UploadMultiTrack)
+				uploadRoutes.POST("/upload-multichannel", requireResources, requireAudioQuota, handler.UploadMultiChannel)
 				uploadRoutes.GET("/:id/audio", handler.GetAudioFile) // Audio streaming shouldn't be compressed
+				uploadRoutes.POST("/upload-url", handler.CreateUploadURL)
+				uploadRoutes.POST("/upload-url/complete", requireResources, requireAudioQuota, handler.CompleteUpload)
+				uploadRoutes.POST("/uploads", requireAudioQuota, handler.CreateResumableUpload)
+				uploadRoutes.GET("/uploads/:id", handler.GetResumableUpload)
+				uploadRoutes.PATCH("/uploads/:id", requireResources, handler.UploadResumablePart)
+				uploadRoutes.DELETE("/uploads/:id", handler.CancelResumableUpload)
+				uploadRoutes.POST("/from-url", requireResources, requireAudioQuota, handler.ImportFromURL)
+				uploadRoutes.GET("/from-url/:id", handler.GetURLImport)
+				uploadRoutes.POST("/from-media", requireResources, requireAudioQuota, handler.ImportFromMedia)
 			}
 			
 			// Regular API routes with compression
-			transcription.POST("/youtube", handler.DownloadFromYouTube)
-			transcription.POST("/submit", handler.SubmitJob)
-			transcription.POST("/:id/start", handler.StartTranscription)
+			transcription.POST("/youtube", requireResources, requireAudioQuota, handler.DownloadFromYouTube)
+			transcription.POST("/submit", requireResources, requireAudioQuota, handler.SubmitJob)
+			transcription.POST("/:id/start", requireResources, requireAudioQuota, handler.StartTranscription)
 			transcription.POST("/:id/kill", handler.KillJob)
 			transcription.GET("/:id/status", handler.GetJobStatus)
 			transcription.GET("/:id/transcript", handler.GetTranscript)
+			transcription.GET("/:id/segments", handler.ListTranscriptSegments)
+			transcription.PATCH("/:id/segments/:index", handler.EditTranscriptSegment)
+			transcription.GET("/:id/revisions", handler.ListTranscriptRevisions)
+			transcription.GET("/:id/versions/:kind", handler.ListTranscriptVersions)
+			transcription.GET("/:id/versions/:kind/diff", handler.DiffTranscriptVersions)
+			transcription.GET("/:id/versions/:kind/:version", handler.GetTranscriptVersion)
+			transcription.POST("/:id/versions/:kind/:version/restore", handler.RestoreTranscriptVersion)
+			transcription.GET("/:id/vocabulary", handler.ListTranscriptionVocabulary)
+			transcription.POST("/:id/vocabulary", handler.CreateTranscriptionVocabularyTerm)
+			transcription.DELETE("/:id/vocabulary/:termId", handler.DeleteTranscriptionVocabularyTerm)
+			transcription.POST("/:id/correct-vocabulary", handler.CorrectTranscriptionVocabulary)
 			transcription.GET("/:id/execution", handler.GetJobExecutionData)
+			transcription.GET("/:id/logs", handler.GetJobLogs)
 			transcription.GET("/:id/merge-status", handler.GetMergeStatus)
 			transcription.GET("/:id/track-progress", handler.GetTrackProgress)
 			transcription.PUT("/:id/title", handler.UpdateTranscriptionTitle)
+			transcription.PUT("/:id/initial-prompt", handler.UpdateInitialPrompt)
+			transcription.PUT("/:id/tags", handler.UpdateTranscriptionTags)
+			transcription.PUT("/:id/content-type", handler.UpdateTranscriptionContentType)
+			transcription.PUT("/:id/project", handler.UpdateTranscriptionProject)
+			transcription.PUT("/:id/priority", handler.UpdateTranscriptionPriority)
+			transcription.GET("/:id/queue", handler.GetQueuePosition)
+			transcription.GET("/:id/quality", handler.GetAudioQuality)
+			transcription.GET("/:id/workflows", handler.ListWorkflowRuns)
+			transcription.GET("/:id/post-processing", handler.GetPostProcessingStatus)
+			transcription.GET("/:id/events", handler.StreamJobEvents)
+			transcription.POST("/:id/workflows", handler.StartWorkflow)
+			transcription.POST("/:id/workflows/:run_id/steps/:step/rerun", handler.RerunWorkflowStep)
 			transcription.GET("/:id/summary", handler.GetSummaryForTranscription)
+			transcription.POST("/:id/summarize", timeouts.Timeout(middleware.TimeoutLong), requireLLMQuota, handler.RegenerateSummary)
+			transcription.DELETE("/:id/summary", handler.DeleteJobSummary)
+			transcription.POST("/:id/range/summarize", timeouts.Timeout(middleware.TimeoutLong), requireLLMQuota, handler.SummarizeTimeRange)
+			transcription.POST("/:id/range/ask", timeouts.Timeout(middleware.TimeoutLong), requireLLMQuota, handler.AskTimeRange)
+			transcription.DELETE("/:id/rag", handler.DeleteJobRAGData)
+			transcription.DELETE("/:id/audio", handler.DeleteJobAudio)
+			transcription.GET("/:id/audio/url", handler.GetAudioURL)
+			transcription.GET("/:id/related", timeouts.Timeout(middleware.TimeoutRead), handler.GetRelatedTranscriptions)
+			transcription.GET("/:id/redacted", handler.GetRedactedTranscript)
+			transcription.POST("/:id/redact", timeouts.Timeout(middleware.TimeoutLong), requireLLMQuota, handler.RedactTranscription)
+			transcription.GET("/:id/translations", handler.ListTranslations)
+			transcription.POST("/:id/translations", timeouts.Timeout(middleware.TimeoutLong), requireLLMQuota, handler.TranslateTranscription)
+			transcription.GET("/:id/translations/:language", handler.GetTranslation)
+			transcription.DELETE("/:id/translations/:language", handler.DeleteTranslation)
+			transcription.GET("/:id/export", handler.ExportTranscript)
+			transcription.GET("/:id/export/bilingual", handler.ExportBilingual)
+			transcription.GET("/:id/export/chapters", handler.ExportChapters)
+			transcription.GET("/:id/shares", handler.ListShareLinks)
+			transcription.POST("/:id/shares", handler.CreateShareLink)
+			transcription.DELETE("/:id/shares/:share_id", handler.RevokeShareLink)
+			transcription.GET("/:id/shares/:share_id/accesses", handler.ListShareLinkAccesses)
+			transcription.GET("/:id/action-items", handler.ListTranscriptionActionItems)
+			transcription.POST("/:id/action-items/deliver", timeouts.Timeout(middleware.TimeoutLong), handler.DeliverTranscriptionActionItems)
+			transcription.GET("/:id/action-items/deliveries", handler.ListActionItemDeliveries)
+			transcription.GET("/:id/entities", handler.ListTranscriptionEntities)
+			transcription.GET("/:id/analytics", handler.GetTranscriptionAnalytics)
+			transcription.GET("/:id/chapters", handler.ListTranscriptionChapters)
+			transcription.GET("/:id/watchlist-matches", handler.ListTranscriptionWatchlistMatches)
 			transcription.GET("/:id", handler.GetJobByID)
 			transcription.DELETE("/:id", handler.DeleteJob)
 			transcription.GET("/list", handler.ListJobs)
+			transcription.POST("/batch", handler.CreateBatchOperation)
+			transcription.GET("/batch", handler.ListBatchOperations)
+			transcription.GET("/batch/:batch_id", handler.GetBatchOperation)
+			transcription.POST("/batch/:batch_id/cancel", handler.CancelBatchOperation)
+			transcription.GET("/archive", handler.ExportArchive)
+			transcription.POST("/archive/import", handler.ImportArchive)
+			transcription.POST("/import-transcript", handler.ImportTranscript)
 			transcription.GET("/models", handler.GetSupportedModels)
 			// Notes for a transcription
 			transcription.GET("/:id/notes", handler.ListNotes)
@@ -118,9 +209,12 @@ func SetupRoutes(handler *Handler, authService *auth.AuthService) *gin.Engine {
 			// Speaker mappings for a transcription
 			transcription.GET("/:id/speakers", handler.GetSpeakerMappings)
 			transcription.POST("/:id/speakers", handler.UpdateSpeakerMappings)
+			transcription.GET("/:id/speaker-matches", handler.ListSpeakerMatches)
+			transcription.POST("/:id/speaker-matches/:speaker/confirm", handler.ConfirmSpeakerMatch)
+			transcription.PUT("/:id/speaker-matches/:speaker", handler.CorrectSpeakerMatch)
 
 			// Quick transcription endpoints
-			transcription.POST("/quick", handler.SubmitQuickTranscription)
+			transcription.POST("/quick", requireResources, requireAudioQuota, handler.SubmitQuickTranscription)
 			transcription.GET("/quick/:id", handler.GetQuickTranscriptionStatus)
 		}
 
@@ -142,18 +236,57 @@ func SetupRoutes(handler *Handler, authService *auth.AuthService) *gin.Engine {
 		{
 			user.GET("/default-profile", handler.GetUserDefaultProfile)
 			user.POST("/default-profile", handler.SetUserDefaultProfile)
+			user.GET("/default-summary-template", handler.GetDefaultSummaryTemplate)
+			user.POST("/default-summary-template", handler.SetDefaultSummaryTemplate)
 			user.GET("/settings", handler.GetUserSettings)
 			user.PUT("/settings", handler.UpdateUserSettings)
 		}
 
 		// Admin routes (require authentication)
 		admin := v1.Group("/admin")
-		admin.Use(middleware.AuthMiddleware(authService))
+		admin.Use(middleware.AuthMiddleware(authService), middleware.AdminOnlyMiddleware())
 		{
+			admin.GET("/users", handler.ListUsers)
+			admin.POST("/users", handler.CreateUser)
+			admin.PUT("/users/:id", handler.UpdateUser)
+			admin.DELETE("/users/:id", handler.DeleteUser)
 			queue := admin.Group("/queue")
 			{
+				queue.GET("", handler.ListQueue)
 				queue.GET("/stats", handler.GetQueueStats)
+				queue.POST("/pause", handler.PauseQueue)
+				queue.POST("/resume", handler.ResumeQueue)
+				queue.PUT("/concurrency", handler.SetQueueConcurrency)
+				queue.POST("/:id/move", handler.MoveQueuedJob)
+				queue.GET("/running", handler.ListRunningJobs)
+				queue.POST("/watchdog/check", handler.CheckStuckJobs)
 			}
+			admin.GET("/standing-context", handler.GetStandingContext)
+			admin.PUT("/standing-context", handler.UpdateStandingContext)
+			admin.GET("/settings/export", handler.ExportSettings)
+			admin.POST("/settings/import", handler.ImportSettings)
+			admin.GET("/resources", handler.GetResourceStatus)
+			admin.GET("/stats", timeouts.Timeout(middleware.TimeoutRead), handler.GetAdminStats)
+			admin.GET("/email-in", handler.ListInboundEmails)
+			admin.POST("/email-in/check", handler.CheckEmailIn)
+			admin.GET("/workflow-failures", handler.ListWorkflowFailures)
+			admin.POST("/workflow-failures/requeue", handler.RequeueDeadLetteredSteps)
+			admin.POST("/workflow-failures/:step_id/requeue", handler.RequeueWorkflowStep)
+			admin.GET("/resummarize/candidates", handler.ListResummarizeCandidates)
+			admin.POST("/resummarize", handler.StartResummarize)
+			admin.GET("/resummarize/runs", handler.ListResummarizeRuns)
+			admin.GET("/resummarize/runs/:id", handler.GetResummarizeRun)
+			admin.GET("/schedules/actions", handler.ListScheduleActions)
+			admin.GET("/schedules", handler.ListSchedules)
+			admin.POST("/schedules", handler.CreateSchedule)
+			admin.PUT("/schedules/:id", handler.UpdateSchedule)
+			admin.DELETE("/schedules/:id", handler.DeleteSchedule)
+			admin.POST("/schedules/:id/run", handler.RunSchedule)
+			admin.GET("/schedules/:id/runs", handler.ListScheduleRuns)
+			admin.GET("/legal-holds", handler.ListLegalHolds)
+			admin.GET("/audit-log", handler.ListAuditLog)
+			admin.GET("/transcription/:id/legal-hold", handler.GetLegalHold)
+			admin.PUT("/transcription/:id/legal-hold", handler.SetLegalHold)
 		}
 
 		// LLM configuration routes (require authentication)
@@ -161,7 +294,17 @@ func SetupRoutes(handler *Handler, authService *auth.AuthService) *gin.Engine {
 		llm.Use(middleware.AuthMiddleware(authService))
 		{
 			llm.GET("/config", handler.GetLLMConfig)
-			llm.POST("/config", handler.SaveLLMConfig)
+			llm.POST("/config", middleware.AdminOnlyMiddleware(), handler.SaveLLMConfig)
+			llm.GET("/providers", handler.GetLLMProviders)
+			llm.GET("/metrics", timeouts.Timeout(middleware.TimeoutRead), handler.GetLLMMetrics)
+		}
+
+		// Token usage and cost routes (require authentication)
+		usage := v1.Group("/usage")
+		usage.Use(middleware.AuthMiddleware(authService))
+		{
+			usage.GET("", timeouts.Timeout(middleware.TimeoutRead), handler.GetUsage)
+			usage.GET("/quota", handler.GetQuotaStatus)
 		}
 
 		// Summarization templates routes (require authentication)
@@ -179,21 +322,21 @@ func SetupRoutes(handler *Handler, authService *auth.AuthService) *gin.Engine {
 
 		// Chat routes (require authentication)
 		chat := v1.Group("/chat")
-		chat.Use(middleware.AuthMiddleware(authService))
+		chat.Use(middleware.AuthMiddleware(authService), requireJobAccess("transcription_id", jobOwnerQuery), requireJobAccess("session_id", chatSessionOwnerQuery))
 		{
-			chat.GET("/models", handler.GetChatModels)
+			chat.GET("/models", timeouts.Timeout(middleware.TimeoutRead), handler.GetChatModels)
 			chat.POST("/sessions", handler.CreateChatSession)
 			chat.GET("/transcriptions/:transcription_id/sessions", handler.GetChatSessions)
 			chat.GET("/sessions/:session_id", handler.GetChatSession)
-			chat.POST("/sessions/:session_id/messages", handler.SendChatMessage)
+			chat.POST("/sessions/:session_id/messages", timeouts.Timeout(middleware.TimeoutStream), requireLLMQuota, handler.SendChatMessage)
 			chat.PUT("/sessions/:session_id/title", handler.UpdateChatSessionTitle)
-			chat.POST("/sessions/:session_id/title/auto", handler.AutoGenerateChatTitle)
+			chat.POST("/sessions/:session_id/title/auto", timeouts.Timeout(middleware.TimeoutLong), requireLLMQuota, handler.AutoGenerateChatTitle)
 			chat.DELETE("/sessions/:session_id", handler.DeleteChatSession)
 		}
 
 		// Notes routes (require authentication)
 		notes := v1.Group("/notes")
-		notes.Use(middleware.AuthMiddleware(authService))
+		notes.Use(middleware.AuthMiddleware(authService), requireJobAccess("note_id", noteOwnerQuery))
 		{
 			notes.GET("/:note_id", handler.GetNote)
 			notes.PUT("/:note_id", handler.UpdateNote)
@@ -204,21 +347,271 @@ func SetupRoutes(handler *Handler, authService *auth.AuthService) *gin.Engine {
 		summarize := v1.Group("/summarize")
 		summarize.Use(middleware.AuthMiddleware(authService))
 		{
-			summarize.POST("/", handler.Summarize)
+			summarize.POST("/", timeouts.Timeout(middleware.TimeoutStream), requireLLMQuota, handler.Summarize)
 		}
 
 		// RAG routes (require authentication)
 		rag := v1.Group("/rag")
 		rag.Use(middleware.AuthMiddleware(authService))
 		{
-			rag.GET("/stats", handler.RAGStats)
-			rag.POST("/chat", handler.RAGChat)
-			rag.POST("/backfill", handler.BackfillRAG)
+			rag.GET("/stats", timeouts.Timeout(middleware.TimeoutRead), handler.RAGStats)
+			rag.GET("/collections", handler.ListRAGCollections)
+			rag.POST("/chat", timeouts.Timeout(middleware.TimeoutLong), requireLLMQuota, handler.RAGChat)
+			rag.POST("/search", timeouts.Timeout(middleware.TimeoutRead), handler.RAGSearch)
+			rag.GET("/answers/:answer_id/sources", handler.ListAnswerSources)
+			rag.GET("/topics", handler.ListTopics)
+
+			// Index maintenance works across every user's transcriptions, and topic refreshes and
+			// retrieval evaluation are for tuning the instance, so they are for admins
+			adminOnly := middleware.AdminOnlyMiddleware()
+			rag.POST("/backfill", adminOnly, timeouts.Timeout(middleware.TimeoutLong), handler.BackfillRAG)
+			rag.POST("/repair", adminOnly, timeouts.Timeout(middleware.TimeoutLong), handler.RepairRAGGaps)
+			rag.POST("/audit", adminOnly, timeouts.Timeout(middleware.TimeoutLong), handler.AuditRAG)
+			rag.POST("/topics/refresh", adminOnly, handler.RefreshTopics)
+			rag.POST("/eval", adminOnly, timeouts.Timeout(middleware.TimeoutLong), handler.RunRAGEval)
+			rag.GET("/eval/cases", adminOnly, handler.ListRAGEvalCases)
+			rag.POST("/eval/cases", adminOnly, handler.CreateRAGEvalCase)
+			rag.DELETE("/eval/cases/:case_id", adminOnly, handler.DeleteRAGEvalCase)
+		}
+
+		// Full-text search routes (require authentication)
+		searchRoutes := v1.Group("/search")
+		searchRoutes.Use(middleware.AuthMiddleware(authService))
+		{
+			searchRoutes.GET("", timeouts.Timeout(middleware.TimeoutRead), handler.SearchTranscripts)
+		}
+
+		// Meeting companion routes (require authentication)
+		companionRoutes := v1.Group("/companion")
+		companionRoutes.Use(middleware.AuthMiddleware(authService))
+		{
+			companionRoutes.GET("/sessions", handler.ListCompanionSessions)
+			companionRoutes.POST("/sessions", handler.CreateCompanionSession)
+			companionRoutes.GET("/sessions/:id", handler.GetCompanionSession)
+			companionRoutes.DELETE("/sessions/:id", handler.DeleteCompanionSession)
+			companionRoutes.POST("/sessions/:id/audio", middleware.NoCompressionMiddleware(), requireResources, requireAudioQuota, handler.AddCompanionAudio)
+			companionRoutes.POST("/sessions/:id/segments", timeouts.Timeout(middleware.TimeoutRead), handler.AddCompanionSegments)
+			companionRoutes.POST("/sessions/:id/summarize", timeouts.Timeout(middleware.TimeoutLong), requireLLMQuota, handler.SummarizeCompanionSession)
+			companionRoutes.POST("/sessions/:id/ask", timeouts.Timeout(middleware.TimeoutLong), requireLLMQuota, handler.AskCompanion)
+			companionRoutes.POST("/sessions/:id/end", handler.EndCompanionSession)
+		}
+
+		// Smart folder routes (require authentication)
+		smartFolders := v1.Group("/folders")
+		smartFolders.Use(middleware.AuthMiddleware(authService))
+		{
+			smartFolders.GET("", handler.ListSmartFolders)
+			smartFolders.POST("", handler.CreateSmartFolder)
+			smartFolders.PUT("/:id", handler.UpdateSmartFolder)
+			smartFolders.DELETE("/:id", handler.DeleteSmartFolder)
+		}
+
+		// Project routes (require authentication)
+		projectRoutes := v1.Group("/projects")
+		projectRoutes.Use(middleware.AuthMiddleware(authService))
+		{
+			projectRoutes.GET("", handler.ListProjects)
+			projectRoutes.POST("", handler.CreateProject)
+			projectRoutes.GET("/:id", handler.GetProject)
+			projectRoutes.PUT("/:id", handler.UpdateProject)
+			projectRoutes.DELETE("/:id", handler.DeleteProject)
+			projectRoutes.POST("/:id/move", handler.MoveProject)
+			projectRoutes.POST("/:id/transcriptions", handler.FileTranscriptions)
+		}
+
+		// Near-duplicate transcription routes (require authentication)
+		duplicateRoutes := v1.Group("/duplicates")
+		duplicateRoutes.Use(middleware.AuthMiddleware(authService))
+		{
+			duplicateRoutes.GET("", handler.ListDuplicates)
+			duplicateRoutes.POST("/scan", handler.ScanDuplicates)
+			duplicateRoutes.POST("/:id/merge", handler.MergeDuplicate)
+			duplicateRoutes.POST("/:id/ignore", handler.IgnoreDuplicate)
+			duplicateRoutes.POST("/:id/reopen", handler.ReopenDuplicate)
+		}
+
+		// Podcast subscription routes (require authentication)
+		podcastRoutes := v1.Group("/podcasts")
+		podcastRoutes.Use(middleware.AuthMiddleware(authService))
+		{
+			podcastRoutes.GET("", handler.ListPodcastFeeds)
+			podcastRoutes.POST("", timeouts.Timeout(middleware.TimeoutLong), handler.SubscribePodcast)
+			podcastRoutes.GET("/:id", handler.GetPodcastFeed)
+			podcastRoutes.PUT("/:id", handler.UpdatePodcastFeed)
+			podcastRoutes.DELETE("/:id", handler.UnsubscribePodcast)
+			podcastRoutes.POST("/:id/refresh", timeouts.Timeout(middleware.TimeoutLong), handler.RefreshPodcastFeed)
+			podcastRoutes.GET("/:id/episodes", handler.ListPodcastEpisodes)
+			podcastRoutes.POST("/:id/episodes/:episode_id/transcribe", handler.TranscribePodcastEpisode)
+		}
+
+		// Job template routes (require authentication)
+		jobTemplates := v1.Group("/job-templates")
+		jobTemplates.Use(middleware.AuthMiddleware(authService))
+		{
+			jobTemplates.GET("", handler.ListJobTemplates)
+			jobTemplates.POST("", handler.CreateJobTemplate)
+			jobTemplates.GET("/:id", handler.GetJobTemplate)
+			jobTemplates.PUT("/:id", handler.UpdateJobTemplate)
+			jobTemplates.DELETE("/:id", handler.DeleteJobTemplate)
+		}
+
+		// Export template routes (require authentication)
+		exportTemplates := v1.Group("/export-templates")
+		exportTemplates.Use(middleware.AuthMiddleware(authService))
+		{
+			exportTemplates.GET("", handler.ListExportTemplates)
+			exportTemplates.POST("", handler.CreateExportTemplate)
+			exportTemplates.GET("/:id", handler.GetExportTemplate)
+			exportTemplates.PUT("/:id", handler.UpdateExportTemplate)
+			exportTemplates.DELETE("/:id", handler.DeleteExportTemplate)
+		}
+
+		// Watchlist routes (require authentication)
+		watchlists := v1.Group("/watchlists")
+		watchlists.Use(middleware.AuthMiddleware(authService))
+		{
+			watchlists.GET("", handler.ListWatchlists)
+			watchlists.POST("", handler.CreateWatchlist)
+			watchlists.PUT("/:id", handler.UpdateWatchlist)
+			watchlists.DELETE("/:id", handler.DeleteWatchlist)
+			watchlists.GET("/:id/matches", handler.ListWatchlistMatches)
+		}
+
+		// Vocabulary routes (require authentication)
+		vocabularyRoutes := v1.Group("/vocabulary")
+		vocabularyRoutes.Use(middleware.AuthMiddleware(authService))
+		{
+			vocabularyRoutes.GET("", handler.ListVocabulary)
+			vocabularyRoutes.POST("", handler.CreateVocabularyTerm)
+			vocabularyRoutes.PUT("/:id", handler.UpdateVocabularyTerm)
+			vocabularyRoutes.DELETE("/:id", handler.DeleteVocabularyTerm)
+		}
+
+		// Speaker profile routes (require authentication)
+		speakerProfiles := v1.Group("/speaker-profiles")
+		speakerProfiles.Use(middleware.AuthMiddleware(authService))
+		{
+			speakerProfiles.GET("", handler.ListSpeakerProfiles)
+			speakerProfiles.POST("", handler.EnrollSpeaker)
+			speakerProfiles.PUT("/:id", handler.RenameSpeakerProfile)
+			speakerProfiles.DELETE("/:id", handler.DeleteSpeakerProfile)
+		}
+
+		// Entity routes (require authentication)
+		entities := v1.Group("/entities")
+		entities.Use(middleware.AuthMiddleware(authService))
+		{
+			entities.GET("", handler.ListEntities)
+			entities.GET("/transcriptions", handler.ListEntityTranscriptions)
+		}
+
+		// Tag routes (require authentication)
+		tagRoutes := v1.Group("/tags")
+		tagRoutes.Use(middleware.AuthMiddleware(authService))
+		{
+			tagRoutes.GET("", handler.ListTags)
+			tagRoutes.POST("", handler.CreateTag)
+			tagRoutes.PUT("/:id", handler.UpdateTag)
+			tagRoutes.DELETE("/:id", handler.DeleteTag)
+			tagRoutes.GET("/:id/transcriptions", handler.ListTagTranscriptions)
+		}
+
+		// Signed download routes: creating a link requires authentication, the link itself is the credential
+		downloads := v1.Group("/downloads")
+		{
+			downloads.POST("", middleware.AuthMiddleware(authService), handler.CreateDownloadLink)
+			downloads.GET("/:token", middleware.NoCompressionMiddleware(), handler.Download)
+		}
+
+		// Share link view: the link is the credential
+		v1.GET("/shared/:token", handler.ViewSharedTranscription)
+
+		// Action item routes (require authentication)
+		actionItems := v1.Group("/action-items")
+		actionItems.Use(middleware.AuthMiddleware(authService))
+		{
+			actionItems.GET("", handler.ListActionItems)
+			actionItems.GET("/export", handler.ExportActionItems)
+			actionItems.GET("/commitments", handler.ListSpeakerCommitments)
+			actionItems.PUT("/:id", handler.UpdateActionItem)
+		}
+
+		// Task manager integration routes (require authentication)
+		integrationRoutes := v1.Group("/integrations")
+		integrationRoutes.Use(middleware.AuthMiddleware(authService))
+		{
+			integrationRoutes.GET("", handler.ListTaskIntegrations)
+			integrationRoutes.POST("", handler.CreateTaskIntegration)
+			integrationRoutes.PUT("/:id", handler.UpdateTaskIntegration)
+			integrationRoutes.DELETE("/:id", handler.DeleteTaskIntegration)
+		}
+
+		// Event outbox routes (require authentication)
+		eventRoutes := v1.Group("/events")
+		eventRoutes.Use(middleware.AuthMiddleware(authService))
+		{
+			eventRoutes.GET("", handler.StreamEvents)
+			eventRoutes.GET("/poll", handler.PollEvents)
+		}
+
+		// Delta sync for clients keeping a local copy (require authentication)
+		v1.GET("/sync", middleware.AuthMiddleware(authService), handler.Sync)
+
+		// WebSocket multiplexing job updates and chat; browsers pass their token in the query
+		v1.GET("/ws", middleware.QueryTokenAuthMiddleware(authService), handler.WebSocket)
+
+		// Document routes for RAG (require authentication)
+		docs := v1.Group("/documents")
+		docs.Use(middleware.AuthMiddleware(authService))
+		{
+			docs.POST("", requireResources, handler.UploadDocument)
+			docs.GET("", handler.ListDocuments)
+			docs.GET("/:id", handler.GetDocument)
+			docs.POST("/:id/reindex", handler.ReindexDocument)
+			docs.DELETE("/:id", handler.DeleteDocument)
+		}
+
+		// Post-processing workflow routes (require authentication)
+		workflows := v1.Group("/workflows")
+		workflows.Use(middleware.AuthMiddleware(authService))
+		{
+			workflows.GET("", handler.ListWorkflowDefinitions)
 		}
 	}
 
+	// OpenAI-compatible routes, for OpenAI client libraries and tools pointed at Scriberr. They
+	// take API keys as bearer tokens, the way those clients send them.
+	openai := router.Group("/v1")
+	openai.Use(middleware.BearerAPIKeyMiddleware(authService), handler.rateLimit(authService), handler.auditLog(), middleware.AuthMiddleware(authService))
+	{
+		openai.POST("/audio/transcriptions", requireResources, requireAudioQuota, handler.CreateOpenAITranscription)
+		openai.POST("/chat/completions", timeouts.Timeout(middleware.TimeoutStream), requireLLMQuota, handler.CreateOpenAIChatCompletion)
+		openai.GET("/models", timeouts.Timeout(middleware.TimeoutRead), handler.ListOpenAIModels)
+	}
+
+	// Model Context Protocol endpoint, for MCP clients such as Claude Desktop. Like the
+	// OpenAI-compatible routes, it takes API keys as bearer tokens.
+	mcpRoutes := router.Group("/mcp")
+	mcpRoutes.Use(middleware.BearerAPIKeyMiddleware(authService), handler.rateLimit(authService), handler.auditLog(), middleware.AuthMiddleware(authService))
+	{
+		mcpRoutes.POST("", timeouts.Timeout(middleware.TimeoutLong), handler.MCP)
+		mcpRoutes.GET("", handler.MCPStream)
+	}
+
 	// Set up static file serving for React app
 	web.SetupStaticRoutes(router, authService)
 
 	return router
 }
+
+// requestTimeouts builds the timeout policy from the configured per-class timeouts
+func requestTimeouts(cfg *config.Config) middleware.TimeoutPolicy {
+	if cfg == nil {
+		return middleware.TimeoutPolicy{}
+	}
+	return middleware.TimeoutPolicy{
+		middleware.TimeoutRead:   time.Duration(cfg.RequestTimeoutReadSeconds) * time.Second,
+		middleware.TimeoutLong:   time.Duration(cfg.RequestTimeoutLongSeconds) * time.Second,
+		middleware.TimeoutStream: time.Duration(cfg.RequestTimeoutStreamSeconds) * time.Second,
+	}
+}
@@ -0,0 +1,543 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/dropzone"
+	"scriberr/internal/models"
+	"scriberr/internal/projects"
+	"scriberr/internal/scheduler"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Built-in schedule actions
+const (
+	ActionDropzoneScan     = "dropzone_scan"
+	ActionDuplicateScan    = "duplicate_scan"
+	ActionRAGBackfill      = "rag_backfill"
+	ActionRetentionCleanup = "retention_cleanup"
+	ActionStorageCleanup   = "storage_cleanup"
+)
+
+// SetScheduler sets the scheduler and registers the built-in actions with it
+func (h *Handler) SetScheduler(service *scheduler.Service) {
+	h.scheduler = service
+	if service == nil {
+		return
+	}
+	service.Register(scheduler.Action{
+		Name:        ActionDropzoneScan,
+		Description: "Upload audio files waiting in the dropzone that the watcher missed",
+		Run:         h.runDropzoneScan,
+	})
+	service.Register(scheduler.Action{
+		Name:        ActionDuplicateScan,
+		Description: "Flag pairs of indexed transcriptions whose embeddings are at least min_similarity alike (default 0.95) and whose transcripts share at least min_overlap of their word sequences (default 0.3) as near-duplicates to merge or ignore",
+		Run:         h.runDuplicateScan,
+		Validate: func(params map[string]interface{}) error {
+			_, err := duplicateOptions(params)
+			return err
+		},
+	})
+	service.Register(scheduler.Action{
+		Name:        ActionRAGBackfill,
+		Description: "Index completed transcriptions in RAG; only_missing (default true) limits it to those missing from the vector store",
+		Run:         h.runRAGBackfill,
+		Validate: func(params map[string]interface{}) error {
+			_, err := scheduler.BoolParam(params, "only_missing", true)
+			return err
+		},
+	})
+	service.Register(scheduler.Action{
+		Name:        ActionRetentionCleanup,
+		Description: "Delete the audio of finished transcriptions older than audio_older_than_days, keeping transcripts and summaries, and delete those older than delete_older_than_days entirely, vector store documents included; a project's retention settings replace these, and transcriptions under legal hold are skipped",
+		Run:         h.runRetentionCleanup,
+		Validate: func(params map[string]interface{}) error {
+			_, err := retentionParams(params)
+			return err
+		},
+	})
+	service.Register(scheduler.Action{
+		Name:        ActionStorageCleanup,
+		Description: "Delete uploads in object storage that no transcription refers to, and the stored transcripts of deleted transcriptions, once older than min_age_hours (default 24)",
+		Run:         h.runStorageCleanup,
+		Validate: func(params map[string]interface{}) error {
+			_, err := storageMinAgeHours(params)
+			return err
+		},
+	})
+}
+
+func (h *Handler) runDropzoneScan(ctx context.Context, params map[string]interface{}) (map[string]interface{}, error) {
+	service := dropzone.NewService(h.config, h.taskQueue)
+	if h.files != nil {
+		service.SetFileStore(h.files)
+	}
+	uploaded, err := service.Scan()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"uploaded": uploaded}, nil
+}
+
+func (h *Handler) runRAGBackfill(ctx context.Context, params map[string]interface{}) (map[string]interface{}, error) {
+	if h.ragService == nil {
+		return nil, errors.New("RAG service not initialized")
+	}
+	onlyMissing, err := scheduler.BoolParam(params, "only_missing", true)
+	if err != nil {
+		return nil, err
+	}
+
+	query := database.DB.Where("status = ?", models.StatusCompleted).Where("transcript IS NOT NULL AND transcript != ''")
+	if onlyMissing {
+		missing, err := h.ragService.FindMissing(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect index gaps: %w", err)
+		}
+		if len(missing) == 0 {
+			return map[string]interface{}{"total": 0, "processed": 0, "failed": 0}, nil
+		}
+		query = query.Where("id IN ?", missing)
+	}
+	var jobs []models.TranscriptionJob
+	if err := query.Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch transcriptions: %w", err)
+	}
+
+	processed, failed := 0, 0
+	for i := range jobs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err := h.storeJobInRAG(&jobs[i]); err != nil {
+			failed++
+			continue
+		}
+		processed++
+	}
+	return map[string]interface{}{"total": len(jobs), "processed": processed, "failed": failed}, nil
+}
+
+// retentionRules are how many days after they were created transcriptions keep their audio
+// and are kept at all; 0 keeps them forever
+type retentionRules struct {
+	audioDays  int
+	deleteDays int
+}
+
+// retentionParams reads the audio_older_than_days and delete_older_than_days params of a
+// retention cleanup, at least one of which is required
+func retentionParams(params map[string]interface{}) (retentionRules, error) {
+	audioDays, err := scheduler.IntParam(params, "audio_older_than_days", 0)
+	if err != nil {
+		return retentionRules{}, err
+	}
+	deleteDays, err := scheduler.IntParam(params, "delete_older_than_days", 0)
+	if err != nil {
+		return retentionRules{}, err
+	}
+	if audioDays < 0 || deleteDays < 0 {
+		return retentionRules{}, errors.New("audio_older_than_days and delete_older_than_days can't be negative")
+	}
+	if audioDays == 0 && deleteDays == 0 {
+		return retentionRules{}, errors.New("audio_older_than_days or delete_older_than_days is required")
+	}
+	return retentionRules{audioDays: audioDays, deleteDays: deleteDays}, nil
+}
+
+// forProject returns the rules for the transcriptions of a project, whose settings replace
+// the cleanup's own
+func (r retentionRules) forProject(settings models.ProjectSettings) retentionRules {
+	if settings.AudioRetentionDays != nil {
+		r.audioDays = *settings.AudioRetentionDays
+	}
+	if settings.RetentionDays != nil {
+		r.deleteDays = *settings.RetentionDays
+	}
+	return r
+}
+
+// expired reports whether something created at created is past a retention of days at now
+func expired(days int, created, now time.Time) bool {
+	return days > 0 && created.Before(now.AddDate(0, 0, -days))
+}
+
+// runRetentionCleanup deletes finished transcriptions past their retention, along with their
+// documents in the vector store, and the audio of those past their audio retention. The
+// rules of a transcription's project come before the cleanup's own; transcriptions under
+// legal hold are skipped.
+func (h *Handler) runRetentionCleanup(ctx context.Context, params map[string]interface{}) (map[string]interface{}, error) {
+	rules, err := retentionParams(params)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	// No rule is shorter than a day, so younger transcriptions needn't be looked at
+	var jobs []models.TranscriptionJob
+	if err := database.DB.Select("id", "project_id", "created_at", "audio_path").
+		Where("status IN ?", []models.JobStatus{models.StatusCompleted, models.StatusFailed}).
+		Where("legal_hold = ? AND created_at < ? AND id NOT LIKE 'track_%'", false, now.AddDate(0, 0, -1)).
+		Order("created_at ASC").
+		Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch transcriptions: %w", err)
+	}
+
+	projectRules := map[string]retentionRules{}
+	audioDeleted, deleted, failed := 0, 0, 0
+	for i := range jobs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		candidate := &jobs[i]
+		jobRules := rules
+		if candidate.ProjectID != nil {
+			cached, ok := projectRules[*candidate.ProjectID]
+			if !ok {
+				settings, err := projects.Settings(*candidate.ProjectID)
+				if err != nil {
+					return nil, err
+				}
+				cached = rules.forProject(settings)
+				projectRules[*candidate.ProjectID] = cached
+			}
+			jobRules = cached
+		}
+
+		switch {
+		case expired(jobRules.deleteDays, candidate.CreatedAt, now):
+			if err := h.deleteIndexedJob(ctx, candidate.ID); err != nil {
+				// A hold placed since the transcriptions were listed keeps it
+				if errors.Is(err, errLegalHold) {
+					continue
+				}
+				logger.ErrorContext(ctx, "Retention cleanup failed to delete transcription", "job_id", candidate.ID, "error", err)
+				failed++
+				continue
+			}
+			deleted++
+		case candidate.AudioPath != "" && expired(jobRules.audioDays, candidate.CreatedAt, now):
+			var job models.TranscriptionJob
+			if err := database.DB.Where("id = ?", candidate.ID).First(&job).Error; err != nil {
+				logger.ErrorContext(ctx, "Retention cleanup failed to load transcription", "job_id", candidate.ID, "error", err)
+				failed++
+				continue
+			}
+			if job.LegalHold {
+				continue
+			}
+			if err := h.deleteJobAudio(&job); err != nil {
+				logger.ErrorContext(ctx, "Retention cleanup failed to delete audio", "job_id", candidate.ID, "error", err)
+				failed++
+				continue
+			}
+			audioDeleted++
+		}
+	}
+	return map[string]interface{}{"audio_deleted": audioDeleted, "transcriptions_deleted": deleted, "failed": failed}, nil
+}
+
+// errLegalHold is returned by deleteIndexedJob for a transcription under legal hold
+var errLegalHold = errors.New("transcription is under legal hold")
+
+// deleteIndexedJob deletes a transcription along with its vector store documents, removing
+// those first so a failure there leaves the transcription in place to be retried. The hold
+// is checked on the freshly loaded transcription, so callers that looked at it earlier
+// can't delete one held since.
+func (h *Handler) deleteIndexedJob(ctx context.Context, jobID string) error {
+	var job models.TranscriptionJob
+	if err := database.DB.Where("id = ?", jobID).First(&job).Error; err != nil {
+		return err
+	}
+	if job.LegalHold {
+		return errLegalHold
+	}
+	if h.ragService != nil {
+		if err := h.ragService.DeleteTranscription(ctx, job.ID); err != nil {
+			return err
+		}
+	}
+	return h.deleteJob(&job)
+}
+
+// ScheduleRequest creates or updates a schedule
+type ScheduleRequest struct {
+	Name    string                 `json:"name" binding:"required"`
+	Action  string                 `json:"action" binding:"required"`
+	Cron    string                 `json:"cron" binding:"required"` // Five fields or a macro such as @daily, in server time
+	Params  map[string]interface{} `json:"params,omitempty"`
+	Enabled *bool                  `json:"enabled,omitempty"` // Defaults to true
+}
+
+// requireScheduler writes an error response when the scheduler isn't running
+func (h *Handler) requireScheduler(c *gin.Context) bool {
+	if h.scheduler == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Scheduler not initialized"})
+		return false
+	}
+	return true
+}
+
+// loadSchedule fetches the schedule in the id path parameter, writing a 404 or 500 response if that fails
+func loadSchedule(c *gin.Context) (*models.Schedule, bool) {
+	var schedule models.Schedule
+	if err := database.DB.Where("id = ?", c.Param("id")).First(&schedule).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get schedule"})
+		return nil, false
+	}
+	return &schedule, true
+}
+
+// applyScheduleRequest copies a request onto a schedule and checks it, writing a 400 response
+// if it isn't valid
+func (h *Handler) applyScheduleRequest(c *gin.Context, schedule *models.Schedule) bool {
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	schedule.Name = strings.TrimSpace(req.Name)
+	schedule.Action = req.Action
+	schedule.Cron = strings.TrimSpace(req.Cron)
+	schedule.Params = req.Params
+	schedule.Enabled = req.Enabled == nil || *req.Enabled
+	if schedule.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return false
+	}
+	if err := h.scheduler.Prepare(schedule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+// ListScheduleActions returns the actions schedules can run
+// @Summary List schedule actions
+// @Description List the maintenance actions a schedule can run, with the params each takes
+// @Tags schedules
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/schedules/actions [get]
+func (h *Handler) ListScheduleActions(c *gin.Context) {
+	if !h.requireScheduler(c) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"actions": h.scheduler.Actions()})
+}
+
+// ListSchedules returns every schedule
+// @Summary List schedules
+// @Description List the schedules with when each runs next and how its last run went
+// @Tags schedules
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/schedules [get]
+func (h *Handler) ListSchedules(c *gin.Context) {
+	schedules := []models.Schedule{}
+	if err := database.DB.Order("name ASC").Find(&schedules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list schedules"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"schedules": schedules})
+}
+
+// CreateSchedule adds a schedule
+// @Summary Create a schedule
+// @Description Run a maintenance action (dropzone_scan, rag_backfill or retention_cleanup) on a cron expression: five fields (minute hour day-of-month month day-of-week) or @hourly, @daily, @weekly, @monthly or @yearly, in server time. A schedule missed while the server was down runs once when it comes back.
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param request body ScheduleRequest true "Schedule"
+// @Success 201 {object} models.Schedule
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/schedules [post]
+func (h *Handler) CreateSchedule(c *gin.Context) {
+	if !h.requireScheduler(c) {
+		return
+	}
+	schedule := models.Schedule{CreatedBy: currentUserID(c)}
+	if !h.applyScheduleRequest(c, &schedule) {
+		return
+	}
+	if err := database.DB.Create(&schedule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create schedule"})
+		return
+	}
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// UpdateSchedule replaces a schedule's settings
+// @Summary Update a schedule
+// @Description Replace a schedule's name, action, cron expression, params and enabled flag. When it next runs is worked out again from now.
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param request body ScheduleRequest true "Schedule"
+// @Success 200 {object} models.Schedule
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/schedules/{id} [put]
+func (h *Handler) UpdateSchedule(c *gin.Context) {
+	if !h.requireScheduler(c) {
+		return
+	}
+	schedule, ok := loadSchedule(c)
+	if !ok {
+		return
+	}
+	if !h.applyScheduleRequest(c, schedule) {
+		return
+	}
+	if err := database.DB.Select("name", "action", "cron", "params", "enabled", "next_run_at").Save(schedule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update schedule"})
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteSchedule removes a schedule and its run history
+// @Summary Delete a schedule
+// @Description Delete a schedule and its run history. A run in progress finishes.
+// @Tags schedules
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/schedules/{id} [delete]
+func (h *Handler) DeleteSchedule(c *gin.Context) {
+	schedule, ok := loadSchedule(c)
+	if !ok {
+		return
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("schedule_id = ?", schedule.ID).Delete(&models.ScheduleRun{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(schedule).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete schedule"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Schedule deleted"})
+}
+
+// RunSchedule runs a schedule's action now
+// @Summary Run a schedule now
+// @Description Run a schedule's action now, whether or not it is enabled, without changing when it next runs. Runs in the background; poll the run history for the outcome.
+// @Tags schedules
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 202 {object} models.ScheduleRun
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/schedules/{id}/run [post]
+func (h *Handler) RunSchedule(c *gin.Context) {
+	if !h.requireScheduler(c) {
+		return
+	}
+	schedule, ok := loadSchedule(c)
+	if !ok {
+		return
+	}
+	run, err := h.scheduler.Trigger(schedule, scheduler.TriggerManual)
+	switch {
+	case errors.Is(err, scheduler.ErrRunInProgress):
+		c.JSON(http.StatusConflict, gin.H{"error": "The schedule is already running"})
+		return
+	case errors.Is(err, scheduler.ErrUnknownAction):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, run)
+}
+
+// ListScheduleRuns returns a schedule's run history
+// @Summary List a schedule's runs
+// @Description List a schedule's runs, newest first, with what each action reported or why it failed
+// @Tags schedules
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Runs per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/schedules/{id}/runs [get]
+func (h *Handler) ListScheduleRuns(c *gin.Context) {
+	schedule, ok := loadSchedule(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	query := func() *gorm.DB {
+		return database.DB.Model(&models.ScheduleRun{}).Where("schedule_id = ?", schedule.ID)
+	}
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count schedule runs"})
+		return
+	}
+	runs := []models.ScheduleRun{}
+	if err := query().Order("started_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&runs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list schedule runs"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"runs": runs,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/search"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxFullTextResults caps the results of a page of full-text search
+const maxFullTextResults = 100
+
+// searchDate parses the from or to of a search, a date or an RFC 3339 time. A date in to
+// includes the whole day. It writes an error response if the value is invalid.
+func searchDate(c *gin.Context, name string) (*time.Time, bool) {
+	value := strings.TrimSpace(c.Query(name))
+	if value == "" {
+		return nil, true
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, true
+	}
+	parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be a date (YYYY-MM-DD) or an RFC 3339 time"})
+		return nil, false
+	}
+	if name == "to" {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+	return &parsed, true
+}
+
+// SearchTranscripts finds transcript segments containing words or phrases
+// @Summary Full-text search over transcripts
+// @Description Find the transcript segments of the caller's transcriptions that contain the words of q, best match first, with their recording, time, speaker and a snippet. Every word must occur; "quoted phrases" must occur as written, a word ending in * matches words starting with it, OR between two terms matches either, and a term starting with - must not occur. Matching ignores case and accents. The snippet is HTML-escaped text around the match with the matched words in <mark>. Unlike /rag/search, which finds passages by meaning, this finds exact terms, names and numbers and needs no embeddings.
+// @Tags search
+// @Produce json
+// @Param q query string true "Search query"
+// @Param speaker query string false "Only segments of this speaker, by label or custom name"
+// @Param tag query []string false "Only transcriptions with every one of these tags (repeat for several)" collectionFormat(multi)
+// @Param from query string false "Only transcriptions created on or after this date (YYYY-MM-DD) or time (RFC 3339)"
+// @Param to query string false "Only transcriptions created on or before this date, or before this time"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Results per page, at most 100" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/search [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) SearchTranscripts(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page must be a positive integer"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > maxFullTextResults {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+	from, ok := searchDate(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := searchDate(c, "to")
+	if !ok {
+		return
+	}
+
+	hits, total, err := search.Transcripts(currentUserID(c), search.Options{
+		Query:         q,
+		Speaker:       strings.TrimSpace(c.Query("speaker")),
+		Tags:          c.QueryArray("tag"),
+		CreatedAfter:  from,
+		CreatedBefore: to,
+		Limit:         limit,
+		Offset:        (page - 1) * limit,
+	})
+	if errors.Is(err, search.ErrEmptyQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search transcripts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":   q,
+		"results": hits,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
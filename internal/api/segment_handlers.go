@@ -0,0 +1,320 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/export"
+	"scriberr/internal/models"
+	"scriberr/internal/revisions"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSegmentSpeakerLength caps speaker labels given in corrections, as speaker mappings do
+const maxSegmentSpeakerLength = 50
+
+// SegmentEditRequest corrects a transcript segment's text, speaker or both
+type SegmentEditRequest struct {
+	Text    *string `json:"text"`
+	Speaker *string `json:"speaker"` // Diarization label, e.g. SPEAKER_01
+}
+
+// storedSegment is a transcript segment as stored, with its timing decoded. The raw JSON is
+// returned as is, so segments keep any word timings the transcription engine added.
+type storedSegment struct {
+	raw        map[string]json.RawMessage
+	start, end float64
+}
+
+// ListTranscriptSegments returns a page of a transcript's segments
+// @Summary List transcript segments
+// @Description Get the segments of a completed transcript a page at a time, optionally only those overlapping a time range, so long transcripts can be loaded piece by piece. Segments are returned as stored, with an index giving their position in the whole transcript. Transcripts stored as plain text have one untimed segment per line, which a time range leaves out.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param from query number false "Only segments ending after this many seconds"
+// @Param to query number false "Only segments starting before this many seconds"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Segments per page" default(200)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/segments [get]
+func (h *Handler) ListTranscriptSegments(c *gin.Context) {
+	from, to := 0.0, -1.0
+	for name, value := range map[string]*float64{"from": &from, "to": &to} {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(raw, 64)
+		if err != nil || seconds < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s must be a non-negative number of seconds", name)})
+			return
+		}
+		*value = seconds
+	}
+	if to >= 0 && to <= from {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be after from"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "200"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 1000 {
+		limit = 200
+	}
+
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	if job.Status != models.StatusCompleted {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Job not completed, current status: %s", job.Status)})
+		return
+	}
+	if job.Transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcript not available"})
+		return
+	}
+	segments, err := storedSegments(job)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse transcript"})
+		return
+	}
+
+	ranged := c.Query("from") != "" || c.Query("to") != ""
+	duration := 0.0
+	var indexes []int
+	for i, segment := range segments {
+		duration = max(duration, segment.end)
+		if ranged && segment.start == 0 && segment.end == 0 {
+			continue // Untimed
+		}
+		overlaps := (segment.end > from || segment.start >= from) && (to < 0 || segment.start < to)
+		if overlaps {
+			indexes = append(indexes, i)
+		}
+	}
+
+	total := len(indexes)
+	selected := []map[string]json.RawMessage{}
+	for _, i := range indexes[min((page-1)*limit, total):min(page*limit, total)] {
+		segment := segments[i].raw
+		segment["index"] = json.RawMessage(strconv.Itoa(i))
+		selected = append(selected, segment)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":         job.ID,
+		"segments":       selected,
+		"total_segments": len(segments),
+		"duration":       duration,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + limit - 1) / limit,
+		},
+	})
+}
+
+// storedSegments decodes the segments of a job's transcript, keeping each segment's JSON.
+// Transcripts without segments become one untimed segment per line of text.
+func storedSegments(job *models.TranscriptionJob) ([]storedSegment, error) {
+	var transcript struct {
+		Segments []map[string]json.RawMessage `json:"segments"`
+	}
+	if strings.HasPrefix(strings.TrimSpace(*job.Transcript), "{") {
+		if err := json.Unmarshal([]byte(*job.Transcript), &transcript); err != nil {
+			return nil, err
+		}
+	}
+	if len(transcript.Segments) == 0 {
+		var segments []storedSegment
+		for _, segment := range export.TranscriptSegments(job) {
+			text, _ := json.Marshal(segment.Text)
+			segments = append(segments, storedSegment{raw: map[string]json.RawMessage{
+				"start": json.RawMessage("0"),
+				"end":   json.RawMessage("0"),
+				"text":  text,
+			}})
+		}
+		return segments, nil
+	}
+
+	segments := make([]storedSegment, 0, len(transcript.Segments))
+	for _, raw := range transcript.Segments {
+		segment := storedSegment{raw: raw}
+		if value, ok := raw["start"]; ok {
+			if err := json.Unmarshal(value, &segment.start); err != nil {
+				return nil, err
+			}
+		}
+		if value, ok := raw["end"]; ok {
+			if err := json.Unmarshal(value, &segment.end); err != nil {
+				return nil, err
+			}
+		}
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+// EditTranscriptSegment corrects the text or speaker of one segment of a transcript
+// @Summary Correct a transcript segment
+// @Description Replace the text or speaker of a transcript segment, at the index ListTranscriptSegments gives it, keeping what it replaced as a revision. Correcting the text drops the segment's word timings, which no longer match it. If the transcription is in the RAG index it is re-indexed, which re-embeds only the chunks the correction touched, so chat answers reflect it.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param index path int true "Segment index"
+// @Param request body SegmentEditRequest true "Corrected text and/or speaker"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/segments/{index} [patch]
+func (h *Handler) EditTranscriptSegment(c *gin.Context) {
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "index must be a non-negative integer"})
+		return
+	}
+	var req SegmentEditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Text == nil && req.Speaker == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Give the corrected text, speaker or both"})
+		return
+	}
+	if req.Text != nil {
+		text := strings.TrimSpace(*req.Text)
+		if text == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "text cannot be empty"})
+			return
+		}
+		req.Text = &text
+	}
+	if req.Speaker != nil {
+		speaker := strings.TrimSpace(*req.Speaker)
+		if speaker == "" || len(speaker) > maxSegmentSpeakerLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("speaker must be 1 to %d characters", maxSegmentSpeakerLength)})
+			return
+		}
+		req.Speaker = &speaker
+	}
+
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	if rejectIfOnHold(c, job) {
+		return
+	}
+	if job.Status != models.StatusCompleted {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Job not completed, current status: %s", job.Status)})
+		return
+	}
+	if job.Transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcript not available"})
+		return
+	}
+
+	edits := []revisions.Edit{{Segment: index, Text: req.Text, Speaker: req.Speaker}}
+	saved, err := revisions.Apply(job, edits, currentUserID(c), models.RevisionSourceManual)
+	if err != nil {
+		switch {
+		case errors.Is(err, revisions.ErrNoSegments):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The transcript is plain text and has no segments to correct"})
+		case errors.Is(err, revisions.ErrSegmentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Segment not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save correction"})
+		}
+		return
+	}
+	var revision *models.TranscriptRevision
+	if len(saved) > 0 {
+		revision = &saved[0]
+	}
+
+	// Chat answers should reflect the correction right away
+	reindexed := false
+	if revision != nil && h.ragService != nil {
+		indexed, err := h.ragService.IsIndexed(job.ID)
+		if err == nil && indexed {
+			if err = h.storeJobInRAG(job); err == nil {
+				reindexed = true
+			}
+		}
+		if err != nil {
+			logger.ErrorContext(c.Request.Context(), "Failed to re-index edited transcription", "job_id", job.ID, "error", err)
+		}
+	}
+
+	segments, err := storedSegments(job)
+	if err != nil || index >= len(segments) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse transcript"})
+		return
+	}
+	segment := segments[index].raw
+	segment["index"] = json.RawMessage(strconv.Itoa(index))
+	c.JSON(http.StatusOK, gin.H{
+		"segment":   segment,
+		"revision":  revision,
+		"reindexed": reindexed,
+	})
+}
+
+// ListTranscriptRevisions returns the corrections made to a transcript
+// @Summary List transcript corrections
+// @Description List the corrections made to a transcript's segments, newest first, each with the text and speaker it replaced
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param segment query int false "Only corrections to the segment at this index"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/revisions [get]
+func (h *Handler) ListTranscriptRevisions(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	query := database.DB.Where("transcription_id = ?", job.ID)
+	if raw := c.Query("segment"); raw != "" {
+		index, err := strconv.Atoi(raw)
+		if err != nil || index < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "segment must be a non-negative integer"})
+			return
+		}
+		query = query.Where("segment_index = ?", index)
+	}
+	list := []models.TranscriptRevision{}
+	if err := query.Order("created_at DESC, id DESC").Find(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list transcript revisions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"transcription_id": job.ID, "revisions": list})
+}
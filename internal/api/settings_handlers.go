@@ -0,0 +1,260 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// settingsBundleVersion is the version of the settings document this server writes and reads
+const settingsBundleVersion = 1
+
+// SettingsBundle is a portable copy of an instance's settings. Provider credentials, API keys
+// and transcriptions are not part of it.
+type SettingsBundle struct {
+	Version          int                           `json:"version"`
+	ExportedAt       time.Time                     `json:"exported_at"`
+	Profiles         []models.TranscriptionProfile `json:"profiles"`
+	SummaryTemplates []models.SummaryTemplate      `json:"summary_templates"`
+	SummarySettings  *SummarySettingsResponse      `json:"summary_settings,omitempty"`
+	StandingContext  *string                       `json:"standing_context,omitempty"`
+	UserSettings     *UserSettingsResponse         `json:"user_settings,omitempty"`
+}
+
+// ImportCounts counts the records an import created and updated
+type ImportCounts struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// SettingsImportResult describes what an import changed
+type SettingsImportResult struct {
+	Profiles         ImportCounts `json:"profiles"`
+	SummaryTemplates ImportCounts `json:"summary_templates"`
+	SummarySettings  bool         `json:"summary_settings"`
+	StandingContext  bool         `json:"standing_context"`
+	UserSettings     bool         `json:"user_settings"`
+}
+
+// ExportSettings returns the instance's settings as one JSON document
+// @Summary Export settings
+// @Description Download the transcription profiles, the caller's and the shared summary templates, the summary settings, the standing context and the caller's own settings as one JSON document that can be imported into another instance. LLM provider credentials and API keys are left out.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} SettingsBundle
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/settings/export [get]
+func (h *Handler) ExportSettings(c *gin.Context) {
+	bundle := SettingsBundle{
+		Version:          settingsBundleVersion,
+		ExportedAt:       time.Now().UTC(),
+		Profiles:         []models.TranscriptionProfile{},
+		SummaryTemplates: []models.SummaryTemplate{},
+	}
+	if err := database.DB.Order("created_at").Find(&bundle.Profiles).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch profiles"})
+		return
+	}
+	userID := currentUserID(c)
+	if err := visibleTemplates(database.DB, userID).Order("created_at").Find(&bundle.SummaryTemplates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch templates"})
+		return
+	}
+
+	var summarySettings models.SummarySetting
+	if err := database.DB.Limit(1).Find(&summarySettings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch settings"})
+		return
+	}
+	if summarySettings.ID != 0 {
+		bundle.SummarySettings = &SummarySettingsResponse{DefaultModel: summarySettings.DefaultModel}
+	}
+
+	var standing models.StandingContext
+	if err := database.DB.Limit(1).Find(&standing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get standing context"})
+		return
+	}
+	if standing.ID != 0 {
+		bundle.StandingContext = &standing.Content
+	}
+
+	if userID != nil {
+		var user models.User
+		if err := database.DB.First(&user, *userID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+			return
+		}
+		bundle.UserSettings = &UserSettingsResponse{
+			AutoTranscriptionEnabled: user.AutoTranscriptionEnabled,
+			DefaultProfileID:         user.DefaultProfileID,
+			DefaultSummaryTemplateID: user.DefaultSummaryTemplateID,
+		}
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="scriberr-settings-%s.json"`, bundle.ExportedAt.Format("2006-01-02")))
+	c.JSON(http.StatusOK, bundle)
+}
+
+// ImportSettings applies an exported settings document to this instance
+// @Summary Import settings
+// @Description Apply a document from the settings export. Profiles are matched by name and summary templates by name and owner: matches are updated and the rest are created, and nothing is deleted. Templates that were shared stay shared and the rest become the caller's. The caller's default profile and template are pointed at the imported records. Sections missing from the document are left alone.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body SettingsBundle true "Exported settings"
+// @Success 200 {object} SettingsImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/settings/import [post]
+func (h *Handler) ImportSettings(c *gin.Context) {
+	var bundle SettingsBundle
+	if err := c.ShouldBindJSON(&bundle); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if bundle.Version != settingsBundleVersion {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unsupported settings version %d", bundle.Version)})
+		return
+	}
+	for _, profile := range bundle.Profiles {
+		if strings.TrimSpace(profile.Name) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Profile name is required"})
+			return
+		}
+	}
+	for _, template := range bundle.SummaryTemplates {
+		if strings.TrimSpace(template.Name) == "" || template.Model == "" || template.Prompt == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("summary template %q needs a name, model and prompt", template.Name)})
+			return
+		}
+		if template.Layout != models.SummaryLayoutDefault && template.Layout != models.SummaryLayoutPerSpeaker {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("summary template %q has an unknown layout", template.Name)})
+			return
+		}
+	}
+	if bundle.StandingContext != nil && len(*bundle.StandingContext) > maxStandingContextLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "standing context is too long"})
+		return
+	}
+
+	userID := currentUserID(c)
+	var result SettingsImportResult
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Exported IDs mean nothing here, so references to them are mapped to the local records
+		profileIDs := make(map[string]string, len(bundle.Profiles))
+		for _, profile := range bundle.Profiles {
+			exportedID := profile.ID
+			profile.CreatedAt, profile.UpdatedAt = time.Time{}, time.Time{}
+			var existing models.TranscriptionProfile
+			if err := tx.Where("name = ?", profile.Name).Limit(1).Find(&existing).Error; err != nil {
+				return err
+			}
+			if existing.ID != "" {
+				profile.ID = existing.ID
+				profile.CreatedAt = existing.CreatedAt
+				if err := tx.Save(&profile).Error; err != nil {
+					return err
+				}
+				result.Profiles.Updated++
+			} else {
+				profile.ID = ""
+				if err := tx.Create(&profile).Error; err != nil {
+					return err
+				}
+				result.Profiles.Created++
+			}
+			profileIDs[exportedID] = profile.ID
+		}
+
+		templateIDs := make(map[string]string, len(bundle.SummaryTemplates))
+		for _, template := range bundle.SummaryTemplates {
+			exportedID := template.ID
+			template.CreatedAt, template.UpdatedAt = time.Time{}, time.Time{}
+			var owner *uint
+			if template.UserID != nil {
+				owner = userID
+			}
+			template.UserID = owner
+			var existing models.SummaryTemplate
+			if err := scopeToOwner(tx.Where("name = ?", template.Name), owner).Limit(1).Find(&existing).Error; err != nil {
+				return err
+			}
+			if existing.ID != "" {
+				template.ID = existing.ID
+				template.CreatedAt = existing.CreatedAt
+				if err := tx.Save(&template).Error; err != nil {
+					return err
+				}
+				result.SummaryTemplates.Updated++
+			} else {
+				template.ID = ""
+				if err := tx.Create(&template).Error; err != nil {
+					return err
+				}
+				result.SummaryTemplates.Created++
+			}
+			templateIDs[exportedID] = template.ID
+		}
+
+		if bundle.SummarySettings != nil && bundle.SummarySettings.DefaultModel != "" {
+			var settings models.SummarySetting
+			if err := tx.Limit(1).Find(&settings).Error; err != nil {
+				return err
+			}
+			settings.DefaultModel = bundle.SummarySettings.DefaultModel
+			if err := tx.Save(&settings).Error; err != nil {
+				return err
+			}
+			result.SummarySettings = true
+		}
+
+		if bundle.StandingContext != nil {
+			var standing models.StandingContext
+			if err := tx.Limit(1).Find(&standing).Error; err != nil {
+				return err
+			}
+			standing.Content = *bundle.StandingContext
+			standing.UpdatedBy = userID
+			if err := tx.Save(&standing).Error; err != nil {
+				return err
+			}
+			result.StandingContext = true
+		}
+
+		if bundle.UserSettings != nil && userID != nil {
+			updates := map[string]interface{}{"auto_transcription_enabled": bundle.UserSettings.AutoTranscriptionEnabled}
+			if id := bundle.UserSettings.DefaultProfileID; id != nil {
+				if local, ok := profileIDs[*id]; ok {
+					updates["default_profile_id"] = local
+				}
+			}
+			if id := bundle.UserSettings.DefaultSummaryTemplateID; id != nil {
+				if local, ok := templateIDs[*id]; ok {
+					updates["default_summary_template_id"] = local
+				}
+			}
+			if err := tx.Model(&models.User{}).Where("id = ?", *userID).Updates(updates).Error; err != nil {
+				return err
+			}
+			result.UserSettings = true
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import settings"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,522 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"scriberr/internal/auth"
+	"scriberr/internal/database"
+	"scriberr/internal/export"
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateShareLinkRequest asks for a public link to a transcription
+type CreateShareLinkRequest struct {
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"` // Unset for a link that works until revoked
+	Password     string     `json:"password,omitempty"`   // Asked for by the view in the X-Share-Password header
+	IncludeAudio bool       `json:"include_audio,omitempty"`
+	// Scan checks the transcript for sensitive passages first; it is always done when SHARE_SCAN is set
+	Scan bool `json:"scan,omitempty"`
+	// ConfirmedPassages are the IDs of flagged passages the caller confirms can be shared
+	ConfirmedPassages []string `json:"confirmed_passages,omitempty"`
+}
+
+// ShareLinkResponse is a share link as its owner sees it
+type ShareLinkResponse struct {
+	models.ShareLink
+	Status      string `json:"status"` // active, expired or revoked
+	HasPassword bool   `json:"has_password"`
+	URL         string `json:"url,omitempty"` // Only returned when the link is created
+}
+
+// SharedSegment is a transcript segment in a shared view, with its speaker's name
+type SharedSegment struct {
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Speaker string  `json:"speaker,omitempty"`
+	Text    string  `json:"text"`
+}
+
+// SharedTranscriptionResponse is the read-only view behind a share link
+type SharedTranscriptionResponse struct {
+	Title          *string                   `json:"title,omitempty"`
+	CreatedAt      time.Time                 `json:"created_at"`
+	Segments       []SharedSegment           `json:"segments"`
+	Summary        string                    `json:"summary,omitempty"` // Markdown
+	Structured     *models.StructuredSummary `json:"structured_summary,omitempty"`
+	AudioURL       string                    `json:"audio_url,omitempty"` // A signed URL that plays the recording
+	AudioExpiresAt *time.Time                `json:"audio_expires_at,omitempty"`
+	ExpiresAt      *time.Time                `json:"expires_at,omitempty"`
+}
+
+// minSharePassword is the shortest password a share link can be given
+const minSharePassword = 6
+
+// sharePasswordAttemptsPerMinute limits the password attempts on each share link, and from
+// each IP address
+const sharePasswordAttemptsPerMinute = 10
+
+// shareLinkStatus returns whether a link is active, expired or revoked
+func shareLinkStatus(link *models.ShareLink, now time.Time) string {
+	switch {
+	case link.RevokedAt != nil:
+		return "revoked"
+	case link.ExpiresAt != nil && now.After(*link.ExpiresAt):
+		return "expired"
+	}
+	return "active"
+}
+
+// newShareLinkResponse describes a share link to its owner
+func newShareLinkResponse(link models.ShareLink) ShareLinkResponse {
+	return ShareLinkResponse{ShareLink: link, Status: shareLinkStatus(&link, time.Now()), HasPassword: link.PasswordHash != ""}
+}
+
+// loadShareLink loads a share link of the id transcription, writing an error response if it can't
+func loadShareLink(c *gin.Context) (*models.ShareLink, bool) {
+	var link models.ShareLink
+	if err := database.DB.Where("id = ? AND transcription_id = ?", c.Param("share_id"), c.Param("id")).Limit(1).Find(&link).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get share link"})
+		return nil, false
+	}
+	if link.ID == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Share link not found"})
+		return nil, false
+	}
+	return &link, true
+}
+
+// recordShareAccess logs a request to a share link; the log is secondary, so failures are ignored
+func recordShareAccess(c *gin.Context, link *models.ShareLink, outcome string) {
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > 512 {
+		userAgent = userAgent[:512]
+	}
+	database.DB.Create(&models.ShareLinkAccess{
+		ShareLinkID: link.ID,
+		Outcome:     outcome,
+		IPAddress:   c.ClientIP(),
+		UserAgent:   userAgent,
+	})
+}
+
+// CreateShareLink creates a public link to a transcription
+// @Summary Share a transcription
+// @Description Create a public link to a read-only view of the transcript and summary, with the audio if include_audio is set. The link works without an account until expires_at, if given, or until it is revoked; with a password, the view asks for it. The URL is only returned now, and is absolute when PUBLIC_URL is set. With scan, or always when SHARE_SCAN is set, the transcript is first scanned for personal data and the terms in SHARE_SENSITIVE_TERMS; if anything is found, the answer is 409 with the flagged passages, and the link is only created once each passage's ID is sent back in confirmed_passages.
+// @Tags sharing
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body CreateShareLinkRequest true "Link options"
+// @Success 201 {object} ShareLinkResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} ShareScanResponse
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/shares [post]
+func (h *Handler) CreateShareLink(c *gin.Context) {
+	var req CreateShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+		return
+	}
+	if req.Password != "" && len(req.Password) < minSharePassword {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "password must be at least 6 characters"})
+		return
+	}
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	if job.Status != models.StatusCompleted || job.Transcript == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only completed transcriptions can be shared"})
+		return
+	}
+	if req.IncludeAudio && job.AudioPath == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This transcription has no audio to share"})
+		return
+	}
+
+	// Nothing is shared until every sensitive passage in the transcript has been confirmed
+	scanned, confirmed, ok := h.confirmShare(c, job, req.Scan, req.ConfirmedPassages)
+	if !ok {
+		return
+	}
+
+	var passwordHash string
+	if req.Password != "" {
+		var err error
+		if passwordHash, err = auth.HashPassword(req.Password); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process password"})
+			return
+		}
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create share link"})
+		return
+	}
+	token := hex.EncodeToString(secret)
+	link := models.ShareLink{
+		TokenHash:         hashDownloadToken(token),
+		TranscriptionID:   job.ID,
+		UserID:            currentUserID(c),
+		PasswordHash:      passwordHash,
+		IncludeAudio:      req.IncludeAudio,
+		ExpiresAt:         req.ExpiresAt,
+		Scanned:           scanned,
+		ConfirmedPassages: confirmed,
+	}
+	if err := database.DB.Create(&link).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create share link"})
+		return
+	}
+
+	response := newShareLinkResponse(link)
+	response.URL = "/api/v1/shared/" + token
+	if h.config != nil && h.config.PublicURL != "" {
+		response.URL = strings.TrimRight(h.config.PublicURL, "/") + response.URL
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListShareLinks lists a transcription's share links
+// @Summary List a transcription's share links
+// @Description List the links a transcription was shared with, newest first, including expired and revoked ones, with how often each was viewed.
+// @Tags sharing
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/shares [get]
+func (h *Handler) ListShareLinks(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	var links []models.ShareLink
+	if err := database.DB.Where("transcription_id = ?", job.ID).Order("created_at DESC").Find(&links).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list share links"})
+		return
+	}
+	responses := make([]ShareLinkResponse, 0, len(links))
+	for _, link := range links {
+		responses = append(responses, newShareLinkResponse(link))
+	}
+	c.JSON(http.StatusOK, gin.H{"share_links": responses})
+}
+
+// RevokeShareLink stops a share link from working
+// @Summary Revoke a share link
+// @Description Revoke a share link; it answers 410 from then on. The link and its access log are kept.
+// @Tags sharing
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param share_id path string true "Share link ID"
+// @Success 200 {object} ShareLinkResponse
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/shares/{share_id} [delete]
+func (h *Handler) RevokeShareLink(c *gin.Context) {
+	link, ok := loadShareLink(c)
+	if !ok {
+		return
+	}
+	if link.RevokedAt == nil {
+		now := time.Now()
+		if err := database.DB.Model(link).Update("revoked_at", now).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke share link"})
+			return
+		}
+		link.RevokedAt = &now
+	}
+	// The audio stops playing too
+	if link.AudioLinkID != "" {
+		database.DB.Where("id = ?", link.AudioLinkID).Delete(&models.DownloadLink{})
+	}
+	c.JSON(http.StatusOK, newShareLinkResponse(*link))
+}
+
+// ListShareLinkAccesses returns a share link's access log
+// @Summary Get a share link's access log
+// @Description List the requests made to a share link, newest first: views, wrong passwords, and attempts after it expired or was revoked, with the client's IP address and user agent.
+// @Tags sharing
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param share_id path string true "Share link ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Entries per page, at most 200" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/shares/{share_id}/accesses [get]
+func (h *Handler) ListShareLinkAccesses(c *gin.Context) {
+	link, ok := loadShareLink(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+	query := database.DB.Model(&models.ShareLinkAccess{}).Where("share_link_id = ?", link.ID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count accesses"})
+		return
+	}
+	accesses := []models.ShareLinkAccess{}
+	if err := query.Order("created_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&accesses).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list accesses"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"accesses": accesses,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// ViewSharedTranscription serves the read-only view behind a share link
+// @Summary View a shared transcription
+// @Description Get the transcript, with speakers by name, and the summary of a shared transcription, without credentials. A password-protected link answers 401 with password_required until the password is sent in the X-Share-Password header; more than 10 password attempts a minute on a link, or from an IP address, answer 429. A link shared with audio returns a signed audio_url that plays the recording until audio_expires_at; view the link again for a fresh one. Expired and revoked links answer 410. Every request is recorded in the link's access log.
+// @Tags sharing
+// @Produce json
+// @Param token path string true "Share link token"
+// @Param X-Share-Password header string false "The link's password"
+// @Success 200 {object} SharedTranscriptionResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 429 {object} map[string]interface{}
+// @Router /api/v1/shared/{token} [get]
+func (h *Handler) ViewSharedTranscription(c *gin.Context) {
+	// The link is the credential, so keep it out of caches and referrers
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+
+	var link models.ShareLink
+	if err := database.DB.Where("token_hash = ?", hashDownloadToken(c.Param("token"))).Limit(1).Find(&link).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get share link"})
+		return
+	}
+	if link.ID == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Share link not found"})
+		return
+	}
+	setAuditTarget(c, link.TranscriptionID)
+	now := time.Now()
+	switch shareLinkStatus(&link, now) {
+	case "revoked":
+		recordShareAccess(c, &link, models.ShareAccessRevoked)
+		c.JSON(http.StatusGone, gin.H{"error": "Share link has been revoked"})
+		return
+	case "expired":
+		recordShareAccess(c, &link, models.ShareAccessExpired)
+		c.JSON(http.StatusGone, gin.H{"error": "Share link has expired"})
+		return
+	}
+	if link.PasswordHash != "" {
+		password := c.GetHeader("X-Share-Password")
+		if password == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Password required", "password_required": true})
+			return
+		}
+		if !h.allowSharePassword(c, &link) {
+			return
+		}
+		if !auth.CheckPassword(password, link.PasswordHash) {
+			recordShareAccess(c, &link, models.ShareAccessWrongPassword)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Incorrect password", "password_required": true})
+			return
+		}
+	}
+
+	var job models.TranscriptionJob
+	if err := database.DB.Where("id = ?", link.TranscriptionID).Limit(1).Find(&job).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transcription"})
+		return
+	}
+	if job.ID == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Share link not found"})
+		return
+	}
+	speakerNames, err := jobSpeakerNames(job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get speaker mappings"})
+		return
+	}
+	response := SharedTranscriptionResponse{
+		Title:     job.Title,
+		CreatedAt: job.CreatedAt,
+		Segments:  sharedSegments(export.TranscriptSegments(&job), speakerNames),
+		ExpiresAt: link.ExpiresAt,
+	}
+	if response.Summary, response.Structured, err = latestSummary(&job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get summary"})
+		return
+	}
+	if link.IncludeAudio {
+		audioURL, expiresAt, err := h.signShareAudio(&link, now)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign audio URL"})
+			return
+		}
+		response.AudioURL, response.AudioExpiresAt = audioURL, &expiresAt
+	}
+
+	recordShareAccess(c, &link, models.ShareAccessViewed)
+	database.DB.Model(&link).Updates(map[string]interface{}{"view_count": gorm.Expr("view_count + 1"), "last_viewed_at": now})
+	c.JSON(http.StatusOK, response)
+}
+
+// allowSharePassword takes a password attempt from the share link's limit and the caller's IP
+// address's, answering 429 with a Retry-After when either is used up
+func (h *Handler) allowSharePassword(c *gin.Context, link *models.ShareLink) bool {
+	for _, subject := range []string{"share-password:" + link.ID, "share-password-ip:" + c.ClientIP()} {
+		allowed, wait := h.quotas.Allow(subject, sharePasswordAttemptsPerMinute)
+		if allowed {
+			continue
+		}
+		retryAfter := int(math.Max(math.Ceil(wait.Seconds()), 1))
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many password attempts", "retry_after": retryAfter})
+		return false
+	}
+	return true
+}
+
+// sharedSegments names the speakers of transcript segments
+func sharedSegments(segments []interfaces.TranscriptSegment, speakerNames map[string]string) []SharedSegment {
+	shared := make([]SharedSegment, 0, len(segments))
+	for _, segment := range segments {
+		s := SharedSegment{Start: segment.Start, End: segment.End, Text: strings.TrimSpace(segment.Text)}
+		if segment.Speaker != nil {
+			s.Speaker = *segment.Speaker
+			if name := speakerNames[s.Speaker]; name != "" {
+				s.Speaker = name
+			}
+		}
+		shared = append(shared, s)
+	}
+	return shared
+}
+
+// latestSummary returns a job's most recent summary as Markdown, with its structure if it is
+// a structured summary
+func latestSummary(job *models.TranscriptionJob) (string, *models.StructuredSummary, error) {
+	if job.StructuredSummary != nil {
+		return job.StructuredSummary.Markdown(), job.StructuredSummary, nil
+	}
+	var summary models.Summary
+	if err := database.DB.Where("transcription_id = ?", job.ID).Order("created_at DESC").Limit(1).Find(&summary).Error; err != nil {
+		return "", nil, err
+	}
+	if summary.ID != "" {
+		return summary.Content, nil, nil
+	}
+	if job.Summary != nil {
+		return *job.Summary, nil, nil
+	}
+	return "", nil, nil
+}
+
+// signShareAudio returns a signed download URL for a shared transcription's audio. Views share
+// one download link until it is about to expire, rather than each adding their own. A new one
+// lasts as long as signed URLs may (SIGNED_URL_MAX_TTL_SECONDS), but not past the share link's
+// expiry.
+func (h *Handler) signShareAudio(link *models.ShareLink, now time.Time) (string, time.Time, error) {
+	if link.AudioLinkID != "" {
+		var current models.DownloadLink
+		if err := database.DB.Where("id = ?", link.AudioLinkID).Limit(1).Find(&current).Error; err != nil {
+			return "", time.Time{}, err
+		}
+		// The token can only be derived again with the same secret, which changes on restarts
+		// when JWT_SECRET isn't set
+		token := h.shareAudioToken(current.ID)
+		if current.ID != "" && current.ExpiresAt.After(now.Add(time.Minute)) && current.TokenHash == hashDownloadToken(token) {
+			return h.shareAudioURL(token), current.ExpiresAt, nil
+		}
+	}
+
+	ttl := 86400
+	if h.config != nil && h.config.SignedURLMaxTTLSeconds > 0 {
+		ttl = h.config.SignedURLMaxTTLSeconds
+	}
+	expiresAt := now.Add(time.Duration(ttl) * time.Second)
+	if link.ExpiresAt != nil && link.ExpiresAt.Before(expiresAt) {
+		expiresAt = *link.ExpiresAt
+	}
+	download := models.DownloadLink{
+		ID:        uuid.New().String(),
+		UserID:    link.UserID,
+		Path:      "/api/v1/transcription/" + link.TranscriptionID + "/audio",
+		ExpiresAt: expiresAt,
+	}
+	token := h.shareAudioToken(download.ID)
+	if token == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return "", time.Time{}, err
+		}
+		token = hex.EncodeToString(secret)
+	}
+	download.TokenHash = hashDownloadToken(token)
+	if err := database.DB.Create(&download).Error; err != nil {
+		return "", time.Time{}, err
+	}
+	if err := database.DB.Model(link).Update("audio_link_id", download.ID).Error; err != nil {
+		return "", time.Time{}, err
+	}
+	return h.shareAudioURL(token), expiresAt, nil
+}
+
+// shareAudioToken derives the token of a share link's audio download link from its ID, since
+// only the token's hash is stored. It returns "" without a secret to derive it with.
+func (h *Handler) shareAudioToken(downloadID string) string {
+	if h.config == nil || h.config.JWTSecret == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, []byte(h.config.JWTSecret))
+	mac.Write([]byte("share-audio:" + downloadID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// shareAudioURL returns the URL of a share link's audio download link
+func (h *Handler) shareAudioURL(token string) string {
+	audioURL := "/api/v1/downloads/" + token
+	if h.config != nil && h.config.PublicURL != "" {
+		audioURL = strings.TrimRight(h.config.PublicURL, "/") + audioURL
+	}
+	return audioURL
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/export"
+	"scriberr/internal/models"
+	"scriberr/internal/redact"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FlaggedPassage is a passage of a transcript the share scan found sensitive data in
+type FlaggedPassage struct {
+	// ID identifies the passage at its place in the transcript, for confirming it
+	ID      string  `json:"id"`
+	Kind    string  `json:"kind"`    // email, phone, credit_card, name or term
+	Text    string  `json:"text"`    // What was found
+	Context string  `json:"context"` // The transcript segment it was found in
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Speaker *string `json:"speaker,omitempty"`
+}
+
+// ShareScanResponse lists the flagged passages that must be confirmed before a link is signed
+type ShareScanResponse struct {
+	Error    string           `json:"error"`
+	Passages []FlaggedPassage `json:"passages"`
+}
+
+// shareSensitiveTerms returns the terms configured in SHARE_SENSITIVE_TERMS
+func (h *Handler) shareSensitiveTerms() []string {
+	if h.config == nil {
+		return nil
+	}
+	var terms []string
+	for _, term := range strings.Split(h.config.ShareSensitiveTerms, ",") {
+		if term = strings.TrimSpace(term); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// scanForSharing finds the personal data and sensitive terms in a transcript. Names are those
+// found when it was redacted, if it was; others can't be told from ordinary words by pattern.
+func (h *Handler) scanForSharing(job *models.TranscriptionJob) ([]FlaggedPassage, error) {
+	var redaction models.Redaction
+	if err := database.DB.Select("names").Where("transcription_id = ?", job.ID).Limit(1).Find(&redaction).Error; err != nil {
+		return nil, err
+	}
+	scanner := redact.New(redaction.Names).WithTerms(h.shareSensitiveTerms())
+
+	passages := []FlaggedPassage{}
+	for i, segment := range export.TranscriptSegments(job) {
+		for _, match := range scanner.Find(segment.Text) {
+			found := segment.Text[match.Start:match.End]
+			sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%d\x00%s", match.Kind, i, match.Start, found)))
+			passages = append(passages, FlaggedPassage{
+				ID:      hex.EncodeToString(sum[:8]),
+				Kind:    match.Kind,
+				Text:    found,
+				Context: strings.TrimSpace(segment.Text),
+				Start:   segment.Start,
+				End:     segment.End,
+				Speaker: segment.Speaker,
+			})
+		}
+	}
+	return passages, nil
+}
+
+// confirmShare scans a transcript before it is shared, when scan is set or SHARE_SCAN is. It
+// writes a 409 response with the flagged passages whose IDs aren't in confirmedIDs, or an error
+// response if the scan fails, and returns whether the transcript was scanned and the IDs of the
+// confirmed passages.
+func (h *Handler) confirmShare(c *gin.Context, job *models.TranscriptionJob, scan bool, confirmedIDs []string) (bool, []string, bool) {
+	if !scan && (h.config == nil || !h.config.ShareScan) {
+		return false, nil, true
+	}
+	passages, err := h.scanForSharing(job)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan transcript"})
+		return false, nil, false
+	}
+	accepted := make(map[string]bool, len(confirmedIDs))
+	for _, id := range confirmedIDs {
+		accepted[id] = true
+	}
+	var confirmed []string
+	unconfirmed := []FlaggedPassage{}
+	for _, passage := range passages {
+		if accepted[passage.ID] {
+			confirmed = append(confirmed, passage.ID)
+		} else {
+			unconfirmed = append(unconfirmed, passage)
+		}
+	}
+	if len(unconfirmed) > 0 {
+		c.JSON(http.StatusConflict, ShareScanResponse{
+			Error:    fmt.Sprintf("Confirm the %d flagged passages to share this transcript", len(unconfirmed)),
+			Passages: unconfirmed,
+		})
+		return false, nil, false
+	}
+	return true, confirmed, true
+}
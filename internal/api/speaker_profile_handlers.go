@@ -0,0 +1,332 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/speakers"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxSpeakerNameLength caps speaker profile names, as speaker mappings do
+const maxSpeakerNameLength = 100
+
+// EnrollSpeakerRequest represents a request to enroll a speaker of a transcription as a known person
+type EnrollSpeakerRequest struct {
+	Name            string `json:"name" binding:"required"`
+	TranscriptionID string `json:"transcription_id" binding:"required"`
+	Speaker         string `json:"speaker" binding:"required"` // Diarization label, e.g. SPEAKER_00
+}
+
+// RenameSpeakerProfileRequest represents a request to rename a speaker profile
+type RenameSpeakerProfileRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// CorrectSpeakerRequest names a speaker of a transcription as an existing profile, by ID, or
+// as the person with the given name, enrolling them if they have no profile yet
+type CorrectSpeakerRequest struct {
+	SpeakerProfileID string `json:"speaker_profile_id"`
+	Name             string `json:"name"`
+}
+
+// speakerName trims a speaker name and checks it fits, writing a 400 response if it doesn't
+func speakerName(c *gin.Context, name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return "", false
+	}
+	if len(name) > maxSpeakerNameLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("name must be at most %d characters", maxSpeakerNameLength)})
+		return "", false
+	}
+	return name, true
+}
+
+// loadSpeakerProfile loads a speaker profile owned by the caller, writing an error response if it can't
+func loadSpeakerProfile(c *gin.Context, id string) (*models.SpeakerProfile, bool) {
+	var profile models.SpeakerProfile
+	if err := scopeToOwner(database.DB, currentUserID(c)).Where("id = ?", id).First(&profile).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Speaker profile not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get speaker profile"})
+		}
+		return nil, false
+	}
+	return &profile, true
+}
+
+// loadJobSpeaker loads a speaker found in a transcription, writing an error response if it can't
+func loadJobSpeaker(c *gin.Context, jobID, label string) (*models.JobSpeaker, bool) {
+	var speaker models.JobSpeaker
+	if err := database.DB.Where("transcription_job_id = ? AND speaker = ?", jobID, label).First(&speaker).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No voice was recorded for this speaker; speaker identification needs a diarized transcription"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get speaker"})
+		}
+		return nil, false
+	}
+	return &speaker, true
+}
+
+// ListSpeakerProfiles returns the caller's speaker profiles
+// @Summary List speaker profiles
+// @Description List the caller's speaker profiles, the known people whose voices name the speakers of new diarized transcriptions
+// @Tags speakers
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/speaker-profiles [get]
+func (h *Handler) ListSpeakerProfiles(c *gin.Context) {
+	profiles := []models.SpeakerProfile{}
+	if err := scopeToOwner(database.DB, currentUserID(c)).Order("name ASC").Find(&profiles).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list speaker profiles"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"profiles": profiles})
+}
+
+// EnrollSpeaker creates a speaker profile from a speaker of a transcription
+// @Summary Enroll a speaker
+// @Description Save the voice of a speaker of a diarized transcription as a known person, and name the speaker after them. Speakers of later transcriptions who sound like the person are named after them automatically. Enrolling a name that already has a profile adds this voice to it.
+// @Tags speakers
+// @Accept json
+// @Produce json
+// @Param request body EnrollSpeakerRequest true "Person and the speaker who is them"
+// @Success 201 {object} models.SpeakerProfile
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/speaker-profiles [post]
+func (h *Handler) EnrollSpeaker(c *gin.Context) {
+	var req EnrollSpeakerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name, ok := speakerName(c, req.Name)
+	if !ok {
+		return
+	}
+	if !checkJobAccess(c, req.TranscriptionID) {
+		return
+	}
+	speaker, ok := loadJobSpeaker(c, req.TranscriptionID, req.Speaker)
+	if !ok {
+		return
+	}
+
+	profile, err := speakers.FindOrCreateProfile(currentUserID(c), name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create speaker profile"})
+		return
+	}
+	if err := speakers.Assign(speaker, profile); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enroll speaker"})
+		return
+	}
+	h.refreshRAGMetadata(speaker.TranscriptionJobID)
+	c.JSON(http.StatusCreated, profile)
+}
+
+// RenameSpeakerProfile renames a speaker profile
+// @Summary Rename a speaker profile
+// @Description Rename a speaker profile, along with the transcript speakers named after it
+// @Tags speakers
+// @Accept json
+// @Produce json
+// @Param id path string true "Speaker profile ID"
+// @Param request body RenameSpeakerProfileRequest true "New name"
+// @Success 200 {object} models.SpeakerProfile
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/speaker-profiles/{id} [put]
+func (h *Handler) RenameSpeakerProfile(c *gin.Context) {
+	profile, ok := loadSpeakerProfile(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	var req RenameSpeakerProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name, ok := speakerName(c, req.Name)
+	if !ok {
+		return
+	}
+
+	var count int64
+	if err := scopeToOwner(database.DB.Model(&models.SpeakerProfile{}), currentUserID(c)).
+		Where("LOWER(name) = ? AND id <> ?", strings.ToLower(name), profile.ID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check speaker profile names"})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Another speaker profile has this name"})
+		return
+	}
+
+	jobIDs, err := speakers.Rename(profile, name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rename speaker profile"})
+		return
+	}
+	for _, jobID := range jobIDs {
+		h.refreshRAGMetadata(jobID)
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+// DeleteSpeakerProfile deletes a speaker profile
+// @Summary Delete a speaker profile
+// @Description Delete a speaker profile. Transcript speakers named after it keep their names.
+// @Tags speakers
+// @Param id path string true "Speaker profile ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/speaker-profiles/{id} [delete]
+func (h *Handler) DeleteSpeakerProfile(c *gin.Context) {
+	profile, ok := loadSpeakerProfile(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	if err := speakers.Delete(profile); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete speaker profile"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Speaker profile deleted"})
+}
+
+// ListSpeakerMatches returns the speakers found in a transcription and who they were identified as
+// @Summary List a transcription's identified speakers
+// @Description List the speakers diarization found in a transcription with the speaker profile each was identified as, if any. A matched speaker was named automatically with the given voice similarity; a confirmed one was named or confirmed by a person.
+// @Tags speakers
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Success 200 {array} models.JobSpeaker
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/speaker-matches [get]
+func (h *Handler) ListSpeakerMatches(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	matches := []models.JobSpeaker{}
+	if err := database.DB.Preload("SpeakerProfile").Where("transcription_job_id = ?", job.ID).Order("speaker ASC").Find(&matches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list speakers"})
+		return
+	}
+	c.JSON(http.StatusOK, matches)
+}
+
+// ConfirmSpeakerMatch confirms that a speaker was identified correctly
+// @Summary Confirm an identified speaker
+// @Description Confirm that a speaker named automatically is the person of their speaker profile. The speaker's voice is added to the profile, so the person is recognized more reliably.
+// @Tags speakers
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param speaker path string true "Diarization label, e.g. SPEAKER_00"
+// @Success 200 {object} models.JobSpeaker
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/speaker-matches/{speaker}/confirm [post]
+func (h *Handler) ConfirmSpeakerMatch(c *gin.Context) {
+	speaker, ok := loadJobSpeaker(c, c.Param("id"), c.Param("speaker"))
+	if !ok {
+		return
+	}
+	if err := speakers.Confirm(speaker); err != nil {
+		if errors.Is(err, speakers.ErrNothingToConfirm) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The speaker was not identified; name them instead"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to confirm speaker"})
+		return
+	}
+	h.refreshRAGMetadata(speaker.TranscriptionJobID)
+	c.JSON(http.StatusOK, speaker)
+}
+
+// CorrectSpeakerMatch names a speaker of a transcription as a known person
+// @Summary Correct an identified speaker
+// @Description Name a speaker of a transcription as the person of a speaker profile, given by speaker_profile_id, or by name, enrolling a new profile when the caller has none by that name. The speaker's voice moves to that profile.
+// @Tags speakers
+// @Accept json
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param speaker path string true "Diarization label, e.g. SPEAKER_00"
+// @Param request body CorrectSpeakerRequest true "Who the speaker is"
+// @Success 200 {object} models.JobSpeaker
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/speaker-matches/{speaker} [put]
+func (h *Handler) CorrectSpeakerMatch(c *gin.Context) {
+	var req CorrectSpeakerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if (req.SpeakerProfileID == "") == (strings.TrimSpace(req.Name) == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Give either speaker_profile_id or name"})
+		return
+	}
+	speaker, ok := loadJobSpeaker(c, c.Param("id"), c.Param("speaker"))
+	if !ok {
+		return
+	}
+
+	var profile *models.SpeakerProfile
+	if req.SpeakerProfileID != "" {
+		if profile, ok = loadSpeakerProfile(c, req.SpeakerProfileID); !ok {
+			return
+		}
+	} else {
+		name, ok := speakerName(c, req.Name)
+		if !ok {
+			return
+		}
+		var err error
+		if profile, err = speakers.FindOrCreateProfile(currentUserID(c), name); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create speaker profile"})
+			return
+		}
+	}
+
+	if err := speakers.Assign(speaker, profile); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to name speaker"})
+		return
+	}
+	h.refreshRAGMetadata(speaker.TranscriptionJobID)
+	speaker.SpeakerProfile = profile
+	c.JSON(http.StatusOK, speaker)
+}
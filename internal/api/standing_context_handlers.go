@@ -0,0 +1,106 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxStandingContextLength caps the stored standing context; only the token budget of it reaches prompts
+const maxStandingContextLength = 50000
+
+// StandingContextRequest replaces the standing context
+type StandingContextRequest struct {
+	Content string `json:"content"`
+}
+
+// StandingContextResponse is the standing context and how much of it fits in chat prompts
+type StandingContextResponse struct {
+	Content   string     `json:"content"`
+	Tokens    int        `json:"tokens"`     // Estimated size of the whole document
+	MaxTokens int        `json:"max_tokens"` // STANDING_CONTEXT_MAX_TOKENS
+	Truncated bool       `json:"truncated"`  // Whether prompts only get the beginning of it
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// standingContextResponse describes a standing context against the configured token budget
+func (h *Handler) standingContextResponse(standing models.StandingContext) StandingContextResponse {
+	budget := rag.DefaultStandingContextTokens
+	if h.config != nil {
+		budget = h.config.StandingContextMaxTokens
+	}
+	_, truncated := rag.TrimToTokenBudget(standing.Content, budget)
+	response := StandingContextResponse{
+		Content:   standing.Content,
+		Tokens:    llm.EstimateTokens(standing.Content),
+		MaxTokens: budget,
+		Truncated: truncated,
+	}
+	if !standing.UpdatedAt.IsZero() {
+		response.UpdatedAt = &standing.UpdatedAt
+	}
+	return response
+}
+
+// GetStandingContext returns the standing context prepended to RAG chat prompts
+// @Summary Get the standing context
+// @Description Get the organization-wide context document (e.g. a team glossary or the current quarter's goals) that is prepended to every RAG chat prompt, with its estimated size and whether it exceeds the token budget
+// @Tags admin
+// @Produce json
+// @Success 200 {object} StandingContextResponse
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/standing-context [get]
+func (h *Handler) GetStandingContext(c *gin.Context) {
+	var standing models.StandingContext
+	if err := database.DB.Limit(1).Find(&standing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get standing context"})
+		return
+	}
+	c.JSON(http.StatusOK, h.standingContextResponse(standing))
+}
+
+// UpdateStandingContext replaces the standing context
+// @Summary Update the standing context
+// @Description Replace the organization-wide context document prepended to every RAG chat prompt. Only the first STANDING_CONTEXT_MAX_TOKENS of it are used, cut at a line break; an empty content removes it.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body StandingContextRequest true "Standing context"
+// @Success 200 {object} StandingContextResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/standing-context [put]
+func (h *Handler) UpdateStandingContext(c *gin.Context) {
+	var req StandingContextRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Content) > maxStandingContextLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "content is too long"})
+		return
+	}
+
+	var standing models.StandingContext
+	if err := database.DB.Limit(1).Find(&standing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get standing context"})
+		return
+	}
+	standing.Content = req.Content
+	standing.UpdatedBy = currentUserID(c)
+	if err := database.DB.Save(&standing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save standing context"})
+		return
+	}
+	c.JSON(http.StatusOK, h.standingContextResponse(standing))
+}
@@ -0,0 +1,330 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+	"scriberr/internal/scheduler"
+	"scriberr/internal/storage"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SetFileStore sets the object storage that audio files and transcripts are kept in (nil to
+// keep them on the local disk only)
+func (h *Handler) SetFileStore(files *storage.Files) {
+	h.files = files
+}
+
+// storeFile uploads a newly saved file to object storage, writing an error response and
+// removing the file if that fails
+func (h *Handler) storeFile(c *gin.Context, localPath string) bool {
+	if h.files == nil {
+		return true
+	}
+	if err := h.files.Upload(c.Request.Context(), localPath); err != nil {
+		logger.ErrorContext(c.Request.Context(), "Failed to store file", "path", localPath, "error", err)
+		os.Remove(localPath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store file"})
+		return false
+	}
+	return true
+}
+
+// fetchStoredFile downloads a file from object storage unless it is on the local disk already
+func (h *Handler) fetchStoredFile(ctx context.Context, localPath string) {
+	if h.files == nil || localPath == "" {
+		return
+	}
+	if err := h.files.Fetch(ctx, localPath); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		logger.ErrorContext(ctx, "Failed to fetch file from object storage", "path", localPath, "error", err)
+	}
+}
+
+// removeStoredFile deletes a file's copy in object storage
+func (h *Handler) removeStoredFile(localPath string) error {
+	if h.files == nil || localPath == "" {
+		return nil
+	}
+	return h.files.Remove(context.Background(), localPath)
+}
+
+// removeStoredJob deletes everything a deleted job kept in object storage
+func (h *Handler) removeStoredJob(job *models.TranscriptionJob) {
+	if h.files == nil {
+		return
+	}
+	paths := []string{job.AudioPath}
+	if job.MergedAudioPath != nil {
+		paths = append(paths, *job.MergedAudioPath)
+	}
+	for _, path := range paths {
+		if err := h.removeStoredFile(path); err != nil {
+			fmt.Printf("Warning: Failed to delete stored file %s: %v\n", path, err)
+		}
+	}
+	if err := h.files.RemoveTranscript(context.Background(), job.ID); err != nil {
+		fmt.Printf("Warning: Failed to delete stored transcript of %s: %v\n", job.ID, err)
+	}
+}
+
+// requireFileStore writes an error response when object storage isn't configured
+func (h *Handler) requireFileStore(c *gin.Context) bool {
+	if h.files == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Object storage is not configured"})
+		return false
+	}
+	return true
+}
+
+// presignTTL is how long presigned URLs last
+func (h *Handler) presignTTL() time.Duration {
+	if h.config != nil && h.config.SignedURLTTLSeconds > 0 {
+		return time.Duration(h.config.SignedURLTTLSeconds) * time.Second
+	}
+	return 5 * time.Minute
+}
+
+// PresignedURLResponse is a URL that reaches object storage directly
+type PresignedURLResponse struct {
+	ID        string    `json:"id,omitempty"`  // The transcription the upload will become
+	Key       string    `json:"key,omitempty"` // The object to complete the upload with
+	URL       string    `json:"url"`
+	Method    string    `json:"method"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// GetAudioURL returns a presigned URL to a transcription's audio in object storage
+// @Summary Get a presigned audio URL
+// @Description Get a URL that downloads a transcription's audio straight from object storage, without credentials, until it expires (SIGNED_URL_TTL_SECONDS). Requires STORAGE_BACKEND=s3.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} PresignedURLResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/{id}/audio/url [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetAudioURL(c *gin.Context) {
+	if !h.requireFileStore(c) {
+		return
+	}
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	audioPath := job.AudioPath
+	if job.IsMultiTrack && job.MergedAudioPath != nil && *job.MergedAudioPath != "" {
+		audioPath = *job.MergedAudioPath
+	}
+	if audioPath == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audio file path not found"})
+		return
+	}
+
+	ttl := h.presignTTL()
+	url, err := h.files.Store().PresignGet(h.files.Key(audioPath), ttl)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign audio URL"})
+		return
+	}
+	c.JSON(http.StatusOK, PresignedURLResponse{URL: url, Method: http.MethodGet, ExpiresAt: time.Now().Add(ttl)})
+}
+
+// UploadURLRequest asks for a presigned upload URL
+type UploadURLRequest struct {
+	Filename string `json:"filename" binding:"required"` // Only its extension is kept
+}
+
+// CreateUploadURL returns a presigned URL to upload audio straight to object storage
+// @Summary Get a presigned upload URL
+// @Description Get a URL that uploads an audio file straight to object storage with a PUT of its bytes. Once uploaded, complete the upload with the returned key to create the transcription. Requires STORAGE_BACKEND=s3.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param request body UploadURLRequest true "File to upload"
+// @Success 200 {object} PresignedURLResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/upload-url [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CreateUploadURL(c *gin.Context) {
+	if !h.requireFileStore(c) {
+		return
+	}
+	var req UploadURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	jobID := uuid.New().String()
+	key := h.files.Key(filepath.Join(h.config.UploadDir, jobID+filepath.Ext(req.Filename)))
+	ttl := h.presignTTL()
+	url, err := h.files.Store().PresignPut(key, ttl)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign upload URL"})
+		return
+	}
+	c.JSON(http.StatusOK, PresignedURLResponse{ID: jobID, Key: key, URL: url, Method: http.MethodPut, ExpiresAt: time.Now().Add(ttl)})
+}
+
+// CompleteUploadRequest creates a transcription from audio uploaded to a presigned URL
+type CompleteUploadRequest struct {
+	Key         string  `json:"key" binding:"required"`
+	Title       *string `json:"title,omitempty"`
+	ContentType string  `json:"content_type,omitempty"` // meeting (default), voice_memo or podcast
+	Priority    int     `json:"priority,omitempty"`
+}
+
+// CompleteUpload creates a transcription from audio uploaded to a presigned URL
+// @Summary Complete a presigned upload
+// @Description Create a transcription, with "uploaded" status, from an audio file uploaded to a URL from /transcription/upload-url.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param request body CompleteUploadRequest true "Uploaded file"
+// @Success 200 {object} models.TranscriptionJob
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/upload-url/complete [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) CompleteUpload(c *gin.Context) {
+	if !h.requireFileStore(c) {
+		return
+	}
+	var req CompleteUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Only keys handed out by CreateUploadURL are accepted
+	name := path.Base(req.Key)
+	jobID := strings.TrimSuffix(name, path.Ext(name))
+	filePath := filepath.Join(h.config.UploadDir, name)
+	if _, err := uuid.Parse(jobID); err != nil || h.files.Key(filePath) != req.Key {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid upload key"})
+		return
+	}
+	contentType := strings.TrimSpace(req.ContentType)
+	if contentType == "" {
+		contentType = models.ContentMeeting
+	}
+	if !models.IsRecordingContentType(contentType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": contentTypeError})
+		return
+	}
+	if req.Priority < queue.MinPriority || req.Priority > queue.MaxPriority {
+		c.JSON(http.StatusBadRequest, gin.H{"error": priorityError})
+		return
+	}
+	var existing int64
+	if err := database.DB.Model(&models.TranscriptionJob{}).Where("id = ?", jobID).Count(&existing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check upload"})
+		return
+	}
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Upload already completed"})
+		return
+	}
+
+	if err := h.files.Fetch(c.Request.Context(), filePath); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Nothing was uploaded to this key"})
+			return
+		}
+		logger.ErrorContext(c.Request.Context(), "Failed to fetch upload", "key", req.Key, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch upload"})
+		return
+	}
+
+	job := models.TranscriptionJob{
+		ID:          jobID,
+		UserID:      currentUserID(c),
+		AudioPath:   filePath,
+		Status:      models.StatusUploaded,
+		ContentType: contentType,
+		Priority:    req.Priority,
+	}
+	if req.Title != nil && *req.Title != "" {
+		job.Title = req.Title
+	}
+	if err := database.DB.Create(&job).Error; err != nil {
+		os.Remove(filePath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// storageMinAgeHours reads the min_age_hours param of a storage cleanup
+func storageMinAgeHours(params map[string]interface{}) (int, error) {
+	hours, err := scheduler.IntParam(params, "min_age_hours", 24)
+	if err != nil {
+		return 0, err
+	}
+	if hours < 1 {
+		return 0, errors.New("min_age_hours must be at least 1")
+	}
+	return hours, nil
+}
+
+// runStorageCleanup deletes uploads no transcription refers to, such as presigned uploads that
+// were never completed, and the stored transcripts of deleted transcriptions
+func (h *Handler) runStorageCleanup(ctx context.Context, params map[string]interface{}) (map[string]interface{}, error) {
+	if h.files == nil {
+		return nil, errors.New("object storage is not configured")
+	}
+	hours, err := storageMinAgeHours(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []models.TranscriptionJob
+	if err := database.DB.Select("id", "audio_path", "merged_audio_path").Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch transcriptions: %w", err)
+	}
+	jobIDs := make(map[string]bool, len(jobs))
+	audioKeys := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		jobIDs[job.ID] = true
+		if job.AudioPath != "" {
+			audioKeys[h.files.Key(job.AudioPath)] = true
+		}
+		if job.MergedAudioPath != nil && *job.MergedAudioPath != "" {
+			audioKeys[h.files.Key(*job.MergedAudioPath)] = true
+		}
+	}
+	// Only uploads are looked at; anything else sharing the bucket is left alone
+	uploads := strings.TrimSuffix(h.files.Key(h.config.UploadDir), "/") + "/"
+
+	result, err := h.files.Cleanup(ctx, time.Duration(hours)*time.Hour, func(key string) (bool, error) {
+		if jobID, ok := h.files.TranscriptJobID(key); ok {
+			return !jobIDs[jobID], nil
+		}
+		return strings.HasPrefix(key, uploads) && !audioKeys[key], nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"scanned": result.Scanned, "deleted": result.Deleted, "bytes": result.Bytes}, nil
+}
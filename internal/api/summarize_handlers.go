@@ -2,15 +2,19 @@ package api
 
 import (
 	"bufio"
-	"context"
-	"log"
+	"errors"
+	"io"
 	"net/http"
 	"strings"
 	"time"
 
 	"scriberr/internal/database"
+	"scriberr/internal/events"
 	"scriberr/internal/llm"
 	"scriberr/internal/models"
+	"scriberr/internal/versions"
+	"scriberr/internal/workflow"
+	"scriberr/pkg/logger"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -32,6 +36,7 @@ type SummarizeRequest struct {
 // @Param request body SummarizeRequest true "Summarize request"
 // @Success 200 {string} string "Event stream"
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
 // @Security BearerAuth
@@ -42,27 +47,30 @@ func (h *Handler) Summarize(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	// The summary is saved on the transcription, so it must be one the caller can open
+	if !checkJobAccess(c, req.TranscriptionID) {
+		return
+	}
 
 	svc, provider, err := h.getLLMService()
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	svc = h.observeLLM(llm.FeatureSummarize, provider, svc)
 
 	// Prepare chat messages: simple single-user message with full content
 	messages := []llm.ChatMessage{{Role: "user", Content: req.Content}}
 
 	start := time.Now()
-	log.Printf("[summarize] start transcription_id=%s provider=%s model=%s content_len=%d", req.TranscriptionID, provider, req.Model, len(req.Content))
+	logger.InfoContext(c.Request.Context(), "Summarizing", "job_id", req.TranscriptionID, "provider", provider, "model", req.Model, "content_len", len(req.Content))
 
 	// Stream response
 	c.Header("Content-Type", "text/event-stream")
 	c.Header("Cache-Control", "no-cache")
 	c.Header("Connection", "keep-alive")
 
-	// Allow longer generation time for large transcripts and smaller models
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Minute)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	contentChan, errChan := svc.ChatCompletionStream(ctx, req.Model, messages, 0.0)
 	flusher, _ := c.Writer.(http.Flusher)
@@ -83,11 +91,19 @@ func (h *Handler) Summarize(c *gin.Context) {
 			Content:         finalText,
 		}
 		if err := database.DB.Create(&sum).Error; err != nil {
-			// Fallback: store on the transcription job record
-			_ = database.DB.Model(&models.TranscriptionJob{}).Where("id = ?", req.TranscriptionID).Update("summary", finalText).Error
-		} else {
-			// Also cache on the transcription job for quick access
-			_ = database.DB.Model(&models.TranscriptionJob{}).Where("id = ?", req.TranscriptionID).Update("summary", finalText).Error
+			logger.ErrorContext(c.Request.Context(), "Failed to save summary history", "job_id", req.TranscriptionID, "error", err)
+		}
+		// Also cache on the transcription job for quick access. A free-text summary replaces any
+		// structured one on the job.
+		content := versions.Content{Text: finalText, Model: &req.Model}
+		if _, err := versions.Save(database.DB, req.TranscriptionID, models.VersionKindSummary, content, models.VersionSourceSummary, currentUserID(c)); err != nil {
+			logger.ErrorContext(c.Request.Context(), "Failed to save summary", "job_id", req.TranscriptionID, "error", err)
+			return
+		}
+		events.RecordForJob(models.EventSummaryReady, req.TranscriptionID, map[string]interface{}{"model": req.Model, "source": "summarize"})
+		// The summary is embedded in the transcription's RAG entry, so replace that entry too
+		if _, err := h.reindexIfIndexed(req.TranscriptionID); err != nil {
+			logger.ErrorContext(c.Request.Context(), "Failed to re-index summarized transcription", "job_id", req.TranscriptionID, "error", err)
 		}
 	}
 	for {
@@ -100,7 +116,7 @@ func (h *Handler) Summarize(c *gin.Context) {
 				}
 				// Persist summary once streaming completes
 				persistIfAny()
-				log.Printf("[summarize] complete transcription_id=%s model=%s bytes=%d duration_ms=%d", req.TranscriptionID, req.Model, len(finalText), time.Since(start).Milliseconds())
+				logger.InfoContext(c.Request.Context(), "Summary complete", "job_id", req.TranscriptionID, "model", req.Model, "bytes", len(finalText), "duration_ms", time.Since(start).Milliseconds())
 				return
 			}
 			finalText += chunk
@@ -111,7 +127,7 @@ func (h *Handler) Summarize(c *gin.Context) {
 			}
 			if !gotFirstChunk && len(chunk) > 0 {
 				gotFirstChunk = true
-				log.Printf("[summarize] first_chunk transcription_id=%s model=%s at_ms=%d", req.TranscriptionID, req.Model, time.Since(start).Milliseconds())
+				logger.DebugContext(c.Request.Context(), "Summary first chunk", "job_id", req.TranscriptionID, "model", req.Model, "at_ms", time.Since(start).Milliseconds())
 			}
 		case err := <-errChan:
 			if err != nil {
@@ -119,10 +135,10 @@ func (h *Handler) Summarize(c *gin.Context) {
 				// If streaming is unsupported for this model/org, fall back to non-streaming
 				errStr := err.Error()
 				if strings.Contains(errStr, "\"param\": \"stream\"") || strings.Contains(errStr, "unsupported_value") || strings.Contains(errStr, "must be verified to stream") {
-					log.Printf("[summarize] falling back to non-streaming transcription_id=%s model=%s due to: %v", req.TranscriptionID, req.Model, err)
+					logger.WarnContext(c.Request.Context(), "Summary streaming unsupported, falling back to non-streaming", "job_id", req.TranscriptionID, "model", req.Model, "error", err)
 					resp, err2 := svc.ChatCompletion(ctx, req.Model, messages, 0.0)
 					if err2 != nil || resp == nil || len(resp.Choices) == 0 {
-						log.Printf("[summarize] fallback failed transcription_id=%s model=%s err=%v", req.TranscriptionID, req.Model, err2)
+						logger.ErrorContext(c.Request.Context(), "Non-streaming summary failed", "job_id", req.TranscriptionID, "model", req.Model, "error", err2)
 						c.Writer.Write([]byte("\n"))
 						writer.Flush()
 						if flusher != nil {
@@ -141,7 +157,7 @@ func (h *Handler) Summarize(c *gin.Context) {
 					}
 					// Persist final summary and exit
 					persistIfAny()
-					log.Printf("[summarize] fallback complete transcription_id=%s model=%s bytes=%d duration_ms=%d", req.TranscriptionID, req.Model, len(finalText), time.Since(start).Milliseconds())
+					logger.InfoContext(c.Request.Context(), "Summary complete", "job_id", req.TranscriptionID, "model", req.Model, "bytes", len(finalText), "duration_ms", time.Since(start).Milliseconds(), "streamed", false)
 					return
 				} else {
 					c.Writer.Write([]byte("\n"))
@@ -149,7 +165,7 @@ func (h *Handler) Summarize(c *gin.Context) {
 					if flusher != nil {
 						flusher.Flush()
 					}
-					log.Printf("[summarize] error transcription_id=%s model=%s err=%v duration_ms=%d", req.TranscriptionID, req.Model, err, time.Since(start).Milliseconds())
+					logger.ErrorContext(c.Request.Context(), "Summary failed", "job_id", req.TranscriptionID, "model", req.Model, "duration_ms", time.Since(start).Milliseconds(), "error", err)
 				}
 			}
 			// Persist any partial content on error
@@ -158,15 +174,139 @@ func (h *Handler) Summarize(c *gin.Context) {
 		case <-ctx.Done():
 			// Persist any partial content on timeout/cancel
 			persistIfAny()
-			log.Printf("[summarize] timeout/cancel transcription_id=%s model=%s bytes=%d duration_ms=%d", req.TranscriptionID, req.Model, len(finalText), time.Since(start).Milliseconds())
+			logger.WarnContext(c.Request.Context(), "Summary timed out or was cancelled", "job_id", req.TranscriptionID, "model", req.Model, "bytes", len(finalText), "duration_ms", time.Since(start).Milliseconds())
+			return
+		}
+	}
+}
+
+// RegenerateSummaryRequest selects how a summary is regenerated; every field is optional
+type RegenerateSummaryRequest struct {
+	Model       string   `json:"model,omitempty"`                                       // Defaults to the configured summary model
+	Temperature *float64 `json:"temperature,omitempty" binding:"omitempty,min=0,max=2"` // Defaults to 0.7
+	TemplateID  *string  `json:"template_id,omitempty"`                                 // Defaults to the job's template, then the owner's default
+	Format      string   `json:"format,omitempty"`                                      // "text" or "structured", defaults to SUMMARY_FORMAT
+}
+
+// RegenerateSummaryResponse is the new summary of a transcription
+type RegenerateSummaryResponse struct {
+	TranscriptionID string                    `json:"transcription_id"`
+	Content         string                    `json:"content"`
+	Structured      *models.StructuredSummary `json:"structured,omitempty"`
+	Model           string                    `json:"model"`
+	TemplateID      *string                   `json:"template_id,omitempty"`
+	Reindexed       bool                      `json:"reindexed"`
+}
+
+// RegenerateSummary generates a transcription's summary again and re-indexes it
+// @Summary Regenerate a transcription's summary
+// @Description Generate the summary of a completed transcription again with the configured summary provider, replacing the current one, and re-index the transcription in RAG. The model, temperature, template and format can be overridden; without a template_id the job's template or the owner's default is used.
+// @Tags summarize
+// @Accept json
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param request body RegenerateSummaryRequest false "Summary options"
+// @Success 200 {object} RegenerateSummaryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/summarize [post]
+func (h *Handler) RegenerateSummary(c *gin.Context) {
+	var req RegenerateSummaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format := req.Format
+	if format == "" && h.config != nil {
+		format = h.config.SummaryFormat
+	}
+	if format != "" && format != workflow.SummaryFormatText && format != workflow.SummaryFormatStructured {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be text or structured"})
+		return
+	}
+
+	if h.llmRegistry == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "LLM providers not initialized"})
+		return
+	}
+	service, model, err := h.llmRegistry.For(llm.FeatureSummary)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Model != "" {
+		model = req.Model
+	}
+	temperature := 0.7
+	if req.Temperature != nil {
+		temperature = *req.Temperature
+	}
+
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	transcript, err := workflow.TranscriptText(job)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var template *models.SummaryTemplate
+	if req.TemplateID != nil && *req.TemplateID != "" {
+		if template, ok = loadSummaryTemplate(c, *req.TemplateID); !ok {
 			return
 		}
+	} else if template, err = workflow.ResolveSummaryTemplate(job, ""); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
+
+	start := time.Now()
+	summary, err := workflow.GenerateSummary(c.Request.Context(), service, job, transcript, workflow.SummaryOptions{
+		Model:       model,
+		Temperature: temperature,
+		Format:      format,
+		Template:    template,
+		Source:      "api",
+		RequestedBy: currentUserID(c),
+	})
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "Failed to regenerate summary", "job_id", job.ID, "model", model, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate summary: " + err.Error()})
+		return
+	}
+	logger.InfoContext(c.Request.Context(), "Regenerated summary", "job_id", job.ID, "model", model, "bytes", len(summary), "duration_ms", time.Since(start).Milliseconds())
+
+	response := RegenerateSummaryResponse{
+		TranscriptionID: job.ID,
+		Content:         summary,
+		Structured:      job.StructuredSummary,
+		Model:           model,
+	}
+	if template != nil {
+		response.TemplateID = &template.ID
+	}
+
+	// The summary is embedded in the transcription's RAG entry, so replace that entry too
+	if h.ragService != nil {
+		if err := h.storeJobInRAG(job); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Summary saved, but failed to re-index it: " + err.Error()})
+			return
+		}
+		response.Reindexed = true
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // GetSummaryForTranscription returns the latest summary for a transcription
 // @Summary Get latest summary for transcription
-// @Description Get the most recent saved summary for the given transcription
+// @Description Get the most recent saved summary for the given transcription. A structured summary (title, TL;DR, key points, decisions and action items) is returned in structured, with its Markdown rendering in content.
 // @Tags summarize
 // @Produce json
 // @Param id path string true "Transcription ID"
@@ -183,6 +323,20 @@ func (h *Handler) GetSummaryForTranscription(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Transcription ID required"})
 		return
 	}
+	// A structured summary is only kept on the job, and is cleared when a newer free-text one is saved
+	var job models.TranscriptionJob
+	if err := database.DB.Select("id", "summary", "structured_summary", "updated_at").Where("id = ?", tid).First(&job).Error; err == nil && job.StructuredSummary != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"transcription_id": tid,
+			"template_id":      nil,
+			"model":            "",
+			"content":          job.StructuredSummary.Markdown(),
+			"structured":       job.StructuredSummary,
+			"created_at":       job.UpdatedAt,
+			"updated_at":       job.UpdatedAt,
+		})
+		return
+	}
 	var s models.Summary
 	if err := database.DB.Where("transcription_id = ?", tid).Order("created_at DESC").First(&s).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -16,6 +17,7 @@ type SummaryTemplateRequest struct {
 	Description *string `json:"description"`
 	Model       string  `json:"model" binding:"required,min=1"`
 	Prompt      string  `json:"prompt" binding:"required,min=1"`
+	Layout      string  `json:"layout" binding:"omitempty,oneof=per_speaker"` // Empty for a summary of the whole recording, or per_speaker
 }
 
 type SummarySettingsRequest struct {
@@ -26,9 +28,62 @@ type SummarySettingsResponse struct {
 	DefaultModel string `json:"default_model"`
 }
 
-// ListSummaryTemplates returns all templates
+// SetDefaultSummaryTemplateRequest sets the caller's default summary template; an empty
+// template_id clears it
+type SetDefaultSummaryTemplateRequest struct {
+	TemplateID string `json:"template_id"`
+}
+
+// DefaultSummaryTemplateResponse is the caller's default summary template, if any
+type DefaultSummaryTemplateResponse struct {
+	TemplateID *string                 `json:"template_id"`
+	Template   *models.SummaryTemplate `json:"template,omitempty"`
+}
+
+// visibleTemplates limits a template query to the caller's templates and the shared ones
+func visibleTemplates(db *gorm.DB, userID *uint) *gorm.DB {
+	if userID == nil {
+		return db.Where("user_id IS NULL")
+	}
+	return db.Where("user_id = ? OR user_id IS NULL", *userID)
+}
+
+// loadSummaryTemplate fetches a template visible to the caller, writing the error response if it can't
+func loadSummaryTemplate(c *gin.Context, id string) (*models.SummaryTemplate, bool) {
+	var item models.SummaryTemplate
+	if err := visibleTemplates(database.DB, currentUserID(c)).Where("id = ?", id).First(&item).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch template"})
+		return nil, false
+	}
+	return &item, true
+}
+
+// summaryTemplateFromForm reads the summary template chosen for an uploaded job, writing a
+// 400 response if it isn't one of the caller's templates
+func summaryTemplateFromForm(c *gin.Context) (*string, bool) {
+	id := strings.TrimSpace(c.PostForm("summary_template_id"))
+	if id == "" {
+		return nil, true
+	}
+	var count int64
+	if err := visibleTemplates(database.DB.Model(&models.SummaryTemplate{}), currentUserID(c)).Where("id = ?", id).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch template"})
+		return nil, false
+	}
+	if count == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Summary template not found"})
+		return nil, false
+	}
+	return &id, true
+}
+
+// ListSummaryTemplates returns the caller's templates and the shared ones
 // @Summary List summarization templates
-// @Description Get all summarization templates
+// @Description Get the caller's summarization templates and the shared ones
 // @Tags summaries
 // @Produce json
 // @Success 200 {array} models.SummaryTemplate
@@ -38,16 +93,16 @@ type SummarySettingsResponse struct {
 // @Router /api/v1/summaries [get]
 func (h *Handler) ListSummaryTemplates(c *gin.Context) {
 	var items []models.SummaryTemplate
-	if err := database.DB.Order("created_at DESC").Find(&items).Error; err != nil {
+	if err := visibleTemplates(database.DB, currentUserID(c)).Order("created_at DESC").Find(&items).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch templates"})
 		return
 	}
 	c.JSON(http.StatusOK, items)
 }
 
-// CreateSummaryTemplate creates a new template
+// CreateSummaryTemplate creates a new template owned by the caller
 // @Summary Create summarization template
-// @Description Create a new summarization template
+// @Description Create a new summarization template owned by the caller. With layout per_speaker, summaries get a section per participant with their key points and commitments.
 // @Tags summaries
 // @Accept json
 // @Produce json
@@ -66,10 +121,12 @@ func (h *Handler) CreateSummaryTemplate(c *gin.Context) {
 		return
 	}
 	item := models.SummaryTemplate{
+		UserID:      currentUserID(c),
 		Name:        req.Name,
 		Description: req.Description,
 		Model:       req.Model,
 		Prompt:      req.Prompt,
+		Layout:      req.Layout,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -94,14 +151,8 @@ func (h *Handler) CreateSummaryTemplate(c *gin.Context) {
 // @Security BearerAuth
 // @Router /api/v1/summaries/{id} [get]
 func (h *Handler) GetSummaryTemplate(c *gin.Context) {
-	id := c.Param("id")
-	var item models.SummaryTemplate
-	if err := database.DB.Where("id = ?", id).First(&item).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch template"})
+	item, ok := loadSummaryTemplate(c, c.Param("id"))
+	if !ok {
 		return
 	}
 	c.JSON(http.StatusOK, item)
@@ -124,27 +175,22 @@ func (h *Handler) GetSummaryTemplate(c *gin.Context) {
 // @Security BearerAuth
 // @Router /api/v1/summaries/{id} [put]
 func (h *Handler) UpdateSummaryTemplate(c *gin.Context) {
-	id := c.Param("id")
 	var req SummaryTemplateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	var item models.SummaryTemplate
-	if err := database.DB.Where("id = ?", id).First(&item).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch template"})
+	item, ok := loadSummaryTemplate(c, c.Param("id"))
+	if !ok {
 		return
 	}
 	item.Name = req.Name
 	item.Description = req.Description
 	item.Model = req.Model
 	item.Prompt = req.Prompt
+	item.Layout = req.Layout
 	item.UpdatedAt = time.Now()
-	if err := database.DB.Save(&item).Error; err != nil {
+	if err := database.DB.Save(item).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update template"})
 		return
 	}
@@ -158,12 +204,16 @@ func (h *Handler) UpdateSummaryTemplate(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Template ID"
 // @Success 204 {string} string "No Content"
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
 // @Router /api/v1/summaries/{id} [delete]
 func (h *Handler) DeleteSummaryTemplate(c *gin.Context) {
-	id := c.Param("id")
-	if err := database.DB.Delete(&models.SummaryTemplate{}, "id = ?", id).Error; err != nil {
+	item, ok := loadSummaryTemplate(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	if err := database.DB.Delete(item).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete template"})
 		return
 	}
@@ -233,3 +283,87 @@ func (h *Handler) SaveSummarySettings(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, SummarySettingsResponse{DefaultModel: s.DefaultModel})
 }
+
+// GetDefaultSummaryTemplate returns the caller's default summary template
+// @Summary Get default summary template
+// @Description Get the summary template post-processing uses for the caller's jobs that don't choose one
+// @Tags summaries
+// @Produce json
+// @Success 200 {object} DefaultSummaryTemplateResponse
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /api/v1/user/default-summary-template [get]
+func (h *Handler) GetDefaultSummaryTemplate(c *gin.Context) {
+	userID := currentUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, *userID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	response := DefaultSummaryTemplateResponse{TemplateID: user.DefaultSummaryTemplateID}
+	if user.DefaultSummaryTemplateID != nil {
+		var item models.SummaryTemplate
+		err := visibleTemplates(database.DB, userID).Where("id = ?", *user.DefaultSummaryTemplateID).First(&item).Error
+		switch {
+		case err == nil:
+			response.Template = &item
+		case err == gorm.ErrRecordNotFound:
+			// The default template was deleted; post-processing falls back to the built-in prompt
+			response.TemplateID = nil
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch template"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// SetDefaultSummaryTemplate sets or clears the caller's default summary template
+// @Summary Set default summary template
+// @Description Set the summary template post-processing uses for the caller's jobs that don't choose one; an empty template_id clears it
+// @Tags summaries
+// @Accept json
+// @Produce json
+// @Param request body SetDefaultSummaryTemplateRequest true "Default template"
+// @Success 200 {object} DefaultSummaryTemplateResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /api/v1/user/default-summary-template [post]
+func (h *Handler) SetDefaultSummaryTemplate(c *gin.Context) {
+	userID := currentUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req SetDefaultSummaryTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	var response DefaultSummaryTemplateResponse
+	if id := strings.TrimSpace(req.TemplateID); id != "" {
+		item, ok := loadSummaryTemplate(c, id)
+		if !ok {
+			return
+		}
+		response = DefaultSummaryTemplateResponse{TemplateID: &item.ID, Template: item}
+	}
+
+	if err := database.DB.Model(&models.User{}).Where("id = ?", *userID).Update("default_summary_template_id", response.TemplateID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set default template"})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
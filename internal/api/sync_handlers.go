@@ -0,0 +1,252 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultSyncLimit is how many changed transcriptions a sync returns when the caller
+	// doesn't say
+	defaultSyncLimit = 200
+	// maxSyncLimit caps the transcriptions returned by one sync
+	maxSyncLimit = 1000
+)
+
+// SyncJob is the part of a transcription a client keeps in its local copy
+type SyncJob struct {
+	ID           string           `json:"id"`
+	Title        *string          `json:"title,omitempty"`
+	Status       models.JobStatus `json:"status"`
+	ContentType  string           `json:"content_type"`
+	Tags         []string         `json:"tags"`
+	HasSummary   bool             `json:"has_summary"`
+	ErrorMessage *string          `json:"error_message,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+// SyncSummary is the current summary of a transcription
+type SyncSummary struct {
+	TranscriptionID string  `json:"transcription_id"`
+	Content         string  `json:"content"`
+	Model           *string `json:"model,omitempty"`
+}
+
+// SyncTag is a tag a client can filter by
+type SyncTag struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// SyncDeleted lists what was deleted since the cursor
+type SyncDeleted struct {
+	Jobs      []string `json:"jobs"`
+	Summaries []string `json:"summaries"` // Transcriptions whose summary was deleted
+	Tags      []uint   `json:"tags"`
+}
+
+// SyncResponse carries the changes since a cursor
+type SyncResponse struct {
+	Cursor    string        `json:"cursor"`
+	Full      bool          `json:"full"` // No cursor was given, so everything is included
+	HasMore   bool          `json:"has_more"`
+	Jobs      []SyncJob     `json:"jobs"`
+	Summaries []SyncSummary `json:"summaries"`
+	Tags      []SyncTag     `json:"tags"`
+	Deleted   SyncDeleted   `json:"deleted"`
+}
+
+// syncJobColumns are the transcription columns a sync reads
+var syncJobColumns = []string{"id", "title", "status", "content_type", "tags", "summary", "summary_model", "error_message", "created_at", "updated_at"}
+
+// Sync returns what changed in the caller's library since a cursor
+// @Summary Sync changes since a cursor
+// @Description Return the caller's transcriptions, summaries and tags created or changed since cursor, and the IDs of those deleted, for clients keeping a local copy such as mobile apps. Without a cursor everything is returned. Transcriptions carry only their list fields; summaries come separately, and only when they change. Pass cursor back on the next sync; while has_more is true, sync again right away for the rest. Cursors are opaque.
+// @Tags sync
+// @Produce json
+// @Param cursor query string false "Cursor returned by the previous sync (default: full sync)"
+// @Param limit query int false "Maximum transcriptions to return (default 200, max 1000)"
+// @Success 200 {object} SyncResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/sync [get]
+func (h *Handler) Sync(c *gin.Context) {
+	var since time.Time
+	full := true
+	if raw := c.Query("cursor"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cursor must be one returned by an earlier sync"})
+			return
+		}
+		since, full = parsed, false
+	}
+
+	limit := defaultSyncLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxSyncLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+			return
+		}
+		limit = parsed
+	}
+
+	userID := currentUserID(c)
+	// Everything up to now is covered, unless there are more changed transcriptions than fit
+	until := time.Now()
+	jobs, until, hasMore, err := changedJobs(userID, full, since, until, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read transcriptions"})
+		return
+	}
+
+	response := SyncResponse{
+		Cursor:    until.Format(time.RFC3339Nano),
+		Full:      full,
+		HasMore:   hasMore,
+		Jobs:      make([]SyncJob, len(jobs)),
+		Summaries: []SyncSummary{},
+		Tags:      []SyncTag{},
+		Deleted:   SyncDeleted{Jobs: []string{}, Summaries: []string{}, Tags: []uint{}},
+	}
+	for i, job := range jobs {
+		response.Jobs[i] = SyncJob{
+			ID:           job.ID,
+			Title:        job.Title,
+			Status:       job.Status,
+			ContentType:  job.ContentType,
+			Tags:         job.Tags,
+			HasSummary:   job.Summary != nil,
+			ErrorMessage: job.ErrorMessage,
+			CreatedAt:    job.CreatedAt,
+			UpdatedAt:    job.UpdatedAt,
+		}
+		if response.Jobs[i].Tags == nil {
+			response.Jobs[i].Tags = []string{}
+		}
+		// A full sync has no summary events to go by, so the summaries come with their transcriptions
+		if full && job.Summary != nil {
+			response.Summaries = append(response.Summaries, SyncSummary{TranscriptionID: job.ID, Content: *job.Summary, Model: job.SummaryModel})
+		}
+	}
+
+	tagQuery := visibleTags(database.DB.Model(&models.Tag{}), userID).Where("updated_at <= ?", until)
+	if !full {
+		tagQuery = tagQuery.Where("updated_at > ?", since)
+	}
+	if err := tagQuery.Select("id", "name").Order("id ASC").Scan(&response.Tags).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read tags"})
+		return
+	}
+
+	if !full {
+		if err := syncDeletions(userID, since, until, &response); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read changes"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// changedJobs returns up to limit of the caller's transcriptions changed after since (any
+// time, for a full sync) and up to until, oldest change first. When there are more, until is
+// moved back to the last one returned, along with any others changed at that same instant.
+func changedJobs(userID *uint, full bool, since, until time.Time, limit int) ([]models.TranscriptionJob, time.Time, bool, error) {
+	query := func() *gorm.DB {
+		changed := scopeToOwner(database.DB.Model(&models.TranscriptionJob{}), userID).Select(syncJobColumns)
+		if !full {
+			changed = changed.Where("updated_at > ?", since)
+		}
+		return changed
+	}
+
+	var jobs []models.TranscriptionJob
+	if err := query().Where("updated_at <= ?", until).Order("updated_at ASC, id ASC").Limit(limit + 1).Find(&jobs).Error; err != nil {
+		return nil, until, false, err
+	}
+	if len(jobs) <= limit {
+		return jobs, until, false, nil
+	}
+
+	jobs = jobs[:limit]
+	last := jobs[limit-1]
+	var ties []models.TranscriptionJob
+	if err := query().Where("updated_at = ? AND id > ?", last.UpdatedAt, last.ID).Order("id ASC").Find(&ties).Error; err != nil {
+		return nil, until, false, err
+	}
+	return append(jobs, ties...), last.UpdatedAt, true, nil
+}
+
+// syncDeletions adds the deletions and summary changes recorded in the event log after since
+// and up to until to a sync response
+func syncDeletions(userID *uint, since, until time.Time, response *SyncResponse) error {
+	var found []models.Event
+	query := database.DB.Model(&models.Event{}).Select("type", "subject_id").
+		Where("created_at > ? AND created_at <= ?", since, until).
+		Where("type IN ?", []string{models.EventJobDeleted, models.EventSummaryReady, models.EventSummaryDeleted, models.EventTagDeleted})
+	// Like tags themselves, deletions of tags without an owner are seen by everyone
+	if userID == nil {
+		query = query.Where("user_id IS NULL")
+	} else {
+		query = query.Where("user_id = ? OR (user_id IS NULL AND type = ?)", *userID, models.EventTagDeleted)
+	}
+	if err := query.Order("sequence ASC").Find(&found).Error; err != nil {
+		return err
+	}
+
+	// Summaries written or deleted, by transcription, in the order they changed
+	var summarized []string
+	seen := map[string]bool{}
+	for _, event := range found {
+		switch event.Type {
+		case models.EventJobDeleted:
+			response.Deleted.Jobs = append(response.Deleted.Jobs, event.SubjectID)
+		case models.EventTagDeleted:
+			if id, err := strconv.ParseUint(event.SubjectID, 10, 64); err == nil {
+				response.Deleted.Tags = append(response.Deleted.Tags, uint(id))
+			}
+		default:
+			if !seen[event.SubjectID] {
+				seen[event.SubjectID] = true
+				summarized = append(summarized, event.SubjectID)
+			}
+		}
+	}
+	if len(summarized) == 0 {
+		return nil
+	}
+
+	// The summary as it is now tells whether it was rewritten or deleted last; summaries of
+	// deleted transcriptions go with them
+	var jobs []models.TranscriptionJob
+	if err := scopeToOwner(database.DB.Model(&models.TranscriptionJob{}), userID).Select("id", "summary", "summary_model").
+		Where("id IN ?", summarized).Find(&jobs).Error; err != nil {
+		return err
+	}
+	byID := make(map[string]models.TranscriptionJob, len(jobs))
+	for _, job := range jobs {
+		byID[job.ID] = job
+	}
+	for _, id := range summarized {
+		job, ok := byID[id]
+		switch {
+		case !ok:
+		case job.Summary != nil:
+			response.Summaries = append(response.Summaries, SyncSummary{TranscriptionID: id, Content: *job.Summary, Model: job.SummaryModel})
+		default:
+			response.Deleted.Summaries = append(response.Deleted.Summaries, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"scriberr/internal/database"
+	"scriberr/internal/events"
+	"scriberr/internal/models"
+	"scriberr/internal/tagging"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TagRequest names a tag
+type TagRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// TagResponse is a tag with the number of transcriptions carrying it
+type TagResponse struct {
+	models.Tag
+	Count int64 `json:"count"`
+}
+
+// visibleTags limits a tag query to the caller's tags and the tags of transcriptions without an owner
+func visibleTags(db *gorm.DB, userID *uint) *gorm.DB {
+	if userID == nil {
+		return db.Where("tags.user_id IS NULL")
+	}
+	return db.Where("tags.user_id = ? OR tags.user_id IS NULL", *userID)
+}
+
+// loadTag loads a tag visible to the caller, writing an error response if it can't
+func loadTag(c *gin.Context) (*models.Tag, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return nil, false
+	}
+	var tag models.Tag
+	if err := visibleTags(database.DB, currentUserID(c)).Where("tags.id = ?", id).First(&tag).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tag"})
+		}
+		return nil, false
+	}
+	return &tag, true
+}
+
+// bindTagName parses and normalizes the name of a tag request
+func bindTagName(c *gin.Context) (string, bool) {
+	var req TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	}
+	names := tagging.Normalize([]string{req.Name})
+	if len(names) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return "", false
+	}
+	return names[0], true
+}
+
+// refreshTaggedJobs copies changed tags onto the vector store entries of the transcriptions carrying them
+func (h *Handler) refreshTaggedJobs(jobIDs []string) {
+	for _, id := range jobIDs {
+		h.refreshRAGMetadata(id)
+	}
+}
+
+// ListTags returns the caller's tags with the number of transcriptions carrying each
+// @Summary List tags
+// @Description List the caller's tags, including those generated after transcription, with how many transcriptions carry each, most used first
+// @Tags tags
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/tags [get]
+func (h *Handler) ListTags(c *gin.Context) {
+	var result []TagResponse
+	err := visibleTags(database.DB.Model(&models.Tag{}), currentUserID(c)).
+		Select("tags.*, COUNT(transcription_tags.tag_id) AS count").
+		Joins("LEFT JOIN transcription_tags ON transcription_tags.tag_id = tags.id").
+		Group("tags.id").
+		Order("count DESC, tags.name ASC").
+		Scan(&result).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list tags"})
+		return
+	}
+	if result == nil {
+		result = []TagResponse{}
+	}
+	c.JSON(http.StatusOK, gin.H{"tags": result})
+}
+
+// CreateTag creates a tag without adding it to any transcription
+// @Summary Create a tag
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param request body TagRequest true "Tag name"
+// @Success 201 {object} models.Tag
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/tags [post]
+func (h *Handler) CreateTag(c *gin.Context) {
+	name, ok := bindTagName(c)
+	if !ok {
+		return
+	}
+
+	userID := currentUserID(c)
+	var count int64
+	if err := tagging.OwnedBy(database.DB.Model(&models.Tag{}), userID).Where("LOWER(tags.name) = LOWER(?)", name).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tag"})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": tagging.ErrTagExists.Error()})
+		return
+	}
+
+	tag := models.Tag{UserID: userID, Name: name}
+	if err := database.DB.Create(&tag).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tag"})
+		return
+	}
+	c.JSON(http.StatusCreated, tag)
+}
+
+// UpdateTag renames a tag on every transcription carrying it
+// @Summary Rename a tag
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Param request body TagRequest true "New name"
+// @Success 200 {object} models.Tag
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/tags/{id} [put]
+func (h *Handler) UpdateTag(c *gin.Context) {
+	tag, ok := loadTag(c)
+	if !ok {
+		return
+	}
+	name, ok := bindTagName(c)
+	if !ok {
+		return
+	}
+
+	jobIDs, err := tagging.Rename(tag, name)
+	if err != nil {
+		if errors.Is(err, tagging.ErrTagExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rename tag"})
+		return
+	}
+	h.refreshTaggedJobs(jobIDs)
+	c.JSON(http.StatusOK, tag)
+}
+
+// DeleteTag deletes a tag and removes it from every transcription carrying it
+// @Summary Delete a tag
+// @Tags tags
+// @Param id path int true "Tag ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/tags/{id} [delete]
+func (h *Handler) DeleteTag(c *gin.Context) {
+	tag, ok := loadTag(c)
+	if !ok {
+		return
+	}
+	jobIDs, err := tagging.Delete(tag)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete tag"})
+		return
+	}
+	events.Record(models.EventTagDeleted, strconv.FormatUint(uint64(tag.ID), 10), tag.UserID, map[string]interface{}{"name": tag.Name})
+	h.refreshTaggedJobs(jobIDs)
+	c.JSON(http.StatusOK, gin.H{"message": "Tag deleted successfully"})
+}
+
+// ListTagTranscriptions returns the transcriptions carrying a tag
+// @Summary List a tag's transcriptions
+// @Description List the transcriptions carrying a tag, newest first, and whether the tag was added by hand or generated
+// @Tags tags
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/tags/{id}/transcriptions [get]
+func (h *Handler) ListTagTranscriptions(c *gin.Context) {
+	tag, ok := loadTag(c)
+	if !ok {
+		return
+	}
+
+	var links []models.TranscriptionTag
+	if err := database.DB.Where("tag_id = ?", tag.ID).Find(&links).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list transcriptions"})
+		return
+	}
+	sources := make(map[string]string, len(links))
+	ids := make([]string, len(links))
+	for i, link := range links {
+		sources[link.TranscriptionID] = link.Source
+		ids[i] = link.TranscriptionID
+	}
+
+	var jobs []models.TranscriptionJob
+	if len(ids) > 0 {
+		if err := database.DB.Select("id", "title", "status", "tags", "created_at").Where("id IN ?", ids).
+			Order("created_at DESC").Find(&jobs).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list transcriptions"})
+			return
+		}
+	}
+
+	transcriptions := make([]gin.H, len(jobs))
+	for i, job := range jobs {
+		transcriptions[i] = gin.H{
+			"id":         job.ID,
+			"title":      job.Title,
+			"status":     job.Status,
+			"tags":       job.Tags,
+			"created_at": job.CreatedAt,
+			"source":     sources[job.ID],
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"tag": tag, "transcriptions": transcriptions})
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/workflow"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeRangeRequest picks a slice of a recording, in seconds
+type TimeRangeRequest struct {
+	From float64 `json:"from"`
+	To   float64 `json:"to,omitempty"` // 0 runs to the end of the recording
+}
+
+// timeRange validates the range, writing an error response if it is invalid
+func (r TimeRangeRequest) timeRange(c *gin.Context) (workflow.TimeRange, bool) {
+	if r.From < 0 || r.To < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must be non-negative numbers of seconds"})
+		return workflow.TimeRange{}, false
+	}
+	if r.To > 0 && r.To <= r.From {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be after from"})
+		return workflow.TimeRange{}, false
+	}
+	return workflow.TimeRange{From: r.From, To: r.To}, true
+}
+
+// RangeSummaryRequest asks for a summary of a slice of a recording
+type RangeSummaryRequest struct {
+	TimeRangeRequest
+	Model       string   `json:"model,omitempty"`                                       // Defaults to the configured summary model
+	Temperature *float64 `json:"temperature,omitempty" binding:"omitempty,min=0,max=2"` // Defaults to 0.7
+	TemplateID  *string  `json:"template_id,omitempty"`                                 // Uses the built-in instructions unless given
+}
+
+// RangeAskRequest asks a question about a slice of a recording
+type RangeAskRequest struct {
+	TimeRangeRequest
+	Question string `json:"question" binding:"required"`
+	Model    string `json:"model,omitempty"` // Defaults to the configured chat model
+}
+
+// RangeResponse is a summary of, or answer about, a slice of a recording
+type RangeResponse struct {
+	TranscriptionID string  `json:"transcription_id"`
+	From            float64 `json:"from"`
+	To              float64 `json:"to,omitempty"`
+	Segments        int     `json:"segments"` // Transcript segments in the range
+	Content         string  `json:"content"`
+	Model           string  `json:"model"`
+}
+
+// loadRangeJob loads the completed transcription named in the path, writing an error
+// response if there is none
+func loadRangeJob(c *gin.Context) (*models.TranscriptionJob, bool) {
+	job, ok := loadJob(c)
+	if !ok {
+		return nil, false
+	}
+	if job.Status != models.StatusCompleted || job.Transcript == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Job not completed, current status: %s", job.Status)})
+		return nil, false
+	}
+	return job, true
+}
+
+// rangeError writes the error response for a failed range summary or question
+func rangeError(c *gin.Context, err error) {
+	if errors.Is(err, workflow.ErrEmptyRange) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No timed transcript segments in this time range"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate a response: " + err.Error()})
+}
+
+// SummarizeTimeRange summarizes a slice of a recording
+// @Summary Summarize part of a transcription
+// @Description Summarize only the transcript segments overlapping a time range, such as one agenda item of a long meeting. The summary is returned, not saved; the transcription's own summary is left as it is.
+// @Tags summarize
+// @Accept json
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param request body RangeSummaryRequest true "Time range and summary options"
+// @Success 200 {object} RangeResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/range/summarize [post]
+func (h *Handler) SummarizeTimeRange(c *gin.Context) {
+	var req RangeSummaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	timeRange, ok := req.timeRange(c)
+	if !ok {
+		return
+	}
+	if h.llmRegistry == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "LLM providers not initialized"})
+		return
+	}
+	service, model, err := h.llmRegistry.For(llm.FeatureSummary)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Model != "" {
+		model = req.Model
+	}
+	temperature := 0.7
+	if req.Temperature != nil {
+		temperature = *req.Temperature
+	}
+
+	job, ok := loadRangeJob(c)
+	if !ok {
+		return
+	}
+	var template *models.SummaryTemplate
+	if req.TemplateID != nil && *req.TemplateID != "" {
+		if template, ok = loadSummaryTemplate(c, *req.TemplateID); !ok {
+			return
+		}
+	}
+
+	summary, segments, err := workflow.SummarizeRange(c.Request.Context(), service, model, temperature, job, timeRange, template)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "Failed to summarize time range", "job_id", job.ID, "range", timeRange, "model", model, "error", err)
+		rangeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, RangeResponse{
+		TranscriptionID: job.ID,
+		From:            timeRange.From,
+		To:              timeRange.To,
+		Segments:        segments,
+		Content:         summary,
+		Model:           model,
+	})
+}
+
+// AskTimeRange answers a question about a slice of a recording
+// @Summary Ask about part of a transcription
+// @Description Answer a question from only the transcript segments overlapping a time range, such as one agenda item of a long meeting, without searching the rest of the recording or the library.
+// @Tags chat
+// @Accept json
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param request body RangeAskRequest true "Time range and question"
+// @Success 200 {object} RangeResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/range/ask [post]
+func (h *Handler) AskTimeRange(c *gin.Context) {
+	var req RangeAskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	question := strings.TrimSpace(req.Question)
+	if question == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "question is required"})
+		return
+	}
+	timeRange, ok := req.timeRange(c)
+	if !ok {
+		return
+	}
+	if h.llmRegistry == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "LLM providers not initialized"})
+		return
+	}
+	service, model, err := h.llmRegistry.For(llm.FeatureChat)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Model != "" {
+		model = req.Model
+	}
+
+	job, ok := loadRangeJob(c)
+	if !ok {
+		return
+	}
+	answer, segments, err := workflow.AskRange(c.Request.Context(), service, model, job, timeRange, question)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "Failed to answer time range question", "job_id", job.ID, "range", timeRange, "model", model, "error", err)
+		rangeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, RangeResponse{
+		TranscriptionID: job.ID,
+		From:            timeRange.From,
+		To:              timeRange.To,
+		Segments:        segments,
+		Content:         answer,
+		Model:           model,
+	})
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/topics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetTopicService enables topic clustering of the transcript library
+func (h *Handler) SetTopicService(service *topics.Service) {
+	h.topicService = service
+}
+
+// ListTopics returns the topics the caller's transcriptions are grouped into
+// @Summary List library topics
+// @Description List the themes the caller's transcriptions were clustered into, largest first, with the transcriptions in each (most representative first). Topics are regenerated periodically in the background.
+// @Tags rag
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/rag/topics [get]
+func (h *Handler) ListTopics(c *gin.Context) {
+	collection, err := rag.ResolveCollection(currentUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var stored []models.Topic
+	if err := database.DB.Where("collection = ?", collection).Order("size DESC, label ASC").Find(&stored).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list topics"})
+		return
+	}
+
+	// Attach titles so the library can be browsed without another request per transcription
+	var ids []string
+	for _, topic := range stored {
+		ids = append(ids, topic.TranscriptionIDs...)
+	}
+	titles := map[string]*string{}
+	if len(ids) > 0 {
+		var jobs []models.TranscriptionJob
+		database.DB.Select("id", "title").Where("id IN ?", ids).Find(&jobs)
+		for _, job := range jobs {
+			titles[job.ID] = job.Title
+		}
+	}
+
+	result := make([]gin.H, 0, len(stored))
+	var generatedAt *time.Time
+	for i, topic := range stored {
+		transcriptions := make([]gin.H, 0, len(topic.TranscriptionIDs))
+		for _, id := range topic.TranscriptionIDs {
+			title, ok := titles[id]
+			if !ok {
+				// Deleted since the last refresh
+				continue
+			}
+			transcriptions = append(transcriptions, gin.H{"id": id, "title": title})
+		}
+		result = append(result, gin.H{
+			"id":             topic.ID,
+			"label":          topic.Label,
+			"description":    topic.Description,
+			"size":           len(transcriptions),
+			"transcriptions": transcriptions,
+		})
+		if generatedAt == nil {
+			generatedAt = &stored[i].CreatedAt
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"topics":       result,
+		"generated_at": generatedAt,
+		"refreshing":   h.topicService != nil && h.topicService.IsRefreshing(collection),
+	})
+}
+
+// RefreshTopics re-clusters the caller's transcriptions in the background
+// @Summary Refresh library topics
+// @Description Cluster the caller's transcriptions again and relabel the topics with the LLM. Runs in the background; poll GET /api/v1/rag/topics until refreshing is false. Admins only.
+// @Tags rag
+// @Produce json
+// @Success 202 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/rag/topics/refresh [post]
+func (h *Handler) RefreshTopics(c *gin.Context) {
+	if h.topicService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Topic clustering requires RAG to be configured"})
+		return
+	}
+	collection, err := rag.ResolveCollection(currentUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.topicService.RefreshInBackground(collection); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Topic refresh started"})
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/events"
+	"scriberr/internal/importer"
+	"scriberr/internal/models"
+	"scriberr/internal/versions"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxTranscriptImportSize caps the transcript file of an import; exports of even very long
+// recordings are far smaller
+const maxTranscriptImportSize = 50 << 20
+
+// TranscriptImportResponse describes a transcription created from another tool's transcript
+type TranscriptImportResponse struct {
+	Job            models.TranscriptionJob `json:"job"`
+	Format         string                  `json:"format"`          // Format the transcript was read as
+	Segments       int                     `json:"segments"`        // Segments in the transcript
+	Speakers       int                     `json:"speakers"`        // Distinct speakers in the transcript
+	PostProcessing bool                    `json:"post_processing"` // Whether the post-processing workflow was started
+}
+
+// ImportTranscript creates a completed transcription from a transcript made by another tool
+// @Summary Import a transcript from another tool
+// @Description Bring a transcript made elsewhere into Scriberr as a completed transcription: Whisper or whisper.cpp JSON, WhisperX JSON (with speakers and word timestamps), an otter.ai TXT export, a Descript plain text export, or SRT or WebVTT subtitles. The format is detected from the file unless given. Speakers the transcript names are labelled SPEAKER_00, SPEAKER_01 and so on with their names as custom speaker names. Paragraphs of text exports end where the next one starts. The recording can be uploaded along with it for playback; without it the transcription can't be transcribed again. The transcription then goes through post-processing like one transcribed here, which summarizes it and adds it to the RAG index; when post-processing is disabled it is added to the RAG index directly.
+// @Tags transcription
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Transcript file"
+// @Param format formData string false "Format of the transcript: whisper, whisperx, otter, descript, srt or vtt (default detected)"
+// @Param audio formData file false "The recording the transcript is of"
+// @Param title formData string false "Title (default the transcript's file name)"
+// @Param content_type formData string false "meeting, voice_memo or podcast" default(meeting)
+// @Param project_id formData string false "Project to file the transcription in; gives it the project's content type unless content_type is set, its tags and its post-processing settings"
+// @Success 201 {object} TranscriptImportResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/import-transcript [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ImportTranscript(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Transcript file is required"})
+		return
+	}
+	if header.Size > maxTranscriptImportSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Transcript file is larger than %d MB", maxTranscriptImportSize>>20)})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read transcript file"})
+		return
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read transcript file"})
+		return
+	}
+	transcript, err := importer.Parse(data, c.PostForm("format"), header.Filename)
+	if err != nil {
+		if errors.Is(err, importer.ErrUnknownFormat) {
+			err = fmt.Errorf("%w: pass format as one of %s", err, strings.Join(importer.Formats, ", "))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	contentType, ok := contentTypeFromForm(c)
+	if !ok {
+		return
+	}
+	project, ok := projectFromForm(c)
+	if !ok {
+		return
+	}
+	encoded, err := json.Marshal(transcript.Result)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode transcript"})
+		return
+	}
+	stored := string(encoded)
+
+	title := strings.TrimSpace(c.PostForm("title"))
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(header.Filename), filepath.Ext(header.Filename))
+	}
+	job := models.TranscriptionJob{
+		ID:          uuid.New().String(),
+		UserID:      currentUserID(c),
+		Title:       &title,
+		Status:      models.StatusCompleted,
+		Transcript:  &stored,
+		Diarization: transcript.Speakers() > 0,
+		ContentType: contentType,
+	}
+	project.apply(c, &job)
+	if language := strings.ToLower(transcript.Result.Language); language != "" && len(language) <= 10 {
+		job.DetectedLanguage = &language
+	}
+
+	if audio, err := c.FormFile("audio"); err == nil {
+		if err := os.MkdirAll(h.config.UploadDir, 0755); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload directory"})
+			return
+		}
+		job.AudioPath = filepath.Join(h.config.UploadDir, job.ID+filepath.Ext(audio.Filename))
+		if err := c.SaveUploadedFile(audio, job.AudioPath); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save audio"})
+			return
+		}
+		if !h.storeFile(c, job.AudioPath) {
+			return
+		}
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&job).Error; err != nil {
+			return err
+		}
+		if _, err := versions.Record(tx, job.ID, models.VersionKindTranscript, versions.Content{Text: stored}, models.VersionSourceImport, job.UserID); err != nil {
+			return err
+		}
+		for label, name := range transcript.SpeakerNames {
+			mapping := models.SpeakerMapping{TranscriptionJobID: job.ID, OriginalSpeaker: label, CustomName: name}
+			if err := tx.Omit("TranscriptionJob").Create(&mapping).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if job.AudioPath != "" {
+			os.Remove(job.AudioPath)
+			h.removeStoredFile(job.AudioPath)
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
+		return
+	}
+	project.tag(&job, nil)
+	events.RecordForJob(models.EventJobCompleted, job.ID, map[string]interface{}{"imported": transcript.Format})
+	if h.files != nil {
+		if err := h.files.SaveTranscript(c.Request.Context(), job.ID, stored); err != nil {
+			logger.ErrorContext(c.Request.Context(), "Failed to store transcript", "job_id", job.ID, "error", err)
+		}
+	}
+
+	// Imported transcriptions are post-processed like ones transcribed here
+	postProcessing := false
+	if h.workflowEngine != nil {
+		h.workflowEngine.OnTranscriptionCompleted(job.ID)
+		postProcessing = true
+	} else if h.ragService != nil {
+		go func(job models.TranscriptionJob) {
+			if err := h.storeJobInRAG(&job); err != nil {
+				logger.Error("Failed to index imported transcription", "job_id", job.ID, "error", err)
+			}
+		}(job)
+	}
+
+	c.JSON(http.StatusCreated, TranscriptImportResponse{
+		Job:            job,
+		Format:         transcript.Format,
+		Segments:       len(transcript.Result.Segments),
+		Speakers:       transcript.Speakers(),
+		PostProcessing: postProcessing,
+	})
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/workflow"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TranslateRequest asks for a transcript to be translated
+type TranslateRequest struct {
+	Language string `json:"language" binding:"required,max=64"` // Target language, e.g. "German" or "de"
+	Model    string `json:"model,omitempty"`                    // Defaults to the summary model
+	Index    *bool  `json:"index,omitempty"`                    // Index the translation into RAG; defaults to INDEX_TRANSLATIONS
+}
+
+// ListTranslations returns the stored translations of a transcription
+// @Summary List translations
+// @Description Get every stored translation of a transcription, segment by segment with the source segments' timing and speakers, along with the source and target language
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Success 200 {array} models.Translation
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/translations [get]
+func (h *Handler) ListTranslations(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	translations := []models.Translation{}
+	if err := database.DB.Where("transcription_job_id = ?", job.ID).Order("created_at").Find(&translations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get translations"})
+		return
+	}
+	c.JSON(http.StatusOK, translations)
+}
+
+// loadTranslation fetches the translation of a job into the :language path parameter,
+// writing the error response if it can't
+func loadTranslation(c *gin.Context, job *models.TranscriptionJob) (*models.Translation, bool) {
+	var translation models.Translation
+	if err := database.DB.Where("transcription_job_id = ? AND language = ?", job.ID, c.Param("language")).Limit(1).Find(&translation).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get translation"})
+		return nil, false
+	}
+	if translation.ID == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No translation into this language"})
+		return nil, false
+	}
+	return &translation, true
+}
+
+// GetTranslation returns one translation of a transcription
+// @Summary Get a translation
+// @Description Get the translation of a transcription into one language
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param language path string true "Target language of the translation"
+// @Success 200 {object} models.Translation
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/translations/{language} [get]
+func (h *Handler) GetTranslation(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	translation, ok := loadTranslation(c, job)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, translation)
+}
+
+// TranslateTranscription translates a transcript on demand
+// @Summary Translate a transcript
+// @Description Translate a completed transcription into another language with the configured summary provider, segment by segment, and store it next to the original, replacing an earlier translation into the same language. With index, the translation is also indexed into RAG so chat and search find the recording in either language.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param request body TranslateRequest true "Target language"
+// @Success 201 {object} models.Translation
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/translations [post]
+func (h *Handler) TranslateTranscription(c *gin.Context) {
+	var req TranslateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Language = strings.TrimSpace(req.Language)
+	if req.Language == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "language is required"})
+		return
+	}
+	index := h.config != nil && h.config.IndexTranslations
+	if req.Index != nil {
+		index = *req.Index
+	}
+	if index && h.ragService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "RAG service not initialized"})
+		return
+	}
+
+	if h.llmRegistry == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "LLM providers not initialized"})
+		return
+	}
+	service, model, err := h.llmRegistry.For(llm.FeatureSummary)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Model != "" {
+		model = req.Model
+	}
+
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	if job.Status != models.StatusCompleted {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Transcription is not completed"})
+		return
+	}
+
+	start := time.Now()
+	translation, err := workflow.TranslateJob(c.Request.Context(), service, model, h.ragService, index, job, req.Language)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "Failed to translate transcript", "job_id", job.ID, "language", req.Language, "model", model, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to translate transcript: " + err.Error()})
+		return
+	}
+	logger.InfoContext(c.Request.Context(), "Translated transcript", "job_id", job.ID, "language", req.Language, "model", model, "segments", len(translation.Segments), "duration_ms", time.Since(start).Milliseconds())
+	c.JSON(http.StatusCreated, translation)
+}
+
+// DeleteTranslation deletes one translation of a transcription
+// @Summary Delete a translation
+// @Description Delete the translation of a transcription into one language, and remove it from RAG
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param language path string true "Target language of the translation"
+// @Success 204 {string} string "No Content"
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/translations/{language} [delete]
+func (h *Handler) DeleteTranslation(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	if rejectIfOnHold(c, job) {
+		return
+	}
+	translation, ok := loadTranslation(c, job)
+	if !ok {
+		return
+	}
+	if h.ragService != nil && translation.Indexed {
+		if err := h.ragService.DeleteTranslation(job.ID, translation.Language); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove translation from RAG: " + err.Error()})
+			return
+		}
+	}
+	if err := database.DB.Delete(translation).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete translation"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,379 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/netguard"
+	"scriberr/internal/queue"
+	"scriberr/internal/tagging"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// urlImportStallTimeout is how long a download may go without progress before it is
+// considered lost, such as after a restart
+const urlImportStallTimeout = 2 * time.Minute
+
+// urlImportProgressInterval is how often the bytes downloaded are saved
+const urlImportProgressInterval = time.Second
+
+// ImportURLRequest submits a remote audio file for transcription
+type ImportURLRequest struct {
+	URL         string  `json:"url" binding:"required"`
+	Title       *string `json:"title,omitempty"`        // Defaults to the file name
+	ContentType string  `json:"content_type,omitempty"` // meeting (default), voice_memo or podcast
+	Priority    int     `json:"priority,omitempty"`
+	InitialPromptRequest
+}
+
+// urlImportMaxBytes returns the largest file a URL import downloads
+func (h *Handler) urlImportMaxBytes() int64 {
+	if h.config.URLImportMaxMB <= 0 {
+		return 2048 << 20
+	}
+	return int64(h.config.URLImportMaxMB) << 20
+}
+
+// urlImportTimeout returns how long a URL import may take to download
+func (h *Handler) urlImportTimeout() time.Duration {
+	if h.config.URLImportTimeoutMinutes <= 0 {
+		return time.Hour
+	}
+	return time.Duration(h.config.URLImportTimeoutMinutes) * time.Minute
+}
+
+// urlImportClient returns the client that downloads URL imports. Unless private networks
+// are allowed, it refuses to connect to loopback, private and link-local addresses, which
+// is checked on each connection so redirects and DNS changes can't get around it.
+func (h *Handler) urlImportClient() *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	if !h.config.URLImportAllowPrivate {
+		dialer.Control = netguard.Control
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Transport: transport}
+}
+
+// isAudioContentType reports whether a download's Content-Type may be audio. Servers that
+// don't know the type send application/octet-stream, which is let through for ffmpeg to judge.
+func isAudioContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "audio/"), strings.HasPrefix(mediaType, "video/"):
+		return true
+	case mediaType == "application/octet-stream", mediaType == "binary/octet-stream", mediaType == "application/ogg":
+		return true
+	}
+	return false
+}
+
+// importFilename picks the name of a downloaded file: from Content-Disposition, the URL
+// path, or else the import ID with an extension for its Content-Type
+func importFilename(resp *http.Response, importID string) string {
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		if name := filepath.Base(params["filename"]); filepath.Ext(name) != "" {
+			return name
+		}
+	}
+	if name := path.Base(resp.Request.URL.Path); filepath.Ext(name) != "" {
+		return name
+	}
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil {
+		if extensions, _ := mime.ExtensionsByType(mediaType); len(extensions) > 0 {
+			return importID + extensions[0]
+		}
+	}
+	return importID
+}
+
+// progressWriter saves how many bytes of a URL import have been downloaded, at most once
+// per urlImportProgressInterval
+type progressWriter struct {
+	importID string
+	written  int64
+	saved    time.Time
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.written += int64(len(p))
+	if time.Since(w.saved) >= urlImportProgressInterval {
+		w.saved = time.Now()
+		database.DB.Model(&models.URLImport{}).Where("id = ?", w.importID).Update("downloaded_bytes", w.written)
+	}
+	return len(p), nil
+}
+
+// failStalledImports marks downloads that stopped making progress as failed
+func failStalledImports() {
+	database.DB.Model(&models.URLImport{}).
+		Where("status = ? AND updated_at < ?", models.URLImportDownloading, time.Now().Add(-urlImportStallTimeout)).
+		Updates(map[string]interface{}{"status": models.URLImportFailed, "error": "Download stopped making progress"})
+}
+
+// ImportFromURL downloads a remote audio file and queues it for transcription
+// @Summary Transcribe audio from a URL
+// @Description Download an audio or video file from an http(s) URL, such as a call recording provider's link, and queue it like an upload to /transcription/upload. The download runs in the background: poll GET /transcription/from-url/{id} for its progress and, once complete, the transcription ID, which is the import ID. Files over URL_IMPORT_MAX_MB and responses that aren't audio or video are refused.
+// @Tags transcription
+// @Accept json
+// @Produce json
+// @Param request body ImportURLRequest true "URL to import"
+// @Success 202 {object} models.URLImport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 507 {object} map[string]interface{}
+// @Router /api/v1/transcription/from-url [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) ImportFromURL(c *gin.Context) {
+	urlImport, ok := bindURLImport(c)
+	if !ok {
+		return
+	}
+	if err := database.DB.Create(urlImport).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create import"})
+		return
+	}
+
+	go h.runURLImport(*urlImport)
+	c.JSON(http.StatusAccepted, urlImport)
+}
+
+// bindURLImport reads and validates an ImportURLRequest into a new URL import, writing an
+// error response if it is invalid
+func bindURLImport(c *gin.Context) (*models.URLImport, bool) {
+	var req ImportURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	parsed, err := url.Parse(strings.TrimSpace(req.URL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url must be an http or https URL"})
+		return nil, false
+	}
+	contentType := strings.TrimSpace(req.ContentType)
+	if contentType == "" {
+		contentType = models.ContentMeeting
+	}
+	if !models.IsRecordingContentType(contentType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": contentTypeError})
+		return nil, false
+	}
+	if req.Priority < queue.MinPriority || req.Priority > queue.MaxPriority {
+		c.JSON(http.StatusBadRequest, gin.H{"error": priorityError})
+		return nil, false
+	}
+	failStalledImports()
+
+	urlImport := &models.URLImport{
+		UserID:      currentUserID(c),
+		URL:         parsed.String(),
+		Status:      models.URLImportDownloading,
+		ContentType: contentType,
+		Priority:    req.Priority,
+	}
+	if req.Title != nil && *req.Title != "" {
+		urlImport.Title = req.Title
+	}
+	if prompt := req.InitialPromptRequest.Compose(); prompt != "" {
+		urlImport.InitialPrompt = &prompt
+	}
+	return urlImport, true
+}
+
+// GetURLImport reports the progress of a URL import
+// @Summary Get a URL import
+// @Description Get the state of a URL import: downloading, with the bytes downloaded and, if the server announced it, the total; completed, with the transcription ID; or failed, with the reason.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Import ID"
+// @Success 200 {object} models.URLImport
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/transcription/from-url/{id} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *Handler) GetURLImport(c *gin.Context) {
+	failStalledImports()
+	var urlImport models.URLImport
+	err := scopeToOwner(database.DB, currentUserID(c)).Where("id = ?", c.Param("id")).First(&urlImport).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Import not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get import"})
+		return
+	}
+	c.JSON(http.StatusOK, urlImport)
+}
+
+// runURLImport downloads a URL import and creates its transcription, recording the outcome
+func (h *Handler) runURLImport(urlImport models.URLImport) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.urlImportTimeout())
+	defer cancel()
+
+	var filePath, title string
+	var err error
+	if urlImport.Extractor == models.URLImportYtDlp {
+		filePath, title, err = h.extractMediaImport(ctx, &urlImport)
+	} else {
+		filePath, title, err = h.downloadURLImport(ctx, urlImport)
+	}
+	if err == nil {
+		err = h.createImportedJob(ctx, urlImport, filePath, title)
+		if err != nil {
+			os.Remove(filePath)
+		}
+	}
+	if err != nil {
+		logger.Error("URL import failed", "import_id", urlImport.ID, "error", err)
+		database.DB.Model(&models.URLImport{}).
+			Where("id = ? AND status = ?", urlImport.ID, models.URLImportDownloading).
+			Updates(map[string]interface{}{"status": models.URLImportFailed, "error": err.Error()})
+	}
+}
+
+// downloadURLImport downloads a URL import into the upload directory, returning the file
+// and its name
+func (h *Handler) downloadURLImport(ctx context.Context, urlImport models.URLImport) (string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlImport.URL, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid url: %w", err)
+	}
+	resp, err := h.urlImportClient().Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("download failed: %s", resp.Status)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !isAudioContentType(contentType) {
+		return "", "", fmt.Errorf("not an audio or video file: %s", contentType)
+	}
+	maxBytes := h.urlImportMaxBytes()
+	if resp.ContentLength > maxBytes {
+		return "", "", fmt.Errorf("file is %d bytes, over the %d byte limit", resp.ContentLength, maxBytes)
+	}
+	if resp.ContentLength > 0 {
+		total := resp.ContentLength
+		database.DB.Model(&models.URLImport{}).Where("id = ?", urlImport.ID).Update("total_bytes", total)
+		if err := h.resourceGuard.Check(ctx, total); err != nil {
+			return "", "", err
+		}
+	}
+
+	filename := importFilename(resp, urlImport.ID)
+	if err := os.MkdirAll(h.config.UploadDir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	filePath := filepath.Join(h.config.UploadDir, urlImport.ID+filepath.Ext(filename))
+	file, err := os.Create(filePath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create file: %w", err)
+	}
+	progress := &progressWriter{importID: urlImport.ID, saved: time.Now()}
+	written, err := io.Copy(io.MultiWriter(file, progress), io.LimitReader(resp.Body, maxBytes+1))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written > maxBytes {
+		err = fmt.Errorf("file is over the %d byte limit", maxBytes)
+	}
+	if err != nil {
+		os.Remove(filePath)
+		return "", "", err
+	}
+	database.DB.Model(&models.URLImport{}).Where("id = ?", urlImport.ID).Update("downloaded_bytes", written)
+	return filePath, filename, nil
+}
+
+// createImportedJob creates and queues the transcription of a downloaded URL import
+func (h *Handler) createImportedJob(ctx context.Context, urlImport models.URLImport, filePath, filename string) error {
+	if h.files != nil {
+		if err := h.files.Upload(ctx, filePath); err != nil {
+			return fmt.Errorf("failed to store file: %w", err)
+		}
+	}
+
+	title := urlImport.Title
+	if title == nil {
+		name := strings.TrimSuffix(filename, filepath.Ext(filename))
+		title = &name
+	}
+	job := models.TranscriptionJob{
+		ID:          urlImport.ID,
+		UserID:      urlImport.UserID,
+		AudioPath:   filePath,
+		Title:       title,
+		Status:      models.StatusUploaded,
+		ContentType: urlImport.ContentType,
+		Priority:    urlImport.Priority,
+		Source:      urlImport.Source,
+	}
+	if job.Source == nil {
+		job.Source = &models.MediaSource{URL: urlImport.URL}
+	}
+	job.Parameters.InitialPrompt = urlImport.InitialPrompt
+	// A template deleted since the import started is left out
+	var template *models.JobTemplate
+	if urlImport.JobTemplateID != nil {
+		var found models.JobTemplate
+		if err := database.DB.Where("id = ?", *urlImport.JobTemplateID).First(&found).Error; err == nil {
+			template = &found
+			job.JobTemplateID = &found.ID
+			job.WebhookURLs = found.WebhookURLs
+		}
+	}
+	if err := database.DB.Create(&job).Error; err != nil {
+		h.removeStoredFile(filePath)
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+	if template != nil && len(template.Tags) > 0 {
+		if _, err := tagging.SetJobTags(&job, template.Tags); err != nil {
+			logger.ErrorContext(ctx, "Failed to tag job with template", "job_id", job.ID, "template", template.Name, "error", err)
+		}
+	}
+	if err := database.DB.Model(&models.URLImport{}).Where("id = ?", urlImport.ID).Updates(map[string]interface{}{
+		"status":           models.URLImportCompleted,
+		"transcription_id": job.ID,
+	}).Error; err != nil {
+		logger.ErrorContext(ctx, "Failed to record the transcription of URL import", "import_id", urlImport.ID, "job_id", job.ID, "error", err)
+	}
+
+	jobPrompt := ""
+	if urlImport.InitialPrompt != nil {
+		jobPrompt = *urlImport.InitialPrompt
+	}
+	h.autoTranscribe(urlImport.UserID, &job, jobPrompt, template)
+
+	// Started after the job is saved for the last time so the report isn't overwritten
+	go checkAudioQuality(job.ID, filePath)
+	return nil
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"scriberr/internal/llmstats"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dailyUsageWindow is the longest window grouped by day unless group_by says otherwise
+const dailyUsageWindow = 92 * 24 * time.Hour
+
+// GetUsage totals the token usage and estimated cost of LLM and embedding calls
+// @Summary Get token usage and cost
+// @Description Total the prompt and completion tokens and the estimated cost in USD of every recorded LLM and embedding call (summaries, chat, transcript chat and embeddings), overall and per day or month, each broken down by feature and by provider/model. Tokens are estimated from the text when a provider doesn't report them, and cost is estimated from built-in list prices and LLM_PRICING; local providers cost nothing.
+// @Tags usage
+// @Produce json
+// @Param since query string false "Start of the window, as a duration before until (e.g. 720h) or an RFC 3339 time (default 30 days)"
+// @Param until query string false "End of the window, RFC 3339 (default now)"
+// @Param group_by query string false "Period of the breakdown, day or month (default day for windows up to 92 days, month otherwise)"
+// @Success 200 {object} llmstats.UsageReport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/usage [get]
+func (h *Handler) GetUsage(c *gin.Context) {
+	since, until, ok := statsWindow(c, 30*24*time.Hour)
+	if !ok {
+		return
+	}
+
+	groupBy := c.Query("group_by")
+	if groupBy == "" {
+		groupBy = llmstats.GroupByDay
+		if until.Sub(since) > dailyUsageWindow {
+			groupBy = llmstats.GroupByMonth
+		}
+	}
+
+	report, err := llmstats.Usage(llmstats.UsageQuery{Since: since, Until: until, GroupBy: groupBy})
+	if err != nil {
+		if errors.Is(err, llmstats.ErrInvalidQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
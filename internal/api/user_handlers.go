@@ -0,0 +1,417 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/auth"
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateUserRequest creates an account
+type CreateUserRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=50"`
+	Password string `json:"password" binding:"required,min=6"`
+	Role     string `json:"role"` // admin, member or viewer; member by default
+}
+
+// UpdateUserRequest changes an account; fields left out are kept
+type UpdateUserRequest struct {
+	Username *string `json:"username,omitempty" binding:"omitempty,min=3,max=50"`
+	Password *string `json:"password,omitempty" binding:"omitempty,min=6"`
+	Role     *string `json:"role,omitempty"`
+	// Limits replacing the instance defaults for this user: 0 is unlimited, and a negative
+	// value goes back to the default
+	RateLimitPerMinute *int `json:"rate_limit_per_minute,omitempty"`
+	QuotaAudioMinutes  *int `json:"quota_audio_minutes,omitempty"`
+	QuotaLLMCalls      *int `json:"quota_llm_calls,omitempty"`
+}
+
+// UserSummary is an account as admins see it
+type UserSummary struct {
+	models.User
+	TranscriptionCount int64 `json:"transcription_count"`
+}
+
+// Tables whose rows belong to a user only as credentials; they're deleted with the user
+// instead of handed over
+var userCredentialTables = map[string]bool{"users": true, "api_keys": true, "refresh_tokens": true}
+
+// isAdmin reports whether the caller has the admin role
+func isAdmin(c *gin.Context) bool {
+	return c.GetString("role") == models.RoleAdmin
+}
+
+// Queries returning the owner of the transcription a route's record belongs to
+const (
+	jobOwnerQuery         = "SELECT user_id FROM transcription_jobs WHERE id = ?"
+	chatSessionOwnerQuery = "SELECT transcription_jobs.user_id FROM chat_sessions JOIN transcription_jobs ON transcription_jobs.id = chat_sessions.transcription_id WHERE chat_sessions.id = ?"
+	noteOwnerQuery        = "SELECT transcription_jobs.user_id FROM notes JOIN transcription_jobs ON transcription_jobs.id = notes.transcription_id WHERE notes.id = ?"
+)
+
+// canAccessJob reports whether the caller may open a transcription owned by ownerID.
+// Admins may open every transcription, other users only their own.
+func canAccessJob(c *gin.Context, ownerID *uint) bool {
+	return isAdmin(c) || ownsJob(currentUserID(c), ownerID)
+}
+
+// ownsJob reports whether userID owns a transcription owned by ownerID
+func ownsJob(userID, ownerID *uint) bool {
+	return userID != nil && ownerID != nil && *userID == *ownerID
+}
+
+// requireJobAccess answers 404 for routes on another user's transcription, as if it didn't
+// exist. ownerQuery looks up the transcription's owner from the route parameter param; routes
+// whose parameter matches no record are left to their handler.
+func requireJobAccess(param, ownerQuery string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param(param)
+		if id == "" || isAdmin(c) {
+			c.Next()
+			return
+		}
+		var owners []struct{ UserID *uint }
+		if err := database.DB.Raw(ownerQuery, id).Scan(&owners).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transcription"})
+			c.Abort()
+			return
+		}
+		if len(owners) > 0 && !canAccessJob(c, owners[0].UserID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transcription not found"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// checkJobAccess answers 404 unless the transcription with the given ID exists and the caller
+// may open it, for handlers that take the ID from the request body, out of requireJobAccess's reach
+func checkJobAccess(c *gin.Context, id string) bool {
+	var owners []struct{ UserID *uint }
+	if err := database.DB.Raw(jobOwnerQuery, id).Scan(&owners).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transcription"})
+		return false
+	}
+	if len(owners) == 0 || !canAccessJob(c, owners[0].UserID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcription not found"})
+		return false
+	}
+	return true
+}
+
+// loadUser loads the account of the id route parameter, writing an error response if it can't
+func loadUser(c *gin.Context) (*models.User, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return nil, false
+	}
+	var user models.User
+	if err := database.DB.First(&user, uint(id)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		}
+		return nil, false
+	}
+	return &user, true
+}
+
+// checkUsername writes an error response if another account has the username
+func checkUsername(c *gin.Context, username string, excludeID uint) bool {
+	var count int64
+	if err := database.DB.Model(&models.User{}).Where("username = ? AND id != ?", username, excludeID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check username"})
+		return false
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
+		return false
+	}
+	return true
+}
+
+// isLastAdmin reports whether user is the only admin left
+func isLastAdmin(user *models.User) (bool, error) {
+	if user.Role != models.RoleAdmin {
+		return false, nil
+	}
+	var count int64
+	if err := database.DB.Model(&models.User{}).Where("role = ?", models.RoleAdmin).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count <= 1, nil
+}
+
+// userOwnedTables lists the tables with a user_id column, whose rows belong to a user
+func userOwnedTables(tx *gorm.DB) ([]string, error) {
+	var tables []string
+	err := tx.Raw(`SELECT m.name FROM sqlite_master m JOIN pragma_table_info(m.name) p
+		WHERE m.type = 'table' AND p.name = 'user_id' ORDER BY m.name`).Scan(&tables).Error
+	return tables, err
+}
+
+// ListUsers lists the accounts
+// @Summary List users
+// @Description List every account with its role and how many transcriptions it owns. Admins only.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/users [get]
+func (h *Handler) ListUsers(c *gin.Context) {
+	var users []models.User
+	if err := database.DB.Order("id").Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list users"})
+		return
+	}
+	var counts []struct {
+		UserID uint
+		Count  int64
+	}
+	if err := database.DB.Model(&models.TranscriptionJob{}).Select("user_id, COUNT(*) AS count").
+		Where("user_id IS NOT NULL AND id NOT LIKE 'track_%'").Group("user_id").Scan(&counts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count transcriptions"})
+		return
+	}
+	byUser := map[uint]int64{}
+	for _, count := range counts {
+		byUser[count.UserID] = count.Count
+	}
+	summaries := make([]UserSummary, 0, len(users))
+	for _, user := range users {
+		summaries = append(summaries, UserSummary{User: user, TranscriptionCount: byUser[user.ID]})
+	}
+	c.JSON(http.StatusOK, gin.H{"users": summaries})
+}
+
+// CreateUser creates an account
+// @Summary Create a user
+// @Description Create an account for a team member. Members work with their own transcriptions, viewers can only read theirs, and admins manage accounts and the instance and can open every transcription. Admins only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body CreateUserRequest true "Account"
+// @Success 201 {object} models.User
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/users [post]
+func (h *Handler) CreateUser(c *gin.Context) {
+	var req CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	if req.Role == "" {
+		req.Role = models.RoleMember
+	}
+	if !models.IsValidRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be admin, member or viewer"})
+		return
+	}
+	req.Username = strings.TrimSpace(req.Username)
+	if !checkUsername(c, req.Username, 0) {
+		return
+	}
+	hashedPassword, err := auth.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process password"})
+		return
+	}
+	user := models.User{Username: req.Username, Password: hashedPassword, Role: req.Role}
+	if err := database.DB.Create(&user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+		return
+	}
+	logger.Info("User created", "user_id", user.ID, "role", user.Role)
+	c.JSON(http.StatusCreated, user)
+}
+
+// UpdateUser changes an account's username, password, role or limits
+// @Summary Update a user
+// @Description Rename an account, reset its password, change its role, or set its own rate limit and monthly quotas in place of the instance defaults (0 is unlimited, a negative value goes back to the default). The last admin can't be demoted. Admins only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body UpdateUserRequest true "Changes"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/users/{id} [put]
+func (h *Handler) UpdateUser(c *gin.Context) {
+	user, ok := loadUser(c)
+	if !ok {
+		return
+	}
+	var req UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.Username != nil {
+		username := strings.TrimSpace(*req.Username)
+		if !checkUsername(c, username, user.ID) {
+			return
+		}
+		updates["username"] = username
+	}
+	if req.Password != nil {
+		hashedPassword, err := auth.HashPassword(*req.Password)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process password"})
+			return
+		}
+		updates["password"] = hashedPassword
+	}
+	if req.Role != nil && *req.Role != user.Role {
+		if !models.IsValidRole(*req.Role) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "role must be admin, member or viewer"})
+			return
+		}
+		last, err := isLastAdmin(user)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count admins"})
+			return
+		}
+		if last {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The last admin can't be demoted"})
+			return
+		}
+		updates["role"] = *req.Role
+	}
+	limits := map[string]*int{
+		"rate_limit_per_minute": req.RateLimitPerMinute,
+		"quota_audio_minutes":   req.QuotaAudioMinutes,
+		"quota_llm_calls":       req.QuotaLLMCalls,
+	}
+	for column, limit := range limits {
+		if limit == nil {
+			continue
+		}
+		if *limit < 0 {
+			updates[column] = nil
+		} else {
+			updates[column] = *limit
+		}
+	}
+	if len(updates) > 0 {
+		if err := database.DB.Model(user).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// DeleteUser deletes an account and hands its data to another
+// @Summary Delete a user
+// @Description Delete an account and its API keys and sessions. Its transcriptions, templates, projects and other data are handed over to the transfer_to account, by default the caller's. Run POST /rag/audit with repair=true afterwards to move the transferred transcriptions into their new owner's RAG collection. Admins can't delete themselves or the last admin. Admins only.
+// @Tags admin
+// @Produce json
+// @Param id path int true "User ID"
+// @Param transfer_to query int false "Account receiving the user's data (default: the caller)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/users/{id} [delete]
+func (h *Handler) DeleteUser(c *gin.Context) {
+	user, ok := loadUser(c)
+	if !ok {
+		return
+	}
+	callerID := currentUserID(c)
+	if callerID != nil && *callerID == user.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You can't delete your own account"})
+		return
+	}
+	last, err := isLastAdmin(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count admins"})
+		return
+	}
+	if last {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The last admin can't be deleted"})
+		return
+	}
+
+	// Data goes to the transfer_to account, or the caller's; an API key without an owner
+	// leaves it without an owner
+	recipient := callerID
+	if value := c.Query("transfer_to"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || uint(id) == user.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "transfer_to must be another user's ID"})
+			return
+		}
+		var count int64
+		if err := database.DB.Model(&models.User{}).Where("id = ?", id).Count(&count).Error; err != nil || count == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "transfer_to must be another user's ID"})
+			return
+		}
+		to := uint(id)
+		recipient = &to
+	}
+
+	transferred := map[string]int64{}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		tables, err := userOwnedTables(tx)
+		if err != nil {
+			return err
+		}
+		for _, table := range tables {
+			if userCredentialTables[table] {
+				continue
+			}
+			result := tx.Table(table).Where("user_id = ?", user.ID).Update("user_id", recipient)
+			if result.Error != nil {
+				return fmt.Errorf("failed to transfer %s: %w", table, result.Error)
+			}
+			if result.RowsAffected > 0 {
+				transferred[table] = result.RowsAffected
+			}
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.APIKey{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.RefreshToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(user).Error
+	})
+	if err != nil {
+		logger.Error("Failed to delete user", "user_id", user.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
+		return
+	}
+	logger.Info("User deleted", "user_id", user.ID, "transferred", transferred)
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "User deleted successfully",
+		"transfer_to": recipient,
+		"transferred": transferred,
+	})
+}
@@ -0,0 +1,248 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/versions"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RestoreVersionResponse is the version a rollback recorded
+type RestoreVersionResponse struct {
+	Version   models.TranscriptVersion `json:"version"`
+	Reindexed bool                     `json:"reindexed"`
+}
+
+// versionKind reads the kind path parameter, writing a 400 response if it isn't one versions
+// are kept of
+func versionKind(c *gin.Context) (string, bool) {
+	kind := c.Param("kind")
+	if !versions.ValidKind(kind) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": versions.ErrUnknownKind.Error()})
+		return "", false
+	}
+	return kind, true
+}
+
+// versionNumber reads a version number, writing a 400 response if it isn't a positive integer
+func versionNumber(c *gin.Context, name, raw string) (int, bool) {
+	number, err := strconv.Atoi(raw)
+	if err != nil || number < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be a positive version number"})
+		return 0, false
+	}
+	return number, true
+}
+
+// writeVersionError writes the response for an error looking up a version
+func writeVersionError(c *gin.Context, err error) {
+	if errors.Is(err, versions.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Version not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get version"})
+}
+
+// ListTranscriptVersions returns the versions kept of a transcript or summary
+// @Summary List transcript or summary versions
+// @Description List the versions kept of a transcription's transcript or summary, newest first, without their content. A version is kept each time either is transcribed, edited, regenerated or rolled back; the newest is the current one.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param kind path string true "transcript or summary"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/versions/{kind} [get]
+func (h *Handler) ListTranscriptVersions(c *gin.Context) {
+	kind, ok := versionKind(c)
+	if !ok {
+		return
+	}
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	list, err := versions.List(database.DB, job.ID, kind)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list versions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"transcription_id": job.ID, "kind": kind, "versions": list})
+}
+
+// GetTranscriptVersion returns one version of a transcript or summary with its content
+// @Summary Get a transcript or summary version
+// @Description Get one version of a transcription's transcript or summary, with its content: the transcript JSON (or plain text), or the summary's Markdown and structured fields
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param kind path string true "transcript or summary"
+// @Param version path int true "Version number"
+// @Success 200 {object} models.TranscriptVersion
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/versions/{kind}/{version} [get]
+func (h *Handler) GetTranscriptVersion(c *gin.Context) {
+	kind, ok := versionKind(c)
+	if !ok {
+		return
+	}
+	number, ok := versionNumber(c, "version", c.Param("version"))
+	if !ok {
+		return
+	}
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	version, err := versions.Get(database.DB, job.ID, kind, number)
+	if err != nil {
+		writeVersionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, version)
+}
+
+// DiffTranscriptVersions compares two versions of a transcript or summary
+// @Summary Diff two transcript or summary versions
+// @Description Compare two versions line by line, listing every line as equal, inserted or deleted. Transcript lines are segments, read as "[mm:ss] Speaker: text"; summary lines are lines of its Markdown. Without to, the current version is compared; without from, the one before to.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param kind path string true "transcript or summary"
+// @Param from query int false "Earlier version (default the one before to)"
+// @Param to query int false "Later version (default the current one)"
+// @Success 200 {object} versions.Diff
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/versions/{kind}/diff [get]
+func (h *Handler) DiffTranscriptVersions(c *gin.Context) {
+	kind, ok := versionKind(c)
+	if !ok {
+		return
+	}
+	var from, to int
+	if raw := c.Query("from"); raw != "" {
+		if from, ok = versionNumber(c, "from", raw); !ok {
+			return
+		}
+	}
+	if raw := c.Query("to"); raw != "" {
+		if to, ok = versionNumber(c, "to", raw); !ok {
+			return
+		}
+	}
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+
+	var later *models.TranscriptVersion
+	var err error
+	if to == 0 {
+		later, err = versions.Latest(database.DB, job.ID, kind)
+	} else {
+		later, err = versions.Get(database.DB, job.ID, kind, to)
+	}
+	if err != nil {
+		writeVersionError(c, err)
+		return
+	}
+	if from == 0 {
+		from = later.Version - 1
+		if from < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "There is no earlier version to compare with"})
+			return
+		}
+	}
+	earlier, err := versions.Get(database.DB, job.ID, kind, from)
+	if err != nil {
+		writeVersionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, versions.Compare(earlier, later))
+}
+
+// RestoreTranscriptVersion rolls a transcript or summary back to an earlier version
+// @Summary Roll back to a transcript or summary version
+// @Description Make an earlier version of a transcription's transcript or summary current again. The restored content is kept as a new version, so the versions after it stay available. If the transcription is in the RAG index, it is re-indexed with the restored content.
+// @Tags transcription
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param kind path string true "transcript or summary"
+// @Param version path int true "Version number to restore"
+// @Success 200 {object} RestoreVersionResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/versions/{kind}/{version}/restore [post]
+func (h *Handler) RestoreTranscriptVersion(c *gin.Context) {
+	kind, ok := versionKind(c)
+	if !ok {
+		return
+	}
+	number, ok := versionNumber(c, "version", c.Param("version"))
+	if !ok {
+		return
+	}
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	if rejectIfOnHold(c, job) {
+		return
+	}
+	// A transcription in progress would overwrite the restored transcript
+	if kind == models.VersionKindTranscript && job.Status != models.StatusCompleted {
+		c.JSON(http.StatusConflict, gin.H{"error": "Transcription is not completed, current status: " + string(job.Status)})
+		return
+	}
+
+	var restored *models.TranscriptVersion
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		restored, err = versions.Restore(tx, job.ID, kind, number, currentUserID(c))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, versions.ErrCurrent) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Version is already current"})
+			return
+		}
+		if errors.Is(err, versions.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Version not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore version"})
+		return
+	}
+	logger.InfoContext(c.Request.Context(), "Restored version", "job_id", job.ID, "kind", kind, "version", number, "restored_as", restored.Version)
+
+	// RAG answers should come from the current version
+	reindexed, err := h.reindexIfIndexed(job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Version restored, but failed to re-index it: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, RestoreVersionResponse{Version: *restored, Reindexed: reindexed})
+}
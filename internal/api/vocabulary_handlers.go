@@ -0,0 +1,407 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/revisions"
+	"scriberr/internal/vocabulary"
+	"scriberr/internal/workflow"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Vocabulary limits
+const (
+	maxVocabularyTerms      = 500 // In a workspace vocabulary, or a transcription's own
+	maxVocabularyTermLength = 255
+	maxVocabularyHints      = 20
+)
+
+// VocabularyTermRequest represents a request to add or change a vocabulary term
+type VocabularyTermRequest struct {
+	Term string `json:"term" binding:"required"`
+	Kind string `json:"kind"` // term (the default), acronym or proper_noun
+	// SoundsLike lists how the term tends to be misheard; transcripts have these replaced by it
+	SoundsLike []string `json:"sounds_like"`
+}
+
+// bindVocabularyTermRequest parses and validates a vocabulary term request. Spaces in the
+// term and its hints are collapsed and duplicate hints, ignoring case, dropped.
+func bindVocabularyTermRequest(c *gin.Context) (*VocabularyTermRequest, bool) {
+	var req VocabularyTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	req.Term = strings.Join(strings.Fields(req.Term), " ")
+	if req.Term == "" || len(req.Term) > maxVocabularyTermLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("term must be 1 to %d characters", maxVocabularyTermLength)})
+		return nil, false
+	}
+	if req.Kind == "" {
+		req.Kind = models.VocabularyKindTerm
+	}
+	if !models.IsVocabularyKind(req.Kind) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be term, acronym or proper_noun"})
+		return nil, false
+	}
+
+	seen := map[string]bool{strings.ToLower(req.Term): true}
+	var hints []string
+	for _, hint := range req.SoundsLike {
+		hint = strings.Join(strings.Fields(hint), " ")
+		if hint == "" || seen[strings.ToLower(hint)] {
+			continue
+		}
+		if len(hint) > maxVocabularyTermLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("sounds_like entries must be at most %d characters", maxVocabularyTermLength)})
+			return nil, false
+		}
+		seen[strings.ToLower(hint)] = true
+		hints = append(hints, hint)
+	}
+	if len(hints) > maxVocabularyHints {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("a term can have at most %d sounds_like entries", maxVocabularyHints)})
+		return nil, false
+	}
+	req.SoundsLike = hints
+	return &req, true
+}
+
+// vocabularyFromForm reads the comma or newline separated terms of an upload's vocabulary
+// field, writing an error response if they aren't valid
+func vocabularyFromForm(c *gin.Context) ([]string, bool) {
+	seen := map[string]bool{}
+	var terms []string
+	for _, term := range strings.Split(joinPromptTerms(c.PostForm("vocabulary")), ", ") {
+		if term == "" || seen[strings.ToLower(term)] {
+			continue
+		}
+		if len(term) > maxVocabularyTermLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("vocabulary terms must be at most %d characters", maxVocabularyTermLength)})
+			return nil, false
+		}
+		seen[strings.ToLower(term)] = true
+		terms = append(terms, term)
+	}
+	if len(terms) > maxVocabularyTerms {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("a vocabulary can have at most %d terms", maxVocabularyTerms)})
+		return nil, false
+	}
+	return terms, true
+}
+
+// saveJobVocabulary adds terms given with an upload to the new job's vocabulary
+func saveJobVocabulary(job *models.TranscriptionJob, terms []string) error {
+	if len(terms) == 0 {
+		return nil
+	}
+	rows := make([]models.VocabularyTerm, len(terms))
+	for i, term := range terms {
+		rows[i] = models.VocabularyTerm{UserID: job.UserID, TranscriptionID: &job.ID, Term: term, Kind: models.VocabularyKindTerm}
+	}
+	return database.DB.Create(&rows).Error
+}
+
+// workspaceVocabulary scopes a query to the caller's workspace vocabulary
+func workspaceVocabulary(c *gin.Context) *gorm.DB {
+	return scopeToOwner(database.DB, currentUserID(c)).Where("transcription_id IS NULL")
+}
+
+// loadVocabularyTerm loads a term of the caller's workspace vocabulary, writing an error
+// response if it can't
+func loadVocabularyTerm(c *gin.Context) (*models.VocabularyTerm, bool) {
+	var term models.VocabularyTerm
+	if err := workspaceVocabulary(c).Where("id = ?", c.Param("id")).First(&term).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Vocabulary term not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get vocabulary term"})
+		}
+		return nil, false
+	}
+	return &term, true
+}
+
+// saveVocabularyTerm creates or updates a term after checking that its vocabulary, the terms
+// matching scope, doesn't have it yet and has room for it
+func saveVocabularyTerm(c *gin.Context, scope *gorm.DB, term *models.VocabularyTerm) {
+	var existing []models.VocabularyTerm
+	if err := scope.Select("id", "term").Find(&existing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save vocabulary term"})
+		return
+	}
+	for _, other := range existing {
+		if other.ID != term.ID && strings.EqualFold(other.Term, term.Term) {
+			c.JSON(http.StatusConflict, gin.H{"error": "The vocabulary already has this term"})
+			return
+		}
+	}
+	if term.ID == "" && len(existing) >= maxVocabularyTerms {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("a vocabulary can have at most %d terms", maxVocabularyTerms)})
+		return
+	}
+
+	status := http.StatusOK
+	if term.ID == "" {
+		status = http.StatusCreated
+	}
+	if err := database.DB.Save(term).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save vocabulary term"})
+		return
+	}
+	c.JSON(status, term)
+}
+
+// ListVocabulary returns the caller's workspace vocabulary
+// @Summary List the workspace vocabulary
+// @Description List the terms of the caller's workspace vocabulary, which apply to all of their transcriptions, in alphabetical order
+// @Tags vocabulary
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/vocabulary [get]
+func (h *Handler) ListVocabulary(c *gin.Context) {
+	terms := []models.VocabularyTerm{}
+	if err := workspaceVocabulary(c).Order("LOWER(term) ASC").Find(&terms).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list vocabulary"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"terms": terms})
+}
+
+// CreateVocabularyTerm adds a term to the caller's workspace vocabulary
+// @Summary Add a workspace vocabulary term
+// @Description Add a word or phrase, such as jargon, an acronym or a name, that the caller's transcriptions should spell a particular way. Terms are passed to the transcription engine as hotwords and in the initial prompt, and the correct_vocabulary workflow step corrects transcripts to them, replacing the sounds_like entries outright.
+// @Tags vocabulary
+// @Accept json
+// @Produce json
+// @Param request body VocabularyTermRequest true "Vocabulary term"
+// @Success 201 {object} models.VocabularyTerm
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/vocabulary [post]
+func (h *Handler) CreateVocabularyTerm(c *gin.Context) {
+	req, ok := bindVocabularyTermRequest(c)
+	if !ok {
+		return
+	}
+	term := models.VocabularyTerm{UserID: currentUserID(c), Term: req.Term, Kind: req.Kind, SoundsLike: req.SoundsLike}
+	saveVocabularyTerm(c, workspaceVocabulary(c), &term)
+}
+
+// UpdateVocabularyTerm replaces a workspace vocabulary term
+// @Summary Update a workspace vocabulary term
+// @Tags vocabulary
+// @Accept json
+// @Produce json
+// @Param id path string true "Vocabulary term ID"
+// @Param request body VocabularyTermRequest true "Vocabulary term"
+// @Success 200 {object} models.VocabularyTerm
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/vocabulary/{id} [put]
+func (h *Handler) UpdateVocabularyTerm(c *gin.Context) {
+	term, ok := loadVocabularyTerm(c)
+	if !ok {
+		return
+	}
+	req, ok := bindVocabularyTermRequest(c)
+	if !ok {
+		return
+	}
+	term.Term = req.Term
+	term.Kind = req.Kind
+	term.SoundsLike = req.SoundsLike
+	saveVocabularyTerm(c, workspaceVocabulary(c), term)
+}
+
+// DeleteVocabularyTerm removes a term from the caller's workspace vocabulary
+// @Summary Delete a workspace vocabulary term
+// @Description Remove a term from the workspace vocabulary. Transcripts already corrected to it stay as they are.
+// @Tags vocabulary
+// @Param id path string true "Vocabulary term ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/vocabulary/{id} [delete]
+func (h *Handler) DeleteVocabularyTerm(c *gin.Context) {
+	term, ok := loadVocabularyTerm(c)
+	if !ok {
+		return
+	}
+	if err := database.DB.Delete(term).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete vocabulary term"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Vocabulary term deleted"})
+}
+
+// ListTranscriptionVocabulary returns the vocabulary a transcription is transcribed and
+// corrected with
+// @Summary List a transcription's vocabulary
+// @Description List the vocabulary a transcription is transcribed and corrected with: its own terms, which have its transcription_id, then those of its owner's workspace vocabulary
+// @Tags vocabulary
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/vocabulary [get]
+func (h *Handler) ListTranscriptionVocabulary(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	terms, err := vocabulary.ForJob(job)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list vocabulary"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"terms": terms})
+}
+
+// CreateTranscriptionVocabularyTerm adds a term to one transcription's vocabulary
+// @Summary Add a transcription vocabulary term
+// @Description Add a term for one transcription only, on top of its owner's workspace vocabulary. It is used the next time the transcription is transcribed or corrected.
+// @Tags vocabulary
+// @Accept json
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param request body VocabularyTermRequest true "Vocabulary term"
+// @Success 201 {object} models.VocabularyTerm
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/vocabulary [post]
+func (h *Handler) CreateTranscriptionVocabularyTerm(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	req, ok := bindVocabularyTermRequest(c)
+	if !ok {
+		return
+	}
+	term := models.VocabularyTerm{UserID: job.UserID, TranscriptionID: &job.ID, Term: req.Term, Kind: req.Kind, SoundsLike: req.SoundsLike}
+	saveVocabularyTerm(c, database.DB.Where("transcription_id = ?", job.ID), &term)
+}
+
+// DeleteTranscriptionVocabularyTerm removes a term from one transcription's vocabulary
+// @Summary Delete a transcription vocabulary term
+// @Tags vocabulary
+// @Param id path string true "Transcription ID"
+// @Param termId path string true "Vocabulary term ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/vocabulary/{termId} [delete]
+func (h *Handler) DeleteTranscriptionVocabularyTerm(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	result := database.DB.Where("id = ? AND transcription_id = ?", c.Param("termId"), job.ID).Delete(&models.VocabularyTerm{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete vocabulary term"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Vocabulary term not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Vocabulary term deleted"})
+}
+
+// CorrectTranscriptionVocabulary corrects a transcript to its vocabulary on demand
+// @Summary Correct a transcript to its vocabulary
+// @Description Correct a completed transcript to the spelling of its vocabulary terms, as the correct_vocabulary workflow step does: known mishearings (sounds_like) are replaced, then the summary LLM, if one is configured, fixes the misspellings left. Each corrected segment is kept as a revision. An indexed transcription is re-indexed.
+// @Tags vocabulary
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/correct-vocabulary [post]
+func (h *Handler) CorrectTranscriptionVocabulary(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	if rejectIfOnHold(c, job) {
+		return
+	}
+	if job.Status != models.StatusCompleted {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Transcription is not completed"})
+		return
+	}
+
+	// Without an LLM, only the known mishearings are corrected
+	var service workflow.LLMService
+	var model string
+	if h.llmRegistry != nil {
+		if s, m, err := h.llmRegistry.For(llm.FeatureSummary); err == nil {
+			service, model = s, m
+		}
+	}
+
+	saved, hinted, err := workflow.CorrectVocabulary(c.Request.Context(), service, model, job)
+	switch {
+	case errors.Is(err, workflow.ErrNoVocabulary):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The transcription has no vocabulary"})
+		return
+	case errors.Is(err, revisions.ErrNoSegments):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The transcript is plain text and has no segments to correct"})
+		return
+	case err != nil:
+		logger.ErrorContext(c.Request.Context(), "Failed to correct vocabulary", "job_id", job.ID, "model", model, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to correct transcript: " + err.Error()})
+		return
+	}
+
+	reindexed := false
+	if len(saved) > 0 && h.ragService != nil {
+		indexed, err := h.ragService.IsIndexed(job.ID)
+		if err == nil && indexed {
+			if err = h.storeJobInRAG(job); err == nil {
+				reindexed = true
+			}
+		}
+		if err != nil {
+			logger.ErrorContext(c.Request.Context(), "Failed to re-index corrected transcription", "job_id", job.ID, "error", err)
+		}
+	}
+	if saved == nil {
+		saved = []models.TranscriptRevision{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"revisions": saved,
+		"replaced":  hinted,
+		"reindexed": reindexed,
+	})
+}
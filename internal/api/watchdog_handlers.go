@@ -0,0 +1,97 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetWatchdog sets the stuck job watchdog
+func (h *Handler) SetWatchdog(watchdog *queue.Watchdog) {
+	h.watchdog = watchdog
+}
+
+// RunningJob is a transcription being processed, with its worker's last heartbeat
+type RunningJob struct {
+	ID                  string     `json:"id"`
+	Title               *string    `json:"title,omitempty"`
+	WorkerID            *string    `json:"worker_id,omitempty"`
+	ProcessingStartedAt *time.Time `json:"processing_started_at,omitempty"`
+	HeartbeatAt         *time.Time `json:"heartbeat_at,omitempty"`
+	StuckRequeues       int        `json:"stuck_requeues"`
+	Local               bool       `json:"local"` // Running on the node that answered
+}
+
+// ListRunningJobs lists the transcriptions being processed and their workers' heartbeats
+// @Summary List running transcriptions
+// @Description List the transcriptions workers are processing, on any node, with each worker's last heartbeat, along with how long a job may run on its node before the watchdog takes it for hung (0 when not checked)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/queue/running [get]
+func (h *Handler) ListRunningJobs(c *gin.Context) {
+	var jobs []models.TranscriptionJob
+	if err := database.DB.Select("id", "title", "worker_id", "processing_started_at", "heartbeat_at", "stuck_requeues").
+		Where("status = ? AND worker_id IS NOT NULL", models.StatusProcessing).
+		Order("processing_started_at ASC").Find(&jobs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list running transcriptions"})
+		return
+	}
+	running := make([]RunningJob, len(jobs))
+	for i, job := range jobs {
+		running[i] = RunningJob{
+			ID:                  job.ID,
+			Title:               job.Title,
+			WorkerID:            job.WorkerID,
+			ProcessingStartedAt: job.ProcessingStartedAt,
+			HeartbeatAt:         job.HeartbeatAt,
+			StuckRequeues:       job.StuckRequeues,
+			Local:               h.taskQueue != nil && h.taskQueue.IsJobRunning(job.ID),
+		}
+	}
+
+	response := gin.H{"jobs": running}
+	if h.taskQueue != nil {
+		response["node_id"] = h.taskQueue.NodeID()
+	}
+	if h.watchdog != nil {
+		limit, err := h.watchdog.DurationLimit()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to estimate the expected duration"})
+			return
+		}
+		response["stuck_after_seconds"] = int(limit.Seconds())
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// CheckStuckJobs runs the stuck job watchdog now
+// @Summary Check for stuck transcriptions
+// @Description Look for transcriptions stuck in processing right away, rather than at the watchdog's next check, and requeue or fail them. Returns the jobs it recovered.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/queue/watchdog/check [post]
+func (h *Handler) CheckStuckJobs(c *gin.Context) {
+	if h.watchdog == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Watchdog not initialized"})
+		return
+	}
+	stuck, err := h.watchdog.Check()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"recovered": stuck})
+}
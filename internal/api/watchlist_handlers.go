@@ -0,0 +1,293 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Watchlist limits
+const (
+	maxWatchlistTerms      = 200
+	maxWatchlistTermLength = 200
+)
+
+// WatchlistRequest represents a request to create or update a watchlist
+type WatchlistRequest struct {
+	Name                string   `json:"name" binding:"required"`
+	Terms               []string `json:"terms" binding:"required"`
+	Fuzzy               bool     `json:"fuzzy"`
+	Semantic            bool     `json:"semantic"`
+	SimilarityThreshold float64  `json:"similarity_threshold"` // 0 uses the default of 0.8
+	WebhookURL          string   `json:"webhook_url"`
+	Paused              bool     `json:"paused"`
+}
+
+// loadWatchlist loads a watchlist owned by the caller, writing an error response if it can't
+func loadWatchlist(c *gin.Context) (*models.Watchlist, bool) {
+	var list models.Watchlist
+	if err := scopeToOwner(database.DB, currentUserID(c)).Where("id = ?", c.Param("id")).First(&list).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Watchlist not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get watchlist"})
+		}
+		return nil, false
+	}
+	return &list, true
+}
+
+// bindWatchlistRequest parses and validates a watchlist request. Terms are trimmed and
+// duplicates, ignoring case, dropped.
+func bindWatchlistRequest(c *gin.Context) (*WatchlistRequest, bool) {
+	var req WatchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return nil, false
+	}
+
+	seen := map[string]bool{}
+	var terms []string
+	for _, term := range req.Terms {
+		term = strings.Join(strings.Fields(term), " ")
+		if term == "" || seen[strings.ToLower(term)] {
+			continue
+		}
+		if len(term) > maxWatchlistTermLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("terms must be at most %d characters", maxWatchlistTermLength)})
+			return nil, false
+		}
+		seen[strings.ToLower(term)] = true
+		terms = append(terms, term)
+	}
+	if len(terms) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "at least one term is required"})
+		return nil, false
+	}
+	if len(terms) > maxWatchlistTerms {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("a watchlist can have at most %d terms", maxWatchlistTerms)})
+		return nil, false
+	}
+	req.Terms = terms
+
+	if req.SimilarityThreshold < 0 || req.SimilarityThreshold > 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "similarity_threshold must be between 0 and 1"})
+		return nil, false
+	}
+	req.WebhookURL = strings.TrimSpace(req.WebhookURL)
+	if req.WebhookURL != "" {
+		u, err := url.Parse(req.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "webhook_url must be an http or https URL"})
+			return nil, false
+		}
+	}
+	return &req, true
+}
+
+// ListWatchlists returns the caller's watchlists
+// @Summary List watchlists
+// @Description List the caller's keyword watchlists, whose terms new transcripts are scanned for
+// @Tags watchlists
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/watchlists [get]
+func (h *Handler) ListWatchlists(c *gin.Context) {
+	lists := []models.Watchlist{}
+	if err := scopeToOwner(database.DB, currentUserID(c)).Order("name ASC").Find(&lists).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list watchlists"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"watchlists": lists})
+}
+
+// CreateWatchlist saves a new watchlist
+// @Summary Create a watchlist
+// @Description Save a list of keywords and phrases to scan new transcripts for. Terms match whole words ignoring case; with fuzzy set they also match small misspellings and words masked with asterisks, and with semantic set, segments close to them in meaning. Matches are sent to webhook_url, or to NOTIFY_WEBHOOK_URL when it is empty.
+// @Tags watchlists
+// @Accept json
+// @Produce json
+// @Param request body WatchlistRequest true "Watchlist"
+// @Success 201 {object} models.Watchlist
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/watchlists [post]
+func (h *Handler) CreateWatchlist(c *gin.Context) {
+	req, ok := bindWatchlistRequest(c)
+	if !ok {
+		return
+	}
+
+	list := models.Watchlist{
+		UserID:              currentUserID(c),
+		Name:                req.Name,
+		Terms:               req.Terms,
+		Fuzzy:               req.Fuzzy,
+		Semantic:            req.Semantic,
+		SimilarityThreshold: req.SimilarityThreshold,
+		WebhookURL:          req.WebhookURL,
+		Paused:              req.Paused,
+	}
+	if err := database.DB.Create(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create watchlist"})
+		return
+	}
+	c.JSON(http.StatusCreated, list)
+}
+
+// UpdateWatchlist replaces a watchlist's settings and terms
+// @Summary Update a watchlist
+// @Description Replace a watchlist's settings and terms. Matches already found are kept.
+// @Tags watchlists
+// @Accept json
+// @Produce json
+// @Param id path string true "Watchlist ID"
+// @Param request body WatchlistRequest true "Watchlist"
+// @Success 200 {object} models.Watchlist
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/watchlists/{id} [put]
+func (h *Handler) UpdateWatchlist(c *gin.Context) {
+	list, ok := loadWatchlist(c)
+	if !ok {
+		return
+	}
+	req, ok := bindWatchlistRequest(c)
+	if !ok {
+		return
+	}
+
+	list.Name = req.Name
+	list.Terms = req.Terms
+	list.Fuzzy = req.Fuzzy
+	list.Semantic = req.Semantic
+	list.SimilarityThreshold = req.SimilarityThreshold
+	list.WebhookURL = req.WebhookURL
+	list.Paused = req.Paused
+	if err := database.DB.Save(list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update watchlist"})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// DeleteWatchlist deletes a watchlist and its matches
+// @Summary Delete a watchlist
+// @Tags watchlists
+// @Param id path string true "Watchlist ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/watchlists/{id} [delete]
+func (h *Handler) DeleteWatchlist(c *gin.Context) {
+	list, ok := loadWatchlist(c)
+	if !ok {
+		return
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("watchlist_id = ?", list.ID).Delete(&models.WatchlistMatch{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(list).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete watchlist"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Watchlist deleted"})
+}
+
+// ListWatchlistMatches returns the matches of a watchlist across the caller's transcriptions
+// @Summary List a watchlist's matches
+// @Description List the transcript segments in which the watchlist's terms were found, newest first, with their timestamps
+// @Tags watchlists
+// @Produce json
+// @Param id path string true "Watchlist ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Matches per page" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/watchlists/{id}/matches [get]
+func (h *Handler) ListWatchlistMatches(c *gin.Context) {
+	list, ok := loadWatchlist(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 1000 {
+		limit = 50
+	}
+
+	query := database.DB.Model(&models.WatchlistMatch{}).Where("watchlist_id = ?", list.ID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count watchlist matches"})
+		return
+	}
+	matches := []models.WatchlistMatch{}
+	if err := query.Order("created_at DESC, transcription_id, start ASC").Offset((page - 1) * limit).Limit(limit).Find(&matches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list watchlist matches"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"matches": matches,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// ListTranscriptionWatchlistMatches returns the watchlist matches found in one transcription
+// @Summary List a transcription's watchlist matches
+// @Description List the segments of a transcription in which the caller's watchlist terms were found, in the order they were spoken
+// @Tags watchlists
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Success 200 {array} models.WatchlistMatch
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/watchlist-matches [get]
+func (h *Handler) ListTranscriptionWatchlistMatches(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	matches := []models.WatchlistMatch{}
+	if err := database.DB.Where("transcription_id = ?", job.ID).Order("start ASC, term ASC").Find(&matches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list watchlist matches"})
+		return
+	}
+	c.JSON(http.StatusOK, matches)
+}
@@ -0,0 +1,347 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/events"
+	"scriberr/internal/models"
+	"scriberr/internal/quota"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// Messages sent to WebSocket clients
+const (
+	socketReady     = "ready"      // The connection is authenticated and streaming
+	socketEvent     = "event"      // An event from the event log, as in GET /api/v1/events
+	socketQueue     = "queue"      // Queue positions of the caller's pending transcriptions
+	socketChatToken = "chat.token" // Part of an assistant reply
+	socketChatDone  = "chat.done"  // The assistant reply is complete and saved
+	socketError     = "error"      // A request failed; id names the request when it had one
+	socketPing      = "ping"       // Keep-alive, sent when the connection is idle
+	socketPong      = "pong"       // Answer to a client ping
+)
+
+// Requests WebSocket clients send
+const (
+	socketSubscribe = "subscribe" // Limit job updates to some transcriptions
+	socketChat      = "chat"      // Send a chat message and stream the reply
+)
+
+// SocketRequest is a message from a WebSocket client
+type SocketRequest struct {
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"` // Chosen by the client and echoed in replies
+	// TranscriptionIDs limits job updates to these transcriptions; empty follows them all
+	TranscriptionIDs []string `json:"transcription_ids,omitempty"`
+	SessionID        string   `json:"session_id,omitempty"` // Chat session of a chat request
+	Content          string   `json:"content,omitempty"`    // Message of a chat request
+}
+
+// SocketMessage is a message to a WebSocket client
+type SocketMessage struct {
+	Type      string         `json:"type"`
+	ID        string         `json:"id,omitempty"`
+	Event     *models.Event  `json:"event,omitempty"`
+	Positions map[string]int `json:"positions,omitempty"` // Omitted once nothing is queued
+	Content   string         `json:"content,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	Cursor    *uint          `json:"cursor,omitempty"`
+}
+
+// socket is one client connection; sends are serialized since chat replies stream from their
+// own goroutines
+type socket struct {
+	conn   *websocket.Conn
+	userID *uint
+	admin  bool
+	// chatRefusal is why the caller may not chat, if they may not: the chat routes are held to
+	// the same scopes and roles over HTTP, but the upgrade request is a GET any key may make
+	chatRefusal string
+
+	mu       sync.Mutex
+	lastSend time.Time
+	subjects map[string]bool // Transcriptions followed; nil follows all
+}
+
+func (s *socket) send(message SocketMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastSend = time.Now()
+	return websocket.JSON.Send(s.conn, message)
+}
+
+func (s *socket) follows(subjectID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.subjects == nil || s.subjects[subjectID]
+}
+
+func (s *socket) subscribe(ids []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(ids) == 0 {
+		s.subjects = nil
+		return
+	}
+	s.subjects = make(map[string]bool, len(ids))
+	for _, id := range ids {
+		s.subjects[id] = true
+	}
+}
+
+func (s *socket) idleFor() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Since(s.lastSend)
+}
+
+// WebSocket multiplexes job updates, queue positions and chat replies over one connection
+// @Summary Open a WebSocket for live updates
+// @Description Upgrade to a WebSocket carrying JSON messages. The server sends ready once connected, then event messages for the caller's events as in GET /api/v1/events, queue messages mapping each of the caller's pending transcriptions to its place in the queue (1 is next) whenever positions change, and ping when idle. Clients send {"type":"subscribe","transcription_ids":[...]} to follow only some transcriptions (empty follows all), {"type":"chat","id":"...","session_id":"...","content":"..."} to send a chat message and receive the reply as chat.token messages followed by chat.done, and {"type":"ping"}. Chat is limited to the caller's own sessions, counts against the LLM request quota, and is refused to viewers and to API keys without the rag_chat scope. Failed requests are answered with an error message carrying the request's id. Browsers can't set headers on the upgrade request, so the JWT or API key may be passed as the token parameter instead.
+// @Tags events
+// @Param token query string false "JWT or API key, when no auth header can be sent"
+// @Param cursor query int false "Sequence of the last event already processed (default: send new events only)"
+// @Param types query string false "Comma-separated event types to send"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/ws [get]
+func (h *Handler) WebSocket(c *gin.Context) {
+	cursor, given, ok := streamCursor(c)
+	if !ok {
+		return
+	}
+	if !given {
+		var err error
+		if cursor, err = latestEventSequence(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read the event log"})
+			return
+		}
+	}
+	userID := currentUserID(c)
+	types := eventTypes(c)
+	s := &socket{userID: userID, admin: isAdmin(c)}
+	switch {
+	case !apiKeyAllows(c, models.APIKeyScopeRAGChat):
+		s.chatRefusal = "This API key's scopes don't allow chat"
+	case c.GetString("role") == models.RoleViewer:
+		s.chatRefusal = "Viewers have read-only access"
+	}
+
+	server := websocket.Server{
+		// Clients authenticate with a token rather than cookies, so any origin may connect
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(conn *websocket.Conn) {
+			s.conn = conn
+			h.serveSocket(s, cursor, types)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// serveSocket pushes updates to a client and answers its requests until it disconnects
+func (h *Handler) serveSocket(s *socket, cursor uint, types []string) {
+	ctx, cancel := context.WithCancel(s.conn.Request().Context())
+	defer cancel()
+
+	if err := s.send(SocketMessage{Type: socketReady, Cursor: &cursor}); err != nil {
+		return
+	}
+	go h.pushUpdates(ctx, s, cursor, types)
+
+	var chats sync.WaitGroup
+	defer chats.Wait()
+	defer cancel() // Stop replies in progress before waiting for them
+	for {
+		var req SocketRequest
+		if err := websocket.JSON.Receive(s.conn, &req); err != nil {
+			return
+		}
+		switch req.Type {
+		case socketSubscribe:
+			s.subscribe(req.TranscriptionIDs)
+		case socketChat:
+			if req.SessionID == "" || req.Content == "" {
+				s.send(SocketMessage{Type: socketError, ID: req.ID, Error: "session_id and content are required"})
+				continue
+			}
+			chats.Add(1)
+			go func() {
+				defer chats.Done()
+				h.socketChat(ctx, s, req)
+			}()
+		case socketPing:
+			s.send(SocketMessage{Type: socketPong, ID: req.ID})
+		default:
+			s.send(SocketMessage{Type: socketError, ID: req.ID, Error: "unknown message type: " + req.Type})
+		}
+	}
+}
+
+// socketChat answers a chat request, streaming the reply as it arrives. It is held to what
+// the chat routes allow: the caller's own sessions, and the LLM request quota.
+func (h *Handler) socketChat(ctx context.Context, s *socket, req SocketRequest) {
+	fail := func(message string) {
+		s.send(SocketMessage{Type: socketError, ID: req.ID, Error: message})
+	}
+	if s.chatRefusal != "" {
+		fail(s.chatRefusal)
+		return
+	}
+	var owners []struct{ UserID *uint }
+	if err := database.DB.Raw(chatSessionOwnerQuery, req.SessionID).Scan(&owners).Error; err != nil {
+		fail("Failed to get chat session")
+		return
+	}
+	if len(owners) == 0 || !(s.admin || ownsJob(s.userID, owners[0].UserID)) {
+		fail("Chat session not found")
+		return
+	}
+	user, err := limitedUser(s.userID)
+	if err != nil {
+		fail("Failed to check quota")
+		return
+	}
+	if user != nil {
+		if err := h.quotas.Check(user, models.QuotaLLMCalls); err != nil {
+			var qerr *quota.Error
+			if !errors.As(err, &qerr) {
+				fail("Failed to check quota")
+				return
+			}
+			fail(qerr.Error())
+			return
+		}
+	}
+
+	turn, _, err := h.beginChatTurn(req.SessionID, req.Content)
+	if err != nil {
+		fail(err.Error())
+		return
+	}
+	err = turn.streamReply(ctx, func(content string) {
+		s.send(SocketMessage{Type: socketChatToken, ID: req.ID, Content: content})
+	})
+	switch {
+	case err == nil:
+		if user != nil {
+			if err := quota.Add(user.ID, models.QuotaLLMCalls, 1); err != nil {
+				logger.ErrorContext(ctx, "Failed to record LLM request against quota", "user_id", user.ID, "error", err)
+			}
+		}
+		s.send(SocketMessage{Type: socketChatDone, ID: req.ID})
+	case ctx.Err() == nil:
+		fail(err.Error())
+	}
+}
+
+// pushUpdates sends the caller's new events and queue position changes every eventPollInterval
+func (h *Handler) pushUpdates(ctx context.Context, s *socket, cursor uint, types []string) {
+	positions := map[string]int{} // Last sent; nothing is sent while nothing is queued
+	for {
+		found, err := events.Poll(scopeToOwner(database.DB, s.userID), cursor, events.MaxPollLimit, types)
+		if err != nil {
+			s.send(SocketMessage{Type: socketError, Error: "Failed to poll events"})
+			return
+		}
+		for i := range found {
+			cursor = found[i].Sequence
+			if found[i].SubjectID != "" && !s.follows(found[i].SubjectID) {
+				continue
+			}
+			if err := s.send(SocketMessage{Type: socketEvent, Event: &found[i]}); err != nil {
+				return
+			}
+		}
+
+		// Other users' jobs move the caller's too, so positions are checked on every tick
+		current, err := h.queuePositions(s.userID)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to read queue positions", "error", err)
+		} else if current = s.followedPositions(current); !maps.Equal(current, positions) {
+			positions = current
+			if err := s.send(SocketMessage{Type: socketQueue, Positions: positions}); err != nil {
+				return
+			}
+		}
+
+		if s.idleFor() >= eventStreamKeepAlive {
+			if err := s.send(SocketMessage{Type: socketPing}); err != nil {
+				return
+			}
+		}
+		if len(found) == events.MaxPollLimit {
+			continue // More are waiting
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventPollInterval):
+		}
+	}
+}
+
+// followedPositions leaves out the positions of transcriptions the client doesn't follow
+func (s *socket) followedPositions(positions map[string]int) map[string]int {
+	followed := make(map[string]int, len(positions))
+	for id, position := range positions {
+		if s.follows(id) {
+			followed[id] = position
+		}
+	}
+	return followed
+}
+
+// queuePositions returns the place in the queue of each of a user's pending transcriptions,
+// counting every user's. Without a task queue, pending transcriptions are ranked the way the
+// queue would run them.
+func (h *Handler) queuePositions(userID *uint) (map[string]int, error) {
+	var ids []string
+	if h.taskQueue != nil {
+		for _, job := range h.taskQueue.Queued() {
+			ids = append(ids, job.ID)
+		}
+	} else if err := database.DB.Model(&models.TranscriptionJob{}).Where("status = ?", models.StatusPending).
+		Order("priority DESC, created_at ASC").Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return map[string]int{}, nil
+	}
+
+	var owned []string
+	if err := scopeToOwner(database.DB.Model(&models.TranscriptionJob{}), userID).Where("id IN ?", ids).Pluck("id", &owned).Error; err != nil {
+		return nil, err
+	}
+	mine := make(map[string]bool, len(owned))
+	for _, id := range owned {
+		mine[id] = true
+	}
+	positions := map[string]int{}
+	for i, id := range ids {
+		if mine[id] {
+			positions[id] = i + 1
+		}
+	}
+	return positions, nil
+}
+
+// latestEventSequence returns the sequence of the newest event, so a stream can start after it
+func latestEventSequence() (uint, error) {
+	var sequence uint
+	err := database.DB.Model(&models.Event{}).Select("COALESCE(MAX(sequence), 0)").Scan(&sequence).Error
+	return sequence, err
+}
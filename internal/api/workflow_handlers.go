@@ -0,0 +1,345 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/workflow"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// StartWorkflowRequest represents a request to run a workflow on a transcription
+type StartWorkflowRequest struct {
+	Workflow string            `json:"workflow" binding:"required"`
+	Params   map[string]string `json:"params,omitempty"`
+}
+
+// WorkflowFailure is a failed or dead-lettered workflow step with the run it belongs to
+type WorkflowFailure struct {
+	models.WorkflowStep
+	TranscriptionID string `json:"transcription_id"`
+	Workflow        string `json:"workflow"`
+}
+
+// ListWorkflowDefinitions returns the available post-processing workflows
+// @Summary List workflows
+// @Description List the post-processing workflows and their step dependencies, the registered steps, and the steps disabled for every run (DISABLED_WORKFLOW_STEPS)
+// @Tags workflows
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/workflows [get]
+func (h *Handler) ListWorkflowDefinitions(c *gin.Context) {
+	if h.workflowEngine == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Post-processing workflows are not enabled"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"workflows":      h.workflowEngine.Definitions(),
+		"steps":          h.workflowEngine.StepNames(),
+		"disabled_steps": h.workflowEngine.DisabledSteps(),
+	})
+}
+
+// ListWorkflowRuns returns the workflow runs of a transcription with per-step status
+// @Summary List workflow runs for a transcription
+// @Description Get every post-processing workflow run for a transcription, newest first, with the status of each step
+// @Tags workflows
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Success 200 {array} models.WorkflowRun
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/workflows [get]
+func (h *Handler) ListWorkflowRuns(c *gin.Context) {
+	jobID := c.Param("id")
+
+	var count int64
+	if err := database.DB.Model(&models.TranscriptionJob{}).Where("id = ?", jobID).Count(&count).Error; err != nil || count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcription not found"})
+		return
+	}
+
+	var runs []models.WorkflowRun
+	if err := database.DB.Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Where("transcription_id = ?", jobID).
+		Order("created_at DESC").
+		Find(&runs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list workflow runs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+// PostProcessingStatusResponse is the post-processing status of a transcription
+type PostProcessingStatusResponse struct {
+	workflow.JobStatus
+	Enabled bool `json:"enabled"` // Whether post-processing runs after transcriptions on this server
+}
+
+// GetPostProcessingStatus returns the latest state of each post-processing step of a transcription
+// @Summary Get post-processing status
+// @Description Get the latest state of each post-processing step of a transcription (summarize, rag_index and so on): pending, running, completed, failed, skipped, blocked or dead_letter, with its error, attempts, timestamps and next retry, so a missing summary or index can be explained. Each step reports its newest run; a transcription that was never post-processed has no steps.
+// @Tags workflows
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Success 200 {object} PostProcessingStatusResponse
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/post-processing [get]
+func (h *Handler) GetPostProcessingStatus(c *gin.Context) {
+	job, ok := loadJob(c)
+	if !ok {
+		return
+	}
+	status, err := workflow.Status(job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get post-processing status"})
+		return
+	}
+	c.JSON(http.StatusOK, PostProcessingStatusResponse{JobStatus: *status, Enabled: h.workflowEngine != nil})
+}
+
+// StartWorkflow runs a workflow on a completed transcription
+// @Summary Start a workflow
+// @Description Run a post-processing workflow (e.g. "default" or "bilingual") on a completed transcription
+// @Tags workflows
+// @Accept json
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param request body StartWorkflowRequest true "Workflow to run"
+// @Success 202 {object} models.WorkflowRun
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/workflows [post]
+func (h *Handler) StartWorkflow(c *gin.Context) {
+	if h.workflowEngine == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Post-processing workflows are not enabled"})
+		return
+	}
+
+	var req StartWorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var job models.TranscriptionJob
+	if err := database.DB.Where("id = ?", c.Param("id")).First(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transcription not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transcription"})
+		return
+	}
+	if job.Status != models.StatusCompleted {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Transcription is not completed"})
+		return
+	}
+
+	if id := req.Params["summary_template_id"]; id != "" {
+		if _, ok := loadSummaryTemplate(c, id); !ok {
+			return
+		}
+	}
+
+	run, err := h.workflowEngine.Start(job.ID, req.Workflow, req.Params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, run)
+}
+
+// RerunWorkflowStep re-runs a single step of a workflow run and the steps that depend on it
+// @Summary Re-run a workflow step
+// @Description Reset a step (typically a failed one) and everything downstream of it, then resume the run. Completed upstream steps are not repeated.
+// @Tags workflows
+// @Produce json
+// @Param id path string true "Transcription ID"
+// @Param run_id path string true "Workflow run ID"
+// @Param step path string true "Step name"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/transcription/{id}/workflows/{run_id}/steps/{step}/rerun [post]
+func (h *Handler) RerunWorkflowStep(c *gin.Context) {
+	if h.workflowEngine == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Post-processing workflows are not enabled"})
+		return
+	}
+
+	var run models.WorkflowRun
+	if err := database.DB.Where("id = ? AND transcription_id = ?", c.Param("run_id"), c.Param("id")).First(&run).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Workflow run not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get workflow run"})
+		return
+	}
+
+	if err := h.workflowEngine.RerunStep(run.ID, c.Param("step")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Step re-run started", "run_id": run.ID, "step": c.Param("step")})
+}
+
+// ListWorkflowFailures returns the workflow steps that failed, across all transcriptions
+// @Summary List failed workflow steps
+// @Description List the post-processing steps that failed, newest first: failed steps waiting for an automatic retry (with next_retry_at) and dead-lettered steps that used up their retries
+// @Tags workflows
+// @Produce json
+// @Param status query string false "Only failed or only dead_letter steps"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Steps per page" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/workflow-failures [get]
+func (h *Handler) ListWorkflowFailures(c *gin.Context) {
+	statuses := []models.WorkflowStatus{models.WorkflowFailed, models.WorkflowDeadLetter}
+	switch status := models.WorkflowStatus(c.Query("status")); status {
+	case "":
+	case models.WorkflowFailed, models.WorkflowDeadLetter:
+		statuses = []models.WorkflowStatus{status}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be failed or dead_letter"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 1000 {
+		limit = 50
+	}
+
+	query := database.DB.Table("workflow_steps").
+		Joins("JOIN workflow_runs ON workflow_runs.id = workflow_steps.run_id").
+		Where("workflow_steps.status IN ?", statuses)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count failed workflow steps"})
+		return
+	}
+	failures := []WorkflowFailure{}
+	if err := query.Select("workflow_steps.*, workflow_runs.transcription_id, workflow_runs.workflow").
+		Order("workflow_steps.updated_at DESC").Offset((page - 1) * limit).Limit(limit).
+		Scan(&failures).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list failed workflow steps"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"failures": failures,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// RequeueWorkflowStep runs a failed or dead-lettered step again
+// @Summary Requeue a failed workflow step
+// @Description Re-run a failed or dead-lettered step and the steps that depend on it, with a fresh set of automatic retries
+// @Tags workflows
+// @Produce json
+// @Param step_id path int true "Workflow step ID"
+// @Success 202 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/workflow-failures/{step_id}/requeue [post]
+func (h *Handler) RequeueWorkflowStep(c *gin.Context) {
+	if h.workflowEngine == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Post-processing workflows are not enabled"})
+		return
+	}
+
+	var step models.WorkflowStep
+	if err := database.DB.Where("id = ?", c.Param("step_id")).First(&step).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Workflow step not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get workflow step"})
+		return
+	}
+	if step.Status != models.WorkflowFailed && step.Status != models.WorkflowDeadLetter {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only failed or dead-lettered steps can be requeued"})
+		return
+	}
+	if err := h.workflowEngine.Requeue(step.RunID, step.Name); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Step requeued", "run_id": step.RunID, "step": step.Name})
+}
+
+// RequeueDeadLetteredSteps runs every dead-lettered step again
+// @Summary Requeue all dead-lettered workflow steps
+// @Description Re-run every dead-lettered step and the steps that depend on it, with a fresh set of automatic retries, e.g. once an LLM or vector store outage is over. Steps of runs still executing are left out.
+// @Tags workflows
+// @Produce json
+// @Success 202 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/admin/workflow-failures/requeue [post]
+func (h *Handler) RequeueDeadLetteredSteps(c *gin.Context) {
+	if h.workflowEngine == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Post-processing workflows are not enabled"})
+		return
+	}
+
+	var steps []models.WorkflowStep
+	if err := database.DB.Where("status = ?", models.WorkflowDeadLetter).Order("run_id, position").Find(&steps).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list dead-lettered steps"})
+		return
+	}
+	requeued, skipped := 0, 0
+	seen := map[string]bool{}
+	for _, step := range steps {
+		// A run executes one requeue at a time; its other steps stay dead-lettered for now
+		if seen[step.RunID] {
+			skipped++
+			continue
+		}
+		seen[step.RunID] = true
+		if err := h.workflowEngine.Requeue(step.RunID, step.Name); err != nil {
+			skipped++
+			continue
+		}
+		requeued++
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"requeued": requeued, "skipped": skipped})
+}
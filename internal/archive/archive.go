@@ -0,0 +1,234 @@
+// Package archive packs transcriptions into a zip archive that another Scriberr instance can
+// restore them from: each transcription's record, summaries, notes, speaker names, chapters,
+// action items, translations and correction history, and optionally its audio, listed in a
+// manifest.
+package archive
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"scriberr/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Version is the version of the archive format this package writes and reads
+const Version = 1
+
+// ManifestName is the name of the manifest in an archive
+const ManifestName = "manifest.json"
+
+// Manifest lists the transcriptions in an archive
+type Manifest struct {
+	Version        int       `json:"version"`
+	ExportedAt     time.Time `json:"exported_at"`
+	Audio          bool      `json:"audio"` // Whether audio was asked for; entries without it had none
+	Transcriptions []Entry   `json:"transcriptions"`
+}
+
+// Entry is a transcription in an archive and where its files are
+type Entry struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title,omitempty"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	Record    string    `json:"record"`          // Path of the Record in the archive
+	Audio     string    `json:"audio,omitempty"` // Path of the audio in the archive, if included
+	AudioSize int64     `json:"audio_size,omitempty"`
+}
+
+// Record is everything an archive keeps about one transcription
+type Record struct {
+	Job          models.TranscriptionJob     `json:"job"`
+	Summaries    []models.Summary            `json:"summaries"`
+	Notes        []models.Note               `json:"notes"`
+	SpeakerNames map[string]string           `json:"speaker_names"` // Custom names of speaker labels
+	Chapters     []models.Chapter            `json:"chapters"`
+	ActionItems  []models.ActionItem         `json:"action_items"`
+	Translations []models.Translation        `json:"translations"`
+	Revisions    []models.TranscriptRevision `json:"revisions"`
+	Versions     []models.TranscriptVersion  `json:"versions,omitempty"` // Missing from archives written before versions were kept
+}
+
+// Writer writes an archive, one transcription at a time, so a whole library can be streamed
+type Writer struct {
+	zip      *zip.Writer
+	manifest Manifest
+}
+
+// NewWriter starts an archive written to w. audio records whether audio is being included.
+func NewWriter(w io.Writer, audio bool) *Writer {
+	return &Writer{
+		zip:      zip.NewWriter(w),
+		manifest: Manifest{Version: Version, ExportedAt: time.Now().UTC(), Audio: audio, Transcriptions: []Entry{}},
+	}
+}
+
+// Add writes a transcription's record and, when audioPath is not empty, its audio
+func (w *Writer) Add(record *Record, audioPath string) error {
+	job := &record.Job
+	dir := "transcriptions/" + job.ID + "/"
+	entry := Entry{ID: job.ID, Status: string(job.Status), CreatedAt: job.CreatedAt, Record: dir + "record.json"}
+	if job.Title != nil {
+		entry.Title = *job.Title
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	file, err := w.zip.Create(entry.Record)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		return err
+	}
+
+	if audioPath != "" {
+		audio, err := os.Open(audioPath)
+		if err != nil {
+			return err
+		}
+		defer audio.Close()
+		entry.Audio = dir + "audio" + filepath.Ext(audioPath)
+		// Audio is compressed already
+		file, err := w.zip.CreateHeader(&zip.FileHeader{Name: entry.Audio, Method: zip.Store, Modified: job.CreatedAt})
+		if err != nil {
+			return err
+		}
+		if entry.AudioSize, err = io.Copy(file, audio); err != nil {
+			return fmt.Errorf("failed to archive audio of %s: %w", job.ID, err)
+		}
+	}
+	w.manifest.Transcriptions = append(w.manifest.Transcriptions, entry)
+	return nil
+}
+
+// Close writes the manifest and finishes the archive
+func (w *Writer) Close() error {
+	data, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	file, err := w.zip.Create(ManifestName)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		return err
+	}
+	return w.zip.Close()
+}
+
+// Reader reads an archive
+type Reader struct {
+	Manifest Manifest
+	files    map[string]*zip.File
+}
+
+// NewReader opens an archive of the given size and reads its manifest
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("not a zip archive: %w", err)
+	}
+	reader := &Reader{files: make(map[string]*zip.File, len(archive.File))}
+	for _, file := range archive.File {
+		reader.files[file.Name] = file
+	}
+	manifest, ok := reader.files[ManifestName]
+	if !ok {
+		return nil, errors.New("the archive has no " + ManifestName)
+	}
+	body, err := manifest.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	if err := json.NewDecoder(body).Decode(&reader.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if reader.Manifest.Version != Version {
+		return nil, fmt.Errorf("unsupported archive version %d", reader.Manifest.Version)
+	}
+	for _, entry := range reader.Manifest.Transcriptions {
+		if _, err := uuid.Parse(entry.ID); err != nil {
+			return nil, fmt.Errorf("invalid transcription ID %q", entry.ID)
+		}
+		if _, ok := reader.files[entry.Record]; !ok {
+			return nil, fmt.Errorf("the record of %s is missing", entry.ID)
+		}
+		if entry.Audio != "" {
+			if _, ok := reader.files[entry.Audio]; !ok {
+				return nil, fmt.Errorf("the audio of %s is missing", entry.ID)
+			}
+		}
+	}
+	return reader, nil
+}
+
+// Record reads the record of a transcription in the manifest
+func (r *Reader) Record(entry Entry) (*Record, error) {
+	body, err := r.files[entry.Record].Open()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	var record Record
+	if err := json.NewDecoder(body).Decode(&record); err != nil {
+		return nil, fmt.Errorf("invalid record of %s: %w", entry.ID, err)
+	}
+	if record.Job.ID != entry.ID {
+		return nil, fmt.Errorf("the record of %s is of another transcription", entry.ID)
+	}
+	return &record, nil
+}
+
+// audioExt matches the file extensions audio is restored with
+var audioExt = regexp.MustCompile(`^\.[A-Za-z0-9]{1,10}$`)
+
+// AudioExt returns the file extension of a transcription's audio, or "" when it has none or
+// an extension no audio file has
+func (r *Reader) AudioExt(entry Entry) string {
+	ext := path.Ext(entry.Audio)
+	if !audioExt.MatchString(ext) {
+		return ""
+	}
+	return ext
+}
+
+// ExtractAudio writes a transcription's audio to dest, which is removed again if that fails
+func (r *Reader) ExtractAudio(entry Entry, dest string) error {
+	body, err := r.files[entry.Audio].Open()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, body); err != nil {
+		file.Close()
+		os.Remove(dest)
+		return fmt.Errorf("failed to extract audio of %s: %w", entry.ID, err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(dest)
+		return err
+	}
+	return nil
+}
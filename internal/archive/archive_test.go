@@ -0,0 +1,104 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"scriberr/internal/models"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	audioPath := filepath.Join(dir, "recording.mp3")
+	if err := os.WriteFile(audioPath, []byte("fake audio"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	title, transcript := "Standup", `{"text":"Hello."}`
+	first := &Record{
+		Job:          models.TranscriptionJob{ID: "0b6f8c1e-3f4a-4d5e-9a7b-1c2d3e4f5a6b", Title: &title, Status: models.StatusCompleted, Transcript: &transcript},
+		Summaries:    []models.Summary{{Content: "Short."}},
+		SpeakerNames: map[string]string{"SPEAKER_00": "Dana"},
+	}
+	second := &Record{Job: models.TranscriptionJob{ID: "7d1e2f3a-4b5c-4d6e-8f9a-0b1c2d3e4f5a", Status: models.StatusFailed}}
+
+	var buf bytes.Buffer
+	writer := NewWriter(&buf, true)
+	if err := writer.Add(first, audioPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Add(second, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := reader.Manifest.Transcriptions
+	if len(entries) != 2 || !reader.Manifest.Audio || entries[0].Title != "Standup" || entries[0].AudioSize != 10 || entries[1].Audio != "" {
+		t.Fatalf("unexpected manifest %+v", reader.Manifest)
+	}
+	record, err := reader.Record(entries[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *record.Job.Transcript != transcript || record.Summaries[0].Content != "Short." || record.SpeakerNames["SPEAKER_00"] != "Dana" {
+		t.Errorf("unexpected record %+v", record)
+	}
+	if ext := reader.AudioExt(entries[0]); ext != ".mp3" {
+		t.Errorf("unexpected audio extension %q", ext)
+	}
+	restored := filepath.Join(dir, "restored", "audio.mp3")
+	if err := reader.ExtractAudio(entries[0], restored); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(restored); string(data) != "fake audio" {
+		t.Errorf("unexpected audio %q", data)
+	}
+	if reader.AudioExt(entries[1]) != "" || reader.AudioExt(Entry{Audio: "transcriptions/x/audio.mp3;rm"}) != "" {
+		t.Error("audio extension not checked")
+	}
+}
+
+func TestArchiveInvalid(t *testing.T) {
+	build := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		archive := zip.NewWriter(&buf)
+		for name, content := range files {
+			w, _ := archive.Create(name)
+			w.Write([]byte(content))
+		}
+		archive.Close()
+		return buf.Bytes()
+	}
+	for name, data := range map[string][]byte{
+		"not a zip":   []byte("hello"),
+		"no manifest": build(map[string]string{"x.json": "{}"}),
+		"version":     build(map[string]string{ManifestName: `{"version":9}`}),
+		"bad id":      build(map[string]string{ManifestName: `{"version":1,"transcriptions":[{"id":"../etc","record":"r.json"}]}`, "r.json": "{}"}),
+		"no record":   build(map[string]string{ManifestName: `{"version":1,"transcriptions":[{"id":"0b6f8c1e-3f4a-4d5e-9a7b-1c2d3e4f5a6b","record":"r.json"}]}`}),
+	} {
+		if _, err := NewReader(bytes.NewReader(data), int64(len(data))); err == nil {
+			t.Errorf("%s: archive accepted", name)
+		}
+	}
+
+	data := build(map[string]string{
+		ManifestName: `{"version":1,"transcriptions":[{"id":"0b6f8c1e-3f4a-4d5e-9a7b-1c2d3e4f5a6b","record":"r.json"}]}`,
+		"r.json":     `{"job":{"id":"7d1e2f3a-4b5c-4d6e-8f9a-0b1c2d3e4f5a"}}`,
+	})
+	reader, err := NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reader.Record(reader.Manifest.Transcriptions[0]); err == nil || !strings.Contains(err.Error(), "another transcription") {
+		t.Errorf("mismatched record accepted: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package audio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ChannelSplitter separates the channels of a multi-channel recording into files of their own
+type ChannelSplitter struct {
+	ffmpegPath  string
+	ffprobePath string
+}
+
+// NewChannelSplitter creates a channel splitter using ffmpeg and ffprobe from PATH
+func NewChannelSplitter() *ChannelSplitter {
+	return &ChannelSplitter{ffmpegPath: "ffmpeg", ffprobePath: "ffprobe"}
+}
+
+// Channels returns the number of channels in a file's first audio stream
+func (s *ChannelSplitter) Channels(ctx context.Context, inputPath string) (int, error) {
+	cmd := exec.CommandContext(ctx, s.ffprobePath,
+		"-v", "quiet",
+		"-print_format", "json",
+		"-show_streams",
+		"-select_streams", "a:0",
+		inputPath)
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe failed: %w", err)
+	}
+
+	var probe struct {
+		Streams []struct {
+			Channels int `json:"channels"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return 0, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+	if len(probe.Streams) == 0 {
+		return 0, fmt.Errorf("no audio stream found")
+	}
+	return probe.Streams[0].Channels, nil
+}
+
+// Split writes each channel of the input to outputDir as a 16 kHz mono FLAC file, named after
+// the entry for it in names, and returns the paths in channel order
+func (s *ChannelSplitter) Split(ctx context.Context, inputPath, outputDir string, names []string) ([]string, error) {
+	graph, labels := splitGraph(len(names))
+	args := []string{"-hide_banner", "-nostats", "-i", inputPath, "-filter_complex", graph}
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = filepath.Join(outputDir, name+".flac")
+		args = append(args, "-map", labels[i], "-ar", "16000", "-c:a", "flac", "-y", paths[i])
+	}
+
+	cmd := exec.CommandContext(ctx, s.ffmpegPath, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("channel split failed: %w - %s", err, string(output))
+	}
+	return paths, nil
+}
+
+// splitGraph builds a filter graph taking each of the input's channels to a mono output of
+// its own, whatever the channel layout, and returns the graph with the output labels
+func splitGraph(channels int) (string, []string) {
+	parts := make([]string, channels)
+	labels := make([]string, channels)
+	for i := 0; i < channels; i++ {
+		labels[i] = fmt.Sprintf("[ch%d]", i)
+		parts[i] = fmt.Sprintf("[0:a]pan=mono|c0=c%d%s", i, labels[i])
+	}
+	return strings.Join(parts, ";"), labels
+}
@@ -0,0 +1,17 @@
+package audio
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitGraph(t *testing.T) {
+	graph, labels := splitGraph(3)
+
+	if want := "[0:a]pan=mono|c0=c0[ch0];[0:a]pan=mono|c0=c1[ch1];[0:a]pan=mono|c0=c2[ch2]"; graph != want {
+		t.Errorf("expected %q, got %q", want, graph)
+	}
+	if want := []string{"[ch0]", "[ch1]", "[ch2]"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("expected labels %v, got %v", want, labels)
+	}
+}
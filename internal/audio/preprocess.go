@@ -0,0 +1,321 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Preprocessing steps applied to audio before transcription
+const (
+	StepTrimSilence = "trim_silence" // drop leading and trailing silence
+	StepSkipSilence = "skip_silence" // drop long silences anywhere, transcribing only the speech
+	StepDenoise     = "denoise"      // rumble filter and FFT noise reduction
+	StepNormalize   = "normalize"    // EBU R128 loudness normalization
+	StepResample    = "resample"     // 16 kHz mono
+)
+
+// PreprocessSteps are the preprocessing steps, in the order they run
+var PreprocessSteps = []string{StepTrimSilence, StepSkipSilence, StepDenoise, StepNormalize, StepResample}
+
+// Silence detection defaults
+const (
+	DefaultSilenceThresholdDb = -50.0 // quieter than this counts as silence
+	DefaultMinSkippedSilence  = 3.0   // seconds; shorter silences inside the speech are kept
+	minSilenceSeconds         = 0.5   // shorter gaps aren't detected at all
+	silencePadSeconds         = 0.25  // kept around the speech so word onsets aren't clipped
+)
+
+// Region is a stretch of the original audio, in seconds
+type Region struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// PreprocessResult describes what preprocessing did to a file
+type PreprocessResult struct {
+	Steps        []string `json:"steps"`
+	TrimmedStart float64  `json:"trimmed_start"` // Seconds of leading silence removed
+	TrimmedEnd   float64  `json:"trimmed_end"`   // Seconds of trailing silence removed
+	// Kept are the regions of the original audio in the output, back to back; nil when
+	// nothing was removed
+	Kept []Region `json:"kept,omitempty"`
+	// Removed are the silences left out of the output, in the original's time
+	Removed []Region `json:"removed,omitempty"`
+}
+
+// OriginalTime converts a time in the preprocessed audio to the same moment in the original.
+// A time right at the join of two kept regions belongs to the earlier one when it ends
+// something, such as a segment, and to the later one otherwise.
+func (r *PreprocessResult) OriginalTime(seconds float64, isEnd bool) float64 {
+	if r == nil || len(r.Kept) == 0 {
+		return seconds
+	}
+	offset := 0.0
+	for i, region := range r.Kept {
+		length := region.End - region.Start
+		last := i == len(r.Kept)-1
+		if seconds < offset+length || (isEnd && seconds == offset+length) || last {
+			return region.Start + seconds - offset
+		}
+		offset += length
+	}
+	return seconds
+}
+
+// SkippedSeconds is how much silence was left out of the output
+func (r *PreprocessResult) SkippedSeconds() float64 {
+	total := 0.0
+	for _, region := range r.Removed {
+		total += region.End - region.Start
+	}
+	return total
+}
+
+// ParsePreprocessSteps reads a comma-separated list of preprocessing steps, returning them in
+// the order they run. An empty list or "none" means no preprocessing.
+func ParsePreprocessSteps(list string) ([]string, error) {
+	chosen := map[string]bool{}
+	for _, step := range strings.Split(list, ",") {
+		step = strings.ToLower(strings.TrimSpace(step))
+		if step == "" || step == "none" {
+			continue
+		}
+		known := false
+		for _, s := range PreprocessSteps {
+			known = known || s == step
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown preprocessing step %q (valid steps: %s)", step, strings.Join(PreprocessSteps, ", "))
+		}
+		chosen[step] = true
+	}
+	var steps []string
+	for _, step := range PreprocessSteps {
+		if chosen[step] {
+			steps = append(steps, step)
+		}
+	}
+	return steps, nil
+}
+
+// Preprocessor prepares audio for transcription with ffmpeg
+type Preprocessor struct {
+	// SilenceThresholdDb is the level below which audio counts as silence
+	SilenceThresholdDb float64
+	// MinSkippedSilence is the shortest silence, in seconds, skip_silence leaves out
+	MinSkippedSilence float64
+
+	ffmpegPath string
+	quality    *QualityAnalyzer
+}
+
+// NewPreprocessor creates a preprocessor using ffmpeg and ffprobe from PATH
+func NewPreprocessor() *Preprocessor {
+	return &Preprocessor{
+		SilenceThresholdDb: DefaultSilenceThresholdDb,
+		MinSkippedSilence:  DefaultMinSkippedSilence,
+		ffmpegPath:         "ffmpeg",
+		quality:            NewQualityAnalyzer(),
+	}
+}
+
+// Run writes a WAV copy of the input with the steps applied
+func (p *Preprocessor) Run(ctx context.Context, inputPath, outputPath string, steps []string) (*PreprocessResult, error) {
+	result := &PreprocessResult{Steps: steps}
+	has := map[string]bool{}
+	for _, step := range steps {
+		has[step] = true
+	}
+
+	if has[StepTrimSilence] || has[StepSkipSilence] {
+		silences, duration, err := p.detectSilence(ctx, inputPath)
+		if err != nil {
+			return nil, err
+		}
+		minSkipped := 0.0 // Only the ends
+		if has[StepSkipSilence] {
+			minSkipped = p.MinSkippedSilence
+		}
+		result.Kept, result.Removed = keptRegions(silences, duration, minSkipped)
+		if len(result.Kept) > 0 {
+			result.TrimmedStart = result.Kept[0].Start
+			result.TrimmedEnd = duration - result.Kept[len(result.Kept)-1].End
+		}
+	}
+
+	var filters []string
+	if has[StepDenoise] {
+		filters = append(filters, "highpass=f=80", "afftdn=nf=-25")
+	}
+	if has[StepNormalize] {
+		filters = append(filters, "loudnorm=I=-16:TP=-1.5:LRA=11")
+	}
+
+	args := []string{"-hide_banner", "-nostats", "-i", inputPath, "-vn"}
+	switch {
+	case len(result.Kept) > 1:
+		// Too many regions for the command line; the graph goes in a file
+		script, err := os.CreateTemp("", "scriberr-filter-*.txt")
+		if err != nil {
+			return nil, fmt.Errorf("failed to write filter graph: %w", err)
+		}
+		defer os.Remove(script.Name())
+		_, err = script.WriteString(concatGraph(result.Kept, filters))
+		if closeErr := script.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to write filter graph: %w", err)
+		}
+		args = append(args, "-filter_complex_script", script.Name(), "-map", "[out]")
+	case len(result.Kept) == 1:
+		trim := []string{
+			fmt.Sprintf("atrim=start=%.3f:end=%.3f", result.Kept[0].Start, result.Kept[0].End),
+			"asetpts=PTS-STARTPTS",
+		}
+		args = append(args, "-af", strings.Join(append(trim, filters...), ","))
+	case len(filters) > 0:
+		args = append(args, "-af", strings.Join(filters, ","))
+	}
+	if has[StepResample] {
+		args = append(args, "-ar", "16000", "-ac", "1")
+	} else if has[StepNormalize] {
+		// loudnorm works at 192 kHz and outputs that unless told otherwise
+		report := &QualityReport{}
+		if err := p.quality.probeFormat(ctx, inputPath, report); err == nil && report.SampleRate > 0 {
+			args = append(args, "-ar", strconv.Itoa(report.SampleRate))
+		} else {
+			args = append(args, "-ar", "48000")
+		}
+	}
+	args = append(args, "-c:a", "pcm_s16le", "-y", outputPath)
+
+	cmd := exec.CommandContext(ctx, p.ffmpegPath, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("audio preprocessing failed: %w - %s", err, string(output))
+	}
+	return result, nil
+}
+
+// concatGraph builds a filter graph that joins the kept regions of the input and then applies
+// the filters, labelling the result [out]
+func concatGraph(kept []Region, filters []string) string {
+	var graph strings.Builder
+	for i, region := range kept {
+		fmt.Fprintf(&graph, "[0:a]atrim=start=%.3f:end=%.3f,asetpts=PTS-STARTPTS[r%d];\n", region.Start, region.End, i)
+	}
+	for i := range kept {
+		fmt.Fprintf(&graph, "[r%d]", i)
+	}
+	fmt.Fprintf(&graph, "concat=n=%d:v=0:a=1", len(kept))
+	for _, filter := range filters {
+		graph.WriteString("," + filter)
+	}
+	graph.WriteString("[out]\n")
+	return graph.String()
+}
+
+// detectSilence finds the silences in a file, along with its duration
+func (p *Preprocessor) detectSilence(ctx context.Context, inputPath string) ([]Region, float64, error) {
+	cmd := exec.CommandContext(ctx, p.ffmpegPath,
+		"-hide_banner", "-nostats",
+		"-i", inputPath,
+		"-vn",
+		"-af", fmt.Sprintf("silencedetect=noise=%.1fdB:d=%.2f", p.SilenceThresholdDb, minSilenceSeconds),
+		"-f", "null",
+		"-")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, 0, fmt.Errorf("silence detection failed: %w - %s", err, string(output))
+	}
+	silences, duration := parseSilencedetect(string(output))
+	return silences, duration, nil
+}
+
+var (
+	durationPattern     = regexp.MustCompile(`Duration: (\d+):(\d+):(\d+(?:\.\d+)?)`)
+	silenceStartPattern = regexp.MustCompile(`silence_start: (-?\d+(?:\.\d+)?)`)
+	silenceEndPattern   = regexp.MustCompile(`silence_end: (\d+(?:\.\d+)?)`)
+)
+
+// parseSilencedetect reads the silences and the input's duration from silencedetect output.
+// Older ffmpeg doesn't report a silence_end for silence running to the end of the file, which
+// is why an open silence is taken to end with it.
+func parseSilencedetect(output string) ([]Region, float64) {
+	duration := 0.0
+	if m := durationPattern.FindStringSubmatch(output); m != nil {
+		hours, _ := strconv.ParseFloat(m[1], 64)
+		minutes, _ := strconv.ParseFloat(m[2], 64)
+		seconds, _ := strconv.ParseFloat(m[3], 64)
+		duration = hours*3600 + minutes*60 + seconds
+	}
+	if duration <= 0 {
+		return nil, 0
+	}
+
+	var silences []Region
+	for _, line := range strings.Split(output, "\n") {
+		if m := silenceStartPattern.FindStringSubmatch(line); m != nil {
+			start, _ := strconv.ParseFloat(m[1], 64)
+			silences = append(silences, Region{Start: start, End: duration})
+		} else if m := silenceEndPattern.FindStringSubmatch(line); m != nil && len(silences) > 0 {
+			silences[len(silences)-1].End, _ = strconv.ParseFloat(m[1], 64)
+		}
+	}
+	return silences, duration
+}
+
+// keptRegions decides which parts of a file to keep given its silences: silence at either end
+// is always removed, and silence elsewhere when it lasts minSkipped seconds or more (0 keeps
+// it all). Speech keeps silencePadSeconds around it. It returns nil when nothing would be
+// removed, or everything would.
+func keptRegions(silences []Region, duration, minSkipped float64) ([]Region, []Region) {
+	const slack = 0.05 // Silence this close to an end reaches it
+	var removed []Region
+	for _, silence := range silences {
+		atStart := silence.Start <= slack
+		atEnd := silence.End >= duration-slack
+		start, end := silence.Start+silencePadSeconds, silence.End-silencePadSeconds
+		switch {
+		case atStart && atEnd:
+			return nil, nil // All silence; left whole
+		case atStart:
+			start = 0
+		case atEnd:
+			end = duration
+		case minSkipped <= 0 || silence.End-silence.Start < minSkipped:
+			continue
+		}
+		if end > start {
+			removed = append(removed, Region{Start: start, End: end})
+		}
+	}
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i].Start < removed[j].Start })
+
+	var kept []Region
+	position := 0.0
+	for _, gap := range removed {
+		if gap.Start > position {
+			kept = append(kept, Region{Start: position, End: gap.Start})
+		}
+		if gap.End > position {
+			position = gap.End
+		}
+	}
+	if position < duration {
+		kept = append(kept, Region{Start: position, End: duration})
+	}
+	if len(kept) == 0 {
+		return nil, nil
+	}
+	return kept, removed
+}
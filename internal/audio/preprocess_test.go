@@ -0,0 +1,120 @@
+package audio
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePreprocessSteps(t *testing.T) {
+	steps, err := ParsePreprocessSteps(" Resample, normalize,trim_silence,normalize ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{StepTrimSilence, StepNormalize, StepResample}; !reflect.DeepEqual(steps, want) {
+		t.Errorf("expected %v in running order, got %v", want, steps)
+	}
+
+	for _, list := range []string{"", "none"} {
+		if steps, err := ParsePreprocessSteps(list); err != nil || len(steps) != 0 {
+			t.Errorf("expected no steps for %q, got %v, %v", list, steps, err)
+		}
+	}
+	if _, err := ParsePreprocessSteps("normalize,reverb"); err == nil {
+		t.Error("expected an error for an unknown step")
+	}
+}
+
+const sampleSilencedetectOutput = `Input #0, wav, from 'memo.wav':
+  Duration: 00:00:20.00, bitrate: 256 kb/s
+[silencedetect @ 0x5581] silence_start: 0
+[silencedetect @ 0x5581] silence_end: 3.5 | silence_duration: 3.5
+[silencedetect @ 0x5581] silence_start: 8.2
+[silencedetect @ 0x5581] silence_end: 9.1 | silence_duration: 0.9
+[silencedetect @ 0x5581] silence_start: 17
+`
+
+func TestKeptRegionsTrimsEnds(t *testing.T) {
+	silences, duration := parseSilencedetect(sampleSilencedetectOutput)
+	if duration != 20 || len(silences) != 3 {
+		t.Fatalf("expected 3 silences in 20s, got %v in %v", silences, duration)
+	}
+	kept, removed := keptRegions(silences, duration, 0)
+	if want := []Region{{3.25, 17.25}}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("expected speech from 3.25 to 17.25, got %v", kept)
+	}
+	if want := []Region{{0, 3.25}, {17.25, 20}}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("expected both ends removed, got %v", removed)
+	}
+
+	// Newer ffmpeg reports the end of silence running to the end of the file
+	silences, duration = parseSilencedetect(sampleSilencedetectOutput + "[silencedetect @ 0x5581] silence_end: 20 | silence_duration: 3\n")
+	if kept, _ := keptRegions(silences, duration, 0); !reflect.DeepEqual(kept, []Region{{3.25, 17.25}}) {
+		t.Errorf("expected speech from 3.25 to 17.25, got %v", kept)
+	}
+
+	// Silence in the middle only is left alone
+	silences, duration = parseSilencedetect(`  Duration: 00:01:00.00, bitrate: 256 kb/s
+[silencedetect @ 0x5581] silence_start: 10
+[silencedetect @ 0x5581] silence_end: 12 | silence_duration: 2
+`)
+	if kept, _ := keptRegions(silences, duration, 0); kept != nil {
+		t.Errorf("expected the whole file, got %v", kept)
+	}
+
+	// A file that is all silence isn't trimmed to nothing
+	silences, duration = parseSilencedetect(`  Duration: 00:00:05.00, bitrate: 256 kb/s
+[silencedetect @ 0x5581] silence_start: 0
+`)
+	if kept, _ := keptRegions(silences, duration, 3); kept != nil {
+		t.Errorf("expected the whole file, got %v", kept)
+	}
+}
+
+func TestKeptRegionsSkipsLongSilences(t *testing.T) {
+	silences, duration := parseSilencedetect(`  Duration: 01:00:00.00, bitrate: 256 kb/s
+[silencedetect @ 0x5581] silence_start: 10
+[silencedetect @ 0x5581] silence_end: 12 | silence_duration: 2
+[silencedetect @ 0x5581] silence_start: 20
+[silencedetect @ 0x5581] silence_end: 1800 | silence_duration: 1780
+[silencedetect @ 0x5581] silence_start: 1830
+`)
+	kept, removed := keptRegions(silences, duration, 3)
+	if want := []Region{{0, 20.25}, {1799.75, 1830.25}}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("expected the two stretches of speech, got %v", kept)
+	}
+	if want := []Region{{20.25, 1799.75}, {1830.25, 3600}}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("expected the long silences removed, got %v", removed)
+	}
+
+	result := &PreprocessResult{Kept: kept, Removed: removed}
+	if got := result.SkippedSeconds(); got != 3549.25 {
+		t.Errorf("expected 3549.25s skipped, got %v", got)
+	}
+	for _, c := range []struct {
+		at    float64
+		isEnd bool
+		want  float64
+	}{
+		{5, false, 5},
+		{20.25, true, 20.25},    // End of the first stretch
+		{20.25, false, 1799.75}, // Start of the second
+		{30.25, false, 1809.75},
+		{60, false, 1839.5}, // Past the end stays past the end
+	} {
+		if got := result.OriginalTime(c.at, c.isEnd); got != c.want {
+			t.Errorf("OriginalTime(%v, %v) = %v, want %v", c.at, c.isEnd, got, c.want)
+		}
+	}
+
+	graph := concatGraph(kept, []string{"loudnorm"})
+	for _, part := range []string{
+		"[0:a]atrim=start=0.000:end=20.250,asetpts=PTS-STARTPTS[r0];",
+		"[0:a]atrim=start=1799.750:end=1830.250,asetpts=PTS-STARTPTS[r1];",
+		"[r0][r1]concat=n=2:v=0:a=1,loudnorm[out]",
+	} {
+		if !strings.Contains(graph, part) {
+			t.Errorf("expected %q in the filter graph:\n%s", part, graph)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package audio
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Thresholds used to flag poor quality recordings
+const (
+	minSNRDb         = 15.0   // below this, background noise noticeably hurts recognition
+	clipPeakDb       = -0.1   // samples at or above this level are treated as clipped
+	maxClippingRatio = 0.0005 // fraction of clipped samples tolerated before warning
+	minBitRate       = 64000  // lossy encodes below this lose consonant detail
+	minSampleRate    = 16000  // models resample to 16kHz, anything lower is missing bandwidth
+	quietRMSDb       = -45.0  // overall level below this is likely a distant or muted microphone
+)
+
+// QualityReport summarizes an audio file's suitability for transcription
+type QualityReport struct {
+	AnalyzedAt     time.Time `json:"analyzed_at"`
+	SNRDb          float64   `json:"snr_db"`
+	RMSLevelDb     float64   `json:"rms_level_db"`
+	PeakLevelDb    float64   `json:"peak_level_db"`
+	NoiseFloorDb   float64   `json:"noise_floor_db"`
+	ClippedSamples int64     `json:"clipped_samples"`
+	ClippingRatio  float64   `json:"clipping_ratio"`
+	BitRate        int       `json:"bit_rate"`
+	SampleRate     int       `json:"sample_rate"`
+	Warnings       []string  `json:"warnings"`
+	Poor           bool      `json:"poor"`
+}
+
+// QualityAnalyzer inspects audio files with ffmpeg/ffprobe
+type QualityAnalyzer struct {
+	ffmpegPath  string
+	ffprobePath string
+}
+
+// NewQualityAnalyzer creates a quality analyzer using ffmpeg and ffprobe from PATH
+func NewQualityAnalyzer() *QualityAnalyzer {
+	return &QualityAnalyzer{
+		ffmpegPath:  "ffmpeg",
+		ffprobePath: "ffprobe",
+	}
+}
+
+// Analyze measures levels, noise and clipping for an audio file and derives warnings
+func (q *QualityAnalyzer) Analyze(ctx context.Context, inputPath string) (*QualityReport, error) {
+	report := &QualityReport{AnalyzedAt: time.Now()}
+
+	if err := q.probeFormat(ctx, inputPath, report); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, q.ffmpegPath,
+		"-hide_banner", "-nostats",
+		"-i", inputPath,
+		"-map", "0:a:0",
+		"-af", "astats=measure_perchannel=none",
+		"-f", "null", "-")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg astats failed: %w", err)
+	}
+
+	stats := parseAstats(string(output))
+	report.RMSLevelDb = stats["RMS level dB"]
+	report.PeakLevelDb = stats["Peak level dB"]
+	report.NoiseFloorDb = stats["Noise floor dB"]
+	if report.NoiseFloorDb != 0 {
+		report.SNRDb = report.RMSLevelDb - report.NoiseFloorDb
+	}
+
+	// astats counts how often the peak value occurs; a peak at full scale means clipping
+	if report.PeakLevelDb >= clipPeakDb {
+		report.ClippedSamples = int64(stats["Peak count"])
+		if samples := stats["Number of samples"]; samples > 0 {
+			report.ClippingRatio = float64(report.ClippedSamples) / samples
+		}
+	}
+
+	report.evaluate()
+	return report, nil
+}
+
+// probeFormat fills in bit rate and sample rate from ffprobe
+func (q *QualityAnalyzer) probeFormat(ctx context.Context, inputPath string, report *QualityReport) error {
+	cmd := exec.CommandContext(ctx, q.ffprobePath,
+		"-v", "quiet",
+		"-print_format", "json",
+		"-show_format",
+		"-show_streams",
+		"-select_streams", "a:0",
+		inputPath)
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("ffprobe failed: %w", err)
+	}
+
+	var probe struct {
+		Streams []struct {
+			SampleRate string `json:"sample_rate"`
+			BitRate    string `json:"bit_rate"`
+		} `json:"streams"`
+		Format struct {
+			BitRate string `json:"bit_rate"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+	if len(probe.Streams) == 0 {
+		return fmt.Errorf("no audio stream found")
+	}
+
+	report.SampleRate, _ = strconv.Atoi(probe.Streams[0].SampleRate)
+	// Prefer the stream bit rate; containers like mp4 include video and metadata overhead
+	bitRate := probe.Streams[0].BitRate
+	if bitRate == "" {
+		bitRate = probe.Format.BitRate
+	}
+	report.BitRate, _ = strconv.Atoi(bitRate)
+	return nil
+}
+
+// evaluate converts measurements into user-facing warnings
+func (r *QualityReport) evaluate() {
+	r.Warnings = []string{}
+
+	if r.NoiseFloorDb != 0 && r.SNRDb < minSNRDb {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("High background noise (estimated SNR %.1f dB)", r.SNRDb))
+		r.Poor = true
+	}
+	if r.ClippingRatio > maxClippingRatio {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("Audio is clipping (%d clipped samples)", r.ClippedSamples))
+		r.Poor = true
+	}
+	if r.RMSLevelDb != 0 && r.RMSLevelDb < quietRMSDb {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("Recording is very quiet (RMS %.1f dB)", r.RMSLevelDb))
+		r.Poor = true
+	}
+	if r.BitRate > 0 && r.BitRate < minBitRate {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("Low bitrate (%d kbps) may reduce accuracy", r.BitRate/1000))
+	}
+	if r.SampleRate > 0 && r.SampleRate < minSampleRate {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("Low sample rate (%d Hz) may reduce accuracy", r.SampleRate))
+	}
+}
+
+// parseAstats extracts the "Overall" section of ffmpeg's astats output into a map
+func parseAstats(output string) map[string]float64 {
+	stats := make(map[string]float64)
+	inOverall := false
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		// Lines look like: [Parsed_astats_0 @ 0x...] RMS level dB: -23.45
+		if idx := strings.Index(line, "] "); idx >= 0 && strings.Contains(line[:idx], "astats") {
+			line = line[idx+2:]
+		} else {
+			continue
+		}
+		line = strings.TrimSpace(line)
+
+		if line == "Overall" {
+			inOverall = true
+			continue
+		}
+		if !inOverall {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		// Silent input reports -inf levels, which can't be serialized
+		if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && !math.IsInf(v, 0) && !math.IsNaN(v) {
+			stats[strings.TrimSpace(key)] = v
+		}
+	}
+	return stats
+}
+
+// Enhance writes a cleaned-up copy of the input: rumble filter, FFT denoise and loudness normalization
+func (q *QualityAnalyzer) Enhance(ctx context.Context, inputPath, outputPath string) error {
+	cmd := exec.CommandContext(ctx, q.ffmpegPath,
+		"-hide_banner", "-nostats",
+		"-i", inputPath,
+		"-vn",
+		"-af", "highpass=f=80,afftdn=nf=-25,dynaudnorm=f=150:g=15,alimiter=limit=0.95",
+		"-ar", "16000",
+		"-ac", "1",
+		"-c:a", "pcm_s16le",
+		"-y",
+		outputPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("audio enhancement failed: %w - %s", err, string(output))
+	}
+	return nil
+}
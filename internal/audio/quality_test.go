@@ -0,0 +1,56 @@
+package audio
+
+import "testing"
+
+const sampleAstatsOutput = `Input #0, wav, from 'meeting.wav':
+  Duration: 00:00:10.00, bitrate: 256 kb/s
+[Parsed_astats_0 @ 0x55d0c8a4e0c0] Channel: 1
+[Parsed_astats_0 @ 0x55d0c8a4e0c0] RMS level dB: -80.000000
+[Parsed_astats_0 @ 0x55d0c8a4e0c0] Overall
+[Parsed_astats_0 @ 0x55d0c8a4e0c0] Peak level dB: 0.000000
+[Parsed_astats_0 @ 0x55d0c8a4e0c0] RMS level dB: -22.500000
+[Parsed_astats_0 @ 0x55d0c8a4e0c0] Noise floor dB: -31.000000
+[Parsed_astats_0 @ 0x55d0c8a4e0c0] Peak count: 400
+[Parsed_astats_0 @ 0x55d0c8a4e0c0] Number of samples: 160000
+[Parsed_astats_0 @ 0x55d0c8a4e0c0] Min level: -inf
+`
+
+func TestParseAstatsReadsOverallSection(t *testing.T) {
+	stats := parseAstats(sampleAstatsOutput)
+
+	if got := stats["RMS level dB"]; got != -22.5 {
+		t.Errorf("expected overall RMS -22.5, got %v", got)
+	}
+	if got := stats["Number of samples"]; got != 160000 {
+		t.Errorf("expected 160000 samples, got %v", got)
+	}
+	if _, ok := stats["Min level"]; ok {
+		t.Error("expected non-finite values to be skipped")
+	}
+}
+
+func TestQualityReportEvaluate(t *testing.T) {
+	report := &QualityReport{
+		RMSLevelDb:     -22.5,
+		NoiseFloorDb:   -31,
+		SNRDb:          8.5,
+		ClippedSamples: 400,
+		ClippingRatio:  400.0 / 160000,
+		BitRate:        32000,
+		SampleRate:     8000,
+	}
+	report.evaluate()
+
+	if !report.Poor {
+		t.Error("expected noisy, clipping audio to be flagged as poor")
+	}
+	if len(report.Warnings) != 4 {
+		t.Errorf("expected 4 warnings, got %d: %v", len(report.Warnings), report.Warnings)
+	}
+
+	clean := &QualityReport{RMSLevelDb: -20, NoiseFloorDb: -70, SNRDb: 50, BitRate: 128000, SampleRate: 44100}
+	clean.evaluate()
+	if clean.Poor || len(clean.Warnings) != 0 {
+		t.Errorf("expected clean audio to pass, got %v", clean.Warnings)
+	}
+}
@@ -0,0 +1,98 @@
+// Package audit records security-relevant and data-changing actions to the audit log, and
+// deletes entries once they are past the retention period.
+package audit
+
+import (
+	"sync"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// pruneInterval is how often entries past the retention period are deleted
+const pruneInterval = time.Hour
+
+// Recorder appends entries to the audit log
+type Recorder struct {
+	retention time.Duration
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+// NewRecorder creates a recorder that keeps entries for retention (0 keeps them forever)
+func NewRecorder(retention time.Duration) *Recorder {
+	return &Recorder{retention: retention}
+}
+
+// Retention returns how long entries are kept; 0 is forever
+func (r *Recorder) Retention() time.Duration {
+	return r.retention
+}
+
+// Record appends an entry. A failure is logged rather than returned, since the action being
+// recorded has already happened.
+func (r *Recorder) Record(entry *models.AuditLog) {
+	if entry.Username == "" && entry.UserID != nil {
+		var user models.User
+		if err := database.DB.Select("id", "username").Where("id = ?", *entry.UserID).Limit(1).Find(&user).Error; err == nil {
+			entry.Username = user.Username
+		}
+	}
+	if err := database.DB.Create(entry).Error; err != nil {
+		logger.Error("Failed to record audit entry", "component", "audit", "action", entry.Action, "user_id", entry.UserID, "error", err)
+	}
+	r.prune()
+}
+
+// Query filters the audit log
+type Query struct {
+	UserID   *uint
+	Action   string
+	TargetID string
+	Since    time.Time
+	Until    time.Time
+}
+
+// Apply restricts db to the entries matching q
+func (q Query) Apply(db *gorm.DB) *gorm.DB {
+	if q.UserID != nil {
+		db = db.Where("user_id = ?", *q.UserID)
+	}
+	if q.Action != "" {
+		db = db.Where("action = ?", q.Action)
+	}
+	if q.TargetID != "" {
+		db = db.Where("target_id = ?", q.TargetID)
+	}
+	if !q.Since.IsZero() {
+		db = db.Where("created_at >= ?", q.Since)
+	}
+	if !q.Until.IsZero() {
+		db = db.Where("created_at < ?", q.Until)
+	}
+	return db
+}
+
+// prune deletes entries past the retention period, at most once per pruneInterval
+func (r *Recorder) prune() {
+	if r.retention <= 0 {
+		return
+	}
+	r.mu.Lock()
+	if time.Since(r.lastPrune) < pruneInterval {
+		r.mu.Unlock()
+		return
+	}
+	r.lastPrune = time.Now()
+	r.mu.Unlock()
+
+	cutoff := time.Now().Add(-r.retention)
+	if err := database.DB.Where("created_at < ?", cutoff).Delete(&models.AuditLog{}).Error; err != nil {
+		logger.Error("Failed to prune audit entries", "component", "audit", "error", err)
+	}
+}
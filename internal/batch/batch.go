@@ -0,0 +1,244 @@
+// Package batch applies an action, such as retagging or re-indexing, to many transcriptions in
+// the background, one at a time, and records the outcome for each on a BatchOperation as it
+// goes. Actions are registered by name by the packages that implement them.
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+)
+
+const (
+	// MaxItems caps the transcriptions of one operation
+	MaxItems = 5000
+	// saveEvery is how many transcriptions are done between saves of an operation's progress
+	saveEvery = 20
+)
+
+var (
+	// ErrUnknownAction is returned for an operation naming an action that isn't registered
+	ErrUnknownAction = errors.New("unknown action")
+	// ErrNotRunning is returned when cancelling an operation that has already stopped
+	ErrNotRunning = errors.New("batch operation is not running")
+)
+
+// Action is what an operation does to each of its transcriptions
+type Action struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Validate checks an operation's params before it starts; nil accepts any
+	Validate func(params *models.BatchParams) error `json:"-"`
+	// Apply does the work for one transcription of an operation. Returning Skip leaves the
+	// transcription alone without counting it as failed.
+	Apply func(ctx context.Context, op *models.BatchOperation, jobID string) error `json:"-"`
+}
+
+// skipError marks a transcription an action left alone
+type skipError struct{ reason string }
+
+func (e *skipError) Error() string { return e.reason }
+
+// Skip is returned by an action's Apply for a transcription it leaves alone, saying why
+func Skip(reason string) error {
+	return &skipError{reason: reason}
+}
+
+// Service runs batch operations in the background
+type Service struct {
+	mu      sync.Mutex
+	actions map[string]Action
+	running map[string]context.CancelFunc
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewService creates a batch service with no actions registered
+func NewService() *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Service{
+		actions: make(map[string]Action),
+		running: make(map[string]context.CancelFunc),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Register makes an action available to operations, replacing any of the same name
+func (s *Service) Register(action Action) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.actions[action.Name] = action
+}
+
+// Actions returns the registered actions by name
+func (s *Service) Actions() []Action {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	actions := make([]Action, 0, len(s.actions))
+	for _, action := range s.actions {
+		actions = append(actions, action)
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i].Name < actions[j].Name })
+	return actions
+}
+
+// Validate checks an operation's action and params before it is started
+func (s *Service) Validate(op *models.BatchOperation) error {
+	s.mu.Lock()
+	action, ok := s.actions[op.Action]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownAction, op.Action)
+	}
+	if action.Validate != nil {
+		return action.Validate(&op.Params)
+	}
+	return nil
+}
+
+// Start records an operation and applies its action to each of jobIDs in the background.
+// Results already on the operation, such as transcriptions that weren't found, are kept and
+// counted in its total.
+func (s *Service) Start(op *models.BatchOperation, jobIDs []string) error {
+	if err := s.Validate(op); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	action := s.actions[op.Action]
+	s.mu.Unlock()
+
+	op.Status = models.BatchRunning
+	op.Total = len(op.Results) + len(jobIDs)
+	op.StartedAt = time.Now()
+	if op.Results == nil {
+		op.Results = []models.BatchItemResult{}
+	}
+	if err := database.DB.Create(op).Error; err != nil {
+		return fmt.Errorf("failed to record batch operation: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.mu.Lock()
+	s.running[op.ID] = cancel
+	s.mu.Unlock()
+
+	// The goroutine works on its own copy, so the caller can respond with op
+	run := *op
+	run.Results = append([]models.BatchItemResult(nil), op.Results...)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.release(run.ID)
+		s.process(ctx, &run, action, jobIDs)
+	}()
+	return nil
+}
+
+// Cancel stops a running operation after the transcription it is working on
+func (s *Service) Cancel(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cancel, ok := s.running[id]
+	if !ok {
+		return ErrNotRunning
+	}
+	cancel()
+	return nil
+}
+
+// IsRunning reports whether an operation is in progress
+func (s *Service) IsRunning(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.running[id]
+	return ok
+}
+
+// CloseInterrupted marks operations left running by a restart as failed
+func (s *Service) CloseInterrupted() error {
+	return database.DB.Model(&models.BatchOperation{}).Where("status = ?", models.BatchRunning).
+		Updates(map[string]interface{}{"status": models.BatchFailed, "error": "interrupted by a restart", "completed_at": time.Now()}).Error
+}
+
+// Stop cancels running operations and waits for them to save their progress
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Service) release(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cancel, ok := s.running[id]; ok {
+		cancel()
+		delete(s.running, id)
+	}
+}
+
+// process applies the action to each transcription in turn, saving progress every few
+func (s *Service) process(ctx context.Context, op *models.BatchOperation, action Action, jobIDs []string) {
+	for i, jobID := range jobIDs {
+		if ctx.Err() != nil {
+			break
+		}
+		result := models.BatchItemResult{TranscriptionID: jobID, Status: models.BatchItemSucceeded}
+		err := apply(ctx, action, op, jobID)
+		var skip *skipError
+		switch {
+		case err == nil:
+			op.Succeeded++
+		case errors.As(err, &skip):
+			result.Status, result.Message = models.BatchItemSkipped, skip.reason
+			op.Skipped++
+		default:
+			result.Status, result.Message = models.BatchItemFailed, err.Error()
+			op.Failed++
+			logger.WarnContext(ctx, "Batch item failed", "component", "batch", "action", op.Action, "operation_id", op.ID, "job_id", jobID, "error", err)
+		}
+		op.Results = append(op.Results, result)
+		if (i+1)%saveEvery == 0 {
+			s.save(op)
+		}
+	}
+
+	now := time.Now()
+	op.CompletedAt = &now
+	op.Status = models.BatchCompleted
+	if ctx.Err() != nil && len(op.Results) < op.Total {
+		op.Status = models.BatchCancelled
+		if s.ctx.Err() != nil {
+			message := "interrupted by a shutdown"
+			op.Status, op.Error = models.BatchFailed, &message
+		}
+	}
+	s.save(op)
+	logger.InfoContext(ctx, "Batch operation finished", "component", "batch", "action", op.Action, "status", op.Status,
+		"operation_id", op.ID, "total", op.Total, "succeeded", op.Succeeded, "failed", op.Failed, "skipped", op.Skipped)
+}
+
+// apply runs an action on one transcription, turning a panic into its failure so the rest of
+// the operation goes on
+func apply(ctx context.Context, action Action, op *models.BatchOperation, jobID string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return action.Apply(ctx, op, jobID)
+}
+
+func (s *Service) save(op *models.BatchOperation) {
+	if err := database.DB.Save(op).Error; err != nil {
+		logger.Error("Failed to save batch operation", "component", "batch", "operation_id", op.ID, "error", err)
+	}
+}
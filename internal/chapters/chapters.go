@@ -0,0 +1,195 @@
+// Package chapters splits a transcript into chapters where its topic shifts. The transcript
+// is cut into windows of about a minute, each window is embedded, and chapter boundaries go
+// where the similarity between the windows before and after a gap dips deepest (TextTiling).
+package chapters
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+const (
+	// WindowSeconds is the length of the transcript windows that are embedded and compared
+	WindowSeconds = 60
+	// MinChapterWindows is the length of the shortest chapter, in windows
+	MinChapterWindows = 3
+	// MaxChapters caps the chapters of one recording
+	MaxChapters = 20
+
+	// blockWindows is how many windows on each side of a gap are averaged to compare them
+	blockWindows = 2
+	// minDepth is the smallest similarity dip taken for a topic shift
+	minDepth = 0.05
+)
+
+// Window is a run of consecutive transcript segments
+type Window struct {
+	Start float64
+	End   float64
+	Text  string
+}
+
+// Span is one chapter: a run of windows
+type Span struct {
+	Start float64
+	End   float64
+	Text  string
+}
+
+// Windows groups segments into windows of about seconds each. A segment is never split, so a
+// window runs until the first segment starting seconds or more after the window's start.
+func Windows(segments []interfaces.TranscriptSegment, seconds float64) []Window {
+	var windows []Window
+	for _, segment := range segments {
+		segmentText := strings.TrimSpace(segment.Text)
+		if segmentText == "" {
+			continue
+		}
+		if len(windows) > 0 && segment.Start-windows[len(windows)-1].Start < seconds {
+			current := &windows[len(windows)-1]
+			current.Text += " " + segmentText
+			current.End = math.Max(current.End, segment.End)
+			continue
+		}
+		windows = append(windows, Window{Start: segment.Start, End: segment.End, Text: segmentText})
+	}
+	return windows
+}
+
+// Boundaries returns the indexes of the windows that start a new chapter, in order; the first
+// chapter always starts at window 0, which is not included. Chapters are at least minWindows
+// long, and there are at most maxChapters of them.
+func Boundaries(vectors [][]float32, minWindows, maxChapters int) []int {
+	n := len(vectors)
+	if minWindows < 1 {
+		minWindows = 1
+	}
+	if n < 2*minWindows || maxChapters < 2 {
+		return []int{}
+	}
+
+	// similarity[i] compares the windows before gap i with those from i on
+	similarity := make([]float64, n)
+	for i := 1; i < n; i++ {
+		before := mean(vectors[max(0, i-blockWindows):i])
+		after := mean(vectors[i:min(n, i+blockWindows)])
+		similarity[i] = cosine(before, after)
+	}
+
+	// A gap's depth is how far its similarity falls below the peaks on either side
+	depths := make([]float64, n)
+	var sum float64
+	for i := 1; i < n; i++ {
+		left := similarity[i]
+		for j := i - 1; j >= 1 && similarity[j] >= left; j-- {
+			left = similarity[j]
+		}
+		right := similarity[i]
+		for j := i + 1; j < n && similarity[j] >= right; j++ {
+			right = similarity[j]
+		}
+		depths[i] = (left - similarity[i]) + (right - similarity[i])
+		sum += depths[i]
+	}
+	average := sum / float64(n-1)
+	var variance float64
+	for i := 1; i < n; i++ {
+		variance += (depths[i] - average) * (depths[i] - average)
+	}
+	threshold := math.Max(average+math.Sqrt(variance/float64(n-1))/2, minDepth)
+
+	candidates := make([]int, 0, n)
+	for i := minWindows; i <= n-minWindows; i++ {
+		if depths[i] >= threshold {
+			candidates = append(candidates, i)
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool { return depths[candidates[a]] > depths[candidates[b]] })
+
+	boundaries := []int{}
+	for _, candidate := range candidates {
+		if len(boundaries) == maxChapters-1 {
+			break
+		}
+		tooClose := false
+		for _, boundary := range boundaries {
+			if absInt(candidate-boundary) < minWindows {
+				tooClose = true
+				break
+			}
+		}
+		if !tooClose {
+			boundaries = append(boundaries, candidate)
+		}
+	}
+	sort.Ints(boundaries)
+	return boundaries
+}
+
+// Split joins windows into chapters starting at the given boundaries. The first chapter
+// starts at 0 and each chapter ends where the next one starts, so the chapters cover the
+// recording without gaps.
+func Split(windows []Window, boundaries []int) []Span {
+	if len(windows) == 0 {
+		return []Span{}
+	}
+	starts := append([]int{0}, boundaries...)
+	spans := make([]Span, 0, len(starts))
+	for i, first := range starts {
+		last := len(windows)
+		if i+1 < len(starts) {
+			last = starts[i+1]
+		}
+		texts := make([]string, 0, last-first)
+		for _, window := range windows[first:last] {
+			texts = append(texts, window.Text)
+		}
+		span := Span{Start: windows[first].Start, End: windows[last-1].End, Text: strings.Join(texts, " ")}
+		if i == 0 {
+			span.Start = 0
+		} else {
+			spans[i-1].End = span.Start
+		}
+		spans = append(spans, span)
+	}
+	return spans
+}
+
+// mean averages vectors
+func mean(vectors [][]float32) []float64 {
+	sum := make([]float64, len(vectors[0]))
+	for _, vector := range vectors {
+		for d, x := range vector {
+			sum[d] += float64(x)
+		}
+	}
+	for d := range sum {
+		sum[d] /= float64(len(vectors))
+	}
+	return sum
+}
+
+// cosine returns the cosine similarity of two vectors, 0 if either is zero
+func cosine(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+// absInt returns the absolute value of a
+func absInt(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
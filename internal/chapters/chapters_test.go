@@ -0,0 +1,81 @@
+package chapters
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+// topicRun returns n noisy vectors around center
+func topicRun(rng *rand.Rand, center []float32, n int) [][]float32 {
+	vectors := make([][]float32, n)
+	for i := range vectors {
+		v := make([]float32, len(center))
+		for d, x := range center {
+			v[d] = x + float32(rng.NormFloat64()*0.05)
+		}
+		vectors[i] = v
+	}
+	return vectors
+}
+
+func TestBoundariesFindTopicShifts(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	var vectors [][]float32
+	vectors = append(vectors, topicRun(rng, []float32{1, 0, 0}, 6)...)
+	vectors = append(vectors, topicRun(rng, []float32{0, 1, 0}, 5)...)
+	vectors = append(vectors, topicRun(rng, []float32{0, 0, 1}, 7)...)
+
+	if got := Boundaries(vectors, MinChapterWindows, MaxChapters); !reflect.DeepEqual(got, []int{6, 11}) {
+		t.Fatalf("expected boundaries at 6 and 11, got %v", got)
+	}
+	if got := Boundaries(vectors, MinChapterWindows, 2); len(got) != 1 {
+		t.Fatalf("expected one boundary with maxChapters 2, got %v", got)
+	}
+}
+
+func TestBoundariesKeepChaptersApart(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	var vectors [][]float32
+	vectors = append(vectors, topicRun(rng, []float32{1, 0}, 5)...)
+	vectors = append(vectors, topicRun(rng, []float32{0, 1}, 1)...)
+	vectors = append(vectors, topicRun(rng, []float32{1, 0}, 5)...)
+
+	for _, boundary := range Boundaries(vectors, MinChapterWindows, MaxChapters) {
+		if boundary < MinChapterWindows || len(vectors)-boundary < MinChapterWindows {
+			t.Fatalf("boundary %d leaves a chapter shorter than %d windows", boundary, MinChapterWindows)
+		}
+	}
+	if got := Boundaries(topicRun(rng, []float32{1, 0}, 12), MinChapterWindows, MaxChapters); len(got) != 0 {
+		t.Fatalf("expected no boundaries in a single-topic transcript, got %v", got)
+	}
+	if got := Boundaries(topicRun(rng, []float32{1, 0}, 4), MinChapterWindows, MaxChapters); len(got) != 0 {
+		t.Fatalf("expected no boundaries in a transcript too short for two chapters, got %v", got)
+	}
+}
+
+func TestWindowsAndSplit(t *testing.T) {
+	segments := []interfaces.TranscriptSegment{
+		{Start: 3, End: 20, Text: "one"},
+		{Start: 25, End: 70, Text: "two"},
+		{Start: 70, End: 80, Text: "  "},
+		{Start: 75, End: 100, Text: "three"},
+		{Start: 140, End: 150, Text: "four"},
+	}
+	windows := Windows(segments, 60)
+	want := []Window{{Start: 3, End: 70, Text: "one two"}, {Start: 75, End: 100, Text: "three"}, {Start: 140, End: 150, Text: "four"}}
+	if !reflect.DeepEqual(windows, want) {
+		t.Fatalf("unexpected windows %+v", windows)
+	}
+
+	spans := Split(windows, []int{1})
+	wantSpans := []Span{{Start: 0, End: 75, Text: "one two"}, {Start: 75, End: 150, Text: "three four"}}
+	if !reflect.DeepEqual(spans, wantSpans) {
+		t.Fatalf("unexpected spans %+v", spans)
+	}
+	if len(Split(nil, nil)) != 0 {
+		t.Fatal("expected no spans without windows")
+	}
+}
@@ -0,0 +1,137 @@
+// Package cli is the scriberr command line client: it uploads recordings, follows their
+// transcription, fetches transcripts and summaries and queries RAG on a Scriberr server
+// through its API, for scripts and headless machines.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// DefaultServer is the server the client talks to unless told otherwise
+const DefaultServer = "http://localhost:8080"
+
+// Environment variables the client reads its settings from
+const (
+	EnvServer = "SCRIBERR_URL"
+	EnvAPIKey = "SCRIBERR_API_KEY"
+)
+
+// usageError is an error in how a command was called, which exits with status 2
+type usageError struct {
+	err error
+}
+
+func (e usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e usageError) Unwrap() error {
+	return e.err
+}
+
+// usageArgs checks a command's positional arguments with validate, as usage errors
+func usageArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := validate(cmd, args); err != nil {
+			return usageError{err}
+		}
+		return nil
+	}
+}
+
+// env is what a command runs with
+type env struct {
+	client *Client
+	stdout io.Writer
+	stderr io.Writer
+	json   bool // Print the server's JSON rather than text
+}
+
+// Run runs the client with the arguments after the program name and returns the exit status
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	root := newRootCommand(stdout, stderr)
+	root.SetArgs(args)
+	cmd, err := root.ExecuteContextC(ctx)
+	if err == nil {
+		return 0
+	}
+	fmt.Fprintf(stderr, "%s: %v\n", cmd.CommandPath(), err)
+	// The root command only fails on unknown commands and flags
+	var usageErr usageError
+	if errors.As(err, &usageErr) || cmd == root {
+		fmt.Fprintln(stderr)
+		cmd.SetOut(stderr)
+		cmd.Usage()
+		return 2
+	}
+	return 1
+}
+
+// newRootCommand builds the client's commands, with the server, API key and output format
+// every command takes
+func newRootCommand(stdout, stderr io.Writer) *cobra.Command {
+	e := &env{stdout: stdout, stderr: stderr}
+	var server, apiKey string
+	root := &cobra.Command{
+		Use:   "scriberr",
+		Short: "Command line client for a Scriberr server",
+		Long: "scriberr uploads recordings, follows their transcription, fetches transcripts and summaries\n" +
+			"and queries RAG on a running Scriberr server, through its API.",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			e.client = NewClient(server, apiKey)
+		},
+	}
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError{err}
+	})
+
+	defaultServer := os.Getenv(EnvServer)
+	if defaultServer == "" {
+		defaultServer = DefaultServer
+	}
+	flags := root.PersistentFlags()
+	flags.StringVar(&server, "server", defaultServer, "Scriberr server URL (or "+EnvServer+")")
+	flags.StringVar(&apiKey, "api-key", os.Getenv(EnvAPIKey), "API key (or "+EnvAPIKey+")")
+	flags.BoolVar(&e.json, "json", false, "Print the server's JSON response")
+
+	root.AddCommand(
+		newUploadCommand(e),
+		newWatchCommand(e),
+		newStatusCommand(e),
+		newListCommand(e),
+		newTranscriptCommand(e),
+		newSummaryCommand(e),
+		newAskCommand(e),
+		newSearchCommand(e),
+		newBackfillCommand(e),
+	)
+	return root
+}
+
+// printJSON prints a value as indented JSON
+func printJSON(w io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// queryArg joins a command's arguments into one query, so it needn't be quoted
+func queryArg(args []string) (string, error) {
+	query := strings.TrimSpace(strings.Join(args, " "))
+	if query == "" {
+		return "", usageError{errors.New("the query is empty")}
+	}
+	return query, nil
+}
@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testKey = "test-key"
+
+// fakeServer answers the API calls the client makes, for one transcription "job-1"
+type fakeServer struct {
+	mu       sync.Mutex
+	statuses []string // Statuses the job reports, in turn; the last one sticks
+	uploaded map[string]string
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-API-Key") != testKey {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid API key"})
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	write := func(v interface{}) { json.NewEncoder(w).Encode(v) }
+
+	switch r.Method + " " + r.URL.Path {
+	case "POST /api/v1/transcription/submit":
+		file, header, err := r.FormFile("audio")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			write(map[string]string{"error": "Audio file is required"})
+			return
+		}
+		data, _ := io.ReadAll(file)
+		f.uploaded = map[string]string{"file": header.Filename, "data": string(data), "title": r.FormValue("title"), "diarization": r.FormValue("diarization")}
+		write(map[string]interface{}{"id": "job-1", "status": "pending", "audio_path": "data/uploads/job-1.mp3"})
+	case "GET /api/v1/transcription/job-1/status":
+		status := f.statuses[0]
+		if len(f.statuses) > 1 {
+			f.statuses = f.statuses[1:]
+		}
+		job := map[string]interface{}{"id": "job-1", "title": "Standup", "status": status}
+		if status == statusFailed {
+			job["error_message"] = "out of memory"
+		}
+		write(job)
+	case "GET /api/v1/transcription/job-1/transcript":
+		write(map[string]interface{}{"transcript": map[string]interface{}{
+			"text": "Hello. Hi.",
+			"segments": []map[string]interface{}{
+				{"start": 0, "text": " Hello.", "speaker": "Ana"},
+				{"start": 65.5, "text": " Hi.", "speaker": "Ben"},
+			},
+		}})
+	case "GET /api/v1/transcription/job-1/export":
+		w.Write([]byte("WEBVTT\n\n" + r.URL.Query().Get("format")))
+	case "POST /api/v1/rag/chat":
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		write(map[string]interface{}{"response": "You asked: " + req["query"].(string), "sources": []string{"job-1"}})
+	case "POST /api/v1/rag/repair":
+		write(map[string]interface{}{"missing": 2, "processed": 1, "failed": 1})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		write(map[string]string{"error": "Job not found"})
+	}
+}
+
+// run runs the client against server and returns its exit status and output
+func run(t *testing.T, server *httptest.Server, args ...string) (int, string, string) {
+	t.Helper()
+	args = append([]string{"--server", server.URL, "--api-key", testKey}, args...)
+	var stdout, stderr bytes.Buffer
+	status := Run(context.Background(), args, &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+func TestUploadAndWait(t *testing.T) {
+	fake := &fakeServer{statuses: []string{"pending", "processing", "processing", "completed"}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "standup.mp3")
+	if err := os.WriteFile(file, []byte("audio"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	status, stdout, stderr := run(t, server, "upload", file, "--title", "Standup", "--diarize", "--wait", "--interval", "1ms")
+	if status != 0 {
+		t.Fatalf("status %d: %s", status, stderr)
+	}
+	wantUploaded := map[string]string{"file": "standup.mp3", "data": "audio", "title": "Standup", "diarization": "true"}
+	if !reflect.DeepEqual(fake.uploaded, wantUploaded) {
+		t.Errorf("uploaded %v, want %v", fake.uploaded, wantUploaded)
+	}
+	want := "job-1\t" + file + "\njob-1\tpending\tStandup\njob-1\tprocessing\tStandup\njob-1\tcompleted\tStandup\n"
+	if stdout != want {
+		t.Errorf("output %q, want %q", stdout, want)
+	}
+}
+
+func TestWatchFailed(t *testing.T) {
+	server := httptest.NewServer(&fakeServer{statuses: []string{"processing", "failed"}})
+	defer server.Close()
+
+	status, stdout, stderr := run(t, server, "watch", "--interval", "1ms", "job-1")
+	if status != 1 {
+		t.Errorf("status %d, want 1", status)
+	}
+	if !strings.Contains(stdout, "job-1\tfailed\tStandup\tout of memory") {
+		t.Errorf("output %q lacks the failure", stdout)
+	}
+	if !strings.Contains(stderr, "1 of the transcriptions failed") {
+		t.Errorf("error %q", stderr)
+	}
+}
+
+func TestTranscript(t *testing.T) {
+	server := httptest.NewServer(&fakeServer{})
+	defer server.Close()
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"transcript", "job-1"}, "Ana: Hello.\nBen: Hi.\n"},
+		{[]string{"transcript", "job-1", "--timestamps"}, "[00:00:00] Ana: Hello.\n[00:01:05] Ben: Hi.\n"},
+		{[]string{"transcript", "--format", "vtt", "job-1"}, "WEBVTT\n\nvtt"},
+	}
+	for _, tt := range tests {
+		status, stdout, stderr := run(t, server, tt.args...)
+		if status != 0 {
+			t.Errorf("%v: status %d: %s", tt.args, status, stderr)
+			continue
+		}
+		if stdout != tt.want {
+			t.Errorf("%v: output %q, want %q", tt.args, stdout, tt.want)
+		}
+	}
+
+	output := filepath.Join(t.TempDir(), "transcript.json")
+	if status, _, stderr := run(t, server, "transcript", "job-1", "--format", "json", "-o", output); status != 0 {
+		t.Fatalf("status %d: %s", status, stderr)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(data) || !strings.Contains(string(data), `"speaker": "Ana"`) {
+		t.Errorf("JSON output %s", data)
+	}
+}
+
+func TestRAG(t *testing.T) {
+	server := httptest.NewServer(&fakeServer{})
+	defer server.Close()
+
+	status, stdout, stderr := run(t, server, "ask", "what", "was", "decided?")
+	if status != 0 {
+		t.Fatalf("status %d: %s", status, stderr)
+	}
+	if want := "You asked: what was decided?\n\nSources: job-1\n"; stdout != want {
+		t.Errorf("output %q, want %q", stdout, want)
+	}
+
+	// Flags may come between the words of a question, and words after "--" are never flags
+	status, stdout, stderr = run(t, server, "ask", "what", "--tag", "standup", "--", "--json", "means?")
+	if status != 0 || stdout != "You asked: what --json means?\n\nSources: job-1\n" {
+		t.Errorf("status %d, output %q, error %q", status, stdout, stderr)
+	}
+
+	status, stdout, _ = run(t, server, "backfill", "--repair")
+	if status != 1 || stdout != "2 missing, 1 indexed, 1 failed\n" {
+		t.Errorf("status %d, output %q", status, stdout)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	server := httptest.NewServer(&fakeServer{})
+	defer server.Close()
+
+	status, _, stderr := run(t, server, "status", "missing")
+	if status != 1 || stderr != "scriberr status: missing: Job not found (HTTP 404)\n" {
+		t.Errorf("status %d, error %q", status, stderr)
+	}
+
+	var stdout, errOut bytes.Buffer
+	if status := Run(context.Background(), []string{"status", "--server", server.URL, "job-1"}, &stdout, &errOut); status != 1 || !strings.Contains(errOut.String(), "Invalid API key (HTTP 401)") {
+		t.Errorf("without a key: status %d, error %q", status, errOut.String())
+	}
+
+	if status, _, stderr := run(t, server, "summary"); status != 2 || !strings.Contains(stderr, "scriberr summary ID [flags]") {
+		t.Errorf("without an ID: status %d, error %q", status, stderr)
+	}
+	if status, _, _ := run(t, server, "transcript", "job-1", "--format", "odt"); status != 1 {
+		t.Errorf("unknown format: status %d", status)
+	}
+	if status := Run(context.Background(), []string{"frobnicate"}, &stdout, &errOut); status != 2 {
+		t.Errorf("unknown command: status %d", status)
+	}
+	if status, _, _ := run(t, server, "list", "--frobnicate"); status != 2 {
+		t.Errorf("unknown flag: status %d", status)
+	}
+	if status, stdout, _ := run(t, server, "help", "summary"); status != 0 || !strings.Contains(stdout, "Print a transcription's summary") {
+		t.Errorf("help: status %d, output %q", status, stdout)
+	}
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/export"
+
+	"github.com/spf13/cobra"
+)
+
+// newAskCommand builds the command that asks RAG a question
+func newAskCommand(e *env) *cobra.Command {
+	var model, tag string
+	var verify bool
+	cmd := &cobra.Command{
+		Use:   "ask QUESTION...",
+		Short: "Ask a question answered from your transcripts",
+		Args:  usageArgs(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query, err := queryArg(args)
+			if err != nil {
+				return err
+			}
+			req := map[string]interface{}{"query": query, "verify": verify}
+			if model != "" {
+				req["model"] = model
+			}
+			if tag != "" {
+				req["tags"] = []string{tag}
+			}
+			var raw json.RawMessage
+			if err := e.client.postJSON(cmd.Context(), "/api/v1/rag/chat", req, &raw); err != nil {
+				return err
+			}
+			if e.json {
+				return printJSON(e.stdout, raw)
+			}
+			var answer struct {
+				Response          string   `json:"response"`
+				Sources           []string `json:"sources"`
+				NoRelevantContext bool     `json:"no_relevant_context"`
+			}
+			if err := json.Unmarshal(raw, &answer); err != nil {
+				return fmt.Errorf("failed to read the server's response: %w", err)
+			}
+			fmt.Fprintln(e.stdout, strings.TrimSpace(answer.Response))
+			if len(answer.Sources) > 0 && !answer.NoRelevantContext {
+				fmt.Fprintf(e.stdout, "\nSources: %s\n", strings.Join(answer.Sources, ", "))
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&model, "model", "", "Chat model (default the server's)")
+	flags.BoolVar(&verify, "verify", false, "Check the answer against the transcripts it came from")
+	flags.StringVar(&tag, "tag", "", "Only use transcriptions with this tag")
+	return cmd
+}
+
+// newSearchCommand builds the command that searches the transcripts by meaning
+func newSearchCommand(e *env) *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "search QUERY...",
+		Short: "Find the transcript passages closest in meaning to a query",
+		Args:  usageArgs(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query, err := queryArg(args)
+			if err != nil {
+				return err
+			}
+			var raw json.RawMessage
+			if err := e.client.postJSON(cmd.Context(), "/api/v1/rag/search", map[string]interface{}{"query": query, "limit": limit}, &raw); err != nil {
+				return err
+			}
+			if e.json {
+				return printJSON(e.stdout, raw)
+			}
+			var found struct {
+				Results []struct {
+					TranscriptionID string  `json:"transcription_id"`
+					Title           *string `json:"title"`
+					Start           float64 `json:"start"`
+					Speaker         string  `json:"speaker"`
+					SpeakerName     string  `json:"speaker_name"`
+					Snippet         string  `json:"snippet"`
+				} `json:"results"`
+			}
+			if err := json.Unmarshal(raw, &found); err != nil {
+				return fmt.Errorf("failed to read the server's response: %w", err)
+			}
+			if len(found.Results) == 0 {
+				fmt.Fprintln(e.stdout, "No matching passages")
+				return nil
+			}
+			for i, result := range found.Results {
+				if i > 0 {
+					fmt.Fprintln(e.stdout)
+				}
+				heading := result.TranscriptionID
+				if result.Title != nil && *result.Title != "" {
+					heading += " " + *result.Title
+				}
+				fmt.Fprintf(e.stdout, "%s [%s]\n", heading, export.Timestamp(result.Start))
+				snippet := strings.TrimSpace(result.Snippet)
+				if speaker := firstNonEmpty(result.SpeakerName, result.Speaker); speaker != "" {
+					snippet = speaker + ": " + snippet
+				}
+				fmt.Fprintf(e.stdout, "  %s\n", snippet)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 10, "How many passages to return, up to 50")
+	return cmd
+}
+
+// newBackfillCommand builds the command that indexes transcriptions for RAG
+func newBackfillCommand(e *env) *cobra.Command {
+	var repair bool
+	cmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "Index completed transcriptions for RAG",
+		Args:  usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "/api/v1/rag/backfill"
+			if repair {
+				path = "/api/v1/rag/repair"
+			}
+			var raw json.RawMessage
+			if err := e.client.postJSON(cmd.Context(), path, nil, &raw); err != nil {
+				return err
+			}
+			if e.json {
+				return printJSON(e.stdout, raw)
+			}
+			var result struct {
+				Total     int `json:"total"`
+				Missing   int `json:"missing"`
+				Processed int `json:"processed"`
+				Failed    int `json:"failed"`
+			}
+			if err := json.Unmarshal(raw, &result); err != nil {
+				return fmt.Errorf("failed to read the server's response: %w", err)
+			}
+			if repair {
+				fmt.Fprintf(e.stdout, "%d missing, %d indexed, %d failed\n", result.Missing, result.Processed, result.Failed)
+			} else {
+				fmt.Fprintf(e.stdout, "%d completed, %d indexed, %d failed\n", result.Total, result.Processed, result.Failed)
+			}
+			if result.Failed > 0 {
+				return fmt.Errorf("%d transcriptions failed to index", result.Failed)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&repair, "repair", false, "Only index the transcriptions missing from the index")
+	return cmd
+}
+
+// firstNonEmpty returns the first of values that isn't empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,373 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"scriberr/internal/export"
+
+	"github.com/spf13/cobra"
+)
+
+// Job statuses the client acts on
+const (
+	statusCompleted = "completed"
+	statusFailed    = "failed"
+)
+
+// job is what the client reads of a transcription
+type job struct {
+	ID           string    `json:"id"`
+	Title        *string   `json:"title"`
+	Status       string    `json:"status"`
+	AudioPath    string    `json:"audio_path"`
+	ErrorMessage *string   `json:"error_message"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// title is the job's title, or its audio file name when it has none
+func (j *job) title() string {
+	if j.Title != nil && *j.Title != "" {
+		return *j.Title
+	}
+	return filepath.Base(j.AudioPath)
+}
+
+// getJob gets a transcription's status, keeping the server's JSON for -json output
+func getJob(ctx context.Context, client *Client, id string) (*job, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.getJSON(ctx, "/api/v1/transcription/"+url.PathEscape(id)+"/status", &raw); err != nil {
+		return nil, nil, err
+	}
+	var j job
+	if err := json.Unmarshal(raw, &j); err != nil {
+		return nil, nil, fmt.Errorf("failed to read the server's response: %w", err)
+	}
+	return &j, raw, nil
+}
+
+// newUploadCommand builds the command that uploads recordings
+func newUploadCommand(e *env) *cobra.Command {
+	var title, language, model string
+	var diarize, wait bool
+	var interval time.Duration
+	cmd := &cobra.Command{
+		Use:   "upload FILE...",
+		Short: "Upload recordings and start transcribing them",
+		Args:  usageArgs(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			fields := map[string]string{}
+			if title != "" {
+				fields["title"] = title
+			}
+			if language != "" {
+				fields["language"] = language
+			}
+			if model != "" {
+				fields["model"] = model
+			}
+			if diarize {
+				fields["diarization"] = "true"
+			}
+
+			ids := make([]string, 0, len(args))
+			for _, file := range args {
+				var raw json.RawMessage
+				if err := e.client.upload(ctx, "/api/v1/transcription/submit", "audio", file, fields, &raw); err != nil {
+					return fmt.Errorf("%s: %w", file, err)
+				}
+				var j job
+				if err := json.Unmarshal(raw, &j); err != nil {
+					return fmt.Errorf("%s: failed to read the server's response: %w", file, err)
+				}
+				ids = append(ids, j.ID)
+				if e.json {
+					if err := printJSON(e.stdout, raw); err != nil {
+						return err
+					}
+				} else {
+					fmt.Fprintf(e.stdout, "%s\t%s\n", j.ID, file)
+				}
+			}
+			if wait {
+				return watch(ctx, e, ids, interval)
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&title, "title", "", "Title of the transcription (default the file name)")
+	flags.StringVar(&language, "language", "", "Language code of the recording, e.g. en (default detect)")
+	flags.StringVar(&model, "model", "", "Whisper model (default the server's)")
+	flags.BoolVar(&diarize, "diarize", false, "Tell the speakers apart")
+	flags.BoolVar(&wait, "wait", false, "Follow the transcriptions until they finish")
+	flags.DurationVar(&interval, "interval", 2*time.Second, "How often to check on the transcriptions with --wait")
+	return cmd
+}
+
+// newWatchCommand builds the command that follows transcriptions until they finish
+func newWatchCommand(e *env) *cobra.Command {
+	var interval time.Duration
+	cmd := &cobra.Command{
+		Use:   "watch ID...",
+		Short: "Follow transcriptions until they finish, printing each change of status",
+		Args:  usageArgs(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return watch(cmd.Context(), e, args, interval)
+		},
+	}
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "How often to check on the transcriptions")
+	return cmd
+}
+
+// watch polls transcriptions until each has completed or failed, printing their changes of
+// status, or with --json their final state. It fails if any of them did.
+func watch(ctx context.Context, e *env, ids []string, interval time.Duration) error {
+	if interval <= 0 {
+		return errors.New("interval must be positive")
+	}
+	last := map[string]string{}
+	failed := 0
+	for {
+		var waiting []string
+		for _, id := range ids {
+			j, raw, err := getJob(ctx, e.client, id)
+			if err != nil {
+				return fmt.Errorf("%s: %w", id, err)
+			}
+			done := j.Status == statusCompleted || j.Status == statusFailed
+			if e.json {
+				if done {
+					if err := printJSON(e.stdout, raw); err != nil {
+						return err
+					}
+				}
+			} else if last[id] != j.Status {
+				line := fmt.Sprintf("%s\t%s\t%s", j.ID, j.Status, j.title())
+				if j.Status == statusFailed && j.ErrorMessage != nil {
+					line += "\t" + *j.ErrorMessage
+				}
+				fmt.Fprintln(e.stdout, line)
+			}
+			last[id] = j.Status
+			switch {
+			case j.Status == statusFailed:
+				failed++
+			case !done:
+				waiting = append(waiting, id)
+			}
+		}
+		if len(waiting) == 0 {
+			break
+		}
+		ids = waiting
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of the transcriptions failed", failed)
+	}
+	return nil
+}
+
+// newStatusCommand builds the command that shows the status of transcriptions
+func newStatusCommand(e *env) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status ID...",
+		Short: "Show the status of transcriptions",
+		Args:  usageArgs(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, id := range args {
+				j, raw, err := getJob(cmd.Context(), e.client, id)
+				if err != nil {
+					return fmt.Errorf("%s: %w", id, err)
+				}
+				if e.json {
+					if err := printJSON(e.stdout, raw); err != nil {
+						return err
+					}
+					continue
+				}
+				fmt.Fprintf(e.stdout, "%s\t%s\t%s\n", j.ID, j.Status, j.title())
+			}
+			return nil
+		},
+	}
+}
+
+// newListCommand builds the command that lists transcriptions
+func newListCommand(e *env) *cobra.Command {
+	var status, query string
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List transcriptions, newest first",
+		Args:  usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := url.Values{"limit": {strconv.Itoa(limit)}}
+			if status != "" {
+				params.Set("status", status)
+			}
+			if query != "" {
+				params.Set("q", query)
+			}
+			var raw json.RawMessage
+			if err := e.client.getJSON(cmd.Context(), "/api/v1/transcription/list?"+params.Encode(), &raw); err != nil {
+				return err
+			}
+			if e.json {
+				return printJSON(e.stdout, raw)
+			}
+			var list struct {
+				Jobs []job `json:"jobs"`
+			}
+			if err := json.Unmarshal(raw, &list); err != nil {
+				return fmt.Errorf("failed to read the server's response: %w", err)
+			}
+			w := tabwriter.NewWriter(e.stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tSTATUS\tCREATED\tTITLE")
+			for i := range list.Jobs {
+				j := &list.Jobs[i]
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", j.ID, j.Status, j.CreatedAt.Local().Format("2006-01-02 15:04"), j.title())
+			}
+			return w.Flush()
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&status, "status", "", "Only transcriptions with this status, e.g. completed")
+	flags.StringVarP(&query, "query", "q", "", "Only transcriptions whose title or file name contains this")
+	flags.IntVar(&limit, "limit", 20, "How many to list, up to 1000")
+	return cmd
+}
+
+// Transcript formats besides the server's export formats
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// newTranscriptCommand builds the command that prints a transcript
+func newTranscriptCommand(e *env) *cobra.Command {
+	var format, output string
+	var timestamps bool
+	cmd := &cobra.Command{
+		Use:   "transcript ID",
+		Short: "Print a transcript as text, JSON, subtitles or a document",
+		Args:  usageArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			out := e.stdout
+			if output != "" {
+				f, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				out = f
+			}
+			path := "/api/v1/transcription/" + url.PathEscape(args[0])
+
+			switch format {
+			case formatText, formatJSON:
+				var raw json.RawMessage
+				if err := e.client.getJSON(ctx, path+"/transcript", &raw); err != nil {
+					return err
+				}
+				if format == formatJSON || e.json {
+					return printJSON(out, raw)
+				}
+				return writeTranscriptText(out, raw, timestamps)
+			case export.FormatSRT, export.FormatVTT, export.FormatMarkdown, export.FormatDOCX, export.FormatPDF:
+				body, err := e.client.download(ctx, path+"/export?format="+format)
+				if err != nil {
+					return err
+				}
+				defer body.Close()
+				_, err = io.Copy(out, body)
+				return err
+			default:
+				return fmt.Errorf("unknown format %q: use text, json, srt, vtt, markdown, docx or pdf", format)
+			}
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&format, "format", formatText, "text, json, srt, vtt, markdown, docx or pdf")
+	flags.StringVarP(&output, "output", "o", "", "Write to this file instead of standard output")
+	flags.BoolVar(&timestamps, "timestamps", false, "Start each line with its time, for text")
+	return cmd
+}
+
+// writeTranscriptText writes a transcript as one line per segment, with its speaker and
+// optionally its time
+func writeTranscriptText(w io.Writer, raw json.RawMessage, timestamps bool) error {
+	var resp struct {
+		Transcript struct {
+			Text     string `json:"text"`
+			Segments []struct {
+				Start   float64 `json:"start"`
+				Text    string  `json:"text"`
+				Speaker string  `json:"speaker"`
+			} `json:"segments"`
+		} `json:"transcript"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return fmt.Errorf("failed to read the transcript: %w", err)
+	}
+	if len(resp.Transcript.Segments) == 0 {
+		_, err := fmt.Fprintln(w, strings.TrimSpace(resp.Transcript.Text))
+		return err
+	}
+	for _, segment := range resp.Transcript.Segments {
+		line := strings.TrimSpace(segment.Text)
+		if segment.Speaker != "" {
+			line = segment.Speaker + ": " + line
+		}
+		if timestamps {
+			line = "[" + export.Timestamp(segment.Start) + "] " + line
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newSummaryCommand builds the command that prints a transcription's summary
+func newSummaryCommand(e *env) *cobra.Command {
+	return &cobra.Command{
+		Use:   "summary ID",
+		Short: "Print a transcription's summary, in Markdown",
+		Args:  usageArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var raw json.RawMessage
+			if err := e.client.getJSON(cmd.Context(), "/api/v1/transcription/"+url.PathEscape(args[0])+"/summary", &raw); err != nil {
+				return err
+			}
+			if e.json {
+				return printJSON(e.stdout, raw)
+			}
+			var summary struct {
+				Content string `json:"content"`
+			}
+			if err := json.Unmarshal(raw, &summary); err != nil {
+				return fmt.Errorf("failed to read the server's response: %w", err)
+			}
+			_, err := fmt.Fprintln(e.stdout, strings.TrimSpace(summary.Content))
+			return err
+		},
+	}
+}
@@ -0,0 +1,728 @@
+// Package companion runs meeting companion sessions: a meeting is transcribed chunk by chunk
+// while it happens, running notes are kept up to date as it goes, and questions can be asked
+// about what was said so far and about the user's earlier transcriptions.
+package companion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/export"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/quota"
+	"scriberr/internal/rag"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// Session statuses
+const (
+	StatusActive    = "active"    // Accepting audio and segments
+	StatusFinishing = "finishing" // Ended; transcribing the last chunks and writing the final notes
+	StatusEnded     = "ended"
+)
+
+const (
+	// SessionTTL is how long a session is kept after its last activity. Idle active sessions
+	// are ended, and ended ones forgotten.
+	SessionTTL = 6 * time.Hour
+	// MaxPendingChunks caps the audio chunks of one session waiting to be transcribed
+	MaxPendingChunks = 8
+
+	cleanupInterval = 10 * time.Minute
+	chunkTimeout    = 10 * time.Minute
+	summaryTimeout  = 5 * time.Minute
+	// summaryBatchLength caps the new transcript text folded into the notes by one LLM call, in characters
+	summaryBatchLength = 12000
+	// askResults is how many passages are retrieved from the meeting, and from the library, per question
+	askResults = 5
+)
+
+var (
+	// ErrNotFound is returned for unknown sessions and sessions of other users
+	ErrNotFound = errors.New("companion session not found")
+	// ErrEnded is returned when adding to or asking about a session that has ended
+	ErrEnded = errors.New("companion session has ended")
+	// ErrBusy is returned when a session already has MaxPendingChunks chunks waiting
+	ErrBusy = errors.New("too many audio chunks waiting to be transcribed")
+)
+
+// Session is a snapshot of a companion session. Segments holds the segments from the
+// requested cursor on; pass Cursor back to get only the segments added since.
+type Session struct {
+	ID                 string                         `json:"id"`
+	UserID             *uint                          `json:"user_id,omitempty"`
+	Title              string                         `json:"title,omitempty"`
+	Status             string                         `json:"status"`
+	Summary            string                         `json:"summary"`
+	SummarizedSegments int                            `json:"summarized_segments"` // Segments covered by the summary
+	SummaryUpdatedAt   *time.Time                     `json:"summary_updated_at,omitempty"`
+	Segments           []interfaces.TranscriptSegment `json:"segments"`
+	Cursor             int                            `json:"cursor"`
+	Duration           float64                        `json:"duration"` // End of the last segment, in seconds
+	PendingChunks      int                            `json:"pending_chunks"`
+	LastError          string                         `json:"last_error,omitempty"`
+	CreatedAt          time.Time                      `json:"created_at"`
+	UpdatedAt          time.Time                      `json:"updated_at"`
+	EndedAt            *time.Time                     `json:"ended_at,omitempty"`
+}
+
+// Moment is a passage of the meeting used to answer a question
+type Moment struct {
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Speaker string  `json:"speaker,omitempty"`
+	Text    string  `json:"text"`
+}
+
+// Answer is the reply to a question asked during a meeting
+type Answer struct {
+	Answer            string   `json:"answer"`
+	Moments           []Moment `json:"moments"` // Passages of this meeting used as context
+	Sources           []string `json:"sources"` // Earlier transcriptions used as context
+	NoRelevantContext bool     `json:"no_relevant_context"`
+}
+
+// audioChunk is a chunk of meeting audio waiting to be transcribed
+type audioChunk struct {
+	audio    []byte
+	filename string
+	offset   *float64
+}
+
+// session is the state of one companion session. mu guards the fields; indexMu and
+// summaryMu serialize indexing and note updates, which run without holding mu.
+type session struct {
+	mu     sync.Mutex
+	id     string
+	userID *uint
+	title  string
+	params models.WhisperXParams
+	status string
+	// discarded is set when the session is deleted, so queued chunks are skipped
+	discarded bool
+
+	segments         []interfaces.TranscriptSegment
+	summary          string
+	summarized       int
+	summaryUpdatedAt *time.Time
+	pending          int
+	lastError        string
+	createdAt        time.Time
+	updatedAt        time.Time
+	endedAt          *time.Time
+
+	indexMu         sync.Mutex
+	indexedSegments int
+	indexedChunks   int
+
+	summaryMu sync.Mutex
+	chunks    chan audioChunk
+}
+
+// snapshot copies the session's state, with the segments from since on. Callers hold mu.
+func (s *session) snapshot(since int) *Session {
+	if since < 0 || since > len(s.segments) {
+		since = len(s.segments)
+	}
+	return &Session{
+		ID:                 s.id,
+		UserID:             s.userID,
+		Title:              s.title,
+		Status:             s.status,
+		Summary:            s.summary,
+		SummarizedSegments: s.summarized,
+		SummaryUpdatedAt:   s.summaryUpdatedAt,
+		Segments:           append([]interfaces.TranscriptSegment{}, s.segments[since:]...),
+		Cursor:             len(s.segments),
+		Duration:           s.duration(),
+		PendingChunks:      s.pending,
+		LastError:          s.lastError,
+		CreatedAt:          s.createdAt,
+		UpdatedAt:          s.updatedAt,
+		EndedAt:            s.endedAt,
+	}
+}
+
+// duration returns the end of the latest segment. Callers hold mu.
+func (s *session) duration() float64 {
+	var end float64
+	for _, segment := range s.segments {
+		end = math.Max(end, segment.End)
+	}
+	return end
+}
+
+// Service keeps the companion sessions in memory. A session's partial transcript is indexed
+// in its own temporary vector store collection, which is dropped when the session ends.
+type Service struct {
+	rag          *rag.RAGService
+	transcriber  Transcriber
+	summaryLLM   rag.LLMService
+	summaryModel string
+	chatLLM      rag.LLMService
+	chatModel    string
+
+	mu       sync.Mutex
+	sessions map[string]*session
+	stop     chan struct{}
+}
+
+// NewService creates a companion service that transcribes chunks with transcriber, keeps
+// notes with the summary model and answers questions with the chat model
+func NewService(ragService *rag.RAGService, transcriber Transcriber, summaryLLM rag.LLMService, summaryModel string, chatLLM rag.LLMService, chatModel string) *Service {
+	return &Service{
+		rag:          ragService,
+		transcriber:  transcriber,
+		summaryLLM:   summaryLLM,
+		summaryModel: summaryModel,
+		chatLLM:      chatLLM,
+		chatModel:    chatModel,
+		sessions:     make(map[string]*session),
+	}
+}
+
+// Start updates the notes of every active session with new segments every summaryInterval,
+// and expires idle sessions. An interval of 0 leaves note updates to Summarize and End.
+func (s *Service) Start(summaryInterval time.Duration) {
+	s.stop = make(chan struct{})
+
+	go func() {
+		var summaries <-chan time.Time
+		if summaryInterval > 0 {
+			ticker := time.NewTicker(summaryInterval)
+			defer ticker.Stop()
+			summaries = ticker.C
+		}
+		cleanup := time.NewTicker(cleanupInterval)
+		defer cleanup.Stop()
+		for {
+			select {
+			case <-summaries:
+				s.summarizeActive()
+			case <-cleanup.C:
+				s.expire()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends periodic note updates and expiry
+func (s *Service) Stop() {
+	if s.stop != nil {
+		close(s.stop)
+	}
+}
+
+// Create starts a session whose audio chunks are transcribed with params
+func (s *Service) Create(userID *uint, title string, params models.WhisperXParams) *Session {
+	now := time.Now()
+	sess := &session{
+		id:        uuid.New().String(),
+		userID:    userID,
+		title:     strings.TrimSpace(title),
+		params:    params,
+		status:    StatusActive,
+		segments:  []interfaces.TranscriptSegment{},
+		createdAt: now,
+		updatedAt: now,
+		chunks:    make(chan audioChunk, MaxPendingChunks),
+	}
+	s.mu.Lock()
+	s.sessions[sess.id] = sess
+	s.mu.Unlock()
+	go s.run(sess)
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.snapshot(0)
+}
+
+// Get returns a session with the segments from since on
+func (s *Service) Get(id string, userID *uint, since int) (*Session, error) {
+	sess, err := s.lookup(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.snapshot(since), nil
+}
+
+// List returns the sessions of a user, newest first, without their segments
+func (s *Service) List(userID *uint) []Session {
+	s.mu.Lock()
+	var owned []*session
+	for _, sess := range s.sessions {
+		if sameOwner(sess.userID, userID) {
+			owned = append(owned, sess)
+		}
+	}
+	s.mu.Unlock()
+
+	sessions := make([]Session, len(owned))
+	for i, sess := range owned {
+		sess.mu.Lock()
+		sessions[i] = *sess.snapshot(-1)
+		sess.mu.Unlock()
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].CreatedAt.After(sessions[j].CreatedAt) })
+	return sessions
+}
+
+// AddAudio queues a chunk of meeting audio for transcription. Its segments are placed offset
+// seconds into the meeting, or after the transcript so far when offset is nil.
+func (s *Service) AddAudio(id string, userID *uint, audio []byte, filename string, offset *float64) (*Session, error) {
+	sess, err := s.lookup(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.status != StatusActive {
+		return nil, ErrEnded
+	}
+	select {
+	case sess.chunks <- audioChunk{audio: audio, filename: filename, offset: offset}:
+	default:
+		return nil, ErrBusy
+	}
+	sess.pending++
+	sess.updatedAt = time.Now()
+	return sess.snapshot(-1), nil
+}
+
+// AddSegments appends segments transcribed elsewhere, such as by a streaming recognizer in
+// the client, with times measured from the start of the meeting
+func (s *Service) AddSegments(id string, userID *uint, segments []interfaces.TranscriptSegment) (*Session, error) {
+	sess, err := s.lookup(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	sess.mu.Lock()
+	if sess.status != StatusActive {
+		sess.mu.Unlock()
+		return nil, ErrEnded
+	}
+	since := len(sess.segments)
+	appendSegments(sess, segments, 0)
+	sess.mu.Unlock()
+
+	if err := s.index(sess, false); err != nil {
+		logger.Error("Failed to index companion session", "component", "companion", "session_id", sess.id, "error", err)
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.snapshot(since), nil
+}
+
+// Summarize brings a session's notes up to date with its transcript right away
+func (s *Service) Summarize(ctx context.Context, id string, userID *uint) (*Session, error) {
+	sess, err := s.lookup(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	sess.summaryMu.Lock()
+	err = s.summarize(ctx, sess)
+	sess.summaryMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.snapshot(-1), nil
+}
+
+// Ask answers a question from the meeting so far and its notes, and with withLibrary from the
+// user's earlier transcriptions as well
+func (s *Service) Ask(ctx context.Context, id string, userID *uint, question string, withLibrary bool) (*Answer, error) {
+	sess, err := s.lookup(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Index the latest segments too, so the question can be about what was just said
+	if err := s.index(sess, true); err != nil {
+		return nil, err
+	}
+	sess.mu.Lock()
+	status, notes := sess.status, sess.summary
+	sess.mu.Unlock()
+	if status == StatusEnded {
+		return nil, ErrEnded
+	}
+
+	live, library, err := s.rag.RetrieveLive(ctx, sess.id, userID, question, askResults, withLibrary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query context: %w", err)
+	}
+	answer := &Answer{Moments: make([]Moment, len(live)), Sources: []string{}}
+	seen := map[string]bool{}
+	for _, doc := range library {
+		if doc.TranscriptionID != "" && !seen[doc.TranscriptionID] {
+			seen[doc.TranscriptionID] = true
+			answer.Sources = append(answer.Sources, doc.TranscriptionID)
+		}
+	}
+	if len(live) == 0 && len(library) == 0 && notes == "" {
+		answer.Answer = rag.NoRelevantContextAnswer
+		answer.NoRelevantContext = true
+		return answer, nil
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString("You are assisting someone during a meeting that is still in progress. " +
+		"Answer their question briefly from the context below. If the context does not contain the answer, say so instead of guessing.\n\n")
+	if notes != "" {
+		prompt.WriteString("Notes on the meeting so far:\n")
+		prompt.WriteString(notes)
+		prompt.WriteString("\n\n")
+	}
+	if len(live) > 0 {
+		prompt.WriteString("From this meeting:\n")
+		for i, doc := range live {
+			moment := Moment{Speaker: doc.Speaker, Text: doc.Content}
+			if doc.Start != nil {
+				moment.Start = *doc.Start
+			}
+			if doc.End != nil {
+				moment.End = *doc.End
+			}
+			answer.Moments[i] = moment
+			fmt.Fprintf(&prompt, "%s\n", transcriptLine(moment.Start, moment.Speaker, moment.Text))
+		}
+		prompt.WriteString("\n")
+	}
+	if len(library) > 0 {
+		prompt.WriteString("From earlier transcriptions:\n")
+		for i, doc := range library {
+			fmt.Fprintf(&prompt, "%d. %s\n", i+1, doc.Content)
+		}
+		prompt.WriteString("\n")
+	}
+	prompt.WriteString("Question: ")
+	prompt.WriteString(question)
+
+	messages := []llm.ChatMessage{{Role: "user", Content: prompt.String()}}
+	response, err := s.chatLLM.ChatCompletion(ctx, s.chatModel, messages, 0.3)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LLM response: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("no response from LLM")
+	}
+	answer.Answer = strings.TrimSpace(response.Choices[0].Message.Content)
+	return answer, nil
+}
+
+// End stops a session from taking more input. The chunks already queued are still
+// transcribed, then the notes are brought up to date one last time and the session's
+// temporary index is dropped, after which its status is StatusEnded.
+func (s *Service) End(id string, userID *uint) (*Session, error) {
+	sess, err := s.lookup(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	s.finish(sess)
+	return sess.snapshot(-1), nil
+}
+
+// Delete discards a session and its temporary index without waiting for queued chunks
+func (s *Service) Delete(id string, userID *uint) error {
+	sess, err := s.lookup(id, userID)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	delete(s.sessions, id)
+	s.mu.Unlock()
+
+	sess.mu.Lock()
+	sess.discarded = true
+	s.finish(sess)
+	sess.mu.Unlock()
+	return nil
+}
+
+// lookup finds a session owned by userID
+func (s *Service) lookup(id string, userID *uint) (*session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[id]
+	if !ok || !sameOwner(sess.userID, userID) {
+		return nil, ErrNotFound
+	}
+	return sess, nil
+}
+
+// finish closes an active session's chunk queue, which lets its worker wrap up. Callers hold mu.
+func (s *Service) finish(sess *session) {
+	if sess.status != StatusActive {
+		return
+	}
+	sess.status = StatusFinishing
+	sess.updatedAt = time.Now()
+	close(sess.chunks)
+}
+
+// run transcribes a session's chunks in the order they arrived, then wraps the session up
+// once its queue is closed: unless it was discarded, the notes are brought up to date, and
+// its temporary index is dropped
+func (s *Service) run(sess *session) {
+	for chunk := range sess.chunks {
+		s.transcribeChunk(sess, chunk)
+	}
+
+	sess.mu.Lock()
+	discarded := sess.discarded
+	sess.mu.Unlock()
+	var err error
+	if !discarded {
+		ctx, cancel := context.WithTimeout(context.Background(), summaryTimeout)
+		sess.summaryMu.Lock()
+		err = s.summarize(ctx, sess)
+		sess.summaryMu.Unlock()
+		cancel()
+	}
+
+	// Hold indexMu until the status changes, so a late question can't index into the dropped collection
+	sess.indexMu.Lock()
+	defer sess.indexMu.Unlock()
+	if dropErr := s.rag.DropLive(sess.id); dropErr != nil {
+		logger.Error("Failed to drop the index of companion session", "component", "companion", "session_id", sess.id, "error", dropErr)
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if err != nil {
+		logger.Error("Failed to write the final notes of companion session", "component", "companion", "session_id", sess.id, "error", err)
+		sess.lastError = err.Error()
+	}
+	now := time.Now()
+	sess.status = StatusEnded
+	sess.endedAt = &now
+	sess.updatedAt = now
+}
+
+// transcribeChunk transcribes one chunk and appends its segments
+func (s *Service) transcribeChunk(sess *session, chunk audioChunk) {
+	sess.mu.Lock()
+	if sess.discarded {
+		sess.pending--
+		sess.mu.Unlock()
+		return
+	}
+	sess.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), chunkTimeout)
+	segments, err := s.transcriber.Transcribe(ctx, chunk.audio, chunk.filename, sess.params)
+	cancel()
+	if err == nil {
+		// Sessions aren't saved as transcriptions, so their audio is metered chunk by chunk
+		quota.RecordAudio(sess.userID, segments)
+	}
+
+	sess.mu.Lock()
+	sess.pending--
+	if err != nil {
+		logger.WarnContext(ctx, "Failed to transcribe a chunk of companion session", "component", "companion", "session_id", sess.id, "error", err)
+		sess.lastError = err.Error()
+		sess.updatedAt = time.Now()
+		sess.mu.Unlock()
+		return
+	}
+	offset := sess.duration()
+	if chunk.offset != nil {
+		offset = *chunk.offset
+	}
+	appendSegments(sess, segments, offset)
+	sess.mu.Unlock()
+
+	if err := s.index(sess, false); err != nil {
+		logger.Error("Failed to index companion session", "component", "companion", "session_id", sess.id, "error", err)
+	}
+}
+
+// appendSegments adds non-empty segments shifted by offset seconds. Callers hold mu.
+func appendSegments(sess *session, segments []interfaces.TranscriptSegment, offset float64) {
+	for _, segment := range segments {
+		if strings.TrimSpace(segment.Text) == "" {
+			continue
+		}
+		segment.Start += offset
+		segment.End += offset
+		sess.segments = append(sess.segments, segment)
+	}
+	sess.updatedAt = time.Now()
+}
+
+// index embeds the segments not indexed yet into the session's temporary collection. Unless
+// flush is set it waits until they fill a whole chunk, so chunks aren't cut short by every
+// small addition.
+func (s *Service) index(sess *session, flush bool) error {
+	sess.indexMu.Lock()
+	defer sess.indexMu.Unlock()
+
+	sess.mu.Lock()
+	if sess.status == StatusEnded {
+		sess.mu.Unlock()
+		return nil
+	}
+	pending := append([]interfaces.TranscriptSegment{}, sess.segments[sess.indexedSegments:]...)
+	sess.mu.Unlock()
+
+	length := 0
+	for _, segment := range pending {
+		length += len(strings.TrimSpace(segment.Text))
+	}
+	if length == 0 || (!flush && length < rag.TranscriptChunkSize) {
+		return nil
+	}
+
+	chunks := rag.ChunkSegments(pending, rag.TranscriptChunkSize)
+	if err := s.rag.StoreLiveChunks(sess.id, sess.indexedChunks, chunks); err != nil {
+		return err
+	}
+	sess.indexedSegments += len(pending)
+	sess.indexedChunks += len(chunks)
+	return nil
+}
+
+// summarize folds the segments added since the last update into the session's notes, in
+// batches of about summaryBatchLength characters. Callers hold summaryMu.
+func (s *Service) summarize(ctx context.Context, sess *session) error {
+	for {
+		sess.mu.Lock()
+		notes, from := sess.summary, sess.summarized
+		var batch []interfaces.TranscriptSegment
+		length := 0
+		for _, segment := range sess.segments[from:] {
+			if len(batch) > 0 && length+len(segment.Text) > summaryBatchLength {
+				break
+			}
+			batch = append(batch, segment)
+			length += len(segment.Text)
+		}
+		sess.mu.Unlock()
+		if len(batch) == 0 {
+			return nil
+		}
+
+		var prompt strings.Builder
+		prompt.WriteString("You are keeping running notes of a meeting while it happens. ")
+		if notes != "" {
+			prompt.WriteString("Update the notes so far with the next part of the transcript, keeping what still matters and adding " +
+				"new topics, decisions, open questions and action items. Reply with the complete updated notes only.\n\n")
+			prompt.WriteString("Notes so far:\n")
+			prompt.WriteString(notes)
+			prompt.WriteString("\n\n")
+		} else {
+			prompt.WriteString("Write concise notes of the following start of the transcript: topics, decisions, " +
+				"open questions and action items. Reply with the notes only.\n\n")
+		}
+		prompt.WriteString("Transcript:\n")
+		for _, segment := range batch {
+			speaker := ""
+			if segment.Speaker != nil {
+				speaker = *segment.Speaker
+			}
+			prompt.WriteString(transcriptLine(segment.Start, speaker, segment.Text))
+			prompt.WriteString("\n")
+		}
+
+		messages := []llm.ChatMessage{{Role: "user", Content: prompt.String()}}
+		response, err := s.summaryLLM.ChatCompletion(ctx, s.summaryModel, messages, 0.3)
+		if err != nil {
+			return fmt.Errorf("failed to update notes: %w", err)
+		}
+		if len(response.Choices) == 0 {
+			return fmt.Errorf("no response from LLM")
+		}
+
+		now := time.Now()
+		sess.mu.Lock()
+		sess.summary = strings.TrimSpace(response.Choices[0].Message.Content)
+		sess.summarized = from + len(batch)
+		sess.summaryUpdatedAt = &now
+		sess.mu.Unlock()
+	}
+}
+
+// summarizeActive updates the notes of the active sessions with new segments in the
+// background, skipping sessions whose notes are being updated already
+func (s *Service) summarizeActive() {
+	s.mu.Lock()
+	var due []*session
+	for _, sess := range s.sessions {
+		sess.mu.Lock()
+		if sess.status == StatusActive && sess.summarized < len(sess.segments) {
+			due = append(due, sess)
+		}
+		sess.mu.Unlock()
+	}
+	s.mu.Unlock()
+
+	for _, sess := range due {
+		if !sess.summaryMu.TryLock() {
+			continue
+		}
+		go func(sess *session) {
+			defer sess.summaryMu.Unlock()
+			ctx, cancel := context.WithTimeout(context.Background(), summaryTimeout)
+			defer cancel()
+			if err := s.summarize(ctx, sess); err != nil {
+				logger.WarnContext(ctx, "Failed to update the notes of companion session", "component", "companion", "session_id", sess.id, "error", err)
+				sess.mu.Lock()
+				sess.lastError = err.Error()
+				sess.mu.Unlock()
+			}
+		}(sess)
+	}
+}
+
+// expire ends sessions idle for SessionTTL and forgets ended ones idle as long
+func (s *Service) expire() {
+	cutoff := time.Now().Add(-SessionTTL)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, sess := range s.sessions {
+		sess.mu.Lock()
+		if sess.updatedAt.Before(cutoff) {
+			if sess.status == StatusEnded {
+				delete(s.sessions, id)
+			} else {
+				s.finish(sess)
+			}
+		}
+		sess.mu.Unlock()
+	}
+}
+
+// transcriptLine formats a segment for a prompt as "[HH:MM:SS] Speaker: text"
+func transcriptLine(start float64, speaker, text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if speaker == "" {
+		return fmt.Sprintf("[%s] %s", export.Timestamp(start), text)
+	}
+	return fmt.Sprintf("[%s] %s: %s", export.Timestamp(start), speaker, text)
+}
+
+// sameOwner reports whether two owners are the same user, or both unset
+func sameOwner(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,68 @@
+package companion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription"
+	"scriberr/internal/transcription/interfaces"
+)
+
+// quickPollInterval is how often a quick transcription is checked for completion
+const quickPollInterval = time.Second
+
+// Transcriber turns one chunk of meeting audio into timed segments, with times relative to
+// the start of the chunk
+type Transcriber interface {
+	Transcribe(ctx context.Context, audio []byte, filename string, params models.WhisperXParams) ([]interfaces.TranscriptSegment, error)
+}
+
+// QuickTranscriber transcribes chunks with the quick transcription service, which keeps
+// nothing in the job history
+type QuickTranscriber struct {
+	Service *transcription.QuickTranscriptionService
+}
+
+// Transcribe submits a chunk as a quick transcription job and waits for its transcript
+func (t *QuickTranscriber) Transcribe(ctx context.Context, audio []byte, filename string, params models.WhisperXParams) ([]interfaces.TranscriptSegment, error) {
+	// Sessions meter their chunks themselves, with transcribers that aren't quick jobs too
+	job, err := t.Service.SubmitQuickJob(bytes.NewReader(audio), filename, params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(quickPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		current, err := t.Service.GetQuickJob(job.ID)
+		if err != nil {
+			return nil, err
+		}
+		switch current.Status {
+		case models.StatusCompleted:
+			if current.Transcript == nil {
+				return []interfaces.TranscriptSegment{}, nil
+			}
+			var result interfaces.TranscriptResult
+			if err := json.Unmarshal([]byte(*current.Transcript), &result); err != nil {
+				return nil, fmt.Errorf("failed to parse transcript: %w", err)
+			}
+			return result.Segments, nil
+		case models.StatusFailed:
+			if current.ErrorMessage != nil {
+				return nil, fmt.Errorf("transcription failed: %s", *current.ErrorMessage)
+			}
+			return nil, fmt.Errorf("transcription failed")
+		}
+	}
+}
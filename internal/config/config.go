@@ -31,11 +31,195 @@ type Config struct {
 	// Python/WhisperX configuration
 	UVPath      string
 	WhisperXEnv string
+	// YtDlpPath runs a yt-dlp binary; when empty, yt-dlp runs from the WhisperX environment with uv
+	YtDlpPath string
+	// AudioPreprocess lists the preprocessing steps applied before transcription when a
+	// job's parameters don't choose any: trim_silence, skip_silence, denoise, normalize and resample
+	AudioPreprocess string
+	// SilenceThresholdDb is the level below which audio counts as silence for trim_silence
+	// and skip_silence, and SkipSilenceMinSeconds the shortest silence skip_silence leaves out
+	SilenceThresholdDb    float64
+	SkipSilenceMinSeconds float64
+	// SpeakerIdentification names diarized speakers after the speaker profiles whose voice
+	// they have at least SpeakerMatchThreshold cosine similarity to
+	SpeakerIdentification bool
+	SpeakerMatchThreshold float64
+	// LanguageDetection detects the spoken language before transcription, overriding a job's
+	// language when at least LanguageOverrideConfidence sure it's wrong. LanguageModels routes
+	// languages to model families, e.g. "en=nvidia_parakeet,*=whisper:large-v3".
+	LanguageDetection          bool
+	LanguageOverrideConfidence float64
+	LanguageModels             string
+
+	// QueueWorkers is how many transcriptions run at once; 0 scales between limits fitting the CPU count
+	QueueWorkers int
+
+	// Stuck job watchdog: jobs whose worker stops sending heartbeats, or that run longer than
+	// StuckJobMultiple times the usual processing time, are requeued or failed
+	StuckHeartbeatSeconds int
+	StuckJobMultiple      float64 // 0 turns the duration check off
+	StuckJobMinMinutes    int
+	StuckJobAction        string // requeue or fail
+	StuckJobMaxRequeues   int
 
 	// RAG configuration
 	OllamaURL      string
 	ChromaDBURL    string
 	EmbeddingModel string
+	// EmbeddingProvider selects the embedding backend: "ollama" (OllamaURL) or "http" (a custom service at EmbeddingURL)
+	EmbeddingProvider string
+	EmbeddingURL      string
+	EmbeddingAPIKey   string
+	// ChunkerURL points at a custom chunking service used to chunk transcripts for RAG (empty uses the built-in chunker)
+	ChunkerURL            string
+	ChunkerAPIKey         string
+	ChunkerTimeoutSeconds int
+	// ChunkerFallback chunks transcripts the built-in way when the chunking service fails, rather than failing to index
+	ChunkerFallback bool
+	// RAGMaxDistance is the retrieval distance above which context counts as irrelevant (0 disables the cutoff)
+	RAGMaxDistance float64
+	// RAGConfidenceWeight scales how much low ASR confidence pushes a transcript chunk down the ranking (0 disables it)
+	RAGConfidenceWeight float64
+	// StandingContextMaxTokens caps the standing context prepended to RAG chat prompts (0 leaves it out)
+	StandingContextMaxTokens int
+	// RAGCollectionRoutes routes content types to collections of their own, e.g. "podcast=podcasts,voice_memo=memos"
+	RAGCollectionRoutes string
+	// RAGCrossLanguage is how chat handles excerpts in another language than the question by
+	// default: "off", "annotate" (mark their language) or "translate"
+	RAGCrossLanguage string
+	// TopicRefreshHours is how often the transcript library is re-clustered into topics (0 disables it)
+	TopicRefreshHours int
+	// ResummarizeIntervalHours is how often summaries written by an older model are rewritten with the current one (0 disables it)
+	ResummarizeIntervalHours int
+	// ResummarizeBatchSize caps the transcriptions summarized again per scheduled run
+	ResummarizeBatchSize int
+	// ResummarizeDelaySeconds is the pause between two transcriptions of a re-summarization run
+	ResummarizeDelaySeconds int
+	// CompanionSummarySeconds is how often the running notes of live meeting companion sessions are updated (0 disables it)
+	CompanionSummarySeconds int
+
+	// LLM providers. Each feature uses its own provider ("ollama", "openai" or "anthropic") and model;
+	// OpenAIBaseURL may point at any OpenAI-compatible server.
+	OpenAIAPIKey       string
+	OpenAIBaseURL      string
+	AnthropicAPIKey    string
+	AnthropicBaseURL   string
+	SummaryLLMProvider string
+	SummaryLLMModel    string
+	ChatLLMProvider    string
+	ChatLLMModel       string
+	// SummaryLLMFallbacks and ChatLLMFallbacks are comma-separated provider:model pairs tried in order when the primary fails
+	SummaryLLMFallbacks string
+	ChatLLMFallbacks    string
+	// LLMAttemptTimeoutSeconds bounds each provider attempt before failing over (0 disables it)
+	LLMAttemptTimeoutSeconds int
+	// LLMMetricsRetentionDays is how long LLM call statistics and usage are kept (0 keeps them forever)
+	LLMMetricsRetentionDays int
+	// LLMPricing adds to or overrides the built-in model prices, as "model=input/output,..." in USD per million tokens
+	LLMPricing string
+
+	// Request timeouts per endpoint class, in seconds (0 disables the timeout): quick reads,
+	// long-running LLM calls and evaluations, and streamed responses
+	RequestTimeoutReadSeconds   int
+	RequestTimeoutLongSeconds   int
+	RequestTimeoutStreamSeconds int
+
+	// Post-processing workflow configuration
+	PostProcessingWorkflow string
+	WorkflowsFile          string // JSON file of extra workflow definitions, or replacements of the built-in ones
+	DisabledWorkflowSteps  string // Comma-separated steps skipped in every workflow run
+	WorkflowMaxAttempts    int    // Attempts of a failing workflow step before it is dead-lettered; 1 turns retries off
+	WorkflowRetrySeconds   int    // Wait before a failed step's first retry, doubled for each one after it
+	NotifyWebhookURL       string
+	// WebhookAllowPrivate lets the webhooks users set, for task integrations, watchlists,
+	// templates and projects, be on loopback and private networks
+	WebhookAllowPrivate    bool
+	PublicURL              string // Where the web app is reached, for links back to recordings from other services
+	SignedURLTTLSeconds    int    // Default lifetime of signed download URLs
+	SignedURLMaxTTLSeconds int    // Longest lifetime a signed download URL may be given
+	ShareScan              bool   // Scan transcripts for sensitive passages before signing a download of them or sharing them
+	ShareSensitiveTerms    string // Comma-separated terms the share scan flags, besides personal data
+	TranslationLanguage    string
+	IndexTranslations      bool   // Index translations into RAG next to the original transcript
+	SummaryFormat          string // "text" or "structured"
+	AutoTags               bool
+	ExtractActionItems     bool
+	ExtractEntities        bool
+	SpeakerAnalytics       bool
+	AutoChapters           bool
+	RedactPII              bool // Run the redact_pii step after every transcription
+	EmbedRedacted          bool // Embed transcriptions into RAG with their personal data redacted
+	CorrectVocabulary      bool // Correct transcripts to their vocabulary terms after every transcription
+
+	// Resource guardrails: uploads and transcriptions are rejected while less than these many
+	// megabytes of disk space (on the upload directory's file system) or memory are free (0 disables a check)
+	MinFreeDiskMB   int
+	MinFreeMemoryMB int
+
+	// Rate limits and monthly quotas each user and API key gets unless an admin sets their own
+	// (0 means unlimited): requests per minute, minutes of audio transcribed, and LLM requests
+	RateLimitPerMinute int
+	QuotaAudioMinutes  int
+	QuotaLLMCalls      int
+
+	// RateLimitAnonymousPerMinute limits the requests a minute of each IP address without
+	// valid credentials, such as logins and shared link passwords (0 means unlimited)
+	RateLimitAnonymousPerMinute int
+
+	// AuditLogRetentionDays is how long audit log entries are kept (0 keeps them forever)
+	AuditLogRetentionDays int
+
+	// OpenTelemetry tracing: the collector spans are exported to over OTLP/HTTP (empty turns
+	// tracing off), headers sent with every export as comma-separated key=value pairs, the
+	// name this instance is traced under, and the share of traces recorded (0 to 1)
+	OTLPEndpoint       string
+	OTLPHeaders        string
+	TracingServiceName string
+	TracingSampleRatio float64
+
+	// JobLogMaxEntries is how many of the newest log lines are kept for each job (0 keeps them all)
+	JobLogMaxEntries int
+
+	// ResumableUploadExpiryHours is how long an unfinished resumable upload is kept after its last part
+	ResumableUploadExpiryHours int
+	// URL imports: the largest file downloaded, how long a download may take, and whether
+	// URLs on loopback and private networks may be fetched
+	URLImportMaxMB          int
+	URLImportTimeoutMinutes int
+	URLImportAllowPrivate   bool
+	// PodcastPollMinutes is how often podcast feeds are checked for new episodes (0 = only on request)
+	PodcastPollMinutes int
+
+	// Email-in: a mailbox read over IMAP for voice memos sent as attachments (disabled when
+	// EmailInIMAPAddr is empty), and the SMTP server the summaries are replied through
+	// (no replies when SMTPAddr is empty)
+	EmailInIMAPAddr       string // host:port
+	EmailInIMAPTLS        bool
+	EmailInUsername       string
+	EmailInPassword       string
+	EmailInMailbox        string
+	EmailInPollSeconds    int
+	EmailInAllowedSenders string // Comma-separated addresses or @domains that may send memos; empty allows anyone
+	EmailInOwner          string // Username owning the transcriptions; empty leaves them without an owner
+	SMTPAddr              string // host:port
+	SMTPUsername          string
+	SMTPPassword          string
+	SMTPFrom              string
+
+	// Object storage: with StorageBackend "s3", audio files and transcripts are also kept in an
+	// S3 or MinIO bucket, so nodes sharing the bucket can work on each other's recordings
+	StorageBackend    string // "local" or "s3"
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3Prefix          string // Key prefix, for sharing a bucket with other data
+	S3PathStyle       bool   // Address the bucket in the path, as MinIO expects
+
+	// FakeProviders swaps transcription, embeddings, the LLMs and the vector store for
+	// deterministic in-process fakes, for integration tests and development without GPUs
+	FakeProviders bool
 }
 
 // Load loads configuration from environment variables and .env file
@@ -45,7 +229,7 @@ func Load() *Config {
 		logger.Debug("No .env file found, using system environment variables")
 	}
 
-	return &Config{
+	cfg := &Config{
 		Port:         getEnv("PORT", "8080"),
 		Host:         getEnv("HOST", "localhost"),
 		DatabasePath: getEnv("DATABASE_PATH", "data/scriberr.db"),
@@ -53,10 +237,136 @@ func Load() *Config {
 		UploadDir:    getEnv("UPLOAD_DIR", "data/uploads"),
 		UVPath:       findUVPath(),
 		WhisperXEnv:  getEnv("WHISPERX_ENV", "data/whisperx-env"),
+		YtDlpPath:    getEnv("YTDLP_PATH", ""),
+		AudioPreprocess: getEnv("AUDIO_PREPROCESS", ""),
+		SilenceThresholdDb:    getEnvAsFloat("SILENCE_THRESHOLD_DB", -50),
+		SkipSilenceMinSeconds: getEnvAsFloat("SKIP_SILENCE_MIN_SECONDS", 3),
+		SpeakerIdentification: getEnvAsBool("SPEAKER_IDENTIFICATION", true),
+		SpeakerMatchThreshold: getEnvAsFloat("SPEAKER_MATCH_THRESHOLD", 0.6),
+		LanguageDetection:          getEnvAsBool("LANGUAGE_DETECTION", true),
+		LanguageOverrideConfidence: getEnvAsFloat("LANGUAGE_OVERRIDE_CONFIDENCE", 0.9),
+		LanguageModels:             getEnv("LANGUAGE_MODELS", ""),
+		QueueWorkers: getEnvAsInt("QUEUE_WORKERS", 2),
+		StuckHeartbeatSeconds: getEnvAsInt("STUCK_HEARTBEAT_SECONDS", 300),
+		StuckJobMultiple:      getEnvAsFloat("STUCK_JOB_MULTIPLE", 3),
+		StuckJobMinMinutes:    getEnvAsInt("STUCK_JOB_MIN_MINUTES", 60),
+		StuckJobAction:        getEnv("STUCK_JOB_ACTION", "requeue"),
+		StuckJobMaxRequeues:   getEnvAsInt("STUCK_JOB_MAX_REQUEUES", 1),
 		OllamaURL:    getEnv("OLLAMA_URL", "http://10.0.0.50:11434"),
 		ChromaDBURL:  getEnv("CHROMADB_URL", "http://chromadb:8000"),
 		EmbeddingModel: getEnv("EMBEDDING_MODEL", "nomic-embed-text"),
+		EmbeddingProvider: getEnv("EMBEDDING_PROVIDER", "ollama"),
+		EmbeddingURL:      getEnv("EMBEDDING_URL", ""),
+		EmbeddingAPIKey:   getEnv("EMBEDDING_API_KEY", ""),
+		ChunkerURL:            getEnv("CHUNKER_URL", ""),
+		ChunkerAPIKey:         getEnv("CHUNKER_API_KEY", ""),
+		ChunkerTimeoutSeconds: getEnvAsInt("CHUNKER_TIMEOUT_SECONDS", 30),
+		ChunkerFallback:       getEnvAsBool("CHUNKER_FALLBACK", true),
+		RAGMaxDistance: getEnvAsFloat("RAG_MAX_DISTANCE", 0),
+		RAGConfidenceWeight: getEnvAsFloat("RAG_CONFIDENCE_WEIGHT", 1),
+		StandingContextMaxTokens: getEnvAsInt("STANDING_CONTEXT_MAX_TOKENS", 1000),
+		RAGCollectionRoutes:      getEnv("RAG_COLLECTION_ROUTES", ""),
+		RAGCrossLanguage:         getEnv("RAG_CROSS_LANGUAGE", "off"),
+		TopicRefreshHours: getEnvAsInt("TOPIC_REFRESH_HOURS", 24),
+		ResummarizeIntervalHours: getEnvAsInt("RESUMMARIZE_INTERVAL_HOURS", 0),
+		ResummarizeBatchSize:     getEnvAsInt("RESUMMARIZE_BATCH_SIZE", 20),
+		ResummarizeDelaySeconds:  getEnvAsInt("RESUMMARIZE_DELAY_SECONDS", 10),
+		CompanionSummarySeconds: getEnvAsInt("COMPANION_SUMMARY_SECONDS", 60),
+		OpenAIAPIKey:       getEnv("OPENAI_API_KEY", ""),
+		OpenAIBaseURL:      getEnv("OPENAI_BASE_URL", ""),
+		AnthropicAPIKey:    getEnv("ANTHROPIC_API_KEY", ""),
+		AnthropicBaseURL:   getEnv("ANTHROPIC_BASE_URL", ""),
+		SummaryLLMProvider: getEnv("SUMMARY_LLM_PROVIDER", "ollama"),
+		SummaryLLMModel:    getEnv("SUMMARY_LLM_MODEL", getEnv("OLLAMA_MODEL", "llama3.2")),
+		ChatLLMProvider:    getEnv("CHAT_LLM_PROVIDER", getEnv("SUMMARY_LLM_PROVIDER", "ollama")),
+		ChatLLMModel:       getEnv("CHAT_LLM_MODEL", getEnv("SUMMARY_LLM_MODEL", getEnv("OLLAMA_MODEL", "llama3.2"))),
+		SummaryLLMFallbacks: getEnv("SUMMARY_LLM_FALLBACKS", ""),
+		ChatLLMFallbacks:    getEnv("CHAT_LLM_FALLBACKS", ""),
+		LLMAttemptTimeoutSeconds: getEnvAsInt("LLM_ATTEMPT_TIMEOUT_SECONDS", 180),
+		LLMMetricsRetentionDays:  getEnvAsInt("LLM_METRICS_RETENTION_DAYS", 365),
+		LLMPricing:               getEnv("LLM_PRICING", ""),
+		RequestTimeoutReadSeconds:   getEnvAsInt("REQUEST_TIMEOUT_READ_SECONDS", 60),
+		RequestTimeoutLongSeconds:   getEnvAsInt("REQUEST_TIMEOUT_LONG_SECONDS", 300),
+		RequestTimeoutStreamSeconds: getEnvAsInt("REQUEST_TIMEOUT_STREAM_SECONDS", 0),
+		PostProcessingWorkflow: getEnv("POST_PROCESSING_WORKFLOW", "default"),
+		WorkflowsFile:          getEnv("WORKFLOWS_FILE", ""),
+		DisabledWorkflowSteps:  getEnv("DISABLED_WORKFLOW_STEPS", ""),
+		WorkflowMaxAttempts:    getEnvAsInt("WORKFLOW_MAX_ATTEMPTS", 5),
+		WorkflowRetrySeconds:   getEnvAsInt("WORKFLOW_RETRY_SECONDS", 30),
+		NotifyWebhookURL:       getEnv("NOTIFY_WEBHOOK_URL", ""),
+		WebhookAllowPrivate:    getEnvAsBool("WEBHOOK_ALLOW_PRIVATE", false),
+		PublicURL:              getEnv("PUBLIC_URL", ""),
+		SignedURLTTLSeconds:    getEnvAsInt("SIGNED_URL_TTL_SECONDS", 300),
+		SignedURLMaxTTLSeconds: getEnvAsInt("SIGNED_URL_MAX_TTL_SECONDS", 86400),
+		ShareScan:              getEnvAsBool("SHARE_SCAN", false),
+		ShareSensitiveTerms:    getEnv("SHARE_SENSITIVE_TERMS", ""),
+		StorageBackend:         getEnv("STORAGE_BACKEND", "local"),
+		S3Endpoint:             getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+		S3Region:               getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:               getEnv("S3_BUCKET", ""),
+		S3AccessKeyID:          getEnv("S3_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey:      getEnv("S3_SECRET_ACCESS_KEY", ""),
+		S3Prefix:               getEnv("S3_PREFIX", ""),
+		S3PathStyle:            getEnvAsBool("S3_PATH_STYLE", false),
+		TranslationLanguage:    getEnv("TRANSLATION_LANGUAGE", ""),
+		IndexTranslations:      getEnvAsBool("INDEX_TRANSLATIONS", false),
+		SummaryFormat:          getEnv("SUMMARY_FORMAT", "text"),
+		AutoTags:               getEnvAsBool("AUTO_TAGS", true),
+		ExtractActionItems:     getEnvAsBool("EXTRACT_ACTION_ITEMS", false),
+		ExtractEntities:        getEnvAsBool("EXTRACT_ENTITIES", false),
+		SpeakerAnalytics:       getEnvAsBool("SPEAKER_ANALYTICS", false),
+		AutoChapters:           getEnvAsBool("AUTO_CHAPTERS", false),
+		RedactPII:              getEnvAsBool("REDACT_PII", false),
+		EmbedRedacted:          getEnvAsBool("EMBED_REDACTED", false),
+		CorrectVocabulary:      getEnvAsBool("CORRECT_VOCABULARY", true),
+		MinFreeDiskMB:          getEnvAsInt("MIN_FREE_DISK_MB", 1024),
+		MinFreeMemoryMB:        getEnvAsInt("MIN_FREE_MEMORY_MB", 512),
+		RateLimitPerMinute:     getEnvAsInt("RATE_LIMIT_PER_MINUTE", 0),
+		QuotaAudioMinutes:      getEnvAsInt("QUOTA_AUDIO_MINUTES_PER_MONTH", 0),
+		QuotaLLMCalls:          getEnvAsInt("QUOTA_LLM_CALLS_PER_MONTH", 0),
+		RateLimitAnonymousPerMinute: getEnvAsInt("RATE_LIMIT_ANONYMOUS_PER_MINUTE", 30),
+		AuditLogRetentionDays:  getEnvAsInt("AUDIT_LOG_RETENTION_DAYS", 365),
+		OTLPEndpoint:           getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		OTLPHeaders:            getEnv("OTEL_EXPORTER_OTLP_HEADERS", ""),
+		TracingServiceName:     getEnv("OTEL_SERVICE_NAME", "scriberr"),
+		TracingSampleRatio:     getEnvAsFloat("OTEL_TRACES_SAMPLER_ARG", 1),
+		JobLogMaxEntries:       getEnvAsInt("JOB_LOG_MAX_ENTRIES", 2000),
+		ResumableUploadExpiryHours: getEnvAsInt("RESUMABLE_UPLOAD_EXPIRY_HOURS", 24),
+		URLImportMaxMB:             getEnvAsInt("URL_IMPORT_MAX_MB", 2048),
+		URLImportTimeoutMinutes:    getEnvAsInt("URL_IMPORT_TIMEOUT_MINUTES", 60),
+		URLImportAllowPrivate:      getEnvAsBool("URL_IMPORT_ALLOW_PRIVATE", false),
+		PodcastPollMinutes:         getEnvAsInt("PODCAST_POLL_MINUTES", 60),
+		EmailInIMAPAddr:            getEnv("EMAIL_IN_IMAP_ADDR", ""),
+		EmailInIMAPTLS:             getEnvAsBool("EMAIL_IN_IMAP_TLS", true),
+		EmailInUsername:            getEnv("EMAIL_IN_USERNAME", ""),
+		EmailInPassword:            getEnv("EMAIL_IN_PASSWORD", ""),
+		EmailInMailbox:             getEnv("EMAIL_IN_MAILBOX", "INBOX"),
+		EmailInPollSeconds:         getEnvAsInt("EMAIL_IN_POLL_SECONDS", 60),
+		EmailInAllowedSenders:      getEnv("EMAIL_IN_ALLOWED_SENDERS", ""),
+		EmailInOwner:               getEnv("EMAIL_IN_OWNER", ""),
+		SMTPAddr:                   getEnv("SMTP_ADDR", ""),
+		SMTPUsername:               getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:               getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:                   getEnv("SMTP_FROM", ""),
+		FakeProviders:          getEnvAsBool("FAKE_PROVIDERS", false),
 	}
+	if cfg.FakeProviders {
+		cfg.useFakeProviders()
+	}
+	return cfg
+}
+
+// useFakeProviders points embeddings and every LLM feature at the fake providers, without fallbacks
+func (c *Config) useFakeProviders() {
+	logger.Warn("FAKE_PROVIDERS is enabled: transcripts, embeddings and LLM replies are placeholders")
+	c.EmbeddingProvider = "fake"
+	c.EmbeddingModel = "fake-embedding"
+	c.SummaryLLMProvider = "fake"
+	c.SummaryLLMModel = "fake-model"
+	c.ChatLLMProvider = "fake"
+	c.ChatLLMModel = "fake-model"
+	c.SummaryLLMFallbacks = ""
+	c.ChatLLMFallbacks = ""
 }
 
 // getEnv gets an environment variable with a default value
@@ -77,6 +387,16 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvAsFloat gets an environment variable as float64 with a default value
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 // getEnvAsBool gets an environment variable as bool with a default value
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
@@ -0,0 +1,378 @@
+// Package dashboard aggregates transcription activity for the admin dashboard: how much audio
+// was processed, how long it took, which models and tags were used, and how much storage the
+// server is using.
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/export"
+	"scriberr/internal/models"
+	"scriberr/internal/resources"
+
+	"gorm.io/gorm"
+)
+
+// MaxBuckets caps the number of time buckets in a report
+const MaxBuckets = 500
+
+// DefaultTopTags is how many tags a report lists when the query doesn't say
+const DefaultTopTags = 10
+
+// loadBatchSize is how many jobs are loaded at a time, since each carries its transcript
+const loadBatchSize = 200
+
+// ErrInvalidQuery is returned by Summarize for an empty window or unusable bucket width
+var ErrInvalidQuery = errors.New("invalid dashboard query")
+
+// Query selects the jobs a report covers, by when they were created
+type Query struct {
+	Since   time.Time
+	Until   time.Time
+	Bucket  time.Duration // Width of each time series bucket
+	TopTags int           // How many tags to list (default DefaultTopTags)
+
+	// Where storage usage is measured; empty skips that part of the report
+	UploadDir    string
+	DatabasePath string
+}
+
+// Totals aggregates every job in the window
+type Totals struct {
+	Jobs                 int     `json:"jobs"`
+	Completed            int     `json:"completed"`
+	Failed               int     `json:"failed"`
+	AudioHours           float64 `json:"audio_hours"`            // Of completed jobs, from the end of their last segment
+	AvgProcessingSeconds float64 `json:"avg_processing_seconds"` // Of the latest successful run of each job
+}
+
+// ModelUsage aggregates the jobs transcribed with one model
+type ModelUsage struct {
+	ModelFamily          string  `json:"model_family"`
+	Model                string  `json:"model"`
+	Jobs                 int     `json:"jobs"`
+	Failed               int     `json:"failed"`
+	AudioHours           float64 `json:"audio_hours"`
+	AvgProcessingSeconds float64 `json:"avg_processing_seconds"`
+}
+
+// TagCount is how many jobs in the window carry a tag. Tags are counted ignoring case, since
+// each user has their own, and spelled as on the earliest job.
+type TagCount struct {
+	Tag  string `json:"tag"`
+	Jobs int    `json:"jobs"`
+}
+
+// Bucket aggregates the jobs created in one time bucket
+type Bucket struct {
+	Start                time.Time `json:"start"`
+	Jobs                 int       `json:"jobs"`
+	Completed            int       `json:"completed"`
+	Failed               int       `json:"failed"`
+	AudioHours           float64   `json:"audio_hours"`
+	AvgProcessingSeconds float64   `json:"avg_processing_seconds"`
+}
+
+// Storage is the space used by uploads and the database, and what's left on the upload
+// directory's file system, in bytes. It covers everything kept, not just the window.
+type Storage struct {
+	UploadBytes   int64  `json:"upload_bytes"`
+	UploadFiles   int    `json:"upload_files"`
+	DatabaseBytes int64  `json:"database_bytes"`
+	DiskFree      uint64 `json:"disk_free"`
+	DiskTotal     uint64 `json:"disk_total"`
+}
+
+// Report is the aggregate of the jobs selected by a Query
+type Report struct {
+	Since         time.Time    `json:"since"`
+	Until         time.Time    `json:"until"`
+	BucketSeconds int64        `json:"bucket_seconds"`
+	Totals        Totals       `json:"totals"`
+	Models        []ModelUsage `json:"models"`   // Most jobs first
+	TopTags       []TagCount   `json:"top_tags"` // Most jobs first
+	Series        []Bucket     `json:"series"`   // Oldest first, including empty buckets
+	Storage       *Storage     `json:"storage,omitempty"`
+}
+
+// job is what a report needs of a transcription job
+type job struct {
+	ID              string
+	Status          models.JobStatus
+	CreatedAt       time.Time
+	ModelFamily     string
+	Model           string
+	Tags            []string
+	AudioSeconds    float64
+	ProcessingMs    int64
+	HasProcessingMs bool
+}
+
+// DefaultBucket picks a time series bucket width for a window: hours for up to two days, so
+// a day's activity can be followed, then days, then weeks once there'd be too many days
+func DefaultBucket(window time.Duration) time.Duration {
+	switch {
+	case window <= 48*time.Hour:
+		return time.Hour
+	case window/(24*time.Hour) < MaxBuckets:
+		return 24 * time.Hour
+	default:
+		return 7 * 24 * time.Hour
+	}
+}
+
+// Summarize aggregates the jobs selected by q
+func Summarize(q Query) (*Report, error) {
+	if !q.Until.After(q.Since) {
+		return nil, fmt.Errorf("%w: until must be after since", ErrInvalidQuery)
+	}
+	if q.Bucket <= 0 || q.Until.Sub(q.Since)/q.Bucket >= MaxBuckets {
+		return nil, fmt.Errorf("%w: bucket must be positive and give at most %d buckets", ErrInvalidQuery, MaxBuckets)
+	}
+	if q.TopTags <= 0 {
+		q.TopTags = DefaultTopTags
+	}
+
+	jobs, err := loadJobs(q)
+	if err != nil {
+		return nil, err
+	}
+	report := aggregate(jobs, q)
+	if q.UploadDir != "" || q.DatabasePath != "" {
+		storage := MeasureStorage(q.UploadDir, q.DatabasePath)
+		report.Storage = &storage
+	}
+	return report, nil
+}
+
+// loadJobs loads the jobs created in q's window, oldest first, with the length of their audio and how long
+// their latest successful run took
+func loadJobs(q Query) ([]job, error) {
+	inWindow := func() *gorm.DB {
+		return database.DB.Model(&models.TranscriptionJob{}).Where("created_at >= ? AND created_at < ?", q.Since, q.Until)
+	}
+
+	var jobs []job
+	var batch []models.TranscriptionJob
+	err := inWindow().Select("id", "status", "created_at", "model_family", "model", "tags", "transcript").
+		FindInBatches(&batch, loadBatchSize, func(*gorm.DB, int) error {
+			for i := range batch {
+				loaded := &batch[i]
+				j := job{
+					ID:          loaded.ID,
+					Status:      loaded.Status,
+					CreatedAt:   loaded.CreatedAt,
+					ModelFamily: loaded.Parameters.ModelFamily,
+					Model:       loaded.Parameters.Model,
+					Tags:        loaded.Tags,
+				}
+				if loaded.Status == models.StatusCompleted {
+					for _, segment := range export.TranscriptSegments(loaded) {
+						j.AudioSeconds = math.Max(j.AudioSeconds, segment.End)
+					}
+				}
+				jobs = append(jobs, j)
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load jobs: %w", err)
+	}
+	// Batches come in ID order; a stable order keeps the spelling of each tag stable
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+
+	// Runs are loaded oldest first, so each job ends up with its latest
+	var executions []models.TranscriptionJobExecution
+	err = database.DB.Select("transcription_job_id", "processing_duration").
+		Where("status = ? AND processing_duration IS NOT NULL", models.StatusCompleted).
+		Where("transcription_job_id IN (?)", inWindow().Select("id")).
+		Order("created_at ASC").
+		Find(&executions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load job runs: %w", err)
+	}
+	durations := make(map[string]int64, len(executions))
+	for _, execution := range executions {
+		durations[execution.TranscriptionJobID] = *execution.ProcessingDuration
+	}
+	for i := range jobs {
+		jobs[i].ProcessingMs, jobs[i].HasProcessingMs = durations[jobs[i].ID]
+	}
+	return jobs, nil
+}
+
+// aggregate builds a report from jobs
+func aggregate(jobs []job, q Query) *Report {
+	count := int(q.Until.Sub(q.Since) / q.Bucket)
+	if q.Until.Sub(q.Since)%q.Bucket != 0 {
+		count++
+	}
+	report := &Report{
+		Since:         q.Since,
+		Until:         q.Until,
+		BucketSeconds: int64(q.Bucket / time.Second),
+		Models:        []ModelUsage{},
+		TopTags:       []TagCount{},
+		Series:        make([]Bucket, count),
+	}
+	for i := range report.Series {
+		report.Series[i].Start = q.Since.Add(time.Duration(i) * q.Bucket)
+	}
+
+	// timing averages processing times as they're added
+	type timing struct {
+		totalMs int64
+		runs    int
+	}
+	average := func(t timing) float64 {
+		if t.runs == 0 {
+			return 0
+		}
+		return round(float64(t.totalMs) / float64(t.runs) / 1000)
+	}
+	var total timing
+	bucketTimes := make([]timing, count)
+	type modelKey struct{ family, model string }
+	byModel := map[modelKey]*ModelUsage{}
+	modelTimes := map[modelKey]*timing{}
+	tags := map[string]*TagCount{}
+
+	for _, j := range jobs {
+		index := int(j.CreatedAt.Sub(q.Since) / q.Bucket)
+		if index < 0 || index >= count {
+			continue
+		}
+		bucket := &report.Series[index]
+		key := modelKey{j.ModelFamily, j.Model}
+		usage, ok := byModel[key]
+		if !ok {
+			usage = &ModelUsage{ModelFamily: j.ModelFamily, Model: j.Model}
+			byModel[key] = usage
+			modelTimes[key] = &timing{}
+		}
+
+		hours := j.AudioSeconds / 3600
+		report.Totals.Jobs++
+		report.Totals.AudioHours += hours
+		bucket.Jobs++
+		bucket.AudioHours += hours
+		usage.Jobs++
+		usage.AudioHours += hours
+		switch j.Status {
+		case models.StatusCompleted:
+			report.Totals.Completed++
+			bucket.Completed++
+		case models.StatusFailed:
+			report.Totals.Failed++
+			bucket.Failed++
+			usage.Failed++
+		}
+		if j.HasProcessingMs {
+			total.totalMs += j.ProcessingMs
+			total.runs++
+			bucketTimes[index].totalMs += j.ProcessingMs
+			bucketTimes[index].runs++
+			modelTimes[key].totalMs += j.ProcessingMs
+			modelTimes[key].runs++
+		}
+
+		seen := map[string]bool{}
+		for _, tag := range j.Tags {
+			name := strings.ToLower(strings.TrimSpace(tag))
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			if counted, ok := tags[name]; ok {
+				counted.Jobs++
+			} else {
+				tags[name] = &TagCount{Tag: strings.TrimSpace(tag), Jobs: 1}
+			}
+		}
+	}
+
+	report.Totals.AudioHours = round(report.Totals.AudioHours)
+	report.Totals.AvgProcessingSeconds = average(total)
+	for i := range report.Series {
+		report.Series[i].AudioHours = round(report.Series[i].AudioHours)
+		report.Series[i].AvgProcessingSeconds = average(bucketTimes[i])
+	}
+
+	for key, usage := range byModel {
+		usage.AudioHours = round(usage.AudioHours)
+		usage.AvgProcessingSeconds = average(*modelTimes[key])
+		report.Models = append(report.Models, *usage)
+	}
+	sort.Slice(report.Models, func(i, j int) bool {
+		a, b := report.Models[i], report.Models[j]
+		if a.Jobs != b.Jobs {
+			return a.Jobs > b.Jobs
+		}
+		if a.ModelFamily != b.ModelFamily {
+			return a.ModelFamily < b.ModelFamily
+		}
+		return a.Model < b.Model
+	})
+
+	for _, tag := range tags {
+		report.TopTags = append(report.TopTags, *tag)
+	}
+	sort.Slice(report.TopTags, func(i, j int) bool {
+		a, b := report.TopTags[i], report.TopTags[j]
+		if a.Jobs != b.Jobs {
+			return a.Jobs > b.Jobs
+		}
+		return strings.ToLower(a.Tag) < strings.ToLower(b.Tag)
+	})
+	if len(report.TopTags) > q.TopTags {
+		report.TopTags = report.TopTags[:q.TopTags]
+	}
+	return report
+}
+
+// MeasureStorage adds up the files under uploadDir and the database file with its journal,
+// and measures the space left on uploadDir's file system. Either may be empty.
+func MeasureStorage(uploadDir, databasePath string) Storage {
+	var storage Storage
+	if uploadDir != "" {
+		filepath.WalkDir(uploadDir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return nil
+			}
+			if info, err := entry.Info(); err == nil {
+				storage.UploadBytes += info.Size()
+				storage.UploadFiles++
+			}
+			return nil
+		})
+		usage := resources.Measure(uploadDir)
+		storage.DiskFree, storage.DiskTotal = usage.DiskFree, usage.DiskTotal
+	}
+	if databasePath != "" {
+		for _, path := range []string{databasePath, databasePath + "-wal", databasePath + "-shm"} {
+			if info, err := os.Stat(path); err == nil {
+				storage.DatabaseBytes += info.Size()
+			}
+		}
+	}
+	return storage
+}
+
+// round rounds to 2 decimal places, which is plenty for a chart
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
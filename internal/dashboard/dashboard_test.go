@@ -0,0 +1,102 @@
+package dashboard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"scriberr/internal/models"
+)
+
+func TestAggregate(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return since.Add(time.Duration(hours) * time.Hour) }
+	jobs := []job{
+		{ID: "a", Status: models.StatusCompleted, CreatedAt: at(1), ModelFamily: "whisper", Model: "small", Tags: []string{"Sales", "standup"}, AudioSeconds: 1800, ProcessingMs: 60000, HasProcessingMs: true},
+		{ID: "b", Status: models.StatusCompleted, CreatedAt: at(2), ModelFamily: "whisper", Model: "small", Tags: []string{"sales"}, AudioSeconds: 3600, ProcessingMs: 120000, HasProcessingMs: true},
+		{ID: "c", Status: models.StatusFailed, CreatedAt: at(30), ModelFamily: "nvidia_parakeet", Model: "small", Tags: []string{"standup", "Standup "}},
+		{ID: "d", Status: models.StatusPending, CreatedAt: at(50), ModelFamily: "whisper", Model: "small"},
+		{ID: "late", Status: models.StatusCompleted, CreatedAt: at(72), AudioSeconds: 3600},
+	}
+
+	report := aggregate(jobs, Query{Since: since, Until: at(72), Bucket: 24 * time.Hour, TopTags: 1})
+	totals := report.Totals
+	if totals.Jobs != 4 || totals.Completed != 2 || totals.Failed != 1 {
+		t.Errorf("unexpected totals %+v", totals)
+	}
+	if totals.AudioHours != 1.5 || totals.AvgProcessingSeconds != 90 {
+		t.Errorf("expected 1.5 hours taking 90s on average, got %+v", totals)
+	}
+
+	if len(report.Series) != 3 {
+		t.Fatalf("expected a bucket per day, got %+v", report.Series)
+	}
+	day := report.Series[0]
+	if !day.Start.Equal(since) || day.Jobs != 2 || day.Completed != 2 || day.AudioHours != 1.5 || day.AvgProcessingSeconds != 90 {
+		t.Errorf("unexpected first day %+v", day)
+	}
+	if report.Series[1].Failed != 1 || report.Series[2].Jobs != 1 || report.Series[2].AvgProcessingSeconds != 0 {
+		t.Errorf("unexpected later days %+v", report.Series[1:])
+	}
+
+	if len(report.Models) != 2 {
+		t.Fatalf("expected 2 models, got %+v", report.Models)
+	}
+	whisper, parakeet := report.Models[0], report.Models[1]
+	if whisper.ModelFamily != "whisper" || whisper.Jobs != 3 || whisper.AudioHours != 1.5 || whisper.AvgProcessingSeconds != 90 {
+		t.Errorf("unexpected whisper usage %+v", whisper)
+	}
+	if parakeet.ModelFamily != "nvidia_parakeet" || parakeet.Jobs != 1 || parakeet.Failed != 1 {
+		t.Errorf("unexpected parakeet usage %+v", parakeet)
+	}
+
+	// Sales and standup both have 2 jobs, counted once per job ignoring case
+	if len(report.TopTags) != 1 || report.TopTags[0].Tag != "Sales" || report.TopTags[0].Jobs != 2 {
+		t.Errorf("unexpected top tags %+v", report.TopTags)
+	}
+}
+
+func TestAggregatePartialBucket(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	report := aggregate(nil, Query{Since: since, Until: since.Add(36 * time.Hour), Bucket: 24 * time.Hour})
+	if len(report.Series) != 2 || report.Totals.Jobs != 0 || len(report.Models) != 0 || len(report.TopTags) != 0 {
+		t.Errorf("expected 2 empty buckets, got %+v", report)
+	}
+}
+
+func TestMeasureStorage(t *testing.T) {
+	dir := t.TempDir()
+	uploads := filepath.Join(dir, "uploads")
+	if err := os.MkdirAll(filepath.Join(uploads, "job"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(path string, size int) {
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(uploads, "a.wav"), 100)
+	write(filepath.Join(uploads, "job", "b.wav"), 50)
+	database := filepath.Join(dir, "scriberr.db")
+	write(database, 40)
+	write(database+"-wal", 2)
+
+	storage := MeasureStorage(uploads, database)
+	if storage.UploadBytes != 150 || storage.UploadFiles != 2 || storage.DatabaseBytes != 42 {
+		t.Errorf("unexpected storage %+v", storage)
+	}
+}
+
+func TestDefaultBucket(t *testing.T) {
+	for window, want := range map[time.Duration]time.Duration{
+		12 * time.Hour:       time.Hour,
+		48 * time.Hour:       time.Hour,
+		30 * 24 * time.Hour:  24 * time.Hour,
+		600 * 24 * time.Hour: 7 * 24 * time.Hour,
+	} {
+		if got := DefaultBucket(window); got != want {
+			t.Errorf("expected %v buckets for a %v window, got %v", want, window, got)
+		}
+	}
+}
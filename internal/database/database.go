@@ -74,6 +74,53 @@ func Initialize(dbPath string) error {
 		&models.Summary{},
 		&models.Note{},
 		&models.RefreshToken{},
+		&models.WorkflowRun{},
+		&models.WorkflowStep{},
+		&models.RAGEvalCase{},
+		&models.Document{},
+		&models.SmartFolder{},
+		&models.Topic{},
+		&models.Event{},
+		&models.LLMCall{},
+		&models.Translation{},
+		&models.ActionItem{},
+		&models.StandingContext{},
+		&models.Tag{},
+		&models.TranscriptionTag{},
+		&models.EntityMention{},
+		&models.ConversationAnalytics{},
+		&models.Chapter{},
+		&models.RAGAnswer{},
+		&models.RAGAnswerSource{},
+		&models.Redaction{},
+		&models.Watchlist{},
+		&models.WatchlistMatch{},
+		&models.TaskIntegration{},
+		&models.ActionItemDelivery{},
+		&models.DownloadLink{},
+		&models.ResummarizeRun{},
+		&models.Schedule{},
+		&models.ScheduleRun{},
+		&models.UploadSession{},
+		&models.URLImport{},
+		&models.JobTemplate{},
+		&models.PodcastFeed{},
+		&models.PodcastEpisode{},
+		&models.InboundEmail{},
+		&models.SpeakerProfile{},
+		&models.JobSpeaker{},
+		&models.TranscriptRevision{},
+		&models.TranscriptVersion{},
+		&models.BatchOperation{},
+		&models.DuplicateCandidate{},
+		&models.VocabularyTerm{},
+		&models.ExportTemplate{},
+		&models.Project{},
+		&models.ShareLink{},
+		&models.ShareLinkAccess{},
+		&models.QuotaUsage{},
+		&models.AuditLog{},
+		&models.JobLogEntry{},
 	); err != nil {
 		return fmt.Errorf("failed to auto migrate: %v", err)
 	}
@@ -82,6 +129,26 @@ func Initialize(dbPath string) error {
 	if err := DB.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_speaker_mappings_unique ON speaker_mappings(transcription_job_id, original_speaker)").Error; err != nil {
 		return fmt.Errorf("failed to create unique constraint for speaker mappings: %v", err)
 	}
+	if err := DB.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_job_speakers_unique ON job_speakers(transcription_job_id, speaker)").Error; err != nil {
+		return fmt.Errorf("failed to create unique constraint for job speakers: %v", err)
+	}
+
+	if err := setupTranscriptSearch(); err != nil {
+		return err
+	}
+
+	// The account that existed before roles did, the instance's first, is its admin
+	if err := DB.Exec(`UPDATE users SET role = 'admin' WHERE id = (SELECT MIN(id) FROM users)
+		AND NOT EXISTS (SELECT 1 FROM users WHERE role = 'admin')`).Error; err != nil {
+		return fmt.Errorf("failed to assign the admin role: %v", err)
+	}
+
+	// Jobs summarized before summary_model was tracked take the model of their latest saved summary
+	if err := DB.Exec(`UPDATE transcription_jobs SET summary_model = (
+		SELECT model FROM summaries WHERE summaries.transcription_id = transcription_jobs.id AND model <> '' ORDER BY created_at DESC LIMIT 1
+	) WHERE summary IS NOT NULL AND summary_model IS NULL`).Error; err != nil {
+		return fmt.Errorf("failed to backfill summary models: %v", err)
+	}
 
 	return nil
 }
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The full-text index of transcripts. transcript_segments holds every segment of every
+// transcript, with its time and speaker, and transcript_search is an FTS5 index of their
+// text. Triggers keep both in step with transcription_jobs.transcript, whichever code writes
+// it. A transcript that isn't JSON, or has no segments, is indexed as one untimed segment.
+const searchSchema = `
+CREATE TABLE IF NOT EXISTS transcript_segments (
+	id         INTEGER PRIMARY KEY,
+	job_id     VARCHAR(36) NOT NULL,
+	position   INTEGER NOT NULL,
+	start_time REAL,
+	end_time   REAL,
+	speaker    TEXT,
+	text       TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_transcript_segments_job ON transcript_segments(job_id);
+CREATE VIRTUAL TABLE IF NOT EXISTS transcript_search USING fts5(
+	text, content='transcript_segments', content_rowid='id', tokenize='unicode61 remove_diacritics 2'
+);
+CREATE TRIGGER IF NOT EXISTS transcript_segments_ai AFTER INSERT ON transcript_segments BEGIN
+	INSERT INTO transcript_search(rowid, text) VALUES (new.id, new.text);
+END;
+CREATE TRIGGER IF NOT EXISTS transcript_segments_ad AFTER DELETE ON transcript_segments BEGIN
+	INSERT INTO transcript_search(transcript_search, rowid, text) VALUES ('delete', old.id, old.text);
+END;
+CREATE TRIGGER IF NOT EXISTS transcription_jobs_search_ai AFTER INSERT ON transcription_jobs
+WHEN new.transcript IS NOT NULL AND new.id NOT LIKE 'track_%' BEGIN
+	{{index}}
+END;
+CREATE TRIGGER IF NOT EXISTS transcription_jobs_search_au AFTER UPDATE OF transcript ON transcription_jobs
+WHEN old.transcript IS NOT new.transcript AND new.id NOT LIKE 'track_%' BEGIN
+	DELETE FROM transcript_segments WHERE job_id = old.id;
+	{{index}}
+END;
+CREATE TRIGGER IF NOT EXISTS transcription_jobs_search_ad AFTER DELETE ON transcription_jobs BEGIN
+	DELETE FROM transcript_segments WHERE job_id = old.id;
+END;
+`
+
+// indexStatements returns the statements that index transcripts: the transcript of the
+// trigger row new, or with backfill those of every transcription
+func indexStatements(backfill bool) string {
+	row, segmentsFrom, plainFrom, where := "new", "", "", ""
+	if backfill {
+		row, segmentsFrom, plainFrom = "j", "transcription_jobs j, ", "FROM transcription_jobs j "
+		where = "j.transcript IS NOT NULL AND j.id NOT LIKE 'track_%' AND "
+	}
+	plain := fmt.Sprintf("CASE WHEN json_valid(%[1]s.transcript) THEN json_extract(%[1]s.transcript, '$.text') ELSE %[1]s.transcript END", row)
+	return fmt.Sprintf(`
+	INSERT INTO transcript_segments (job_id, position, start_time, end_time, speaker, text)
+	SELECT %[1]s.id, CAST(key AS INTEGER), json_extract(value, '$.start'), json_extract(value, '$.end'),
+		json_extract(value, '$.speaker'), json_extract(value, '$.text')
+	FROM %[2]sjson_each(CASE WHEN json_valid(%[1]s.transcript) THEN %[1]s.transcript ELSE '{}' END, '$.segments')
+	WHERE %[4]strim(COALESCE(json_extract(value, '$.text'), '')) <> '';
+	INSERT INTO transcript_segments (job_id, position, text)
+	SELECT %[1]s.id, 0, %[5]s
+	%[3]sWHERE %[4]strim(COALESCE(%[5]s, '')) <> ''
+		AND NOT EXISTS (SELECT 1 FROM transcript_segments WHERE transcript_segments.job_id = %[1]s.id);`,
+		row, segmentsFrom, plainFrom, where, plain)
+}
+
+// setupTranscriptSearch creates the full-text index of transcripts, filling it with the
+// transcripts saved before it existed
+func setupTranscriptSearch() error {
+	var existing int64
+	if err := DB.Raw("SELECT count(*) FROM sqlite_master WHERE name = 'transcript_search'").Scan(&existing).Error; err != nil {
+		return err
+	}
+	if err := DB.Exec(strings.ReplaceAll(searchSchema, "{{index}}", indexStatements(false))).Error; err != nil {
+		return fmt.Errorf("failed to create transcript search index: %w", err)
+	}
+	if existing == 0 {
+		if err := DB.Exec(indexStatements(true)).Error; err != nil {
+			return fmt.Errorf("failed to index existing transcripts: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Package documents ingests uploaded text documents (agendas, notes, PDFs) into the RAG index.
+package documents
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// ErrUnsupportedType is returned for files whose text can't be extracted
+var ErrUnsupportedType = errors.New("unsupported document type")
+
+// SupportedExtensions lists the file extensions that can be ingested
+var SupportedExtensions = []string{".txt", ".md", ".markdown", ".pdf"}
+
+// IsSupported reports whether a file name has a supported extension
+func IsSupported(fileName string) bool {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	for _, supported := range SupportedExtensions {
+		if ext == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractText returns the plain text of a document, choosing the parser by file extension
+func ExtractText(path string) (string, error) {
+	var text string
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".txt", ".md", ".markdown":
+		text, err = readPlainText(path)
+	case ".pdf":
+		text, err = readPDF(path)
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedType, filepath.Ext(path))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", fmt.Errorf("no text found in document")
+	}
+	return text, nil
+}
+
+// readPlainText reads a text file, rejecting binary content
+func readPlainText(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read document: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // UTF-8 byte order mark
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("document is not valid UTF-8 text")
+	}
+	return string(data), nil
+}
+
+// readPDF extracts the text layer of a PDF. Scanned PDFs without a text layer yield no text.
+func readPDF(path string) (text string, err error) {
+	// The PDF parser panics on some malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to parse PDF: %v", r)
+		}
+	}()
+
+	f, reader, err := pdf.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open PDF: %w", err)
+	}
+	defer f.Close()
+
+	var out strings.Builder
+	for i := 1; i <= reader.NumPage(); i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		pageText, err := page.GetPlainText(nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to read PDF page %d: %w", i, err)
+		}
+		out.WriteString(pageText)
+		out.WriteString("\n\n")
+	}
+	return out.String(), nil
+}
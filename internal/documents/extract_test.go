@@ -0,0 +1,44 @@
+package documents
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractTextPlain(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "agenda.md")
+	if err := os.WriteFile(path, []byte("\xef\xbb\xbf# Agenda\n\n1. Budget\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := ExtractText(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "# Agenda\n\n1. Budget" {
+		t.Errorf("unexpected text %q", text)
+	}
+}
+
+func TestExtractTextRejectsUnsupportedAndBinary(t *testing.T) {
+	dir := t.TempDir()
+
+	docx := filepath.Join(dir, "notes.docx")
+	os.WriteFile(docx, []byte("PK"), 0644)
+	if _, err := ExtractText(docx); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType, got %v", err)
+	}
+
+	binary := filepath.Join(dir, "notes.txt")
+	os.WriteFile(binary, []byte{0xff, 0xfe, 0x00, 0x81}, 0644)
+	if _, err := ExtractText(binary); err == nil {
+		t.Error("expected an error for non-UTF-8 content")
+	}
+
+	if !IsSupported("Minutes.PDF") || IsSupported("audio.mp3") {
+		t.Error("unexpected IsSupported result")
+	}
+}
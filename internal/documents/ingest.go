@@ -0,0 +1,103 @@
+package documents
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/pkg/logger"
+)
+
+// maxSummaryInputLength limits the document text sent to the LLM for summarization
+const maxSummaryInputLength = 10000
+
+// Ingester summarizes uploaded documents and indexes them into RAG
+type Ingester struct {
+	rag   *rag.RAGService
+	llm   rag.LLMService
+	model string
+}
+
+// NewIngester creates an ingester that summarizes with model and indexes into ragService
+func NewIngester(ragService *rag.RAGService, llmService rag.LLMService, model string) *Ingester {
+	return &Ingester{
+		rag:   ragService,
+		llm:   llmService,
+		model: model,
+	}
+}
+
+// Ingest summarizes and indexes a document whose text has already been extracted, recording the
+// outcome on the document. A failed summary doesn't prevent indexing.
+func (i *Ingester) Ingest(ctx context.Context, documentID string) error {
+	var doc models.Document
+	if err := database.DB.Where("id = ?", documentID).First(&doc).Error; err != nil {
+		return fmt.Errorf("failed to load document %s: %w", documentID, err)
+	}
+	if doc.Content == nil || *doc.Content == "" {
+		return i.fail(&doc, fmt.Errorf("document has no extracted text"))
+	}
+
+	summary, err := i.summarize(ctx, *doc.Content)
+	if err != nil {
+		logger.WarnContext(ctx, "Document summary failed, indexing without it", "component", "documents", "document_id", doc.ID, "error", err)
+	} else {
+		doc.Summary = &summary
+	}
+
+	chunks, err := i.rag.StoreDocument(&doc, *doc.Content, summary)
+	if err != nil {
+		return i.fail(&doc, err)
+	}
+
+	now := time.Now()
+	doc.ChunkCount = chunks
+	doc.Status = models.DocumentIndexed
+	doc.Error = nil
+	doc.IndexedAt = &now
+	if err := database.DB.Save(&doc).Error; err != nil {
+		return fmt.Errorf("failed to update document %s: %w", doc.ID, err)
+	}
+	logger.InfoContext(ctx, "Indexed document", "component", "documents", "document_id", doc.ID, "title", doc.Title, "chunks", chunks)
+	return nil
+}
+
+// Remove deletes a document's chunks from the vector store
+func (i *Ingester) Remove(doc *models.Document) error {
+	return i.rag.DeleteDocument(doc)
+}
+
+// summarize asks the LLM for a short summary of the document text
+func (i *Ingester) summarize(ctx context.Context, text string) (string, error) {
+	if len(text) > maxSummaryInputLength {
+		text = text[:maxSummaryInputLength] + "... [truncated]"
+	}
+	messages := []llm.ChatMessage{
+		{Role: "user", Content: fmt.Sprintf("Please provide a concise summary of the following document:\n\n%s", text)},
+	}
+
+	response, err := i.llm.ChatCompletion(ctx, i.model, messages, 0.3)
+	if err != nil {
+		return "", fmt.Errorf("LLM call failed: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("no response from LLM")
+	}
+	return response.Choices[0].Message.Content, nil
+}
+
+// fail records an indexing error on the document and returns it
+func (i *Ingester) fail(doc *models.Document, err error) error {
+	msg := err.Error()
+	doc.Status = models.DocumentFailed
+	doc.Error = &msg
+	if saveErr := database.DB.Save(doc).Error; saveErr != nil {
+		logger.Error("Failed to record document error", "component", "documents", "document_id", doc.ID, "error", saveErr)
+	}
+	logger.Error("Failed to index document", "component", "documents", "document_id", doc.ID, "error", err)
+	return err
+}
@@ -1,9 +1,9 @@
 package dropzone
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,6 +12,7 @@ import (
 	"scriberr/internal/config"
 	"scriberr/internal/database"
 	"scriberr/internal/models"
+	"scriberr/pkg/logger"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/google/uuid"
@@ -22,12 +23,18 @@ type TaskQueue interface {
 	EnqueueJob(jobID string) error
 }
 
+// FileStore keeps uploaded files in object storage
+type FileStore interface {
+	Upload(ctx context.Context, localPath string) error
+}
+
 // Service manages the dropzone file monitoring
 type Service struct {
 	config       *config.Config
 	watcher      *fsnotify.Watcher
 	dropzonePath string
 	taskQueue    TaskQueue
+	fileStore    FileStore
 }
 
 // NewService creates a new dropzone service
@@ -39,16 +46,21 @@ func NewService(cfg *config.Config, taskQueue TaskQueue) *Service {
 	}
 }
 
+// SetFileStore uploads the files picked up from the dropzone to object storage too
+func (s *Service) SetFileStore(store FileStore) {
+	s.fileStore = store
+}
+
 // Start initializes the dropzone directory and starts file monitoring
 func (s *Service) Start() error {
-	log.Printf("Starting dropzone service...")
+	logger.Info("Starting dropzone service", "component", "dropzone")
 
 	// Create dropzone directory if it doesn't exist
 	if err := os.MkdirAll(s.dropzonePath, 0755); err != nil {
 		return fmt.Errorf("failed to create dropzone directory: %v", err)
 	}
 
-	log.Printf("Dropzone directory created/verified at: %s", s.dropzonePath)
+	logger.Info("Dropzone directory ready", "component", "dropzone", "path", s.dropzonePath)
 
 	// Initialize file watcher
 	watcher, err := fsnotify.NewWatcher()
@@ -65,20 +77,20 @@ func (s *Service) Start() error {
 
 	// Process existing files recursively on startup
 	if err := s.processExistingFiles(); err != nil {
-		log.Printf("Warning: failed to process some existing files: %v", err)
+		logger.Warn("Failed to process some existing dropzone files", "component", "dropzone", "error", err)
 	}
 
 	// Start monitoring in a goroutine
 	go s.watchFiles()
 
-	log.Printf("Dropzone service started, monitoring recursively: %s", s.dropzonePath)
+	logger.Info("Dropzone service started", "component", "dropzone", "path", s.dropzonePath)
 	return nil
 }
 
 // Stop stops the dropzone service
 func (s *Service) Stop() error {
 	if s.watcher != nil {
-		log.Printf("Stopping dropzone service...")
+		logger.Info("Stopping dropzone service", "component", "dropzone")
 		return s.watcher.Close()
 	}
 	return nil
@@ -88,17 +100,17 @@ func (s *Service) Stop() error {
 func (s *Service) addDirectoryRecursively(root string) error {
 	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			log.Printf("Warning: error accessing path %s: %v", path, err)
+			logger.Warn("Failed to access dropzone path", "component", "dropzone", "path", path, "error", err)
 			return nil // Continue walking despite errors
 		}
 
 		// Only add directories to the watcher
 		if info.IsDir() {
 			if err := s.watcher.Add(path); err != nil {
-				log.Printf("Warning: failed to watch directory %s: %v", path, err)
+				logger.Warn("Failed to watch dropzone directory", "component", "dropzone", "path", path, "error", err)
 				return nil // Continue despite individual directory failures
 			}
-			log.Printf("Added directory to watcher: %s", path)
+			logger.Debug("Watching dropzone directory", "component", "dropzone", "path", path)
 		}
 
 		return nil
@@ -109,7 +121,7 @@ func (s *Service) addDirectoryRecursively(root string) error {
 func (s *Service) processExistingFiles() error {
 	return filepath.Walk(s.dropzonePath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			log.Printf("Warning: error accessing path %s: %v", path, err)
+			logger.Warn("Failed to access dropzone path", "component", "dropzone", "path", path, "error", err)
 			return nil // Continue walking despite errors
 		}
 
@@ -117,7 +129,7 @@ func (s *Service) processExistingFiles() error {
 		if !info.IsDir() {
 			filename := filepath.Base(path)
 			if s.isAudioFile(filename) {
-				log.Printf("Processing existing audio file: %s", path)
+				logger.Info("Processing existing dropzone file", "component", "dropzone", "path", path)
 				s.processFile(path)
 			}
 		}
@@ -126,6 +138,28 @@ func (s *Service) processExistingFiles() error {
 	})
 }
 
+// Scan uploads every audio file waiting in the dropzone, as on startup, and returns how many
+// were uploaded. It is for picking up files the watcher missed, such as those copied in while
+// the server was down or onto a mount that doesn't report changes.
+func (s *Service) Scan() (int, error) {
+	if err := os.MkdirAll(s.dropzonePath, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create dropzone directory: %v", err)
+	}
+
+	uploaded := 0
+	err := filepath.Walk(s.dropzonePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			logger.Warn("Failed to access dropzone path", "component", "dropzone", "path", path, "error", err)
+			return nil // Continue walking despite errors
+		}
+		if !info.IsDir() && s.isAudioFile(filepath.Base(path)) && s.processFile(path) {
+			uploaded++
+		}
+		return nil
+	})
+	return uploaded, err
+}
+
 // watchFiles monitors the dropzone directory for new files
 func (s *Service) watchFiles() {
 	for {
@@ -139,13 +173,13 @@ func (s *Service) watchFiles() {
 			if event.Op&fsnotify.Create == fsnotify.Create {
 				// Check if the created item is a directory
 				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
-					log.Printf("Detected new directory in dropzone: %s", event.Name)
+					logger.Debug("Detected new dropzone directory", "component", "dropzone", "path", event.Name)
 					// Add the new directory to the watcher recursively
 					if err := s.addDirectoryRecursively(event.Name); err != nil {
-						log.Printf("Failed to watch new directory %s: %v", event.Name, err)
+						logger.Warn("Failed to watch dropzone directory", "component", "dropzone", "path", event.Name, "error", err)
 					}
 				} else {
-					log.Printf("Detected new file in dropzone: %s", event.Name)
+					logger.Debug("Detected new dropzone file", "component", "dropzone", "path", event.Name)
 					s.processFile(event.Name)
 				}
 			}
@@ -154,7 +188,7 @@ func (s *Service) watchFiles() {
 			if !ok {
 				return
 			}
-			log.Printf("Dropzone watcher error: %v", err)
+			logger.Error("Dropzone watcher error", "component", "dropzone", "error", err)
 		}
 	}
 }
@@ -175,8 +209,8 @@ func (s *Service) isAudioFile(filename string) bool {
 	return false
 }
 
-// processFile handles a newly detected file in the dropzone
-func (s *Service) processFile(filePath string) {
+// processFile handles a newly detected file in the dropzone, reporting whether it was uploaded
+func (s *Service) processFile(filePath string) bool {
 	// Small delay to ensure file is fully written
 	time.Sleep(500 * time.Millisecond)
 
@@ -184,36 +218,37 @@ func (s *Service) processFile(filePath string) {
 
 	// Check if it's an audio file
 	if !s.isAudioFile(filename) {
-		log.Printf("Skipping non-audio file: %s", filename)
-		return
+		logger.Debug("Skipping non-audio dropzone file", "component", "dropzone", "filename", filename)
+		return false
 	}
 
 	// Check if file exists and is accessible
 	fileInfo, err := os.Stat(filePath)
 	if err != nil {
-		log.Printf("Error accessing file %s: %v", filePath, err)
-		return
+		logger.Warn("Failed to access dropzone file", "component", "dropzone", "path", filePath, "error", err)
+		return false
 	}
 
 	// Skip if it's a directory
 	if fileInfo.IsDir() {
-		return
+		return false
 	}
 
-	log.Printf("Processing audio file: %s", filename)
+	logger.Info("Processing dropzone file", "component", "dropzone", "filename", filename)
 
 	// Upload the file using the same logic as the API handler
 	if err := s.uploadFile(filePath, filename); err != nil {
-		log.Printf("Failed to upload file %s: %v", filename, err)
-		return
+		logger.Error("Failed to upload dropzone file", "component", "dropzone", "filename", filename, "error", err)
+		return false
 	}
 
 	// Delete the original file from dropzone after successful upload
 	if err := os.Remove(filePath); err != nil {
-		log.Printf("Warning: Failed to delete file from dropzone %s: %v", filePath, err)
+		logger.Warn("Failed to delete dropzone file", "component", "dropzone", "path", filePath, "error", err)
 	} else {
-		log.Printf("Successfully processed and removed file: %s", filename)
+		logger.Info("Processed and removed dropzone file", "component", "dropzone", "filename", filename)
 	}
+	return true
 }
 
 // uploadFile uploads the file using the existing pipeline logic
@@ -234,6 +269,12 @@ func (s *Service) uploadFile(sourcePath, originalFilename string) error {
 	if err := s.copyFile(sourcePath, destPath); err != nil {
 		return fmt.Errorf("failed to copy file: %v", err)
 	}
+	if s.fileStore != nil {
+		if err := s.fileStore.Upload(context.Background(), destPath); err != nil {
+			os.Remove(destPath)
+			return fmt.Errorf("failed to store file: %v", err)
+		}
+	}
 
 	// Create job record with "uploaded" status
 	job := models.TranscriptionJob{
@@ -253,25 +294,25 @@ func (s *Service) uploadFile(sourcePath, originalFilename string) error {
 	if s.isAutoTranscriptionEnabled() {
 		// Multi-track files should never be auto-transcribed
 		if job.IsMultiTrack {
-			log.Printf("Skipping auto-transcription for multi-track job %s", jobID)
+			logger.Info("Skipping auto-transcription for multi-track job", "component", "dropzone", "job_id", jobID)
 		} else {
-			log.Printf("Auto-transcription enabled, enqueueing job %s", jobID)
+			logger.Debug("Auto-transcription enabled, enqueueing job", "component", "dropzone", "job_id", jobID)
 
 			// Update job status to pending before enqueueing
 			if err := database.DB.Model(&job).Update("status", models.StatusPending).Error; err != nil {
-				log.Printf("Warning: Failed to update job status to pending: %v", err)
+				logger.Warn("Failed to update job status to pending", "component", "dropzone", "job_id", jobID, "error", err)
 			}
 
 			// Enqueue the job for transcription
 			if err := s.taskQueue.EnqueueJob(jobID); err != nil {
-				log.Printf("Failed to enqueue job %s for transcription: %v", jobID, err)
+				logger.Error("Failed to enqueue job for transcription", "component", "dropzone", "job_id", jobID, "error", err)
 			} else {
-				log.Printf("Job %s enqueued for auto-transcription", jobID)
+				logger.Info("Job enqueued for auto-transcription", "component", "dropzone", "job_id", jobID)
 			}
 		}
 	}
 
-	log.Printf("Successfully uploaded file %s as job %s", originalFilename, jobID)
+	logger.Info("Uploaded dropzone file", "component", "dropzone", "filename", originalFilename, "job_id", jobID)
 	return nil
 }
 
@@ -285,7 +326,7 @@ func (s *Service) isAutoTranscriptionEnabled() bool {
 		Count(&count).Error
 
 	if err != nil {
-		log.Printf("Error checking auto-transcription settings: %v", err)
+		logger.Error("Failed to check auto-transcription settings", "component", "dropzone", "error", err)
 		return false
 	}
 
@@ -0,0 +1,362 @@
+// Package duplicates finds transcriptions that are near-duplicates of each other, such as one
+// meeting recorded by two people or an edited recording uploaded again, by comparing their
+// whole-transcription embeddings. The pairs it finds are kept as DuplicateCandidates until
+// someone merges or ignores them.
+package duplicates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/transcription"
+	"scriberr/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultMinSimilarity is the cosine similarity from which two transcriptions are compared word for word
+	DefaultMinSimilarity = 0.95
+	// DefaultMinOverlap is the share of word sequences two transcriptions must have in common to be flagged
+	DefaultMinOverlap = 0.3
+
+	// shingleSize is the length of the word sequences compared by Overlap
+	shingleSize = 3
+	// lookupBatch caps the IDs in one query, well under SQLite's variable limit
+	lookupBatch = 500
+)
+
+// Options are the thresholds a pair of transcriptions must reach to be flagged
+type Options struct {
+	// MinSimilarity is the least cosine similarity of their embeddings
+	MinSimilarity float64 `json:"min_similarity"`
+	// MinOverlap is the least share of the shorter transcript's word sequences found in the
+	// other. It tells the same conversation apart from one on the same subject, such as last
+	// week's standup; 0 flags on similarity alone.
+	MinOverlap float64 `json:"min_overlap"`
+}
+
+// DefaultOptions returns the default thresholds
+func DefaultOptions() Options {
+	return Options{MinSimilarity: DefaultMinSimilarity, MinOverlap: DefaultMinOverlap}
+}
+
+// Validate checks that the thresholds are between 0 and 1
+func (o Options) Validate() error {
+	if o.MinSimilarity <= 0 || o.MinSimilarity > 1 {
+		return errors.New("min_similarity must be greater than 0 and at most 1")
+	}
+	if o.MinOverlap < 0 || o.MinOverlap > 1 {
+		return errors.New("min_overlap must be between 0 and 1")
+	}
+	return nil
+}
+
+// Entry is a transcription to compare
+type Entry struct {
+	TranscriptionID string
+	Embedding       []float32
+	Text            string // Plain transcript text
+}
+
+// Pair is two transcriptions found to be near-duplicates. First comes before Second in the
+// entries given to Find.
+type Pair struct {
+	FirstID    string
+	SecondID   string
+	Similarity float64
+	Overlap    float64
+}
+
+// Find compares every pair of entries and returns those reaching the thresholds, most similar
+// first
+func Find(ctx context.Context, entries []Entry, opts Options) ([]Pair, error) {
+	vectors := make([][]float64, len(entries))
+	for i, entry := range entries {
+		vectors[i] = normalize(entry.Embedding)
+	}
+	shingles := make([]map[string]bool, len(entries))
+	shinglesOf := func(i int) map[string]bool {
+		if shingles[i] == nil {
+			shingles[i] = Shingles(entries[i].Text)
+		}
+		return shingles[i]
+	}
+
+	var pairs []Pair
+	for i := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for j := i + 1; j < len(entries); j++ {
+			if len(vectors[i]) != len(vectors[j]) {
+				continue // Embedded by different models
+			}
+			similarity := dot(vectors[i], vectors[j])
+			if similarity < opts.MinSimilarity {
+				continue
+			}
+			overlap := Overlap(shinglesOf(i), shinglesOf(j))
+			if overlap < opts.MinOverlap {
+				continue
+			}
+			pairs = append(pairs, Pair{
+				FirstID:    entries[i].TranscriptionID,
+				SecondID:   entries[j].TranscriptionID,
+				Similarity: round(similarity),
+				Overlap:    round(overlap),
+			})
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].Similarity > pairs[j].Similarity })
+	return pairs, nil
+}
+
+// Shingles returns the sequences of shingleSize words in text, lowercased and without
+// punctuation. Texts shorter than that are one sequence.
+func Shingles(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	set := make(map[string]bool)
+	if len(words) == 0 {
+		return set
+	}
+	if len(words) < shingleSize {
+		set[strings.Join(words, " ")] = true
+		return set
+	}
+	for i := 0; i+shingleSize <= len(words); i++ {
+		set[strings.Join(words[i:i+shingleSize], " ")] = true
+	}
+	return set
+}
+
+// Overlap returns the share of the smaller set's shingles found in the other, so a trimmed
+// copy of a recording overlaps the full one completely
+func Overlap(a, b map[string]bool) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if len(a) == 0 {
+		return 0
+	}
+	shared := 0
+	for shingle := range a {
+		if b[shingle] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a))
+}
+
+// Report counts what a scan did
+type Report struct {
+	Collections int `json:"collections"`
+	Compared    int `json:"compared"` // Transcriptions compared
+	Found       int `json:"found"`    // Pairs reaching the thresholds, including ones flagged before
+	New         int `json:"new"`      // Pairs flagged for the first time
+	Cleared     int `json:"cleared"`  // Pending pairs no longer reaching the thresholds
+}
+
+func (r *Report) add(other Report) {
+	r.Collections += other.Collections
+	r.Compared += other.Compared
+	r.Found += other.Found
+	r.New += other.New
+	r.Cleared += other.Cleared
+}
+
+// Scan looks for near-duplicates in each collection in turn, stopping at the first failure
+func Scan(ctx context.Context, ragService *rag.RAGService, collections []string, opts Options) (Report, error) {
+	var report Report
+	for _, collection := range collections {
+		found, err := ScanCollection(ctx, ragService, collection, opts)
+		if err != nil {
+			return report, fmt.Errorf("failed to scan %s: %w", collection, err)
+		}
+		report.add(found)
+	}
+	return report, nil
+}
+
+// ScanCollection compares the transcriptions indexed in a collection and the collections routed
+// next to it, flagging new near-duplicate pairs as pending. Pairs that were merged or ignored
+// stay so, and pending pairs that no longer reach the thresholds, for instance after an edit,
+// are dropped.
+func ScanCollection(ctx context.Context, ragService *rag.RAGService, collection string, opts Options) (Report, error) {
+	if err := opts.Validate(); err != nil {
+		return Report{}, err
+	}
+	indexed, err := ragService.SummaryEmbeddings(ctx, collection)
+	if err != nil {
+		return Report{}, err
+	}
+	entries, err := entriesOf(indexed)
+	if err != nil {
+		return Report{}, err
+	}
+	pairs, err := Find(ctx, entries, opts)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{Collections: 1, Compared: len(entries), Found: len(pairs)}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var existing []models.DuplicateCandidate
+		if err := tx.Where("collection = ?", collection).Find(&existing).Error; err != nil {
+			return err
+		}
+		known := make(map[[2]string]*models.DuplicateCandidate, len(existing))
+		for i := range existing {
+			candidate := &existing[i]
+			known[[2]string{candidate.FirstID, candidate.SecondID}] = candidate
+			known[[2]string{candidate.SecondID, candidate.FirstID}] = candidate
+		}
+
+		seen := make(map[uint]bool, len(pairs))
+		for _, pair := range pairs {
+			if candidate, ok := known[[2]string{pair.FirstID, pair.SecondID}]; ok {
+				seen[candidate.ID] = true
+				if candidate.Status == models.DuplicatePending {
+					if err := tx.Model(candidate).Updates(map[string]interface{}{"similarity": pair.Similarity, "overlap": pair.Overlap}).Error; err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			candidate := models.DuplicateCandidate{
+				Collection: collection,
+				FirstID:    pair.FirstID,
+				SecondID:   pair.SecondID,
+				Similarity: pair.Similarity,
+				Overlap:    pair.Overlap,
+				Status:     models.DuplicatePending,
+			}
+			if err := tx.Create(&candidate).Error; err != nil {
+				return err
+			}
+			report.New++
+		}
+
+		var cleared []uint
+		for _, candidate := range existing {
+			if candidate.Status == models.DuplicatePending && !seen[candidate.ID] {
+				cleared = append(cleared, candidate.ID)
+			}
+		}
+		if len(cleared) > 0 {
+			if err := tx.Delete(&models.DuplicateCandidate{}, cleared).Error; err != nil {
+				return err
+			}
+		}
+		report.Cleared = len(cleared)
+		return nil
+	})
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to save duplicates: %w", err)
+	}
+	if report.New > 0 || report.Cleared > 0 {
+		logger.InfoContext(ctx, "Scanned for duplicates", "component", "duplicates", "collection", collection, "compared", report.Compared, "found", report.Found, "new", report.New, "cleared", report.Cleared)
+	}
+	return report, nil
+}
+
+// entriesOf pairs indexed transcriptions with their transcript text, oldest first, leaving out
+// those deleted since they were indexed and those already merged into another
+func entriesOf(indexed []rag.SummaryEmbedding) ([]Entry, error) {
+	byID := make(map[string]rag.SummaryEmbedding, len(indexed))
+	ids := make([]string, 0, len(indexed))
+	for _, entry := range indexed {
+		if _, ok := byID[entry.TranscriptionID]; !ok {
+			ids = append(ids, entry.TranscriptionID)
+		}
+		byID[entry.TranscriptionID] = entry
+	}
+
+	var jobs []models.TranscriptionJob
+	for start := 0; start < len(ids); start += lookupBatch {
+		end := start + lookupBatch
+		if end > len(ids) {
+			end = len(ids)
+		}
+		var batch []models.TranscriptionJob
+		if err := database.DB.Select("id", "transcript", "created_at").
+			Where("id IN ?", ids[start:end]).Where("duplicate_of IS NULL").
+			Find(&batch).Error; err != nil {
+			return nil, fmt.Errorf("failed to look up transcriptions: %w", err)
+		}
+		jobs = append(jobs, batch...)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+
+	entries := make([]Entry, 0, len(jobs))
+	for _, job := range jobs {
+		entry := Entry{TranscriptionID: job.ID, Embedding: byID[job.ID].Embedding}
+		if job.Transcript != nil {
+			text, err := transcription.ExtractTranscriptText(*job.Transcript)
+			if err != nil {
+				text = *job.Transcript
+			}
+			entry.Text = text
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Resolve records how a pending pair was settled
+func Resolve(tx *gorm.DB, candidate *models.DuplicateCandidate, status string, keptID *string, userID *uint) error {
+	now := time.Now()
+	candidate.Status, candidate.KeptID, candidate.ResolvedBy, candidate.ResolvedAt = status, keptID, userID, &now
+	return tx.Model(candidate).Updates(map[string]interface{}{
+		"status":      status,
+		"kept_id":     keptID,
+		"resolved_by": userID,
+		"resolved_at": now,
+	}).Error
+}
+
+func normalize(v []float32) []float64 {
+	out := make([]float64, len(v))
+	var norm float64
+	for i, x := range v {
+		out[i] = float64(x)
+		norm += out[i] * out[i]
+	}
+	if norm == 0 {
+		return out
+	}
+	norm = math.Sqrt(norm)
+	for i := range out {
+		out[i] /= norm
+	}
+	return out
+}
+
+func dot(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// round keeps four decimals, enough to compare against a threshold
+func round(x float64) float64 {
+	return math.Round(x*10000) / 10000
+}
@@ -0,0 +1,63 @@
+package duplicates
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOverlap(t *testing.T) {
+	full := Shingles("We agreed to ship the release on Friday, and Bob owns it.")
+	trimmed := Shingles("we agreed to ship the release on friday")
+	if got := Overlap(full, trimmed); got != 1 {
+		t.Errorf("expected a trimmed copy to overlap completely, got %v", got)
+	}
+	other := Shingles("Budget review comes first on Monday.")
+	if got := Overlap(full, other); got != 0 {
+		t.Errorf("expected no overlap, got %v", got)
+	}
+	if got := Overlap(Shingles(""), full); got != 0 {
+		t.Errorf("expected no overlap with nothing, got %v", got)
+	}
+	if got := len(Shingles("Hi there")); got != 1 {
+		t.Errorf("expected a short text to be one sequence, got %d", got)
+	}
+}
+
+func TestFind(t *testing.T) {
+	entries := []Entry{
+		{TranscriptionID: "a", Embedding: []float32{1, 0, 0}, Text: "we ship on friday and bob owns the release"},
+		{TranscriptionID: "b", Embedding: []float32{0.99, 0.05, 0}, Text: "we ship on friday and bob owns the release notes"},
+		// As alike as b, but a different conversation
+		{TranscriptionID: "c", Embedding: []float32{0.98, 0.05, 0}, Text: "standup again this week with nothing new to report"},
+		{TranscriptionID: "d", Embedding: []float32{0, 1, 0}, Text: "we ship on friday and bob owns the release"},
+		// Embedded by another model
+		{TranscriptionID: "e", Embedding: []float32{1, 0}, Text: "we ship on friday and bob owns the release"},
+	}
+	pairs, err := Find(context.Background(), entries, DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 1 || pairs[0].FirstID != "a" || pairs[0].SecondID != "b" || pairs[0].Overlap != 1 {
+		t.Fatalf("expected only a and b, got %+v", pairs)
+	}
+
+	// Without the overlap check, similarity alone decides
+	pairs, err = Find(context.Background(), entries, Options{MinSimilarity: 0.95})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 3 || pairs[0].Similarity < pairs[2].Similarity {
+		t.Errorf("expected three pairs, most similar first, got %+v", pairs)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, opts := range []Options{{MinSimilarity: 0}, {MinSimilarity: 1.1}, {MinSimilarity: 0.9, MinOverlap: -0.1}, {MinSimilarity: 0.9, MinOverlap: 2}} {
+		if err := opts.Validate(); err == nil {
+			t.Errorf("expected %+v to be rejected", opts)
+		}
+	}
+	if err := DefaultOptions().Validate(); err != nil {
+		t.Errorf("expected the defaults to be valid: %v", err)
+	}
+}
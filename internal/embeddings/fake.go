@@ -0,0 +1,63 @@
+package embeddings
+
+import (
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// FakeDimensions is the length of the vectors produced by the fake embedding service
+const FakeDimensions = 256
+
+// FakeEmbeddingService produces deterministic embeddings without a model, for tests and
+// local development. Each word is hashed into one of FakeDimensions buckets and the counts
+// are normalized, so texts sharing words are closer than unrelated ones.
+type FakeEmbeddingService struct{}
+
+// NewFakeEmbeddingService creates a fake embedding service
+func NewFakeEmbeddingService() *FakeEmbeddingService {
+	return &FakeEmbeddingService{}
+}
+
+// Model returns the name of the embedding model in use
+func (s *FakeEmbeddingService) Model() string {
+	return "fake-embedding"
+}
+
+// GenerateEmbedding generates an embedding for the given text
+func (s *FakeEmbeddingService) GenerateEmbedding(text string) ([]float32, error) {
+	vector := make([]float32, FakeDimensions)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, word := range words {
+		h := fnv.New32a()
+		h.Write([]byte(word))
+		vector[h.Sum32()%FakeDimensions]++
+	}
+
+	var norm float64
+	for _, x := range vector {
+		norm += float64(x) * float64(x)
+	}
+	if norm == 0 {
+		// Empty text still gets a valid unit vector
+		vector[0] = 1
+		return vector, nil
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range vector {
+		vector[i] *= scale
+	}
+	return vector, nil
+}
+
+// GenerateEmbeddings generates embeddings for multiple texts, in order
+func (s *FakeEmbeddingService) GenerateEmbeddings(texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = s.GenerateEmbedding(text)
+	}
+	return vectors, nil
+}
@@ -0,0 +1,102 @@
+package embeddings
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPEmbeddingService calls a user-provided embedding service. The contract is a single
+// POST of {"model": "...", "inputs": ["..."]} answered with {"vectors": [[...]]}, one
+// vector per input in the same order.
+type HTTPEmbeddingService struct {
+	url    string
+	model  string
+	apiKey string
+	client *http.Client
+}
+
+// HTTPEmbeddingRequest is the body sent to a custom embedding service
+type HTTPEmbeddingRequest struct {
+	Model  string   `json:"model"`
+	Inputs []string `json:"inputs"`
+}
+
+// HTTPEmbeddingResponse is the body expected back from a custom embedding service
+type HTTPEmbeddingResponse struct {
+	Vectors [][]float32 `json:"vectors"`
+}
+
+// NewHTTPEmbeddingService creates a client for a custom embedding service at url. A non-empty
+// apiKey is sent as a bearer token.
+func NewHTTPEmbeddingService(url, model, apiKey string) *HTTPEmbeddingService {
+	return &HTTPEmbeddingService{
+		url:    url,
+		model:  model,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 120 * time.Second},
+	}
+}
+
+// Model returns the name of the embedding model in use
+func (s *HTTPEmbeddingService) Model() string {
+	return s.model
+}
+
+// GenerateEmbedding generates an embedding for the given text
+func (s *HTTPEmbeddingService) GenerateEmbedding(text string) ([]float32, error) {
+	vectors, err := s.GenerateEmbeddings([]string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+// GenerateEmbeddings generates embeddings for multiple texts in one request
+func (s *HTTPEmbeddingService) GenerateEmbeddings(texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+
+	data, err := json.Marshal(HTTPEmbeddingRequest{Model: s.model, Inputs: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", s.url, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var embedResp HTTPEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(embedResp.Vectors) != len(texts) {
+		return nil, fmt.Errorf("embedding service returned %d vectors for %d inputs", len(embedResp.Vectors), len(texts))
+	}
+	for i, vector := range embedResp.Vectors {
+		if len(vector) == 0 {
+			return nil, fmt.Errorf("embedding service returned an empty vector for input %d", i)
+		}
+	}
+	return embedResp.Vectors, nil
+}
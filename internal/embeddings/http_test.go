@@ -0,0 +1,73 @@
+package embeddings
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPEmbeddingServiceContract(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("expected bearer token, got %q", got)
+		}
+		var req HTTPEmbeddingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.Model != "my-model" {
+			t.Errorf("expected model my-model, got %q", req.Model)
+		}
+		resp := HTTPEmbeddingResponse{}
+		for i := range req.Inputs {
+			resp.Vectors = append(resp.Vectors, []float32{float32(i), float32(len(req.Inputs[i]))})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	service := NewHTTPEmbeddingService(server.URL, "my-model", "secret")
+	vectors, err := service.GenerateEmbeddings([]string{"a", "bbb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vectors) != 2 || vectors[1][0] != 1 || vectors[1][1] != 3 {
+		t.Errorf("expected one vector per input in order, got %v", vectors)
+	}
+
+	vector, err := service.GenerateEmbedding("hello")
+	if err != nil || len(vector) != 2 {
+		t.Errorf("expected a single vector, got %v (%v)", vector, err)
+	}
+}
+
+func TestHTTPEmbeddingServiceRejectsBadResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			http.Error(w, "model not loaded", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"vectors": [[0.1, 0.2]]}`))
+	}))
+	defer server.Close()
+
+	if _, err := NewHTTPEmbeddingService(server.URL, "m", "").GenerateEmbeddings([]string{"a", "b"}); err == nil {
+		t.Error("expected an error when the vector count doesn't match the inputs")
+	}
+	if _, err := NewHTTPEmbeddingService(server.URL+"/fail", "m", "").GenerateEmbedding("a"); err == nil {
+		t.Error("expected an error for a non-200 response")
+	}
+}
+
+func TestNewService(t *testing.T) {
+	if _, err := NewService("http", "", "m", ""); err == nil {
+		t.Error("expected the http provider to require a URL")
+	}
+	if _, err := NewService("openai", "http://x", "m", ""); err == nil {
+		t.Error("expected an unknown provider to be rejected")
+	}
+	if s, err := NewService("", "http://ollama:11434", "nomic-embed-text", ""); err != nil || s.Model() != "nomic-embed-text" {
+		t.Errorf("expected Ollama by default, got %v (%v)", s, err)
+	}
+}
@@ -30,6 +30,11 @@ func NewOllamaEmbeddingService(baseURL, model string) *OllamaEmbeddingService {
 	}
 }
 
+// Model returns the name of the embedding model in use
+func (s *OllamaEmbeddingService) Model() string {
+	return s.model
+}
+
 // EmbeddingRequest represents an embedding request
 type EmbeddingRequest struct {
 	Model  string `json:"model"`
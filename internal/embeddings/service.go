@@ -0,0 +1,41 @@
+package embeddings
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Service generates embeddings for text
+type Service interface {
+	// Model returns the name of the embedding model in use
+	Model() string
+	// GenerateEmbedding generates an embedding for the given text
+	GenerateEmbedding(text string) ([]float32, error)
+	// GenerateEmbeddings generates embeddings for multiple texts, in order
+	GenerateEmbeddings(texts []string) ([][]float32, error)
+}
+
+// Supported embedding providers
+const (
+	ProviderOllama = "ollama"
+	ProviderHTTP   = "http"
+	ProviderFake   = "fake" // deterministic vectors for tests and local development
+)
+
+// NewService creates the embedding service for a provider. For ProviderHTTP, url is the
+// full endpoint of the custom service; for ProviderOllama it is the Ollama base URL.
+func NewService(provider, url, model, apiKey string) (Service, error) {
+	switch strings.ToLower(provider) {
+	case "", ProviderOllama:
+		return NewOllamaEmbeddingService(url, model), nil
+	case ProviderHTTP:
+		if url == "" {
+			return nil, fmt.Errorf("the http embedding provider requires EMBEDDING_URL")
+		}
+		return NewHTTPEmbeddingService(url, model, apiKey), nil
+	case ProviderFake:
+		return NewFakeEmbeddingService(), nil
+	default:
+		return nil, fmt.Errorf("unknown embedding provider: %s", provider)
+	}
+}
@@ -0,0 +1,59 @@
+// Package events appends domain events to the outbox table that integrations poll.
+package events
+
+import (
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// MaxPollLimit is the largest page a poll may request
+const MaxPollLimit = 500
+
+// Record appends an event to the outbox. A failure is logged rather than returned, since
+// the action that produced the event has already happened and shouldn't be undone.
+func Record(eventType, subjectID string, userID *uint, data map[string]interface{}) {
+	if err := Append(database.DB, eventType, subjectID, userID, data); err != nil {
+		logger.Error("Failed to record event", "component", "events", "type", eventType, "subject_id", subjectID, "error", err)
+	}
+}
+
+// Append adds an event to the outbox within a transaction, for events that must be kept or
+// rolled back together with the change they describe
+func Append(tx *gorm.DB, eventType, subjectID string, userID *uint, data map[string]interface{}) error {
+	return tx.Create(&models.Event{
+		Type:      eventType,
+		SubjectID: subjectID,
+		UserID:    userID,
+		Data:      data,
+	}).Error
+}
+
+// RecordForJob appends an event about a transcription job, attributed to the job's owner
+func RecordForJob(eventType, jobID string, data map[string]interface{}) {
+	var job models.TranscriptionJob
+	if err := database.DB.Select("id", "user_id").Where("id = ?", jobID).First(&job).Error; err != nil {
+		logger.Error("Failed to record event", "component", "events", "type", eventType, "job_id", jobID, "error", err)
+		return
+	}
+	Record(eventType, jobID, job.UserID, data)
+}
+
+// Poll returns up to limit events with a sequence greater than after, oldest first,
+// optionally restricted to some event types
+func Poll(query *gorm.DB, after uint, limit int, types []string) ([]models.Event, error) {
+	if limit <= 0 {
+		limit = MaxPollLimit
+	}
+	query = query.Where("sequence > ?", after)
+	if len(types) > 0 {
+		query = query.Where("type IN ?", types)
+	}
+	var events []models.Event
+	if err := query.Order("sequence ASC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
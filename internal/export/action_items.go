@@ -0,0 +1,85 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"scriberr/internal/models"
+)
+
+// FormatCSV is the CSV export format
+const FormatCSV = "csv"
+
+// ActionItemsCSV renders action items as CSV with a header row. titles maps transcription
+// IDs to their titles.
+func ActionItemsCSV(items []models.ActionItem, titles map[string]string) ([]byte, error) {
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+	rows := [][]string{{"transcription_id", "transcription_title", "task", "owner", "due", "timestamp", "completed", "completed_at"}}
+	for _, item := range items {
+		timestamp := ""
+		if item.SourceTime != nil {
+			timestamp = Timestamp(*item.SourceTime)
+		}
+		completedAt := ""
+		if item.CompletedAt != nil {
+			completedAt = item.CompletedAt.UTC().Format(time.RFC3339)
+		}
+		rows = append(rows, []string{
+			item.TranscriptionID,
+			titles[item.TranscriptionID],
+			item.Task,
+			item.Owner,
+			item.Due,
+			timestamp,
+			fmt.Sprint(item.Completed),
+			completedAt,
+		})
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// ActionItemsMarkdown renders action items as Markdown task lists, one section per
+// transcription in the order the items are given
+func ActionItemsMarkdown(items []models.ActionItem, titles map[string]string) []byte {
+	var out strings.Builder
+	out.WriteString("# Action items\n")
+	current := ""
+	for i, item := range items {
+		if i == 0 || item.TranscriptionID != current {
+			current = item.TranscriptionID
+			title := titles[current]
+			if title == "" {
+				title = current
+			}
+			fmt.Fprintf(&out, "\n## %s\n\n", title)
+		}
+
+		check := " "
+		if item.Completed {
+			check = "x"
+		}
+		fmt.Fprintf(&out, "- [%s] %s", check, strings.Join(strings.Fields(item.Task), " "))
+		var details []string
+		if item.Owner != "" {
+			details = append(details, item.Owner)
+		}
+		if item.Due != "" {
+			details = append(details, "due "+item.Due)
+		}
+		if item.SourceTime != nil {
+			details = append(details, "at "+Timestamp(*item.SourceTime))
+		}
+		if len(details) > 0 {
+			fmt.Fprintf(&out, " (%s)", strings.Join(details, ", "))
+		}
+		out.WriteString("\n")
+	}
+	return []byte(out.String())
+}
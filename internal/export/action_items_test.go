@@ -0,0 +1,47 @@
+package export
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"scriberr/internal/models"
+)
+
+func TestActionItemsExport(t *testing.T) {
+	at := 754.0
+	items := []models.ActionItem{
+		{TranscriptionID: "a", Task: "Update the roadmap", Owner: "Dana", Due: "Friday", SourceTime: &at, Completed: true},
+		{TranscriptionID: "a", Task: "Book a room, with a projector"},
+		{TranscriptionID: "b", Task: "Send the notes"},
+	}
+	titles := map[string]string{"a": "Weekly sync"}
+
+	data, err := ActionItemsCSV(items, titles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("expected a header and 3 rows, got %d", len(rows))
+	}
+	if got := strings.Join(rows[1], "|"); got != "a|Weekly sync|Update the roadmap|Dana|Friday|00:12:34|true|" {
+		t.Errorf("unexpected row %q", got)
+	}
+	if rows[2][2] != "Book a room, with a projector" {
+		t.Errorf("expected the comma to be quoted, got %q", rows[2][2])
+	}
+
+	markdown := string(ActionItemsMarkdown(items, titles))
+	for _, want := range []string{
+		"## Weekly sync\n\n- [x] Update the roadmap (Dana, due Friday, at 00:12:34)\n- [ ] Book a room, with a projector\n",
+		"## b\n\n- [ ] Send the notes\n",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("expected %q in\n%s", want, markdown)
+		}
+	}
+}
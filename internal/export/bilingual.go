@@ -0,0 +1,132 @@
+package export
+
+import (
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+)
+
+// Bilingual layouts
+const (
+	// LayoutSideBySide puts the original and the translation of each segment in adjacent columns
+	LayoutSideBySide = "side_by_side"
+	// LayoutInterleaved follows each original segment with its translation
+	LayoutInterleaved = "interleaved"
+)
+
+// BilingualRow is one segment of a bilingual transcript
+type BilingualRow struct {
+	Start       float64
+	End         float64
+	Speaker     string
+	Original    string
+	Translation string
+}
+
+// Bilingual is a transcript alongside its translation
+type Bilingual struct {
+	Title    string
+	Language string // Of the translation
+	Timed    bool   // Whether rows have timestamps
+	Rows     []BilingualRow
+}
+
+// NewBilingual aligns a translation with the original segments by timestamp. Each translated
+// segment goes to the original segment it overlaps most, or the one starting closest to it,
+// so the two sides still line up after either has been re-segmented. Without timestamps on
+// both sides, segments are paired in order. Speakers are shown by their custom names if known.
+func NewBilingual(title, language string, original []interfaces.TranscriptSegment, translated []models.TranslatedSegment, speakerNames map[string]string) *Bilingual {
+	b := &Bilingual{Title: title, Language: language, Timed: timed(original, translated)}
+	b.Rows = make([]BilingualRow, len(original))
+	for i, segment := range original {
+		b.Rows[i] = BilingualRow{Start: segment.Start, End: segment.End, Original: strings.TrimSpace(segment.Text)}
+		if segment.Speaker != nil {
+			b.Rows[i].Speaker = *segment.Speaker
+			if name, ok := speakerNames[*segment.Speaker]; ok && name != "" {
+				b.Rows[i].Speaker = name
+			}
+		}
+	}
+
+	for i, segment := range translated {
+		text := strings.TrimSpace(segment.Text)
+		if text == "" {
+			continue
+		}
+		row := i
+		if b.Timed {
+			row = closest(original, segment)
+		}
+		if row >= len(b.Rows) {
+			b.Rows = append(b.Rows, BilingualRow{Start: segment.Start, End: segment.End})
+			row = len(b.Rows) - 1
+		}
+		if b.Rows[row].Translation != "" {
+			text = b.Rows[row].Translation + " " + text
+		}
+		b.Rows[row].Translation = text
+	}
+	return b
+}
+
+// timed reports whether both sides carry timestamps
+func timed(original []interfaces.TranscriptSegment, translated []models.TranslatedSegment) bool {
+	if len(original) == 0 {
+		return false
+	}
+	originalTimed, translatedTimed := false, false
+	for _, segment := range original {
+		originalTimed = originalTimed || segment.End > 0
+	}
+	for _, segment := range translated {
+		translatedTimed = translatedTimed || segment.End > 0
+	}
+	return originalTimed && translatedTimed
+}
+
+// closest returns the index of the original segment that overlaps segment the most, or
+// failing any overlap, the one whose start is nearest to segment's
+func closest(original []interfaces.TranscriptSegment, segment models.TranslatedSegment) int {
+	best, bestOverlap, bestGap := 0, 0.0, -1.0
+	for i, candidate := range original {
+		overlap := min(candidate.End, segment.End) - max(candidate.Start, segment.Start)
+		if overlap > bestOverlap {
+			best, bestOverlap = i, overlap
+			continue
+		}
+		if bestOverlap > 0 {
+			continue
+		}
+		gap := candidate.Start - segment.Start
+		if gap < 0 {
+			gap = -gap
+		}
+		if bestGap < 0 || gap < bestGap {
+			best, bestGap = i, gap
+		}
+	}
+	return best
+}
+
+// hasSpeakers reports whether any row has a speaker
+func (b *Bilingual) hasSpeakers() bool {
+	for _, row := range b.Rows {
+		if row.Speaker != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// heading labels a row with its timestamp and speaker, as far as they are known
+func (b *Bilingual) heading(row BilingualRow) string {
+	var parts []string
+	if b.Timed {
+		parts = append(parts, Timestamp(row.Start))
+	}
+	if row.Speaker != "" {
+		parts = append(parts, row.Speaker)
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,88 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+)
+
+func TestNewBilingualAlignsByTimestamp(t *testing.T) {
+	speaker := "SPEAKER_00"
+	original := []interfaces.TranscriptSegment{
+		{Start: 0, End: 4, Text: "Good morning everyone.", Speaker: &speaker},
+		{Start: 4, End: 9, Text: "Let's start with the budget."},
+		{Start: 9, End: 12, Text: "Any questions?"},
+	}
+	// The translation was split differently: two segments cover the second original one
+	translated := []models.TranslatedSegment{
+		{Start: 0, End: 4, Text: "Buenos días a todos."},
+		{Start: 4, End: 6, Text: "Empecemos"},
+		{Start: 6, End: 9, Text: "con el presupuesto."},
+		{Start: 12.5, End: 14, Text: "¿Preguntas?"},
+	}
+
+	b := NewBilingual("Weekly sync", "Spanish", original, translated, map[string]string{speaker: "Alice"})
+	if len(b.Rows) != 3 || !b.Timed {
+		t.Fatalf("expected 3 timed rows, got %+v", b)
+	}
+	if b.Rows[1].Translation != "Empecemos con el presupuesto." {
+		t.Errorf("expected overlapping segments joined, got %q", b.Rows[1].Translation)
+	}
+	if b.Rows[2].Translation != "¿Preguntas?" {
+		t.Errorf("expected a segment without overlap to go to the nearest start, got %q", b.Rows[2].Translation)
+	}
+	if b.Rows[0].Speaker != "Alice" {
+		t.Errorf("expected custom speaker name, got %q", b.Rows[0].Speaker)
+	}
+
+	markdown := string(b.Markdown(LayoutSideBySide))
+	if !strings.Contains(markdown, "| Time | Speaker | Original | Translation (Spanish) |") ||
+		!strings.Contains(markdown, "| 00:00:00 | Alice | Good morning everyone. | Buenos días a todos. |") {
+		t.Errorf("unexpected side-by-side markdown:\n%s", markdown)
+	}
+	interleaved := string(b.Markdown(LayoutInterleaved))
+	if !strings.Contains(interleaved, "**00:00:04**\n\nLet's start with the budget.\n\n> Empecemos con el presupuesto.") {
+		t.Errorf("unexpected interleaved markdown:\n%s", interleaved)
+	}
+}
+
+func TestNewBilingualPairsUntimedSegments(t *testing.T) {
+	original := []interfaces.TranscriptSegment{{Text: "one | two"}, {Text: "three"}}
+	translated := []models.TranslatedSegment{{Text: "uno | dos"}, {Text: "tres"}, {Text: "extra"}}
+
+	b := NewBilingual("", "es", original, translated, nil)
+	if b.Timed || len(b.Rows) != 3 || b.Rows[1].Translation != "tres" || b.Rows[2].Original != "" {
+		t.Fatalf("expected segments paired in order, got %+v", b.Rows)
+	}
+	if markdown := string(b.Markdown(LayoutSideBySide)); !strings.Contains(markdown, `| one \| two | uno \| dos |`) {
+		t.Errorf("expected pipes escaped and no time column, got:\n%s", markdown)
+	}
+}
+
+func TestBilingualDOCX(t *testing.T) {
+	b := NewBilingual("R&D <sync>", "fr", []interfaces.TranscriptSegment{{Start: 0, End: 2, Text: "Hello"}}, []models.TranslatedSegment{{Start: 0, End: 2, Text: "Bonjour"}}, nil)
+	data, err := b.DOCX(LayoutSideBySide)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("expected a zip package: %v", err)
+	}
+	var document string
+	for _, file := range archive.File {
+		if file.Name == "word/document.xml" {
+			r, _ := file.Open()
+			content, _ := io.ReadAll(r)
+			document = string(content)
+		}
+	}
+	if !strings.Contains(document, "R&amp;D &lt;sync&gt;") || !strings.Contains(document, "<w:tbl>") || !strings.Contains(document, "Bonjour") {
+		t.Errorf("unexpected document.xml: %s", document)
+	}
+}
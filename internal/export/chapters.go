@@ -0,0 +1,139 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"scriberr/internal/models"
+)
+
+// Chapter export formats
+const (
+	ChaptersYouTube    = "youtube"
+	ChaptersMarkdown   = "markdown"
+	ChaptersWebVTT     = "webvtt"
+	ChaptersFFMetadata = "ffmetadata"
+	ChaptersPodcast    = "podcast"
+)
+
+// ChapterFormats lists the chapter export formats
+var ChapterFormats = []string{ChaptersYouTube, ChaptersMarkdown, ChaptersWebVTT, ChaptersFFMetadata, ChaptersPodcast}
+
+// Chapters renders chapters, in order, in one of the ChapterFormats. It returns the rendered
+// file, its content type and its file extension.
+func Chapters(format, title string, chapters []models.Chapter) ([]byte, string, string, error) {
+	switch format {
+	case ChaptersYouTube:
+		return chaptersYouTube(chapters), "text/plain; charset=utf-8", "txt", nil
+	case ChaptersMarkdown:
+		return chaptersMarkdown(title, chapters), "text/markdown; charset=utf-8", "md", nil
+	case ChaptersWebVTT:
+		return chaptersWebVTT(chapters), "text/vtt; charset=utf-8", "vtt", nil
+	case ChaptersFFMetadata:
+		return chaptersFFMetadata(title, chapters), "text/plain; charset=utf-8", "ffmetadata", nil
+	case ChaptersPodcast:
+		data, err := chaptersPodcast(title, chapters)
+		return data, "application/json+chapters", "json", err
+	}
+	return nil, "", "", fmt.Errorf("unknown chapter format %q", format)
+}
+
+// chapterTimestamp formats seconds the way YouTube and show notes do: M:SS, or H:MM:SS from
+// an hour on
+func chapterTimestamp(seconds float64) string {
+	total := int(seconds)
+	if total >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", total/3600, total%3600/60, total%60)
+	}
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
+
+// chaptersYouTube renders one "M:SS Title" line per chapter, for a video description.
+// YouTube requires the first chapter to start at 0:00.
+func chaptersYouTube(chapters []models.Chapter) []byte {
+	var out strings.Builder
+	for i, chapter := range chapters {
+		start := chapter.Start
+		if i == 0 {
+			start = 0
+		}
+		fmt.Fprintf(&out, "%s %s\n", chapterTimestamp(start), chapter.Title)
+	}
+	return []byte(out.String())
+}
+
+// chaptersMarkdown renders show notes: a list of timestamped chapter titles with their summaries
+func chaptersMarkdown(title string, chapters []models.Chapter) []byte {
+	var out strings.Builder
+	if title != "" {
+		fmt.Fprintf(&out, "# %s\n\n", title)
+	}
+	out.WriteString("## Chapters\n\n")
+	for _, chapter := range chapters {
+		fmt.Fprintf(&out, "- **%s** %s", chapterTimestamp(chapter.Start), chapter.Title)
+		if chapter.Summary != "" {
+			fmt.Fprintf(&out, " — %s", chapter.Summary)
+		}
+		out.WriteString("\n")
+	}
+	return []byte(out.String())
+}
+
+// vttTimestamp formats seconds as a WebVTT timestamp, HH:MM:SS.mmm
+func vttTimestamp(seconds float64) string {
+	millis := int64(math.Round(seconds * 1000))
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", millis/3600000, millis%3600000/60000, millis%60000/1000, millis%1000)
+}
+
+// chaptersWebVTT renders a WebVTT chapters track, one cue per chapter
+func chaptersWebVTT(chapters []models.Chapter) []byte {
+	var out strings.Builder
+	out.WriteString("WEBVTT\n")
+	for i, chapter := range chapters {
+		fmt.Fprintf(&out, "\n%d\n%s --> %s\n%s\n", i+1, vttTimestamp(chapter.Start), vttTimestamp(chapter.End), chapter.Title)
+	}
+	return []byte(out.String())
+}
+
+// ffmetadataEscaper escapes the characters FFmpeg's metadata format treats specially
+var ffmetadataEscaper = strings.NewReplacer(`\`, `\\`, "=", `\=`, ";", `\;`, "#", `\#`, "\n", "\\\n")
+
+// chaptersFFMetadata renders an FFmpeg metadata file, which ffmpeg -i audio -i chapters
+// -map_metadata 1 embeds into an MP3 or M4A as chapter markers
+func chaptersFFMetadata(title string, chapters []models.Chapter) []byte {
+	var out strings.Builder
+	out.WriteString(";FFMETADATA1\n")
+	if title != "" {
+		fmt.Fprintf(&out, "title=%s\n", ffmetadataEscaper.Replace(title))
+	}
+	for _, chapter := range chapters {
+		fmt.Fprintf(&out, "\n[CHAPTER]\nTIMEBASE=1/1000\nSTART=%d\nEND=%d\ntitle=%s\n",
+			int64(math.Round(chapter.Start*1000)), int64(math.Round(chapter.End*1000)), ffmetadataEscaper.Replace(chapter.Title))
+	}
+	return []byte(out.String())
+}
+
+// podcastChapters is the Podcasting 2.0 JSON chapters format
+type podcastChapters struct {
+	Version  string           `json:"version"`
+	Title    string           `json:"title,omitempty"`
+	Chapters []podcastChapter `json:"chapters"`
+}
+
+type podcastChapter struct {
+	StartTime float64 `json:"startTime"`
+	EndTime   float64 `json:"endTime,omitempty"`
+	Title     string  `json:"title"`
+}
+
+// chaptersPodcast renders the Podcasting 2.0 JSON chapters file referenced by a feed's
+// podcast:chapters tag
+func chaptersPodcast(title string, chapters []models.Chapter) ([]byte, error) {
+	result := podcastChapters{Version: "1.2.0", Title: title, Chapters: make([]podcastChapter, len(chapters))}
+	for i, chapter := range chapters {
+		result.Chapters[i] = podcastChapter{StartTime: chapter.Start, EndTime: chapter.End, Title: chapter.Title}
+	}
+	return json.MarshalIndent(result, "", "  ")
+}
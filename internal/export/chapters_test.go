@@ -0,0 +1,56 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"scriberr/internal/models"
+)
+
+func TestChaptersExport(t *testing.T) {
+	chapters := []models.Chapter{
+		{Title: "Intro", Summary: "Who is on the show.", Start: 0, End: 95.5},
+		{Title: "Q&A; part=1", Start: 95.5, End: 3725},
+	}
+
+	for format, want := range map[string]string{
+		ChaptersYouTube:    "0:00 Intro\n1:35 Q&A; part=1\n",
+		ChaptersMarkdown:   "# Episode 12\n\n## Chapters\n\n- **0:00** Intro — Who is on the show.\n- **1:35** Q&A; part=1\n",
+		ChaptersWebVTT:     "WEBVTT\n\n1\n00:00:00.000 --> 00:01:35.500\nIntro\n\n2\n00:01:35.500 --> 01:02:05.000\nQ&A; part=1\n",
+		ChaptersFFMetadata: ";FFMETADATA1\ntitle=Episode 12\n\n[CHAPTER]\nTIMEBASE=1/1000\nSTART=0\nEND=95500\ntitle=Intro\n\n[CHAPTER]\nTIMEBASE=1/1000\nSTART=95500\nEND=3725000\ntitle=Q&A\\; part\\=1\n",
+	} {
+		data, _, _, err := Chapters(format, "Episode 12", chapters)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("%s: unexpected output\n%s", format, data)
+		}
+	}
+
+	data, contentType, extension, err := Chapters(ChaptersPodcast, "Episode 12", chapters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "application/json+chapters" || extension != "json" {
+		t.Errorf("unexpected podcast file type %q .%s", contentType, extension)
+	}
+	var podcast struct {
+		Version  string `json:"version"`
+		Chapters []struct {
+			StartTime float64 `json:"startTime"`
+			Title     string  `json:"title"`
+		} `json:"chapters"`
+	}
+	if err := json.Unmarshal(data, &podcast); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if podcast.Version != "1.2.0" || len(podcast.Chapters) != 2 || podcast.Chapters[1].StartTime != 95.5 {
+		t.Errorf("unexpected podcast chapters %s", data)
+	}
+
+	if _, _, _, err := Chapters("srt", "", chapters); err == nil || !strings.Contains(err.Error(), "srt") {
+		t.Errorf("expected an unknown format error, got %v", err)
+	}
+}
@@ -0,0 +1,334 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+)
+
+// Document formats besides FormatMarkdown and FormatDOCX
+const FormatPDF = "pdf"
+
+// Document sections
+const (
+	SectionSummary     = "summary"
+	SectionChapters    = "chapters"
+	SectionActionItems = "action_items"
+	SectionTranscript  = "transcript"
+)
+
+// DocumentSections lists the sections a document can have, in their default order
+var DocumentSections = []string{SectionSummary, SectionChapters, SectionActionItems, SectionTranscript}
+
+// Document is a transcription laid out for sharing: its summary, chapters, action items and
+// transcript under the branding, header and footer of an export template. Sections without
+// content are left out.
+type Document struct {
+	Title        string
+	Date         time.Time
+	Organization string   // Brand name shown above the title
+	Header       string   // Shown at the top of every page; {{title}} and {{date}} are filled in
+	Footer       string   // Shown at the bottom of every page, likewise
+	AccentColor  string   // Hex RGB of the title and headings, e.g. "1F6FEB"; empty for black
+	Sections     []string // In the order they appear
+	Timestamps   bool     // Start each transcript paragraph with its time
+	Speakers     bool     // Start each transcript paragraph with its speaker
+	Summary      string   // Markdown
+	Chapters     []models.Chapter
+	ActionItems  []models.ActionItem
+	Segments     []interfaces.TranscriptSegment
+	SpeakerNames map[string]string // Custom names of speaker labels
+}
+
+// blockKind is what a block of a document is
+type blockKind int
+
+const (
+	blockBrand blockKind = iota
+	blockTitle
+	blockByline
+	blockHeading
+	blockSubheading
+	blockLabel
+	blockParagraph
+	blockBullet
+)
+
+// block is one paragraph of a document, the same in every format
+type block struct {
+	kind blockKind
+	text string // May hold inline Markdown emphasis, which plainText strips
+}
+
+// fill replaces the placeholders of a header or footer
+func (d *Document) fill(text string) string {
+	date := ""
+	if !d.Date.IsZero() {
+		date = d.Date.Format("January 2, 2006")
+	}
+	return strings.NewReplacer("{{title}}", d.Title, "{{date}}", date).Replace(text)
+}
+
+// blocks lays out the document
+func (d *Document) blocks() []block {
+	var blocks []block
+	if d.Organization != "" {
+		blocks = append(blocks, block{blockBrand, d.Organization})
+	}
+	title := d.Title
+	if title == "" {
+		title = "Transcript"
+	}
+	blocks = append(blocks, block{blockTitle, title})
+	if !d.Date.IsZero() {
+		blocks = append(blocks, block{blockByline, d.Date.Format("January 2, 2006 at 15:04")})
+	}
+
+	for _, section := range d.Sections {
+		switch section {
+		case SectionSummary:
+			if strings.TrimSpace(d.Summary) != "" {
+				blocks = append(blocks, block{blockHeading, "Summary"})
+				blocks = append(blocks, markdownBlocks(d.Summary)...)
+			}
+		case SectionChapters:
+			if len(d.Chapters) > 0 {
+				blocks = append(blocks, block{blockHeading, "Chapters"})
+				for _, chapter := range d.Chapters {
+					text := chapterTimestamp(chapter.Start) + " " + chapter.Title
+					if chapter.Summary != "" {
+						text += " — " + chapter.Summary
+					}
+					blocks = append(blocks, block{blockBullet, text})
+				}
+			}
+		case SectionActionItems:
+			if len(d.ActionItems) > 0 {
+				blocks = append(blocks, block{blockHeading, "Action Items"})
+				for _, item := range d.ActionItems {
+					blocks = append(blocks, block{blockBullet, actionItemLine(item)})
+				}
+			}
+		case SectionTranscript:
+			if paragraphs := d.transcriptBlocks(); len(paragraphs) > 0 {
+				blocks = append(blocks, block{blockHeading, "Transcript"})
+				blocks = append(blocks, paragraphs...)
+			}
+		}
+	}
+	return blocks
+}
+
+// actionItemLine describes an action item on one line
+func actionItemLine(item models.ActionItem) string {
+	box := "☐ "
+	if item.Completed {
+		box = "☑ "
+	}
+	var details []string
+	if item.Owner != "" {
+		details = append(details, item.Owner)
+	}
+	if item.Due != "" {
+		details = append(details, "due "+item.Due)
+	}
+	if len(details) > 0 {
+		return box + item.Task + " (" + strings.Join(details, ", ") + ")"
+	}
+	return box + item.Task
+}
+
+// transcriptBlocks joins consecutive segments of the same speaker into paragraphs, each under
+// a label with its time and speaker as the document shows them
+func (d *Document) transcriptBlocks() []block {
+	var blocks []block
+	var text []string
+	label, speaker := "", ""
+	flush := func() {
+		if len(text) == 0 {
+			return
+		}
+		if label != "" {
+			blocks = append(blocks, block{blockLabel, label})
+		}
+		blocks = append(blocks, block{blockParagraph, strings.Join(text, " ")})
+		text = nil
+	}
+	for i, segment := range d.Segments {
+		segmentSpeaker := ""
+		if segment.Speaker != nil {
+			segmentSpeaker = *segment.Speaker
+		}
+		if i == 0 || segmentSpeaker != speaker {
+			flush()
+			speaker = segmentSpeaker
+			var parts []string
+			if d.Timestamps && segment.End > 0 {
+				parts = append(parts, Timestamp(segment.Start))
+			}
+			if d.Speakers && speaker != "" {
+				name := speaker
+				if custom := d.SpeakerNames[speaker]; custom != "" {
+					name = custom
+				}
+				parts = append(parts, name)
+			}
+			label = strings.Join(parts, " · ")
+		}
+		if line := strings.Join(strings.Fields(segment.Text), " "); line != "" {
+			text = append(text, line)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// markdownBullet matches a Markdown list item marker
+var markdownBullet = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+`)
+
+// markdownBlocks turns a Markdown summary into blocks: headings become subheadings, list items
+// bullets and the rest paragraphs, one per run of lines
+func markdownBlocks(text string) []block {
+	var blocks []block
+	var lines []string
+	flush := func() {
+		if len(lines) > 0 {
+			blocks = append(blocks, block{blockParagraph, strings.Join(lines, " ")})
+			lines = nil
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "#"):
+			flush()
+			if heading := strings.TrimSpace(strings.TrimLeft(line, "#")); heading != "" {
+				blocks = append(blocks, block{blockSubheading, heading})
+			}
+		case markdownBullet.MatchString(line):
+			flush()
+			blocks = append(blocks, block{blockBullet, markdownBullet.ReplaceAllString(line, "")})
+		default:
+			lines = append(lines, line)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// markdownEmphasis matches the inline Markdown markers plainText removes
+var markdownEmphasis = regexp.MustCompile("\\*\\*|__|`")
+
+// plainText strips inline Markdown emphasis, for formats that show text as it is
+func plainText(text string) string {
+	return markdownEmphasis.ReplaceAllString(text, "")
+}
+
+// Markdown renders the document as Markdown, with the header and footer as italic lines
+// above and below it
+func (d *Document) Markdown() []byte {
+	var out strings.Builder
+	if header := d.fill(d.Header); header != "" {
+		fmt.Fprintf(&out, "_%s_\n\n", header)
+	}
+	previous := blockParagraph
+	for _, b := range d.blocks() {
+		// A list ends with a blank line
+		if previous == blockBullet && b.kind != blockBullet {
+			out.WriteString("\n")
+		}
+		previous = b.kind
+		switch b.kind {
+		case blockBrand:
+			fmt.Fprintf(&out, "**%s**\n\n", b.text)
+		case blockTitle:
+			fmt.Fprintf(&out, "# %s\n\n", b.text)
+		case blockByline:
+			fmt.Fprintf(&out, "_%s_\n\n", b.text)
+		case blockHeading:
+			fmt.Fprintf(&out, "## %s\n\n", b.text)
+		case blockSubheading:
+			fmt.Fprintf(&out, "### %s\n\n", b.text)
+		case blockLabel:
+			fmt.Fprintf(&out, "**%s**\n\n", b.text)
+		case blockBullet:
+			fmt.Fprintf(&out, "- %s\n", b.text)
+		default:
+			fmt.Fprintf(&out, "%s\n\n", b.text)
+		}
+	}
+	if previous == blockBullet {
+		out.WriteString("\n")
+	}
+	if footer := d.fill(d.Footer); footer != "" {
+		fmt.Fprintf(&out, "---\n\n_%s_\n", footer)
+	}
+	return []byte(out.String())
+}
+
+// accent returns the accent color as Word and PDF take it, or "" for none
+func (d *Document) accent() string {
+	color := strings.ToUpper(strings.TrimPrefix(d.AccentColor, "#"))
+	if !hexColor.MatchString(color) {
+		return ""
+	}
+	return color
+}
+
+// hexColor matches a six-digit hex RGB color
+var hexColor = regexp.MustCompile(`^[0-9A-F]{6}$`)
+
+// DOCX renders the document as a Word document, with the header and footer on every page and
+// the page number at the bottom
+func (d *Document) DOCX() ([]byte, error) {
+	color := ""
+	if accent := d.accent(); accent != "" {
+		color = `<w:color w:val="` + accent + `"/>`
+	}
+	muted := `<w:color w:val="666666"/>`
+
+	var body strings.Builder
+	for _, b := range d.blocks() {
+		text := plainText(b.text)
+		switch b.kind {
+		case blockBrand:
+			body.WriteString(paragraph(run(text, `<w:b/><w:caps/>`+color+`<w:sz w:val="20"/>`)))
+		case blockTitle:
+			body.WriteString(paragraph(run(text, `<w:b/>`+color+`<w:sz w:val="40"/>`)))
+		case blockByline:
+			body.WriteString(paragraph(run(text, `<w:i/>`+muted)))
+		case blockHeading:
+			body.WriteString(`<w:p><w:pPr><w:keepNext/><w:spacing w:before="360" w:after="120"/></w:pPr>` +
+				run(text, `<w:b/>`+color+`<w:sz w:val="30"/>`) + `</w:p>`)
+		case blockSubheading:
+			body.WriteString(`<w:p><w:pPr><w:keepNext/></w:pPr>` + run(text, `<w:b/><w:sz w:val="24"/>`) + `</w:p>`)
+		case blockLabel:
+			body.WriteString(`<w:p><w:pPr><w:keepNext/><w:spacing w:before="160" w:after="0"/></w:pPr>` +
+				run(text, `<w:b/>`+muted+`<w:sz w:val="18"/>`) + `</w:p>`)
+		case blockBullet:
+			body.WriteString(`<w:p><w:pPr><w:ind w:left="360" w:hanging="240"/></w:pPr>` + run("•\t"+text, "") + `</w:p>`)
+		default:
+			body.WriteString(paragraph(run(text, "")))
+		}
+	}
+
+	header := ""
+	if text := d.fill(d.Header); text != "" {
+		header = `<w:p><w:pPr><w:jc w:val="right"/></w:pPr>` + run(text, muted+`<w:sz w:val="18"/>`) + `</w:p>`
+	}
+	footer := `<w:p><w:pPr><w:jc w:val="center"/></w:pPr>`
+	if text := d.fill(d.Footer); text != "" {
+		footer += run(text+" · ", muted+`<w:sz w:val="18"/>`)
+	}
+	footer += run("Page ", muted+`<w:sz w:val="18"/>`) +
+		`<w:r><w:rPr>` + muted + `<w:sz w:val="18"/></w:rPr><w:fldChar w:fldCharType="begin"/></w:r>` +
+		`<w:r><w:rPr>` + muted + `<w:sz w:val="18"/></w:rPr><w:instrText xml:space="preserve"> PAGE </w:instrText></w:r>` +
+		`<w:r><w:rPr>` + muted + `<w:sz w:val="18"/></w:rPr><w:fldChar w:fldCharType="end"/></w:r></w:p>`
+	return writeDOCX(body.String(), header, footer)
+}
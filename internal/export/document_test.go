@@ -0,0 +1,143 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+)
+
+func testDocument() *Document {
+	alice, bob := "SPEAKER_00", "SPEAKER_01"
+	return &Document{
+		Title:        "Weekly sync",
+		Date:         time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC),
+		Organization: "Acme",
+		Header:       "{{title}} — Confidential",
+		Footer:       "Acme Corp, {{date}}",
+		AccentColor:  "#1f6feb",
+		Sections:     DocumentSections,
+		Timestamps:   true,
+		Speakers:     true,
+		Summary:      "## Decisions\n- Ship on **Friday**\n\nEveryone agreed.",
+		Chapters:     []models.Chapter{{Start: 0, Title: "Intro"}, {Start: 65, Title: "Release", Summary: "Dates"}},
+		ActionItems:  []models.ActionItem{{Task: "Send notes", Owner: "Alice", Due: "Monday"}, {Task: "Book room", Completed: true}},
+		Segments: []interfaces.TranscriptSegment{
+			{Start: 0, End: 2, Text: "Morning.", Speaker: &alice},
+			{Start: 2, End: 4, Text: " Let's  start.", Speaker: &alice},
+			{Start: 65, End: 70, Text: "Release (is) on Friday.", Speaker: &bob},
+		},
+		SpeakerNames: map[string]string{alice: "Alice"},
+	}
+}
+
+func TestDocumentMarkdown(t *testing.T) {
+	want := "_Weekly sync — Confidential_\n\n" +
+		"**Acme**\n\n# Weekly sync\n\n_March 4, 2026 at 09:30_\n\n" +
+		"## Summary\n\n### Decisions\n\n- Ship on **Friday**\n\nEveryone agreed.\n\n" +
+		"## Chapters\n\n- 0:00 Intro\n- 1:05 Release — Dates\n\n" +
+		"## Action Items\n\n- ☐ Send notes (Alice, due Monday)\n- ☑ Book room\n\n" +
+		"## Transcript\n\n**00:00:00 · Alice**\n\nMorning. Let's start.\n\n**00:01:05 · SPEAKER_01**\n\nRelease (is) on Friday.\n\n" +
+		"---\n\n_Acme Corp, March 4, 2026_\n"
+	if got := string(testDocument().Markdown()); got != want {
+		t.Errorf("unexpected Markdown\n%s", got)
+	}
+
+	document := testDocument()
+	document.Sections = []string{SectionTranscript}
+	document.Timestamps, document.Header, document.Footer = false, "", ""
+	got := string(document.Markdown())
+	if strings.Contains(got, "Summary") || strings.Contains(got, "00:00:00") || !strings.Contains(got, "**Alice**") {
+		t.Errorf("sections and timestamps not left out\n%s", got)
+	}
+}
+
+func TestDocumentDOCX(t *testing.T) {
+	data, err := testDocument().DOCX()
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string]string{}
+	for _, file := range archive.File {
+		r, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(r)
+		parts[file.Name] = string(content)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "word/document.xml", "word/_rels/document.xml.rels", "word/header1.xml", "word/footer1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("missing part %s", name)
+		}
+	}
+	body := parts["word/document.xml"]
+	for _, text := range []string{"Ship on Friday", "00:01:05 · SPEAKER_01", "Release (is) on Friday.", `w:val="1F6FEB"`, `r:id="rIdFooter"`} {
+		if !strings.Contains(body, text) {
+			t.Errorf("document lacks %q", text)
+		}
+	}
+	if !strings.Contains(parts["word/header1.xml"], "Weekly sync — Confidential") || !strings.Contains(parts["word/footer1.xml"], " PAGE ") {
+		t.Errorf("unexpected header or footer\n%s\n%s", parts["word/header1.xml"], parts["word/footer1.xml"])
+	}
+}
+
+func TestDocumentPDF(t *testing.T) {
+	document := testDocument()
+	for i := 0; i < 120; i++ {
+		document.Segments = append(document.Segments, interfaces.TranscriptSegment{Start: float64(100 + i), End: float64(101 + i), Text: strings.Repeat("word ", 40)})
+	}
+	data := document.PDF()
+	text := string(data)
+	if !strings.HasPrefix(text, "%PDF-1.4") || !strings.HasSuffix(text, "%%EOF\n") {
+		t.Fatal("not a PDF")
+	}
+	if !strings.Contains(text, "(Release \\(is\\) on Friday.) Tj") {
+		t.Error("parentheses not escaped")
+	}
+	if !strings.Contains(text, "(Weekly sync \x97 Confidential) Tj") {
+		t.Error("header not on the page, encoded in WinAnsi")
+	}
+	if !strings.Contains(text, "([ ] Send notes \\(Alice, due Monday\\)) Tj") {
+		t.Error("check box not replaced")
+	}
+	pages := strings.Count(text, "/Type /Page ")
+	if pages < 2 || !strings.Contains(text, "Page 2 of ") || strings.Contains(text, "Page 1 of 1)") {
+		t.Errorf("long transcript not paginated: %d pages", pages)
+	}
+
+	// The cross-reference table points at every object
+	xref := strings.LastIndex(text, "xref\n")
+	for n, line := range strings.Split(text[xref:], "\n")[3:] {
+		if !strings.HasSuffix(line, " n ") {
+			break
+		}
+		var offset int
+		if _, err := fmt.Sscan(line, &offset); err != nil || !strings.HasPrefix(text[offset:], strconv.Itoa(n+1)+" 0 obj") {
+			t.Fatalf("object %d not at offset %d", n+1, offset)
+		}
+	}
+}
+
+func TestPDFWrap(t *testing.T) {
+	lines := pdfWrap(pdfText("aaa bbb ccc "+strings.Repeat("x", 40)), pdfRegular, 10, 60)
+	if len(lines) < 4 || string(lines[0]) != "aaa bbb ccc" {
+		t.Errorf("unexpected lines %q", lines)
+	}
+	for _, line := range lines {
+		if pdfWidth(line, pdfRegular, 10) > 60 {
+			t.Errorf("line %q too wide", line)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"strings"
+)
+
+// docxPackageRels is the package relationship to the main document part
+const docxPackageRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/></Relationships>`
+
+// writeDOCX packages a document body as a minimal WordprocessingML package. The header and
+// footer, each paragraphs like the body, are repeated on every page when given.
+func writeDOCX(body, header, footer string) ([]byte, error) {
+	const wordNS = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+	const partHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+	overrides := `<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>`
+	var rels, section strings.Builder
+	parts := []struct{ name, content string }{{"_rels/.rels", docxPackageRels}}
+	if header != "" {
+		overrides += `<Override PartName="/word/header1.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml"/>`
+		rels.WriteString(`<Relationship Id="rIdHeader" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/header" Target="header1.xml"/>`)
+		section.WriteString(`<w:headerReference w:type="default" r:id="rIdHeader"/>`)
+		parts = append(parts, struct{ name, content string }{"word/header1.xml", partHeader + `<w:hdr ` + wordNS + `>` + header + `</w:hdr>`})
+	}
+	if footer != "" {
+		overrides += `<Override PartName="/word/footer1.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml"/>`
+		rels.WriteString(`<Relationship Id="rIdFooter" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer" Target="footer1.xml"/>`)
+		section.WriteString(`<w:footerReference w:type="default" r:id="rIdFooter"/>`)
+		parts = append(parts, struct{ name, content string }{"word/footer1.xml", partHeader + `<w:ftr ` + wordNS + `>` + footer + `</w:ftr>`})
+	}
+	parts = append(parts, struct{ name, content string }{"[Content_Types].xml", partHeader +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/>` +
+		overrides + `</Types>`})
+	if rels.Len() > 0 {
+		parts = append(parts, struct{ name, content string }{"word/_rels/document.xml.rels", partHeader +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` + rels.String() + `</Relationships>`})
+	}
+	parts = append(parts, struct{ name, content string }{"word/document.xml", partHeader +
+		`<w:document ` + wordNS + `><w:body>` + body +
+		`<w:sectPr>` + section.String() + `<w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1418" w:right="1134" w:bottom="1418" w:left="1134" w:header="567" w:footer="567" w:gutter="0"/></w:sectPr>` +
+		`</w:body></w:document>`})
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, part := range parts {
+		w, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DOCX renders the bilingual transcript in layout as a Word document. The side-by-side
+// layout is a table; the interleaved layout follows each original with its translation in italics.
+func (b *Bilingual) DOCX(layout string) ([]byte, error) {
+	var body strings.Builder
+	if b.Title != "" {
+		body.WriteString(paragraph(run(b.Title, `<w:b/><w:sz w:val="32"/>`)))
+	}
+	body.WriteString(paragraph(run("Translation: "+b.Language, "<w:i/>")))
+
+	if layout == LayoutInterleaved {
+		for _, row := range b.Rows {
+			if heading := b.heading(row); heading != "" {
+				body.WriteString(paragraph(run(heading, "<w:b/>")))
+			}
+			if row.Original != "" {
+				body.WriteString(paragraph(run(row.Original, "")))
+			}
+			if row.Translation != "" {
+				body.WriteString(paragraph(run(row.Translation, `<w:i/><w:color w:val="555555"/>`)))
+			}
+		}
+	} else {
+		b.docxTable(&body)
+	}
+	// Word requires a paragraph after a table at the end of the body
+	body.WriteString(paragraph(""))
+
+	return writeDOCX(body.String(), "", "")
+}
+
+// docxTable writes the side-by-side table, with time and speaker columns when known
+func (b *Bilingual) docxTable(body *strings.Builder) {
+	speakers := b.hasSpeakers()
+	body.WriteString(`<w:tbl><w:tblPr><w:tblW w:w="5000" w:type="pct"/><w:tblBorders>`)
+	for _, side := range []string{"top", "left", "bottom", "right", "insideH", "insideV"} {
+		body.WriteString(`<w:` + side + ` w:val="single" w:sz="4" w:space="0" w:color="BBBBBB"/>`)
+	}
+	body.WriteString(`</w:tblBorders></w:tblPr>`)
+
+	header := []string{"Original", "Translation (" + b.Language + ")"}
+	if speakers {
+		header = append([]string{"Speaker"}, header...)
+	}
+	if b.Timed {
+		header = append([]string{"Time"}, header...)
+	}
+	body.WriteString(`<w:tr><w:trPr><w:tblHeader/></w:trPr>`)
+	for _, title := range header {
+		body.WriteString(cell(run(title, "<w:b/>")))
+	}
+	body.WriteString(`</w:tr>`)
+
+	for _, row := range b.Rows {
+		body.WriteString(`<w:tr><w:trPr><w:cantSplit/></w:trPr>`)
+		if b.Timed {
+			body.WriteString(cell(run(Timestamp(row.Start), "")))
+		}
+		if speakers {
+			body.WriteString(cell(run(row.Speaker, "")))
+		}
+		body.WriteString(cell(run(row.Original, "")))
+		body.WriteString(cell(run(row.Translation, "")))
+		body.WriteString(`</w:tr>`)
+	}
+	body.WriteString(`</w:tbl>`)
+}
+
+// run is a run of text with the given run properties
+func run(text, properties string) string {
+	if text == "" {
+		return ""
+	}
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(text))
+	if properties != "" {
+		properties = "<w:rPr>" + properties + "</w:rPr>"
+	}
+	return `<w:r>` + properties + `<w:t xml:space="preserve">` + escaped.String() + `</w:t></w:r>`
+}
+
+func paragraph(runs string) string {
+	return `<w:p>` + runs + `</w:p>`
+}
+
+// cell is a table cell holding one paragraph; Word requires every cell to have one
+func cell(runs string) string {
+	return `<w:tc>` + paragraph(runs) + `</w:tc>`
+}
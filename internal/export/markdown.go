@@ -0,0 +1,60 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown renders the bilingual transcript in layout as a Markdown document. The
+// side-by-side layout is a table; the interleaved layout quotes each translation below its original.
+func (b *Bilingual) Markdown(layout string) []byte {
+	var out strings.Builder
+	if b.Title != "" {
+		fmt.Fprintf(&out, "# %s\n\n", b.Title)
+	}
+	fmt.Fprintf(&out, "_Translation: %s_\n\n", b.Language)
+
+	if layout == LayoutInterleaved {
+		for _, row := range b.Rows {
+			if heading := b.heading(row); heading != "" {
+				fmt.Fprintf(&out, "**%s**\n\n", heading)
+			}
+			if row.Original != "" {
+				fmt.Fprintf(&out, "%s\n\n", row.Original)
+			}
+			if row.Translation != "" {
+				fmt.Fprintf(&out, "> %s\n\n", row.Translation)
+			}
+		}
+		return []byte(out.String())
+	}
+
+	var header []string
+	if b.Timed {
+		header = append(header, "Time")
+	}
+	speakers := b.hasSpeakers()
+	if speakers {
+		header = append(header, "Speaker")
+	}
+	header = append(header, "Original", "Translation ("+tableCell(b.Language)+")")
+	fmt.Fprintf(&out, "| %s |\n|%s\n", strings.Join(header, " | "), strings.Repeat(" --- |", len(header)))
+	for _, row := range b.Rows {
+		var cells []string
+		if b.Timed {
+			cells = append(cells, Timestamp(row.Start))
+		}
+		if speakers {
+			cells = append(cells, tableCell(row.Speaker))
+		}
+		cells = append(cells, tableCell(row.Original), tableCell(row.Translation))
+		fmt.Fprintf(&out, "| %s |\n", strings.Join(cells, " | "))
+	}
+	return []byte(out.String())
+}
+
+// tableCell escapes text for a Markdown table cell, which can't hold pipes or line breaks
+func tableCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,287 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PDF page geometry in points: A4 with margins of about 2 cm
+const (
+	pdfPageWidth    = 595.0
+	pdfPageHeight   = 842.0
+	pdfMarginX      = 56.0
+	pdfMarginTop    = 72.0
+	pdfMarginBottom = 72.0
+)
+
+// pdfFont is one of the standard fonts every PDF reader has, so none need embedding
+type pdfFont int
+
+const (
+	pdfRegular pdfFont = iota
+	pdfBold
+	pdfItalic
+)
+
+// pdfFontNames are the base fonts of the pdfFonts, by resource name
+var pdfFontNames = []string{"Helvetica", "Helvetica-Bold", "Helvetica-Oblique"}
+
+// Widths of the printable ASCII characters, space to tilde, in thousandths of the font size.
+// Helvetica-Oblique has the widths of Helvetica.
+var (
+	helveticaWidths = []int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = []int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// winAnsi maps the characters of WinAnsiEncoding outside Latin-1 to their codes
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// pdfText encodes text in WinAnsiEncoding, the encoding of the standard fonts. Characters it
+// lacks become "?", except the check boxes of action items.
+func pdfText(text string) []byte {
+	text = strings.NewReplacer("☐", "[ ]", "☑", "[x]").Replace(text)
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch code, ok := winAnsi[r]; {
+		case ok:
+			out = append(out, code)
+		case r == '\t':
+			out = append(out, ' ')
+		case r >= 0x20 && r < 0x7F, r >= 0xA0 && r <= 0xFF:
+			out = append(out, byte(r))
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+// pdfWidth is the width in points of encoded text set in font at size
+func pdfWidth(text []byte, font pdfFont, size float64) float64 {
+	widths := helveticaWidths
+	if font == pdfBold {
+		widths = helveticaBoldWidths
+	}
+	total := 0
+	for _, c := range text {
+		if c >= 0x20 && c < 0x7F {
+			total += widths[c-0x20]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// pdfWrap breaks encoded text into lines no wider than width, between words where it can
+func pdfWrap(text []byte, font pdfFont, size, width float64) [][]byte {
+	var lines [][]byte
+	var line []byte
+	for _, word := range bytes.Fields(text) {
+		candidate := word
+		if len(line) > 0 {
+			candidate = append(append(append([]byte{}, line...), ' '), word...)
+		}
+		if pdfWidth(candidate, font, size) <= width {
+			line = candidate
+			continue
+		}
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+		// A word wider than a line is broken wherever it reaches the edge
+		for len(word) > 1 && pdfWidth(word, font, size) > width {
+			cut := 1
+			for cut < len(word) && pdfWidth(word[:cut+1], font, size) <= width {
+				cut++
+			}
+			lines = append(lines, word[:cut])
+			word = word[cut:]
+		}
+		line = word
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// pdfString is encoded text as a PDF literal string
+func pdfString(text []byte) string {
+	var out strings.Builder
+	out.WriteByte('(')
+	for _, c := range text {
+		if c == '(' || c == ')' || c == '\\' {
+			out.WriteByte('\\')
+		}
+		out.WriteByte(c)
+	}
+	out.WriteByte(')')
+	return out.String()
+}
+
+// pdfColor is a hex RGB color as the operands of the rg operator
+func pdfColor(hex string) string {
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return "0 0 0"
+	}
+	return fmt.Sprintf("%.3f %.3f %.3f", float64(value>>16&0xFF)/255, float64(value>>8&0xFF)/255, float64(value&0xFF)/255)
+}
+
+// pdfStyle is how a kind of block is set
+type pdfStyle struct {
+	font          pdfFont
+	size, leading float64
+	before, after float64 // Space above and below
+	color         string  // Hex RGB
+	keepNext      bool    // Moved to the next page rather than left alone at the bottom
+}
+
+// pdfStyle returns the style of a kind of block
+func (d *Document) pdfStyle(kind blockKind) pdfStyle {
+	accent := d.accent()
+	if accent == "" {
+		accent = "000000"
+	}
+	switch kind {
+	case blockBrand:
+		return pdfStyle{font: pdfBold, size: 10, leading: 13, after: 4, color: accent}
+	case blockTitle:
+		return pdfStyle{font: pdfBold, size: 20, leading: 24, after: 4, color: accent}
+	case blockByline:
+		return pdfStyle{font: pdfItalic, size: 10, leading: 13, after: 8, color: "666666"}
+	case blockHeading:
+		return pdfStyle{font: pdfBold, size: 15, leading: 19, before: 16, after: 6, color: accent, keepNext: true}
+	case blockSubheading:
+		return pdfStyle{font: pdfBold, size: 12, leading: 15, before: 8, after: 4, color: "000000", keepNext: true}
+	case blockLabel:
+		return pdfStyle{font: pdfBold, size: 9, leading: 12, before: 8, after: 1, color: "666666", keepNext: true}
+	default:
+		return pdfStyle{font: pdfRegular, size: 11, leading: 15, after: 6, color: "000000"}
+	}
+}
+
+// PDF renders the document as a PDF, with the header and footer and "Page n of N" on every page
+func (d *Document) PDF() []byte {
+	width := pdfPageWidth - 2*pdfMarginX
+	var pages []*strings.Builder
+	var page *strings.Builder
+	y := 0.0
+	newPage := func() {
+		page = &strings.Builder{}
+		pages = append(pages, page)
+		y = pdfPageHeight - pdfMarginTop
+	}
+	newPage()
+
+	blocks := d.blocks()
+	for i, b := range blocks {
+		style := d.pdfStyle(b.kind)
+		indent := 0.0
+		if b.kind == blockBullet {
+			indent = 14
+		}
+		lines := pdfWrap(pdfText(plainText(b.text)), style.font, style.size, width-indent)
+		if len(lines) == 0 {
+			continue
+		}
+
+		// Headings and labels stay with the first lines of what follows them
+		needed := style.before + style.leading
+		if style.keepNext && i+1 < len(blocks) {
+			next := d.pdfStyle(blocks[i+1].kind)
+			needed += style.after + next.before + 2*next.leading
+		}
+		if y-needed < pdfMarginBottom && y < pdfPageHeight-pdfMarginTop {
+			newPage()
+		} else if y < pdfPageHeight-pdfMarginTop {
+			y -= style.before
+		}
+
+		fmt.Fprintf(page, "%s rg\n", pdfColor(style.color))
+		for j, line := range lines {
+			if y-style.leading < pdfMarginBottom {
+				newPage()
+				fmt.Fprintf(page, "%s rg\n", pdfColor(style.color))
+			}
+			y -= style.leading
+			if b.kind == blockBullet && j == 0 {
+				fmt.Fprintf(page, "BT /F%d %.1f Tf %.2f %.2f Td %s Tj ET\n", style.font, style.size, pdfMarginX+2, y, pdfString([]byte{0x95}))
+			}
+			fmt.Fprintf(page, "BT /F%d %.1f Tf %.2f %.2f Td %s Tj ET\n", style.font, style.size, pdfMarginX+indent, y, pdfString(line))
+		}
+		y -= style.after
+	}
+
+	// Running header and footer, now that the number of pages is known
+	header, footer := pdfText(d.fill(d.Header)), pdfText(d.fill(d.Footer))
+	for n, page := range pages {
+		page.WriteString(pdfColor("666666") + " rg\n")
+		if len(header) > 0 {
+			fmt.Fprintf(page, "BT /F%d 9.0 Tf %.2f %.2f Td %s Tj ET\n", pdfRegular,
+				pdfPageWidth-pdfMarginX-pdfWidth(header, pdfRegular, 9), pdfPageHeight-40, pdfString(header))
+		}
+		text := pdfText(fmt.Sprintf("Page %d of %d", n+1, len(pages)))
+		if len(footer) > 0 {
+			text = append(append(append([]byte{}, footer...), pdfText(" · ")...), text...)
+		}
+		fmt.Fprintf(page, "BT /F%d 9.0 Tf %.2f %.2f Td %s Tj ET\n", pdfRegular,
+			(pdfPageWidth-pdfWidth(text, pdfRegular, 9))/2, 36.0, pdfString(text))
+	}
+
+	// Objects: the catalog, the page tree, the fonts, then each page and its content stream
+	objects := []string{"<< /Type /Catalog /Pages 2 0 R >>", ""}
+	var fonts strings.Builder
+	for i, name := range pdfFontNames {
+		objects = append(objects, fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+		fmt.Fprintf(&fonts, "/F%d %d 0 R ", i, len(objects))
+	}
+	var kids []string
+	for _, page := range pages {
+		content := page.String()
+		pageID := len(objects) + 1
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << %s>> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, fonts.String(), pageID+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageID))
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return out.Bytes()
+}
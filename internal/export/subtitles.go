@@ -0,0 +1,294 @@
+package export
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"scriberr/internal/rag"
+	"scriberr/internal/transcription/interfaces"
+)
+
+// Subtitle formats
+const (
+	FormatSRT = "srt"
+	FormatVTT = "vtt"
+)
+
+// Default subtitle layout: two lines of at most 42 characters per cue, the common broadcast
+// and streaming limit
+const (
+	DefaultSubtitleLineLength = 42
+	DefaultSubtitleLines      = 2
+)
+
+// SubtitleOptions sets how a transcript is cut into subtitle cues
+type SubtitleOptions struct {
+	MaxLineLength int               // Characters per line; a longer word gets a line of its own, or is split in Chinese and Japanese
+	MaxLines      int               // Lines per cue
+	Speakers      bool              // Start a cue with "Name: " whenever the speaker changes
+	SpeakerNames  map[string]string // Custom names of speaker labels
+}
+
+// token is a piece of a segment's text that lines and cues can be broken between
+type token struct {
+	text  string
+	space bool // Whether a space separates it from the token before
+}
+
+// cue is one subtitle: lines shown together from Start to End
+type cue struct {
+	Start, End float64
+	Lines      []string
+}
+
+// Subtitles renders timed segments as SRT or WebVTT subtitles. Segments too long for one cue
+// are split between words, at the end of a sentence where one is near, and each part is timed
+// by the word timestamps in words when they still match the segment's text, or else by its
+// share of the segment's characters. It returns the rendered file, its content type and its
+// file extension.
+func Subtitles(format string, segments []interfaces.TranscriptSegment, words []interfaces.TranscriptWord, opts SubtitleOptions) ([]byte, string, string, error) {
+	if format != FormatSRT && format != FormatVTT {
+		return nil, "", "", fmt.Errorf("unknown subtitle format %q", format)
+	}
+	if opts.MaxLineLength <= 0 {
+		opts.MaxLineLength = DefaultSubtitleLineLength
+	}
+	if opts.MaxLines <= 0 {
+		opts.MaxLines = DefaultSubtitleLines
+	}
+
+	var cues []cue
+	previousSpeaker := ""
+	for _, segment := range segments {
+		tokens := splitTokens(segment.Text, opts.MaxLineLength)
+		if len(tokens) == 0 || segment.End <= segment.Start {
+			continue
+		}
+		speaker, prefix := "", ""
+		if segment.Speaker != nil {
+			speaker = *segment.Speaker
+		}
+		if opts.Speakers && speaker != "" && speaker != previousSpeaker {
+			name := speaker
+			if custom := opts.SpeakerNames[speaker]; custom != "" {
+				name = custom
+			}
+			prefix = name + ":"
+		}
+		previousSpeaker = speaker
+		cues = append(cues, segmentCues(segment, tokens, segmentWords(segment, tokens, words), prefix, opts)...)
+	}
+
+	// Cues never overlap, so players don't stack them
+	for i := 1; i < len(cues); i++ {
+		if cues[i].Start < cues[i-1].End {
+			cues[i-1].End = math.Max(cues[i].Start, cues[i-1].Start)
+			cues[i].Start = cues[i-1].End
+		}
+	}
+
+	var out strings.Builder
+	if format == FormatVTT {
+		out.WriteString("WEBVTT\n")
+		for i, c := range cues {
+			text := strings.Join(c.Lines, "\n")
+			text = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+			fmt.Fprintf(&out, "\n%d\n%s --> %s\n%s\n", i+1, vttTimestamp(c.Start), vttTimestamp(c.End), text)
+		}
+		return []byte(out.String()), "text/vtt; charset=utf-8", "vtt", nil
+	}
+	for i, c := range cues {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		fmt.Fprintf(&out, "%d\n%s --> %s\n%s\n", i+1, srtTimestamp(c.Start), srtTimestamp(c.End), strings.Join(c.Lines, "\n"))
+	}
+	return []byte(out.String()), "application/x-subrip; charset=utf-8", "srt", nil
+}
+
+// srtTimestamp formats seconds as an SRT timestamp, HH:MM:SS,mmm
+func srtTimestamp(seconds float64) string {
+	return strings.Replace(vttTimestamp(seconds), ".", ",", 1)
+}
+
+// splitTokens splits a segment's text into tokens at its spaces. Chinese and Japanese are
+// written without spaces, so a word of theirs longer than a line is split further at the ends
+// of its sentences, found as for RAG chunks, and a sentence still longer than a line into
+// single characters.
+func splitTokens(text string, width int) []token {
+	var tokens []token
+	for _, field := range strings.Fields(text) {
+		if len([]rune(field)) <= width || !strings.ContainsFunc(field, unspaced) {
+			tokens = append(tokens, token{text: field, space: true})
+			continue
+		}
+		space := true
+		for _, sentence := range rag.SplitSentences(field) {
+			if len([]rune(sentence)) <= width {
+				tokens = append(tokens, token{text: sentence, space: space})
+				space = false
+				continue
+			}
+			for _, r := range sentence {
+				tokens = append(tokens, token{text: string(r), space: space})
+				space = false
+			}
+		}
+	}
+	return tokens
+}
+
+// unspaced reports whether r is from a script written without spaces between words
+func unspaced(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+// size returns the characters a token adds to the text after the token before it
+func (t token) size() int {
+	if t.space {
+		return len([]rune(t.text)) + 1
+	}
+	return len([]rune(t.text))
+}
+
+// segmentWords returns the word timestamps of a segment, one per token of its text, or nil
+// when they don't match the text any more, as after a correction
+func segmentWords(segment interfaces.TranscriptSegment, tokens []token, words []interfaces.TranscriptWord) []interfaces.TranscriptWord {
+	var inside []interfaces.TranscriptWord
+	for _, word := range words {
+		if word.Start >= segment.Start-0.01 && word.End <= segment.End+0.01 && strings.TrimSpace(word.Word) != "" {
+			inside = append(inside, word)
+		}
+	}
+	if len(inside) != len(tokens) {
+		return nil
+	}
+	return inside
+}
+
+// segmentCues splits a segment into cues of at most opts.MaxLines lines, timing each by its
+// words when known
+func segmentCues(segment interfaces.TranscriptSegment, tokens []token, words []interfaces.TranscriptWord, prefix string, opts SubtitleOptions) []cue {
+	units := tokens
+	if prefix != "" {
+		units = append([]token{{text: prefix, space: true}}, tokens...)
+	}
+	capacity := opts.MaxLineLength * opts.MaxLines
+
+	// Cut the tokens into runs that fit a cue. Where the rest of the segment won't fit in the
+	// cue anyway, a run ends early at the end of a sentence, once it is a third full.
+	rest := make([]int, len(units)+1)
+	for i := len(units) - 1; i >= 0; i-- {
+		rest[i] = rest[i+1] + units[i].size()
+	}
+	var runs [][2]int
+	start, length := 0, 0
+	for i, unit := range units {
+		added := len([]rune(unit.text))
+		if i > start && unit.space {
+			added++
+		}
+		if i > start && (length+added > capacity || len(wrapLines(units[start:i+1], opts.MaxLineLength)) > opts.MaxLines) {
+			runs = append(runs, [2]int{start, i})
+			start, length, added = i, 0, len([]rune(unit.text))
+		}
+		length += added
+		if endsSentence(unit.text) && i+1 < len(units) && length+rest[i+1] > capacity && length*3 >= capacity {
+			runs = append(runs, [2]int{start, i + 1})
+			start, length = i+1, 0
+		}
+	}
+	if start < len(units) {
+		runs = append(runs, [2]int{start, len(units)})
+	}
+
+	total := rest[0]
+	duration := segment.End - segment.Start
+	offset := 0
+	if prefix != "" {
+		offset = 1
+	}
+	cues := make([]cue, 0, len(runs))
+	before := 0
+	for _, run := range runs {
+		chars := 0
+		for _, unit := range units[run[0]:run[1]] {
+			chars += unit.size()
+		}
+		c := cue{
+			Start: segment.Start + duration*float64(before)/float64(total),
+			End:   segment.Start + duration*float64(before+chars)/float64(total),
+			Lines: balanceLines(units[run[0]:run[1]], opts.MaxLineLength),
+		}
+		before += chars
+
+		// Word timestamps give the exact times; the prefix has none
+		first, last := run[0]-offset, run[1]-1-offset
+		if words != nil && first < 0 {
+			first = 0
+		}
+		if words != nil && last >= first {
+			c.Start, c.End = words[first].Start, words[last].End
+			if len(cues) == 0 {
+				c.Start = math.Min(c.Start, segment.Start)
+			}
+		}
+		cues = append(cues, c)
+	}
+	if words != nil && len(cues) > 0 {
+		cues[len(cues)-1].End = math.Max(cues[len(cues)-1].End, segment.End)
+	}
+	return cues
+}
+
+// endsSentence reports whether a token ends a sentence
+func endsSentence(token string) bool {
+	token = strings.TrimRight(token, `"')]”’`)
+	return strings.HasSuffix(token, ".") || strings.HasSuffix(token, "?") || strings.HasSuffix(token, "!") ||
+		strings.HasSuffix(token, "。") || strings.HasSuffix(token, "？") || strings.HasSuffix(token, "！")
+}
+
+// wrapLines fills lines of at most width characters with tokens, greedily
+func wrapLines(tokens []token, width int) []string {
+	var lines []string
+	line := ""
+	for _, token := range tokens {
+		switch {
+		case line == "":
+			line = token.text
+		case len([]rune(line))+token.size() <= width:
+			if token.space {
+				line += " "
+			}
+			line += token.text
+		default:
+			lines = append(lines, line)
+			line = token.text
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// balanceLines wraps tokens into as few lines as fit within width, with the lines as even in
+// length as possible, so a two-line cue isn't one long line and a short one
+func balanceLines(tokens []token, width int) []string {
+	lines := wrapLines(tokens, width)
+	if len(lines) < 2 {
+		return lines
+	}
+	chars := len([]rune(tokens[0].text))
+	for _, token := range tokens[1:] {
+		chars += token.size()
+	}
+	for narrower := (chars + len(lines) - 1) / len(lines); narrower < width; narrower++ {
+		if balanced := wrapLines(tokens, narrower); len(balanced) == len(lines) {
+			return balanced
+		}
+	}
+	return lines
+}
@@ -0,0 +1,99 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+func TestSubtitlesExport(t *testing.T) {
+	alice, bob := "SPEAKER_00", "SPEAKER_01"
+	segments := []interfaces.TranscriptSegment{
+		{Start: 0, End: 2, Text: "Hello there.", Speaker: &alice},
+		{Start: 2, End: 12, Text: "We shipped the release on Friday. The dashboards look fine and nobody has paged the on-call engineer since then.", Speaker: &alice},
+		{Start: 11.5, End: 14, Text: "Great <news> & thanks.", Speaker: &bob},
+	}
+	words := []interfaces.TranscriptWord{
+		{Start: 0.4, End: 0.9, Word: "Hello"},
+		{Start: 1.0, End: 1.6, Word: "there."},
+	}
+	opts := SubtitleOptions{Speakers: true, SpeakerNames: map[string]string{alice: "Alice"}}
+
+	data, contentType, extension, err := Subtitles(FormatSRT, segments, words, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "application/x-subrip; charset=utf-8" || extension != "srt" {
+		t.Errorf("unexpected file type %q .%s", contentType, extension)
+	}
+	want := "1\n00:00:00,000 --> 00:00:02,000\nAlice: Hello there.\n\n" +
+		"2\n00:00:02,000 --> 00:00:05,009\nWe shipped the release on Friday.\n\n" +
+		"3\n00:00:05,009 --> 00:00:11,500\nThe dashboards look fine and nobody has\npaged the on-call engineer since then.\n\n" +
+		"4\n00:00:11,500 --> 00:00:14,000\nSPEAKER_01: Great <news> & thanks.\n"
+	if string(data) != want {
+		t.Errorf("unexpected SRT\n%s", data)
+	}
+
+	opts.Speakers = false
+	data, _, _, err = Subtitles(FormatVTT, segments[2:], nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "WEBVTT\n\n1\n00:00:11.500 --> 00:00:14.000\nGreat &lt;news&gt; &amp; thanks.\n"; string(data) != want {
+		t.Errorf("unexpected WebVTT\n%s", data)
+	}
+
+	if _, _, _, err := Subtitles("ass", segments, nil, opts); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestSubtitleLinesStayWithinLimits(t *testing.T) {
+	text := strings.Repeat("word ", 60) + "supercalifragilisticexpialidocious-and-then-some"
+	segments := []interfaces.TranscriptSegment{{Start: 0, End: 60, Text: text}}
+	data, _, _, err := Subtitles(FormatSRT, segments, nil, SubtitleOptions{MaxLineLength: 32, MaxLines: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cue := range strings.Split(strings.TrimSpace(string(data)), "\n\n") {
+		lines := strings.Split(cue, "\n")[2:]
+		if len(lines) > 2 {
+			t.Errorf("cue has %d lines:\n%s", len(lines), cue)
+		}
+		for _, line := range lines {
+			if len(line) > 32 && strings.Contains(line, " ") {
+				t.Errorf("line too long: %q", line)
+			}
+		}
+	}
+}
+
+func TestSubtitleLinesSplitTextWithoutSpaces(t *testing.T) {
+	text := strings.Repeat("今日は新しいバージョンを公開しました。", 4) + strings.Repeat("問", 30)
+	segments := []interfaces.TranscriptSegment{{Start: 0, End: 20, Text: text}}
+	data, _, _, err := Subtitles(FormatSRT, segments, nil, SubtitleOptions{MaxLineLength: 16, MaxLines: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var joined strings.Builder
+	cues := strings.Split(strings.TrimSpace(string(data)), "\n\n")
+	for _, cue := range cues {
+		lines := strings.Split(cue, "\n")[2:]
+		if len(lines) > 2 {
+			t.Errorf("cue has %d lines:\n%s", len(lines), cue)
+		}
+		for _, line := range lines {
+			if len([]rune(line)) > 16 || strings.Contains(line, " ") {
+				t.Errorf("unexpected line %q", line)
+			}
+			joined.WriteString(line)
+		}
+	}
+	if joined.String() != text {
+		t.Errorf("text changed: %q", joined.String())
+	}
+	if len(cues) < 4 || !strings.HasSuffix(cues[0], "。") {
+		t.Errorf("expected cues to end at sentence ends, got %q", cues[0])
+	}
+}
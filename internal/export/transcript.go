@@ -0,0 +1,67 @@
+// Package export renders transcripts into downloadable document formats.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+)
+
+// Export formats
+const (
+	FormatMarkdown = "markdown"
+	FormatDOCX     = "docx"
+)
+
+// TranscriptSegments returns the timed segments of a job's transcript. A transcript stored
+// as plain text, or without segments, becomes one untimed segment per line of text.
+func TranscriptSegments(job *models.TranscriptionJob) []interfaces.TranscriptSegment {
+	if job.Transcript == nil {
+		return nil
+	}
+	text := *job.Transcript
+	var result interfaces.TranscriptResult
+	if err := json.Unmarshal([]byte(text), &result); err == nil {
+		var segments []interfaces.TranscriptSegment
+		for _, segment := range result.Segments {
+			if strings.TrimSpace(segment.Text) != "" {
+				segments = append(segments, segment)
+			}
+		}
+		if len(segments) > 0 {
+			return segments
+		}
+		text = result.Text
+	} else if strings.HasPrefix(strings.TrimSpace(text), "{") {
+		return nil
+	}
+
+	var segments []interfaces.TranscriptSegment
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			segments = append(segments, interfaces.TranscriptSegment{Text: line})
+		}
+	}
+	return segments
+}
+
+// TranscriptWords returns the word timestamps of a job's transcript, if it has any
+func TranscriptWords(job *models.TranscriptionJob) []interfaces.TranscriptWord {
+	if job.Transcript == nil {
+		return nil
+	}
+	var result interfaces.TranscriptResult
+	if err := json.Unmarshal([]byte(*job.Transcript), &result); err != nil {
+		return nil
+	}
+	return result.WordSegments
+}
+
+// Timestamp formats seconds as HH:MM:SS
+func Timestamp(seconds float64) string {
+	total := int(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, total%3600/60, total%60)
+}
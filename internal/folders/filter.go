@@ -0,0 +1,90 @@
+// Package folders evaluates smart folder filters against transcriptions.
+package folders
+
+import (
+	"fmt"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Validate checks that a filter is well formed and normalizes its values
+func Validate(filter *models.SmartFolderFilter) error {
+	filter.Tags = NormalizeTerms(filter.Tags)
+	filter.Speakers = NormalizeTerms(filter.Speakers)
+	filter.Text = strings.TrimSpace(filter.Text)
+
+	switch filter.Status {
+	case "", models.StatusUploaded, models.StatusPending, models.StatusProcessing, models.StatusCompleted, models.StatusFailed:
+	default:
+		return fmt.Errorf("invalid status: %s", filter.Status)
+	}
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && filter.CreatedBefore.Before(*filter.CreatedAfter) {
+		return fmt.Errorf("created_before must be after created_after")
+	}
+	return nil
+}
+
+// Apply narrows a query on transcription_jobs to the transcriptions matching filter
+func Apply(query *gorm.DB, filter models.SmartFolderFilter) *gorm.DB {
+	// Tags are a JSON array; rows written before tags existed may hold NULL
+	for _, tag := range filter.Tags {
+		query = query.Where(`EXISTS (SELECT 1 FROM json_each(CASE WHEN json_valid(transcription_jobs.tags) THEN transcription_jobs.tags ELSE '[]' END) WHERE lower(value) = lower(?))`, tag)
+	}
+
+	if len(filter.Speakers) > 0 {
+		conditions := make([]string, 0, len(filter.Speakers))
+		args := make([]interface{}, 0, len(filter.Speakers)*3)
+		for _, speaker := range filter.Speakers {
+			// Mapped names and raw labels from the speaker mapping, or the raw label in the transcript itself
+			conditions = append(conditions, `EXISTS (SELECT 1 FROM speaker_mappings sm WHERE sm.transcription_job_id = transcription_jobs.id AND (lower(sm.custom_name) = lower(?) OR lower(sm.original_speaker) = lower(?))) OR transcription_jobs.transcript LIKE ?`)
+			args = append(args, speaker, speaker, `%"`+speaker+`"%`)
+		}
+		query = query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+
+	if filter.Text != "" {
+		pattern := "%" + filter.Text + "%"
+		query = query.Where("(transcription_jobs.title LIKE ? COLLATE NOCASE OR transcription_jobs.summary LIKE ? COLLATE NOCASE OR transcription_jobs.transcript LIKE ? COLLATE NOCASE)", pattern, pattern, pattern)
+	}
+
+	if filter.Status != "" {
+		query = query.Where("transcription_jobs.status = ?", filter.Status)
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("transcription_jobs.created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where("transcription_jobs.created_at < ?", *filter.CreatedBefore)
+	}
+	return query
+}
+
+// MatchingJobIDs returns the IDs of every transcription in a folder
+func MatchingJobIDs(folder *models.SmartFolder) ([]string, error) {
+	var ids []string
+	query := Apply(database.DB.Model(&models.TranscriptionJob{}), folder.Filter)
+	if err := query.Where("transcription_jobs.id NOT LIKE 'track_%'").Pluck("transcription_jobs.id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to evaluate folder %s: %w", folder.ID, err)
+	}
+	return ids, nil
+}
+
+// NormalizeTerms trims terms (tags, speaker names) and drops empty and duplicate (case-insensitive) ones
+func NormalizeTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	out := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		key := strings.ToLower(term)
+		if term == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, term)
+	}
+	return out
+}
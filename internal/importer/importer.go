@@ -0,0 +1,248 @@
+// Package importer reads transcripts exported by other transcription tools, so a library kept
+// elsewhere can be brought into Scriberr: Whisper and WhisperX JSON, otter.ai and Descript
+// text exports, and SRT and WebVTT subtitles.
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+// Import formats
+const (
+	FormatWhisper  = "whisper"  // openai-whisper or whisper.cpp JSON
+	FormatWhisperX = "whisperx" // WhisperX JSON, with speakers and word timestamps
+	FormatOtter    = "otter"    // otter.ai TXT export
+	FormatDescript = "descript" // Descript plain text export
+	FormatSRT      = "srt"
+	FormatVTT      = "vtt"
+)
+
+// Formats lists the formats transcripts can be imported from
+var Formats = []string{FormatWhisper, FormatWhisperX, FormatOtter, FormatDescript, FormatSRT, FormatVTT}
+
+// ErrUnknownFormat is returned when the format of a file can't be told
+var ErrUnknownFormat = errors.New("unrecognized transcript format")
+
+// Transcript is a transcript read from another tool's export, in the form Scriberr stores
+// transcripts. Speakers the export names are labelled SPEAKER_00, SPEAKER_01 and so on in
+// the order they first speak, with their names in SpeakerNames; labels of that form in the
+// export are kept as they are.
+type Transcript struct {
+	Format       string
+	Result       interfaces.TranscriptResult
+	SpeakerNames map[string]string // Names of speaker labels
+}
+
+// Speakers returns the number of speakers in the transcript
+func (t *Transcript) Speakers() int {
+	seen := map[string]bool{}
+	for _, segment := range t.Result.Segments {
+		if segment.Speaker != nil {
+			seen[*segment.Speaker] = true
+		}
+	}
+	return len(seen)
+}
+
+// Parse reads a transcript in the given format, or in the format Detect finds when format
+// is empty. filename, which may be empty, helps tell the format.
+func Parse(data []byte, format, filename string) (*Transcript, error) {
+	if !utf8.Valid(data) {
+		return nil, errors.New("the transcript is not UTF-8 text")
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	text := strings.ReplaceAll(strings.ReplaceAll(string(data), "\r\n", "\n"), "\r", "\n")
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		if format = Detect(data, filename); format == "" {
+			return nil, ErrUnknownFormat
+		}
+	}
+
+	var entries []entry
+	var err error
+	transcript := &Transcript{Format: format}
+	switch format {
+	case FormatWhisper, FormatWhisperX:
+		return parseWhisper(data, transcript)
+	case FormatOtter:
+		entries, err = parseOtter(text)
+	case FormatDescript:
+		entries, err = parseDescript(text)
+	case FormatSRT, FormatVTT:
+		entries, err = parseSubtitles(text, format)
+	default:
+		return nil, fmt.Errorf("unknown format %q: formats are %s", format, strings.Join(Formats, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	transcript.build(entries)
+	if len(transcript.Result.Segments) == 0 {
+		return nil, fmt.Errorf("no transcript found in the %s file", format)
+	}
+	return transcript, nil
+}
+
+// Patterns Detect looks for in text exports
+var (
+	otterHeader    = regexp.MustCompile(`^(?:(\S.*?)\s+)?((?:\d+:)?\d{1,2}:\d{2})$`)
+	descriptLine   = regexp.MustCompile(`^\[((?:\d+:)?\d{1,2}:\d{2}(?:[.,]\d+)?)\]\s*(.*)$`)
+	subtitleTiming = regexp.MustCompile(`^((?:\d+:)?\d{1,2}:\d{2}[.,]\d{1,3})\s*-->\s*((?:\d+:)?\d{1,2}:\d{2}[.,]\d{1,3})`)
+)
+
+// Detect tells the format of a transcript from its file extension and content, returning ""
+// when it can't
+func Detect(data []byte, filename string) string {
+	text := strings.TrimSpace(strings.TrimPrefix(string(data), "\xef\xbb\xbf"))
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".srt":
+		return FormatSRT
+	case ".vtt":
+		return FormatVTT
+	}
+
+	if strings.HasPrefix(text, "{") {
+		return detectWhisper(data)
+	}
+	if strings.HasPrefix(text, "WEBVTT") {
+		return FormatVTT
+	}
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+		if len(lines) == 3 {
+			break
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	switch {
+	case subtitleTiming.MatchString(lines[0]),
+		len(lines) > 1 && subtitleTiming.MatchString(lines[1]):
+		return FormatSRT
+	case descriptLine.MatchString(lines[0]):
+		return FormatDescript
+	case len(lines) > 1 && otterHeader.MatchString(lines[0]) && !otterHeader.MatchString(lines[1]):
+		return FormatOtter
+	}
+	return ""
+}
+
+// entry is a paragraph of a text export: when it starts, if the export says, who speaks and
+// what is said
+type entry struct {
+	start   float64
+	end     float64 // Zero when the export doesn't say
+	timed   bool
+	speaker string
+	text    string
+}
+
+// Seconds per word when the end of the last paragraph has to be guessed, about 150 words a minute
+const secondsPerWord = 0.4
+
+// build turns the entries of a text export into the transcript. A paragraph without an end
+// time ends where the next timed one starts, and the last one after as long as it takes to
+// say; paragraphs without times are left untimed.
+func (t *Transcript) build(entries []entry) {
+	labels := newLabeller()
+	var texts []string
+	for i, e := range entries {
+		text := strings.Join(strings.Fields(e.text), " ")
+		if text == "" {
+			continue
+		}
+		segment := interfaces.TranscriptSegment{Text: text}
+		if e.timed {
+			segment.Start, segment.End = e.start, e.end
+			if segment.End <= segment.Start {
+				segment.End = segment.Start + math.Max(1, float64(len(strings.Fields(text)))*secondsPerWord)
+				for _, next := range entries[i+1:] {
+					if next.timed && next.start > segment.Start {
+						segment.End = next.start
+						break
+					}
+				}
+			}
+		}
+		if label := labels.label(e.speaker); label != "" {
+			segment.Speaker = &label
+		}
+		t.Result.Segments = append(t.Result.Segments, segment)
+		texts = append(texts, text)
+	}
+	t.Result.Text = strings.Join(texts, " ")
+	t.SpeakerNames = labels.names
+}
+
+// speakerLabel matches the speaker labels Scriberr and WhisperX give, which are kept
+var speakerLabel = regexp.MustCompile(`^SPEAKER_\d+$`)
+
+// labeller gives the speakers of an export their labels
+type labeller struct {
+	labels map[string]string
+	names  map[string]string
+	next   int
+}
+
+func newLabeller() *labeller {
+	return &labeller{labels: map[string]string{}, names: map[string]string{}}
+}
+
+// label returns the label of a speaker, or "" for none
+func (l *labeller) label(speaker string) string {
+	speaker = strings.TrimSpace(speaker)
+	if speaker == "" {
+		return ""
+	}
+	if label, ok := l.labels[speaker]; ok {
+		return label
+	}
+	label := speaker
+	if !speakerLabel.MatchString(speaker) {
+		for {
+			label = fmt.Sprintf("SPEAKER_%02d", l.next)
+			l.next++
+			if _, taken := l.labels[label]; !taken {
+				break
+			}
+		}
+		l.names[label] = speaker
+	}
+	l.labels[speaker] = label
+	// Named speakers after this one get other labels
+	l.labels[label] = label
+	return label
+}
+
+// parseClock parses a time written as [H:]MM:SS[.mmm] or with a comma before the fraction
+func parseClock(value string) (float64, bool) {
+	parts := strings.Split(strings.Replace(value, ",", ".", 1), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+	seconds := 0.0
+	for i, part := range parts {
+		number, err := strconv.ParseFloat(part, 64)
+		if err != nil || number < 0 || (i < len(parts)-1 && strings.Contains(part, ".")) {
+			return 0, false
+		}
+		seconds = seconds*60 + number
+	}
+	return seconds, true
+}
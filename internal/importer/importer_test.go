@@ -0,0 +1,160 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// segmentLines describes the segments one per line as "start-end speaker: text", for comparing
+func segmentLines(transcript *Transcript) string {
+	var lines []string
+	for _, segment := range transcript.Result.Segments {
+		speaker := "-"
+		if segment.Speaker != nil {
+			speaker = *segment.Speaker
+		}
+		lines = append(lines, fmt.Sprintf("%g-%g %s: %s", segment.Start, segment.End, speaker, segment.Text))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func parse(t *testing.T, data, format, filename string) *Transcript {
+	t.Helper()
+	transcript, err := Parse([]byte(data), format, filename)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return transcript
+}
+
+func TestParseWhisperX(t *testing.T) {
+	data := `{
+		"language": "en",
+		"segments": [
+			{"start": 0.5, "end": 2.0, "text": " Hello there.", "speaker": "SPEAKER_01",
+			 "words": [{"word": "Hello", "start": 0.5, "end": 1.0, "score": 0.9, "speaker": "SPEAKER_01"},
+			           {"word": "there.", "start": 1.1, "end": 2.0, "score": 0.8}]},
+			{"start": 2.5, "end": 4.0, "text": "It's 2 o'clock.", "speaker": "SPEAKER_00",
+			 "words": [{"word": "It's", "start": 2.5, "end": 2.8}, {"word": "2"}, {"word": "o'clock.", "start": 3.0, "end": 4.0}]}
+		]
+	}`
+	transcript := parse(t, data, "", "meeting.json")
+	if transcript.Format != FormatWhisperX {
+		t.Fatalf("format = %q, want whisperx", transcript.Format)
+	}
+	want := "0.5-2 SPEAKER_01: Hello there.\n2.5-4 SPEAKER_00: It's 2 o'clock."
+	if got := segmentLines(transcript); got != want {
+		t.Fatalf("segments:\n%s\nwant:\n%s", got, want)
+	}
+	if transcript.Result.Text != "Hello there. It's 2 o'clock." || transcript.Result.Language != "en" {
+		t.Fatalf("text %q, language %q", transcript.Result.Text, transcript.Result.Language)
+	}
+	// The word without timestamps is left out, and words take their segment's speaker
+	words := transcript.Result.WordSegments
+	if len(words) != 4 || words[1].Speaker == nil || *words[1].Speaker != "SPEAKER_01" || words[3].Word != "o'clock." {
+		t.Fatalf("unexpected words: %+v", words)
+	}
+	if len(transcript.SpeakerNames) != 0 || transcript.Speakers() != 2 {
+		t.Fatalf("names %v, speakers %d", transcript.SpeakerNames, transcript.Speakers())
+	}
+}
+
+func TestParseWhisper(t *testing.T) {
+	transcript := parse(t, `{"text": "One. Two.", "segments": [{"id": 0, "start": 0, "end": 1.5, "text": " One."}, {"id": 1, "start": 1.5, "end": 3, "text": " Two."}], "language": "de"}`, "", "")
+	if transcript.Format != FormatWhisper {
+		t.Fatalf("format = %q, want whisper", transcript.Format)
+	}
+	if got := segmentLines(transcript); got != "0-1.5 -: One.\n1.5-3 -: Two." {
+		t.Fatalf("segments:\n%s", got)
+	}
+
+	// whisper.cpp's -oj output
+	cpp := `{"result": {"language": "en"}, "transcription": [{"offsets": {"from": 0, "to": 2500}, "text": " Good morning."}]}`
+	transcript = parse(t, cpp, "", "")
+	if transcript.Format != FormatWhisper || segmentLines(transcript) != "0-2.5 -: Good morning." || transcript.Result.Language != "en" {
+		t.Fatalf("unexpected whisper.cpp transcript: %s (%s)", segmentLines(transcript), transcript.Format)
+	}
+}
+
+func TestParseOtter(t *testing.T) {
+	data := "Jane Doe  0:03\r\nWelcome, everyone.\r\nLet's start.\r\n\r\nSpeaker 2  1:05\r\nThanks.\r\n\r\nJane Doe  1:01:10\r\nBye.\r\n\r\nTranscribed by https://otter.ai\r\n"
+	transcript := parse(t, data, "", "meeting.txt")
+	if transcript.Format != FormatOtter {
+		t.Fatalf("format = %q, want otter", transcript.Format)
+	}
+	want := "3-65 SPEAKER_00: Welcome, everyone. Let's start.\n65-3670 SPEAKER_01: Thanks.\n3670-3671 SPEAKER_00: Bye."
+	if got := segmentLines(transcript); got != want {
+		t.Fatalf("segments:\n%s\nwant:\n%s", got, want)
+	}
+	if transcript.SpeakerNames["SPEAKER_00"] != "Jane Doe" || transcript.SpeakerNames["SPEAKER_01"] != "Speaker 2" {
+		t.Fatalf("unexpected speaker names: %v", transcript.SpeakerNames)
+	}
+}
+
+func TestParseDescript(t *testing.T) {
+	data := "[00:00:00] Alan: Hi, I'm Alan.\nThis carries on.\n\n[00:00:04] Bea: Hello: nice to meet you.\n[00:00:09] Alan: Likewise, and thanks for coming all the way here today.\n"
+	transcript := parse(t, data, "", "")
+	if transcript.Format != FormatDescript {
+		t.Fatalf("format = %q, want descript", transcript.Format)
+	}
+	want := "0-4 SPEAKER_00: Hi, I'm Alan. This carries on.\n4-9 SPEAKER_01: Hello: nice to meet you.\n9-13 SPEAKER_00: Likewise, and thanks for coming all the way here today."
+	if got := segmentLines(transcript); got != want {
+		t.Fatalf("segments:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Without timecodes the transcript is untimed
+	transcript = parse(t, "Alan: Hi.\n\nBea: Hello.\n", FormatDescript, "")
+	if got := segmentLines(transcript); got != "0-0 SPEAKER_00: Hi.\n0-0 SPEAKER_01: Hello." {
+		t.Fatalf("segments:\n%s", got)
+	}
+}
+
+func TestParseSubtitles(t *testing.T) {
+	srt := "1\n00:00:01,000 --> 00:00:03,500\nDana: <i>Hello</i> and\nwelcome.\n\n2\n00:00:03,500 --> 00:00:05,000\nSo, the agenda: budget.\n\n3\n00:00:05,000 --> 00:00:07,250\n{\\an8}Eli: Sounds good.\n"
+	transcript := parse(t, srt, "", "")
+	if transcript.Format != FormatSRT {
+		t.Fatalf("format = %q, want srt", transcript.Format)
+	}
+	want := "1-3.5 SPEAKER_00: Hello and welcome.\n3.5-5 SPEAKER_00: So, the agenda: budget.\n5-7.25 SPEAKER_01: Sounds good."
+	if got := segmentLines(transcript); got != want {
+		t.Fatalf("segments:\n%s\nwant:\n%s", got, want)
+	}
+
+	vtt := "WEBVTT\n\nNOTE exported elsewhere\n\nintro\n00:01.000 --> 00:02.000 align:start\n<v.loud Dana>Fish &amp; chips</v>\n\n01:00:00.000 --> 01:00:01.500\nNo speaker change.\n"
+	transcript = parse(t, vtt, "", "talk.vtt")
+	want = "1-2 SPEAKER_00: Fish & chips\n3600-3601.5 SPEAKER_00: No speaker change."
+	if transcript.Format != FormatVTT || segmentLines(transcript) != want {
+		t.Fatalf("segments:\n%s\nwant:\n%s", segmentLines(transcript), want)
+	}
+	if transcript.SpeakerNames["SPEAKER_00"] != "Dana" {
+		t.Fatalf("unexpected speaker names: %v", transcript.SpeakerNames)
+	}
+
+	if _, err := Parse([]byte("1\n00:00:01 --> later\nHi\n"), FormatSRT, ""); err == nil {
+		t.Fatal("expected an error for an invalid cue timing")
+	}
+}
+
+func TestSpeakerLabels(t *testing.T) {
+	labels := newLabeller()
+	got := []string{labels.label("SPEAKER_00"), labels.label("Ann"), labels.label("SPEAKER_00"), labels.label("Bo"), labels.label("Ann")}
+	if strings.Join(got, ",") != "SPEAKER_00,SPEAKER_01,SPEAKER_00,SPEAKER_02,SPEAKER_01" {
+		t.Fatalf("labels = %v", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := Parse([]byte("Just some notes without any structure."), "", "notes.txt"); err != ErrUnknownFormat {
+		t.Fatalf("err = %v, want ErrUnknownFormat", err)
+	}
+	if _, err := Parse([]byte(`{"segments": []}`), FormatWhisper, ""); err == nil {
+		t.Fatal("expected an error for a transcript without segments")
+	}
+	if _, err := Parse([]byte("x"), "docx", ""); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+	if _, err := Parse([]byte{0xff, 0xfe, 0x00}, "", ""); err == nil {
+		t.Fatal("expected an error for text that isn't UTF-8")
+	}
+}
@@ -0,0 +1,149 @@
+package importer
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// speakerPrefix matches a paragraph or cue that starts with who speaks, as in "Jane Doe: Hello"
+var speakerPrefix = regexp.MustCompile(`^(\p{L}[\p{L}\p{N}_.'’ -]{0,39}?):\s+(\S.*)$`)
+
+// Most words in a speaker's name, so a sentence with a colon isn't taken for one
+const maxNameWords = 4
+
+// splitSpeaker splits the speaker off the start of a line, returning "" when there is none
+func splitSpeaker(line string) (string, string) {
+	match := speakerPrefix.FindStringSubmatch(line)
+	if match == nil || len(strings.Fields(match[1])) > maxNameWords {
+		return "", line
+	}
+	return match[1], match[2]
+}
+
+// paragraphs splits text into runs of lines between blank lines, trimmed
+func paragraphs(text string) [][]string {
+	var result [][]string
+	var current []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			if len(current) > 0 {
+				result = append(result, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		result = append(result, current)
+	}
+	return result
+}
+
+// parseOtter reads an otter.ai TXT export, where each paragraph starts with a line of who
+// speaks and when, as in "Jane Doe  1:23", and the export ends with a "Transcribed by" line
+func parseOtter(text string) ([]entry, error) {
+	var entries []entry
+	for _, lines := range paragraphs(text) {
+		if match := otterHeader.FindStringSubmatch(lines[0]); match != nil {
+			start, ok := parseClock(match[2])
+			if !ok {
+				return nil, fmt.Errorf("invalid time %q", match[2])
+			}
+			entries = append(entries, entry{start: start, timed: true, speaker: match[1], text: strings.Join(lines[1:], " ")})
+			continue
+		}
+		if last := strings.ToLower(lines[len(lines)-1]); strings.HasPrefix(last, "transcribed by") && strings.Contains(last, "otter.ai") {
+			lines = lines[:len(lines)-1]
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		// Text that isn't under a header of its own carries on the paragraph before it
+		if len(entries) > 0 {
+			entries[len(entries)-1].text += " " + strings.Join(lines, " ")
+		} else {
+			entries = append(entries, entry{text: strings.Join(lines, " ")})
+		}
+	}
+	return entries, nil
+}
+
+// parseDescript reads a Descript plain text export: paragraphs that start with their time
+// in brackets when timecodes were exported, and with "Name:" when speaker labels were, as
+// in "[00:01:23] Jane Doe: Hello"
+func parseDescript(text string) ([]entry, error) {
+	var entries []entry
+	for _, lines := range paragraphs(text) {
+		for i, line := range lines {
+			e := entry{}
+			if match := descriptLine.FindStringSubmatch(line); match != nil {
+				start, ok := parseClock(match[1])
+				if !ok {
+					return nil, fmt.Errorf("invalid time %q", match[1])
+				}
+				e.start, e.timed, line = start, true, match[2]
+			} else if i > 0 {
+				// A line without a time carries on the paragraph
+				entries[len(entries)-1].text += " " + line
+				continue
+			}
+			e.speaker, e.text = splitSpeaker(line)
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// Markup parseSubtitles removes from cue text
+var (
+	voiceTag    = regexp.MustCompile(`<v(?:\.[^\s>]*)?\s+([^>]*)>`)
+	markupTag   = regexp.MustCompile(`<[^>]*>`)
+	assOverride = regexp.MustCompile(`\{\\[^}]*\}`)
+)
+
+// parseSubtitles reads SRT or WebVTT cues. The speaker of a cue is taken from a WebVTT voice
+// tag or a "Name:" at its start, as Scriberr's own subtitle export writes it, and carries on
+// to the cues after it until another is named.
+func parseSubtitles(text, format string) ([]entry, error) {
+	var entries []entry
+	speaker := ""
+	for _, lines := range paragraphs(text) {
+		timing := -1
+		for i, line := range lines {
+			if strings.Contains(line, "-->") {
+				timing = i
+				break
+			}
+		}
+		// Headers, notes, styles and regions have no timing
+		if timing < 0 {
+			continue
+		}
+		match := subtitleTiming.FindStringSubmatch(lines[timing])
+		if match == nil {
+			return nil, fmt.Errorf("invalid cue timing %q", lines[timing])
+		}
+		start, ok := parseClock(match[1])
+		end, ok2 := parseClock(match[2])
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("invalid cue timing %q", lines[timing])
+		}
+
+		cue := strings.Join(lines[timing+1:], " ")
+		if voice := voiceTag.FindStringSubmatch(cue); voice != nil && strings.TrimSpace(voice[1]) != "" {
+			speaker = strings.TrimSpace(voice[1])
+		}
+		cue = assOverride.ReplaceAllString(markupTag.ReplaceAllString(cue, ""), "")
+		if format == FormatVTT {
+			cue = html.UnescapeString(cue)
+		}
+		if name, rest := splitSpeaker(strings.TrimSpace(cue)); name != "" {
+			speaker, cue = name, rest
+		}
+		entries = append(entries, entry{start: start, end: end, timed: true, speaker: speaker, text: cue})
+	}
+	return entries, nil
+}
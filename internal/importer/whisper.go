@@ -0,0 +1,130 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+// whisperJSON is the JSON written by openai-whisper (text, segments, language), WhisperX
+// (speakers on segments and words, word_segments) and whisper.cpp (transcription, result)
+type whisperJSON struct {
+	Text     string `json:"text"`
+	Language string `json:"language"`
+	Segments []struct {
+		Start   float64       `json:"start"`
+		End     float64       `json:"end"`
+		Text    string        `json:"text"`
+		Speaker string        `json:"speaker"`
+		Words   []whisperWord `json:"words"`
+	} `json:"segments"`
+	WordSegments  []whisperWord `json:"word_segments"`
+	Transcription []struct {
+		Offsets struct {
+			From float64 `json:"from"` // Milliseconds
+			To   float64 `json:"to"`
+		} `json:"offsets"`
+		Text string `json:"text"`
+	} `json:"transcription"`
+	Result struct {
+		Language string `json:"language"`
+	} `json:"result"`
+}
+
+// whisperWord is a word with its timestamps. WhisperX leaves them out of words it couldn't
+// align, such as numbers.
+type whisperWord struct {
+	Word    string   `json:"word"`
+	Start   *float64 `json:"start"`
+	End     *float64 `json:"end"`
+	Score   float64  `json:"score"`
+	Speaker string   `json:"speaker"`
+}
+
+// detectWhisper tells Whisper JSON from WhisperX JSON, returning "" for other JSON
+func detectWhisper(data []byte) string {
+	var doc whisperJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return ""
+	}
+	if len(doc.Transcription) > 0 {
+		return FormatWhisper
+	}
+	if len(doc.Segments) == 0 {
+		return ""
+	}
+	if len(doc.WordSegments) > 0 {
+		return FormatWhisperX
+	}
+	for _, segment := range doc.Segments {
+		if segment.Speaker != "" || len(segment.Words) > 0 {
+			return FormatWhisperX
+		}
+	}
+	return FormatWhisper
+}
+
+// parseWhisper reads Whisper, WhisperX or whisper.cpp JSON, keeping word timestamps when
+// there are any
+func parseWhisper(data []byte, transcript *Transcript) (*Transcript, error) {
+	var doc whisperJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid %s JSON: %w", transcript.Format, err)
+	}
+
+	labels := newLabeller()
+	result := &transcript.Result
+	result.Language = doc.Language
+	if result.Language == "" {
+		result.Language = doc.Result.Language
+	}
+	var texts []string
+	add := func(start, end float64, text, speaker string) {
+		if text = strings.Join(strings.Fields(text), " "); text == "" {
+			return
+		}
+		segment := interfaces.TranscriptSegment{Start: start, End: end, Text: text}
+		if label := labels.label(speaker); label != "" {
+			segment.Speaker = &label
+		}
+		result.Segments = append(result.Segments, segment)
+		texts = append(texts, text)
+	}
+	for _, segment := range doc.Segments {
+		add(segment.Start, segment.End, segment.Text, segment.Speaker)
+	}
+	for _, segment := range doc.Transcription {
+		add(segment.Offsets.From/1000, segment.Offsets.To/1000, segment.Text, "")
+	}
+	if len(result.Segments) == 0 {
+		return nil, fmt.Errorf("no transcript found in the %s file", transcript.Format)
+	}
+	result.Text = strings.Join(texts, " ")
+
+	words := doc.WordSegments
+	if len(words) == 0 {
+		for _, segment := range doc.Segments {
+			for _, word := range segment.Words {
+				if word.Speaker == "" {
+					word.Speaker = segment.Speaker
+				}
+				words = append(words, word)
+			}
+		}
+	}
+	for _, word := range words {
+		text := strings.TrimSpace(word.Word)
+		if text == "" || word.Start == nil || word.End == nil {
+			continue
+		}
+		timed := interfaces.TranscriptWord{Start: *word.Start, End: *word.End, Word: text, Score: word.Score}
+		if label := labels.label(word.Speaker); label != "" {
+			timed.Speaker = &label
+		}
+		result.WordSegments = append(result.WordSegments, timed)
+	}
+	transcript.SpeakerNames = labels.names
+	return transcript, nil
+}
@@ -0,0 +1,126 @@
+// Package integrations delivers action items to task managers: Todoist, Linear or any
+// service that accepts a webhook. Each task carries a back-reference to the recording and
+// the moment in it where the action item was mentioned.
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"scriberr/internal/export"
+	"scriberr/internal/netguard"
+)
+
+// Supported task managers
+const (
+	ProviderTodoist = "todoist"
+	ProviderLinear  = "linear"
+	ProviderWebhook = "webhook"
+)
+
+// Providers lists the supported task managers
+var Providers = []string{ProviderTodoist, ProviderLinear, ProviderWebhook}
+
+// Task is an action item as delivered to a task manager
+type Task struct {
+	ActionItemID   string   `json:"action_item_id"`
+	Title          string   `json:"title"`
+	Owner          string   `json:"owner,omitempty"`    // As mentioned in the recording
+	Assignee       string   `json:"assignee,omitempty"` // The owner's account in the task manager, if mapped
+	Due            string   `json:"due,omitempty"`      // As mentioned in the recording, e.g. "next Friday"
+	RecordingID    string   `json:"recording_id"`
+	RecordingTitle string   `json:"recording_title,omitempty"`
+	RecordingURL   string   `json:"recording_url,omitempty"` // Opens the recording at Timestamp, when PUBLIC_URL is set
+	Timestamp      *float64 `json:"timestamp,omitempty"`     // Seconds into the recording where it was mentioned
+	Summary        string   `json:"summary,omitempty"`       // Summary of the recording
+}
+
+// Description renders the task's details and back-reference as Markdown, for task managers
+// that only take a title and a description
+func (t Task) Description() string {
+	var b strings.Builder
+	if t.Owner != "" {
+		fmt.Fprintf(&b, "**Owner:** %s\n", t.Owner)
+	}
+	if t.Due != "" {
+		fmt.Fprintf(&b, "**Due:** %s\n", t.Due)
+	}
+	source := t.RecordingTitle
+	if source == "" {
+		source = t.RecordingID
+	}
+	if t.RecordingURL != "" {
+		source = fmt.Sprintf("[%s](%s)", source, t.RecordingURL)
+	}
+	if t.Timestamp != nil {
+		source += " at " + export.Timestamp(*t.Timestamp)
+	}
+	fmt.Fprintf(&b, "**From:** %s\n", source)
+	return b.String()
+}
+
+// Result identifies a task created in a task manager
+type Result struct {
+	ID  string `json:"id,omitempty"`
+	URL string `json:"url,omitempty"`
+}
+
+// Client creates tasks in a task manager
+type Client interface {
+	CreateTask(ctx context.Context, task Task) (Result, error)
+}
+
+// New returns the client of a task manager. token authenticates with Todoist and Linear,
+// target is the Todoist project or Linear team tasks are created in, and url is where a
+// webhook posts to. A webhook on a private network is refused unless allowPrivate is set.
+func New(provider, token, target, url string, allowPrivate bool) (Client, error) {
+	switch provider {
+	case ProviderTodoist:
+		if token == "" {
+			return nil, fmt.Errorf("a Todoist API token is required")
+		}
+		return &TodoistClient{Token: token, ProjectID: target}, nil
+	case ProviderLinear:
+		if token == "" || target == "" {
+			return nil, fmt.Errorf("a Linear API key and team ID are required")
+		}
+		return &LinearClient{APIKey: token, TeamID: target}, nil
+	case ProviderWebhook:
+		if url == "" {
+			return nil, fmt.Errorf("a webhook URL is required")
+		}
+		return &WebhookClient{URL: url, Secret: token, AllowPrivate: allowPrivate}, nil
+	}
+	return nil, fmt.Errorf("unknown task manager %q, expected one of %s", provider, strings.Join(Providers, ", "))
+}
+
+// httpClient is shared by the clients
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// publicClient is what webhooks post with. Their URLs are chosen by users, so it only
+// connects to public addresses.
+var publicClient = netguard.Client(30 * time.Second)
+
+// do sends a request with client, returning the body of a successful response. Errors reach
+// users, so the body of a failed response is left out: the server shouldn't relay what an
+// address it was pointed at answers.
+func do(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("error %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,175 @@
+package integrations
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func sampleTask() Task {
+	seconds := 754.0
+	return Task{
+		ActionItemID:   "item-1",
+		Title:          "Send the revised quote",
+		Owner:          "Dana",
+		Assignee:       "user-42",
+		Due:            "next Friday",
+		RecordingID:    "job-1",
+		RecordingTitle: "Weekly sync",
+		RecordingURL:   "https://scriberr.example.com/audio/job-1?t=754",
+		Timestamp:      &seconds,
+	}
+}
+
+func TestDescription(t *testing.T) {
+	want := "**Owner:** Dana\n**Due:** next Friday\n**From:** [Weekly sync](https://scriberr.example.com/audio/job-1?t=754) at 00:12:34\n"
+	if got := sampleTask().Description(); got != want {
+		t.Errorf("Description() = %q, want %q", got, want)
+	}
+	if got := (Task{RecordingID: "job-1"}).Description(); got != "**From:** job-1\n" {
+		t.Errorf("Description() of a bare task = %q", got)
+	}
+}
+
+func TestTodoistClient(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tasks" || r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unexpected request", http.StatusUnauthorized)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"id":"8001","content":"Send the revised quote"}`))
+	}))
+	defer server.Close()
+
+	client := &TodoistClient{Token: "secret", ProjectID: "p1", BaseURL: server.URL}
+	result, err := client.CreateTask(context.Background(), sampleTask())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ID != "8001" || result.URL != "https://app.todoist.com/app/task/8001" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if got["content"] != "Send the revised quote" || got["project_id"] != "p1" || got["assignee_id"] != "user-42" {
+		t.Errorf("unexpected payload %v", got)
+	}
+	if !strings.Contains(got["description"], "at 00:12:34") {
+		t.Errorf("description lacks the back-reference: %q", got["description"])
+	}
+
+	client.Token = "wrong"
+	if _, err := client.CreateTask(context.Background(), sampleTask()); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected the status in the error, got %v", err)
+	}
+}
+
+func TestLinearClient(t *testing.T) {
+	var request struct {
+		Query     string `json:"query"`
+		Variables struct {
+			Input map[string]string `json:"input"`
+		} `json:"variables"`
+	}
+	reply := `{"data":{"issueCreate":{"success":true,"issue":{"id":"uuid","identifier":"ENG-12","url":"https://linear.app/acme/issue/ENG-12"}}}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "lin_api_key" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Write([]byte(reply))
+	}))
+	defer server.Close()
+
+	client := &LinearClient{APIKey: "lin_api_key", TeamID: "team-1", APIURL: server.URL}
+	result, err := client.CreateTask(context.Background(), sampleTask())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ID != "ENG-12" || result.URL != "https://linear.app/acme/issue/ENG-12" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if !strings.Contains(request.Query, "issueCreate") || request.Variables.Input["teamId"] != "team-1" || request.Variables.Input["assigneeId"] != "user-42" {
+		t.Errorf("unexpected request %+v", request)
+	}
+
+	reply = `{"errors":[{"message":"Entity not found: Team"}]}`
+	if _, err := client.CreateTask(context.Background(), sampleTask()); err == nil || !strings.Contains(err.Error(), "Entity not found") {
+		t.Errorf("expected the GraphQL error, got %v", err)
+	}
+}
+
+func TestWebhookClientSignsBody(t *testing.T) {
+	var payload webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("shh"))
+		mac.Write(body)
+		if r.Header.Get("X-Scriberr-Signature") != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+			http.Error(w, "bad signature", http.StatusForbidden)
+			return
+		}
+		json.Unmarshal(body, &payload)
+		w.Write([]byte(`{"id":"T-1"}`))
+	}))
+	defer server.Close()
+
+	client, err := New(ProviderWebhook, "shh", "", server.URL, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := client.CreateTask(context.Background(), sampleTask())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ID != "T-1" || payload.Type != "action_item.created" || payload.Task.RecordingID != "job-1" || *payload.Task.Timestamp != 754 {
+		t.Errorf("unexpected result %+v or payload %+v", result, payload)
+	}
+}
+
+func TestWebhookClientStaysOffPrivateNetworks(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		http.Error(w, "internal admin page", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, err := New(ProviderWebhook, "", "", server.URL, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateTask(context.Background(), sampleTask()); err == nil || !strings.Contains(err.Error(), "private networks") {
+		t.Errorf("expected the loopback address to be refused, got %v", err)
+	}
+	if called {
+		t.Error("the webhook was called")
+	}
+
+	// A failed response's body isn't relayed
+	allowed := &WebhookClient{URL: server.URL, AllowPrivate: true}
+	if _, err := allowed.CreateTask(context.Background(), sampleTask()); err == nil || !strings.Contains(err.Error(), "500") || strings.Contains(err.Error(), "admin page") {
+		t.Errorf("expected only the status in the error, got %v", err)
+	}
+}
+
+func TestNewValidatesSettings(t *testing.T) {
+	for _, c := range []struct{ provider, token, target, url string }{
+		{ProviderTodoist, "", "", ""},
+		{ProviderLinear, "key", "", ""},
+		{ProviderWebhook, "", "", ""},
+		{"jira", "key", "", ""},
+	} {
+		if _, err := New(c.provider, c.token, c.target, c.url, false); err == nil {
+			t.Errorf("New(%q) accepted incomplete settings %+v", c.provider, c)
+		}
+	}
+}
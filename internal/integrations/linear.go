@@ -0,0 +1,97 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// LinearAPIURL is the Linear GraphQL endpoint the client calls
+const LinearAPIURL = "https://api.linear.app/graphql"
+
+// issueCreateMutation creates a Linear issue
+const issueCreateMutation = `mutation IssueCreate($input: IssueCreateInput!) {
+  issueCreate(input: $input) {
+    success
+    issue { id identifier url }
+  }
+}`
+
+// LinearClient creates issues through the Linear GraphQL API. An assignee is a Linear user ID.
+type LinearClient struct {
+	APIKey string
+	TeamID string
+	APIURL string // Defaults to LinearAPIURL
+}
+
+// CreateTask creates a Linear issue in the team
+func (c *LinearClient) CreateTask(ctx context.Context, task Task) (Result, error) {
+	input := map[string]string{
+		"teamId":      c.TeamID,
+		"title":       task.Title,
+		"description": task.Description(),
+	}
+	if task.Assignee != "" {
+		input["assigneeId"] = task.Assignee
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"query":     issueCreateMutation,
+		"variables": map[string]interface{}{"input": input},
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to encode Linear issue: %w", err)
+	}
+
+	apiURL := c.APIURL
+	if apiURL == "" {
+		apiURL = LinearAPIURL
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewReader(data))
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", c.APIKey)
+
+	body, err := do(httpClient, req)
+	if err != nil {
+		return Result{}, fmt.Errorf("linear: %w", err)
+	}
+	var reply struct {
+		Data struct {
+			IssueCreate struct {
+				Success bool `json:"success"`
+				Issue   struct {
+					ID         string `json:"id"`
+					Identifier string `json:"identifier"`
+					URL        string `json:"url"`
+				} `json:"issue"`
+			} `json:"issueCreate"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &reply); err != nil {
+		return Result{}, fmt.Errorf("linear: failed to parse response: %w", err)
+	}
+	if len(reply.Errors) > 0 {
+		messages := make([]string, len(reply.Errors))
+		for i, e := range reply.Errors {
+			messages[i] = e.Message
+		}
+		return Result{}, fmt.Errorf("linear: %s", strings.Join(messages, "; "))
+	}
+	if !reply.Data.IssueCreate.Success {
+		return Result{}, fmt.Errorf("linear: issue was not created")
+	}
+	issue := reply.Data.IssueCreate.Issue
+	id := issue.Identifier
+	if id == "" {
+		id = issue.ID
+	}
+	return Result{ID: id, URL: issue.URL}, nil
+}
@@ -0,0 +1,65 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// TodoistAPIURL is the Todoist API the client calls
+const TodoistAPIURL = "https://api.todoist.com/api/v1"
+
+// TodoistClient creates tasks through the Todoist API. An assignee is a Todoist user ID and
+// only takes effect in shared projects.
+type TodoistClient struct {
+	Token     string
+	ProjectID string // Empty for the Inbox
+	BaseURL   string // Defaults to TodoistAPIURL
+}
+
+// CreateTask creates a Todoist task
+func (c *TodoistClient) CreateTask(ctx context.Context, task Task) (Result, error) {
+	payload := map[string]string{
+		"content":     task.Title,
+		"description": task.Description(),
+	}
+	if c.ProjectID != "" {
+		payload["project_id"] = c.ProjectID
+	}
+	if task.Assignee != "" {
+		payload["assignee_id"] = task.Assignee
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to encode Todoist task: %w", err)
+	}
+
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = TodoistAPIURL
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/tasks", bytes.NewReader(data))
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+
+	body, err := do(httpClient, req)
+	if err != nil {
+		return Result{}, fmt.Errorf("todoist: %w", err)
+	}
+	var created struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil {
+		return Result{}, fmt.Errorf("todoist: failed to parse response: %w", err)
+	}
+	if created.URL == "" && created.ID != "" {
+		created.URL = "https://app.todoist.com/app/task/" + created.ID
+	}
+	return Result{ID: created.ID, URL: created.URL}, nil
+}
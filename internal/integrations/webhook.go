@@ -0,0 +1,61 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookClient posts each task as JSON. With a Secret, the body's HMAC-SHA256 is sent in the
+// X-Scriberr-Signature header as "sha256=<hex>". A response body with "id" and "url" fields
+// is taken as the created task. Webhooks on loopback, private and link-local networks are
+// refused unless AllowPrivate is set.
+type WebhookClient struct {
+	URL          string
+	Secret       string
+	AllowPrivate bool
+}
+
+// webhookPayload is the body a webhook receives
+type webhookPayload struct {
+	Type      string    `json:"type"`
+	Task      Task      `json:"task"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// CreateTask posts the task to the webhook
+func (c *WebhookClient) CreateTask(ctx context.Context, task Task) (Result, error) {
+	data, err := json.Marshal(webhookPayload{Type: "action_item.created", Task: task, Timestamp: time.Now()})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to encode task: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.URL, bytes.NewReader(data))
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(c.Secret))
+		mac.Write(data)
+		req.Header.Set("X-Scriberr-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	client := publicClient
+	if c.AllowPrivate {
+		client = httpClient
+	}
+	body, err := do(client, req)
+	if err != nil {
+		return Result{}, fmt.Errorf("webhook: %w", err)
+	}
+	var result Result
+	// Receivers don't have to answer with JSON
+	_ = json.Unmarshal(body, &result)
+	return result, nil
+}
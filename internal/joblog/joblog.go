@@ -0,0 +1,223 @@
+// Package joblog keeps the lines logged while processing each transcription job, so a job's
+// processing log can be read back through the API long after its console output is gone.
+package joblog
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+)
+
+const (
+	// queueSize is how many lines wait to be written before new ones are dropped
+	queueSize = 4096
+	// batchSize is the most lines written at once
+	batchSize = 256
+	// flushInterval is the longest a line waits to be written
+	flushInterval = time.Second
+)
+
+// levels orders the log levels, for filtering by the least severe one wanted
+var levels = map[string]int{"DEBUG": 0, "INFO": 1, "WARN": 2, "ERROR": 3}
+
+// Store writes the lines logged about jobs to the database in batches, off the logging
+// goroutine. Lines are dropped rather than slowing processing down when writing can't keep up.
+type Store struct {
+	maxPerJob int
+
+	queue    chan models.JobLogEntry
+	flush    chan chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+
+	mu      sync.Mutex
+	dropped int
+}
+
+// NewStore creates a store keeping the newest maxPerJob lines of each job (0 keeps them all)
+func NewStore(maxPerJob int) *Store {
+	return &Store{
+		maxPerJob: maxPerJob,
+		queue:     make(chan models.JobLogEntry, queueSize),
+		flush:     make(chan chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start starts keeping the lines logged about jobs
+func (s *Store) Start() {
+	s.wg.Add(1)
+	go s.run()
+	logger.SetJobSink(s.add)
+}
+
+// Stop writes the lines still queued and stops keeping them
+func (s *Store) Stop() {
+	logger.SetJobSink(nil)
+	s.stopOnce.Do(func() { close(s.done) })
+	s.wg.Wait()
+}
+
+// add queues a line for writing, dropping it if the queue is full
+func (s *Store) add(record logger.JobRecord) {
+	entry := models.JobLogEntry{
+		JobID:     record.JobID,
+		Time:      record.Time,
+		Level:     record.Level,
+		Message:   record.Message,
+		Component: record.Component,
+		RequestID: record.RequestID,
+	}
+	if len(record.Attrs) > 0 {
+		entry.Attrs = record.Attrs
+	}
+	select {
+	case s.queue <- entry:
+	default:
+		s.mu.Lock()
+		s.dropped++
+		s.mu.Unlock()
+	}
+}
+
+// Flush writes every line queued so far, so a job's log can be read up to date
+func (s *Store) Flush(ctx context.Context) error {
+	ack := make(chan struct{})
+	select {
+	case s.flush <- ack:
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run writes lines whenever a batch fills up or flushInterval passes
+func (s *Store) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	var batch []models.JobLogEntry
+	write := func() {
+		if len(batch) > 0 {
+			s.write(batch)
+			batch = nil
+		}
+	}
+	drain := func() {
+		for {
+			select {
+			case entry := <-s.queue:
+				if batch = append(batch, entry); len(batch) >= batchSize {
+					write()
+				}
+			default:
+				write()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case entry := <-s.queue:
+			if batch = append(batch, entry); len(batch) >= batchSize {
+				write()
+			}
+		case <-ticker.C:
+			write()
+		case ack := <-s.flush:
+			drain()
+			close(ack)
+		case <-s.done:
+			drain()
+			return
+		}
+	}
+}
+
+// write saves a batch of lines and trims the logs of the jobs they're about. Failures are
+// logged without a job, so they aren't written back here.
+func (s *Store) write(batch []models.JobLogEntry) {
+	s.mu.Lock()
+	dropped := s.dropped
+	s.dropped = 0
+	s.mu.Unlock()
+	if dropped > 0 {
+		logger.Warn("Dropped job log lines while the write queue was full", "component", "joblog", "dropped", dropped)
+	}
+
+	if err := database.DB.CreateInBatches(batch, batchSize).Error; err != nil {
+		logger.Error("Failed to write job log lines", "component", "joblog", "lines", len(batch), "error", err)
+		return
+	}
+	if s.maxPerJob <= 0 {
+		return
+	}
+	trimmed := map[string]bool{}
+	for _, entry := range batch {
+		if trimmed[entry.JobID] {
+			continue
+		}
+		trimmed[entry.JobID] = true
+		newest := database.DB.Model(&models.JobLogEntry{}).Select("id").Where("job_id = ?", entry.JobID).Order("id DESC").Limit(s.maxPerJob)
+		if err := database.DB.Where("job_id = ? AND id NOT IN (?)", entry.JobID, newest).Delete(&models.JobLogEntry{}).Error; err != nil {
+			logger.Error("Failed to trim a job log", "component", "joblog", "log_job_id", entry.JobID, "error", err)
+		}
+	}
+}
+
+// Query filters a job's log
+type Query struct {
+	// Level is the least severe level returned, e.g. WARN for warnings and errors
+	Level     string
+	Component string
+	// AfterID returns only the lines after this one, to follow a log as it grows
+	AfterID uint
+	Limit   int
+}
+
+// ValidLevel reports whether level names a log level, in any case
+func ValidLevel(level string) bool {
+	_, ok := levels[strings.ToUpper(level)]
+	return ok
+}
+
+// List returns the lines of a job's log matching q, oldest first
+func List(jobID string, q Query) ([]models.JobLogEntry, error) {
+	db := database.DB.Where("job_id = ?", jobID)
+	if min, ok := levels[strings.ToUpper(q.Level)]; ok && min > 0 {
+		var wanted []string
+		for level, rank := range levels {
+			if rank >= min {
+				wanted = append(wanted, level)
+			}
+		}
+		db = db.Where("level IN ?", wanted)
+	}
+	if q.Component != "" {
+		db = db.Where("component = ?", q.Component)
+	}
+	if q.AfterID > 0 {
+		db = db.Where("id > ?", q.AfterID)
+	}
+	if q.Limit > 0 {
+		db = db.Limit(q.Limit)
+	}
+	entries := []models.JobLogEntry{}
+	err := db.Order("id ASC").Find(&entries).Error
+	return entries, err
+}
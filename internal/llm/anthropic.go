@@ -0,0 +1,334 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"scriberr/pkg/logger"
+)
+
+const (
+	// DefaultAnthropicBaseURL is the Anthropic API endpoint used when no base URL is configured
+	DefaultAnthropicBaseURL = "https://api.anthropic.com"
+	anthropicVersion        = "2023-06-01"
+	// anthropicMaxTokens caps the reply length; the Messages API requires an explicit limit
+	anthropicMaxTokens = 4096
+)
+
+// AnthropicService handles Anthropic Messages API interactions
+type AnthropicService struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+// NewAnthropicService creates a new Anthropic service. An empty baseURL uses the public API.
+func NewAnthropicService(apiKey, baseURL string) *AnthropicService {
+	if baseURL == "" {
+		baseURL = DefaultAnthropicBaseURL
+	}
+	return &AnthropicService{
+		apiKey:  apiKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 300 * time.Second},
+	}
+}
+
+// Anthropic Messages API payloads
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature *float64           `json:"temperature,omitempty"`
+	Stream      bool               `json:"stream"`
+	Tools       []anthropicTool    `json:"tools,omitempty"`
+	ToolChoice  *anthropicToolUse  `json:"tool_choice,omitempty"`
+}
+
+// anthropicTool is a tool the model can call; structured completions force a call to one
+// tool whose input schema is the requested JSON schema
+type anthropicTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+type anthropicToolUse struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type anthropicResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Role    string `json:"role"`
+	Content []struct {
+		Type  string          `json:"type"`
+		Text  string          `json:"text"`
+		Input json.RawMessage `json:"input"` // Of tool_use blocks
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// GetModels retrieves available models from Anthropic
+func (s *AnthropicService) GetModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.baseURL+"/v1/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	s.setHeaders(req)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var modelsResp ModelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&modelsResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	out := make([]string, 0, len(modelsResp.Data))
+	for _, m := range modelsResp.Data {
+		out = append(out, m.ID)
+	}
+	return out, nil
+}
+
+// ChatCompletion performs a non-streaming chat completion against Anthropic
+func (s *AnthropicService) ChatCompletion(ctx context.Context, model string, messages []ChatMessage, temperature float64) (*ChatResponse, error) {
+	return s.chatCompletion(ctx, model, messages, temperature, nil)
+}
+
+// ChatCompletionJSON performs a chat completion whose reply is constrained to schema, by
+// forcing a call to a tool taking the schema as its input. The reply is the tool input.
+func (s *AnthropicService) ChatCompletionJSON(ctx context.Context, model string, messages []ChatMessage, temperature float64, schema Schema) (*ChatResponse, error) {
+	return s.chatCompletion(ctx, model, messages, temperature, &schema)
+}
+
+// chatCompletion performs a non-streaming chat completion, structured if schema is set
+func (s *AnthropicService) chatCompletion(ctx context.Context, model string, messages []ChatMessage, temperature float64, schema *Schema) (*ChatResponse, error) {
+	req, err := s.newMessagesRequest(ctx, model, messages, temperature, false, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.DebugContext(ctx, "Chat completion request", "component", "anthropic", "model", model, "messages", len(messages))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		logger.WarnContext(ctx, "Chat completion failed", "component", "anthropic", "model", model, "status", resp.StatusCode, "body", truncate(string(body), 500))
+		return nil, fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var aResp anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&aResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	var text strings.Builder
+	for _, block := range aResp.Content {
+		// A structured reply is the input of the forced tool call; any text around it is dropped
+		if schema != nil && block.Type == "tool_use" {
+			text.Reset()
+			text.Write(block.Input)
+			break
+		}
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+
+	// Map to generic ChatResponse
+	cr := &ChatResponse{ID: aResp.ID, Model: aResp.Model}
+	cr.Choices = []struct {
+		Index   int `json:"index"`
+		Message struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	}{{
+		Index:        0,
+		FinishReason: aResp.StopReason,
+	}}
+	cr.Choices[0].Message.Role = "assistant"
+	cr.Choices[0].Message.Content = text.String()
+	cr.Usage.PromptTokens = aResp.Usage.InputTokens
+	cr.Usage.CompletionTokens = aResp.Usage.OutputTokens
+	cr.Usage.TotalTokens = aResp.Usage.InputTokens + aResp.Usage.OutputTokens
+	return cr, nil
+}
+
+// ChatCompletionStream performs a streaming chat completion against Anthropic
+func (s *AnthropicService) ChatCompletionStream(ctx context.Context, model string, messages []ChatMessage, temperature float64) (<-chan string, <-chan error) {
+	contentChan := make(chan string, 100)
+	errorChan := make(chan error, 1)
+
+	go func() {
+		defer close(contentChan)
+		defer close(errorChan)
+
+		req, err := s.newMessagesRequest(ctx, model, messages, temperature, true, nil)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		req.Header.Set("Accept", "text/event-stream")
+
+		logger.DebugContext(ctx, "Chat stream request", "component", "anthropic", "model", model, "messages", len(messages))
+		resp, err := s.client.Do(req)
+		if err != nil {
+			errorChan <- fmt.Errorf("failed to make request: %w", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			logger.WarnContext(ctx, "Chat stream failed", "component", "anthropic", "model", model, "status", resp.StatusCode, "body", truncate(string(body), 500))
+			errorChan <- fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
+			return
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			// Event names are repeated in the data payload, so only data lines matter
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+			var event anthropicStreamEvent
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+				continue
+			}
+
+			switch event.Type {
+			case "content_block_delta":
+				if event.Delta.Type != "text_delta" || event.Delta.Text == "" {
+					continue
+				}
+				select {
+				case contentChan <- event.Delta.Text:
+				case <-ctx.Done():
+					return
+				}
+			case "message_stop":
+				logger.DebugContext(ctx, "Chat stream done", "component", "anthropic", "model", model)
+				return
+			case "error":
+				errorChan <- fmt.Errorf("stream error: %s - %s", event.Error.Type, event.Error.Message)
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			errorChan <- fmt.Errorf("error reading stream: %w", err)
+		}
+	}()
+
+	return contentChan, errorChan
+}
+
+// newMessagesRequest builds a Messages API request. System messages are moved to the
+// system prompt and consecutive messages from the same role are merged, since the API
+// only accepts alternating user and assistant turns. A schema forces a call to a tool
+// taking it as input.
+func (s *AnthropicService) newMessagesRequest(ctx context.Context, model string, messages []ChatMessage, temperature float64, stream bool, schema *Schema) (*http.Request, error) {
+	var system []string
+	var msgs []anthropicMessage
+	for _, m := range messages {
+		if m.Role == "system" {
+			system = append(system, m.Content)
+			continue
+		}
+		role := "user"
+		if m.Role == "assistant" {
+			role = "assistant"
+		}
+		if n := len(msgs); n > 0 && msgs[n-1].Role == role {
+			msgs[n-1].Content += "\n\n" + m.Content
+			continue
+		}
+		msgs = append(msgs, anthropicMessage{Role: role, Content: m.Content})
+	}
+	if len(msgs) == 0 {
+		return nil, fmt.Errorf("no user message to send")
+	}
+
+	reqBody := anthropicRequest{
+		Model:     model,
+		System:    strings.Join(system, "\n\n"),
+		Messages:  msgs,
+		MaxTokens: anthropicMaxTokens,
+		Stream:    stream,
+	}
+	// Only set temperature if caller provided one; Anthropic accepts at most 1
+	if temperature > 0 {
+		t := temperature
+		if t > 1 {
+			t = 1
+		}
+		reqBody.Temperature = &t
+	}
+	if schema != nil {
+		reqBody.Tools = []anthropicTool{{Name: schema.Name, Description: schema.Description, InputSchema: schema.Definition}}
+		reqBody.ToolChoice = &anthropicToolUse{Type: "tool", Name: schema.Name}
+	}
+
+	data, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.baseURL+"/v1/messages", bytes.NewBuffer(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	s.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+func (s *AnthropicService) setHeaders(req *http.Request) {
+	req.Header.Set("x-api-key", s.apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+}
@@ -0,0 +1,81 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAnthropicChatCompletion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("x-api-key"); got != "secret" {
+			t.Errorf("expected API key header, got %q", got)
+		}
+		var req anthropicRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.System != "Be brief." {
+			t.Errorf("expected system message moved to the system prompt, got %q", req.System)
+		}
+		if len(req.Messages) != 1 || req.Messages[0].Role != "user" || req.Messages[0].Content != "Context\n\nQuestion" {
+			t.Errorf("expected consecutive user messages merged, got %+v", req.Messages)
+		}
+		if req.MaxTokens == 0 || req.Temperature == nil || *req.Temperature != 1 {
+			t.Errorf("expected max_tokens set and temperature capped at 1, got %d and %v", req.MaxTokens, req.Temperature)
+		}
+		fmt.Fprint(w, `{"id":"msg_1","model":"claude-test","content":[{"type":"text","text":"Hello"},{"type":"text","text":" there"}],"stop_reason":"end_turn","usage":{"input_tokens":12,"output_tokens":3}}`)
+	}))
+	defer server.Close()
+
+	service := NewAnthropicService("secret", server.URL)
+	messages := []ChatMessage{
+		{Role: "system", Content: "Be brief."},
+		{Role: "user", Content: "Context"},
+		{Role: "user", Content: "Question"},
+	}
+	resp, err := service.ChatCompletion(context.Background(), "claude-test", messages, 1.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Choices) != 1 || resp.Choices[0].Message.Content != "Hello there" {
+		t.Errorf("expected text blocks joined into one choice, got %+v", resp.Choices)
+	}
+	if resp.Usage.TotalTokens != 15 {
+		t.Errorf("expected usage mapped, got %+v", resp.Usage)
+	}
+}
+
+func TestAnthropicChatCompletionStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		events := []string{
+			`{"type":"message_start"}`,
+			`{"type":"content_block_delta","delta":{"type":"text_delta","text":"Hel"}}`,
+			`{"type":"content_block_delta","delta":{"type":"text_delta","text":"lo"}}`,
+			`{"type":"message_stop"}`,
+		}
+		for _, event := range events {
+			fmt.Fprintf(w, "event: x\ndata: %s\n\n", event)
+		}
+	}))
+	defer server.Close()
+
+	contentChan, errorChan := NewAnthropicService("secret", server.URL).ChatCompletionStream(context.Background(), "claude-test", []ChatMessage{{Role: "user", Content: "Hi"}}, 0)
+	var text strings.Builder
+	for chunk := range contentChan {
+		text.WriteString(chunk)
+	}
+	if err := <-errorChan; err != nil {
+		t.Fatal(err)
+	}
+	if text.String() != "Hello" {
+		t.Errorf("expected streamed text Hello, got %q", text.String())
+	}
+}
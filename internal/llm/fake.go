@@ -0,0 +1,158 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FakeModel is the only model the fake provider offers
+const FakeModel = "fake-model"
+
+// fakeExcerptWords caps how much of the prompt a fake reply repeats
+const fakeExcerptWords = 50
+
+// numberedLine matches the "[n] text" lines of batched prompts such as translations
+var numberedLine = regexp.MustCompile(`(?m)^\[\d+\] .*$`)
+
+// FakeService answers deterministically without a model, for tests and local development.
+// A prompt made of numbered "[n] text" lines is answered with the same lines, so batched
+// translations keep their shape; anything else gets an excerpt of the prompt's last
+// paragraph. Structured completions are filled in from the schema.
+type FakeService struct{}
+
+// NewFakeService creates a fake LLM service
+func NewFakeService() *FakeService {
+	return &FakeService{}
+}
+
+// GetModels returns the fake model
+func (s *FakeService) GetModels(ctx context.Context) ([]string, error) {
+	return []string{FakeModel}, nil
+}
+
+// ChatCompletion replies with a deterministic answer derived from the last message
+func (s *FakeService) ChatCompletion(ctx context.Context, model string, messages []ChatMessage, temperature float64) (*ChatResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return fakeResponse(model, messages, fakeReply(model, messages)), nil
+}
+
+// ChatCompletionJSON replies with a JSON object matching schema
+func (s *FakeService) ChatCompletionJSON(ctx context.Context, model string, messages []ChatMessage, temperature float64, schema Schema) (*ChatResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var definition map[string]interface{}
+	if err := json.Unmarshal(schema.Definition, &definition); err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", schema.Name, err)
+	}
+	reply, err := json.Marshal(fakeValue(schema.Name, definition))
+	if err != nil {
+		return nil, err
+	}
+	return fakeResponse(model, messages, string(reply)), nil
+}
+
+// ChatCompletionStream streams the ChatCompletion reply word by word
+func (s *FakeService) ChatCompletionStream(ctx context.Context, model string, messages []ChatMessage, temperature float64) (<-chan string, <-chan error) {
+	contentChan := make(chan string)
+	errorChan := make(chan error, 1)
+	go func() {
+		defer close(contentChan)
+		defer close(errorChan)
+		for i, word := range strings.Fields(fakeReply(model, messages)) {
+			if i > 0 {
+				word = " " + word
+			}
+			select {
+			case contentChan <- word:
+			case <-ctx.Done():
+				errorChan <- ctx.Err()
+				return
+			}
+		}
+	}()
+	return contentChan, errorChan
+}
+
+// fakeReply builds the text reply to a conversation
+func fakeReply(model string, messages []ChatMessage) string {
+	if len(messages) == 0 {
+		return fmt.Sprintf("[%s] Hello.", model)
+	}
+	prompt := messages[len(messages)-1].Content
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			prompt = messages[i].Content
+			break
+		}
+	}
+
+	if lines := numberedLine.FindAllString(prompt, -1); len(lines) > 0 {
+		return strings.Join(lines, "\n")
+	}
+
+	paragraphs := strings.Split(strings.TrimSpace(prompt), "\n\n")
+	words := strings.Fields(paragraphs[len(paragraphs)-1])
+	if len(words) > fakeExcerptWords {
+		words = append(words[:fakeExcerptWords], "...")
+	}
+	return fmt.Sprintf("[%s] %s", model, strings.Join(words, " "))
+}
+
+// fakeResponse wraps a reply in a ChatResponse with estimated token usage
+func fakeResponse(model string, messages []ChatMessage, reply string) *ChatResponse {
+	resp := &ChatResponse{Object: "chat.completion", Model: model}
+	resp.Choices = make([]struct {
+		Index   int `json:"index"`
+		Message struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	}, 1)
+	resp.Choices[0].Message.Role = "assistant"
+	resp.Choices[0].Message.Content = reply
+	resp.Choices[0].FinishReason = "stop"
+
+	for _, message := range messages {
+		resp.Usage.PromptTokens += EstimateTokens(message.Content)
+	}
+	resp.Usage.CompletionTokens = EstimateTokens(reply)
+	resp.Usage.TotalTokens = resp.Usage.PromptTokens + resp.Usage.CompletionTokens
+	return resp
+}
+
+// fakeValue builds a placeholder value for a JSON Schema: objects get every property,
+// arrays one item, strings the property name, and enums their first option
+func fakeValue(name string, schema map[string]interface{}) interface{} {
+	if options, ok := schema["enum"].([]interface{}); ok && len(options) > 0 {
+		return options[0]
+	}
+	switch schema["type"] {
+	case "object":
+		object := map[string]interface{}{}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for property, definition := range properties {
+			if definition, ok := definition.(map[string]interface{}); ok {
+				object[property] = fakeValue(property, definition)
+			}
+		}
+		return object
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		return []interface{}{fakeValue(name, items)}
+	case "number", "integer":
+		return 0
+	case "boolean":
+		return false
+	case "null":
+		return nil
+	default:
+		return "Fake " + strings.ReplaceAll(name, "_", " ")
+	}
+}
@@ -0,0 +1,270 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"scriberr/internal/tracing"
+	"scriberr/pkg/logger"
+)
+
+// ChainTarget is one provider and model in a fallback chain
+type ChainTarget struct {
+	Binding
+	Service Service
+
+	fallback bool // set by order for every target but the primary
+}
+
+// Call describes one attempt against a provider
+type Call struct {
+	Binding
+	Feature          string
+	Fallback         bool // The provider isn't the feature's primary
+	Stream           bool
+	Latency          time.Duration
+	Err              error
+	PromptTokens     int
+	CompletionTokens int
+	TokensEstimated  bool // The provider didn't report usage, so the counts are estimated from the text
+}
+
+// CallObserver is told about every provider attempt a chain makes
+type CallObserver interface {
+	ObserveCall(call Call)
+}
+
+// Chain is a Service that tries an ordered list of providers until one succeeds. Providers
+// that are currently unhealthy are tried after the healthy ones rather than skipped, so a
+// call only fails when every provider does.
+type Chain struct {
+	targets []ChainTarget
+	health  *Health
+	// attemptTimeout bounds each provider attempt; 0 leaves it to the caller's context
+	attemptTimeout time.Duration
+
+	feature  string
+	observer CallObserver
+}
+
+// NewChain creates a fallback chain over targets, primary first
+func NewChain(targets []ChainTarget, health *Health, attemptTimeout time.Duration) *Chain {
+	if health == nil {
+		health = NewHealth(DefaultFailureThreshold, DefaultCooldown)
+	}
+	return &Chain{targets: targets, health: health, attemptTimeout: attemptTimeout}
+}
+
+// GetModels lists the models of the primary provider
+func (c *Chain) GetModels(ctx context.Context) ([]string, error) {
+	if len(c.targets) == 0 {
+		return nil, fmt.Errorf("no LLM providers configured")
+	}
+	return c.targets[0].Service.GetModels(ctx)
+}
+
+// ChatCompletion runs the completion against each provider in turn. A non-empty model
+// replaces the primary's configured model; fallbacks always use their own.
+func (c *Chain) ChatCompletion(ctx context.Context, model string, messages []ChatMessage, temperature float64) (*ChatResponse, error) {
+	return c.complete(ctx, model, messages, temperature, nil)
+}
+
+// ChatCompletionJSON runs a structured completion against each provider in turn. Providers
+// without JSON support are given the schema in the prompt instead.
+func (c *Chain) ChatCompletionJSON(ctx context.Context, model string, messages []ChatMessage, temperature float64, schema Schema) (*ChatResponse, error) {
+	return c.complete(ctx, model, messages, temperature, &schema)
+}
+
+// complete runs a completion, structured if schema is set, against each provider in turn
+func (c *Chain) complete(ctx context.Context, model string, messages []ChatMessage, temperature float64, schema *Schema) (*ChatResponse, error) {
+	var errs []error
+	for _, target := range c.order(model) {
+		attemptCtx, cancel := c.attemptContext(ctx)
+		attemptCtx, span := c.startSpan(attemptCtx, target, false)
+		started := time.Now()
+		var resp *ChatResponse
+		var err error
+		if schema != nil {
+			resp, err = completeJSON(attemptCtx, target.Service, target.Model, messages, temperature, *schema)
+		} else {
+			resp, err = target.Service.ChatCompletion(attemptCtx, target.Model, messages, temperature)
+		}
+		cancel()
+		call := Call{Binding: target.Binding, Latency: time.Since(started), Err: err}
+		output := 0
+		if resp != nil {
+			call.PromptTokens = resp.Usage.PromptTokens
+			call.CompletionTokens = resp.Usage.CompletionTokens
+			for _, choice := range resp.Choices {
+				output += len(choice.Message.Content)
+			}
+		}
+		c.observe(target, call, messages, output)
+		span.SetAttributes("gen_ai.usage.input_tokens", call.PromptTokens, "gen_ai.usage.output_tokens", call.CompletionTokens)
+		span.RecordError(err)
+		span.End()
+		if err == nil {
+			c.health.RecordSuccess(target.Provider)
+			return resp, nil
+		}
+		if done := c.fail(ctx, target, err); done {
+			return nil, err
+		}
+		errs = append(errs, fmt.Errorf("%s/%s: %w", target.Provider, target.Model, err))
+	}
+	return nil, c.exhausted(errs)
+}
+
+// ChatCompletionStream streams from the first provider that produces output. Once content
+// has been sent, a later failure is reported rather than retried, since the caller has
+// already seen part of the answer.
+func (c *Chain) ChatCompletionStream(ctx context.Context, model string, messages []ChatMessage, temperature float64) (<-chan string, <-chan error) {
+	contentChan := make(chan string, 100)
+	errorChan := make(chan error, 1)
+
+	go func() {
+		defer close(contentChan)
+		defer close(errorChan)
+
+		var errs []error
+		for _, target := range c.order(model) {
+			attemptCtx, cancel := c.attemptContext(ctx)
+			streamCtx, span := c.startSpan(ctx, target, true)
+			begin := time.Now()
+			output, err := c.stream(attemptCtx, streamCtx, target, messages, temperature, contentChan)
+			cancel()
+			span.SetAttribute("llm.output_bytes", output)
+			span.RecordError(err)
+			span.End()
+			c.observe(target, Call{Binding: target.Binding, Stream: true, Latency: time.Since(begin), Err: err}, messages, output)
+			started := output > 0
+			if err == nil {
+				c.health.RecordSuccess(target.Provider)
+				return
+			}
+			if done := c.fail(ctx, target, err); done || started {
+				errorChan <- err
+				return
+			}
+			errs = append(errs, fmt.Errorf("%s/%s: %w", target.Provider, target.Model, err))
+		}
+		errorChan <- c.exhausted(errs)
+	}()
+
+	return contentChan, errorChan
+}
+
+// stream forwards one provider's stream, returning how many bytes of content were
+// forwarded. The attempt timeout only applies until the first chunk arrives.
+func (c *Chain) stream(attemptCtx, ctx context.Context, target ChainTarget, messages []ChatMessage, temperature float64, out chan<- string) (int, error) {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	chunks, errs := target.Service.ChatCompletionStream(streamCtx, target.Model, messages, temperature)
+
+	forwarded := 0
+	for {
+		var deadline <-chan struct{}
+		if forwarded == 0 {
+			deadline = attemptCtx.Done()
+		}
+		select {
+		case chunk, ok := <-chunks:
+			if !ok {
+				err := <-errs
+				if err == nil && ctx.Err() != nil {
+					err = ctx.Err()
+				}
+				return forwarded, err
+			}
+			select {
+			case out <- chunk:
+				forwarded += len(chunk)
+			case <-ctx.Done():
+				return forwarded, ctx.Err()
+			}
+		case <-deadline:
+			return 0, fmt.Errorf("no response within %s", c.attemptTimeout)
+		}
+	}
+}
+
+// startSpan traces an attempt against a provider
+func (c *Chain) startSpan(ctx context.Context, target ChainTarget, stream bool) (context.Context, *tracing.Span) {
+	return tracing.StartKind(ctx, tracing.KindClient, "llm.chat",
+		"llm.feature", c.feature, "gen_ai.system", target.Provider, "gen_ai.request.model", target.Model,
+		"llm.fallback", target.fallback, "llm.stream", stream)
+}
+
+// observe reports an attempt to the observer, if any, estimating token counts from the
+// prompt and output length when the provider didn't report them. Attempts abandoned
+// without output because the caller gave up say nothing about the provider and aren't reported.
+func (c *Chain) observe(target ChainTarget, call Call, messages []ChatMessage, output int) {
+	if c.observer == nil || (output == 0 && call.Err != nil && errors.Is(call.Err, context.Canceled)) {
+		return
+	}
+	call.Feature = c.feature
+	call.Fallback = target.fallback
+	if call.PromptTokens == 0 && call.CompletionTokens == 0 && (call.Err == nil || output > 0) {
+		for _, m := range messages {
+			call.PromptTokens += EstimateTokens(m.Content)
+		}
+		call.CompletionTokens = (output + bytesPerToken - 1) / bytesPerToken
+		call.TokensEstimated = true
+	}
+	c.observer.ObserveCall(call)
+}
+
+// order returns the targets to try, healthy providers first, with the primary's model
+// replaced by model if set
+func (c *Chain) order(model string) []ChainTarget {
+	healthy := make([]ChainTarget, 0, len(c.targets))
+	var unhealthy []ChainTarget
+	for i, target := range c.targets {
+		if i == 0 && model != "" {
+			target.Model = model
+		}
+		target.fallback = i > 0
+		if c.health.Available(target.Provider) {
+			healthy = append(healthy, target)
+		} else {
+			unhealthy = append(unhealthy, target)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// attemptContext derives the context for one provider attempt
+func (c *Chain) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.attemptTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.attemptTimeout)
+}
+
+// fail records a failed attempt and reports whether the chain should stop, which it does
+// when the caller gave up rather than the provider failing
+func (c *Chain) fail(ctx context.Context, target ChainTarget, err error) bool {
+	if ctx.Err() != nil {
+		return true
+	}
+	c.health.RecordFailure(target.Provider, err)
+	if len(c.targets) > 1 {
+		logger.WarnContext(ctx, "Provider failed, trying the next one", "component", "llm", "provider", target.Provider, "model", target.Model, "error", err)
+	}
+	return false
+}
+
+// exhausted builds the error returned when every provider failed
+func (c *Chain) exhausted(errs []error) error {
+	if len(errs) == 1 {
+		return errors.Unwrap(errs[0])
+	}
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return fmt.Errorf("all LLM providers failed: %s", strings.Join(messages, "; "))
+}
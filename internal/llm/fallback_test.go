@@ -0,0 +1,228 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeService answers with its name, or fails with err, recording the models it was asked for
+type fakeService struct {
+	name   string
+	err    error
+	delay  time.Duration
+	models []string
+}
+
+func (f *fakeService) GetModels(ctx context.Context) ([]string, error) { return []string{f.name}, nil }
+
+func (f *fakeService) ChatCompletion(ctx context.Context, model string, messages []ChatMessage, temperature float64) (*ChatResponse, error) {
+	f.models = append(f.models, model)
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := &ChatResponse{Model: model}
+	resp.Choices = make([]struct {
+		Index   int `json:"index"`
+		Message struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	}, 1)
+	resp.Choices[0].Message.Content = f.name
+	return resp, nil
+}
+
+func (f *fakeService) ChatCompletionStream(ctx context.Context, model string, messages []ChatMessage, temperature float64) (<-chan string, <-chan error) {
+	contentChan := make(chan string, 1)
+	errorChan := make(chan error, 1)
+	if f.err != nil {
+		errorChan <- f.err
+	} else {
+		contentChan <- f.name
+	}
+	close(contentChan)
+	close(errorChan)
+	return contentChan, errorChan
+}
+
+func answer(t *testing.T, chain *Chain, model string) string {
+	t.Helper()
+	resp, err := chain.ChatCompletion(context.Background(), model, nil, 0)
+	if err != nil {
+		t.Fatalf("expected a fallback to answer, got %v", err)
+	}
+	return resp.Choices[0].Message.Content
+}
+
+func TestChainFailsOver(t *testing.T) {
+	primary := &fakeService{name: "primary", err: errors.New("connection refused")}
+	fallback := &fakeService{name: "fallback"}
+	health := NewHealth(2, time.Hour)
+	chain := NewChain([]ChainTarget{
+		{Binding: Binding{Provider: "ollama", Model: "llama3.2"}, Service: primary},
+		{Binding: Binding{Provider: "openai", Model: "gpt-4o-mini"}, Service: fallback},
+	}, health, 0)
+
+	if got := answer(t, chain, "llama3.1"); got != "fallback" {
+		t.Errorf("expected the fallback to answer, got %s", got)
+	}
+	if primary.models[0] != "llama3.1" || fallback.models[0] != "gpt-4o-mini" {
+		t.Errorf("expected the requested model to replace only the primary's, got %v and %v", primary.models, fallback.models)
+	}
+
+	// A second failure reaches the threshold, after which the primary is tried last
+	answer(t, chain, "")
+	if health.Available("ollama") {
+		t.Error("expected ollama to be unhealthy after 2 consecutive failures")
+	}
+	answer(t, chain, "")
+	if len(primary.models) != 2 {
+		t.Errorf("expected an unhealthy primary to be skipped while a fallback works, got %d calls", len(primary.models))
+	}
+
+	states := health.Snapshot()
+	if len(states) != 2 || states[0].Provider != "ollama" || states[0].Healthy || states[0].LastError != "connection refused" {
+		t.Errorf("unexpected health snapshot %+v", states)
+	}
+	if states[1].Successes != 3 {
+		t.Errorf("expected 3 fallback successes, got %+v", states[1])
+	}
+
+	// Once the cooldown has passed the primary is tried first again, and recovers on success
+	health.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	primary.err = nil
+	if got := answer(t, chain, ""); got != "primary" {
+		t.Errorf("expected the primary to answer after its cooldown, got %s", got)
+	}
+	if !health.Available("ollama") {
+		t.Error("expected a success to make ollama healthy again")
+	}
+}
+
+func TestChainTimeoutAndExhaustion(t *testing.T) {
+	slow := &fakeService{name: "slow", delay: time.Second}
+	fast := &fakeService{name: "fast"}
+	chain := NewChain([]ChainTarget{
+		{Binding: Binding{Provider: "ollama", Model: "a"}, Service: slow},
+		{Binding: Binding{Provider: "anthropic", Model: "b"}, Service: fast},
+	}, nil, 20*time.Millisecond)
+	if got := answer(t, chain, ""); got != "fast" {
+		t.Errorf("expected a timed-out primary to fail over, got %s", got)
+	}
+
+	broken := NewChain([]ChainTarget{
+		{Binding: Binding{Provider: "ollama", Model: "a"}, Service: &fakeService{err: errors.New("down")}},
+		{Binding: Binding{Provider: "openai", Model: "b"}, Service: &fakeService{err: errors.New("quota")}},
+	}, nil, 0)
+	if _, err := broken.ChatCompletion(context.Background(), "", nil, 0); err == nil {
+		t.Error("expected an error when every provider fails")
+	}
+
+	content, errs := broken.ChatCompletionStream(context.Background(), "", nil, 0)
+	for range content {
+	}
+	if err := <-errs; err == nil {
+		t.Error("expected a stream error when every provider fails")
+	}
+}
+
+func TestParseChain(t *testing.T) {
+	chain, err := ParseChain(" OpenAI:gpt-4o-mini , ollama:llama3.2:3b,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 2 || chain[0] != (Binding{Provider: "openai", Model: "gpt-4o-mini"}) || chain[1].Model != "llama3.2:3b" {
+		t.Errorf("unexpected chain %+v", chain)
+	}
+	if _, err := ParseChain("openai"); err == nil {
+		t.Error("expected an entry without a model to be rejected")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	if _, err := NewProvider(ProviderAnthropic, ProviderConfig{}); err == nil {
+		t.Error("expected Anthropic without an API key to be rejected")
+	}
+	if _, err := NewProvider(ProviderOpenAI, ProviderConfig{BaseURL: "http://localhost:8000/v1"}); err != nil {
+		t.Errorf("expected an OpenAI-compatible server without a key to be accepted, got %v", err)
+	}
+
+	registry := NewRegistry()
+	ollama, err := NewProvider(ProviderOllama, ProviderConfig{BaseURL: "http://localhost:11434"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry.Register(ProviderOllama, ollama)
+
+	if err := registry.Bind(FeatureChat, ProviderAnthropic, "claude-test"); err == nil {
+		t.Error("expected binding to an unregistered provider to fail")
+	}
+	if err := registry.Bind(FeatureSummary, "Ollama", "llama3.2"); err != nil {
+		t.Fatal(err)
+	}
+	service, model, err := registry.For(FeatureSummary)
+	if err != nil || service == nil || model != "llama3.2" {
+		t.Errorf("expected summary bound to ollama/llama3.2, got %q %v", model, err)
+	}
+	if _, _, err := registry.For(FeatureChat); err == nil {
+		t.Error("expected an unbound feature to return an error")
+	}
+}
+
+type callLog []Call
+
+func (l *callLog) ObserveCall(call Call) { *l = append(*l, call) }
+
+func TestRegistryObservesCalls(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(ProviderOllama, &fakeService{name: "ollama", err: errors.New("busy")})
+	registry.Register(ProviderOpenAI, &fakeService{name: "openai"})
+	if err := registry.BindChain(FeatureChat, []Binding{{ProviderOllama, "llama3.2"}, {ProviderOpenAI, "gpt-4o-mini"}}); err != nil {
+		t.Fatal(err)
+	}
+	calls := &callLog{}
+	registry.SetObserver(calls)
+
+	service, _, err := registry.For(FeatureChat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := []ChatMessage{{Role: "user", Content: "summarize this please"}}
+	if _, err := service.ChatCompletion(context.Background(), "", messages, 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(*calls) != 2 {
+		t.Fatalf("expected both attempts observed, got %+v", *calls)
+	}
+	failed, served := (*calls)[0], (*calls)[1]
+	if failed.Err == nil || failed.Fallback || failed.Feature != FeatureChat {
+		t.Errorf("unexpected primary attempt %+v", failed)
+	}
+	if served.Err != nil || !served.Fallback || served.Model != "gpt-4o-mini" {
+		t.Errorf("unexpected fallback attempt %+v", served)
+	}
+	if !served.TokensEstimated || served.PromptTokens != 6 || served.CompletionTokens != 2 {
+		t.Errorf("expected tokens estimated from the text, got %+v", served)
+	}
+	if failed.PromptTokens != 0 {
+		t.Errorf("expected no tokens for a failed attempt, got %+v", failed)
+	}
+
+	observed := registry.Observe(FeatureTranscriptChat, ProviderOpenAI, &fakeService{name: "openai"})
+	if _, err := observed.ChatCompletion(context.Background(), "gpt-4o", messages, 0); err != nil {
+		t.Fatal(err)
+	}
+	if last := (*calls)[len(*calls)-1]; last.Feature != FeatureTranscriptChat || last.Model != "gpt-4o" || last.Fallback {
+		t.Errorf("unexpected observed call %+v", last)
+	}
+}
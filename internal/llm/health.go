@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Defaults for provider health tracking
+const (
+	// DefaultFailureThreshold is how many consecutive failures mark a provider unhealthy
+	DefaultFailureThreshold = 3
+	// DefaultCooldown is how long an unhealthy provider is tried last before it gets another chance
+	DefaultCooldown = time.Minute
+)
+
+// ProviderHealth is the tracked state of one provider
+type ProviderHealth struct {
+	Provider            string     `json:"provider"`
+	Healthy             bool       `json:"healthy"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Successes           int64      `json:"successes"`
+	Failures            int64      `json:"failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"` // When an unhealthy provider is tried first again
+}
+
+// Health tracks the recent successes and failures of each provider. A provider that fails
+// threshold times in a row is unhealthy until its cooldown passes; the next call then
+// decides whether it recovers.
+type Health struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	states    map[string]*ProviderHealth
+	now       func() time.Time
+}
+
+// NewHealth creates a health tracker
+func NewHealth(threshold int, cooldown time.Duration) *Health {
+	if threshold <= 0 {
+		threshold = DefaultFailureThreshold
+	}
+	return &Health{
+		threshold: threshold,
+		cooldown:  cooldown,
+		states:    make(map[string]*ProviderHealth),
+		now:       time.Now,
+	}
+}
+
+// RecordSuccess marks a call to provider as successful, making it healthy again
+func (h *Health) RecordSuccess(provider string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	state := h.state(provider)
+	now := h.now()
+	state.Successes++
+	state.ConsecutiveFailures = 0
+	state.LastSuccessAt = &now
+	state.RetryAt = nil
+}
+
+// RecordFailure marks a call to provider as failed
+func (h *Health) RecordFailure(provider string, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	state := h.state(provider)
+	now := h.now()
+	state.Failures++
+	state.ConsecutiveFailures++
+	state.LastFailureAt = &now
+	if err != nil {
+		state.LastError = err.Error()
+	}
+	if state.ConsecutiveFailures >= h.threshold {
+		retryAt := now.Add(h.cooldown)
+		state.RetryAt = &retryAt
+	}
+}
+
+// Available reports whether provider should be tried in its normal place in a chain
+func (h *Health) Available(provider string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.available(h.state(provider))
+}
+
+// Snapshot returns the state of every provider that has been called, sorted by name
+func (h *Health) Snapshot() []ProviderHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make([]ProviderHealth, 0, len(h.states))
+	for _, state := range h.states {
+		snapshot := *state
+		snapshot.Healthy = h.available(state)
+		out = append(out, snapshot)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Provider < out[j].Provider })
+	return out
+}
+
+func (h *Health) available(state *ProviderHealth) bool {
+	return state.ConsecutiveFailures < h.threshold || state.RetryAt == nil || !h.now().Before(*state.RetryAt)
+}
+
+// state returns provider's state, creating it; callers hold mu
+func (h *Health) state(provider string) *ProviderHealth {
+	state, ok := h.states[provider]
+	if !ok {
+		state = &ProviderHealth{Provider: provider}
+		h.states[provider] = state
+	}
+	return state
+}
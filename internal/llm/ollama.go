@@ -77,6 +77,7 @@ type ollamaChatRequest struct {
 	Model    string              `json:"model"`
 	Messages []ollamaChatMessage `json:"messages"`
 	Stream   bool                `json:"stream"`
+	Format   json.RawMessage     `json:"format,omitempty"` // JSON Schema the reply must match
 	Options  map[string]any      `json:"options,omitempty"`
 }
 
@@ -86,11 +87,24 @@ type ollamaChatResponse struct {
 		Role    string `json:"role"`
 		Content string `json:"content"`
 	} `json:"message"`
-	Done bool `json:"done"`
+	Done            bool `json:"done"`
+	PromptEvalCount int  `json:"prompt_eval_count"`
+	EvalCount       int  `json:"eval_count"`
 }
 
 // ChatCompletion performs a non-streaming chat completion against Ollama
 func (s *OllamaService) ChatCompletion(ctx context.Context, model string, messages []ChatMessage, temperature float64) (*ChatResponse, error) {
+	return s.chatCompletion(ctx, model, messages, temperature, nil)
+}
+
+// ChatCompletionJSON performs a chat completion whose reply is constrained to schema
+func (s *OllamaService) ChatCompletionJSON(ctx context.Context, model string, messages []ChatMessage, temperature float64, schema Schema) (*ChatResponse, error) {
+	return s.chatCompletion(ctx, model, messages, temperature, schema.Definition)
+}
+
+// chatCompletion performs a non-streaming chat completion, with the reply constrained to
+// the format schema if given
+func (s *OllamaService) chatCompletion(ctx context.Context, model string, messages []ChatMessage, temperature float64, format json.RawMessage) (*ChatResponse, error) {
 	// Map to Ollama messages
 	msgs := make([]ollamaChatMessage, 0, len(messages))
 	for _, m := range messages {
@@ -100,6 +114,7 @@ func (s *OllamaService) ChatCompletion(ctx context.Context, model string, messag
 		Model:    model,
 		Messages: msgs,
 		Stream:   false,
+		Format:   format,
 	}
 	if temperature > 0 {
 		reqBody.Options = map[string]any{"temperature": temperature}
@@ -140,6 +155,9 @@ func (s *OllamaService) ChatCompletion(ctx context.Context, model string, messag
 	}}
 	cr.Choices[0].Message.Role = oResp.Message.Role
 	cr.Choices[0].Message.Content = oResp.Message.Content
+	cr.Usage.PromptTokens = oResp.PromptEvalCount
+	cr.Usage.CompletionTokens = oResp.EvalCount
+	cr.Usage.TotalTokens = oResp.PromptEvalCount + oResp.EvalCount
 	return cr, nil
 }
 
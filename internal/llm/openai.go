@@ -7,12 +7,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"strings"
 	"time"
+
+	"scriberr/pkg/logger"
 )
 
+// DefaultOpenAIBaseURL is the OpenAI API endpoint used when no base URL is configured
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
 // OpenAIService handles OpenAI API interactions
 type OpenAIService struct {
 	apiKey  string
@@ -22,9 +26,19 @@ type OpenAIService struct {
 
 // NewOpenAIService creates a new OpenAI service
 func NewOpenAIService(apiKey string) *OpenAIService {
+	return NewOpenAICompatibleService(apiKey, DefaultOpenAIBaseURL)
+}
+
+// NewOpenAICompatibleService creates a service for any endpoint implementing the OpenAI
+// chat completions API (vLLM, LM Studio, OpenRouter, ...). The API key may be empty for
+// servers that don't require one.
+func NewOpenAICompatibleService(apiKey, baseURL string) *OpenAIService {
+	if baseURL == "" {
+		baseURL = DefaultOpenAIBaseURL
+	}
 	return &OpenAIService{
 		apiKey:  apiKey,
-		baseURL: "https://api.openai.com/v1",
+		baseURL: strings.TrimRight(baseURL, "/"),
 		client: &http.Client{
 			Timeout: 300 * time.Second,
 		},
@@ -44,6 +58,18 @@ type ChatRequest struct {
 	Stream      bool          `json:"stream"`
 	Temperature float64       `json:"temperature,omitempty"`
 	MaxTokens   int           `json:"max_tokens,omitempty"`
+	// ResponseFormat constrains the reply to a JSON schema (structured outputs)
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+}
+
+// ResponseFormat is the response_format of a chat completion request
+type ResponseFormat struct {
+	Type       string `json:"type"` // "json_schema"
+	JSONSchema struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description,omitempty"`
+		Schema      json.RawMessage `json:"schema"`
+	} `json:"json_schema"`
 }
 
 // ChatResponse represents the OpenAI chat completion response
@@ -100,7 +126,7 @@ func (s *OpenAIService) GetModels(ctx context.Context) ([]string, error) {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	s.setAuthorization(req)
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := s.client.Do(req)
@@ -119,10 +145,10 @@ func (s *OpenAIService) GetModels(ctx context.Context) ([]string, error) {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	// Filter for chat models (GPT models)
+	// Filter for chat models (GPT models); other compatible servers only list what they serve
 	var chatModels []string
 	for _, model := range modelsResp.Data {
-		if strings.Contains(model.ID, "gpt") {
+		if s.baseURL != DefaultOpenAIBaseURL || strings.Contains(model.ID, "gpt") {
 			chatModels = append(chatModels, model.ID)
 		}
 	}
@@ -132,11 +158,26 @@ func (s *OpenAIService) GetModels(ctx context.Context) ([]string, error) {
 
 // ChatCompletion performs a non-streaming chat completion
 func (s *OpenAIService) ChatCompletion(ctx context.Context, model string, messages []ChatMessage, temperature float64) (*ChatResponse, error) {
+	return s.chatCompletion(ctx, model, messages, temperature, nil)
+}
+
+// ChatCompletionJSON performs a chat completion whose reply is constrained to schema
+func (s *OpenAIService) ChatCompletionJSON(ctx context.Context, model string, messages []ChatMessage, temperature float64, schema Schema) (*ChatResponse, error) {
+	format := &ResponseFormat{Type: "json_schema"}
+	format.JSONSchema.Name = schema.Name
+	format.JSONSchema.Description = schema.Description
+	format.JSONSchema.Schema = schema.Definition
+	return s.chatCompletion(ctx, model, messages, temperature, format)
+}
+
+// chatCompletion performs a non-streaming chat completion with an optional response format
+func (s *OpenAIService) chatCompletion(ctx context.Context, model string, messages []ChatMessage, temperature float64, format *ResponseFormat) (*ChatResponse, error) {
 	// Build request without temperature to use model defaults.
 	reqBody := ChatRequest{
-		Model:    model,
-		Messages: messages,
-		Stream:   false,
+		Model:          model,
+		Messages:       messages,
+		Stream:         false,
+		ResponseFormat: format,
 	}
 	// Only set temperature if caller provided a non-zero value.
 	if temperature != 0 {
@@ -153,10 +194,10 @@ func (s *OpenAIService) ChatCompletion(ctx context.Context, model string, messag
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	s.setAuthorization(req)
 	req.Header.Set("Content-Type", "application/json")
 
-	log.Printf("[openai] chat completion request model=%s messages=%d stream=%v", model, len(messages), false)
+	logger.DebugContext(ctx, "Chat completion request", "component", "openai", "model", model, "messages", len(messages))
 	resp, err := s.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
@@ -165,7 +206,7 @@ func (s *OpenAIService) ChatCompletion(ctx context.Context, model string, messag
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		log.Printf("[openai] chat completion error status=%d body=%s", resp.StatusCode, truncate(string(body), 500))
+		logger.WarnContext(ctx, "Chat completion failed", "component", "openai", "model", model, "status", resp.StatusCode, "body", truncate(string(body), 500))
 		return nil, fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
 	}
 
@@ -174,7 +215,7 @@ func (s *OpenAIService) ChatCompletion(ctx context.Context, model string, messag
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	log.Printf("[openai] chat completion ok model=%s choices=%d", model, len(chatResp.Choices))
+	logger.DebugContext(ctx, "Chat completion done", "component", "openai", "model", model, "choices", len(chatResp.Choices))
 	return &chatResp, nil
 }
 
@@ -210,11 +251,11 @@ func (s *OpenAIService) ChatCompletionStream(ctx context.Context, model string,
 			return
 		}
 
-		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+		s.setAuthorization(req)
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Accept", "text/event-stream")
 
-		log.Printf("[openai] chat stream request model=%s messages=%d stream=%v", model, len(messages), true)
+		logger.DebugContext(ctx, "Chat stream request", "component", "openai", "model", model, "messages", len(messages))
 		resp, err := s.client.Do(req)
 		if err != nil {
 			errorChan <- fmt.Errorf("failed to make request: %w", err)
@@ -224,7 +265,7 @@ func (s *OpenAIService) ChatCompletionStream(ctx context.Context, model string,
 
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
-			log.Printf("[openai] chat stream error status=%d body=%s", resp.StatusCode, truncate(string(body), 500))
+			logger.WarnContext(ctx, "Chat stream failed", "component", "openai", "model", model, "status", resp.StatusCode, "body", truncate(string(body), 500))
 			errorChan <- fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
 			return
 		}
@@ -244,7 +285,7 @@ func (s *OpenAIService) ChatCompletionStream(ctx context.Context, model string,
 
 			// Check for end of stream
 			if data == "[DONE]" {
-				log.Printf("[openai] chat stream done model=%s", model)
+				logger.DebugContext(ctx, "Chat stream done", "component", "openai", "model", model)
 				return
 			}
 
@@ -264,7 +305,7 @@ func (s *OpenAIService) ChatCompletionStream(ctx context.Context, model string,
 				}
 				if !loggedFirst {
 					loggedFirst = true
-					log.Printf("[openai] chat stream first content model=%s", model)
+					logger.DebugContext(ctx, "Chat stream first content", "component", "openai", "model", model)
 				}
 			}
 		}
@@ -277,6 +318,13 @@ func (s *OpenAIService) ChatCompletionStream(ctx context.Context, model string,
 	return contentChan, errorChan
 }
 
+// setAuthorization adds the API key, if any, to a request
+func (s *OpenAIService) setAuthorization(req *http.Request) {
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+}
+
 // truncate returns s trimmed to at most n runes.
 func truncate(s string, n int) string {
 	if len(s) <= n {
@@ -292,7 +340,7 @@ func (s *OpenAIService) ValidateAPIKey(ctx context.Context) error {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	s.setAuthorization(req)
 
 	resp, err := s.client.Do(req)
 	if err != nil {
@@ -0,0 +1,217 @@
+package llm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Provider names
+const (
+	ProviderOllama    = "ollama"
+	ProviderOpenAI    = "openai"
+	ProviderAnthropic = "anthropic"
+	ProviderFake      = "fake" // deterministic replies for tests and local development
+)
+
+// Features that can each use their own provider and model
+const (
+	// FeatureSummary covers post-processing: summaries, translations, document summaries and topic labels
+	FeatureSummary = "summary"
+	// FeatureChat covers RAG chat answers and their groundedness checks
+	FeatureChat = "chat"
+	// FeatureTranscriptChat covers chat sessions about a single transcript, using the LLM configured in the UI
+	FeatureTranscriptChat = "transcript_chat"
+	// FeatureSummarize covers summaries requested from the UI, using the LLM configured there
+	FeatureSummarize = "summarize"
+	// FeatureEmbedding covers embedding calls, which are recorded for usage but not routed through the registry
+	FeatureEmbedding = "embedding"
+)
+
+// ProviderConfig holds the connection settings of one provider
+type ProviderConfig struct {
+	BaseURL string
+	APIKey  string
+}
+
+// NewProvider creates the chat backend for a provider. Ollama needs a base URL, Anthropic
+// an API key, and OpenAI an API key unless it points at a compatible server.
+func NewProvider(name string, cfg ProviderConfig) (Service, error) {
+	switch strings.ToLower(name) {
+	case ProviderOllama:
+		if cfg.BaseURL == "" {
+			return nil, fmt.Errorf("Ollama base URL not configured")
+		}
+		return NewOllamaService(cfg.BaseURL), nil
+	case ProviderOpenAI:
+		if cfg.APIKey == "" && (cfg.BaseURL == "" || strings.TrimRight(cfg.BaseURL, "/") == DefaultOpenAIBaseURL) {
+			return nil, fmt.Errorf("OpenAI API key not configured")
+		}
+		return NewOpenAICompatibleService(cfg.APIKey, cfg.BaseURL), nil
+	case ProviderAnthropic:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("Anthropic API key not configured")
+		}
+		return NewAnthropicService(cfg.APIKey, cfg.BaseURL), nil
+	case ProviderFake:
+		return NewFakeService(), nil
+	default:
+		return nil, fmt.Errorf("unsupported LLM provider: %s", name)
+	}
+}
+
+// Binding is the provider and model a feature uses
+type Binding struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+}
+
+// ParseChain parses a comma-separated list of provider:model pairs, e.g.
+// "openai:gpt-4o-mini,anthropic:claude-3-5-haiku-latest". Only the first colon separates
+// the provider, so Ollama tags like "ollama:llama3.2:3b" work.
+func ParseChain(value string) ([]Binding, error) {
+	var chain []Binding
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		provider, model, ok := strings.Cut(entry, ":")
+		provider, model = strings.TrimSpace(provider), strings.TrimSpace(model)
+		if !ok || provider == "" || model == "" {
+			return nil, fmt.Errorf("invalid LLM fallback %q, expected provider:model", entry)
+		}
+		chain = append(chain, Binding{Provider: strings.ToLower(provider), Model: model})
+	}
+	return chain, nil
+}
+
+// Registry holds the configured providers and the fallback chain of each feature, and
+// tracks the health of every provider across features
+type Registry struct {
+	mu             sync.RWMutex
+	providers      map[string]Service
+	chains         map[string][]Binding
+	health         *Health
+	attemptTimeout time.Duration
+	observer       CallObserver
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		providers: make(map[string]Service),
+		chains:    make(map[string][]Binding),
+		health:    NewHealth(DefaultFailureThreshold, DefaultCooldown),
+	}
+}
+
+// SetAttemptTimeout bounds each provider attempt, so a provider that hangs fails over
+// instead of holding the call until the caller's deadline. 0 disables the bound.
+func (r *Registry) SetAttemptTimeout(timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attemptTimeout = timeout
+}
+
+// SetObserver reports every provider attempt made through the registry's chains to observer
+func (r *Registry) SetObserver(observer CallObserver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observer = observer
+}
+
+// Register adds a provider under a name, replacing any provider of the same name
+func (r *Registry) Register(name string, service Service) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[strings.ToLower(name)] = service
+}
+
+// Bind makes a feature use a registered provider and model, without fallbacks
+func (r *Registry) Bind(feature, provider, model string) error {
+	return r.BindChain(feature, []Binding{{Provider: provider, Model: model}})
+}
+
+// BindChain makes a feature use a list of registered providers and models, tried in order
+func (r *Registry) BindChain(feature string, chain []Binding) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(chain) == 0 {
+		return fmt.Errorf("no LLM provider configured for %s", feature)
+	}
+	bound := make([]Binding, len(chain))
+	for i, binding := range chain {
+		binding.Provider = strings.ToLower(binding.Provider)
+		if _, ok := r.providers[binding.Provider]; !ok {
+			return fmt.Errorf("LLM provider %q for %s is not configured", binding.Provider, feature)
+		}
+		if binding.Model == "" {
+			return fmt.Errorf("no model configured for %s on %s", feature, binding.Provider)
+		}
+		bound[i] = binding
+	}
+	r.chains[feature] = bound
+	return nil
+}
+
+// For returns the service and primary model bound to a feature. The service falls back
+// along the feature's chain and reports every call to the registry's health tracker.
+func (r *Registry) For(feature string) (Service, string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	chain, ok := r.chains[feature]
+	if !ok {
+		return nil, "", fmt.Errorf("no LLM provider bound to %s", feature)
+	}
+	targets := make([]ChainTarget, len(chain))
+	for i, binding := range chain {
+		targets[i] = ChainTarget{Binding: binding, Service: r.providers[binding.Provider]}
+	}
+	service := NewChain(targets, r.health, r.attemptTimeout)
+	service.feature = feature
+	service.observer = r.observer
+	return service, chain[0].Model, nil
+}
+
+// Observe returns service wrapped so its calls are reported to the registry's observer
+// under feature. It is for services configured outside the registry; the wrapper has no
+// fallbacks and keeps its own health, so the model passed to each call is used as is.
+func (r *Registry) Observe(feature, provider string, service Service) Service {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	chain := NewChain([]ChainTarget{{Binding: Binding{Provider: strings.ToLower(provider)}, Service: service}}, nil, 0)
+	chain.feature = feature
+	chain.observer = r.observer
+	return chain
+}
+
+// Chains returns the fallback chain of every feature
+func (r *Registry) Chains() map[string][]Binding {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[string][]Binding, len(r.chains))
+	for feature, chain := range r.chains {
+		out[feature] = append([]Binding(nil), chain...)
+	}
+	return out
+}
+
+// Health returns the tracker shared by every feature's chain
+func (r *Registry) Health() *Health {
+	return r.health
+}
+
+// Providers returns the names of the registered providers, sorted
+func (r *Registry) Providers() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
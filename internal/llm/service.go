@@ -2,9 +2,14 @@ package llm
 
 import "context"
 
+// Completer runs non-streaming chat completions
+type Completer interface {
+	ChatCompletion(ctx context.Context, model string, messages []ChatMessage, temperature float64) (*ChatResponse, error)
+}
+
 // Service is a provider-agnostic LLM interface
 type Service interface {
+	Completer
 	GetModels(ctx context.Context) ([]string, error)
-	ChatCompletion(ctx context.Context, model string, messages []ChatMessage, temperature float64) (*ChatResponse, error)
 	ChatCompletionStream(ctx context.Context, model string, messages []ChatMessage, temperature float64) (<-chan string, <-chan error)
 }
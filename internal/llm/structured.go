@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Schema describes the JSON object a structured completion must reply with
+type Schema struct {
+	Name        string          // Identifier of the object, e.g. "summary"
+	Description string          // What the object holds, for providers that take one
+	Definition  json.RawMessage // JSON Schema of the object
+}
+
+// StructuredService is implemented by services that can constrain a completion to a JSON
+// schema: OpenAI structured outputs, Ollama's format parameter and Anthropic tool use.
+// The reply's message content is the JSON object.
+type StructuredService interface {
+	ChatCompletionJSON(ctx context.Context, model string, messages []ChatMessage, temperature float64, schema Schema) (*ChatResponse, error)
+}
+
+// CompleteJSON runs a completion whose reply is a JSON object matching schema and decodes it
+// into out. Services without JSON support are given the schema in the prompt instead.
+func CompleteJSON(ctx context.Context, service Completer, model string, messages []ChatMessage, temperature float64, schema Schema, out any) error {
+	resp, err := completeJSON(ctx, service, model, messages, temperature, schema)
+	if err != nil {
+		return err
+	}
+	if len(resp.Choices) == 0 {
+		return fmt.Errorf("no response from LLM")
+	}
+	return DecodeJSON(resp.Choices[0].Message.Content, out)
+}
+
+// completeJSON runs one structured completion, natively if the service supports it
+func completeJSON(ctx context.Context, service Completer, model string, messages []ChatMessage, temperature float64, schema Schema) (*ChatResponse, error) {
+	if structured, ok := service.(StructuredService); ok {
+		return structured.ChatCompletionJSON(ctx, model, messages, temperature, schema)
+	}
+	return service.ChatCompletion(ctx, model, withSchemaInstructions(messages, schema), temperature)
+}
+
+// withSchemaInstructions appends the schema to the conversation as a system message
+func withSchemaInstructions(messages []ChatMessage, schema Schema) []ChatMessage {
+	instructions := fmt.Sprintf("Reply with a single JSON object and nothing else. It must match this JSON Schema:\n%s", schema.Definition)
+	return append(append([]ChatMessage(nil), messages...), ChatMessage{Role: "system", Content: instructions})
+}
+
+// DecodeJSON decodes the JSON object in an LLM reply into out, tolerating code fences or
+// text around it
+func DecodeJSON(reply string, out any) error {
+	start := strings.Index(reply, "{")
+	end := strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return fmt.Errorf("reply contained no JSON object")
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), out); err != nil {
+		return fmt.Errorf("failed to decode JSON reply: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var testSchema = Schema{Name: "answer", Definition: json.RawMessage(`{"type":"object","properties":{"value":{"type":"integer"}},"required":["value"]}`)}
+
+type jsonAnswer struct {
+	Value int `json:"value"`
+}
+
+func TestCompleteJSONWithAnthropicToolUse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req anthropicRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if len(req.Tools) != 1 || req.Tools[0].Name != "answer" || req.ToolChoice == nil || req.ToolChoice.Name != "answer" {
+			t.Errorf("expected a forced call to the schema tool, got %+v and %+v", req.Tools, req.ToolChoice)
+		}
+		fmt.Fprint(w, `{"id":"msg_1","model":"claude-test","content":[{"type":"text","text":"Sure."},{"type":"tool_use","name":"answer","input":{"value":42}}],"stop_reason":"tool_use"}`)
+	}))
+	defer server.Close()
+
+	var out jsonAnswer
+	err := CompleteJSON(context.Background(), NewAnthropicService("secret", server.URL), "claude-test", []ChatMessage{{Role: "user", Content: "What is it?"}}, 0, testSchema, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Value != 42 {
+		t.Errorf("expected the tool input as the reply, got %+v", out)
+	}
+}
+
+func TestCompleteJSONWithOpenAIResponseFormat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.ResponseFormat == nil || req.ResponseFormat.Type != "json_schema" || req.ResponseFormat.JSONSchema.Name != "answer" {
+			t.Errorf("expected a json_schema response format, got %+v", req.ResponseFormat)
+		}
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"{\"value\":7}"}}]}`)
+	}))
+	defer server.Close()
+
+	var out jsonAnswer
+	if err := CompleteJSON(context.Background(), NewOpenAICompatibleService("", server.URL), "local", []ChatMessage{{Role: "user", Content: "?"}}, 0, testSchema, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Value != 7 {
+		t.Errorf("expected 7, got %+v", out)
+	}
+}
+
+func TestCompleteJSONThroughChainFallsBackToPrompt(t *testing.T) {
+	// fakeService has no JSON mode, so the schema goes into the prompt and the object is
+	// extracted from whatever the model wraps it in
+	chain := NewChain([]ChainTarget{{Binding: Binding{Provider: "fake"}, Service: &fakeService{name: "```json\n{\"value\": 3}\n```"}}}, nil, 0)
+
+	var out jsonAnswer
+	if err := CompleteJSON(context.Background(), chain, "", []ChatMessage{{Role: "user", Content: "?"}}, 0, testSchema, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Value != 3 {
+		t.Errorf("expected 3, got %+v", out)
+	}
+	if err := DecodeJSON("no json here", &out); err == nil {
+		t.Error("expected an error for a reply without a JSON object")
+	}
+}
@@ -0,0 +1,9 @@
+package llm
+
+// bytesPerToken approximates how much English text one token covers for common tokenizers
+const bytesPerToken = 4
+
+// EstimateTokens approximates the token count of text, for providers that don't report usage
+func EstimateTokens(text string) int {
+	return (len(text) + bytesPerToken - 1) / bytesPerToken
+}
@@ -0,0 +1,100 @@
+package llmstats
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Price is what a model costs in USD per million tokens
+type Price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// Pricing maps model names to prices. A model matches the longest entry it starts with,
+// after dropping any "vendor/" prefix, so "gpt-4o" also prices "gpt-4o-2024-08-06".
+type Pricing map[string]Price
+
+// defaultPricing holds list prices of common hosted models; LLM_PRICING adds to or overrides them
+var defaultPricing = Pricing{
+	"gpt-4o":                 {Input: 2.50, Output: 10.00},
+	"gpt-4o-mini":            {Input: 0.15, Output: 0.60},
+	"gpt-4.1":                {Input: 2.00, Output: 8.00},
+	"gpt-4.1-mini":           {Input: 0.40, Output: 1.60},
+	"gpt-4.1-nano":           {Input: 0.10, Output: 0.40},
+	"gpt-4-turbo":            {Input: 10.00, Output: 30.00},
+	"gpt-3.5-turbo":          {Input: 0.50, Output: 1.50},
+	"o3-mini":                {Input: 1.10, Output: 4.40},
+	"o4-mini":                {Input: 1.10, Output: 4.40},
+	"text-embedding-3-small": {Input: 0.02},
+	"text-embedding-3-large": {Input: 0.13},
+	"claude-3-haiku":         {Input: 0.25, Output: 1.25},
+	"claude-3-5-haiku":       {Input: 0.80, Output: 4.00},
+	"claude-3-5-sonnet":      {Input: 3.00, Output: 15.00},
+	"claude-3-7-sonnet":      {Input: 3.00, Output: 15.00},
+	"claude-3-opus":          {Input: 15.00, Output: 75.00},
+	"claude-sonnet-4":        {Input: 3.00, Output: 15.00},
+	"claude-opus-4":          {Input: 15.00, Output: 75.00},
+}
+
+// freeProviders run locally, so their calls cost nothing whatever the model
+var freeProviders = map[string]bool{"ollama": true}
+
+// ParsePricing parses "model=input/output,..." prices in USD per million tokens on top of
+// the defaults. An empty spec gives the defaults.
+func ParsePricing(spec string) (Pricing, error) {
+	pricing := make(Pricing, len(defaultPricing))
+	for model, price := range defaultPricing {
+		pricing[model] = price
+	}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		model, prices, ok := strings.Cut(entry, "=")
+		model = strings.ToLower(strings.TrimSpace(model))
+		if !ok || model == "" {
+			return nil, fmt.Errorf("invalid price %q, expected model=input/output", entry)
+		}
+		input, output, _ := strings.Cut(prices, "/")
+		var price Price
+		var err error
+		if price.Input, err = strconv.ParseFloat(strings.TrimSpace(input), 64); err != nil || price.Input < 0 {
+			return nil, fmt.Errorf("invalid input price for %s: %q", model, input)
+		}
+		if strings.TrimSpace(output) != "" {
+			if price.Output, err = strconv.ParseFloat(strings.TrimSpace(output), 64); err != nil || price.Output < 0 {
+				return nil, fmt.Errorf("invalid output price for %s: %q", model, output)
+			}
+		}
+		pricing[model] = price
+	}
+	return pricing, nil
+}
+
+// Lookup returns the price of a model on a provider, reporting false for unknown models.
+// Models of local providers are free.
+func (p Pricing) Lookup(provider, model string) (Price, bool) {
+	if freeProviders[strings.ToLower(provider)] {
+		return Price{}, true
+	}
+	model = strings.ToLower(model)
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		model = model[i+1:]
+	}
+	best, found := "", false
+	for name := range p {
+		if strings.HasPrefix(model, name) && len(name) >= len(best) {
+			best, found = name, true
+		}
+	}
+	return p[best], found
+}
+
+// Cost returns the estimated cost in USD of a call, or 0 for unknown models
+func (p Pricing) Cost(provider, model string, promptTokens, completionTokens int) float64 {
+	price, _ := p.Lookup(provider, model)
+	return (float64(promptTokens)*price.Input + float64(completionTokens)*price.Output) / 1e6
+}
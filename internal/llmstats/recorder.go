@@ -0,0 +1,126 @@
+// Package llmstats records every LLM provider and embedding call and aggregates latency,
+// error, token and cost statistics.
+package llmstats
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/embeddings"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+)
+
+const (
+	// maxErrorLength caps the stored error message of a failed call
+	maxErrorLength = 500
+	// pruneInterval is how often calls past the retention period are deleted
+	pruneInterval = time.Hour
+)
+
+// Recorder stores every provider call observed by an LLM registry, and the embedding
+// calls of services wrapped with WrapEmbeddings
+type Recorder struct {
+	retention time.Duration
+	pricing   Pricing
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+// NewRecorder creates a recorder that keeps calls for retention (0 keeps them forever)
+// and prices them with pricing
+func NewRecorder(retention time.Duration, pricing Pricing) *Recorder {
+	return &Recorder{retention: retention, pricing: pricing}
+}
+
+// ObserveCall stores a call. Failures are logged rather than returned, since they
+// shouldn't affect the LLM call being measured.
+func (r *Recorder) ObserveCall(call llm.Call) {
+	record := models.LLMCall{
+		Feature:          call.Feature,
+		Provider:         call.Provider,
+		Model:            call.Model,
+		Fallback:         call.Fallback,
+		Stream:           call.Stream,
+		Success:          call.Err == nil,
+		LatencyMs:        call.Latency.Milliseconds(),
+		PromptTokens:     call.PromptTokens,
+		CompletionTokens: call.CompletionTokens,
+		TokensEstimated:  call.TokensEstimated,
+		CostUSD:          r.pricing.Cost(call.Provider, call.Model, call.PromptTokens, call.CompletionTokens),
+	}
+	if call.Err != nil {
+		record.Error = call.Err.Error()
+		if len(record.Error) > maxErrorLength {
+			record.Error = record.Error[:maxErrorLength] + "..."
+		}
+	}
+	if err := database.DB.Create(&record).Error; err != nil {
+		logger.Error("Failed to record LLM call", "component", "llmstats", "provider", call.Provider, "error", err)
+	}
+	r.prune()
+}
+
+// WrapEmbeddings returns service wrapped so each of its calls is recorded under the
+// embedding feature. Embedding services don't report usage, so tokens are estimated.
+func (r *Recorder) WrapEmbeddings(provider string, service embeddings.Service) embeddings.Service {
+	return &recordedEmbeddings{Service: service, provider: strings.ToLower(provider), recorder: r}
+}
+
+// recordedEmbeddings records the calls of the embedding service it wraps
+type recordedEmbeddings struct {
+	embeddings.Service
+	provider string
+	recorder *Recorder
+}
+
+func (e *recordedEmbeddings) GenerateEmbedding(text string) ([]float32, error) {
+	started := time.Now()
+	embedding, err := e.Service.GenerateEmbedding(text)
+	e.record([]string{text}, time.Since(started), err)
+	return embedding, err
+}
+
+func (e *recordedEmbeddings) GenerateEmbeddings(texts []string) ([][]float32, error) {
+	started := time.Now()
+	vectors, err := e.Service.GenerateEmbeddings(texts)
+	e.record(texts, time.Since(started), err)
+	return vectors, err
+}
+
+func (e *recordedEmbeddings) record(texts []string, latency time.Duration, err error) {
+	call := llm.Call{
+		Binding:         llm.Binding{Provider: e.provider, Model: e.Model()},
+		Feature:         llm.FeatureEmbedding,
+		Latency:         latency,
+		Err:             err,
+		TokensEstimated: true,
+	}
+	for _, text := range texts {
+		call.PromptTokens += llm.EstimateTokens(text)
+	}
+	e.recorder.ObserveCall(call)
+}
+
+// prune deletes calls past the retention period, at most once per pruneInterval
+func (r *Recorder) prune() {
+	if r.retention <= 0 {
+		return
+	}
+	r.mu.Lock()
+	if time.Since(r.lastPrune) < pruneInterval {
+		r.mu.Unlock()
+		return
+	}
+	r.lastPrune = time.Now()
+	r.mu.Unlock()
+
+	cutoff := time.Now().Add(-r.retention)
+	if err := database.DB.Where("created_at < ?", cutoff).Delete(&models.LLMCall{}).Error; err != nil {
+		logger.Error("Failed to prune LLM calls", "component", "llmstats", "error", err)
+	}
+}
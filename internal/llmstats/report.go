@@ -0,0 +1,218 @@
+package llmstats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+)
+
+// MaxBuckets caps the number of time buckets in a report
+const MaxBuckets = 500
+
+// ErrInvalidQuery is returned by Summarize for an empty window or unusable bucket width
+var ErrInvalidQuery = errors.New("invalid metrics query")
+
+// Query selects the calls a report covers
+type Query struct {
+	Since    time.Time
+	Until    time.Time
+	Bucket   time.Duration // Width of each time series bucket
+	Feature  string        // Optional
+	Provider string        // Optional
+}
+
+// Latency holds latency percentiles in milliseconds
+type Latency struct {
+	P50 int64 `json:"p50"`
+	P90 int64 `json:"p90"`
+	P99 int64 `json:"p99"`
+	Max int64 `json:"max"`
+}
+
+// ProviderStats aggregates the calls to one provider
+type ProviderStats struct {
+	Provider         string   `json:"provider"`
+	Models           []string `json:"models"`
+	Calls            int      `json:"calls"`
+	Errors           int      `json:"errors"`
+	ErrorRate        float64  `json:"error_rate"`
+	TrafficShare     float64  `json:"traffic_share"`  // Share of all successful calls served by this provider
+	FallbackCalls    int      `json:"fallback_calls"` // Successful calls served as a fallback
+	Latency          Latency  `json:"latency_ms"`     // Of successful calls
+	PromptTokens     int64    `json:"prompt_tokens"`
+	CompletionTokens int64    `json:"completion_tokens"`
+	TotalTokens      int64    `json:"total_tokens"`
+}
+
+// Bucket aggregates the calls to one provider in one time bucket
+type Bucket struct {
+	Start       time.Time `json:"start"`
+	Provider    string    `json:"provider"`
+	Calls       int       `json:"calls"`
+	Errors      int       `json:"errors"`
+	P50Ms       int64     `json:"p50_ms"`
+	TotalTokens int64     `json:"total_tokens"`
+}
+
+// Report is the aggregate of the calls selected by a Query
+type Report struct {
+	Since         time.Time       `json:"since"`
+	Until         time.Time       `json:"until"`
+	BucketSeconds int64           `json:"bucket_seconds"`
+	Calls         int             `json:"calls"`
+	Providers     []ProviderStats `json:"providers"` // Most successful calls first
+	Series        []Bucket        `json:"series"`    // Oldest first
+}
+
+// Summarize aggregates the calls selected by q
+func Summarize(q Query) (*Report, error) {
+	if !q.Until.After(q.Since) {
+		return nil, fmt.Errorf("%w: until must be after since", ErrInvalidQuery)
+	}
+	if q.Bucket <= 0 || q.Until.Sub(q.Since)/q.Bucket >= MaxBuckets {
+		return nil, fmt.Errorf("%w: bucket must be positive and give at most %d buckets", ErrInvalidQuery, MaxBuckets)
+	}
+
+	query := database.DB.Where("created_at >= ? AND created_at < ?", q.Since, q.Until)
+	if q.Feature != "" {
+		query = query.Where("feature = ?", q.Feature)
+	}
+	if q.Provider != "" {
+		query = query.Where("provider = ?", q.Provider)
+	}
+	var calls []models.LLMCall
+	if err := query.Order("created_at ASC").Find(&calls).Error; err != nil {
+		return nil, fmt.Errorf("failed to load LLM calls: %w", err)
+	}
+
+	return aggregate(calls, q), nil
+}
+
+// aggregate builds a report from calls sorted by time
+func aggregate(calls []models.LLMCall, q Query) *Report {
+	report := &Report{
+		Since:         q.Since,
+		Until:         q.Until,
+		BucketSeconds: int64(q.Bucket / time.Second),
+		Calls:         len(calls),
+		Providers:     []ProviderStats{},
+		Series:        []Bucket{},
+	}
+
+	type accumulator struct {
+		stats     ProviderStats
+		models    map[string]bool
+		latencies []int64
+	}
+	byProvider := map[string]*accumulator{}
+	type bucketKey struct {
+		index    int64
+		provider string
+	}
+	buckets := map[bucketKey]*Bucket{}
+	bucketLatencies := map[bucketKey][]int64{}
+	successes := 0
+
+	for _, call := range calls {
+		acc, ok := byProvider[call.Provider]
+		if !ok {
+			acc = &accumulator{stats: ProviderStats{Provider: call.Provider}, models: map[string]bool{}}
+			byProvider[call.Provider] = acc
+		}
+		key := bucketKey{int64(call.CreatedAt.Sub(q.Since) / q.Bucket), call.Provider}
+		bucket, ok := buckets[key]
+		if !ok {
+			bucket = &Bucket{Start: q.Since.Add(time.Duration(key.index) * q.Bucket), Provider: call.Provider}
+			buckets[key] = bucket
+		}
+
+		tokens := int64(call.PromptTokens + call.CompletionTokens)
+		acc.models[call.Model] = true
+		acc.stats.Calls++
+		acc.stats.PromptTokens += int64(call.PromptTokens)
+		acc.stats.CompletionTokens += int64(call.CompletionTokens)
+		acc.stats.TotalTokens += tokens
+		bucket.Calls++
+		bucket.TotalTokens += tokens
+		if !call.Success {
+			acc.stats.Errors++
+			bucket.Errors++
+			continue
+		}
+		successes++
+		if call.Fallback {
+			acc.stats.FallbackCalls++
+		}
+		acc.latencies = append(acc.latencies, call.LatencyMs)
+		bucketLatencies[key] = append(bucketLatencies[key], call.LatencyMs)
+	}
+
+	for _, acc := range byProvider {
+		stats := acc.stats
+		for model := range acc.models {
+			stats.Models = append(stats.Models, model)
+		}
+		sort.Strings(stats.Models)
+		stats.ErrorRate = ratio(stats.Errors, stats.Calls)
+		stats.TrafficShare = ratio(stats.Calls-stats.Errors, successes)
+		sort.Slice(acc.latencies, func(i, j int) bool { return acc.latencies[i] < acc.latencies[j] })
+		stats.Latency = Latency{
+			P50: Percentile(acc.latencies, 50),
+			P90: Percentile(acc.latencies, 90),
+			P99: Percentile(acc.latencies, 99),
+		}
+		if n := len(acc.latencies); n > 0 {
+			stats.Latency.Max = acc.latencies[n-1]
+		}
+		report.Providers = append(report.Providers, stats)
+	}
+	sort.Slice(report.Providers, func(i, j int) bool {
+		a, b := report.Providers[i], report.Providers[j]
+		if a.TrafficShare != b.TrafficShare {
+			return a.TrafficShare > b.TrafficShare
+		}
+		return a.Provider < b.Provider
+	})
+
+	for key, bucket := range buckets {
+		latencies := bucketLatencies[key]
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		bucket.P50Ms = Percentile(latencies, 50)
+		report.Series = append(report.Series, *bucket)
+	}
+	sort.Slice(report.Series, func(i, j int) bool {
+		a, b := report.Series[i], report.Series[j]
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+		return a.Provider < b.Provider
+	})
+	return report
+}
+
+// Percentile returns the nearest-rank percentile p (0-100) of sorted values, or 0 if there are none
+func Percentile(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
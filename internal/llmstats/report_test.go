@@ -0,0 +1,78 @@
+package llmstats
+
+import (
+	"testing"
+	"time"
+
+	"scriberr/internal/models"
+)
+
+func TestPercentile(t *testing.T) {
+	values := []int64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}
+	if got := Percentile(values, 50); got != 50 {
+		t.Errorf("expected p50 50, got %d", got)
+	}
+	if got := Percentile(values, 99); got != 100 {
+		t.Errorf("expected p99 100, got %d", got)
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("expected 0 without values, got %d", got)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	call := func(minute int, provider string, success, fallback bool, latency int64, tokens int) models.LLMCall {
+		return models.LLMCall{
+			Provider:         provider,
+			Model:            provider + "-model",
+			Success:          success,
+			Fallback:         fallback,
+			LatencyMs:        latency,
+			PromptTokens:     tokens,
+			CompletionTokens: tokens / 2,
+			CreatedAt:        since.Add(time.Duration(minute) * time.Minute),
+		}
+	}
+	calls := []models.LLMCall{
+		call(1, "ollama", true, false, 1000, 100),
+		call(2, "ollama", false, false, 30000, 0),
+		call(2, "openrouter", true, true, 400, 200),
+		call(61, "ollama", false, false, 30000, 0),
+		call(61, "openrouter", true, true, 600, 200),
+	}
+
+	report := aggregate(calls, Query{Since: since, Until: since.Add(2 * time.Hour), Bucket: time.Hour})
+	if report.Calls != 5 || len(report.Providers) != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	fallback := report.Providers[0]
+	if fallback.Provider != "openrouter" {
+		t.Fatalf("expected the provider serving most traffic first, got %s", fallback.Provider)
+	}
+	if fallback.FallbackCalls != 2 || fallback.TrafficShare < 0.66 || fallback.TrafficShare > 0.67 {
+		t.Errorf("expected openrouter to serve 2 of 3 successful calls as fallback, got %+v", fallback)
+	}
+	if fallback.Latency.P50 != 400 || fallback.Latency.Max != 600 || fallback.TotalTokens != 600 {
+		t.Errorf("unexpected openrouter latency or tokens %+v", fallback)
+	}
+
+	primary := report.Providers[1]
+	if primary.Errors != 2 || primary.ErrorRate < 0.66 || primary.ErrorRate > 0.67 {
+		t.Errorf("expected 2 of 3 ollama calls to have failed, got %+v", primary)
+	}
+	if primary.Latency.P99 != 1000 {
+		t.Errorf("expected latency percentiles to cover only successful calls, got %+v", primary.Latency)
+	}
+
+	if len(report.Series) != 4 {
+		t.Fatalf("expected one bucket per provider per hour, got %+v", report.Series)
+	}
+	if first := report.Series[0]; first.Provider != "ollama" || first.Calls != 2 || first.Errors != 1 || !first.Start.Equal(since) {
+		t.Errorf("unexpected first bucket %+v", first)
+	}
+	if last := report.Series[3]; last.Provider != "openrouter" || !last.Start.Equal(since.Add(time.Hour)) {
+		t.Errorf("unexpected last bucket %+v", last)
+	}
+}
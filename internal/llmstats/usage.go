@@ -0,0 +1,177 @@
+package llmstats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+)
+
+// Usage periods
+const (
+	GroupByDay   = "day"
+	GroupByMonth = "month"
+)
+
+// UsageQuery selects the calls a usage report covers
+type UsageQuery struct {
+	Since   time.Time
+	Until   time.Time
+	GroupBy string // GroupByDay or GroupByMonth, in UTC
+}
+
+// UsageTotals sums the tokens and cost of a set of calls
+type UsageTotals struct {
+	Calls            int     `json:"calls"`
+	EstimatedCalls   int     `json:"estimated_calls"` // Calls whose token counts were estimated from the text
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	TotalTokens      int64   `json:"total_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+}
+
+// UsageGroup is the usage of one feature or one provider/model pair
+type UsageGroup struct {
+	Name string `json:"name"`
+	UsageTotals
+}
+
+// UsagePeriod is the usage of one day or month
+type UsagePeriod struct {
+	Start time.Time `json:"start"`
+	UsageTotals
+	ByFeature []UsageGroup `json:"by_feature"`
+	ByModel   []UsageGroup `json:"by_model"`
+}
+
+// UsageReport is the token usage and cost of the calls selected by a UsageQuery
+type UsageReport struct {
+	Since   time.Time `json:"since"`
+	Until   time.Time `json:"until"`
+	GroupBy string    `json:"group_by"`
+	UsageTotals
+	ByFeature []UsageGroup  `json:"by_feature"` // Most expensive first, then most tokens
+	ByModel   []UsageGroup  `json:"by_model"`   // "provider/model", ordered like ByFeature
+	Periods   []UsagePeriod `json:"periods"`    // Oldest first
+}
+
+// Usage totals the token usage and cost of the calls selected by q
+func Usage(q UsageQuery) (*UsageReport, error) {
+	if !q.Until.After(q.Since) {
+		return nil, fmt.Errorf("%w: until must be after since", ErrInvalidQuery)
+	}
+	if q.GroupBy != GroupByDay && q.GroupBy != GroupByMonth {
+		return nil, fmt.Errorf("%w: group_by must be %s or %s", ErrInvalidQuery, GroupByDay, GroupByMonth)
+	}
+	if q.GroupBy == GroupByDay && q.Until.Sub(q.Since) >= MaxBuckets*24*time.Hour {
+		return nil, fmt.Errorf("%w: at most %d days can be grouped by day", ErrInvalidQuery, MaxBuckets)
+	}
+
+	var calls []models.LLMCall
+	err := database.DB.Where("created_at >= ? AND created_at < ?", q.Since, q.Until).
+		Order("created_at ASC").Find(&calls).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load LLM calls: %w", err)
+	}
+	return usage(calls, q), nil
+}
+
+// usageAccumulator sums calls overall and per feature and model
+type usageAccumulator struct {
+	totals    UsageTotals
+	byFeature map[string]*UsageTotals
+	byModel   map[string]*UsageTotals
+}
+
+func newUsageAccumulator() *usageAccumulator {
+	return &usageAccumulator{byFeature: map[string]*UsageTotals{}, byModel: map[string]*UsageTotals{}}
+}
+
+func (a *usageAccumulator) add(call models.LLMCall) {
+	model := call.Provider + "/" + call.Model
+	for _, totals := range []*UsageTotals{&a.totals, group(a.byFeature, call.Feature), group(a.byModel, model)} {
+		totals.Calls++
+		if call.TokensEstimated {
+			totals.EstimatedCalls++
+		}
+		totals.PromptTokens += int64(call.PromptTokens)
+		totals.CompletionTokens += int64(call.CompletionTokens)
+		totals.TotalTokens += int64(call.PromptTokens + call.CompletionTokens)
+		totals.CostUSD += call.CostUSD
+	}
+}
+
+func group(groups map[string]*UsageTotals, name string) *UsageTotals {
+	totals, ok := groups[name]
+	if !ok {
+		totals = &UsageTotals{}
+		groups[name] = totals
+	}
+	return totals
+}
+
+// usage builds a usage report from calls
+func usage(calls []models.LLMCall, q UsageQuery) *UsageReport {
+	overall := newUsageAccumulator()
+	periods := map[time.Time]*usageAccumulator{}
+	for _, call := range calls {
+		start := periodStart(call.CreatedAt, q.GroupBy)
+		period, ok := periods[start]
+		if !ok {
+			period = newUsageAccumulator()
+			periods[start] = period
+		}
+		overall.add(call)
+		period.add(call)
+	}
+
+	report := &UsageReport{
+		Since:       q.Since,
+		Until:       q.Until,
+		GroupBy:     q.GroupBy,
+		UsageTotals: overall.totals,
+		ByFeature:   sortedGroups(overall.byFeature),
+		ByModel:     sortedGroups(overall.byModel),
+		Periods:     make([]UsagePeriod, 0, len(periods)),
+	}
+	for start, period := range periods {
+		report.Periods = append(report.Periods, UsagePeriod{
+			Start:       start,
+			UsageTotals: period.totals,
+			ByFeature:   sortedGroups(period.byFeature),
+			ByModel:     sortedGroups(period.byModel),
+		})
+	}
+	sort.Slice(report.Periods, func(i, j int) bool { return report.Periods[i].Start.Before(report.Periods[j].Start) })
+	return report
+}
+
+// periodStart returns the start of the UTC day or month containing t
+func periodStart(t time.Time, groupBy string) time.Time {
+	t = t.UTC()
+	if groupBy == GroupByMonth {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// sortedGroups lists groups by cost, then tokens, then name
+func sortedGroups(groups map[string]*UsageTotals) []UsageGroup {
+	list := make([]UsageGroup, 0, len(groups))
+	for name, totals := range groups {
+		list = append(list, UsageGroup{Name: name, UsageTotals: *totals})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.CostUSD != b.CostUSD {
+			return a.CostUSD > b.CostUSD
+		}
+		if a.TotalTokens != b.TotalTokens {
+			return a.TotalTokens > b.TotalTokens
+		}
+		return a.Name < b.Name
+	})
+	return list
+}
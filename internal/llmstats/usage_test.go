@@ -0,0 +1,72 @@
+package llmstats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"scriberr/internal/models"
+)
+
+func TestPricing(t *testing.T) {
+	pricing, err := ParsePricing("my-model=1/2, gpt-4o=5/15")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pricing.Cost("openai", "my-model", 1_000_000, 500_000); got != 2 {
+		t.Errorf("expected configured price, got %v", got)
+	}
+	if got := pricing.Cost("openai", "gpt-4o", 1_000_000, 0); got != 5 {
+		t.Errorf("expected override of the default price, got %v", got)
+	}
+	if got := pricing.Cost("openai", "openai/gpt-4o-mini-2024-07-18", 1_000_000, 0); got != 0.15 {
+		t.Errorf("expected the longest prefix to match after the vendor, got %v", got)
+	}
+	if got := pricing.Cost("ollama", "gpt-4o", 1_000_000, 1_000_000); got != 0 {
+		t.Errorf("expected local providers to be free, got %v", got)
+	}
+	if _, ok := pricing.Lookup("openai", "unknown-model"); ok {
+		t.Error("expected unknown model to have no price")
+	}
+	if _, err := ParsePricing("no-price"); err == nil {
+		t.Error("expected an error for an entry without a price")
+	}
+}
+
+func TestUsage(t *testing.T) {
+	since := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	call := func(day int, feature, provider string, tokens int, cost float64) models.LLMCall {
+		return models.LLMCall{
+			Feature:          feature,
+			Provider:         provider,
+			Model:            "m",
+			PromptTokens:     tokens,
+			CompletionTokens: tokens / 2,
+			TokensEstimated:  provider == "ollama",
+			CostUSD:          cost,
+			CreatedAt:        since.Add(time.Duration(day)*24*time.Hour + time.Hour),
+		}
+	}
+	calls := []models.LLMCall{
+		call(0, "summary", "openai", 1000, 0.01),
+		call(0, "embedding", "ollama", 400, 0),
+		call(1, "chat", "openai", 2000, 0.02),
+	}
+
+	report := usage(calls, UsageQuery{Since: since, Until: since.Add(48 * time.Hour), GroupBy: GroupByMonth})
+	if report.Calls != 3 || report.EstimatedCalls != 1 || report.TotalTokens != 5100 {
+		t.Errorf("unexpected totals %+v", report.UsageTotals)
+	}
+	if math.Abs(report.CostUSD-0.03) > 1e-9 {
+		t.Errorf("expected total cost 0.03, got %v", report.CostUSD)
+	}
+	if len(report.Periods) != 2 || !report.Periods[1].Start.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected January and February periods, got %+v", report.Periods)
+	}
+	if first := report.Periods[0]; first.Calls != 2 || len(first.ByFeature) != 2 || first.ByFeature[0].Name != "summary" {
+		t.Errorf("expected the costlier feature first in January, got %+v", first.ByFeature)
+	}
+	if len(report.ByModel) != 2 || report.ByModel[0].Name != "openai/m" || report.ByModel[0].Calls != 2 {
+		t.Errorf("unexpected model breakdown %+v", report.ByModel)
+	}
+}
@@ -0,0 +1,206 @@
+package mailin
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// imapTimeout bounds each IMAP command, including fetching a message
+const imapTimeout = 5 * time.Minute
+
+// Mailbox is the inbox emails are read from
+type Mailbox interface {
+	// Unseen returns the UIDs of the messages not yet marked seen
+	Unseen() ([]uint32, error)
+	// Fetch returns a message without marking it seen
+	Fetch(uid uint32) ([]byte, error)
+	MarkSeen(uid uint32) error
+	Close() error
+}
+
+// IMAPConfig is how to reach a mailbox over IMAP
+type IMAPConfig struct {
+	Addr     string // host:port
+	TLS      bool   // Implicit TLS, as on port 993
+	Username string
+	Password string
+	Mailbox  string // Defaults to INBOX
+}
+
+// imapClient speaks just enough IMAP4rev1 to read and flag messages
+type imapClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+	tag  int
+}
+
+// imapResponse is an untagged response line, with the literal it carried, if any
+type imapResponse struct {
+	text    string
+	literal []byte
+}
+
+// Open connects, logs in and selects the mailbox
+func (c IMAPConfig) Open() (Mailbox, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var conn net.Conn
+	var err error
+	if c.TLS {
+		host, _, _ := net.SplitHostPort(c.Addr)
+		conn, err = tls.DialWithDialer(dialer, "tcp", c.Addr, &tls.Config{ServerName: host})
+	} else {
+		conn, err = dialer.Dial("tcp", c.Addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", c.Addr, err)
+	}
+
+	client := &imapClient{conn: conn, r: bufio.NewReader(conn)}
+	conn.SetDeadline(time.Now().Add(imapTimeout))
+	greeting, err := client.readLine()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read greeting: %w", err)
+	}
+	if !strings.HasPrefix(greeting, "* OK") && !strings.HasPrefix(greeting, "* PREAUTH") {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected greeting: %s", greeting)
+	}
+	if !strings.HasPrefix(greeting, "* PREAUTH") {
+		if _, err := client.command("LOGIN " + quote(c.Username) + " " + quote(c.Password)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	mailbox := c.Mailbox
+	if mailbox == "" {
+		mailbox = "INBOX"
+	}
+	if _, err := client.command("SELECT " + quote(mailbox)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *imapClient) Unseen() ([]uint32, error) {
+	responses, err := c.command("UID SEARCH UNSEEN")
+	if err != nil {
+		return nil, err
+	}
+	var uids []uint32
+	for _, resp := range responses {
+		if !strings.HasPrefix(resp.text, "* SEARCH") {
+			continue
+		}
+		for _, field := range strings.Fields(strings.TrimPrefix(resp.text, "* SEARCH")) {
+			if uid, err := strconv.ParseUint(field, 10, 32); err == nil {
+				uids = append(uids, uint32(uid))
+			}
+		}
+	}
+	return uids, nil
+}
+
+func (c *imapClient) Fetch(uid uint32) ([]byte, error) {
+	responses, err := c.command(fmt.Sprintf("UID FETCH %d (BODY.PEEK[])", uid))
+	if err != nil {
+		return nil, err
+	}
+	for _, resp := range responses {
+		if resp.literal != nil && strings.Contains(resp.text, "FETCH") {
+			return resp.literal, nil
+		}
+	}
+	return nil, fmt.Errorf("message %d not found", uid)
+}
+
+func (c *imapClient) MarkSeen(uid uint32) error {
+	_, err := c.command(fmt.Sprintf(`UID STORE %d +FLAGS.SILENT (\Seen)`, uid))
+	return err
+}
+
+func (c *imapClient) Close() error {
+	c.command("LOGOUT")
+	return c.conn.Close()
+}
+
+// command sends a command and reads the untagged responses up to its completion. Failures
+// name only the command, so a LOGIN's password isn't logged.
+func (c *imapClient) command(cmd string) ([]imapResponse, error) {
+	c.tag++
+	tag := "A" + strconv.Itoa(c.tag)
+	name := strings.SplitN(cmd, " ", 2)[0]
+	if name == "UID" {
+		name = strings.Join(strings.Fields(cmd)[:2], " ")
+	}
+	c.conn.SetDeadline(time.Now().Add(imapTimeout))
+	if _, err := io.WriteString(c.conn, tag+" "+cmd+"\r\n"); err != nil {
+		return nil, fmt.Errorf("IMAP %s failed: %w", name, err)
+	}
+
+	var responses []imapResponse
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, fmt.Errorf("IMAP %s failed: %w", name, err)
+		}
+		if strings.HasPrefix(line, tag+" ") {
+			status := strings.TrimPrefix(line, tag+" ")
+			if !strings.HasPrefix(status, "OK") {
+				return nil, fmt.Errorf("IMAP %s failed: %s", name, status)
+			}
+			return responses, nil
+		}
+		resp := imapResponse{text: line}
+		// A literal announced as {size} at the end of a line follows it, then the rest of the response
+		if size, ok := literalSize(line); ok {
+			resp.literal = make([]byte, size)
+			if _, err := io.ReadFull(c.r, resp.literal); err != nil {
+				return nil, fmt.Errorf("IMAP %s failed: %w", name, err)
+			}
+			rest, err := c.readLine()
+			if err != nil {
+				return nil, fmt.Errorf("IMAP %s failed: %w", name, err)
+			}
+			resp.text += rest
+		}
+		responses = append(responses, resp)
+	}
+}
+
+func (c *imapClient) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// literalSize returns the size of the literal a line ends by announcing
+func literalSize(line string) (int, bool) {
+	if !strings.HasSuffix(line, "}") {
+		return 0, false
+	}
+	start := strings.LastIndex(line, "{")
+	if start < 0 {
+		return 0, false
+	}
+	size, err := strconv.Atoi(line[start+1 : len(line)-1])
+	if err != nil || size < 0 {
+		return 0, false
+	}
+	return size, true
+}
+
+// quote makes an IMAP quoted string
+func quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
@@ -0,0 +1,96 @@
+package mailin
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveIMAP answers the commands the client sends with canned responses and returns the
+// commands it received once the client logs out
+func serveIMAP(t *testing.T, message string) (string, <-chan []string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	commands := make(chan []string, 1)
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var received []string
+		defer func() { commands <- received }()
+		r := bufio.NewReader(conn)
+		fmt.Fprint(conn, "* OK IMAP4rev1 ready\r\n")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			tag, cmd, _ := strings.Cut(line, " ")
+			received = append(received, cmd)
+			switch {
+			case strings.HasPrefix(cmd, "LOGIN"):
+				if cmd != `LOGIN "memos@example.com" "p\"ss"` {
+					fmt.Fprintf(conn, "%s NO [AUTHENTICATIONFAILED] Invalid credentials\r\n", tag)
+					continue
+				}
+			case strings.HasPrefix(cmd, "SELECT"):
+				fmt.Fprint(conn, "* 2 EXISTS\r\n* OK [UIDVALIDITY 1] UIDs valid\r\n")
+			case cmd == "UID SEARCH UNSEEN":
+				fmt.Fprint(conn, "* SEARCH 7 9\r\n")
+			case strings.HasPrefix(cmd, "UID FETCH 7"):
+				fmt.Fprintf(conn, "* 1 FETCH (UID 7 BODY[] {%d}\r\n%s)\r\n", len(message), message)
+			case strings.HasPrefix(cmd, "UID FETCH"):
+			case cmd == "LOGOUT":
+				fmt.Fprintf(conn, "* BYE\r\n%s OK LOGOUT completed\r\n", tag)
+				return
+			}
+			fmt.Fprintf(conn, "%s OK done\r\n", tag)
+		}
+	}()
+	return listener.Addr().String(), commands
+}
+
+func TestIMAPClient(t *testing.T) {
+	message := "From: a@example.com\r\nSubject: memo\r\n\r\nhello\r\n"
+	addr, commands := serveIMAP(t, message)
+	mailbox, err := IMAPConfig{Addr: addr, Username: "memos@example.com", Password: `p"ss`}.Open()
+	require.NoError(t, err)
+
+	uids, err := mailbox.Unseen()
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{7, 9}, uids)
+	raw, err := mailbox.Fetch(7)
+	require.NoError(t, err)
+	assert.Equal(t, message, string(raw))
+	_, err = mailbox.Fetch(9)
+	assert.Error(t, err, "a message the server doesn't return")
+	require.NoError(t, mailbox.MarkSeen(7))
+	require.NoError(t, mailbox.Close())
+
+	assert.Equal(t, []string{
+		`LOGIN "memos@example.com" "p\"ss"`,
+		`SELECT "INBOX"`,
+		"UID SEARCH UNSEEN",
+		"UID FETCH 7 (BODY.PEEK[])",
+		"UID FETCH 9 (BODY.PEEK[])",
+		`UID STORE 7 +FLAGS.SILENT (\Seen)`,
+		"LOGOUT",
+	}, <-commands)
+}
+
+func TestIMAPLoginFailureHidesPassword(t *testing.T) {
+	addr, _ := serveIMAP(t, "")
+	_, err := IMAPConfig{Addr: addr, Username: "memos@example.com", Password: "wrong"}.Open()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "IMAP LOGIN failed")
+	assert.NotContains(t, err.Error(), "wrong")
+}
@@ -0,0 +1,181 @@
+package mailin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+)
+
+// maxPartDepth bounds how deeply nested multipart bodies and forwarded emails are read
+const maxPartDepth = 10
+
+// audioExtensions are the file extensions of attachments taken for audio when the mail client
+// didn't label them as audio
+var audioExtensions = map[string]bool{
+	".m4a": true, ".mp3": true, ".wav": true, ".aac": true, ".ogg": true, ".oga": true,
+	".opus": true, ".amr": true, ".3gp": true, ".flac": true, ".webm": true, ".caf": true,
+	".mp4": true, ".mov": true, ".wma": true,
+}
+
+// typeExtensions names unnamed attachments of the common audio types, whatever the system's
+// MIME table says
+var typeExtensions = map[string]string{
+	"audio/mpeg": ".mp3", "audio/mp4": ".m4a", "audio/x-m4a": ".m4a", "audio/aac": ".aac",
+	"audio/wav": ".wav", "audio/x-wav": ".wav", "audio/ogg": ".ogg", "audio/opus": ".opus",
+	"audio/amr": ".amr", "audio/webm": ".webm", "audio/flac": ".flac", "audio/3gpp": ".3gp",
+}
+
+// Message is an email with the audio attachments found in it
+type Message struct {
+	// MessageID identifies the email; for one without a Message-ID it is a hash of the email
+	MessageID string
+	From      string // Address of the sender, in lower case
+	ReplyTo   string // Where replies go: the Reply-To address, or else the sender's
+	Subject   string
+	// Automatic is set for emails sent by a program, such as out-of-office replies, which are
+	// never replied to
+	Automatic   bool
+	Attachments []Attachment
+}
+
+// Attachment is an audio file attached to an email, or to an email forwarded as an attachment
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// ParseMessage reads an email and the audio attachments in it
+func ParseMessage(raw []byte) (*Message, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email: %w", err)
+	}
+	from, err := mail.ParseAddress(msg.Header.Get("From"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid From address: %w", err)
+	}
+	decoder := new(mime.WordDecoder)
+	subject, err := decoder.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+
+	m := &Message{
+		MessageID: strings.TrimSpace(msg.Header.Get("Message-Id")),
+		From:      strings.ToLower(from.Address),
+		Subject:   strings.TrimSpace(subject),
+	}
+	if m.MessageID == "" {
+		sum := sha256.Sum256(raw)
+		m.MessageID = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	autoSubmitted := strings.ToLower(strings.TrimSpace(msg.Header.Get("Auto-Submitted")))
+	precedence := strings.ToLower(strings.TrimSpace(msg.Header.Get("Precedence")))
+	m.Automatic = (autoSubmitted != "" && autoSubmitted != "no") ||
+		precedence == "bulk" || precedence == "junk" || precedence == "auto_reply"
+	m.ReplyTo = m.From
+	if replyTo, err := mail.ParseAddress(msg.Header.Get("Reply-To")); err == nil {
+		m.ReplyTo = replyTo.Address
+	}
+	if err := m.readPart(textproto.MIMEHeader(msg.Header), msg.Body, 0); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// readPart collects the audio attachments in a part of an email, descending into multipart
+// bodies and forwarded emails
+func (m *Message) readPart(header textproto.MIMEHeader, body io.Reader, depth int) error {
+	if depth > maxPartDepth {
+		return nil
+	}
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+	body = decodeTransferEncoding(header.Get("Content-Transfer-Encoding"), body)
+
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read multipart body: %w", err)
+			}
+			if err := m.readPart(part.Header, part, depth+1); err != nil {
+				return err
+			}
+		}
+	case mediaType == "message/rfc822":
+		forwarded, err := mail.ReadMessage(body)
+		if err != nil {
+			return nil // Not worth failing the email over
+		}
+		return m.readPart(textproto.MIMEHeader(forwarded.Header), forwarded.Body, depth+1)
+	}
+
+	filename := attachmentName(header, params)
+	if !strings.HasPrefix(mediaType, "audio/") && !strings.HasPrefix(mediaType, "video/") &&
+		!audioExtensions[strings.ToLower(filepath.Ext(filename))] {
+		return nil
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("failed to read attachment %s: %w", filename, err)
+	}
+	if filename == "" {
+		filename = "voice-memo" + typeExtensions[mediaType]
+		if _, known := typeExtensions[mediaType]; !known {
+			if extensions, _ := mime.ExtensionsByType(mediaType); len(extensions) > 0 {
+				filename += extensions[0]
+			}
+		}
+	}
+	m.Attachments = append(m.Attachments, Attachment{Filename: filename, ContentType: mediaType, Data: data})
+	return nil
+}
+
+// attachmentName returns the file name of a part, from its Content-Disposition or the name
+// parameter of its Content-Type
+func attachmentName(header textproto.MIMEHeader, contentTypeParams map[string]string) string {
+	name := ""
+	if _, params, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil {
+		name = params["filename"]
+	}
+	if name == "" {
+		name = contentTypeParams["name"]
+	}
+	if decoded, err := new(mime.WordDecoder).DecodeHeader(name); err == nil {
+		name = decoded
+	}
+	if name == "" {
+		return ""
+	}
+	return filepath.Base(name)
+}
+
+// decodeTransferEncoding undoes a part's Content-Transfer-Encoding
+func decodeTransferEncoding(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	}
+	return body
+}
@@ -0,0 +1,103 @@
+package mailin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func crlf(s string) []byte {
+	return []byte(strings.ReplaceAll(s, "\n", "\r\n"))
+}
+
+func TestParseMessageAttachments(t *testing.T) {
+	raw := crlf(`From: "Dana Field" <Dana@Field.Example.com>
+Reply-To: team@field.example.com
+To: memos@example.com
+Subject: =?utf-8?q?Site_visit_=E2=80=93_Oslo?=
+Message-ID: <abc@mail.example.com>
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="outer"
+
+--outer
+Content-Type: multipart/alternative; boundary="inner"
+
+--inner
+Content-Type: text/plain
+
+Memo attached.
+--inner
+Content-Type: text/html
+
+<p>Memo attached.</p>
+--inner--
+--outer
+Content-Type: audio/mp4; name="site visit.m4a"
+Content-Disposition: attachment; filename="site visit.m4a"
+Content-Transfer-Encoding: base64
+
+SUQzIGF1
+ZGlv
+--outer
+Content-Type: application/octet-stream
+Content-Disposition: attachment; filename="call.amr"
+
+raw amr
+--outer
+Content-Type: application/pdf
+Content-Disposition: attachment; filename="notes.pdf"
+
+%PDF
+--outer--
+`)
+	msg, err := ParseMessage(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "<abc@mail.example.com>", msg.MessageID)
+	assert.Equal(t, "dana@field.example.com", msg.From)
+	assert.Equal(t, "team@field.example.com", msg.ReplyTo)
+	assert.Equal(t, "Site visit – Oslo", msg.Subject)
+	assert.False(t, msg.Automatic)
+
+	require.Len(t, msg.Attachments, 2, "only audio is taken")
+	assert.Equal(t, "site visit.m4a", msg.Attachments[0].Filename)
+	assert.Equal(t, "audio/mp4", msg.Attachments[0].ContentType)
+	assert.Equal(t, "ID3 audio", string(msg.Attachments[0].Data))
+	assert.Equal(t, "call.amr", msg.Attachments[1].Filename, "recognized by its extension")
+	assert.Equal(t, "raw amr", string(msg.Attachments[1].Data))
+}
+
+func TestParseMessageForwardedAndAutomatic(t *testing.T) {
+	raw := crlf(`From: voicemail@pbx.example.com
+Subject: New voicemail
+Auto-Submitted: auto-generated
+Content-Type: multipart/mixed; boundary="b"
+
+--b
+Content-Type: message/rfc822
+
+From: someone@example.com
+Subject: Fwd
+Content-Type: audio/wav
+
+RIFF
+--b--
+`)
+	msg, err := ParseMessage(raw)
+	require.NoError(t, err)
+	assert.True(t, msg.Automatic)
+	assert.True(t, strings.HasPrefix(msg.MessageID, "sha256:"), "emails without a Message-ID are identified by a hash")
+	assert.Equal(t, msg.From, msg.ReplyTo)
+	require.Len(t, msg.Attachments, 1)
+	assert.Equal(t, "voice-memo.wav", msg.Attachments[0].Filename)
+
+	again, err := ParseMessage(raw)
+	require.NoError(t, err)
+	assert.Equal(t, msg.MessageID, again.MessageID)
+}
+
+func TestParseMessageRequiresSender(t *testing.T) {
+	_, err := ParseMessage(crlf("Subject: hi\n\nbody\n"))
+	assert.Error(t, err)
+}
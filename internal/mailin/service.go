@@ -0,0 +1,377 @@
+// Package mailin turns emailed voice memos into transcriptions: it reads a mailbox over IMAP,
+// hands the audio attachments of each new email over to be transcribed, and replies with the
+// summaries once they are done.
+package mailin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/workflow"
+	"scriberr/pkg/logger"
+)
+
+const (
+	// summaryWait is how long a completed transcription without a workflow run is given to
+	// get one before the reply goes out without a summary
+	summaryWait = 10 * time.Minute
+	// maxTranscriptChars caps the transcript quoted in a reply when there is no summary
+	maxTranscriptChars = 3000
+)
+
+// ImportFunc queues an attachment of an email for transcription under a title, returning
+// the ID of the transcription
+type ImportFunc func(email *models.InboundEmail, attachment Attachment, title string) (string, error)
+
+// CheckResult is what a check of the mailbox did
+type CheckResult struct {
+	Received       int `json:"received"`       // New emails read
+	Transcriptions int `json:"transcriptions"` // Audio attachments queued
+	Replied        int `json:"replied"`
+}
+
+// Service checks the mailbox in the background
+type Service struct {
+	open      func() (Mailbox, error)
+	allowed   []string
+	publicURL string
+	importer  ImportFunc
+	sender    Sender
+
+	mu   sync.Mutex // Checks run one at a time
+	stop chan struct{}
+}
+
+// NewService creates an email-in service reading the mailbox open returns. Emails are only
+// taken from allowedSenders, addresses or @domains, unless there are none. Replies link to
+// the recordings under publicURL, when set.
+func NewService(open func() (Mailbox, error), allowedSenders []string, publicURL string) *Service {
+	var allowed []string
+	for _, sender := range allowedSenders {
+		if sender = strings.ToLower(strings.TrimSpace(sender)); sender != "" {
+			allowed = append(allowed, sender)
+		}
+	}
+	return &Service{open: open, allowed: allowed, publicURL: publicURL}
+}
+
+// SetImporter sets how attachments are queued for transcription
+func (s *Service) SetImporter(importer ImportFunc) {
+	s.importer = importer
+}
+
+// SetSender sets how replies are sent; without one, no replies are sent
+func (s *Service) SetSender(sender Sender) {
+	s.sender = sender
+}
+
+// Start checks the mailbox every interval. An interval of 0 disables checking.
+func (s *Service) Start(interval time.Duration) {
+	s.stop = make(chan struct{})
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := s.Check(context.Background()); err != nil {
+					logger.Error("Failed to check the mailbox", "component", "mailin", "error", err)
+				}
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends checking
+func (s *Service) Stop() {
+	if s.stop != nil {
+		close(s.stop)
+	}
+}
+
+// Check reads the new emails in the mailbox, queues their attachments, and replies to the
+// emails whose transcriptions are done. Replies are sent even when the mailbox can't be read.
+func (s *Service) Check(ctx context.Context) (*CheckResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &CheckResult{}
+	receiveErr := s.receive(result)
+	if err := s.reply(ctx, result); err != nil {
+		return result, errors.Join(receiveErr, err)
+	}
+	return result, receiveErr
+}
+
+// receive reads the unseen emails, marking each seen once handled
+func (s *Service) receive(result *CheckResult) error {
+	mailbox, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer mailbox.Close()
+
+	uids, err := mailbox.Unseen()
+	if err != nil {
+		return err
+	}
+	for _, uid := range uids {
+		raw, err := mailbox.Fetch(uid)
+		if err != nil {
+			return err
+		}
+		if err := s.handle(raw, result); err != nil {
+			logger.Error("Failed to handle email", "component", "mailin", "uid", uid, "error", err)
+		}
+		if err := mailbox.MarkSeen(uid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handle records an email and queues its audio attachments
+func (s *Service) handle(raw []byte, result *CheckResult) error {
+	msg, err := ParseMessage(raw)
+	if err != nil {
+		return err
+	}
+	if !s.isAllowed(msg.From) {
+		logger.Info("Ignoring email from a sender who isn't allowed", "component", "mailin", "from", msg.From)
+		return nil
+	}
+	var count int64
+	if err := database.DB.Model(&models.InboundEmail{}).Where("message_id = ?", msg.MessageID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil // Already read, such as before it was marked unread again
+	}
+
+	email := models.InboundEmail{
+		MessageID:        msg.MessageID,
+		From:             msg.From,
+		ReplyTo:          msg.ReplyTo,
+		Subject:          msg.Subject,
+		Automatic:        msg.Automatic,
+		TranscriptionIDs: []string{},
+		Status:           models.InboundEmailProcessing,
+	}
+	if err := database.DB.Create(&email).Error; err != nil {
+		return fmt.Errorf("failed to save email: %w", err)
+	}
+	result.Received++
+
+	var failures []string
+	for _, attachment := range msg.Attachments {
+		if s.importer == nil {
+			break
+		}
+		id, err := s.importer(&email, attachment, attachmentTitle(msg, attachment))
+		if err != nil {
+			logger.Error("Failed to queue email attachment", "component", "mailin", "filename", attachment.Filename, "from", msg.From, "error", err)
+			failures = append(failures, fmt.Sprintf("%s: %v", attachment.Filename, err))
+			continue
+		}
+		email.TranscriptionIDs = append(email.TranscriptionIDs, id)
+		result.Transcriptions++
+	}
+	if len(failures) > 0 {
+		message := strings.Join(failures, "; ")
+		email.Error = &message
+	}
+	return database.DB.Model(&email).Select("transcription_ids", "error").Updates(&email).Error
+}
+
+// attachmentTitle names an attachment's transcription after the email's subject, and after
+// the file too when the email has several
+func attachmentTitle(msg *Message, attachment Attachment) string {
+	name := strings.TrimSuffix(attachment.Filename, filepath.Ext(attachment.Filename))
+	switch {
+	case msg.Subject == "":
+		return name
+	case len(msg.Attachments) == 1:
+		return msg.Subject
+	default:
+		return msg.Subject + ": " + name
+	}
+}
+
+// isAllowed reports whether emails from an address are taken
+func (s *Service) isAllowed(address string) bool {
+	if len(s.allowed) == 0 {
+		return true
+	}
+	for _, allowed := range s.allowed {
+		if address == allowed || (strings.HasPrefix(allowed, "@") && strings.HasSuffix(address, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// reply answers the emails whose transcriptions are done
+func (s *Service) reply(ctx context.Context, result *CheckResult) error {
+	var emails []models.InboundEmail
+	if err := database.DB.Where("status = ?", models.InboundEmailProcessing).Order("created_at ASC").Find(&emails).Error; err != nil {
+		return fmt.Errorf("failed to list emails: %w", err)
+	}
+	for i := range emails {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		email := &emails[i]
+		body, done, err := s.composeReply(email)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to compose email reply", "component", "mailin", "email_id", email.ID, "error", err)
+			continue
+		}
+		if !done {
+			continue
+		}
+
+		updates := map[string]interface{}{"status": models.InboundEmailCompleted}
+		if s.sender != nil && !email.Automatic {
+			reply := Reply{To: email.ReplyTo, Subject: replySubject(email.Subject), Body: body}
+			if strings.Contains(email.MessageID, "@") {
+				reply.InReplyTo = email.MessageID
+			}
+			if err := s.sender.Send(reply); err != nil {
+				logger.ErrorContext(ctx, "Failed to send email reply", "component", "mailin", "email_id", email.ID, "error", err)
+				updates = map[string]interface{}{"status": models.InboundEmailFailed, "error": err.Error()}
+			} else {
+				updates = map[string]interface{}{"status": models.InboundEmailReplied, "replied_at": time.Now()}
+				result.Replied++
+			}
+		}
+		if err := database.DB.Model(email).Updates(updates).Error; err != nil {
+			logger.ErrorContext(ctx, "Failed to save email reply", "component", "mailin", "email_id", email.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// composeReply writes the reply to an email, or reports that its transcriptions aren't done
+// yet. A transcription is done when it failed, or when it completed and its post-processing
+// finished.
+func (s *Service) composeReply(email *models.InboundEmail) (string, bool, error) {
+	var body strings.Builder
+	if len(email.TranscriptionIDs) == 0 {
+		body.WriteString("No voice memos could be transcribed from your email.")
+		if email.Error != nil {
+			fmt.Fprintf(&body, " These attachments couldn't be queued: %s.", *email.Error)
+		} else {
+			body.WriteString(" Attach them as audio files, such as .m4a, .mp3 or .wav.")
+		}
+		body.WriteString("\n")
+		return body.String(), true, nil
+	}
+
+	var jobs []models.TranscriptionJob
+	if err := database.DB.Where("id IN ?", email.TranscriptionIDs).Find(&jobs).Error; err != nil {
+		return "", false, err
+	}
+	byID := make(map[string]*models.TranscriptionJob, len(jobs))
+	for i := range jobs {
+		byID[jobs[i].ID] = &jobs[i]
+	}
+
+	var sections []string
+	for _, id := range email.TranscriptionIDs {
+		job, found := byID[id]
+		if !found {
+			sections = append(sections, "A voice memo was deleted before it was transcribed.")
+			continue
+		}
+		title := "Voice memo"
+		if job.Title != nil && *job.Title != "" {
+			title = *job.Title
+		}
+		switch job.Status {
+		case models.StatusFailed:
+			reason := "unknown error"
+			if job.ErrorMessage != nil {
+				reason = *job.ErrorMessage
+			}
+			sections = append(sections, fmt.Sprintf("== %s ==\nTranscription failed: %s", title, reason))
+		case models.StatusCompleted:
+			settled, err := postProcessingSettled(job)
+			if err != nil {
+				return "", false, err
+			}
+			if !settled {
+				return "", false, nil
+			}
+			sections = append(sections, s.section(title, job))
+		default:
+			return "", false, nil
+		}
+	}
+	if email.Error != nil {
+		sections = append(sections, "These attachments couldn't be queued: "+*email.Error)
+	}
+	return strings.Join(sections, "\n\n") + "\n", true, nil
+}
+
+// section describes a completed transcription: its summary, or the start of its transcript
+// when it has none, and a link to it
+func (s *Service) section(title string, job *models.TranscriptionJob) string {
+	var section strings.Builder
+	fmt.Fprintf(&section, "== %s ==\n", title)
+	if job.Summary != nil && strings.TrimSpace(*job.Summary) != "" {
+		fmt.Fprintf(&section, "Summary:\n%s\n", strings.TrimSpace(*job.Summary))
+	} else if text, err := workflow.TranscriptText(job); err == nil {
+		text = strings.TrimSpace(text)
+		if runes := []rune(text); len(runes) > maxTranscriptChars {
+			text = string(runes[:maxTranscriptChars]) + "…"
+		}
+		fmt.Fprintf(&section, "Transcript:\n%s\n", text)
+	}
+	if link := workflow.RecordingURL(s.publicURL, job.ID, nil); link != "" {
+		fmt.Fprintf(&section, "\nOpen it: %s\n", link)
+	}
+	return strings.TrimRight(section.String(), "\n")
+}
+
+// postProcessingSettled reports whether a completed transcription's post-processing is done:
+// it has a workflow run and none is still going, or it has a summary, or it has gone
+// summaryWait without a run starting
+func postProcessingSettled(job *models.TranscriptionJob) (bool, error) {
+	var active, total int64
+	if err := database.DB.Model(&models.WorkflowRun{}).Where("transcription_id = ?", job.ID).Count(&total).Error; err != nil {
+		return false, err
+	}
+	if err := database.DB.Model(&models.WorkflowRun{}).
+		Where("transcription_id = ? AND status IN ?", job.ID, []models.WorkflowStatus{models.WorkflowPending, models.WorkflowRunning}).
+		Count(&active).Error; err != nil {
+		return false, err
+	}
+	if active > 0 {
+		return false, nil
+	}
+	return total > 0 || job.Summary != nil || time.Since(job.UpdatedAt) > summaryWait, nil
+}
+
+// replySubject prefixes a subject with Re: unless it already is
+func replySubject(subject string) string {
+	if subject == "" {
+		return "Re: your voice memo"
+	}
+	if strings.HasPrefix(strings.ToLower(subject), "re:") {
+		return subject
+	}
+	return "Re: " + subject
+}
@@ -0,0 +1,75 @@
+package mailin
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reply is a reply to an email
+type Reply struct {
+	To        string
+	Subject   string
+	InReplyTo string // Message-ID of the email replied to, if it had one
+	Body      string // Plain text
+}
+
+// Sender sends replies
+type Sender interface {
+	Send(reply Reply) error
+}
+
+// SMTPSender sends replies through an SMTP server, upgrading to TLS when the server offers it
+type SMTPSender struct {
+	Addr     string // host:port
+	Username string // No authentication when empty
+	Password string
+	From     string
+}
+
+// Send sends a reply
+func (s *SMTPSender) Send(reply Reply) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		host, _, _ := net.SplitHostPort(s.Addr)
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+	if err := smtp.SendMail(s.Addr, auth, s.From, []string{reply.To}, s.compose(reply)); err != nil {
+		return fmt.Errorf("failed to send reply to %s: %w", reply.To, err)
+	}
+	return nil
+}
+
+// compose renders a reply as a plain text email
+func (s *SMTPSender) compose(reply Reply) []byte {
+	var msg bytes.Buffer
+	domain := "localhost"
+	if at := strings.LastIndex(s.From, "@"); at >= 0 {
+		domain = s.From[at+1:]
+	}
+	fmt.Fprintf(&msg, "From: %s\r\n", s.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", reply.To)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", reply.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: <%s@%s>\r\n", uuid.New().String(), domain)
+	if reply.InReplyTo != "" {
+		fmt.Fprintf(&msg, "In-Reply-To: %s\r\n", reply.InReplyTo)
+		fmt.Fprintf(&msg, "References: %s\r\n", reply.InReplyTo)
+	}
+	// Marks the reply as automatic, so out-of-office replies aren't sent back
+	msg.WriteString("Auto-Submitted: auto-replied\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	body := quotedprintable.NewWriter(&msg)
+	body.Write([]byte(strings.ReplaceAll(reply.Body, "\n", "\r\n")))
+	body.Close()
+	return msg.Bytes()
+}
@@ -0,0 +1,230 @@
+// Package mcp serves tools over the Model Context Protocol, so MCP clients such as Claude
+// Desktop can call them. It implements the protocol's JSON-RPC messages; the transport is up
+// to the caller.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// LatestProtocolVersion is the newest protocol revision the server speaks
+const LatestProtocolVersion = "2025-06-18"
+
+// protocolVersions are the revisions the server speaks; it answers in the client's if it can
+var protocolVersions = map[string]bool{
+	"2025-06-18": true,
+	"2025-03-26": true,
+	"2024-11-05": true,
+}
+
+// JSON-RPC error codes
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+)
+
+// Request is a JSON-RPC request, or a notification when it has no ID
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// IsNotification reports whether the request expects no response
+func (r *Request) IsNotification() bool {
+	return len(r.ID) == 0
+}
+
+// Response is a JSON-RPC response, with either a result or an error
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id" swaggertype:"string"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// ErrorResponse answers the request with the given ID, null if unknown, with an error
+func ErrorResponse(id json.RawMessage, code int, message string) *Response {
+	return &Response{JSONRPC: "2.0", ID: id, Error: &Error{Code: code, Message: message}}
+}
+
+// ParseRequest decodes a JSON-RPC request, returning the error response to send if it isn't one
+func ParseRequest(data []byte) (*Request, *Response) {
+	var req Request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, ErrorResponse(nil, CodeParseError, "Invalid JSON-RPC message: "+err.Error())
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return nil, ErrorResponse(req.ID, CodeInvalidRequest, "Not a JSON-RPC 2.0 request")
+	}
+	return &req, nil
+}
+
+// Tool is a function MCP clients can call
+type Tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+	Call        ToolFunc               `json:"-"`
+}
+
+// ToolFunc runs a tool with its arguments as sent, a JSON object
+type ToolFunc func(ctx context.Context, args json.RawMessage) (*ToolResult, error)
+
+// ObjectSchema is the JSON schema of a tool's arguments, with the given properties
+func ObjectSchema(properties map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// Content is a block of a tool's result
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// ToolResult is what a tool call returns. Failures the model can act on, like an unknown ID,
+// are results with IsError set rather than JSON-RPC errors.
+type ToolResult struct {
+	Content           []Content   `json:"content"`
+	StructuredContent interface{} `json:"structuredContent,omitempty"`
+	IsError           bool        `json:"isError,omitempty"`
+}
+
+// TextResult is a tool result of plain text
+func TextResult(text string) *ToolResult {
+	return &ToolResult{Content: []Content{{Type: "text", Text: text}}}
+}
+
+// JSONResult is a tool result of a value, as JSON text for older clients and as structured content
+func JSONResult(value interface{}) (*ToolResult, error) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	result := TextResult(string(data))
+	result.StructuredContent = value
+	return result, nil
+}
+
+// ErrorResult is a failed tool call
+func ErrorResult(message string) *ToolResult {
+	result := TextResult(message)
+	result.IsError = true
+	return result
+}
+
+// Server answers MCP requests with its tools
+type Server struct {
+	name         string
+	version      string
+	instructions string
+	tools        []Tool
+}
+
+// NewServer creates a server introducing itself with name and version. Instructions tell the
+// client's model what the tools are for.
+func NewServer(name, version, instructions string, tools []Tool) *Server {
+	return &Server{name: name, version: version, instructions: instructions, tools: tools}
+}
+
+// Handle answers a request, or returns nil for notifications
+func (s *Server) Handle(ctx context.Context, req *Request) *Response {
+	result, rpcErr := s.dispatch(ctx, req)
+	if req.IsNotification() {
+		return nil
+	}
+	if rpcErr != nil {
+		return &Response{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+	return &Response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func (s *Server) dispatch(ctx context.Context, req *Request) (interface{}, *Error) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		version := LatestProtocolVersion
+		if protocolVersions[params.ProtocolVersion] {
+			version = params.ProtocolVersion
+		}
+		result := map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{"listChanged": false}},
+			"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+		}
+		if s.instructions != "" {
+			result["instructions"] = s.instructions
+		}
+		return result, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": s.tools}, nil
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		tool := s.tool(params.Name)
+		if tool == nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: fmt.Sprintf("Unknown tool: %s", params.Name)}
+		}
+		if len(params.Arguments) == 0 || string(params.Arguments) == "null" {
+			params.Arguments = json.RawMessage("{}")
+		}
+		result, err := tool.Call(ctx, params.Arguments)
+		if err != nil {
+			return ErrorResult(err.Error()), nil
+		}
+		return result, nil
+	}
+	if req.IsNotification() {
+		// notifications/initialized, notifications/cancelled and the like need no action
+		return nil, nil
+	}
+	return nil, &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("Method not found: %s", req.Method)}
+}
+
+// tool finds a tool by name
+func (s *Server) tool(name string) *Tool {
+	for i := range s.tools {
+		if s.tools[i].Name == name {
+			return &s.tools[i]
+		}
+	}
+	return nil
+}
+
+// decodeParams decodes a request's params, which may be left out
+func decodeParams(params json.RawMessage, v interface{}) *Error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &Error{Code: CodeInvalidParams, Message: "Invalid params: " + err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func testServer() *Server {
+	return NewServer("scriberr", "1.0.0", "Search transcripts.", []Tool{
+		{
+			Name:        "echo",
+			Description: "Echo the text",
+			InputSchema: ObjectSchema(map[string]interface{}{"text": map[string]interface{}{"type": "string"}}, "text"),
+			Call: func(ctx context.Context, args json.RawMessage) (*ToolResult, error) {
+				var params struct {
+					Text string `json:"text"`
+				}
+				if err := json.Unmarshal(args, &params); err != nil {
+					return nil, err
+				}
+				if params.Text == "" {
+					return nil, errors.New("text is required")
+				}
+				return TextResult(params.Text), nil
+			},
+		},
+	})
+}
+
+// call handles a raw message and returns the response as JSON, or "" for none
+func call(t *testing.T, server *Server, message string) string {
+	t.Helper()
+	req, errResp := ParseRequest([]byte(message))
+	resp := errResp
+	if req != nil {
+		resp = server.Handle(context.Background(), req)
+	}
+	if resp == nil {
+		return ""
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestHandle(t *testing.T) {
+	server := testServer()
+	tests := []struct {
+		name, message, want string
+	}{
+		{
+			"initialize in the client's version",
+			`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{}}}`,
+			`{"jsonrpc":"2.0","id":1,"result":{"capabilities":{"tools":{"listChanged":false}},"instructions":"Search transcripts.","protocolVersion":"2025-03-26","serverInfo":{"name":"scriberr","version":"1.0.0"}}}`,
+		},
+		{
+			"initialize in an unknown version",
+			`{"jsonrpc":"2.0","id":"a","method":"initialize","params":{"protocolVersion":"2099-01-01"}}`,
+			`{"jsonrpc":"2.0","id":"a","result":{"capabilities":{"tools":{"listChanged":false}},"instructions":"Search transcripts.","protocolVersion":"2025-06-18","serverInfo":{"name":"scriberr","version":"1.0.0"}}}`,
+		},
+		{
+			"notification",
+			`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+			``,
+		},
+		{
+			"ping",
+			`{"jsonrpc":"2.0","id":2,"method":"ping"}`,
+			`{"jsonrpc":"2.0","id":2,"result":{}}`,
+		},
+		{
+			"list tools",
+			`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`,
+			`{"jsonrpc":"2.0","id":3,"result":{"tools":[{"name":"echo","description":"Echo the text","inputSchema":{"properties":{"text":{"type":"string"}},"required":["text"],"type":"object"}}]}}`,
+		},
+		{
+			"call a tool",
+			`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hello"}}}`,
+			`{"jsonrpc":"2.0","id":4,"result":{"content":[{"type":"text","text":"hello"}]}}`,
+		},
+		{
+			"failed tool call",
+			`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"echo"}}`,
+			`{"jsonrpc":"2.0","id":5,"result":{"content":[{"type":"text","text":"text is required"}],"isError":true}}`,
+		},
+		{
+			"unknown tool",
+			`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"delete_everything"}}`,
+			`{"jsonrpc":"2.0","id":6,"error":{"code":-32602,"message":"Unknown tool: delete_everything"}}`,
+		},
+		{
+			"unknown method",
+			`{"jsonrpc":"2.0","id":7,"method":"resources/list"}`,
+			`{"jsonrpc":"2.0","id":7,"error":{"code":-32601,"message":"Method not found: resources/list"}}`,
+		},
+		{
+			"not JSON-RPC 2.0",
+			`{"id":8,"method":"ping"}`,
+			`{"jsonrpc":"2.0","id":8,"error":{"code":-32600,"message":"Not a JSON-RPC 2.0 request"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := call(t, server, tt.message); got != tt.want {
+				t.Errorf("got %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+
+	if got := call(t, server, `{"jsonrpc":`); got == "" || !json.Valid([]byte(got)) {
+		t.Fatalf("expected a parse error response, got %q", got)
+	}
+	var resp Response
+	if err := json.Unmarshal([]byte(call(t, server, `not json`)), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error == nil || resp.Error.Code != CodeParseError || string(resp.ID) != "null" {
+		t.Errorf("expected a parse error for a null ID, got %+v", resp)
+	}
+}
+
+func TestJSONResult(t *testing.T) {
+	result, err := JSONResult(map[string]int{"count": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Content[0].Text != "{\n  \"count\": 2\n}" {
+		t.Errorf("unexpected text %q", result.Content[0].Text)
+	}
+	if result.StructuredContent == nil || result.IsError {
+		t.Errorf("expected structured content, got %+v", result)
+	}
+}
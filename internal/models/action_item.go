@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ActionItem is a follow-up task extracted from a transcription by the extract_action_items step
+type ActionItem struct {
+	ID              string     `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TranscriptionID string     `json:"transcription_id" gorm:"type:varchar(36);not null;index"`
+	UserID          *uint      `json:"user_id,omitempty" gorm:"index"` // Owner of the transcription
+	Task            string     `json:"task" gorm:"type:text;not null"`
+	Owner           string     `json:"owner,omitempty" gorm:"type:varchar(255)"`
+	Due             string     `json:"due,omitempty" gorm:"type:varchar(255)"` // As mentioned in the recording, e.g. "next Friday"
+	SourceTime      *float64   `json:"source_time,omitempty"`                  // Seconds into the recording where it was mentioned
+	Completed       bool       `json:"completed" gorm:"not null;default:false"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	Model           string     `json:"model,omitempty" gorm:"type:varchar(255)"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (a *ActionItem) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Audit log actions
+const (
+	AuditLogin           = "auth.login"
+	AuditLogout          = "auth.logout"
+	AuditRegister        = "auth.register"
+	AuditPasswordChange  = "auth.password_changed"
+	AuditUsernameChange  = "auth.username_changed"
+	AuditAPIKeyCreate    = "api_key.created"
+	AuditAPIKeyRevoke    = "api_key.revoked"
+	AuditUserCreate      = "user.created"
+	AuditUserUpdate      = "user.updated"
+	AuditUserDelete      = "user.deleted"
+	AuditUpload          = "transcription.uploaded"
+	AuditDelete          = "transcription.deleted"
+	AuditAudioDelete     = "transcription.audio_deleted"
+	AuditExport          = "transcription.exported"
+	AuditShareCreate     = "share.created"
+	AuditShareRevoke     = "share.revoked"
+	AuditShareView       = "share.viewed"
+	AuditDownloadCreate  = "download.created"
+	AuditDownload        = "download.used"
+	AuditQuery           = "content.queried"
+	AuditLegalHoldChange = "legal_hold.changed"
+	// AuditChange is recorded for every other request that changes data
+	AuditChange = "data.changed"
+)
+
+// AuditLog is an entry in the audit log: who did what to which record, when and from where,
+// and how the server answered. Entries are only added, and removed once past the retention period.
+type AuditLog struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	UserID   *uint  `json:"user_id,omitempty" gorm:"index"`
+	Username string `json:"username,omitempty" gorm:"type:varchar(50)"`  // Kept so entries stay readable after the user is deleted
+	AuthType string `json:"auth_type,omitempty" gorm:"type:varchar(20)"` // jwt, api_key or signed_url; empty for public requests
+	APIKeyID *uint  `json:"api_key_id,omitempty"`
+	Action   string `json:"action" gorm:"type:varchar(64);not null;index"`
+	Method   string `json:"method" gorm:"type:varchar(10);not null"`
+	Route    string `json:"route" gorm:"type:text;not null"`
+	// TargetID is the record the request was about, such as the transcription uploaded or deleted
+	TargetID  string                 `json:"target_id,omitempty" gorm:"type:varchar(64);index"`
+	Status    int                    `json:"status"`
+	IPAddress string                 `json:"ip_address" gorm:"type:varchar(64)"`
+	UserAgent string                 `json:"user_agent,omitempty" gorm:"type:text"`
+	Details   map[string]interface{} `json:"details,omitempty" gorm:"type:text;serializer:json"`
+	CreatedAt time.Time              `json:"created_at" gorm:"autoCreateTime;index"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Statuses of a batch operation
+const (
+	BatchRunning   = "running"
+	BatchCompleted = "completed"
+	BatchCancelled = "cancelled"
+	BatchFailed    = "failed" // Stopped before it got through its transcriptions, e.g. by a restart
+)
+
+// Outcomes of a batch operation for one transcription
+const (
+	BatchItemSucceeded = "succeeded"
+	BatchItemFailed    = "failed"
+	BatchItemSkipped   = "skipped" // Left alone, e.g. under legal hold
+)
+
+// BatchParams are the settings of a batch operation's action. Each action reads its own.
+type BatchParams struct {
+	// Tags replaces the tags of every transcription (retag)
+	Tags []string `json:"tags,omitempty"`
+	// AddTags and RemoveTags change tags, keeping the others (retag)
+	AddTags    []string `json:"add_tags,omitempty"`
+	RemoveTags []string `json:"remove_tags,omitempty"`
+	// ProjectID is the project transcriptions are moved to; nil takes them out of theirs (move)
+	ProjectID *string `json:"project_id,omitempty"`
+	// Model, Format and TemplateID override the summary defaults (resummarize)
+	Model      string  `json:"model,omitempty"`
+	Format     string  `json:"format,omitempty"`
+	TemplateID *string `json:"template_id,omitempty"`
+}
+
+// BatchOperation is an action, such as retagging or deleting, applied to many transcriptions
+// in the background, with the outcome for each
+type BatchOperation struct {
+	ID     string      `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID *uint       `json:"user_id,omitempty" gorm:"index"` // Who started it; nil for API keys without a user
+	Action string      `json:"action" gorm:"type:varchar(32);not null"`
+	Params BatchParams `json:"params" gorm:"type:text;serializer:json"`
+	Status string      `json:"status" gorm:"type:varchar(20);not null;index"`
+	// Total is how many transcriptions were selected, including those not found
+	Total     int               `json:"total"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Skipped   int               `json:"skipped"`
+	Results   []BatchItemResult `json:"results,omitempty" gorm:"type:text;serializer:json"`
+	Error     *string           `json:"error,omitempty" gorm:"type:text"`
+	StartedAt time.Time         `json:"started_at"`
+	// CompletedAt is set once the operation stops, whether it finished or not
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime;index"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BatchItemResult is the outcome of a batch operation for one transcription
+type BatchItemResult struct {
+	TranscriptionID string `json:"transcription_id"`
+	Status          string `json:"status"`
+	// Message says why the transcription failed or was skipped
+	Message string `json:"message,omitempty"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (b *BatchOperation) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == "" {
+		b.ID = uuid.New().String()
+	}
+	return nil
+}
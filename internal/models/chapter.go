@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Chapter is a section of a recording about one topic, found by the generate_chapters
+// workflow step. A recording's chapters follow each other without gaps, in Position order.
+type Chapter struct {
+	ID              string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TranscriptionID string    `json:"transcription_id" gorm:"type:varchar(36);not null;index"`
+	UserID          *uint     `json:"user_id,omitempty" gorm:"index"` // Owner of the transcription
+	Position        int       `json:"position"`                       // 0 for the first chapter
+	Title           string    `json:"title" gorm:"type:varchar(255);not null"`
+	Summary         string    `json:"summary,omitempty" gorm:"type:text"`
+	Start           float64   `json:"start"` // Seconds into the recording
+	End             float64   `json:"end"`
+	Model           string    `json:"model,omitempty" gorm:"type:varchar(255)"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (c *Chapter) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
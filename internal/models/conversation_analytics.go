@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Sentiment labels of transcript segments
+const (
+	SentimentPositive = "positive"
+	SentimentNeutral  = "neutral"
+	SentimentNegative = "negative"
+)
+
+// ConversationAnalytics holds the per-speaker metrics and segment sentiment computed for a
+// transcription by the speaker_analytics step
+type ConversationAnalytics struct {
+	TranscriptionID string             `json:"transcription_id" gorm:"primaryKey;type:varchar(36)"`
+	UserID          *uint              `json:"user_id,omitempty" gorm:"index"` // Owner of the transcription
+	Duration        float64            `json:"duration"`                       // Seconds from the first segment's start to the last one's end
+	Speakers        []SpeakerAnalytics `json:"speakers" gorm:"type:text;serializer:json"`
+	Segments        []SegmentSentiment `json:"segments" gorm:"type:text;serializer:json"`
+	Model           string             `json:"model,omitempty" gorm:"type:varchar(255)"` // Model that scored the sentiment
+	CreatedAt       time.Time          `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time          `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// SpeakerAnalytics is how one speaker took part in a conversation
+type SpeakerAnalytics struct {
+	Speaker        string   `json:"speaker"`         // Mapped name, else the diarization label
+	Label          string   `json:"label,omitempty"` // Diarization label, e.g. SPEAKER_00
+	TalkTime       float64  `json:"talk_time"`       // Seconds
+	TalkShare      float64  `json:"talk_share"`      // Fraction of all talk time, 0 to 1
+	Segments       int      `json:"segments"`
+	Turns          int      `json:"turns"` // Runs of consecutive segments
+	LongestTurn    float64  `json:"longest_turn"`
+	Words          int      `json:"words"`
+	WordsPerMinute float64  `json:"words_per_minute"`
+	Interruptions  int      `json:"interruptions"`       // Times they started talking over someone else
+	Interrupted    int      `json:"interrupted"`         // Times someone else started talking over them
+	Sentiment      *float64 `json:"sentiment,omitempty"` // Mean segment score, -1 to 1
+}
+
+// SegmentSentiment is the sentiment of one transcript segment
+type SegmentSentiment struct {
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Speaker string  `json:"speaker,omitempty"`
+	Score   float64 `json:"score"` // -1 (negative) to 1 (positive)
+	Label   string  `json:"label"`
+}
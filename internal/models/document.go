@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DocumentStatus represents the indexing status of an uploaded document
+type DocumentStatus string
+
+const (
+	DocumentProcessing DocumentStatus = "processing"
+	DocumentIndexed    DocumentStatus = "indexed"
+	DocumentFailed     DocumentStatus = "failed"
+)
+
+// Document is an uploaded text document (agenda, notes, PDF) indexed for RAG,
+// either standalone or linked to a recording
+type Document struct {
+	ID              string         `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID          *uint          `json:"user_id,omitempty" gorm:"index"`
+	TranscriptionID *string        `json:"transcription_id,omitempty" gorm:"type:varchar(36);index"` // Linked recording, if any
+	Title           string         `json:"title" gorm:"type:text;not null"`
+	FileName        string         `json:"file_name" gorm:"type:text;not null"`
+	ContentType     string         `json:"content_type" gorm:"type:varchar(100)"`
+	FilePath        string         `json:"-" gorm:"type:text;not null"`
+	Size            int64          `json:"size"`
+	Content         *string        `json:"content,omitempty" gorm:"type:text"` // Extracted text
+	Summary         *string        `json:"summary,omitempty" gorm:"type:text"`
+	ChunkCount      int            `json:"chunk_count"`
+	Status          DocumentStatus `json:"status" gorm:"type:varchar(20);not null;default:'processing'"`
+	Error           *string        `json:"error,omitempty" gorm:"type:text"`
+	IndexedAt       *time.Time     `json:"indexed_at,omitempty"`
+	CreatedAt       time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (d *Document) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	return nil
+}
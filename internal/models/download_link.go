@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DownloadLink is a signed, expiring URL that downloads audio or an export without API
+// credentials, so it can be handed to a browser or media player. Only a hash of its token
+// is stored.
+type DownloadLink struct {
+	ID        string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TokenHash string `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	UserID    *uint  `json:"user_id,omitempty" gorm:"index"` // The link downloads as this user
+	// Path and Query are the API request the link stands for, e.g. /api/v1/transcription/ID/audio
+	Path      string     `json:"path" gorm:"type:varchar(2048);not null"`
+	Query     string     `json:"query,omitempty" gorm:"type:text"`
+	OneTime   bool       `json:"one_time"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null;index"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	// Scanned is set when the transcript was scanned for sensitive passages before signing;
+	// ConfirmedPassages are the flagged passages the creator confirmed could be shared
+	Scanned           bool      `json:"scanned" gorm:"not null;default:false"`
+	ConfirmedPassages []string  `json:"confirmed_passages,omitempty" gorm:"type:text;serializer:json"`
+	CreatedAt         time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (l *DownloadLink) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == "" {
+		l.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Statuses of a pair of transcriptions flagged as near-duplicates
+const (
+	DuplicatePending = "pending"
+	DuplicateIgnored = "ignored" // Not duplicates; never flagged again
+	DuplicateMerged  = "merged"  // One was kept and the other taken out of RAG or deleted
+)
+
+// DuplicateCandidate is a pair of transcriptions in one RAG collection whose whole-transcription
+// embeddings are nearly the same, such as one meeting recorded by two people, waiting to be
+// merged or ignored. FirstID is the transcription created first.
+type DuplicateCandidate struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	Collection string     `json:"-" gorm:"type:varchar(255);not null;index"`
+	FirstID    string     `json:"first_id" gorm:"type:varchar(36);not null;uniqueIndex:idx_duplicate_pair"`
+	SecondID   string     `json:"second_id" gorm:"type:varchar(36);not null;uniqueIndex:idx_duplicate_pair;index"`
+	Similarity float64    `json:"similarity"` // Cosine similarity of the embeddings
+	Overlap    float64    `json:"overlap"`    // Share of the shorter text's word sequences found in the other
+	Status     string     `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
+	KeptID     *string    `json:"kept_id,omitempty" gorm:"type:varchar(36)"` // Transcription a merge kept; the other is marked its duplicate
+	ResolvedBy *uint      `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
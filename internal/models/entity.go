@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Entity types extracted from transcripts
+const (
+	EntityPerson       = "person"
+	EntityOrganization = "organization"
+	EntityProduct      = "product"
+	EntityLocation     = "location"
+)
+
+// EntityTypes lists the entity types in the order they are presented
+var EntityTypes = []string{EntityPerson, EntityOrganization, EntityProduct, EntityLocation}
+
+// EntityMention is one mention of a named entity in a transcription, found by the
+// extract_entities step. Mentions of the same entity share its type and name.
+type EntityMention struct {
+	ID              string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TranscriptionID string    `json:"transcription_id" gorm:"type:varchar(36);not null;index"`
+	UserID          *uint     `json:"user_id,omitempty" gorm:"index"` // Owner of the transcription
+	Type            string    `json:"type" gorm:"type:varchar(32);not null;index:idx_entity_mentions_entity"`
+	Name            string    `json:"name" gorm:"type:varchar(255);not null;index:idx_entity_mentions_entity"`
+	SourceTime      *float64  `json:"source_time,omitempty"` // Seconds into the recording; nil if the transcript has no timings
+	Model           string    `json:"model,omitempty" gorm:"type:varchar(255)"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (m *EntityMention) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// Event types recorded in the outbox
+const (
+	EventJobCreated   = "job.created"
+	EventJobStarted   = "job.started"
+	EventJobCompleted = "job.completed"
+	EventJobFailed    = "job.failed"
+	EventSummaryReady = "summary.ready"
+	EventIndexUpdated = "index.updated"
+	// EventJobProgress is recorded at each stage of a transcription, with a rough percentage
+	EventJobProgress = "job.progress"
+	// EventWatchlistMatched is recorded once per watchlist with matches in a new transcript
+	EventWatchlistMatched = "watchlist.matched"
+	// EventWorkflowStepFinished is recorded each time a post-processing step completes, fails or skips
+	EventWorkflowStepFinished = "workflow.step_finished"
+	// EventWorkflowFinished is recorded when a post-processing run ends
+	EventWorkflowFinished = "workflow.finished"
+	// EventWorkflowStepDeadLettered is recorded when a workflow step fails for the last time
+	EventWorkflowStepDeadLettered = "workflow.step_dead_lettered"
+	// Deletions, so clients keeping a copy in sync learn what to drop
+	EventJobDeleted     = "job.deleted"
+	EventSummaryDeleted = "summary.deleted"
+	EventTagDeleted     = "tag.deleted"
+	// Legal hold changes double as the audit trail of holds
+	EventLegalHoldPlaced   = "legal_hold.placed"
+	EventLegalHoldReleased = "legal_hold.released"
+	// EventJobStuck is recorded when the watchdog finds a transcription that stopped making progress
+	EventJobStuck = "job.stuck"
+	// EventTranscriptEdited is recorded for each correction made to a transcript segment
+	EventTranscriptEdited = "transcript.edited"
+)
+
+// Event is an entry in the append-only outbox read by integrations. Sequence increases
+// monotonically and serves as the polling cursor.
+type Event struct {
+	Sequence  uint                   `json:"sequence" gorm:"primaryKey;autoIncrement"`
+	Type      string                 `json:"type" gorm:"type:varchar(64);not null;index"`
+	SubjectID string                 `json:"subject_id" gorm:"type:varchar(64);index"` // Transcription or document the event is about
+	UserID    *uint                  `json:"user_id,omitempty" gorm:"index"`
+	Data      map[string]interface{} `json:"data,omitempty" gorm:"type:text;serializer:json"`
+	CreatedAt time.Time              `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ExportTemplate is the layout and branding of exported transcript documents: the header,
+// footer and organization name on every page, the accent color, and which sections appear in
+// which order. Template names are unique per owner, ignoring case.
+type ExportTemplate struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID *uint  `json:"user_id,omitempty" gorm:"index"`
+	Name   string `json:"name" gorm:"type:varchar(255);not null"`
+	// Header and Footer repeat on every page; {{title}} and {{date}} are replaced by the
+	// transcription's title and date
+	Header       string `json:"header" gorm:"type:text"`
+	Footer       string `json:"footer" gorm:"type:text"`
+	Organization string `json:"organization" gorm:"type:varchar(255)"` // Shown above the title
+	AccentColor  string `json:"accent_color" gorm:"type:varchar(7)"`   // Hex RGB of the title and headings, e.g. #1F6FEB
+	// Sections are summary, chapters, action_items and transcript, in the order they appear
+	Sections   []string  `json:"sections" gorm:"type:text;serializer:json"`
+	Timestamps bool      `json:"timestamps"` // Show when each transcript paragraph starts
+	Speakers   bool      `json:"speakers"`   // Show who speaks each transcript paragraph
+	IsDefault  bool      `json:"is_default"` // Used by exports that don't name a template; one per owner
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (t *ExportTemplate) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
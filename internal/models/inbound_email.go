@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Statuses of an inbound email
+const (
+	InboundEmailProcessing = "processing" // Waiting for its transcriptions to finish
+	InboundEmailReplied    = "replied"
+	InboundEmailCompleted  = "completed" // Finished without a reply: the email was automatic or no SMTP server is configured
+	InboundEmailFailed     = "failed"    // The reply couldn't be sent
+)
+
+// InboundEmail is an email read from the email-in mailbox, whose audio attachments were
+// queued for transcription. Once they are transcribed, the sender gets a reply with the
+// summaries.
+type InboundEmail struct {
+	ID        string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	MessageID string `json:"message_id" gorm:"type:varchar(512);not null;uniqueIndex"`
+	From      string `json:"from" gorm:"type:varchar(320);not null;index"`
+	ReplyTo   string `json:"reply_to" gorm:"type:varchar(320)"`
+	Subject   string `json:"subject" gorm:"type:text"`
+	// Automatic emails, such as voicemail notifications, are transcribed but not replied to
+	Automatic bool `json:"automatic"`
+	// TranscriptionIDs are the transcriptions of the email's audio attachments
+	TranscriptionIDs []string   `json:"transcription_ids" gorm:"type:text;serializer:json"`
+	Status           string     `json:"status" gorm:"type:varchar(20);not null;index"`
+	Error            *string    `json:"error,omitempty" gorm:"type:text"` // Attachments that couldn't be queued, or why the reply failed
+	RepliedAt        *time.Time `json:"replied_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (e *InboundEmail) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	return nil
+}
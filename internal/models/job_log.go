@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// JobLogEntry is a line logged while processing a transcription job, kept so the job's
+// processing log can be read back after the fact
+type JobLogEntry struct {
+	ID        uint                   `json:"id" gorm:"primaryKey"`
+	JobID     string                 `json:"job_id" gorm:"type:varchar(36);not null;index"`
+	Time      time.Time              `json:"time" gorm:"not null"`
+	Level     string                 `json:"level" gorm:"type:varchar(10);not null"` // DEBUG, INFO, WARN or ERROR
+	Message   string                 `json:"message" gorm:"type:text;not null"`
+	Component string                 `json:"component,omitempty" gorm:"type:varchar(32)"`
+	RequestID string                 `json:"request_id,omitempty" gorm:"type:varchar(64)"`
+	Attrs     map[string]interface{} `json:"attrs,omitempty" gorm:"type:text;serializer:json"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// JobTemplate is a named set of transcription settings that an upload can pick with its
+// template field instead of sending each one. Template names are unique per owner, ignoring case.
+type JobTemplate struct {
+	ID          string  `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID      *uint   `json:"user_id,omitempty" gorm:"index"`
+	Name        string  `json:"name" gorm:"type:varchar(255);not null"`
+	Description *string `json:"description,omitempty" gorm:"type:text"`
+	// ProfileID is the profile whose parameters jobs start from; nil uses the default profile
+	ProfileID *string `json:"profile_id,omitempty" gorm:"type:varchar(36)"`
+	// ModelFamily, Model and Diarize override the profile's when set
+	ModelFamily *string `json:"model_family,omitempty" gorm:"type:varchar(20)"`
+	Model       *string `json:"model,omitempty" gorm:"type:varchar(50)"`
+	Diarize     *bool   `json:"diarize,omitempty"`
+	// Tags are added to each job
+	Tags []string `json:"tags" gorm:"type:text;serializer:json"`
+	// WebhookURLs are notified when each job's post-processing completes, along with
+	// NOTIFY_WEBHOOK_URL
+	WebhookURLs []string  `json:"webhook_urls" gorm:"type:text;serializer:json"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (t *JobTemplate) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// LLMCall records one attempt against an LLM provider or embedding service, for latency,
+// error, token and cost statistics
+type LLMCall struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	Feature          string    `json:"feature" gorm:"type:varchar(32);index"` // "summary", "chat", "summarize", "transcript_chat" or "embedding"
+	Provider         string    `json:"provider" gorm:"type:varchar(32);not null;index"`
+	Model            string    `json:"model" gorm:"type:varchar(255)"`
+	Fallback         bool      `json:"fallback"` // Served by a provider other than the feature's primary
+	Stream           bool      `json:"stream"`
+	Success          bool      `json:"success"`
+	Error            string    `json:"error,omitempty" gorm:"type:text"`
+	LatencyMs        int64     `json:"latency_ms"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	TokensEstimated  bool      `json:"tokens_estimated"` // The provider didn't report usage
+	CostUSD          float64   `json:"cost_usd"`         // Estimated from LLM_PRICING; 0 for local or unknown models
+	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+}
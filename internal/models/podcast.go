@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PodcastFeed is a user's subscription to a podcast's RSS feed. The feed is polled for new
+// episodes, which are downloaded and transcribed as podcasts when AutoTranscribe is set.
+type PodcastFeed struct {
+	ID          string  `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID      *uint   `json:"user_id,omitempty" gorm:"index"`
+	URL         string  `json:"url" gorm:"type:text;not null"`
+	Title       string  `json:"title" gorm:"type:text"`
+	Description *string `json:"description,omitempty" gorm:"type:text"`
+	ImageURL    *string `json:"image_url,omitempty" gorm:"type:text"`
+	// AutoTranscribe downloads and transcribes each new episode; without it episodes are only listed
+	AutoTranscribe bool `json:"auto_transcribe"`
+	// JobTemplateID picks the profile, tags and webhooks of the episodes' transcriptions
+	JobTemplateID *string `json:"job_template_id,omitempty" gorm:"type:varchar(36)"`
+	Priority      int     `json:"priority"`
+	Paused        bool    `json:"paused"` // Paused feeds are not polled
+	// ETag and LastModified are sent back on the next poll, so an unchanged feed isn't downloaded again
+	ETag         string     `json:"-" gorm:"type:varchar(255)"`
+	LastModified string     `json:"-" gorm:"type:varchar(64)"`
+	LastPolledAt *time.Time `json:"last_polled_at,omitempty"`
+	LastError    *string    `json:"last_error,omitempty" gorm:"type:text"` // Why the last poll failed; nil after a successful one
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (f *PodcastFeed) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == "" {
+		f.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// PodcastEpisode is an episode found in a podcast feed
+type PodcastEpisode struct {
+	ID              string     `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	FeedID          string     `json:"feed_id" gorm:"type:varchar(36);not null;uniqueIndex:idx_podcast_episode_guid"`
+	UserID          *uint      `json:"user_id,omitempty" gorm:"index"` // Owner of the feed
+	GUID            string     `json:"guid" gorm:"type:varchar(512);not null;uniqueIndex:idx_podcast_episode_guid"`
+	Title           string     `json:"title" gorm:"type:text"`
+	Description     *string    `json:"description,omitempty" gorm:"type:text"`
+	AudioURL        string     `json:"audio_url" gorm:"type:text;not null"`
+	PublishedAt     *time.Time `json:"published_at,omitempty" gorm:"index"`
+	DurationSeconds int        `json:"duration_seconds,omitempty"`
+	// ImportID is the URL import downloading the episode; once downloaded, it is also the ID
+	// of the episode's transcription
+	ImportID  *string   `json:"import_id,omitempty" gorm:"type:varchar(36);index"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (e *PodcastEpisode) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	return nil
+}
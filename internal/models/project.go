@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProjectSettings are the post-processing settings a project gives the transcriptions filed
+// in it. A subproject inherits each setting it leaves empty from its parent.
+type ProjectSettings struct {
+	// SummaryTemplateID is the template summaries are written with when the transcription
+	// doesn't name one; it comes before the owner's default
+	SummaryTemplateID *string `json:"summary_template_id,omitempty" gorm:"type:varchar(36)"`
+	// ContentType is given to recordings uploaded into the project without a content_type
+	ContentType string `json:"content_type,omitempty" gorm:"type:varchar(20)"`
+	// Tags are added to recordings uploaded into the project
+	Tags []string `json:"tags" gorm:"type:text;serializer:json"`
+	// WebhookURLs are notified when post-processing of a transcription in the project
+	// completes, along with the transcription's own
+	WebhookURLs []string `json:"webhook_urls" gorm:"type:text;serializer:json"`
+	// AudioRetentionDays is how many days after they were created the retention cleanup keeps
+	// the audio of its transcriptions, in place of the cleanup's own; 0 keeps it forever
+	AudioRetentionDays *int `json:"audio_retention_days,omitempty"`
+	// RetentionDays is how many days after they were created the retention cleanup keeps its
+	// transcriptions before deleting them entirely, in place of the cleanup's own; 0 keeps
+	// them forever
+	RetentionDays *int `json:"retention_days,omitempty"`
+}
+
+// Project is a folder of transcriptions. Projects nest: ParentID is the project it is in, or
+// nil at the top level. Names are unique among the projects of a parent, ignoring case.
+type Project struct {
+	ID              string  `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID          *uint   `json:"user_id,omitempty" gorm:"index"`
+	ParentID        *string `json:"parent_id,omitempty" gorm:"type:varchar(36);index"`
+	Name            string  `json:"name" gorm:"type:varchar(255);not null"`
+	Description     *string `json:"description,omitempty" gorm:"type:text"`
+	ProjectSettings `gorm:"embedded"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (p *Project) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	return nil
+}
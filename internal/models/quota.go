@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Metrics metered against monthly quotas
+const (
+	QuotaAudioMinutes = "audio_minutes" // Minutes of audio transcribed
+	QuotaLLMCalls     = "llm_calls"     // Requests that call an LLM
+)
+
+// QuotaUsage is how much of a metric a user has used in a calendar month
+type QuotaUsage struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_quota_usage_period"`
+	Period    string    `json:"period" gorm:"type:varchar(7);not null;uniqueIndex:idx_quota_usage_period"` // YYYY-MM, in UTC
+	Metric    string    `json:"metric" gorm:"type:varchar(20);not null;uniqueIndex:idx_quota_usage_period"`
+	Amount    float64   `json:"amount" gorm:"not null;default:0"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RAGAnswer is a RAG chat answer, kept so the full set of excerpts retrieved for it can be
+// paged through after the response
+type RAGAnswer struct {
+	ID          string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID      *uint     `json:"user_id,omitempty" gorm:"index"`
+	Query       string    `json:"query" gorm:"type:text;not null"`
+	Answer      string    `json:"answer" gorm:"type:text;not null"`
+	SourceCount int       `json:"source_count"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (a *RAGAnswer) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// RAGAnswerSource is one excerpt retrieved for a RAG answer. The excerpt's text is copied so
+// it can still be shown after the transcription is re-indexed.
+type RAGAnswerSource struct {
+	ID              uint     `json:"-" gorm:"primaryKey"`
+	AnswerID        string   `json:"answer_id" gorm:"type:varchar(36);not null;index"`
+	Rank            int      `json:"rank"`                                        // 1 for the best match
+	ChunkID         string   `json:"chunk_id,omitempty" gorm:"type:varchar(255)"` // Vector store ID of the excerpt
+	TranscriptionID string   `json:"transcription_id,omitempty" gorm:"type:varchar(36);index"`
+	DocumentID      string   `json:"document_id,omitempty" gorm:"type:varchar(36);index"`
+	RecordingID     string   `json:"recording_id,omitempty" gorm:"type:varchar(36)"`
+	Start           *float64 `json:"start,omitempty"`
+	End             *float64 `json:"end,omitempty"`
+	Speaker         string   `json:"speaker,omitempty" gorm:"type:varchar(255)"`
+	Language        string   `json:"language,omitempty" gorm:"type:varchar(64)"` // Set when the excerpt is from a translation
+	Content         string   `json:"content" gorm:"type:text"`
+	Distance        float32  `json:"distance"`
+	InContext       bool     `json:"in_context"` // Whether the excerpt was given to the LLM for the answer
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RAGEvalCase is a stored retrieval test: a question and the transcriptions that should answer it
+type RAGEvalCase struct {
+	ID              string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID          *uint     `json:"user_id,omitempty" gorm:"index"`
+	Question        string    `json:"question" gorm:"type:text;not null"`
+	ExpectedSources []string  `json:"expected_sources" gorm:"type:text;serializer:json"` // Transcription IDs
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (rc *RAGEvalCase) BeforeCreate(tx *gorm.DB) error {
+	if rc.ID == "" {
+		rc.ID = uuid.New().String()
+	}
+	return nil
+}
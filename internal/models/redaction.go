@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Redaction is the redacted variant of a transcript, made by the redact_pii workflow step:
+// the transcript with email addresses, phone numbers, payment card numbers and names replaced
+// by placeholders. A transcription has at most one; redacting again replaces it.
+type Redaction struct {
+	ID              string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TranscriptionID string `json:"transcription_id" gorm:"type:varchar(36);not null;uniqueIndex"`
+	// Transcript is the redacted transcript, in the same JSON format as the original but
+	// without word timings
+	Transcript string         `json:"-" gorm:"type:text;not null"`
+	Counts     map[string]int `json:"counts" gorm:"type:text;serializer:json"` // Replacements by kind of data
+	// Names are the names found in the transcript, kept to redact text derived from it, such
+	// as summaries and translations, the same way
+	Names     []string  `json:"-" gorm:"type:text;serializer:json"`
+	Model     string    `json:"model,omitempty" gorm:"type:varchar(255)"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (r *Redaction) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
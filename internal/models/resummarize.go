@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Statuses of a re-summarization run
+const (
+	ResummarizeRunning   = "running"
+	ResummarizeCompleted = "completed"
+	ResummarizeFailed    = "failed"
+)
+
+// Verdicts of the comparison of a new summary with the one it replaced
+const (
+	VerdictImproved = "improved"
+	VerdictSame     = "same"
+	VerdictWorse    = "worse"
+)
+
+// ResummarizeRun is a batch of transcriptions summarized again because their summaries were
+// written by an older model than the current default, with a report of the results
+type ResummarizeRun struct {
+	ID      string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Model   string `json:"model" gorm:"type:varchar(255);not null"` // The model summaries were rewritten with
+	Status  string `json:"status" gorm:"type:varchar(20);not null;index"`
+	Trigger string `json:"trigger" gorm:"type:varchar(20)"` // "schedule" or "api"
+	// Candidates is how many transcriptions were picked for the run
+	Candidates   int                 `json:"candidates"`
+	Resummarized int                 `json:"resummarized"`
+	Failed       int                 `json:"failed"`
+	Improved     int                 `json:"improved"` // Judged better than the summary they replaced
+	Results      []ResummarizeResult `json:"results" gorm:"type:text;serializer:json"`
+	Error        *string             `json:"error,omitempty" gorm:"type:text"`
+	StartedAt    time.Time           `json:"started_at"`
+	CompletedAt  *time.Time          `json:"completed_at,omitempty"`
+	CreatedAt    time.Time           `json:"created_at" gorm:"autoCreateTime;index"`
+	UpdatedAt    time.Time           `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// ResummarizeResult is the outcome for one transcription of a re-summarization run
+type ResummarizeResult struct {
+	TranscriptionID string `json:"transcription_id"`
+	Title           string `json:"title,omitempty"`
+	OldModel        string `json:"old_model,omitempty"` // Empty when the model wasn't recorded
+	NewModel        string `json:"new_model"`
+	OldLength       int    `json:"old_length"` // Characters
+	NewLength       int    `json:"new_length,omitempty"`
+	// Verdict compares the new summary with the old one; empty when they weren't compared
+	Verdict string `json:"verdict,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (r *ResummarizeRun) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Statuses of a schedule run
+const (
+	ScheduleRunRunning   = "running"
+	ScheduleRunCompleted = "completed"
+	ScheduleRunFailed    = "failed"
+)
+
+// Schedule runs a maintenance action, such as a dropzone scan or a RAG backfill, whenever its
+// cron expression comes due
+type Schedule struct {
+	ID     string                 `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Name   string                 `json:"name" gorm:"type:varchar(255);not null"`
+	Action string                 `json:"action" gorm:"type:varchar(64);not null;index"`
+	Cron   string                 `json:"cron" gorm:"type:varchar(255);not null"` // Five fields or a macro such as @daily, in server time
+	Params map[string]interface{} `json:"params,omitempty" gorm:"type:text;serializer:json"`
+	// Enabled schedules run when due; disabled ones can still be run by hand
+	Enabled    bool       `json:"enabled" gorm:"not null;index"`
+	NextRunAt  *time.Time `json:"next_run_at,omitempty" gorm:"index"` // Nil while disabled
+	LastRunAt  *time.Time `json:"last_run_at,omitempty"`
+	LastStatus string     `json:"last_status,omitempty" gorm:"type:varchar(20)"`
+	CreatedBy  *uint      `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (s *Schedule) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// ScheduleRun is one run of a schedule's action, due or started by hand
+type ScheduleRun struct {
+	ID         uint                   `json:"id" gorm:"primaryKey"`
+	ScheduleID string                 `json:"schedule_id" gorm:"type:varchar(36);not null;index"`
+	Action     string                 `json:"action" gorm:"type:varchar(64);not null"`
+	Trigger    string                 `json:"trigger" gorm:"type:varchar(20)"` // "schedule" or "manual"
+	Status     string                 `json:"status" gorm:"type:varchar(20);not null;index"`
+	Result     map[string]interface{} `json:"result,omitempty" gorm:"type:text;serializer:json"` // What the action reported, e.g. counts
+	Error      *string                `json:"error,omitempty" gorm:"type:text"`
+	StartedAt  time.Time              `json:"started_at" gorm:"index"`
+	FinishedAt *time.Time             `json:"finished_at,omitempty"`
+}
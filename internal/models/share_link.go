@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ShareLink is a public link to a read-only view of a transcription's transcript and summary,
+// and optionally its audio. It works until it expires or is revoked. Only hashes of its token
+// and password are stored.
+type ShareLink struct {
+	ID              string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TokenHash       string `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	TranscriptionID string `json:"transcription_id" gorm:"type:varchar(36);not null;index"`
+	UserID          *uint  `json:"user_id,omitempty" gorm:"index"` // Who shared it
+	PasswordHash    string `json:"-" gorm:"type:varchar(255)"`
+	IncludeAudio    bool   `json:"include_audio"`
+	// AudioLinkID is the signed download link views play the audio through, while it lasts
+	AudioLinkID string `json:"-" gorm:"type:varchar(36)"`
+	// ExpiresAt is unset for links that work until revoked
+	ExpiresAt *time.Time `json:"expires_at,omitempty" gorm:"index"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	// Scanned is set when the transcript was scanned for sensitive passages before sharing;
+	// ConfirmedPassages are the flagged passages the creator confirmed could be shared
+	Scanned           bool       `json:"scanned" gorm:"not null;default:false"`
+	ConfirmedPassages []string   `json:"confirmed_passages,omitempty" gorm:"type:text;serializer:json"`
+	ViewCount         int        `json:"view_count" gorm:"not null;default:0"`
+	LastViewedAt      *time.Time `json:"last_viewed_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (l *ShareLink) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == "" {
+		l.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// Outcomes of a request to a share link
+const (
+	ShareAccessViewed        = "viewed"
+	ShareAccessWrongPassword = "wrong_password"
+	ShareAccessExpired       = "expired"
+	ShareAccessRevoked       = "revoked"
+)
+
+// ShareLinkAccess records a request to a share link, successful or not
+type ShareLinkAccess struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ShareLinkID string    `json:"share_link_id" gorm:"type:varchar(36);not null;index"`
+	Outcome     string    `json:"outcome" gorm:"type:varchar(20);not null"`
+	IPAddress   string    `json:"ip_address" gorm:"type:varchar(64)"`
+	UserAgent   string    `json:"user_agent" gorm:"type:varchar(512)"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+}
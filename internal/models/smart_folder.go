@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SmartFolderFilter selects transcriptions. All set criteria must match; an empty filter matches everything.
+type SmartFolderFilter struct {
+	Tags          []string   `json:"tags,omitempty"`     // Every tag must be present
+	Speakers      []string   `json:"speakers,omitempty"` // Any speaker, by label or mapped name
+	Text          string     `json:"text,omitempty"`     // Matched against title, summary and transcript
+	Status        JobStatus  `json:"status,omitempty"`
+	CreatedAfter  *time.Time `json:"created_after,omitempty"`
+	CreatedBefore *time.Time `json:"created_before,omitempty"`
+}
+
+// SmartFolder is a saved filter shown as a virtual folder of transcriptions
+type SmartFolder struct {
+	ID        string            `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID    *uint             `json:"user_id,omitempty" gorm:"index"`
+	Name      string            `json:"name" gorm:"type:varchar(255);not null"`
+	Filter    SmartFolderFilter `json:"filter" gorm:"type:text;serializer:json"`
+	CreatedAt time.Time         `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time         `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (sf *SmartFolder) BeforeCreate(tx *gorm.DB) error {
+	if sf.ID == "" {
+		sf.ID = uuid.New().String()
+	}
+	return nil
+}
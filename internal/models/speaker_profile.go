@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SpeakerProfile is a known person's voice, used to name the speakers diarization finds in
+// new recordings. Its embedding is the average of the voice embeddings it was enrolled with.
+type SpeakerProfile struct {
+	ID          string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID      *uint     `json:"user_id,omitempty" gorm:"index"`
+	Name        string    `json:"name" gorm:"type:varchar(100);not null"`
+	Embedding   []float32 `json:"-" gorm:"type:text;serializer:json"`
+	SampleCount int       `json:"sample_count"` // Recordings the embedding was averaged from
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (p *SpeakerProfile) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// Job speaker statuses
+const (
+	JobSpeakerUnmatched = "unmatched" // No profile sounds like the speaker
+	JobSpeakerMatched   = "matched"   // Named automatically from the closest profile
+	JobSpeakerConfirmed = "confirmed" // Named or confirmed by a person
+)
+
+// JobSpeaker is a speaker diarization found in a recording, with their voice embedding and
+// the profile they were identified as, if any
+type JobSpeaker struct {
+	ID                 uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	TranscriptionJobID string    `json:"transcription_job_id" gorm:"type:varchar(36);not null;index"`
+	Speaker            string    `json:"speaker" gorm:"type:varchar(50);not null"` // e.g. "SPEAKER_00"
+	Embedding          []float32 `json:"-" gorm:"type:text;serializer:json"`
+	SpeakerProfileID   *string   `json:"speaker_profile_id,omitempty" gorm:"type:varchar(36);index"`
+	Similarity         float64   `json:"similarity"` // To the profile, when matched automatically
+	Status             string    `json:"status" gorm:"type:varchar(16);not null"`
+	CreatedAt          time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt          time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	SpeakerProfile *SpeakerProfile `json:"speaker_profile,omitempty" gorm:"foreignKey:SpeakerProfileID"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// StandingContext is the organization-wide context document, e.g. a team glossary or the
+// current quarter's goals, prepended to every RAG chat prompt (single row)
+type StandingContext struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Content   string    `json:"content" gorm:"type:text;not null;default:''"`
+	UpdatedBy *uint     `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
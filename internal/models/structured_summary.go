@@ -0,0 +1,100 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StructuredSummary is a summary generated as a JSON object with a fixed set of fields
+type StructuredSummary struct {
+	Title       string              `json:"title"`
+	TLDR        string              `json:"tldr"`
+	KeyPoints   []string            `json:"key_points"`
+	Decisions   []string            `json:"decisions"`
+	ActionItems []SummaryActionItem `json:"action_items"`
+	// Speakers is set by templates with the per-speaker layout
+	Speakers []SpeakerSection `json:"speakers,omitempty"`
+}
+
+// SpeakerSection is what one participant contributed to a recording
+type SpeakerSection struct {
+	Speaker     string   `json:"speaker"`
+	KeyPoints   []string `json:"key_points"`
+	Commitments []string `json:"commitments"` // What they said they would do
+}
+
+// SummaryActionItem is a follow-up task named in a structured summary
+type SummaryActionItem struct {
+	Task  string `json:"task"`
+	Owner string `json:"owner,omitempty"`
+	Due   string `json:"due,omitempty"` // As mentioned in the recording, e.g. "next Friday"
+}
+
+// Commitments returns the speaker sections that list commitments, only those of speaker
+// (matched case-insensitively) if it is set
+func (s *StructuredSummary) Commitments(speaker string) []SpeakerSection {
+	var sections []SpeakerSection
+	for _, section := range s.Speakers {
+		if len(section.Commitments) == 0 || (speaker != "" && !strings.EqualFold(section.Speaker, speaker)) {
+			continue
+		}
+		sections = append(sections, section)
+	}
+	return sections
+}
+
+// Markdown renders the summary as Markdown, leaving out empty sections
+func (s *StructuredSummary) Markdown() string {
+	var out strings.Builder
+	if s.Title != "" {
+		fmt.Fprintf(&out, "## %s\n\n", s.Title)
+	}
+	if s.TLDR != "" {
+		fmt.Fprintf(&out, "**TL;DR:** %s\n\n", s.TLDR)
+	}
+	writeList := func(heading string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&out, "### %s\n\n", heading)
+		for _, item := range items {
+			fmt.Fprintf(&out, "- %s\n", item)
+		}
+		out.WriteString("\n")
+	}
+	writeList("Key points", s.KeyPoints)
+	writeList("Decisions", s.Decisions)
+
+	items := make([]string, len(s.ActionItems))
+	for i, item := range s.ActionItems {
+		items[i] = item.Task
+		var details []string
+		if item.Owner != "" {
+			details = append(details, item.Owner)
+		}
+		if item.Due != "" {
+			details = append(details, "due "+item.Due)
+		}
+		if len(details) > 0 {
+			items[i] += " (" + strings.Join(details, ", ") + ")"
+		}
+	}
+	writeList("Action items", items)
+
+	for _, section := range s.Speakers {
+		fmt.Fprintf(&out, "### %s\n\n", section.Speaker)
+		writeLabeled := func(label string, items []string) {
+			if len(items) == 0 {
+				return
+			}
+			fmt.Fprintf(&out, "**%s:**\n\n", label)
+			for _, item := range items {
+				fmt.Fprintf(&out, "- %s\n", item)
+			}
+			out.WriteString("\n")
+		}
+		writeLabeled("Key points", section.KeyPoints)
+		writeLabeled("Commitments", section.Commitments)
+	}
+	return strings.TrimSpace(out.String())
+}
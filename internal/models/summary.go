@@ -6,13 +6,24 @@ import (
 	"time"
 )
 
-// SummaryTemplate represents a saved summarization prompt/template
+// Summary template layouts
+const (
+	// SummaryLayoutDefault summarizes the recording as a whole
+	SummaryLayoutDefault = ""
+	// SummaryLayoutPerSpeaker adds a section per participant with their key points and commitments
+	SummaryLayoutPerSpeaker = "per_speaker"
+)
+
+// SummaryTemplate represents a saved summarization prompt/template. Templates without an
+// owner predate per-user templates and are shared by every user.
 type SummaryTemplate struct {
 	ID          string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID      *uint     `json:"user_id,omitempty" gorm:"index"`
 	Name        string    `json:"name" gorm:"type:varchar(255);not null"`
 	Description *string   `json:"description,omitempty" gorm:"type:text"`
 	Model       string    `json:"model" gorm:"type:varchar(255);not null;default:''"`
 	Prompt      string    `json:"prompt" gorm:"type:text;not null"`
+	Layout      string    `json:"layout" gorm:"type:varchar(32);not null;default:''"` // SummaryLayoutDefault or SummaryLayoutPerSpeaker
 	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Tag sources
+const (
+	// TagSourceManual marks a tag added by a user
+	TagSourceManual = "manual"
+	// TagSourceAuto marks a tag suggested by the LLM after transcription
+	TagSourceAuto = "auto"
+)
+
+// Tag is a label for grouping transcriptions. Tag names are unique per owner, ignoring case.
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    *uint     `json:"user_id,omitempty" gorm:"index"` // Owner; nil for tags of transcriptions without an owner
+	Name      string    `json:"name" gorm:"type:varchar(100);not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TranscriptionTag links a tag to a transcription. The transcription's tags column holds a
+// copy of its tag names for filtering and indexing, kept in sync by the tagging package.
+type TranscriptionTag struct {
+	TranscriptionID string    `json:"transcription_id" gorm:"primaryKey;type:varchar(36)"`
+	TagID           uint      `json:"tag_id" gorm:"primaryKey;index"`
+	Source          string    `json:"source" gorm:"type:varchar(10);not null;default:'manual'"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TaskIntegration is a task manager a user delivers action items to
+type TaskIntegration struct {
+	ID       string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID   *uint  `json:"user_id,omitempty" gorm:"index"`
+	Name     string `json:"name" gorm:"type:varchar(255);not null"`
+	Provider string `json:"provider" gorm:"type:varchar(16);not null"` // todoist, linear or webhook
+	// Token is the Todoist API token or Linear API key, or the secret webhook bodies are signed with
+	Token string `json:"-" gorm:"type:text"`
+	// Target is the Todoist project or the Linear team tasks are created in
+	Target     string `json:"target,omitempty" gorm:"type:varchar(255)"`
+	WebhookURL string `json:"webhook_url,omitempty" gorm:"type:varchar(2048)"`
+	// Assignees maps action item owners, as named in recordings, to accounts in the task manager
+	Assignees map[string]string `json:"assignees" gorm:"type:text;serializer:json"`
+	// AutoDeliver delivers the action items of new transcriptions as they are extracted
+	AutoDeliver bool      `json:"auto_deliver"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (i *TaskIntegration) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// Action item delivery states
+const (
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// ActionItemDelivery records an action item delivered to a task integration. Action items are
+// replaced when extracted again, so deliveries are matched to them by transcription and task
+// text, and a task already delivered isn't delivered twice.
+type ActionItemDelivery struct {
+	ID              string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	IntegrationID   string    `json:"integration_id" gorm:"type:varchar(36);not null;uniqueIndex:idx_delivery_task"`
+	TranscriptionID string    `json:"transcription_id" gorm:"type:varchar(36);not null;uniqueIndex:idx_delivery_task;index"`
+	TaskKey         string    `json:"-" gorm:"type:text;not null;uniqueIndex:idx_delivery_task"` // Lowercased task text
+	ActionItemID    string    `json:"action_item_id" gorm:"type:varchar(36);index"`              // The item last delivered for the task
+	Status          string    `json:"status" gorm:"type:varchar(16);not null"`                   // delivered or failed
+	ExternalID      string    `json:"external_id,omitempty" gorm:"type:varchar(255)"`
+	ExternalURL     string    `json:"external_url,omitempty" gorm:"type:varchar(2048)"`
+	Error           string    `json:"error,omitempty" gorm:"type:text"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (d *ActionItemDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	return nil
+}
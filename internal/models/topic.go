@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Topic is a cluster of similar transcriptions in one RAG collection, labeled by the LLM.
+// Topics are regenerated as a set; every refresh replaces a collection's previous topics.
+type Topic struct {
+	ID               string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Collection       string    `json:"-" gorm:"type:varchar(255);not null;index"`
+	Label            string    `json:"label" gorm:"type:varchar(255);not null"`
+	Description      string    `json:"description,omitempty" gorm:"type:text"`
+	TranscriptionIDs []string  `json:"transcription_ids" gorm:"type:text;serializer:json"` // Most central first
+	Size             int       `json:"size"`
+	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (t *Topic) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
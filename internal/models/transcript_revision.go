@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// Sources of transcript revisions
+const (
+	RevisionSourceManual     = "manual"     // Corrected by a person
+	RevisionSourceVocabulary = "vocabulary" // Corrected to the spelling of a vocabulary term
+)
+
+// TranscriptRevision is a correction made to one segment of a transcript, with what it
+// replaced. The transcript itself holds the latest text; its revisions are the history.
+type TranscriptRevision struct {
+	ID              uint    `json:"id" gorm:"primaryKey;autoIncrement"`
+	TranscriptionID string  `json:"transcription_id" gorm:"type:varchar(36);not null;index:idx_transcript_revisions_segment"`
+	SegmentIndex    int     `json:"segment_index" gorm:"not null;index:idx_transcript_revisions_segment"`
+	Revision        int     `json:"revision"` // 1 for a segment's first correction
+	PreviousText    string  `json:"previous_text" gorm:"type:text"`
+	Text            string  `json:"text" gorm:"type:text"`
+	PreviousSpeaker *string `json:"previous_speaker,omitempty" gorm:"type:varchar(50)"`
+	Speaker         *string `json:"speaker,omitempty" gorm:"type:varchar(50)"`
+	Source          string  `json:"source" gorm:"type:varchar(20);not null;default:'manual'"`
+	// EditedBy is the user who made the correction; nil for API keys without a user
+	EditedBy  *uint     `json:"edited_by,omitempty"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+)
+
+// Kinds of content a transcription keeps versions of
+const (
+	VersionKindTranscript = "transcript"
+	VersionKindSummary    = "summary"
+)
+
+// Sources of transcript and summary versions
+const (
+	VersionSourceOriginal      = "original"      // Content written before versions were kept
+	VersionSourceTranscription = "transcription" // Written by the transcription engine
+	VersionSourceImport        = "import"        // Imported from another tool
+	VersionSourceManual        = RevisionSourceManual
+	VersionSourceVocabulary    = RevisionSourceVocabulary
+	VersionSourceSummary       = "summary"  // Written by an LLM
+	VersionSourceRollback      = "rollback" // Restored from an earlier version
+)
+
+// TranscriptVersion is an immutable copy of a transcription's transcript or summary as it read
+// after one change. The job holds the current content; its versions are the history, numbered
+// per kind, the latest matching the job.
+type TranscriptVersion struct {
+	ID              uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	TranscriptionID string `json:"transcription_id" gorm:"type:varchar(36);not null;uniqueIndex:idx_transcript_versions_number"`
+	Kind            string `json:"kind" gorm:"type:varchar(20);not null;uniqueIndex:idx_transcript_versions_number"`
+	Version         int    `json:"version" gorm:"not null;uniqueIndex:idx_transcript_versions_number"` // 1 for the first version of its kind
+	// Content is the transcript JSON (or plain text) or the summary's Markdown
+	Content           string             `json:"content,omitempty" gorm:"type:text"`
+	StructuredSummary *StructuredSummary `json:"structured_summary,omitempty" gorm:"type:text;serializer:json"`
+	Model             *string            `json:"model,omitempty" gorm:"type:varchar(255)"` // LLM model that wrote a summary
+	Source            string             `json:"source" gorm:"type:varchar(20);not null"`
+	// RestoredFrom is the version a rollback copied
+	RestoredFrom *int `json:"restored_from,omitempty"`
+	// CreatedBy is the user who made the change; nil for automatic changes and API keys without a user
+	CreatedBy *uint     `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,12 +11,16 @@ import (
 // TranscriptionJob represents a transcription job record
 type TranscriptionJob struct {
 	ID               string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID           *uint     `json:"user_id,omitempty" gorm:"index"` // Owner; nil for jobs created without a user (e.g. dropzone)
 	Title            *string   `json:"title,omitempty" gorm:"type:text"`
 	Status           JobStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
 	AudioPath        string    `json:"audio_path" gorm:"type:text;not null"`
 	Transcript       *string   `json:"transcript,omitempty" gorm:"type:text"`
 	Diarization      bool      `json:"diarization" gorm:"type:boolean;default:false"`
 	Summary          *string   `json:"summary,omitempty" gorm:"type:text"`
+	StructuredSummary *StructuredSummary `json:"structured_summary,omitempty" gorm:"type:text;serializer:json"` // Set when the summary was generated as structured output; Summary holds its Markdown rendering
+	SummaryTemplateID *string `json:"summary_template_id,omitempty" gorm:"type:varchar(36)"` // Template the post-processing summary is written with; nil uses the owner's default
+	SummaryModel     *string   `json:"summary_model,omitempty" gorm:"type:varchar(255);index"` // LLM model that wrote Summary; nil when unknown
 	ErrorMessage     *string   `json:"error_message,omitempty" gorm:"type:text"`
 	IsMultiTrack     bool      `json:"is_multi_track" gorm:"type:boolean;default:false"`
 	AupFilePath      *string   `json:"aup_file_path,omitempty" gorm:"type:text"`
@@ -24,6 +29,31 @@ type TranscriptionJob struct {
 	MergeStatus           string `json:"merge_status" gorm:"type:varchar(20);default:'none'"` // none, pending, processing, completed, failed
 	MergeError            *string `json:"merge_error,omitempty" gorm:"type:text"`
 	IndividualTranscripts *string `json:"individual_transcripts,omitempty" gorm:"type:text"` // JSON-serialized map[string]*string
+	AudioQuality          *string `json:"audio_quality,omitempty" gorm:"type:text"`          // JSON-serialized audio.QualityReport
+	// Language detected in the audio before transcription, how sure the detection was, and
+	// every language heard, most spoken first, for recordings that switch between languages
+	DetectedLanguage      *string  `json:"detected_language,omitempty" gorm:"type:varchar(10)"`
+	LanguageConfidence    *float64 `json:"language_confidence,omitempty"`
+	Languages             []string `json:"languages,omitempty" gorm:"type:text;serializer:json"`
+	Tags                  []string `json:"tags,omitempty" gorm:"type:text;serializer:json"`
+	ContentType           string   `json:"content_type" gorm:"type:varchar(20);not null;default:'meeting';index"` // meeting, voice_memo or podcast; picks the RAG collection, chunking and prompts
+	Priority              int      `json:"priority" gorm:"not null;default:0;index"` // Higher is transcribed first, from -10 to 10
+	JobTemplateID         *string  `json:"job_template_id,omitempty" gorm:"type:varchar(36);index"` // Template the job was uploaded with
+	ProjectID             *string  `json:"project_id,omitempty" gorm:"type:varchar(36);index"` // Project the transcription is filed in
+	WebhookURLs           []string `json:"webhook_urls,omitempty" gorm:"type:text;serializer:json"` // Notified when post-processing completes, along with NOTIFY_WEBHOOK_URL
+	Source                *MediaSource `json:"source,omitempty" gorm:"type:text;serializer:json"` // Where an imported recording was downloaded from
+	DuplicateOf           *string  `json:"duplicate_of,omitempty" gorm:"type:varchar(36);index"` // Transcription this one was merged into as a duplicate; kept out of RAG
+	// Legal hold blocks deleting the job or any of its data until an admin releases it
+	LegalHold             bool       `json:"legal_hold" gorm:"not null;default:false;index"`
+	LegalHoldReason       *string    `json:"legal_hold_reason,omitempty" gorm:"type:text"`
+	LegalHoldBy           *uint      `json:"legal_hold_by,omitempty"` // User who placed the hold
+	LegalHoldAt           *time.Time `json:"legal_hold_at,omitempty"`
+	// Set by the queue worker that claims the job: which worker, when it started and when it
+	// last reported the job alive. The watchdog requeues or fails jobs that stop reporting.
+	WorkerID              *string    `json:"worker_id,omitempty" gorm:"type:varchar(255);index"`
+	ProcessingStartedAt   *time.Time `json:"processing_started_at,omitempty"`
+	HeartbeatAt           *time.Time `json:"heartbeat_at,omitempty"`
+	StuckRequeues         int        `json:"stuck_requeues" gorm:"not null;default:0"` // Times the watchdog requeued the job
 	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt        time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
@@ -45,6 +75,19 @@ const (
 	StatusFailed     JobStatus = "failed"
 )
 
+// Content types of recordings. Uploaded documents are indexed as ContentDocument.
+const (
+	ContentMeeting   = "meeting"
+	ContentVoiceMemo = "voice_memo"
+	ContentPodcast   = "podcast"
+	ContentDocument  = "document"
+)
+
+// IsRecordingContentType reports whether t is a content type a transcription can have
+func IsRecordingContentType(t string) bool {
+	return t == ContentMeeting || t == ContentVoiceMemo || t == ContentPodcast
+}
+
 // WhisperXParams contains parameters for WhisperX transcription
 type WhisperXParams struct {
 	// Model family (whisper or nvidia)
@@ -105,6 +148,12 @@ type WhisperXParams struct {
 	LogprobThreshold               float64 `json:"logprob_threshold" gorm:"type:real;default:-1.0"`
 	NoSpeechThreshold              float64 `json:"no_speech_threshold" gorm:"type:real;default:0.6"`
 
+	// Audio enhancement (denoise/normalize) applied before transcription when the quality check flags poor audio
+	AutoEnhance bool `json:"auto_enhance" gorm:"type:boolean;default:false"`
+	// Comma-separated preprocessing steps (trim_silence, skip_silence, denoise, normalize, resample) applied
+	// before transcription; empty uses AUDIO_PREPROCESS and "none" turns them off
+	Preprocess string `json:"preprocess,omitempty" gorm:"type:varchar(100)"`
+
 	// Output formatting
 	MaxLineWidth      *int   `json:"max_line_width,omitempty" gorm:"type:int"`
 	MaxLineCount      *int   `json:"max_line_count,omitempty" gorm:"type:int"`
@@ -123,23 +172,63 @@ type WhisperXParams struct {
 	IsMultiTrackEnabled bool `json:"is_multi_track_enabled" gorm:"type:boolean;default:false"`
 }
 
-// BeforeCreate sets the ID if not already set
+// BeforeCreate sets the ID and content type if not already set
 func (tj *TranscriptionJob) BeforeCreate(tx *gorm.DB) error {
 	if tj.ID == "" {
 		tj.ID = uuid.New().String()
 	}
+	if tj.ContentType == "" {
+		tj.ContentType = ContentMeeting
+	}
 	return nil
 }
 
+// AfterCreate records a job.created event in the same transaction as the job, so the
+// outbox can't miss a job. Internal per-track jobs of multi-track recordings are skipped.
+func (tj *TranscriptionJob) AfterCreate(tx *gorm.DB) error {
+	if strings.HasPrefix(tj.ID, "track_") {
+		return nil
+	}
+	data := map[string]interface{}{"status": tj.Status}
+	if tj.Title != nil {
+		data["title"] = *tj.Title
+	}
+	return tx.Create(&Event{
+		Type:      EventJobCreated,
+		SubjectID: tj.ID,
+		UserID:    tj.UserID,
+		Data:      data,
+	}).Error
+}
+
 // User represents a user for authentication
 type User struct {
 	ID                       uint      `json:"id" gorm:"primaryKey"`
 	Username                 string    `json:"username" gorm:"uniqueIndex;not null;type:varchar(50)"`
 	Password                 string    `json:"-" gorm:"not null;type:varchar(255)"`
+	Role                     string    `json:"role" gorm:"type:varchar(20);not null;default:'member'"` // admin, member or viewer
 	DefaultProfileID         *string   `json:"default_profile_id,omitempty" gorm:"type:varchar(36)"`
+	DefaultSummaryTemplateID *string   `json:"default_summary_template_id,omitempty" gorm:"type:varchar(36)"`
 	AutoTranscriptionEnabled bool      `json:"auto_transcription_enabled" gorm:"not null;default:false"`
-	CreatedAt                time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt                time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	// Limits an admin set for this user instead of the instance defaults; 0 is unlimited
+	RateLimitPerMinute *int      `json:"rate_limit_per_minute,omitempty"`
+	QuotaAudioMinutes  *int      `json:"quota_audio_minutes,omitempty"` // Per calendar month
+	QuotaLLMCalls      *int      `json:"quota_llm_calls,omitempty"`     // Per calendar month
+	CreatedAt          time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt          time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// User roles. Admins manage accounts and the instance and can open every transcription,
+// members work with their own transcriptions, and viewers can only read theirs.
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+	RoleViewer = "viewer"
+)
+
+// IsValidRole reports whether role is one of the user roles
+func IsValidRole(role string) bool {
+	return role == RoleAdmin || role == RoleMember || role == RoleViewer
 }
 
 // APIKey represents an API key for external authentication
@@ -151,11 +240,33 @@ type APIKey struct {
 	// IsActive should persist explicit false values; avoid default tag to prevent
 	// GORM from overriding false with DB defaults during inserts.
 	IsActive  bool       `json:"is_active" gorm:"type:boolean;not null"`
+	// UserID is the user who created the key; requests made with it act as that user
+	UserID    *uint      `json:"user_id,omitempty" gorm:"index"`
+	// Scopes limits what the key can do; a key without scopes can do everything its user can
+	Scopes    []string   `json:"scopes,omitempty" gorm:"type:text;serializer:json"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Unset for keys that work until revoked
+	// RateLimitPerMinute is a rate limit of the key's own, applied on top of its user's; 0 is unlimited
+	RateLimitPerMinute *int `json:"rate_limit_per_minute,omitempty"`
 	LastUsed  *time.Time `json:"last_used,omitempty"`
 	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
+// API key scopes. A read key can only make GET requests, an upload key can only upload and
+// import recordings and follow their status, and a rag_chat key can only ask questions of the
+// RAG index. A key with several scopes can do what each of them allows; none of them allows
+// admin routes.
+const (
+	APIKeyScopeRead    = "read"
+	APIKeyScopeUpload  = "upload"
+	APIKeyScopeRAGChat = "rag_chat"
+)
+
+// IsValidAPIKeyScope reports whether scope is one of the API key scopes
+func IsValidAPIKeyScope(scope string) bool {
+	return scope == APIKeyScopeRead || scope == APIKeyScopeUpload || scope == APIKeyScopeRAGChat
+}
+
 // BeforeCreate sets the API key if not already set
 func (ak *APIKey) BeforeCreate(tx *gorm.DB) error {
 	if ak.Key == "" {
@@ -197,9 +308,9 @@ func (tp *TranscriptionProfile) BeforeSave(tx *gorm.DB) error {
 // LLMConfig represents LLM configuration settings
 type LLMConfig struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
-	Provider  string    `json:"provider" gorm:"not null;type:varchar(50)"` // "ollama" or "openai"
-	BaseURL   *string   `json:"base_url,omitempty" gorm:"type:text"`       // For Ollama
-	APIKey    *string   `json:"api_key,omitempty" gorm:"type:text"`        // For OpenAI (encrypted)
+	Provider  string    `json:"provider" gorm:"not null;type:varchar(50)"` // "ollama", "openai" or "anthropic"
+	BaseURL   *string   `json:"base_url,omitempty" gorm:"type:text"`       // For Ollama, or an OpenAI-compatible server
+	APIKey    *string   `json:"api_key,omitempty" gorm:"type:text"`        // For OpenAI or Anthropic (encrypted)
 	IsActive  bool      `json:"is_active" gorm:"type:boolean;default:false"`
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TranslatedSegment is the translation of one transcript segment, with the source segment's timing
+type TranslatedSegment struct {
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Speaker *string `json:"speaker,omitempty"`
+	Text    string  `json:"text"`
+}
+
+// Translation is a transcript translated into another language, segment by segment.
+// A transcription has at most one translation per language; translating again replaces it.
+type Translation struct {
+	ID                 string              `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TranscriptionJobID string              `json:"transcription_job_id" gorm:"type:varchar(36);not null;uniqueIndex:idx_translation_job_language"`
+	Language           string              `json:"language" gorm:"type:varchar(64);not null;uniqueIndex:idx_translation_job_language"`
+	SourceLanguage     string              `json:"source_language,omitempty" gorm:"type:varchar(64)"` // Language of the transcript, if known
+	Model              string              `json:"model,omitempty" gorm:"type:varchar(255)"`
+	Segments           []TranslatedSegment `json:"segments" gorm:"type:text;serializer:json"`
+	Indexed            bool                `json:"indexed"` // Whether the translation is searchable in RAG next to the original
+	CreatedAt          time.Time           `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (t *Translation) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UploadSession is a resumable upload in progress. The client sends the file in parts, each
+// appended at Received, and the transcription is created once all Size bytes have arrived.
+type UploadSession struct {
+	ID            string  `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID        *uint   `json:"user_id,omitempty" gorm:"index"`
+	Filename      string  `json:"filename" gorm:"type:varchar(255);not null"`
+	Size          int64   `json:"size" gorm:"not null"`     // Total size of the file, in bytes
+	Received      int64   `json:"received" gorm:"not null"` // Bytes received so far
+	Title         *string `json:"title,omitempty" gorm:"type:text"`
+	ContentType   string  `json:"content_type" gorm:"type:varchar(20)"`
+	Priority      int     `json:"priority"`
+	InitialPrompt *string `json:"initial_prompt,omitempty" gorm:"type:text"`
+	// TranscriptionID is the transcription created from the upload once it is complete
+	TranscriptionID *string   `json:"transcription_id,omitempty" gorm:"type:varchar(36)"`
+	ExpiresAt       time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (u *UploadSession) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == "" {
+		u.ID = uuid.New().String()
+	}
+	return nil
+}
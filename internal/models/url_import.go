@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Statuses of a URL import
+const (
+	URLImportDownloading = "downloading"
+	URLImportCompleted   = "completed"
+	URLImportFailed      = "failed"
+)
+
+// URLImportYtDlp is the extractor of URL imports whose URL is a page, such as a YouTube
+// video, that yt-dlp extracts the audio of
+const URLImportYtDlp = "yt-dlp"
+
+// MediaSource is where an imported recording was downloaded from. Imports through yt-dlp also
+// record the site's metadata of the video or track.
+type MediaSource struct {
+	URL             string  `json:"url"`
+	Site            string  `json:"site,omitempty"`     // yt-dlp's name for the site, e.g. Youtube
+	MediaID         string  `json:"media_id,omitempty"` // The site's ID of the video or track
+	Title           string  `json:"title,omitempty"`
+	Channel         string  `json:"channel,omitempty"`
+	ChannelURL      string  `json:"channel_url,omitempty"`
+	Uploader        string  `json:"uploader,omitempty"`
+	UploadDate      string  `json:"upload_date,omitempty"` // YYYY-MM-DD
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+}
+
+// URLImport is a remote audio file being downloaded for transcription. Once downloaded, it
+// becomes a transcription with the same ID.
+type URLImport struct {
+	ID              string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID          *uint  `json:"user_id,omitempty" gorm:"index"`
+	URL             string `json:"url" gorm:"type:text;not null"`
+	// Extractor is URLImportYtDlp for pages whose audio is extracted; empty when the URL is the file
+	Extractor       string `json:"extractor,omitempty" gorm:"type:varchar(20)"`
+	Status          string `json:"status" gorm:"type:varchar(20);not null;index"`
+	DownloadedBytes int64  `json:"downloaded_bytes" gorm:"not null"`
+	// TotalBytes is the size the server announced, if it did
+	TotalBytes    *int64  `json:"total_bytes,omitempty"`
+	Error         *string `json:"error,omitempty" gorm:"type:text"`
+	Title         *string `json:"title,omitempty" gorm:"type:text"`
+	ContentType   string  `json:"content_type" gorm:"type:varchar(20)"`
+	Priority      int     `json:"priority"`
+	InitialPrompt *string `json:"initial_prompt,omitempty" gorm:"type:text"`
+	JobTemplateID *string `json:"job_template_id,omitempty" gorm:"type:varchar(36)"`
+	// Source is the metadata yt-dlp found, once it has
+	Source *MediaSource `json:"source,omitempty" gorm:"type:text;serializer:json"`
+	// TranscriptionID is the transcription created once the download is complete
+	TranscriptionID *string   `json:"transcription_id,omitempty" gorm:"type:varchar(36)"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (i *URLImport) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = uuid.New().String()
+	}
+	return nil
+}
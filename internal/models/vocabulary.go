@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Kinds of vocabulary terms
+const (
+	VocabularyKindTerm       = "term"        // Domain jargon, product names and the like
+	VocabularyKindAcronym    = "acronym"     // Spelled out letter by letter, e.g. SLA
+	VocabularyKindProperNoun = "proper_noun" // Names of people, places and organizations
+)
+
+// VocabularyTerm is a word or phrase transcripts should spell a particular way. Terms without
+// a TranscriptionID make up their owner's workspace vocabulary and apply to all of the owner's
+// transcriptions; the others apply to that transcription only. Terms are passed to the
+// transcription engine as hotwords and in the initial prompt, and transcripts are corrected
+// to them afterwards.
+type VocabularyTerm struct {
+	ID              string  `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID          *uint   `json:"user_id,omitempty" gorm:"index"`
+	TranscriptionID *string `json:"transcription_id,omitempty" gorm:"type:varchar(36);index"`
+	Term            string  `json:"term" gorm:"type:varchar(255);not null"`
+	Kind            string  `json:"kind" gorm:"type:varchar(20);not null;default:'term'"`
+	// SoundsLike lists how the term tends to be misheard, e.g. "cube cuddle" for kubectl;
+	// transcripts have these replaced by the term
+	SoundsLike []string  `json:"sounds_like,omitempty" gorm:"type:text;serializer:json"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (v *VocabularyTerm) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == "" {
+		v.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// IsVocabularyKind reports whether kind is one of the known kinds of vocabulary terms
+func IsVocabularyKind(kind string) bool {
+	return kind == VocabularyKindTerm || kind == VocabularyKindAcronym || kind == VocabularyKindProperNoun
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Watchlist is a user's list of keywords and phrases to be alerted about. New transcripts are
+// scanned for its terms by the scan_watchlists workflow step.
+type Watchlist struct {
+	ID     string   `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID *uint    `json:"user_id,omitempty" gorm:"index"`
+	Name   string   `json:"name" gorm:"type:varchar(255);not null"`
+	Terms  []string `json:"terms" gorm:"type:text;serializer:json"`
+	// Fuzzy also matches small misspellings and words masked with asterisks
+	Fuzzy bool `json:"fuzzy"`
+	// Semantic also matches segments whose meaning is close to a term, by embedding similarity
+	Semantic bool `json:"semantic"`
+	// SimilarityThreshold is the similarity a semantic match needs; 0 uses the default
+	SimilarityThreshold float64 `json:"similarity_threshold,omitempty"`
+	// WebhookURL receives the list's matches; without one they go to NOTIFY_WEBHOOK_URL, if set
+	WebhookURL string    `json:"webhook_url,omitempty" gorm:"type:varchar(2048)"`
+	Paused     bool      `json:"paused"` // Paused lists are not scanned for
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (w *Watchlist) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == "" {
+		w.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// WatchlistMatch is a transcript segment in which a watchlist term was found
+type WatchlistMatch struct {
+	ID              string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	WatchlistID     string    `json:"watchlist_id" gorm:"type:varchar(36);not null;index"`
+	TranscriptionID string    `json:"transcription_id" gorm:"type:varchar(36);not null;index"`
+	UserID          *uint     `json:"user_id,omitempty" gorm:"index"` // Owner of the transcription
+	Term            string    `json:"term" gorm:"type:varchar(255);not null"`
+	MatchType       string    `json:"match_type" gorm:"type:varchar(16);not null"` // exact, fuzzy or semantic
+	Score           float64   `json:"score"`                                       // 1 for exact matches
+	Text            string    `json:"text" gorm:"type:text"`                       // The matching segment
+	Start           float64   `json:"start"`                                       // Seconds into the recording
+	End             float64   `json:"end"`
+	Speaker         string    `json:"speaker,omitempty" gorm:"type:varchar(255)"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (m *WatchlistMatch) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = uuid.New().String()
+	}
+	return nil
+}
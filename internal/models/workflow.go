@@ -0,0 +1,73 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WorkflowStatus represents the status of a workflow run or one of its steps
+type WorkflowStatus string
+
+const (
+	WorkflowPending   WorkflowStatus = "pending"
+	WorkflowRunning   WorkflowStatus = "running"
+	WorkflowCompleted WorkflowStatus = "completed"
+	WorkflowFailed    WorkflowStatus = "failed"
+	// WorkflowSkipped marks a step that chose not to run (e.g. notifications not configured)
+	WorkflowSkipped WorkflowStatus = "skipped"
+	// WorkflowBlocked marks a step that can't run because a dependency failed
+	WorkflowBlocked WorkflowStatus = "blocked"
+	// WorkflowDeadLetter marks a failed step that used up its automatic retries; it only runs
+	// again when requeued
+	WorkflowDeadLetter WorkflowStatus = "dead_letter"
+)
+
+// WorkflowRun is one execution of a post-processing workflow for a transcription
+type WorkflowRun struct {
+	ID              string         `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TranscriptionID string         `json:"transcription_id" gorm:"type:varchar(36);not null;index"`
+	Workflow        string         `json:"workflow" gorm:"type:varchar(100);not null"`
+	Status          WorkflowStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	Params          *string        `json:"params,omitempty" gorm:"type:text"` // JSON-serialized map[string]string
+	CreatedAt       time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Steps []WorkflowStep `json:"steps,omitempty" gorm:"foreignKey:RunID"`
+}
+
+// BeforeCreate sets the ID if not already set
+func (wr *WorkflowRun) BeforeCreate(tx *gorm.DB) error {
+	if wr.ID == "" {
+		wr.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// WorkflowStep is the persisted state of a single step within a workflow run
+type WorkflowStep struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	RunID       string         `json:"run_id" gorm:"type:varchar(36);not null;index"`
+	Name        string         `json:"name" gorm:"type:varchar(100);not null"`
+	Position    int            `json:"position" gorm:"not null"`
+	DependsOn   string         `json:"depends_on" gorm:"type:text"` // Comma-separated step names
+	Status      WorkflowStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	Attempts    int            `json:"attempts" gorm:"not null;default:0"`
+	Output      *string        `json:"output,omitempty" gorm:"type:text"`
+	Error       *string        `json:"error,omitempty" gorm:"type:text"`
+	NextRetryAt *time.Time     `json:"next_retry_at,omitempty" gorm:"index"` // When a failed step is retried automatically
+	StartedAt   *time.Time     `json:"started_at,omitempty"`
+	CompletedAt *time.Time     `json:"completed_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Dependencies returns the names of the steps this step depends on
+func (ws *WorkflowStep) Dependencies() []string {
+	if ws.DependsOn == "" {
+		return nil
+	}
+	return strings.Split(ws.DependsOn, ",")
+}
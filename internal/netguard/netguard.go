@@ -0,0 +1,45 @@
+// Package netguard keeps requests to URLs users supply off the server's own networks, so
+// they can't reach services only the server can see.
+package netguard
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress is returned when a connection would be made to a private network
+var ErrPrivateAddress = errors.New("addresses on private networks can't be reached")
+
+// IsPrivateIP reports whether an address is on loopback, a private or link-local network,
+// or isn't a valid address
+func IsPrivateIP(ip net.IP) bool {
+	return ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast()
+}
+
+// Control is a net.Dialer Control function refusing loopback, private and link-local
+// addresses. It is checked on each connection, so redirects and DNS changes can't get
+// around it.
+func Control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if IsPrivateIP(net.ParseIP(host)) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// Client returns a client that only connects to public addresses, giving up on requests
+// after timeout. Proxies are ignored, since they would connect on the client's behalf.
+func Client(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, Control: Control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"scriberr/internal/netguard"
+)
+
+// Event is the payload delivered to notification endpoints
+type Event struct {
+	Type            string            `json:"type"`
+	TranscriptionID string            `json:"transcription_id"`
+	Title           string            `json:"title,omitempty"`
+	Data            map[string]string `json:"data,omitempty"`
+	Timestamp       time.Time         `json:"timestamp"`
+}
+
+// WebhookNotifier posts events as JSON to a configured URL
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier for the given URL; an empty URL disables delivery
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// NewUserWebhookNotifier creates a notifier for a URL a user set rather than the admin.
+// Unless allowPrivate is set it only connects to public addresses, so users can't have the
+// server post to its own networks.
+func NewUserWebhookNotifier(url string, allowPrivate bool) *WebhookNotifier {
+	if allowPrivate {
+		return NewWebhookNotifier(url)
+	}
+	return &WebhookNotifier{url: url, client: netguard.Client(30 * time.Second)}
+}
+
+// Enabled reports whether a webhook URL is configured
+func (n *WebhookNotifier) Enabled() bool {
+	return n.url != ""
+}
+
+// Send delivers an event to the webhook
+func (n *WebhookNotifier) Send(ctx context.Context, event Event) error {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.url, bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// The body is left out: errors reach users, and the server shouldn't relay what an
+	// address it was pointed at answers
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook error: %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package podcasts
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pubDateLayouts are the date formats found in the pubDate of feeds in the wild
+var pubDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC3339,
+}
+
+// Feed is a parsed podcast feed
+type Feed struct {
+	Title       string
+	Description string
+	ImageURL    string
+	Episodes    []Episode
+}
+
+// Episode is an item of a podcast feed with an audio enclosure
+type Episode struct {
+	GUID            string
+	Title           string
+	Description     string
+	AudioURL        string
+	PublishedAt     *time.Time
+	DurationSeconds int
+}
+
+// rssDocument is the part of an RSS feed that is read, including the iTunes podcast tags
+type rssDocument struct {
+	Channel struct {
+		Title       string `xml:"title"`
+		Description string `xml:"description"`
+		// Before Image, which would match itunes:image as well
+		ITunesImage struct {
+			Href string `xml:"href,attr"`
+		} `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+		Image struct {
+			URL string `xml:"url"`
+		} `xml:"image"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	GUID        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+	Description string `xml:"description"`
+	Enclosure   struct {
+		URL string `xml:"url,attr"`
+	} `xml:"enclosure"`
+	Duration string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+}
+
+// ParseFeed parses an RSS podcast feed. Items without an audio enclosure are left out, and
+// items without a guid are identified by their enclosure URL.
+func ParseFeed(r io.Reader) (*Feed, error) {
+	var doc rssDocument
+	decoder := xml.NewDecoder(r)
+	// Feeds declared as ISO-8859-1 or Windows-1252 are mostly ASCII; read them as is
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+	if doc.Channel.Title == "" && len(doc.Channel.Items) == 0 {
+		return nil, fmt.Errorf("not an RSS feed")
+	}
+
+	feed := &Feed{
+		Title:       strings.TrimSpace(doc.Channel.Title),
+		Description: strings.TrimSpace(doc.Channel.Description),
+		ImageURL:    strings.TrimSpace(doc.Channel.ITunesImage.Href),
+	}
+	if feed.ImageURL == "" {
+		feed.ImageURL = strings.TrimSpace(doc.Channel.Image.URL)
+	}
+	for _, item := range doc.Channel.Items {
+		audioURL := strings.TrimSpace(item.Enclosure.URL)
+		if audioURL == "" {
+			continue
+		}
+		episode := Episode{
+			GUID:            strings.TrimSpace(item.GUID),
+			Title:           strings.TrimSpace(item.Title),
+			Description:     strings.TrimSpace(item.Description),
+			AudioURL:        audioURL,
+			PublishedAt:     parsePubDate(item.PubDate),
+			DurationSeconds: parseDuration(item.Duration),
+		}
+		if episode.GUID == "" {
+			episode.GUID = audioURL
+		}
+		feed.Episodes = append(feed.Episodes, episode)
+	}
+	return feed, nil
+}
+
+// parsePubDate parses an item's pubDate, or returns nil if it is missing or unreadable
+func parsePubDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range pubDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// parseDuration parses an itunes:duration of seconds, MM:SS or HH:MM:SS, returning 0 if it
+// is missing or unreadable
+func parseDuration(value string) int {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	seconds := 0
+	for _, part := range strings.Split(value, ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+	return seconds
+}
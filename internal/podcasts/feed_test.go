@@ -0,0 +1,70 @@
+package podcasts
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+<channel>
+  <title>Deep Dives</title>
+  <description>Long conversations</description>
+  <image><url>https://example.com/small.png</url></image>
+  <itunes:image href="https://example.com/cover.jpg"/>
+  <item>
+    <title>Episode 2</title>
+    <guid isPermaLink="false">ep-2</guid>
+    <pubDate>Tue, 3 Feb 2026 08:00:00 +0000</pubDate>
+    <enclosure url="https://example.com/ep2.mp3" type="audio/mpeg" length="1234"/>
+    <itunes:duration>1:02:03</itunes:duration>
+  </item>
+  <item>
+    <title>Episode 1</title>
+    <pubDate>Mon, 26 Jan 2026 08:00:00 GMT</pubDate>
+    <enclosure url="https://example.com/ep1.mp3" type="audio/mpeg"/>
+    <itunes:duration>95</itunes:duration>
+  </item>
+  <item>
+    <title>Show notes only</title>
+    <guid>notes</guid>
+  </item>
+</channel>
+</rss>`
+
+func TestParseFeed(t *testing.T) {
+	feed, err := ParseFeed(strings.NewReader(sampleFeed))
+	require.NoError(t, err)
+	assert.Equal(t, "Deep Dives", feed.Title)
+	assert.Equal(t, "https://example.com/cover.jpg", feed.ImageURL, "the iTunes image is preferred")
+	require.Len(t, feed.Episodes, 2, "items without audio are left out")
+
+	latest := feed.Episodes[0]
+	assert.Equal(t, "ep-2", latest.GUID)
+	assert.Equal(t, "https://example.com/ep2.mp3", latest.AudioURL)
+	assert.Equal(t, 3723, latest.DurationSeconds)
+	require.NotNil(t, latest.PublishedAt)
+	assert.True(t, latest.PublishedAt.Equal(time.Date(2026, 2, 3, 8, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, "https://example.com/ep1.mp3", feed.Episodes[1].GUID, "without a guid the audio URL identifies the episode")
+	assert.Equal(t, 95, feed.Episodes[1].DurationSeconds)
+	assert.NotNil(t, feed.Episodes[1].PublishedAt)
+}
+
+func TestParseFeedRejectsOtherDocuments(t *testing.T) {
+	_, err := ParseFeed(strings.NewReader("<html><body>Sign in</body></html>"))
+	assert.Error(t, err)
+	_, err = ParseFeed(strings.NewReader("not xml"))
+	assert.Error(t, err)
+}
+
+func TestParseDuration(t *testing.T) {
+	assert.Equal(t, 0, parseDuration(""))
+	assert.Equal(t, 0, parseDuration("about an hour"))
+	assert.Equal(t, 125, parseDuration("2:05"))
+	assert.Equal(t, 3600, parseDuration("3600"))
+}
@@ -0,0 +1,264 @@
+// Package podcasts keeps podcast subscriptions up to date: it polls their RSS feeds, records
+// new episodes and hands them over to be downloaded and transcribed.
+package podcasts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+)
+
+const (
+	// MaxBackfill caps how many existing episodes a new subscription transcribes
+	MaxBackfill = 20
+	// maxImportsPerPoll caps the new episodes one poll transcribes, so a feed that republishes
+	// its archive doesn't flood the queue
+	maxImportsPerPoll = 10
+	// maxFeedBytes caps the size of a feed document
+	maxFeedBytes = 20 << 20
+	// pollTimeout bounds a background poll of all feeds
+	pollTimeout = 30 * time.Minute
+)
+
+// ImportFunc downloads an episode's audio and queues it for transcription, returning the ID
+// of the URL import doing so
+type ImportFunc func(feed *models.PodcastFeed, episode *models.PodcastEpisode) (string, error)
+
+// PollResult is what a poll of a feed found
+type PollResult struct {
+	NewEpisodes int  `json:"new_episodes"`
+	Imported    int  `json:"imported"`     // New episodes queued for transcription
+	NotModified bool `json:"not_modified"` // The feed hasn't changed since the last poll
+}
+
+// Service polls podcast feeds in the background
+type Service struct {
+	client   *http.Client
+	importer ImportFunc
+
+	mu   sync.Mutex // Polls run one at a time
+	stop chan struct{}
+}
+
+// NewService creates a podcast service that records episodes but doesn't import them until
+// SetImporter is called
+func NewService() *Service {
+	return &Service{client: &http.Client{Timeout: time.Minute}}
+}
+
+// SetClient sets the client feeds are fetched with
+func (s *Service) SetClient(client *http.Client) {
+	s.client = client
+}
+
+// SetImporter sets how the episodes of feeds that transcribe automatically are imported
+func (s *Service) SetImporter(importer ImportFunc) {
+	s.importer = importer
+}
+
+// Start polls every feed that isn't paused every interval. An interval of 0 disables polling.
+func (s *Service) Start(interval time.Duration) {
+	s.stop = make(chan struct{})
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.pollAll()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends polling
+func (s *Service) Stop() {
+	if s.stop != nil {
+		close(s.stop)
+	}
+}
+
+// pollAll polls every feed that isn't paused, logging failures
+func (s *Service) pollAll() {
+	ctx, cancel := context.WithTimeout(context.Background(), pollTimeout)
+	defer cancel()
+
+	var feeds []models.PodcastFeed
+	if err := database.DB.Where("paused = ?", false).Find(&feeds).Error; err != nil {
+		logger.ErrorContext(ctx, "Failed to list podcast feeds", "component", "podcasts", "error", err)
+		return
+	}
+	for i := range feeds {
+		if _, err := s.Poll(ctx, &feeds[i], 0); err != nil {
+			logger.WarnContext(ctx, "Failed to poll podcast feed", "component", "podcasts", "feed_id", feeds[i].ID, "url", feeds[i].URL, "error", err)
+		}
+	}
+}
+
+// Poll fetches a feed, records its new episodes and, if the feed transcribes automatically,
+// imports them, newest first. On the first poll of a feed every episode is new, so only the
+// newest backfill are imported. The outcome is saved as the feed's last poll.
+func (s *Service) Poll(ctx context.Context, feed *models.PodcastFeed, backfill int) (*PollResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.poll(ctx, feed, backfill)
+	now := time.Now()
+	feed.LastPolledAt = &now
+	feed.LastError = nil
+	if err != nil {
+		message := err.Error()
+		feed.LastError = &message
+	}
+	if dbErr := database.DB.Model(feed).Updates(map[string]interface{}{
+		"last_polled_at": feed.LastPolledAt,
+		"last_error":     feed.LastError,
+	}).Error; dbErr != nil {
+		logger.ErrorContext(ctx, "Failed to save podcast feed poll", "component", "podcasts", "feed_id", feed.ID, "error", dbErr)
+	}
+	return result, err
+}
+
+func (s *Service) poll(ctx context.Context, feed *models.PodcastFeed, backfill int) (*PollResult, error) {
+	firstPoll := feed.LastPolledAt == nil
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feed.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid feed url: %w", err)
+	}
+	if !firstPoll {
+		if feed.ETag != "" {
+			req.Header.Set("If-None-Match", feed.ETag)
+		}
+		if feed.LastModified != "" {
+			req.Header.Set("If-Modified-Since", feed.LastModified)
+		}
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		return &PollResult{NotModified: true}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feed request failed: %s", resp.Status)
+	}
+	parsed, err := ParseFeed(io.LimitReader(resp.Body, maxFeedBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	feed.Title = parsed.Title
+	if feed.Title == "" {
+		feed.Title = feed.URL
+	}
+	feed.Description = optional(parsed.Description)
+	feed.ImageURL = optional(parsed.ImageURL)
+	feed.ETag = resp.Header.Get("ETag")
+	feed.LastModified = resp.Header.Get("Last-Modified")
+	if err := database.DB.Model(feed).Updates(map[string]interface{}{
+		"title":         feed.Title,
+		"description":   feed.Description,
+		"image_url":     feed.ImageURL,
+		"e_tag":         feed.ETag,
+		"last_modified": feed.LastModified,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to save feed: %w", err)
+	}
+
+	fresh, err := recordEpisodes(feed, parsed.Episodes)
+	if err != nil {
+		return nil, err
+	}
+	result := &PollResult{NewEpisodes: len(fresh)}
+	if s.importer == nil || !feed.AutoTranscribe {
+		return result, nil
+	}
+
+	limit := maxImportsPerPoll
+	if firstPoll {
+		limit = backfill
+		if limit > MaxBackfill {
+			limit = MaxBackfill
+		}
+	}
+	sort.SliceStable(fresh, func(i, j int) bool {
+		a, b := fresh[i].PublishedAt, fresh[j].PublishedAt
+		return a != nil && (b == nil || a.After(*b))
+	})
+	for i := range fresh {
+		if result.Imported >= limit {
+			break
+		}
+		episode := &fresh[i]
+		importID, err := s.importer(feed, episode)
+		if err != nil {
+			logger.WarnContext(ctx, "Failed to import podcast episode", "component", "podcasts", "feed_id", feed.ID, "episode_id", episode.ID, "error", err)
+			continue
+		}
+		episode.ImportID = &importID
+		if err := database.DB.Model(episode).Update("import_id", importID).Error; err != nil {
+			logger.ErrorContext(ctx, "Failed to record podcast episode import", "component", "podcasts", "episode_id", episode.ID, "error", err)
+		}
+		result.Imported++
+	}
+	return result, nil
+}
+
+// recordEpisodes saves the episodes not yet known for a feed and returns them
+func recordEpisodes(feed *models.PodcastFeed, episodes []Episode) ([]models.PodcastEpisode, error) {
+	var known []string
+	if err := database.DB.Model(&models.PodcastEpisode{}).Where("feed_id = ?", feed.ID).Pluck("guid", &known).Error; err != nil {
+		return nil, fmt.Errorf("failed to list episodes: %w", err)
+	}
+	seen := make(map[string]bool, len(known))
+	for _, guid := range known {
+		seen[guid] = true
+	}
+
+	var fresh []models.PodcastEpisode
+	for _, item := range episodes {
+		if seen[item.GUID] {
+			continue
+		}
+		seen[item.GUID] = true
+		episode := models.PodcastEpisode{
+			FeedID:          feed.ID,
+			UserID:          feed.UserID,
+			GUID:            item.GUID,
+			Title:           item.Title,
+			Description:     optional(item.Description),
+			AudioURL:        item.AudioURL,
+			PublishedAt:     item.PublishedAt,
+			DurationSeconds: item.DurationSeconds,
+		}
+		if err := database.DB.Create(&episode).Error; err != nil {
+			return nil, fmt.Errorf("failed to save episode: %w", err)
+		}
+		fresh = append(fresh, episode)
+	}
+	return fresh, nil
+}
+
+// optional returns nil for an empty string
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
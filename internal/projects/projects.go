@@ -0,0 +1,96 @@
+// Package projects walks the hierarchy of projects, the folders transcriptions are filed in,
+// and works out the settings each project inherits from the projects it is in.
+package projects
+
+import (
+	"fmt"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+)
+
+// Path returns a project and the projects it is in, the project itself first and the top-level
+// project last. It is empty when the project doesn't exist.
+func Path(projectID string) ([]models.Project, error) {
+	var path []models.Project
+	err := database.DB.Raw(`WITH RECURSIVE path(id, depth) AS (
+		SELECT id, 0 FROM projects WHERE id = ?
+		UNION
+		SELECT projects.parent_id, path.depth + 1 FROM projects JOIN path ON projects.id = path.id
+		WHERE projects.parent_id IS NOT NULL AND path.depth < 1000
+	)
+	SELECT projects.* FROM projects JOIN path ON projects.id = path.id ORDER BY path.depth`, projectID).Scan(&path).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the path of project %s: %w", projectID, err)
+	}
+	return path, nil
+}
+
+// Subtree returns the IDs of a project and every project nested in it, however deeply
+func Subtree(projectID string) ([]string, error) {
+	var ids []string
+	err := database.DB.Raw(`WITH RECURSIVE subtree(id) AS (
+		SELECT id FROM projects WHERE id = ?
+		UNION
+		SELECT projects.id FROM projects JOIN subtree ON projects.parent_id = subtree.id
+	)
+	SELECT id FROM subtree`, projectID).Scan(&ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the subprojects of project %s: %w", projectID, err)
+	}
+	return ids, nil
+}
+
+// Contains reports whether a project is the other project or nested in it
+func Contains(projectID, otherID string) (bool, error) {
+	ids, err := Subtree(projectID)
+	if err != nil {
+		return false, err
+	}
+	for _, id := range ids {
+		if id == otherID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Settings returns the settings a project gives its transcriptions: each setting of the
+// nearest project on its path that sets it
+func Settings(projectID string) (models.ProjectSettings, error) {
+	path, err := Path(projectID)
+	if err != nil {
+		return models.ProjectSettings{}, err
+	}
+	var settings models.ProjectSettings
+	for _, project := range path {
+		if settings.SummaryTemplateID == nil {
+			settings.SummaryTemplateID = project.SummaryTemplateID
+		}
+		if settings.ContentType == "" {
+			settings.ContentType = project.ContentType
+		}
+		if len(settings.Tags) == 0 {
+			settings.Tags = project.Tags
+		}
+		if len(settings.WebhookURLs) == 0 {
+			settings.WebhookURLs = project.WebhookURLs
+		}
+		if settings.AudioRetentionDays == nil {
+			settings.AudioRetentionDays = project.AudioRetentionDays
+		}
+		if settings.RetentionDays == nil {
+			settings.RetentionDays = project.RetentionDays
+		}
+	}
+	return settings, nil
+}
+
+// ForJob returns the settings of the project a job is filed in, which are empty when it
+// isn't in one
+func ForJob(job *models.TranscriptionJob) (models.ProjectSettings, error) {
+	if job.ProjectID == nil {
+		return models.ProjectSettings{}, nil
+	}
+	return Settings(*job.ProjectID)
+}
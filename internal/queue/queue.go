@@ -2,18 +2,22 @@ package queue
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"os"
 	"os/exec"
 	"runtime"
+	"sort"
 	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"scriberr/internal/database"
+	"scriberr/internal/events"
 	"scriberr/internal/models"
+	"scriberr/internal/quota"
+	"scriberr/internal/tracing"
 	"scriberr/pkg/logger"
 )
 
@@ -23,12 +27,53 @@ type RunningJob struct {
 	Process *exec.Cmd
 }
 
+const (
+	// queueCapacity is how many jobs can wait for a worker; pending jobs beyond it are picked
+	// up by the job scanner as room frees up
+	queueCapacity = 200
+	// MinPriority and MaxPriority bound job priorities; higher runs first, 0 is the default
+	MinPriority = -10
+	MaxPriority = 10
+	// MaxConcurrency caps the workers SetConcurrency allows
+	MaxConcurrency = 32
+	// HeartbeatInterval is how often workers report their running jobs alive
+	HeartbeatInterval = 30 * time.Second
+)
+
+// ErrNotQueued is returned when reordering a job that isn't waiting in the queue
+var ErrNotQueued = errors.New("job is not waiting in the queue")
+
+// errNotPending is returned when a worker claims a job that is no longer pending, because
+// another node's worker took it or it was cancelled or deleted while it waited
+var errNotPending = errors.New("job is no longer pending")
+
+// queuedJob is a job waiting for a worker. Jobs run by priority, highest first, and then by
+// order, lowest first, which is the order they were enqueued in unless they were moved.
+type queuedJob struct {
+	id       string
+	priority int
+	order    float64
+}
+
+func (a queuedJob) before(b queuedJob) bool {
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	return a.order < b.order
+}
+
+// QueuedJob is a job waiting in the queue and its place in it, 1 being next
+type QueuedJob struct {
+	ID       string `json:"id"`
+	Priority int    `json:"priority"`
+	Position int    `json:"position"`
+}
+
 // TaskQueue manages transcription job processing
 type TaskQueue struct {
 	minWorkers    int
 	maxWorkers    int
 	currentWorkers int64 // Use atomic for thread-safe access
-	jobChannel    chan string
 	ctx           context.Context
 	cancel        context.CancelFunc
 	wg            sync.WaitGroup
@@ -38,6 +83,20 @@ type TaskQueue struct {
 	workerMutex   sync.Mutex
 	autoScale     bool
 	lastScaleTime time.Time
+
+	// Waiting jobs in the order they run, guarded by queueMutex; workers wait on queueReady
+	queueMutex    sync.Mutex
+	queueReady    *sync.Cond
+	queued        []queuedJob
+	starting      map[string]bool // Taken off the queue but not yet running
+	nextOrder     float64
+	paused        bool
+	stopped       bool
+	activeWorkers int // Worker goroutines alive; those beyond currentWorkers retire when idle
+	nextWorkerID  int
+
+	nodeID    string          // Identifies this process among the nodes sharing the database
+	abandoned map[string]bool // Running jobs handed over by the watchdog, guarded by jobsMutex
 }
 
 // JobProcessor defines the interface for processing jobs
@@ -94,18 +153,22 @@ func NewTaskQueue(legacyWorkers int, processor JobProcessor) *TaskQueue {
 		autoScale = false // Disable auto-scaling if min == max
 	}
 
-	return &TaskQueue{
+	tq := &TaskQueue{
 		minWorkers:     min,
 		maxWorkers:     max,
 		currentWorkers: int64(min),
-		jobChannel:     make(chan string, 200), // Increased buffer for better throughput
 		ctx:            ctx,
 		cancel:         cancel,
 		processor:      processor,
 		runningJobs:    make(map[string]*RunningJob),
 		autoScale:      autoScale,
 		lastScaleTime:  time.Now(),
+		starting:       make(map[string]bool),
+		nodeID:         defaultNodeID(),
+		abandoned:      make(map[string]bool),
 	}
+	tq.queueReady = sync.NewCond(&tq.queueMutex)
+	return tq
 }
 
 // Start starts the task queue workers
@@ -117,16 +180,19 @@ func (tq *TaskQueue) Start() {
 		"max_workers", tq.maxWorkers, 
 		"auto_scale", tq.autoScale)
 
-	// Start initial workers
-	for i := 0; i < workers; i++ {
-		tq.wg.Add(1)
-		go tq.worker(i)
-	}
+	// Start initial workers; SetConcurrency may already have started some
+	tq.queueMutex.Lock()
+	tq.spawnWorkers(workers - tq.activeWorkers)
+	tq.queueMutex.Unlock()
 
 	// Start the job scanner
 	tq.wg.Add(1)
 	go tq.jobScanner()
 
+	// Report running jobs alive
+	tq.wg.Add(1)
+	go tq.heartbeats()
+
 	// Start auto-scaling monitor if enabled
 	if tq.autoScale {
 		tq.wg.Add(1)
@@ -138,96 +204,288 @@ func (tq *TaskQueue) Start() {
 func (tq *TaskQueue) Stop() {
 	logger.Debug("Stopping task queue")
 	tq.cancel()
-	close(tq.jobChannel)
+	tq.queueMutex.Lock()
+	tq.stopped = true
+	tq.queueReady.Broadcast()
+	tq.queueMutex.Unlock()
 	tq.wg.Wait()
 	logger.Debug("Task queue stopped")
 }
 
-// EnqueueJob adds a job to the queue
+// EnqueueJob adds a job to the queue behind the waiting jobs of the same or higher priority.
+// A job already waiting keeps its place, and a running job isn't queued again.
 func (tq *TaskQueue) EnqueueJob(jobID string) error {
-	select {
-	case tq.jobChannel <- jobID:
-		return nil
-	case <-tq.ctx.Done():
+	if tq.ctx.Err() != nil {
 		return fmt.Errorf("queue is shutting down")
-	default:
+	}
+
+	// Jobs that were never saved run at the default priority
+	var priority int
+	database.DB.Model(&models.TranscriptionJob{}).Select("priority").Where("id = ?", jobID).Scan(&priority)
+
+	tq.queueMutex.Lock()
+	defer tq.queueMutex.Unlock()
+	if tq.stopped {
+		return fmt.Errorf("queue is shutting down")
+	}
+	if tq.indexOf(jobID) >= 0 || tq.starting[jobID] || tq.IsJobRunning(jobID) {
+		return nil
+	}
+	if len(tq.queued) >= queueCapacity {
 		return fmt.Errorf("queue is full")
 	}
+	tq.nextOrder++
+	tq.insert(queuedJob{id: jobID, priority: priority, order: tq.nextOrder})
+	tq.queueReady.Signal()
+	return nil
+}
+
+// insert places a job in the queue by priority and order; queueMutex must be held
+func (tq *TaskQueue) insert(job queuedJob) {
+	i := sort.Search(len(tq.queued), func(i int) bool { return job.before(tq.queued[i]) })
+	tq.queued = append(tq.queued, queuedJob{})
+	copy(tq.queued[i+1:], tq.queued[i:])
+	tq.queued[i] = job
+}
+
+// indexOf returns where a job waits in the queue, or -1; queueMutex must be held
+func (tq *TaskQueue) indexOf(jobID string) int {
+	for i, job := range tq.queued {
+		if job.id == jobID {
+			return i
+		}
+	}
+	return -1
+}
+
+// remove takes a waiting job out of the queue; queueMutex must be held
+func (tq *TaskQueue) remove(i int) queuedJob {
+	job := tq.queued[i]
+	tq.queued = append(tq.queued[:i], tq.queued[i+1:]...)
+	return job
+}
+
+// spawnWorkers starts n more workers; queueMutex must be held
+func (tq *TaskQueue) spawnWorkers(n int) {
+	for i := 0; i < n; i++ {
+		tq.activeWorkers++
+		tq.wg.Add(1)
+		go tq.worker(tq.nextWorkerID)
+		tq.nextWorkerID++
+	}
+}
+
+// next blocks until a worker can take the next job. It returns false when the queue stops or
+// there are more workers than wanted, and the worker should exit.
+func (tq *TaskQueue) next() (string, bool) {
+	tq.queueMutex.Lock()
+	defer tq.queueMutex.Unlock()
+	for {
+		if tq.stopped {
+			return "", false
+		}
+		if tq.activeWorkers > int(atomic.LoadInt64(&tq.currentWorkers)) {
+			tq.activeWorkers--
+			return "", false
+		}
+		if !tq.paused && len(tq.queued) > 0 {
+			job := tq.remove(0)
+			tq.starting[job.id] = true
+			return job.id, true
+		}
+		tq.queueReady.Wait()
+	}
+}
+
+// started marks a job taken off the queue as no longer starting
+func (tq *TaskQueue) started(jobID string) {
+	tq.queueMutex.Lock()
+	delete(tq.starting, jobID)
+	tq.queueMutex.Unlock()
 }
 
-// worker processes jobs from the channel
+// worker processes jobs from the queue
 func (tq *TaskQueue) worker(id int) {
 	defer tq.wg.Done()
 
 	logger.Debug("Worker started", "worker_id", id)
 
 	for {
-		select {
-		case jobID, ok := <-tq.jobChannel:
-			if !ok {
-				logger.Debug("Worker stopped", "worker_id", id)
-				return
-			}
+		jobID, ok := tq.next()
+		if !ok {
+			logger.Debug("Worker stopped", "worker_id", id)
+			return
+		}
 
-			logger.WorkerOperation(id, jobID, "start")
+		logger.WorkerOperation(id, jobID, "start")
 
-			// Update job status to processing
-			if err := tq.updateJobStatus(jobID, models.StatusProcessing); err != nil {
+		// Update job status to processing
+		if err := tq.claim(jobID, id); err != nil {
+			if errors.Is(err, errNotPending) {
+				logger.Info("Skipping job that is no longer pending", "worker_id", id, "job_id", jobID)
+			} else {
 				logger.Error("Failed to update job status", "worker_id", id, "job_id", jobID, "error", err)
-				continue
 			}
+			tq.started(jobID)
+			continue
+		}
+		events.RecordForJob(models.EventJobStarted, jobID, nil)
 
-			// Create context for this job and track it
-			jobCtx, jobCancel := context.WithCancel(tq.ctx)
-			runningJob := &RunningJob{
-				Cancel:  jobCancel,
-				Process: nil, // Will be set by registerProcess callback
-			}
+		// Create context for this job and track it; lines logged with it go to the job's log
+		jobCtx, jobCancel := context.WithCancel(logger.WithJob(tq.ctx, jobID))
+		runningJob := &RunningJob{
+			Cancel:  jobCancel,
+			Process: nil, // Will be set by registerProcess callback
+		}
+
+		tq.jobsMutex.Lock()
+		tq.runningJobs[jobID] = runningJob
+		tq.jobsMutex.Unlock()
+		tq.started(jobID)
 
+		// Register process callback
+		registerProcess := func(cmd *exec.Cmd) {
 			tq.jobsMutex.Lock()
-			tq.runningJobs[jobID] = runningJob
+			if job, exists := tq.runningJobs[jobID]; exists {
+				job.Process = cmd
+			}
 			tq.jobsMutex.Unlock()
+		}
 
-			// Register process callback
-			registerProcess := func(cmd *exec.Cmd) {
-				tq.jobsMutex.Lock()
-				if job, exists := tq.runningJobs[jobID]; exists {
-					job.Process = cmd
-				}
-				tq.jobsMutex.Unlock()
+		// Process the job with process registration, traced along with its post-processing
+		spanCtx, span := tracing.Start(tracing.ForJob(jobCtx, jobID), "transcription.job", "job.id", jobID, "worker.id", id, "node.id", tq.nodeID)
+		err := tq.processor.ProcessJobWithProcess(spanCtx, jobID, registerProcess)
+		span.RecordError(err)
+		span.End()
+
+		// Remove job from running jobs
+		tq.jobsMutex.Lock()
+		delete(tq.runningJobs, jobID)
+		abandoned := tq.abandoned[jobID]
+		delete(tq.abandoned, jobID)
+		tq.jobsMutex.Unlock()
+
+		// Handle result
+		if abandoned {
+			// The watchdog has already requeued or failed it
+			logger.Info("Abandoned job stopped", "worker_id", id, "job_id", jobID)
+		} else if err != nil {
+			if jobCtx.Err() == context.Canceled {
+				logger.Info("Job cancelled", "worker_id", id, "job_id", jobID)
+				tq.updateJobStatus(jobID, models.StatusFailed)
+				tq.updateJobError(jobID, "Job was cancelled by user")
+				events.RecordForJob(models.EventJobFailed, jobID, map[string]interface{}{"error": "Job was cancelled by user", "cancelled": true})
+			} else {
+				logger.Error("Job processing failed", "worker_id", id, "job_id", jobID, "error", err)
+				tq.updateJobStatus(jobID, models.StatusFailed)
+				tq.updateJobError(jobID, err.Error())
+				events.RecordForJob(models.EventJobFailed, jobID, map[string]interface{}{"error": err.Error()})
 			}
+		} else {
+			logger.Debug("Job processed successfully", "worker_id", id, "job_id", jobID)
+			tq.updateJobStatus(jobID, models.StatusCompleted)
+			quota.RecordTranscription(jobID)
+			events.RecordForJob(models.EventJobCompleted, jobID, nil)
+		}
+	}
+}
 
-			// Process the job with process registration
-			err := tq.processor.ProcessJobWithProcess(jobCtx, jobID, registerProcess)
+// defaultNodeID names this process by host and process ID
+func defaultNodeID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "localhost"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
 
-			// Remove job from running jobs
-			tq.jobsMutex.Lock()
-			delete(tq.runningJobs, jobID)
-			tq.jobsMutex.Unlock()
+// NodeID returns the name this process gives its workers
+func (tq *TaskQueue) NodeID() string {
+	return tq.nodeID
+}
 
-			// Handle result
-			if err != nil {
-				if jobCtx.Err() == context.Canceled {
-					logger.Info("Job cancelled", "worker_id", id, "job_id", jobID)
-					tq.updateJobStatus(jobID, models.StatusFailed)
-					tq.updateJobError(jobID, "Job was cancelled by user")
-				} else {
-					logger.Error("Job processing failed", "worker_id", id, "job_id", jobID, "error", err)
-					tq.updateJobStatus(jobID, models.StatusFailed)
-					tq.updateJobError(jobID, err.Error())
-				}
-			} else {
-				logger.Debug("Job processed successfully", "worker_id", id, "job_id", jobID)
-				tq.updateJobStatus(jobID, models.StatusCompleted)
-			}
+// claim marks a pending job as processing by one of this node's workers. Only one worker
+// across the nodes sharing the database can claim a job.
+func (tq *TaskQueue) claim(jobID string, workerID int) error {
+	now := time.Now()
+	result := database.DB.Model(&models.TranscriptionJob{}).
+		Where("id = ? AND status = ?", jobID, models.StatusPending).
+		Updates(map[string]interface{}{
+			"status":                models.StatusProcessing,
+			"worker_id":             fmt.Sprintf("%s/worker-%d", tq.nodeID, workerID),
+			"processing_started_at": now,
+			"heartbeat_at":          now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != 1 {
+		return errNotPending
+	}
+	return nil
+}
 
+// heartbeats reports the jobs running on this node alive every HeartbeatInterval
+func (tq *TaskQueue) heartbeats() {
+	defer tq.wg.Done()
+
+	ticker := time.NewTicker(HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			tq.beat()
 		case <-tq.ctx.Done():
-			logger.Debug("Worker stopped", "worker_id", id, "reason", "context_cancelled")
 			return
 		}
 	}
 }
 
+// beat updates the heartbeat of the jobs running on this node
+func (tq *TaskQueue) beat() {
+	tq.jobsMutex.RLock()
+	ids := make([]string, 0, len(tq.runningJobs))
+	for id := range tq.runningJobs {
+		if !tq.abandoned[id] {
+			ids = append(ids, id)
+		}
+	}
+	tq.jobsMutex.RUnlock()
+	if len(ids) == 0 {
+		return
+	}
+	if err := database.DB.Model(&models.TranscriptionJob{}).
+		Where("id IN ? AND status = ?", ids, models.StatusProcessing).
+		Update("heartbeat_at", time.Now()).Error; err != nil {
+		logger.Warn("Failed to record worker heartbeats", "error", err)
+	}
+}
+
+// Abandon stops a job running on this node without touching its status, which the caller
+// takes over. It reports whether the job was running here.
+func (tq *TaskQueue) Abandon(jobID string) bool {
+	tq.jobsMutex.Lock()
+	defer tq.jobsMutex.Unlock()
+	runningJob, exists := tq.runningJobs[jobID]
+	if !exists {
+		return false
+	}
+	tq.abandoned[jobID] = true
+	if mtProcessor, ok := tq.processor.(MultiTrackJobProcessor); ok && mtProcessor.IsMultiTrackJob(jobID) {
+		if err := mtProcessor.TerminateMultiTrackJob(jobID); err != nil {
+			logger.Error("Failed to terminate multi-track job", "job_id", jobID, "error", err)
+		}
+	}
+	if runningJob.Process != nil && runningJob.Process.Process != nil {
+		if err := killProcessTree(runningJob.Process.Process); err != nil {
+			_ = runningJob.Process.Process.Kill()
+		}
+	}
+	runningJob.Cancel()
+	return true
+}
+
 // jobScanner scans for pending jobs and adds them to the queue
 func (tq *TaskQueue) jobScanner() {
 	defer tq.wg.Done()
@@ -248,26 +506,149 @@ func (tq *TaskQueue) jobScanner() {
 	}
 }
 
-// scanPendingJobs finds pending jobs and enqueues them
+// scanPendingJobs finds pending jobs that aren't queued, e.g. after a restart, and enqueues
+// them by priority and then age
 func (tq *TaskQueue) scanPendingJobs() {
 	var jobs []models.TranscriptionJob
 
-	if err := database.DB.Where("status = ?", models.StatusPending).Find(&jobs).Error; err != nil {
+	if err := database.DB.Select("id").Where("status = ?", models.StatusPending).
+		Order("priority DESC, created_at ASC").Find(&jobs).Error; err != nil {
 		logger.Error("Failed to scan pending jobs", "error", err)
 		return
 	}
 
 	for _, job := range jobs {
-		select {
-		case tq.jobChannel <- job.ID:
-			logger.Debug("Enqueued pending job", "job_id", job.ID)
-		default:
-			logger.Warn("Queue full, skipping job", "job_id", job.ID)
-			break
+		if err := tq.EnqueueJob(job.ID); err != nil {
+			logger.Warn("Could not enqueue pending job", "job_id", job.ID, "error", err)
+			return
 		}
 	}
 }
 
+// Queued returns the jobs waiting for a worker, next first
+func (tq *TaskQueue) Queued() []QueuedJob {
+	tq.queueMutex.Lock()
+	defer tq.queueMutex.Unlock()
+	jobs := make([]QueuedJob, len(tq.queued))
+	for i, job := range tq.queued {
+		jobs[i] = QueuedJob{ID: job.id, Priority: job.priority, Position: i + 1}
+	}
+	return jobs
+}
+
+// Position returns a job's place in the queue, 1 being next, or 0 if it isn't waiting
+func (tq *TaskQueue) Position(jobID string) int {
+	tq.queueMutex.Lock()
+	defer tq.queueMutex.Unlock()
+	return tq.indexOf(jobID) + 1
+}
+
+// SetPriority saves a job's priority and, if it is waiting, moves it to its new place. It
+// keeps its order among jobs of the new priority.
+func (tq *TaskQueue) SetPriority(jobID string, priority int) error {
+	if priority < MinPriority || priority > MaxPriority {
+		return fmt.Errorf("priority must be between %d and %d", MinPriority, MaxPriority)
+	}
+	tq.queueMutex.Lock()
+	defer tq.queueMutex.Unlock()
+	if err := database.DB.Model(&models.TranscriptionJob{}).Where("id = ?", jobID).Update("priority", priority).Error; err != nil {
+		return fmt.Errorf("failed to save priority: %w", err)
+	}
+	if i := tq.indexOf(jobID); i >= 0 {
+		job := tq.remove(i)
+		job.priority = priority
+		tq.insert(job)
+	}
+	return nil
+}
+
+// Move puts a waiting job at a place in the queue, 1 being next, and returns it in its new place.
+// The job takes the priority of the job it lands in front of, or behind when moved to the
+// end, so the move holds as other jobs come in; the priority is saved, while the order among
+// jobs of the same priority lasts until a restart.
+func (tq *TaskQueue) Move(jobID string, position int) (QueuedJob, error) {
+	tq.queueMutex.Lock()
+	defer tq.queueMutex.Unlock()
+	i := tq.indexOf(jobID)
+	if i < 0 {
+		return QueuedJob{}, ErrNotQueued
+	}
+	job := tq.remove(i)
+	position = max(1, min(position, len(tq.queued)+1))
+
+	switch {
+	case len(tq.queued) == 0:
+	case position > len(tq.queued):
+		last := tq.queued[len(tq.queued)-1]
+		job.priority, job.order = last.priority, last.order+1
+	default:
+		target := tq.queued[position-1]
+		previous := target.order - 1
+		if position > 1 && tq.queued[position-2].priority == target.priority {
+			previous = tq.queued[position-2].order
+		}
+		job.priority, job.order = target.priority, (previous+target.order)/2
+	}
+	if err := database.DB.Model(&models.TranscriptionJob{}).Where("id = ?", jobID).Update("priority", job.priority).Error; err != nil {
+		tq.insert(job)
+		return QueuedJob{}, fmt.Errorf("failed to save priority: %w", err)
+	}
+	tq.insert(job)
+	return QueuedJob{ID: jobID, Priority: job.priority, Position: tq.indexOf(jobID) + 1}, nil
+}
+
+// Pause stops workers from taking new jobs; jobs already running finish
+func (tq *TaskQueue) Pause() {
+	tq.queueMutex.Lock()
+	tq.paused = true
+	tq.queueMutex.Unlock()
+	logger.Info("Task queue paused")
+}
+
+// Resume lets workers take jobs again
+func (tq *TaskQueue) Resume() {
+	tq.queueMutex.Lock()
+	tq.paused = false
+	tq.queueReady.Broadcast()
+	tq.queueMutex.Unlock()
+	logger.Info("Task queue resumed")
+}
+
+// IsPaused reports whether the queue is paused
+func (tq *TaskQueue) IsPaused() bool {
+	tq.queueMutex.Lock()
+	defer tq.queueMutex.Unlock()
+	return tq.paused
+}
+
+// SetConcurrency fixes how many jobs run at once, turning off auto-scaling. Extra workers
+// exit once their current job is done.
+func (tq *TaskQueue) SetConcurrency(workers int) error {
+	if workers < 1 || workers > MaxConcurrency {
+		return fmt.Errorf("workers must be between 1 and %d", MaxConcurrency)
+	}
+	tq.workerMutex.Lock()
+	tq.minWorkers, tq.maxWorkers, tq.autoScale = workers, workers, false
+	tq.workerMutex.Unlock()
+	tq.scaleTo(workers)
+	logger.Info("Task queue concurrency set", "workers", workers)
+	return nil
+}
+
+// scaleTo sets the number of workers, starting new ones or waking idle ones to retire
+func (tq *TaskQueue) scaleTo(workers int) {
+	tq.queueMutex.Lock()
+	defer tq.queueMutex.Unlock()
+	atomic.StoreInt64(&tq.currentWorkers, int64(workers))
+	if tq.stopped {
+		return
+	}
+	if workers > tq.activeWorkers {
+		tq.spawnWorkers(workers - tq.activeWorkers)
+	}
+	tq.queueReady.Broadcast()
+}
+
 // KillJob aggressively terminates a running job
 func (tq *TaskQueue) KillJob(jobID string) error {
 	tq.jobsMutex.Lock()
@@ -294,7 +675,7 @@ func (tq *TaskQueue) KillJob(jobID string) error {
 	if runningJob.Process != nil && runningJob.Process.Process != nil {
 		logger.Debug("Terminating process tree", "pid", runningJob.Process.Process.Pid, "job_id", jobID)
 		if err := killProcessTree(runningJob.Process.Process); err != nil {
-			log.Printf("Failed to terminate process tree for job %s: %v, trying direct kill()", jobID, err)
+			logger.Warn("Failed to terminate process tree, trying direct kill()", "component", "queue", "job_id", jobID, "error", err)
 			_ = runningJob.Process.Process.Kill()
 		}
 	}
@@ -351,14 +732,14 @@ func (tq *TaskQueue) autoScaler() {
 	ticker := time.NewTicker(30 * time.Second) // Check every 30 seconds
 	defer ticker.Stop()
 
-	log.Println("Auto-scaler started")
+	logger.Info("Auto-scaler started", "component", "queue")
 
 	for {
 		select {
 		case <-ticker.C:
 			tq.checkAndScale()
 		case <-tq.ctx.Done():
-			log.Println("Auto-scaler stopped")
+			logger.Info("Auto-scaler stopped", "component", "queue")
 			return
 		}
 	}
@@ -371,7 +752,16 @@ func (tq *TaskQueue) checkAndScale() {
 		return
 	}
 
-	queueSize := len(tq.jobChannel)
+	tq.workerMutex.Lock()
+	autoScale, minWorkers, maxWorkers := tq.autoScale, tq.minWorkers, tq.maxWorkers
+	tq.workerMutex.Unlock()
+	if !autoScale {
+		return // Concurrency was fixed since the queue started
+	}
+
+	tq.queueMutex.Lock()
+	queueSize := len(tq.queued)
+	tq.queueMutex.Unlock()
 	currentWorkers := int(atomic.LoadInt64(&tq.currentWorkers))
 	
 	tq.jobsMutex.RLock()
@@ -379,26 +769,22 @@ func (tq *TaskQueue) checkAndScale() {
 	tq.jobsMutex.RUnlock()
 
 	// Scale up if queue is building up and we have capacity
-	if queueSize > 10 && currentWorkers < tq.maxWorkers {
+	if queueSize > 10 && currentWorkers < maxWorkers {
 		newWorkerCount := currentWorkers + 1
-		log.Printf("Scaling up workers: %d -> %d (queue size: %d)", currentWorkers, newWorkerCount, queueSize)
+		logger.Info("Scaling up workers", "component", "queue", "from", currentWorkers, "to", newWorkerCount, "queue_size", queueSize)
 		
-		atomic.StoreInt64(&tq.currentWorkers, int64(newWorkerCount))
-		tq.wg.Add(1)
-		go tq.worker(newWorkerCount - 1)
+		tq.scaleTo(newWorkerCount)
 		tq.lastScaleTime = time.Now()
 		
 	// Scale down if queue is empty and minimal jobs running
-	} else if queueSize == 0 && runningJobsCount <= 1 && currentWorkers > tq.minWorkers {
+	} else if queueSize == 0 && runningJobsCount <= 1 && currentWorkers > minWorkers {
 		newWorkerCount := currentWorkers - 1
-		log.Printf("Scaling down workers: %d -> %d (queue size: %d, running: %d)", 
-			currentWorkers, newWorkerCount, queueSize, runningJobsCount)
+		logger.Info("Scaling down workers", "component", "queue", "from", currentWorkers, "to", newWorkerCount,
+			"queue_size", queueSize, "running", runningJobsCount)
 		
-		atomic.StoreInt64(&tq.currentWorkers, int64(newWorkerCount))
+		// An idle worker exits; a busy one once its job is done
+		tq.scaleTo(newWorkerCount)
 		tq.lastScaleTime = time.Now()
-		
-		// Note: We don't actively stop workers here. They will naturally exit 
-		// when no more jobs are available and the queue empties.
 	}
 }
 
@@ -415,13 +801,21 @@ func (tq *TaskQueue) GetQueueStats() map[string]interface{} {
 	runningJobsCount := len(tq.runningJobs)
 	tq.jobsMutex.RUnlock()
 
+	tq.queueMutex.Lock()
+	queueSize, paused := len(tq.queued), tq.paused
+	tq.queueMutex.Unlock()
+	tq.workerMutex.Lock()
+	minWorkers, maxWorkers, autoScale := tq.minWorkers, tq.maxWorkers, tq.autoScale
+	tq.workerMutex.Unlock()
+
 	return map[string]interface{}{
-		"queue_size":       len(tq.jobChannel),
-		"queue_capacity":   cap(tq.jobChannel),
+		"queue_size":       queueSize,
+		"queue_capacity":   queueCapacity,
+		"paused":           paused,
 		"current_workers":  int(atomic.LoadInt64(&tq.currentWorkers)),
-		"min_workers":      tq.minWorkers,
-		"max_workers":      tq.maxWorkers,
-		"auto_scale":       tq.autoScale,
+		"min_workers":      minWorkers,
+		"max_workers":      maxWorkers,
+		"auto_scale":       autoScale,
 		"running_jobs":     runningJobsCount,
 		"pending_jobs":     pendingCount,
 		"processing_jobs":  processingCount,
@@ -0,0 +1,263 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/events"
+	"scriberr/internal/models"
+	"scriberr/internal/notify"
+	"scriberr/pkg/logger"
+)
+
+const (
+	// WatchdogInterval is how often the watchdog looks for stuck jobs
+	WatchdogInterval = time.Minute
+	// expectedDurationSample is how many recent transcriptions the expected duration is taken from
+	expectedDurationSample = 50
+
+	// Stuck job actions
+	StuckActionRequeue = "requeue"
+	StuckActionFail    = "fail"
+
+	// Why a job was found stuck
+	StuckReasonHeartbeat = "heartbeat" // Its worker stopped reporting it alive
+	StuckReasonDuration  = "duration"  // It ran far longer than transcriptions usually take
+)
+
+// WatchdogConfig tells the watchdog when a job is stuck and what to do about it
+type WatchdogConfig struct {
+	// HeartbeatTimeout is how long a job may go without a heartbeat before its worker is taken
+	// for dead; at least twice HeartbeatInterval
+	HeartbeatTimeout time.Duration
+	// DurationMultiple is how many times the expected duration a job may run on this node before
+	// it is taken for hung; 0 turns the check off
+	DurationMultiple float64
+	// MinDuration is the least a job may run before it is taken for hung
+	MinDuration time.Duration
+	// Action is StuckActionRequeue or StuckActionFail
+	Action string
+	// MaxRequeues is how often a job is requeued before it is failed instead
+	MaxRequeues int
+}
+
+// StuckJob is a job the watchdog found stuck and what it did about it
+type StuckJob struct {
+	ID          string     `json:"id"`
+	WorkerID    string     `json:"worker_id"`
+	Reason      string     `json:"reason"`
+	Action      string     `json:"action"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	HeartbeatAt *time.Time `json:"heartbeat_at,omitempty"`
+}
+
+// Watchdog finds jobs stuck in processing, because their worker died or hung, and requeues
+// or fails them
+type Watchdog struct {
+	queue    *TaskQueue
+	config   WatchdogConfig
+	notifier *notify.WebhookNotifier
+	now      func() time.Time
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewWatchdog creates a watchdog over the jobs of a queue, notifying notifier, if enabled,
+// about the stuck jobs it finds
+func NewWatchdog(queue *TaskQueue, config WatchdogConfig, notifier *notify.WebhookNotifier) *Watchdog {
+	if config.Action != StuckActionFail {
+		config.Action = StuckActionRequeue
+	}
+	// A heartbeat or two may be late without the worker being dead
+	config.HeartbeatTimeout = max(config.HeartbeatTimeout, 2*HeartbeatInterval)
+	return &Watchdog{queue: queue, config: config, notifier: notifier, now: time.Now}
+}
+
+// Start checks for stuck jobs every interval until Stop is called
+func (w *Watchdog) Start(interval time.Duration) {
+	w.stop = make(chan struct{})
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := w.Check(); err != nil {
+					logger.Warn("Stuck job check failed", "error", err)
+				}
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic checks
+func (w *Watchdog) Stop() {
+	if w.stop != nil {
+		close(w.stop)
+		w.wg.Wait()
+	}
+}
+
+// ExpectedDuration returns the average processing time of recent transcriptions, or 0 when
+// none has finished yet
+func (w *Watchdog) ExpectedDuration() (time.Duration, error) {
+	var durations []int64
+	if err := database.DB.Model(&models.TranscriptionJobExecution{}).
+		Where("status = ? AND processing_duration IS NOT NULL", models.StatusCompleted).
+		Order("created_at DESC").Limit(expectedDurationSample).
+		Pluck("processing_duration", &durations).Error; err != nil {
+		return 0, err
+	}
+	if len(durations) == 0 {
+		return 0, nil
+	}
+	var total int64
+	for _, d := range durations {
+		total += d
+	}
+	return time.Duration(total/int64(len(durations))) * time.Millisecond, nil
+}
+
+// DurationLimit returns how long a job may run on this node before it is taken for hung, or 0
+// when that isn't checked
+func (w *Watchdog) DurationLimit() (time.Duration, error) {
+	if w.config.DurationMultiple <= 0 {
+		return 0, nil
+	}
+	expected, err := w.ExpectedDuration()
+	if err != nil || expected == 0 {
+		// Without finished transcriptions there's nothing to tell a long job from a hung one
+		return 0, err
+	}
+	return max(w.config.MinDuration, time.Duration(float64(expected)*w.config.DurationMultiple)), nil
+}
+
+// Check requeues or fails the jobs that are stuck. Any node may take over a job whose
+// heartbeat stopped, but only the node running a job judges it hung, since another node can't
+// stop it.
+func (w *Watchdog) Check() ([]StuckJob, error) {
+	limit, err := w.DurationLimit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate the expected duration: %w", err)
+	}
+
+	// Rows without a worker are the temporary jobs of multi-track and quick transcriptions
+	var jobs []models.TranscriptionJob
+	if err := database.DB.Select("id", "worker_id", "processing_started_at", "heartbeat_at", "stuck_requeues", "updated_at").
+		Where("status = ? AND worker_id IS NOT NULL", models.StatusProcessing).
+		Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list processing jobs: %w", err)
+	}
+
+	now := w.now()
+	stuck := []StuckJob{}
+	for _, job := range jobs {
+		reason := ""
+		if w.queue.IsJobRunning(job.ID) {
+			if limit > 0 && job.ProcessingStartedAt != nil && now.Sub(*job.ProcessingStartedAt) > limit {
+				reason = StuckReasonDuration
+			}
+		} else {
+			lastSeen := job.UpdatedAt
+			if job.HeartbeatAt != nil {
+				lastSeen = *job.HeartbeatAt
+			}
+			if now.Sub(lastSeen) > w.config.HeartbeatTimeout {
+				reason = StuckReasonHeartbeat
+			}
+		}
+		if reason == "" {
+			continue
+		}
+		if result, ok := w.recover(job, reason); ok {
+			stuck = append(stuck, result)
+		}
+	}
+	return stuck, nil
+}
+
+// recover requeues or fails a stuck job, reporting false if another node got to it first
+func (w *Watchdog) recover(job models.TranscriptionJob, reason string) (StuckJob, bool) {
+	result := StuckJob{ID: job.ID, Reason: reason, StartedAt: job.ProcessingStartedAt, HeartbeatAt: job.HeartbeatAt}
+	if job.WorkerID != nil {
+		result.WorkerID = *job.WorkerID
+	}
+	result.Action = w.config.Action
+	if result.Action == StuckActionRequeue && job.StuckRequeues >= w.config.MaxRequeues {
+		result.Action = StuckActionFail
+	}
+
+	message := "Transcription stopped making progress"
+	if reason == StuckReasonHeartbeat {
+		message = fmt.Sprintf("Worker %s stopped responding", result.WorkerID)
+	}
+	updates := map[string]interface{}{"status": models.StatusFailed, "error_message": message}
+	if result.Action == StuckActionRequeue {
+		updates = map[string]interface{}{"status": models.StatusPending, "stuck_requeues": job.StuckRequeues + 1}
+	}
+	// Only the node whose update lands acts, and only while the job is still in the same hands
+	update := database.DB.Model(&models.TranscriptionJob{}).
+		Where("id = ? AND status = ? AND worker_id = ?", job.ID, models.StatusProcessing, result.WorkerID).
+		Updates(updates)
+	if update.Error != nil {
+		logger.Error("Failed to recover stuck job", "job_id", job.ID, "error", update.Error)
+		return result, false
+	}
+	if update.RowsAffected == 0 {
+		return result, false
+	}
+	w.queue.Abandon(job.ID)
+
+	logger.Warn("Recovered stuck job", "job_id", job.ID, "worker_id", result.WorkerID, "reason", reason, "action", result.Action)
+	events.RecordForJob(models.EventJobStuck, job.ID, map[string]interface{}{
+		"worker_id": result.WorkerID,
+		"reason":    reason,
+		"action":    result.Action,
+	})
+	if result.Action == StuckActionRequeue {
+		if err := w.queue.EnqueueJob(job.ID); err != nil {
+			// The job scanner picks it up once there's room
+			logger.Warn("Could not enqueue requeued job", "job_id", job.ID, "error", err)
+		}
+	} else {
+		events.RecordForJob(models.EventJobFailed, job.ID, map[string]interface{}{"error": message})
+	}
+	w.notify(result, message)
+	return result, true
+}
+
+// notify tells the webhook, if any, about a stuck job
+func (w *Watchdog) notify(job StuckJob, message string) {
+	if w.notifier == nil || !w.notifier.Enabled() {
+		return
+	}
+	event := notify.Event{
+		Type:            "job.stuck",
+		TranscriptionID: job.ID,
+		Data: map[string]string{
+			"worker_id": job.WorkerID,
+			"reason":    job.Reason,
+			"action":    job.Action,
+			"message":   message,
+		},
+	}
+	if job.StartedAt != nil {
+		event.Data["running_seconds"] = strconv.Itoa(int(w.now().Sub(*job.StartedAt).Seconds()))
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := w.notifier.Send(ctx, event); err != nil {
+			logger.Warn("Failed to send stuck job notification", "job_id", job.ID, "error", err)
+		}
+	}()
+}
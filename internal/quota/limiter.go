@@ -0,0 +1,79 @@
+package quota
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the limiter forgets buckets that have refilled
+const sweepInterval = time.Minute
+
+// Limiter is an in-memory token bucket rate limiter. Each subject gets a bucket holding up to
+// a minute's worth of requests, which refills continuously, so short bursts are allowed as
+// long as the average stays under the limit.
+type Limiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// bucket is the requests a subject has left
+type bucket struct {
+	tokens  float64
+	size    float64
+	updated time.Time
+}
+
+// NewLimiter creates a rate limiter
+func NewLimiter() *Limiter {
+	return &Limiter{buckets: map[string]*bucket{}}
+}
+
+// Allow takes a request from subject's bucket, allowing perMinute requests a minute (0 or
+// less is unlimited). When the bucket is empty it returns false and how long until the
+// next request would be allowed.
+func (l *Limiter) Allow(subject string, perMinute int, now time.Time) (bool, time.Duration) {
+	if perMinute <= 0 {
+		return true, 0
+	}
+	size := float64(perMinute)
+	perSecond := size / 60
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	b, ok := l.buckets[subject]
+	if !ok {
+		b = &bucket{tokens: size, size: size, updated: now}
+		l.buckets[subject] = b
+	} else {
+		if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+			b.tokens += elapsed * perSecond
+		}
+		b.size = size
+		b.tokens = math.Min(b.tokens, size)
+		b.updated = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+	return false, wait
+}
+
+// sweep forgets buckets that would be full by now, since a new bucket starts full
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for subject, b := range l.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*b.size/60 >= b.size {
+			delete(l.buckets, subject)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package quota
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	limiter := NewLimiter()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// A full minute's worth can be used at once
+	for i := 0; i < 3; i++ {
+		if ok, _ := limiter.Allow("user:1", 3, now); !ok {
+			t.Fatalf("request %d should be allowed", i+1)
+		}
+	}
+	ok, wait := limiter.Allow("user:1", 3, now)
+	if ok {
+		t.Fatal("fourth request should be limited")
+	}
+	if wait != 20*time.Second {
+		t.Errorf("expected to wait 20s for the next request, got %v", wait)
+	}
+
+	// Other subjects have their own buckets
+	if ok, _ := limiter.Allow("key:1", 3, now); !ok {
+		t.Error("another subject should be allowed")
+	}
+
+	// One request refills every 20 seconds
+	if ok, _ := limiter.Allow("user:1", 3, now.Add(20*time.Second)); !ok {
+		t.Error("request after refill should be allowed")
+	}
+	if ok, _ := limiter.Allow("user:1", 3, now.Add(21*time.Second)); ok {
+		t.Error("bucket should be empty again")
+	}
+
+	// No limit
+	for i := 0; i < 100; i++ {
+		if ok, _ := limiter.Allow("user:2", 0, now); !ok {
+			t.Fatal("unlimited subject was limited")
+		}
+	}
+}
+
+func TestLimiterSweep(t *testing.T) {
+	limiter := NewLimiter()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	limiter.Allow("user:1", 60, now)
+	for i := 0; i < 60; i++ {
+		limiter.Allow("user:2", 60, now.Add(50*time.Second))
+	}
+	limiter.Allow("user:3", 60, now.Add(70*time.Second))
+	if _, ok := limiter.buckets["user:1"]; ok {
+		t.Error("refilled bucket should be forgotten")
+	}
+	if _, ok := limiter.buckets["user:2"]; !ok {
+		t.Error("bucket still refilling should be kept")
+	}
+}
+
+func TestPeriod(t *testing.T) {
+	period, end := Period(time.Date(2026, 12, 31, 23, 30, 0, 0, time.FixedZone("", -2*60*60)))
+	if period != "2027-01" {
+		t.Errorf("expected the UTC month, got %s", period)
+	}
+	if !end.Equal(time.Date(2027, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected end %v", end)
+	}
+}
@@ -0,0 +1,232 @@
+// Package quota enforces request rate limits per user and API key, and monthly quotas of
+// minutes of audio transcribed and LLM requests per user. Quotas are counted per calendar
+// month in UTC.
+package quota
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/export"
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Metrics, in the order they are reported
+var Metrics = []string{models.QuotaAudioMinutes, models.QuotaLLMCalls}
+
+// Limits are the rate limit and monthly quotas that apply to a user. Zero is unlimited.
+type Limits struct {
+	RequestsPerMinute int
+	AudioMinutes      int
+	LLMCalls          int
+}
+
+// Quota returns the monthly quota of metric
+func (l Limits) Quota(metric string) int {
+	switch metric {
+	case models.QuotaAudioMinutes:
+		return l.AudioMinutes
+	case models.QuotaLLMCalls:
+		return l.LLMCalls
+	}
+	return 0
+}
+
+// Error reports a used up quota
+type Error struct {
+	Metric   string
+	Used     float64
+	Limit    int
+	ResetsAt time.Time
+}
+
+func (e *Error) Error() string {
+	switch e.Metric {
+	case models.QuotaAudioMinutes:
+		return fmt.Sprintf("monthly quota of %d minutes of audio used up", e.Limit)
+	case models.QuotaLLMCalls:
+		return fmt.Sprintf("monthly quota of %d LLM requests used up", e.Limit)
+	}
+	return fmt.Sprintf("monthly %s quota used up", e.Metric)
+}
+
+// Service checks requests against the instance's default limits and the limits admins set
+// per user and API key
+type Service struct {
+	defaults  Limits
+	anonymous int
+	limiter   *Limiter
+	now       func() time.Time
+}
+
+// NewService creates a service applying defaults to users without limits of their own, and
+// a rate limit of anonymous requests a minute to each IP address without credentials
+func NewService(defaults Limits, anonymous int) *Service {
+	return &Service{defaults: defaults, anonymous: anonymous, limiter: NewLimiter(), now: time.Now}
+}
+
+// LimitsFor returns the limits of user: the defaults, replaced by any set for the user.
+// A nil user, such as an API key without an owner, is unlimited.
+func (s *Service) LimitsFor(user *models.User) Limits {
+	if user == nil {
+		return Limits{}
+	}
+	limits := s.defaults
+	if user.RateLimitPerMinute != nil {
+		limits.RequestsPerMinute = *user.RateLimitPerMinute
+	}
+	if user.QuotaAudioMinutes != nil {
+		limits.AudioMinutes = *user.QuotaAudioMinutes
+	}
+	if user.QuotaLLMCalls != nil {
+		limits.LLMCalls = *user.QuotaLLMCalls
+	}
+	return limits
+}
+
+// DefaultRateLimit returns the rate limit of callers without one of their own
+func (s *Service) DefaultRateLimit() int {
+	return s.defaults.RequestsPerMinute
+}
+
+// AnonymousRateLimit returns the rate limit of each IP address's requests without valid
+// credentials
+func (s *Service) AnonymousRateLimit() int {
+	return s.anonymous
+}
+
+// Allow takes a request from subject's rate limit of perMinute requests a minute. When it
+// is used up it returns false and how long until the next request is allowed.
+func (s *Service) Allow(subject string, perMinute int) (bool, time.Duration) {
+	return s.limiter.Allow(subject, perMinute, s.now())
+}
+
+// Check returns an *Error when user has used up their monthly quota of metric
+func (s *Service) Check(user *models.User, metric string) error {
+	limit := s.LimitsFor(user).Quota(metric)
+	if limit <= 0 {
+		return nil
+	}
+	period, resetsAt := Period(s.now())
+	used, err := Used(user.ID, period)
+	if err != nil {
+		return err
+	}
+	if used[metric] >= float64(limit) {
+		return &Error{Metric: metric, Used: used[metric], Limit: limit, ResetsAt: resetsAt}
+	}
+	return nil
+}
+
+// Status is a user's rate limit and their use of their quotas this month
+type Status struct {
+	Period             string        `json:"period"`                // YYYY-MM, in UTC
+	ResetsAt           time.Time     `json:"resets_at"`             // When the quotas start over
+	RateLimitPerMinute int           `json:"rate_limit_per_minute"` // 0 is unlimited
+	Quotas             []QuotaStatus `json:"quotas"`
+}
+
+// QuotaStatus is the use of one quota
+type QuotaStatus struct {
+	Metric    string   `json:"metric"`
+	Used      float64  `json:"used"`
+	Limit     int      `json:"limit"`               // 0 is unlimited
+	Remaining *float64 `json:"remaining,omitempty"` // Unset when unlimited
+	Exceeded  bool     `json:"exceeded"`
+}
+
+// Status reports user's limits and what they have used this month. rateLimit replaces the
+// user's rate limit when the caller has one of their own, as API keys may.
+func (s *Service) Status(user *models.User, rateLimit *int) (*Status, error) {
+	limits := s.LimitsFor(user)
+	if rateLimit != nil {
+		limits.RequestsPerMinute = *rateLimit
+	}
+	period, resetsAt := Period(s.now())
+	status := &Status{Period: period, ResetsAt: resetsAt, RateLimitPerMinute: limits.RequestsPerMinute, Quotas: []QuotaStatus{}}
+
+	used := map[string]float64{}
+	if user != nil {
+		var err error
+		if used, err = Used(user.ID, period); err != nil {
+			return nil, err
+		}
+	}
+	for _, metric := range Metrics {
+		quota := QuotaStatus{Metric: metric, Used: used[metric], Limit: limits.Quota(metric)}
+		if quota.Limit > 0 {
+			remaining := math.Max(float64(quota.Limit)-quota.Used, 0)
+			quota.Remaining = &remaining
+			quota.Exceeded = remaining == 0
+		}
+		status.Quotas = append(status.Quotas, quota)
+	}
+	return status, nil
+}
+
+// Period returns the month t falls in, as YYYY-MM in UTC, and when it ends
+func Period(t time.Time) (string, time.Time) {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start.Format("2006-01"), start.AddDate(0, 1, 0)
+}
+
+// Used returns how much of each metric userID has used in period
+func Used(userID uint, period string) (map[string]float64, error) {
+	var rows []models.QuotaUsage
+	if err := database.DB.Where("user_id = ? AND period = ?", userID, period).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	used := map[string]float64{}
+	for _, row := range rows {
+		used[row.Metric] = row.Amount
+	}
+	return used, nil
+}
+
+// Add adds amount of metric to userID's usage this month
+func Add(userID uint, metric string, amount float64) error {
+	period, _ := Period(time.Now())
+	usage := models.QuotaUsage{UserID: userID, Period: period, Metric: metric, Amount: amount}
+	return database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "period"}, {Name: "metric"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"amount": gorm.Expr("quota_usages.amount + ?", amount), "updated_at": time.Now()}),
+	}).Create(&usage).Error
+}
+
+// RecordTranscription adds the length of a transcribed job's audio to its owner's audio
+// minutes, as RecordAudio does
+func RecordTranscription(jobID string) {
+	var job models.TranscriptionJob
+	if err := database.DB.Select("id", "user_id", "transcript").Where("id = ?", jobID).Limit(1).Find(&job).Error; err != nil {
+		logger.Error("Failed to load job for quota", "component", "quota", "job_id", jobID, "error", err)
+		return
+	}
+	RecordAudio(job.UserID, export.TranscriptSegments(&job))
+}
+
+// RecordAudio adds the length of transcribed audio, taken from the end of its last segment,
+// to userID's audio minutes. Audio transcribed without an owner isn't metered. Failures are
+// logged rather than returned, since they shouldn't fail the transcription.
+func RecordAudio(userID *uint, segments []interfaces.TranscriptSegment) {
+	if userID == nil {
+		return
+	}
+	var seconds float64
+	for _, segment := range segments {
+		seconds = math.Max(seconds, segment.End)
+	}
+	if seconds <= 0 {
+		return
+	}
+	if err := Add(*userID, models.QuotaAudioMinutes, seconds/60); err != nil {
+		logger.Error("Failed to record audio minutes", "component", "quota", "user_id", *userID, "error", err)
+	}
+}
@@ -0,0 +1,54 @@
+package rag
+
+import (
+	"fmt"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxAnswerSources caps the excerpts retrieved, and kept, for one chat answer
+	MaxAnswerSources = 50
+	// chatContextDocuments is how many of the best excerpts are given to the LLM
+	chatContextDocuments = 5
+)
+
+// SaveAnswer stores a chat answer with every excerpt retrieved for it, so the full source list
+// can be paged through later
+func SaveAnswer(userID *uint, query string, result *ChatResult) (*models.RAGAnswer, error) {
+	answer := models.RAGAnswer{UserID: userID, Query: query, Answer: result.Answer, SourceCount: len(result.Retrieved)}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&answer).Error; err != nil {
+			return err
+		}
+		if len(result.Retrieved) == 0 {
+			return nil
+		}
+		sources := make([]models.RAGAnswerSource, len(result.Retrieved))
+		for i, doc := range result.Retrieved {
+			sources[i] = models.RAGAnswerSource{
+				AnswerID:        answer.ID,
+				Rank:            i + 1,
+				ChunkID:         doc.ChunkID,
+				TranscriptionID: doc.TranscriptionID,
+				DocumentID:      doc.DocumentID,
+				RecordingID:     doc.RecordingID,
+				Start:           doc.Start,
+				End:             doc.End,
+				Speaker:         doc.Speaker,
+				Language:        doc.Language,
+				Content:         doc.Content,
+				Distance:        doc.Distance,
+				InContext:       i < chatContextDocuments,
+			}
+		}
+		return tx.Create(&sources).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save answer: %w", err)
+	}
+	return &answer, nil
+}
@@ -0,0 +1,226 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/vectordb"
+)
+
+// auditPageSize is how many vector store documents are fetched per request during an audit
+const auditPageSize = 500
+
+// AuditReport describes how the vector store differs from the database
+type AuditReport struct {
+	CheckedAt     time.Time `json:"checked_at"`
+	ExpectedCount int       `json:"expected_count"`
+	IndexedCount  int       `json:"indexed_count"`
+	Collections   []string  `json:"collections"`
+	// Missing are completed transcriptions with no document in their owner's collection
+	Missing []string `json:"missing"`
+	// Orphaned are documents whose transcription is gone, no longer completed, or owned by someone else
+	Orphaned []OrphanedDocument `json:"orphaned"`
+	// Stale are documents indexed before the transcription was last updated
+	Stale []string `json:"stale"`
+	// MissingDocuments are indexed uploaded documents with no chunks in their owner's collection
+	MissingDocuments []string `json:"missing_documents"`
+}
+
+// OrphanedDocument identifies vector store documents that should not exist. DocumentID is
+// set for chunks of uploaded documents, TranscriptionID for transcripts.
+type OrphanedDocument struct {
+	TranscriptionID string `json:"transcription_id,omitempty"`
+	DocumentID      string `json:"document_id,omitempty"`
+	Collection      string `json:"collection"`
+}
+
+// Consistent reports whether the audit found no discrepancies
+func (r *AuditReport) Consistent() bool {
+	return len(r.Missing) == 0 && len(r.Orphaned) == 0 && len(r.Stale) == 0 && len(r.MissingDocuments) == 0
+}
+
+// indexedDocument is the audit's view of a single vector store document, with the chunks
+// of one transcription or uploaded document merged together
+type indexedDocument struct {
+	sourceID  string
+	indexedAt time.Time
+}
+
+// collectionIndex is the contents of one collection, split by source
+type collectionIndex struct {
+	transcriptions map[string]indexedDocument
+	documents      map[string]indexedDocument // Uploaded documents, keyed by document ID
+}
+
+// Audit cross-checks completed transcriptions against the documents in every user's collections
+func (s *RAGService) Audit(ctx context.Context) (*AuditReport, error) {
+	refs, err := completedTranscriptions()
+	if err != nil {
+		return nil, err
+	}
+	resolver, err := newScopeResolver()
+	if err != nil {
+		return nil, err
+	}
+	bases, err := Collections()
+	if err != nil {
+		return nil, err
+	}
+	var collections []string
+	for _, base := range bases {
+		collections = append(collections, s.collectionsOf(base, nil)...)
+	}
+
+	report := &AuditReport{
+		CheckedAt:        time.Now(),
+		ExpectedCount:    len(refs),
+		Collections:      collections,
+		Missing:          []string{},
+		Orphaned:         []OrphanedDocument{},
+		Stale:            []string{},
+		MissingDocuments: []string{},
+	}
+
+	indexes := make(map[string]*collectionIndex, len(collections))
+	for _, collection := range collections {
+		s.ensureCollection(collection)
+		index, err := s.listDocuments(ctx, collection)
+		if err != nil {
+			return nil, err
+		}
+		indexes[collection] = index
+		report.IndexedCount += len(index.transcriptions)
+	}
+
+	// expected maps each transcription to the collection it belongs in
+	expected := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		collection := routedCollection(resolver.collection(ref.UserID), s.routeOf(ref.ContentType))
+		expected[ref.ID] = collection
+		doc, ok := indexes[collection].transcriptions[ref.ID]
+		if !ok {
+			report.Missing = append(report.Missing, ref.ID)
+			continue
+		}
+		// Documents indexed before indexed_at was recorded can't be verified, so treat them as stale
+		if doc.indexedAt.IsZero() || ref.UpdatedAt.After(doc.indexedAt) {
+			report.Stale = append(report.Stale, ref.ID)
+		}
+	}
+
+	// Uploaded documents are checked the same way, but only those that were indexed successfully
+	var uploaded []models.Document
+	if err := database.DB.Select("id", "user_id", "status").Find(&uploaded).Error; err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	expectedDocuments := make(map[string]string, len(uploaded))
+	for _, doc := range uploaded {
+		collection := routedCollection(resolver.collection(doc.UserID), s.routeOf(models.ContentDocument))
+		expectedDocuments[doc.ID] = collection
+		if doc.Status != models.DocumentIndexed {
+			continue
+		}
+		if _, ok := indexes[collection].documents[doc.ID]; !ok {
+			report.MissingDocuments = append(report.MissingDocuments, doc.ID)
+		}
+	}
+
+	for _, collection := range collections {
+		for id := range indexes[collection].transcriptions {
+			if expected[id] != collection {
+				report.Orphaned = append(report.Orphaned, OrphanedDocument{TranscriptionID: id, Collection: collection})
+			}
+		}
+		for id := range indexes[collection].documents {
+			if expectedDocuments[id] != collection {
+				report.Orphaned = append(report.Orphaned, OrphanedDocument{DocumentID: id, Collection: collection})
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// Collections returns the shared collection and one per user, whether or not they currently own
+// transcriptions. These are the main collections; content types routed elsewhere live next to them.
+func Collections() ([]string, error) {
+	var userIDs []uint
+	if err := database.DB.Model(&models.User{}).Pluck("id", &userIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	collections := []string{sharedCollection}
+	for i := range userIDs {
+		collections = append(collections, CollectionName(&userIDs[i]))
+	}
+	return collections, nil
+}
+
+// RemoveOrphan deletes an orphaned transcription's or uploaded document's entries from the collection they were found in
+func (s *RAGService) RemoveOrphan(orphan OrphanedDocument) error {
+	where := map[string]interface{}{"transcription_id": orphan.TranscriptionID}
+	id := orphan.TranscriptionID
+	if orphan.DocumentID != "" {
+		where = map[string]interface{}{"document_id": orphan.DocumentID}
+		id = orphan.DocumentID
+	}
+	if err := s.vectorDB.DeleteDocuments(orphan.Collection, nil, where); err != nil {
+		return fmt.Errorf("failed to delete documents for %s: %w", id, err)
+	}
+	return nil
+}
+
+// listDocuments pages through the collection's metadata and returns its entries grouped by
+// transcription or uploaded document
+func (s *RAGService) listDocuments(ctx context.Context, collection string) (*collectionIndex, error) {
+	index := &collectionIndex{
+		transcriptions: make(map[string]indexedDocument),
+		documents:      make(map[string]indexedDocument),
+	}
+	for offset := 0; ; offset += auditPageSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		page, err := s.store(ctx).GetDocuments(collection, vectordb.GetRequest{
+			Limit:   auditPageSize,
+			Offset:  offset,
+			Include: []string{"metadatas"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list documents in %s: %w", collection, err)
+		}
+
+		for i, id := range page.IDs {
+			doc := indexedDocument{sourceID: id}
+			docs := index.transcriptions
+			if i < len(page.Metadatas) && page.Metadatas[i] != nil {
+				meta := page.Metadatas[i]
+				if did, ok := meta["document_id"].(string); ok && did != "" {
+					doc.sourceID = did
+					docs = index.documents
+				} else if tid, ok := meta["transcription_id"].(string); ok && tid != "" {
+					doc.sourceID = tid
+				}
+				// JSON numbers decode as float64
+				if ts, ok := meta["indexed_at"].(float64); ok && ts > 0 {
+					doc.indexedAt = time.Unix(int64(ts), 0)
+				}
+			}
+			// Merge chunks of the same source, keeping the oldest index time
+			if existing, ok := docs[doc.sourceID]; ok {
+				if !existing.indexedAt.IsZero() && (doc.indexedAt.IsZero() || doc.indexedAt.Before(existing.indexedAt)) {
+					existing.indexedAt = doc.indexedAt
+				}
+				doc = existing
+			}
+			docs[doc.sourceID] = doc
+		}
+
+		if len(page.IDs) < auditPageSize {
+			return index, nil
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package rag
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+)
+
+// reservedChunkMetadata are the metadata keys Scriberr sets or filters on itself, which a
+// chunker's metadata may not set
+var reservedChunkMetadata = map[string]bool{
+	"transcription_id": true, "type": true, "chunk_index": true, "start": true, "end": true,
+	"indexed_at": true, "content_hash": true, "embedding_model": true, "speaker": true,
+	"speaker_name": true, "title": true, "tags": true, "content_type": true, "confidence": true,
+	"low_confidence_share": true, "user_id": true, "language": true, "document_id": true,
+	"session_id": true, "recording_id": true,
+}
+
+// Chunker splits a transcript into the chunks that are embedded and searched, in place of
+// ChunkSegments
+type Chunker interface {
+	Chunk(ctx context.Context, req ChunkRequest) ([]TranscriptChunk, error)
+}
+
+// ChunkRequest is a transcript to be chunked, as sent to a custom chunking service
+type ChunkRequest struct {
+	TranscriptionID string `json:"transcription_id"`
+	Title           string `json:"title,omitempty"`
+	ContentType     string `json:"content_type"`
+	Language        string `json:"language,omitempty"`
+	// InitialPrompt holds the participants, agenda or glossary given with the recording
+	InitialPrompt string `json:"initial_prompt,omitempty"`
+	// ChunkSize is the size in characters the built-in chunker aims for with this content type
+	ChunkSize int            `json:"chunk_size"`
+	Segments  []ChunkSegment `json:"segments"`
+}
+
+// ChunkSegment is one transcript segment of a ChunkRequest
+type ChunkSegment struct {
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Speaker string  `json:"speaker,omitempty"`
+	Text    string  `json:"text"`
+}
+
+// ChunkResponse is the body expected back from a custom chunking service
+type ChunkResponse struct {
+	Chunks []ServiceChunk `json:"chunks"`
+}
+
+// ServiceChunk is one chunk returned by a custom chunking service. Metadata holds string,
+// number or boolean values stored with the chunk, such as an agenda item.
+type ServiceChunk struct {
+	Start    float64                `json:"start"`
+	End      float64                `json:"end"`
+	Speaker  string                 `json:"speaker,omitempty"`
+	Text     string                 `json:"text"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// HTTPChunker calls a user-provided chunking service. The contract is a single POST of a
+// ChunkRequest answered with a ChunkResponse, chunks in transcript order.
+type HTTPChunker struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+// NewHTTPChunker creates a client for a custom chunking service at url. A non-empty apiKey
+// is sent as a bearer token.
+func NewHTTPChunker(url, apiKey string, timeout time.Duration) *HTTPChunker {
+	return &HTTPChunker{
+		url:    url,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Chunk sends a transcript to the chunking service and returns the chunks it made
+func (c *HTTPChunker) Chunk(ctx context.Context, chunkReq ChunkRequest) ([]TranscriptChunk, error) {
+	data, err := json.Marshal(chunkReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var chunkResp ChunkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chunkResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	chunks := make([]TranscriptChunk, 0, len(chunkResp.Chunks))
+	for i, returned := range chunkResp.Chunks {
+		if strings.TrimSpace(returned.Text) == "" {
+			continue
+		}
+		if returned.End < returned.Start {
+			return nil, fmt.Errorf("chunking service returned chunk %d ending before it starts", i)
+		}
+		for key, value := range returned.Metadata {
+			switch value.(type) {
+			case string, float64, bool:
+			default:
+				return nil, fmt.Errorf("chunking service returned metadata %q of chunk %d that isn't a string, number or boolean", key, i)
+			}
+		}
+		chunks = append(chunks, TranscriptChunk{
+			Start:    returned.Start,
+			End:      returned.End,
+			Speaker:  returned.Speaker,
+			Text:     strings.TrimSpace(returned.Text),
+			Metadata: returned.Metadata,
+		})
+	}
+	if len(chunks) == 0 && len(chunkReq.Segments) > 0 {
+		return nil, fmt.Errorf("chunking service returned no chunks for %d segments", len(chunkReq.Segments))
+	}
+	return chunks, nil
+}
+
+// SetChunker sets a chunker used in place of the built-in one when indexing transcripts.
+// With fallback, transcripts are chunked the built-in way when the chunker fails rather than
+// failing to index.
+func (s *RAGService) SetChunker(chunker Chunker, fallback bool) {
+	s.chunker = chunker
+	s.chunkerFallback = fallback
+}
+
+// chunkTranscript splits a transcript into chunks with the configured chunker, if any, or
+// ChunkSegments. The chunker is sent the text as it will be stored.
+func (s *RAGService) chunkTranscript(transcriptionID string, result interfaces.TranscriptResult, initialPrompt string, meta *jobMetadata, indexText func(string) string) ([]TranscriptChunk, error) {
+	chunkSize := Profile(meta.contentType).ChunkSize
+	if s.chunker == nil || len(result.Segments) == 0 {
+		return ChunkSegments(result.Segments, chunkSize), nil
+	}
+
+	req := ChunkRequest{
+		TranscriptionID: transcriptionID,
+		Title:           meta.title,
+		ContentType:     meta.contentType,
+		Language:        result.Language,
+		InitialPrompt:   indexText(initialPrompt),
+		ChunkSize:       chunkSize,
+		Segments:        make([]ChunkSegment, len(result.Segments)),
+	}
+	for i, segment := range result.Segments {
+		req.Segments[i] = ChunkSegment{Start: segment.Start, End: segment.End, Text: indexText(segment.Text)}
+		if segment.Speaker != nil {
+			req.Segments[i].Speaker = *segment.Speaker
+		}
+	}
+
+	chunks, err := s.chunker.Chunk(context.Background(), req)
+	if err == nil {
+		for _, chunk := range chunks {
+			for key := range chunk.Metadata {
+				if reservedChunkMetadata[key] {
+					delete(chunk.Metadata, key)
+				}
+			}
+		}
+		return chunks, nil
+	}
+	if !s.chunkerFallback {
+		return nil, fmt.Errorf("failed to chunk transcript: %w", err)
+	}
+	logger.Warn("Chunking service failed, using built-in chunking", "transcription_id", transcriptionID, "error", err)
+	return ChunkSegments(result.Segments, chunkSize), nil
+}
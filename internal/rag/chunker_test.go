@@ -0,0 +1,86 @@
+package rag
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// chunkingServer answers chunking requests with body, after checking the request it got
+func chunkingServer(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("expected bearer token, got %q", got)
+		}
+		var req ChunkRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if req.TranscriptionID != "job-1" || len(req.Segments) != 2 {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func chunkRequest() ChunkRequest {
+	return ChunkRequest{
+		TranscriptionID: "job-1",
+		Segments: []ChunkSegment{
+			{Start: 0, End: 5, Speaker: "SPEAKER_00", Text: "Item one is the budget."},
+			{Start: 5, End: 9, Speaker: "SPEAKER_01", Text: "Item two is hiring."},
+		},
+	}
+}
+
+func TestHTTPChunkerReturnsChunksWithMetadata(t *testing.T) {
+	server := chunkingServer(t, `{"chunks":[
+		{"start":0,"end":5,"text":" Item one is the budget. ","metadata":{"agenda_item":"Budget","item_number":1,"decision":false}},
+		{"start":5,"end":9,"text":"   "},
+		{"start":5,"end":9,"speaker":"SPEAKER_01","text":"Item two is hiring."}
+	]}`)
+
+	chunks, err := NewHTTPChunker(server.URL, "secret", time.Second).Chunk(context.Background(), chunkRequest())
+	if err != nil {
+		t.Fatalf("chunking failed: %v", err)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("expected the empty chunk to be dropped, got %+v", chunks)
+	}
+	if chunks[0].Text != "Item one is the budget." || chunks[0].Metadata["agenda_item"] != "Budget" || chunks[0].Metadata["item_number"] != float64(1) {
+		t.Errorf("unexpected first chunk: %+v", chunks[0])
+	}
+	if chunks[1].Speaker != "SPEAKER_01" || chunks[1].Metadata != nil {
+		t.Errorf("unexpected second chunk: %+v", chunks[1])
+	}
+}
+
+func TestHTTPChunkerRejectsBadResponses(t *testing.T) {
+	for name, body := range map[string]string{
+		"no chunks":          `{"chunks":[]}`,
+		"nested metadata":    `{"chunks":[{"start":0,"end":5,"text":"Budget","metadata":{"item":{"number":1}}}]}`,
+		"ends before starts": `{"chunks":[{"start":5,"end":0,"text":"Budget"}]}`,
+		"not json":           `chunks`,
+	} {
+		server := chunkingServer(t, body)
+		if _, err := NewHTTPChunker(server.URL, "secret", time.Second).Chunk(context.Background(), chunkRequest()); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "segmenter crashed", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	_, err := NewHTTPChunker(server.URL, "", time.Second).Chunk(context.Background(), chunkRequest())
+	if err == nil || !strings.Contains(err.Error(), "segmenter crashed") {
+		t.Errorf("expected the service's error, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package rag
+
+import (
+	"sort"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+// LowConfidenceThreshold is the word alignment score below which a word counts as low confidence
+const LowConfidenceThreshold = 0.5
+
+// confidenceOverfetch is how many more results than requested are fetched when confidence
+// weighting is on, so well-transcribed chunks just outside the top results can move up
+const confidenceOverfetch = 2
+
+// applyConfidence sets each chunk's confidence from the ASR scores of the words it spans.
+// Words without a score (WhisperX leaves numbers and symbols unaligned) are ignored, and
+// chunks with no scored words keep a nil Confidence. Chunks must be in time order.
+func applyConfidence(chunks []TranscriptChunk, words []interfaces.TranscriptWord) {
+	if len(chunks) == 0 {
+		return
+	}
+
+	totals := make([]float64, len(chunks))
+	counts := make([]int, len(chunks))
+	lows := make([]int, len(chunks))
+	for _, word := range words {
+		if word.Score <= 0 {
+			continue
+		}
+		// The first chunk that ends after the word starts, if the word falls inside it
+		i := sort.Search(len(chunks), func(i int) bool { return chunks[i].End > word.Start })
+		if i == len(chunks) && word.Start == chunks[i-1].End {
+			i--
+		}
+		if i == len(chunks) || word.Start < chunks[i].Start {
+			continue
+		}
+		totals[i] += word.Score
+		counts[i]++
+		if word.Score < LowConfidenceThreshold {
+			lows[i]++
+		}
+	}
+
+	for i := range chunks {
+		if counts[i] == 0 {
+			continue
+		}
+		confidence := totals[i] / float64(counts[i])
+		chunks[i].Confidence = &confidence
+		chunks[i].LowConfidenceShare = float64(lows[i]) / float64(counts[i])
+	}
+}
+
+// weighByConfidence pushes documents dominated by low-confidence words down the ranking by
+// scaling their distance with the share of such words, then re-sorts by the new distance.
+// A chunk made up entirely of low-confidence words has its distance multiplied by 1+weight.
+func weighByConfidence(docs []RetrievedDocument, weight float64) []RetrievedDocument {
+	if weight <= 0 {
+		return docs
+	}
+	for i := range docs {
+		docs[i].Distance *= float32(1 + weight*docs[i].lowConfidenceShare)
+	}
+	sort.SliceStable(docs, func(i, j int) bool { return docs[i].Distance < docs[j].Distance })
+	return docs
+}
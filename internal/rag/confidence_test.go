@@ -0,0 +1,62 @@
+package rag
+
+import (
+	"math"
+	"testing"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+func word(start, score float64) interfaces.TranscriptWord {
+	return interfaces.TranscriptWord{Start: start, End: start + 0.5, Word: "w", Score: score}
+}
+
+func TestApplyConfidence(t *testing.T) {
+	chunks := []TranscriptChunk{
+		{Start: 0, End: 10},
+		{Start: 10, End: 20},
+		{Start: 25, End: 30},
+	}
+	words := []interfaces.TranscriptWord{
+		word(1, 0.9), word(2, 0.7), word(3, 0), // unscored word is ignored
+		word(10, 0.2), word(12, 0.3), word(15, 0.9), word(19.5, 0.4),
+		word(22, 0.1), // in the gap between chunks
+	}
+
+	applyConfidence(chunks, words)
+
+	if chunks[0].Confidence == nil || math.Abs(*chunks[0].Confidence-0.8) > 1e-9 {
+		t.Errorf("expected first chunk confidence 0.8, got %v", chunks[0].Confidence)
+	}
+	if chunks[0].LowConfidenceShare != 0 {
+		t.Errorf("expected no low-confidence words in first chunk, got %v", chunks[0].LowConfidenceShare)
+	}
+	if chunks[1].LowConfidenceShare != 0.75 {
+		t.Errorf("expected 3 of 4 words low confidence in second chunk, got %v", chunks[1].LowConfidenceShare)
+	}
+	if chunks[2].Confidence != nil {
+		t.Errorf("expected unknown confidence for a chunk without scored words, got %v", *chunks[2].Confidence)
+	}
+}
+
+func TestWeighByConfidence(t *testing.T) {
+	docs := []RetrievedDocument{
+		{TranscriptionID: "garbled", Distance: 0.30, lowConfidenceShare: 1},
+		{TranscriptionID: "clean", Distance: 0.40},
+		{TranscriptionID: "mixed", Distance: 0.35, lowConfidenceShare: 0.2},
+	}
+
+	weighted := weighByConfidence(append([]RetrievedDocument(nil), docs...), 1)
+	order := []string{weighted[0].TranscriptionID, weighted[1].TranscriptionID, weighted[2].TranscriptionID}
+	if order[0] != "clean" || order[1] != "mixed" || order[2] != "garbled" {
+		t.Errorf("expected low-confidence chunks to rank last, got %v", order)
+	}
+	if math.Abs(float64(weighted[2].Distance)-0.60) > 1e-6 {
+		t.Errorf("expected fully low-confidence distance to double, got %v", weighted[2].Distance)
+	}
+
+	unweighted := weighByConfidence(append([]RetrievedDocument(nil), docs...), 0)
+	if unweighted[0].TranscriptionID != "garbled" || unweighted[0].Distance != 0.30 {
+		t.Errorf("expected weight 0 to leave the ranking alone, got %+v", unweighted[0])
+	}
+}
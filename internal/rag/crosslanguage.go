@@ -0,0 +1,205 @@
+package rag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+)
+
+// Ways Chat handles excerpts in a language other than the question's. Retrieval itself works
+// across languages when the embedding model is multilingual, but a prompt mixing languages
+// without comment tends to get answers that mix them too.
+const (
+	// CrossLanguageOff assembles the prompt from the excerpts as they are
+	CrossLanguageOff = "off"
+	// CrossLanguageAnnotate marks each excerpt with its source language and tells the LLM to
+	// answer in the language of the question
+	CrossLanguageAnnotate = "annotate"
+	// CrossLanguageTranslate translates excerpts in another language into the language of the
+	// question before they go into the prompt
+	CrossLanguageTranslate = "translate"
+)
+
+// IsCrossLanguageMode reports whether mode is one of the CrossLanguage modes
+func IsCrossLanguageMode(mode string) bool {
+	return mode == CrossLanguageOff || mode == CrossLanguageAnnotate || mode == CrossLanguageTranslate
+}
+
+// languageNames names the languages transcription engines report by ISO 639-1 code, so a
+// transcript in "de" and a translation into "German" count as the same language
+var languageNames = map[string]string{
+	"ar": "Arabic", "cs": "Czech", "da": "Danish", "de": "German", "el": "Greek",
+	"en": "English", "es": "Spanish", "fi": "Finnish", "fr": "French", "he": "Hebrew",
+	"hi": "Hindi", "hu": "Hungarian", "id": "Indonesian", "it": "Italian", "ja": "Japanese",
+	"ko": "Korean", "nl": "Dutch", "no": "Norwegian", "pl": "Polish", "pt": "Portuguese",
+	"ro": "Romanian", "ru": "Russian", "sv": "Swedish", "th": "Thai", "tr": "Turkish",
+	"uk": "Ukrainian", "vi": "Vietnamese", "zh": "Chinese",
+}
+
+// LanguageName returns the English name of a language given by code or name
+func LanguageName(language string) string {
+	language = strings.TrimSpace(language)
+	if name, ok := languageNames[strings.ToLower(language)]; ok {
+		return name
+	}
+	return language
+}
+
+// sameLanguage reports whether two languages, by code or name, are the same
+func sameLanguage(a, b string) bool {
+	return strings.EqualFold(LanguageName(a), LanguageName(b))
+}
+
+// excerptLanguages returns the language each excerpt is in, or "" where it isn't known.
+// Excerpts of translations carry their language; the rest are in the language their
+// recording was transcribed in. Uploaded documents have no known language.
+func excerptLanguages(docs []RetrievedDocument) []string {
+	transcribed := map[string]string{}
+	languages := make([]string, len(docs))
+	for i, doc := range docs {
+		switch {
+		case doc.Language != "":
+			languages[i] = doc.Language
+		case doc.DocumentID != "" || doc.TranscriptionID == "":
+		default:
+			language, ok := transcribed[doc.TranscriptionID]
+			if !ok {
+				language = transcribedLanguage(doc.TranscriptionID)
+				transcribed[doc.TranscriptionID] = language
+			}
+			languages[i] = language
+		}
+	}
+	return languages
+}
+
+// transcribedLanguage returns the language a recording was transcribed in: the one the
+// engine detected, or else the one it was asked for
+func transcribedLanguage(transcriptionID string) string {
+	var job models.TranscriptionJob
+	if err := database.DB.Select("id", "transcript", "language").Where("id = ?", transcriptionID).First(&job).Error; err != nil {
+		return ""
+	}
+	if job.Transcript != nil {
+		var result struct {
+			Language string `json:"language"`
+		}
+		if err := json.Unmarshal([]byte(*job.Transcript), &result); err == nil && result.Language != "" {
+			return result.Language
+		}
+	}
+	if job.Parameters.Language != nil {
+		return *job.Parameters.Language
+	}
+	return ""
+}
+
+// languageSchema is the JSON Schema of the LLM's guess at the language of a question
+var languageSchema = llm.Schema{
+	Name:        "question_language",
+	Description: "The language a question is written in",
+	Definition: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "language": {"type": "string", "description": "The language's English name, e.g. German"}
+  },
+  "required": ["language"]
+}`),
+}
+
+// detectLanguage asks the LLM which language a question is written in
+func (s *RAGService) detectLanguage(ctx context.Context, model, query string) (string, error) {
+	var reply struct {
+		Language string `json:"language"`
+	}
+	messages := []llm.ChatMessage{{Role: "user", Content: "Which language is the following question written in?\n\n" + query}}
+	if err := llm.CompleteJSON(ctx, s.llmService, model, messages, 0, languageSchema, &reply); err != nil {
+		return "", err
+	}
+	language := strings.TrimSpace(reply.Language)
+	if language == "" {
+		return "", fmt.Errorf("no language in reply")
+	}
+	return LanguageName(language), nil
+}
+
+// translateExcerpt translates one excerpt into language
+func (s *RAGService) translateExcerpt(ctx context.Context, model, language, content string) (string, error) {
+	prompt := fmt.Sprintf("Translate the following excerpt into %s. Keep names, numbers and speaker labels as they are. Reply with the translation only.\n\n%s", language, content)
+	response, err := s.llmService.ChatCompletion(ctx, model, []llm.ChatMessage{{Role: "user", Content: prompt}}, 0.2)
+	if err != nil {
+		return "", err
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("no response from LLM")
+	}
+	translated := strings.TrimSpace(response.Choices[0].Message.Content)
+	if translated == "" {
+		return "", fmt.Errorf("empty translation")
+	}
+	return translated, nil
+}
+
+// crossLanguageContexts prepares excerpts in other languages than the question for the prompt
+// as opts.CrossLanguage asks, returning the labeled excerpts and a note explaining them to the
+// LLM. result records the question's language and how many excerpts were translated. An
+// excerpt that fails to translate goes into the prompt marked with its language instead.
+func (s *RAGService) crossLanguageContexts(ctx context.Context, model, query string, docs []RetrievedDocument, opts ChatOptions, result *ChatResult) ([]string, string) {
+	contexts := labeledContexts(docs)
+	if opts.CrossLanguage == "" || opts.CrossLanguage == CrossLanguageOff {
+		return contexts, ""
+	}
+	languages := excerptLanguages(docs)
+
+	target := LanguageName(opts.QueryLanguage)
+	if opts.CrossLanguage == CrossLanguageTranslate && target == "" {
+		detected, err := s.detectLanguage(ctx, model, query)
+		if err != nil {
+			logger.WarnContext(ctx, "Failed to detect the language of a question, marking excerpt languages instead", "component", "rag", "error", err)
+		}
+		target = detected
+	}
+	result.QueryLanguage = target
+
+	marked := false
+	for i, doc := range docs {
+		language := languages[i]
+		if language == "" || (target != "" && sameLanguage(language, target)) {
+			continue
+		}
+		label := Profile(doc.ContentType).Label
+		if opts.CrossLanguage == CrossLanguageTranslate && target != "" {
+			translated, err := s.translateExcerpt(ctx, model, target, doc.Content)
+			if err == nil {
+				contexts[i] = fmt.Sprintf("[%s, translated from %s] %s", label, LanguageName(language), translated)
+				result.TranslatedExcerpts++
+				marked = true
+				continue
+			}
+			logger.WarnContext(ctx, "Failed to translate an excerpt", "component", "rag", "transcription_id", doc.TranscriptionID, "language", language, "error", err)
+		}
+		contexts[i] = fmt.Sprintf("[%s, in %s] %s", label, LanguageName(language), doc.Content)
+		marked = true
+	}
+	if !marked {
+		return contexts, ""
+	}
+
+	var note strings.Builder
+	note.WriteString("Some excerpts come from recordings in other languages; their labels say which. ")
+	if result.TranslatedExcerpts > 0 {
+		note.WriteString("Excerpts marked as translated were machine-translated for this question, so treat their exact wording with care. ")
+	}
+	note.WriteString("Read each excerpt in its source language and answer in the language of the question")
+	if target != "" {
+		fmt.Fprintf(&note, " (%s)", target)
+	}
+	note.WriteString(", translating anything you quote.\n\n")
+	return contexts, note.String()
+}
@@ -0,0 +1,33 @@
+package rag
+
+import "testing"
+
+func TestSameLanguage(t *testing.T) {
+	cases := []struct {
+		a, b string
+		same bool
+	}{
+		{"de", "German", true},
+		{"DE", "german", true},
+		{" en ", "English", true},
+		{"de", "fr", false},
+		{"Klingon", "klingon", true},
+		{"Klingon", "en", false},
+	}
+	for _, c := range cases {
+		if got := sameLanguage(c.a, c.b); got != c.same {
+			t.Errorf("sameLanguage(%q, %q) = %v, want %v", c.a, c.b, got, c.same)
+		}
+	}
+}
+
+func TestIsCrossLanguageMode(t *testing.T) {
+	for _, mode := range []string{CrossLanguageOff, CrossLanguageAnnotate, CrossLanguageTranslate} {
+		if !IsCrossLanguageMode(mode) {
+			t.Errorf("IsCrossLanguageMode(%q) = false", mode)
+		}
+	}
+	if IsCrossLanguageMode("") || IsCrossLanguageMode("auto") {
+		t.Error("unknown modes should be rejected")
+	}
+}
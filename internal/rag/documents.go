@@ -0,0 +1,245 @@
+package rag
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"scriberr/internal/events"
+	"scriberr/internal/models"
+)
+
+// Chunking defaults for uploaded documents, in characters
+const (
+	DocumentChunkSize    = 1500
+	DocumentChunkOverlap = 200
+)
+
+// documentType tags vector store entries that come from uploaded documents rather than transcripts
+const documentType = "document"
+
+// documentChunkID returns the vector store ID of one chunk of a document
+func documentChunkID(documentID string, index int) string {
+	return fmt.Sprintf("document_%s_%d", documentID, index)
+}
+
+// StoreDocument chunks, embeds and stores an uploaded document in its owner's collection for documents,
+// replacing any chunks from an earlier indexing. The summary, if any, is stored as its own
+// chunk. Returns the number of chunks stored.
+func (s *RAGService) StoreDocument(doc *models.Document, text, summary string) (int, error) {
+	collection, err := s.collectionForType(doc.UserID, models.ContentDocument)
+	if err != nil {
+		return 0, err
+	}
+
+	header := fmt.Sprintf("Document: %s", doc.Title)
+	var contents []string
+	if summary != "" {
+		contents = append(contents, fmt.Sprintf("%s\n\nSummary: %s", header, summary))
+	}
+	for _, chunk := range ChunkText(text, DocumentChunkSize, DocumentChunkOverlap) {
+		contents = append(contents, fmt.Sprintf("%s\n\n%s", header, chunk))
+	}
+	if len(contents) == 0 {
+		return 0, fmt.Errorf("document has no text to index")
+	}
+
+	ids := make([]string, len(contents))
+	embeddings := make([][]float32, len(contents))
+	metadatas := make([]map[string]interface{}, len(contents))
+	indexedAt := time.Now().Unix()
+	for i, content := range contents {
+		embedding, err := s.embedding.GenerateEmbedding(content)
+		if err != nil {
+			return 0, fmt.Errorf("failed to generate embedding for chunk %d: %w", i, err)
+		}
+		ids[i] = documentChunkID(doc.ID, i)
+		embeddings[i] = embedding
+		metadata := map[string]interface{}{
+			"document_id":  doc.ID,
+			"type":         documentType,
+			"content_type": models.ContentDocument,
+			"title":        doc.Title,
+			"chunk_index":  i,
+			"indexed_at":   indexedAt,
+		}
+		if doc.TranscriptionID != nil {
+			metadata["recording_id"] = *doc.TranscriptionID
+		}
+		if doc.UserID != nil {
+			metadata["user_id"] = *doc.UserID
+		}
+		metadatas[i] = metadata
+	}
+
+	// Drop chunks from a previous indexing first, since the new text may have fewer chunks
+	if err := s.vectorDB.DeleteDocuments(collection, nil, map[string]interface{}{"document_id": doc.ID}); err != nil {
+		return 0, fmt.Errorf("failed to remove previous chunks: %w", err)
+	}
+	if err := s.vectorDB.UpsertDocuments(collection, ids, contents, embeddings, metadatas); err != nil {
+		return 0, fmt.Errorf("failed to store in vector DB: %w", err)
+	}
+	events.Record(models.EventIndexUpdated, doc.ID, doc.UserID, map[string]interface{}{"kind": "document", "chunks": len(contents)})
+	return len(contents), nil
+}
+
+// DeleteDocument removes every chunk of an uploaded document from its owner's collection
+func (s *RAGService) DeleteDocument(doc *models.Document) error {
+	collection, err := s.collectionForType(doc.UserID, models.ContentDocument)
+	if err != nil {
+		return err
+	}
+	if err := s.vectorDB.DeleteDocuments(collection, nil, map[string]interface{}{"document_id": doc.ID}); err != nil {
+		return fmt.Errorf("failed to delete document %s: %w", doc.ID, err)
+	}
+	return nil
+}
+
+// ChunkText splits text into chunks of at most size characters, packing whole paragraphs
+// and sentences where possible, so chunks don't end mid-sentence. Sentences are found with
+// SplitSentences, which also handles CJK and other scripts without Latin punctuation; only a
+// sentence longer than a chunk is broken, on word boundaries. Each chunk after the first
+// starts with up to overlap characters from the end of the previous one, whole sentences if
+// they fit, so content cut at a boundary stays retrievable.
+func ChunkText(text string, size, overlap int) []string {
+	if size <= 0 {
+		size = DocumentChunkSize
+	}
+	if overlap < 0 || overlap >= size/2 {
+		overlap = 0
+	}
+
+	// Pieces are kept small enough that the overlap, a paragraph break and one piece always fit in a chunk
+	limit := size - overlap - 2
+	if limit < 1 {
+		limit = 1
+	}
+	var pieces []textPiece
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.Join(strings.Fields(paragraph), " ")
+		if paragraph == "" {
+			continue
+		}
+		sep := "\n\n"
+		for _, sentence := range SplitSentences(paragraph) {
+			trimmed := strings.TrimRight(sentence, " ")
+			parts := splitWords(trimmed, limit)
+			parts[0].sep = sep
+			pieces = append(pieces, parts...)
+			// CJK sentences follow each other without a space
+			sep = sentence[len(trimmed):]
+		}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	var starts []int // Offset of each piece in current
+	for _, piece := range pieces {
+		if current.Len() > 0 && current.Len()+len(piece.sep)+len(piece.text) > size {
+			chunk := current.String()
+			chunks = append(chunks, chunk)
+			current.Reset()
+			var repeated string
+			repeated, starts = chunkOverlap(chunk, starts, overlap)
+			current.WriteString(repeated)
+		}
+		if current.Len() > 0 {
+			current.WriteString(piece.sep)
+		}
+		starts = append(starts, current.Len())
+		current.WriteString(piece.text)
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+// textPiece is a sentence, or part of one, to be packed into a chunk
+type textPiece struct {
+	text string
+	sep  string // Joins the piece to the one before it in the same chunk
+}
+
+// splitWords breaks text into pieces of at most limit characters on word boundaries,
+// cutting words that are longer than limit on their own at a character boundary
+func splitWords(text string, limit int) []textPiece {
+	if len(text) <= limit {
+		return []textPiece{{text: text}}
+	}
+	var pieces []textPiece
+	var current strings.Builder
+	sep := ""
+	flush := func() {
+		pieces = append(pieces, textPiece{text: current.String(), sep: sep})
+		current.Reset()
+		sep = " "
+	}
+	for _, word := range strings.Fields(text) {
+		for len(word) > limit {
+			if current.Len() > 0 {
+				flush()
+			}
+			cut := limit
+			for cut > 0 && !utf8.RuneStart(word[cut]) {
+				cut--
+			}
+			if cut == 0 {
+				_, cut = utf8.DecodeRuneInString(word)
+			}
+			current.WriteString(word[:cut])
+			flush()
+			sep = ""
+			word = word[cut:]
+		}
+		if current.Len() > 0 && current.Len()+1+len(word) > limit {
+			flush()
+		}
+		if current.Len() > 0 {
+			current.WriteByte(' ')
+		}
+		current.WriteString(word)
+	}
+	if current.Len() > 0 {
+		flush()
+	}
+	return pieces
+}
+
+// chunkOverlap returns the end of chunk to repeat at the start of the next chunk: the last
+// whole pieces that fit in n characters, or else the last words that do. starts holds the
+// offset of each piece in chunk; the offsets of the repeated pieces in the overlap are
+// returned with it.
+func chunkOverlap(chunk string, starts []int, n int) (string, []int) {
+	if n <= 0 {
+		return "", nil
+	}
+	for i := 1; i < len(starts); i++ {
+		if len(chunk)-starts[i] <= n {
+			offsets := make([]int, 0, len(starts)-i)
+			for _, start := range starts[i:] {
+				offsets = append(offsets, start-starts[i])
+			}
+			return chunk[starts[i]:], offsets
+		}
+	}
+	if repeated := tail(chunk, n); repeated != "" {
+		return repeated, []int{0}
+	}
+	return "", nil
+}
+
+// tail returns at most n characters from the end of text, starting at a word boundary
+func tail(text string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if len(text) <= n {
+		return text
+	}
+	cut := text[len(text)-n:]
+	if i := strings.IndexAny(cut, " \n"); i >= 0 {
+		return strings.TrimSpace(cut[i:])
+	}
+	return ""
+}
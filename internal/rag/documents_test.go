@@ -0,0 +1,80 @@
+package rag
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestChunkTextPacksParagraphs(t *testing.T) {
+	text := "First paragraph.\n\nSecond paragraph.\n\nThird paragraph."
+	chunks := ChunkText(text, 1000, 100)
+	if len(chunks) != 1 {
+		t.Fatalf("expected short text to fit in one chunk, got %d", len(chunks))
+	}
+	if !strings.Contains(chunks[0], "Second paragraph.\n\nThird") {
+		t.Errorf("expected paragraph breaks to be kept, got %q", chunks[0])
+	}
+}
+
+func TestChunkTextRespectsSizeAndOverlap(t *testing.T) {
+	words := make([]string, 500)
+	for i := range words {
+		words[i] = "word"
+	}
+	text := strings.Join(words, " ")
+
+	chunks := ChunkText(text, 200, 40)
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if len(chunk) > 200 {
+			t.Errorf("chunk %d is %d characters, over the limit", i, len(chunk))
+		}
+	}
+	// The start of the second chunk repeats the end of the first
+	overlap := tail(chunks[0], 40)
+	if overlap == "" || !strings.HasPrefix(chunks[1], overlap) {
+		t.Errorf("expected chunk 2 to start with the end of chunk 1, got %q", chunks[1])
+	}
+
+	if got := ChunkText(strings.Repeat("x", 450), 200, 0); len(got) != 3 {
+		t.Errorf("expected an overlong word to be cut into 3 chunks, got %d", len(got))
+	}
+	if got := ChunkText("   \n\n  ", 200, 0); len(got) != 0 {
+		t.Errorf("expected no chunks for blank text, got %d", len(got))
+	}
+}
+
+func TestChunkTextKeepsSentencesWhole(t *testing.T) {
+	text := "The first sentence is about the budget. The second one covers hiring plans. " +
+		"The third sentence is on office moves. The fourth wraps up the meeting."
+	chunks := ChunkText(text, 90, 40)
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if !strings.HasPrefix(chunk, "The ") || !strings.HasSuffix(chunk, ".") {
+			t.Errorf("chunk %d doesn't start and end at sentence boundaries: %q", i, chunk)
+		}
+	}
+
+	cjk := strings.Repeat("今天的会议讨论了预算。", 20)
+	chunks = ChunkText(cjk, 100, 0)
+	if len(chunks) < 2 {
+		t.Fatalf("expected CJK text to be split, got %d chunks", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if len(chunk) > 100 || !strings.HasSuffix(chunk, "。") || strings.Contains(chunk, " ") {
+			t.Errorf("CJK chunk %d isn't whole sentences without added spaces: %q", i, chunk)
+		}
+	}
+
+	// A long run without punctuation or spaces is cut between characters, not inside one
+	for _, chunk := range ChunkText(strings.Repeat("予算", 100), 100, 0) {
+		if !utf8.ValidString(chunk) {
+			t.Errorf("chunk cut inside a character: %q", chunk)
+		}
+	}
+}
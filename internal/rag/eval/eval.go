@@ -0,0 +1,164 @@
+// Package eval measures retrieval quality against a set of questions with known answers.
+package eval
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultK is the number of results retrieved per question when none is given
+const DefaultK = 5
+
+// Retriever returns the source transcription IDs retrieved for a question, best match first
+type Retriever func(ctx context.Context, question string, k int) ([]string, error)
+
+// Case is a question and the transcriptions that are expected to answer it
+type Case struct {
+	ID              string
+	Question        string
+	ExpectedSources []string
+}
+
+// CaseResult is the outcome of a single case
+type CaseResult struct {
+	CaseID          string   `json:"case_id"`
+	Question        string   `json:"question"`
+	ExpectedSources []string `json:"expected_sources"`
+	Retrieved       []string `json:"retrieved"`
+	// FirstRelevantRank is the 1-based rank of the first expected source, or 0 if none was retrieved
+	FirstRelevantRank int     `json:"first_relevant_rank"`
+	Recall            float64 `json:"recall"`
+	ReciprocalRank    float64 `json:"reciprocal_rank"`
+	Error             string  `json:"error,omitempty"`
+}
+
+// Report aggregates the results of an evaluation run. Metrics are averaged over the
+// cases that ran; cases whose retrieval failed are reported but not scored.
+type Report struct {
+	RanAt          time.Time    `json:"ran_at"`
+	DurationMs     int64        `json:"duration_ms"`
+	K              int          `json:"k"`
+	EmbeddingModel string       `json:"embedding_model,omitempty"`
+	CaseCount      int          `json:"case_count"`
+	Evaluated      int          `json:"evaluated"`
+	Errors         int          `json:"errors"`
+	RecallAtK      float64      `json:"recall_at_k"`
+	MRR            float64      `json:"mrr"`
+	HitRate        float64      `json:"hit_rate"` // Share of cases with at least one expected source in the top k
+	Results        []CaseResult `json:"results"`
+}
+
+// Run evaluates every case against retrieve, retrieving k results per question
+func Run(ctx context.Context, retrieve Retriever, cases []Case, k int) *Report {
+	if k <= 0 {
+		k = DefaultK
+	}
+
+	start := time.Now()
+	report := &Report{
+		RanAt:     start,
+		K:         k,
+		CaseCount: len(cases),
+		Results:   make([]CaseResult, 0, len(cases)),
+	}
+
+	var recallSum, rrSum float64
+	var hits int
+	for _, c := range cases {
+		result := CaseResult{
+			CaseID:          c.ID,
+			Question:        c.Question,
+			ExpectedSources: c.ExpectedSources,
+		}
+
+		if err := ctx.Err(); err != nil {
+			result.Error = err.Error()
+			report.Errors++
+			report.Results = append(report.Results, result)
+			continue
+		}
+
+		retrieved, err := retrieve(ctx, c.Question, k)
+		if err != nil {
+			result.Error = err.Error()
+			report.Errors++
+			report.Results = append(report.Results, result)
+			continue
+		}
+
+		result.Retrieved = dedupe(retrieved)
+		result.Recall = RecallAtK(result.Retrieved, c.ExpectedSources, k)
+		result.ReciprocalRank, result.FirstRelevantRank = ReciprocalRank(result.Retrieved, c.ExpectedSources)
+		if result.FirstRelevantRank > 0 && result.FirstRelevantRank <= k {
+			hits++
+		}
+
+		recallSum += result.Recall
+		rrSum += result.ReciprocalRank
+		report.Evaluated++
+		report.Results = append(report.Results, result)
+	}
+
+	if report.Evaluated > 0 {
+		n := float64(report.Evaluated)
+		report.RecallAtK = recallSum / n
+		report.MRR = rrSum / n
+		report.HitRate = float64(hits) / n
+	}
+	report.DurationMs = time.Since(start).Milliseconds()
+	return report
+}
+
+// RecallAtK returns the share of expected sources found in the first k retrieved sources
+func RecallAtK(retrieved, expected []string, k int) float64 {
+	if len(expected) == 0 {
+		return 0
+	}
+	if k > 0 && len(retrieved) > k {
+		retrieved = retrieved[:k]
+	}
+
+	want := make(map[string]bool, len(expected))
+	for _, id := range expected {
+		want[id] = true
+	}
+
+	found := 0
+	for _, id := range retrieved {
+		if want[id] {
+			found++
+			delete(want, id)
+		}
+	}
+	return float64(found) / float64(len(expected))
+}
+
+// ReciprocalRank returns 1/rank of the first expected source among the retrieved sources,
+// together with that 1-based rank. Both are 0 when no expected source was retrieved.
+func ReciprocalRank(retrieved, expected []string) (float64, int) {
+	want := make(map[string]bool, len(expected))
+	for _, id := range expected {
+		want[id] = true
+	}
+	for i, id := range retrieved {
+		if want[id] {
+			return 1 / float64(i+1), i + 1
+		}
+	}
+	return 0, 0
+}
+
+// dedupe keeps the first occurrence of each source, so several chunks of one
+// transcription count as a single ranked result
+func dedupe(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
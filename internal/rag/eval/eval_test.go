@@ -0,0 +1,83 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestRecallAtK(t *testing.T) {
+	retrieved := []string{"a", "b", "c", "d"}
+
+	if got := RecallAtK(retrieved, []string{"b", "d"}, 4); !approxEqual(got, 1) {
+		t.Errorf("expected recall 1, got %v", got)
+	}
+	if got := RecallAtK(retrieved, []string{"b", "d"}, 2); !approxEqual(got, 0.5) {
+		t.Errorf("expected recall 0.5 at k=2, got %v", got)
+	}
+	if got := RecallAtK(retrieved, []string{"x"}, 4); got != 0 {
+		t.Errorf("expected recall 0, got %v", got)
+	}
+}
+
+func TestReciprocalRank(t *testing.T) {
+	rr, rank := ReciprocalRank([]string{"a", "b", "c"}, []string{"c", "b"})
+	if rank != 2 || !approxEqual(rr, 0.5) {
+		t.Errorf("expected rank 2 and rr 0.5, got %d and %v", rank, rr)
+	}
+
+	rr, rank = ReciprocalRank([]string{"a"}, []string{"b"})
+	if rank != 0 || rr != 0 {
+		t.Errorf("expected no relevant result, got rank %d rr %v", rank, rr)
+	}
+}
+
+func TestRunAggregatesMetrics(t *testing.T) {
+	results := map[string][]string{
+		"q1": {"t1", "t1", "t2"}, // duplicate chunks of t1 count once
+		"q2": {"t3", "t4"},
+		"q3": {"t5"},
+	}
+	retrieve := func(ctx context.Context, question string, k int) ([]string, error) {
+		if question == "broken" {
+			return nil, errors.New("embedding failed")
+		}
+		return results[question], nil
+	}
+
+	cases := []Case{
+		{ID: "1", Question: "q1", ExpectedSources: []string{"t2"}},
+		{ID: "2", Question: "q2", ExpectedSources: []string{"t3"}},
+		{ID: "3", Question: "q3", ExpectedSources: []string{"t9"}},
+		{ID: "4", Question: "broken", ExpectedSources: []string{"t1"}},
+	}
+
+	report := Run(context.Background(), retrieve, cases, 0)
+
+	if report.K != DefaultK {
+		t.Errorf("expected default k %d, got %d", DefaultK, report.K)
+	}
+	if report.Evaluated != 3 || report.Errors != 1 {
+		t.Fatalf("expected 3 evaluated and 1 error, got %d and %d", report.Evaluated, report.Errors)
+	}
+	if got := report.Results[0].FirstRelevantRank; got != 2 {
+		t.Errorf("expected t2 at rank 2 after dedupe, got %d", got)
+	}
+	if !approxEqual(report.RecallAtK, 2.0/3) {
+		t.Errorf("expected recall@k 2/3, got %v", report.RecallAtK)
+	}
+	if !approxEqual(report.MRR, (0.5+1)/3) {
+		t.Errorf("expected MRR 0.5, got %v", report.MRR)
+	}
+	if !approxEqual(report.HitRate, 2.0/3) {
+		t.Errorf("expected hit rate 2/3, got %v", report.HitRate)
+	}
+	if report.Results[3].Error == "" {
+		t.Error("expected the failed case to record its error")
+	}
+}
@@ -0,0 +1,119 @@
+package rag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/llm"
+)
+
+// Answer modes of Chat
+const (
+	// ModeAbstractive has the LLM write the answer from the retrieved context
+	ModeAbstractive = "abstractive"
+	// ModeExtractive composes the answer only of sentences quoted word for word from the
+	// retrieved context, each with a citation; the LLM only chooses the sentences
+	ModeExtractive = "extractive"
+)
+
+// NoExtractiveAnswer is the answer of an extractive chat when no retrieved sentence answers the question
+const NoExtractiveAnswer = "No sentence in the relevant transcripts answers this question."
+
+// Quote is a sentence of an extractive answer, as it appears in the excerpt it was taken from
+type Quote struct {
+	Text string `json:"text"`
+	// Source is the number the answer cites the excerpt by, counting from 1 in retrieval order
+	Source          int      `json:"source"`
+	TranscriptionID string   `json:"transcription_id,omitempty"`
+	DocumentID      string   `json:"document_id,omitempty"`
+	Start           *float64 `json:"start,omitempty"` // Time range of the excerpt the sentence is in
+	End             *float64 `json:"end,omitempty"`
+	Speaker         string   `json:"speaker,omitempty"`
+}
+
+// selectionSchema is the JSON Schema of the LLM's choice of sentences
+var selectionSchema = llm.Schema{
+	Name:        "sentence_selection",
+	Description: "The numbered sentences that answer a question",
+	Definition: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "sentences": {
+      "type": "array",
+      "items": {"type": "string", "description": "A sentence number such as 2.3, exactly as given"}
+    }
+  },
+  "required": ["sentences"]
+}`),
+}
+
+// contentLabels start the parts of a summary entry (see StoreSummary); they aren't quoted
+var contentLabels = []string{"Summary: ", "Transcript: "}
+
+// extractAnswer asks the LLM which sentences of docs answer query and composes the answer of
+// those sentences, verbatim, in the order chosen, each followed by the number of its excerpt
+func (s *RAGService) extractAnswer(ctx context.Context, model, query string, docs []RetrievedDocument) (string, []Quote, error) {
+	sentences := map[string]Quote{}
+	var prompt strings.Builder
+	prompt.WriteString("The following excerpts of transcriptions are split into numbered sentences.\n\n")
+	for i, doc := range docs {
+		prompt.WriteString(fmt.Sprintf("Excerpt %d:\n", i+1))
+		for j, sentence := range SplitSentences(doc.Content) {
+			text := strings.TrimSpace(sentence)
+			for _, label := range contentLabels {
+				text = strings.TrimSpace(strings.TrimPrefix(text, label))
+			}
+			if text == "" {
+				continue
+			}
+			id := fmt.Sprintf("%d.%d", i+1, j+1)
+			sentences[id] = Quote{
+				Text:            text,
+				Source:          i + 1,
+				TranscriptionID: doc.TranscriptionID,
+				DocumentID:      doc.DocumentID,
+				Start:           doc.Start,
+				End:             doc.End,
+				Speaker:         doc.Speaker,
+			}
+			prompt.WriteString(fmt.Sprintf("[%s] %s\n", id, text))
+		}
+		prompt.WriteString("\n")
+	}
+	prompt.WriteString("Question: ")
+	prompt.WriteString(query)
+	prompt.WriteString("\n\nChoose the sentences that together answer the question, in the order they should be read, " +
+		"and reply with their numbers only. Don't write anything yourself. Choose as few sentences as answer the question fully, " +
+		"and none if no sentence answers it.")
+
+	messages := []llm.ChatMessage{{Role: "user", Content: prompt.String()}}
+	var reply struct {
+		Sentences []string `json:"sentences"`
+	}
+	if err := llm.CompleteJSON(ctx, s.llmService, model, messages, 0, selectionSchema, &reply); err != nil {
+		return "", nil, fmt.Errorf("failed to select sentences: %w", err)
+	}
+
+	quotes := []Quote{}
+	seen := map[string]bool{}
+	for _, id := range reply.Sentences {
+		id = strings.Trim(strings.TrimSpace(id), "[]")
+		quote, ok := sentences[id]
+		if !ok || seen[id] {
+			continue // Numbers the LLM made up are ignored
+		}
+		seen[id] = true
+		quotes = append(quotes, quote)
+	}
+	if len(quotes) == 0 {
+		return NoExtractiveAnswer, quotes, nil
+	}
+	lines := make([]string, len(quotes))
+	for i, quote := range quotes {
+		lines[i] = quote.Text + " [" + strconv.Itoa(quote.Source) + "]"
+	}
+	return strings.Join(lines, "\n"), quotes, nil
+}
@@ -0,0 +1,188 @@
+package rag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/llm"
+	"scriberr/pkg/logger"
+)
+
+// NoRelevantContextAnswer is returned by Chat when retrieval finds nothing relevant to the question
+const NoRelevantContextAnswer = "No relevant transcripts found for this question."
+
+// ChatOptions adjusts how Chat answers a query
+type ChatOptions struct {
+	// Verify runs a second LLM pass that checks the answer against the retrieved context
+	Verify bool
+	// TranscriptionIDs limits retrieval to these transcriptions (and their linked documents) when non-nil
+	TranscriptionIDs []string
+	// Mode is ModeAbstractive (the default when empty) or ModeExtractive
+	Mode string
+	// Collections limits retrieval to the collections of these routes (see Routes); empty searches all of them
+	Collections []string
+	// CrossLanguage is how excerpts in another language than the question are put into the
+	// prompt: CrossLanguageOff (the default when empty), CrossLanguageAnnotate or
+	// CrossLanguageTranslate. Extractive answers quote excerpts as they are.
+	CrossLanguage string
+	// QueryLanguage is the language of the question, by code or name; when empty, translating
+	// asks the LLM for it
+	QueryLanguage string
+	// History is the conversation before the question, oldest first. It goes before the prompt
+	// so follow-up questions can refer back to it; retrieval uses the question alone.
+	// Extractive answers leave it out.
+	History []llm.ChatMessage
+}
+
+// ChatResult is the answer to a RAG chat query along with where it came from
+type ChatResult struct {
+	Answer            string        `json:"response"`
+	Sources           []string      `json:"sources"`                    // Transcription IDs used as context
+	DocumentSources   []string      `json:"document_sources,omitempty"` // Uploaded document IDs used as context
+	NoRelevantContext bool          `json:"no_relevant_context"`
+	Verification      *Verification `json:"verification,omitempty"`
+	Quotes            []Quote       `json:"quotes,omitempty"` // The sentences of an extractive answer
+	// QueryLanguage is the language of the question, when excerpts in other languages were handled
+	QueryLanguage      string `json:"query_language,omitempty"`
+	TranslatedExcerpts int    `json:"translated_excerpts,omitempty"` // Excerpts translated into QueryLanguage
+
+	// Retrieved is every relevant excerpt found for the query, best first; the first
+	// chatContextDocuments of them were given to the LLM
+	Retrieved []RetrievedDocument `json:"-"`
+}
+
+// Verification is the outcome of checking an answer against its retrieved context
+type Verification struct {
+	Grounded          bool     `json:"grounded"`
+	Confidence        float64  `json:"confidence"` // 0-1, how well the context supports the answer
+	UnsupportedClaims []string `json:"unsupported_claims,omitempty"`
+	Error             string   `json:"error,omitempty"` // Set when the check itself could not be completed
+}
+
+// relevant drops documents beyond the configured distance threshold
+func (s *RAGService) relevant(docs []RetrievedDocument) []RetrievedDocument {
+	if s.maxDistance <= 0 {
+		return docs
+	}
+	kept := make([]RetrievedDocument, 0, len(docs))
+	for _, doc := range docs {
+		if doc.Distance <= s.maxDistance {
+			kept = append(kept, doc)
+		}
+	}
+	return kept
+}
+
+// sourceIDs returns the distinct transcriptions behind a set of documents, in rank order
+func sourceIDs(docs []RetrievedDocument) []string {
+	return distinct(docs, func(doc RetrievedDocument) string { return doc.TranscriptionID })
+}
+
+// documentIDs returns the distinct uploaded documents behind a set of documents, in rank order
+func documentIDs(docs []RetrievedDocument) []string {
+	return distinct(docs, func(doc RetrievedDocument) string { return doc.DocumentID })
+}
+
+func distinct(docs []RetrievedDocument, key func(RetrievedDocument) string) []string {
+	seen := make(map[string]bool, len(docs))
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		id := key(doc)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// writeContexts appends numbered context passages to a prompt
+func writeContexts(prompt *strings.Builder, contexts []string) {
+	for i, ctx := range contexts {
+		prompt.WriteString(fmt.Sprintf("%d. %s\n\n", i+1, ctx))
+	}
+}
+
+// verifyAnswer asks the LLM whether every claim in answer is supported by the context.
+// Failures are reported on the returned Verification rather than failing the chat.
+func (s *RAGService) verifyAnswer(ctx context.Context, model, query, answer string, contexts []string) *Verification {
+	var prompt strings.Builder
+	prompt.WriteString("You are checking whether an answer is supported by the context it was based on.\n\n")
+	prompt.WriteString("Context:\n")
+	writeContexts(&prompt, contexts)
+	prompt.WriteString("Question: ")
+	prompt.WriteString(query)
+	prompt.WriteString("\n\nAnswer: ")
+	prompt.WriteString(answer)
+	prompt.WriteString("\n\nCheck every factual claim in the answer against the context. ")
+	prompt.WriteString(`Reply with JSON only, in the form {"grounded": true, "confidence": 0.9, "unsupported_claims": []}, where grounded is false if any claim is not supported by the context, confidence is between 0 and 1, and unsupported_claims lists the claims the context does not support.`)
+
+	messages := []llm.ChatMessage{
+		{Role: "user", Content: prompt.String()},
+	}
+
+	response, err := s.llmService.ChatCompletion(ctx, model, messages, 0)
+	if err != nil {
+		logger.WarnContext(ctx, "Answer verification failed", "component", "rag", "error", err)
+		return &Verification{Error: fmt.Sprintf("verification failed: %v", err)}
+	}
+	if len(response.Choices) == 0 {
+		return &Verification{Error: "no response from LLM"}
+	}
+
+	verification, err := parseVerification(response.Choices[0].Message.Content)
+	if err != nil {
+		logger.WarnContext(ctx, "Could not parse answer verification", "component", "rag", "error", err)
+		return &Verification{Error: err.Error()}
+	}
+	return verification
+}
+
+// parseVerification extracts the verification JSON from an LLM reply, tolerating surrounding text or code fences
+func parseVerification(reply string) (*Verification, error) {
+	start := strings.Index(reply, "{")
+	end := strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("verification reply contained no JSON object")
+	}
+
+	var raw struct {
+		Grounded          *bool    `json:"grounded"`
+		Confidence        *float64 `json:"confidence"`
+		UnsupportedClaims []string `json:"unsupported_claims"`
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode verification reply: %w", err)
+	}
+	if raw.Grounded == nil {
+		return nil, fmt.Errorf("verification reply is missing the grounded field")
+	}
+
+	v := &Verification{
+		Grounded:          *raw.Grounded,
+		UnsupportedClaims: raw.UnsupportedClaims,
+	}
+	switch {
+	case raw.Confidence != nil:
+		v.Confidence = *raw.Confidence
+	case v.Grounded:
+		v.Confidence = 1
+	}
+	// Some models answer on a 0-100 scale
+	if v.Confidence > 1 && v.Confidence <= 100 {
+		v.Confidence /= 100
+	}
+	if v.Confidence < 0 {
+		v.Confidence = 0
+	} else if v.Confidence > 1 {
+		v.Confidence = 1
+	}
+	// An answer with unsupported claims isn't grounded, whatever the model concluded
+	if len(v.UnsupportedClaims) > 0 {
+		v.Grounded = false
+	}
+	return v, nil
+}
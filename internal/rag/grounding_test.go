@@ -0,0 +1,51 @@
+package rag
+
+import "testing"
+
+func TestParseVerification(t *testing.T) {
+	reply := "```json\n{\"grounded\": true, \"confidence\": 85, \"unsupported_claims\": []}\n```"
+	v, err := parseVerification(reply)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !v.Grounded || v.Confidence != 0.85 {
+		t.Errorf("expected grounded with confidence 0.85, got %v and %v", v.Grounded, v.Confidence)
+	}
+
+	v, err = parseVerification(`{"grounded": true, "confidence": 0.7, "unsupported_claims": ["the launch is in May"]}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.Grounded {
+		t.Error("expected an answer with unsupported claims to be ungrounded")
+	}
+
+	if _, err := parseVerification("The answer looks fine."); err == nil {
+		t.Error("expected an error for a reply without JSON")
+	}
+	if _, err := parseVerification(`{"confidence": 0.5}`); err == nil {
+		t.Error("expected an error when grounded is missing")
+	}
+}
+
+func TestRelevantDropsDistantDocuments(t *testing.T) {
+	docs := []RetrievedDocument{
+		{TranscriptionID: "a", Distance: 0.2},
+		{TranscriptionID: "b", Distance: 0.9},
+		{TranscriptionID: "a", Distance: 0.3},
+	}
+
+	s := &RAGService{}
+	if got := s.relevant(docs); len(got) != 3 {
+		t.Errorf("expected no cutoff by default, got %d documents", len(got))
+	}
+
+	s.SetMaxDistance(0.5)
+	kept := s.relevant(docs)
+	if len(kept) != 2 {
+		t.Fatalf("expected 2 documents within distance, got %d", len(kept))
+	}
+	if ids := sourceIDs(kept); len(ids) != 1 || ids[0] != "a" {
+		t.Errorf("expected a single source a, got %v", ids)
+	}
+}
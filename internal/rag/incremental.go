@@ -0,0 +1,170 @@
+package rag
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/events"
+	"scriberr/internal/models"
+	"scriberr/internal/vectordb"
+	"scriberr/pkg/logger"
+)
+
+// Indexing a transcription stores, on each of its entries, a hash of the embedded text and the
+// embedding model. Re-indexing reuses the embedding of any entry whose text and model are
+// unchanged, so an edit to an hour-long transcript only re-embeds the chunks it touched.
+// Titles, tags and speaker names are copied onto the entries as metadata, and changing them
+// only updates that metadata (see UpdateMetadata).
+
+// contentHash identifies the text an embedding was computed from
+func contentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// embeddingCache embeds texts, reusing embeddings from the previous indexing of a transcription
+type embeddingCache struct {
+	s     *RAGService
+	ctx   context.Context      // Embedding calls are traced as its children
+	known map[string][]float32 // content hash -> embedding
+
+	embedded, reused int
+}
+
+// newEmbeddingCache loads the embeddings a transcription was last indexed with. Entries made
+// by another embedding model, or before hashes were recorded, are not reused.
+func (s *RAGService) newEmbeddingCache(ctx context.Context, collection, transcriptionID string) (*embeddingCache, error) {
+	cache := &embeddingCache{s: s, ctx: ctx, known: map[string][]float32{}}
+	existing, err := s.store(ctx).GetDocuments(collection, vectordb.GetRequest{
+		Where:   map[string]interface{}{"transcription_id": transcriptionID},
+		Include: []string{"metadatas", "embeddings"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load previous embeddings for %s: %w", transcriptionID, err)
+	}
+	model := s.embedding.Model()
+	for i := range existing.IDs {
+		if i >= len(existing.Metadatas) || i >= len(existing.Embeddings) {
+			break
+		}
+		hash, _ := existing.Metadatas[i]["content_hash"].(string)
+		entryModel, _ := existing.Metadatas[i]["embedding_model"].(string)
+		if hash != "" && entryModel == model && len(existing.Embeddings[i]) > 0 {
+			cache.known[hash] = existing.Embeddings[i]
+		}
+	}
+	return cache, nil
+}
+
+// embed returns the embedding of text and its content hash
+func (c *embeddingCache) embed(text string) ([]float32, string, error) {
+	hash := contentHash(text)
+	if embedding, ok := c.known[hash]; ok {
+		c.reused++
+		return embedding, hash, nil
+	}
+	embedding, err := c.s.embedder(c.ctx).GenerateEmbedding(text)
+	if err != nil {
+		return nil, "", err
+	}
+	c.known[hash] = embedding
+	c.embedded++
+	return embedding, hash, nil
+}
+
+// jobMetadata holds the transcription fields copied onto its vector store entries
+type jobMetadata struct {
+	title       string
+	tags        string
+	contentType string
+	speakers    map[string]string // upper-cased diarization label -> custom name
+}
+
+// loadJobMetadata reads the title, tags, content type and speaker names of a transcription
+func loadJobMetadata(transcriptionID string) (*jobMetadata, error) {
+	var job models.TranscriptionJob
+	if err := database.DB.Select("id", "title", "tags", "content_type").Where("id = ?", transcriptionID).First(&job).Error; err != nil {
+		return nil, fmt.Errorf("failed to load transcription %s: %w", transcriptionID, err)
+	}
+	var mappings []models.SpeakerMapping
+	if err := database.DB.Where("transcription_job_id = ?", transcriptionID).Find(&mappings).Error; err != nil {
+		return nil, fmt.Errorf("failed to load speaker names for %s: %w", transcriptionID, err)
+	}
+
+	meta := &jobMetadata{
+		// Vector store metadata only holds scalars, so tags are stored comma-separated
+		tags:        strings.Join(job.Tags, ","),
+		contentType: job.ContentType,
+		speakers:    make(map[string]string, len(mappings)),
+	}
+	if job.Title != nil {
+		meta.title = *job.Title
+	}
+	for _, mapping := range mappings {
+		if mapping.CustomName != "" {
+			meta.speakers[strings.ToUpper(mapping.OriginalSpeaker)] = mapping.CustomName
+		}
+	}
+	return meta, nil
+}
+
+// apply sets the transcription fields on an entry's metadata. Fields are always written,
+// empty when unset, so clearing a title or tag replaces the old value.
+func (m *jobMetadata) apply(metadata map[string]interface{}) {
+	metadata["title"] = m.title
+	metadata["tags"] = m.tags
+	metadata["content_type"] = m.contentType
+	if speaker, ok := metadata["speaker"].(string); ok && speaker != "" {
+		metadata["speaker_name"] = m.speakers[strings.ToUpper(speaker)]
+	}
+}
+
+// UpdateMetadata refreshes the title, tags and speaker names stored with a transcription's
+// entries without re-embedding anything. It does nothing if the transcription isn't indexed.
+func (s *RAGService) UpdateMetadata(transcriptionID string) error {
+	owner, collection, err := s.jobCollection(transcriptionID)
+	if err != nil {
+		return err
+	}
+	meta, err := loadJobMetadata(transcriptionID)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.vectorDB.GetDocuments(collection, vectordb.GetRequest{
+		Where:   map[string]interface{}{"transcription_id": transcriptionID},
+		Include: []string{"metadatas"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load entries for %s: %w", transcriptionID, err)
+	}
+	if len(existing.IDs) == 0 {
+		return nil
+	}
+
+	// The entries are current again, so the audit shouldn't report them as stale
+	indexedAt := time.Now().Unix()
+	metadatas := make([]map[string]interface{}, len(existing.IDs))
+	for i := range existing.IDs {
+		metadata := map[string]interface{}{"indexed_at": indexedAt}
+		if i < len(existing.Metadatas) {
+			if speaker, ok := existing.Metadatas[i]["speaker"].(string); ok {
+				metadata["speaker"] = speaker
+			}
+		}
+		meta.apply(metadata)
+		metadatas[i] = metadata
+	}
+	if err := s.vectorDB.UpdateMetadata(collection, existing.IDs, metadatas); err != nil {
+		return fmt.Errorf("failed to update metadata for %s: %w", transcriptionID, err)
+	}
+
+	logger.Info("Updated metadata without re-embedding", "component", "rag", "job_id", transcriptionID, "entries", len(existing.IDs))
+	events.Record(models.EventIndexUpdated, transcriptionID, owner, map[string]interface{}{"kind": "transcription", "metadata_only": true})
+	return nil
+}
@@ -0,0 +1,98 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// liveChunkType tags vector store entries holding part of a meeting that is still being transcribed
+const liveChunkType = "live_chunk"
+
+// LiveCollectionName returns the temporary collection holding a live session's partial transcript.
+// It is kept apart from the users' collections so unfinished meetings never show up in chat or search.
+func LiveCollectionName(sessionID string) string {
+	return "live_" + sessionID
+}
+
+// StoreLiveChunks indexes chunks of a live session's transcript in the session's temporary
+// collection. Chunk IDs continue from first, so each call only embeds the new chunks.
+func (s *RAGService) StoreLiveChunks(sessionID string, first int, chunks []TranscriptChunk) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+	collection := LiveCollectionName(sessionID)
+	s.ensureCollection(collection)
+
+	indexedAt := time.Now().Unix()
+	ids := make([]string, len(chunks))
+	contents := make([]string, len(chunks))
+	embeddings := make([][]float32, len(chunks))
+	metadatas := make([]map[string]interface{}, len(chunks))
+	for i, chunk := range chunks {
+		embedding, err := s.embedding.GenerateEmbedding(chunk.Text)
+		if err != nil {
+			return fmt.Errorf("failed to generate embedding for chunk %d: %w", first+i, err)
+		}
+		ids[i] = fmt.Sprintf("live_%s_%d", sessionID, first+i)
+		contents[i] = chunk.Text
+		embeddings[i] = embedding
+		metadata := map[string]interface{}{
+			"session_id":      sessionID,
+			"type":            liveChunkType,
+			"chunk_index":     first + i,
+			"start":           chunk.Start,
+			"end":             chunk.End,
+			"indexed_at":      indexedAt,
+			"embedding_model": s.embedding.Model(),
+		}
+		if chunk.Speaker != "" {
+			metadata["speaker"] = chunk.Speaker
+		}
+		metadatas[i] = metadata
+	}
+	if err := s.vectorDB.UpsertDocuments(collection, ids, contents, embeddings, metadatas); err != nil {
+		return fmt.Errorf("failed to store live chunks in vector DB: %w", err)
+	}
+	return nil
+}
+
+// RetrieveLive returns the parts of a live session's transcript most similar to query, best
+// match first. With withLibrary, the finished transcriptions visible to userID are searched too.
+// Documents beyond the configured distance threshold are left out of both.
+func (s *RAGService) RetrieveLive(ctx context.Context, sessionID string, userID *uint, query string, nResults int, withLibrary bool) (live, library []RetrievedDocument, err error) {
+	if nResults == 0 {
+		nResults = 5
+	}
+	collection := LiveCollectionName(sessionID)
+	s.ensureCollection(collection)
+	live, err = s.query(ctx, collection, query, nResults, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	library = []RetrievedDocument{}
+	if withLibrary {
+		if library, err = s.retrieve(ctx, userID, query, nResults, nil); err != nil {
+			return nil, nil, err
+		}
+	}
+	return s.relevant(live), s.relevant(library), nil
+}
+
+// DropLive deletes everything indexed for a live session
+func (s *RAGService) DropLive(sessionID string) error {
+	collection := LiveCollectionName(sessionID)
+	s.mu.Lock()
+	created := s.collections[collection]
+	s.mu.Unlock()
+	if !created {
+		return nil
+	}
+	if err := s.vectorDB.DeleteDocuments(collection, nil, map[string]interface{}{"session_id": sessionID}); err != nil {
+		return fmt.Errorf("failed to delete live session %s: %w", sessionID, err)
+	}
+	s.mu.Lock()
+	delete(s.collections, collection)
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,40 @@
+package rag
+
+import (
+	"fmt"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/redact"
+)
+
+// SetEmbedRedacted sets whether transcriptions are embedded and stored with their personal
+// data redacted rather than as transcribed
+func (s *RAGService) SetEmbedRedacted(redacted bool) {
+	s.embedRedacted = redacted
+}
+
+// EmbedsRedacted reports whether transcriptions are stored with their personal data redacted
+func (s *RAGService) EmbedsRedacted() bool {
+	return s.embedRedacted
+}
+
+// textForIndex returns the function that turns a transcription's text, or text derived from
+// it, into the text that is embedded and stored. Without redacted embedding that is the text
+// as is. With it, personal data is replaced using the names found when the transcript was
+// redacted; a transcription that hasn't been redacted yet still gets its email addresses,
+// phone numbers and card numbers replaced.
+func (s *RAGService) textForIndex(transcriptionID string) (func(string) string, error) {
+	if !s.embedRedacted {
+		return func(text string) string { return text }, nil
+	}
+	var redaction models.Redaction
+	if err := database.DB.Select("names").Where("transcription_id = ?", transcriptionID).Limit(1).Find(&redaction).Error; err != nil {
+		return nil, fmt.Errorf("failed to load redaction of %s: %w", transcriptionID, err)
+	}
+	redactor := redact.New(redaction.Names)
+	return func(text string) string {
+		redacted, _ := redactor.Redact(text)
+		return redacted
+	}, nil
+}
@@ -0,0 +1,147 @@
+package rag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/internal/vectordb"
+)
+
+// summaryType tags the single whole-transcription entry stored for each transcription
+const summaryType = "summary"
+
+// ErrNothingToCompare is returned by Related when a transcription has neither a summary nor transcript text
+var ErrNothingToCompare = errors.New("transcription has no summary or transcript to compare")
+
+// SummaryEmbedding is the stored whole-transcription entry of one transcription
+type SummaryEmbedding struct {
+	TranscriptionID string
+	Content         string
+	Embedding       []float32
+}
+
+// SummaryEmbeddings returns the whole-transcription entry of every transcription in a main
+// collection and the collections routed next to it
+func (s *RAGService) SummaryEmbeddings(ctx context.Context, collection string) ([]SummaryEmbedding, error) {
+	var entries []SummaryEmbedding
+	for _, name := range s.collectionsOf(collection, nil) {
+		found, err := s.summaryEmbeddingsIn(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, found...)
+	}
+	return entries, nil
+}
+
+// summaryEmbeddingsIn returns the whole-transcription entries stored in one collection
+func (s *RAGService) summaryEmbeddingsIn(ctx context.Context, collection string) ([]SummaryEmbedding, error) {
+	var entries []SummaryEmbedding
+	for offset := 0; ; offset += auditPageSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page, err := s.store(ctx).GetDocuments(collection, vectordb.GetRequest{
+			Where:   map[string]interface{}{"type": summaryType},
+			Limit:   auditPageSize,
+			Offset:  offset,
+			Include: []string{"metadatas", "documents", "embeddings"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list summaries in %s: %w", collection, err)
+		}
+		for i, id := range page.IDs {
+			entry := SummaryEmbedding{TranscriptionID: id}
+			if i < len(page.Metadatas) {
+				if tid, ok := page.Metadatas[i]["transcription_id"].(string); ok && tid != "" {
+					entry.TranscriptionID = tid
+				}
+			}
+			if i < len(page.Documents) {
+				entry.Content = page.Documents[i]
+			}
+			if i < len(page.Embeddings) {
+				entry.Embedding = page.Embeddings[i]
+			}
+			if len(entry.Embedding) > 0 {
+				entries = append(entries, entry)
+			}
+		}
+		if len(page.IDs) < auditPageSize {
+			return entries, nil
+		}
+	}
+}
+
+// RelatedTranscription is a transcription similar to another one
+type RelatedTranscription struct {
+	TranscriptionID string  `json:"transcription_id"`
+	Distance        float32 `json:"distance"`
+}
+
+// Related returns the transcriptions visible to userID that are most similar to the given one,
+// best match first. The transcription's summary is embedded and compared against the
+// whole-transcription entries of the others; without a summary its transcript text is used.
+func (s *RAGService) Related(ctx context.Context, userID *uint, transcriptionID string, nResults int) ([]RelatedTranscription, error) {
+	var job models.TranscriptionJob
+	if err := database.DB.Select("id", "summary", "transcript").Where("id = ?", transcriptionID).First(&job).Error; err != nil {
+		return nil, fmt.Errorf("failed to load transcription %s: %w", transcriptionID, err)
+	}
+
+	var text string
+	if job.Summary != nil {
+		text = strings.TrimSpace(*job.Summary)
+	}
+	if text == "" && job.Transcript != nil {
+		text = transcriptText(*job.Transcript)
+	}
+	if text == "" {
+		return nil, ErrNothingToCompare
+	}
+
+	where := map[string]interface{}{
+		"$and": []map[string]interface{}{
+			{"type": summaryType},
+			{"transcription_id": map[string]interface{}{"$ne": transcriptionID}},
+		},
+	}
+	docs, err := s.retrieve(ctx, userID, text, nResults, where)
+	if err != nil {
+		return nil, err
+	}
+	docs = s.relevant(docs)
+
+	related := make([]RelatedTranscription, 0, len(docs))
+	for _, doc := range docs {
+		related = append(related, RelatedTranscription{TranscriptionID: doc.TranscriptionID, Distance: doc.Distance})
+	}
+	return related, nil
+}
+
+// transcriptText returns the plain text of a stored transcript, which is either
+// transcript JSON or plain text
+func transcriptText(raw string) string {
+	var result interfaces.TranscriptResult
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		if strings.HasPrefix(strings.TrimSpace(raw), "{") {
+			return ""
+		}
+		return strings.TrimSpace(raw)
+	}
+	if result.Text != "" {
+		return strings.TrimSpace(result.Text)
+	}
+	parts := make([]string, 0, len(result.Segments))
+	for _, segment := range result.Segments {
+		if text := strings.TrimSpace(segment.Text); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
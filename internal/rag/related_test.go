@@ -0,0 +1,17 @@
+package rag
+
+import "testing"
+
+func TestTranscriptText(t *testing.T) {
+	cases := map[string]string{
+		`{"text":" Full text. ","segments":[{"text":"ignored"}]}`:        "Full text.",
+		`{"segments":[{"text":" Hello "},{"text":""},{"text":"world"}]}`: "Hello world",
+		"plain transcript": "plain transcript",
+		`{"broken json`:    "",
+	}
+	for raw, want := range cases {
+		if got := transcriptText(raw); got != want {
+			t.Errorf("transcriptText(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package rag
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+)
+
+// Content types can be routed to collections of their own next to each owner's main collection,
+// e.g. podcasts to transcriptions_1_podcasts. Content types that aren't routed stay in the main
+// collection. Whatever the collection, each content type is chunked to its own size and
+// introduced to the LLM with its own guidance.
+
+// DefaultRoute names an owner's main collection
+const DefaultRoute = "default"
+
+// ContentProfile is how entries of one content type are chunked and presented to the LLM
+type ContentProfile struct {
+	Label     string // Shown before each excerpt in chat prompts
+	ChunkSize int    // Characters per chunk
+	Guidance  string // How the LLM should read excerpts of this type
+}
+
+// ContentTypes lists every content type, in the order routes list them
+var ContentTypes = []string{models.ContentMeeting, models.ContentVoiceMemo, models.ContentPodcast, models.ContentDocument}
+
+var contentProfiles = map[string]ContentProfile{
+	models.ContentMeeting: {
+		Label:     "Meeting",
+		ChunkSize: TranscriptChunkSize,
+		Guidance:  "Meeting excerpts are conversations between several people; attribute decisions and action items to whoever made them.",
+	},
+	models.ContentVoiceMemo: {
+		Label:     "Voice memo",
+		ChunkSize: 500,
+		Guidance:  "Voice memos are short personal notes by one speaker; treat them as that person's thoughts and reminders, not as agreed facts.",
+	},
+	models.ContentPodcast: {
+		Label:     "Podcast",
+		ChunkSize: 1500,
+		Guidance:  "Podcast excerpts are published conversations; keep the opinions of hosts and guests apart from established facts.",
+	},
+	models.ContentDocument: {
+		Label:     "Document",
+		ChunkSize: DocumentChunkSize,
+		Guidance:  "Document excerpts are written material uploaded by users.",
+	},
+}
+
+// Profile returns the profile of a content type; unknown types are treated as meetings
+func Profile(contentType string) ContentProfile {
+	if profile, ok := contentProfiles[contentType]; ok {
+		return profile
+	}
+	return contentProfiles[models.ContentMeeting]
+}
+
+// routeName is what route names may look like; they must start with a letter so that they
+// can't be mistaken for the user ID in a collection name
+var routeName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,30}$`)
+
+// ParseRoutes parses a comma-separated list of content_type=route pairs, such as
+// "podcast=podcasts,voice_memo=memos". Several content types may share a route.
+func ParseRoutes(spec string) (map[string]string, error) {
+	routes := map[string]string{}
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		contentType, route, ok := strings.Cut(pair, "=")
+		contentType, route = strings.TrimSpace(contentType), strings.TrimSpace(route)
+		if !ok {
+			return nil, fmt.Errorf("invalid route %q: expected content_type=route", pair)
+		}
+		if _, known := contentProfiles[contentType]; !known {
+			return nil, fmt.Errorf("unknown content type %q in route %q", contentType, pair)
+		}
+		if !routeName.MatchString(route) {
+			return nil, fmt.Errorf("invalid route name %q: use lowercase letters, digits and underscores, starting with a letter", route)
+		}
+		routes[contentType] = route
+	}
+	return routes, nil
+}
+
+// SetRoutes routes content types to collections of their own; content types left out stay in
+// the main collection
+func (s *RAGService) SetRoutes(routes map[string]string) {
+	s.routes = routes
+}
+
+// routeOf returns the route entries of a content type are stored under
+func (s *RAGService) routeOf(contentType string) string {
+	if route, ok := s.routes[contentType]; ok {
+		return route
+	}
+	return DefaultRoute
+}
+
+// Route is a collection within each owner's scope and the content types stored in it
+type Route struct {
+	Name         string   `json:"name"`
+	ContentTypes []string `json:"content_types"`
+}
+
+// Routes returns every route, the default one first
+func (s *RAGService) Routes() []Route {
+	byName := map[string]*Route{DefaultRoute: {Name: DefaultRoute, ContentTypes: []string{}}}
+	names := []string{}
+	for _, contentType := range ContentTypes {
+		name := s.routeOf(contentType)
+		if byName[name] == nil {
+			byName[name] = &Route{Name: name, ContentTypes: []string{}}
+			names = append(names, name)
+		}
+		byName[name].ContentTypes = append(byName[name].ContentTypes, contentType)
+	}
+	sort.Strings(names)
+	routes := []Route{*byName[DefaultRoute]}
+	for _, name := range names {
+		routes = append(routes, *byName[name])
+	}
+	return routes
+}
+
+// HasRoute reports whether name is one of the routes
+func (s *RAGService) HasRoute(name string) bool {
+	if name == DefaultRoute {
+		return true
+	}
+	for _, route := range s.routes {
+		if route == name {
+			return true
+		}
+	}
+	return false
+}
+
+// routedCollection returns the collection of a route within an owner's main collection
+func routedCollection(base, route string) string {
+	if route == DefaultRoute {
+		return base
+	}
+	return base + "_" + route
+}
+
+// collectionsOf returns the collections of the given routes within an owner's main collection,
+// or of every route when routes is empty, creating them as needed
+func (s *RAGService) collectionsOf(base string, routes []string) []string {
+	if len(routes) == 0 {
+		for _, route := range s.Routes() {
+			routes = append(routes, route.Name)
+		}
+	}
+	collections := make([]string, 0, len(routes))
+	seen := map[string]bool{}
+	for _, route := range routes {
+		collection := routedCollection(base, route)
+		if seen[collection] {
+			continue
+		}
+		seen[collection] = true
+		s.ensureCollection(collection)
+		collections = append(collections, collection)
+	}
+	return collections
+}
+
+// collectionForType resolves and ensures the collection of an owner's entries of a content type
+func (s *RAGService) collectionForType(userID *uint, contentType string) (string, error) {
+	base, err := s.collectionFor(userID)
+	if err != nil {
+		return "", err
+	}
+	collection := routedCollection(base, s.routeOf(contentType))
+	s.ensureCollection(collection)
+	return collection, nil
+}
+
+// jobCollection returns the owner of a transcription and the collection its entries belong in
+func (s *RAGService) jobCollection(transcriptionID string) (*uint, string, error) {
+	var job models.TranscriptionJob
+	if err := database.DB.Select("id", "user_id", "content_type").Where("id = ?", transcriptionID).First(&job).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to look up transcription %s: %w", transcriptionID, err)
+	}
+	collection, err := s.collectionForType(job.UserID, job.ContentType)
+	if err != nil {
+		return nil, "", err
+	}
+	return job.UserID, collection, nil
+}
+
+// otherCollections returns the owner's collections other than collection, where entries of a
+// transcription may be left from before its content type or the routes changed
+func (s *RAGService) otherCollections(owner *uint, collection string) ([]string, error) {
+	base, err := s.collectionFor(owner)
+	if err != nil {
+		return nil, err
+	}
+	var others []string
+	for _, candidate := range s.collectionsOf(base, nil) {
+		if candidate != collection {
+			others = append(others, candidate)
+		}
+	}
+	return others, nil
+}
+
+// labeledContexts returns the content of each hit prefixed with the label of its content type
+func labeledContexts(docs []RetrievedDocument) []string {
+	contexts := make([]string, len(docs))
+	for i, doc := range docs {
+		contexts[i] = fmt.Sprintf("[%s] %s", Profile(doc.ContentType).Label, doc.Content)
+	}
+	return contexts
+}
+
+// contentGuidance explains to the LLM how to read each content type among the hits
+func contentGuidance(docs []RetrievedDocument) string {
+	present := map[string]bool{}
+	for _, doc := range docs {
+		present[Profile(doc.ContentType).Label] = true
+	}
+	var guidance strings.Builder
+	guidance.WriteString("The excerpts are labeled with the kind of recording or document they come from:\n")
+	for _, contentType := range ContentTypes {
+		profile := Profile(contentType)
+		if present[profile.Label] {
+			guidance.WriteString(fmt.Sprintf("- %s: %s\n", profile.Label, profile.Guidance))
+		}
+	}
+	guidance.WriteString("\n")
+	return guidance.String()
+}
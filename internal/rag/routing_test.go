@@ -0,0 +1,39 @@
+package rag
+
+import "testing"
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes(" podcast=podcasts ,voice_memo=podcasts,, document=default")
+	if err != nil {
+		t.Fatalf("ParseRoutes: %v", err)
+	}
+	want := map[string]string{"podcast": "podcasts", "voice_memo": "podcasts", "document": "default"}
+	if len(routes) != len(want) {
+		t.Fatalf("ParseRoutes = %v, want %v", routes, want)
+	}
+	for contentType, route := range want {
+		if routes[contentType] != route {
+			t.Errorf("route of %s = %q, want %q", contentType, routes[contentType], route)
+		}
+	}
+
+	for _, spec := range []string{"podcast", "lecture=lectures", "podcast=Podcasts", "podcast=1", "podcast="} {
+		if _, err := ParseRoutes(spec); err == nil {
+			t.Errorf("ParseRoutes(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	s := &RAGService{routes: map[string]string{"podcast": "podcasts", "voice_memo": "memos"}}
+	routes := s.Routes()
+	if len(routes) != 3 || routes[0].Name != DefaultRoute || routes[1].Name != "memos" || routes[2].Name != "podcasts" {
+		t.Fatalf("Routes = %+v", routes)
+	}
+	if len(routes[0].ContentTypes) != 2 {
+		t.Errorf("default route holds %v, want meetings and documents", routes[0].ContentTypes)
+	}
+	if !s.HasRoute("memos") || !s.HasRoute(DefaultRoute) || s.HasRoute("lectures") {
+		t.Error("HasRoute disagrees with the routes")
+	}
+}
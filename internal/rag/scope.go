@@ -0,0 +1,127 @@
+package rag
+
+import (
+	"fmt"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+)
+
+// sharedCollection holds transcriptions without an owner on instances with more than one user
+const sharedCollection = "transcriptions"
+
+// CollectionName returns the vector store collection for a user's transcriptions.
+// Each user gets an isolated collection so retrieval can never cross accounts.
+func CollectionName(userID *uint) string {
+	if userID == nil {
+		return sharedCollection
+	}
+	return fmt.Sprintf("%s_%d", sharedCollection, *userID)
+}
+
+// scopeResolver maps record owners to collections. Unowned records (dropzone jobs, API keys
+// created before keys had owners) belong to the only user on single-user instances, and to
+// the shared collection otherwise.
+type scopeResolver struct {
+	soleUser *uint
+}
+
+// newScopeResolver looks up whether the instance has exactly one user
+func newScopeResolver() (*scopeResolver, error) {
+	var ids []uint
+	if err := database.DB.Model(&models.User{}).Order("id").Limit(2).Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	r := &scopeResolver{}
+	if len(ids) == 1 {
+		r.soleUser = &ids[0]
+	}
+	return r, nil
+}
+
+// owner returns the effective owner of a record
+func (r *scopeResolver) owner(userID *uint) *uint {
+	if userID == nil {
+		return r.soleUser
+	}
+	return userID
+}
+
+// collection returns the collection a record owned by userID lives in
+func (r *scopeResolver) collection(userID *uint) string {
+	return CollectionName(r.owner(userID))
+}
+
+// ResolveCollection returns the collection holding the transcriptions visible to userID
+func ResolveCollection(userID *uint) (string, error) {
+	resolver, err := newScopeResolver()
+	if err != nil {
+		return "", err
+	}
+	return resolver.collection(userID), nil
+}
+
+// ensureCollection creates a collection the first time it is used
+func (s *RAGService) ensureCollection(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.collections[name] {
+		return
+	}
+	if err := s.vectorDB.CreateCollection(name, map[string]interface{}{
+		"description": "Transcription summaries and content",
+	}); err == nil {
+		s.collections[name] = true
+	}
+}
+
+// collectionFor resolves and ensures the collection for a user's transcriptions
+func (s *RAGService) collectionFor(userID *uint) (string, error) {
+	resolver, err := newScopeResolver()
+	if err != nil {
+		return "", err
+	}
+	name := resolver.collection(userID)
+	s.ensureCollection(name)
+	return name, nil
+}
+
+// transcriptionOwner returns the owner of a transcription job
+func transcriptionOwner(transcriptionID string) (*uint, error) {
+	var job models.TranscriptionJob
+	if err := database.DB.Select("id", "user_id").Where("id = ?", transcriptionID).First(&job).Error; err != nil {
+		return nil, fmt.Errorf("failed to look up transcription %s: %w", transcriptionID, err)
+	}
+	return job.UserID, nil
+}
+
+// scopeFilter builds the vector store filter limiting a query to the given transcriptions
+// and the documents linked to them, or nil for no limit
+func scopeFilter(transcriptionIDs []string) map[string]interface{} {
+	if transcriptionIDs == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"$or": []map[string]interface{}{
+			{"transcription_id": map[string]interface{}{"$in": transcriptionIDs}},
+			{"recording_id": map[string]interface{}{"$in": transcriptionIDs}},
+		},
+	}
+}
+
+// andFilter combines vector store filters, skipping nil ones
+func andFilter(filters ...map[string]interface{}) map[string]interface{} {
+	var clauses []map[string]interface{}
+	for _, filter := range filters {
+		if filter != nil {
+			clauses = append(clauses, filter)
+		}
+	}
+	switch len(clauses) {
+	case 0:
+		return nil
+	case 1:
+		return clauses[0]
+	}
+	return map[string]interface{}{"$and": clauses}
+}
@@ -0,0 +1,264 @@
+package rag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+)
+
+// TranscriptChunkSize is the target size of a transcript chunk, in characters
+const TranscriptChunkSize = 1000
+
+// transcriptChunkType tags vector store entries holding a time range of a transcript,
+// as opposed to the whole-transcription "summary" entry
+const transcriptChunkType = "transcript_chunk"
+
+// TranscriptChunk is a run of consecutive transcript segments indexed as one entry
+type TranscriptChunk struct {
+	Start   float64
+	End     float64
+	Speaker string // Speaker of most of the chunk's text, if diarized
+	Text    string
+
+	// Confidence is the mean ASR score of the chunk's words, nil if the transcript has no word scores
+	Confidence *float64
+	// LowConfidenceShare is the share of scored words below LowConfidenceThreshold
+	LowConfidenceShare float64
+
+	// Metadata is extra metadata stored with the chunk, as returned by a custom chunker
+	Metadata map[string]interface{}
+}
+
+// transcriptChunkID returns the vector store ID of one chunk of a transcript
+func transcriptChunkID(transcriptionID string, index int) string {
+	return fmt.Sprintf("transcript_%s_%d", transcriptionID, index)
+}
+
+// ChunkSegments groups consecutive segments into chunks of about size characters.
+// Segments are never split, so a single long segment becomes a chunk of its own. Since
+// transcription engines often cut segments mid-sentence, a chunk that fills up ends after
+// its last segment that finishes a sentence, if that leaves it at least half full; the
+// segments after it start the next chunk.
+func ChunkSegments(segments []interfaces.TranscriptSegment, size int) []TranscriptChunk {
+	if size <= 0 {
+		size = TranscriptChunkSize
+	}
+
+	var chunks []TranscriptChunk
+	var pending []interfaces.TranscriptSegment
+	length := 0 // Length of the pending segments' text, joined with spaces
+
+	// flush makes a chunk of the first n pending segments
+	flush := func(n int) {
+		chunk := TranscriptChunk{Start: pending[0].Start, End: pending[n-1].End}
+		texts := make([]string, n)
+		speakerChars := map[string]int{}
+		for i, segment := range pending[:n] {
+			texts[i] = segment.Text
+			if segment.Speaker != nil && *segment.Speaker != "" {
+				speakerChars[*segment.Speaker] += len(segment.Text)
+			}
+		}
+		chunk.Text = strings.Join(texts, " ")
+		best := 0
+		for speaker, chars := range speakerChars {
+			if chars > best || (chars == best && speaker < chunk.Speaker) {
+				chunk.Speaker, best = speaker, chars
+			}
+		}
+		chunks = append(chunks, chunk)
+		pending = pending[n:]
+		length -= len(chunk.Text)
+		if len(pending) > 0 {
+			length--
+		}
+	}
+
+	for _, segment := range segments {
+		segment.Text = strings.TrimSpace(segment.Text)
+		if segment.Text == "" {
+			continue
+		}
+		for len(pending) > 0 && length+1+len(segment.Text) > size {
+			flush(sentenceCut(pending, size))
+		}
+		if len(pending) > 0 {
+			length++
+		}
+		pending = append(pending, segment)
+		length += len(segment.Text)
+	}
+	if len(pending) > 0 {
+		flush(len(pending))
+	}
+	return chunks
+}
+
+// sentenceCut returns how many of the pending segments to put in a chunk that is full: up to
+// the last one that ends a sentence, if those hold at least half of size characters, or else all
+func sentenceCut(pending []interfaces.TranscriptSegment, size int) int {
+	cut := len(pending)
+	length := -1
+	for i, segment := range pending {
+		length += 1 + len(segment.Text)
+		if length >= size/2 && i < len(pending)-1 && endsSentence(segment.Text) {
+			cut = i + 1
+		}
+	}
+	return cut
+}
+
+// storeTranscriptChunks indexes the segments of a transcription as timestamped chunks,
+// replacing chunks from an earlier indexing. Only chunks whose text changed are re-embedded.
+// Transcripts without segments are skipped. indexText gives the text stored for each chunk.
+func (s *RAGService) storeTranscriptChunks(ctx context.Context, collection, transcriptionID string, owner *uint, indexedAt int64, cache *embeddingCache, meta *jobMetadata, indexText func(string) string) error {
+	var job models.TranscriptionJob
+	if err := database.DB.Select("id", "transcript", "initial_prompt").Where("id = ?", transcriptionID).First(&job).Error; err != nil {
+		return fmt.Errorf("failed to load transcript %s: %w", transcriptionID, err)
+	}
+
+	var chunks []TranscriptChunk
+	if job.Transcript != nil {
+		var result interfaces.TranscriptResult
+		if err := json.Unmarshal([]byte(*job.Transcript), &result); err == nil {
+			initialPrompt := ""
+			if job.Parameters.InitialPrompt != nil {
+				initialPrompt = *job.Parameters.InitialPrompt
+			}
+			if chunks, err = s.chunkTranscript(transcriptionID, result, initialPrompt, meta, indexText); err != nil {
+				return err
+			}
+			applyConfidence(chunks, result.WordSegments)
+		}
+	}
+
+	ids := make([]string, len(chunks))
+	contents := make([]string, len(chunks))
+	embeddings := make([][]float32, len(chunks))
+	metadatas := make([]map[string]interface{}, len(chunks))
+	for i, chunk := range chunks {
+		chunk.Text = indexText(chunk.Text)
+		embedding, hash, err := cache.embed(chunk.Text)
+		if err != nil {
+			return fmt.Errorf("failed to generate embedding for chunk %d: %w", i, err)
+		}
+		ids[i] = transcriptChunkID(transcriptionID, i)
+		contents[i] = chunk.Text
+		embeddings[i] = embedding
+		metadata := make(map[string]interface{}, len(chunk.Metadata)+8)
+		for key, value := range chunk.Metadata {
+			metadata[key] = value
+		}
+		metadata["transcription_id"] = transcriptionID
+		metadata["type"] = transcriptChunkType
+		metadata["chunk_index"] = i
+		metadata["start"] = chunk.Start
+		metadata["end"] = chunk.End
+		metadata["indexed_at"] = indexedAt
+		metadata["content_hash"] = hash
+		metadata["embedding_model"] = s.embedding.Model()
+		if chunk.Speaker != "" {
+			metadata["speaker"] = chunk.Speaker
+		}
+		meta.apply(metadata)
+		if chunk.Confidence != nil {
+			metadata["confidence"] = *chunk.Confidence
+			metadata["low_confidence_share"] = chunk.LowConfidenceShare
+		}
+		if owner != nil {
+			metadata["user_id"] = *owner
+		}
+		metadatas[i] = metadata
+	}
+
+	// Drop chunks from a previous indexing first, since the transcript may have fewer chunks now
+	previous := map[string]interface{}{
+		"$and": []map[string]interface{}{
+			{"transcription_id": transcriptionID},
+			{"type": transcriptChunkType},
+		},
+	}
+	if err := s.store(ctx).DeleteDocuments(collection, nil, previous); err != nil {
+		return fmt.Errorf("failed to remove previous chunks: %w", err)
+	}
+	if len(chunks) == 0 {
+		return nil
+	}
+	if err := s.store(ctx).UpsertDocuments(collection, ids, contents, embeddings, metadatas); err != nil {
+		return fmt.Errorf("failed to store chunks in vector DB: %w", err)
+	}
+	return nil
+}
+
+// SearchHit is a transcript chunk, or a chunk of a translated transcript, matching a search query
+type SearchHit struct {
+	TranscriptionID string   `json:"transcription_id"`
+	Start           float64  `json:"start"`
+	End             float64  `json:"end"`
+	Speaker         string   `json:"speaker,omitempty"`
+	Language        string   `json:"language,omitempty"`   // Set when the passage is from a translation
+	Confidence      *float64 `json:"confidence,omitempty"` // Mean ASR confidence of the passage, if known
+	Snippet         string   `json:"snippet"`
+	Distance        float32  `json:"distance"`
+}
+
+// snippetLength caps the text returned with each search hit, in characters
+const snippetLength = 300
+
+// Search returns the transcript chunks most similar to query, best match first, without
+// involving the LLM. A non-nil transcriptionIDs limits the search to those transcriptions.
+func (s *RAGService) Search(ctx context.Context, userID *uint, query string, nResults int, transcriptionIDs []string) ([]SearchHit, error) {
+	if transcriptionIDs != nil && len(transcriptionIDs) == 0 {
+		return []SearchHit{}, nil
+	}
+	types := map[string]interface{}{"type": map[string]interface{}{"$in": []string{transcriptChunkType, translationChunkType}}}
+	where := andFilter(types, scopeFilter(transcriptionIDs))
+	docs, err := s.retrieve(ctx, userID, query, nResults, where)
+	if err != nil {
+		return nil, err
+	}
+	docs = s.relevant(docs)
+
+	hits := make([]SearchHit, 0, len(docs))
+	for _, doc := range docs {
+		hit := SearchHit{
+			TranscriptionID: doc.TranscriptionID,
+			Speaker:         doc.Speaker,
+			Language:        doc.Language,
+			Confidence:      doc.Confidence,
+			Snippet:         snippet(doc.Content, snippetLength),
+			Distance:        doc.Distance,
+		}
+		if doc.Start != nil {
+			hit.Start = *doc.Start
+		}
+		if doc.End != nil {
+			hit.End = *doc.End
+		}
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// snippet shortens text to at most n characters, cutting at a word boundary
+func snippet(text string, n int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) <= n {
+		return text
+	}
+	cut := text[:n]
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	} else {
+		for len(cut) > 0 && !utf8.RuneStart(text[len(cut)]) {
+			cut = cut[:len(cut)-1]
+		}
+	}
+	return cut + "…"
+}
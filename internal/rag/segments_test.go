@@ -0,0 +1,103 @@
+package rag
+
+import (
+	"strings"
+	"testing"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+func segment(start, end float64, speaker, text string) interfaces.TranscriptSegment {
+	seg := interfaces.TranscriptSegment{Start: start, End: end, Text: text}
+	if speaker != "" {
+		seg.Speaker = &speaker
+	}
+	return seg
+}
+
+func TestChunkSegmentsGroupsConsecutiveSegments(t *testing.T) {
+	segments := []interfaces.TranscriptSegment{
+		segment(0, 4, "SPEAKER_00", "Welcome everyone to the planning meeting."),
+		segment(4, 6, "SPEAKER_01", "Thanks."),
+		segment(6, 7, "", "   "),
+		segment(7, 12, "SPEAKER_00", "First item on the agenda is the budget."),
+		segment(12, 20, "SPEAKER_01", "I think we should cut the travel budget by half this quarter."),
+	}
+
+	chunks := ChunkSegments(segments, 100)
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d: %+v", len(chunks), chunks)
+	}
+
+	first := chunks[0]
+	if first.Start != 0 || first.End != 12 {
+		t.Errorf("expected first chunk to span 0-12, got %v-%v", first.Start, first.End)
+	}
+	if first.Speaker != "SPEAKER_00" {
+		t.Errorf("expected the speaker with most text, got %q", first.Speaker)
+	}
+	if first.Text != "Welcome everyone to the planning meeting. Thanks. First item on the agenda is the budget." {
+		t.Errorf("unexpected chunk text %q", first.Text)
+	}
+
+	if chunks[1].Start != 12 || chunks[1].End != 20 || chunks[1].Speaker != "SPEAKER_01" {
+		t.Errorf("unexpected second chunk %+v", chunks[1])
+	}
+}
+
+func TestChunkSegmentsWithoutSpeakers(t *testing.T) {
+	chunks := ChunkSegments([]interfaces.TranscriptSegment{segment(1.5, 3, "", "Just a note to self.")}, 0)
+	if len(chunks) != 1 || chunks[0].Speaker != "" || chunks[0].Start != 1.5 {
+		t.Errorf("unexpected chunks %+v", chunks)
+	}
+	if got := ChunkSegments(nil, 100); len(got) != 0 {
+		t.Errorf("expected no chunks for no segments, got %d", len(got))
+	}
+}
+
+func TestChunkSegmentsEndsChunksAtSentences(t *testing.T) {
+	segments := []interfaces.TranscriptSegment{
+		segment(0, 5, "", "We reviewed the quarterly numbers in detail with finance."),
+		segment(5, 9, "", "Then we moved on to the hiring plan, which"),
+		segment(9, 12, "", "needs two more engineers."),
+	}
+	chunks := ChunkSegments(segments, 100)
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d: %+v", len(chunks), chunks)
+	}
+	if chunks[0].Text != "We reviewed the quarterly numbers in detail with finance." || chunks[0].End != 5 {
+		t.Errorf("expected the first chunk to end with the first sentence, got %+v", chunks[0])
+	}
+	if chunks[1].Start != 5 || chunks[1].Text != "Then we moved on to the hiring plan, which needs two more engineers." {
+		t.Errorf("expected the sentence split across segments to stay together, got %+v", chunks[1])
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	if got := snippet("short   text\nhere", 300); got != "short text here" {
+		t.Errorf("expected whitespace to be collapsed, got %q", got)
+	}
+
+	got := snippet(strings.Repeat("budget ", 100), 50)
+	if len(got) > 50+len("…") || !strings.HasSuffix(got, "budget…") {
+		t.Errorf("expected a cut at a word boundary, got %q", got)
+	}
+
+	if got := snippet(strings.Repeat("é", 40), 25); !strings.HasSuffix(got, "é…") {
+		t.Errorf("expected a cut at a rune boundary, got %q", got)
+	}
+}
+
+func TestAndFilter(t *testing.T) {
+	if andFilter(nil, nil) != nil {
+		t.Error("expected nil for no filters")
+	}
+	only := map[string]interface{}{"type": transcriptChunkType}
+	if got := andFilter(only, nil); got["type"] != transcriptChunkType {
+		t.Errorf("expected a single filter to be returned as is, got %v", got)
+	}
+	got := andFilter(only, scopeFilter([]string{"a"}))
+	if clauses, ok := got["$and"].([]map[string]interface{}); !ok || len(clauses) != 2 {
+		t.Errorf("expected two clauses under $and, got %v", got)
+	}
+}
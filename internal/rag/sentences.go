@@ -0,0 +1,183 @@
+package rag
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// fullStops end a sentence whether or not a space follows: the full-width CJK marks and the
+// sentence marks of scripts that don't use Latin punctuation
+var fullStops = map[rune]bool{
+	'。': true, '！': true, '？': true, '｡': true, // Chinese, Japanese, Korean
+	'؟': true, '۔': true, // Arabic, Urdu
+	'।': true, '॥': true, // Devanagari and other Indic scripts
+	'։': true,            // Armenian
+	'።': true, '፧': true, // Ethiopic
+	'။': true, // Myanmar
+	'។': true, // Khmer
+	'‼': true, '⁇': true, '⁈': true, '⁉': true,
+}
+
+// latinStops end a sentence when followed by a space and a word that doesn't start in lowercase
+var latinStops = map[rune]bool{'.': true, '!': true, '?': true, '…': true}
+
+// closers are kept with the sentence they follow
+const closers = "\"')]}”’»›」』）】〉》〕］｝"
+
+// abbreviations are words that are usually followed by a full stop without ending a sentence,
+// lowercased and without their final full stop
+var abbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "sr": true, "jr": true,
+	"st": true, "mt": true, "ft": true, "vs": true, "gen": true, "col": true, "lt": true,
+	"sgt": true, "capt": true, "rev": true, "hon": true, "fig": true, "no": true, "nr": true,
+	"vol": true, "approx": true, "dept": true, "est": true, "cf": true, "al": true,
+	"e.g": true, "i.e": true, "z.b": true, "bzw": true, "ca": true,
+}
+
+// SplitSentences splits text into sentences. Each sentence keeps the whitespace that follows
+// it, so the sentences concatenate back to text.
+//
+// A full stop, exclamation or question mark ends a sentence when whitespace and a word that
+// doesn't start in lowercase follow it, unless it ends a common abbreviation or an initial;
+// decimals and dotted acronyms therefore stay whole. CJK full-width marks and the sentence
+// marks of other scripts (Arabic, Devanagari, Armenian, Ethiopic, Myanmar, Khmer) end a
+// sentence without a following space. Thai and Lao mark sentences with a space rather than
+// punctuation, so a space between two words in those scripts ends one. Closing quotes and
+// brackets stay with the sentence they close, and a quotation the sentence goes on past
+// doesn't end it.
+func SplitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		end := -1
+		switch {
+		case fullStops[r] || latinStops[r]:
+			j := i + size
+			for j < len(text) {
+				next, n := utf8.DecodeRuneInString(text[j:])
+				if !fullStops[next] && !latinStops[next] && !strings.ContainsRune(closers, next) {
+					break
+				}
+				j += n
+			}
+			if (fullStops[r] && !quotedWithin(text, j)) || (!fullStops[r] && endsLatinSentence(text, start, i, j)) {
+				end = j
+			} else {
+				i = j
+				continue
+			}
+		case unicode.IsSpace(r) && i > 0 && spaceEndsSentence(text, i, size):
+			end = i
+		}
+		if end < 0 {
+			i += size
+			continue
+		}
+		for end < len(text) {
+			next, n := utf8.DecodeRuneInString(text[end:])
+			if !unicode.IsSpace(next) {
+				break
+			}
+			end += n
+		}
+		if strings.TrimSpace(text[start:end]) != "" {
+			sentences = append(sentences, text[start:end])
+			start = end
+		}
+		i = end
+	}
+	if start < len(text) {
+		if strings.TrimSpace(text[start:]) == "" && len(sentences) > 0 {
+			sentences[len(sentences)-1] += text[start:]
+		} else {
+			sentences = append(sentences, text[start:])
+		}
+	}
+	return sentences
+}
+
+// endsLatinSentence reports whether the marks at text[mark:after], in the sentence starting at
+// start, end it
+func endsLatinSentence(text string, start, mark, after int) bool {
+	if after < len(text) {
+		next, _ := utf8.DecodeRuneInString(text[after:])
+		if !unicode.IsSpace(next) {
+			return false
+		}
+		word := strings.TrimLeftFunc(text[after:], unicode.IsSpace)
+		if first, _ := utf8.DecodeRuneInString(word); unicode.IsLower(first) {
+			return false
+		}
+	}
+	if text[mark] != '.' {
+		return true
+	}
+	return !isAbbreviation(lastWord(text[start:mark]))
+}
+
+// quotedWithin reports whether the full stop ending at after closes a quotation that the
+// sentence goes on past, as in 「はい。」と言った。
+func quotedWithin(text string, after int) bool {
+	if after >= len(text) {
+		return false
+	}
+	closing, _ := utf8.DecodeLastRuneInString(text[:after])
+	next, _ := utf8.DecodeRuneInString(text[after:])
+	return strings.ContainsRune(closers, closing) && !unicode.IsSpace(next)
+}
+
+// spaceEndsSentence reports whether the space at text[i:i+size] separates two sentences of a
+// language that doesn't punctuate them
+func spaceEndsSentence(text string, i, size int) bool {
+	before, _ := utf8.DecodeLastRuneInString(text[:i])
+	after, _ := utf8.DecodeRuneInString(text[i+size:])
+	return spaceSeparated(before) && spaceSeparated(after)
+}
+
+// spaceSeparated reports whether r is from a script that separates sentences with spaces
+func spaceSeparated(r rune) bool {
+	return unicode.In(r, unicode.Thai, unicode.Lao)
+}
+
+// lastWord returns the last whitespace-separated word of text, without opening punctuation
+func lastWord(text string) string {
+	if i := strings.LastIndexFunc(text, unicode.IsSpace); i >= 0 {
+		text = text[i+1:]
+	}
+	return strings.TrimLeft(text, "\"'([{“‘«‹")
+}
+
+// isAbbreviation reports whether a full stop after word is more likely part of the word than
+// the end of a sentence
+func isAbbreviation(word string) bool {
+	if word == "" {
+		return false
+	}
+	if abbreviations[strings.ToLower(word)] {
+		return true
+	}
+	// An initial such as the J in "J. Smith"
+	if r, size := utf8.DecodeRuneInString(word); size == len(word) && unicode.IsUpper(r) {
+		return true
+	}
+	// A dotted acronym such as U.S
+	return strings.Contains(word, ".") && !strings.ContainsFunc(word, unicode.IsLower)
+}
+
+// endsSentence reports whether text ends at the end of a sentence
+func endsSentence(text string) bool {
+	text = strings.TrimRight(strings.TrimRightFunc(text, unicode.IsSpace), closers)
+	last, size := utf8.DecodeLastRuneInString(text)
+	if fullStops[last] {
+		return true
+	}
+	if !latinStops[last] {
+		return false
+	}
+	if last != '.' {
+		return true
+	}
+	return !isAbbreviation(lastWord(strings.TrimRight(text[:len(text)-size], ".")))
+}
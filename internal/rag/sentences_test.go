@@ -0,0 +1,51 @@
+package rag
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitSentences(t *testing.T) {
+	cases := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"latin", "We met at 9.30 today. Dr. Smith joined! Did J. R. Jones? No.", []string{"We met at 9.30 today. ", "Dr. Smith joined! ", "Did J. R. Jones? ", "No."}},
+		{"lowercase continues", "Bring fruit, e.g. apples, pears etc. and cheese. Thanks.", []string{"Bring fruit, e.g. apples, pears etc. and cheese. ", "Thanks."}},
+		{"acronyms and quotes", `The U.S. team said "We won." Then they left...`, []string{`The U.S. team said "We won." `, "Then they left..."}},
+		{"chinese", "今天开会了。预算通过了！下次什么时候？", []string{"今天开会了。", "预算通过了！", "下次什么时候？"}},
+		{"japanese closers", "「はい。」と言った。次へ。", []string{"「はい。」と言った。", "次へ。"}},
+		{"hindi", "बैठक शुरू हुई। बजट पास हुआ।", []string{"बैठक शुरू हुई। ", "बजट पास हुआ।"}},
+		{"thai", "วันนี้ประชุม งบประมาณผ่านแล้ว", []string{"วันนี้ประชุม ", "งบประมาณผ่านแล้ว"}},
+		{"no punctuation", "just some words", []string{"just some words"}},
+	}
+	for _, tc := range cases {
+		got := SplitSentences(tc.text)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+		if strings.Join(got, "") != tc.text {
+			t.Errorf("%s: sentences don't concatenate back to the text", tc.name)
+		}
+	}
+	if got := SplitSentences(""); len(got) != 0 {
+		t.Errorf("expected no sentences for empty text, got %q", got)
+	}
+}
+
+func TestEndsSentence(t *testing.T) {
+	for text, want := range map[string]bool{
+		"The budget was approved.": true,
+		"Is that right?\"":         true,
+		"予算が通りました。":                true,
+		"and then we asked Dr.":    false,
+		"so the plan is to":        false,
+		"":                         false,
+	} {
+		if got := endsSentence(text); got != want {
+			t.Errorf("endsSentence(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
@@ -3,13 +3,19 @@ package rag
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"scriberr/internal/database"
 	"scriberr/internal/embeddings"
+	"scriberr/internal/events"
 	"scriberr/internal/llm"
 	"scriberr/internal/models"
+	"scriberr/internal/tracing"
 	"scriberr/internal/vectordb"
+	"scriberr/pkg/logger"
 )
 
 // LLMService interface for RAG service
@@ -17,33 +23,82 @@ type LLMService interface {
 	ChatCompletion(ctx context.Context, model string, messages []llm.ChatMessage, temperature float64) (*llm.ChatResponse, error)
 }
 
-// RAGService handles RAG operations
+// RAGService handles RAG operations. Transcriptions are stored in per-user
+// collections (see CollectionName) and every read is scoped to one user.
 type RAGService struct {
-	vectorDB   *vectordb.ChromaDBClient
-	embedding  *embeddings.OllamaEmbeddingService
+	vectorDB   vectordb.Store
+	embedding  embeddings.Service
 	llmService LLMService
-	collectionName string
+
+	// maxDistance drops retrieved documents farther than this from the query; 0 keeps everything
+	maxDistance float32
+	// confidenceWeight scales the distance penalty for low-confidence transcript chunks; 0 disables it
+	confidenceWeight float64
+	// standingContextTokens caps the standing context prepended to chat prompts; 0 leaves it out
+	standingContextTokens int
+	// embedRedacted stores transcriptions with their personal data redacted
+	embedRedacted bool
+
+	// routes maps content types to the collections they are stored in; see SetRoutes
+	routes map[string]string
+
+	// chunker, if set, chunks transcripts for indexing in place of ChunkSegments; see SetChunker
+	chunker         Chunker
+	chunkerFallback bool
+
+	mu          sync.Mutex
+	collections map[string]bool // collections known to exist
 }
 
 // NewRAGService creates a new RAG service
-func NewRAGService(vectorDB *vectordb.ChromaDBClient, embedding *embeddings.OllamaEmbeddingService, llmService LLMService) *RAGService {
-	service := &RAGService{
-		vectorDB:      vectorDB,
-		embedding:     embedding,
-		llmService:    llmService,
-		collectionName: "transcriptions",
+func NewRAGService(vectorDB vectordb.Store, embedding embeddings.Service, llmService LLMService) *RAGService {
+	return &RAGService{
+		vectorDB:    vectorDB,
+		embedding:   embedding,
+		llmService:  llmService,
+		collections: make(map[string]bool),
+
+		standingContextTokens: DefaultStandingContextTokens,
 	}
-	
-	// Ensure collection exists
-	_ = service.vectorDB.CreateCollection(service.collectionName, map[string]interface{}{
-		"description": "Transcription summaries and content",
-	})
-	
-	return service
 }
 
-// StoreSummary stores a summary in the vector database
-func (s *RAGService) StoreSummary(transcriptionID, summary, transcript string) error {
+// SetMaxDistance sets the distance above which retrieved documents are treated as irrelevant to chat
+func (s *RAGService) SetMaxDistance(distance float32) {
+	s.maxDistance = distance
+}
+
+// SetConfidenceWeight sets how strongly low ASR confidence pushes transcript chunks down the ranking
+func (s *RAGService) SetConfidenceWeight(weight float64) {
+	if weight < 0 {
+		weight = 0
+	}
+	s.confidenceWeight = weight
+}
+
+// StoreSummary stores a summary in the vector database, in the collection of the transcription's
+// owner that its content type is routed to
+func (s *RAGService) StoreSummary(ctx context.Context, transcriptionID, summary, transcript string) error {
+	// A transcription merged into another as its duplicate stays out of the index
+	var duplicates int64
+	if err := database.DB.Model(&models.TranscriptionJob{}).Where("id = ? AND duplicate_of IS NOT NULL", transcriptionID).Count(&duplicates).Error; err != nil {
+		return fmt.Errorf("failed to look up transcription %s: %w", transcriptionID, err)
+	}
+	if duplicates > 0 {
+		logger.InfoContext(ctx, "Not indexing duplicate transcription", "component", "rag", "job_id", transcriptionID)
+		return s.DeleteTranscription(ctx, transcriptionID)
+	}
+
+	owner, collection, err := s.jobCollection(transcriptionID)
+	if err != nil {
+		return err
+	}
+
+	indexText, err := s.textForIndex(transcriptionID)
+	if err != nil {
+		return err
+	}
+	summary, transcript = indexText(summary), indexText(transcript)
+
 	// Combine summary and transcript for better context
 	// If summary is empty, just use transcript
 	var content string
@@ -53,20 +108,38 @@ func (s *RAGService) StoreSummary(transcriptionID, summary, transcript string) e
 		content = fmt.Sprintf("Transcript: %s", transcript)
 	}
 	
-	// Generate embedding
-	embedding, err := s.embedding.GenerateEmbedding(content)
+	meta, err := loadJobMetadata(transcriptionID)
+	if err != nil {
+		return err
+	}
+	cache, err := s.newEmbeddingCache(ctx, collection, transcriptionID)
+	if err != nil {
+		return err
+	}
+
+	// Generate embedding, unless the content is unchanged since the last indexing
+	embedding, hash, err := cache.embed(content)
 	if err != nil {
 		return fmt.Errorf("failed to generate embedding: %w", err)
 	}
 	
 	// Store in vector DB
+	indexedAt := time.Now().Unix()
 	metadata := map[string]interface{}{
 		"transcription_id": transcriptionID,
-		"type":            "summary",
+		"type":            summaryType,
+		"indexed_at":      indexedAt,
+		"content_hash":    hash,
+		"embedding_model": s.embedding.Model(),
+	}
+	if owner != nil {
+		metadata["user_id"] = *owner
 	}
+	meta.apply(metadata)
 	
-	err = s.vectorDB.AddDocuments(
-		s.collectionName,
+	// Upsert so re-indexing a transcription replaces its previous document
+	err = s.store(ctx).UpsertDocuments(
+		collection,
 		[]string{transcriptionID},
 		[]string{content},
 		[][]float32{embedding},
@@ -77,87 +150,508 @@ func (s *RAGService) StoreSummary(transcriptionID, summary, transcript string) e
 		return fmt.Errorf("failed to store in vector DB: %w", err)
 	}
 	
+	// Timestamped chunks of the transcript make individual passages findable
+	if err := s.storeTranscriptChunks(ctx, collection, transcriptionID, owner, indexedAt, cache, meta, indexText); err != nil {
+		return err
+	}
+	if len(s.routes) > 0 {
+		// Entries from before the content type changed would otherwise show up twice
+		others, err := s.otherCollections(owner, collection)
+		if err != nil {
+			return err
+		}
+		for _, other := range others {
+			if err := s.store(ctx).DeleteDocuments(other, nil, map[string]interface{}{"transcription_id": transcriptionID}); err != nil {
+				return fmt.Errorf("failed to remove entries from %s: %w", other, err)
+			}
+		}
+	}
+	logger.InfoContext(ctx, "Indexed transcription", "component", "rag", "job_id", transcriptionID, "embedded", cache.embedded, "reused", cache.reused)
+	events.Record(models.EventIndexUpdated, transcriptionID, owner, map[string]interface{}{"kind": "transcription", "embedded": cache.embedded, "reused": cache.reused})
 	return nil
 }
 
-// Query performs a RAG query
-func (s *RAGService) Query(ctx context.Context, query string, nResults int) ([]string, error) {
+// RetrievedDocument is a single retrieval hit, ranked by similarity. Hits from uploaded
+// documents have DocumentID set and, if the document is linked to a recording, RecordingID.
+// Hits from transcript chunks carry the time range and speaker of the chunk, and the ASR
+// confidence of its words when known; hits from translated chunks also carry their language. Every hit
+// carries its content type when known. Distance includes any low-confidence penalty.
+type RetrievedDocument struct {
+	ChunkID         string   `json:"chunk_id,omitempty"`
+	TranscriptionID string   `json:"transcription_id,omitempty"`
+	DocumentID      string   `json:"document_id,omitempty"`
+	RecordingID     string   `json:"recording_id,omitempty"`
+	Start           *float64 `json:"start,omitempty"`
+	End             *float64 `json:"end,omitempty"`
+	Speaker         string   `json:"speaker,omitempty"`
+	Language        string   `json:"language,omitempty"` // Set on hits from a translation of the transcript
+	ContentType     string   `json:"content_type,omitempty"`
+	Confidence      *float64 `json:"confidence,omitempty"`
+	Content         string   `json:"-"`
+	Distance        float32  `json:"distance"`
+
+	lowConfidenceShare float64
+}
+
+// Query performs a RAG query over the transcriptions visible to userID
+func (s *RAGService) Query(ctx context.Context, userID *uint, query string, nResults int) ([]string, error) {
+	docs, err := s.Retrieve(ctx, userID, query, nResults)
+	if err != nil {
+		return nil, err
+	}
+
+	contents := make([]string, len(docs))
+	for i, doc := range docs {
+		contents[i] = doc.Content
+	}
+	return contents, nil
+}
+
+// Retrieve returns the documents most similar to query, best match first, along with their source transcriptions
+func (s *RAGService) Retrieve(ctx context.Context, userID *uint, query string, nResults int) ([]RetrievedDocument, error) {
+	return s.RetrieveWithin(ctx, userID, query, nResults, nil)
+}
+
+// RetrieveWithin is Retrieve limited to the given transcriptions and the documents linked to them.
+// A nil scope searches everything; an empty scope matches nothing.
+func (s *RAGService) RetrieveWithin(ctx context.Context, userID *uint, query string, nResults int, transcriptionIDs []string) ([]RetrievedDocument, error) {
+	return s.retrieveWithin(ctx, userID, nil, query, nResults, transcriptionIDs)
+}
+
+// retrieveWithin is RetrieveWithin limited to the collections of the given routes, or all of them when routes is empty
+func (s *RAGService) retrieveWithin(ctx context.Context, userID *uint, routes []string, query string, nResults int, transcriptionIDs []string) ([]RetrievedDocument, error) {
+	if transcriptionIDs != nil && len(transcriptionIDs) == 0 {
+		return []RetrievedDocument{}, nil
+	}
+	return s.retrieveFrom(ctx, userID, routes, query, nResults, scopeFilter(transcriptionIDs))
+}
+
+// retrieve runs a similarity query against every collection visible to userID, restricted by an optional where filter
+func (s *RAGService) retrieve(ctx context.Context, userID *uint, query string, nResults int, where map[string]interface{}) ([]RetrievedDocument, error) {
+	return s.retrieveFrom(ctx, userID, nil, query, nResults, where)
+}
+
+// retrieveFrom runs a similarity query against the collections of the given routes visible to
+// userID, or all of them when routes is empty, and merges the hits by distance
+func (s *RAGService) retrieveFrom(ctx context.Context, userID *uint, routes []string, query string, nResults int, where map[string]interface{}) ([]RetrievedDocument, error) {
 	if nResults == 0 {
 		nResults = 5
 	}
-	
+	ctx, span := tracing.Start(ctx, "rag.retrieve", "n_results", nResults, "routes", strings.Join(routes, ","))
+	defer span.End()
+
+	base, err := s.collectionFor(userID)
+	if err != nil {
+		return nil, err
+	}
+	queryEmbedding, err := s.embedder(ctx).GenerateEmbedding(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
+	}
+	collections := s.collectionsOf(base, routes)
+	if len(collections) == 1 {
+		return s.queryEmbedding(ctx, collections[0], queryEmbedding, nResults, where)
+	}
+	var docs []RetrievedDocument
+	for _, collection := range collections {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		hits, err := s.queryEmbedding(ctx, collection, queryEmbedding, nResults, where)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, hits...)
+	}
+	sort.SliceStable(docs, func(i, j int) bool { return docs[i].Distance < docs[j].Distance })
+	if len(docs) > nResults {
+		docs = docs[:nResults]
+	}
+	return docs, nil
+}
+
+// query runs a similarity query against one collection, restricted by an optional where filter
+func (s *RAGService) query(ctx context.Context, collection, query string, nResults int, where map[string]interface{}) ([]RetrievedDocument, error) {
 	// Generate embedding for query
-	queryEmbedding, err := s.embedding.GenerateEmbedding(query)
+	queryEmbedding, err := s.embedder(ctx).GenerateEmbedding(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
 	}
-	
-	// Query vector DB
-	results, err := s.vectorDB.Query(s.collectionName, [][]float32{queryEmbedding}, nResults, nil)
+	return s.queryEmbedding(ctx, collection, queryEmbedding, nResults, where)
+}
+
+// queryEmbedding runs a similarity query for an embedded query against one collection
+func (s *RAGService) queryEmbedding(ctx context.Context, collection string, queryEmbedding []float32, nResults int, where map[string]interface{}) ([]RetrievedDocument, error) {
+	// Query vector DB, fetching extra results that confidence weighting may rank higher
+	fetch := nResults
+	if s.confidenceWeight > 0 {
+		fetch = nResults * confidenceOverfetch
+	}
+	results, err := s.store(ctx).Query(collection, [][]float32{queryEmbedding}, fetch, where)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query vector DB: %w", err)
 	}
 	
 	if len(results.Documents) == 0 || len(results.Documents[0]) == 0 {
-		return []string{}, nil
+		return []RetrievedDocument{}, nil
 	}
 	
-	return results.Documents[0], nil
+	docs := make([]RetrievedDocument, len(results.Documents[0]))
+	for i, content := range results.Documents[0] {
+		docs[i].Content = content
+		if len(results.IDs) > 0 && i < len(results.IDs[0]) {
+			docs[i].ChunkID = results.IDs[0][i]
+		}
+		var meta map[string]interface{}
+		if len(results.Metadatas) > 0 && i < len(results.Metadatas[0]) {
+			meta = results.Metadatas[0][i]
+		}
+		docs[i].ContentType, _ = meta["content_type"].(string)
+		if id, ok := meta["document_id"].(string); ok && id != "" {
+			docs[i].DocumentID = id
+			docs[i].RecordingID, _ = meta["recording_id"].(string)
+			docs[i].ContentType = models.ContentDocument
+		} else if id, ok := meta["transcription_id"].(string); ok && id != "" || meta["session_id"] != nil {
+			// Live session chunks carry times and speakers like transcript chunks, but no transcription yet
+			docs[i].TranscriptionID = id
+			if start, ok := meta["start"].(float64); ok {
+				docs[i].Start = &start
+			}
+			if end, ok := meta["end"].(float64); ok {
+				docs[i].End = &end
+			}
+			docs[i].Speaker, _ = meta["speaker"].(string)
+			if name, ok := meta["speaker_name"].(string); ok && name != "" {
+				docs[i].Speaker = name
+			}
+			if meta["type"] == translationChunkType {
+				docs[i].Language, _ = meta["language"].(string)
+			}
+			if confidence, ok := meta["confidence"].(float64); ok {
+				docs[i].Confidence = &confidence
+			}
+			docs[i].lowConfidenceShare, _ = meta["low_confidence_share"].(float64)
+		} else if len(results.IDs) > 0 && i < len(results.IDs[0]) {
+			// Transcript entries indexed without metadata use the transcription ID as document ID
+			docs[i].TranscriptionID = results.IDs[0][i]
+		}
+		if len(results.Distances) > 0 && i < len(results.Distances[0]) {
+			docs[i].Distance = results.Distances[0][i]
+		}
+	}
+
+	docs = weighByConfidence(docs, s.confidenceWeight)
+	if len(docs) > nResults {
+		docs = docs[:nResults]
+	}
+	return docs, nil
 }
 
-// Chat performs a RAG-enhanced chat
-func (s *RAGService) Chat(ctx context.Context, query string, model string, temperature float64) (string, error) {
-	// Query relevant context
-	contexts, err := s.Query(ctx, query, 5)
+// Chat performs a RAG-enhanced chat over the transcriptions visible to userID.
+// When no relevant context is retrieved the LLM is not called and NoRelevantContextAnswer
+// is returned instead. In ModeExtractive the answer only quotes the retrieved context.
+func (s *RAGService) Chat(ctx context.Context, userID *uint, query string, model string, temperature float64, opts ChatOptions) (*ChatResult, error) {
+	ctx, span := tracing.Start(ctx, "rag.chat", "mode", opts.Mode, "verify", opts.Verify)
+	defer span.End()
+
+	// Query relevant context; only the best matches go into the prompt, the rest are kept as
+	// further supporting excerpts
+	retrieved, err := s.retrieveWithin(ctx, userID, opts.Collections, query, MaxAnswerSources, opts.TranscriptionIDs)
 	if err != nil {
-		return "", fmt.Errorf("failed to query context: %w", err)
+		return nil, fmt.Errorf("failed to query context: %w", err)
+	}
+	retrieved = s.relevant(retrieved)
+	docs := retrieved
+	if len(docs) > chatContextDocuments {
+		docs = docs[:chatContextDocuments]
+	}
+
+	result := &ChatResult{Sources: sourceIDs(docs), DocumentSources: documentIDs(docs), Retrieved: retrieved}
+	if len(docs) == 0 {
+		result.Answer = NoRelevantContextAnswer
+		result.NoRelevantContext = true
+		return result, nil
+	}
+	contexts := make([]string, len(docs))
+	for i, doc := range docs {
+		contexts[i] = doc.Content
+	}
+	if opts.Mode == ModeExtractive {
+		// Standing context isn't part of the records, so it can't be quoted from
+		if result.Answer, result.Quotes, err = s.extractAnswer(ctx, model, query, docs); err != nil {
+			return nil, err
+		}
+		if opts.Verify && len(result.Quotes) > 0 {
+			result.Verification = s.verifyAnswer(ctx, model, query, result.Answer, contexts)
+		}
+		return result, nil
+	}
+	standing, err := s.standingContext()
+	if err != nil {
+		return nil, err
 	}
 	
 	// Build prompt with context
 	var prompt strings.Builder
 	prompt.WriteString("You are a helpful assistant that answers questions based on the following transcription summaries and transcripts.\n\n")
-	prompt.WriteString("Relevant context:\n")
-	for i, ctx := range contexts {
-		prompt.WriteString(fmt.Sprintf("%d. %s\n\n", i+1, ctx))
+	if standing != "" {
+		// Background the team keeps up to date, e.g. a glossary and current goals
+		prompt.WriteString("Standing context about the team:\n")
+		prompt.WriteString(standing)
+		prompt.WriteString("\n\n")
 	}
+	// Each excerpt is labeled with its content type, which the guidance explains, and excerpts
+	// in another language than the question with their language if asked for
+	labeled, languageNote := s.crossLanguageContexts(ctx, model, query, docs, opts, result)
+	prompt.WriteString(contentGuidance(docs))
+	prompt.WriteString(languageNote)
+	prompt.WriteString("Relevant context:\n")
+	writeContexts(&prompt, labeled)
 	prompt.WriteString("\nUser question: ")
 	prompt.WriteString(query)
-	prompt.WriteString("\n\nPlease provide a helpful answer based on the context above.")
+	prompt.WriteString("\n\nPlease provide a helpful answer based on the context above. If the context does not contain the answer, say so instead of guessing.")
 	
-	// Call LLM
-	messages := []llm.ChatMessage{
-		{Role: "user", Content: prompt.String()},
-	}
+	// Call LLM, after the conversation so far
+	messages := append([]llm.ChatMessage{}, opts.History...)
+	messages = append(messages, llm.ChatMessage{Role: "user", Content: prompt.String()})
 	
 	response, err := s.llmService.ChatCompletion(ctx, model, messages, temperature)
 	if err != nil {
-		return "", fmt.Errorf("failed to get LLM response: %w", err)
+		return nil, fmt.Errorf("failed to get LLM response: %w", err)
 	}
 	
 	if len(response.Choices) == 0 {
-		return "", fmt.Errorf("no response from LLM")
+		return nil, fmt.Errorf("no response from LLM")
+	}
+	result.Answer = response.Choices[0].Message.Content
+
+	if opts.Verify {
+		result.Verification = s.verifyAnswer(ctx, model, query, result.Answer, contexts)
 	}
 	
-	return response.Choices[0].Message.Content, nil
+	return result, nil
 }
 
-// GetStats returns statistics about the RAG system
-func (s *RAGService) GetStats(ctx context.Context) (map[string]interface{}, error) {
-	stats := make(map[string]interface{})
-	
-	// Count completed transcriptions (each one should be in RAG)
-	// This is more reliable than querying ChromaDB directly
-	var count int64
+// Embed embeds texts with the embedding model used for the vector store, in order
+func (s *RAGService) Embed(texts []string) ([][]float32, error) {
+	return s.embedding.GenerateEmbeddings(texts)
+}
+
+// EmbeddingModel returns the name of the model used to embed documents and queries
+func (s *RAGService) EmbeddingModel() string {
+	return s.embedding.Model()
+}
+
+// IsIndexed reports whether a transcription has at least one document in the collection its content type is routed to
+func (s *RAGService) IsIndexed(transcriptionID string) (bool, error) {
+	_, collection, err := s.jobCollection(transcriptionID)
+	if err != nil {
+		return false, err
+	}
+	return s.isIndexedIn(collection, transcriptionID)
+}
+
+// DeleteTranscription removes a transcription's summary entry and transcript chunks from each
+// of its owner's collections. Uploaded documents linked to the recording are kept.
+func (s *RAGService) DeleteTranscription(ctx context.Context, transcriptionID string) error {
+	owner, err := transcriptionOwner(transcriptionID)
+	if err != nil {
+		return err
+	}
+	base, err := s.collectionFor(owner)
+	if err != nil {
+		return err
+	}
+	for _, collection := range s.collectionsOf(base, nil) {
+		if err := s.store(ctx).DeleteDocuments(collection, nil, map[string]interface{}{"transcription_id": transcriptionID}); err != nil {
+			return fmt.Errorf("failed to delete documents for %s: %w", transcriptionID, err)
+		}
+	}
+	events.Record(models.EventIndexUpdated, transcriptionID, owner, map[string]interface{}{"kind": "transcription", "deleted": true})
+	return nil
+}
+
+// isIndexedIn reports whether a collection holds documents for a transcription
+func (s *RAGService) isIndexedIn(collection, transcriptionID string) (bool, error) {
+	count, err := s.vectorDB.CountDocuments(collection, map[string]interface{}{
+		"transcription_id": transcriptionID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to count documents for %s: %w", transcriptionID, err)
+	}
+	return count > 0, nil
+}
+
+// FindMissing returns the IDs of completed transcriptions that have no documents in the collection their content type is routed to
+func (s *RAGService) FindMissing(ctx context.Context) ([]string, error) {
+	refs, err := completedTranscriptions()
+	if err != nil {
+		return nil, err
+	}
+	resolver, err := newScopeResolver()
+	if err != nil {
+		return nil, err
+	}
+
+	missing := []string{}
+	for _, ref := range refs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		collection := routedCollection(resolver.collection(ref.UserID), s.routeOf(ref.ContentType))
+		s.ensureCollection(collection)
+		indexed, err := s.isIndexedIn(collection, ref.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !indexed {
+			missing = append(missing, ref.ID)
+		}
+	}
+	return missing, nil
+}
+
+// transcriptionRef identifies a transcription that is expected to be in the vector store
+type transcriptionRef struct {
+	ID          string
+	UserID      *uint
+	ContentType string
+	UpdatedAt   time.Time
+}
+
+// completedTranscriptions lists completed transcriptions with a non-empty transcript that
+// aren't merged duplicates, i.e. every job that is expected to be present in the vector store
+func completedTranscriptions() ([]transcriptionRef, error) {
+	var refs []transcriptionRef
 	if err := database.DB.Model(&models.TranscriptionJob{}).
+		Select("id", "user_id", "content_type", "updated_at").
 		Where("status = ?", models.StatusCompleted).
 		Where("transcript IS NOT NULL AND transcript != ''").
-		Count(&count).Error; err != nil {
-		return nil, fmt.Errorf("failed to count transcriptions: %w", err)
+		Where("duplicate_of IS NULL").
+		Scan(&refs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list transcriptions: %w", err)
+	}
+	return refs, nil
+}
+
+// GetStats returns statistics about the RAG collections visible to userID, read from the vector store itself
+func (s *RAGService) GetStats(ctx context.Context, userID *uint) (map[string]interface{}, error) {
+	stats := make(map[string]interface{})
+
+	resolver, err := newScopeResolver()
+	if err != nil {
+		return nil, err
+	}
+	collection := resolver.collection(userID)
+	s.ensureCollection(collection)
+	collections := s.collectionsOf(collection, nil)
+
+	// Count completed transcriptions in this scope (each one should be in RAG)
+	refs, err := completedTranscriptions()
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, ref := range refs {
+		if resolver.collection(ref.UserID) == collection {
+			ids = append(ids, ref.ID)
+		}
 	}
 	
-	stats["transcript_count"] = int(count)
-	stats["collection_name"] = s.collectionName
+	stats["transcript_count"] = len(ids)
+	stats["collection_name"] = collection
+	stats["collections"] = collections
+	stats["embedding_model"] = s.embedding.Model()
 	stats["status"] = "active"
+
+	// Every entry in the store is one chunk, an indexed transcription has one summary entry and
+	// an uploaded document one first chunk, so everything is counted by the store rather than
+	// read out of it
+	summaries := map[string]interface{}{"type": summaryType}
+	expected := andFilter(summaries, map[string]interface{}{"transcription_id": map[string]interface{}{"$in": ids}})
+	firstDocumentChunks := map[string]interface{}{"type": documentType, "chunk_index": 0}
+	var countErr error
+	count := func(collection string, where map[string]interface{}) int {
+		if countErr != nil {
+			return 0
+		}
+		n, err := s.store(ctx).CountDocuments(collection, where)
+		countErr = err
+		return n
+	}
+	documentCount, indexedCount, indexedTranscriptions, indexedDocuments := 0, 0, 0, 0
+	for _, name := range collections {
+		documentCount += count(name, nil)
+		indexedTranscriptions += count(name, summaries)
+		indexedDocuments += count(name, firstDocumentChunks)
+		if len(ids) > 0 {
+			indexedCount += count(name, expected)
+		}
+	}
+	if countErr != nil {
+		stats["status"] = "degraded"
+		stats["index_error"] = countErr.Error()
+		return stats, nil
+	}
+	// A transcription caught moving between collections is briefly in both
+	indexedCount = min(indexedCount, len(ids))
+
+	stats["document_count"] = documentCount
+	stats["indexed_count"] = indexedCount
+	stats["indexed_transcriptions"] = indexedTranscriptions
+	stats["indexed_documents"] = indexedDocuments
+	stats["missing_count"] = len(ids) - indexedCount
+	if lastIndexed, err := lastIndexedAt(ids); err != nil {
+		return nil, err
+	} else if !lastIndexed.IsZero() {
+		stats["last_indexed_at"] = lastIndexed
+	}
+
+	dimension := 0
+	for _, name := range collections {
+		if dimension, err = s.embeddingDimension(ctx, name); err != nil || dimension > 0 {
+			break
+		}
+	}
+	if err != nil {
+		stats["dimension_error"] = err.Error()
+	} else {
+		stats["embedding_dimension"] = dimension
+	}
+
+	// Approximate footprint of the stored float32 vectors
+	stats["index_size_bytes"] = documentCount * dimension * 4
 	
 	return stats, nil
 }
+
+// lastIndexedAt returns when any of the transcriptions was last indexed, going by the index
+// events recorded for them, or the zero time if none was
+func lastIndexedAt(ids []string) (time.Time, error) {
+	if len(ids) == 0 {
+		return time.Time{}, nil
+	}
+	var event models.Event
+	err := database.DB.Select("created_at").
+		Where("type = ? AND subject_id IN ?", models.EventIndexUpdated, ids).
+		Order("sequence DESC").Limit(1).Find(&event).Error
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to find the last indexing: %w", err)
+	}
+	return event.CreatedAt, nil
+}
+
+// embeddingDimension reads the vector length from a stored document, returning 0 for an empty collection
+func (s *RAGService) embeddingDimension(ctx context.Context, collection string) (int, error) {
+	sample, err := s.store(ctx).GetDocuments(collection, vectordb.GetRequest{
+		Limit:   1,
+		Include: []string{"embeddings"},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to sample embeddings: %w", err)
+	}
+	if len(sample.Embeddings) == 0 {
+		return 0, nil
+	}
+	return len(sample.Embeddings[0]), nil
+}
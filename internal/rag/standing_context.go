@@ -0,0 +1,69 @@
+package rag
+
+import (
+	"fmt"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+)
+
+// DefaultStandingContextTokens is the default token budget of the standing context in chat prompts
+const DefaultStandingContextTokens = 1000
+
+// SetStandingContextTokens sets how many tokens of the standing context are prepended to chat
+// prompts; 0 leaves it out
+func (s *RAGService) SetStandingContextTokens(tokens int) {
+	if tokens < 0 {
+		tokens = 0
+	}
+	s.standingContextTokens = tokens
+}
+
+// standingContext returns the standing context trimmed to the token budget, or "" if there is none
+func (s *RAGService) standingContext() (string, error) {
+	if s.standingContextTokens == 0 {
+		return "", nil
+	}
+	var standing models.StandingContext
+	if err := database.DB.Limit(1).Find(&standing).Error; err != nil {
+		return "", fmt.Errorf("failed to load standing context: %w", err)
+	}
+	text, _ := TrimToTokenBudget(standing.Content, s.standingContextTokens)
+	return text, nil
+}
+
+// TrimToTokenBudget cuts text to about budget tokens, keeping whole lines where it can so a
+// glossary loses its last entries rather than half of one. It reports whether text was cut.
+func TrimToTokenBudget(text string, budget int) (string, bool) {
+	text = strings.TrimSpace(text)
+	if llm.EstimateTokens(text) <= budget {
+		return text, false
+	}
+
+	var kept []string
+	used := 0
+	for _, line := range strings.Split(text, "\n") {
+		tokens := llm.EstimateTokens(line + "\n")
+		if used+tokens > budget {
+			if len(kept) == 0 {
+				// A single line over budget is cut between words
+				words := strings.Fields(line)
+				for _, word := range words {
+					tokens := llm.EstimateTokens(word + " ")
+					if used+tokens > budget {
+						break
+					}
+					kept = append(kept, word)
+					used += tokens
+				}
+				return strings.Join(kept, " "), true
+			}
+			break
+		}
+		kept = append(kept, line)
+		used += tokens
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n")), true
+}
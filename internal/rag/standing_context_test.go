@@ -0,0 +1,30 @@
+package rag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTrimToTokenBudget(t *testing.T) {
+	glossary := "ARR: annual recurring revenue\nNPS: net promoter score\nQBR: quarterly business review"
+
+	if text, truncated := TrimToTokenBudget("  "+glossary+"\n", 100); truncated || text != glossary {
+		t.Errorf("expected text within budget to be kept, got %q (truncated %v)", text, truncated)
+	}
+
+	// The first line is 8 tokens and the second 6, so a budget of 10 keeps the first only
+	text, truncated := TrimToTokenBudget(glossary, 10)
+	if !truncated || text != "ARR: annual recurring revenue" {
+		t.Errorf("expected whole lines to be kept, got %q (truncated %v)", text, truncated)
+	}
+
+	long := strings.Repeat("word ", 100)
+	text, truncated = TrimToTokenBudget(long, 10)
+	if !truncated || text == "" || strings.Count(text, "word") > 8 {
+		t.Errorf("expected a long line to be cut between words, got %q", text)
+	}
+
+	if text, _ := TrimToTokenBudget(glossary, 0); text != "" {
+		t.Errorf("expected nothing to fit a zero budget, got %q", text)
+	}
+}
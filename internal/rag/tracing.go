@@ -0,0 +1,132 @@
+package rag
+
+import (
+	"context"
+
+	"scriberr/internal/embeddings"
+	"scriberr/internal/tracing"
+	"scriberr/internal/vectordb"
+)
+
+// store returns the vector store, with each call traced as a child of the span in ctx
+func (s *RAGService) store(ctx context.Context) vectordb.Store {
+	if !tracing.Enabled() {
+		return s.vectorDB
+	}
+	return &tracedStore{Store: s.vectorDB, ctx: ctx}
+}
+
+// embedder returns the embedding service, with each call traced as a child of the span in ctx
+func (s *RAGService) embedder(ctx context.Context) embeddings.Service {
+	if !tracing.Enabled() {
+		return s.embedding
+	}
+	return &tracedEmbeddings{Service: s.embedding, ctx: ctx}
+}
+
+// tracedStore traces the calls of the vector store it wraps
+type tracedStore struct {
+	vectordb.Store
+	ctx context.Context
+}
+
+// start starts the span of a call against collection
+func (t *tracedStore) start(operation, collection string, attrs ...interface{}) *tracing.Span {
+	_, span := tracing.StartKind(t.ctx, tracing.KindClient, "vectordb."+operation, append([]interface{}{"db.collection.name", collection}, attrs...)...)
+	return span
+}
+
+// endSpan records the outcome of a call
+func endSpan(span *tracing.Span, err error) {
+	span.RecordError(err)
+	span.End()
+}
+
+func (t *tracedStore) CreateCollection(name string, metadata map[string]interface{}) error {
+	span := t.start("create_collection", name)
+	err := t.Store.CreateCollection(name, metadata)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedStore) AddDocuments(collectionName string, ids []string, documents []string, embeddings [][]float32, metadatas []map[string]interface{}) error {
+	span := t.start("add", collectionName, "documents", len(ids))
+	err := t.Store.AddDocuments(collectionName, ids, documents, embeddings, metadatas)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedStore) UpsertDocuments(collectionName string, ids []string, documents []string, embeddings [][]float32, metadatas []map[string]interface{}) error {
+	span := t.start("upsert", collectionName, "documents", len(ids))
+	err := t.Store.UpsertDocuments(collectionName, ids, documents, embeddings, metadatas)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedStore) Query(collectionName string, queryEmbeddings [][]float32, nResults int, where map[string]interface{}) (*vectordb.QueryResponse, error) {
+	span := t.start("query", collectionName, "n_results", nResults, "filtered", where != nil)
+	resp, err := t.Store.Query(collectionName, queryEmbeddings, nResults, where)
+	endSpan(span, err)
+	return resp, err
+}
+
+func (t *tracedStore) CountDocuments(collectionName string, where map[string]interface{}) (int, error) {
+	span := t.start("count", collectionName, "filtered", where != nil)
+	count, err := t.Store.CountDocuments(collectionName, where)
+	endSpan(span, err)
+	return count, err
+}
+
+func (t *tracedStore) GetDocuments(collectionName string, getReq vectordb.GetRequest) (*vectordb.GetResponse, error) {
+	span := t.start("get", collectionName, "ids", len(getReq.IDs), "filtered", getReq.Where != nil)
+	resp, err := t.Store.GetDocuments(collectionName, getReq)
+	if resp != nil {
+		span.SetAttribute("documents", len(resp.IDs))
+	}
+	endSpan(span, err)
+	return resp, err
+}
+
+func (t *tracedStore) UpdateMetadata(collectionName string, ids []string, metadatas []map[string]interface{}) error {
+	span := t.start("update_metadata", collectionName, "documents", len(ids))
+	err := t.Store.UpdateMetadata(collectionName, ids, metadatas)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedStore) DeleteDocuments(collectionName string, ids []string, where map[string]interface{}) error {
+	span := t.start("delete", collectionName, "ids", len(ids), "filtered", where != nil)
+	err := t.Store.DeleteDocuments(collectionName, ids, where)
+	endSpan(span, err)
+	return err
+}
+
+// tracedEmbeddings traces the calls of the embedding service it wraps
+type tracedEmbeddings struct {
+	embeddings.Service
+	ctx context.Context
+}
+
+func (t *tracedEmbeddings) GenerateEmbedding(text string) ([]float32, error) {
+	span := t.start(1, len(text))
+	embedding, err := t.Service.GenerateEmbedding(text)
+	endSpan(span, err)
+	return embedding, err
+}
+
+func (t *tracedEmbeddings) GenerateEmbeddings(texts []string) ([][]float32, error) {
+	size := 0
+	for _, text := range texts {
+		size += len(text)
+	}
+	span := t.start(len(texts), size)
+	vectors, err := t.Service.GenerateEmbeddings(texts)
+	endSpan(span, err)
+	return vectors, err
+}
+
+// start starts the span of a call embedding texts of size bytes in total
+func (t *tracedEmbeddings) start(texts, size int) *tracing.Span {
+	_, span := tracing.StartKind(t.ctx, tracing.KindClient, "embeddings.generate", "gen_ai.request.model", t.Model(), "texts", texts, "bytes", size)
+	return span
+}
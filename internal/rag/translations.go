@@ -0,0 +1,119 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+)
+
+// translationChunkType tags vector store entries holding a time range of a translated
+// transcript. They carry the same transcription ID, times and speaker as the original's
+// chunks, plus the language they are in, so questions in either language find the recording.
+const translationChunkType = "translation_chunk"
+
+// translationChunkID returns the vector store ID of one chunk of a translation
+func translationChunkID(transcriptionID, language string, index int) string {
+	slug := strings.ToLower(strings.Join(strings.Fields(language), "-"))
+	return fmt.Sprintf("translation_%s_%s_%d", transcriptionID, slug, index)
+}
+
+// translationFilter matches the entries of one translation of a transcription
+func translationFilter(transcriptionID, language string) map[string]interface{} {
+	return map[string]interface{}{
+		"$and": []map[string]interface{}{
+			{"transcription_id": transcriptionID},
+			{"type": translationChunkType},
+			{"language": language},
+		},
+	}
+}
+
+// StoreTranslation indexes a translation as timestamped chunks next to the original
+// transcript, replacing an earlier indexing of the same language. Only chunks whose text
+// changed are re-embedded.
+func (s *RAGService) StoreTranslation(ctx context.Context, translation *models.Translation) error {
+	owner, collection, err := s.jobCollection(translation.TranscriptionJobID)
+	if err != nil {
+		return err
+	}
+	meta, err := loadJobMetadata(translation.TranscriptionJobID)
+	if err != nil {
+		return err
+	}
+	cache, err := s.newEmbeddingCache(ctx, collection, translation.TranscriptionJobID)
+	if err != nil {
+		return err
+	}
+	indexText, err := s.textForIndex(translation.TranscriptionJobID)
+	if err != nil {
+		return err
+	}
+
+	segments := make([]interfaces.TranscriptSegment, len(translation.Segments))
+	for i, segment := range translation.Segments {
+		segments[i] = interfaces.TranscriptSegment{Start: segment.Start, End: segment.End, Speaker: segment.Speaker, Text: segment.Text}
+	}
+	chunks := ChunkSegments(segments, Profile(meta.contentType).ChunkSize)
+
+	indexedAt := time.Now().Unix()
+	ids := make([]string, len(chunks))
+	contents := make([]string, len(chunks))
+	embeddings := make([][]float32, len(chunks))
+	metadatas := make([]map[string]interface{}, len(chunks))
+	for i, chunk := range chunks {
+		chunk.Text = indexText(chunk.Text)
+		embedding, hash, err := cache.embed(chunk.Text)
+		if err != nil {
+			return fmt.Errorf("failed to generate embedding for translated chunk %d: %w", i, err)
+		}
+		ids[i] = translationChunkID(translation.TranscriptionJobID, translation.Language, i)
+		contents[i] = chunk.Text
+		embeddings[i] = embedding
+		metadata := map[string]interface{}{
+			"transcription_id": translation.TranscriptionJobID,
+			"type":             translationChunkType,
+			"language":         translation.Language,
+			"chunk_index":      i,
+			"start":            chunk.Start,
+			"end":              chunk.End,
+			"indexed_at":       indexedAt,
+			"content_hash":     hash,
+			"embedding_model":  s.embedding.Model(),
+		}
+		if chunk.Speaker != "" {
+			metadata["speaker"] = chunk.Speaker
+		}
+		meta.apply(metadata)
+		if owner != nil {
+			metadata["user_id"] = *owner
+		}
+		metadatas[i] = metadata
+	}
+
+	if err := s.store(ctx).DeleteDocuments(collection, nil, translationFilter(translation.TranscriptionJobID, translation.Language)); err != nil {
+		return fmt.Errorf("failed to remove previous translated chunks: %w", err)
+	}
+	if len(chunks) == 0 {
+		return nil
+	}
+	if err := s.store(ctx).UpsertDocuments(collection, ids, contents, embeddings, metadatas); err != nil {
+		return fmt.Errorf("failed to store translated chunks in vector DB: %w", err)
+	}
+	return nil
+}
+
+// DeleteTranslation removes one translation of a transcription from its owner's collection
+func (s *RAGService) DeleteTranslation(transcriptionID, language string) error {
+	_, collection, err := s.jobCollection(transcriptionID)
+	if err != nil {
+		return err
+	}
+	if err := s.vectorDB.DeleteDocuments(collection, nil, translationFilter(transcriptionID, language)); err != nil {
+		return fmt.Errorf("failed to delete translated chunks for %s: %w", transcriptionID, err)
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+// Package redact finds personal data in transcript text and replaces it with placeholders.
+// Email addresses, phone numbers and payment card numbers are found by pattern; names can't
+// be, so they are given by the caller, usually as found by an LLM.
+package redact
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kinds of personal data
+const (
+	KindEmail      = "email"
+	KindPhone      = "phone"
+	KindCreditCard = "credit_card"
+	KindName       = "name"
+	KindTerm       = "term" // A sensitive term given by the caller, such as a project codename
+)
+
+// Kinds lists every kind of personal data, in the order overlapping matches are resolved
+var Kinds = []string{KindTerm, KindCreditCard, KindEmail, KindPhone, KindName}
+
+// placeholders replace each kind of personal data
+var placeholders = map[string]string{
+	KindEmail:      "[EMAIL]",
+	KindPhone:      "[PHONE]",
+	KindCreditCard: "[CARD]",
+	KindName:       "[NAME]",
+	KindTerm:       "[REDACTED]",
+}
+
+var (
+	emailPattern = regexp.MustCompile(`(?i)[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}`)
+	// 13 to 19 digits, optionally grouped with spaces or dashes
+	cardPattern = regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`)
+	// An optional country code, an optional bracketed area code, then groups of digits
+	phonePattern = regexp.MustCompile(`(?:\+\d{1,3}[ .\-]?)?(?:\(\d{1,5}\)[ .\-]?)?\d{2,5}(?:[ .\-]?\d{2,5}){1,4}`)
+)
+
+// datePattern matches dates, which the phone pattern would otherwise take
+var datePattern = regexp.MustCompile(`^(?:\d{4}[\-./]\d{1,2}[\-./]\d{1,2}|\d{1,2}[\-./]\d{1,2}[\-./]\d{2,4})$`)
+
+// Redactor replaces personal data in text with placeholders such as [EMAIL] and [NAME]
+type Redactor struct {
+	names []*regexp.Regexp // Longest first, so a full name wins over a part of it
+	terms []*regexp.Regexp
+}
+
+// New returns a Redactor that also replaces the given names, ignoring case. The parts of a
+// multi-word name are replaced on their own too when capitalized, so "John" is caught after
+// "John Smith" was found, but "will" isn't after "Will Jones".
+func New(names []string) *Redactor {
+	type term struct {
+		text  string
+		exact bool
+	}
+	seen := map[string]bool{}
+	var terms []term
+	add := func(text string, exact bool) {
+		key := strings.ToLower(text)
+		if text != "" && !seen[key] {
+			seen[key] = true
+			terms = append(terms, term{text, exact})
+		}
+	}
+	for _, name := range names {
+		name = strings.Join(strings.Fields(name), " ")
+		add(name, false)
+		parts := strings.Fields(name)
+		if len(parts) < 2 {
+			continue
+		}
+		for _, part := range parts {
+			part = strings.Trim(part, ".,")
+			if first, _ := utf8.DecodeRuneInString(part); utf8.RuneCountInString(part) > 1 && unicode.IsUpper(first) {
+				add(part, true)
+			}
+		}
+	}
+	sort.SliceStable(terms, func(i, j int) bool { return len(terms[i].text) > len(terms[j].text) })
+
+	r := &Redactor{}
+	for _, t := range terms {
+		pattern := regexp.QuoteMeta(t.text)
+		if !t.exact {
+			pattern = `(?i)` + pattern
+		}
+		r.names = append(r.names, regexp.MustCompile(pattern))
+	}
+	return r
+}
+
+// WithTerms returns a copy of r that also finds the given sensitive terms as whole words,
+// ignoring case
+func (r *Redactor) WithTerms(terms []string) *Redactor {
+	copied := &Redactor{names: r.names, terms: append([]*regexp.Regexp(nil), r.terms...)}
+	for _, term := range terms {
+		if term = strings.Join(strings.Fields(term), " "); term != "" {
+			copied.terms = append(copied.terms, regexp.MustCompile(`(?i)`+regexp.QuoteMeta(term)))
+		}
+	}
+	return copied
+}
+
+// Match is one piece of personal data found in a text, at text[Start:End]
+type Match struct {
+	Kind       string
+	Start, End int
+}
+
+// Find returns the personal data in text in the order it appears. Where matches of several
+// kinds overlap, the kind earliest in Kinds wins.
+func (r *Redactor) Find(text string) []Match {
+	var matches []Match
+	taken := func(start, end int) bool {
+		for _, m := range matches {
+			if start < m.End && m.Start < end {
+				return true
+			}
+		}
+		return false
+	}
+	for _, kind := range Kinds {
+		for _, match := range r.find(kind, text) {
+			if !taken(match[0], match[1]) {
+				matches = append(matches, Match{kind, match[0], match[1]})
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+	return matches
+}
+
+// Redact returns text with the personal data it holds replaced, and how many of each kind
+// were replaced
+func (r *Redactor) Redact(text string) (string, map[string]int) {
+	counts := map[string]int{}
+	matches := r.Find(text)
+	if len(matches) == 0 {
+		return text, counts
+	}
+
+	var out strings.Builder
+	last := 0
+	for _, m := range matches {
+		out.WriteString(text[last:m.Start])
+		out.WriteString(placeholders[m.Kind])
+		last = m.End
+		counts[m.Kind]++
+	}
+	out.WriteString(text[last:])
+	return out.String(), counts
+}
+
+// find returns the byte ranges of the personal data of one kind in text
+func (r *Redactor) find(kind, text string) [][]int {
+	switch kind {
+	case KindEmail:
+		return emailPattern.FindAllStringIndex(text, -1)
+	case KindCreditCard:
+		var matches [][]int
+		for _, match := range cardPattern.FindAllStringIndex(text, -1) {
+			if luhn(text[match[0]:match[1]]) {
+				matches = append(matches, match)
+			}
+		}
+		return matches
+	case KindPhone:
+		var matches [][]int
+		for _, match := range phonePattern.FindAllStringIndex(text, -1) {
+			if isPhoneNumber(text, match[0], match[1]) {
+				matches = append(matches, match)
+			}
+		}
+		return matches
+	case KindName:
+		var matches [][]int
+		for _, name := range r.names {
+			matches = append(matches, findWord(text, name)...)
+		}
+		return matches
+	case KindTerm:
+		var matches [][]int
+		for _, term := range r.terms {
+			matches = append(matches, findWord(text, term)...)
+		}
+		return matches
+	}
+	return nil
+}
+
+// isPhoneNumber reports whether the digits at text[start:end] look like a phone number rather
+// than an amount or a date: 7 to 15 digits, not part of a longer number, and written with a
+// country code, brackets, dashes or dots unless there are at least 10 of them
+func isPhoneNumber(text string, start, end int) bool {
+	if start > 0 && (isDigit(text[start-1]) || text[start-1] == '.' || text[start-1] == ',') {
+		return false
+	}
+	if end < len(text) && (isDigit(text[end]) || (text[end] == ',' || text[end] == '.') && end+1 < len(text) && isDigit(text[end+1])) {
+		return false
+	}
+	number := text[start:end]
+	digits := 0
+	for i := 0; i < len(number); i++ {
+		if isDigit(number[i]) {
+			digits++
+		}
+	}
+	if digits < 7 || digits > 15 || datePattern.MatchString(number) {
+		return false
+	}
+	return digits >= 10 || strings.ContainsAny(number, "+(-.")
+}
+
+// luhn reports whether the digits of number pass the Luhn checksum used by payment cards
+func luhn(number string) bool {
+	sum, digits := 0, 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		if !isDigit(number[i]) {
+			continue
+		}
+		d := int(number[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// findWord returns the byte ranges of the matches of pattern in text that stand as whole
+// words rather than inside longer ones
+func findWord(text string, pattern *regexp.Regexp) [][]int {
+	var matches [][]int
+	for pos := 0; pos < len(text); {
+		match := pattern.FindStringIndex(text[pos:])
+		if match == nil {
+			break
+		}
+		start, end := pos+match[0], pos+match[1]
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start == 0 || !isWordRune(before)) && (end == len(text) || !isWordRune(after)) {
+			matches = append(matches, []int{start, end})
+			pos = end
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		pos = start + size
+	}
+	return matches
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
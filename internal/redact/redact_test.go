@@ -0,0 +1,58 @@
+package redact
+
+import "testing"
+
+func TestRedactPatterns(t *testing.T) {
+	r := New(nil)
+	text := "Mail jane.doe@example.com or call +1 (555) 123-4567, card 4111 1111 1111 1111. " +
+		"The budget is 1,250,000 for 2024-05-12, ticket 4111 1111 1111 1112."
+	got, counts := r.Redact(text)
+	want := "Mail [EMAIL] or call [PHONE], card [CARD]. " +
+		"The budget is 1,250,000 for 2024-05-12, ticket 4111 1111 1111 1112."
+	if got != want {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+	if counts[KindEmail] != 1 || counts[KindPhone] != 1 || counts[KindCreditCard] != 1 {
+		t.Errorf("unexpected counts %v", counts)
+	}
+	if got, _ := r.Redact("Call 555-1234 at 10:30."); got != "Call [PHONE] at 10:30." {
+		t.Errorf("expected a local number to be redacted, got %q", got)
+	}
+}
+
+func TestRedactNames(t *testing.T) {
+	r := New([]string{"Jane Doe", "  José  Álvarez ", "Will Jones"})
+	got, counts := r.Redact("JANE DOE met José Álvarez. Later Jane and Álvarez agreed. Doesn't Janet? We will ask Will.")
+	want := "[NAME] met [NAME]. Later [NAME] and [NAME] agreed. Doesn't Janet? We will ask [NAME]."
+	if got != want {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+	if counts[KindName] != 5 {
+		t.Errorf("expected 5 names, got %v", counts)
+	}
+	if got, counts := New(nil).Redact(""); got != "" || len(counts) != 0 {
+		t.Errorf("expected nothing to redact in empty text, got %q %v", got, counts)
+	}
+}
+
+func TestFindTerms(t *testing.T) {
+	r := New([]string{"Jane Doe"}).WithTerms([]string{"Project  Falcon", "merger", ""})
+	text := "Jane Doe said the project falcon merger is off; mergers in general aren't."
+	matches := r.Find(text)
+	var found []string
+	for _, m := range matches {
+		found = append(found, m.Kind+":"+text[m.Start:m.End])
+	}
+	want := []string{"name:Jane Doe", "term:project falcon", "term:merger"}
+	if len(found) != len(want) {
+		t.Fatalf("got %v, want %v", found, want)
+	}
+	for i := range want {
+		if found[i] != want[i] {
+			t.Errorf("match %d: got %q, want %q", i, found[i], want[i])
+		}
+	}
+	if got, _ := r.Redact("Falcon launches"); got != "Falcon launches" {
+		t.Errorf("expected part of a term to be left alone, got %q", got)
+	}
+}
@@ -0,0 +1,47 @@
+//go:build linux
+// +build linux
+
+package resources
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// memoryUsage returns the memory available for new processes without swapping, and the total
+// memory, from /proc/meminfo
+func memoryUsage() (available, total uint64, err error) {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemAvailable:":
+			available = kb << 10
+		case "MemTotal:":
+			total = kb << 10
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, err
+	}
+	if total == 0 || available == 0 {
+		return 0, 0, fmt.Errorf("MemAvailable not reported")
+	}
+	return available, total, nil
+}
@@ -0,0 +1,203 @@
+// Package resources checks free disk space and memory before uploads and transcriptions, so
+// work the machine can't finish is turned away up front rather than failing halfway through
+// and leaving partial files behind.
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"scriberr/internal/notify"
+	"scriberr/pkg/logger"
+)
+
+// Resources a Guard checks
+const (
+	ResourceDisk   = "disk"
+	ResourceMemory = "memory"
+)
+
+// AlertInterval is how long a Guard waits before notifying about the same resource again
+const AlertInterval = 15 * time.Minute
+
+// errUnsupported is returned where a resource can't be measured on this platform
+var errUnsupported = errors.New("not supported on this platform")
+
+// Usage is a snapshot of the free and total disk space and memory, in bytes. Fields the
+// platform can't report are zero.
+type Usage struct {
+	DiskFree        uint64 `json:"disk_free"`
+	DiskTotal       uint64 `json:"disk_total"`
+	MemoryAvailable uint64 `json:"memory_available"`
+	MemoryTotal     uint64 `json:"memory_total"`
+}
+
+// Measure returns the free space of the file system holding dir and the memory available to
+// new processes
+func Measure(dir string) Usage {
+	var usage Usage
+	if free, total, err := diskUsage(dir); err == nil {
+		usage.DiskFree, usage.DiskTotal = free, total
+	} else if !errors.Is(err, errUnsupported) {
+		logger.Warn("Failed to measure disk space", "component", "resources", "dir", dir, "error", err)
+	}
+	if available, total, err := memoryUsage(); err == nil {
+		usage.MemoryAvailable, usage.MemoryTotal = available, total
+	} else if !errors.Is(err, errUnsupported) {
+		logger.Warn("Failed to measure memory", "component", "resources", "error", err)
+	}
+	return usage
+}
+
+// Error reports that a resource is too low to take on more work
+type Error struct {
+	Resource  string
+	Available uint64 // Bytes free
+	Required  uint64 // Bytes that must be free
+}
+
+func (e *Error) Error() string {
+	switch e.Resource {
+	case ResourceDisk:
+		return fmt.Sprintf("not enough free disk space: %s free, %s needed; delete old recordings or their audio, or free up space on the server", FormatBytes(e.Available), FormatBytes(e.Required))
+	default:
+		return fmt.Sprintf("not enough free memory: %s available, %s needed; try again once running transcriptions finish", FormatBytes(e.Available), FormatBytes(e.Required))
+	}
+}
+
+// StatusCode is the HTTP status to answer with: 507 Insufficient Storage for disk space,
+// which won't come back by itself, and 429 Too Many Requests for memory, which frees up as
+// running work finishes
+func (e *Error) StatusCode() int {
+	if e.Resource == ResourceDisk {
+		return http.StatusInsufficientStorage
+	}
+	return http.StatusTooManyRequests
+}
+
+// Guard rejects work when the disk holding the upload directory or the memory would fall
+// below their thresholds. A threshold of zero turns its check off.
+type Guard struct {
+	dir       string
+	minDisk   uint64
+	minMemory uint64
+	notifier  *notify.WebhookNotifier
+	measure   func(dir string) Usage
+
+	mu         sync.Mutex
+	lastAlerts map[string]time.Time
+}
+
+// NewGuard creates a guard that keeps minDiskMB megabytes free on the file system holding
+// dir and minMemoryMB megabytes of memory available, notifying notifier, if enabled, when
+// it turns work away
+func NewGuard(dir string, minDiskMB, minMemoryMB int, notifier *notify.WebhookNotifier) *Guard {
+	g := &Guard{dir: dir, notifier: notifier, measure: Measure, lastAlerts: map[string]time.Time{}}
+	if minDiskMB > 0 {
+		g.minDisk = uint64(minDiskMB) << 20
+	}
+	if minMemoryMB > 0 {
+		g.minMemory = uint64(minMemoryMB) << 20
+	}
+	return g
+}
+
+// Usage returns the current disk space and memory
+func (g *Guard) Usage() Usage {
+	return g.measure(g.dir)
+}
+
+// Thresholds returns the disk space and memory, in bytes, the guard keeps free
+func (g *Guard) Thresholds() (disk, memory uint64) {
+	return g.minDisk, g.minMemory
+}
+
+// Check returns an *Error when taking on work that writes size bytes to disk would leave less
+// free space than the threshold, or when available memory is already below its threshold.
+// A resource the platform can't measure passes.
+func (g *Guard) Check(ctx context.Context, size int64) error {
+	if g == nil || (g.minDisk == 0 && g.minMemory == 0) {
+		return nil
+	}
+	if err := g.evaluate(g.measure(g.dir), size); err != nil {
+		return g.reject(ctx, err)
+	}
+	return nil
+}
+
+// Accepts reports whether work that writes nothing to disk would pass Check given usage
+func (g *Guard) Accepts(usage Usage) bool {
+	return g == nil || g.evaluate(usage, 0) == nil
+}
+
+// evaluate returns the resource usage falls short on, if any
+func (g *Guard) evaluate(usage Usage, size int64) *Error {
+	if g.minDisk > 0 && usage.DiskTotal > 0 {
+		required := g.minDisk
+		if size > 0 {
+			required += uint64(size)
+		}
+		if usage.DiskFree < required {
+			return &Error{Resource: ResourceDisk, Available: usage.DiskFree, Required: required}
+		}
+	}
+	if g.minMemory > 0 && usage.MemoryTotal > 0 && usage.MemoryAvailable < g.minMemory {
+		return &Error{Resource: ResourceMemory, Available: usage.MemoryAvailable, Required: g.minMemory}
+	}
+	return nil
+}
+
+// reject logs the error and notifies about it, at most once per AlertInterval per resource
+func (g *Guard) reject(ctx context.Context, err *Error) error {
+	g.mu.Lock()
+	due := time.Since(g.lastAlerts[err.Resource]) >= AlertInterval
+	if due {
+		g.lastAlerts[err.Resource] = time.Now()
+	}
+	g.mu.Unlock()
+	if !due {
+		return err
+	}
+
+	logger.WarnContext(ctx, "Rejecting work", "component", "resources", "resource", err.Resource, "error", err)
+	if g.notifier == nil || !g.notifier.Enabled() {
+		return err
+	}
+	event := notify.Event{
+		Type: "resources.low",
+		Data: map[string]string{
+			"resource":  err.Resource,
+			"available": strconv.FormatUint(err.Available, 10),
+			"required":  strconv.FormatUint(err.Required, 10),
+			"message":   err.Error(),
+		},
+	}
+	// The request being turned away shouldn't wait for the webhook
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if sendErr := g.notifier.Send(ctx, event); sendErr != nil {
+			logger.ErrorContext(ctx, "Failed to send low resource notification", "component", "resources", "resource", err.Resource, "error", sendErr)
+		}
+	}()
+	return err
+}
+
+// FormatBytes formats a byte count for people, e.g. "1.5 GB"
+func FormatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, exp := float64(n)/unit, 0
+	for value >= unit && exp < 4 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", value, "KMGTP"[exp])
+}
@@ -0,0 +1,79 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func fixedUsage(usage Usage) func(string) Usage {
+	return func(string) Usage { return usage }
+}
+
+func TestCheck(t *testing.T) {
+	guard := NewGuard("/data", 1024, 512, nil)
+	guard.measure = fixedUsage(Usage{DiskFree: 2 << 30, DiskTotal: 100 << 30, MemoryAvailable: 1 << 30, MemoryTotal: 8 << 30})
+	if err := guard.Check(context.Background(), 100<<20); err != nil {
+		t.Fatalf("expected enough room, got %v", err)
+	}
+
+	// 2 GB free, 1 GB kept free, so a 1.5 GB upload doesn't fit
+	err := guard.Check(context.Background(), 1536<<20)
+	var rerr *Error
+	if !errors.As(err, &rerr) || rerr.Resource != ResourceDisk {
+		t.Fatalf("expected a disk error, got %v", err)
+	}
+	if rerr.StatusCode() != http.StatusInsufficientStorage || rerr.Required != 2560<<20 {
+		t.Errorf("unexpected disk error %+v", rerr)
+	}
+	if got := rerr.Error(); got != "not enough free disk space: 2.0 GB free, 2.5 GB needed; delete old recordings or their audio, or free up space on the server" {
+		t.Errorf("unexpected message %q", got)
+	}
+
+	guard.measure = fixedUsage(Usage{DiskFree: 50 << 30, DiskTotal: 100 << 30, MemoryAvailable: 256 << 20, MemoryTotal: 8 << 30})
+	err = guard.Check(context.Background(), 0)
+	if !errors.As(err, &rerr) || rerr.Resource != ResourceMemory || rerr.StatusCode() != http.StatusTooManyRequests {
+		t.Fatalf("expected a memory error, got %v", err)
+	}
+}
+
+func TestCheckPassesWhatCantBeMeasured(t *testing.T) {
+	guard := NewGuard("/data", 1024, 512, nil)
+	guard.measure = fixedUsage(Usage{})
+	if err := guard.Check(context.Background(), 1<<40); err != nil {
+		t.Errorf("expected unmeasured resources to pass, got %v", err)
+	}
+
+	var disabled *Guard
+	if err := disabled.Check(context.Background(), 1<<40); err != nil {
+		t.Errorf("expected a nil guard to pass, got %v", err)
+	}
+	guard = NewGuard("/data", 0, 0, nil)
+	guard.measure = fixedUsage(Usage{DiskFree: 1, DiskTotal: 1 << 30})
+	if err := guard.Check(context.Background(), 1<<20); err != nil {
+		t.Errorf("expected zero thresholds to turn the checks off, got %v", err)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	usage := Measure(t.TempDir())
+	if usage.DiskTotal > 0 && usage.DiskFree > usage.DiskTotal {
+		t.Errorf("free space %d exceeds total %d", usage.DiskFree, usage.DiskTotal)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	cases := map[uint64]string{
+		512:           "512 B",
+		1536:          "1.5 KB",
+		5 << 20:       "5.0 MB",
+		3 << 30:       "3.0 GB",
+		1<<40 + 1<<39: "1.5 TB",
+	}
+	for n, want := range cases {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
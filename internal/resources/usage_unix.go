@@ -0,0 +1,17 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package resources
+
+import "syscall"
+
+// diskUsage returns the space available to unprivileged users and the total size of the file
+// system holding path
+func diskUsage(path string) (free, total uint64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	blockSize := uint64(stat.Bsize)
+	return stat.Bavail * blockSize, stat.Blocks * blockSize, nil
+}
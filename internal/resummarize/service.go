@@ -0,0 +1,352 @@
+// Package resummarize summarizes transcriptions again when their summaries were written by a
+// model other than the current default summary model, a batch at a time and throttled, and
+// records a report of each run. Any other model counts as older: the default is changed when
+// a better model becomes available.
+package resummarize
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/workflow"
+	"scriberr/pkg/logger"
+)
+
+const (
+	// DefaultBatchSize is how many transcriptions a run summarizes again by default
+	DefaultBatchSize = 20
+	// MaxBatchSize caps the transcriptions of one run
+	MaxBatchSize = 500
+	// DefaultDelay is the pause between two transcriptions of a run
+	DefaultDelay = 10 * time.Second
+
+	// compareExcerptLength caps the transcript shown to the LLM when comparing summaries, in characters
+	compareExcerptLength = 8000
+)
+
+// ErrRunInProgress is returned when a re-summarization run is already going
+var ErrRunInProgress = errors.New("re-summarization already in progress")
+
+// Options controls a re-summarization run
+type Options struct {
+	// BatchSize is the most transcriptions summarized again, oldest first
+	BatchSize int
+	// Delay is the pause between two transcriptions, to spare the LLM provider
+	Delay time.Duration
+	// IncludeUnknown also picks summaries whose model wasn't recorded
+	IncludeUnknown bool
+	// Compare has the LLM judge whether each new summary improves on the old one
+	Compare bool
+	// Trigger is recorded on the run, e.g. "schedule" or "api"
+	Trigger string
+}
+
+// Service summarizes outdated summaries again in the background
+type Service struct {
+	llm   workflow.LLMService
+	model string
+	rag   *rag.RAGService
+
+	mu      sync.Mutex
+	running bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// NewService creates a re-summarization service that rewrites summaries with model. With
+// ragService set, transcriptions already indexed are re-indexed with their new summary.
+func NewService(llmService workflow.LLMService, model string, ragService *rag.RAGService) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Service{llm: llmService, model: model, rag: ragService, ctx: ctx, cancel: cancel}
+}
+
+// Model returns the model summaries are rewritten with
+func (s *Service) Model() string {
+	return s.model
+}
+
+// Start runs a batch every interval, skipping intervals with nothing to do. An interval of 0
+// disables scheduled runs.
+func (s *Service) Start(interval time.Duration, opts Options) {
+	if interval <= 0 {
+		return
+	}
+	opts.Trigger = "schedule"
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				candidates, err := s.Candidates(1, opts.IncludeUnknown)
+				if err != nil {
+					logger.Error("Failed to find outdated summaries", "component", "resummarize", "error", err)
+					continue
+				}
+				if len(candidates) == 0 {
+					continue
+				}
+				if _, err := s.Run(s.ctx, opts); err != nil && !errors.Is(err, ErrRunInProgress) {
+					logger.Error("Scheduled run failed", "component", "resummarize", "error", err)
+				}
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends scheduled runs and interrupts a run in progress
+func (s *Service) Stop() {
+	s.cancel()
+}
+
+// IsRunning reports whether a run is in progress
+func (s *Service) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// Candidates returns up to limit completed transcriptions whose summary was written by a
+// model other than the current one, least recently updated first. Transcriptions under legal
+// hold are left alone.
+func (s *Service) Candidates(limit int, includeUnknown bool) ([]models.TranscriptionJob, error) {
+	query := database.DB.Where("status = ? AND summary IS NOT NULL AND summary <> '' AND legal_hold = ?", models.StatusCompleted, false)
+	if includeUnknown {
+		query = query.Where("summary_model IS NULL OR summary_model = '' OR summary_model <> ?", s.model)
+	} else {
+		query = query.Where("summary_model IS NOT NULL AND summary_model <> '' AND summary_model <> ?", s.model)
+	}
+	var jobs []models.TranscriptionJob
+	if err := query.Order("updated_at ASC").Limit(limit).Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to find outdated summaries: %w", err)
+	}
+	return jobs, nil
+}
+
+// RunInBackground starts a run and returns it without waiting for it to finish
+func (s *Service) RunInBackground(opts Options) (*models.ResummarizeRun, error) {
+	run, jobs, err := s.begin(opts)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := *run
+	go s.process(s.ctx, run, jobs, opts)
+	return &snapshot, nil
+}
+
+// Run summarizes a batch of outdated summaries again and returns the run's report
+func (s *Service) Run(ctx context.Context, opts Options) (*models.ResummarizeRun, error) {
+	run, jobs, err := s.begin(opts)
+	if err != nil {
+		return nil, err
+	}
+	s.process(ctx, run, jobs, opts)
+	return run, nil
+}
+
+// begin reserves the service for a run, picks its transcriptions and records it
+func (s *Service) begin(opts Options) (*models.ResummarizeRun, []models.TranscriptionJob, error) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, nil, ErrRunInProgress
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	jobs, err := s.Candidates(min(batchSize, MaxBatchSize), opts.IncludeUnknown)
+	if err != nil {
+		s.release()
+		return nil, nil, err
+	}
+	run := &models.ResummarizeRun{
+		Model:      s.model,
+		Status:     models.ResummarizeRunning,
+		Trigger:    opts.Trigger,
+		Candidates: len(jobs),
+		Results:    []models.ResummarizeResult{},
+		StartedAt:  time.Now(),
+	}
+	if err := database.DB.Create(run).Error; err != nil {
+		s.release()
+		return nil, nil, fmt.Errorf("failed to record re-summarization run: %w", err)
+	}
+	return run, jobs, nil
+}
+
+func (s *Service) release() {
+	s.mu.Lock()
+	s.running = false
+	s.mu.Unlock()
+}
+
+// process summarizes each job again, pausing between jobs, and saves the report as it goes
+func (s *Service) process(ctx context.Context, run *models.ResummarizeRun, jobs []models.TranscriptionJob, opts Options) {
+	defer s.release()
+
+	for i := range jobs {
+		if i > 0 && opts.Delay > 0 {
+			select {
+			case <-time.After(opts.Delay):
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			message := "interrupted: " + ctx.Err().Error()
+			run.Error = &message
+			break
+		}
+
+		result := s.resummarize(logger.WithJob(ctx, jobs[i].ID), &jobs[i], opts.Compare)
+		switch {
+		case result.Error != "":
+			run.Failed++
+			logger.Warn("Failed to summarize again", "component", "resummarize", "job_id", result.TranscriptionID, "run_id", run.ID, "error", result.Error)
+		default:
+			run.Resummarized++
+			if result.Verdict == models.VerdictImproved {
+				run.Improved++
+			}
+		}
+		run.Results = append(run.Results, result)
+		if err := database.DB.Save(run).Error; err != nil {
+			logger.Error("Failed to save run", "component", "resummarize", "run_id", run.ID, "error", err)
+		}
+	}
+
+	now := time.Now()
+	run.CompletedAt = &now
+	run.Status = models.ResummarizeCompleted
+	if run.Error != nil {
+		run.Status = models.ResummarizeFailed
+	}
+	if err := database.DB.Save(run).Error; err != nil {
+		logger.Error("Failed to save run", "component", "resummarize", "run_id", run.ID, "error", err)
+	}
+	logger.Info("Run finished", "component", "resummarize", "run_id", run.ID, "candidates", run.Candidates,
+		"resummarized", run.Resummarized, "improved", run.Improved, "failed", run.Failed)
+}
+
+// resummarize writes a job's summary again with the current model, in the format and with
+// the template of the summary it replaces
+func (s *Service) resummarize(ctx context.Context, job *models.TranscriptionJob, compare bool) models.ResummarizeResult {
+	old := *job.Summary
+	result := models.ResummarizeResult{
+		TranscriptionID: job.ID,
+		NewModel:        s.model,
+		OldLength:       len([]rune(old)),
+	}
+	if job.Title != nil {
+		result.Title = *job.Title
+	}
+	if job.SummaryModel != nil {
+		result.OldModel = *job.SummaryModel
+	}
+
+	transcript, err := workflow.TranscriptText(job)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	template, err := workflow.ResolveSummaryTemplate(job, "")
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	format := workflow.SummaryFormatText
+	if job.StructuredSummary != nil {
+		format = workflow.SummaryFormatStructured
+	}
+	summary, err := workflow.GenerateSummary(ctx, s.llm, job, transcript, workflow.SummaryOptions{
+		Model:       s.model,
+		Temperature: 0.7,
+		Format:      format,
+		Template:    template,
+		Source:      "resummarize",
+	})
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.NewLength = len([]rune(summary))
+
+	// The summary is embedded in the transcription's RAG entry; transcriptions that were
+	// never indexed are left that way
+	if s.rag != nil {
+		if indexed, err := s.rag.IsIndexed(job.ID); err != nil {
+			result.Error = "summary saved, but failed to check the RAG index: " + err.Error()
+			return result
+		} else if indexed {
+			if err := s.rag.StoreSummary(ctx, job.ID, summary, transcript); err != nil {
+				result.Error = "summary saved, but failed to re-index it: " + err.Error()
+				return result
+			}
+		}
+	}
+
+	if compare {
+		verdict, reason, err := s.compare(ctx, transcript, old, summary)
+		if err != nil {
+			// The new summary is kept; only the report lacks a verdict
+			result.Reason = "comparison failed: " + err.Error()
+		} else {
+			result.Verdict, result.Reason = verdict, reason
+		}
+	}
+	return result
+}
+
+// verdictSchema is the JSON Schema of the LLM's comparison of two summaries
+var verdictSchema = llm.Schema{
+	Name:        "summary_comparison",
+	Description: "Whether a new summary of a transcript is better than the old one",
+	Definition: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "verdict": {"type": "string", "enum": ["improved", "same", "worse"]},
+    "reason": {"type": "string", "description": "One sentence explaining the verdict"}
+  },
+  "required": ["verdict", "reason"]
+}`),
+}
+
+// compare asks the LLM whether the new summary covers the transcript better than the old one
+func (s *Service) compare(ctx context.Context, transcript, old, summary string) (string, string, error) {
+	excerpt := transcript
+	if len(excerpt) > compareExcerptLength {
+		excerpt = excerpt[:compareExcerptLength] + "... [truncated]"
+	}
+	prompt := fmt.Sprintf("Here is a transcript and two summaries of it.\n\nTranscript:\n%s\n\nOld summary:\n%s\n\nNew summary:\n%s\n\n"+
+		"Is the new summary more accurate, complete and clear than the old one? Answer improved, same or worse, with a one-sentence reason.",
+		excerpt, old, summary)
+	var reply struct {
+		Verdict string `json:"verdict"`
+		Reason  string `json:"reason"`
+	}
+	messages := []llm.ChatMessage{{Role: "user", Content: prompt}}
+	if err := llm.CompleteJSON(ctx, s.llm, s.model, messages, 0, verdictSchema, &reply); err != nil {
+		return "", "", err
+	}
+	verdict := strings.ToLower(strings.TrimSpace(reply.Verdict))
+	switch verdict {
+	case models.VerdictImproved, models.VerdictSame, models.VerdictWorse:
+	default:
+		return "", "", fmt.Errorf("unexpected verdict %q", reply.Verdict)
+	}
+	return verdict, strings.TrimSpace(reply.Reason), nil
+}
@@ -0,0 +1,213 @@
+// Package revisions corrects segments of stored transcripts, keeping what each correction
+// replaced as a TranscriptRevision. Corrections are made to the transcript JSON as stored, so
+// fields the transcription engine added are kept.
+package revisions
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/events"
+	"scriberr/internal/models"
+	"scriberr/internal/versions"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNoSegments is returned for transcripts stored as plain text, which have no segments to edit
+	ErrNoSegments = errors.New("transcript has no segments")
+	// ErrSegmentNotFound is returned for a segment index past the end of a transcript
+	ErrSegmentNotFound = errors.New("segment not found")
+)
+
+// Edit corrects the text, speaker or both of the segment at Segment
+type Edit struct {
+	Segment int
+	Text    *string
+	Speaker *string
+}
+
+// Apply makes edits to a job's JSON transcript, in order, and saves it as a new version along
+// with a revision for each edit that changed its segment, recording a transcript.edited event
+// for each. The job's transcript is updated in place. Edits that leave their segment as it was
+// are dropped, so the revisions returned may be fewer than the edits, or none.
+func Apply(job *models.TranscriptionJob, edits []Edit, editedBy *uint, source string) ([]models.TranscriptRevision, error) {
+	if job.Transcript == nil {
+		return nil, ErrNoSegments
+	}
+	transcript := *job.Transcript
+	var revisions []models.TranscriptRevision
+	for _, edit := range edits {
+		edited, revision, err := editSegment(transcript, edit.Segment, edit.Text, edit.Speaker)
+		if err != nil {
+			return nil, err
+		}
+		if revision != nil {
+			revision.TranscriptionID = job.ID
+			revision.EditedBy = editedBy
+			revision.Source = source
+			revisions = append(revisions, *revision)
+		}
+		transcript = edited
+	}
+	if len(revisions) == 0 {
+		return nil, nil
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := versions.Save(tx, job.ID, models.VersionKindTranscript, versions.Content{Text: transcript}, source, editedBy); err != nil {
+			return err
+		}
+		for i := range revisions {
+			revision := &revisions[i]
+			var count int64
+			if err := tx.Model(&models.TranscriptRevision{}).Where("transcription_id = ? AND segment_index = ?", job.ID, revision.SegmentIndex).Count(&count).Error; err != nil {
+				return err
+			}
+			revision.Revision = int(count) + 1
+			if err := tx.Create(revision).Error; err != nil {
+				return err
+			}
+			if err := events.Append(tx, models.EventTranscriptEdited, job.ID, job.UserID, map[string]interface{}{
+				"segment":   revision.SegmentIndex,
+				"revision":  revision.Revision,
+				"edited_by": revision.EditedBy,
+				"source":    source,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	job.Transcript = &transcript
+	return revisions, nil
+}
+
+// editSegment applies a correction to the segment at index of a JSON transcript, returning
+// the new transcript and a revision recording the change, or a nil revision when the segment
+// already read so. Word timings of a segment whose text changes are dropped, both its own and
+// those in word_segments, and a new speaker is given to its words. The transcript's full text
+// is rebuilt from the segments.
+func editSegment(transcript string, index int, text, speaker *string) (string, *models.TranscriptRevision, error) {
+	if !strings.HasPrefix(strings.TrimSpace(transcript), "{") {
+		return "", nil, ErrNoSegments
+	}
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(transcript), &top); err != nil {
+		return "", nil, err
+	}
+	var segments []map[string]json.RawMessage
+	if raw, ok := top["segments"]; ok {
+		if err := json.Unmarshal(raw, &segments); err != nil {
+			return "", nil, err
+		}
+	}
+	if len(segments) == 0 {
+		return "", nil, ErrNoSegments
+	}
+	if index >= len(segments) {
+		return "", nil, ErrSegmentNotFound
+	}
+
+	segment := segments[index]
+	var previousText string
+	var previousSpeaker *string
+	var start, end float64
+	for key, target := range map[string]interface{}{"text": &previousText, "speaker": &previousSpeaker, "start": &start, "end": &end} {
+		if raw, ok := segment[key]; ok {
+			if err := json.Unmarshal(raw, target); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+
+	textChanged := text != nil && *text != strings.TrimSpace(previousText)
+	speakerChanged := speaker != nil && (previousSpeaker == nil || *speaker != *previousSpeaker)
+	if !textChanged && !speakerChanged {
+		return transcript, nil, nil
+	}
+	revision := &models.TranscriptRevision{
+		SegmentIndex:    index,
+		PreviousText:    previousText,
+		Text:            previousText,
+		PreviousSpeaker: previousSpeaker,
+		Speaker:         previousSpeaker,
+	}
+	if textChanged {
+		revision.Text = *text
+		segment["text"], _ = json.Marshal(*text)
+		delete(segment, "words")
+	}
+	if speakerChanged {
+		revision.Speaker = speaker
+		segment["speaker"], _ = json.Marshal(*speaker)
+		if raw, ok := segment["words"]; ok {
+			words, err := relabelWords(raw, *speaker)
+			if err != nil {
+				return "", nil, err
+			}
+			segment["words"] = words
+		}
+	}
+
+	if raw, ok := top["word_segments"]; ok && end > start {
+		var words []map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &words); err != nil {
+			return "", nil, err
+		}
+		kept := make([]map[string]json.RawMessage, 0, len(words))
+		for _, word := range words {
+			var wordStart, wordEnd float64
+			json.Unmarshal(word["start"], &wordStart)
+			json.Unmarshal(word["end"], &wordEnd)
+			// Words belong to the segment their middle falls in
+			if middle := (wordStart + wordEnd) / 2; middle >= start && middle < end {
+				if textChanged {
+					continue
+				}
+				word["speaker"], _ = json.Marshal(*speaker)
+			}
+			kept = append(kept, word)
+		}
+		top["word_segments"], _ = json.Marshal(kept)
+	}
+
+	if textChanged {
+		if _, ok := top["text"]; ok {
+			parts := make([]string, 0, len(segments))
+			for _, s := range segments {
+				var part string
+				json.Unmarshal(s["text"], &part)
+				if part = strings.TrimSpace(part); part != "" {
+					parts = append(parts, part)
+				}
+			}
+			top["text"], _ = json.Marshal(strings.Join(parts, " "))
+		}
+	}
+	top["segments"], _ = json.Marshal(segments)
+	data, err := json.Marshal(top)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(data), revision, nil
+}
+
+// relabelWords gives every word of a JSON word list a new speaker
+func relabelWords(raw json.RawMessage, speaker string) (json.RawMessage, error) {
+	var words []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &words); err != nil {
+		return nil, err
+	}
+	label, _ := json.Marshal(speaker)
+	for _, word := range words {
+		word["speaker"] = label
+	}
+	return json.Marshal(words)
+}
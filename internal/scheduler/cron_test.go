@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2026, time.March, 4, 10, 17, 30, 0, time.UTC)
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, time.March, 4, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, time.March, 4, 10, 30, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2026, time.March, 5, 3, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, time.March, 4, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 9 * * 1-5", time.Date(2026, time.March, 5, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 12 29 2 *", time.Date(2028, time.February, 29, 12, 0, 0, 0, time.UTC)},
+		// Either day field matches when both are restricted
+		{"0 0 15 * 5", time.Date(2026, time.March, 6, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		cron, err := ParseCron(tc.expr)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.expr, err)
+		}
+		if got := cron.Next(from); !got.Equal(tc.want) {
+			t.Errorf("%q: expected %v, got %v", tc.expr, tc.want, got)
+		}
+	}
+}
+
+func TestCronNeverMatching(t *testing.T) {
+	cron, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next := cron.Next(time.Now()); !next.IsZero() {
+		t.Errorf("expected no match for February 30, got %v", next)
+	}
+}
+
+func TestParseCronRejectsInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@often"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("expected %q to be rejected", expr)
+		}
+	}
+}
+
+func TestParams(t *testing.T) {
+	params := map[string]interface{}{"days": float64(30), "fraction": 1.5, "flag": false, "text": "yes"}
+	if days, err := IntParam(params, "days", 0); err != nil || days != 30 {
+		t.Errorf("expected 30, got %d (%v)", days, err)
+	}
+	if days, err := IntParam(params, "missing", 7); err != nil || days != 7 {
+		t.Errorf("expected the fallback, got %d (%v)", days, err)
+	}
+	if _, err := IntParam(params, "fraction", 0); err == nil {
+		t.Error("expected an error for a fraction")
+	}
+	if fraction, err := FloatParam(params, "fraction", 0); err != nil || fraction != 1.5 {
+		t.Errorf("expected 1.5, got %v (%v)", fraction, err)
+	}
+	if _, err := FloatParam(params, "text", 0); err == nil {
+		t.Error("expected an error for a string number")
+	}
+	if flag, err := BoolParam(params, "flag", true); err != nil || flag {
+		t.Errorf("expected false, got %v (%v)", flag, err)
+	}
+	if _, err := BoolParam(params, "text", true); err == nil {
+		t.Error("expected an error for a string flag")
+	}
+}
@@ -0,0 +1,311 @@
+// Package scheduler runs maintenance actions, such as dropzone scans, RAG backfills and
+// retention cleanups, on cron schedules stored in the database, and keeps a history of runs.
+// Actions are registered by name by the packages that implement them.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+)
+
+// CheckInterval is how often the scheduler looks for schedules that are due
+const CheckInterval = 30 * time.Second
+
+// Triggers recorded on runs
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+var (
+	// ErrUnknownAction is returned for a schedule naming an action that isn't registered
+	ErrUnknownAction = errors.New("unknown action")
+	// ErrRunInProgress is returned when a schedule's previous run hasn't finished
+	ErrRunInProgress = errors.New("schedule is already running")
+)
+
+// Action is a job a schedule can run
+type Action struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Run does the work and returns a summary of it for the run history, such as counts
+	Run func(ctx context.Context, params map[string]interface{}) (map[string]interface{}, error) `json:"-"`
+	// Validate checks a schedule's params when it is saved; nil accepts any
+	Validate func(params map[string]interface{}) error `json:"-"`
+}
+
+// Service runs schedules as they come due
+type Service struct {
+	mu      sync.Mutex
+	actions map[string]Action
+	running map[string]bool // Schedules with a run in progress
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewService creates a scheduler with no actions registered
+func NewService() *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Service{
+		actions: make(map[string]Action),
+		running: make(map[string]bool),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Register makes an action available to schedules, replacing any of the same name
+func (s *Service) Register(action Action) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.actions[action.Name] = action
+}
+
+// Actions returns the registered actions by name
+func (s *Service) Actions() []Action {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	actions := make([]Action, 0, len(s.actions))
+	for _, action := range s.actions {
+		actions = append(actions, action)
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i].Name < actions[j].Name })
+	return actions
+}
+
+func (s *Service) action(name string) (Action, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	action, ok := s.actions[name]
+	return action, ok
+}
+
+// Prepare checks a schedule before it is saved and sets when it next runs
+func (s *Service) Prepare(schedule *models.Schedule) error {
+	action, ok := s.action(schedule.Action)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownAction, schedule.Action)
+	}
+	cron, err := ParseCron(schedule.Cron)
+	if err != nil {
+		return err
+	}
+	if action.Validate != nil {
+		if err := action.Validate(schedule.Params); err != nil {
+			return err
+		}
+	}
+	schedule.NextRunAt = nil
+	if schedule.Enabled {
+		next := cron.Next(time.Now())
+		if next.IsZero() {
+			return fmt.Errorf("cron expression %q never matches", schedule.Cron)
+		}
+		schedule.NextRunAt = &next
+	}
+	return nil
+}
+
+// Start checks for due schedules every interval until Stop. Runs left unfinished by a
+// restart are marked failed first.
+func (s *Service) Start(interval time.Duration) {
+	if err := database.DB.Model(&models.ScheduleRun{}).Where("status = ?", models.ScheduleRunRunning).
+		Updates(map[string]interface{}{"status": models.ScheduleRunFailed, "error": "interrupted by a restart", "finished_at": time.Now()}).Error; err != nil {
+		logger.Error("Failed to close interrupted schedule runs", "component", "scheduler", "error", err)
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.RunDue(time.Now())
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends scheduling, interrupts runs in progress and waits for them to return
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// RunDue starts every enabled schedule due at now. A schedule missed while the server was
+// down runs once, then keeps to its expression.
+func (s *Service) RunDue(now time.Time) {
+	var due []models.Schedule
+	if err := database.DB.Where("enabled = ? AND next_run_at <= ?", true, now).Find(&due).Error; err != nil {
+		logger.Error("Failed to find due schedules", "component", "scheduler", "error", err)
+		return
+	}
+	for i := range due {
+		schedule := &due[i]
+		// The next run is set before this one starts, so a long run isn't started twice
+		var next *time.Time
+		if cron, err := ParseCron(schedule.Cron); err != nil {
+			logger.Warn("Disabling schedule with an invalid cron", "component", "scheduler", "schedule_id", schedule.ID, "cron", schedule.Cron, "error", err)
+		} else if at := cron.Next(now); !at.IsZero() {
+			next = &at
+		}
+		updates := map[string]interface{}{"next_run_at": next}
+		if next == nil {
+			updates["enabled"] = false
+		}
+		if err := database.DB.Model(schedule).Updates(updates).Error; err != nil {
+			logger.Error("Failed to reschedule", "component", "scheduler", "schedule_id", schedule.ID, "error", err)
+			continue
+		}
+		if _, err := s.Trigger(schedule, TriggerSchedule); err != nil {
+			logger.Warn("Schedule not started", "component", "scheduler", "schedule_id", schedule.ID, "name", schedule.Name, "action", schedule.Action, "error", err)
+		}
+	}
+}
+
+// Trigger starts a run of a schedule's action in the background and returns it
+func (s *Service) Trigger(schedule *models.Schedule, trigger string) (*models.ScheduleRun, error) {
+	action, ok := s.action(schedule.Action)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAction, schedule.Action)
+	}
+	s.mu.Lock()
+	if s.running[schedule.ID] {
+		s.mu.Unlock()
+		return nil, ErrRunInProgress
+	}
+	s.running[schedule.ID] = true
+	s.mu.Unlock()
+
+	run := &models.ScheduleRun{
+		ScheduleID: schedule.ID,
+		Action:     schedule.Action,
+		Trigger:    trigger,
+		Status:     models.ScheduleRunRunning,
+		StartedAt:  time.Now(),
+	}
+	if err := database.DB.Create(run).Error; err != nil {
+		s.finish(schedule.ID)
+		return nil, fmt.Errorf("failed to record run: %w", err)
+	}
+
+	s.wg.Add(1)
+	go func(run models.ScheduleRun) {
+		defer s.wg.Done()
+		defer s.finish(schedule.ID)
+		s.execute(action, schedule, &run)
+	}(*run)
+	return run, nil
+}
+
+// finish marks a schedule as no longer running
+func (s *Service) finish(scheduleID string) {
+	s.mu.Lock()
+	delete(s.running, scheduleID)
+	s.mu.Unlock()
+}
+
+// execute runs an action and records the outcome on the run and its schedule
+func (s *Service) execute(action Action, schedule *models.Schedule, run *models.ScheduleRun) {
+	result, err := runAction(s.ctx, action, schedule.Params)
+	finished := time.Now()
+	run.FinishedAt = &finished
+	run.Result = result
+	run.Status = models.ScheduleRunCompleted
+	if err != nil {
+		message := err.Error()
+		run.Status = models.ScheduleRunFailed
+		run.Error = &message
+		logger.ErrorContext(s.ctx, "Schedule run failed", "component", "scheduler", "schedule_id", schedule.ID, "name", schedule.Name, "action", schedule.Action, "error", err)
+	}
+	if err := database.DB.Save(run).Error; err != nil {
+		logger.ErrorContext(s.ctx, "Failed to record schedule run", "component", "scheduler", "run_id", run.ID, "error", err)
+	}
+	if err := database.DB.Model(&models.Schedule{}).Where("id = ?", schedule.ID).
+		Updates(map[string]interface{}{"last_run_at": run.StartedAt, "last_status": run.Status}).Error; err != nil {
+		logger.ErrorContext(s.ctx, "Failed to update schedule", "component", "scheduler", "schedule_id", schedule.ID, "error", err)
+	}
+}
+
+// runAction runs an action, turning a panic into an error so a faulty action can't take the
+// scheduler down
+func runAction(ctx context.Context, action Action, params map[string]interface{}) (result map[string]interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("action panicked: %v", r)
+		}
+	}()
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	return action.Run(ctx, params)
+}
+
+// IsRunning reports whether a schedule has a run in progress
+func (s *Service) IsRunning(scheduleID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running[scheduleID]
+}
+
+// IntParam reads a whole number from a schedule's params, fallback when absent
+func IntParam(params map[string]interface{}, name string, fallback int) (int, error) {
+	value, ok := params[name]
+	if !ok || value == nil {
+		return fallback, nil
+	}
+	switch v := value.(type) {
+	case float64:
+		if v != float64(int(v)) {
+			return 0, fmt.Errorf("%s must be a whole number", name)
+		}
+		return int(v), nil
+	case int:
+		return v, nil
+	default:
+		return 0, fmt.Errorf("%s must be a number", name)
+	}
+}
+
+// FloatParam reads a number from a schedule's params, fallback when absent
+func FloatParam(params map[string]interface{}, name string, fallback float64) (float64, error) {
+	value, ok := params[name]
+	if !ok || value == nil {
+		return fallback, nil
+	}
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("%s must be a number", name)
+	}
+}
+
+// BoolParam reads a flag from a schedule's params, fallback when absent
+func BoolParam(params map[string]interface{}, name string, fallback bool) (bool, error) {
+	value, ok := params[name]
+	if !ok || value == nil {
+		return fallback, nil
+	}
+	flag, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s must be true or false", name)
+	}
+	return flag, nil
+}
@@ -0,0 +1,205 @@
+// Package search finds the words and phrases of transcripts with the SQLite FTS5 index the
+// database keeps of every transcript's segments. It complements the semantic search of the
+// RAG index: exact terms, names and numbers are found quickly, without embeddings.
+package search
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+	"unicode"
+
+	"scriberr/internal/database"
+	"scriberr/internal/folders"
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrEmptyQuery is returned for a query without a word to search for
+var ErrEmptyQuery = errors.New("the query has no words to search for")
+
+// Options narrows a search
+type Options struct {
+	Query         string // Words, "quoted phrases", prefix* terms, OR between alternatives and -excluded terms
+	Speaker       string // Label or custom name of the speaker
+	Tags          []string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Limit         int
+	Offset        int
+}
+
+// Hit is a transcript segment that matches a search
+type Hit struct {
+	TranscriptionID string    `json:"transcription_id"`
+	Title           *string   `json:"title,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	Start           *float64  `json:"start,omitempty"` // Seconds into the recording; unset for transcripts without times
+	End             *float64  `json:"end,omitempty"`
+	Speaker         string    `json:"speaker,omitempty"`
+	SpeakerName     string    `json:"speaker_name,omitempty"` // Custom name mapped to the speaker label, if any
+	Snippet         string    `json:"snippet"`                // HTML-escaped text around the match, with the matched terms in <mark>
+	Score           float64   `json:"score"`                  // bm25 relevance, lower is better
+}
+
+// Snippet markers, which can't occur in transcripts and are replaced after escaping
+const (
+	markStart = "\x02"
+	markEnd   = "\x03"
+)
+
+// snippetTokens is the length of a snippet in words
+const snippetTokens = 24
+
+// Transcripts returns the transcript segments of the user's transcriptions that match the
+// search, best match first, and how many match in all
+func Transcripts(userID *uint, opts Options) ([]Hit, int64, error) {
+	match, err := MatchQuery(opts.Query)
+	if err != nil {
+		return nil, 0, err
+	}
+	query := func() *gorm.DB {
+		query := database.DB.Table("transcript_search").
+			Joins("JOIN transcript_segments ON transcript_segments.id = transcript_search.rowid").
+			Joins("JOIN transcription_jobs ON transcription_jobs.id = transcript_segments.job_id").
+			Joins("LEFT JOIN speaker_mappings ON speaker_mappings.transcription_job_id = transcript_segments.job_id AND speaker_mappings.original_speaker = transcript_segments.speaker").
+			Where("transcript_search MATCH ?", match)
+		if userID == nil {
+			query = query.Where("transcription_jobs.user_id IS NULL")
+		} else {
+			query = query.Where("transcription_jobs.user_id = ?", *userID)
+		}
+		if opts.Speaker != "" {
+			query = query.Where("(lower(transcript_segments.speaker) = lower(?) OR lower(speaker_mappings.custom_name) = lower(?))", opts.Speaker, opts.Speaker)
+		}
+		return folders.Apply(query, models.SmartFolderFilter{
+			Tags:          folders.NormalizeTerms(opts.Tags),
+			CreatedAfter:  opts.CreatedAfter,
+			CreatedBefore: opts.CreatedBefore,
+		})
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count matches: %w", err)
+	}
+	hits := []Hit{}
+	err = query().Select(fmt.Sprintf(`transcript_segments.job_id AS transcription_id, transcription_jobs.title, transcription_jobs.created_at,
+		transcript_segments.start_time AS "start", transcript_segments.end_time AS "end", transcript_segments.speaker,
+		speaker_mappings.custom_name AS speaker_name,
+		snippet(transcript_search, 0, char(2), char(3), '…', %d) AS snippet, transcript_search.rank AS score`, snippetTokens)).
+		Order("transcript_search.rank, transcription_jobs.created_at DESC, transcript_segments.position").
+		Limit(opts.Limit).Offset(opts.Offset).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search transcripts: %w", err)
+	}
+	for i := range hits {
+		hits[i].Snippet = highlight(hits[i].Snippet)
+	}
+	return hits, total, nil
+}
+
+// highlight escapes a snippet for HTML and marks its matched terms
+func highlight(snippet string) string {
+	return strings.NewReplacer(markStart, "<mark>", markEnd, "</mark>").Replace(html.EscapeString(snippet))
+}
+
+// MatchQuery turns a search query into an FTS5 query. Words and "quoted phrases" must all
+// occur, a word ending in * matches words starting with it, OR between two terms matches
+// either, and a term starting with - must not occur. Everything else is taken literally, so
+// no query is an FTS5 syntax error.
+func MatchQuery(query string) (string, error) {
+	var terms, excluded []string
+	or := false
+	for _, token := range tokenize(query) {
+		if token.text == "OR" && !token.quoted {
+			or = len(terms) > 0
+			continue
+		}
+		term := token.text
+		if !token.quoted {
+			term = strings.TrimSuffix(term, "*")
+		}
+		if !hasWord(term) {
+			continue
+		}
+		term = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+		if !token.quoted && strings.HasSuffix(token.text, "*") {
+			term += "*"
+		}
+		switch {
+		case token.excluded:
+			excluded = append(excluded, term)
+		case or:
+			terms[len(terms)-1] += " OR " + term
+		default:
+			terms = append(terms, term)
+		}
+		or = false
+	}
+	if len(terms) == 0 {
+		return "", ErrEmptyQuery
+	}
+
+	// OR joins just the terms on either side of it
+	for i, term := range terms {
+		if strings.Contains(term, " OR ") {
+			terms[i] = "(" + term + ")"
+		}
+	}
+	match := strings.Join(terms, " ")
+	for _, term := range excluded {
+		match = "(" + match + ") NOT " + term
+	}
+	return match, nil
+}
+
+// token is a word or quoted phrase of a query
+type token struct {
+	text     string
+	quoted   bool
+	excluded bool
+}
+
+// tokenize splits a query into words and quoted phrases; an unclosed quote runs to the end
+func tokenize(query string) []token {
+	var tokens []token
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+		t := token{}
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			t.excluded = true
+			i++
+		}
+		if runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			t.text, t.quoted = string(runes[i+1:end]), true
+			i = end + 1
+		} else {
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) {
+				end++
+			}
+			t.text = string(runes[i:end])
+			i = end
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens
+}
+
+// hasWord reports whether text has a letter or digit for the index to match
+func hasWord(text string) bool {
+	return strings.IndexFunc(text, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsNumber(r) }) >= 0
+}
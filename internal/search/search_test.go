@@ -0,0 +1,41 @@
+package search
+
+import "testing"
+
+func TestMatchQuery(t *testing.T) {
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"budget review", `"budget" "review"`},
+		{`"quarterly budget" Q3`, `"quarterly budget" "Q3"`},
+		{"forecast* OR estimate plan", `("forecast"* OR "estimate") "plan"`},
+		{"launch -delay -\"slipped date\"", `(("launch") NOT "delay") NOT "slipped date"`},
+		{`say "hi`, `"say" "hi"`},
+		{`a"b (c) OR`, `"a""b" "(c)"`},
+		{"OR budget", `"budget"`},
+		{"- budget ...", `"budget"`},
+	}
+	for _, tc := range cases {
+		got, err := MatchQuery(tc.query)
+		if err != nil {
+			t.Fatalf("MatchQuery(%q): %v", tc.query, err)
+		}
+		if got != tc.want {
+			t.Errorf("MatchQuery(%q) = %s, want %s", tc.query, got, tc.want)
+		}
+	}
+
+	for _, query := range []string{"", "   ", "-budget", `"" ... OR *`} {
+		if _, err := MatchQuery(query); err != ErrEmptyQuery {
+			t.Errorf("MatchQuery(%q) err = %v, want ErrEmptyQuery", query, err)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	got := highlight("…the \x02<b>budget</b>\x03 & more")
+	if got != "…the <mark>&lt;b&gt;budget&lt;/b&gt;</mark> &amp; more" {
+		t.Fatalf("highlight = %q", got)
+	}
+}
@@ -0,0 +1,365 @@
+// Package speakers names the speakers diarization finds in a recording after the people they
+// sound like. A known person has a SpeakerProfile holding the average of their voice
+// embeddings; each speaker of a new recording is matched to the closest of its owner's
+// profiles, and a person confirming or correcting a match teaches the profile their voice.
+package speakers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// DefaultThreshold is the cosine similarity a speaker's voice needs to a profile to be named
+// after it
+const DefaultThreshold = 0.6
+
+// ErrNothingToConfirm is returned when confirming a speaker that wasn't matched to a profile
+var ErrNothingToConfirm = errors.New("the speaker was not matched to a profile")
+
+// Matcher names the speakers of diarized recordings from their voices
+type Matcher struct {
+	threshold float64
+}
+
+// NewMatcher creates a matcher naming speakers whose voice has at least the given similarity
+// to a profile; 0 uses DefaultThreshold
+func NewMatcher(threshold float64) *Matcher {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	return &Matcher{threshold: threshold}
+}
+
+// IdentifySpeakers records the speakers of a job with their voice embeddings and names those
+// that sound like one of the job owner's profiles, each profile naming at most one speaker.
+// Names given by hand are left alone; names from an earlier automatic match are replaced.
+func (m *Matcher) IdentifySpeakers(jobID string, embeddings map[string][]float32) error {
+	var job models.TranscriptionJob
+	if err := database.DB.Select("id", "user_id").Where("id = ?", jobID).First(&job).Error; err != nil {
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+	var profiles []models.SpeakerProfile
+	if err := ownedBy(database.DB, job.UserID).Find(&profiles).Error; err != nil {
+		return fmt.Errorf("failed to get speaker profiles: %w", err)
+	}
+
+	labels := make([]string, 0, len(embeddings))
+	for label := range embeddings {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	speakers := make(map[string]*models.JobSpeaker, len(labels))
+	for _, label := range labels {
+		speakers[label] = &models.JobSpeaker{
+			TranscriptionJobID: jobID,
+			Speaker:            label,
+			Embedding:          embeddings[label],
+			Status:             models.JobSpeakerUnmatched,
+		}
+	}
+
+	byID := map[string]*models.SpeakerProfile{}
+	for i := range profiles {
+		byID[profiles[i].ID] = &profiles[i]
+	}
+	for _, match := range m.match(embeddings, profiles) {
+		speaker := speakers[match.label]
+		speaker.SpeakerProfileID = &match.profileID
+		speaker.Similarity = match.similarity
+		speaker.Status = models.JobSpeakerMatched
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		// Drop what an earlier run of this job decided automatically
+		var previous []models.JobSpeaker
+		if err := tx.Where("transcription_job_id = ? AND status = ?", jobID, models.JobSpeakerMatched).Find(&previous).Error; err != nil {
+			return err
+		}
+		for _, speaker := range previous {
+			if err := tx.Where("transcription_job_id = ? AND original_speaker = ?", jobID, speaker.Speaker).Delete(&models.SpeakerMapping{}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("transcription_job_id = ?", jobID).Delete(&models.JobSpeaker{}).Error; err != nil {
+			return err
+		}
+
+		for _, label := range labels {
+			speaker := speakers[label]
+			if err := tx.Create(speaker).Error; err != nil {
+				return err
+			}
+			if speaker.SpeakerProfileID == nil {
+				continue
+			}
+			var count int64
+			if err := tx.Model(&models.SpeakerMapping{}).Where("transcription_job_id = ? AND original_speaker = ?", jobID, label).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				continue // Named by hand
+			}
+			name := byID[*speaker.SpeakerProfileID].Name
+			if err := tx.Create(&models.SpeakerMapping{TranscriptionJobID: jobID, OriginalSpeaker: label, CustomName: name}).Error; err != nil {
+				return err
+			}
+			logger.Info("Identified speaker", "job_id", jobID, "speaker", label, "name", name, "similarity", speaker.Similarity)
+		}
+		return nil
+	})
+}
+
+// match is a speaker label paired with a profile
+type match struct {
+	label      string
+	profileID  string
+	similarity float64
+}
+
+// match pairs speakers with the profiles they sound most like, best pairs first, leaving out
+// pairs below the threshold and using each speaker and profile once
+func (m *Matcher) match(embeddings map[string][]float32, profiles []models.SpeakerProfile) []match {
+	var candidates []match
+	for label, embedding := range embeddings {
+		for _, profile := range profiles {
+			if similarity := Similarity(embedding, profile.Embedding); similarity >= m.threshold {
+				candidates = append(candidates, match{label: label, profileID: profile.ID, similarity: similarity})
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].similarity != candidates[j].similarity {
+			return candidates[i].similarity > candidates[j].similarity
+		}
+		if candidates[i].label != candidates[j].label {
+			return candidates[i].label < candidates[j].label
+		}
+		return candidates[i].profileID < candidates[j].profileID
+	})
+
+	var matches []match
+	usedLabels, usedProfiles := map[string]bool{}, map[string]bool{}
+	for _, candidate := range candidates {
+		if usedLabels[candidate.label] || usedProfiles[candidate.profileID] {
+			continue
+		}
+		usedLabels[candidate.label] = true
+		usedProfiles[candidate.profileID] = true
+		matches = append(matches, candidate)
+	}
+	return matches
+}
+
+// Assign records that a speaker of a job is the person of a profile, naming them after it in
+// the job's transcript. The speaker's voice is added to the profile, and taken out of the
+// profile they were confirmed as before, if any.
+func Assign(speaker *models.JobSpeaker, profile *models.SpeakerProfile) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		confirmedAs := ""
+		if speaker.Status == models.JobSpeakerConfirmed && speaker.SpeakerProfileID != nil {
+			confirmedAs = *speaker.SpeakerProfileID
+		}
+		if confirmedAs != profile.ID {
+			if confirmedAs != "" {
+				var previous models.SpeakerProfile
+				if err := tx.Where("id = ?", confirmedAs).First(&previous).Error; err == nil {
+					previous.Embedding, previous.SampleCount = unlearn(previous.Embedding, previous.SampleCount, speaker.Embedding)
+					if err := tx.Save(&previous).Error; err != nil {
+						return err
+					}
+				} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+			}
+			profile.Embedding, profile.SampleCount = learn(profile.Embedding, profile.SampleCount, speaker.Embedding)
+			if err := tx.Save(profile).Error; err != nil {
+				return err
+			}
+		}
+
+		speaker.SpeakerProfileID = &profile.ID
+		speaker.SpeakerProfile = nil
+		speaker.Similarity = Similarity(speaker.Embedding, profile.Embedding)
+		speaker.Status = models.JobSpeakerConfirmed
+		if err := tx.Save(speaker).Error; err != nil {
+			return err
+		}
+		return setMapping(tx, speaker.TranscriptionJobID, speaker.Speaker, profile.Name)
+	})
+}
+
+// Confirm records that a speaker matched automatically is the person of their profile
+func Confirm(speaker *models.JobSpeaker) error {
+	if speaker.SpeakerProfileID == nil {
+		return ErrNothingToConfirm
+	}
+	var profile models.SpeakerProfile
+	if err := database.DB.Where("id = ?", *speaker.SpeakerProfileID).First(&profile).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNothingToConfirm
+		}
+		return err
+	}
+	return Assign(speaker, &profile)
+}
+
+// FindOrCreateProfile returns the owner's profile with the given name, ignoring case, creating
+// one without a voice when there is none
+func FindOrCreateProfile(userID *uint, name string) (*models.SpeakerProfile, error) {
+	var profile models.SpeakerProfile
+	err := ownedBy(database.DB, userID).Where("LOWER(name) = ?", strings.ToLower(name)).First(&profile).Error
+	if err == nil {
+		return &profile, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	profile = models.SpeakerProfile{UserID: userID, Name: name}
+	if err := database.DB.Create(&profile).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// Rename renames a profile and the speakers named after it, returning the jobs whose
+// speakers were renamed
+func Rename(profile *models.SpeakerProfile, name string) ([]string, error) {
+	var jobIDs []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		oldName := profile.Name
+		profile.Name = name
+		if err := tx.Save(profile).Error; err != nil {
+			return err
+		}
+		var speakers []models.JobSpeaker
+		if err := tx.Where("speaker_profile_id = ?", profile.ID).Find(&speakers).Error; err != nil {
+			return err
+		}
+		for _, speaker := range speakers {
+			result := tx.Model(&models.SpeakerMapping{}).
+				Where("transcription_job_id = ? AND original_speaker = ? AND custom_name = ?", speaker.TranscriptionJobID, speaker.Speaker, oldName).
+				Update("custom_name", name)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				jobIDs = append(jobIDs, speaker.TranscriptionJobID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobIDs, nil
+}
+
+// Delete removes a profile. The speakers it named keep their names but are no longer linked
+// to anyone.
+func Delete(profile *models.SpeakerProfile) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.JobSpeaker{}).Where("speaker_profile_id = ?", profile.ID).
+			Updates(map[string]interface{}{"speaker_profile_id": nil, "similarity": 0, "status": models.JobSpeakerUnmatched}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(profile).Error
+	})
+}
+
+// setMapping names a speaker of a job in its transcript
+func setMapping(tx *gorm.DB, jobID, label, name string) error {
+	var mapping models.SpeakerMapping
+	err := tx.Where("transcription_job_id = ? AND original_speaker = ?", jobID, label).First(&mapping).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return tx.Create(&models.SpeakerMapping{TranscriptionJobID: jobID, OriginalSpeaker: label, CustomName: name}).Error
+	}
+	if err != nil {
+		return err
+	}
+	mapping.CustomName = name
+	return tx.Save(&mapping).Error
+}
+
+// ownedBy limits a query on speaker profiles to a user's
+func ownedBy(db *gorm.DB, userID *uint) *gorm.DB {
+	if userID == nil {
+		return db.Where("user_id IS NULL")
+	}
+	return db.Where("user_id = ?", *userID)
+}
+
+// Similarity is the cosine similarity of two voice embeddings, 0 when they can't be compared
+func Similarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// learn adds a voice to the average of count others. Voices are scaled to unit length first, so
+// loud and quiet recordings count the same.
+func learn(average []float32, count int, voice []float32) ([]float32, int) {
+	unit := normalize(voice)
+	if unit == nil {
+		return average, count
+	}
+	if count == 0 || len(average) != len(unit) {
+		return unit, 1
+	}
+	result := make([]float32, len(unit))
+	for i := range unit {
+		result[i] = (average[i]*float32(count) + unit[i]) / float32(count+1)
+	}
+	return result, count + 1
+}
+
+// unlearn takes a voice added by learn back out of the average
+func unlearn(average []float32, count int, voice []float32) ([]float32, int) {
+	unit := normalize(voice)
+	if unit == nil || len(average) != len(unit) || count == 0 {
+		return average, count
+	}
+	if count == 1 {
+		return nil, 0
+	}
+	result := make([]float32, len(unit))
+	for i := range unit {
+		result[i] = (average[i]*float32(count) - unit[i]) / float32(count-1)
+	}
+	return result, count - 1
+}
+
+// normalize scales an embedding to unit length, returning nil for one that can't be
+func normalize(embedding []float32) []float32 {
+	var norm float64
+	for _, v := range embedding {
+		norm += float64(v) * float64(v)
+	}
+	if norm == 0 {
+		return nil
+	}
+	norm = math.Sqrt(norm)
+	result := make([]float32, len(embedding))
+	for i, v := range embedding {
+		result[i] = float32(float64(v) / norm)
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+package speakers
+
+import (
+	"math"
+	"testing"
+
+	"scriberr/internal/models"
+)
+
+func TestMatchUsesEachProfileOnce(t *testing.T) {
+	embeddings := map[string][]float32{
+		"SPEAKER_00": {1, 0.1, 0},
+		"SPEAKER_01": {1, 0, 0.1},
+		"SPEAKER_02": {0, 0, 1},
+	}
+	profiles := []models.SpeakerProfile{
+		{ID: "alice", Embedding: []float32{1, 0, 0}},
+		{ID: "bob", Embedding: []float32{0, 1, 0}},
+		{ID: "short", Embedding: []float32{1, 0}}, // From another model; never compared
+	}
+
+	matches := NewMatcher(0.9).match(embeddings, profiles)
+	if len(matches) != 1 {
+		t.Fatalf("expected one match, got %+v", matches)
+	}
+	// Both sound like Alice equally; the tie goes to the first label
+	if matches[0].label != "SPEAKER_00" || matches[0].profileID != "alice" {
+		t.Errorf("unexpected match %+v", matches[0])
+	}
+}
+
+func TestLearnAndUnlearnAverageUnitVoices(t *testing.T) {
+	average, count := learn(nil, 0, []float32{3, 4})
+	if count != 1 || !near(average, []float32{0.6, 0.8}) {
+		t.Fatalf("first voice: got %v (%d)", average, count)
+	}
+	average, count = learn(average, count, []float32{0, 10})
+	if count != 2 || !near(average, []float32{0.3, 0.9}) {
+		t.Fatalf("second voice: got %v (%d)", average, count)
+	}
+	average, count = unlearn(average, count, []float32{0, 2})
+	if count != 1 || !near(average, []float32{0.6, 0.8}) {
+		t.Fatalf("after unlearning: got %v (%d)", average, count)
+	}
+	average, count = unlearn(average, count, []float32{3, 4})
+	if count != 0 || average != nil {
+		t.Errorf("unlearning the last voice should leave none, got %v (%d)", average, count)
+	}
+
+	// Silence has no direction and teaches nothing
+	average, count = learn([]float32{1, 0}, 1, []float32{0, 0})
+	if count != 1 || !near(average, []float32{1, 0}) {
+		t.Errorf("zero voice: got %v (%d)", average, count)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if s := Similarity([]float32{1, 0}, []float32{2, 0}); math.Abs(s-1) > 1e-9 {
+		t.Errorf("parallel voices: got %v", s)
+	}
+	if s := Similarity([]float32{1, 0}, []float32{0, 1}); s != 0 {
+		t.Errorf("orthogonal voices: got %v", s)
+	}
+	if s := Similarity([]float32{1, 0}, []float32{1, 0, 0}); s != 0 {
+		t.Errorf("different lengths: got %v", s)
+	}
+}
+
+func near(a, b []float32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(float64(a[i]-b[i])) > 1e-6 {
+			return false
+		}
+	}
+	return true
+}
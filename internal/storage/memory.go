@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store kept in memory, for tests. Its presigned URLs are memory:// URLs
+// that only name the object.
+type MemoryStore struct {
+	mu      sync.Mutex
+	objects map[string]memoryObject
+}
+
+type memoryObject struct {
+	data     []byte
+	modified time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{objects: make(map[string]memoryObject)}
+}
+
+// Put stores an object
+func (m *MemoryStore) Put(ctx context.Context, key string, body io.Reader, size int64) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return fmt.Errorf("expected %d bytes, got %d", size, len(data))
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = memoryObject{data: data, modified: time.Now()}
+	return nil
+}
+
+// Get returns an object's bytes
+func (m *MemoryStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	object, ok := m.objects[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(object.data)), nil
+}
+
+// Delete removes an object
+func (m *MemoryStore) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.objects[key]; !ok {
+		return ErrNotFound
+	}
+	delete(m.objects, key)
+	return nil
+}
+
+// List returns the objects whose key starts with prefix, by key
+func (m *MemoryStore) List(ctx context.Context, prefix string) ([]Object, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var objects []Object
+	for key, object := range m.objects {
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, Object{Key: key, Size: int64(len(object.data)), LastModified: object.modified})
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
+
+// PresignGet returns a memory:// URL naming the object
+func (m *MemoryStore) PresignGet(key string, ttl time.Duration) (string, error) {
+	return "memory:///" + (&url.URL{Path: key}).EscapedPath() + "?method=GET", nil
+}
+
+// PresignPut returns a memory:// URL naming the object
+func (m *MemoryStore) PresignPut(key string, ttl time.Duration) (string, error) {
+	return "memory:///" + (&url.URL{Path: key}).EscapedPath() + "?method=PUT", nil
+}
+
+// SetModified backdates an object, for testing cleanups
+func (m *MemoryStore) SetModified(key string, modified time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if object, ok := m.objects[key]; ok {
+		object.modified = modified
+		m.objects[key] = object
+	}
+}
@@ -0,0 +1,199 @@
+// Package storage keeps audio files and transcripts in object storage, such as S3 or MinIO,
+// so that several Scriberr nodes can share them. The local disk holds working copies: files
+// are written there first and uploaded, and a node that doesn't have a file fetches it back
+// when it needs it.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned for an object that doesn't exist
+var ErrNotFound = errors.New("object not found")
+
+// Object describes a stored object
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Store is an object store
+type Store interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// List returns every object whose key starts with prefix
+	List(ctx context.Context, prefix string) ([]Object, error)
+	// PresignGet and PresignPut return URLs that download or upload an object without
+	// credentials until ttl has passed
+	PresignGet(key string, ttl time.Duration) (string, error)
+	PresignPut(key string, ttl time.Duration) (string, error)
+}
+
+// transcriptsDir is where transcripts are kept in the store, under the prefix
+const transcriptsDir = "transcripts"
+
+// Files maps the app's local files to objects in a Store. A file's key is its path, relative
+// to the working directory, under the prefix, so every node configured alike agrees on it.
+type Files struct {
+	store  Store
+	prefix string
+}
+
+// NewFiles keeps files in store under prefix, which may be empty
+func NewFiles(store Store, prefix string) *Files {
+	prefix = strings.Trim(prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &Files{store: store, prefix: prefix}
+}
+
+// Store returns the underlying object store
+func (f *Files) Store() Store {
+	return f.store
+}
+
+// Key returns the object key of a local file
+func (f *Files) Key(localPath string) string {
+	return f.prefix + strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(localPath)), "/")
+}
+
+// TranscriptKey returns the object key of a job's transcript
+func (f *Files) TranscriptKey(jobID string) string {
+	return f.prefix + transcriptsDir + "/" + jobID + ".json"
+}
+
+// Upload copies a local file to the store
+func (f *Files) Upload(ctx context.Context, localPath string) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if err := f.store.Put(ctx, f.Key(localPath), file, info.Size()); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", localPath, err)
+	}
+	return nil
+}
+
+// Fetch downloads a file from the store unless there is a local copy already
+func (f *Files) Fetch(ctx context.Context, localPath string) error {
+	if _, err := os.Stat(localPath); err == nil {
+		return nil
+	}
+	body, err := f.store.Get(ctx, f.Key(localPath))
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", localPath, err)
+	}
+	defer body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return err
+	}
+	// Written aside and renamed, so a half-downloaded file is never taken for the whole
+	temp, err := os.CreateTemp(filepath.Dir(localPath), ".fetch-*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(temp, body); err != nil {
+		temp.Close()
+		os.Remove(temp.Name())
+		return fmt.Errorf("failed to fetch %s: %w", localPath, err)
+	}
+	if err := temp.Close(); err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	if err := os.Rename(temp.Name(), localPath); err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	return nil
+}
+
+// Remove deletes a file's object; a missing object is not an error
+func (f *Files) Remove(ctx context.Context, localPath string) error {
+	if err := f.store.Delete(ctx, f.Key(localPath)); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// SaveTranscript stores a job's transcript
+func (f *Files) SaveTranscript(ctx context.Context, jobID, transcript string) error {
+	if err := f.store.Put(ctx, f.TranscriptKey(jobID), strings.NewReader(transcript), int64(len(transcript))); err != nil {
+		return fmt.Errorf("failed to store transcript of %s: %w", jobID, err)
+	}
+	return nil
+}
+
+// RemoveTranscript deletes a job's stored transcript; a missing one is not an error
+func (f *Files) RemoveTranscript(ctx context.Context, jobID string) error {
+	if err := f.store.Delete(ctx, f.TranscriptKey(jobID)); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// CleanupResult reports what Cleanup deleted
+type CleanupResult struct {
+	Scanned int   `json:"scanned"`
+	Deleted int   `json:"deleted"`
+	Bytes   int64 `json:"bytes"`
+}
+
+// Cleanup deletes the objects under the prefix that no longer belong to anything, as told by
+// orphaned, and that are older than minAge, which leaves time for uploads in progress
+func (f *Files) Cleanup(ctx context.Context, minAge time.Duration, orphaned func(key string) (bool, error)) (CleanupResult, error) {
+	var result CleanupResult
+	objects, err := f.store.List(ctx, f.prefix)
+	if err != nil {
+		return result, err
+	}
+	cutoff := time.Now().Add(-minAge)
+	for _, object := range objects {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		result.Scanned++
+		if object.LastModified.After(cutoff) {
+			continue
+		}
+		orphan, err := orphaned(object.Key)
+		if err != nil {
+			return result, err
+		}
+		if !orphan {
+			continue
+		}
+		if err := f.store.Delete(ctx, object.Key); err != nil && !errors.Is(err, ErrNotFound) {
+			return result, err
+		}
+		result.Deleted++
+		result.Bytes += object.Size
+	}
+	return result, nil
+}
+
+// TranscriptJobID returns the job whose transcript a key holds, or false for other keys
+func (f *Files) TranscriptJobID(key string) (string, bool) {
+	name, ok := strings.CutPrefix(key, f.prefix+transcriptsDir+"/")
+	if !ok || strings.Contains(name, "/") || !strings.HasSuffix(name, ".json") {
+		return "", false
+	}
+	return strings.TrimSuffix(name, ".json"), true
+}
@@ -0,0 +1,225 @@
+// Package tagging manages tags and their links to transcriptions. Each transcription's tags
+// column holds a copy of its tag names, which smart folders, list filters and the vector store
+// metadata read; every change goes through this package so that copy stays current.
+package tagging
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/folders"
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxTagLength caps tag names, in characters
+const maxTagLength = 100
+
+// ErrTagExists is returned when renaming a tag to the name of another tag of the same owner
+var ErrTagExists = errors.New("a tag with this name already exists")
+
+// Normalize trims tag names, drops a leading #, shortens overlong names and removes empty and
+// duplicate (case-insensitive) ones
+func Normalize(names []string) []string {
+	cleaned := make([]string, len(names))
+	for i, name := range names {
+		name = strings.Join(strings.Fields(strings.TrimPrefix(strings.TrimSpace(name), "#")), " ")
+		if runes := []rune(name); len(runes) > maxTagLength {
+			name = strings.TrimSpace(string(runes[:maxTagLength]))
+		}
+		cleaned[i] = name
+	}
+	return folders.NormalizeTerms(cleaned)
+}
+
+// OwnedBy limits a query on tags to those of one owner
+func OwnedBy(db *gorm.DB, userID *uint) *gorm.DB {
+	if userID == nil {
+		return db.Where("tags.user_id IS NULL")
+	}
+	return db.Where("tags.user_id = ?", *userID)
+}
+
+// findOrCreate returns the owner's tag called name, matched case-insensitively, creating it if needed
+func findOrCreate(tx *gorm.DB, userID *uint, name string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := OwnedBy(tx, userID).Where("LOWER(tags.name) = LOWER(?)", name).Limit(1).Find(&tag).Error; err != nil {
+		return nil, err
+	}
+	if tag.ID != 0 {
+		return &tag, nil
+	}
+	tag = models.Tag{UserID: userID, Name: name}
+	if err := tx.Create(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// SetJobTags replaces a transcription's tags. Tags it already had keep their source; new
+// ones are recorded as added by hand. It returns the transcription's tag names.
+func SetJobTags(job *models.TranscriptionJob, names []string) ([]string, error) {
+	return setTags(job, Normalize(names), models.TagSourceManual)
+}
+
+// SetAutoTags replaces the automatically generated tags of a transcription, keeping the tags
+// added by hand. It returns the transcription's tag names.
+func SetAutoTags(job *models.TranscriptionJob, names []string) ([]string, error) {
+	return setTags(job, Normalize(names), models.TagSourceAuto)
+}
+
+// setTags links the named tags to a transcription and unlinks the others. With the auto
+// source only automatic links are removed.
+func setTags(job *models.TranscriptionJob, names []string, source string) ([]string, error) {
+	var result []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var links []models.TranscriptionTag
+		if err := tx.Where("transcription_id = ?", job.ID).Find(&links).Error; err != nil {
+			return err
+		}
+
+		wanted := make(map[uint]bool, len(names))
+		for _, name := range names {
+			tag, err := findOrCreate(tx, job.UserID, name)
+			if err != nil {
+				return err
+			}
+			wanted[tag.ID] = true
+			link := models.TranscriptionTag{TranscriptionID: job.ID, TagID: tag.ID, Source: source}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error; err != nil {
+				return err
+			}
+		}
+		for _, link := range links {
+			if wanted[link.TagID] || (source == models.TagSourceAuto && link.Source != models.TagSourceAuto) {
+				continue
+			}
+			if err := tx.Where("transcription_id = ? AND tag_id = ?", job.ID, link.TagID).Delete(&models.TranscriptionTag{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := Sync(tx, job.ID); err != nil {
+			return err
+		}
+		var err error
+		result, err = jobTagNames(tx, job.ID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save tags of %s: %w", job.ID, err)
+	}
+	job.Tags = result
+	return result, nil
+}
+
+// jobTagNames returns the names of a transcription's tags in the order they were added
+func jobTagNames(tx *gorm.DB, transcriptionID string) ([]string, error) {
+	var names []string
+	err := tx.Table("transcription_tags").
+		Joins("JOIN tags ON tags.id = transcription_tags.tag_id").
+		Where("transcription_tags.transcription_id = ?", transcriptionID).
+		Order("transcription_tags.created_at ASC, tags.name ASC").
+		Pluck("tags.name", &names).Error
+	return names, err
+}
+
+// Sync rewrites the tags column of transcriptions from their tag links
+func Sync(tx *gorm.DB, transcriptionIDs ...string) error {
+	for _, id := range transcriptionIDs {
+		names, err := jobTagNames(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.TranscriptionJob{}).Where("id = ?", id).Select("tags").
+			Updates(&models.TranscriptionJob{Tags: names}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taggedJobIDs returns the transcriptions a tag is linked to
+func taggedJobIDs(tx *gorm.DB, tagID uint) ([]string, error) {
+	var ids []string
+	err := tx.Model(&models.TranscriptionTag{}).Where("tag_id = ?", tagID).Pluck("transcription_id", &ids).Error
+	return ids, err
+}
+
+// Rename changes a tag's name on every transcription it is linked to and returns those transcriptions
+func Rename(tag *models.Tag, name string) ([]string, error) {
+	var ids []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := OwnedBy(tx.Model(&models.Tag{}), tag.UserID).
+			Where("LOWER(tags.name) = LOWER(?) AND tags.id != ?", name, tag.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrTagExists
+		}
+		if err := tx.Model(tag).Update("name", name).Error; err != nil {
+			return err
+		}
+		var err error
+		if ids, err = taggedJobIDs(tx, tag.ID); err != nil {
+			return err
+		}
+		return Sync(tx, ids...)
+	})
+	return ids, err
+}
+
+// Delete removes a tag from every transcription it is linked to and returns those transcriptions
+func Delete(tag *models.Tag) ([]string, error) {
+	var ids []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if ids, err = taggedJobIDs(tx, tag.ID); err != nil {
+			return err
+		}
+		if err := tx.Where("tag_id = ?", tag.ID).Delete(&models.TranscriptionTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(tag).Error; err != nil {
+			return err
+		}
+		return Sync(tx, ids...)
+	})
+	return ids, err
+}
+
+// Names returns the names of an owner's most used tags, at most limit of them
+func Names(userID *uint, limit int) ([]string, error) {
+	var names []string
+	err := OwnedBy(database.DB.Model(&models.Tag{}), userID).
+		Joins("LEFT JOIN transcription_tags ON transcription_tags.tag_id = tags.id").
+		Group("tags.id").
+		Order("COUNT(transcription_tags.tag_id) DESC, tags.name ASC").
+		Limit(limit).
+		Pluck("tags.name", &names).Error
+	return names, err
+}
+
+// MigrateJobTags creates tags and links for transcriptions tagged before the tags table
+// existed, i.e. with tag names in their tags column but no links. It returns how many
+// transcriptions it migrated.
+func MigrateJobTags() (int, error) {
+	var jobs []models.TranscriptionJob
+	if err := database.DB.Select("id", "user_id", "tags").
+		Where("tags IS NOT NULL AND tags NOT IN ('', 'null', '[]')").
+		Where("id NOT IN (?)", database.DB.Model(&models.TranscriptionTag{}).Select("transcription_id")).
+		Find(&jobs).Error; err != nil {
+		return 0, fmt.Errorf("failed to find tagged transcriptions: %w", err)
+	}
+	for i := range jobs {
+		if _, err := SetJobTags(&jobs[i], jobs[i].Tags); err != nil {
+			return i, err
+		}
+	}
+	return len(jobs), nil
+}
@@ -0,0 +1,166 @@
+package topics
+
+import (
+	"math"
+	"math/rand"
+)
+
+// maxIterations bounds k-means when assignments keep shifting
+const maxIterations = 100
+
+// ChooseK picks the number of clusters for n transcriptions, about sqrt(n/2) clamped to [2, MaxTopics]
+func ChooseK(n int) int {
+	k := int(math.Round(math.Sqrt(float64(n) / 2)))
+	if k < 2 {
+		k = 2
+	}
+	if k > MaxTopics {
+		k = MaxTopics
+	}
+	if k > n {
+		k = n
+	}
+	return k
+}
+
+// KMeans clusters vectors by cosine similarity (spherical k-means with k-means++ seeding)
+// and returns each vector's cluster and the unit-length cluster centroids. The same seed
+// gives the same clusters for the same input.
+func KMeans(vectors [][]float32, k int, seed int64) ([]int, [][]float64) {
+	points := make([][]float64, len(vectors))
+	for i, v := range vectors {
+		points[i] = normalize(v)
+	}
+	if k > len(points) {
+		k = len(points)
+	}
+	assignments := make([]int, len(points))
+	if k <= 0 {
+		return assignments, nil
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	centroids := seedCentroids(points, k, rng)
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		changed := iteration == 0
+		for i, p := range points {
+			best := nearest(p, centroids)
+			if best != assignments[i] {
+				assignments[i] = best
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+		centroids = recompute(points, assignments, centroids)
+	}
+	return assignments, centroids
+}
+
+// seedCentroids picks initial centroids with k-means++: each next centroid is drawn with
+// probability proportional to its squared distance from the nearest centroid so far
+func seedCentroids(points [][]float64, k int, rng *rand.Rand) [][]float64 {
+	centroids := [][]float64{points[rng.Intn(len(points))]}
+	distances := make([]float64, len(points))
+	for len(centroids) < k {
+		total := 0.0
+		for i, p := range points {
+			d := 1 - cosine(p, centroids[nearest(p, centroids)])
+			distances[i] = d * d
+			total += distances[i]
+		}
+		if total == 0 {
+			// Every point coincides with a centroid; duplicates are as good as anything
+			centroids = append(centroids, points[rng.Intn(len(points))])
+			continue
+		}
+		target := rng.Float64() * total
+		chosen := len(points) - 1
+		for i, d := range distances {
+			if target -= d; target <= 0 {
+				chosen = i
+				break
+			}
+		}
+		centroids = append(centroids, points[chosen])
+	}
+	return centroids
+}
+
+// recompute sets each centroid to the normalized mean of its points. A cluster that lost
+// all its points takes over the point farthest from its own centroid.
+func recompute(points [][]float64, assignments []int, previous [][]float64) [][]float64 {
+	dims := len(points[0])
+	centroids := make([][]float64, len(previous))
+	counts := make([]int, len(previous))
+	for c := range centroids {
+		centroids[c] = make([]float64, dims)
+	}
+	for i, p := range points {
+		c := assignments[i]
+		counts[c]++
+		for d, x := range p {
+			centroids[c][d] += x
+		}
+	}
+	for c := range centroids {
+		if counts[c] > 0 {
+			centroids[c] = normalize64(centroids[c])
+			continue
+		}
+		farthest, worst := 0, math.Inf(1)
+		for i, p := range points {
+			if sim := cosine(p, previous[assignments[i]]); sim < worst {
+				farthest, worst = i, sim
+			}
+		}
+		centroids[c] = points[farthest]
+	}
+	return centroids
+}
+
+// nearest returns the index of the centroid most similar to p
+func nearest(p []float64, centroids [][]float64) int {
+	best, bestSim := 0, math.Inf(-1)
+	for c, centroid := range centroids {
+		if sim := cosine(p, centroid); sim > bestSim {
+			best, bestSim = c, sim
+		}
+	}
+	return best
+}
+
+// cosine is the dot product of two unit vectors
+func cosine(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		if i < len(b) {
+			sum += a[i] * b[i]
+		}
+	}
+	return sum
+}
+
+func normalize(v []float32) []float64 {
+	out := make([]float64, len(v))
+	for i, x := range v {
+		out[i] = float64(x)
+	}
+	return normalize64(out)
+}
+
+func normalize64(v []float64) []float64 {
+	norm := 0.0
+	for _, x := range v {
+		norm += x * x
+	}
+	if norm == 0 {
+		return v
+	}
+	norm = math.Sqrt(norm)
+	for i := range v {
+		v[i] /= norm
+	}
+	return v
+}
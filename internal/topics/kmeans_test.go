@@ -0,0 +1,87 @@
+package topics
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// blob returns n noisy vectors around center
+func blob(rng *rand.Rand, center []float32, n int) [][]float32 {
+	vectors := make([][]float32, n)
+	for i := range vectors {
+		v := make([]float32, len(center))
+		for d, x := range center {
+			v[d] = x + float32(rng.NormFloat64()*0.05)
+		}
+		vectors[i] = v
+	}
+	return vectors
+}
+
+func TestKMeansSeparatesClusters(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	var vectors [][]float32
+	vectors = append(vectors, blob(rng, []float32{1, 0, 0}, 10)...)
+	vectors = append(vectors, blob(rng, []float32{0, 1, 0}, 10)...)
+	vectors = append(vectors, blob(rng, []float32{0, 0, 1}, 10)...)
+
+	assignments, centroids := KMeans(vectors, 3, clusterSeed)
+	if len(centroids) != 3 {
+		t.Fatalf("expected 3 centroids, got %d", len(centroids))
+	}
+	for group := 0; group < 3; group++ {
+		first := assignments[group*10]
+		for i := group * 10; i < (group+1)*10; i++ {
+			if assignments[i] != first {
+				t.Fatalf("expected vectors from blob %d in one cluster, got %v", group, assignments)
+			}
+		}
+		for other := 0; other < group; other++ {
+			if assignments[other*10] == first {
+				t.Fatalf("expected blobs %d and %d in different clusters, got %v", other, group, assignments)
+			}
+		}
+	}
+
+	again, _ := KMeans(vectors, 3, clusterSeed)
+	for i := range again {
+		if again[i] != assignments[i] {
+			t.Fatal("expected the same seed to give the same clusters")
+		}
+	}
+}
+
+func TestKMeansScaleInvariant(t *testing.T) {
+	// Cosine clustering ignores vector length
+	vectors := [][]float32{{1, 0}, {10, 0.5}, {0, 1}, {0.2, 8}}
+	assignments, _ := KMeans(vectors, 2, clusterSeed)
+	if assignments[0] != assignments[1] || assignments[2] != assignments[3] || assignments[0] == assignments[2] {
+		t.Errorf("expected clusters by direction, got %v", assignments)
+	}
+}
+
+func TestChooseK(t *testing.T) {
+	cases := map[int]int{4: 2, 18: 3, 50: 5, 1000: MaxTopics}
+	for n, want := range cases {
+		if got := ChooseK(n); got != want {
+			t.Errorf("ChooseK(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestParseLabel(t *testing.T) {
+	label, description, err := parseLabel("Sure!\n```json\n{\"label\": \"Quarterly budget.\", \"description\": \"Budget planning meetings\"}\n```")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label != "Quarterly budget" || description != "Budget planning meetings" {
+		t.Errorf("unexpected label %q / %q", label, description)
+	}
+
+	if _, _, err := parseLabel(`{"description": "no label"}`); err == nil {
+		t.Error("expected an error for a reply without a label")
+	}
+	if _, _, err := parseLabel("Budget"); err == nil {
+		t.Error("expected an error for a reply without JSON")
+	}
+}
@@ -0,0 +1,322 @@
+// Package topics groups the transcript library into themes by clustering the stored
+// transcript embeddings and labeling each cluster with the LLM.
+package topics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxTopics caps the number of clusters per collection
+	MaxTopics = 12
+	// MinTranscriptions is the smallest library worth clustering
+	MinTranscriptions = 4
+
+	// labelSamples is how many of the most central transcriptions are shown to the LLM per cluster
+	labelSamples = 5
+	// labelExcerptLength caps each sample's text in the labeling prompt, in characters
+	labelExcerptLength = 600
+	// clusterSeed keeps clusters stable between refreshes of an unchanged library
+	clusterSeed = 1
+	// refreshTimeout bounds a background refresh
+	refreshTimeout = 30 * time.Minute
+)
+
+// ErrRefreshInProgress is returned when a collection is already being clustered
+var ErrRefreshInProgress = errors.New("topic refresh already in progress")
+
+// Service clusters transcriptions into topics in the background
+type Service struct {
+	rag   *rag.RAGService
+	llm   rag.LLMService
+	model string
+
+	mu      sync.Mutex
+	running map[string]bool // collections being refreshed
+	stop    chan struct{}
+}
+
+// NewService creates a topic service that labels clusters with model
+func NewService(ragService *rag.RAGService, llmService rag.LLMService, model string) *Service {
+	return &Service{
+		rag:     ragService,
+		llm:     llmService,
+		model:   model,
+		running: make(map[string]bool),
+	}
+}
+
+// Start refreshes the topics of every collection every interval, and once right away if
+// none have been generated yet. An interval of 0 disables periodic refreshes.
+func (s *Service) Start(interval time.Duration) {
+	s.stop = make(chan struct{})
+
+	var count int64
+	database.DB.Model(&models.Topic{}).Count(&count)
+	if count == 0 {
+		go s.refreshAll()
+	}
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.refreshAll()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends periodic refreshes
+func (s *Service) Stop() {
+	if s.stop != nil {
+		close(s.stop)
+	}
+}
+
+// refreshAll refreshes every collection, logging failures
+func (s *Service) refreshAll() {
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+
+	collections, err := rag.Collections()
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to list collections", "component", "topics", "error", err)
+		return
+	}
+	for _, collection := range collections {
+		if err := s.Refresh(ctx, collection); err != nil && !errors.Is(err, ErrRefreshInProgress) {
+			logger.ErrorContext(ctx, "Failed to refresh topics", "component", "topics", "collection", collection, "error", err)
+		}
+	}
+}
+
+// IsRefreshing reports whether a collection is being clustered right now
+func (s *Service) IsRefreshing(collection string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running[collection]
+}
+
+// RefreshInBackground starts refreshing a collection's topics and returns without waiting
+func (s *Service) RefreshInBackground(collection string) error {
+	if !s.reserve(collection) {
+		return ErrRefreshInProgress
+	}
+	go func() {
+		defer s.release(collection)
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		defer cancel()
+		if err := s.refresh(ctx, collection); err != nil {
+			logger.ErrorContext(ctx, "Failed to refresh topics", "component", "topics", "collection", collection, "error", err)
+		}
+	}()
+	return nil
+}
+
+// Refresh clusters the transcriptions in a collection and replaces its topics
+func (s *Service) Refresh(ctx context.Context, collection string) error {
+	if !s.reserve(collection) {
+		return ErrRefreshInProgress
+	}
+	defer s.release(collection)
+	return s.refresh(ctx, collection)
+}
+
+// reserve marks a collection as being refreshed, reporting false if it already is
+func (s *Service) reserve(collection string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[collection] {
+		return false
+	}
+	s.running[collection] = true
+	return true
+}
+
+// release clears a collection's refresh mark
+func (s *Service) release(collection string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, collection)
+}
+
+// refresh does the work of Refresh once the collection is reserved
+func (s *Service) refresh(ctx context.Context, collection string) error {
+	entries, err := s.rag.SummaryEmbeddings(ctx, collection)
+	if err != nil {
+		return err
+	}
+	entries, err = existing(entries)
+	if err != nil {
+		return err
+	}
+
+	var topics []models.Topic
+	if len(entries) >= MinTranscriptions {
+		topics = s.cluster(ctx, collection, entries)
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection = ?", collection).Delete(&models.Topic{}).Error; err != nil {
+			return err
+		}
+		if len(topics) == 0 {
+			return nil
+		}
+		return tx.Create(&topics).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save topics: %w", err)
+	}
+	logger.InfoContext(ctx, "Clustered transcriptions into topics", "component", "topics", "collection", collection, "transcriptions", len(entries), "topics", len(topics))
+	return nil
+}
+
+// cluster groups entries with k-means and labels each non-empty cluster, largest first
+func (s *Service) cluster(ctx context.Context, collection string, entries []rag.SummaryEmbedding) []models.Topic {
+	vectors := make([][]float32, len(entries))
+	for i, entry := range entries {
+		vectors[i] = entry.Embedding
+	}
+	assignments, centroids := KMeans(vectors, ChooseK(len(entries)), clusterSeed)
+
+	type member struct {
+		entry      rag.SummaryEmbedding
+		similarity float64
+	}
+	members := make([][]member, len(centroids))
+	for i, entry := range entries {
+		c := assignments[i]
+		members[c] = append(members[c], member{entry, cosine(normalize(entry.Embedding), centroids[c])})
+	}
+
+	var topics []models.Topic
+	for c := range members {
+		if len(members[c]) == 0 {
+			continue
+		}
+		sort.SliceStable(members[c], func(i, j int) bool { return members[c][i].similarity > members[c][j].similarity })
+
+		ids := make([]string, len(members[c]))
+		var samples []string
+		for i, m := range members[c] {
+			ids[i] = m.entry.TranscriptionID
+			if i < labelSamples {
+				samples = append(samples, m.entry.Content)
+			}
+		}
+
+		label, description, err := s.label(ctx, samples)
+		if err != nil {
+			logger.WarnContext(ctx, "Failed to label a topic cluster", "component", "topics", "collection", collection, "error", err)
+			label = fmt.Sprintf("Topic %d", len(topics)+1)
+		}
+		topics = append(topics, models.Topic{
+			Collection:       collection,
+			Label:            label,
+			Description:      description,
+			TranscriptionIDs: ids,
+			Size:             len(ids),
+		})
+	}
+
+	sort.SliceStable(topics, func(i, j int) bool { return topics[i].Size > topics[j].Size })
+	return topics
+}
+
+// label asks the LLM to name the theme shared by a cluster's most central transcriptions
+func (s *Service) label(ctx context.Context, samples []string) (string, string, error) {
+	var prompt strings.Builder
+	prompt.WriteString("The following excerpts come from recordings that were grouped together because they cover similar subjects.\n\n")
+	for i, sample := range samples {
+		if len(sample) > labelExcerptLength {
+			sample = sample[:labelExcerptLength] + "..."
+		}
+		fmt.Fprintf(&prompt, "[%d] %s\n\n", i+1, sample)
+	}
+	prompt.WriteString("Name the topic they share. Reply with JSON only, in the form ")
+	prompt.WriteString(`{"label": "a 2-5 word topic name", "description": "one sentence describing the topic"}`)
+
+	messages := []llm.ChatMessage{{Role: "user", Content: prompt.String()}}
+	response, err := s.llm.ChatCompletion(ctx, s.model, messages, 0.2)
+	if err != nil {
+		return "", "", err
+	}
+	if len(response.Choices) == 0 {
+		return "", "", fmt.Errorf("no response from LLM")
+	}
+	return parseLabel(response.Choices[0].Message.Content)
+}
+
+// parseLabel extracts the label JSON from an LLM reply, tolerating surrounding text or code fences
+func parseLabel(reply string) (string, string, error) {
+	start := strings.Index(reply, "{")
+	end := strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return "", "", fmt.Errorf("label reply contained no JSON object")
+	}
+	var raw struct {
+		Label       string `json:"label"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &raw); err != nil {
+		return "", "", fmt.Errorf("failed to decode label reply: %w", err)
+	}
+	label := strings.Trim(strings.TrimSpace(raw.Label), `"'.`)
+	if label == "" {
+		return "", "", fmt.Errorf("label reply is missing the label")
+	}
+	if len(label) > 255 {
+		label = label[:255]
+	}
+	return label, strings.TrimSpace(raw.Description), nil
+}
+
+// existing drops entries whose transcription has been deleted but is still in the vector store
+func existing(entries []rag.SummaryEmbedding) ([]rag.SummaryEmbedding, error) {
+	if len(entries) == 0 {
+		return entries, nil
+	}
+	ids := make([]string, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.TranscriptionID
+	}
+	var found []string
+	if err := database.DB.Model(&models.TranscriptionJob{}).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		return nil, fmt.Errorf("failed to look up transcriptions: %w", err)
+	}
+	present := make(map[string]bool, len(found))
+	for _, id := range found {
+		present[id] = true
+	}
+	kept := entries[:0]
+	for _, entry := range entries {
+		if present[entry.TranscriptionID] {
+			kept = append(kept, entry)
+		}
+	}
+	return kept, nil
+}
@@ -0,0 +1,305 @@
+// Package tracing records OpenTelemetry spans of the transcription and RAG pipeline (job
+// processing, post-processing steps, embedding, vector store and LLM calls) and exports them
+// to a collector over OTLP/HTTP, so a slow transcription can be broken down into its stages.
+// Tracing is off until Init is given an endpoint; until then spans cost nothing.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span kinds, as OTLP numbers them
+const (
+	KindInternal = int(trace.SpanKindInternal)
+	KindServer   = int(trace.SpanKindServer)
+	KindClient   = int(trace.SpanKindClient)
+)
+
+// Config is where and how spans are exported
+type Config struct {
+	// Endpoint is the collector's OTLP/HTTP base URL, e.g. http://localhost:4318; spans are
+	// posted to its /v1/traces. Empty turns tracing off.
+	Endpoint string
+	// Headers are sent with every export, e.g. a vendor's API key
+	Headers map[string]string
+	// ServiceName names this process in the tracing backend
+	ServiceName string
+	// SampleRatio is the share of traces recorded, from 0 to 1
+	SampleRatio float64
+}
+
+// TraceID identifies a trace
+type TraceID = trace.TraceID
+
+type contextKey int
+
+const jobKey contextKey = iota
+
+var (
+	mu       sync.RWMutex
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+
+	propagator = propagation.TraceContext{}
+)
+
+// Init starts exporting spans as cfg says. An empty endpoint leaves tracing off.
+func Init(cfg Config) error {
+	if cfg.Endpoint == "" {
+		return nil
+	}
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return fmt.Errorf("sample ratio must be between 0 and 1, got %g", cfg.SampleRatio)
+	}
+	parsed, err := url.Parse(cfg.Endpoint)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid OTLP endpoint %q: expected an http or https URL", cfg.Endpoint)
+	}
+	exporter, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(strings.TrimSuffix(cfg.Endpoint, "/")+"/v1/traces"),
+		otlptracehttp.WithHeaders(cfg.Headers),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	install(sdktrace.NewBatchSpanProcessor(exporter), cfg)
+	return nil
+}
+
+// install makes spans go through processor, replacing the previous provider
+func install(processor sdktrace.SpanProcessor, cfg Config) {
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = "scriberr"
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithIDGenerator(jobIDGenerator{}),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+	mu.Lock()
+	previous := provider
+	provider, tracer = tp, tp.Tracer("scriberr")
+	mu.Unlock()
+	if previous != nil {
+		previous.Shutdown(context.Background())
+	}
+}
+
+// Shutdown exports the spans still buffered and stops tracing
+func Shutdown(ctx context.Context) error {
+	mu.Lock()
+	tp := provider
+	provider, tracer = nil, nil
+	mu.Unlock()
+	if tp == nil {
+		return nil
+	}
+	return tp.Shutdown(ctx)
+}
+
+// Enabled reports whether spans are being exported
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return provider != nil
+}
+
+// ForJob returns ctx with spans that start a new trace there joining the trace of a
+// transcription job. Its processing, post-processing and retries run at different times and
+// in different goroutines, and this puts them all in one trace.
+func ForJob(ctx context.Context, jobID string) context.Context {
+	return context.WithValue(ctx, jobKey, jobID)
+}
+
+// JobTraceID returns the ID of the trace of a transcription job's spans
+func JobTraceID(jobID string) TraceID {
+	var id TraceID
+	sum := sha256.Sum256([]byte("scriberr-job:" + jobID))
+	copy(id[:], sum[:])
+	return id
+}
+
+// jobIDGenerator gives root spans started from a ForJob context the job's trace ID
+type jobIDGenerator struct{}
+
+func (jobIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	var traceID trace.TraceID
+	if jobID, ok := ctx.Value(jobKey).(string); ok {
+		traceID = JobTraceID(jobID)
+	} else {
+		rand.Read(traceID[:])
+	}
+	return traceID, newSpanID()
+}
+
+func (jobIDGenerator) NewSpanID(context.Context, trace.TraceID) trace.SpanID {
+	return newSpanID()
+}
+
+func newSpanID() trace.SpanID {
+	var id trace.SpanID
+	rand.Read(id[:])
+	return id
+}
+
+// Span is an operation being timed. A nil *Span, which Start returns while tracing is off
+// or the trace isn't sampled, is valid and records nothing.
+type Span struct {
+	span trace.Span
+}
+
+// Start starts a span named name as a child of the span in ctx, if any, and returns ctx with
+// the new span as the parent of spans started from it. attrs are key/value pairs, as for the
+// logger. End the span when the operation is done.
+func Start(ctx context.Context, name string, attrs ...interface{}) (context.Context, *Span) {
+	return StartKind(ctx, KindInternal, name, attrs...)
+}
+
+// StartKind is Start for a span serving a request (KindServer) or making one (KindClient)
+func StartKind(ctx context.Context, kind int, name string, attrs ...interface{}) (context.Context, *Span) {
+	mu.RLock()
+	t := tracer
+	mu.RUnlock()
+	if t == nil {
+		return ctx, nil
+	}
+
+	ctx, span := t.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKind(kind)),
+		trace.WithAttributes(keyValues(attrs)...),
+	)
+	if !span.IsRecording() {
+		// The span context stays in ctx, so children of an unsampled span are left out too
+		// rather than starting traces of their own
+		return ctx, nil
+	}
+	return ctx, &Span{span: span}
+}
+
+// SetAttribute records a value describing the operation. Strings, bools, integers and
+// floats are kept as they are; anything else is formatted with %v.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(keyValue(key, value))
+}
+
+// SetAttributes records key/value pairs, as for the logger
+func (s *Span) SetAttributes(kv ...interface{}) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(keyValues(kv)...)
+}
+
+// RecordError marks the operation as failed, if err is set
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End finishes the span and queues it for export. Only the first call counts.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.span.End()
+}
+
+// TraceID returns the ID of the span's trace, or the zero ID for a nil span
+func (s *Span) TraceID() TraceID {
+	if s == nil {
+		return TraceID{}
+	}
+	return s.span.SpanContext().TraceID()
+}
+
+// keyValues converts key/value pairs, as for the logger, to attributes
+func keyValues(kv []interface{}) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		if key, ok := kv[i].(string); ok {
+			attrs = append(attrs, keyValue(key, kv[i+1]))
+		}
+	}
+	return attrs
+}
+
+func keyValue(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case uint:
+		return attribute.Int64(key, int64(v))
+	case uint32:
+		return attribute.Int64(key, int64(v))
+	case float32:
+		return attribute.Float64(key, float64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	default:
+		return attribute.String(key, fmt.Sprintf("%v", v))
+	}
+}
+
+// Inject adds a W3C traceparent header naming the span in ctx, so the service called
+// continues the trace
+func Inject(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Extract returns ctx with the span named by a W3C traceparent header as the parent of the
+// spans started from it, so a caller's trace is continued. Invalid headers are ignored.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// ParseHeaders parses export headers in the OTEL_EXPORTER_OTLP_HEADERS format:
+// comma-separated key=value pairs with URL-encoded values
+func ParseHeaders(spec string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("expected key=value, got %q", pair)
+		}
+		decoded, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		headers[key] = decoded
+	}
+	return headers, nil
+}
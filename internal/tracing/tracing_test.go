@@ -0,0 +1,205 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// record turns tracing on with ended spans kept in memory
+func record(t *testing.T, ratio float64) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	install(sdktrace.NewSimpleSpanProcessor(exporter), Config{SampleRatio: ratio})
+	t.Cleanup(func() { Shutdown(context.Background()) })
+	return exporter
+}
+
+// byName returns the spans ended so far by name
+func byName(exporter *tracetest.InMemoryExporter) map[string]tracetest.SpanStub {
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+	return spans
+}
+
+func attributeValue(span tracetest.SpanStub, key string) (attribute.Value, bool) {
+	for _, attr := range span.Attributes {
+		if string(attr.Key) == key {
+			return attr.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestDisabledSpansAreNoOps(t *testing.T) {
+	ctx, span := Start(context.Background(), "noop", "key", "value")
+	if span != nil || ctx != context.Background() {
+		t.Fatalf("expected no span while tracing is off")
+	}
+	span.SetAttribute("key", 1)
+	span.RecordError(errors.New("failed"))
+	span.End()
+}
+
+func TestSpansAreRecordedAsATree(t *testing.T) {
+	exporter := record(t, 1)
+
+	ctx, root := Start(ForJob(context.Background(), "job-1"), "transcription.job", "job.id", "job-1")
+	_, child := StartKind(ctx, KindClient, "transcription.transcribe", "segments", 12, "fallback", true)
+	child.RecordError(errors.New("model crashed"))
+	child.End()
+	child.End()
+	root.End()
+
+	if len(exporter.GetSpans()) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(exporter.GetSpans()))
+	}
+	spans := byName(exporter)
+	parent, transcribe := spans["transcription.job"], spans["transcription.transcribe"]
+	if parent.SpanContext.TraceID() != JobTraceID("job-1") || transcribe.SpanContext.TraceID() != JobTraceID("job-1") {
+		t.Errorf("spans aren't in the job's trace")
+	}
+	if parent.Parent.IsValid() || transcribe.Parent.SpanID() != parent.SpanContext.SpanID() {
+		t.Errorf("unexpected parents %v, %v", parent.Parent.SpanID(), transcribe.Parent.SpanID())
+	}
+	if transcribe.SpanKind != trace.SpanKindClient || transcribe.Status.Code != codes.Error || transcribe.Status.Description != "model crashed" {
+		t.Errorf("unexpected child span %+v", transcribe)
+	}
+	if v, ok := attributeValue(transcribe, "segments"); !ok || v.AsInt64() != 12 {
+		t.Errorf("unexpected segments attribute %v", v.Emit())
+	}
+	if v, ok := attributeValue(transcribe, "fallback"); !ok || !v.AsBool() {
+		t.Errorf("unexpected fallback attribute %v", v.Emit())
+	}
+	if name, ok := parent.Resource.Set().Value("service.name"); !ok || name.AsString() != "scriberr" {
+		t.Errorf("unexpected service name %v", name.Emit())
+	}
+}
+
+func TestJobTracesAreShared(t *testing.T) {
+	record(t, 1)
+
+	// Processing and a later workflow run start separately but join the same trace
+	_, first := Start(ForJob(context.Background(), "job-2"), "transcription.job")
+	_, second := Start(ForJob(context.Background(), "job-2"), "workflow.run")
+	_, other := Start(context.Background(), "rag.chat")
+	if first.TraceID() != second.TraceID() || first.TraceID() != JobTraceID("job-2") {
+		t.Errorf("job spans are in different traces")
+	}
+	if other.TraceID() == first.TraceID() {
+		t.Errorf("unrelated span joined the job's trace")
+	}
+}
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	exporter := record(t, 1)
+
+	ctx, span := Start(context.Background(), "client")
+	header := http.Header{}
+	Inject(ctx, header)
+	spanID := span.span.SpanContext().SpanID()
+	want := "00-" + span.TraceID().String() + "-" + spanID.String() + "-01"
+	if header.Get("traceparent") != want {
+		t.Fatalf("got traceparent %q, want %q", header.Get("traceparent"), want)
+	}
+
+	_, server := StartKind(Extract(context.Background(), header), KindServer, "server")
+	server.End()
+	if got := byName(exporter)["server"]; got.SpanContext.TraceID() != span.TraceID() || got.Parent.SpanID() != spanID {
+		t.Errorf("extracted span doesn't continue the trace")
+	}
+
+	header.Set("traceparent", "00-not-hex-01")
+	_, fresh := Start(Extract(context.Background(), header), "fresh")
+	fresh.End()
+	if got := byName(exporter)["fresh"]; got.SpanContext.TraceID() == span.TraceID() || got.Parent.IsValid() {
+		t.Errorf("invalid traceparent wasn't ignored")
+	}
+}
+
+func TestUnsampledTracesAreDropped(t *testing.T) {
+	exporter := record(t, 0)
+
+	ctx, root := Start(context.Background(), "root")
+	_, child := Start(ctx, "child")
+	if root != nil || child != nil {
+		t.Fatalf("expected unsampled spans to be nil")
+	}
+	header := http.Header{}
+	Inject(ctx, header)
+	if !strings.HasSuffix(header.Get("traceparent"), "-00") {
+		t.Errorf("unsampled trace was propagated as sampled: %q", header.Get("traceparent"))
+	}
+	if spans := exporter.GetSpans(); len(spans) != 0 {
+		t.Errorf("expected no spans, got %d", len(spans))
+	}
+}
+
+func TestSpansAreExportedOverOTLP(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	var token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		paths = append(paths, r.URL.Path)
+		token = r.Header.Get("X-Token")
+	}))
+	defer server.Close()
+
+	err := Init(Config{Endpoint: server.URL + "/", Headers: map[string]string{"X-Token": "secret"}, SampleRatio: 1})
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	_, span := Start(context.Background(), "exported")
+	span.End()
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(paths) != 1 || paths[0] != "/v1/traces" {
+		t.Errorf("expected one export to /v1/traces, got %v", paths)
+	}
+	if token != "secret" {
+		t.Errorf("export headers weren't sent")
+	}
+}
+
+func TestInitValidation(t *testing.T) {
+	if err := Init(Config{}); err != nil || Enabled() {
+		t.Errorf("empty endpoint should leave tracing off, got %v", err)
+	}
+	if err := Init(Config{Endpoint: "localhost:4318", SampleRatio: 1}); err == nil {
+		t.Errorf("expected an error for an endpoint without a scheme")
+	}
+	if err := Init(Config{Endpoint: "http://localhost:4318", SampleRatio: 2}); err == nil {
+		t.Errorf("expected an error for a sample ratio above 1")
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := ParseHeaders("api-key=abc%20def, x-team = core ,")
+	if err != nil {
+		t.Fatalf("ParseHeaders: %v", err)
+	}
+	if len(headers) != 2 || headers["api-key"] != "abc def" || headers["x-team"] != "core" {
+		t.Errorf("unexpected headers %v", headers)
+	}
+	if _, err := ParseHeaders("novalue"); err == nil {
+		t.Errorf("expected an error for a pair without =")
+	}
+}
@@ -0,0 +1,321 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+// fakeSegmentSeconds is the length of each segment the fake transcriber produces
+const fakeSegmentSeconds = 5.0
+
+// fakeDefaultDuration is assumed when the audio duration could not be probed
+const fakeDefaultDuration = 30 * time.Second
+
+// fakeSentences are the lines fake transcripts are made of
+var fakeSentences = []string{
+	"Welcome everyone and thanks for joining the meeting today.",
+	"Let's start with a quick review of last week's action items.",
+	"The budget for the next quarter still needs final approval.",
+	"We agreed to ship the new release by the end of the month.",
+	"Customer feedback on the mobile app has been mostly positive.",
+	"Can someone take the lead on updating the documentation?",
+	"The migration to the new database finished without any issues.",
+	"Let's schedule a follow-up call with the design team on Friday.",
+	"Our hiring plan includes two engineers and one product manager.",
+	"Please send your notes to the shared folder after the call.",
+}
+
+// FakeTranscriptionAdapter produces deterministic transcripts without a model, for tests and
+// local development. The text is chosen from a fixed set of sentences seeded by the audio
+// content, so the same file always gives the same transcript.
+type FakeTranscriptionAdapter struct {
+	*BaseAdapter
+	// DetectedLanguages are the languages DetectLanguage finds, one per fakeSegmentSeconds of
+	// audio in turn; English throughout when empty
+	DetectedLanguages []string
+}
+
+// NewFakeTranscriptionAdapter creates a fake transcription adapter registered under modelID
+func NewFakeTranscriptionAdapter(modelID string) *FakeTranscriptionAdapter {
+	capabilities := interfaces.ModelCapabilities{
+		ModelID:            modelID,
+		ModelFamily:        "fake",
+		DisplayName:        "Fake Transcriber",
+		Description:        "Deterministic transcripts for tests and local development",
+		Version:            "1.0.0",
+		SupportedLanguages: []string{"*"},
+		SupportedFormats:   []string{"*"},
+		Features: map[string]bool{
+			"timestamps":      true,
+			"word_timestamps": true,
+			"diarization":     true,
+		},
+		Metadata: map[string]string{"engine": "fake"},
+	}
+
+	schema := []interfaces.ParameterSchema{
+		{
+			Name:        "language",
+			Type:        "string",
+			Required:    false,
+			Default:     "en",
+			Description: "Language reported for the transcript",
+			Group:       "basic",
+		},
+		{
+			Name:        "diarize",
+			Type:        "bool",
+			Required:    false,
+			Default:     false,
+			Description: "Alternate two speakers between segments",
+			Group:       "basic",
+		},
+		{
+			Name:        "speaker_embeddings",
+			Type:        "bool",
+			Required:    false,
+			Default:     false,
+			Description: "Output a voice embedding for each speaker",
+			Group:       "advanced",
+		},
+		{
+			Name:        "initial_prompt",
+			Type:        "string",
+			Required:    false,
+			Description: "Recorded in the transcript metadata",
+			Group:       "advanced",
+		},
+		{
+			Name:        "hotwords",
+			Type:        "string",
+			Required:    false,
+			Description: "Recorded in the transcript metadata",
+			Group:       "advanced",
+		},
+	}
+
+	return &FakeTranscriptionAdapter{BaseAdapter: NewBaseAdapter(modelID, "", capabilities, schema)}
+}
+
+// GetSupportedModels returns the fake model variant
+func (f *FakeTranscriptionAdapter) GetSupportedModels() []string {
+	return []string{"fake"}
+}
+
+// Transcribe returns one segment per fakeSegmentSeconds of audio
+func (f *FakeTranscriptionAdapter) Transcribe(ctx context.Context, input interfaces.AudioInput, params map[string]interface{}, procCtx interfaces.ProcessingContext) (*interfaces.TranscriptResult, error) {
+	startTime := time.Now()
+	rng, err := fakeRand(input)
+	if err != nil {
+		return nil, err
+	}
+
+	language := f.GetStringParameter(params, "language")
+	if language == "" {
+		language = "en"
+	}
+	diarize := f.GetBoolParameter(params, "diarize")
+
+	result := &interfaces.TranscriptResult{
+		Language:   language,
+		Confidence: 0.95,
+		ModelUsed:  f.modelID,
+		Metadata:   map[string]string{"engine": "fake"},
+	}
+	// Nothing is recognized, so the prompt and hotwords are only noted, for tests to check
+	for _, name := range []string{"initial_prompt", "hotwords"} {
+		if value := f.GetStringParameter(params, name); value != "" {
+			result.Metadata[name] = value
+		}
+	}
+
+	var texts []string
+	for i, window := range fakeWindows(input) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		text := fakeSentences[rng.Intn(len(fakeSentences))]
+		segment := interfaces.TranscriptSegment{Start: window[0], End: window[1], Text: text}
+		var speaker *string
+		if diarize {
+			name := fakeSpeaker(i)
+			speaker = &name
+			segment.Speaker = speaker
+		}
+		result.Segments = append(result.Segments, segment)
+		texts = append(texts, text)
+
+		// Spread the words evenly over the segment
+		words := strings.Fields(text)
+		step := (window[1] - window[0]) / float64(len(words))
+		for j, word := range words {
+			result.WordSegments = append(result.WordSegments, interfaces.TranscriptWord{
+				Start:   window[0] + float64(j)*step,
+				End:     window[0] + float64(j+1)*step,
+				Word:    word,
+				Score:   0.9 + 0.1*rng.Float64(),
+				Speaker: speaker,
+			})
+		}
+	}
+
+	result.Text = strings.Join(texts, " ")
+	if diarize && f.GetBoolParameter(params, "speaker_embeddings") {
+		result.SpeakerEmbeddings = fakeVoices(len(result.Segments))
+	}
+	result.ProcessingTime = time.Since(startTime)
+	return result, nil
+}
+
+// DetectLanguage reports DetectedLanguages over the segments the audio is transcribed in
+func (f *FakeTranscriptionAdapter) DetectLanguage(ctx context.Context, input interfaces.AudioInput, params map[string]interface{}, procCtx interfaces.ProcessingContext) ([]interfaces.LanguageSpan, error) {
+	languages := f.DetectedLanguages
+	if len(languages) == 0 {
+		languages = []string{"en"}
+	}
+	var spans []interfaces.LanguageSpan
+	for i, window := range fakeWindows(input) {
+		spans = append(spans, interfaces.LanguageSpan{Start: window[0], End: window[1], Language: languages[i%len(languages)], Probability: 0.95})
+	}
+	return spans, nil
+}
+
+// FakeDiarizationAdapter produces deterministic speaker turns without a model, alternating
+// two speakers every fakeSegmentSeconds
+type FakeDiarizationAdapter struct {
+	*BaseAdapter
+}
+
+// NewFakeDiarizationAdapter creates a fake diarization adapter registered under modelID
+func NewFakeDiarizationAdapter(modelID string) *FakeDiarizationAdapter {
+	capabilities := interfaces.ModelCapabilities{
+		ModelID:            modelID,
+		ModelFamily:        "fake",
+		DisplayName:        "Fake Diarizer",
+		Description:        "Deterministic speaker turns for tests and local development",
+		Version:            "1.0.0",
+		SupportedLanguages: []string{"*"},
+		SupportedFormats:   []string{"*"},
+		Features:           map[string]bool{"speaker_detection": true},
+		Metadata:           map[string]string{"engine": "fake"},
+	}
+
+	return &FakeDiarizationAdapter{BaseAdapter: NewBaseAdapter(modelID, "", capabilities, nil)}
+}
+
+// GetMaxSpeakers returns the number of speakers the fake diarizer assigns
+func (f *FakeDiarizationAdapter) GetMaxSpeakers() int {
+	return 2
+}
+
+// GetMinSpeakers returns the minimum number of speakers
+func (f *FakeDiarizationAdapter) GetMinSpeakers() int {
+	return 1
+}
+
+// Diarize returns alternating speaker turns covering the audio
+func (f *FakeDiarizationAdapter) Diarize(ctx context.Context, input interfaces.AudioInput, params map[string]interface{}, procCtx interfaces.ProcessingContext) (*interfaces.DiarizationResult, error) {
+	startTime := time.Now()
+	result := &interfaces.DiarizationResult{
+		ModelUsed: f.modelID,
+		Metadata:  map[string]string{"engine": "fake"},
+	}
+
+	speakers := map[string]bool{}
+	for i, window := range fakeWindows(input) {
+		speaker := fakeSpeaker(i)
+		result.Segments = append(result.Segments, interfaces.DiarizationSegment{
+			Start:      window[0],
+			End:        window[1],
+			Speaker:    speaker,
+			Confidence: 0.9,
+		})
+		if !speakers[speaker] {
+			speakers[speaker] = true
+			result.Speakers = append(result.Speakers, speaker)
+		}
+	}
+
+	result.SpeakerCount = len(result.Speakers)
+	if f.GetBoolParameter(params, "speaker_embeddings") {
+		result.SpeakerEmbeddings = fakeVoices(len(result.Segments))
+	}
+	result.ProcessingTime = time.Since(startTime)
+	return result, nil
+}
+
+// fakeWindows splits the audio into consecutive [start, end] windows of fakeSegmentSeconds
+func fakeWindows(input interfaces.AudioInput) [][2]float64 {
+	duration := input.Duration
+	if duration <= 0 {
+		duration = fakeDefaultDuration
+	}
+	total := duration.Seconds()
+
+	var windows [][2]float64
+	for start := 0.0; start < total; start += fakeSegmentSeconds {
+		end := start + fakeSegmentSeconds
+		if end > total {
+			end = total
+		}
+		windows = append(windows, [2]float64{start, end})
+	}
+	return windows
+}
+
+// fakeSpeaker names the speaker of the i-th window
+func fakeSpeaker(i int) string {
+	return fmt.Sprintf("SPEAKER_%02d", i%2)
+}
+
+// fakeVoiceDimensions is the length of the fake voice embeddings
+const fakeVoiceDimensions = 16
+
+// fakeVoices returns a voice embedding for each speaker of the first segments windows. A
+// speaker's voice depends only on their label, so the same speaker sounds alike in every
+// recording.
+func fakeVoices(segments int) map[string][]float32 {
+	voices := map[string][]float32{}
+	for i := 0; i < segments; i++ {
+		speaker := fakeSpeaker(i)
+		if _, ok := voices[speaker]; ok {
+			continue
+		}
+		h := fnv.New64a()
+		h.Write([]byte(speaker))
+		rng := rand.New(rand.NewSource(int64(h.Sum64())))
+		voice := make([]float32, fakeVoiceDimensions)
+		for j := range voice {
+			voice[j] = float32(rng.NormFloat64())
+		}
+		voices[speaker] = voice
+	}
+	return voices
+}
+
+// fakeRand returns a random source seeded by the first megabyte of the audio file
+func fakeRand(input interfaces.AudioInput) (*rand.Rand, error) {
+	path := input.FilePath
+	if input.TempFilePath != "" {
+		path = input.TempFilePath
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer file.Close()
+
+	h := fnv.New64a()
+	if _, err := io.Copy(h, io.LimitReader(file, 1<<20)); err != nil {
+		return nil, fmt.Errorf("failed to read audio file: %w", err)
+	}
+	return rand.New(rand.NewSource(int64(h.Sum64()))), nil
+}
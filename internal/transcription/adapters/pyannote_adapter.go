@@ -101,6 +101,14 @@ func NewPyAnnoteAdapter(envPath string) *PyAnnoteAdapter {
 			Description: "Output format for diarization results",
 			Group:       "advanced",
 		},
+		{
+			Name:        "speaker_embeddings",
+			Type:        "bool",
+			Required:    false,
+			Default:     false,
+			Description: "Output each speaker's voice embedding (JSON output only)",
+			Group:       "advanced",
+		},
 
 		// Performance settings
 		{
@@ -316,8 +324,8 @@ nemo-toolkit = { git = "https://github.com/NVIDIA/NeMo.git" }
 func (p *PyAnnoteAdapter) createDiarizationScript() error {
 	scriptPath := filepath.Join(p.envPath, "pyannote_diarize.py")
 	
-	// Check if script already exists
-	if _, err := os.Stat(scriptPath); err == nil {
+	// Check if script already exists; those written before speaker embeddings are replaced
+	if data, err := os.ReadFile(scriptPath); err == nil && strings.Contains(string(data), "--speaker-embeddings") {
 		return nil
 	}
 
@@ -343,7 +351,8 @@ def diarize_audio(
     min_speakers: int = None,
     max_speakers: int = None,
     output_format: str = "rttm",
-    device: str = "cpu"
+    device: str = "cpu",
+    speaker_embeddings: bool = False
 ):
     """
     Perform speaker diarization on audio file using PyAnnote.
@@ -397,10 +406,14 @@ def diarize_audio(
             
         if diarization_params:
             print(f"Using speaker constraints: {diarization_params}")
-            diarization = pipeline(audio_path, **diarization_params)
         else:
             print("Using automatic speaker detection")
-            diarization = pipeline(audio_path)
+
+        embeddings = None
+        if speaker_embeddings:
+            diarization, embeddings = pipeline(audio_path, return_embeddings=True, **diarization_params)
+        else:
+            diarization = pipeline(audio_path, **diarization_params)
         
         print(f"Diarization completed. Saving results to: {output_file}")
         
@@ -410,7 +423,7 @@ def diarize_audio(
                 diarization.write_rttm(rttm)
         else:
             # Save as JSON format
-            save_json_format(diarization, output_file, audio_path)
+            save_json_format(diarization, output_file, audio_path, embeddings)
         
         # Print summary
         speakers = set()
@@ -431,7 +444,7 @@ def diarize_audio(
         sys.exit(1)
 
 
-def save_json_format(diarization, output_file: str, audio_path: str):
+def save_json_format(diarization, output_file: str, audio_path: str, embeddings=None):
     """Save diarization results in JSON format."""
     segments = []
     speakers = set()
@@ -462,6 +475,15 @@ def save_json_format(diarization, output_file: str, audio_path: str):
         }
     }
     
+    if embeddings is not None:
+        # One row per speaker, in the order of diarization.labels(); speakers with too
+        # little speech for an embedding get NaNs and are left out
+        results["speaker_embeddings"] = {
+            label: [float(v) for v in embeddings[i]]
+            for i, label in enumerate(diarization.labels())
+            if i < len(embeddings) and all(v == v for v in embeddings[i])
+        }
+
     with open(output_file, "w") as f:
         json.dump(results, f, indent=2)
 
@@ -511,6 +533,11 @@ def main():
         default="cpu",
         help="Device to use for computation"
     )
+    parser.add_argument(
+        "--speaker-embeddings",
+        action="store_true",
+        help="Include each speaker's voice embedding in JSON output"
+    )
 
     args = parser.parse_args()
 
@@ -546,7 +573,8 @@ def main():
             min_speakers=args.min_speakers,
             max_speakers=args.max_speakers,
             output_format=args.output_format,
-            device=args.device
+            device=args.device,
+            speaker_embeddings=args.speaker_embeddings
         )
     except Exception as e:
         print(f"Error during diarization: {e}")
@@ -673,6 +701,11 @@ func (p *PyAnnoteAdapter) buildPyAnnoteArgs(input interfaces.AudioInput, params
 		args = append(args, "--device", device)
 	}
 
+	// Voice embeddings only fit in JSON output
+	if outputFormat == "json" && p.GetBoolParameter(params, "speaker_embeddings") {
+		args = append(args, "--speaker-embeddings")
+	}
+
 	return args, nil
 }
 
@@ -709,6 +742,7 @@ func (p *PyAnnoteAdapter) parseJSONResult(tempDir string) (*interfaces.Diarizati
 		Speakers      []string `json:"speakers"`
 		SpeakerCount  int      `json:"speaker_count"`
 		TotalDuration float64  `json:"total_duration"`
+		SpeakerEmbeddings map[string][]float32 `json:"speaker_embeddings,omitempty"`
 	}
 
 	if err := json.Unmarshal(data, &pyannoteResult); err != nil {
@@ -720,6 +754,7 @@ func (p *PyAnnoteAdapter) parseJSONResult(tempDir string) (*interfaces.Diarizati
 		Segments:     make([]interfaces.DiarizationSegment, len(pyannoteResult.Segments)),
 		SpeakerCount: pyannoteResult.SpeakerCount,
 		Speakers:     pyannoteResult.Speakers,
+		SpeakerEmbeddings: pyannoteResult.SpeakerEmbeddings,
 	}
 
 	for i, seg := range pyannoteResult.Segments {
@@ -177,6 +177,14 @@ func NewWhisperXAdapter(envPath string) *WhisperXAdapter {
 			Description: "Maximum number of speakers",
 			Group:       "advanced",
 		},
+		{
+			Name:        "speaker_embeddings",
+			Type:        "bool",
+			Required:    false,
+			Default:     false,
+			Description: "Output each speaker's voice embedding, for identifying them in other recordings",
+			Group:       "advanced",
+		},
 		{
 			Name:        "hf_token",
 			Type:        "string",
@@ -227,6 +235,20 @@ func NewWhisperXAdapter(envPath string) *WhisperXAdapter {
 			Description: "Beam search patience",
 			Group:       "quality",
 		},
+		{
+			Name:        "initial_prompt",
+			Type:        "string",
+			Required:    false,
+			Description: "Text to condition the first window on, e.g. names and terms to spell",
+			Group:       "quality",
+		},
+		{
+			Name:        "hotwords",
+			Type:        "string",
+			Required:    false,
+			Description: "Comma-separated words and phrases to favor while decoding",
+			Group:       "quality",
+		},
 
 		// VAD settings
 		{
@@ -431,6 +453,72 @@ func (w *WhisperXAdapter) Transcribe(ctx context.Context, input interfaces.Audio
 	return result, nil
 }
 
+// whisperxLanguageWindow is the length of audio, in seconds, each language is detected in:
+// the 30 second window Whisper reads at a time
+const whisperxLanguageWindow = 30
+
+// detectLanguageScript prints the language Whisper detects in each window of the audio, as
+// JSON, using the faster-whisper model WhisperX runs on
+const detectLanguageScript = `
+import json, sys
+from faster_whisper import WhisperModel
+from faster_whisper.audio import decode_audio
+
+path, model_name, device, compute_type, window = sys.argv[1], sys.argv[2], sys.argv[3], sys.argv[4], int(sys.argv[5])
+model = WhisperModel(model_name, device=device, compute_type=compute_type)
+audio = decode_audio(path, sampling_rate=16000)
+spans = []
+for start in range(0, len(audio), window * 16000):
+    chunk = audio[start:start + window * 16000]
+    if len(chunk) < 16000 and spans:
+        break
+    language, probability, _ = model.detect_language(chunk)
+    spans.append({"start": start / 16000, "end": (start + len(chunk)) / 16000, "language": language, "probability": probability})
+print(json.dumps(spans))
+`
+
+// DetectLanguage detects the language spoken in each 30 second window of the audio with the
+// job's Whisper model, or the small one for English-only models, which can't tell languages apart
+func (w *WhisperXAdapter) DetectLanguage(ctx context.Context, input interfaces.AudioInput, params map[string]interface{}, procCtx interfaces.ProcessingContext) ([]interfaces.LanguageSpan, error) {
+	if err := w.ValidateAudioInput(input); err != nil {
+		return nil, fmt.Errorf("invalid audio input: %w", err)
+	}
+	model := w.GetStringParameter(params, "model")
+	if model == "" || strings.HasSuffix(model, ".en") {
+		model = "small"
+	}
+	device := w.GetStringParameter(params, "device")
+	if device == "" || device == "mps" {
+		device = "cpu"
+	}
+
+	whisperxPath := filepath.Join(w.envPath, "WhisperX")
+	args := []string{
+		"run", "--native-tls", "--project", whisperxPath, "python", "-c", detectLanguageScript,
+		input.FilePath, model, device, w.GetStringParameter(params, "compute_type"), strconv.Itoa(whisperxLanguageWindow),
+	}
+	cmd := exec.CommandContext(ctx, "uv", args...)
+	cmd.Env = append(os.Environ(), "PYTHONUNBUFFERED=1")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if ctx.Err() == context.Canceled {
+		return nil, fmt.Errorf("language detection was cancelled")
+	}
+	if err != nil {
+		logger.Error("WhisperX language detection failed", "output", stderr.String(), "error", err)
+		return nil, fmt.Errorf("language detection failed: %w", err)
+	}
+
+	// The JSON is the last line printed; libraries may log before it
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	var spans []interfaces.LanguageSpan
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &spans); err != nil {
+		return nil, fmt.Errorf("failed to parse language detection output: %w", err)
+	}
+	return spans, nil
+}
+
 // buildWhisperXArgs builds the command arguments for WhisperX
 func (w *WhisperXAdapter) buildWhisperXArgs(input interfaces.AudioInput, params map[string]interface{}, outputDir string) ([]string, error) {
 	whisperxPath := filepath.Join(w.envPath, "WhisperX")
@@ -483,6 +571,9 @@ func (w *WhisperXAdapter) buildWhisperXArgs(input interfaces.AudioInput, params
 		if maxSpeakers := w.GetIntParameter(params, "max_speakers"); maxSpeakers > 0 {
 			args = append(args, "--max_speakers", strconv.Itoa(maxSpeakers))
 		}
+		if w.GetBoolParameter(params, "speaker_embeddings") {
+			args = append(args, "--speaker_embeddings")
+		}
 	}
 
 	// Quality settings
@@ -490,6 +581,12 @@ func (w *WhisperXAdapter) buildWhisperXArgs(input interfaces.AudioInput, params
 	args = append(args, "--best_of", strconv.Itoa(w.GetIntParameter(params, "best_of")))
 	args = append(args, "--beam_size", strconv.Itoa(w.GetIntParameter(params, "beam_size")))
 	args = append(args, "--patience", fmt.Sprintf("%.2f", w.GetFloatParameter(params, "patience")))
+	if prompt := w.GetStringParameter(params, "initial_prompt"); prompt != "" {
+		args = append(args, "--initial_prompt", prompt)
+	}
+	if hotwords := w.GetStringParameter(params, "hotwords"); hotwords != "" {
+		args = append(args, "--hotwords", hotwords)
+	}
 
 	// HuggingFace token
 	if hfToken := w.GetStringParameter(params, "hf_token"); hfToken != "" {
@@ -539,6 +636,7 @@ func (w *WhisperXAdapter) parseResult(outputDir string, input interfaces.AudioIn
 		} `json:"word_segments,omitempty"`
 		Language string `json:"language"`
 		Text     string `json:"text,omitempty"`
+		SpeakerEmbeddings map[string][]float32 `json:"speaker_embeddings,omitempty"`
 	}
 
 	if err := json.Unmarshal(data, &whisperxResult); err != nil {
@@ -551,6 +649,7 @@ func (w *WhisperXAdapter) parseResult(outputDir string, input interfaces.AudioIn
 		Segments:   make([]interfaces.TranscriptSegment, len(whisperxResult.Segments)),
 		WordSegments: make([]interfaces.TranscriptWord, len(whisperxResult.Word)),
 		Confidence: 0.0, // WhisperX doesn't provide overall confidence
+		SpeakerEmbeddings: whisperxResult.SpeakerEmbeddings,
 	}
 
 	// Convert segments
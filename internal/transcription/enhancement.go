@@ -0,0 +1,71 @@
+package transcription
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"scriberr/internal/audio"
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/pkg/logger"
+)
+
+// AnalyzeJobAudioQuality runs the quality check on a job's audio and stores the report on the job
+func AnalyzeJobAudioQuality(ctx context.Context, jobID, audioPath string) (*audio.QualityReport, error) {
+	report, err := audio.NewQualityAnalyzer().Analyze(ctx, audioPath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.DB.Model(&models.TranscriptionJob{}).
+		Where("id = ?", jobID).
+		Update("audio_quality", string(data)).Error; err != nil {
+		return nil, err
+	}
+
+	if len(report.Warnings) > 0 {
+		logger.Warn("Audio quality warnings", "job_id", jobID, "warnings", strings.Join(report.Warnings, "; "))
+	}
+	return report, nil
+}
+
+// enhanceIfPoor writes an enhanced copy of the job's audio when its quality report flags it as poor.
+// It returns the path of the enhanced file, or "" when the original audio should be used.
+func (u *UnifiedTranscriptionService) enhanceIfPoor(ctx context.Context, job *models.TranscriptionJob) string {
+	var report *audio.QualityReport
+	if job.AudioQuality != nil {
+		var stored audio.QualityReport
+		if err := json.Unmarshal([]byte(*job.AudioQuality), &stored); err == nil {
+			report = &stored
+		}
+	}
+
+	// The upload-time check may not have finished (or run) yet
+	if report == nil {
+		analyzed, err := AnalyzeJobAudioQuality(ctx, job.ID, job.AudioPath)
+		if err != nil {
+			logger.Warn("Audio quality check failed, skipping enhancement", "job_id", job.ID, "error", err)
+			return ""
+		}
+		report = analyzed
+	}
+
+	if !report.Poor {
+		return ""
+	}
+
+	outputPath := filepath.Join(u.tempDirectory, job.ID+"_enhanced.wav")
+	if err := audio.NewQualityAnalyzer().Enhance(ctx, job.AudioPath, outputPath); err != nil {
+		logger.Warn("Audio enhancement failed, using original audio", "job_id", job.ID, "error", err)
+		return ""
+	}
+
+	logger.Info("Applied audio enhancement", "job_id", job.ID, "warnings", strings.Join(report.Warnings, "; "))
+	return outputPath
+}
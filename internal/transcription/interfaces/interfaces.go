@@ -83,6 +83,16 @@ type TranscriptResult struct {
 	ProcessingTime time.Duration    `json:"processing_time"`
 	ModelUsed    string             `json:"model_used"`
 	Metadata     map[string]string  `json:"metadata"`
+	Silences     []SilenceGap       `json:"silences,omitempty"` // Left out before transcription
+	// SpeakerEmbeddings are the voice embeddings of the diarized speakers, by label, when the
+	// model was asked for them; they're kept apart from the transcript
+	SpeakerEmbeddings map[string][]float32 `json:"-"`
+}
+
+// SilenceGap is a stretch of silence in a recording that wasn't transcribed
+type SilenceGap struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
 }
 
 // DiarizationSegment represents speaker diarization information
@@ -101,6 +111,8 @@ type DiarizationResult struct {
 	ProcessingTime time.Duration        `json:"processing_time"`
 	ModelUsed      string               `json:"model_used"`
 	Metadata       map[string]string    `json:"metadata"`
+	// SpeakerEmbeddings are the voice embeddings of the speakers, by label, when asked for
+	SpeakerEmbeddings map[string][]float32 `json:"speaker_embeddings,omitempty"`
 }
 
 // ProcessingContext contains context information for processing
@@ -170,6 +182,22 @@ type CompositeAdapter interface {
 	ProcessCombined(ctx context.Context, input AudioInput, params map[string]interface{}, procCtx ProcessingContext) (*TranscriptResult, *DiarizationResult, error)
 }
 
+// LanguageSpan is the language spoken over a stretch of a recording
+type LanguageSpan struct {
+	Start       float64 `json:"start"`
+	End         float64 `json:"end"`
+	Language    string  `json:"language"`
+	Probability float64 `json:"probability"`
+}
+
+// LanguageDetector is implemented by transcription adapters that can tell which language is
+// spoken in a recording before transcribing it
+type LanguageDetector interface {
+	// DetectLanguage returns the language spoken in each stretch of the audio, in order. params
+	// are the ones the audio would be transcribed with.
+	DetectLanguage(ctx context.Context, input AudioInput, params map[string]interface{}, procCtx ProcessingContext) ([]LanguageSpan, error)
+}
+
 // ModelRequirements specifies what capabilities are needed for a job
 type ModelRequirements struct {
 	Language         string            `json:"language"`
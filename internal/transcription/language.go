@@ -0,0 +1,262 @@
+package transcription
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+)
+
+// languageModelFamilies are the model families recordings can be routed to by language
+var languageModelFamilies = map[string]bool{"whisper": true, "nvidia_parakeet": true, "nvidia_canary": true}
+
+// Languages spoken in less than minLanguageShare of a recording, and stretches detected with
+// less than minSpanProbability, are taken for detection noise and counted as the main language
+const (
+	minLanguageShare   = 0.1
+	minSpanProbability = 0.5
+)
+
+// LanguageRoute is the model configuration recordings in a language are transcribed with
+type LanguageRoute struct {
+	ModelFamily string
+	Model       string // Empty keeps the job's model
+}
+
+// ParseLanguageModels parses a comma-separated list of language=family routes, each family
+// optionally followed by :model, with * standing for any other language. For example
+// "en=nvidia_parakeet,*=whisper:large-v3" transcribes English with Parakeet and the rest with
+// Whisper large-v3.
+func ParseLanguageModels(spec string) (map[string]LanguageRoute, error) {
+	routes := map[string]LanguageRoute{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		language, target, ok := strings.Cut(entry, "=")
+		language = strings.ToLower(strings.TrimSpace(language))
+		if !ok || language == "" {
+			return nil, fmt.Errorf("invalid language route %q: expected language=family[:model]", entry)
+		}
+		family, model, _ := strings.Cut(strings.TrimSpace(target), ":")
+		if !languageModelFamilies[family] {
+			return nil, fmt.Errorf("invalid language route %q: unknown model family %q", entry, family)
+		}
+		if _, exists := routes[language]; exists {
+			return nil, fmt.Errorf("language %q is routed twice", language)
+		}
+		routes[language] = LanguageRoute{ModelFamily: family, Model: strings.TrimSpace(model)}
+	}
+	return routes, nil
+}
+
+// SetLanguageDetection turns on detecting the language of recordings before transcription.
+// A job's own language setting is overridden when the detection is at least overrideConfidence
+// sure it's wrong; routes choose the model family by language.
+func (u *UnifiedTranscriptionService) SetLanguageDetection(overrideConfidence float64, routes map[string]LanguageRoute) {
+	u.languageDetection = true
+	u.languageOverrideConfidence = overrideConfidence
+	u.languageRoutes = routes
+}
+
+// languageDetection sums up the languages detected in a recording
+type languageDetection struct {
+	Language   string   // Spoken most
+	Confidence float64  // Mean probability of the stretches in Language, weighted by length
+	Languages  []string // Every language spoken, most first
+	Spans      []interfaces.LanguageSpan
+}
+
+// summarizeLanguages finds the main language of a recording from the languages detected in
+// its stretches, and relabels the stretches taken for noise with it. It returns nil when
+// nothing was detected.
+func summarizeLanguages(spans []interfaces.LanguageSpan) *languageDetection {
+	durations := map[string]float64{}
+	total := 0.0
+	for _, span := range spans {
+		if span.Language == "" || span.End <= span.Start {
+			continue
+		}
+		durations[strings.ToLower(span.Language)] += span.End - span.Start
+		total += span.End - span.Start
+	}
+	if total == 0 {
+		return nil
+	}
+
+	var languages []string
+	for language := range durations {
+		languages = append(languages, language)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if durations[languages[i]] != durations[languages[j]] {
+			return durations[languages[i]] > durations[languages[j]]
+		}
+		return languages[i] < languages[j]
+	})
+	detection := &languageDetection{Language: languages[0]}
+	for _, language := range languages {
+		if durations[language]/total >= minLanguageShare {
+			detection.Languages = append(detection.Languages, language)
+		}
+	}
+
+	heard := 0.0
+	for _, span := range spans {
+		if span.Language == "" || span.End <= span.Start {
+			continue
+		}
+		span.Language = strings.ToLower(span.Language)
+		if span.Probability < minSpanProbability || durations[span.Language]/total < minLanguageShare {
+			span.Language = detection.Language
+		}
+		if span.Language == detection.Language {
+			detection.Confidence += span.Probability * (span.End - span.Start)
+			heard += span.End - span.Start
+		}
+		detection.Spans = append(detection.Spans, span)
+	}
+	detection.Confidence /= heard
+	return detection
+}
+
+// detectLanguage detects the languages spoken in the audio, with the job's transcription model
+// if it can, or else the default one. It returns nil when detection is off, no model can
+// detect languages or the detection failed; the job is then transcribed as it asks.
+func (u *UnifiedTranscriptionService) detectLanguage(ctx context.Context, job *models.TranscriptionJob, input interfaces.AudioInput, modelID string, procCtx interfaces.ProcessingContext) *languageDetection {
+	if !u.languageDetection {
+		return nil
+	}
+	for _, id := range []string{modelID, u.defaultModelIDs["transcription"]} {
+		adapter, err := u.registry.GetTranscriptionAdapter(id)
+		if err != nil {
+			continue
+		}
+		detector, ok := adapter.(interfaces.LanguageDetector)
+		if !ok {
+			continue
+		}
+		spans, err := detector.DetectLanguage(ctx, input, u.convertParametersForModel(job.Parameters, id), procCtx)
+		if err != nil {
+			logger.Warn("Language detection failed, transcribing with the job's language", "job_id", job.ID, "model_id", id, "error", err)
+			return nil
+		}
+		return summarizeLanguages(spans)
+	}
+	logger.Debug("No model can detect languages", "job_id", job.ID, "model_id", modelID)
+	return nil
+}
+
+// applyLanguage stores the detected language on the job and returns the parameters to
+// transcribe it with: the detected language, unless the job set another one and the detection
+// isn't sure enough to override it, and the model family that language is routed to. Without
+// a route, a language the job's model doesn't support is transcribed with Whisper.
+func (u *UnifiedTranscriptionService) applyLanguage(job *models.TranscriptionJob, detection *languageDetection) models.WhisperXParams {
+	params := job.Parameters
+	data := map[string]interface{}{
+		"language":   detection.Language,
+		"confidence": detection.Confidence,
+		"languages":  detection.Languages,
+	}
+
+	language := detection.Language
+	if requested := requestedLanguage(params); requested != "" && !strings.EqualFold(requested, language) {
+		if detection.Confidence < u.languageOverrideConfidence {
+			language = requested
+		} else {
+			logger.Warn("Detected language differs from the job's, transcribing in the detected one",
+				"job_id", job.ID, "requested", requested, "detected", language, "confidence", detection.Confidence)
+			data["requested_language"] = requested
+		}
+	}
+	params.Language = &language
+
+	route, ok := u.languageRoutes[strings.ToLower(language)]
+	if !ok {
+		route, ok = u.languageRoutes["*"]
+	}
+	if ok {
+		params.ModelFamily = route.ModelFamily
+		if route.Model != "" {
+			params.Model = route.Model
+		}
+	} else if !u.supportsLanguage(params, language) {
+		params.ModelFamily = "whisper"
+	}
+	if params.ModelFamily != job.Parameters.ModelFamily {
+		data["model_family"] = params.ModelFamily
+	}
+
+	job.DetectedLanguage = &detection.Language
+	job.LanguageConfidence = &detection.Confidence
+	job.Languages = detection.Languages
+	if err := database.DB.Model(&models.TranscriptionJob{}).Where("id = ?", job.ID).Updates(&models.TranscriptionJob{
+		DetectedLanguage:   job.DetectedLanguage,
+		LanguageConfidence: job.LanguageConfidence,
+		Languages:          job.Languages,
+	}).Error; err != nil {
+		logger.Warn("Failed to save detected language", "job_id", job.ID, "error", err)
+	}
+	logger.Info("Detected language", "job_id", job.ID, "language", detection.Language,
+		"confidence", detection.Confidence, "languages", detection.Languages, "model_family", params.ModelFamily)
+	reportProgress(job.ID, "detecting_language", 15, data)
+	return params
+}
+
+// requestedLanguage returns the language a job asks to be transcribed in, or "" when it leaves
+// it to detection
+func requestedLanguage(params models.WhisperXParams) string {
+	if params.Language == nil || strings.EqualFold(*params.Language, "auto") {
+		return ""
+	}
+	return strings.TrimSpace(*params.Language)
+}
+
+// supportsLanguage reports whether the transcription model params select supports a language
+func (u *UnifiedTranscriptionService) supportsLanguage(params models.WhisperXParams, language string) bool {
+	modelID, _, err := u.selectModels(params)
+	if err != nil {
+		return false
+	}
+	adapter, err := u.registry.GetTranscriptionAdapter(modelID)
+	if err != nil {
+		return true // Whichever model runs instead decides
+	}
+	supported := adapter.GetCapabilities().SupportedLanguages
+	if len(supported) == 0 {
+		return true
+	}
+	for _, code := range supported {
+		if code == "*" || strings.EqualFold(code, language) {
+			return true
+		}
+	}
+	return false
+}
+
+// tagLanguages sets the language of each segment to that of the detected stretch it overlaps
+// most, so recordings that switch between languages show where they do
+func tagLanguages(result *interfaces.TranscriptResult, detection *languageDetection) {
+	if result == nil || detection == nil {
+		return
+	}
+	if result.Language == "" {
+		result.Language = detection.Language
+	}
+	for i := range result.Segments {
+		segment := &result.Segments[i]
+		language, best := detection.Language, 0.0
+		for _, span := range detection.Spans {
+			if overlap := min(segment.End, span.End) - max(segment.Start, span.Start); overlap > best {
+				language, best = span.Language, overlap
+			}
+		}
+		segment.Language = &language
+	}
+}
@@ -0,0 +1,56 @@
+package transcription
+
+import (
+	"reflect"
+	"testing"
+
+	"scriberr/internal/transcription/interfaces"
+)
+
+func TestParseLanguageModels(t *testing.T) {
+	routes, err := ParseLanguageModels(" en=nvidia_parakeet, *=whisper:large-v3 ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]LanguageRoute{
+		"en": {ModelFamily: "nvidia_parakeet"},
+		"*":  {ModelFamily: "whisper", Model: "large-v3"},
+	}
+	if !reflect.DeepEqual(routes, want) {
+		t.Errorf("got %+v, want %+v", routes, want)
+	}
+
+	for _, spec := range []string{"en", "en=gpt", "=whisper", "en=whisper,EN=nvidia_canary"} {
+		if _, err := ParseLanguageModels(spec); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}
+
+func TestSummarizeLanguages(t *testing.T) {
+	spans := []interfaces.LanguageSpan{
+		{Start: 0, End: 30, Language: "en", Probability: 0.9},
+		{Start: 30, End: 60, Language: "ES", Probability: 0.8},
+		{Start: 60, End: 90, Language: "en", Probability: 0.7},
+		{Start: 90, End: 92, Language: "fr", Probability: 0.9},  // Too short to count
+		{Start: 92, End: 100, Language: "de", Probability: 0.3}, // Too unsure to count
+	}
+	detection := summarizeLanguages(spans)
+	if detection.Language != "en" || !reflect.DeepEqual(detection.Languages, []string{"en", "es"}) {
+		t.Fatalf("unexpected detection %+v", detection)
+	}
+	var labels []string
+	for _, span := range detection.Spans {
+		labels = append(labels, span.Language)
+	}
+	if !reflect.DeepEqual(labels, []string{"en", "es", "en", "en", "en"}) {
+		t.Errorf("unexpected span languages %v", labels)
+	}
+	if want := (0.9*30 + 0.7*30 + 0.9*2 + 0.3*8) / 70; detection.Confidence < want-1e-9 || detection.Confidence > want+1e-9 {
+		t.Errorf("got confidence %f, want %f", detection.Confidence, want)
+	}
+
+	if summarizeLanguages(nil) != nil {
+		t.Error("no spans should give no detection")
+	}
+}
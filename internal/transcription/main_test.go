@@ -0,0 +1,25 @@
+package transcription
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"scriberr/internal/transcription/adapters"
+	"scriberr/internal/transcription/registry"
+)
+
+// TestMain registers the adapters the same way cmd/server does, since adapters
+// no longer self-register and need their environment path injected.
+func TestMain(m *testing.M) {
+	envPath := filepath.Join(os.TempDir(), "scriberr-adapter-tests")
+	nvidiaEnvPath := filepath.Join(envPath, "parakeet")
+
+	registry.RegisterTranscriptionAdapter("whisperx", adapters.NewWhisperXAdapter(envPath))
+	registry.RegisterTranscriptionAdapter("parakeet", adapters.NewParakeetAdapter(nvidiaEnvPath))
+	registry.RegisterTranscriptionAdapter("canary", adapters.NewCanaryAdapter(nvidiaEnvPath))
+	registry.RegisterDiarizationAdapter("pyannote", adapters.NewPyAnnoteAdapter(nvidiaEnvPath))
+	registry.RegisterDiarizationAdapter("sortformer", adapters.NewSortformerAdapter(nvidiaEnvPath))
+
+	os.Exit(m.Run())
+}
@@ -15,6 +15,7 @@ import (
 	"scriberr/internal/database"
 	"scriberr/internal/models"
 	"scriberr/internal/transcription/interfaces"
+	"scriberr/internal/versions"
 	"scriberr/pkg/logger"
 
 	"gorm.io/gorm"
@@ -94,6 +95,9 @@ func (mt *MultiTrackTranscriber) ProcessMultiTrackTranscription(ctx context.Cont
 
 	for i, trackFile := range job.MultiTrackFiles {
 		trackStartTime := time.Now()
+		reportProgress(jobID, "transcribing", 5+85*i/len(job.MultiTrackFiles), map[string]interface{}{
+			"track": i + 1, "tracks": len(job.MultiTrackFiles),
+		})
 		
 		logger.Info("Processing track",
 			"job_id", jobID,
@@ -157,6 +161,7 @@ func (mt *MultiTrackTranscriber) ProcessMultiTrackTranscription(ctx context.Cont
 	}
 
 	// Merge all track transcripts with timing
+	reportProgress(jobID, "merging", 90, nil)
 	mergeStartTime := time.Now()
 	logger.Info("Merging track transcripts", "job_id", jobID, "tracks_count", len(trackTranscripts))
 
@@ -192,14 +197,20 @@ func (mt *MultiTrackTranscriber) ProcessMultiTrackTranscription(ctx context.Cont
 		// Don't fail the entire job for speaker mapping issues, just log the warning
 	}
 
-	// Save results to database
+	// Save results to database, keeping the merged transcript as a new version
 	updates := map[string]interface{}{
-		"transcript":             &mergedTranscriptStr,
 		"individual_transcripts": &individualTranscriptsStr,
 		"status":                 models.StatusCompleted,
 	}
 
-	if err := mt.db.Model(&models.TranscriptionJob{}).Where("id = ?", jobID).Updates(updates).Error; err != nil {
+	err = mt.db.Transaction(func(tx *gorm.DB) error {
+		content := versions.Content{Text: mergedTranscriptStr}
+		if _, err := versions.Save(tx, jobID, models.VersionKindTranscript, content, models.VersionSourceTranscription, nil); err != nil {
+			return err
+		}
+		return tx.Model(&models.TranscriptionJob{}).Where("id = ?", jobID).Updates(updates).Error
+	})
+	if err != nil {
 		return fmt.Errorf("failed to save transcription results: %w", err)
 	}
 
@@ -0,0 +1,82 @@
+package transcription
+
+import (
+	"context"
+	"path/filepath"
+
+	"scriberr/internal/audio"
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/pkg/logger"
+)
+
+// SetDefaultPreprocessing sets the preprocessing steps for jobs whose parameters don't choose any
+func (u *UnifiedTranscriptionService) SetDefaultPreprocessing(steps []string) {
+	u.defaultPreprocessing = steps
+}
+
+// SetSilenceDetection sets the level below which audio counts as silence, and the shortest
+// silence skip_silence leaves out, in seconds
+func (u *UnifiedTranscriptionService) SetSilenceDetection(thresholdDb, minSkippedSeconds float64) {
+	u.silenceThresholdDb = &thresholdDb
+	u.minSkippedSilence = minSkippedSeconds
+}
+
+// preprocessingSteps returns the preprocessing steps a job asks for, or the default ones
+func (u *UnifiedTranscriptionService) preprocessingSteps(params models.WhisperXParams) []string {
+	if params.Preprocess == "" {
+		return u.defaultPreprocessing
+	}
+	steps, err := audio.ParsePreprocessSteps(params.Preprocess)
+	if err != nil {
+		logger.Warn("Ignoring invalid preprocessing steps", "preprocess", params.Preprocess, "error", err)
+		return nil
+	}
+	return steps
+}
+
+// preprocess writes a copy of the audio with the job's preprocessing steps applied. It returns
+// the path of that copy, or "" when the audio should be used as it is, and what was done.
+func (u *UnifiedTranscriptionService) preprocess(ctx context.Context, job *models.TranscriptionJob, audioPath string) (string, *audio.PreprocessResult) {
+	steps := u.preprocessingSteps(job.Parameters)
+	if len(steps) == 0 {
+		return "", nil
+	}
+
+	outputPath := filepath.Join(u.tempDirectory, job.ID+"_preprocessed.wav")
+	preprocessor := audio.NewPreprocessor()
+	if u.silenceThresholdDb != nil {
+		preprocessor.SilenceThresholdDb = *u.silenceThresholdDb
+	}
+	if u.minSkippedSilence > 0 {
+		preprocessor.MinSkippedSilence = u.minSkippedSilence
+	}
+	result, err := preprocessor.Run(ctx, audioPath, outputPath, steps)
+	if err != nil {
+		logger.Warn("Audio preprocessing failed, using original audio", "job_id", job.ID, "error", err)
+		return "", nil
+	}
+
+	logger.Info("Preprocessed audio", "job_id", job.ID, "steps", steps,
+		"trimmed_start", result.TrimmedStart, "trimmed_end", result.TrimmedEnd, "skipped_seconds", result.SkippedSeconds())
+	return outputPath, result
+}
+
+// mapTranscript moves the timestamps of a transcript of preprocessed audio back to the
+// original recording, and records the silences preprocessing left out
+func mapTranscript(result *interfaces.TranscriptResult, preprocessed *audio.PreprocessResult) {
+	if result == nil || preprocessed == nil || len(preprocessed.Kept) == 0 {
+		return
+	}
+	for i := range result.Segments {
+		result.Segments[i].Start = preprocessed.OriginalTime(result.Segments[i].Start, false)
+		result.Segments[i].End = preprocessed.OriginalTime(result.Segments[i].End, true)
+	}
+	for i := range result.WordSegments {
+		result.WordSegments[i].Start = preprocessed.OriginalTime(result.WordSegments[i].Start, false)
+		result.WordSegments[i].End = preprocessed.OriginalTime(result.WordSegments[i].End, true)
+	}
+	for _, removed := range preprocessed.Removed {
+		result.Silences = append(result.Silences, interfaces.SilenceGap{Start: removed.Start, End: removed.End})
+	}
+}
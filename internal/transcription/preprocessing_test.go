@@ -0,0 +1,49 @@
+package transcription
+
+import (
+	"reflect"
+	"testing"
+
+	"scriberr/internal/audio"
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+)
+
+func TestPreprocessingSteps(t *testing.T) {
+	u := &UnifiedTranscriptionService{}
+	u.SetDefaultPreprocessing([]string{audio.StepNormalize})
+
+	if steps := u.preprocessingSteps(models.WhisperXParams{}); !reflect.DeepEqual(steps, []string{audio.StepNormalize}) {
+		t.Errorf("expected the default steps, got %v", steps)
+	}
+	if steps := u.preprocessingSteps(models.WhisperXParams{Preprocess: "none"}); len(steps) != 0 {
+		t.Errorf("expected no steps, got %v", steps)
+	}
+	if steps := u.preprocessingSteps(models.WhisperXParams{Preprocess: "resample,trim_silence"}); !reflect.DeepEqual(steps, []string{audio.StepTrimSilence, audio.StepResample}) {
+		t.Errorf("expected the job's steps, got %v", steps)
+	}
+}
+
+func TestMapTranscript(t *testing.T) {
+	result := &interfaces.TranscriptResult{
+		Segments:     []interfaces.TranscriptSegment{{Start: 0.1, End: 2}, {Start: 3, End: 4}},
+		WordSegments: []interfaces.TranscriptWord{{Start: 0.1, End: 0.5}, {Start: 3, End: 3.5}},
+	}
+	mapTranscript(result, &audio.PreprocessResult{
+		Kept:    []audio.Region{{Start: 3.25, End: 5.25}, {Start: 100, End: 110}},
+		Removed: []audio.Region{{Start: 0, End: 3.25}, {Start: 5.25, End: 100}},
+	})
+
+	if result.Segments[0].Start != 3.35 || result.Segments[0].End != 5.25 {
+		t.Errorf("first segment not mapped: %+v", result.Segments[0])
+	}
+	if result.Segments[1].Start != 101 || result.Segments[1].End != 102 {
+		t.Errorf("second segment not mapped past the skipped silence: %+v", result.Segments[1])
+	}
+	if result.WordSegments[0].Start != 3.35 || result.WordSegments[1].End != 101.5 {
+		t.Errorf("words not mapped: %+v", result.WordSegments)
+	}
+	if want := []interfaces.SilenceGap{{Start: 0, End: 3.25}, {Start: 5.25, End: 100}}; !reflect.DeepEqual(result.Silences, want) {
+		t.Errorf("expected the skipped silences recorded, got %v", result.Silences)
+	}
+}
@@ -11,7 +11,9 @@ import (
 
 	"scriberr/internal/config"
 	"scriberr/internal/database"
+	"scriberr/internal/export"
 	"scriberr/internal/models"
+	"scriberr/internal/quota"
 
 	"github.com/google/uuid"
 )
@@ -26,6 +28,9 @@ type QuickTranscriptionJob struct {
 	CreatedAt    time.Time             `json:"created_at"`
 	ExpiresAt    time.Time             `json:"expires_at"`
 	ErrorMessage *string               `json:"error_message,omitempty"`
+
+	userID *uint         // The audio is metered against this user's quota
+	done   chan struct{} // Closed once processing has finished
 }
 
 // QuickTranscriptionService handles temporary transcriptions without database persistence
@@ -61,8 +66,9 @@ func NewQuickTranscriptionService(cfg *config.Config, unifiedProcessor *UnifiedJ
 	return service, nil
 }
 
-// SubmitQuickJob creates and processes a temporary transcription job
-func (qs *QuickTranscriptionService) SubmitQuickJob(audioData io.Reader, filename string, params models.WhisperXParams) (*QuickTranscriptionJob, error) {
+// SubmitQuickJob creates and processes a temporary transcription job for userID, whose audio
+// minutes it counts once transcribed
+func (qs *QuickTranscriptionService) SubmitQuickJob(audioData io.Reader, filename string, params models.WhisperXParams, userID *uint) (*QuickTranscriptionJob, error) {
 	// Generate unique job ID
 	jobID := uuid.New().String()
 
@@ -92,6 +98,8 @@ func (qs *QuickTranscriptionService) SubmitQuickJob(audioData io.Reader, filenam
 		Parameters: params,
 		CreatedAt:  now,
 		ExpiresAt:  now.Add(6 * time.Hour),
+		userID:     userID,
+		done:       make(chan struct{}),
 	}
 
 	// Store in memory
@@ -100,7 +108,10 @@ func (qs *QuickTranscriptionService) SubmitQuickJob(audioData io.Reader, filenam
 	qs.jobsMutex.Unlock()
 
 	// Start processing in background
-	go qs.processQuickJob(jobID)
+	go func() {
+		defer close(job.done)
+		qs.processQuickJob(jobID)
+	}()
 
 	return job, nil
 }
@@ -123,6 +134,33 @@ func (qs *QuickTranscriptionService) GetQuickJob(jobID string) (*QuickTranscript
 	return job, nil
 }
 
+// WaitQuickJob blocks until a quick transcription job has finished, successfully or not, and
+// returns it. It gives up when ctx is done; the job carries on regardless.
+func (qs *QuickTranscriptionService) WaitQuickJob(ctx context.Context, jobID string) (*QuickTranscriptionJob, error) {
+	job, err := qs.GetQuickJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-job.done:
+		return job, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// RemoveQuickJob forgets a finished quick transcription job and deletes its files before it
+// expires, for callers that have taken the transcript
+func (qs *QuickTranscriptionService) RemoveQuickJob(jobID string) {
+	qs.jobsMutex.Lock()
+	defer qs.jobsMutex.Unlock()
+
+	if job, exists := qs.jobs[jobID]; exists {
+		qs.removeJobFiles(job)
+		delete(qs.jobs, jobID)
+	}
+}
+
 // processQuickJob processes a quick transcription job
 func (qs *QuickTranscriptionService) processQuickJob(jobID string) {
 	// Update job status to processing
@@ -176,8 +214,10 @@ func (qs *QuickTranscriptionService) processQuickJob(jobID string) {
 	// Load the processed result back
 	var processedJob models.TranscriptionJob
 	if loadErr := database.DB.Where("id = ?", jobID).First(&processedJob).Error; loadErr == nil {
-		// Copy result back to quick job if successful
-		if err == nil && processedJob.Status == models.StatusCompleted {
+		// Copy result back to quick job if successful. The status is left for the queue to set,
+		// so success is told by the error alone.
+		if err == nil {
+			quota.RecordAudio(job.userID, export.TranscriptSegments(&processedJob))
 			if processedJob.Transcript != nil {
 				// Save transcript to temp file for loadTranscriptFromTemp
 				transcriptPath := filepath.Join(qs.tempDir, jobID+"_transcript.json")
@@ -186,7 +226,9 @@ func (qs *QuickTranscriptionService) processQuickJob(jobID string) {
 		}
 	}
 	
-	// Clean up temporary database entry
+	// Clean up temporary database entry, after the records processing made for it
+	database.DB.Where("transcription_job_id = ?", jobID).Delete(&models.TranscriptionJobExecution{})
+	database.DB.Where("transcription_job_id = ?", jobID).Delete(&models.SpeakerMapping{})
 	database.DB.Delete(&models.TranscriptionJob{}, "id = ?", jobID)
 
 	// Update job with results
@@ -245,9 +287,7 @@ func (qs *QuickTranscriptionService) cleanupExpiredJobs() {
 	for jobID, job := range qs.jobs {
 		if now.After(job.ExpiresAt) {
 			// Remove files
-			os.Remove(job.AudioPath)
-			os.Remove(filepath.Join(qs.tempDir, jobID+"_transcript.json"))
-			os.RemoveAll(filepath.Join(qs.tempDir, jobID+"_output"))
+			qs.removeJobFiles(job)
 
 			// Remove from memory
 			delete(qs.jobs, jobID)
@@ -257,6 +297,12 @@ func (qs *QuickTranscriptionService) cleanupExpiredJobs() {
 	}
 }
 
+// removeJobFiles deletes a job's audio, transcript and output files
+func (qs *QuickTranscriptionService) removeJobFiles(job *QuickTranscriptionJob) {
+	os.Remove(job.AudioPath)
+	os.Remove(filepath.Join(qs.tempDir, job.ID+"_transcript.json"))
+	os.RemoveAll(filepath.Join(qs.tempDir, job.ID+"_output"))
+}
 
 // Close stops the cleanup routine
 func (qs *QuickTranscriptionService) Close() {
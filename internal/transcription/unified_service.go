@@ -3,6 +3,7 @@ package transcription
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,18 +13,50 @@ import (
 	"time"
 
 	"scriberr/internal/database"
+	"scriberr/internal/events"
 	"scriberr/internal/models"
+	"scriberr/internal/tracing"
 	"scriberr/internal/transcription/interfaces"
 	"scriberr/internal/transcription/pipeline"
 	"scriberr/internal/transcription/registry"
+	"scriberr/internal/versions"
+	"scriberr/internal/vocabulary"
 	"scriberr/pkg/logger"
 )
 
+// CompletionHook is notified when a transcription job completes successfully
+type CompletionHook interface {
+	OnTranscriptionCompleted(jobID string)
+}
+
 // SetPostProcessingHook sets the post-processing hook
-func (u *UnifiedTranscriptionService) SetPostProcessingHook(hook *PostProcessingHook) {
+func (u *UnifiedTranscriptionService) SetPostProcessingHook(hook CompletionHook) {
 	u.postProcessingHook = hook
 }
 
+// FileStore keeps audio files and transcripts beyond the local disk, for nodes sharing them
+type FileStore interface {
+	// Fetch brings a file to the local disk unless it is there already
+	Fetch(ctx context.Context, path string) error
+	SaveTranscript(ctx context.Context, jobID, transcript string) error
+}
+
+// SetFileStore sets where audio missing from this node is fetched from and transcripts are kept
+func (u *UnifiedTranscriptionService) SetFileStore(store FileStore) {
+	u.fileStore = store
+}
+
+// SpeakerIdentifier names the speakers of a diarized recording from their voice embeddings
+type SpeakerIdentifier interface {
+	IdentifySpeakers(jobID string, embeddings map[string][]float32) error
+}
+
+// SetSpeakerIdentifier sets what names diarized speakers after known voices; with one set,
+// diarization models are asked for the speakers' voice embeddings
+func (u *UnifiedTranscriptionService) SetSpeakerIdentifier(identifier SpeakerIdentifier) {
+	u.speakerIdentifier = identifier
+}
+
 // UnifiedTranscriptionService provides a unified interface for all transcription and diarization models
 type UnifiedTranscriptionService struct {
 	registry          *registry.ModelRegistry
@@ -34,7 +67,15 @@ type UnifiedTranscriptionService struct {
 	outputDirectory   string
 	defaultModelIDs   map[string]string // Default model IDs for each task type
 	multiTrackTranscriber *MultiTrackTranscriber // For termination support
-	postProcessingHook *PostProcessingHook // Post-processing hook for RAG
+	postProcessingHook CompletionHook // Post-processing hook (workflow engine)
+	fileStore         FileStore      // Object storage shared with other nodes, if any
+	defaultPreprocessing []string    // Preprocessing steps for jobs that don't choose any
+	silenceThresholdDb *float64      // Overrides the preprocessor's silence level when set
+	minSkippedSilence  float64       // Overrides the shortest silence skip_silence leaves out when set
+	speakerIdentifier  SpeakerIdentifier // Names diarized speakers, if set
+	languageDetection  bool              // Detect the spoken language before transcription
+	languageOverrideConfidence float64   // Detection confidence needed to override a job's language
+	languageRoutes     map[string]LanguageRoute // Model configuration by detected language
 }
 
 // NewUnifiedTranscriptionService creates a new unified transcription service
@@ -77,7 +118,9 @@ func (u *UnifiedTranscriptionService) Initialize(ctx context.Context) error {
 // ProcessJob processes a transcription job using the new adapter architecture
 func (u *UnifiedTranscriptionService) ProcessJob(ctx context.Context, jobID string) error {
 	startTime := time.Now()
-	logger.Info("Processing job with unified service", "job_id", jobID)
+	// Lines logged with ctx go to the job's log
+	ctx = logger.WithComponent(logger.WithJob(ctx, jobID), "transcription")
+	logger.InfoContext(ctx, "Processing job with unified service")
 
 	// Get the job from database
 	var job models.TranscriptionJob
@@ -85,6 +128,17 @@ func (u *UnifiedTranscriptionService) ProcessJob(ctx context.Context, jobID stri
 		return fmt.Errorf("failed to get job: %w", err)
 	}
 
+	// The audio may have been uploaded through another node
+	if u.fileStore != nil && !job.IsMultiTrack && job.AudioPath != "" {
+		fetchCtx, span := tracing.Start(ctx, "storage.fetch_audio", "job.id", jobID)
+		err := u.fileStore.Fetch(fetchCtx, job.AudioPath)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
+			return fmt.Errorf("failed to get audio: %w", err)
+		}
+	}
+
 	// Create execution record
 	execution := &models.TranscriptionJobExecution{
 		TranscriptionJobID: jobID,
@@ -113,24 +167,37 @@ func (u *UnifiedTranscriptionService) ProcessJob(ctx context.Context, jobID stri
 
 	// Check for multi-track processing
 	if job.IsMultiTrack && job.Parameters.IsMultiTrackEnabled {
-		logger.Info("Processing multi-track job", "job_id", jobID)
-		if err := u.processMultiTrackJob(ctx, &job); err != nil {
+		logger.InfoContext(ctx, "Processing multi-track job")
+		trackCtx, span := tracing.Start(ctx, "transcription.multitrack", "job.id", jobID, "tracks", len(job.MultiTrackFiles))
+		err := u.processMultiTrackJob(trackCtx, &job)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
 			errMsg := fmt.Sprintf("multi-track processing failed: %v", err)
 			updateExecutionStatus(models.StatusFailed, errMsg)
-			return fmt.Errorf(errMsg)
+			return errors.New(errMsg)
 		}
 	} else {
 		// Process single track
 		if err := u.processSingleTrackJob(ctx, &job); err != nil {
 			errMsg := fmt.Sprintf("single-track processing failed: %v", err)
 			updateExecutionStatus(models.StatusFailed, errMsg)
-			return fmt.Errorf(errMsg)
+			return errors.New(errMsg)
 		}
 	}
 
 	// Success
 	updateExecutionStatus(models.StatusCompleted, "")
-	logger.Info("Job processed successfully", "job_id", jobID, "duration", time.Since(startTime))
+	logger.InfoContext(ctx, "Job processed successfully", "duration", time.Since(startTime))
+
+	if u.fileStore != nil {
+		var saved models.TranscriptionJob
+		if err := database.DB.Select("id", "transcript").Where("id = ?", jobID).First(&saved).Error; err == nil && saved.Transcript != nil {
+			if err := u.fileStore.SaveTranscript(ctx, jobID, *saved.Transcript); err != nil {
+				logger.WarnContext(ctx, "Failed to store transcript", "error", err)
+			}
+		}
+	}
 	
 	// Call post-processing hook if available
 	if u.postProcessingHook != nil {
@@ -142,7 +209,7 @@ func (u *UnifiedTranscriptionService) ProcessJob(ctx context.Context, jobID stri
 
 // processSingleTrackJob handles single audio file transcription
 func (u *UnifiedTranscriptionService) processSingleTrackJob(ctx context.Context, job *models.TranscriptionJob) error {
-	logger.Info("Processing single-track job", "job_id", job.ID, "model_family", job.Parameters.ModelFamily)
+	logger.InfoContext(ctx, "Processing single-track job", "model_family", job.Parameters.ModelFamily)
 
 	// Create processing context
 	procCtx := interfaces.ProcessingContext{
@@ -157,8 +224,48 @@ func (u *UnifiedTranscriptionService) processSingleTrackJob(ctx context.Context,
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
+	var tempFilesToCleanup []string
+
+	// Ensure cleanup of temporary files when function exits
+	defer func() {
+		for _, tempFile := range tempFilesToCleanup {
+			if err := os.Remove(tempFile); err != nil {
+				logger.WarnContext(ctx, "Failed to clean up temporary file", "file", tempFile, "error", err)
+			} else {
+				logger.InfoContext(ctx, "Cleaned up temporary file", "file", tempFile)
+			}
+		}
+	}()
+
+	reportProgress(job.ID, "preprocessing", 5, nil)
+
+	// Optionally clean up poor quality audio before anything else touches it
+	preprocessCtx, span := tracing.Start(ctx, "transcription.preprocess", "job.id", job.ID)
+	audioPath := job.AudioPath
+	if job.Parameters.AutoEnhance {
+		if enhancedPath := u.enhanceIfPoor(preprocessCtx, job); enhancedPath != "" {
+			audioPath = enhancedPath
+			tempFilesToCleanup = append(tempFilesToCleanup, enhancedPath)
+		}
+	}
+
+	// Then apply the chosen preprocessing steps, remembering which parts of the audio were
+	// kept so the timestamps can be moved back in line with the original recording
+	preprocessPath, preprocessed := u.preprocess(preprocessCtx, job, audioPath)
+	span.End()
+	if preprocessPath != "" {
+		audioPath = preprocessPath
+		tempFilesToCleanup = append(tempFilesToCleanup, preprocessPath)
+		reportProgress(job.ID, "preprocessing", 10, map[string]interface{}{
+			"steps":           preprocessed.Steps,
+			"trimmed_start":   preprocessed.TrimmedStart,
+			"trimmed_end":     preprocessed.TrimmedEnd,
+			"skipped_seconds": preprocessed.SkippedSeconds(),
+		})
+	}
+
 	// Create audio input
-	audioInput, err := u.createAudioInput(job.AudioPath)
+	audioInput, err := u.createAudioInput(audioPath)
 	if err != nil {
 		return fmt.Errorf("failed to create audio input: %w", err)
 	}
@@ -171,7 +278,6 @@ func (u *UnifiedTranscriptionService) processSingleTrackJob(ctx context.Context,
 
 	// Apply preprocessing to ensure audio is in correct format (mono 16kHz)
 	var preprocessedInput interfaces.AudioInput
-	var tempFilesToCleanup []string
 
 	// Get model capabilities for preprocessing decisions
 	var capabilities interfaces.ModelCapabilities
@@ -186,15 +292,18 @@ func (u *UnifiedTranscriptionService) processSingleTrackJob(ctx context.Context,
 	}
 
 	// Apply preprocessing
-	preprocessedInput, err = u.pipeline.ProcessAudio(ctx, audioInput, capabilities)
+	convertCtx, span := tracing.Start(ctx, "transcription.convert_audio", "job.id", job.ID)
+	preprocessedInput, err = u.pipeline.ProcessAudio(convertCtx, audioInput, capabilities)
+	span.RecordError(err)
+	span.End()
 	if err != nil {
-		logger.Warn("Audio preprocessing failed, using original", "error", err)
+		logger.WarnContext(ctx, "Audio preprocessing failed, using original", "error", err)
 		preprocessedInput = audioInput
 	} else {
 		// Track temporary file for cleanup if preprocessing created one
 		if preprocessedInput.TempFilePath != "" && preprocessedInput.TempFilePath != audioInput.FilePath {
 			tempFilesToCleanup = append(tempFilesToCleanup, preprocessedInput.TempFilePath)
-			logger.Info("Audio preprocessing completed", 
+			logger.InfoContext(ctx, "Audio preprocessing completed", 
 				"original", audioInput.FilePath,
 				"converted", preprocessedInput.TempFilePath,
 				"original_sr", audioInput.SampleRate,
@@ -204,51 +313,61 @@ func (u *UnifiedTranscriptionService) processSingleTrackJob(ctx context.Context,
 		}
 	}
 
-	// Ensure cleanup of temporary files when function exits
-	defer func() {
-		for _, tempFile := range tempFilesToCleanup {
-			if err := os.Remove(tempFile); err != nil {
-				logger.Warn("Failed to clean up temporary file", "file", tempFile, "error", err)
-			} else {
-				logger.Info("Cleaned up temporary file", "file", tempFile)
-			}
+	// Detect the spoken language, which decides the language and model it is transcribed with
+	params := job.Parameters
+	detectCtx, span := tracing.Start(ctx, "transcription.detect_language", "job.id", job.ID)
+	detection := u.detectLanguage(detectCtx, job, preprocessedInput, transcriptionModelID, procCtx)
+	span.End()
+	if detection != nil {
+		params = u.applyLanguage(job, detection)
+		if transcriptionModelID, diarizationModelID, err = u.selectModels(params); err != nil {
+			return fmt.Errorf("failed to select models: %w", err)
 		}
-	}()
+	}
 
 	var transcriptResult *interfaces.TranscriptResult
 	var diarizationResult *interfaces.DiarizationResult
 
 	// Perform transcription using the preprocessed audio
 	if transcriptionModelID != "" {
-		logger.Info("Running transcription", "model_id", transcriptionModelID)
+		logger.InfoContext(ctx, "Running transcription", "model_id", transcriptionModelID)
 		transcriptionAdapter, err := u.registry.GetTranscriptionAdapter(transcriptionModelID)
 		if err != nil {
 			return fmt.Errorf("failed to get transcription adapter: %w", err)
 		}
 
 		// Convert parameters for this specific model
-		params := u.convertParametersForModel(job.Parameters, transcriptionModelID)
-
-		transcriptResult, err = transcriptionAdapter.Transcribe(ctx, preprocessedInput, params, procCtx)
+		modelParams := u.convertParametersForModel(params, transcriptionModelID)
+		u.addVocabulary(job, modelParams)
+
+		reportProgress(job.ID, "transcribing", 20, nil)
+		transcribeCtx, span := tracing.Start(ctx, "transcription.transcribe", "job.id", job.ID, "model.id", transcriptionModelID)
+		transcriptResult, err = transcriptionAdapter.Transcribe(transcribeCtx, preprocessedInput, modelParams, procCtx)
+		span.RecordError(err)
+		span.End()
 		if err != nil {
 			return fmt.Errorf("transcription failed: %w", err)
 		}
 	}
 
 	// Perform diarization if requested and not already done by transcription
-	if job.Parameters.Diarize && diarizationModelID != "" {
+	if params.Diarize && diarizationModelID != "" {
 		// Convert parameters for diarization model
-		diarizationParams := u.convertParametersForModel(job.Parameters, diarizationModelID)
+		diarizationParams := u.convertParametersForModel(params, diarizationModelID)
 		
 		if !u.transcriptionIncludesDiarization(transcriptionModelID, diarizationParams) {
-			logger.Info("Running separate diarization", "model_id", diarizationModelID)
+			logger.InfoContext(ctx, "Running separate diarization", "model_id", diarizationModelID)
 			diarizationAdapter, err := u.registry.GetDiarizationAdapter(diarizationModelID)
 			if err != nil {
 				return fmt.Errorf("failed to get diarization adapter: %w", err)
 			}
 
 			// Use the same preprocessed audio for diarization
-			diarizationResult, err = diarizationAdapter.Diarize(ctx, preprocessedInput, diarizationParams, procCtx)
+			reportProgress(job.ID, "diarizing", 70, nil)
+			diarizeCtx, span := tracing.Start(ctx, "transcription.diarize", "job.id", job.ID, "model.id", diarizationModelID)
+			diarizationResult, err = diarizationAdapter.Diarize(diarizeCtx, preprocessedInput, diarizationParams, procCtx)
+			span.RecordError(err)
+			span.End()
 			if err != nil {
 				return fmt.Errorf("diarization failed: %w", err)
 			}
@@ -262,17 +381,49 @@ func (u *UnifiedTranscriptionService) processSingleTrackJob(ctx context.Context,
 
 	// Save results to database
 	if transcriptResult != nil {
-		if err := u.saveTranscriptionResults(job.ID, transcriptResult); err != nil {
+		tagLanguages(transcriptResult, detection)
+		mapTranscript(transcriptResult, preprocessed)
+		reportProgress(job.ID, "saving", 95, nil)
+		_, span := tracing.Start(ctx, "transcription.save", "job.id", job.ID, "segments", len(transcriptResult.Segments))
+		err := u.saveTranscriptionResults(job.ID, transcriptResult)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
 			return fmt.Errorf("failed to save transcription results: %w", err)
 		}
+
+		// Name the speakers before post-processing reads the transcript
+		if u.speakerIdentifier != nil && len(transcriptResult.SpeakerEmbeddings) > 0 {
+			_, span := tracing.Start(ctx, "transcription.identify_speakers", "job.id", job.ID, "speakers", len(transcriptResult.SpeakerEmbeddings))
+			if err := u.speakerIdentifier.IdentifySpeakers(job.ID, transcriptResult.SpeakerEmbeddings); err != nil {
+				logger.WarnContext(ctx, "Speaker identification failed", "error", err)
+				span.RecordError(err)
+			}
+			span.End()
+		}
 	}
 
 	return nil
 }
 
+// reportProgress records that a job reached a stage of processing, with a rough percentage
+// of the work done. Internal per-track jobs of multi-track recordings aren't reported; their
+// recording reports a stage per track instead.
+func reportProgress(jobID, stage string, percent int, data map[string]interface{}) {
+	if strings.HasPrefix(jobID, "track_") {
+		return
+	}
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	data["stage"] = stage
+	data["percent"] = percent
+	events.RecordForJob(models.EventJobProgress, jobID, data)
+}
+
 // processMultiTrackJob handles multi-track audio processing
 func (u *UnifiedTranscriptionService) processMultiTrackJob(ctx context.Context, job *models.TranscriptionJob) error {
-	logger.Info("Processing multi-track job", "job_id", job.ID, "track_count", len(job.MultiTrackFiles))
+	logger.InfoContext(ctx, "Processing multi-track job", "track_count", len(job.MultiTrackFiles))
 
 	// Create unified processor for this service
 	unifiedProcessor := &UnifiedJobProcessor{
@@ -546,6 +697,7 @@ func (u *UnifiedTranscriptionService) convertToWhisperXParams(params models.Whis
 		// Diarization
 		"diarize":       params.Diarize,
 		"diarize_model": params.DiarizeModel,
+		"speaker_embeddings": u.wantsSpeakerEmbeddings(params),
 		
 		// Quality settings
 		"temperature":    params.Temperature,
@@ -593,6 +745,7 @@ func (u *UnifiedTranscriptionService) convertToPyannoteParams(params models.Whis
 	paramMap := map[string]interface{}{
 		"output_format": "json",
 		"auto_convert_audio": true,
+		"speaker_embeddings": u.wantsSpeakerEmbeddings(params),
 	}
 	
 	if params.MinSpeakers != nil {
@@ -608,6 +761,31 @@ func (u *UnifiedTranscriptionService) convertToPyannoteParams(params models.Whis
 	return paramMap
 }
 
+// addVocabulary passes a job's vocabulary to the transcription engine as hotwords and at
+// the end of the initial prompt; engines that take neither ignore them
+func (u *UnifiedTranscriptionService) addVocabulary(job *models.TranscriptionJob, params map[string]interface{}) {
+	terms, err := vocabulary.ForJob(job)
+	if err != nil {
+		logger.Warn("Failed to load vocabulary", "job_id", job.ID, "error", err)
+		return
+	}
+	if len(terms) == 0 {
+		return
+	}
+	params["hotwords"] = vocabulary.Hotwords(terms)
+	prompt := vocabulary.Prompt(terms)
+	if existing, _ := params["initial_prompt"].(string); strings.TrimSpace(existing) != "" {
+		prompt = strings.TrimSpace(existing) + " " + prompt
+	}
+	params["initial_prompt"] = prompt
+}
+
+// wantsSpeakerEmbeddings tells whether to ask the diarization model for the speakers' voice
+// embeddings: when the job asks for them, or to identify the speakers
+func (u *UnifiedTranscriptionService) wantsSpeakerEmbeddings(params models.WhisperXParams) bool {
+	return params.SpeakerEmbeddings || u.speakerIdentifier != nil
+}
+
 // convertToSortformerParams converts to Sortformer-specific parameters
 func (u *UnifiedTranscriptionService) convertToSortformerParams(params models.WhisperXParams) map[string]interface{} {
 	return map[string]interface{}{
@@ -703,6 +881,9 @@ func (u *UnifiedTranscriptionService) mergeDiarizationWithTranscription(transcri
 	mergedTranscript := *transcript
 	mergedTranscript.Segments = make([]interfaces.TranscriptSegment, len(transcript.Segments))
 	copy(mergedTranscript.Segments, transcript.Segments)
+	if len(diarization.SpeakerEmbeddings) > 0 {
+		mergedTranscript.SpeakerEmbeddings = diarization.SpeakerEmbeddings
+	}
 
 	// Assign speakers to transcript segments based on timing overlap
 	for i := range mergedTranscript.Segments {
@@ -758,10 +939,9 @@ func (u *UnifiedTranscriptionService) saveTranscriptionResults(jobID string, res
 		return fmt.Errorf("failed to convert result to JSON: %w", err)
 	}
 
-	// Update the job in the database
-	if err := database.DB.Model(&models.TranscriptionJob{}).
-		Where("id = ?", jobID).
-		Update("transcript", resultJSON).Error; err != nil {
+	// Update the job in the database, keeping the transcript as a new version
+	content := versions.Content{Text: resultJSON}
+	if _, err := versions.Save(database.DB, jobID, models.VersionKindTranscript, content, models.VersionSourceTranscription, nil); err != nil {
 		return fmt.Errorf("failed to update job transcript: %w", err)
 	}
 
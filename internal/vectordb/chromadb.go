@@ -128,6 +128,41 @@ func (c *ChromaDBClient) AddDocuments(collectionName string, ids []string, docum
 	return nil
 }
 
+// UpsertDocuments adds documents to a collection, replacing any existing documents with the same IDs
+func (c *ChromaDBClient) UpsertDocuments(collectionName string, ids []string, documents []string, embeddings [][]float32, metadatas []map[string]interface{}) error {
+	reqBody := AddRequest{
+		CollectionName: collectionName,
+		IDs:            ids,
+		Documents:      documents,
+		Embeddings:     embeddings,
+		Metadatas:      metadatas,
+	}
+
+	data, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.baseURL+"/api/v1/collections/"+collectionName+"/upsert", bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
 // Query queries a collection with embeddings
 func (c *ChromaDBClient) Query(collectionName string, queryEmbeddings [][]float32, nResults int, where map[string]interface{}) (*QueryResponse, error) {
 	reqBody := QueryRequest{
@@ -218,3 +253,119 @@ func (c *ChromaDBClient) CountDocuments(collectionName string, where map[string]
 	
 	return countResp.Count, nil
 }
+
+// GetRequest represents a request to fetch documents by ID or metadata filter
+type GetRequest struct {
+	IDs     []string               `json:"ids,omitempty"`
+	Where   map[string]interface{} `json:"where,omitempty"`
+	Limit   int                    `json:"limit,omitempty"`
+	Offset  int                    `json:"offset,omitempty"`
+	Include []string               `json:"include,omitempty"`
+}
+
+// GetResponse represents the documents returned by a get request
+type GetResponse struct {
+	IDs        []string                 `json:"ids"`
+	Documents  []string                 `json:"documents"`
+	Metadatas  []map[string]interface{} `json:"metadatas"`
+	Embeddings [][]float32              `json:"embeddings"`
+}
+
+// GetDocuments fetches documents from a collection without a similarity query
+func (c *ChromaDBClient) GetDocuments(collectionName string, getReq GetRequest) (*GetResponse, error) {
+	data, err := json.Marshal(getReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.baseURL+"/api/v1/collections/"+collectionName+"/get", bytes.NewBuffer(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var getResp GetResponse
+	if err := json.NewDecoder(resp.Body).Decode(&getResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &getResp, nil
+}
+
+// UpdateRequest represents a request to change the metadata of existing documents
+type UpdateRequest struct {
+	IDs       []string                 `json:"ids"`
+	Metadatas []map[string]interface{} `json:"metadatas"`
+}
+
+// UpdateMetadata merges new metadata into existing documents without re-sending their embeddings.
+// Like ChromaDB itself, keys that aren't given are kept and unknown IDs are ignored.
+func (c *ChromaDBClient) UpdateMetadata(collectionName string, ids []string, metadatas []map[string]interface{}) error {
+	data, err := json.Marshal(UpdateRequest{IDs: ids, Metadatas: metadatas})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.baseURL+"/api/v1/collections/"+collectionName+"/update", bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// DeleteRequest represents a request to delete documents by ID or metadata filter
+type DeleteRequest struct {
+	IDs   []string               `json:"ids,omitempty"`
+	Where map[string]interface{} `json:"where,omitempty"`
+}
+
+// DeleteDocuments removes documents from a collection by ID and/or metadata filter
+func (c *ChromaDBClient) DeleteDocuments(collectionName string, ids []string, where map[string]interface{}) error {
+	data, err := json.Marshal(DeleteRequest{IDs: ids, Where: where})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.baseURL+"/api/v1/collections/"+collectionName+"/delete", bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
@@ -0,0 +1,422 @@
+package vectordb
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MemoryStore is an in-process vector store for tests and local development. It keeps
+// everything in memory, ranks by squared L2 distance like a default ChromaDB collection,
+// and understands the where operators ChromaDB supports for metadata.
+type MemoryStore struct {
+	mu          sync.RWMutex
+	collections map[string]*memoryCollection
+}
+
+// memoryCollection holds a collection's documents in insertion order
+type memoryCollection struct {
+	order []string
+	docs  map[string]*memoryDocument
+}
+
+type memoryDocument struct {
+	content   string
+	embedding []float32
+	metadata  map[string]interface{}
+}
+
+// NewMemoryStore creates an empty in-memory vector store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{collections: make(map[string]*memoryCollection)}
+}
+
+// CreateCollection creates a collection, or does nothing if it already exists
+func (m *MemoryStore) CreateCollection(name string, metadata map[string]interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.collections[name]; !ok {
+		m.collections[name] = &memoryCollection{docs: make(map[string]*memoryDocument)}
+	}
+	return nil
+}
+
+// AddDocuments adds documents with embeddings to a collection; IDs that already exist are left alone
+func (m *MemoryStore) AddDocuments(collectionName string, ids []string, documents []string, embeddings [][]float32, metadatas []map[string]interface{}) error {
+	return m.write(collectionName, ids, documents, embeddings, metadatas, false)
+}
+
+// UpsertDocuments adds documents, replacing any existing documents with the same IDs
+func (m *MemoryStore) UpsertDocuments(collectionName string, ids []string, documents []string, embeddings [][]float32, metadatas []map[string]interface{}) error {
+	return m.write(collectionName, ids, documents, embeddings, metadatas, true)
+}
+
+func (m *MemoryStore) write(collectionName string, ids []string, documents []string, embeddings [][]float32, metadatas []map[string]interface{}, replace bool) error {
+	if len(documents) != len(ids) || len(embeddings) != len(ids) || (metadatas != nil && len(metadatas) != len(ids)) {
+		return fmt.Errorf("ids, documents, embeddings and metadatas must have the same length")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	collection, err := m.collection(collectionName)
+	if err != nil {
+		return err
+	}
+	for i, id := range ids {
+		doc := &memoryDocument{content: documents[i], embedding: append([]float32(nil), embeddings[i]...)}
+		if metadatas != nil {
+			// Round-trip through JSON so stored values have the types ChromaDB would return
+			if err := roundTrip(metadatas[i], &doc.metadata); err != nil {
+				return fmt.Errorf("invalid metadata for %s: %w", id, err)
+			}
+		}
+		if _, exists := collection.docs[id]; exists {
+			if replace {
+				collection.docs[id] = doc
+			}
+			continue
+		}
+		collection.docs[id] = doc
+		collection.order = append(collection.order, id)
+	}
+	return nil
+}
+
+// Query returns the nResults documents nearest to each query embedding that match where
+func (m *MemoryStore) Query(collectionName string, queryEmbeddings [][]float32, nResults int, where map[string]interface{}) (*QueryResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	collection, err := m.collection(collectionName)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := collection.matching(nil, where)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &QueryResponse{}
+	for _, query := range queryEmbeddings {
+		type hit struct {
+			id       string
+			distance float32
+		}
+		hits := make([]hit, len(matches))
+		for i, id := range matches {
+			hits[i] = hit{id, squaredL2(query, collection.docs[id].embedding)}
+		}
+		sort.SliceStable(hits, func(i, j int) bool { return hits[i].distance < hits[j].distance })
+		if nResults >= 0 && len(hits) > nResults {
+			hits = hits[:nResults]
+		}
+
+		ids := make([]string, len(hits))
+		documents := make([]string, len(hits))
+		distances := make([]float32, len(hits))
+		metadatas := make([]map[string]interface{}, len(hits))
+		for i, h := range hits {
+			doc := collection.docs[h.id]
+			ids[i], documents[i], distances[i], metadatas[i] = h.id, doc.content, h.distance, doc.metadata
+		}
+		response.IDs = append(response.IDs, ids)
+		response.Documents = append(response.Documents, documents)
+		response.Distances = append(response.Distances, distances)
+		response.Metadatas = append(response.Metadatas, metadatas)
+	}
+	return response, nil
+}
+
+// CountDocuments counts the documents matching where, or all documents if where is nil
+func (m *MemoryStore) CountDocuments(collectionName string, where map[string]interface{}) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	collection, err := m.collection(collectionName)
+	if err != nil {
+		return 0, err
+	}
+	matches, err := collection.matching(nil, where)
+	return len(matches), err
+}
+
+// GetDocuments fetches documents by ID or filter, in insertion order. Like ChromaDB, it
+// returns documents and metadatas unless Include names the fields to return.
+func (m *MemoryStore) GetDocuments(collectionName string, getReq GetRequest) (*GetResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	collection, err := m.collection(collectionName)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := collection.matching(getReq.IDs, getReq.Where)
+	if err != nil {
+		return nil, err
+	}
+	if getReq.Offset > 0 {
+		if getReq.Offset >= len(matches) {
+			matches = nil
+		} else {
+			matches = matches[getReq.Offset:]
+		}
+	}
+	if getReq.Limit > 0 && len(matches) > getReq.Limit {
+		matches = matches[:getReq.Limit]
+	}
+
+	include := map[string]bool{"documents": true, "metadatas": true}
+	if len(getReq.Include) > 0 {
+		include = make(map[string]bool, len(getReq.Include))
+		for _, field := range getReq.Include {
+			include[field] = true
+		}
+	}
+
+	response := &GetResponse{IDs: matches}
+	for _, id := range matches {
+		doc := collection.docs[id]
+		if include["documents"] {
+			response.Documents = append(response.Documents, doc.content)
+		}
+		if include["metadatas"] {
+			response.Metadatas = append(response.Metadatas, doc.metadata)
+		}
+		if include["embeddings"] {
+			response.Embeddings = append(response.Embeddings, doc.embedding)
+		}
+	}
+	return response, nil
+}
+
+// UpdateMetadata merges new metadata into existing documents; unknown IDs are ignored
+func (m *MemoryStore) UpdateMetadata(collectionName string, ids []string, metadatas []map[string]interface{}) error {
+	if len(metadatas) != len(ids) {
+		return fmt.Errorf("ids and metadatas must have the same length")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	collection, err := m.collection(collectionName)
+	if err != nil {
+		return err
+	}
+	for i, id := range ids {
+		doc, ok := collection.docs[id]
+		if !ok {
+			continue
+		}
+		var update map[string]interface{}
+		if err := roundTrip(metadatas[i], &update); err != nil {
+			return fmt.Errorf("invalid metadata for %s: %w", id, err)
+		}
+		// Build a new map, since earlier results may still hold the old one
+		merged := make(map[string]interface{}, len(doc.metadata)+len(update))
+		for key, value := range doc.metadata {
+			merged[key] = value
+		}
+		for key, value := range update {
+			merged[key] = value
+		}
+		doc.metadata = merged
+	}
+	return nil
+}
+
+// DeleteDocuments removes documents by ID and/or filter. Without either, nothing is removed.
+func (m *MemoryStore) DeleteDocuments(collectionName string, ids []string, where map[string]interface{}) error {
+	if ids == nil && where == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	collection, err := m.collection(collectionName)
+	if err != nil {
+		return err
+	}
+	matches, err := collection.matching(ids, where)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	removed := make(map[string]bool, len(matches))
+	for _, id := range matches {
+		removed[id] = true
+		delete(collection.docs, id)
+	}
+	kept := collection.order[:0]
+	for _, id := range collection.order {
+		if !removed[id] {
+			kept = append(kept, id)
+		}
+	}
+	collection.order = kept
+	return nil
+}
+
+// collection returns a collection; the caller holds the lock
+func (m *MemoryStore) collection(name string) (*memoryCollection, error) {
+	collection, ok := m.collections[name]
+	if !ok {
+		return nil, fmt.Errorf("collection %s does not exist", name)
+	}
+	return collection, nil
+}
+
+// matching returns the IDs, in insertion order, of the documents among ids (all if nil)
+// whose metadata matches where
+func (c *memoryCollection) matching(ids []string, where map[string]interface{}) ([]string, error) {
+	var filter map[string]interface{}
+	if where != nil {
+		if err := roundTrip(where, &filter); err != nil {
+			return nil, fmt.Errorf("invalid where filter: %w", err)
+		}
+	}
+	var wanted map[string]bool
+	if ids != nil {
+		wanted = make(map[string]bool, len(ids))
+		for _, id := range ids {
+			wanted[id] = true
+		}
+	}
+
+	var matches []string
+	for _, id := range c.order {
+		if wanted != nil && !wanted[id] {
+			continue
+		}
+		ok, err := matchWhere(c.docs[id].metadata, filter)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matches = append(matches, id)
+		}
+	}
+	return matches, nil
+}
+
+// matchWhere reports whether metadata satisfies a where filter. All top-level clauses must hold.
+func matchWhere(metadata map[string]interface{}, where map[string]interface{}) (bool, error) {
+	for key, condition := range where {
+		var ok bool
+		var err error
+		switch key {
+		case "$and", "$or":
+			clauses, isList := condition.([]interface{})
+			if !isList {
+				return false, fmt.Errorf("%s expects a list of filters", key)
+			}
+			ok, err = matchClauses(metadata, clauses, key == "$and")
+		default:
+			ok, err = matchField(metadata[key], hasKey(metadata, key), condition)
+		}
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// matchClauses combines sub-filters with AND (all) or OR (any)
+func matchClauses(metadata map[string]interface{}, clauses []interface{}, all bool) (bool, error) {
+	for _, clause := range clauses {
+		filter, ok := clause.(map[string]interface{})
+		if !ok {
+			return false, fmt.Errorf("filter clauses must be objects")
+		}
+		matched, err := matchWhere(metadata, filter)
+		if err != nil {
+			return false, err
+		}
+		if matched != all {
+			return matched, nil
+		}
+	}
+	return all, nil
+}
+
+// matchField checks one metadata value against a literal or an operator object such as {"$in": [...]}
+func matchField(value interface{}, present bool, condition interface{}) (bool, error) {
+	operators, isOperator := condition.(map[string]interface{})
+	if !isOperator {
+		return present && value == condition, nil
+	}
+	for op, operand := range operators {
+		var ok bool
+		switch op {
+		case "$eq":
+			ok = present && value == operand
+		case "$ne":
+			ok = present && value != operand
+		case "$in", "$nin":
+			list, isList := operand.([]interface{})
+			if !isList {
+				return false, fmt.Errorf("%s expects a list", op)
+			}
+			found := false
+			for _, item := range list {
+				if value == item {
+					found = true
+					break
+				}
+			}
+			ok = present && found == (op == "$in")
+		case "$gt", "$gte", "$lt", "$lte":
+			a, aOK := value.(float64)
+			b, bOK := operand.(float64)
+			if !bOK {
+				return false, fmt.Errorf("%s expects a number", op)
+			}
+			ok = present && aOK && compare(op, a, b)
+		default:
+			return false, fmt.Errorf("unsupported where operator %s", op)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func compare(op string, a, b float64) bool {
+	switch op {
+	case "$gt":
+		return a > b
+	case "$gte":
+		return a >= b
+	case "$lt":
+		return a < b
+	default:
+		return a <= b
+	}
+}
+
+func hasKey(m map[string]interface{}, key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+// squaredL2 is ChromaDB's default distance
+func squaredL2(a, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		if i >= len(b) {
+			break
+		}
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return sum
+}
+
+// roundTrip copies a value through JSON into out
+func roundTrip(in interface{}, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
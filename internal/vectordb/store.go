@@ -0,0 +1,27 @@
+package vectordb
+
+// Store is a vector store holding documents with embeddings and metadata in named
+// collections. Filters use ChromaDB's where syntax.
+type Store interface {
+	// CreateCollection creates a collection, or does nothing if it already exists
+	CreateCollection(name string, metadata map[string]interface{}) error
+	// AddDocuments adds documents with embeddings to a collection
+	AddDocuments(collectionName string, ids []string, documents []string, embeddings [][]float32, metadatas []map[string]interface{}) error
+	// UpsertDocuments adds documents, replacing any existing documents with the same IDs
+	UpsertDocuments(collectionName string, ids []string, documents []string, embeddings [][]float32, metadatas []map[string]interface{}) error
+	// Query returns the nResults documents nearest to each query embedding that match where
+	Query(collectionName string, queryEmbeddings [][]float32, nResults int, where map[string]interface{}) (*QueryResponse, error)
+	// CountDocuments counts the documents matching where, or all documents if where is nil
+	CountDocuments(collectionName string, where map[string]interface{}) (int, error)
+	// GetDocuments fetches documents by ID or filter without a similarity query
+	GetDocuments(collectionName string, getReq GetRequest) (*GetResponse, error)
+	// UpdateMetadata merges new metadata into existing documents, keeping their content and embeddings
+	UpdateMetadata(collectionName string, ids []string, metadatas []map[string]interface{}) error
+	// DeleteDocuments removes documents by ID and/or filter
+	DeleteDocuments(collectionName string, ids []string, where map[string]interface{}) error
+}
+
+var (
+	_ Store = (*ChromaDBClient)(nil)
+	_ Store = (*MemoryStore)(nil)
+)
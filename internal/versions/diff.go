@@ -0,0 +1,171 @@
+package versions
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/models"
+)
+
+// maxDiffCells bounds the table used to find the lines two versions share. Versions differing
+// in more lines than it allows are compared coarsely: the differing lines are listed as
+// removed and then added, without looking for lines they share.
+const maxDiffCells = 4_000_000
+
+// Operations of a diff line
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// DiffLine is a line of one or both versions. Transcript lines are segments, read as
+// "[mm:ss] Speaker: text"; summary lines are lines of its Markdown.
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// Diff is how a version reads compared with an earlier one
+type Diff struct {
+	Kind    string     `json:"kind"`
+	From    int        `json:"from"`
+	To      int        `json:"to"`
+	Added   int        `json:"added"`
+	Removed int        `json:"removed"`
+	Lines   []DiffLine `json:"lines"`
+}
+
+// Compare diffs two versions of the same kind line by line
+func Compare(from, to *models.TranscriptVersion) Diff {
+	diff := Diff{Kind: to.Kind, From: from.Version, To: to.Version, Lines: []DiffLine{}}
+	for _, line := range diffLines(Lines(from), Lines(to)) {
+		switch line.Op {
+		case DiffInsert:
+			diff.Added++
+		case DiffDelete:
+			diff.Removed++
+		}
+		diff.Lines = append(diff.Lines, line)
+	}
+	return diff
+}
+
+// Lines splits a version into the lines it is compared by
+func Lines(version *models.TranscriptVersion) []string {
+	if version.Kind == models.VersionKindTranscript {
+		if lines, ok := segmentLines(version.Content); ok {
+			return lines
+		}
+	}
+	content := strings.TrimRight(strings.ReplaceAll(version.Content, "\r\n", "\n"), "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
+
+// segmentLines reads a JSON transcript as a line per segment, and false for plain text or JSON
+// without segments
+func segmentLines(transcript string) ([]string, bool) {
+	var parsed struct {
+		Segments []struct {
+			Start   float64 `json:"start"`
+			Speaker *string `json:"speaker"`
+			Text    string  `json:"text"`
+		} `json:"segments"`
+	}
+	if err := json.Unmarshal([]byte(transcript), &parsed); err != nil || len(parsed.Segments) == 0 {
+		return nil, false
+	}
+	lines := make([]string, 0, len(parsed.Segments))
+	for _, segment := range parsed.Segments {
+		seconds := int(segment.Start)
+		line := fmt.Sprintf("[%02d:%02d] ", seconds/60, seconds%60)
+		if seconds >= 3600 {
+			line = fmt.Sprintf("[%d:%02d:%02d] ", seconds/3600, seconds/60%60, seconds%60)
+		}
+		if segment.Speaker != nil && *segment.Speaker != "" {
+			line += *segment.Speaker + ": "
+		}
+		lines = append(lines, line+strings.TrimSpace(segment.Text))
+	}
+	return lines, true
+}
+
+// diffLines lists the lines of a and b in order, marking those only in a as deleted and those
+// only in b as inserted, keeping as many shared lines as it can
+func diffLines(a, b []string) []DiffLine {
+	var lines []DiffLine
+	// Lines shared at the start and end don't need the table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		lines = append(lines, DiffLine{Op: DiffEqual, Text: a[prefix]})
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	if len(midA)*len(midB) > maxDiffCells {
+		for _, line := range midA {
+			lines = append(lines, DiffLine{Op: DiffDelete, Text: line})
+		}
+		for _, line := range midB {
+			lines = append(lines, DiffLine{Op: DiffInsert, Text: line})
+		}
+	} else {
+		lines = append(lines, lcsDiff(midA, midB)...)
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, DiffLine{Op: DiffEqual, Text: line})
+	}
+	return lines
+}
+
+// lcsDiff diffs a and b by their longest common subsequence of lines
+func lcsDiff(a, b []string) []DiffLine {
+	n, m := len(a), len(b)
+	// shared[i*(m+1)+j] is the length of the longest common subsequence of a[i:] and b[j:]
+	shared := make([]int32, (n+1)*(m+1))
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				shared[i*(m+1)+j] = shared[(i+1)*(m+1)+j+1] + 1
+			case shared[(i+1)*(m+1)+j] >= shared[i*(m+1)+j+1]:
+				shared[i*(m+1)+j] = shared[(i+1)*(m+1)+j]
+			default:
+				shared[i*(m+1)+j] = shared[i*(m+1)+j+1]
+			}
+		}
+	}
+
+	lines := make([]DiffLine, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, DiffLine{Op: DiffEqual, Text: a[i]})
+			i++
+			j++
+		case shared[(i+1)*(m+1)+j] >= shared[i*(m+1)+j+1]:
+			lines = append(lines, DiffLine{Op: DiffDelete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		lines = append(lines, DiffLine{Op: DiffDelete, Text: a[i]})
+	}
+	for ; j < m; j++ {
+		lines = append(lines, DiffLine{Op: DiffInsert, Text: b[j]})
+	}
+	return lines
+}
@@ -0,0 +1,55 @@
+package versions
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"scriberr/internal/models"
+)
+
+func TestDiffLines(t *testing.T) {
+	a := strings.Split("a b c d e f", " ")
+	b := strings.Split("a x c d f g", " ")
+	want := []DiffLine{
+		{DiffEqual, "a"},
+		{DiffDelete, "b"},
+		{DiffInsert, "x"},
+		{DiffEqual, "c"},
+		{DiffEqual, "d"},
+		{DiffDelete, "e"},
+		{DiffEqual, "f"},
+		{DiffInsert, "g"},
+	}
+	if got := diffLines(a, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected diff %v", got)
+	}
+	if got := diffLines(nil, []string{"a"}); !reflect.DeepEqual(got, []DiffLine{{DiffInsert, "a"}}) {
+		t.Errorf("unexpected diff from nothing %v", got)
+	}
+}
+
+func TestCompareSummaries(t *testing.T) {
+	from := &models.TranscriptVersion{Kind: models.VersionKindSummary, Version: 1, Content: "# Summary\r\nShip Friday\n"}
+	to := &models.TranscriptVersion{Kind: models.VersionKindSummary, Version: 2, Content: "# Summary\nShip Monday\nBob owns it"}
+	diff := Compare(from, to)
+	if diff.From != 1 || diff.To != 2 || diff.Added != 2 || diff.Removed != 1 || len(diff.Lines) != 4 {
+		t.Errorf("unexpected diff %+v", diff)
+	}
+}
+
+func TestTranscriptLines(t *testing.T) {
+	version := &models.TranscriptVersion{
+		Kind:    models.VersionKindTranscript,
+		Content: `{"segments": [{"start": 5.5, "text": " Hello ", "speaker": "Alice"}, {"start": 3725, "text": "Bye"}]}`,
+	}
+	want := []string{"[00:05] Alice: Hello", "[1:02:05] Bye"}
+	if got := Lines(version); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	version.Content = "plain text\nsecond line"
+	if got := Lines(version); len(got) != 2 {
+		t.Errorf("expected plain text split by line, got %q", got)
+	}
+}
@@ -0,0 +1,221 @@
+// Package versions keeps the history of transcripts and summaries. Every write of either goes
+// through Save, which updates the job and keeps what it now holds as an immutable, numbered
+// TranscriptVersion, so earlier versions can be compared with the current one and restored.
+package versions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUnknownKind is returned for a kind other than transcript or summary
+	ErrUnknownKind = errors.New("kind must be transcript or summary")
+	// ErrNotFound is returned for a version number a transcription doesn't have
+	ErrNotFound = errors.New("version not found")
+	// ErrCurrent is returned when restoring the version the job already holds
+	ErrCurrent = errors.New("version is already current")
+)
+
+// Content is what a version holds. Structured and Model are only kept for summaries.
+type Content struct {
+	Text       string
+	Structured *models.StructuredSummary
+	Model      *string
+}
+
+// ValidKind reports whether kind is one versions are kept of
+func ValidKind(kind string) bool {
+	return kind == models.VersionKindTranscript || kind == models.VersionKindSummary
+}
+
+// Save replaces a job's transcript or summary with content and records it as a new version.
+// Content written before versions were kept is recorded first, as the original, so it isn't
+// lost. Saving the content the latest version already holds records nothing and returns it.
+func Save(tx *gorm.DB, jobID, kind string, content Content, source string, createdBy *uint) (*models.TranscriptVersion, error) {
+	return save(tx, jobID, kind, content, source, createdBy, nil)
+}
+
+// Record keeps content the caller has already written to the job as a new version, for jobs
+// created with their transcript
+func Record(tx *gorm.DB, jobID, kind string, content Content, source string, createdBy *uint) (*models.TranscriptVersion, error) {
+	if !ValidKind(kind) {
+		return nil, ErrUnknownKind
+	}
+	return record(tx, jobID, kind, content, source, createdBy, nil)
+}
+
+// Baseline records what a job currently holds of kind as its original version, unless versions
+// of kind are already kept or there is nothing to keep. Call it before clearing content without
+// replacing it, so the cleared content can still be restored.
+func Baseline(tx *gorm.DB, jobID, kind string) error {
+	if !ValidKind(kind) {
+		return ErrUnknownKind
+	}
+	var count int64
+	if err := tx.Model(&models.TranscriptVersion{}).Where("transcription_id = ? AND kind = ?", jobID, kind).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	var job models.TranscriptionJob
+	if err := tx.Select("id", "transcript", "summary", "structured_summary", "summary_model").Where("id = ?", jobID).Take(&job).Error; err != nil {
+		return err
+	}
+	current, ok := held(&job, kind)
+	if !ok {
+		return nil
+	}
+	_, err := record(tx, jobID, kind, current, models.VersionSourceOriginal, nil, nil)
+	return err
+}
+
+// Restore makes an earlier version current again, recording it as a new version that notes
+// which one it copied. Versions after it are kept.
+func Restore(tx *gorm.DB, jobID, kind string, number int, createdBy *uint) (*models.TranscriptVersion, error) {
+	target, err := Get(tx, jobID, kind, number)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := latest(tx, jobID, kind)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.Version == number {
+		return nil, ErrCurrent
+	}
+	content := Content{Text: target.Content, Structured: target.StructuredSummary, Model: target.Model}
+	return save(tx, jobID, kind, content, models.VersionSourceRollback, createdBy, &target.Version)
+}
+
+// List returns the versions of kind kept for a job, newest first, without their content
+func List(tx *gorm.DB, jobID, kind string) ([]models.TranscriptVersion, error) {
+	if !ValidKind(kind) {
+		return nil, ErrUnknownKind
+	}
+	list := []models.TranscriptVersion{}
+	err := tx.Omit("content", "structured_summary").Where("transcription_id = ? AND kind = ?", jobID, kind).
+		Order("version DESC").Find(&list).Error
+	return list, err
+}
+
+// Get returns one version of a job's transcript or summary
+func Get(tx *gorm.DB, jobID, kind string, number int) (*models.TranscriptVersion, error) {
+	if !ValidKind(kind) {
+		return nil, ErrUnknownKind
+	}
+	var version models.TranscriptVersion
+	err := tx.Where("transcription_id = ? AND kind = ? AND version = ?", jobID, kind, number).Take(&version).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// Latest returns the newest version of kind kept for a job, or ErrNotFound if there is none
+func Latest(tx *gorm.DB, jobID, kind string) (*models.TranscriptVersion, error) {
+	if !ValidKind(kind) {
+		return nil, ErrUnknownKind
+	}
+	version, err := latest(tx, jobID, kind)
+	if err == nil && version == nil {
+		return nil, ErrNotFound
+	}
+	return version, err
+}
+
+func save(tx *gorm.DB, jobID, kind string, content Content, source string, createdBy *uint, restoredFrom *int) (*models.TranscriptVersion, error) {
+	if err := Baseline(tx, jobID, kind); err != nil {
+		return nil, err
+	}
+	job := tx.Model(&models.TranscriptionJob{}).Where("id = ?", jobID)
+	var err error
+	if kind == models.VersionKindTranscript {
+		err = job.Update("transcript", content.Text).Error
+	} else {
+		// Selecting the columns also clears a structured summary left by an earlier run
+		err = job.Select("summary", "structured_summary", "summary_model").
+			Updates(&models.TranscriptionJob{Summary: &content.Text, StructuredSummary: content.Structured, SummaryModel: content.Model}).Error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save %s: %w", kind, err)
+	}
+	return record(tx, jobID, kind, content, source, createdBy, restoredFrom)
+}
+
+func record(tx *gorm.DB, jobID, kind string, content Content, source string, createdBy *uint, restoredFrom *int) (*models.TranscriptVersion, error) {
+	previous, err := latest(tx, jobID, kind)
+	if err != nil {
+		return nil, err
+	}
+	if kind == models.VersionKindTranscript {
+		content.Structured, content.Model = nil, nil
+	}
+	next := 1
+	if previous != nil {
+		if same(previous, content) {
+			return previous, nil
+		}
+		next = previous.Version + 1
+	}
+	version := &models.TranscriptVersion{
+		TranscriptionID:   jobID,
+		Kind:              kind,
+		Version:           next,
+		Content:           content.Text,
+		StructuredSummary: content.Structured,
+		Model:             content.Model,
+		Source:            source,
+		RestoredFrom:      restoredFrom,
+		CreatedBy:         createdBy,
+	}
+	if err := tx.Create(version).Error; err != nil {
+		return nil, fmt.Errorf("failed to record %s version: %w", kind, err)
+	}
+	return version, nil
+}
+
+func latest(tx *gorm.DB, jobID, kind string) (*models.TranscriptVersion, error) {
+	var versions []models.TranscriptVersion
+	if err := tx.Where("transcription_id = ? AND kind = ?", jobID, kind).Order("version DESC").Limit(1).Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, nil
+	}
+	return &versions[0], nil
+}
+
+// held returns what a job holds of kind, and false when it holds nothing
+func held(job *models.TranscriptionJob, kind string) (Content, bool) {
+	if kind == models.VersionKindTranscript {
+		if job.Transcript == nil || *job.Transcript == "" {
+			return Content{}, false
+		}
+		return Content{Text: *job.Transcript}, true
+	}
+	if job.Summary == nil || *job.Summary == "" {
+		return Content{}, false
+	}
+	return Content{Text: *job.Summary, Structured: job.StructuredSummary, Model: job.SummaryModel}, true
+}
+
+// same reports whether a version holds content. The model isn't compared: the same text
+// written by another model is no new version.
+func same(version *models.TranscriptVersion, content Content) bool {
+	if version.Content != content.Text {
+		return false
+	}
+	a, _ := json.Marshal(version.StructuredSummary)
+	b, _ := json.Marshal(content.Structured)
+	return string(a) == string(b)
+}
@@ -0,0 +1,133 @@
+// Package vocabulary gives transcriptions the spelling of the terms their owner cares about.
+// A transcription's vocabulary is its own terms plus its owner's workspace vocabulary; the
+// terms are passed to the transcription engine as hotwords and in the initial prompt, and
+// the words a term is known to be misheard as are replaced by it afterwards.
+package vocabulary
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+)
+
+// maxPromptTerms caps the terms put in the initial prompt, of which Whisper only reads the
+// last couple of hundred tokens
+const maxPromptTerms = 100
+
+// ForJob returns the vocabulary of a transcription: its own terms first, then those of its
+// owner's workspace, each term once ignoring case
+func ForJob(job *models.TranscriptionJob) ([]models.VocabularyTerm, error) {
+	var own, workspace []models.VocabularyTerm
+	if err := database.DB.Where("transcription_id = ?", job.ID).Order("created_at ASC").Find(&own).Error; err != nil {
+		return nil, err
+	}
+	query := database.DB.Where("transcription_id IS NULL")
+	if job.UserID == nil {
+		query = query.Where("user_id IS NULL")
+	} else {
+		query = query.Where("user_id = ?", *job.UserID)
+	}
+	if err := query.Order("created_at ASC").Find(&workspace).Error; err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	terms := make([]models.VocabularyTerm, 0, len(own)+len(workspace))
+	for _, term := range append(own, workspace...) {
+		if key := strings.ToLower(term.Term); !seen[key] {
+			seen[key] = true
+			terms = append(terms, term)
+		}
+	}
+	return terms, nil
+}
+
+// Hotwords returns the terms as the comma-separated list engines take as hotwords
+func Hotwords(terms []models.VocabularyTerm) string {
+	names := make([]string, len(terms))
+	for i, term := range terms {
+		names[i] = term.Term
+	}
+	return strings.Join(names, ", ")
+}
+
+// Prompt returns a sentence naming the terms, for the initial prompt; the model picks up
+// their spelling from it
+func Prompt(terms []models.VocabularyTerm) string {
+	if len(terms) == 0 {
+		return ""
+	}
+	if len(terms) > maxPromptTerms {
+		terms = terms[:maxPromptTerms]
+	}
+	return "Vocabulary: " + Hotwords(terms) + "."
+}
+
+// ApplyHints replaces the words each term is known to be misheard as with the term, as whole
+// words ignoring case, returning the corrected text and the number of replacements
+func ApplyHints(text string, terms []models.VocabularyTerm) (string, int) {
+	replaced := 0
+	for _, term := range terms {
+		for _, hint := range term.SoundsLike {
+			if strings.TrimSpace(hint) == "" || strings.EqualFold(hint, term.Term) {
+				continue
+			}
+			matches := find(text, hint)
+			if len(matches) == 0 {
+				continue
+			}
+			var b strings.Builder
+			last := 0
+			for _, match := range matches {
+				b.WriteString(text[last:match[0]])
+				b.WriteString(term.Term)
+				last = match[1]
+			}
+			b.WriteString(text[last:])
+			text = b.String()
+			replaced += len(matches)
+		}
+	}
+	return text, replaced
+}
+
+// Mentions reports whether text contains any of the terms, as whole words ignoring case
+func Mentions(text string, terms []models.VocabularyTerm) bool {
+	for _, term := range terms {
+		if len(find(text, term.Term)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// find returns the byte ranges of phrase in text as whole words, ignoring case and treating
+// any run of spaces in phrase as one or more spaces
+func find(text, phrase string) [][2]int {
+	words := strings.Fields(phrase)
+	if len(words) == 0 {
+		return nil
+	}
+	for i, word := range words {
+		words[i] = regexp.QuoteMeta(word)
+	}
+	re := regexp.MustCompile(`(?i)` + strings.Join(words, `\s+`))
+
+	var matches [][2]int
+	for _, match := range re.FindAllStringIndex(text, -1) {
+		before, _ := utf8.DecodeLastRuneInString(text[:match[0]])
+		after, _ := utf8.DecodeRuneInString(text[match[1]:])
+		if !isWordRune(before) && !isWordRune(after) {
+			matches = append(matches, [2]int{match[0], match[1]})
+		}
+	}
+	return matches
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
@@ -0,0 +1,41 @@
+package vocabulary
+
+import (
+	"testing"
+
+	"scriberr/internal/models"
+)
+
+func TestApplyHintsReplacesWholeWords(t *testing.T) {
+	terms := []models.VocabularyTerm{
+		{Term: "kubectl", SoundsLike: []string{"cube cuddle", "cube control"}},
+		{Term: "SLA", Kind: models.VocabularyKindAcronym, SoundsLike: []string{"slaw"}},
+	}
+
+	text, n := ApplyHints("Run Cube  cuddle apply, then cube control get pods; the slaw is 99.9%.", terms)
+	if want := "Run kubectl apply, then kubectl get pods; the SLA is 99.9%."; text != want || n != 3 {
+		t.Errorf("got %q (%d), want %q (3)", text, n, want)
+	}
+
+	// Only whole words are replaced
+	text, n = ApplyHints("Coleslaw and slawson", terms)
+	if text != "Coleslaw and slawson" || n != 0 {
+		t.Errorf("got %q (%d)", text, n)
+	}
+}
+
+func TestMentionsAndPrompt(t *testing.T) {
+	terms := []models.VocabularyTerm{{Term: "Siobhan"}, {Term: "Q3 OKRs"}}
+	if !Mentions("Thanks, siobhan.", terms) || !Mentions("the q3   OKRs are in", terms) {
+		t.Error("expected the terms to be found")
+	}
+	if Mentions("Siobhans", terms) {
+		t.Error("a longer word doesn't mention the term")
+	}
+	if got := Prompt(terms); got != "Vocabulary: Siobhan, Q3 OKRs." {
+		t.Errorf("unexpected prompt %q", got)
+	}
+	if Prompt(nil) != "" {
+		t.Error("no terms should give no prompt")
+	}
+}
@@ -0,0 +1,170 @@
+// Package watchlist finds the terms of user-defined watchlists in transcript text. Terms are
+// matched as whole words ignoring case, optionally also with small misspellings and against
+// words the transcription engine masked with asterisks; matching by meaning is done by the
+// caller with embeddings, using Similarity.
+package watchlist
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Kinds of match
+const (
+	MatchExact    = "exact"
+	MatchFuzzy    = "fuzzy"
+	MatchSemantic = "semantic"
+)
+
+// DefaultSimilarity is the cosine similarity above which a segment matches a term by meaning
+const DefaultSimilarity = 0.8
+
+// Hit is a term found in a text
+type Hit struct {
+	Term  string
+	Type  string  // MatchExact or MatchFuzzy
+	Score float64 // 1 for an exact match, less the more edits a fuzzy match needed
+}
+
+// Find returns the terms found in text, each at most once, exact matches preferred. With
+// fuzzy set, a term also matches words a few edits away from it (one for words of four to
+// seven letters, two for longer ones) and masked words such as "f**k".
+func Find(terms []string, text string, fuzzy bool) []Hit {
+	words := tokenize(text)
+	var hits []Hit
+	for _, term := range terms {
+		termWords := tokenize(term)
+		if len(termWords) == 0 || len(termWords) > len(words) {
+			continue
+		}
+		best := Hit{Term: term}
+		for i := 0; i+len(termWords) <= len(words); i++ {
+			window := words[i : i+len(termWords)]
+			if equalWords(window, termWords) {
+				best = Hit{Term: term, Type: MatchExact, Score: 1}
+				break
+			}
+			if !fuzzy {
+				continue
+			}
+			if score, ok := fuzzyMatch(window, termWords); ok && score > best.Score {
+				best = Hit{Term: term, Type: MatchFuzzy, Score: score}
+			}
+		}
+		if best.Type != "" {
+			hits = append(hits, best)
+		}
+	}
+	return hits
+}
+
+// tokenize splits text into lowercase words. Apostrophes and asterisks inside words are kept.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '’' && r != '*'
+	})
+}
+
+func equalWords(a, b []string) bool {
+	for i := range a {
+		if strings.Trim(a[i], "'’*") != strings.Trim(b[i], "'’*") {
+			return false
+		}
+	}
+	return true
+}
+
+// fuzzyMatch compares a window of words with a term's words, word by word, returning a score
+// that drops with each edit
+func fuzzyMatch(window, term []string) (float64, bool) {
+	edits, length := 0, 0
+	for i := range term {
+		word := []rune(window[i])
+		want := []rune(term[i])
+		length += len(want)
+		if masked(word, want) {
+			continue
+		}
+		d := editDistance(word, want)
+		if d > allowedEdits(len(want)) {
+			return 0, false
+		}
+		edits += d
+	}
+	if edits == 0 {
+		return 0.99, true // Masked words only
+	}
+	return 1 - float64(edits)/float64(length), true
+}
+
+// masked reports whether word is want with some of its letters, but not the first, replaced by asterisks
+func masked(word, want []rune) bool {
+	if len(word) != len(want) || len(word) < 3 || word[0] == '*' {
+		return false
+	}
+	stars := 0
+	for i := range word {
+		if word[i] == '*' {
+			stars++
+		} else if word[i] != want[i] {
+			return false
+		}
+	}
+	return stars > 0
+}
+
+// allowedEdits is how many edits a fuzzy match of a word of n letters may need
+func allowedEdits(n int) int {
+	switch {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance returns the number of insertions, deletions, substitutions and swaps of
+// adjacent letters that turn a into b
+func editDistance(a, b []rune) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(a)][len(b)]
+}
+
+// Similarity returns the cosine similarity of two embeddings
+func Similarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,48 @@
+package watchlist
+
+import "testing"
+
+func TestFind(t *testing.T) {
+	terms := []string{"refund", "legal action", "damn", "cancel my subscription", "ass"}
+	text := "I want a REFUND, or we'll take legal-action. Damn it, the class was bad."
+
+	hits := Find(terms, text, false)
+	if len(hits) != 3 {
+		t.Fatalf("expected 3 exact hits, got %+v", hits)
+	}
+	for _, hit := range hits {
+		if hit.Type != MatchExact || hit.Score != 1 {
+			t.Errorf("expected an exact hit, got %+v", hit)
+		}
+	}
+
+	hits = Find([]string{"subscription", "refund", "shit", "cat"}, "Cancel my subscripton, no refnud. This is s**t, the car broke.", true)
+	found := map[string]Hit{}
+	for _, hit := range hits {
+		found[hit.Term] = hit
+	}
+	if hit := found["subscription"]; hit.Type != MatchFuzzy || hit.Score >= 1 {
+		t.Errorf("expected a fuzzy match for a misspelling, got %+v", hit)
+	}
+	if hit, ok := found["refund"]; !ok || hit.Type != MatchFuzzy {
+		t.Errorf("expected a transposition to match fuzzily, got %+v", hit)
+	}
+	if hit := found["shit"]; hit.Type != MatchFuzzy {
+		t.Errorf("expected a masked word to match, got %+v", hit)
+	}
+	if _, ok := found["cat"]; ok {
+		t.Error("short words must match exactly")
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if got := Similarity([]float32{1, 0}, []float32{1, 0}); got < 0.999 {
+		t.Errorf("expected identical vectors to be similar, got %v", got)
+	}
+	if got := Similarity([]float32{1, 0}, []float32{0, 1}); got != 0 {
+		t.Errorf("expected orthogonal vectors to score 0, got %v", got)
+	}
+	if got := Similarity([]float32{1}, []float32{1, 0}); got != 0 {
+		t.Errorf("expected mismatched dimensions to score 0, got %v", got)
+	}
+}
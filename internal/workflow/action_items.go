@@ -0,0 +1,204 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/export"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+
+	"gorm.io/gorm"
+)
+
+// actionItemsSchema is the JSON Schema of an action item extraction reply
+var actionItemsSchema = llm.Schema{
+	Name:        "action_items",
+	Description: "The action items agreed in a transcribed recording",
+	Definition: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "action_items": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "task": {"type": "string", "description": "What has to be done"},
+          "owner": {"type": "string", "description": "Who is responsible, empty if not mentioned"},
+          "due": {"type": "string", "description": "When it is due as mentioned, empty if not mentioned"},
+          "timestamp": {"type": "number", "description": "Seconds value of the [seconds] marker of the line where it was mentioned"}
+        },
+        "required": ["task", "owner", "due", "timestamp"]
+      }
+    }
+  },
+  "required": ["action_items"]
+}`),
+}
+
+// extractedActionItem is one action item of an extraction reply
+type extractedActionItem struct {
+	Task      string  `json:"task"`
+	Owner     string  `json:"owner"`
+	Due       string  `json:"due"`
+	Timestamp float64 `json:"timestamp"`
+}
+
+// ActionItemsStep extracts the action items of a transcript into the action_items table.
+// It only runs when Enabled, or when the run's action_items parameter is "true"; a
+// parameter of "false" turns it off for one run.
+type ActionItemsStep struct {
+	LLM     LLMService
+	Model   string
+	Enabled bool
+}
+
+// Run extracts and saves the action items, returning them one per line
+func (s *ActionItemsStep) Run(ctx context.Context, rc *RunContext) (string, error) {
+	enabled := s.Enabled
+	if param := rc.Params["action_items"]; param != "" {
+		enabled = param == "true"
+	}
+	if !enabled {
+		return "", ErrSkipped
+	}
+
+	items, err := ExtractActionItems(ctx, s.LLM, s.Model, rc.Job)
+	if err != nil {
+		return "", err
+	}
+	lines := make([]string, len(items))
+	for i, item := range items {
+		lines[i] = "- " + item.Task
+		if item.Owner != "" {
+			lines[i] += " (" + item.Owner + ")"
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// ExtractActionItems asks the LLM for the action items of a job's transcript and replaces the
+// job's stored action items with them. Items that were completed before keep their completed
+// state when the same task is extracted again.
+func ExtractActionItems(ctx context.Context, service LLMService, model string, job *models.TranscriptionJob) ([]models.ActionItem, error) {
+	transcript, timed, _, err := speakerTranscript(job)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt := "List the action items agreed in the following transcription: tasks someone committed to or was asked to do. " +
+		"Give the owner and due date only if they were mentioned, and the [seconds] marker of the line where the task was mentioned. " +
+		"Leave the list empty if there are none.\n\n" + truncateForLLM(transcript)
+	messages := []llm.ChatMessage{{Role: "user", Content: prompt}}
+
+	var reply struct {
+		ActionItems []extractedActionItem `json:"action_items"`
+	}
+	if err := llm.CompleteJSON(ctx, service, model, messages, 0.2, actionItemsSchema, &reply); err != nil {
+		return nil, fmt.Errorf("action item extraction failed: %w", err)
+	}
+
+	var items []models.ActionItem
+	for _, extracted := range reply.ActionItems {
+		item := models.ActionItem{
+			TranscriptionID: job.ID,
+			UserID:          job.UserID,
+			Task:            strings.TrimSpace(extracted.Task),
+			Owner:           strings.TrimSpace(extracted.Owner),
+			Due:             strings.TrimSpace(extracted.Due),
+			Model:           model,
+		}
+		if item.Task == "" {
+			continue
+		}
+		if timed && extracted.Timestamp >= 0 {
+			seconds := extracted.Timestamp
+			item.SourceTime = &seconds
+		}
+		items = append(items, item)
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var previous []models.ActionItem
+		if err := tx.Where("transcription_id = ?", job.ID).Find(&previous).Error; err != nil {
+			return err
+		}
+		completed := make(map[string]models.ActionItem, len(previous))
+		for _, item := range previous {
+			if item.Completed {
+				completed[strings.ToLower(item.Task)] = item
+			}
+		}
+		if err := tx.Where("transcription_id = ?", job.ID).Delete(&models.ActionItem{}).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			if done, ok := completed[strings.ToLower(items[i].Task)]; ok {
+				items[i].Completed = true
+				items[i].CompletedAt = done.CompletedAt
+			}
+			if err := tx.Create(&items[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save action items: %w", err)
+	}
+	return items, nil
+}
+
+// speakerTranscript renders a job's transcript one segment per line, each marked with its
+// start time in seconds and prefixed with its speaker's mapped name. It also reports whether
+// the segments carry timings and whether any of them is attributed to a speaker.
+func speakerTranscript(job *models.TranscriptionJob) (text string, timed, attributed bool, err error) {
+	segments := export.TranscriptSegments(job)
+	if len(segments) == 0 {
+		return "", false, false, fmt.Errorf("no transcript available")
+	}
+	return renderSegments(job.ID, segments)
+}
+
+// renderSegments renders segments of a job's transcript as speakerTranscript does
+func renderSegments(jobID string, segments []interfaces.TranscriptSegment) (text string, timed, attributed bool, err error) {
+	speakerNames, err := jobSpeakerNames(jobID)
+	if err != nil {
+		return "", false, false, err
+	}
+
+	var transcript strings.Builder
+	for _, segment := range segments {
+		if segment.End > 0 {
+			timed = true
+		}
+		fmt.Fprintf(&transcript, "[%.0f] ", segment.Start)
+		if segment.Speaker != nil && *segment.Speaker != "" {
+			attributed = true
+			speaker := *segment.Speaker
+			if name := speakerNames[strings.ToUpper(speaker)]; name != "" {
+				speaker = name
+			}
+			transcript.WriteString(speaker + ": ")
+		}
+		transcript.WriteString(strings.TrimSpace(segment.Text) + "\n")
+	}
+	return transcript.String(), timed, attributed, nil
+}
+
+// jobSpeakerNames maps a job's diarization labels, in upper case, to the names given to them
+func jobSpeakerNames(jobID string) (map[string]string, error) {
+	var mappings []models.SpeakerMapping
+	if err := database.DB.Where("transcription_job_id = ?", jobID).Find(&mappings).Error; err != nil {
+		return nil, fmt.Errorf("failed to load speaker names: %w", err)
+	}
+	names := make(map[string]string, len(mappings))
+	for _, mapping := range mappings {
+		names[strings.ToUpper(mapping.OriginalSpeaker)] = mapping.CustomName
+	}
+	return names, nil
+}
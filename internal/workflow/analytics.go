@@ -0,0 +1,159 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"scriberr/internal/analytics"
+	"scriberr/internal/database"
+	"scriberr/internal/export"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+
+	"gorm.io/gorm/clause"
+)
+
+// sentimentBatchLength caps the transcript text sent to the LLM in one sentiment request.
+// Segments are never split, so a batch holding one long segment can exceed it.
+const sentimentBatchLength = 6000
+
+// sentimentSchema is the JSON Schema of a sentiment scoring reply
+var sentimentSchema = llm.Schema{
+	Name:        "sentiment",
+	Description: "Sentiment scores of numbered transcript lines",
+	Definition: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "scores": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "line": {"type": "integer", "description": "The line's [n] number"},
+          "score": {"type": "number", "description": "From -1 (very negative) through 0 (neutral) to 1 (very positive)"}
+        },
+        "required": ["line", "score"]
+      }
+    }
+  },
+  "required": ["scores"]
+}`),
+}
+
+// AnalyticsStep computes per-speaker talk time, turns and interruptions from the transcript's
+// timed segments and scores the sentiment of each segment with the LLM. It only runs when
+// Enabled, or when the run's analytics parameter is "true"; a parameter of "false" turns it
+// off for one run.
+type AnalyticsStep struct {
+	LLM     LLMService
+	Model   string
+	Enabled bool
+}
+
+// Run computes and saves the analytics, returning each speaker's share of the talk time
+func (s *AnalyticsStep) Run(ctx context.Context, rc *RunContext) (string, error) {
+	enabled := s.Enabled
+	if param := rc.Params["analytics"]; param != "" {
+		enabled = param == "true"
+	}
+	if !enabled {
+		return "", ErrSkipped
+	}
+
+	result, err := AnalyzeConversation(ctx, s.LLM, s.Model, rc.Job)
+	if err != nil {
+		return "", err
+	}
+	lines := make([]string, len(result.Speakers))
+	for i, speaker := range result.Speakers {
+		lines[i] = fmt.Sprintf("- %s: %.0f%% of talk time, %d interruptions", speaker.Speaker, speaker.TalkShare*100, speaker.Interruptions)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// AnalyzeConversation computes a job's conversation analytics and saves them, replacing any
+// computed before
+func AnalyzeConversation(ctx context.Context, service LLMService, model string, job *models.TranscriptionJob) (*models.ConversationAnalytics, error) {
+	segments := export.TranscriptSegments(job)
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("no transcript available")
+	}
+	names, err := jobSpeakerNames(job.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	speakers, duration := analytics.Speakers(segments, names)
+	sentiment, err := scoreSentiment(ctx, service, model, segments, names)
+	if err != nil {
+		return nil, err
+	}
+	analytics.AddSentiment(speakers, sentiment)
+
+	result := &models.ConversationAnalytics{
+		TranscriptionID: job.ID,
+		UserID:          job.UserID,
+		Duration:        duration,
+		Speakers:        speakers,
+		Segments:        sentiment,
+		Model:           model,
+	}
+	if err := database.DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(result).Error; err != nil {
+		return nil, fmt.Errorf("failed to save analytics: %w", err)
+	}
+	return result, nil
+}
+
+// scoreSentiment scores every segment in batches of numbered lines. A segment the LLM left
+// out of its reply is scored as neutral.
+func scoreSentiment(ctx context.Context, service LLMService, model string, segments []interfaces.TranscriptSegment, names map[string]string) ([]models.SegmentSentiment, error) {
+	scored := make([]models.SegmentSentiment, len(segments))
+	for i, segment := range segments {
+		scored[i] = models.SegmentSentiment{
+			Start:   segment.Start,
+			End:     segment.End,
+			Speaker: analytics.DisplayName(analytics.SegmentLabel(segment), names),
+			Label:   models.SentimentNeutral,
+		}
+	}
+
+	for start := 0; start < len(segments); {
+		end, length := start, 0
+		for end < len(segments) && (end == start || length+len(segments[end].Text) <= sentimentBatchLength) {
+			length += len(segments[end].Text)
+			end++
+		}
+
+		var prompt strings.Builder
+		prompt.WriteString("Score the sentiment of each numbered line of the following conversation, from -1 (very negative) " +
+			"through 0 (neutral) to 1 (very positive), judging each line in the context of the lines around it.\n\n")
+		for i := start; i < end; i++ {
+			fmt.Fprintf(&prompt, "[%d] %s: %s\n", i-start+1, scored[i].Speaker, strings.Join(strings.Fields(segments[i].Text), " "))
+		}
+		messages := []llm.ChatMessage{{Role: "user", Content: prompt.String()}}
+
+		var reply struct {
+			Scores []struct {
+				Line  int     `json:"line"`
+				Score float64 `json:"score"`
+			} `json:"scores"`
+		}
+		if err := llm.CompleteJSON(ctx, service, model, messages, 0.1, sentimentSchema, &reply); err != nil {
+			return nil, fmt.Errorf("sentiment scoring failed: %w", err)
+		}
+		for _, line := range reply.Scores {
+			if line.Line < 1 || line.Line > end-start {
+				continue
+			}
+			score := math.Round(math.Max(-1, math.Min(1, line.Score))*100) / 100
+			scored[start+line.Line-1].Score = score
+			scored[start+line.Line-1].Label = analytics.SentimentLabel(score)
+		}
+		start = end
+	}
+	return scored, nil
+}
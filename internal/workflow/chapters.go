@@ -0,0 +1,181 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/chapters"
+	"scriberr/internal/database"
+	"scriberr/internal/export"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+
+	"gorm.io/gorm"
+)
+
+// MinChapterDuration is the shortest recording, in seconds, the generate_chapters step splits
+// into chapters unless a run asks for it
+const MinChapterDuration = 10 * 60
+
+// chaptersSchema is the JSON Schema of a chapter titling reply
+var chaptersSchema = llm.Schema{
+	Name:        "chapters",
+	Description: "Titles and one-line summaries of the chapters of a recording",
+	Definition: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "chapters": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "number": {"type": "integer", "description": "The chapter's [n] number"},
+          "title": {"type": "string", "description": "A short title of at most eight words"},
+          "summary": {"type": "string", "description": "One sentence on what the chapter covers"}
+        },
+        "required": ["number", "title", "summary"]
+      }
+    }
+  },
+  "required": ["chapters"]
+}`),
+}
+
+// ChaptersStep splits long recordings into chapters where the topic shifts and has the LLM
+// title them. Boundaries come from the embeddings of one-minute windows of the transcript,
+// so recordings of any length are covered. It only runs when Enabled, or when the run's
+// chapters parameter is "true", which also chapters recordings shorter than
+// MinChapterDuration; a parameter of "false" turns it off for one run.
+type ChaptersStep struct {
+	LLM     LLMService
+	Model   string
+	Enabled bool
+	RAG     *rag.RAGService // Embeds the transcript windows
+}
+
+// Run detects, titles and saves the chapters, returning them one per line
+func (s *ChaptersStep) Run(ctx context.Context, rc *RunContext) (string, error) {
+	enabled := s.Enabled
+	if param := rc.Params["chapters"]; param != "" {
+		enabled = param == "true"
+	}
+	if !enabled || s.RAG == nil {
+		return "", ErrSkipped
+	}
+	if rc.Params["chapters"] != "true" {
+		segments := export.TranscriptSegments(rc.Job)
+		if len(segments) == 0 || segments[len(segments)-1].End < MinChapterDuration {
+			return "", ErrSkipped
+		}
+	}
+
+	result, err := GenerateChapters(ctx, s.LLM, s.Model, s.RAG, rc.Job)
+	if err != nil {
+		return "", err
+	}
+	lines := make([]string, len(result))
+	for i, chapter := range result {
+		lines[i] = fmt.Sprintf("%s %s", export.Timestamp(chapter.Start), chapter.Title)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// GenerateChapters splits a job's transcript into chapters and replaces its stored chapters
+// with them. A transcript without a topic shift becomes a single chapter.
+func GenerateChapters(ctx context.Context, service LLMService, model string, embedder *rag.RAGService, job *models.TranscriptionJob) ([]models.Chapter, error) {
+	segments := export.TranscriptSegments(job)
+	timed := false
+	for _, segment := range segments {
+		if segment.End > 0 {
+			timed = true
+			break
+		}
+	}
+	if !timed {
+		return nil, fmt.Errorf("no timed transcript available")
+	}
+
+	windows := chapters.Windows(segments, chapters.WindowSeconds)
+	texts := make([]string, len(windows))
+	for i, window := range windows {
+		texts[i] = window.Text
+	}
+	vectors, err := embedder.Embed(texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed transcript windows: %w", err)
+	}
+	spans := chapters.Split(windows, chapters.Boundaries(vectors, chapters.MinChapterWindows, chapters.MaxChapters))
+
+	// Every chapter gets an equal share of the LLM input budget
+	excerptLength := maxLLMInputLength / len(spans)
+	var prompt strings.Builder
+	prompt.WriteString("The following are the chapters of a recording, each with its time range and the beginning of its transcript. " +
+		"Give each chapter a short, specific title and a one-sentence summary, in the language of the transcript.\n\n")
+	for i, span := range spans {
+		excerpt := span.Text
+		if len(excerpt) > excerptLength {
+			excerpt = excerpt[:excerptLength]
+			if cut := strings.LastIndexByte(excerpt, ' '); cut > 0 {
+				excerpt = excerpt[:cut]
+			}
+			excerpt += " …"
+		}
+		fmt.Fprintf(&prompt, "[%d] %s–%s: %s\n\n", i+1, export.Timestamp(span.Start), export.Timestamp(span.End), excerpt)
+	}
+	messages := []llm.ChatMessage{{Role: "user", Content: prompt.String()}}
+
+	var reply struct {
+		Chapters []struct {
+			Number  int    `json:"number"`
+			Title   string `json:"title"`
+			Summary string `json:"summary"`
+		} `json:"chapters"`
+	}
+	if err := llm.CompleteJSON(ctx, service, model, messages, 0.3, chaptersSchema, &reply); err != nil {
+		return nil, fmt.Errorf("chapter titling failed: %w", err)
+	}
+
+	result := make([]models.Chapter, len(spans))
+	for i, span := range spans {
+		result[i] = models.Chapter{
+			TranscriptionID: job.ID,
+			UserID:          job.UserID,
+			Position:        i,
+			Title:           fmt.Sprintf("Chapter %d", i+1),
+			Start:           span.Start,
+			End:             span.End,
+			Model:           model,
+		}
+	}
+	for _, chapter := range reply.Chapters {
+		if chapter.Number < 1 || chapter.Number > len(result) {
+			continue
+		}
+		if title := strings.Join(strings.Fields(chapter.Title), " "); title != "" {
+			if runes := []rune(title); len(runes) > 255 {
+				title = string(runes[:255])
+			}
+			result[chapter.Number-1].Title = title
+		}
+		result[chapter.Number-1].Summary = strings.TrimSpace(chapter.Summary)
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("transcription_id = ?", job.ID).Delete(&models.Chapter{}).Error; err != nil {
+			return err
+		}
+		for i := range result {
+			if err := tx.Create(&result[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save chapters: %w", err)
+	}
+	return result, nil
+}
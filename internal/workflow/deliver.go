@@ -0,0 +1,155 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/integrations"
+	"scriberr/internal/models"
+)
+
+// DeliverActionItemsStep delivers a transcription's action items to the task integrations of
+// its owner that have AutoDeliver set. Tasks are linked back to the recording under
+// PublicURL, when set. Failed deliveries fail the step, and re-running it retries them
+// without delivering the others again.
+type DeliverActionItemsStep struct {
+	PublicURL            string
+	AllowPrivateWebhooks bool // Let webhook integrations be on private networks
+}
+
+// Run delivers the action items, returning the created tasks one per line
+func (s *DeliverActionItemsStep) Run(ctx context.Context, rc *RunContext) (string, error) {
+	var targets []models.TaskIntegration
+	query := database.DB.Where("auto_deliver = ?", true)
+	if rc.Job.UserID != nil {
+		query = query.Where("user_id = ?", *rc.Job.UserID)
+	} else {
+		query = query.Where("user_id IS NULL")
+	}
+	if err := query.Order("created_at").Find(&targets).Error; err != nil {
+		return "", fmt.Errorf("failed to load task integrations: %w", err)
+	}
+	if len(targets) == 0 {
+		return "", ErrSkipped
+	}
+	var count int64
+	if err := database.DB.Model(&models.ActionItem{}).Where("transcription_id = ?", rc.Job.ID).Count(&count).Error; err != nil {
+		return "", fmt.Errorf("failed to count action items: %w", err)
+	}
+	if count == 0 {
+		return "", ErrSkipped
+	}
+
+	var lines, failures []string
+	for i := range targets {
+		deliveries, err := DeliverActionItems(ctx, s.PublicURL, s.AllowPrivateWebhooks, &targets[i], rc.Job)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", targets[i].Name, err))
+			continue
+		}
+		for _, delivery := range deliveries {
+			if delivery.Status == models.DeliveryFailed {
+				failures = append(failures, fmt.Sprintf("%s: %s", targets[i].Name, delivery.Error))
+				continue
+			}
+			line := fmt.Sprintf("- %s: %s", targets[i].Name, delivery.ExternalID)
+			if delivery.ExternalURL != "" {
+				line += " " + delivery.ExternalURL
+			}
+			lines = append(lines, line)
+		}
+	}
+	if len(failures) > 0 {
+		return "", fmt.Errorf("failed to deliver action items: %s", strings.Join(failures, "; "))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// RecordingURL links to a recording in the web app, at the given second if not nil, or
+// returns "" when publicURL isn't set
+func RecordingURL(publicURL, jobID string, seconds *float64) string {
+	if publicURL == "" {
+		return ""
+	}
+	url := strings.TrimRight(publicURL, "/") + "/audio/" + jobID
+	if seconds != nil {
+		url += fmt.Sprintf("?t=%d", int(*seconds))
+	}
+	return url
+}
+
+// DeliverActionItems creates a task for each open action item of a job in a task integration,
+// in the order they were mentioned, and records the deliveries. Tasks already delivered to
+// the integration are skipped; ones that failed before are tried again. A failed task doesn't
+// stop the others: the returned deliveries carry each task's outcome. A webhook integration
+// on a private network is refused unless allowPrivate is set.
+func DeliverActionItems(ctx context.Context, publicURL string, allowPrivate bool, target *models.TaskIntegration, job *models.TranscriptionJob) ([]models.ActionItemDelivery, error) {
+	client, err := integrations.New(target.Provider, target.Token, target.Target, target.WebhookURL, allowPrivate)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []models.ActionItem
+	if err := database.DB.Where("transcription_id = ? AND completed = ?", job.ID, false).
+		Order("source_time ASC, created_at ASC").Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to load action items: %w", err)
+	}
+	var previous []models.ActionItemDelivery
+	if err := database.DB.Where("integration_id = ? AND transcription_id = ?", target.ID, job.ID).Find(&previous).Error; err != nil {
+		return nil, fmt.Errorf("failed to load deliveries: %w", err)
+	}
+	existing := make(map[string]models.ActionItemDelivery, len(previous))
+	for _, delivery := range previous {
+		existing[delivery.TaskKey] = delivery
+	}
+	assignees := make(map[string]string, len(target.Assignees))
+	for owner, account := range target.Assignees {
+		assignees[strings.ToLower(strings.TrimSpace(owner))] = account
+	}
+
+	var deliveries []models.ActionItemDelivery
+	for _, item := range items {
+		key := strings.ToLower(strings.TrimSpace(item.Task))
+		delivery, found := existing[key]
+		if found && delivery.Status == models.DeliveryDelivered {
+			continue
+		}
+
+		task := integrations.Task{
+			ActionItemID: item.ID,
+			Title:        item.Task,
+			Owner:        item.Owner,
+			Assignee:     assignees[strings.ToLower(item.Owner)],
+			Due:          item.Due,
+			RecordingID:  job.ID,
+			RecordingURL: RecordingURL(publicURL, job.ID, item.SourceTime),
+			Timestamp:    item.SourceTime,
+		}
+		if job.Title != nil {
+			task.RecordingTitle = *job.Title
+		}
+		if job.Summary != nil {
+			task.Summary = *job.Summary
+		}
+
+		result, err := client.CreateTask(ctx, task)
+		delivery.IntegrationID = target.ID
+		delivery.TranscriptionID = job.ID
+		delivery.TaskKey = key
+		delivery.ActionItemID = item.ID
+		delivery.ExternalID, delivery.ExternalURL = result.ID, result.URL
+		if err != nil {
+			delivery.Status, delivery.Error = models.DeliveryFailed, err.Error()
+		} else {
+			delivery.Status, delivery.Error = models.DeliveryDelivered, ""
+		}
+		if err := database.DB.Save(&delivery).Error; err != nil {
+			return deliveries, fmt.Errorf("failed to record delivery: %w", err)
+		}
+		existing[key] = delivery // A task extracted twice is delivered once
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,638 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/events"
+	"scriberr/internal/models"
+	"scriberr/internal/tracing"
+	"scriberr/internal/transcription"
+	"scriberr/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// ErrSkipped is returned by a step that decided not to run; the step is marked skipped
+// and its dependents still run.
+var ErrSkipped = errors.New("step skipped")
+
+// Step is a single unit of post-processing work
+type Step interface {
+	// Run executes the step and returns output that dependent steps can read
+	Run(ctx context.Context, rc *RunContext) (string, error)
+}
+
+// StepSpec declares a step within a workflow and the steps it waits for
+type StepSpec struct {
+	Name      string   `json:"name"`
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// Definition is a named chain of dependent steps
+type Definition struct {
+	Name  string     `json:"name"`
+	Steps []StepSpec `json:"steps"`
+}
+
+// RunContext carries everything a step needs to do its work
+type RunContext struct {
+	Run        *models.WorkflowRun
+	Job        *models.TranscriptionJob
+	Transcript string            // Plain transcript text
+	Params     map[string]string // Run parameters, e.g. target_language
+	Outputs    map[string]string // Outputs of completed steps, keyed by step name
+}
+
+// RetryPollInterval is how often the engine looks for failed steps due for a retry
+const RetryPollInterval = 15 * time.Second
+
+// RetryPolicy controls the automatic retries of failed steps
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts before a step is dead-lettered; 1 or less turns retries off
+	BaseDelay   time.Duration // Wait before the first retry, doubled for each one after it
+	MaxDelay    time.Duration // Longest wait between retries; an hour when zero
+}
+
+// Engine runs workflows as chains of dependent, individually persisted steps
+type Engine struct {
+	steps           map[string]Step
+	definitions     map[string]Definition
+	disabled        map[string]bool // Steps skipped in every run
+	defaultWorkflow string
+	stepTimeout     time.Duration
+	retry           RetryPolicy
+	stop            chan struct{}
+
+	mu     sync.Mutex
+	active map[string]bool // run IDs currently executing
+}
+
+// NewEngine creates a workflow engine that starts defaultWorkflow when a transcription completes
+func NewEngine(defaultWorkflow string) *Engine {
+	return &Engine{
+		steps:           make(map[string]Step),
+		definitions:     make(map[string]Definition),
+		disabled:        make(map[string]bool),
+		defaultWorkflow: defaultWorkflow,
+		stepTimeout:     10 * time.Minute,
+		active:          make(map[string]bool),
+	}
+}
+
+// RegisterStep makes a step available to workflow definitions
+func (e *Engine) RegisterStep(name string, step Step) {
+	e.steps[name] = step
+}
+
+// RegisterWorkflow validates and registers a workflow definition
+func (e *Engine) RegisterWorkflow(def Definition) error {
+	seen := make(map[string]bool, len(def.Steps))
+	for _, spec := range def.Steps {
+		if _, ok := e.steps[spec.Name]; !ok {
+			return fmt.Errorf("workflow %s: unknown step %s", def.Name, spec.Name)
+		}
+		// Dependencies must be declared earlier, which also rules out cycles
+		for _, dep := range spec.DependsOn {
+			if !seen[dep] {
+				return fmt.Errorf("workflow %s: step %s depends on %s, which is not declared before it", def.Name, spec.Name, dep)
+			}
+		}
+		if seen[spec.Name] {
+			return fmt.Errorf("workflow %s: duplicate step %s", def.Name, spec.Name)
+		}
+		seen[spec.Name] = true
+	}
+	e.definitions[def.Name] = def
+	return nil
+}
+
+// SetRetryPolicy makes failed steps retry automatically, with exponential backoff, once
+// StartRetries is called. A step that fails on its last attempt is dead-lettered.
+func (e *Engine) SetRetryPolicy(policy RetryPolicy) {
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = time.Hour
+	}
+	e.retry = policy
+}
+
+// StartRetries re-runs failed steps whose retry is due, checking every interval
+func (e *Engine) StartRetries(interval time.Duration) {
+	e.stop = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				e.RetryDue()
+			case <-e.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the retries started by StartRetries
+func (e *Engine) Stop() {
+	if e.stop != nil {
+		close(e.stop)
+	}
+}
+
+// RetryDue re-runs the failed steps whose retry is due, one step per run, returning how many
+// were started. A step whose run is still executing is retried on a later call.
+func (e *Engine) RetryDue() int {
+	var due []models.WorkflowStep
+	if err := database.DB.Where("status = ? AND next_retry_at <= ?", models.WorkflowFailed, time.Now()).
+		Order("run_id, position").Find(&due).Error; err != nil {
+		logger.Error("Failed to load steps due for a retry", "component", "workflow", "error", err)
+		return 0
+	}
+	started := 0
+	seen := map[string]bool{}
+	for _, step := range due {
+		if seen[step.RunID] {
+			continue
+		}
+		seen[step.RunID] = true
+		if err := e.RerunStep(step.RunID, step.Name); err != nil {
+			logger.Error("Failed to retry step", "component", "workflow", "run_id", step.RunID, "step", step.Name, "error", err)
+			continue
+		}
+		logger.Info("Retrying step", "component", "workflow", "run_id", step.RunID, "step", step.Name, "attempt", step.Attempts+1)
+		started++
+	}
+	return started
+}
+
+// Requeue re-runs a failed or dead-lettered step and its dependents with a fresh set of retries
+func (e *Engine) Requeue(runID, stepName string) error {
+	if e.isActive(runID) {
+		return fmt.Errorf("workflow run %s is still executing", runID)
+	}
+	if err := database.DB.Model(&models.WorkflowStep{}).
+		Where("run_id = ? AND name = ?", runID, stepName).
+		Update("attempts", 0).Error; err != nil {
+		return fmt.Errorf("failed to reset attempts: %w", err)
+	}
+	return e.RerunStep(runID, stepName)
+}
+
+// LoadDefinitions registers the workflow definitions in a JSON file, a list of objects like
+// {"name": "meetings", "steps": [{"name": "summarize"}, {"name": "notify", "depends_on": ["summarize"]}]}.
+// A definition named like a built-in one replaces it.
+func (e *Engine) LoadDefinitions(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read workflow definitions: %w", err)
+	}
+	var defs []Definition
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return fmt.Errorf("failed to parse workflow definitions in %s: %w", path, err)
+	}
+	for _, def := range defs {
+		if def.Name == "" {
+			return fmt.Errorf("workflow definition in %s has no name", path)
+		}
+		if err := e.RegisterWorkflow(def); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DisableSteps turns steps off for every run: they are marked skipped without running, so
+// the steps depending on them still run
+func (e *Engine) DisableSteps(names []string) error {
+	for _, name := range names {
+		if _, ok := e.steps[name]; !ok {
+			return fmt.Errorf("cannot disable unknown step %s", name)
+		}
+	}
+	for _, name := range names {
+		e.disabled[name] = true
+	}
+	return nil
+}
+
+// StepNames returns the names of the registered steps, sorted
+func (e *Engine) StepNames() []string {
+	names := make([]string, 0, len(e.steps))
+	for name := range e.steps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DisabledSteps returns the names of the steps turned off for every run, sorted
+func (e *Engine) DisabledSteps() []string {
+	names := make([]string, 0, len(e.disabled))
+	for name := range e.disabled {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Definitions returns the registered workflow definitions
+func (e *Engine) Definitions() []Definition {
+	defs := make([]Definition, 0, len(e.definitions))
+	for _, def := range e.definitions {
+		defs = append(defs, def)
+	}
+	return defs
+}
+
+// OnTranscriptionCompleted starts the default workflow for a completed job
+func (e *Engine) OnTranscriptionCompleted(jobID string) {
+	if _, err := e.Start(jobID, e.defaultWorkflow, nil); err != nil {
+		logger.Error("Failed to start workflow", "component", "workflow", "job_id", jobID, "workflow", e.defaultWorkflow, "error", err)
+	}
+}
+
+// Start records a new run of a workflow for a transcription and executes it in the background
+func (e *Engine) Start(transcriptionID, workflowName string, params map[string]string) (*models.WorkflowRun, error) {
+	def, ok := e.definitions[workflowName]
+	if !ok {
+		return nil, fmt.Errorf("unknown workflow: %s", workflowName)
+	}
+
+	run := models.WorkflowRun{
+		TranscriptionID: transcriptionID,
+		Workflow:        def.Name,
+		Status:          models.WorkflowPending,
+	}
+	if len(params) > 0 {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode params: %w", err)
+		}
+		encoded := string(data)
+		run.Params = &encoded
+	}
+	for i, spec := range def.Steps {
+		run.Steps = append(run.Steps, models.WorkflowStep{
+			Name:      spec.Name,
+			Position:  i,
+			DependsOn: strings.Join(spec.DependsOn, ","),
+			Status:    models.WorkflowPending,
+		})
+	}
+
+	if err := database.DB.Create(&run).Error; err != nil {
+		return nil, fmt.Errorf("failed to create workflow run: %w", err)
+	}
+
+	go e.execute(run.ID)
+	return &run, nil
+}
+
+// RerunStep resets a step and everything downstream of it, then executes the run again.
+// Steps that already completed upstream are not repeated.
+func (e *Engine) RerunStep(runID, stepName string) error {
+	if e.isActive(runID) {
+		return fmt.Errorf("workflow run %s is still executing", runID)
+	}
+
+	var steps []models.WorkflowStep
+	if err := database.DB.Where("run_id = ?", runID).Order("position").Find(&steps).Error; err != nil {
+		return fmt.Errorf("failed to load workflow steps: %w", err)
+	}
+
+	reset := map[string]bool{stepName: true}
+	found := false
+	for _, step := range steps {
+		if step.Name == stepName {
+			found = true
+			continue
+		}
+		// Steps are ordered so dependencies always come first
+		for _, dep := range step.Dependencies() {
+			if reset[dep] {
+				reset[step.Name] = true
+				break
+			}
+		}
+	}
+	if !found {
+		return fmt.Errorf("step %s not found in run %s", stepName, runID)
+	}
+
+	names := make([]string, 0, len(reset))
+	for name := range reset {
+		names = append(names, name)
+	}
+	if err := database.DB.Model(&models.WorkflowStep{}).
+		Where("run_id = ? AND name IN ?", runID, names).
+		Updates(map[string]interface{}{
+			"status":        models.WorkflowPending,
+			"error":         nil,
+			"completed_at":  nil,
+			"next_retry_at": nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to reset steps: %w", err)
+	}
+	if err := database.DB.Model(&models.WorkflowRun{}).Where("id = ?", runID).
+		Update("status", models.WorkflowPending).Error; err != nil {
+		return fmt.Errorf("failed to reset run: %w", err)
+	}
+
+	go e.execute(runID)
+	return nil
+}
+
+// RecoverInterrupted fails steps and runs left running by a previous process so they can be
+// re-run. With retries on, the steps are retried straight away unless out of attempts.
+func (e *Engine) RecoverInterrupted() error {
+	msg := "interrupted by server restart"
+	if e.retry.MaxAttempts > 1 {
+		if err := database.DB.Model(&models.WorkflowStep{}).
+			Where("status = ? AND attempts < ?", models.WorkflowRunning, e.retry.MaxAttempts).
+			Updates(map[string]interface{}{"status": models.WorkflowFailed, "error": msg, "next_retry_at": time.Now()}).Error; err != nil {
+			return err
+		}
+		if err := database.DB.Model(&models.WorkflowStep{}).
+			Where("status = ?", models.WorkflowRunning).
+			Updates(map[string]interface{}{"status": models.WorkflowDeadLetter, "error": msg}).Error; err != nil {
+			return err
+		}
+	} else if err := database.DB.Model(&models.WorkflowStep{}).
+		Where("status = ?", models.WorkflowRunning).
+		Updates(map[string]interface{}{"status": models.WorkflowFailed, "error": msg}).Error; err != nil {
+		return err
+	}
+	return database.DB.Model(&models.WorkflowRun{}).
+		Where("status IN ?", []models.WorkflowStatus{models.WorkflowRunning, models.WorkflowPending}).
+		Update("status", models.WorkflowFailed).Error
+}
+
+// execute runs every pending step of a run whose dependencies are satisfied, in order
+func (e *Engine) execute(runID string) {
+	if !e.claim(runID) {
+		return
+	}
+	defer e.release(runID)
+
+	var run models.WorkflowRun
+	if err := database.DB.Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Where("id = ?", runID).First(&run).Error; err != nil {
+		logger.Error("Failed to load run", "component", "workflow", "run_id", runID, "error", err)
+		return
+	}
+
+	// Runs join the trace and log of their transcription, however long after it they run
+	ctx := logger.WithComponent(logger.WithJob(context.Background(), run.TranscriptionID), "workflow")
+	ctx, span := tracing.Start(tracing.ForJob(ctx, run.TranscriptionID), "workflow.run",
+		"job.id", run.TranscriptionID, "run.id", run.ID, "workflow", run.Workflow)
+	defer span.End()
+
+	rc, err := newRunContext(&run)
+	if err != nil {
+		logger.ErrorContext(ctx, "Run cannot start", "run_id", run.ID, "error", err)
+		span.RecordError(err)
+		e.finish(&run, models.WorkflowFailed)
+		return
+	}
+
+	database.DB.Model(&run).Update("status", models.WorkflowRunning)
+
+	statuses := make(map[string]models.WorkflowStatus, len(run.Steps))
+	for _, step := range run.Steps {
+		statuses[step.Name] = step.Status
+	}
+
+	for i := range run.Steps {
+		step := &run.Steps[i]
+		if step.Status != models.WorkflowPending {
+			continue
+		}
+
+		if blockedBy := firstUnsatisfied(step, statuses); blockedBy != "" {
+			msg := fmt.Sprintf("dependency %s did not complete", blockedBy)
+			step.Status = models.WorkflowBlocked
+			step.Error = &msg
+			database.DB.Save(step)
+			statuses[step.Name] = step.Status
+			continue
+		}
+
+		if e.isDisabled(rc, step.Name) {
+			disabled := "disabled"
+			step.Status = models.WorkflowSkipped
+			step.Output = &disabled
+			database.DB.Save(step)
+			statuses[step.Name] = step.Status
+			continue
+		}
+
+		e.runStep(ctx, rc, step)
+		statuses[step.Name] = step.Status
+	}
+
+	final := models.WorkflowCompleted
+	for _, status := range statuses {
+		if status == models.WorkflowFailed || status == models.WorkflowBlocked || status == models.WorkflowDeadLetter {
+			final = models.WorkflowFailed
+			break
+		}
+	}
+	span.SetAttribute("status", string(final))
+	e.finish(&run, final)
+	logger.InfoContext(ctx, "Run finished", "run_id", run.ID, "workflow", run.Workflow, "status", final)
+}
+
+// isDisabled reports whether a step is turned off for every run or, through the comma-separated
+// skip_steps parameter, for this one
+func (e *Engine) isDisabled(rc *RunContext, name string) bool {
+	if e.disabled[name] {
+		return true
+	}
+	for _, skipped := range strings.Split(rc.Params["skip_steps"], ",") {
+		if strings.TrimSpace(skipped) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// runStep executes a single step and persists its outcome
+func (e *Engine) runStep(ctx context.Context, rc *RunContext, step *models.WorkflowStep) {
+	now := time.Now()
+	step.Status = models.WorkflowRunning
+	step.Attempts++
+	step.StartedAt = &now
+	step.Error = nil
+	step.NextRetryAt = nil
+	database.DB.Save(step)
+
+	ctx, span := tracing.Start(ctx, "workflow.step", "job.id", rc.Job.ID, "step", step.Name, "attempt", step.Attempts)
+	defer span.End()
+	ctx, cancel := context.WithTimeout(ctx, e.stepTimeout)
+	defer cancel()
+
+	output, err := e.steps[step.Name].Run(ctx, rc)
+	completed := time.Now()
+	if !errors.Is(err, ErrSkipped) {
+		span.RecordError(err)
+	}
+	step.CompletedAt = &completed
+
+	switch {
+	case errors.Is(err, ErrSkipped):
+		step.Status = models.WorkflowSkipped
+	case err != nil:
+		msg := err.Error()
+		step.Status = models.WorkflowFailed
+		step.Error = &msg
+		logger.WarnContext(ctx, "Step failed", "step", step.Name, "attempt", step.Attempts, "error", err)
+		e.scheduleRetry(rc, step)
+	default:
+		step.Status = models.WorkflowCompleted
+		if output != "" {
+			step.Output = &output
+			rc.Outputs[step.Name] = output
+		}
+	}
+	database.DB.Save(step)
+	span.SetAttribute("status", string(step.Status))
+
+	data := map[string]interface{}{
+		"run_id":   rc.Run.ID,
+		"workflow": rc.Run.Workflow,
+		"step":     step.Name,
+		"status":   step.Status,
+		"attempts": step.Attempts,
+	}
+	if step.Error != nil {
+		data["error"] = *step.Error
+	}
+	events.Record(models.EventWorkflowStepFinished, rc.Job.ID, rc.Job.UserID, data)
+}
+
+// scheduleRetry sets when a failed step is retried, or dead-letters it when it has used up
+// its attempts
+func (e *Engine) scheduleRetry(rc *RunContext, step *models.WorkflowStep) {
+	if e.retry.MaxAttempts <= 1 {
+		return
+	}
+	if step.Attempts < e.retry.MaxAttempts {
+		delay := e.retry.BaseDelay
+		for i := 1; i < step.Attempts && delay < e.retry.MaxDelay; i++ {
+			delay *= 2
+		}
+		retryAt := time.Now().Add(min(delay, e.retry.MaxDelay))
+		step.NextRetryAt = &retryAt
+		return
+	}
+
+	step.Status = models.WorkflowDeadLetter
+	logger.Error("Step dead-lettered", "component", "workflow", "job_id", rc.Job.ID, "step", step.Name, "attempts", step.Attempts)
+	events.Record(models.EventWorkflowStepDeadLettered, rc.Job.ID, rc.Job.UserID, map[string]interface{}{
+		"run_id":   rc.Run.ID,
+		"workflow": rc.Run.Workflow,
+		"step":     step.Name,
+		"attempts": step.Attempts,
+		"error":    *step.Error,
+	})
+}
+
+// finish records the final status of a run
+func (e *Engine) finish(run *models.WorkflowRun, status models.WorkflowStatus) {
+	run.Status = status
+	database.DB.Model(run).Update("status", status)
+	events.RecordForJob(models.EventWorkflowFinished, run.TranscriptionID, map[string]interface{}{
+		"run_id":   run.ID,
+		"workflow": run.Workflow,
+		"status":   status,
+	})
+}
+
+// firstUnsatisfied returns the first dependency that didn't complete or skip, if any
+func firstUnsatisfied(step *models.WorkflowStep, statuses map[string]models.WorkflowStatus) string {
+	for _, dep := range step.Dependencies() {
+		if s := statuses[dep]; s != models.WorkflowCompleted && s != models.WorkflowSkipped {
+			return dep
+		}
+	}
+	return ""
+}
+
+// newRunContext loads the job and transcript a run operates on, along with earlier step outputs
+func newRunContext(run *models.WorkflowRun) (*RunContext, error) {
+	var job models.TranscriptionJob
+	if err := database.DB.Where("id = ?", run.TranscriptionID).First(&job).Error; err != nil {
+		return nil, fmt.Errorf("failed to load job: %w", err)
+	}
+	text, err := TranscriptText(&job)
+	if err != nil {
+		return nil, err
+	}
+
+	rc := &RunContext{
+		Run:        run,
+		Job:        &job,
+		Transcript: text,
+		Params:     map[string]string{},
+		Outputs:    map[string]string{},
+	}
+	if run.Params != nil {
+		if err := json.Unmarshal([]byte(*run.Params), &rc.Params); err != nil {
+			return nil, fmt.Errorf("failed to decode params: %w", err)
+		}
+	}
+	for _, step := range run.Steps {
+		if step.Status == models.WorkflowCompleted && step.Output != nil {
+			rc.Outputs[step.Name] = *step.Output
+		}
+	}
+	return rc, nil
+}
+
+// TranscriptText returns the plain text of a completed job's transcript, as given to the LLM
+func TranscriptText(job *models.TranscriptionJob) (string, error) {
+	if job.Status != models.StatusCompleted || job.Transcript == nil || *job.Transcript == "" {
+		return "", fmt.Errorf("job has no completed transcript")
+	}
+
+	text, err := transcription.ExtractTranscriptText(*job.Transcript)
+	if err != nil {
+		// Fallback: use raw transcript if JSON parsing fails
+		text = *job.Transcript
+	}
+	if strings.TrimSpace(text) == "" {
+		return "", fmt.Errorf("transcript text is empty")
+	}
+	return text, nil
+}
+
+func (e *Engine) claim(runID string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.active[runID] {
+		return false
+	}
+	e.active[runID] = true
+	return true
+}
+
+func (e *Engine) release(runID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.active, runID)
+}
+
+func (e *Engine) isActive(runID string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.active[runID]
+}
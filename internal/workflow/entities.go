@@ -0,0 +1,173 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// entitiesSchema is the JSON Schema of an entity extraction reply
+var entitiesSchema = llm.Schema{
+	Name:        "entities",
+	Description: "The named entities mentioned in a transcribed recording",
+	Definition: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "entities": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string", "description": "The entity's full name as it is usually written"},
+          "type": {"type": "string", "enum": ["person", "organization", "product", "location"]},
+          "timestamps": {"type": "array", "items": {"type": "number"}, "description": "Seconds values of the [seconds] markers of the lines where it is mentioned"}
+        },
+        "required": ["name", "type", "timestamps"]
+      }
+    }
+  },
+  "required": ["entities"]
+}`),
+}
+
+// extractedEntity is one entity of an extraction reply
+type extractedEntity struct {
+	Name       string    `json:"name"`
+	Type       string    `json:"type"`
+	Timestamps []float64 `json:"timestamps"`
+}
+
+// EntitiesStep extracts the people, organizations, products and locations mentioned in a
+// transcript into the entity_mentions table. It only runs when Enabled, or when the run's
+// entities parameter is "true"; a parameter of "false" turns it off for one run.
+type EntitiesStep struct {
+	LLM     LLMService
+	Model   string
+	Enabled bool
+}
+
+// Run extracts and saves the entities, returning them one per line
+func (s *EntitiesStep) Run(ctx context.Context, rc *RunContext) (string, error) {
+	enabled := s.Enabled
+	if param := rc.Params["entities"]; param != "" {
+		enabled = param == "true"
+	}
+	if !enabled {
+		return "", ErrSkipped
+	}
+
+	mentions, err := ExtractEntities(ctx, s.LLM, s.Model, rc.Job)
+	if err != nil {
+		return "", err
+	}
+	var lines []string
+	seen := map[string]bool{}
+	for _, mention := range mentions {
+		line := fmt.Sprintf("- %s (%s)", mention.Name, mention.Type)
+		if !seen[line] {
+			seen[line] = true
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// ExtractEntities asks the LLM for the named entities of a job's transcript and replaces the
+// job's stored mentions with them, one per entity and time it was mentioned
+func ExtractEntities(ctx context.Context, service LLMService, model string, job *models.TranscriptionJob) ([]models.EntityMention, error) {
+	transcript, timed, _, err := speakerTranscript(job)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt := "List the people, organizations, products and locations mentioned in the following transcription. " +
+		"Give each one once, under its full name, with the [seconds] markers of every line where it is mentioned. " +
+		"Don't list the speakers unless they are mentioned by name.\n\n" + truncateForLLM(transcript)
+	messages := []llm.ChatMessage{{Role: "user", Content: prompt}}
+
+	var reply struct {
+		Entities []extractedEntity `json:"entities"`
+	}
+	if err := llm.CompleteJSON(ctx, service, model, messages, 0.1, entitiesSchema, &reply); err != nil {
+		return nil, fmt.Errorf("entity extraction failed: %w", err)
+	}
+
+	// The same entity may come back more than once; the first spelling wins
+	names := map[string]string{}
+	times := map[string]map[float64]bool{}
+	var keys []string
+	for _, entity := range reply.Entities {
+		name := strings.Join(strings.Fields(entity.Name), " ")
+		entityType := strings.ToLower(strings.TrimSpace(entity.Type))
+		if name == "" || !isEntityType(entityType) {
+			continue
+		}
+		key := entityType + "\x00" + strings.ToLower(name)
+		if _, ok := names[key]; !ok {
+			names[key] = name
+			times[key] = map[float64]bool{}
+			keys = append(keys, key)
+		}
+		if timed {
+			for _, seconds := range entity.Timestamps {
+				if seconds >= 0 {
+					times[key][seconds] = true
+				}
+			}
+		}
+	}
+
+	var mentions []models.EntityMention
+	for _, key := range keys {
+		entityType := key[:strings.IndexByte(key, 0)]
+		mention := models.EntityMention{TranscriptionID: job.ID, UserID: job.UserID, Type: entityType, Name: names[key], Model: model}
+		if len(times[key]) == 0 {
+			mentions = append(mentions, mention)
+			continue
+		}
+		seconds := make([]float64, 0, len(times[key]))
+		for value := range times[key] {
+			seconds = append(seconds, value)
+		}
+		sort.Float64s(seconds)
+		for i := range seconds {
+			timedMention := mention
+			timedMention.SourceTime = &seconds[i]
+			mentions = append(mentions, timedMention)
+		}
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("transcription_id = ?", job.ID).Delete(&models.EntityMention{}).Error; err != nil {
+			return err
+		}
+		for i := range mentions {
+			if err := tx.Create(&mentions[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save entities: %w", err)
+	}
+	return mentions, nil
+}
+
+// isEntityType reports whether t is one of models.EntityTypes
+func isEntityType(t string) bool {
+	for _, known := range models.EntityTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
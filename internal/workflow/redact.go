@@ -0,0 +1,154 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/export"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/redact"
+	"scriberr/internal/transcription/interfaces"
+
+	"gorm.io/gorm"
+)
+
+// namesSchema is the JSON Schema of a name detection reply
+var namesSchema = llm.Schema{
+	Name:        "names",
+	Description: "The names of the people in a transcribed recording",
+	Definition: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "names": {
+      "type": "array",
+      "items": {"type": "string", "description": "A person's name exactly as it is written in the transcription"}
+    }
+  },
+  "required": ["names"]
+}`),
+}
+
+// RedactStep saves a redacted variant of the transcript, with email addresses, phone numbers,
+// payment card numbers and the names of people replaced by placeholders. It only runs when
+// Enabled, or when the run's redact_pii parameter is "true"; a parameter of "false" turns it
+// off for one run.
+type RedactStep struct {
+	LLM     LLMService
+	Model   string
+	Enabled bool
+}
+
+// Run redacts the transcript, returning how much of each kind of data was replaced
+func (s *RedactStep) Run(ctx context.Context, rc *RunContext) (string, error) {
+	enabled := s.Enabled
+	if param := rc.Params["redact_pii"]; param != "" {
+		enabled = param == "true"
+	}
+	if !enabled {
+		return "", ErrSkipped
+	}
+
+	redaction, err := RedactJob(ctx, s.LLM, s.Model, rc.Job)
+	if err != nil {
+		return "", err
+	}
+	var lines []string
+	for _, kind := range redact.Kinds {
+		if n := redaction.Counts[kind]; n > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %d", kind, n))
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// RedactJob finds the personal data in a job's transcript and saves the redacted transcript,
+// replacing an earlier one. Email addresses, phone numbers and card numbers are found by
+// pattern; names are asked of the LLM, a batch of segments at a time, unless service is nil.
+func RedactJob(ctx context.Context, service LLMService, model string, job *models.TranscriptionJob) (*models.Redaction, error) {
+	segments := export.TranscriptSegments(job)
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("no transcript available")
+	}
+
+	var names []string
+	if service != nil {
+		var err error
+		if names, err = findNames(ctx, service, model, segments); err != nil {
+			return nil, err
+		}
+	} else {
+		model = "" // No model was involved
+	}
+
+	redactor := redact.New(names)
+	counts := map[string]int{}
+	result := interfaces.TranscriptResult{Language: transcriptLanguage(job)}
+	texts := make([]string, len(segments))
+	for i, segment := range segments {
+		text, found := redactor.Redact(segment.Text)
+		for kind, n := range found {
+			counts[kind] += n
+		}
+		segment.Text = text
+		result.Segments = append(result.Segments, segment)
+		texts[i] = strings.TrimSpace(text)
+	}
+	result.Text = strings.Join(texts, " ")
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode redacted transcript: %w", err)
+	}
+
+	redaction := models.Redaction{TranscriptionID: job.ID, Transcript: string(data), Counts: counts, Names: names, Model: model}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("transcription_id = ?", job.ID).Delete(&models.Redaction{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&redaction).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save redacted transcript: %w", err)
+	}
+	return &redaction, nil
+}
+
+// findNames asks the LLM for the names of the people in segments, in batches, returning each
+// name once in the order found
+func findNames(ctx context.Context, service LLMService, model string, segments []interfaces.TranscriptSegment) ([]string, error) {
+	seen := map[string]bool{}
+	var names []string
+	for start := 0; start < len(segments); {
+		end, length := start, 0
+		var batch strings.Builder
+		for end < len(segments) && (end == start || length+len(segments[end].Text) <= translationBatchLength) {
+			length += len(segments[end].Text)
+			batch.WriteString(strings.Join(strings.Fields(segments[end].Text), " "))
+			batch.WriteByte('\n')
+			end++
+		}
+
+		prompt := "List the names of the people mentioned or addressed in the following transcription, each once, " +
+			"exactly as they are written. Include first names and surnames used on their own. " +
+			"Don't list organizations, products or places.\n\n" + batch.String()
+		messages := []llm.ChatMessage{{Role: "user", Content: prompt}}
+		var reply struct {
+			Names []string `json:"names"`
+		}
+		if err := llm.CompleteJSON(ctx, service, model, messages, 0.1, namesSchema, &reply); err != nil {
+			return nil, fmt.Errorf("name detection failed: %w", err)
+		}
+		for _, name := range reply.Names {
+			name = strings.Join(strings.Fields(name), " ")
+			if name != "" && !seen[strings.ToLower(name)] {
+				seen[strings.ToLower(name)] = true
+				names = append(names, name)
+			}
+		}
+		start = end
+	}
+	return names, nil
+}
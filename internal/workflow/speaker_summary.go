@@ -0,0 +1,91 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+)
+
+// speakerSummarySchema is the JSON Schema of a summary with the per-speaker layout
+var speakerSummarySchema = llm.Schema{
+	Name:        "speaker_summary",
+	Description: "A summary of a transcribed recording with a section per participant",
+	Definition: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "title": {"type": "string", "description": "A short descriptive title for the recording"},
+    "tldr": {"type": "string", "description": "One or two sentences capturing the essence of the recording"},
+    "speakers": {
+      "type": "array",
+      "description": "One section per participant, in the order they first spoke",
+      "items": {
+        "type": "object",
+        "properties": {
+          "speaker": {"type": "string", "description": "The participant's name exactly as it appears before their lines"},
+          "key_points": {"type": "array", "items": {"type": "string"}, "description": "The main points this participant made"},
+          "commitments": {"type": "array", "items": {"type": "string"}, "description": "What this participant said they would do, empty if nothing"}
+        },
+        "required": ["speaker", "key_points", "commitments"]
+      }
+    }
+  },
+  "required": ["title", "tldr", "speakers"]
+}`),
+}
+
+// summarizeBySpeaker generates a structured summary with a section per participant from a
+// job's speaker-attributed segments, following a summary template's instructions when they
+// are given. Transcripts without speaker labels get a plain structured summary instead.
+func summarizeBySpeaker(ctx context.Context, service LLMService, model string, job *models.TranscriptionJob, transcript, instructions string) (*models.StructuredSummary, error) {
+	attributedTranscript, _, attributed, err := speakerTranscript(job)
+	if err != nil || !attributed {
+		return summarizeStructured(ctx, service, model, transcript, instructions)
+	}
+
+	prompt := "Summarize the following transcription, then give each participant a section with the main points they made " +
+		"and what they committed to do. Lines start with a [seconds] marker and the speaker's name. " +
+		"Only list commitments that were actually stated.\n\n"
+	if instructions != "" {
+		prompt += "Instructions:\n" + instructions + "\n\nTranscript:\n"
+	}
+	prompt += truncateForLLM(attributedTranscript)
+	messages := []llm.ChatMessage{{Role: "user", Content: prompt}}
+
+	var summary models.StructuredSummary
+	if err := llm.CompleteJSON(ctx, service, model, messages, 0.3, speakerSummarySchema, &summary); err != nil {
+		return nil, fmt.Errorf("per-speaker summary failed: %w", err)
+	}
+	summary.Title = strings.TrimSpace(summary.Title)
+	summary.TLDR = strings.TrimSpace(summary.TLDR)
+
+	sections := summary.Speakers[:0]
+	for _, section := range summary.Speakers {
+		section.Speaker = strings.TrimSpace(section.Speaker)
+		section.KeyPoints = trimItems(section.KeyPoints)
+		section.Commitments = trimItems(section.Commitments)
+		if section.Speaker == "" || len(section.KeyPoints)+len(section.Commitments) == 0 {
+			continue
+		}
+		sections = append(sections, section)
+	}
+	summary.Speakers = sections
+	if summary.TLDR == "" && len(summary.Speakers) == 0 {
+		return nil, fmt.Errorf("per-speaker summary is empty")
+	}
+	return &summary, nil
+}
+
+// trimItems trims list entries and drops empty ones
+func trimItems(items []string) []string {
+	kept := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,78 @@
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// StepState is the latest state of one post-processing step for a transcription, taken from
+// the newest run that includes the step
+type StepState struct {
+	Name        string                `json:"name"`
+	Status      models.WorkflowStatus `json:"status"`
+	Attempts    int                   `json:"attempts"`
+	Error       *string               `json:"error,omitempty"`
+	RunID       string                `json:"run_id"`
+	Workflow    string                `json:"workflow"`
+	StartedAt   *time.Time            `json:"started_at,omitempty"`
+	CompletedAt *time.Time            `json:"completed_at,omitempty"`
+	NextRetryAt *time.Time            `json:"next_retry_at,omitempty"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+// JobStatus sums up the post-processing of a transcription
+type JobStatus struct {
+	TranscriptionID string `json:"transcription_id"`
+	// Status of the newest run, or empty when post-processing never ran
+	Status    models.WorkflowStatus `json:"status,omitempty"`
+	Runs      int                   `json:"runs"`
+	Steps     []StepState           `json:"steps"`
+	UpdatedAt *time.Time            `json:"updated_at,omitempty"`
+}
+
+// Status returns the latest state of every post-processing step run for a transcription. A
+// step re-run by a later workflow, e.g. summarize run again by hand, reports its newest state.
+func Status(transcriptionID string) (*JobStatus, error) {
+	var runs []models.WorkflowRun
+	if err := database.DB.Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Where("transcription_id = ?", transcriptionID).
+		Order("created_at DESC").
+		Find(&runs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load workflow runs: %w", err)
+	}
+
+	status := &JobStatus{TranscriptionID: transcriptionID, Runs: len(runs), Steps: []StepState{}}
+	if len(runs) == 0 {
+		return status, nil
+	}
+	status.Status = runs[0].Status
+	status.UpdatedAt = &runs[0].UpdatedAt
+
+	seen := map[string]bool{}
+	for _, run := range runs {
+		for _, step := range run.Steps {
+			if seen[step.Name] {
+				continue
+			}
+			seen[step.Name] = true
+			status.Steps = append(status.Steps, StepState{
+				Name:        step.Name,
+				Status:      step.Status,
+				Attempts:    step.Attempts,
+				Error:       step.Error,
+				RunID:       run.ID,
+				Workflow:    run.Workflow,
+				StartedAt:   step.StartedAt,
+				CompletedAt: step.CompletedAt,
+				NextRetryAt: step.NextRetryAt,
+				UpdatedAt:   step.UpdatedAt,
+			})
+		}
+	}
+	return status, nil
+}
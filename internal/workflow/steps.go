@@ -0,0 +1,386 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/events"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/notify"
+	"scriberr/internal/projects"
+	"scriberr/internal/rag"
+	"scriberr/internal/versions"
+
+	"gorm.io/gorm"
+)
+
+// Built-in step names
+const (
+	StepSummarize            = "summarize"
+	StepTranslate            = "translate"
+	StepSummarizeTranslation = "summarize_translation"
+	StepExtractActionItems   = "extract_action_items"
+	StepDeliverActionItems   = "deliver_action_items"
+	StepGenerateTags         = "generate_tags"
+	StepExtractEntities      = "extract_entities"
+	StepSpeakerAnalytics     = "speaker_analytics"
+	StepGenerateChapters     = "generate_chapters"
+	StepRedactPII            = "redact_pii"
+	StepCorrectVocabulary    = "correct_vocabulary"
+	StepScanWatchlists       = "scan_watchlists"
+	StepRAGIndex             = "rag_index"
+	StepNotify               = "notify"
+)
+
+// maxLLMInputLength limits the transcript text sent to the LLM (to avoid token limits)
+const maxLLMInputLength = 10000
+
+// LLMService interface for workflow steps
+type LLMService interface {
+	ChatCompletion(ctx context.Context, model string, messages []llm.ChatMessage, temperature float64) (*llm.ChatResponse, error)
+}
+
+// complete sends a single-prompt chat completion and returns the reply text
+func complete(ctx context.Context, service LLMService, model, prompt string, temperature float64) (string, error) {
+	messages := []llm.ChatMessage{
+		{Role: "user", Content: prompt},
+	}
+
+	response, err := service.ChatCompletion(ctx, model, messages, temperature)
+	if err != nil {
+		return "", fmt.Errorf("LLM call failed: %w", err)
+	}
+
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("no response from LLM")
+	}
+
+	return response.Choices[0].Message.Content, nil
+}
+
+// truncateForLLM shortens text that would exceed the LLM input budget
+func truncateForLLM(text string) string {
+	if len(text) > maxLLMInputLength {
+		return text[:maxLLMInputLength] + "... [truncated]"
+	}
+	return text
+}
+
+// SummarizeStep summarizes the transcript and saves the summary on the job. The run's
+// summary_format parameter, or else Format, picks a free-text or structured summary, and
+// the job's summary template, if any, replaces the built-in instructions.
+type SummarizeStep struct {
+	LLM    LLMService
+	Model  string
+	Format string
+}
+
+// Run generates the summary
+func (s *SummarizeStep) Run(ctx context.Context, rc *RunContext) (string, error) {
+	format := rc.Params["summary_format"]
+	if format == "" {
+		format = s.Format
+	}
+	template, err := ResolveSummaryTemplate(rc.Job, rc.Params["summary_template_id"])
+	if err != nil {
+		return "", err
+	}
+	return GenerateSummary(ctx, s.LLM, rc.Job, rc.Transcript, SummaryOptions{
+		Model:       s.Model,
+		Temperature: 0.7,
+		Format:      format,
+		Template:    template,
+		Source:      "workflow",
+	})
+}
+
+// SummaryOptions controls how GenerateSummary summarizes a transcript
+type SummaryOptions struct {
+	Model       string
+	Temperature float64
+	// Format is SummaryFormatText (the default) or SummaryFormatStructured
+	Format string
+	// Template, if set, replaces the built-in instructions
+	Template *models.SummaryTemplate
+	// Source is recorded on the summary event, e.g. "workflow" or "api"
+	Source string
+	// RequestedBy is recorded on the summary's version; nil for automatic runs
+	RequestedBy *uint
+}
+
+// GenerateSummary summarizes a transcript and saves it as the job's summary, replacing any
+// earlier one and keeping it as a new version. Free-text summaries are also added to the job's
+// summary history.
+func GenerateSummary(ctx context.Context, service LLMService, job *models.TranscriptionJob, transcript string, opts SummaryOptions) (string, error) {
+	var instructions string
+	var templateID *string
+	if opts.Template != nil {
+		instructions = opts.Template.Prompt
+		templateID = &opts.Template.ID
+	}
+
+	var summary string
+	var structured *models.StructuredSummary
+	var err error
+	var layout string
+	if opts.Template != nil {
+		layout = opts.Template.Layout
+	}
+	switch {
+	case layout == models.SummaryLayoutPerSpeaker:
+		// Speaker sections are structured data, whatever the format
+		if structured, err = summarizeBySpeaker(ctx, service, opts.Model, job, transcript, instructions); err != nil {
+			return "", err
+		}
+		summary = structured.Markdown()
+	case opts.Format == "" || opts.Format == SummaryFormatText:
+		prompt := fmt.Sprintf("Please provide a concise summary of the following transcription:\n\n%s", truncateForLLM(transcript))
+		if opts.Template != nil {
+			prompt = templatePrompt(opts.Template, transcript)
+		}
+		text, err := complete(ctx, service, opts.Model, prompt, opts.Temperature)
+		if err != nil {
+			return "", err
+		}
+		summary = text
+	case opts.Format == SummaryFormatStructured:
+		if structured, err = summarizeStructured(ctx, service, opts.Model, transcript, instructions); err != nil {
+			return "", err
+		}
+		summary = structured.Markdown()
+	default:
+		return "", fmt.Errorf("unknown summary format %q", opts.Format)
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if structured == nil {
+			record := models.Summary{TranscriptionID: job.ID, TemplateID: templateID, Model: opts.Model, Content: summary}
+			if err := tx.Create(&record).Error; err != nil {
+				return err
+			}
+		}
+		content := versions.Content{Text: summary, Structured: structured, Model: &opts.Model}
+		_, err := versions.Save(tx, job.ID, models.VersionKindSummary, content, models.VersionSourceSummary, opts.RequestedBy)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to save summary: %w", err)
+	}
+	job.Summary = &summary
+	job.StructuredSummary = structured
+	job.SummaryModel = &opts.Model
+	data := map[string]interface{}{"model": opts.Model, "source": opts.Source, "format": opts.Format}
+	if templateID != nil {
+		data["template_id"] = *templateID
+	}
+	if layout != models.SummaryLayoutDefault {
+		data["layout"] = layout
+	}
+	events.Record(models.EventSummaryReady, job.ID, job.UserID, data)
+	return summary, nil
+}
+
+// TranslateStep translates the transcript into the run's target_language segment by
+// segment and saves it as the job's translation in that language. With Index set, or an
+// index_translation run parameter of "true", the translation is also indexed into RAG.
+type TranslateStep struct {
+	LLM             LLMService
+	Model           string
+	DefaultLanguage string
+	RAG             *rag.RAGService
+	Index           bool
+}
+
+// Run produces the translated transcript text
+func (s *TranslateStep) Run(ctx context.Context, rc *RunContext) (string, error) {
+	language := rc.Params["target_language"]
+	if language == "" {
+		language = s.DefaultLanguage
+	}
+	if language == "" {
+		return "", fmt.Errorf("no target_language given and no default translation language configured")
+	}
+	index := s.Index
+	if param := rc.Params["index_translation"]; param != "" {
+		index = param == "true"
+	}
+
+	translation, err := TranslateJob(ctx, s.LLM, s.Model, s.RAG, index, rc.Job, language)
+	if err != nil {
+		return "", err
+	}
+
+	lines := make([]string, 0, len(translation.Segments))
+	for _, segment := range translation.Segments {
+		if segment.Text != "" {
+			lines = append(lines, segment.Text)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// SummarizeTranslationStep summarizes the output of the translate step in the target language
+type SummarizeTranslationStep struct {
+	LLM   LLMService
+	Model string
+}
+
+// Run generates the translated summary
+func (s *SummarizeTranslationStep) Run(ctx context.Context, rc *RunContext) (string, error) {
+	translation := rc.Outputs[StepTranslate]
+	if strings.TrimSpace(translation) == "" {
+		return "", fmt.Errorf("no translation available")
+	}
+	prompt := fmt.Sprintf("Please provide a concise summary of the following transcription, written in the same language as the transcription:\n\n%s", truncateForLLM(translation))
+	return complete(ctx, s.LLM, s.Model, prompt, 0.7)
+}
+
+// RAGIndexStep stores the transcript and its summary in the vector store
+type RAGIndexStep struct {
+	RAG *rag.RAGService
+}
+
+// Run indexes the transcription. The summary is optional, so a failed summary step doesn't prevent indexing.
+func (s *RAGIndexStep) Run(ctx context.Context, rc *RunContext) (string, error) {
+	if s.RAG == nil {
+		return "", ErrSkipped
+	}
+	summary := rc.Outputs[StepSummarize]
+	if summary == "" && rc.Job.Summary != nil {
+		summary = *rc.Job.Summary
+	}
+	if err := s.RAG.StoreSummary(ctx, rc.Job.ID, summary, rc.Transcript); err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+// NotifyStep posts a completion event with the outputs of earlier steps
+type NotifyStep struct {
+	Notifier             *notify.WebhookNotifier
+	AllowPrivateWebhooks bool // Let the webhooks of jobs and projects be on private networks
+}
+
+// Run sends the notification to the configured webhook, the job's own and its project's, or
+// skips when there are none. Every webhook is tried; the first failure is returned.
+func (s *NotifyStep) Run(ctx context.Context, rc *RunContext) (string, error) {
+	var notifiers []*notify.WebhookNotifier
+	if s.Notifier != nil && s.Notifier.Enabled() {
+		notifiers = append(notifiers, s.Notifier)
+	}
+	settings, err := projects.ForJob(rc.Job)
+	if err != nil {
+		return "", err
+	}
+	seen := map[string]bool{}
+	for _, url := range append(append([]string{}, rc.Job.WebhookURLs...), settings.WebhookURLs...) {
+		if !seen[url] {
+			seen[url] = true
+			notifiers = append(notifiers, notify.NewUserWebhookNotifier(url, s.AllowPrivateWebhooks))
+		}
+	}
+	if len(notifiers) == 0 {
+		return "", ErrSkipped
+	}
+
+	event := notify.Event{
+		Type:            "workflow.completed",
+		TranscriptionID: rc.Job.ID,
+		Data: map[string]string{
+			"run_id":   rc.Run.ID,
+			"workflow": rc.Run.Workflow,
+		},
+	}
+	if rc.Job.Title != nil {
+		event.Title = *rc.Job.Title
+	}
+	for name, output := range rc.Outputs {
+		event.Data[name] = output
+	}
+
+	var firstErr error
+	for _, notifier := range notifiers {
+		if err := notifier.Send(ctx, event); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return "", firstErr
+}
+
+// RegisterBuiltins registers the built-in steps and the "default" and "bilingual" workflows.
+// The generate_tags and extract_action_items steps in both only run when autoTags and
+// actionItems are set, or when a run asks for them. When the RAG service embeds redacted
+// text, rag_index and translate, which may index the translation, wait for redact_pii so
+// they can use the names that step finds. deliver_action_items waits for the summary and the
+// action items, and links the tasks it creates to the recording under publicURL. Webhooks
+// users set may only be on private networks with allowPrivateWebhooks. correct_vocabulary
+// comes first, so the steps after it read the corrected transcript.
+func RegisterBuiltins(e *Engine, llmService LLMService, model, summaryFormat string, ragService *rag.RAGService, notifier *notify.WebhookNotifier, translationLanguage, publicURL string, allowPrivateWebhooks, indexTranslations, autoTags, actionItems, entities, speakerAnalytics, autoChapters, redactPII, correctVocabulary bool) error {
+	if summaryFormat != "" && summaryFormat != SummaryFormatText && summaryFormat != SummaryFormatStructured {
+		return fmt.Errorf("unknown summary format %q, expected %s or %s", summaryFormat, SummaryFormatText, SummaryFormatStructured)
+	}
+	e.RegisterStep(StepSummarize, &SummarizeStep{LLM: llmService, Model: model, Format: summaryFormat})
+	e.RegisterStep(StepTranslate, &TranslateStep{LLM: llmService, Model: model, DefaultLanguage: translationLanguage, RAG: ragService, Index: indexTranslations})
+	e.RegisterStep(StepSummarizeTranslation, &SummarizeTranslationStep{LLM: llmService, Model: model})
+	e.RegisterStep(StepGenerateTags, &GenerateTagsStep{LLM: llmService, Model: model, Enabled: autoTags, RAG: ragService})
+	e.RegisterStep(StepExtractActionItems, &ActionItemsStep{LLM: llmService, Model: model, Enabled: actionItems})
+	e.RegisterStep(StepDeliverActionItems, &DeliverActionItemsStep{PublicURL: publicURL, AllowPrivateWebhooks: allowPrivateWebhooks})
+	e.RegisterStep(StepExtractEntities, &EntitiesStep{LLM: llmService, Model: model, Enabled: entities})
+	e.RegisterStep(StepSpeakerAnalytics, &AnalyticsStep{LLM: llmService, Model: model, Enabled: speakerAnalytics})
+	e.RegisterStep(StepGenerateChapters, &ChaptersStep{LLM: llmService, Model: model, Enabled: autoChapters, RAG: ragService})
+	e.RegisterStep(StepRedactPII, &RedactStep{LLM: llmService, Model: model, Enabled: redactPII})
+	e.RegisterStep(StepCorrectVocabulary, &VocabularyStep{LLM: llmService, Model: model, Enabled: correctVocabulary})
+	e.RegisterStep(StepScanWatchlists, &WatchlistStep{RAG: ragService, Notifier: notifier, AllowPrivateWebhooks: allowPrivateWebhooks})
+	e.RegisterStep(StepRAGIndex, &RAGIndexStep{RAG: ragService})
+	e.RegisterStep(StepNotify, &NotifyStep{Notifier: notifier, AllowPrivateWebhooks: allowPrivateWebhooks})
+
+	var indexDeps []string
+	if ragService != nil && ragService.EmbedsRedacted() {
+		indexDeps = []string{StepRedactPII}
+	}
+
+	// default: summarize → index → notify (indexing doesn't wait on the summary, matching the old hook)
+	if err := e.RegisterWorkflow(Definition{
+		Name: "default",
+		Steps: []StepSpec{
+			{Name: StepCorrectVocabulary},
+			{Name: StepRedactPII},
+			{Name: StepSummarize},
+			{Name: StepGenerateTags},
+			{Name: StepExtractActionItems},
+			{Name: StepDeliverActionItems, DependsOn: []string{StepSummarize, StepExtractActionItems}},
+			{Name: StepExtractEntities},
+			{Name: StepSpeakerAnalytics},
+			{Name: StepGenerateChapters},
+			{Name: StepScanWatchlists},
+			{Name: StepRAGIndex, DependsOn: indexDeps},
+			{Name: StepNotify, DependsOn: []string{StepSummarize, StepGenerateTags, StepExtractActionItems, StepDeliverActionItems, StepExtractEntities, StepSpeakerAnalytics, StepGenerateChapters, StepRedactPII, StepCorrectVocabulary, StepScanWatchlists, StepRAGIndex}},
+		},
+	}); err != nil {
+		return err
+	}
+
+	// bilingual: translate → summarize in both languages → index → notify
+	return e.RegisterWorkflow(Definition{
+		Name: "bilingual",
+		Steps: []StepSpec{
+			{Name: StepCorrectVocabulary},
+			{Name: StepRedactPII},
+			{Name: StepSummarize},
+			{Name: StepTranslate, DependsOn: indexDeps},
+			{Name: StepSummarizeTranslation, DependsOn: []string{StepTranslate}},
+			{Name: StepGenerateTags},
+			{Name: StepExtractActionItems},
+			{Name: StepDeliverActionItems, DependsOn: []string{StepSummarize, StepExtractActionItems}},
+			{Name: StepExtractEntities},
+			{Name: StepSpeakerAnalytics},
+			{Name: StepGenerateChapters},
+			{Name: StepScanWatchlists},
+			{Name: StepRAGIndex, DependsOn: indexDeps},
+			{Name: StepNotify, DependsOn: []string{StepSummarize, StepSummarizeTranslation, StepGenerateTags, StepExtractActionItems, StepDeliverActionItems, StepExtractEntities, StepSpeakerAnalytics, StepGenerateChapters, StepRedactPII, StepCorrectVocabulary, StepScanWatchlists, StepRAGIndex}},
+		},
+	})
+}
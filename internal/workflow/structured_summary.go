@@ -0,0 +1,70 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+)
+
+// Summary formats
+const (
+	// SummaryFormatText asks for a free-text summary
+	SummaryFormatText = "text"
+	// SummaryFormatStructured asks for a models.StructuredSummary as JSON
+	SummaryFormatStructured = "structured"
+)
+
+// structuredSummarySchema is the JSON Schema of models.StructuredSummary
+var structuredSummarySchema = llm.Schema{
+	Name:        "summary",
+	Description: "A structured summary of a transcribed recording",
+	Definition: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "title": {"type": "string", "description": "A short descriptive title for the recording"},
+    "tldr": {"type": "string", "description": "One or two sentences capturing the essence of the recording"},
+    "key_points": {"type": "array", "items": {"type": "string"}, "description": "The main points discussed"},
+    "decisions": {"type": "array", "items": {"type": "string"}, "description": "Decisions that were made, if any"},
+    "action_items": {
+      "type": "array",
+      "description": "Follow-up tasks that were agreed, if any",
+      "items": {
+        "type": "object",
+        "properties": {
+          "task": {"type": "string"},
+          "owner": {"type": "string", "description": "Who is responsible, empty if not mentioned"},
+          "due": {"type": "string", "description": "When it is due as mentioned, empty if not mentioned"}
+        },
+        "required": ["task", "owner", "due"]
+      }
+    }
+  },
+  "required": ["title", "tldr", "key_points", "decisions", "action_items"]
+}`),
+}
+
+// summarizeStructured generates a structured summary of transcript, following a summary
+// template's instructions when they are given
+func summarizeStructured(ctx context.Context, service LLMService, model, transcript, instructions string) (*models.StructuredSummary, error) {
+	prompt := "Summarize the following transcription. Only list decisions and action items that were actually stated; leave those lists empty otherwise.\n\n"
+	if instructions != "" {
+		prompt += "Instructions:\n" + instructions + "\n\nTranscript:\n"
+	}
+	prompt += truncateForLLM(transcript)
+	messages := []llm.ChatMessage{{Role: "user", Content: prompt}}
+
+	var summary models.StructuredSummary
+	if err := llm.CompleteJSON(ctx, service, model, messages, 0.3, structuredSummarySchema, &summary); err != nil {
+		return nil, fmt.Errorf("structured summary failed: %w", err)
+	}
+	summary.Title = strings.TrimSpace(summary.Title)
+	summary.TLDR = strings.TrimSpace(summary.TLDR)
+	if summary.TLDR == "" && len(summary.KeyPoints) == 0 {
+		return nil, fmt.Errorf("structured summary is empty")
+	}
+	return &summary, nil
+}
@@ -0,0 +1,78 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/projects"
+	"scriberr/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// ResolveSummaryTemplate returns the template a job is summarized with: the template id,
+// else the template chosen for the job, else its project's, else its owner's default. It
+// returns nil when none applies or the chosen template has been deleted, and the built-in
+// prompt is used.
+func ResolveSummaryTemplate(job *models.TranscriptionJob, id string) (*models.SummaryTemplate, error) {
+	if id == "" && job.SummaryTemplateID != nil {
+		id = *job.SummaryTemplateID
+	}
+	if id == "" {
+		settings, err := projects.ForJob(job)
+		if err != nil {
+			return nil, err
+		}
+		if settings.SummaryTemplateID != nil {
+			id = *settings.SummaryTemplateID
+		}
+	}
+	if id == "" {
+		user, err := jobOwner(job)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil && user.DefaultSummaryTemplateID != nil {
+			id = *user.DefaultSummaryTemplateID
+		}
+	}
+	if id == "" {
+		return nil, nil
+	}
+
+	var template models.SummaryTemplate
+	err := database.DB.Where("id = ?", id).First(&template).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Warn("Summary template no longer exists, using the built-in prompt", "component", "workflow", "job_id", job.ID, "template_id", id)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load summary template %s: %w", id, err)
+	}
+	return &template, nil
+}
+
+// jobOwner returns the user who owns a job. Jobs without an owner belong to the only user
+// when the instance has a single account, and to nobody otherwise.
+func jobOwner(job *models.TranscriptionJob) (*models.User, error) {
+	var users []models.User
+	query := database.DB.Select("id", "default_summary_template_id")
+	if job.UserID != nil {
+		query = query.Where("id = ?", *job.UserID)
+	}
+	if err := query.Limit(2).Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to load the owner of job %s: %w", job.ID, err)
+	}
+	if len(users) != 1 {
+		return nil, nil
+	}
+	return &users[0], nil
+}
+
+// templatePrompt asks for a summary following a template's instructions, in the same
+// layout the web UI uses when summarizing with a template
+func templatePrompt(template *models.SummaryTemplate, transcript string) string {
+	return fmt.Sprintf("Transcript:\n%s\n\nInstructions:\n%s", truncateForLLM(transcript), template.Prompt)
+}
@@ -0,0 +1,99 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/llm"
+	"scriberr/internal/rag"
+	"scriberr/internal/tagging"
+	"scriberr/pkg/logger"
+)
+
+// Bounds on the number of tags generated for a transcription
+const (
+	minGeneratedTags = 3
+	maxGeneratedTags = 7
+)
+
+// knownTagsInPrompt caps how many of the owner's existing tags are offered for reuse
+const knownTagsInPrompt = 100
+
+// tagsSchema is the JSON Schema of a tag generation reply
+var tagsSchema = llm.Schema{
+	Name:        "tags",
+	Description: "Topical tags for a transcribed recording",
+	Definition: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "tags": {"type": "array", "items": {"type": "string"}, "description": "Short topical tags, one to three words each"}
+  },
+  "required": ["tags"]
+}`),
+}
+
+// GenerateTagsStep asks the LLM for topical tags and links them to the transcription,
+// replacing tags it generated before but keeping the ones added by hand. It runs when Enabled,
+// unless the run's auto_tags parameter says otherwise.
+type GenerateTagsStep struct {
+	LLM     LLMService
+	Model   string
+	Enabled bool
+	// RAG, if set, has the new tags copied onto the transcription's vector store entries
+	RAG *rag.RAGService
+}
+
+// Run generates the tags and returns them comma-separated
+func (s *GenerateTagsStep) Run(ctx context.Context, rc *RunContext) (string, error) {
+	enabled := s.Enabled
+	if param := rc.Params["auto_tags"]; param != "" {
+		enabled = param == "true"
+	}
+	if !enabled {
+		return "", ErrSkipped
+	}
+	if strings.TrimSpace(rc.Transcript) == "" {
+		return "", fmt.Errorf("no transcript available")
+	}
+
+	known, err := tagging.Names(rc.Job.UserID, knownTagsInPrompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to load existing tags: %w", err)
+	}
+	prompt := fmt.Sprintf("Suggest %d to %d short topical tags for the following transcription, such as its subject, project or type of meeting. ", minGeneratedTags, maxGeneratedTags)
+	if len(known) > 0 {
+		prompt += "Reuse these existing tags where they fit: " + strings.Join(known, ", ") + ".\n\n"
+	} else {
+		prompt += "\n\n"
+	}
+	prompt += truncateForLLM(rc.Transcript)
+	messages := []llm.ChatMessage{{Role: "user", Content: prompt}}
+
+	var reply struct {
+		Tags []string `json:"tags"`
+	}
+	if err := llm.CompleteJSON(ctx, s.LLM, s.Model, messages, 0.3, tagsSchema, &reply); err != nil {
+		return "", fmt.Errorf("tag generation failed: %w", err)
+	}
+	generated := tagging.Normalize(reply.Tags)
+	if len(generated) == 0 {
+		return "", fmt.Errorf("no tags generated")
+	}
+	if len(generated) > maxGeneratedTags {
+		generated = generated[:maxGeneratedTags]
+	}
+
+	tags, err := tagging.SetAutoTags(rc.Job, generated)
+	if err != nil {
+		return "", err
+	}
+	// Indexing runs alongside this step, so entries it already wrote need the new tags
+	if s.RAG != nil {
+		if err := s.RAG.UpdateMetadata(rc.Job.ID); err != nil {
+			logger.WarnContext(ctx, "Failed to copy tags to the vector store", "error", err)
+		}
+	}
+	return strings.Join(tags, ", "), nil
+}
@@ -0,0 +1,83 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"scriberr/internal/export"
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+)
+
+// ErrEmptyRange is returned when no timed segment of a transcript falls in a time range
+var ErrEmptyRange = errors.New("no transcript segments in this time range")
+
+// TimeRange is a slice of a recording, in seconds. A To of 0 runs to the end.
+type TimeRange struct {
+	From float64
+	To   float64
+}
+
+// String formats the range as HH:MM:SS-HH:MM:SS, or HH:MM:SS-end
+func (r TimeRange) String() string {
+	if r.To <= 0 {
+		return export.Timestamp(r.From) + "-end"
+	}
+	return export.Timestamp(r.From) + "-" + export.Timestamp(r.To)
+}
+
+// contains reports whether a segment overlaps the range. Untimed segments never do.
+func (r TimeRange) contains(segment interfaces.TranscriptSegment) bool {
+	if segment.Start == 0 && segment.End == 0 {
+		return false
+	}
+	return segment.End > r.From && (r.To <= 0 || segment.Start < r.To)
+}
+
+// RangeTranscript renders the segments of a job's transcript that overlap a time range as
+// speakerTranscript does, along with how many there are
+func RangeTranscript(job *models.TranscriptionJob, r TimeRange) (string, int, error) {
+	var segments []interfaces.TranscriptSegment
+	for _, segment := range export.TranscriptSegments(job) {
+		if r.contains(segment) {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		return "", 0, ErrEmptyRange
+	}
+	text, _, _, err := renderSegments(job.ID, segments)
+	return text, len(segments), err
+}
+
+// SummarizeRange summarizes the part of a job's transcript in a time range, without saving
+// the summary. A template, if set, replaces the built-in instructions.
+func SummarizeRange(ctx context.Context, service LLMService, model string, temperature float64, job *models.TranscriptionJob, r TimeRange, template *models.SummaryTemplate) (string, int, error) {
+	excerpt, count, err := RangeTranscript(job, r)
+	if err != nil {
+		return "", 0, err
+	}
+	prompt := fmt.Sprintf("Please provide a concise summary of the following excerpt (%s) of a transcription. Each line starts with its start time in seconds:\n\n%s", r, truncateForLLM(excerpt))
+	if template != nil {
+		prompt = templatePrompt(template, excerpt)
+	}
+	summary, err := complete(ctx, service, model, prompt, temperature)
+	return summary, count, err
+}
+
+// AskRange answers a question from the part of a job's transcript in a time range only
+func AskRange(ctx context.Context, service LLMService, model string, job *models.TranscriptionJob, r TimeRange, question string) (string, int, error) {
+	excerpt, count, err := RangeTranscript(job, r)
+	if err != nil {
+		return "", 0, err
+	}
+	prompt := fmt.Sprintf(`Answer the question using only the following excerpt (%s) of a transcription. Each line starts with its start time in seconds. Cite the start times you rely on, and if the excerpt doesn't answer the question, say so.
+
+Excerpt:
+%s
+
+Question: %s`, r, truncateForLLM(excerpt), question)
+	answer, err := complete(ctx, service, model, prompt, 0.3)
+	return answer, count, err
+}
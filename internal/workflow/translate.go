@@ -0,0 +1,169 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/export"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/transcription/interfaces"
+
+	"gorm.io/gorm"
+)
+
+// translationBatchLength caps the transcript text sent to the LLM in one translation request.
+// Segments are never split, so a batch holding one long segment can exceed it.
+const translationBatchLength = 4000
+
+// numberedLine matches one "[n] text" line of a batch translation reply
+var numberedLine = regexp.MustCompile(`^\s*\[(\d+)\]\s?(.*)$`)
+
+// TranslateJob translates a job's transcript into language and saves it, replacing an earlier
+// translation into the same language. With index set the translation is also indexed into RAG
+// next to the original, so questions in either language find the recording; otherwise an
+// earlier indexing of that language is removed, since it no longer matches.
+func TranslateJob(ctx context.Context, service LLMService, model string, ragService *rag.RAGService, index bool, job *models.TranscriptionJob, language string) (*models.Translation, error) {
+	source := export.TranscriptSegments(job)
+	if len(source) == 0 {
+		return nil, fmt.Errorf("no transcript available")
+	}
+	segments, err := translateSegments(ctx, service, model, language, source)
+	if err != nil {
+		return nil, err
+	}
+
+	translation := models.Translation{
+		TranscriptionJobID: job.ID,
+		Language:           language,
+		SourceLanguage:     transcriptLanguage(job),
+		Model:              model,
+		Segments:           segments,
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("transcription_job_id = ? AND language = ?", job.ID, language).Delete(&models.Translation{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&translation).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save translation: %w", err)
+	}
+
+	if ragService == nil {
+		return &translation, nil
+	}
+	if !index {
+		if err := ragService.DeleteTranslation(job.ID, language); err != nil {
+			return nil, fmt.Errorf("translation saved, but failed to remove its earlier indexing: %w", err)
+		}
+		return &translation, nil
+	}
+	if err := ragService.StoreTranslation(ctx, &translation); err != nil {
+		return nil, fmt.Errorf("translation saved, but failed to index it: %w", err)
+	}
+	translation.Indexed = true
+	if err := database.DB.Model(&translation).Update("indexed", true).Error; err != nil {
+		return nil, fmt.Errorf("failed to save translation: %w", err)
+	}
+	return &translation, nil
+}
+
+// transcriptLanguage returns the language a job was transcribed in: the one the engine
+// detected, or else the one it was asked for
+func transcriptLanguage(job *models.TranscriptionJob) string {
+	if job.Transcript != nil {
+		var result interfaces.TranscriptResult
+		if err := json.Unmarshal([]byte(*job.Transcript), &result); err == nil && result.Language != "" {
+			return result.Language
+		}
+	}
+	if job.Parameters.Language != nil {
+		return *job.Parameters.Language
+	}
+	return ""
+}
+
+// translateSegments translates segments into language in batches of numbered lines, so each
+// translation stays aligned with the timing and speaker of its source segment. A segment the
+// LLM left out of its reply gets an empty translation.
+func translateSegments(ctx context.Context, service LLMService, model, language string, segments []interfaces.TranscriptSegment) ([]models.TranslatedSegment, error) {
+	translated := make([]models.TranslatedSegment, len(segments))
+	for i, segment := range segments {
+		translated[i] = models.TranslatedSegment{Start: segment.Start, End: segment.End, Speaker: segment.Speaker}
+	}
+
+	for start := 0; start < len(segments); {
+		end, length := start, 0
+		for end < len(segments) && (end == start || length+len(segments[end].Text) <= translationBatchLength) {
+			length += len(segments[end].Text)
+			end++
+		}
+
+		lines, err := translateBatch(ctx, service, model, language, segments[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for i, line := range lines {
+			translated[start+i].Text = line
+		}
+		start = end
+	}
+	return translated, nil
+}
+
+// translateBatch translates one batch of segments, returning a translation per segment
+func translateBatch(ctx context.Context, service LLMService, model, language string, batch []interfaces.TranscriptSegment) ([]string, error) {
+	if len(batch) == 1 {
+		prompt := fmt.Sprintf("Translate the following transcription into %s. Reply with the translation only.\n\n%s", language, batch[0].Text)
+		reply, err := complete(ctx, service, model, prompt, 0.2)
+		return []string{strings.TrimSpace(reply)}, err
+	}
+
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Translate each numbered line of the following transcription into %s. ", language)
+	prompt.WriteString("Reply with the translated lines only, one per line, keeping each line's [n] number.\n\n")
+	for i, segment := range batch {
+		fmt.Fprintf(&prompt, "[%d] %s\n", i+1, strings.Join(strings.Fields(segment.Text), " "))
+	}
+	reply, err := complete(ctx, service, model, prompt.String(), 0.2)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, found := parseNumberedLines(reply, len(batch))
+	if found == 0 {
+		return nil, fmt.Errorf("translation reply did not keep the line numbers")
+	}
+	return lines, nil
+}
+
+// parseNumberedLines splits a reply into n translations by their [n] numbers, reporting how
+// many were found. Unnumbered lines continue the line before them; numbers out of range are ignored.
+func parseNumberedLines(reply string, n int) ([]string, int) {
+	lines := make([]string, n)
+	found, current := 0, -1
+	for _, line := range strings.Split(reply, "\n") {
+		if match := numberedLine.FindStringSubmatch(line); match != nil {
+			number, _ := strconv.Atoi(match[1])
+			current = -1
+			if number >= 1 && number <= n {
+				current = number - 1
+				if lines[current] == "" {
+					found++
+				}
+				lines[current] = strings.TrimSpace(match[2])
+			}
+			continue
+		}
+		if text := strings.TrimSpace(line); text != "" && current >= 0 {
+			lines[current] = strings.TrimSpace(lines[current] + " " + text)
+		}
+	}
+	return lines, found
+}
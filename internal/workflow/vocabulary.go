@@ -0,0 +1,180 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/revisions"
+	"scriberr/internal/vocabulary"
+)
+
+// correctionsSchema is the JSON Schema of a vocabulary correction reply
+var correctionsSchema = llm.Schema{
+	Name:        "corrections",
+	Description: "Transcript lines corrected to the spelling of vocabulary terms",
+	Definition: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "corrections": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "line": {"type": "integer", "description": "The number of the corrected line"},
+          "text": {"type": "string", "description": "The whole line, corrected"}
+        },
+        "required": ["line", "text"]
+      }
+    }
+  },
+  "required": ["corrections"]
+}`),
+}
+
+// VocabularyStep corrects the transcript to the spelling of the job's vocabulary terms, with
+// CorrectVocabulary, before the other steps read it. The step is skipped for jobs without a
+// vocabulary and for plain-text transcripts; it only runs when Enabled, or when the run's
+// correct_vocabulary parameter is "true", and a parameter of "false" turns it off for one run.
+type VocabularyStep struct {
+	LLM     LLMService
+	Model   string
+	Enabled bool
+}
+
+// Run corrects the transcript, returning how many segments were changed
+func (s *VocabularyStep) Run(ctx context.Context, rc *RunContext) (string, error) {
+	enabled := s.Enabled
+	if param := rc.Params["correct_vocabulary"]; param != "" {
+		enabled = param == "true"
+	}
+	if !enabled {
+		return "", ErrSkipped
+	}
+
+	saved, hinted, err := CorrectVocabulary(ctx, s.LLM, s.Model, rc.Job)
+	if errors.Is(err, ErrNoVocabulary) || errors.Is(err, revisions.ErrNoSegments) {
+		return "", ErrSkipped
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(saved) > 0 {
+		if rc.Transcript, err = TranscriptText(rc.Job); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%d segments corrected, %d known mishearings replaced", len(saved), hinted), nil
+}
+
+// ErrNoVocabulary is returned by CorrectVocabulary for a job without vocabulary terms
+var ErrNoVocabulary = errors.New("transcription has no vocabulary")
+
+// CorrectVocabulary corrects a job's transcript to the spelling of its vocabulary terms: the
+// words a term is known to be misheard as are replaced by it, then the LLM, unless service is
+// nil, fixes the misspellings left. Each corrected segment is saved as a revision, which are
+// returned along with the number of mishearings replaced. Plain-text transcripts have no
+// segments to correct and give revisions.ErrNoSegments.
+func CorrectVocabulary(ctx context.Context, service LLMService, model string, job *models.TranscriptionJob) ([]models.TranscriptRevision, int, error) {
+	terms, err := vocabulary.ForJob(job)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load vocabulary: %w", err)
+	}
+	if len(terms) == 0 {
+		return nil, 0, ErrNoVocabulary
+	}
+	var stored struct {
+		Segments []struct {
+			Text string `json:"text"`
+		} `json:"segments"`
+	}
+	if job.Transcript == nil || json.Unmarshal([]byte(*job.Transcript), &stored) != nil || len(stored.Segments) == 0 {
+		return nil, 0, revisions.ErrNoSegments
+	}
+
+	texts := make([]string, len(stored.Segments))
+	hinted := 0
+	for i, segment := range stored.Segments {
+		var n int
+		texts[i], n = vocabulary.ApplyHints(segment.Text, terms)
+		hinted += n
+	}
+	if service != nil {
+		if err := correctSpelling(ctx, service, model, texts, terms); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var edits []revisions.Edit
+	for i, segment := range stored.Segments {
+		if text := strings.TrimSpace(texts[i]); texts[i] != segment.Text && text != "" {
+			edits = append(edits, revisions.Edit{Segment: i, Text: &text})
+		}
+	}
+	saved, err := revisions.Apply(job, edits, nil, models.RevisionSourceVocabulary)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to save corrections: %w", err)
+	}
+	return saved, hinted, nil
+}
+
+// correctSpelling asks the LLM to correct misspelled vocabulary terms in texts, a batch of
+// lines at a time, and replaces the lines it corrected. A correction is only taken if it
+// contains one of the terms and is about as long as the line, so the LLM can't rewrite lines
+// that have nothing to do with the vocabulary.
+func correctSpelling(ctx context.Context, service LLMService, model string, texts []string, terms []models.VocabularyTerm) error {
+	var glossary strings.Builder
+	for _, term := range terms {
+		glossary.WriteString("- " + term.Term)
+		if term.Kind != "" && term.Kind != models.VocabularyKindTerm {
+			glossary.WriteString(" (" + strings.ReplaceAll(term.Kind, "_", " ") + ")")
+		}
+		if len(term.SoundsLike) > 0 {
+			glossary.WriteString(", may be heard as: " + strings.Join(term.SoundsLike, "; "))
+		}
+		glossary.WriteByte('\n')
+	}
+
+	for start := 0; start < len(texts); {
+		end, length := start, 0
+		var batch strings.Builder
+		for end < len(texts) && (end == start || length+len(texts[end]) <= translationBatchLength) {
+			length += len(texts[end])
+			fmt.Fprintf(&batch, "%d: %s\n", end+1, strings.Join(strings.Fields(texts[end]), " "))
+			end++
+		}
+
+		prompt := "The following numbered lines were transcribed by speech recognition, which often misspells " +
+			"the words and names in this vocabulary:\n\n" + glossary.String() + "\n" +
+			"Return each line in which a vocabulary term was misheard or misspelled, with only those words " +
+			"corrected to the vocabulary's spelling. Leave everything else as it is, and leave out lines " +
+			"that need no correction.\n\n" + batch.String()
+		messages := []llm.ChatMessage{{Role: "user", Content: prompt}}
+		var reply struct {
+			Corrections []struct {
+				Line int    `json:"line"`
+				Text string `json:"text"`
+			} `json:"corrections"`
+		}
+		if err := llm.CompleteJSON(ctx, service, model, messages, 0.1, correctionsSchema, &reply); err != nil {
+			return fmt.Errorf("vocabulary correction failed: %w", err)
+		}
+		for _, correction := range reply.Corrections {
+			i := correction.Line - 1
+			text := strings.Join(strings.Fields(correction.Text), " ")
+			if i < start || i >= end || text == "" || !vocabulary.Mentions(text, terms) {
+				continue
+			}
+			if original := len(texts[i]); len(text) < original/2 || len(text) > original*3/2+20 {
+				continue
+			}
+			texts[i] = text
+		}
+		start = end
+	}
+	return nil
+}
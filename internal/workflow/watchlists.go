@@ -0,0 +1,207 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"scriberr/internal/database"
+	"scriberr/internal/events"
+	"scriberr/internal/export"
+	"scriberr/internal/models"
+	"scriberr/internal/notify"
+	"scriberr/internal/rag"
+	"scriberr/internal/watchlist"
+
+	"gorm.io/gorm"
+)
+
+// WatchlistStep scans the transcript for the terms of its owner's watchlists, saves the
+// matching segments and sends each list's matches to its webhook, or to Notifier when the
+// list has none. Lists asking for semantic matches need RAG to embed the transcript.
+type WatchlistStep struct {
+	RAG                  *rag.RAGService
+	Notifier             *notify.WebhookNotifier
+	AllowPrivateWebhooks bool // Let lists' webhooks be on private networks
+}
+
+// Run scans the transcript, returning the matches one per line, or skips when the owner has
+// no active watchlists
+func (s *WatchlistStep) Run(ctx context.Context, rc *RunContext) (string, error) {
+	var lists []models.Watchlist
+	query := database.DB.Where("paused = ?", false)
+	if rc.Job.UserID != nil {
+		query = query.Where("user_id = ?", *rc.Job.UserID)
+	} else {
+		query = query.Where("user_id IS NULL")
+	}
+	if err := query.Order("created_at").Find(&lists).Error; err != nil {
+		return "", fmt.Errorf("failed to load watchlists: %w", err)
+	}
+	if len(lists) == 0 {
+		return "", ErrSkipped
+	}
+
+	matches, err := ScanWatchlists(s.RAG, rc.Job, lists)
+	if err != nil {
+		return "", err
+	}
+	if err := s.alert(ctx, rc.Job, lists, matches); err != nil {
+		return "", err
+	}
+
+	names := map[string]string{}
+	for _, list := range lists {
+		names[list.ID] = list.Name
+	}
+	lines := make([]string, len(matches))
+	for i, match := range matches {
+		lines[i] = fmt.Sprintf("%s %s (%s)", export.Timestamp(match.Start), match.Term, names[match.WatchlistID])
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// alert records an event and sends a notification for each list with matches. Every webhook
+// is tried; the first failure is returned.
+func (s *WatchlistStep) alert(ctx context.Context, job *models.TranscriptionJob, lists []models.Watchlist, matches []models.WatchlistMatch) error {
+	var firstErr error
+	for _, list := range lists {
+		var found []models.WatchlistMatch
+		for _, match := range matches {
+			if match.WatchlistID == list.ID {
+				found = append(found, match)
+			}
+		}
+		if len(found) == 0 {
+			continue
+		}
+		events.Record(models.EventWatchlistMatched, job.ID, job.UserID, map[string]interface{}{"watchlist_id": list.ID, "count": len(found)})
+
+		notifier := s.Notifier
+		if list.WebhookURL != "" {
+			notifier = notify.NewUserWebhookNotifier(list.WebhookURL, s.AllowPrivateWebhooks)
+		}
+		if notifier == nil || !notifier.Enabled() {
+			continue
+		}
+		data, err := json.Marshal(found)
+		if err != nil {
+			return fmt.Errorf("failed to encode watchlist matches: %w", err)
+		}
+		event := notify.Event{
+			Type:            models.EventWatchlistMatched,
+			TranscriptionID: job.ID,
+			Data: map[string]string{
+				"watchlist_id": list.ID,
+				"watchlist":    list.Name,
+				"count":        strconv.Itoa(len(found)),
+				"matches":      string(data),
+			},
+		}
+		if job.Title != nil {
+			event.Title = *job.Title
+		}
+		if err := notifier.Send(ctx, event); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to notify watchlist %q: %w", list.Name, err)
+		}
+	}
+	return firstErr
+}
+
+// ScanWatchlists finds the terms of lists in a job's transcript, segment by segment, and
+// replaces the job's saved matches with the ones found. A term is matched at most once per
+// segment, exact matches preferred over fuzzy ones and those over semantic ones. Lists
+// asking for semantic matches are only matched exactly and fuzzily when embedder is nil.
+func ScanWatchlists(embedder *rag.RAGService, job *models.TranscriptionJob, lists []models.Watchlist) ([]models.WatchlistMatch, error) {
+	segments := export.TranscriptSegments(job)
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("no transcript available")
+	}
+
+	// The segments are embedded once, and only when a list needs them
+	var segmentVectors [][]float32
+	embedSegments := func() error {
+		if segmentVectors != nil {
+			return nil
+		}
+		texts := make([]string, len(segments))
+		for i, segment := range segments {
+			texts[i] = segment.Text
+		}
+		vectors, err := embedder.Embed(texts)
+		if err != nil {
+			return fmt.Errorf("failed to embed transcript: %w", err)
+		}
+		segmentVectors = vectors
+		return nil
+	}
+
+	var matches []models.WatchlistMatch
+	for _, list := range lists {
+		var termVectors [][]float32
+		if list.Semantic && embedder != nil && len(list.Terms) > 0 {
+			if err := embedSegments(); err != nil {
+				return nil, err
+			}
+			vectors, err := embedder.Embed(list.Terms)
+			if err != nil {
+				return nil, fmt.Errorf("failed to embed watchlist terms: %w", err)
+			}
+			termVectors = vectors
+		}
+		threshold := list.SimilarityThreshold
+		if threshold <= 0 {
+			threshold = watchlist.DefaultSimilarity
+		}
+
+		for i, segment := range segments {
+			found := map[string]bool{}
+			add := func(term, matchType string, score float64) {
+				found[term] = true
+				match := models.WatchlistMatch{
+					WatchlistID:     list.ID,
+					TranscriptionID: job.ID,
+					UserID:          job.UserID,
+					Term:            term,
+					MatchType:       matchType,
+					Score:           score,
+					Text:            strings.TrimSpace(segment.Text),
+					Start:           segment.Start,
+					End:             segment.End,
+				}
+				if segment.Speaker != nil {
+					match.Speaker = *segment.Speaker
+				}
+				matches = append(matches, match)
+			}
+			for _, hit := range watchlist.Find(list.Terms, segment.Text, list.Fuzzy) {
+				add(hit.Term, hit.Type, hit.Score)
+			}
+			for t, vector := range termVectors {
+				term := list.Terms[t]
+				if found[term] || i >= len(segmentVectors) {
+					continue
+				}
+				if score := watchlist.Similarity(vector, segmentVectors[i]); score >= threshold {
+					add(term, watchlist.MatchSemantic, score)
+				}
+			}
+		}
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("transcription_id = ?", job.ID).Delete(&models.WatchlistMatch{}).Error; err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return nil
+		}
+		return tx.Create(&matches).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save watchlist matches: %w", err)
+	}
+	return matches, nil
+}
@@ -0,0 +1,251 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Field names every log line may carry to say what it's about
+const (
+	KeyJobID     = "job_id"
+	KeyRequestID = "request_id"
+	KeyComponent = "component"
+)
+
+type contextKey int
+
+const fieldsKey contextKey = 0
+
+// fields are carried in a context so lines logged with it say which job and request they
+// belong to and which part of the server wrote them
+type fields struct {
+	jobID     string
+	requestID string
+	component string
+}
+
+func fieldsFrom(ctx context.Context) fields {
+	if ctx == nil {
+		return fields{}
+	}
+	f, _ := ctx.Value(fieldsKey).(fields)
+	return f
+}
+
+// WithJob returns ctx with lines logged with it tagged with a transcription job
+func WithJob(ctx context.Context, jobID string) context.Context {
+	f := fieldsFrom(ctx)
+	f.jobID = jobID
+	return context.WithValue(ctx, fieldsKey, f)
+}
+
+// WithRequestID returns ctx with lines logged with it tagged with an API request
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	f := fieldsFrom(ctx)
+	f.requestID = requestID
+	return context.WithValue(ctx, fieldsKey, f)
+}
+
+// WithComponent returns ctx with lines logged with it tagged with the part of the server
+// writing them, such as queue or workflow
+func WithComponent(ctx context.Context, component string) context.Context {
+	f := fieldsFrom(ctx)
+	f.component = component
+	return context.WithValue(ctx, fieldsKey, f)
+}
+
+// JobID returns the job ctx was tagged with by WithJob, if any
+func JobID(ctx context.Context) string {
+	return fieldsFrom(ctx).jobID
+}
+
+// RequestID returns the request ctx was tagged with by WithRequestID, if any
+func RequestID(ctx context.Context) string {
+	return fieldsFrom(ctx).requestID
+}
+
+// Context-aware variants of the convenience methods, tagging the line with the job,
+// request and component in ctx
+
+func DebugContext(ctx context.Context, msg string, args ...any) {
+	if currentLevel <= LevelDebug {
+		Get().DebugContext(ctx, msg, args...)
+	}
+}
+
+func InfoContext(ctx context.Context, msg string, args ...any) {
+	if currentLevel <= LevelInfo {
+		Get().InfoContext(ctx, msg, args...)
+	}
+}
+
+func WarnContext(ctx context.Context, msg string, args ...any) {
+	if currentLevel <= LevelWarn {
+		Get().WarnContext(ctx, msg, args...)
+	}
+}
+
+func ErrorContext(ctx context.Context, msg string, args ...any) {
+	if currentLevel <= LevelError {
+		Get().ErrorContext(ctx, msg, args...)
+	}
+}
+
+// JobRecord is a log line about a transcription job, as passed to the job sink
+type JobRecord struct {
+	Time      time.Time
+	Level     string
+	Message   string
+	JobID     string
+	RequestID string
+	Component string
+	Attrs     map[string]any
+}
+
+var (
+	sinkMu  sync.RWMutex
+	jobSink func(JobRecord)
+)
+
+// SetJobSink has every line logged about a job passed to sink as well, so a job's processing
+// log can be kept. sink is called on the logging goroutine and must not block or log about
+// a job itself. nil removes it.
+func SetJobSink(sink func(JobRecord)) {
+	sinkMu.Lock()
+	jobSink = sink
+	sinkMu.Unlock()
+}
+
+// handler adds the fields in the context to each line, moves the "[component]" prefix of
+// lines written through the standard log package into the component field, and passes lines
+// about a job to the job sink
+type handler struct {
+	next slog.Handler
+	// attrs were added with With, outside any group
+	attrs   []slog.Attr
+	grouped bool
+}
+
+func newHandler(next slog.Handler) *handler {
+	return &handler{next: next}
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := &handler{next: h.next.WithAttrs(attrs), attrs: h.attrs, grouped: h.grouped}
+	if !h.grouped {
+		next.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	}
+	return next
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{next: h.next.WithGroup(name), attrs: h.attrs, grouped: true}
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	values := map[string]string{}
+	for _, a := range h.attrs {
+		values[a.Key] = a.Value.String()
+	}
+	if !h.grouped {
+		r.Attrs(func(a slog.Attr) bool {
+			values[a.Key] = a.Value.String()
+			return true
+		})
+	}
+
+	// Only the fields the line doesn't already have are added
+	message := r.Message
+	var added []slog.Attr
+	add := func(key, value string) {
+		if _, ok := values[key]; !ok && value != "" {
+			values[key] = value
+			added = append(added, slog.String(key, value))
+		}
+	}
+	f := fieldsFrom(ctx)
+	add(KeyJobID, f.jobID)
+	add(KeyRequestID, f.requestID)
+	add(KeyComponent, f.component)
+	if component, rest, ok := splitComponent(message); ok {
+		add(KeyComponent, component)
+		message = rest
+	}
+	if message != r.Message || len(added) > 0 {
+		record := slog.NewRecord(r.Time, r.Level, message, r.PC)
+		r.Attrs(func(a slog.Attr) bool {
+			record.AddAttrs(a)
+			return true
+		})
+		record.AddAttrs(added...)
+		r = record
+	}
+
+	if jobID := values[KeyJobID]; jobID != "" {
+		sinkMu.RLock()
+		sink := jobSink
+		sinkMu.RUnlock()
+		if sink != nil {
+			sink(h.jobRecord(r, values))
+		}
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// jobRecord converts a line about a job for the job sink
+func (h *handler) jobRecord(r slog.Record, values map[string]string) JobRecord {
+	record := JobRecord{
+		Time:      r.Time,
+		Level:     r.Level.String(),
+		Message:   r.Message,
+		JobID:     values[KeyJobID],
+		RequestID: values[KeyRequestID],
+		Component: values[KeyComponent],
+		Attrs:     map[string]any{},
+	}
+	collect := func(a slog.Attr) bool {
+		switch a.Key {
+		case KeyJobID, KeyRequestID, KeyComponent:
+		default:
+			record.Attrs[a.Key] = plain(a.Value)
+		}
+		return true
+	}
+	for _, a := range h.attrs {
+		collect(a)
+	}
+	if !h.grouped {
+		r.Attrs(collect)
+	}
+	return record
+}
+
+// plain converts a value to one that encodes to JSON the way it reads in the log
+func plain(v slog.Value) any {
+	v = v.Resolve()
+	switch v.Kind() {
+	case slog.KindString, slog.KindInt64, slog.KindUint64, slog.KindFloat64, slog.KindBool:
+		return v.Any()
+	default:
+		return v.String()
+	}
+}
+
+// splitComponent splits the "[component] " prefix off a message written with log.Printf
+func splitComponent(message string) (string, string, bool) {
+	if !strings.HasPrefix(message, "[") {
+		return "", message, false
+	}
+	end := strings.Index(message, "] ")
+	if end < 2 || strings.ContainsAny(message[1:end], " []") {
+		return "", message, false
+	}
+	return message[1:end], message[end+2:], true
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSplitComponent(t *testing.T) {
+	cases := []struct {
+		message, component, rest string
+	}{
+		{"[workflow] Step failed", "workflow", "Step failed"},
+		{"Step failed", "", "Step failed"},
+		{"[a b] Step failed", "", "[a b] Step failed"},
+		{"[] Step failed", "", "[] Step failed"},
+		{"[workflow]Step failed", "", "[workflow]Step failed"},
+	}
+	for _, c := range cases {
+		component, rest, _ := splitComponent(c.message)
+		if component != c.component || rest != c.rest {
+			t.Errorf("splitComponent(%q) = %q, %q; want %q, %q", c.message, component, rest, c.component, c.rest)
+		}
+	}
+}
+
+func TestHandlerAddsContextFields(t *testing.T) {
+	var out bytes.Buffer
+	var records []JobRecord
+	SetJobSink(func(record JobRecord) { records = append(records, record) })
+	defer SetJobSink(nil)
+	log := slog.New(newHandler(slog.NewTextHandler(&out, nil)))
+
+	ctx := WithComponent(WithRequestID(WithJob(context.Background(), "job-1"), "req-1"), "queue")
+	log.With("job_id", "job-1").InfoContext(ctx, "[workflow] Claimed", "worker_id", 2)
+	log.InfoContext(context.Background(), "[rag] Indexed")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", out.String())
+	}
+	if strings.Count(lines[0], "job_id=") != 1 || !strings.Contains(lines[0], "request_id=req-1") ||
+		!strings.Contains(lines[0], "component=queue") || !strings.Contains(lines[0], `msg=Claimed`) {
+		t.Errorf("unexpected line %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "component=rag") || strings.Contains(lines[1], "job_id") {
+		t.Errorf("unexpected line %q", lines[1])
+	}
+
+	// Only the line about a job reaches the sink
+	if len(records) != 1 {
+		t.Fatalf("expected 1 job record, got %d", len(records))
+	}
+	record := records[0]
+	if record.JobID != "job-1" || record.RequestID != "req-1" || record.Component != "queue" || record.Message != "Claimed" ||
+		record.Level != "INFO" || record.Attrs["worker_id"] != int64(2) || len(record.Attrs) != 1 {
+		t.Errorf("unexpected job record %+v", record)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -92,9 +93,17 @@ func Init(level string) {
 		},
 	}
 
-	// Use text handler for clean, readable output
-	handler := slog.NewTextHandler(os.Stdout, opts)
-	defaultLogger = &Logger{slog.New(handler)}
+	// Use text handler for clean, readable output, or JSON for log collectors
+	var output slog.Handler
+	if strings.ToLower(os.Getenv("LOG_FORMAT")) == "json" {
+		output = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slogLevel})
+	} else {
+		output = slog.NewTextHandler(os.Stdout, opts)
+	}
+	defaultLogger = &Logger{slog.New(newHandler(output))}
+
+	// Lines written with the standard log package go through the same handler, at INFO
+	slog.SetDefault(defaultLogger.Logger)
 }
 
 // Get returns the default logger instance
@@ -243,7 +252,11 @@ func GinLogger() gin.HandlerFunc {
 		// Calculate request duration
 		duration := time.Since(start)
 
-		// Build path with query string
+		// Build path with query string, keeping tokens passed in it out of the log
+		if values, err := url.ParseQuery(raw); err == nil && values.Has("token") {
+			values.Set("token", "REDACTED")
+			raw = values.Encode()
+		}
 		if raw != "" {
 			path = path + "?" + raw
 		}
@@ -265,7 +278,7 @@ func GinLogger() gin.HandlerFunc {
 		
 		if currentLevel <= LevelDebug {
 			// Detailed logging for DEBUG
-			Debug("API request",
+			DebugContext(c.Request.Context(), "API request",
 				"method", c.Request.Method,
 				"path", path,
 				"status", status,
@@ -18,9 +18,13 @@ func AuthMiddleware(authService *auth.AuthService) gin.HandlerFunc {
 		// Check for API key first
 		apiKey := c.GetHeader("X-API-Key")
 		if apiKey != "" {
-			if validateAPIKey(apiKey) {
+			if key, ok := validateAPIKey(apiKey); ok {
 				c.Set("auth_type", "api_key")
 				c.Set("api_key", apiKey)
+				setAPIKeyOwner(c, key)
+				if !allowScopes(c, key) || !setRole(c, key.UserID) || !allowRole(c) {
+					return
+				}
 				c.Next()
 				return
 			}
@@ -53,26 +57,194 @@ func AuthMiddleware(authService *auth.AuthService) gin.HandlerFunc {
 		c.Set("auth_type", "jwt")
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
+		if !setRole(c, &claims.UserID) || !allowRole(c) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// QueryTokenAuthMiddleware authenticates like AuthMiddleware, but also accepts the JWT or API
+// key in the token query parameter, since browsers can't set headers on WebSocket upgrade requests
+func QueryTokenAuthMiddleware(authService *auth.AuthService) gin.HandlerFunc {
+	authenticate := AuthMiddleware(authService)
+	return func(c *gin.Context) {
+		token := c.Query("token")
+		if token != "" && c.GetHeader("X-API-Key") == "" && c.GetHeader("Authorization") == "" {
+			if _, err := authService.ValidateToken(token); err == nil {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			} else {
+				c.Request.Header.Set("X-API-Key", token)
+			}
+		}
+		authenticate(c)
+	}
+}
+
+// BearerAPIKeyMiddleware moves an API key sent as a bearer token to the X-API-Key header,
+// since OpenAI client libraries send their key as "Authorization: Bearer <key>". Bearer tokens
+// that are JWTs are left where they are. It only rewrites the request, so it runs before the
+// rate limits and authentication that read the headers.
+func BearerAPIKeyMiddleware(authService *auth.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if len(parts) == 2 && parts[0] == "Bearer" && c.GetHeader("X-API-Key") == "" {
+			if _, err := authService.ValidateToken(parts[1]); err != nil {
+				c.Request.Header.Set("X-API-Key", parts[1])
+				c.Request.Header.Del("Authorization")
+			}
+		}
 		c.Next()
 	}
 }
 
 // validateAPIKey validates an API key against the database and updates last used timestamp
-func validateAPIKey(key string) bool {
+func validateAPIKey(key string) (*models.APIKey, bool) {
 	var apiKey models.APIKey
-	result := database.DB.Where("key = ? AND is_active = ?", key, true).First(&apiKey)
+	now := time.Now()
+	result := database.DB.Where("key = ? AND is_active = ? AND (expires_at IS NULL OR expires_at > ?)", key, true, now).First(&apiKey)
 	if result.Error != nil {
-		return false
+		return nil, false
 	}
 
 	// Update last used timestamp
-	now := time.Now()
 	apiKey.LastUsed = &now
 	database.DB.Save(&apiKey)
 
+	return &apiKey, true
+}
+
+// setAPIKeyOwner makes requests authenticated with a user's API key act as that user
+func setAPIKeyOwner(c *gin.Context, key *models.APIKey) {
+	c.Set("api_key_id", key.ID)
+	if key.UserID != nil {
+		c.Set("user_id", *key.UserID)
+	}
+}
+
+// scopeRoutes are the routes each API key scope allows besides what its methods allow
+var scopeRoutes = map[string]map[string]bool{
+	// MCP tools check the scopes themselves: read keys may read and search, rag_chat keys search and ask
+	models.APIKeyScopeRead: {
+		"/mcp": true,
+	},
+	models.APIKeyScopeUpload: {
+		"/api/v1/transcription/upload":              true,
+		"/api/v1/transcription/upload-video":        true,
+		"/api/v1/transcription/upload-multitrack":   true,
+		"/api/v1/transcription/upload-multichannel": true,
+		"/api/v1/transcription/upload-url":          true,
+		"/api/v1/transcription/upload-url/complete": true,
+		"/api/v1/transcription/uploads":             true,
+		"/api/v1/transcription/uploads/:id":         true,
+		"/api/v1/transcription/from-url":            true,
+		"/api/v1/transcription/from-url/:id":        true,
+		"/api/v1/transcription/from-media":          true,
+		"/api/v1/transcription/youtube":             true,
+		"/api/v1/transcription/submit":              true,
+		"/api/v1/transcription/import-transcript":   true,
+		"/api/v1/transcription/quick":               true,
+		"/api/v1/transcription/quick/:id":           true,
+		"/api/v1/transcription/:id/status":          true,
+		"/v1/audio/transcriptions":                  true,
+		"/v1/models":                                true,
+	},
+	models.APIKeyScopeRAGChat: {
+		"/api/v1/rag/chat":                       true,
+		"/api/v1/rag/search":                     true,
+		"/api/v1/rag/collections":                true,
+		"/api/v1/rag/answers/:answer_id/sources": true,
+		"/v1/chat/completions":                   true,
+		"/v1/models":                             true,
+		"/mcp":                                   true,
+	},
+}
+
+// allowScopes keeps a scoped API key to what its scopes allow. Keys without scopes can do
+// everything their user can; AdminOnlyMiddleware keeps scoped keys off admin routes. The
+// scopes of a scoped key are set as api_key_scopes, for handlers that allow less than their
+// route.
+func allowScopes(c *gin.Context, key *models.APIKey) bool {
+	if len(key.Scopes) == 0 {
+		return true
+	}
+	c.Set("api_key_scopes", key.Scopes)
+	method := c.Request.Method
+	path := c.FullPath()
+	for _, scope := range key.Scopes {
+		if scope == models.APIKeyScopeRead && (method == http.MethodGet || method == http.MethodHead) {
+			return true
+		}
+		if scopeRoutes[scope][path] {
+			return true
+		}
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": "This API key's scopes don't allow this request", "scopes": key.Scopes})
+	c.Abort()
+	return false
+}
+
+// viewerWrites are the routes viewers may POST to: asking questions changes nothing
+var viewerWrites = map[string]bool{
+	"/api/v1/rag/chat":                    true,
+	"/api/v1/rag/search":                  true,
+	"/api/v1/transcription/:id/range/ask": true,
+	"/v1/chat/completions":                true,
+	"/mcp":                                true,
+}
+
+// setRole looks up the caller's role. API keys without an owner act for the instance and
+// have the admin role. It aborts with 401 if the user no longer exists.
+func setRole(c *gin.Context, userID *uint) bool {
+	if userID == nil {
+		c.Set("role", models.RoleAdmin)
+		return true
+	}
+	var user models.User
+	result := database.DB.Select("id", "role").Where("id = ?", *userID).Limit(1).Find(&user)
+	if result.Error != nil || result.RowsAffected == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User no longer exists"})
+		c.Abort()
+		return false
+	}
+	c.Set("role", user.Role)
 	return true
 }
 
+// allowRole keeps viewers to reading: they may only use safe methods, chat with
+// transcriptions and ask questions of them
+func allowRole(c *gin.Context) bool {
+	if c.GetString("role") != models.RoleViewer {
+		return true
+	}
+	method := c.Request.Method
+	path := c.FullPath()
+	if method == http.MethodGet || method == http.MethodHead || viewerWrites[path] || strings.HasPrefix(path, "/api/v1/chat/") {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": "Viewers have read-only access"})
+	c.Abort()
+	return false
+}
+
+// AdminOnlyMiddleware only allows admins; it must run after authentication. Scoped API keys
+// are for what their scopes name, so they are turned away even when their user is an admin.
+func AdminOnlyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != models.RoleAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			c.Abort()
+			return
+		}
+		if _, scoped := c.Get("api_key_scopes"); scoped {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin routes can't be used with a scoped API key"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 // APIKeyOnlyMiddleware only allows API key authentication
 func APIKeyOnlyMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -83,7 +255,8 @@ func APIKeyOnlyMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		if !validateAPIKey(apiKey) {
+		key, ok := validateAPIKey(apiKey)
+		if !ok {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
 			c.Abort()
 			return
@@ -91,6 +264,10 @@ func APIKeyOnlyMiddleware() gin.HandlerFunc {
 
 		c.Set("auth_type", "api_key")
 		c.Set("api_key", apiKey)
+		setAPIKeyOwner(c, key)
+		if !allowScopes(c, key) || !setRole(c, key.UserID) {
+			return
+		}
 		c.Next()
 	}
 }
@@ -123,6 +300,9 @@ func JWTOnlyMiddleware(authService *auth.AuthService) gin.HandlerFunc {
 		c.Set("auth_type", "jwt")
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
+		if !setRole(c, &claims.UserID) {
+			return
+		}
 		c.Next()
 	}
 }
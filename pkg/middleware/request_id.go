@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries a request's ID, both ways
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware gives each request an ID, taken from the caller's X-Request-ID header
+// if it sends a usable one, and returns it in the response's. Lines logged with the request's
+// context carry it, so they can be found by the ID a client reports.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+		c.Set("request_id", id)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// validRequestID accepts short printable IDs, so a caller's ID can't break up log lines
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
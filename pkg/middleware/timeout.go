@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Endpoint classes for request timeouts
+const (
+	TimeoutRead   = "read"   // lookups and searches that should answer quickly
+	TimeoutLong   = "long"   // LLM calls, evaluations and other slow synchronous work
+	TimeoutStream = "stream" // streamed responses, which run for as long as generation does
+)
+
+// TimeoutPolicy maps endpoint classes to request timeouts. A class that is missing or
+// set to zero has no timeout.
+type TimeoutPolicy map[string]time.Duration
+
+// Timeout returns middleware that bounds a request by its class's timeout. The deadline
+// is set on c.Request's context, so handlers see it through c.Request.Context(). A request
+// whose deadline passed before anything was written is answered with 504.
+func (p TimeoutPolicy) Timeout(class string) gin.HandlerFunc {
+	timeout := p[class]
+	return func(c *gin.Context) {
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{"error": "Request timed out"})
+		}
+	}
+}
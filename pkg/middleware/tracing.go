@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"scriberr/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TracingMiddleware records a server span for each request, continuing the caller's trace
+// when it sends a traceparent header. The span is the parent of the spans handlers start
+// from c.Request's context.
+func TracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !tracing.Enabled() {
+			c.Next()
+			return
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		ctx, span := tracing.StartKind(tracing.Extract(c.Request.Context(), c.Request.Header),
+			tracing.KindServer, c.Request.Method+" "+route,
+			"http.request.method", c.Request.Method,
+			"http.route", route,
+		)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttribute("http.response.status_code", status)
+		if len(c.Errors) > 0 {
+			span.RecordError(c.Errors.Last())
+		} else if status >= http.StatusInternalServerError {
+			span.RecordError(errors.New(http.StatusText(status)))
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/dashboard"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type AdminStatsTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	router *gin.Engine
+}
+
+func (suite *AdminStatsTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "admin_stats_test.db")
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, queue.NewTaskQueue(1, &MockJobProcessor{}), nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *AdminStatsTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// job creates a transcription created at createdAt. Completed jobs get a transcript of
+// audioSeconds and a run taking processing.
+func (suite *AdminStatsTestSuite) job(createdAt time.Time, status models.JobStatus, family string, audioSeconds float64, processing time.Duration, tags ...string) *models.TranscriptionJob {
+	t := suite.T()
+	job := suite.helper.CreateTestTranscriptionJob(t, "Stats Job")
+	job.Status = status
+	job.Parameters.ModelFamily = family
+	job.Tags = tags
+	if status == models.StatusCompleted {
+		transcript, _ := json.Marshal(map[string]interface{}{
+			"text":     "hello there",
+			"segments": []map[string]interface{}{{"start": 0.0, "end": audioSeconds, "text": "hello there"}},
+		})
+		job.Transcript = stringPtr(string(transcript))
+	}
+	require.NoError(t, suite.helper.DB.Save(job).Error)
+	require.NoError(t, suite.helper.DB.Model(job).UpdateColumn("created_at", createdAt).Error)
+
+	if status == models.StatusCompleted {
+		// An earlier, slower attempt that failed is left out of the processing time
+		ms := processing.Milliseconds()
+		failedMs := 10 * ms
+		require.NoError(t, suite.helper.DB.Create(&models.TranscriptionJobExecution{
+			TranscriptionJobID: job.ID, StartedAt: createdAt, ProcessingDuration: &failedMs, Status: models.StatusFailed, CreatedAt: createdAt,
+		}).Error)
+		require.NoError(t, suite.helper.DB.Create(&models.TranscriptionJobExecution{
+			TranscriptionJobID: job.ID, StartedAt: createdAt, ProcessingDuration: &ms, Status: models.StatusCompleted, CreatedAt: createdAt.Add(time.Minute),
+		}).Error)
+	}
+	return job
+}
+
+// stats reads the dashboard statistics with the given query string
+func (suite *AdminStatsTestSuite) stats(query string) (*httptest.ResponseRecorder, dashboard.Report) {
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/admin/stats"+query, nil)
+	req.Header.Set("Authorization", "Bearer "+suite.helper.TestToken)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	var report dashboard.Report
+	if w.Code == http.StatusOK {
+		require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &report))
+	}
+	return w, report
+}
+
+func (suite *AdminStatsTestSuite) TestStats() {
+	t := suite.T()
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	suite.job(since.Add(2*time.Hour), models.StatusCompleted, "whisper", 5400, 3*time.Minute, "Sales", "weekly")
+	suite.job(since.Add(5*time.Hour), models.StatusCompleted, "whisper", 1800, time.Minute, "sales")
+	suite.job(since.Add(26*time.Hour), models.StatusFailed, "nvidia_parakeet", 0, 0, "weekly")
+	suite.job(since.Add(50*time.Hour), models.StatusPending, "whisper", 0, 0)
+	// Outside the window
+	suite.job(since.Add(-time.Hour), models.StatusCompleted, "whisper", 3600, time.Minute)
+	require.NoError(t, os.WriteFile(filepath.Join(suite.helper.Config.UploadDir, "audio.wav"), make([]byte, 1000), 0644))
+
+	w, report := suite.stats("?since=2026-03-01T00:00:00Z&until=2026-03-04T00:00:00Z&top_tags=1")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	assert.Equal(t, dashboard.Totals{Jobs: 4, Completed: 2, Failed: 1, AudioHours: 2, AvgProcessingSeconds: 120}, report.Totals)
+	assert.Equal(t, int64(24*3600), report.BucketSeconds, "a 3 day window should be bucketed by day")
+	require.Len(t, report.Series, 3)
+	assert.Equal(t, dashboard.Bucket{Start: since, Jobs: 2, Completed: 2, AudioHours: 2, AvgProcessingSeconds: 120}, report.Series[0])
+	assert.Equal(t, 1, report.Series[1].Failed)
+	assert.Equal(t, 1, report.Series[2].Jobs)
+
+	require.Len(t, report.Models, 2)
+	assert.Equal(t, "whisper", report.Models[0].ModelFamily)
+	assert.Equal(t, "base", report.Models[0].Model)
+	assert.Equal(t, 3, report.Models[0].Jobs)
+	assert.Equal(t, 1, report.Models[1].Failed)
+
+	assert.Equal(t, []dashboard.TagCount{{Tag: "Sales", Jobs: 2}}, report.TopTags)
+
+	require.NotNil(t, report.Storage)
+	assert.GreaterOrEqual(t, report.Storage.UploadBytes, int64(1000))
+	assert.Positive(t, report.Storage.DatabaseBytes)
+
+	// Hourly buckets
+	_, report = suite.stats("?since=2026-03-01T00:00:00Z&until=2026-03-02T00:00:00Z&bucket=1h")
+	require.Len(t, report.Series, 24)
+	assert.Equal(t, 1, report.Series[2].Jobs)
+	assert.Equal(t, 1, report.Series[5].Jobs)
+}
+
+func (suite *AdminStatsTestSuite) TestInvalidQueries() {
+	for _, query := range []string{
+		"?bucket=soon",
+		"?bucket=1m",
+		"?since=2026-03-02T00:00:00Z&until=2026-03-01T00:00:00Z",
+		"?top_tags=0",
+		"?until=yesterday",
+	} {
+		w, _ := suite.stats(query)
+		assert.Equal(suite.T(), http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func (suite *AdminStatsTestSuite) TestRequiresAuthentication() {
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/admin/stats", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	assert.Equal(suite.T(), http.StatusUnauthorized, w.Code)
+}
+
+func TestAdminStatsTestSuite(t *testing.T) {
+	suite.Run(t, new(AdminStatsTestSuite))
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/embeddings"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/vectordb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type AnswerSourcesTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	router *gin.Engine
+}
+
+func (suite *AnswerSourcesTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "answer_sources_test.db")
+	ragService := rag.NewRAGService(vectordb.NewMemoryStore(), embeddings.NewFakeEmbeddingService(), &replyLLM{reply: "The budget was approved."})
+	for i := 0; i < 8; i++ {
+		job := suite.helper.CreateTestTranscriptionJob(suite.T(), fmt.Sprintf("Budget meeting %d", i))
+		require.NoError(suite.T(), ragService.StoreSummary(context.Background(), job.ID, "", fmt.Sprintf("Meeting %d: the budget was discussed and the budget was approved.", i)))
+	}
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, nil, nil, nil, ragService)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *AnswerSourcesTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *AnswerSourcesTestSuite) TestPagingThroughAnswerSources() {
+	t := suite.T()
+	w := suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/rag/chat", map[string]string{"query": "Was the budget approved?"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var chat struct {
+		AnswerID    string   `json:"answer_id"`
+		SourceCount int      `json:"source_count"`
+		Sources     []string `json:"sources"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &chat))
+	require.NotEmpty(t, chat.AnswerID)
+	assert.Equal(t, 8, chat.SourceCount, "every relevant excerpt is kept, not only the ones in the prompt")
+	assert.Len(t, chat.Sources, 5)
+
+	type page struct {
+		Sources []struct {
+			models.RAGAnswerSource
+			Title *string `json:"title"`
+		} `json:"sources"`
+		Pagination struct {
+			Total int `json:"total"`
+			Pages int `json:"pages"`
+		} `json:"pagination"`
+	}
+	var first, second page
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/rag/answers/"+chat.AnswerID+"/sources?limit=6", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &first))
+	assert.Equal(t, 8, first.Pagination.Total)
+	assert.Equal(t, 2, first.Pagination.Pages)
+	require.Len(t, first.Sources, 6)
+	assert.Equal(t, 1, first.Sources[0].Rank)
+	assert.True(t, first.Sources[4].InContext)
+	assert.False(t, first.Sources[5].InContext)
+	assert.Contains(t, first.Sources[0].Content, "budget")
+	assert.NotEmpty(t, first.Sources[0].ChunkID)
+	require.NotNil(t, first.Sources[0].Title)
+	assert.Contains(t, *first.Sources[0].Title, "Budget meeting")
+
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/rag/answers/"+chat.AnswerID+"/sources?limit=6&page=2", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &second))
+	require.Len(t, second.Sources, 2)
+	assert.Equal(t, 7, second.Sources[0].Rank)
+
+	// Deleting a transcription removes its excerpts
+	w = suite.helper.Request(suite.T(), suite.router, "DELETE", "/api/v1/transcription/"+first.Sources[0].TranscriptionID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/rag/answers/"+chat.AnswerID+"/sources", nil)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &first))
+	assert.Equal(t, 7, first.Pagination.Total)
+
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/rag/answers/missing/sources", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func (suite *AnswerSourcesTestSuite) TestExtractiveAnswer() {
+	t := suite.T()
+	selector := &replyLLM{reply: `{"sentences": ["1.3", "9.9", "1.3"]}`}
+	ragService := rag.NewRAGService(vectordb.NewMemoryStore(), embeddings.NewFakeEmbeddingService(), selector)
+	job := suite.helper.CreateTestTranscriptionJob(t, "Board meeting")
+	require.NoError(t, ragService.StoreSummary(context.Background(), job.ID, "The board met.", "Anna opened the meeting. The budget was approved by the board. Lunch was served."))
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, nil, nil, nil, ragService)
+	router := api.SetupRoutes(handler, suite.helper.AuthService)
+	request := func(body map[string]string) *httptest.ResponseRecorder {
+		return suite.helper.Request(t, router, "POST", "/api/v1/rag/chat", body)
+	}
+
+	w := request(map[string]string{"query": "Was the budget approved?", "mode": "extractive"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var chat struct {
+		Response string      `json:"response"`
+		Mode     string      `json:"mode"`
+		Quotes   []rag.Quote `json:"quotes"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &chat))
+	assert.Equal(t, "extractive", chat.Mode)
+	assert.Equal(t, "The budget was approved by the board. [1]", chat.Response, "made-up and repeated sentence numbers are ignored")
+	require.Len(t, chat.Quotes, 1)
+	assert.Equal(t, job.ID, chat.Quotes[0].TranscriptionID)
+	assert.Contains(t, selector.prompt, "[1.2] Anna opened the meeting.", "section labels are not quoted")
+
+	selector.reply = `{"sentences": []}`
+	w = request(map[string]string{"query": "Where was lunch served?", "mode": "extractive"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &chat))
+	assert.Equal(t, rag.NoExtractiveAnswer, chat.Response)
+
+	w = request(map[string]string{"query": "Was the budget approved?", "mode": "creative"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAnswerSourcesTestSuite(t *testing.T) {
+	suite.Run(t, new(AnswerSourcesTestSuite))
+}
@@ -9,13 +9,16 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"scriberr/internal/api"
 	"scriberr/internal/models"
 	"scriberr/internal/queue"
 	"scriberr/internal/transcription"
+	"scriberr/internal/workflow"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -42,7 +45,7 @@ func (suite *APIHandlerTestSuite) SetupSuite() {
 	assert.NoError(suite.T(), err)
 
 	suite.taskQueue = queue.NewTaskQueue(1, suite.unifiedProcessor)
-	suite.handler = api.NewHandler(suite.helper.Config, suite.helper.AuthService, suite.taskQueue, suite.unifiedProcessor, suite.quickTranscription)
+	suite.handler = api.NewHandler(suite.helper.Config, suite.helper.AuthService, suite.taskQueue, suite.unifiedProcessor, suite.quickTranscription, nil)
 
 	// Set up router
 	suite.router = api.SetupRoutes(suite.handler, suite.helper.AuthService)
@@ -298,6 +301,573 @@ func (suite *APIHandlerTestSuite) TestDeleteTranscriptionJob() {
 	assert.Equal(suite.T(), 404, w.Code)
 }
 
+// Test that a legal hold blocks deletion until it is released
+func (suite *APIHandlerTestSuite) TestLegalHold() {
+	testJob := suite.helper.CreateTestTranscriptionJob(suite.T(), "Job on Hold")
+	holdPath := fmt.Sprintf("/api/v1/admin/transcription/%s/legal-hold", testJob.ID)
+
+	w := suite.makeAuthenticatedRequest("PUT", holdPath, map[string]interface{}{"hold": true}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	w = suite.makeAuthenticatedRequest("PUT", holdPath, map[string]interface{}{"hold": true, "reason": "Case 2024-17"}, true)
+	assert.Equal(suite.T(), 200, w.Code)
+
+	w = suite.makeAuthenticatedRequest("DELETE", fmt.Sprintf("/api/v1/transcription/%s", testJob.ID), nil, false)
+	assert.Equal(suite.T(), 409, w.Code)
+	w = suite.makeAuthenticatedRequest("DELETE", fmt.Sprintf("/api/v1/transcription/%s/summary", testJob.ID), nil, false)
+	assert.Equal(suite.T(), 409, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", fmt.Sprintf("/api/v1/transcription/%s/redact", testJob.ID), nil, false)
+	assert.Equal(suite.T(), 409, w.Code)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/legal-holds", nil, true)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), testJob.ID)
+
+	w = suite.makeAuthenticatedRequest("PUT", holdPath, map[string]interface{}{"hold": false, "reason": "Case closed"}, true)
+	assert.Equal(suite.T(), 200, w.Code)
+
+	var hold api.LegalHoldResponse
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &hold))
+	assert.False(suite.T(), hold.LegalHold)
+	if assert.Len(suite.T(), hold.History, 2) {
+		assert.Equal(suite.T(), models.EventLegalHoldPlaced, hold.History[0].Type)
+		assert.Equal(suite.T(), "Case 2024-17", hold.History[0].Data["reason"])
+		assert.Equal(suite.T(), models.EventLegalHoldReleased, hold.History[1].Type)
+	}
+
+	w = suite.makeAuthenticatedRequest("DELETE", fmt.Sprintf("/api/v1/transcription/%s", testJob.ID), nil, false)
+	assert.Equal(suite.T(), 200, w.Code)
+}
+
+// Test listing and exporting chapters
+func (suite *APIHandlerTestSuite) TestChapters() {
+	testJob := suite.helper.CreateTestTranscriptionJob(suite.T(), "Episode 12")
+	base := fmt.Sprintf("/api/v1/transcription/%s", testJob.ID)
+
+	w := suite.makeAuthenticatedRequest("GET", base+"/export/chapters?format=youtube", nil, false)
+	assert.Equal(suite.T(), 404, w.Code)
+
+	for i, title := range []string{"Intro", "Interview"} {
+		chapter := models.Chapter{TranscriptionID: testJob.ID, Position: i, Title: title, Start: float64(i * 90), End: float64(i*90 + 90)}
+		suite.Require().NoError(suite.helper.DB.Create(&chapter).Error)
+	}
+
+	w = suite.makeAuthenticatedRequest("GET", base+"/chapters", nil, false)
+	assert.Equal(suite.T(), 200, w.Code)
+	var chapters []models.Chapter
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &chapters))
+	if assert.Len(suite.T(), chapters, 2) {
+		assert.Equal(suite.T(), "Interview", chapters[1].Title)
+	}
+
+	w = suite.makeAuthenticatedRequest("GET", base+"/export/chapters?format=youtube", nil, false)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Equal(suite.T(), "0:00 Intro\n1:30 Interview\n", w.Body.String())
+	assert.Contains(suite.T(), w.Header().Get("Content-Disposition"), "Episode_12.chapters.txt")
+
+	w = suite.makeAuthenticatedRequest("GET", base+"/export/chapters?format=srt", nil, false)
+	assert.Equal(suite.T(), 400, w.Code)
+}
+
+// Test exporting a transcript as subtitles
+func (suite *APIHandlerTestSuite) TestSubtitles() {
+	testJob := suite.helper.CreateTestTranscriptionJob(suite.T(), "Weekly sync")
+	base := fmt.Sprintf("/api/v1/transcription/%s", testJob.ID)
+
+	plain := "Just a plain transcript."
+	testJob.Transcript = &plain
+	suite.Require().NoError(suite.helper.DB.Save(testJob).Error)
+	w := suite.makeAuthenticatedRequest("GET", base+"/export?format=srt", nil, false)
+	assert.Equal(suite.T(), 400, w.Code, "plain text has no timestamps")
+
+	transcript := `{"text":"Morning. Let's start.","segments":[{"start":0,"end":1.5,"text":"Morning.","speaker":"SPEAKER_00"},{"start":1.5,"end":3,"text":"Let's start.","speaker":"SPEAKER_01"}]}`
+	testJob.Transcript = &transcript
+	suite.Require().NoError(suite.helper.DB.Save(testJob).Error)
+	mapping := models.SpeakerMapping{TranscriptionJobID: testJob.ID, OriginalSpeaker: "SPEAKER_00", CustomName: "Dana"}
+	suite.Require().NoError(suite.helper.DB.Create(&mapping).Error)
+
+	w = suite.makeAuthenticatedRequest("GET", base+"/export?format=srt", nil, false)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Equal(suite.T(), "1\n00:00:00,000 --> 00:00:01,500\nDana: Morning.\n\n2\n00:00:01,500 --> 00:00:03,000\nSPEAKER_01: Let's start.\n", w.Body.String())
+	assert.Contains(suite.T(), w.Header().Get("Content-Disposition"), "Weekly_sync.srt")
+
+	w = suite.makeAuthenticatedRequest("GET", base+"/export?format=vtt&speakers=false", nil, false)
+	assert.Equal(suite.T(), 200, w.Code)
+	assert.Equal(suite.T(), "WEBVTT\n\n1\n00:00:00.000 --> 00:00:01.500\nMorning.\n\n2\n00:00:01.500 --> 00:00:03.000\nLet's start.\n", w.Body.String())
+	assert.Contains(suite.T(), w.Header().Get("Content-Type"), "text/vtt")
+
+	for _, query := range []string{"format=html", "max_lines=5", "max_line_length=10", "speakers=maybe"} {
+		w = suite.makeAuthenticatedRequest("GET", base+"/export?"+query, nil, false)
+		assert.Equal(suite.T(), 400, w.Code, query)
+	}
+}
+
+// Test getting a redacted transcript and redacting on demand without an LLM
+func (suite *APIHandlerTestSuite) TestRedactedTranscript() {
+	testJob := suite.helper.CreateTestTranscriptionJob(suite.T(), "Sales call")
+	base := fmt.Sprintf("/api/v1/transcription/%s", testJob.ID)
+
+	w := suite.makeAuthenticatedRequest("GET", base+"/redacted", nil, false)
+	assert.Equal(suite.T(), 404, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", base+"/redact", nil, false)
+	assert.Equal(suite.T(), 503, w.Code, "no LLM providers are configured")
+
+	redaction := models.Redaction{TranscriptionID: testJob.ID, Transcript: `{"text":"Call [PHONE].","segments":[]}`, Counts: map[string]int{"phone": 1}, Names: []string{"Jane"}}
+	suite.Require().NoError(suite.helper.DB.Create(&redaction).Error)
+	w = suite.makeAuthenticatedRequest("GET", base+"/redacted", nil, false)
+	suite.Require().Equal(200, w.Code)
+	var response struct {
+		Counts     map[string]int `json:"counts"`
+		Transcript struct {
+			Text string `json:"text"`
+		} `json:"transcript"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), "Call [PHONE].", response.Transcript.Text)
+	assert.Equal(suite.T(), 1, response.Counts["phone"])
+	assert.NotContains(suite.T(), w.Body.String(), "Jane", "the names found are not returned")
+}
+
+// Test paging through a transcript's segments and filtering them by time
+func (suite *APIHandlerTestSuite) TestListTranscriptSegments() {
+	testJob := suite.helper.CreateTestTranscriptionJob(suite.T(), "Long lecture")
+	base := fmt.Sprintf("/api/v1/transcription/%s/segments", testJob.ID)
+	w := suite.makeAuthenticatedRequest("GET", base, nil, false)
+	assert.Equal(suite.T(), 400, w.Code, "the transcription is not completed")
+
+	var segments []string
+	for i := 0; i < 20; i++ {
+		segments = append(segments, fmt.Sprintf(`{"start":%d,"end":%d,"text":"Part %d","words":[{"word":"Part","start":%d,"end":%d}]}`, i*60, i*60+60, i, i*60, i*60+1))
+	}
+	transcript := `{"text":"","segments":[` + strings.Join(segments, ",") + `]}`
+	suite.Require().NoError(suite.helper.DB.Model(testJob).Updates(map[string]interface{}{"status": models.StatusCompleted, "transcript": transcript}).Error)
+
+	type page struct {
+		Segments []struct {
+			Index int     `json:"index"`
+			Start float64 `json:"start"`
+			Text  string  `json:"text"`
+			Words []struct {
+				Word string `json:"word"`
+			} `json:"words"`
+		} `json:"segments"`
+		TotalSegments int     `json:"total_segments"`
+		Duration      float64 `json:"duration"`
+		Pagination    struct {
+			Total int `json:"total"`
+			Pages int `json:"pages"`
+		} `json:"pagination"`
+	}
+	get := func(query string) page {
+		w := suite.makeAuthenticatedRequest("GET", base+query, nil, false)
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		var response page
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	response := get("?limit=8&page=3")
+	suite.Require().Len(response.Segments, 4)
+	assert.Equal(suite.T(), 16, response.Segments[0].Index)
+	assert.Equal(suite.T(), "Part 16", response.Segments[0].Text)
+	assert.Equal(suite.T(), "Part", response.Segments[0].Words[0].Word, "segments are returned as stored")
+	assert.Equal(suite.T(), 20, response.TotalSegments)
+	assert.Equal(suite.T(), 1200.0, response.Duration)
+	assert.Equal(suite.T(), 3, response.Pagination.Pages)
+
+	// Segments overlapping 10:00 to 15:00
+	response = get("?from=600&to=900")
+	suite.Require().Len(response.Segments, 5)
+	assert.Equal(suite.T(), 10, response.Segments[0].Index)
+	assert.Equal(suite.T(), 14, response.Segments[4].Index)
+	response = get("?from=630&to=631")
+	suite.Require().Len(response.Segments, 1)
+	assert.Equal(suite.T(), 10, response.Segments[0].Index)
+	response = get("?from=5000")
+	assert.Empty(suite.T(), response.Segments)
+
+	w = suite.makeAuthenticatedRequest("GET", base+"?from=900&to=600", nil, false)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("GET", base+"?from=-1", nil, false)
+	assert.Equal(suite.T(), 400, w.Code)
+}
+
+// Test downloading audio and exports through signed URLs
+func (suite *APIHandlerTestSuite) TestSignedDownloads() {
+	testJob := suite.helper.CreateTestTranscriptionJob(suite.T(), "Shared recording")
+	audioPath := filepath.Join(suite.T().TempDir(), "recording.mp3")
+	suite.Require().NoError(os.WriteFile(audioPath, []byte("fake audio"), 0644))
+	suite.Require().NoError(suite.helper.DB.Model(testJob).Update("audio_path", audioPath).Error)
+
+	create := func(body map[string]interface{}) api.DownloadLinkResponse {
+		w := suite.makeAuthenticatedRequest("POST", "/api/v1/downloads", body, true)
+		suite.Require().Equal(201, w.Code, w.Body.String())
+		var link api.DownloadLinkResponse
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &link))
+		return link
+	}
+	download := func(url string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", url, nil)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+	audio := fmt.Sprintf("/api/v1/transcription/%s/audio", testJob.ID)
+
+	link := create(map[string]interface{}{"path": audio, "ttl_seconds": 60})
+	assert.WithinDuration(suite.T(), time.Now().Add(time.Minute), link.ExpiresAt, 5*time.Second)
+	w := download(link.URL)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	assert.Equal(suite.T(), "fake audio", w.Body.String())
+	assert.Equal(suite.T(), 200, download(link.URL).Code, "a link works until it expires")
+	assert.Equal(suite.T(), 404, download("/api/v1/downloads/not-a-token").Code)
+
+	// A one-time link works once
+	link = create(map[string]interface{}{"path": audio, "one_time": true})
+	assert.Equal(suite.T(), 200, download(link.URL).Code)
+	assert.Equal(suite.T(), 410, download(link.URL).Code)
+
+	// Expired links are refused
+	link = create(map[string]interface{}{"path": audio})
+	suite.Require().NoError(suite.helper.DB.Model(&models.DownloadLink{}).Where("path = ? AND one_time = ?", audio, false).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	assert.Equal(suite.T(), 410, download(link.URL).Code)
+
+	// The query is signed with the path
+	link = create(map[string]interface{}{"path": "/api/v1/action-items/export?format=markdown"})
+	w = download(link.URL + "?format=csv")
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	assert.Contains(suite.T(), w.Header().Get("Content-Type"), "text/markdown")
+	assert.Equal(suite.T(), "private, no-store", w.Header().Get("Cache-Control"))
+
+	for _, body := range []map[string]interface{}{
+		{"path": "/api/v1/transcription/list"},
+		{"path": "https://example.com" + audio},
+		{"path": audio, "ttl_seconds": 10 * 86400},
+	} {
+		w = suite.makeAuthenticatedRequest("POST", "/api/v1/downloads", body, true)
+		assert.Equal(suite.T(), 400, w.Code, body)
+	}
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/downloads", map[string]interface{}{"path": "/api/v1/transcription/missing/audio"}, true)
+	assert.Equal(suite.T(), 404, w.Code)
+	req, _ := http.NewRequest("POST", "/api/v1/downloads", strings.NewReader(`{"path":"`+audio+`"}`))
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	assert.Equal(suite.T(), 401, w.Code, "creating a link needs credentials")
+}
+
+// Test that sensitive passages must be confirmed before a scanned transcript is shared
+func (suite *APIHandlerTestSuite) TestShareScan() {
+	t := suite.T()
+	testJob := suite.helper.CreateTestTranscriptionJob(t, "Board call")
+	transcript := `{"segments":[{"start":0,"end":4,"text":"Project Falcon closes on Friday."},{"start":4,"end":9,"text":"Mail me at cfo@example.com with the figures."},{"start":9,"end":12,"text":"Thanks everyone."}]}`
+	suite.Require().NoError(suite.helper.DB.Model(testJob).Updates(map[string]interface{}{"transcript": transcript, "status": models.StatusCompleted}).Error)
+	suite.helper.Config.ShareSensitiveTerms = "project falcon, acquisition"
+	defer func() { suite.helper.Config.ShareSensitiveTerms = "" }()
+	path := fmt.Sprintf("/api/v1/transcription/%s/export/chapters", testJob.ID)
+
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/downloads", map[string]interface{}{"path": path, "scan": true}, true)
+	suite.Require().Equal(409, w.Code, w.Body.String())
+	var flagged api.ShareScanResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &flagged))
+	suite.Require().Len(flagged.Passages, 2)
+	assert.Equal(t, "term", flagged.Passages[0].Kind)
+	assert.Equal(t, "Project Falcon", flagged.Passages[0].Text)
+	assert.Equal(t, "email", flagged.Passages[1].Kind)
+	assert.Equal(t, "Mail me at cfo@example.com with the figures.", flagged.Passages[1].Context)
+	assert.Equal(t, 4.0, flagged.Passages[1].Start)
+
+	// Confirming some of them isn't enough
+	body := map[string]interface{}{"path": path, "scan": true, "confirmed_passages": []string{flagged.Passages[0].ID}}
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/downloads", body, true)
+	suite.Require().Equal(409, w.Code)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &flagged))
+	assert.Len(t, flagged.Passages, 1)
+
+	body["confirmed_passages"] = []string{body["confirmed_passages"].([]string)[0], flagged.Passages[0].ID}
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/downloads", body, true)
+	suite.Require().Equal(201, w.Code, w.Body.String())
+	var link api.DownloadLinkResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &link))
+	assert.True(t, link.Scanned)
+	var stored models.DownloadLink
+	suite.Require().NoError(suite.helper.DB.Where("path = ?", path).First(&stored).Error)
+	assert.Len(t, stored.ConfirmedPassages, 2)
+
+	// Without a scan requested or required, the link is signed as before
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/downloads", map[string]interface{}{"path": path}, true)
+	assert.Equal(t, 201, w.Code)
+	suite.helper.Config.ShareScan = true
+	defer func() { suite.helper.Config.ShareScan = false }()
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/downloads", map[string]interface{}{"path": path}, true)
+	assert.Equal(t, 409, w.Code, "SHARE_SCAN scans every transcript")
+}
+
+// Test the per-step post-processing status of a transcription
+func (suite *APIHandlerTestSuite) TestPostProcessingStatus() {
+	testJob := suite.helper.CreateTestTranscriptionJob(suite.T(), "Summarized twice")
+	path := fmt.Sprintf("/api/v1/transcription/%s/post-processing", testJob.ID)
+	var response api.PostProcessingStatusResponse
+	w := suite.makeAuthenticatedRequest("GET", path, nil, false)
+	suite.Require().Equal(200, w.Code)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Empty(suite.T(), response.Steps, "never post-processed")
+	assert.Empty(suite.T(), response.Status)
+	assert.False(suite.T(), response.Enabled)
+
+	errMsg := "ollama: connection refused"
+	first := models.WorkflowRun{TranscriptionID: testJob.ID, Workflow: "default", Status: models.WorkflowFailed, CreatedAt: time.Now().Add(-time.Hour), Steps: []models.WorkflowStep{
+		{Name: "summarize", Position: 0, Status: models.WorkflowFailed, Attempts: 1, Error: &errMsg},
+		{Name: "rag_index", Position: 1, Status: models.WorkflowCompleted, Attempts: 1},
+	}}
+	suite.Require().NoError(suite.helper.DB.Create(&first).Error)
+	w = suite.makeAuthenticatedRequest("GET", path, nil, false)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	suite.Require().Len(response.Steps, 2)
+	assert.Equal(suite.T(), models.WorkflowFailed, response.Status)
+	assert.Equal(suite.T(), "summarize", response.Steps[0].Name)
+	suite.Require().NotNil(response.Steps[0].Error)
+	assert.Equal(suite.T(), errMsg, *response.Steps[0].Error)
+
+	// A later run of one step reports that step's newest state
+	second := models.WorkflowRun{TranscriptionID: testJob.ID, Workflow: "summary_only", Status: models.WorkflowCompleted, Steps: []models.WorkflowStep{
+		{Name: "summarize", Position: 0, Status: models.WorkflowCompleted, Attempts: 1},
+	}}
+	suite.Require().NoError(suite.helper.DB.Create(&second).Error)
+	response = api.PostProcessingStatusResponse{}
+	w = suite.makeAuthenticatedRequest("GET", path, nil, false)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), 2, response.Runs)
+	assert.Equal(suite.T(), models.WorkflowCompleted, response.Status)
+	states := map[string]models.WorkflowStatus{}
+	for _, step := range response.Steps {
+		states[step.Name] = step.Status
+	}
+	assert.Equal(suite.T(), map[string]models.WorkflowStatus{"summarize": models.WorkflowCompleted, "rag_index": models.WorkflowCompleted}, states)
+	assert.Equal(suite.T(), second.ID, response.Steps[0].RunID)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/missing/post-processing", nil, false)
+	assert.Equal(suite.T(), 404, w.Code)
+}
+
+// Test listing failed workflow steps and requeueing them
+func (suite *APIHandlerTestSuite) TestWorkflowFailures() {
+	testJob := suite.helper.CreateTestTranscriptionJob(suite.T(), "Indexed later")
+	transcript := `{"segments":[{"start":0,"end":1,"text":"hello"}]}`
+	suite.Require().NoError(suite.helper.DB.Model(testJob).Updates(map[string]interface{}{"status": models.StatusCompleted, "transcript": transcript}).Error)
+	errMsg := "vector store unreachable"
+	run := models.WorkflowRun{TranscriptionID: testJob.ID, Workflow: "default", Status: models.WorkflowFailed, Steps: []models.WorkflowStep{
+		{Name: "summarize", Position: 0, Status: models.WorkflowCompleted, Attempts: 1},
+		{Name: "rag_index", Position: 1, Status: models.WorkflowDeadLetter, Attempts: 5, Error: &errMsg},
+	}}
+	suite.Require().NoError(suite.helper.DB.Create(&run).Error)
+	deadLettered := run.Steps[1]
+
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/admin/workflow-failures?status=dead_letter", nil, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var response struct {
+		Failures []api.WorkflowFailure `json:"failures"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	suite.Require().Len(response.Failures, 1)
+	assert.Equal(suite.T(), "rag_index", response.Failures[0].Name)
+	assert.Equal(suite.T(), testJob.ID, response.Failures[0].TranscriptionID)
+	assert.Equal(suite.T(), "default", response.Failures[0].Workflow)
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/admin/workflow-failures?status=blocked", nil, true)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	path := fmt.Sprintf("/api/v1/admin/workflow-failures/%d/requeue", deadLettered.ID)
+	w = suite.makeAuthenticatedRequest("POST", path, nil, true)
+	assert.Equal(suite.T(), 503, w.Code, "post-processing is not enabled")
+
+	engine := workflow.NewEngine("default")
+	engine.RegisterStep("summarize", &fakeStep{})
+	engine.RegisterStep("rag_index", &fakeStep{})
+	suite.handler.SetWorkflowEngine(engine)
+	defer suite.handler.SetWorkflowEngine(nil)
+
+	w = suite.makeAuthenticatedRequest("POST", fmt.Sprintf("/api/v1/admin/workflow-failures/%d/requeue", run.Steps[0].ID), nil, true)
+	assert.Equal(suite.T(), 409, w.Code, "completed steps can't be requeued")
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/workflow-failures/999999/requeue", nil, true)
+	assert.Equal(suite.T(), 404, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", path, nil, true)
+	suite.Require().Equal(202, w.Code, w.Body.String())
+
+	var step models.WorkflowStep
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		suite.Require().NoError(suite.helper.DB.First(&step, deadLettered.ID).Error)
+		if step.Status == models.WorkflowCompleted {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.Equal(suite.T(), models.WorkflowCompleted, step.Status)
+	assert.Equal(suite.T(), 1, step.Attempts, "a requeued step starts over")
+}
+
+// Test managing watchlists and listing their matches
+func (suite *APIHandlerTestSuite) TestWatchlists() {
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/watchlists", map[string]interface{}{"name": "Churn", "terms": []string{"  "}}, true)
+	assert.Equal(suite.T(), 400, w.Code, "a watchlist needs a term")
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/watchlists", map[string]interface{}{"name": "Churn", "terms": []string{"refund"}, "webhook_url": "ftp://example.com"}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/watchlists", map[string]interface{}{"name": "Churn", "terms": []string{"refund"}, "similarity_threshold": 1.5}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/watchlists", map[string]interface{}{
+		"name":        " Churn ",
+		"terms":       []string{"refund", " cancel   my subscription", "Refund"},
+		"fuzzy":       true,
+		"webhook_url": "https://example.com/hook",
+	}, true)
+	suite.Require().Equal(201, w.Code)
+	var list models.Watchlist
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(suite.T(), "Churn", list.Name)
+	assert.Equal(suite.T(), []string{"refund", "cancel my subscription"}, list.Terms)
+
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/watchlists/"+list.ID, map[string]interface{}{"name": "Churn", "terms": []string{"refund"}, "paused": true}, true)
+	suite.Require().Equal(200, w.Code)
+	var updated models.Watchlist
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.True(suite.T(), updated.Paused)
+	assert.Empty(suite.T(), updated.WebhookURL)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/watchlists", nil, true)
+	suite.Require().Equal(200, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), list.ID)
+
+	testJob := suite.helper.CreateTestTranscriptionJob(suite.T(), "Support call")
+	match := models.WatchlistMatch{WatchlistID: list.ID, TranscriptionID: testJob.ID, UserID: testJob.UserID, Term: "refund", MatchType: "exact", Score: 1, Text: "I want a refund.", Start: 12, End: 15}
+	suite.Require().NoError(suite.helper.DB.Create(&match).Error)
+
+	w = suite.makeAuthenticatedRequest("GET", "/api/v1/watchlists/"+list.ID+"/matches", nil, true)
+	suite.Require().Equal(200, w.Code)
+	var page struct {
+		Matches    []models.WatchlistMatch `json:"matches"`
+		Pagination struct {
+			Total int64 `json:"total"`
+		} `json:"pagination"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &page))
+	suite.Require().Len(page.Matches, 1)
+	assert.Equal(suite.T(), 12.0, page.Matches[0].Start)
+	assert.Equal(suite.T(), int64(1), page.Pagination.Total)
+
+	w = suite.makeAuthenticatedRequest("GET", fmt.Sprintf("/api/v1/transcription/%s/watchlist-matches", testJob.ID), nil, false)
+	suite.Require().Equal(200, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "I want a refund.")
+
+	w = suite.makeAuthenticatedRequest("DELETE", "/api/v1/watchlists/"+list.ID, nil, true)
+	suite.Require().Equal(200, w.Code)
+	var count int64
+	suite.Require().NoError(suite.helper.DB.Model(&models.WatchlistMatch{}).Where("watchlist_id = ?", list.ID).Count(&count).Error)
+	assert.Equal(suite.T(), int64(0), count)
+	w = suite.makeAuthenticatedRequest("DELETE", "/api/v1/watchlists/"+list.ID, nil, true)
+	assert.Equal(suite.T(), 404, w.Code)
+}
+
+// Test managing task integrations and listing deliveries
+func (suite *APIHandlerTestSuite) TestTaskIntegrations() {
+	w := suite.makeAuthenticatedRequest("POST", "/api/v1/integrations", map[string]interface{}{"name": "Linear", "provider": "linear", "token": "lin_key"}, true)
+	assert.Equal(suite.T(), 400, w.Code, "Linear needs a team")
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/integrations", map[string]interface{}{"name": "Jira", "provider": "jira", "token": "key"}, true)
+	assert.Equal(suite.T(), 400, w.Code)
+
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/integrations", map[string]interface{}{
+		"name":      "Todoist",
+		"provider":  "todoist",
+		"token":     "todoist-token",
+		"target":    "2203306141",
+		"assignees": map[string]string{" Dana  Smith ": "U-7", "Sam": " "},
+	}, true)
+	suite.Require().Equal(201, w.Code, w.Body.String())
+	assert.NotContains(suite.T(), w.Body.String(), "todoist-token")
+	var created api.TaskIntegrationResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	assert.True(suite.T(), created.HasToken)
+	assert.Equal(suite.T(), map[string]string{"Dana Smith": "U-7"}, created.Assignees)
+
+	// Leaving the token out keeps it
+	w = suite.makeAuthenticatedRequest("PUT", "/api/v1/integrations/"+created.ID, map[string]interface{}{"name": "Todoist", "provider": "todoist", "auto_deliver": true}, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var updated api.TaskIntegrationResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.True(suite.T(), updated.HasToken)
+	assert.True(suite.T(), updated.AutoDeliver)
+	assert.Empty(suite.T(), updated.Target)
+
+	testJob := suite.helper.CreateTestTranscriptionJob(suite.T(), "Weekly sync")
+	delivery := models.ActionItemDelivery{IntegrationID: created.ID, TranscriptionID: testJob.ID, TaskKey: "book the venue", Status: models.DeliveryDelivered, ExternalID: "8001"}
+	suite.Require().NoError(suite.helper.DB.Create(&delivery).Error)
+	w = suite.makeAuthenticatedRequest("GET", fmt.Sprintf("/api/v1/transcription/%s/action-items/deliveries", testJob.ID), nil, false)
+	suite.Require().Equal(200, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"external_id":"8001"`)
+	w = suite.makeAuthenticatedRequest("POST", fmt.Sprintf("/api/v1/transcription/%s/action-items/deliver", testJob.ID), map[string]string{"integration_id": "missing"}, true)
+	assert.Equal(suite.T(), 404, w.Code)
+
+	w = suite.makeAuthenticatedRequest("DELETE", "/api/v1/integrations/"+created.ID, nil, true)
+	suite.Require().Equal(200, w.Code)
+	var count int64
+	suite.Require().NoError(suite.helper.DB.Model(&models.ActionItemDelivery{}).Where("integration_id = ?", created.ID).Count(&count).Error)
+	assert.Zero(suite.T(), count)
+}
+
+// Test exporting settings and importing them again
+func (suite *APIHandlerTestSuite) TestSettingsExportImport() {
+	profile := suite.helper.CreateTestProfile(suite.T(), "Portable Profile", false)
+	template := models.SummaryTemplate{UserID: &suite.helper.TestUser.ID, Name: "Portable notes", Model: "gpt-4", Prompt: "Summarize", Layout: models.SummaryLayoutPerSpeaker}
+	suite.Require().NoError(suite.helper.DB.Create(&template).Error)
+	suite.Require().NoError(suite.helper.DB.Model(suite.helper.TestUser).Updates(map[string]interface{}{
+		"default_profile_id":          profile.ID,
+		"default_summary_template_id": template.ID,
+	}).Error)
+
+	w := suite.makeAuthenticatedRequest("GET", "/api/v1/admin/settings/export", nil, true)
+	suite.Require().Equal(200, w.Code)
+	var bundle api.SettingsBundle
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &bundle))
+	assert.Equal(suite.T(), 1, bundle.Version)
+	suite.Require().NotNil(bundle.UserSettings)
+	assert.Equal(suite.T(), profile.ID, *bundle.UserSettings.DefaultProfileID)
+
+	// Importing into an instance without the profile and template recreates them
+	suite.Require().NoError(suite.helper.DB.Delete(profile).Error)
+	suite.Require().NoError(suite.helper.DB.Delete(&template).Error)
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/settings/import", bundle, true)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	var result api.SettingsImportResult
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(suite.T(), 1, result.Profiles.Created)
+	assert.Equal(suite.T(), 1, result.SummaryTemplates.Created)
+	assert.True(suite.T(), result.UserSettings)
+
+	var imported models.TranscriptionProfile
+	suite.Require().NoError(suite.helper.DB.Where("name = ?", "Portable Profile").First(&imported).Error)
+	assert.NotEqual(suite.T(), profile.ID, imported.ID)
+	assert.Equal(suite.T(), "float32", imported.Parameters.ComputeType)
+	var importedTemplate models.SummaryTemplate
+	suite.Require().NoError(suite.helper.DB.Where("name = ?", "Portable notes").First(&importedTemplate).Error)
+	assert.Equal(suite.T(), models.SummaryLayoutPerSpeaker, importedTemplate.Layout)
+	var user models.User
+	suite.Require().NoError(suite.helper.DB.First(&user, suite.helper.TestUser.ID).Error)
+	assert.Equal(suite.T(), imported.ID, *user.DefaultProfileID, "references follow the imported records")
+	assert.Equal(suite.T(), importedTemplate.ID, *user.DefaultSummaryTemplateID)
+
+	// Importing again updates the same records
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/settings/import", bundle, true)
+	suite.Require().Equal(200, w.Code)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Zero(suite.T(), result.Profiles.Created)
+	assert.Zero(suite.T(), result.SummaryTemplates.Created)
+	assert.Equal(suite.T(), 1, result.SummaryTemplates.Updated)
+
+	bundle.Version = 2
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/admin/settings/import", bundle, true)
+	assert.Equal(suite.T(), 400, w.Code)
+}
+
 // Test getting supported models
 func (suite *APIHandlerTestSuite) TestGetSupportedModels() {
 	w := suite.makeAuthenticatedRequest("GET", "/api/v1/transcription/models", nil, false)
@@ -345,6 +915,15 @@ func (suite *APIHandlerTestSuite) TestProfileManagement() {
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), "Test Profile", createResponse.Name)
 
+	// Unknown preprocessing steps are rejected
+	badProfile := map[string]interface{}{
+		"name":       "Bad Preprocessing",
+		"parameters": map[string]interface{}{"preprocess": "normalize,reverb"},
+	}
+	w = suite.makeAuthenticatedRequest("POST", "/api/v1/profiles/", badProfile, false)
+	assert.Equal(suite.T(), 400, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "reverb")
+
 	// Get profile
 	w = suite.makeAuthenticatedRequest("GET", fmt.Sprintf("/api/v1/profiles/%s", createResponse.ID), nil, false)
 	assert.Equal(suite.T(), 200, w.Code)
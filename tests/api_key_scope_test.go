@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type APIKeyScopeTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	router *gin.Engine
+}
+
+func (suite *APIKeyScopeTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "api_key_scope_test.db")
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, queue.NewTaskQueue(1, &MockJobProcessor{}), nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *APIKeyScopeTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// createKey creates an API key for the test user
+func (suite *APIKeyScopeTestSuite) createKey(body gin.H) models.APIKey {
+	w := suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodPost, "/api/v1/api-keys/", body)
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+	var key models.APIKey
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &key))
+	return key
+}
+
+func (suite *APIKeyScopeTestSuite) TestScopes() {
+	t := suite.T()
+	job := suite.helper.CreateTestTranscriptionJob(t, "Scoped")
+	require.NoError(t, suite.helper.DB.Model(job).Update("user_id", suite.helper.TestUser.ID).Error)
+
+	read := suite.createKey(gin.H{"name": "Dashboard", "scopes": []string{"read"}})
+	assert.Equal(t, []string{models.APIKeyScopeRead}, read.Scopes)
+	assert.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, read.Key, http.MethodGet, "/api/v1/transcription/list", nil).Code)
+	assert.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, read.Key, http.MethodGet, "/api/v1/transcription/"+job.ID, nil).Code)
+	assert.Equal(t, http.StatusForbidden, suite.helper.RequestAs(suite.T(), suite.router, read.Key, http.MethodPut, "/api/v1/transcription/"+job.ID+"/title", gin.H{"title": "Renamed"}).Code)
+	assert.Equal(t, http.StatusForbidden, suite.helper.RequestAs(suite.T(), suite.router, read.Key, http.MethodPost, "/api/v1/rag/search", gin.H{"query": "budget"}).Code)
+	// Admin routes are off limits to scoped keys, even an admin's
+	assert.Equal(t, http.StatusForbidden, suite.helper.RequestAs(suite.T(), suite.router, read.Key, http.MethodGet, "/api/v1/admin/settings/export", nil).Code)
+	assert.Equal(t, http.StatusForbidden, suite.helper.RequestAs(suite.T(), suite.router, read.Key, http.MethodGet, "/api/v1/rag/eval/cases", nil).Code)
+
+	upload := suite.createKey(gin.H{"name": "Recorder", "scopes": []string{"upload", "UPLOAD"}})
+	assert.Equal(t, []string{models.APIKeyScopeUpload}, upload.Scopes)
+	assert.Equal(t, http.StatusBadRequest, suite.helper.RequestAs(suite.T(), suite.router, upload.Key, http.MethodPost, "/api/v1/transcription/upload", nil).Code, "no file, but allowed")
+	assert.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, upload.Key, http.MethodGet, "/api/v1/transcription/"+job.ID+"/status", nil).Code)
+	assert.Equal(t, http.StatusForbidden, suite.helper.RequestAs(suite.T(), suite.router, upload.Key, http.MethodGet, "/api/v1/transcription/"+job.ID, nil).Code)
+	assert.Equal(t, http.StatusForbidden, suite.helper.RequestAs(suite.T(), suite.router, upload.Key, http.MethodDelete, "/api/v1/transcription/"+job.ID, nil).Code)
+
+	chat := suite.createKey(gin.H{"name": "Bot", "scopes": []string{"rag_chat"}})
+	assert.NotEqual(t, http.StatusForbidden, suite.helper.RequestAs(suite.T(), suite.router, chat.Key, http.MethodPost, "/api/v1/rag/search", gin.H{"query": "budget"}).Code)
+	assert.Equal(t, http.StatusForbidden, suite.helper.RequestAs(suite.T(), suite.router, chat.Key, http.MethodGet, "/api/v1/transcription/list", nil).Code)
+
+	// Scopes add up
+	both := suite.createKey(gin.H{"name": "Sync", "scopes": []string{"read", "upload"}})
+	assert.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, both.Key, http.MethodGet, "/api/v1/transcription/list", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, suite.helper.RequestAs(suite.T(), suite.router, both.Key, http.MethodPost, "/api/v1/transcription/upload", nil).Code)
+
+	// A key without scopes can do everything
+	full := suite.createKey(gin.H{"name": "Everything"})
+	assert.Empty(t, full.Scopes)
+	assert.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, full.Key, http.MethodPut, "/api/v1/transcription/"+job.ID+"/title", gin.H{"title": "Renamed"}).Code)
+	assert.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, full.Key, http.MethodGet, "/api/v1/admin/settings/export", nil).Code)
+
+	assert.Equal(t, http.StatusBadRequest, suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodPost, "/api/v1/api-keys/", gin.H{"name": "Bad", "scopes": []string{"admin"}}).Code)
+}
+
+func (suite *APIKeyScopeTestSuite) TestExpiryAndRevocation() {
+	t := suite.T()
+	key := suite.createKey(gin.H{"name": "Temporary", "expires_at": time.Now().Add(time.Hour)})
+	require.NotNil(t, key.ExpiresAt)
+	assert.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, key.Key, http.MethodGet, "/api/v1/transcription/list", nil).Code)
+
+	require.NoError(t, suite.helper.DB.Model(&models.APIKey{}).Where("id = ?", key.ID).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	assert.Equal(t, http.StatusUnauthorized, suite.helper.RequestAs(suite.T(), suite.router, key.Key, http.MethodGet, "/api/v1/transcription/list", nil).Code)
+
+	w := suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodGet, "/api/v1/api-keys/", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list api.APIKeysWrapper
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	var listed *api.APIKeyListResponse
+	for i := range list.APIKeys {
+		if list.APIKeys[i].ID == key.ID {
+			listed = &list.APIKeys[i]
+		}
+	}
+	require.NotNil(t, listed)
+	assert.True(t, listed.Expired)
+	assert.NotEmpty(t, listed.ExpiresAt)
+	assert.NotEmpty(t, listed.LastUsed)
+
+	// Revoking one key leaves the others working
+	other := suite.createKey(gin.H{"name": "Kept"})
+	revoked := suite.createKey(gin.H{"name": "Revoked"})
+	require.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodDelete, fmt.Sprintf("/api/v1/api-keys/%d", revoked.ID), nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, suite.helper.RequestAs(suite.T(), suite.router, revoked.Key, http.MethodGet, "/api/v1/transcription/list", nil).Code)
+	assert.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, other.Key, http.MethodGet, "/api/v1/transcription/list", nil).Code)
+
+	assert.Equal(t, http.StatusBadRequest, suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodPost, "/api/v1/api-keys/", gin.H{"name": "Past", "expires_at": time.Now().Add(-time.Hour)}).Code)
+}
+
+func TestAPIKeyScopeTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyScopeTestSuite))
+}
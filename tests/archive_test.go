@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+	"scriberr/internal/tagging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ArchiveTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	router *gin.Engine
+}
+
+func (suite *ArchiveTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "archive_test.db")
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, queue.NewTaskQueue(1, &MockJobProcessor{}), nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *ArchiveTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *ArchiveTestSuite) get(path string) *httptest.ResponseRecorder {
+	return suite.helper.Request(suite.T(), suite.router, http.MethodGet, path, nil)
+}
+
+func (suite *ArchiveTestSuite) importArchive(data []byte) *httptest.ResponseRecorder {
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, err := writer.CreateFormFile("archive", "backup.zip")
+	require.NoError(suite.T(), err)
+	part.Write(data)
+	require.NoError(suite.T(), writer.Close())
+	req := suite.helper.NewRequest(suite.T(), suite.helper.TestAPIKey, http.MethodPost, "/api/v1/transcription/archive/import", &form)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return serve(suite.router, req)
+}
+
+// Test backing up transcriptions and restoring them after they were deleted
+func (suite *ArchiveTestSuite) TestExportAndImport() {
+	t := suite.T()
+	db := suite.helper.DB
+	job := suite.helper.CreateTestTranscriptionJob(t, "Quarterly review")
+	audioPath := filepath.Join(suite.helper.Config.UploadDir, job.ID+".mp3")
+	require.NoError(t, os.WriteFile(audioPath, []byte("fake audio"), 0644))
+	transcript := `{"text":"Revenue is up.","segments":[{"start":0,"end":2,"text":"Revenue is up.","speaker":"SPEAKER_00"}]}`
+	require.NoError(t, db.Model(job).Updates(map[string]interface{}{"status": models.StatusCompleted, "transcript": transcript, "summary": "Good quarter.", "audio_path": audioPath}).Error)
+	_, err := tagging.SetJobTags(job, []string{"finance"})
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&models.Summary{TranscriptionID: job.ID, Model: "test", Content: "Good quarter."}).Error)
+	require.NoError(t, db.Create(&models.Note{ID: "note-1", TranscriptionID: job.ID, Quote: "Revenue", Content: "Check the numbers"}).Error)
+	require.NoError(t, db.Create(&models.SpeakerMapping{TranscriptionJobID: job.ID, OriginalSpeaker: "SPEAKER_00", CustomName: "CFO"}).Error)
+	require.NoError(t, db.Create(&models.Chapter{TranscriptionID: job.ID, Title: "Revenue", End: 2}).Error)
+	require.NoError(t, db.Create(&models.ActionItem{TranscriptionID: job.ID, Task: "Send report"}).Error)
+	other := suite.helper.CreateTestTranscriptionJob(t, "Not exported")
+
+	w := suite.get("/api/v1/transcription/archive?audio=maybe")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = suite.get("/api/v1/transcription/archive?ids=missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = suite.get("/api/v1/transcription/archive?audio=true&ids=" + job.ID)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	backup := w.Body.Bytes()
+
+	// Importing where the transcriptions exist skips them
+	w = suite.importArchive(backup)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result api.ArchiveImportResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, []string{job.ID}, result.Skipped)
+	assert.Empty(t, result.Imported)
+
+	// Gone, then restored
+	for _, model := range []interface{}{&models.Summary{}, &models.Note{}, &models.Chapter{}, &models.ActionItem{}, &models.TranscriptionTag{}} {
+		require.NoError(t, db.Where("transcription_id = ?", job.ID).Delete(model).Error)
+	}
+	require.NoError(t, db.Where("transcription_job_id = ?", job.ID).Delete(&models.SpeakerMapping{}).Error)
+	require.NoError(t, db.Delete(&models.TranscriptionJob{}, "id = ?", job.ID).Error)
+	require.NoError(t, os.Remove(audioPath))
+
+	w = suite.importArchive(backup)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, []string{job.ID}, result.Imported)
+	assert.Equal(t, 1, result.Audio)
+
+	var restored models.TranscriptionJob
+	require.NoError(t, db.Where("id = ?", job.ID).First(&restored).Error)
+	assert.Equal(t, "Quarterly review", *restored.Title)
+	assert.Equal(t, models.StatusCompleted, restored.Status)
+	assert.Equal(t, transcript, *restored.Transcript)
+	assert.Equal(t, []string{"finance"}, restored.Tags)
+	data, err := os.ReadFile(restored.AudioPath)
+	require.NoError(t, err)
+	assert.Equal(t, "fake audio", string(data))
+	for model, want := range map[interface{}]int64{&models.Summary{}: 1, &models.Note{}: 1, &models.Chapter{}: 1, &models.ActionItem{}: 1} {
+		var count int64
+		require.NoError(t, db.Model(model).Where("transcription_id = ?", job.ID).Count(&count).Error)
+		assert.Equal(t, want, count)
+	}
+	var mapping models.SpeakerMapping
+	require.NoError(t, db.Where("transcription_job_id = ?", job.ID).First(&mapping).Error)
+	assert.Equal(t, "CFO", mapping.CustomName)
+
+	// Every transcription without ids; one without audio that never finished is marked failed
+	w = suite.get("/api/v1/transcription/archive")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, db.Delete(&models.TranscriptionJob{}, "id = ?", other.ID).Error)
+	w = suite.importArchive(w.Body.Bytes())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, []string{other.ID}, result.Imported)
+	assert.Equal(t, 0, result.Audio)
+	var unfinished models.TranscriptionJob
+	require.NoError(t, db.Where("id = ?", other.ID).First(&unfinished).Error)
+	assert.Equal(t, models.StatusFailed, unfinished.Status)
+	assert.Empty(t, unfinished.AudioPath)
+
+	w = suite.importArchive([]byte("not a zip"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestArchiveTestSuite(t *testing.T) {
+	suite.Run(t, new(ArchiveTestSuite))
+}
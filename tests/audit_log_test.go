@@ -0,0 +1,256 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditLogTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	router *gin.Engine
+	stale  models.AuditLog
+}
+
+func (suite *AuditLogTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "audit_log_test.db")
+	suite.helper.Config.AuditLogRetentionDays = 30
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, queue.NewTaskQueue(1, &MockJobProcessor{}), nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+
+	// An entry past the retention period, deleted once anything is recorded
+	suite.stale = models.AuditLog{Action: models.AuditDelete, TargetID: "long-gone", CreatedAt: time.Now().AddDate(0, 0, -60)}
+	require.NoError(suite.T(), suite.helper.DB.Create(&suite.stale).Error)
+}
+
+func (suite *AuditLogTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// entries lists the audit log with the given filters
+func (suite *AuditLogTestSuite) entries(filters url.Values) []models.AuditLog {
+	w := suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodGet, "/api/v1/admin/audit-log?"+filters.Encode(), nil)
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Entries []models.AuditLog `json:"entries"`
+	}
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Entries
+}
+
+// latest returns the newest entry of an action
+func (suite *AuditLogTestSuite) latest(action string) models.AuditLog {
+	entries := suite.entries(url.Values{"action": {action}, "limit": {"1"}})
+	require.Len(suite.T(), entries, 1, "no %s entry", action)
+	return entries[0]
+}
+
+// completedJob creates a completed transcription owned by the test user
+func (suite *AuditLogTestSuite) completedJob(title string) *models.TranscriptionJob {
+	t := suite.T()
+	job := suite.helper.CreateTestTranscriptionJob(t, title)
+	require.NoError(t, suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status":     models.StatusCompleted,
+		"user_id":    suite.helper.TestUser.ID,
+		"transcript": `{"segments":[{"start":0,"end":3,"text":"The budget is approved."}]}`,
+	}).Error)
+	return job
+}
+
+func (suite *AuditLogTestSuite) TestLogin() {
+	t := suite.T()
+	w := suite.helper.RequestAs(suite.T(), suite.router, "", http.MethodPost, "/api/v1/auth/login", gin.H{"username": "testuser", "password": "wrong-password"})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	entry := suite.latest(models.AuditLogin)
+	assert.Equal(t, http.StatusUnauthorized, entry.Status)
+	assert.Nil(t, entry.UserID)
+	assert.Equal(t, "testuser", entry.Details["username"])
+	assert.NotContains(t, entry.Details, "password")
+
+	w = suite.helper.RequestAs(suite.T(), suite.router, "", http.MethodPost, "/api/v1/auth/login", gin.H{"username": "testuser", "password": "testpassword123"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	entry = suite.latest(models.AuditLogin)
+	assert.Equal(t, http.StatusOK, entry.Status)
+}
+
+func (suite *AuditLogTestSuite) TestTranscriptions() {
+	t := suite.T()
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, err := writer.CreateFormFile("audio", "board-meeting.mp3")
+	require.NoError(t, err)
+	part.Write([]byte("fake audio"))
+	require.NoError(t, writer.Close())
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/transcription/upload", &form)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+suite.helper.TestToken)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var uploaded models.TranscriptionJob
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &uploaded))
+
+	entry := suite.latest(models.AuditUpload)
+	assert.Equal(t, uploaded.ID, entry.TargetID)
+	require.NotNil(t, entry.UserID)
+	assert.Equal(t, suite.helper.TestUser.ID, *entry.UserID)
+	assert.Equal(t, "testuser", entry.Username)
+	assert.Equal(t, "jwt", entry.AuthType)
+	assert.Equal(t, []interface{}{"board-meeting.mp3"}, entry.Details["files"])
+
+	job := suite.completedJob("Budget review")
+	w = suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodGet, "/api/v1/transcription/"+job.ID+"/export?format=markdown", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	entry = suite.latest(models.AuditExport)
+	assert.Equal(t, job.ID, entry.TargetID)
+	assert.Equal(t, "markdown", entry.Details["format"])
+	assert.Equal(t, "/api/v1/transcription/:id/export", entry.Route)
+
+	w = suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodPut, "/api/v1/transcription/"+job.ID+"/title", gin.H{"title": "Budget review (final)"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	entry = suite.latest(models.AuditChange)
+	assert.Equal(t, job.ID, entry.TargetID)
+	assert.Equal(t, http.MethodPut, entry.Method)
+
+	w = suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodDelete, "/api/v1/transcription/"+job.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	entry = suite.latest(models.AuditDelete)
+	assert.Equal(t, job.ID, entry.TargetID)
+	assert.Equal(t, http.StatusOK, entry.Status)
+
+	// Reads that aren't exports aren't recorded
+	before := len(suite.entries(url.Values{"limit": {"1000"}}))
+	require.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodGet, "/api/v1/transcription/list", nil).Code)
+	assert.Len(t, suite.entries(url.Values{"limit": {"1000"}}), before)
+}
+
+func (suite *AuditLogTestSuite) TestShares() {
+	t := suite.T()
+	job := suite.completedJob("Launch review")
+	w := suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodPost, "/api/v1/transcription/"+job.ID+"/shares", gin.H{"include_audio": false})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var link api.ShareLinkResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &link))
+
+	entry := suite.latest(models.AuditShareCreate)
+	assert.Equal(t, link.ID, entry.TargetID, "the share link is the target")
+	assert.Equal(t, job.ID, entry.Details["id"], "of the transcription")
+	assert.Equal(t, false, entry.Details["include_audio"])
+
+	w = suite.helper.RequestAs(suite.T(), suite.router, "", http.MethodGet, link.URL, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	entry = suite.latest(models.AuditShareView)
+	assert.Equal(t, job.ID, entry.TargetID)
+	assert.Nil(t, entry.UserID)
+	assert.Equal(t, "/api/v1/shared/:token", entry.Route)
+	assert.Empty(t, entry.Details, "the token isn't recorded")
+}
+
+func (suite *AuditLogTestSuite) TestQueries() {
+	t := suite.T()
+	suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodPost, "/api/v1/rag/search", gin.H{"query": "What is the budget?"})
+	entry := suite.latest(models.AuditQuery)
+	assert.Equal(t, "What is the budget?", entry.Details["query"])
+	assert.Equal(t, "/api/v1/rag/search", entry.Route)
+}
+
+func (suite *AuditLogTestSuite) TestAPIKeys() {
+	t := suite.T()
+	w := suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodPost, "/api/v1/api-keys/", gin.H{"name": "Backup script"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var key models.APIKey
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &key))
+
+	entry := suite.latest(models.AuditAPIKeyCreate)
+	assert.Equal(t, fmt.Sprint(key.ID), entry.TargetID)
+	assert.Equal(t, "Backup script", entry.Details["name"])
+	assert.NotContains(t, entry.Details, "key", "the new key isn't recorded")
+	assert.Nil(t, entry.APIKeyID)
+
+	// Requests made with a key record which key
+	job := suite.completedJob("Nightly backup")
+	w = suite.helper.RequestAs(suite.T(), suite.router, key.Key, http.MethodPut, "/api/v1/transcription/"+job.ID+"/title", gin.H{"title": "Nightly backup (verified)"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	entry = suite.latest(models.AuditChange)
+	assert.Equal(t, "api_key", entry.AuthType)
+	require.NotNil(t, entry.APIKeyID)
+	assert.Equal(t, key.ID, *entry.APIKeyID)
+	require.NotNil(t, entry.UserID, "the key's owner")
+	assert.Equal(t, suite.helper.TestUser.ID, *entry.UserID)
+
+	w = suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodDelete, fmt.Sprintf("/api/v1/api-keys/%d", key.ID), nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, fmt.Sprint(key.ID), suite.latest(models.AuditAPIKeyRevoke).TargetID)
+}
+
+func (suite *AuditLogTestSuite) TestListAuditLog() {
+	t := suite.T()
+	w := suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodPost, "/api/v1/admin/users", gin.H{"username": "auditee", "password": "password123"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var member models.User
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &member))
+	entry := suite.latest(models.AuditUserCreate)
+	assert.Equal(t, fmt.Sprint(member.ID), entry.TargetID)
+	assert.Equal(t, "auditee", entry.Details["username"])
+	assert.NotContains(t, entry.Details, "password")
+
+	token, err := suite.helper.AuthService.GenerateToken(&member)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, suite.helper.RequestAs(suite.T(), suite.router, token, http.MethodGet, "/api/v1/admin/audit-log", nil).Code)
+	job := suite.completedJob("Members only")
+	suite.helper.RequestAs(suite.T(), suite.router, token, http.MethodDelete, "/api/v1/transcription/"+job.ID, nil)
+
+	entries := suite.entries(url.Values{"user_id": {fmt.Sprint(member.ID)}})
+	require.Len(t, entries, 1)
+	assert.Equal(t, models.AuditDelete, entries[0].Action)
+	assert.Equal(t, http.StatusNotFound, entries[0].Status, "failed attempts are recorded too")
+	assert.Equal(t, "auditee", entries[0].Username)
+
+	entries = suite.entries(url.Values{"target_id": {job.ID}})
+	require.Len(t, entries, 1)
+	assert.Equal(t, member.ID, *entries[0].UserID)
+
+	assert.Empty(t, suite.entries(url.Values{"since": {time.Now().Add(time.Hour).Format(time.RFC3339)}}))
+	assert.Equal(t, http.StatusBadRequest, suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodGet, "/api/v1/admin/audit-log?user_id=me", nil).Code)
+
+	w = suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodGet, "/api/v1/admin/audit-log?limit=2", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Entries       []models.AuditLog `json:"entries"`
+		RetentionDays int               `json:"retention_days"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Entries, 2)
+	assert.Equal(t, 30, resp.RetentionDays)
+	assert.False(t, resp.Entries[0].CreatedAt.Before(resp.Entries[1].CreatedAt), "newest first")
+}
+
+func (suite *AuditLogTestSuite) TestRetention() {
+	t := suite.T()
+	suite.helper.RequestAs(suite.T(), suite.router, "", http.MethodPost, "/api/v1/auth/login", gin.H{"username": "nobody", "password": "nothing"})
+	var count int64
+	require.NoError(t, suite.helper.DB.Model(&models.AuditLog{}).Where("id = ?", suite.stale.ID).Count(&count).Error)
+	assert.Zero(t, count, "entries past the retention period are deleted")
+	assert.Empty(t, suite.entries(url.Values{"target_id": {"long-gone"}, "since": {"2160h"}}))
+}
+
+func TestAuditLogTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditLogTestSuite))
+}
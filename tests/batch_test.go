@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/batch"
+	"scriberr/internal/embeddings"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/vectordb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type BatchTestSuite struct {
+	suite.Suite
+	helper  *TestHelper
+	batches *batch.Service
+	store   *vectordb.MemoryStore
+	rag     *rag.RAGService
+	router  *gin.Engine
+}
+
+func (suite *BatchTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "batch_test.db")
+	suite.store = vectordb.NewMemoryStore()
+	suite.rag = rag.NewRAGService(suite.store, embeddings.NewFakeEmbeddingService(), llm.NewFakeService())
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, nil, nil, nil, suite.rag)
+	suite.batches = batch.NewService()
+	handler.SetBatchService(suite.batches)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *BatchTestSuite) TearDownSuite() {
+	suite.batches.Stop()
+	suite.helper.Cleanup()
+}
+
+// run starts a batch operation and waits for it to finish
+func (suite *BatchTestSuite) run(body interface{}) models.BatchOperation {
+	t := suite.T()
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/transcription/batch", body)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var op models.BatchOperation
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &op))
+	assert.Equal(t, models.BatchRunning, op.Status)
+
+	require.Eventually(t, func() bool { return !suite.batches.IsRunning(op.ID) }, 5*time.Second, 10*time.Millisecond)
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/transcription/batch/"+op.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &op))
+	return op
+}
+
+// completedJob creates a completed transcription with a transcript
+func (suite *BatchTestSuite) completedJob(title string, tags ...string) *models.TranscriptionJob {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), title)
+	job.Status = models.StatusCompleted
+	job.Transcript = stringPtr(`{"text": "` + title + ` notes", "segments": []}`)
+	require.NoError(suite.T(), suite.helper.DB.Save(job).Error)
+	if len(tags) > 0 {
+		w := suite.helper.Request(suite.T(), suite.router, http.MethodPut, "/api/v1/transcription/"+job.ID+"/tags", gin.H{"tags": tags})
+		require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+	}
+	return job
+}
+
+func (suite *BatchTestSuite) reload(job *models.TranscriptionJob) *models.TranscriptionJob {
+	var current models.TranscriptionJob
+	require.NoError(suite.T(), suite.helper.DB.First(&current, "id = ?", job.ID).Error)
+	return &current
+}
+
+func (suite *BatchTestSuite) TestRetagByIDs() {
+	t := suite.T()
+	first := suite.completedJob("Retag one", "draft", "Weekly")
+	second := suite.completedJob("Retag two")
+
+	op := suite.run(gin.H{
+		"action": api.BatchRetag,
+		"ids":    []string{first.ID, second.ID, first.ID, "missing"},
+		"params": gin.H{"add_tags": []string{"Reviewed"}, "remove_tags": []string{"DRAFT"}},
+	})
+	assert.Equal(t, models.BatchCompleted, op.Status)
+	assert.Equal(t, 3, op.Total, "repeated IDs are done once")
+	assert.Equal(t, 2, op.Succeeded)
+	assert.Equal(t, 1, op.Failed)
+	require.Len(t, op.Results, 3)
+	assert.Equal(t, models.BatchItemResult{TranscriptionID: "missing", Status: models.BatchItemFailed, Message: "Transcription not found"}, op.Results[0])
+	require.NotNil(t, op.CompletedAt)
+
+	assert.ElementsMatch(t, []string{"Weekly", "Reviewed"}, []string(suite.reload(first).Tags))
+	assert.ElementsMatch(t, []string{"Reviewed"}, []string(suite.reload(second).Tags))
+
+	// Operations are listed without their results
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/transcription/batch", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list struct {
+		Operations []models.BatchOperation `json:"operations"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.NotEmpty(t, list.Operations)
+	assert.Empty(t, list.Operations[0].Results)
+}
+
+func (suite *BatchTestSuite) TestMoveAndDeleteByFilter() {
+	t := suite.T()
+	project := models.Project{Name: "Batch archive"}
+	require.NoError(t, suite.helper.DB.Create(&project).Error)
+	kept := suite.completedJob("Filter held", "batch-filter")
+	moved := suite.completedJob("Filter free", "batch-filter")
+	other := suite.completedJob("Filter other")
+	require.NoError(t, suite.helper.DB.Model(kept).Update("legal_hold", true).Error)
+
+	op := suite.run(gin.H{
+		"action": api.BatchMove,
+		"filter": gin.H{"tags": []string{"batch-filter"}},
+		"params": gin.H{"project_id": project.ID},
+	})
+	assert.Equal(t, 2, op.Succeeded)
+	require.NotNil(t, suite.reload(moved).ProjectID)
+	assert.Equal(t, project.ID, *suite.reload(moved).ProjectID)
+	assert.Nil(t, suite.reload(other).ProjectID)
+
+	// A transcription under legal hold is skipped, not deleted
+	op = suite.run(gin.H{"action": api.BatchDelete, "filter": gin.H{"project_id": project.ID}})
+	assert.Equal(t, 2, op.Total)
+	assert.Equal(t, 1, op.Succeeded)
+	assert.Equal(t, 1, op.Skipped)
+	var count int64
+	require.NoError(t, suite.helper.DB.Model(&models.TranscriptionJob{}).Where("id IN ?", []string{kept.ID, moved.ID}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+	assert.True(t, suite.reload(kept).LegalHold)
+}
+
+func (suite *BatchTestSuite) TestReindex() {
+	t := suite.T()
+	job := suite.completedJob("Reindex me")
+	empty := suite.helper.CreateTestTranscriptionJob(t, "No transcript")
+
+	op := suite.run(gin.H{"action": api.BatchReindex, "ids": []string{job.ID, empty.ID}})
+	assert.Equal(t, 1, op.Succeeded)
+	assert.Equal(t, 1, op.Skipped)
+	indexed, err := suite.rag.IsIndexed(job.ID)
+	require.NoError(t, err)
+	assert.True(t, indexed)
+}
+
+func (suite *BatchTestSuite) TestInvalidRequests() {
+	job := suite.completedJob("Invalid batch")
+	for name, body := range map[string]gin.H{
+		"unknown action":    {"action": "shred", "ids": []string{job.ID}},
+		"ids and filter":    {"action": api.BatchReindex, "ids": []string{job.ID}, "filter": gin.H{"text": "x"}},
+		"neither":           {"action": api.BatchReindex},
+		"empty filter":      {"action": api.BatchReindex, "filter": gin.H{}},
+		"no match":          {"action": api.BatchReindex, "filter": gin.H{"text": "nothing matches this"}},
+		"retag without":     {"action": api.BatchRetag, "ids": []string{job.ID}},
+		"retag mixed":       {"action": api.BatchRetag, "ids": []string{job.ID}, "params": gin.H{"tags": []string{"a"}, "add_tags": []string{"b"}}},
+		"unknown project":   {"action": api.BatchMove, "ids": []string{job.ID}, "params": gin.H{"project_id": "missing"}},
+		"bad summary style": {"action": api.BatchResummarize, "ids": []string{job.ID}, "params": gin.H{"format": "haiku"}},
+	} {
+		w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/transcription/batch", body)
+		assert.Equal(suite.T(), http.StatusBadRequest, w.Code, name+": "+w.Body.String())
+	}
+
+	assert.Equal(suite.T(), http.StatusNotFound, suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/transcription/batch/missing", nil).Code)
+	assert.Equal(suite.T(), http.StatusNotFound, suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/transcription/batch/missing/cancel", nil).Code)
+}
+
+func TestBatchTestSuite(t *testing.T) {
+	suite.Run(t, new(BatchTestSuite))
+}
@@ -0,0 +1,247 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/companion"
+	"scriberr/internal/embeddings"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/quota"
+	"scriberr/internal/rag"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/internal/vectordb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// chunkTranscriber returns one segment per chunk, holding the chunk's file name
+type chunkTranscriber struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (t *chunkTranscriber) Transcribe(ctx context.Context, audio []byte, filename string, params models.WhisperXParams) ([]interfaces.TranscriptSegment, error) {
+	t.mu.Lock()
+	t.calls = append(t.calls, filename)
+	t.mu.Unlock()
+	if filename == "broken.wav" {
+		return nil, fmt.Errorf("decoder error")
+	}
+	return []interfaces.TranscriptSegment{{Start: 0, End: float64(len(audio)), Text: "chunk " + filename}}, nil
+}
+
+type CompanionTestSuite struct {
+	suite.Suite
+	helper      *TestHelper
+	transcriber *chunkTranscriber
+	store       *vectordb.MemoryStore
+	service     *companion.Service
+	router      *gin.Engine
+}
+
+func (suite *CompanionTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "companion_test.db")
+}
+
+func (suite *CompanionTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *CompanionTestSuite) SetupTest() {
+	fakeLLM := llm.NewFakeService()
+	suite.store = vectordb.NewMemoryStore()
+	suite.transcriber = &chunkTranscriber{}
+	ragService := rag.NewRAGService(suite.store, embeddings.NewFakeEmbeddingService(), fakeLLM)
+	suite.service = companion.NewService(ragService, suite.transcriber, fakeLLM, llm.FakeModel, fakeLLM, llm.FakeModel)
+
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, nil, nil, nil, nil)
+	handler.SetCompanionService(suite.service)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+// meetingSegments returns n segments of two alternating speakers, ten seconds each
+func meetingSegments(from, n int) []interfaces.TranscriptSegment {
+	segments := make([]interfaces.TranscriptSegment, n)
+	for i := range segments {
+		speaker := fmt.Sprintf("SPEAKER_%02d", (from+i)%2)
+		segments[i] = interfaces.TranscriptSegment{
+			Start:   float64((from + i) * 10),
+			End:     float64((from+i)*10 + 9),
+			Speaker: &speaker,
+			Text:    fmt.Sprintf("Point %d: the launch budget needs another review before the quarterly planning meeting.", from+i),
+		}
+	}
+	return segments
+}
+
+// waitForStatus polls a session until it reaches status
+func (suite *CompanionTestSuite) waitForStatus(id string, userID *uint, status string) *companion.Session {
+	var session *companion.Session
+	require.Eventually(suite.T(), func() bool {
+		var err error
+		session, err = suite.service.Get(id, userID, 0)
+		require.NoError(suite.T(), err)
+		return session.Status == status
+	}, 5*time.Second, 10*time.Millisecond)
+	return session
+}
+
+func (suite *CompanionTestSuite) TestSegmentsNotesAndQuestions() {
+	userID := suite.helper.TestUser.ID
+	session := suite.service.Create(&userID, "Planning", models.WhisperXParams{})
+	assert.Equal(suite.T(), companion.StatusActive, session.Status)
+
+	added, err := suite.service.AddSegments(session.ID, &userID, meetingSegments(0, 4))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 4, added.Cursor)
+	assert.Len(suite.T(), added.Segments, 4)
+
+	_, err = suite.service.Get(session.ID, nil, 0)
+	assert.ErrorIs(suite.T(), err, companion.ErrNotFound, "sessions are private to their owner")
+
+	summarized, err := suite.service.Summarize(context.Background(), session.ID, &userID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 4, summarized.SummarizedSegments)
+	assert.NotEmpty(suite.T(), summarized.Summary)
+	require.NotNil(suite.T(), summarized.SummaryUpdatedAt)
+
+	_, err = suite.service.AddSegments(session.ID, &userID, meetingSegments(4, 2))
+	require.NoError(suite.T(), err)
+	polled, err := suite.service.Get(session.ID, &userID, 4)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 6, polled.Cursor)
+	require.Len(suite.T(), polled.Segments, 2, "only the segments after the cursor are returned")
+	assert.Equal(suite.T(), 40.0, polled.Segments[0].Start)
+	assert.Equal(suite.T(), 59.0, polled.Duration)
+	assert.Equal(suite.T(), 4, polled.SummarizedSegments)
+
+	// Asking indexes what was just said into the session's own collection
+	answer, err := suite.service.Ask(context.Background(), session.ID, &userID, "What about the launch budget?", false)
+	require.NoError(suite.T(), err)
+	assert.False(suite.T(), answer.NoRelevantContext)
+	assert.NotEmpty(suite.T(), answer.Answer)
+	require.NotEmpty(suite.T(), answer.Moments)
+	assert.Contains(suite.T(), answer.Moments[0].Text, "launch budget")
+	assert.Empty(suite.T(), answer.Sources)
+	count, err := suite.store.CountDocuments(rag.LiveCollectionName(session.ID), nil)
+	require.NoError(suite.T(), err)
+	assert.Positive(suite.T(), count)
+	userCount, err := suite.store.CountDocuments(rag.CollectionName(&userID), nil)
+	if err == nil {
+		assert.Zero(suite.T(), userCount, "the partial transcript stays out of the user's collection")
+	}
+
+	ended, err := suite.service.End(session.ID, &userID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), companion.StatusFinishing, ended.Status)
+	_, err = suite.service.AddSegments(session.ID, &userID, meetingSegments(6, 1))
+	assert.ErrorIs(suite.T(), err, companion.ErrEnded)
+
+	final := suite.waitForStatus(session.ID, &userID, companion.StatusEnded)
+	assert.Equal(suite.T(), 6, final.SummarizedSegments, "ending folds the remaining segments into the notes")
+	assert.Len(suite.T(), final.Segments, 6)
+	require.NotNil(suite.T(), final.EndedAt)
+	count, err = suite.store.CountDocuments(rag.LiveCollectionName(session.ID), nil)
+	require.NoError(suite.T(), err)
+	assert.Zero(suite.T(), count, "the temporary index is dropped")
+
+	_, err = suite.service.Ask(context.Background(), session.ID, &userID, "What about the launch budget?", false)
+	assert.ErrorIs(suite.T(), err, companion.ErrEnded)
+}
+
+func (suite *CompanionTestSuite) TestAudioChunks() {
+	userID := suite.helper.TestUser.ID
+	period, _ := quota.Period(time.Now())
+	before, err := quota.Used(userID, period)
+	require.NoError(suite.T(), err)
+	session := suite.service.Create(&userID, "", models.WhisperXParams{})
+
+	offset := 100.0
+	for _, chunk := range []struct {
+		name   string
+		offset *float64
+	}{{"first.wav", nil}, {"broken.wav", nil}, {"second.wav", nil}, {"third.wav", &offset}} {
+		_, err := suite.service.AddAudio(session.ID, &userID, make([]byte, 5), chunk.name, chunk.offset)
+		require.NoError(suite.T(), err)
+	}
+
+	var polled *companion.Session
+	require.Eventually(suite.T(), func() bool {
+		var err error
+		polled, err = suite.service.Get(session.ID, &userID, 0)
+		require.NoError(suite.T(), err)
+		return polled.PendingChunks == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Len(suite.T(), polled.Segments, 3)
+	assert.Equal(suite.T(), "chunk first.wav", polled.Segments[0].Text)
+	assert.Equal(suite.T(), 0.0, polled.Segments[0].Start)
+	assert.Equal(suite.T(), "chunk second.wav", polled.Segments[1].Text)
+	assert.Equal(suite.T(), 5.0, polled.Segments[1].Start, "chunks without an offset follow the transcript so far")
+	assert.Equal(suite.T(), 100.0, polled.Segments[2].Start)
+	assert.Contains(suite.T(), polled.LastError, "decoder error")
+	assert.Equal(suite.T(), []string{"first.wav", "broken.wav", "second.wav", "third.wav"}, suite.transcriber.calls)
+
+	// The chunks transcribed count against the user's audio quota
+	used, err := quota.Used(userID, period)
+	require.NoError(suite.T(), err)
+	assert.InDelta(suite.T(), 15.0/60, used[models.QuotaAudioMinutes]-before[models.QuotaAudioMinutes], 0.001)
+
+	require.NoError(suite.T(), suite.service.Delete(session.ID, &userID))
+	_, err = suite.service.Get(session.ID, &userID, 0)
+	assert.ErrorIs(suite.T(), err, companion.ErrNotFound)
+}
+
+func (suite *CompanionTestSuite) TestAPI() {
+	w := suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/companion/sessions", map[string]string{"title": "Standup"})
+	require.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+	var session companion.Session
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &session))
+	assert.Equal(suite.T(), "Standup", session.Title)
+	base := "/api/v1/companion/sessions/" + session.ID
+
+	w = suite.helper.Request(suite.T(), suite.router, "POST", base+"/segments", map[string]interface{}{"segments": meetingSegments(0, 3)})
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+	w = suite.helper.Request(suite.T(), suite.router, "POST", base+"/segments", map[string]interface{}{"segments": []map[string]interface{}{{"start": 5, "end": 1, "text": "backwards"}}})
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	w = suite.helper.Request(suite.T(), suite.router, "GET", base+"?since=2", nil)
+	require.Equal(suite.T(), http.StatusOK, w.Code)
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &session))
+	assert.Equal(suite.T(), 3, session.Cursor)
+	assert.Len(suite.T(), session.Segments, 1)
+
+	w = suite.helper.Request(suite.T(), suite.router, "POST", base+"/ask", map[string]interface{}{"question": "What needs review?", "include_library": true})
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+	var answer companion.Answer
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &answer))
+	assert.NotEmpty(suite.T(), answer.Moments)
+
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/companion/sessions", nil)
+	require.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.True(suite.T(), strings.Contains(w.Body.String(), session.ID))
+
+	w = suite.helper.Request(suite.T(), suite.router, "POST", base+"/end", nil)
+	assert.Equal(suite.T(), http.StatusAccepted, w.Code)
+	w = suite.helper.Request(suite.T(), suite.router, "POST", base+"/segments", map[string]interface{}{"segments": meetingSegments(3, 1)})
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/companion/sessions/missing", nil)
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func TestCompanionTestSuite(t *testing.T) {
+	suite.Run(t, new(CompanionTestSuite))
+}
@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/duplicates"
+	"scriberr/internal/embeddings"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/vectordb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// meeting is the transcript of one meeting, as heard by a recorder
+const meeting = "Good morning everyone. We agreed to ship the release on Friday. Bob owns the release notes " +
+	"and Alice will check the migration scripts before Thursday. The budget review moves to next week " +
+	"because finance needs more time. Any questions before we wrap up? No? Then thanks everyone."
+
+type DuplicateTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	rag    *rag.RAGService
+	router *gin.Engine
+}
+
+func (suite *DuplicateTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "duplicate_test.db")
+	suite.rag = rag.NewRAGService(vectordb.NewMemoryStore(), embeddings.NewFakeEmbeddingService(), llm.NewFakeService())
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, nil, nil, nil, suite.rag)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *DuplicateTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *DuplicateTestSuite) SetupTest() {
+	// Each test starts from an empty library
+	var ids []string
+	require.NoError(suite.T(), suite.helper.DB.Model(&models.TranscriptionJob{}).Pluck("id", &ids).Error)
+	for _, id := range ids {
+		require.NoError(suite.T(), suite.rag.DeleteTranscription(suite.T().Context(), id))
+	}
+	suite.helper.DB.Where("1 = 1").Delete(&models.TranscriptionJob{})
+	suite.helper.DB.Where("1 = 1").Delete(&models.DuplicateCandidate{})
+}
+
+// indexed creates a completed transcription with the given transcript and indexes it
+func (suite *DuplicateTestSuite) indexed(title, text string, tags ...string) *models.TranscriptionJob {
+	t := suite.T()
+	data, err := json.Marshal(map[string]interface{}{"text": text, "segments": []interface{}{}})
+	require.NoError(t, err)
+	job := suite.helper.CreateTestTranscriptionJob(t, title)
+	job.Status = models.StatusCompleted
+	job.Transcript = stringPtr(string(data))
+	job.Tags = tags
+	require.NoError(t, suite.helper.DB.Save(job).Error)
+	require.NoError(t, suite.rag.StoreSummary(context.Background(), job.ID, "", text))
+	return job
+}
+
+func (suite *DuplicateTestSuite) scan() duplicates.Report {
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/duplicates/scan", nil)
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+	var report duplicates.Report
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &report))
+	return report
+}
+
+func (suite *DuplicateTestSuite) list(status string) []api.DuplicatePair {
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/duplicates?status="+status, nil)
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Duplicates []api.DuplicatePair `json:"duplicates"`
+	}
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Duplicates
+}
+
+func (suite *DuplicateTestSuite) isIndexed(jobID string) bool {
+	indexed, err := suite.rag.IsIndexed(jobID)
+	require.NoError(suite.T(), err)
+	return indexed
+}
+
+func (suite *DuplicateTestSuite) TestScanAndMerge() {
+	t := suite.T()
+	first := suite.indexed("Standup (Alice's phone)", meeting, "weekly")
+	// The same meeting recorded by someone else, heard slightly differently
+	second := suite.indexed("Standup (Bob's laptop)", strings.Replace(meeting, "Good morning everyone.", "Morning all.", 1), "release")
+	suite.indexed("Podcast", "A long talk about gardening, tomatoes, compost and the weather this spring in the north.")
+
+	report := suite.scan()
+	assert.Equal(t, 3, report.Compared)
+	assert.Equal(t, 1, report.Found)
+	assert.Equal(t, 1, report.New)
+
+	pending := suite.list(models.DuplicatePending)
+	require.Len(t, pending, 1)
+	pair := pending[0]
+	assert.Equal(t, first.ID, pair.FirstID, "the older transcription comes first")
+	assert.Equal(t, second.ID, pair.SecondID)
+	assert.GreaterOrEqual(t, pair.Similarity, duplicates.DefaultMinSimilarity)
+	require.NotNil(t, pair.SecondTitle)
+	assert.Equal(t, "Standup (Bob's laptop)", *pair.SecondTitle)
+
+	// Scanning again finds nothing new
+	assert.Equal(t, 0, suite.scan().New)
+
+	path := "/api/v1/duplicates/" + strconv.FormatUint(uint64(pair.ID), 10)
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, path+"/merge", gin.H{"keep": "someone-else"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, path+"/merge", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var merged models.DuplicateCandidate
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &merged))
+	assert.Equal(t, models.DuplicateMerged, merged.Status)
+	require.NotNil(t, merged.KeptID)
+	assert.Equal(t, first.ID, *merged.KeptID)
+
+	var kept, duplicate models.TranscriptionJob
+	require.NoError(t, suite.helper.DB.First(&kept, "id = ?", first.ID).Error)
+	require.NoError(t, suite.helper.DB.First(&duplicate, "id = ?", second.ID).Error)
+	assert.ElementsMatch(t, []string{"weekly", "release"}, []string(kept.Tags), "the kept transcription takes the other's tags")
+	require.NotNil(t, duplicate.DuplicateOf)
+	assert.Equal(t, first.ID, *duplicate.DuplicateOf)
+	assert.False(t, suite.isIndexed(second.ID), "the duplicate leaves RAG")
+
+	// Indexing the duplicate again is refused, and backfills don't count it as missing
+	require.NoError(t, suite.rag.StoreSummary(context.Background(), second.ID, "", meeting))
+	assert.False(t, suite.isIndexed(second.ID))
+	missing, err := suite.rag.FindMissing(context.Background())
+	require.NoError(t, err)
+	assert.NotContains(t, missing, second.ID)
+	assert.Empty(t, suite.list(models.DuplicatePending))
+	assert.Equal(t, http.StatusConflict, suite.helper.Request(suite.T(), suite.router, http.MethodPost, path+"/merge", nil).Code)
+
+	// Reopening brings the duplicate back into RAG
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, path+"/reopen", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, suite.helper.DB.First(&duplicate, "id = ?", second.ID).Error)
+	assert.Nil(t, duplicate.DuplicateOf)
+	assert.True(t, suite.isIndexed(second.ID))
+	assert.Len(t, suite.list(models.DuplicatePending), 1)
+
+	// Merging with delete removes the other transcription and its pairs
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, path+"/merge", gin.H{"keep": second.ID, "delete": true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var count int64
+	require.NoError(t, suite.helper.DB.Model(&models.TranscriptionJob{}).Where("id = ?", first.ID).Count(&count).Error)
+	assert.Zero(t, count)
+	assert.Empty(t, suite.list("all"))
+}
+
+func (suite *DuplicateTestSuite) TestIgnore() {
+	t := suite.T()
+	suite.indexed("Standup", meeting)
+	suite.indexed("Standup again", meeting)
+	require.Equal(t, 1, suite.scan().New)
+	pair := suite.list(models.DuplicatePending)[0]
+	path := "/api/v1/duplicates/" + strconv.FormatUint(uint64(pair.ID), 10)
+
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, path+"/ignore", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusConflict, suite.helper.Request(suite.T(), suite.router, http.MethodPost, path+"/ignore", nil).Code)
+
+	// An ignored pair isn't flagged again
+	assert.Equal(t, 0, suite.scan().New)
+	assert.Empty(t, suite.list(models.DuplicatePending))
+	assert.Len(t, suite.list(models.DuplicateIgnored), 1)
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, path+"/reopen", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, suite.list(models.DuplicatePending), 1)
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/duplicates/scan", gin.H{"min_similarity": 1, "min_overlap": 1.5})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	assert.Equal(t, http.StatusBadRequest, suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/duplicates?status=maybe", nil).Code)
+	assert.Equal(t, http.StatusNotFound, suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/duplicates/999/ignore", nil).Code)
+}
+
+func TestDuplicateTestSuite(t *testing.T) {
+	suite.Run(t, new(DuplicateTestSuite))
+}
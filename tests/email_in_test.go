@@ -0,0 +1,251 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/database"
+	"scriberr/internal/mailin"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeMailbox holds emails by UID until they are marked seen
+type fakeMailbox struct {
+	messages map[uint32]string
+	seen     map[uint32]bool
+}
+
+func (m *fakeMailbox) Unseen() ([]uint32, error) {
+	var uids []uint32
+	for uid := uint32(1); uid <= uint32(len(m.messages)); uid++ {
+		if !m.seen[uid] {
+			uids = append(uids, uid)
+		}
+	}
+	return uids, nil
+}
+
+func (m *fakeMailbox) Fetch(uid uint32) ([]byte, error) {
+	return []byte(m.messages[uid]), nil
+}
+
+func (m *fakeMailbox) MarkSeen(uid uint32) error {
+	m.seen[uid] = true
+	return nil
+}
+
+func (m *fakeMailbox) Close() error { return nil }
+
+// add delivers an email, returning its UID
+func (m *fakeMailbox) add(raw string) uint32 {
+	uid := uint32(len(m.messages) + 1)
+	m.messages[uid] = strings.ReplaceAll(raw, "\n", "\r\n")
+	return uid
+}
+
+type fakeSender struct {
+	replies []mailin.Reply
+}
+
+func (s *fakeSender) Send(reply mailin.Reply) error {
+	s.replies = append(s.replies, reply)
+	return nil
+}
+
+// voiceMemoEmail builds an email with an audio attachment for each file name
+func voiceMemoEmail(from, messageID, subject string, headers string, filenames ...string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\nSubject: %s\nMessage-ID: %s\n%sContent-Type: multipart/mixed; boundary=\"b\"\n\n", from, subject, messageID, headers)
+	b.WriteString("--b\nContent-Type: text/plain\n\nSee attached.\n")
+	for _, name := range filenames {
+		fmt.Fprintf(&b, "--b\nContent-Type: audio/mp4\nContent-Disposition: attachment; filename=%q\nContent-Transfer-Encoding: base64\n\nSUQzIGF1ZGlv\n", name)
+	}
+	b.WriteString("--b--\n")
+	return b.String()
+}
+
+type EmailInTestSuite struct {
+	suite.Suite
+	helper  *TestHelper
+	queue   *queue.TaskQueue
+	router  *gin.Engine
+	mailbox *fakeMailbox
+	sender  *fakeSender
+}
+
+func (suite *EmailInTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "email_in_test.db")
+	suite.helper.Config.EmailInOwner = suite.helper.TestUser.Username
+	suite.queue = queue.NewTaskQueue(1, &MockJobProcessor{})
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, suite.queue, nil, nil, nil)
+	suite.mailbox = &fakeMailbox{messages: map[uint32]string{}, seen: map[uint32]bool{}}
+	suite.sender = &fakeSender{}
+	service := mailin.NewService(func() (mailin.Mailbox, error) { return suite.mailbox, nil },
+		[]string{"@field.example.com", "boss@example.com"}, "https://scriberr.example.com")
+	service.SetSender(suite.sender)
+	handler.SetEmailInService(service)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *EmailInTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *EmailInTestSuite) SetupTest() {
+	database.DB.Where("1 = 1").Delete(&models.InboundEmail{})
+	database.DB.Where("1 = 1").Delete(&models.WorkflowRun{})
+	database.DB.Where("1 = 1").Delete(&models.TranscriptionJob{})
+	suite.sender.replies = nil
+}
+
+// check checks the mailbox through the API
+func (suite *EmailInTestSuite) check() mailin.CheckResult {
+	w := suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/admin/email-in/check", nil)
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+	var result mailin.CheckResult
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &result))
+	return result
+}
+
+func (suite *EmailInTestSuite) email(messageID string) models.InboundEmail {
+	var email models.InboundEmail
+	require.NoError(suite.T(), database.DB.Where("message_id = ?", messageID).First(&email).Error)
+	return email
+}
+
+func (suite *EmailInTestSuite) complete(jobID, summary string) {
+	require.NoError(suite.T(), database.DB.Model(&models.TranscriptionJob{}).Where("id = ?", jobID).
+		Updates(map[string]interface{}{"status": models.StatusCompleted, "summary": summary}).Error)
+	require.NoError(suite.T(), database.DB.Create(&models.WorkflowRun{TranscriptionID: jobID, Status: models.WorkflowCompleted}).Error)
+}
+
+func (suite *EmailInTestSuite) TestVoiceMemoIsQueuedAndRepliedTo() {
+	t := suite.T()
+	single := suite.mailbox.add(voiceMemoEmail("Dana <dana@field.example.com>", "<one@field.example.com>", "Site visit", "", "memo.m4a"))
+	suite.mailbox.add(voiceMemoEmail("boss@example.com", "<two@example.com>", "Standup", "", "monday.m4a", "tuesday.m4a"))
+	stranger := suite.mailbox.add(voiceMemoEmail("someone@elsewhere.example.org", "<three@elsewhere.example.org>", "Hi", "", "spam.m4a"))
+
+	result := suite.check()
+	assert.Equal(t, 2, result.Received, "the stranger's email is ignored")
+	assert.Equal(t, 3, result.Transcriptions)
+	assert.Equal(t, 0, result.Replied)
+	assert.True(t, suite.mailbox.seen[single])
+	assert.True(t, suite.mailbox.seen[stranger], "ignored emails aren't read again")
+
+	email := suite.email("<one@field.example.com>")
+	assert.Equal(t, models.InboundEmailProcessing, email.Status)
+	require.Len(t, email.TranscriptionIDs, 1)
+	var job models.TranscriptionJob
+	require.NoError(t, database.DB.First(&job, "id = ?", email.TranscriptionIDs[0]).Error)
+	assert.Equal(t, "Site visit", *job.Title)
+	assert.Equal(t, models.ContentVoiceMemo, job.ContentType)
+	require.NotNil(t, job.UserID)
+	assert.Equal(t, suite.helper.TestUser.ID, *job.UserID)
+	require.NotNil(t, job.Source)
+	assert.Equal(t, "mailto:dana@field.example.com", job.Source.URL)
+
+	standup := suite.email("<two@example.com>")
+	require.Len(t, standup.TranscriptionIDs, 2)
+	var titles []string
+	database.DB.Model(&models.TranscriptionJob{}).Where("id IN ?", standup.TranscriptionIDs).Order("title").Pluck("title", &titles)
+	assert.Equal(t, []string{"Standup: monday", "Standup: tuesday"}, titles)
+
+	// Not done yet, so no reply
+	assert.Equal(t, 0, suite.check().Replied)
+
+	suite.complete(email.TranscriptionIDs[0], "Fence needs repair.")
+	database.DB.Model(&models.TranscriptionJob{}).Where("id = ?", standup.TranscriptionIDs[0]).Updates(map[string]interface{}{
+		"status": models.StatusFailed, "error_message": "audio is silent"})
+	require.NoError(t, database.DB.Create(&models.WorkflowRun{TranscriptionID: standup.TranscriptionIDs[1], Status: models.WorkflowRunning}).Error)
+	database.DB.Model(&models.TranscriptionJob{}).Where("id = ?", standup.TranscriptionIDs[1]).Update("status", models.StatusCompleted)
+
+	result = suite.check()
+	assert.Equal(t, 0, result.Received)
+	assert.Equal(t, 1, result.Replied, "the standup waits for its running workflow")
+	require.Len(t, suite.sender.replies, 1)
+	reply := suite.sender.replies[0]
+	assert.Equal(t, "dana@field.example.com", reply.To)
+	assert.Equal(t, "Re: Site visit", reply.Subject)
+	assert.Equal(t, "<one@field.example.com>", reply.InReplyTo)
+	assert.Contains(t, reply.Body, "== Site visit ==")
+	assert.Contains(t, reply.Body, "Fence needs repair.")
+	assert.Contains(t, reply.Body, "https://scriberr.example.com/")
+	assert.Contains(t, reply.Body, email.TranscriptionIDs[0])
+	email = suite.email("<one@field.example.com>")
+	assert.Equal(t, models.InboundEmailReplied, email.Status)
+	assert.NotNil(t, email.RepliedAt)
+
+	database.DB.Model(&models.WorkflowRun{}).Where("transcription_id = ?", standup.TranscriptionIDs[1]).Update("status", models.WorkflowCompleted)
+	assert.Equal(t, 1, suite.check().Replied)
+	require.Len(t, suite.sender.replies, 2)
+	assert.Contains(t, suite.sender.replies[1].Body, "Transcription failed: audio is silent")
+	assert.Contains(t, suite.sender.replies[1].Body, "== Standup: tuesday ==")
+}
+
+func (suite *EmailInTestSuite) TestEmailWithoutAudioIsAnswered() {
+	t := suite.T()
+	suite.mailbox.add("From: dana@field.example.com\nSubject: Forgot the file\nMessage-ID: <empty@field.example.com>\n\nOops\n")
+
+	result := suite.check()
+	assert.Equal(t, 1, result.Received)
+	assert.Equal(t, 1, result.Replied)
+	require.Len(t, suite.sender.replies, 1)
+	assert.Contains(t, suite.sender.replies[0].Body, "No voice memos could be transcribed")
+
+	// The same email delivered again isn't taken twice
+	suite.mailbox.add("From: dana@field.example.com\nSubject: Forgot the file\nMessage-ID: <empty@field.example.com>\n\nOops\n")
+	assert.Equal(t, 0, suite.check().Received)
+}
+
+func (suite *EmailInTestSuite) TestAutomaticEmailIsNeverRepliedTo() {
+	t := suite.T()
+	suite.mailbox.add(voiceMemoEmail("voicemail@field.example.com", "<auto@field.example.com>", "Voicemail", "Auto-Submitted: auto-generated\n", "vm.m4a"))
+	suite.check()
+
+	email := suite.email("<auto@field.example.com>")
+	assert.True(t, email.Automatic)
+	require.Len(t, email.TranscriptionIDs, 1)
+	suite.complete(email.TranscriptionIDs[0], "Call back.")
+
+	assert.Equal(t, 0, suite.check().Replied)
+	assert.Empty(t, suite.sender.replies)
+	assert.Equal(t, models.InboundEmailCompleted, suite.email("<auto@field.example.com>").Status)
+}
+
+func (suite *EmailInTestSuite) TestListInboundEmails() {
+	t := suite.T()
+	suite.mailbox.add("From: dana@field.example.com\nSubject: A\nMessage-ID: <a@field.example.com>\n\nNo audio\n")
+	suite.mailbox.add(voiceMemoEmail("dana@field.example.com", "<b@field.example.com>", "B", "", "b.m4a"))
+	suite.check()
+
+	w := suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/admin/email-in?status=processing", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response struct {
+		Emails     []models.InboundEmail `json:"emails"`
+		Pagination struct {
+			Total int64 `json:"total"`
+		} `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(1), response.Pagination.Total)
+	require.Len(t, response.Emails, 1)
+	assert.Equal(t, "B", response.Emails[0].Subject)
+
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/admin/email-in", nil)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(2), response.Pagination.Total)
+}
+
+func TestEmailInTestSuite(t *testing.T) {
+	suite.Run(t, new(EmailInTestSuite))
+}
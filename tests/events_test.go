@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/database"
+	"scriberr/internal/events"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type EventsTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	router *gin.Engine
+}
+
+type pollResponse struct {
+	Events     []models.Event `json:"events"`
+	NextCursor uint           `json:"next_cursor"`
+	HasMore    bool           `json:"has_more"`
+}
+
+func (suite *EventsTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "events_test.db")
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, nil, nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *EventsTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *EventsTestSuite) SetupTest() {
+	require.NoError(suite.T(), database.DB.Where("1 = 1").Delete(&models.Event{}).Error)
+}
+
+func (suite *EventsTestSuite) poll(query string) (int, pollResponse) {
+	w := suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/events/poll"+query, nil)
+	var resp pollResponse
+	if w.Code == http.StatusOK {
+		require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &resp))
+	}
+	return w.Code, resp
+}
+
+func (suite *EventsTestSuite) TestJobCreationIsRecorded() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Board meeting")
+	require.NoError(suite.T(), database.DB.Create(&models.TranscriptionJob{
+		ID:        "track_" + job.ID + "_0",
+		Status:    models.StatusPending,
+		AudioPath: "test/path/track.mp3",
+	}).Error)
+
+	var recorded []models.Event
+	require.NoError(suite.T(), database.DB.Find(&recorded).Error)
+	require.Len(suite.T(), recorded, 1, "per-track jobs should not be recorded")
+	assert.Equal(suite.T(), models.EventJobCreated, recorded[0].Type)
+	assert.Equal(suite.T(), job.ID, recorded[0].SubjectID)
+	assert.Equal(suite.T(), "Board meeting", recorded[0].Data["title"])
+}
+
+func (suite *EventsTestSuite) TestPollWithCursor() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Interview")
+	events.RecordForJob(models.EventJobCompleted, job.ID, nil)
+	events.RecordForJob(models.EventSummaryReady, job.ID, map[string]interface{}{"model": "llama3"})
+
+	code, first := suite.poll("?limit=2")
+	require.Equal(suite.T(), http.StatusOK, code)
+	require.Len(suite.T(), first.Events, 2)
+	assert.True(suite.T(), first.HasMore)
+	assert.Equal(suite.T(), models.EventJobCreated, first.Events[0].Type)
+	assert.Equal(suite.T(), models.EventJobCompleted, first.Events[1].Type)
+	assert.Equal(suite.T(), first.Events[1].Sequence, first.NextCursor)
+
+	code, second := suite.poll("?limit=2&cursor=" + strconv.FormatUint(uint64(first.NextCursor), 10))
+	require.Equal(suite.T(), http.StatusOK, code)
+	require.Len(suite.T(), second.Events, 1)
+	assert.False(suite.T(), second.HasMore)
+	assert.Equal(suite.T(), "llama3", second.Events[0].Data["model"])
+
+	// An exhausted cursor is handed back unchanged
+	code, empty := suite.poll("?cursor=" + strconv.FormatUint(uint64(second.NextCursor), 10))
+	require.Equal(suite.T(), http.StatusOK, code)
+	assert.Empty(suite.T(), empty.Events)
+	assert.Equal(suite.T(), second.NextCursor, empty.NextCursor)
+
+	code, filtered := suite.poll("?types=summary.ready,job.failed")
+	require.Equal(suite.T(), http.StatusOK, code)
+	require.Len(suite.T(), filtered.Events, 1)
+	assert.Equal(suite.T(), models.EventSummaryReady, filtered.Events[0].Type)
+}
+
+func (suite *EventsTestSuite) TestPollIsScopedToOwner() {
+	other := uint(9999)
+	events.Record(models.EventIndexUpdated, "someone-elses", &other, nil)
+	events.Record(models.EventIndexUpdated, "mine", nil, nil)
+
+	code, resp := suite.poll("")
+	require.Equal(suite.T(), http.StatusOK, code)
+	require.Len(suite.T(), resp.Events, 1)
+	assert.Equal(suite.T(), "mine", resp.Events[0].SubjectID)
+}
+
+func (suite *EventsTestSuite) TestPollValidation() {
+	for _, query := range []string{"?cursor=-1", "?limit=0", "?limit=501", "?wait=31"} {
+		code, _ := suite.poll(query)
+		assert.Equal(suite.T(), http.StatusBadRequest, code, query)
+	}
+}
+
+// stream reads an event stream for a second and returns what was sent
+func (suite *EventsTestSuite) stream(path, lastEventID string, during func()) (int, string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req := suite.helper.NewRequest(suite.T(), suite.helper.TestAPIKey, "GET", path, nil).WithContext(ctx)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		suite.router.ServeHTTP(w, req)
+		close(done)
+	}()
+	if during != nil {
+		time.Sleep(100 * time.Millisecond)
+		during()
+	}
+	<-done
+	return w.Code, w.Body.String()
+}
+
+func (suite *EventsTestSuite) TestJobEventStream() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Standup")
+	events.RecordForJob(models.EventJobStarted, job.ID, nil)
+	events.Record(models.EventIndexUpdated, "another-job", nil, nil)
+
+	code, body := suite.stream("/api/v1/transcription/"+job.ID+"/events", "", func() {
+		events.RecordForJob(models.EventJobProgress, job.ID, map[string]interface{}{"stage": "transcribing", "percent": 20})
+	})
+	require.Equal(suite.T(), http.StatusOK, code)
+	assert.True(suite.T(), strings.HasPrefix(body, "event: snapshot\ndata: {"), body)
+	assert.Contains(suite.T(), body, `"status":"pending"`)
+	// History is replayed, then new events follow
+	created := strings.Index(body, "event: job.created\n")
+	started := strings.Index(body, "event: job.started\n")
+	progress := strings.Index(body, "event: job.progress\n")
+	require.True(suite.T(), created > 0 && started > created && progress > started, body)
+	assert.Contains(suite.T(), body, `"percent":20`)
+	assert.NotContains(suite.T(), body, "another-job")
+
+	// A reconnecting client resumes after the last event it saw
+	var startedEvent models.Event
+	require.NoError(suite.T(), database.DB.Where("type = ? AND subject_id = ?", models.EventJobStarted, job.ID).First(&startedEvent).Error)
+	_, body = suite.stream("/api/v1/transcription/"+job.ID+"/events", strconv.Itoa(int(startedEvent.Sequence)), nil)
+	assert.NotContains(suite.T(), body, "event: job.started")
+	assert.Contains(suite.T(), body, "event: job.progress")
+	assert.Contains(suite.T(), body, "id: ")
+
+	code, _ = suite.stream("/api/v1/transcription/missing/events", "", nil)
+	assert.Equal(suite.T(), http.StatusNotFound, code)
+}
+
+func (suite *EventsTestSuite) TestEventStreamFollowsNewEvents() {
+	events.Record(models.EventIndexUpdated, "before", nil, nil)
+
+	code, body := suite.stream("/api/v1/events?types=summary.ready", "", func() {
+		events.Record(models.EventSummaryReady, "after", nil, nil)
+		events.Record(models.EventIndexUpdated, "filtered-out", nil, nil)
+	})
+	require.Equal(suite.T(), http.StatusOK, code)
+	assert.NotContains(suite.T(), body, "before")
+	assert.NotContains(suite.T(), body, "filtered-out")
+	assert.Contains(suite.T(), body, "event: summary.ready\n")
+	assert.Contains(suite.T(), body, `"subject_id":"after"`)
+
+	code, _ = suite.stream("/api/v1/events?cursor=abc", "", nil)
+	assert.Equal(suite.T(), http.StatusBadRequest, code)
+}
+
+func TestEventsTestSuite(t *testing.T) {
+	suite.Run(t, new(EventsTestSuite))
+}
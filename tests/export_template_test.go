@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExportTemplateTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	router *gin.Engine
+}
+
+func (suite *ExportTemplateTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "export_template_test.db")
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, queue.NewTaskQueue(1, &MockJobProcessor{}), nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *ExportTemplateTestSuite) SetupTest() {
+	suite.helper.DB.Where("1 = 1").Delete(&models.ExportTemplate{})
+}
+
+func (suite *ExportTemplateTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *ExportTemplateTestSuite) TestTemplates() {
+	t := suite.T()
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/export-templates", gin.H{
+		"name":         "Client",
+		"header":       "{{title}}",
+		"organization": "Acme",
+		"accent_color": "1f6feb",
+		"sections":     []string{"transcript", "Summary", "transcript"},
+		"timestamps":   false,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var template models.ExportTemplate
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &template))
+	assert.Equal(t, "#1F6FEB", template.AccentColor)
+	assert.Equal(t, []string{"transcript", "summary"}, template.Sections)
+	assert.False(t, template.Timestamps)
+	assert.True(t, template.Speakers, "speakers default to shown")
+	assert.False(t, template.IsDefault)
+
+	for _, body := range []gin.H{
+		{"name": "Bad color", "accent_color": "blue"},
+		{"name": "Bad section", "sections": []string{"appendix"}},
+		{"name": "No sections", "sections": []string{}},
+		{"name": " "},
+	} {
+		w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/export-templates", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body["name"])
+	}
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/export-templates", gin.H{"name": "client"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Only one template is the default
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/export-templates", gin.H{"name": "Internal", "is_default": true})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var internal models.ExportTemplate
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &internal))
+	assert.Equal(t, []string{"summary", "chapters", "action_items", "transcript"}, internal.Sections)
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPut, "/api/v1/export-templates/"+template.ID, gin.H{"name": "Client", "sections": []string{"summary"}, "is_default": true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/export-templates/"+internal.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &internal))
+	assert.False(t, internal.IsDefault)
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/export-templates", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Templates []models.ExportTemplate `json:"templates"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(t, list.Templates, 2)
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodDelete, "/api/v1/export-templates/"+template.ID, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodDelete, "/api/v1/export-templates/"+internal.ID, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/export-templates/"+template.ID, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func (suite *ExportTemplateTestSuite) TestExportDocument() {
+	t := suite.T()
+	job := suite.helper.CreateTestTranscriptionJob(t, "Board meeting")
+	transcript := `{"text":"","segments":[{"start":0,"end":2,"text":"Welcome.","speaker":"SPEAKER_00"},{"start":62,"end":65,"text":"Budget is approved.","speaker":"SPEAKER_01"}]}`
+	summary := "The board approved the budget."
+	require.NoError(t, suite.helper.DB.Model(job).Updates(map[string]interface{}{"transcript": transcript, "summary": summary}).Error)
+	require.NoError(t, suite.helper.DB.Create(&models.SpeakerMapping{TranscriptionJobID: job.ID, OriginalSpeaker: "SPEAKER_00", CustomName: "Chair"}).Error)
+	require.NoError(t, suite.helper.DB.Create(&models.Chapter{TranscriptionID: job.ID, Position: 1, Start: 60, End: 65, Title: "Budget"}).Error)
+	require.NoError(t, suite.helper.DB.Create(&models.ActionItem{TranscriptionID: job.ID, Task: "Publish minutes", Owner: "Chair"}).Error)
+	base := "/api/v1/transcription/" + job.ID + "/export"
+
+	// The built-in layout has every section
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodGet, base+"?format=md", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "Board_meeting.md")
+	body := w.Body.String()
+	for _, text := range []string{"# Board meeting", "## Summary\n\nThe board approved the budget.", "- 1:00 Budget", "- ☐ Publish minutes (Chair)", "**00:00:00 · Chair**\n\nWelcome.", "**00:01:02 · SPEAKER_01**"} {
+		assert.Contains(t, body, text)
+	}
+
+	// The default template applies unless another is named
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/export-templates", gin.H{"name": "Transcript only", "sections": []string{"transcript"}, "timestamps": false, "footer": "Acme {{date}}", "is_default": true})
+	require.Equal(t, http.StatusCreated, w.Code)
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/export-templates", gin.H{"name": "Summary only", "sections": []string{"summary"}})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var summaryOnly models.ExportTemplate
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summaryOnly))
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodGet, base+"?format=markdown", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "## Summary")
+	assert.Contains(t, w.Body.String(), "**Chair**\n\nWelcome.")
+	assert.Contains(t, w.Body.String(), "_Acme ")
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodGet, base+"?format=markdown&template="+summaryOnly.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "## Summary")
+	assert.NotContains(t, w.Body.String(), "Welcome.")
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodGet, base+"?format=pdf&template=missing", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodGet, base+"?format=pdf", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "%PDF-"))
+	assert.Contains(t, w.Body.String(), "(Welcome.) Tj")
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodGet, base+"?format=docx", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	require.NoError(t, err)
+	var document string
+	for _, file := range archive.File {
+		if file.Name == "word/document.xml" {
+			r, err := file.Open()
+			require.NoError(t, err)
+			content, _ := io.ReadAll(r)
+			document = string(content)
+		}
+	}
+	assert.Contains(t, document, "Budget is approved.")
+}
+
+func TestExportTemplateTestSuite(t *testing.T) {
+	suite.Run(t, new(ExportTemplateTestSuite))
+}
@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"scriberr/internal/embeddings"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/notify"
+	"scriberr/internal/rag"
+	"scriberr/internal/transcription"
+	"scriberr/internal/transcription/adapters"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/internal/transcription/registry"
+	"scriberr/internal/vectordb"
+	"scriberr/internal/workflow"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// FakeProvidersTestSuite runs a job through transcription, diarization, summarization,
+// translation and indexing with every provider faked, the same way FAKE_PROVIDERS=true does
+type FakeProvidersTestSuite struct {
+	suite.Suite
+	helper    *TestHelper
+	processor *transcription.UnifiedJobProcessor
+	rag       *rag.RAGService
+	engine    *workflow.Engine
+}
+
+func (suite *FakeProvidersTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "fake_providers_test.db")
+
+	registry.ClearRegistry()
+	for _, modelID := range []string{"whisperx", "parakeet", "canary"} {
+		registry.RegisterTranscriptionAdapter(modelID, adapters.NewFakeTranscriptionAdapter(modelID))
+	}
+	for _, modelID := range []string{"pyannote", "sortformer"} {
+		registry.RegisterDiarizationAdapter(modelID, adapters.NewFakeDiarizationAdapter(modelID))
+	}
+	suite.processor = transcription.NewUnifiedJobProcessor()
+	require.NoError(suite.T(), suite.processor.InitEmbeddedPythonEnv())
+
+	fakeLLM := llm.NewFakeService()
+	suite.rag = rag.NewRAGService(vectordb.NewMemoryStore(), embeddings.NewFakeEmbeddingService(), fakeLLM)
+	suite.rag.SetEmbedRedacted(true)
+	suite.engine = workflow.NewEngine("bilingual")
+	require.NoError(suite.T(), workflow.RegisterBuiltins(suite.engine, fakeLLM, llm.FakeModel, workflow.SummaryFormatText, suite.rag, notify.NewWebhookNotifier(""), "fr", "", false, true, true, true, true, true, true, true, true))
+}
+
+func (suite *FakeProvidersTestSuite) TearDownSuite() {
+	registry.ClearRegistry()
+	suite.helper.Cleanup()
+}
+
+// fakeAudio writes 20 seconds' worth of bytes (at the 16kHz 16-bit default) for the fake transcriber
+func (suite *FakeProvidersTestSuite) fakeAudio(seed byte) string {
+	data := make([]byte, 20*32000)
+	for i := range data {
+		data[i] = byte(i) ^ seed
+	}
+	path := filepath.Join(suite.T().TempDir(), "audio.wav")
+	require.NoError(suite.T(), os.WriteFile(path, data, 0644))
+	return path
+}
+
+func (suite *FakeProvidersTestSuite) transcribe(audioPath string) *models.TranscriptionJob {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Fake Job")
+	job.AudioPath = audioPath
+	job.Parameters.ModelFamily = "nvidia_parakeet"
+	job.Parameters.Diarize = true
+	require.NoError(suite.T(), suite.helper.DB.Save(job).Error)
+
+	require.NoError(suite.T(), suite.processor.ProcessJob(context.Background(), job.ID))
+	require.NoError(suite.T(), suite.helper.DB.Model(job).Update("status", models.StatusCompleted).Error)
+	require.NoError(suite.T(), suite.helper.DB.Where("id = ?", job.ID).First(job).Error)
+	require.NotNil(suite.T(), job.Transcript)
+	return job
+}
+
+func (suite *FakeProvidersTestSuite) TestTranscriptsAreDeterministic() {
+	audio := suite.fakeAudio(1)
+	first := suite.transcribe(audio)
+	second := suite.transcribe(audio)
+
+	var result, again interfaces.TranscriptResult
+	require.NoError(suite.T(), json.Unmarshal([]byte(*first.Transcript), &result))
+	require.NoError(suite.T(), json.Unmarshal([]byte(*second.Transcript), &again))
+	assert.Equal(suite.T(), result.Segments, again.Segments)
+	assert.Equal(suite.T(), result.WordSegments, again.WordSegments)
+	require.Len(suite.T(), result.Segments, 4)
+	assert.NotEmpty(suite.T(), result.WordSegments)
+	for i, segment := range result.Segments {
+		assert.Equal(suite.T(), float64(i*5), segment.Start)
+		require.NotNil(suite.T(), segment.Speaker, "separate diarization should be merged in")
+	}
+	assert.Equal(suite.T(), "SPEAKER_00", *result.Segments[0].Speaker)
+	assert.Equal(suite.T(), "SPEAKER_01", *result.Segments[1].Speaker)
+
+	var other interfaces.TranscriptResult
+	require.NoError(suite.T(), json.Unmarshal([]byte(*suite.transcribe(suite.fakeAudio(2)).Transcript), &other))
+	assert.NotEqual(suite.T(), result.Text, other.Text)
+}
+
+func (suite *FakeProvidersTestSuite) TestFullPipeline() {
+	job := suite.transcribe(suite.fakeAudio(3))
+
+	run, err := suite.engine.Start(job.ID, "bilingual", map[string]string{"summary_format": workflow.SummaryFormatStructured})
+	require.NoError(suite.T(), err)
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) && run.Status != models.WorkflowCompleted && run.Status != models.WorkflowFailed {
+		time.Sleep(20 * time.Millisecond)
+		require.NoError(suite.T(), suite.helper.DB.Preload("Steps").Where("id = ?", run.ID).First(run).Error)
+	}
+	require.Equal(suite.T(), models.WorkflowCompleted, run.Status)
+	statuses := stepStatuses(*run)
+	assert.Equal(suite.T(), models.WorkflowCompleted, statuses[workflow.StepTranslate])
+	assert.Equal(suite.T(), models.WorkflowCompleted, statuses[workflow.StepRAGIndex])
+	assert.Equal(suite.T(), models.WorkflowCompleted, statuses[workflow.StepRedactPII])
+	assert.Equal(suite.T(), models.WorkflowCompleted, statuses[workflow.StepExtractActionItems])
+	assert.Equal(suite.T(), models.WorkflowCompleted, statuses[workflow.StepExtractEntities])
+	assert.Equal(suite.T(), models.WorkflowCompleted, statuses[workflow.StepSpeakerAnalytics])
+	assert.Equal(suite.T(), models.WorkflowSkipped, statuses[workflow.StepGenerateChapters], "the recording is too short for chapters")
+
+	require.NoError(suite.T(), suite.helper.DB.Where("id = ?", job.ID).First(job).Error)
+	require.NotNil(suite.T(), job.StructuredSummary, "the fake LLM should satisfy the summary schema")
+	require.NotNil(suite.T(), job.Summary)
+	assert.NotEmpty(suite.T(), *job.Summary)
+
+	var items []models.ActionItem
+	require.NoError(suite.T(), suite.helper.DB.Where("transcription_id = ?", job.ID).Find(&items).Error)
+	assert.NotEmpty(suite.T(), items)
+
+	var result interfaces.TranscriptResult
+	require.NoError(suite.T(), json.Unmarshal([]byte(*job.Transcript), &result))
+	indexed, err := suite.rag.IsIndexed(job.ID)
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), indexed)
+
+	hits, err := suite.rag.Search(context.Background(), nil, result.Segments[0].Text, 3, []string{job.ID})
+	require.NoError(suite.T(), err)
+	require.NotEmpty(suite.T(), hits)
+	assert.Equal(suite.T(), job.ID, hits[0].TranscriptionID)
+
+	var translation models.Translation
+	require.NoError(suite.T(), suite.helper.DB.Where("transcription_job_id = ? AND language = ?", job.ID, "fr").First(&translation).Error)
+	assert.True(suite.T(), translation.Indexed, "INDEX_TRANSLATIONS is on")
+}
+
+func TestFakeProvidersTestSuite(t *testing.T) {
+	suite.Run(t, new(FakeProvidersTestSuite))
+}
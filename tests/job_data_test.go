@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type JobDataTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	router *gin.Engine
+}
+
+func (suite *JobDataTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "job_data_test.db")
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, nil, nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *JobDataTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *JobDataTestSuite) delete(path string) *httptest.ResponseRecorder {
+	return suite.helper.Request(suite.T(), suite.router, "DELETE", path, nil)
+}
+
+func (suite *JobDataTestSuite) reload(job *models.TranscriptionJob) models.TranscriptionJob {
+	var reloaded models.TranscriptionJob
+	require.NoError(suite.T(), database.DB.First(&reloaded, "id = ?", job.ID).Error)
+	return reloaded
+}
+
+func (suite *JobDataTestSuite) TestDeleteSummaryKeepsTranscript() {
+	t := suite.T()
+	job := suite.helper.CreateTestTranscriptionJob(t, "Summarized meeting")
+	require.NoError(t, database.DB.Model(job).Updates(map[string]interface{}{
+		"summary":    "Short summary",
+		"transcript": `{"text":"Full transcript"}`,
+		"status":     models.StatusCompleted,
+	}).Error)
+	require.NoError(t, database.DB.Create(&models.Summary{TranscriptionID: job.ID, Model: "llama3.2", Content: "Short summary"}).Error)
+
+	w := suite.delete("/api/v1/transcription/" + job.ID + "/summary")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	reloaded := suite.reload(job)
+	assert.Nil(t, reloaded.Summary)
+	require.NotNil(t, reloaded.Transcript)
+	var count int64
+	database.DB.Model(&models.Summary{}).Where("transcription_id = ?", job.ID).Count(&count)
+	assert.Zero(t, count)
+
+	assert.Equal(t, http.StatusNotFound, suite.delete("/api/v1/transcription/missing/summary").Code)
+}
+
+func (suite *JobDataTestSuite) TestDeleteAudioKeepsRecord() {
+	t := suite.T()
+	audio := filepath.Join(t.TempDir(), "meeting.mp3")
+	require.NoError(t, os.WriteFile(audio, []byte("audio"), 0644))
+
+	job := suite.helper.CreateTestTranscriptionJob(t, "Recorded meeting")
+	require.NoError(t, database.DB.Model(job).Update("audio_path", audio).Error)
+
+	// Pending jobs still need their audio
+	assert.Equal(t, http.StatusConflict, suite.delete("/api/v1/transcription/"+job.ID+"/audio").Code)
+	assert.FileExists(t, audio)
+
+	require.NoError(t, database.DB.Model(job).Updates(map[string]interface{}{
+		"status":     models.StatusCompleted,
+		"transcript": `{"text":"Full transcript"}`,
+	}).Error)
+	w := suite.delete("/api/v1/transcription/" + job.ID + "/audio")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	assert.NoFileExists(t, audio)
+	reloaded := suite.reload(job)
+	assert.Empty(t, reloaded.AudioPath)
+	assert.NotNil(t, reloaded.Transcript)
+
+	// Deleting again is harmless
+	assert.Equal(t, http.StatusOK, suite.delete("/api/v1/transcription/"+job.ID+"/audio").Code)
+}
+
+func (suite *JobDataTestSuite) TestDeleteRAGDataRequiresRAG() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Unindexed")
+	assert.Equal(suite.T(), http.StatusInternalServerError, suite.delete("/api/v1/transcription/"+job.ID+"/rag").Code)
+}
+
+func TestJobDataTestSuite(t *testing.T) {
+	suite.Run(t, new(JobDataTestSuite))
+}
@@ -0,0 +1,214 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/joblog"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+	"scriberr/internal/transcription"
+	"scriberr/internal/transcription/adapters"
+	"scriberr/internal/transcription/registry"
+	"scriberr/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type JobLogTestSuite struct {
+	suite.Suite
+	helper    *TestHelper
+	router    *gin.Engine
+	store     *joblog.Store
+	processor *transcription.UnifiedJobProcessor
+}
+
+func (suite *JobLogTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "job_log_test.db")
+	suite.store = joblog.NewStore(50)
+	suite.store.Start()
+
+	registry.ClearRegistry()
+	registry.RegisterTranscriptionAdapter("parakeet", adapters.NewFakeTranscriptionAdapter("parakeet"))
+	suite.processor = transcription.NewUnifiedJobProcessor()
+	require.NoError(suite.T(), suite.processor.InitEmbeddedPythonEnv())
+
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, queue.NewTaskQueue(1, &MockJobProcessor{}), nil, nil, nil)
+	handler.SetJobLogStore(suite.store)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *JobLogTestSuite) TearDownSuite() {
+	suite.store.Stop()
+	registry.ClearRegistry()
+	suite.helper.Cleanup()
+}
+
+// job creates a transcription owned by the test user, with 10 seconds of fake audio
+func (suite *JobLogTestSuite) job() *models.TranscriptionJob {
+	t := suite.T()
+	path := filepath.Join(t.TempDir(), "audio.wav")
+	require.NoError(t, os.WriteFile(path, make([]byte, 10*32000), 0644))
+	job := suite.helper.CreateTestTranscriptionJob(t, "Logged Job")
+	job.AudioPath = path
+	job.UserID = &suite.helper.TestUser.ID
+	job.Parameters.ModelFamily = "nvidia_parakeet"
+	require.NoError(t, suite.helper.DB.Save(job).Error)
+	return job
+}
+
+// request makes a request as the test user, sending requestID as its X-Request-ID if set
+func (suite *JobLogTestSuite) request(method, path, requestID string) *httptest.ResponseRecorder {
+	req := suite.helper.NewRequest(suite.T(), suite.helper.TestToken, method, path, nil)
+	if requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
+	return serve(suite.router, req)
+}
+
+// logs reads a job's log with the given query string
+func (suite *JobLogTestSuite) logs(jobID, query string) []models.JobLogEntry {
+	w := suite.request(http.MethodGet, "/api/v1/transcription/"+jobID+"/logs"+query, "")
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		JobID   string               `json:"job_id"`
+		Entries []models.JobLogEntry `json:"entries"`
+	}
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(suite.T(), jobID, resp.JobID)
+	return resp.Entries
+}
+
+func (suite *JobLogTestSuite) TestProcessingLog() {
+	t := suite.T()
+	job := suite.job()
+	other := suite.job()
+	require.NoError(t, suite.processor.ProcessJob(context.Background(), job.ID))
+	require.NoError(t, suite.processor.ProcessJob(context.Background(), other.ID))
+
+	entries := suite.logs(job.ID, "")
+	require.NotEmpty(t, entries)
+	messages := map[string]models.JobLogEntry{}
+	for i, entry := range entries {
+		assert.Equal(t, job.ID, entry.JobID)
+		if i > 0 {
+			assert.Greater(t, entry.ID, entries[i-1].ID, "entries should be oldest first")
+		}
+		messages[entry.Message] = entry
+	}
+	started, ok := messages["Processing job with unified service"]
+	require.True(t, ok, "missing the start of processing in %+v", entries)
+	assert.Equal(t, "INFO", started.Level)
+	assert.Equal(t, "transcription", started.Component)
+	assert.NotContains(t, started.Attrs, "job_id", "the job ID is a column, not an attribute")
+
+	running := messages["Running transcription"]
+	assert.Equal(t, "parakeet", running.Attrs["model_id"])
+	assert.Contains(t, messages, "Job processed successfully")
+
+	// Lines after the last one read, to follow a log as it grows
+	later := suite.logs(job.ID, fmt.Sprintf("?after_id=%d", started.ID))
+	assert.Len(t, later, len(entries)-1-indexOf(entries, started.ID))
+
+	assert.Len(t, suite.logs(job.ID, "?limit=2"), 2)
+	assert.Empty(t, suite.logs(job.ID, "?component=workflow"))
+}
+
+func (suite *JobLogTestSuite) TestLevelFilter() {
+	t := suite.T()
+	job := suite.job()
+	ctx := logger.WithComponent(logger.WithJob(context.Background(), job.ID), "test")
+	logger.InfoContext(ctx, "All good")
+	logger.WarnContext(ctx, "Running low", "free_mb", 12)
+	logger.ErrorContext(ctx, "Out of space")
+	// Lines naming the job themselves are kept too
+	logger.Warn("Disk check failed", "job_id", job.ID)
+
+	var warnings []string
+	for _, entry := range suite.logs(job.ID, "?level=warn") {
+		warnings = append(warnings, entry.Message)
+	}
+	assert.Equal(t, []string{"Running low", "Out of space", "Disk check failed"}, warnings)
+	assert.Len(t, suite.logs(job.ID, "?level=ERROR"), 1)
+
+	low := suite.logs(job.ID, "?level=warn&component=test")
+	require.Len(t, low, 2)
+	assert.Equal(t, float64(12), low[0].Attrs["free_mb"])
+
+	w := suite.request(http.MethodGet, "/api/v1/transcription/"+job.ID+"/logs?level=loud", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func (suite *JobLogTestSuite) TestOldLinesAreTrimmed() {
+	t := suite.T()
+	job := suite.job()
+	ctx := logger.WithJob(context.Background(), job.ID)
+	for i := 0; i < 60; i++ {
+		logger.InfoContext(ctx, fmt.Sprintf("Line %d", i))
+	}
+
+	entries := suite.logs(job.ID, "?limit=100")
+	require.Len(t, entries, 50)
+	assert.Equal(t, "Line 10", entries[0].Message)
+	assert.Equal(t, "Line 59", entries[49].Message)
+}
+
+func (suite *JobLogTestSuite) TestRequestID() {
+	t := suite.T()
+	job := suite.job()
+
+	w := suite.request(http.MethodGet, "/api/v1/transcription/"+job.ID+"/logs", "trace-me-123")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "trace-me-123", w.Header().Get("X-Request-ID"))
+
+	// IDs that could break up log lines are replaced
+	w = suite.request(http.MethodGet, "/api/v1/transcription/"+job.ID+"/logs", "bad id INFO forged")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, w.Header().Get("X-Request-ID"), 16)
+
+	// Lines logged while handling a request carry its ID
+	ctx := logger.WithJob(logger.WithRequestID(context.Background(), "req-42"), job.ID)
+	logger.InfoContext(ctx, "Title changed")
+	entries := suite.logs(job.ID, "")
+	require.Len(t, entries, 1)
+	assert.Equal(t, "req-42", entries[0].RequestID)
+}
+
+func (suite *JobLogTestSuite) TestMissingAndDeletedJobs() {
+	t := suite.T()
+	w := suite.request(http.MethodGet, "/api/v1/transcription/no-such-job/logs", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	job := suite.job()
+	logger.InfoContext(logger.WithJob(context.Background(), job.ID), "Queued")
+	require.Len(t, suite.logs(job.ID, ""), 1)
+
+	w = suite.request(http.MethodDelete, "/api/v1/transcription/"+job.ID, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var count int64
+	require.NoError(t, suite.helper.DB.Model(&models.JobLogEntry{}).Where("job_id = ?", job.ID).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func indexOf(entries []models.JobLogEntry, id uint) int {
+	for i, entry := range entries {
+		if entry.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestJobLogTestSuite(t *testing.T) {
+	suite.Run(t, new(JobLogTestSuite))
+}
@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/notify"
+	"scriberr/internal/queue"
+	"scriberr/internal/workflow"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type JobTemplateTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	queue  *queue.TaskQueue
+	router *gin.Engine
+}
+
+func (suite *JobTemplateTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "job_template_test.db")
+	suite.queue = queue.NewTaskQueue(1, &MockJobProcessor{})
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, suite.queue, nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *JobTemplateTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *JobTemplateTestSuite) upload(fields map[string]string) *httptest.ResponseRecorder {
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, err := writer.CreateFormFile("audio", "standup.mp3")
+	require.NoError(suite.T(), err)
+	part.Write([]byte("fake audio"))
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+	require.NoError(suite.T(), writer.Close())
+	req := suite.helper.NewRequest(suite.T(), suite.helper.TestAPIKey, http.MethodPost, "/api/v1/transcription/upload", &form)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return serve(suite.router, req)
+}
+
+func (suite *JobTemplateTestSuite) TestUploadWithTemplate() {
+	t := suite.T()
+	suite.helper.CreateTestProfile(t, "Default", true)
+	accurate := &models.TranscriptionProfile{ID: "accurate-profile", Name: "Accurate", Parameters: models.WhisperXParams{Model: "large-v3", BatchSize: 4, ComputeType: "float32", Device: "cpu", Task: "transcribe"}}
+	require.NoError(t, suite.helper.DB.Create(accurate).Error)
+
+	var received []notify.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event notify.Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err == nil {
+			received = append(received, event)
+		}
+	}))
+	defer server.Close()
+
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/job-templates", gin.H{
+		"name":         "Sales calls",
+		"profile_id":   accurate.ID,
+		"model_family": "nvidia_parakeet",
+		"diarize":      true,
+		"tags":         []string{"sales", " Sales ", "calls"},
+		"webhook_urls": []string{server.URL},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var template models.JobTemplate
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &template))
+	assert.Equal(t, []string{"sales", "calls"}, template.Tags)
+
+	// Picked by name, ignoring case
+	w = suite.upload(map[string]string{"template": "sales CALLS", "title": "Acme"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var job models.TranscriptionJob
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, models.StatusPending, job.Status)
+	assert.Contains(t, order(suite.queue), job.ID)
+	require.NotNil(t, job.JobTemplateID)
+	assert.Equal(t, template.ID, *job.JobTemplateID)
+	assert.Equal(t, "large-v3", job.Parameters.Model, "the template's profile is used")
+	assert.Equal(t, "nvidia_parakeet", job.Parameters.ModelFamily)
+	assert.True(t, job.Parameters.Diarize)
+	assert.True(t, job.Diarization)
+	assert.ElementsMatch(t, []string{"sales", "calls"}, job.Tags)
+	assert.Equal(t, []string{server.URL}, job.WebhookURLs)
+
+	// The completion notification goes to the template's webhook
+	_, err := (&workflow.NotifyStep{AllowPrivateWebhooks: true}).Run(context.Background(), &workflow.RunContext{
+		Run: &models.WorkflowRun{ID: "run", Workflow: "default"},
+		Job: &job,
+	})
+	require.NoError(t, err)
+	require.Len(t, received, 1)
+	assert.Equal(t, job.ID, received[0].TranscriptionID)
+	assert.Equal(t, "Acme", received[0].Title)
+
+	// Without a template the default profile is used as before
+	w = suite.upload(nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var plain models.TranscriptionJob
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &plain))
+	assert.Equal(t, "small", plain.Parameters.Model)
+	assert.Nil(t, plain.JobTemplateID)
+	assert.Empty(t, plain.Tags)
+
+	w = suite.upload(map[string]string{"template": "Nonexistent"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func (suite *JobTemplateTestSuite) TestTemplateValidation() {
+	t := suite.T()
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/job-templates", gin.H{"name": "Podcasts", "model": "medium"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var podcasts models.JobTemplate
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &podcasts))
+
+	for _, body := range []gin.H{
+		{"name": "podcasts"},
+		{"name": "Engine", "model_family": "whisper.cpp"},
+		{"name": "Profile", "profile_id": "missing"},
+		{"name": "Webhook", "webhook_urls": []string{"ftp://example.com"}},
+		{"name": " "},
+	} {
+		w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/job-templates", body)
+		assert.Contains(t, []int{http.StatusBadRequest, http.StatusConflict}, w.Code, body)
+	}
+	assert.Equal(t, http.StatusConflict, suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/job-templates", gin.H{"name": "PODCASTS"}).Code)
+
+	// Keeping its own name on update is fine
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPut, "/api/v1/job-templates/"+podcasts.ID, gin.H{"name": "Podcasts", "diarize": false})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var updated models.JobTemplate
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Nil(t, updated.Model)
+	require.NotNil(t, updated.Diarize)
+	assert.False(t, *updated.Diarize)
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodDelete, "/api/v1/job-templates/"+podcasts.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusNotFound, suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/job-templates/"+podcasts.ID, nil).Code)
+}
+
+func TestJobTemplateTestSuite(t *testing.T) {
+	suite.Run(t, new(JobTemplateTestSuite))
+}
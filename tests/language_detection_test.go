@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"scriberr/internal/models"
+	"scriberr/internal/transcription"
+	"scriberr/internal/transcription/adapters"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/internal/transcription/registry"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// LanguageDetectionTestSuite detects the languages the fake transcriber is told are spoken,
+// and transcribes with the language and model they call for
+type LanguageDetectionTestSuite struct {
+	suite.Suite
+	helper    *TestHelper
+	whisperx  *adapters.FakeTranscriptionAdapter
+	processor *transcription.UnifiedJobProcessor
+}
+
+func (suite *LanguageDetectionTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "language_detection_test.db")
+
+	registry.ClearRegistry()
+	suite.whisperx = adapters.NewFakeTranscriptionAdapter("whisperx")
+	registry.RegisterTranscriptionAdapter("whisperx", suite.whisperx)
+	registry.RegisterTranscriptionAdapter("parakeet", adapters.NewFakeTranscriptionAdapter("parakeet"))
+	suite.processor = transcription.NewUnifiedJobProcessor()
+	require.NoError(suite.T(), suite.processor.InitEmbeddedPythonEnv())
+}
+
+func (suite *LanguageDetectionTestSuite) TearDownSuite() {
+	registry.ClearRegistry()
+	suite.helper.Cleanup()
+}
+
+func (suite *LanguageDetectionTestSuite) SetupTest() {
+	suite.processor.GetUnifiedService().SetLanguageDetection(0.9, nil)
+}
+
+// transcribe runs a Whisper job over 20 seconds of fake audio, in which the languages are
+// detected one per 5 seconds
+func (suite *LanguageDetectionTestSuite) transcribe(language *string, detected ...string) (*models.TranscriptionJob, interfaces.TranscriptResult) {
+	t := suite.T()
+	suite.whisperx.DetectedLanguages = detected
+	path := filepath.Join(t.TempDir(), "audio.wav")
+	require.NoError(t, os.WriteFile(path, make([]byte, 20*32000), 0644))
+
+	job := suite.helper.CreateTestTranscriptionJob(t, "Call")
+	job.AudioPath = path
+	job.Parameters.ModelFamily = "whisper"
+	job.Parameters.Language = language
+	require.NoError(t, suite.helper.DB.Save(job).Error)
+	require.NoError(t, suite.processor.ProcessJob(context.Background(), job.ID))
+
+	require.NoError(t, suite.helper.DB.First(job, "id = ?", job.ID).Error)
+	var result interfaces.TranscriptResult
+	require.NoError(t, json.Unmarshal([]byte(*job.Transcript), &result))
+	return job, result
+}
+
+func (suite *LanguageDetectionTestSuite) TestDetectedLanguageIsStoredAndUsed() {
+	t := suite.T()
+	job, result := suite.transcribe(nil, "de")
+
+	require.NotNil(t, job.DetectedLanguage)
+	assert.Equal(t, "de", *job.DetectedLanguage)
+	assert.InDelta(t, 0.95, *job.LanguageConfidence, 1e-9)
+	assert.Equal(t, []string{"de"}, job.Languages)
+	assert.Nil(t, job.Parameters.Language, "the job's own setting is left as it was")
+	assert.Equal(t, "de", result.Language)
+	assert.Equal(t, "whisperx", result.ModelUsed)
+
+	var progress []models.Event
+	require.NoError(t, suite.helper.DB.Where("subject_id = ? AND type = ?", job.ID, models.EventJobProgress).Find(&progress).Error)
+	var detecting map[string]interface{}
+	for _, event := range progress {
+		if event.Data["stage"] == "detecting_language" {
+			detecting = event.Data
+		}
+	}
+	require.NotNil(t, detecting)
+	assert.Equal(t, "de", detecting["language"])
+	assert.NotContains(t, detecting, "requested_language")
+}
+
+func (suite *LanguageDetectionTestSuite) TestCodeSwitchedSegmentsAreTagged() {
+	t := suite.T()
+	job, result := suite.transcribe(nil, "en", "es", "es", "es")
+
+	assert.Equal(t, "es", *job.DetectedLanguage)
+	assert.Equal(t, []string{"es", "en"}, job.Languages)
+	require.Len(t, result.Segments, 4)
+	var tags []string
+	for _, segment := range result.Segments {
+		require.NotNil(t, segment.Language)
+		tags = append(tags, *segment.Language)
+	}
+	assert.Equal(t, []string{"en", "es", "es", "es"}, tags)
+}
+
+func (suite *LanguageDetectionTestSuite) TestConfidentDetectionOverridesTheJobLanguage() {
+	t := suite.T()
+	french := "fr"
+	_, result := suite.transcribe(&french, "de")
+	assert.Equal(t, "de", result.Language, "the detection is sure enough to override fr")
+
+	suite.processor.GetUnifiedService().SetLanguageDetection(0.99, nil)
+	job, result := suite.transcribe(&french, "de")
+	assert.Equal(t, "fr", result.Language, "the detection isn't sure enough to override fr")
+	assert.Equal(t, "de", *job.DetectedLanguage)
+}
+
+func (suite *LanguageDetectionTestSuite) TestLanguagesAreRoutedToModels() {
+	t := suite.T()
+	routes, err := transcription.ParseLanguageModels("de=nvidia_parakeet,en=whisper:large-v3")
+	require.NoError(t, err)
+	suite.processor.GetUnifiedService().SetLanguageDetection(0.9, routes)
+
+	job, result := suite.transcribe(nil, "de")
+	assert.Equal(t, "parakeet", result.ModelUsed)
+	assert.Equal(t, "whisper", job.Parameters.ModelFamily, "routing doesn't change the job's settings")
+
+	_, result = suite.transcribe(nil, "en")
+	assert.Equal(t, "whisperx", result.ModelUsed)
+}
+
+func TestLanguageDetectionTestSuite(t *testing.T) {
+	suite.Run(t, new(LanguageDetectionTestSuite))
+}
@@ -0,0 +1,250 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/auth"
+	"scriberr/internal/embeddings"
+	"scriberr/internal/mcp"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/vectordb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// MCPTestSuite talks to the MCP endpoint the way an MCP client does
+type MCPTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	rag    *rag.RAGService
+	router *gin.Engine
+	job    *models.TranscriptionJob
+	viewer string // Token of a user without transcriptions
+}
+
+func (suite *MCPTestSuite) SetupSuite() {
+	t := suite.T()
+	suite.helper = NewTestHelper(t, "mcp_test.db")
+	suite.rag = rag.NewRAGService(vectordb.NewMemoryStore(), embeddings.NewFakeEmbeddingService(), &replyLLM{reply: "The release ships on Friday."})
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, nil, nil, nil, suite.rag)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+
+	// The viewer comes first: with a second user, the transcription goes in the shared collection
+	hashed, err := auth.HashPassword("viewerpassword")
+	require.NoError(t, err)
+	viewer := models.User{Username: "mcp-viewer", Password: hashed, Role: models.RoleViewer}
+	require.NoError(t, suite.helper.DB.Create(&viewer).Error)
+	suite.viewer, err = suite.helper.AuthService.GenerateToken(&viewer)
+	require.NoError(t, err)
+
+	suite.job = suite.helper.CreateTestTranscriptionJob(t, "Release planning")
+	require.NoError(t, suite.helper.DB.Model(suite.job).Updates(map[string]interface{}{
+		"status":  models.StatusCompleted,
+		"summary": "Ship on Friday.",
+		"transcript": `{"text":"","segments":[
+			{"start":0,"end":4,"text":"When does the release ship?","speaker":"SPEAKER_00"},
+			{"start":4,"end":9,"text":"The release ships on Friday.","speaker":"SPEAKER_01"}]}`,
+	}).Error)
+	require.NoError(t, suite.helper.DB.Create(&models.SpeakerMapping{TranscriptionJobID: suite.job.ID, OriginalSpeaker: "SPEAKER_01", CustomName: "Priya"}).Error)
+	require.NoError(t, suite.rag.StoreSummary(context.Background(), suite.job.ID, "Ship on Friday.", "When does the release ship? The release ships on Friday."))
+}
+
+func (suite *MCPTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// send posts a JSON-RPC message, authenticating with bearer
+func (suite *MCPTestSuite) send(bearer string, message gin.H) *httptest.ResponseRecorder {
+	message["jsonrpc"] = "2.0"
+	data, err := json.Marshal(message)
+	require.NoError(suite.T(), err)
+	req, err := http.NewRequest(http.MethodPost, "/mcp", bytes.NewReader(data))
+	require.NoError(suite.T(), err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+// result sends a request and decodes its result into v
+func (suite *MCPTestSuite) result(bearer, method string, params gin.H, v interface{}) {
+	t := suite.T()
+	w := suite.send(bearer, gin.H{"id": 1, "method": method, "params": params})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *mcp.Error      `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Nil(t, resp.Error, w.Body.String())
+	require.NoError(t, json.Unmarshal(resp.Result, v))
+}
+
+// callTool calls a tool and returns its result
+func (suite *MCPTestSuite) callTool(bearer, name string, args gin.H) mcp.ToolResult {
+	var result mcp.ToolResult
+	suite.result(bearer, "tools/call", gin.H{"name": name, "arguments": args}, &result)
+	require.NotEmpty(suite.T(), result.Content)
+	return result
+}
+
+// tools lists the names of the tools the caller can use
+func (suite *MCPTestSuite) tools(bearer string) []string {
+	var list struct {
+		Tools []mcp.Tool `json:"tools"`
+	}
+	suite.result(bearer, "tools/list", nil, &list)
+	names := []string{}
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func (suite *MCPTestSuite) TestSession() {
+	t := suite.T()
+	key := suite.helper.TestAPIKey
+
+	var initialized struct {
+		ProtocolVersion string            `json:"protocolVersion"`
+		ServerInfo      map[string]string `json:"serverInfo"`
+		Capabilities    map[string]interface{}
+	}
+	suite.result(key, "initialize", gin.H{"protocolVersion": "2025-03-26", "capabilities": gin.H{}, "clientInfo": gin.H{"name": "test", "version": "1"}}, &initialized)
+	assert.Equal(t, "2025-03-26", initialized.ProtocolVersion)
+	assert.Equal(t, "scriberr", initialized.ServerInfo["name"])
+	assert.Contains(t, initialized.Capabilities, "tools")
+
+	w := suite.send(key, gin.H{"method": "notifications/initialized"})
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	assert.Equal(t, []string{"search_transcripts", "list_transcriptions", "get_transcript", "get_summary", "rag_query"}, suite.tools(key))
+
+	// Scriberr sends nothing of its own, so there is no event stream to open
+	req, err := http.NewRequest(http.MethodGet, "/mcp", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+key)
+	rec := httptest.NewRecorder()
+	suite.router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/mcp", bytes.NewBufferString("{not json"))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+key)
+	rec = httptest.NewRecorder()
+	suite.router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/mcp", bytes.NewBufferString(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, func() int {
+		rec := httptest.NewRecorder()
+		suite.router.ServeHTTP(rec, req)
+		return rec.Code
+	}())
+}
+
+func (suite *MCPTestSuite) TestTools() {
+	t := suite.T()
+	key := suite.helper.TestAPIKey
+
+	var found struct {
+		Mode    string                `json:"mode"`
+		Results []api.MCPSearchResult `json:"results"`
+	}
+	result := suite.callTool(key, "search_transcripts", gin.H{"query": "release"})
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &found))
+	assert.Equal(t, api.MCPSearchSemantic, found.Mode)
+	require.NotEmpty(t, found.Results)
+	assert.Equal(t, suite.job.ID, found.Results[0].TranscriptionID)
+	assert.Equal(t, "Release planning", found.Results[0].Title)
+
+	result = suite.callTool(key, "search_transcripts", gin.H{"query": "Friday", "mode": api.MCPSearchKeyword})
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &found))
+	require.Len(t, found.Results, 1)
+	assert.Equal(t, "The release ships on Friday.", found.Results[0].Snippet, "snippets come without HTML")
+	assert.Equal(t, "Priya", found.Results[0].Speaker)
+	require.NotNil(t, found.Results[0].Start)
+	assert.Equal(t, 4.0, *found.Results[0].Start)
+
+	var listed struct {
+		Transcriptions []api.MCPTranscription `json:"transcriptions"`
+	}
+	result = suite.callTool(key, "list_transcriptions", gin.H{"query": "planning"})
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &listed))
+	require.Len(t, listed.Transcriptions, 1)
+	assert.True(t, listed.Transcriptions[0].HasSummary)
+
+	result = suite.callTool(key, "get_transcript", gin.H{"id": suite.job.ID})
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "# Release planning")
+	assert.Contains(t, result.Content[0].Text, "Priya")
+	assert.Contains(t, result.Content[0].Text, "The release ships on Friday.")
+
+	result = suite.callTool(key, "get_summary", gin.H{"id": suite.job.ID})
+	assert.Equal(t, "Ship on Friday.", result.Content[0].Text)
+
+	var answer struct {
+		Answer  string          `json:"answer"`
+		Sources []api.MCPSource `json:"sources"`
+	}
+	result = suite.callTool(key, "rag_query", gin.H{"query": "When does the release ship?"})
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &answer))
+	assert.Equal(t, "The release ships on Friday.", answer.Answer)
+	assert.Equal(t, []api.MCPSource{{TranscriptionID: suite.job.ID, Title: "Release planning"}}, answer.Sources)
+
+	// Failures are results the model can read
+	result = suite.callTool(key, "get_transcript", gin.H{"id": "missing"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "not found")
+	result = suite.callTool(key, "search_transcripts", gin.H{"query": "release", "limit": 500})
+	assert.True(t, result.IsError)
+}
+
+func (suite *MCPTestSuite) TestCallerAccess() {
+	t := suite.T()
+
+	// Other users don't see the library's transcriptions
+	result := suite.callTool(suite.viewer, "get_transcript", gin.H{"id": suite.job.ID})
+	assert.True(t, result.IsError)
+	var listed struct {
+		Transcriptions []api.MCPTranscription `json:"transcriptions"`
+	}
+	result = suite.callTool(suite.viewer, "list_transcriptions", gin.H{})
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &listed))
+	assert.Empty(t, listed.Transcriptions)
+
+	// Scoped keys get the tools their scopes allow
+	readKey := models.APIKey{Key: "mcp-read-key", Name: "Reader", IsActive: true, Scopes: []string{models.APIKeyScopeRead}}
+	chatKey := models.APIKey{Key: "mcp-chat-key", Name: "Chat", IsActive: true, Scopes: []string{models.APIKeyScopeRAGChat}}
+	uploadKey := models.APIKey{Key: "mcp-upload-key", Name: "Uploader", IsActive: true, Scopes: []string{models.APIKeyScopeUpload}}
+	for _, key := range []*models.APIKey{&readKey, &chatKey, &uploadKey} {
+		require.NoError(t, suite.helper.DB.Create(key).Error)
+	}
+	assert.Equal(t, []string{"search_transcripts", "list_transcriptions", "get_transcript", "get_summary"}, suite.tools(readKey.Key))
+	assert.Equal(t, []string{"search_transcripts", "rag_query"}, suite.tools(chatKey.Key))
+	w := suite.send(chatKey.Key, gin.H{"id": 1, "method": "tools/call", "params": gin.H{"name": "get_transcript", "arguments": gin.H{"id": suite.job.ID}}})
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp mcp.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, mcp.CodeInvalidParams, resp.Error.Code)
+	assert.Equal(t, http.StatusForbidden, suite.send(uploadKey.Key, gin.H{"id": 1, "method": "tools/list"}).Code)
+}
+
+func TestMCPTestSuite(t *testing.T) {
+	suite.Run(t, new(MCPTestSuite))
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeYtDlp answers --dump-single-json with canned metadata, and otherwise writes a small
+// file where --output says, the way yt-dlp names the extracted audio
+const fakeYtDlp = `#!/bin/sh
+case "$*" in
+*--dump-single-json*)
+	case "$*" in
+	*live*) echo '{"id":"live","title":"Live now","is_live":true}' ;;
+	*missing*) echo "WARNING: retrying" >&2; echo "ERROR: [youtube] missing: Video unavailable" >&2; exit 1 ;;
+	*) echo '{"id":"abc123","title":"Quarterly Town Hall","channel":"Acme Corp","channel_url":"https://www.youtube.com/@acme","uploader":"Acme","upload_date":"20260105","duration":3605.5,"webpage_url":"https://www.youtube.com/watch?v=abc123","extractor_key":"Youtube","filesize_approx":9}' ;;
+	esac ;;
+*)
+	while [ $# -gt 0 ]; do
+		if [ "$1" = "--output" ]; then out="$2"; fi
+		shift
+	done
+	echo "scriberr-progress 4 NA 9.0"
+	printf 'ID3 audio' > "$(echo "$out" | sed 's/%(ext)s/mp3/')" ;;
+esac
+`
+
+type MediaImportTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	queue  *queue.TaskQueue
+	router *gin.Engine
+}
+
+func (suite *MediaImportTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "media_import_test.db")
+	script := filepath.Join(suite.T().TempDir(), "yt-dlp")
+	require.NoError(suite.T(), os.WriteFile(script, []byte(fakeYtDlp), 0755))
+	suite.helper.Config.YtDlpPath = script
+	suite.helper.Config.URLImportAllowPrivate = true // No DNS lookups
+	suite.queue = queue.NewTaskQueue(1, &MockJobProcessor{})
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, suite.queue, nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *MediaImportTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// importMedia submits a page and waits for its audio to be extracted
+func (suite *MediaImportTestSuite) importMedia(body gin.H) models.URLImport {
+	t := suite.T()
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/transcription/from-media", body)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var urlImport models.URLImport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &urlImport))
+	assert.Equal(t, models.URLImportYtDlp, urlImport.Extractor)
+
+	require.Eventually(t, func() bool {
+		w := suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/transcription/from-url/"+urlImport.ID, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &urlImport))
+		return urlImport.Status != models.URLImportDownloading
+	}, 5*time.Second, 20*time.Millisecond)
+	return urlImport
+}
+
+func (suite *MediaImportTestSuite) TestImportRecordsSource() {
+	t := suite.T()
+	suite.helper.CreateTestProfile(t, "Default", true)
+	urlImport := suite.importMedia(gin.H{"url": "https://youtu.be/abc123", "content_type": "podcast"})
+	require.Equal(t, models.URLImportCompleted, urlImport.Status, urlImport.Error)
+	assert.EqualValues(t, 9, urlImport.DownloadedBytes)
+	require.NotNil(t, urlImport.Source)
+	assert.Equal(t, "Acme Corp", urlImport.Source.Channel)
+
+	var job models.TranscriptionJob
+	require.NoError(t, suite.helper.DB.First(&job, "id = ?", urlImport.ID).Error)
+	assert.Equal(t, models.StatusPending, job.Status, "queued with the default profile")
+	assert.Equal(t, models.ContentPodcast, job.ContentType)
+	require.NotNil(t, job.Title)
+	assert.Equal(t, "Quarterly Town Hall", *job.Title)
+	require.NotNil(t, job.Source)
+	assert.Equal(t, models.MediaSource{
+		URL:             "https://www.youtube.com/watch?v=abc123",
+		Site:            "Youtube",
+		MediaID:         "abc123",
+		Title:           "Quarterly Town Hall",
+		Channel:         "Acme Corp",
+		ChannelURL:      "https://www.youtube.com/@acme",
+		Uploader:        "Acme",
+		UploadDate:      "2026-01-05",
+		DurationSeconds: 3605.5,
+	}, *job.Source)
+	assert.Equal(t, ".mp3", filepath.Ext(job.AudioPath))
+	data, err := os.ReadFile(job.AudioPath)
+	require.NoError(t, err)
+	assert.Equal(t, "ID3 audio", string(data))
+}
+
+func (suite *MediaImportTestSuite) TestRefusedImports() {
+	t := suite.T()
+	live := suite.importMedia(gin.H{"url": "https://www.youtube.com/watch?v=live"})
+	assert.Equal(t, models.URLImportFailed, live.Status)
+	require.NotNil(t, live.Error)
+	assert.Contains(t, *live.Error, "live streams")
+
+	missing := suite.importMedia(gin.H{"url": "https://www.youtube.com/watch?v=missing"})
+	assert.Equal(t, models.URLImportFailed, missing.Status)
+	require.NotNil(t, missing.Error)
+	assert.Equal(t, "yt-dlp failed: [youtube] missing: Video unavailable", *missing.Error)
+	assert.Nil(t, missing.TranscriptionID)
+
+	suite.helper.Config.URLImportAllowPrivate = false
+	defer func() { suite.helper.Config.URLImportAllowPrivate = true }()
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/transcription/from-media", gin.H{"url": "http://127.0.0.1/watch?v=1"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/transcription/from-media", gin.H{"url": "file:///etc/passwd"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestMediaImportTestSuite(t *testing.T) {
+	suite.Run(t, new(MediaImportTestSuite))
+}
@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeFFprobe reports FAKE_CHANNELS channels for any file
+const fakeFFprobe = `#!/bin/sh
+echo "{\"streams\":[{\"channels\":${FAKE_CHANNELS:-1},\"sample_rate\":\"48000\"}]}"
+`
+
+// fakeFFmpeg writes every output file it is given, the argument after each -y
+const fakeFFmpeg = `#!/bin/sh
+while [ $# -gt 0 ]; do
+	if [ "$1" = "-y" ]; then printf 'fLaC' > "$2"; fi
+	shift
+done
+`
+
+type MultiChannelTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	router *gin.Engine
+}
+
+func (suite *MultiChannelTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "multichannel_test.db")
+	bin := suite.T().TempDir()
+	require.NoError(suite.T(), os.WriteFile(filepath.Join(bin, "ffprobe"), []byte(fakeFFprobe), 0755))
+	require.NoError(suite.T(), os.WriteFile(filepath.Join(bin, "ffmpeg"), []byte(fakeFFmpeg), 0755))
+	suite.T().Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	taskQueue := queue.NewTaskQueue(1, &MockJobProcessor{})
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, taskQueue, nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *MultiChannelTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *MultiChannelTestSuite) upload(fields map[string]string) *httptest.ResponseRecorder {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("audio", "Episode 12.wav")
+	require.NoError(suite.T(), err)
+	part.Write([]byte("RIFF stereo"))
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+	writer.Close()
+
+	req := suite.helper.NewRequest(suite.T(), suite.helper.TestAPIKey, "POST", "/api/v1/transcription/upload-multichannel", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return serve(suite.router, req)
+}
+
+func (suite *MultiChannelTestSuite) TestUploadSplitsChannelsIntoTracks() {
+	t := suite.T()
+	t.Setenv("FAKE_CHANNELS", "3")
+	profile := suite.helper.CreateTestProfile(t, "Diarizing default", true)
+	database.DB.Model(profile).Update("diarize", true)
+	defer database.DB.Delete(profile)
+
+	w := suite.upload(map[string]string{"speakers": "Alice, Dr. Bob,"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var job models.TranscriptionJob
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+
+	assert.Equal(t, "Episode 12", *job.Title)
+	assert.True(t, job.IsMultiTrack)
+	require.NotNil(t, job.MergedAudioPath)
+	assert.Equal(t, job.AudioPath, *job.MergedAudioPath, "the recording itself is played back")
+	assert.FileExists(t, job.AudioPath)
+
+	require.Len(t, job.MultiTrackFiles, 3)
+	for i, name := range []string{"Alice.flac", "Dr. Bob.flac", "Channel 3.flac"} {
+		assert.Equal(t, name, job.MultiTrackFiles[i].FileName)
+		assert.Equal(t, i, job.MultiTrackFiles[i].TrackIndex)
+		assert.FileExists(t, job.MultiTrackFiles[i].FilePath)
+	}
+
+	// Queued in multi-track mode even though the default profile diarizes
+	assert.Equal(t, models.StatusPending, job.Status)
+	assert.True(t, job.Parameters.IsMultiTrackEnabled)
+	assert.False(t, job.Parameters.Diarize)
+	assert.Equal(t, "small", job.Parameters.Model)
+}
+
+func (suite *MultiChannelTestSuite) TestUploadRejectsUnsuitableRecordings() {
+	t := suite.T()
+	entries := func() int {
+		list, _ := os.ReadDir(suite.helper.Config.UploadDir)
+		return len(list)
+	}
+	before := entries()
+
+	t.Setenv("FAKE_CHANNELS", "1")
+	w := suite.upload(nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "single channel")
+
+	t.Setenv("FAKE_CHANNELS", "2")
+	w = suite.upload(map[string]string{"speakers": "Alice, Bob, Carol"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "recording has 2")
+
+	w = suite.upload(map[string]string{"speakers": "Alice, alice"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "named twice")
+
+	assert.Equal(t, before, entries(), "rejected uploads leave no files behind")
+}
+
+func TestMultiChannelTestSuite(t *testing.T) {
+	suite.Run(t, new(MultiChannelTestSuite))
+}
@@ -0,0 +1,375 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/embeddings"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/quota"
+	"scriberr/internal/rag"
+	"scriberr/internal/transcription"
+	"scriberr/internal/transcription/adapters"
+	"scriberr/internal/transcription/registry"
+	"scriberr/internal/vectordb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// OpenAITestSuite calls the OpenAI-compatible endpoints the way OpenAI's client libraries do,
+// with a fake transcriber and LLMs
+type OpenAITestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	quick  *transcription.QuickTranscriptionService
+	llm    *replyLLM
+	rag    *rag.RAGService
+	router *gin.Engine
+}
+
+func (suite *OpenAITestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "openai_test.db")
+
+	registry.ClearRegistry()
+	registry.RegisterTranscriptionAdapter("whisperx", adapters.NewFakeTranscriptionAdapter("whisperx"))
+	registry.RegisterTranscriptionAdapter("parakeet", adapters.NewFakeTranscriptionAdapter("parakeet"))
+	processor := transcription.NewUnifiedJobProcessor()
+	require.NoError(suite.T(), processor.InitEmbeddedPythonEnv())
+
+	var err error
+	suite.quick, err = transcription.NewQuickTranscriptionService(suite.helper.Config, processor)
+	require.NoError(suite.T(), err)
+	suite.llm = &replyLLM{reply: "The release ships on Friday."}
+	suite.rag = rag.NewRAGService(vectordb.NewMemoryStore(), embeddings.NewFakeEmbeddingService(), suite.llm)
+	// Models other than scriberr-rag go to the LLM configured in the UI
+	suite.helper.CreateTestLLMConfig(suite.T(), llm.ProviderFake)
+
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, nil, processor, suite.quick, suite.rag)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *OpenAITestSuite) TearDownSuite() {
+	suite.quick.Close()
+	registry.ClearRegistry()
+	suite.helper.Cleanup()
+}
+
+// transcribe posts 20 seconds' worth of fake audio with the given form fields, authenticating
+// with bearer, as OpenAI's clients do
+func (suite *OpenAITestSuite) transcribe(bearer string, fields map[string][]string) *httptest.ResponseRecorder {
+	return suite.postAudio("/v1/audio/transcriptions", "file", bearer, fields)
+}
+
+// postAudio posts 20 seconds' worth of fake audio as the form file field, with the given
+// form fields, authenticating with bearer
+func (suite *OpenAITestSuite) postAudio(path, field, bearer string, fields map[string][]string) *httptest.ResponseRecorder {
+	t := suite.T()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, "meeting.wav")
+	require.NoError(t, err)
+	audio := make([]byte, 20*32000)
+	for i := range audio {
+		audio[i] = byte(i)
+	}
+	_, err = part.Write(audio)
+	require.NoError(t, err)
+	for name, values := range fields {
+		for _, value := range values {
+			require.NoError(t, writer.WriteField(name, value))
+		}
+	}
+	require.NoError(t, writer.Close())
+
+	req, err := http.NewRequest(http.MethodPost, path, &body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+func (suite *OpenAITestSuite) TestResponseFormats() {
+	t := suite.T()
+
+	w := suite.transcribe(suite.helper.TestAPIKey, map[string][]string{"model": {"whisper-1"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var plain api.OpenAITranscription
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &plain))
+	assert.NotEmpty(t, plain.Text)
+
+	w = suite.transcribe(suite.helper.TestAPIKey, map[string][]string{"response_format": {"text"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+	assert.Equal(t, plain.Text, strings.TrimSpace(w.Body.String()), "the fake transcriber hears the same audio the same way")
+
+	w = suite.transcribe(suite.helper.TestAPIKey, map[string][]string{"response_format": {"srt"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.True(t, strings.HasPrefix(w.Body.String(), "1\n00:00:00,000 --> "), w.Body.String())
+
+	w = suite.transcribe(suite.helper.TestAPIKey, map[string][]string{"response_format": {"vtt"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.True(t, strings.HasPrefix(w.Body.String(), "WEBVTT"), w.Body.String())
+
+	// A model family on its own is enough
+	w = suite.transcribe(suite.helper.TestAPIKey, map[string][]string{"model": {"nvidia_parakeet"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = suite.transcribe(suite.helper.TestAPIKey, map[string][]string{
+		"model":                     {"whisper:large-v3"},
+		"language":                  {"de"},
+		"response_format":           {"verbose_json"},
+		"timestamp_granularities[]": {"segment", "word"},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var verbose api.OpenAIVerboseTranscription
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &verbose))
+	assert.Equal(t, "transcribe", verbose.Task)
+	assert.Equal(t, "german", verbose.Language)
+	assert.Equal(t, float64(20), verbose.Duration)
+	require.Len(t, verbose.Segments, 4)
+	assert.Equal(t, 3, verbose.Segments[3].ID)
+	assert.Equal(t, float64(15), verbose.Segments[3].Start)
+	assert.NotEmpty(t, verbose.Words)
+
+	// Words alone leave out the segments
+	w = suite.transcribe(suite.helper.TestAPIKey, map[string][]string{"response_format": {"verbose_json"}, "timestamp_granularities[]": {"word"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	verbose = api.OpenAIVerboseTranscription{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &verbose))
+	assert.Empty(t, verbose.Segments)
+	assert.NotEmpty(t, verbose.Words)
+}
+
+func (suite *OpenAITestSuite) TestProfileModelAndQuota() {
+	t := suite.T()
+	suite.helper.CreateTestProfile(t, "openai-profile", false)
+
+	// Signed-in users have the audio counted against their monthly quota
+	w := suite.transcribe(suite.helper.TestToken, map[string][]string{"model": {"openai-profile"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	period, _ := quota.Period(time.Now())
+	used, err := quota.Used(suite.helper.TestUser.ID, period)
+	require.NoError(t, err)
+	assert.InDelta(t, 20.0/60, used[models.QuotaAudioMinutes], 0.001)
+}
+
+func (suite *OpenAITestSuite) TestQuickTranscriptionQuota() {
+	t := suite.T()
+	period, _ := quota.Period(time.Now())
+	before, err := quota.Used(suite.helper.TestUser.ID, period)
+	require.NoError(t, err)
+
+	// Quick transcriptions aren't saved, but their audio is counted all the same
+	w := suite.postAudio("/api/v1/transcription/quick", "audio", suite.helper.TestToken, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var job transcription.QuickTranscriptionJob
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	finished, err := suite.quick.WaitQuickJob(context.Background(), job.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.StatusCompleted, finished.Status)
+
+	used, err := quota.Used(suite.helper.TestUser.ID, period)
+	require.NoError(t, err)
+	assert.InDelta(t, 20.0/60, used[models.QuotaAudioMinutes]-before[models.QuotaAudioMinutes], 0.001)
+}
+
+func (suite *OpenAITestSuite) TestErrors() {
+	t := suite.T()
+	var resp api.OpenAIError
+
+	w := suite.transcribe(suite.helper.TestAPIKey, map[string][]string{"model": {"gpt-4o-transcribe"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "invalid_request_error", resp.Error.Type)
+	require.NotNil(t, resp.Error.Code)
+	assert.Equal(t, "model_not_found", *resp.Error.Code)
+	require.NotNil(t, resp.Error.Param)
+	assert.Equal(t, "model", *resp.Error.Param)
+
+	for name, fields := range map[string]map[string][]string{
+		"unknown whisper size": {"model": {"whisper:huge"}},
+		"unknown format":       {"response_format": {"docx"}},
+		"bad temperature":      {"temperature": {"2"}},
+		"bad granularity":      {"response_format": {"verbose_json"}, "timestamp_granularities[]": {"sentence"}},
+	} {
+		w := suite.transcribe(suite.helper.TestAPIKey, fields)
+		assert.Equal(t, http.StatusBadRequest, w.Code, name+": "+w.Body.String())
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, suite.transcribe("not-a-key", nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, suite.transcribe("", nil).Code)
+
+	req, err := http.NewRequest(http.MethodPost, "/v1/audio/transcriptions", strings.NewReader(""))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+suite.helper.TestAPIKey)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "file", *resp.Error.Param)
+}
+
+// chat posts a chat completion request, authenticating with bearer
+func (suite *OpenAITestSuite) chat(bearer string, body interface{}) *httptest.ResponseRecorder {
+	data, err := json.Marshal(body)
+	require.NoError(suite.T(), err)
+	req, err := http.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader(data))
+	require.NoError(suite.T(), err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+// chunks reads the server-sent event chunks of a streamed chat completion, checking it ends with [DONE]
+func (suite *OpenAITestSuite) chunks(w *httptest.ResponseRecorder) []api.OpenAIChatCompletion {
+	t := suite.T()
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/event-stream")
+	var chunks []api.OpenAIChatCompletion
+	done := false
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		require.False(t, done, "nothing follows [DONE]")
+		if data == "[DONE]" {
+			done = true
+			continue
+		}
+		var chunk api.OpenAIChatCompletion
+		require.NoError(t, json.Unmarshal([]byte(data), &chunk))
+		assert.Equal(t, "chat.completion.chunk", chunk.Object)
+		chunks = append(chunks, chunk)
+	}
+	assert.True(t, done)
+	return chunks
+}
+
+func (suite *OpenAITestSuite) TestRAGChatCompletion() {
+	t := suite.T()
+	job := suite.helper.CreateTestTranscriptionJob(t, "Release planning")
+	job.Status = models.StatusCompleted
+	job.Transcript = stringPtr(`{"text": "The release ships on Friday.", "segments": []}`)
+	require.NoError(t, suite.helper.DB.Save(job).Error)
+	require.NoError(t, suite.rag.StoreSummary(context.Background(), job.ID, "", "The release ships on Friday."))
+
+	conversation := []gin.H{
+		{"role": "system", "content": "You are a helpful assistant."},
+		{"role": "user", "content": "We talked about the launch."},
+		{"role": "assistant", "content": "Yes, in the planning meeting."},
+		{"role": "user", "content": []gin.H{{"type": "text", "text": "When does the release ship?"}}},
+	}
+	w := suite.chat(suite.helper.TestAPIKey, gin.H{"model": api.OpenAIRAGModel, "messages": conversation})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var completion api.OpenAIChatCompletion
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &completion))
+	assert.Equal(t, "chat.completion", completion.Object)
+	assert.Equal(t, api.OpenAIRAGModel, completion.Model)
+	assert.True(t, strings.HasPrefix(completion.ID, "chatcmpl-"))
+	require.Len(t, completion.Choices, 1)
+	assert.Equal(t, "assistant", completion.Choices[0].Message.Role)
+	assert.Equal(t, "The release ships on Friday.\n\nSources: Release planning", completion.Choices[0].Message.Content)
+	assert.Equal(t, []string{job.ID}, completion.Sources)
+	require.NotNil(t, completion.Usage)
+	assert.Positive(t, completion.Usage.TotalTokens)
+
+	// The conversation comes before the question, without the client's system prompt
+	assert.Contains(t, suite.llm.prompt, "We talked about the launch.")
+	assert.Contains(t, suite.llm.prompt, "Yes, in the planning meeting.")
+	assert.NotContains(t, suite.llm.prompt, "You are a helpful assistant.")
+	assert.Contains(t, suite.llm.prompt, "User question: When does the release ship?")
+
+	// Streamed, the answer comes in one chunk
+	w = suite.chat(suite.helper.TestAPIKey, gin.H{"model": api.OpenAIRAGModel, "messages": conversation, "stream": true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	chunks := suite.chunks(w)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, "assistant", chunks[0].Choices[0].Delta.Role)
+	assert.Equal(t, completion.Choices[0].Message.Content, chunks[0].Choices[0].Delta.Content)
+	require.NotNil(t, chunks[1].Choices[0].FinishReason)
+	assert.Equal(t, "stop", *chunks[1].Choices[0].FinishReason)
+
+	w = suite.chat(suite.helper.TestAPIKey, gin.H{"model": api.OpenAIRAGModel, "messages": []gin.H{{"role": "system", "content": "Hi"}}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func (suite *OpenAITestSuite) TestProxiedChatCompletion() {
+	t := suite.T()
+	messages := []gin.H{{"role": "user", "content": "Say hello"}}
+
+	w := suite.chat(suite.helper.TestAPIKey, gin.H{"model": llm.FakeModel, "messages": messages})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var completion api.OpenAIChatCompletion
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &completion))
+	require.Len(t, completion.Choices, 1)
+	assert.Equal(t, "[fake-model] Say hello", completion.Choices[0].Message.Content)
+	assert.Empty(t, completion.Sources)
+
+	w = suite.chat(suite.helper.TestAPIKey, gin.H{"model": llm.FakeModel, "messages": messages, "stream": true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var reply strings.Builder
+	for _, chunk := range suite.chunks(w) {
+		reply.WriteString(chunk.Choices[0].Delta.Content)
+	}
+	assert.Equal(t, "[fake-model] Say hello", reply.String())
+
+	// The models of the configured LLM are listed next to Scriberr's own
+	req, err := http.NewRequest(http.MethodGet, "/v1/models", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+suite.helper.TestAPIKey)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list struct {
+		Data []api.OpenAIModel `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	var ids []string
+	for _, model := range list.Data {
+		ids = append(ids, model.ID)
+	}
+	assert.Equal(t, []string{api.OpenAIRAGModel, api.OpenAIDefaultModel, llm.FakeModel}, ids)
+}
+
+func (suite *OpenAITestSuite) TestScopedKeysOnlyUseRAG() {
+	t := suite.T()
+	key := models.APIKey{Key: "openai-rag-chat-key", Name: "Chat client", IsActive: true, Scopes: []string{models.APIKeyScopeRAGChat}}
+	require.NoError(t, suite.helper.DB.Create(&key).Error)
+	messages := []gin.H{{"role": "user", "content": "Say hello"}}
+
+	w := suite.chat(key.Key, gin.H{"model": llm.FakeModel, "messages": messages})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	var resp api.OpenAIError
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "model_not_allowed", *resp.Error.Code)
+
+	w = suite.chat(key.Key, gin.H{"model": api.OpenAIRAGModel, "messages": messages})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Nor can they transcribe
+	assert.Equal(t, http.StatusForbidden, suite.transcribe(key.Key, nil).Code)
+}
+
+func TestOpenAITestSuite(t *testing.T) {
+	suite.Run(t, new(OpenAITestSuite))
+}
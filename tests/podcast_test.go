@@ -0,0 +1,225 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/podcasts"
+	"scriberr/internal/queue"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PodcastTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	queue  *queue.TaskQueue
+	router *gin.Engine
+	remote *httptest.Server
+
+	mu       sync.Mutex
+	episodes []int // Episode numbers in the feed, newest first
+}
+
+func (suite *PodcastTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "podcast_test.db")
+	suite.helper.Config.URLImportAllowPrivate = true // The test server is on loopback
+	suite.queue = queue.NewTaskQueue(1, &MockJobProcessor{})
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, suite.queue, nil, nil, nil)
+	handler.SetPodcastService(podcasts.NewService())
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/feed.xml", func(w http.ResponseWriter, r *http.Request) {
+		suite.mu.Lock()
+		defer suite.mu.Unlock()
+		etag := fmt.Sprintf(`"v%d"`, len(suite.episodes))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/rss+xml")
+		var items strings.Builder
+		for _, n := range suite.episodes {
+			fmt.Fprintf(&items, `<item><title>Episode %d</title><guid>ep-%d</guid><pubDate>%s</pubDate><enclosure url="%s/ep%d.mp3" type="audio/mpeg"/><itunes:duration>10:00</itunes:duration></item>`,
+				n, n, time.Date(2026, 1, n, 8, 0, 0, 0, time.UTC).Format(time.RFC1123Z), suite.remote.URL, n)
+		}
+		fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"><channel><title>Weekly Show</title>%s</channel></rss>`, items.String())
+	})
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html>Sign in</html>"))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write([]byte("ID3 episode " + r.URL.Path))
+	})
+	suite.remote = httptest.NewServer(mux)
+}
+
+func (suite *PodcastTestSuite) TearDownSuite() {
+	suite.remote.Close()
+	suite.helper.Cleanup()
+}
+
+func (suite *PodcastTestSuite) setEpisodes(episodes ...int) {
+	suite.mu.Lock()
+	defer suite.mu.Unlock()
+	suite.episodes = episodes
+}
+
+func (suite *PodcastTestSuite) subscribe(body gin.H) api.PodcastPollResponse {
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/podcasts", body)
+	require.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+	var resp api.PodcastPollResponse
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+// episodesOf waits for the feed's downloads to finish and returns its episodes by title
+func (suite *PodcastTestSuite) episodesOf(feedID string) map[string]api.PodcastEpisodeResponse {
+	t := suite.T()
+	byTitle := map[string]api.PodcastEpisodeResponse{}
+	require.Eventually(t, func() bool {
+		w := suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/podcasts/"+feedID+"/episodes", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Episodes []api.PodcastEpisodeResponse `json:"episodes"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		for _, episode := range resp.Episodes {
+			if episode.Status == "downloading" {
+				return false
+			}
+			byTitle[episode.Title] = episode
+		}
+		return true
+	}, 5*time.Second, 20*time.Millisecond)
+	return byTitle
+}
+
+func (suite *PodcastTestSuite) TestSubscribeAndPoll() {
+	t := suite.T()
+	suite.helper.CreateTestProfile(t, "Default", true)
+	suite.setEpisodes(3, 2, 1)
+
+	sub := suite.subscribe(gin.H{"url": suite.remote.URL + "/feed.xml", "backfill": 1})
+	assert.Equal(t, "Weekly Show", sub.Feed.Title)
+	assert.True(t, sub.Feed.AutoTranscribe)
+	assert.Equal(t, 3, sub.Poll.NewEpisodes)
+	assert.Equal(t, 1, sub.Poll.Imported, "only the newest is backfilled")
+
+	episodes := suite.episodesOf(sub.Feed.ID)
+	require.Len(t, episodes, 3)
+	latest := episodes["Episode 3"]
+	assert.Equal(t, 600, latest.DurationSeconds)
+	assert.Equal(t, string(models.StatusPending), latest.Status)
+	require.NotNil(t, latest.TranscriptionID)
+	assert.Equal(t, "available", episodes["Episode 1"].Status)
+	assert.Nil(t, episodes["Episode 1"].TranscriptionID)
+
+	var job models.TranscriptionJob
+	require.NoError(t, suite.helper.DB.First(&job, "id = ?", *latest.TranscriptionID).Error)
+	assert.Equal(t, models.ContentPodcast, job.ContentType)
+	require.NotNil(t, job.Title)
+	assert.Equal(t, "Episode 3", *job.Title)
+	require.NotNil(t, job.Source)
+	assert.Equal(t, "Weekly Show", job.Source.Channel)
+	assert.Contains(t, order(suite.queue), job.ID)
+
+	// Unchanged feeds aren't read again
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/podcasts/"+sub.Feed.ID+"/refresh", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var refresh api.PodcastPollResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &refresh))
+	assert.True(t, refresh.Poll.NotModified)
+	require.NotNil(t, refresh.Feed.LastPolledAt)
+	assert.Nil(t, refresh.Feed.LastError)
+
+	// A new episode is transcribed on the next poll
+	suite.setEpisodes(4, 3, 2, 1)
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/podcasts/"+sub.Feed.ID+"/refresh", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &refresh))
+	assert.False(t, refresh.Poll.NotModified)
+	assert.Equal(t, 1, refresh.Poll.NewEpisodes)
+	assert.Equal(t, 1, refresh.Poll.Imported)
+	episodes = suite.episodesOf(sub.Feed.ID)
+	assert.NotNil(t, episodes["Episode 4"].TranscriptionID)
+
+	// Older episodes can be transcribed on demand, once
+	older := episodes["Episode 1"]
+	path := "/api/v1/podcasts/" + sub.Feed.ID + "/episodes/" + older.ID + "/transcribe"
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, path, nil)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	episodes = suite.episodesOf(sub.Feed.ID)
+	require.NotNil(t, episodes["Episode 1"].TranscriptionID)
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, path, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Subscribing twice is refused
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/podcasts", gin.H{"url": suite.remote.URL + "/feed.xml"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Unsubscribing keeps the transcriptions
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodDelete, "/api/v1/podcasts/"+sub.Feed.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var remaining int64
+	suite.helper.DB.Model(&models.PodcastEpisode{}).Where("feed_id = ?", sub.Feed.ID).Count(&remaining)
+	assert.Zero(t, remaining)
+	assert.NoError(t, suite.helper.DB.First(&models.TranscriptionJob{}, "id = ?", job.ID).Error)
+}
+
+func (suite *PodcastTestSuite) TestSettings() {
+	t := suite.T()
+	suite.setEpisodes(2, 1)
+	sub := suite.subscribe(gin.H{"url": suite.remote.URL + "/feed.xml?listen", "auto_transcribe": false, "backfill": 2})
+	assert.False(t, sub.Feed.AutoTranscribe)
+	assert.Equal(t, 2, sub.Poll.NewEpisodes)
+	assert.Zero(t, sub.Poll.Imported, "episodes are only listed")
+
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPut, "/api/v1/podcasts/"+sub.Feed.ID, gin.H{"paused": true, "priority": 5})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var feed models.PodcastFeed
+	require.NoError(t, suite.helper.DB.First(&feed, "id = ?", sub.Feed.ID).Error)
+	assert.True(t, feed.Paused)
+	assert.True(t, feed.AutoTranscribe)
+	assert.Equal(t, 5, feed.Priority)
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPut, "/api/v1/podcasts/"+sub.Feed.ID, gin.H{"job_template_id": "missing"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/podcasts", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), sub.Feed.ID)
+}
+
+func (suite *PodcastTestSuite) TestInvalidFeeds() {
+	t := suite.T()
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/podcasts", gin.H{"url": suite.remote.URL + "/page"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var count int64
+	suite.helper.DB.Model(&models.PodcastFeed{}).Where("url = ?", suite.remote.URL+"/page").Count(&count)
+	assert.Zero(t, count, "a feed that can't be read isn't kept")
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/podcasts", gin.H{"url": "ftp://example.com/feed"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/podcasts", gin.H{"url": suite.remote.URL + "/feed.xml?many", "backfill": 21})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPodcastTestSuite(t *testing.T) {
+	suite.Run(t, new(PodcastTestSuite))
+}
@@ -0,0 +1,246 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/notify"
+	"scriberr/internal/queue"
+	"scriberr/internal/workflow"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProjectTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	router *gin.Engine
+}
+
+func (suite *ProjectTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "project_test.db")
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, queue.NewTaskQueue(1, &MockJobProcessor{}), nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *ProjectTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// create creates a project and returns it
+func (suite *ProjectTestSuite) create(body gin.H) models.Project {
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/projects", body)
+	require.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+	var project models.Project
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &project))
+	return project
+}
+
+// listJobs returns the IDs of the transcriptions listed with the query
+func (suite *ProjectTestSuite) listJobs(query string) []string {
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/transcription/list?"+query, nil)
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Jobs []models.TranscriptionJob `json:"jobs"`
+	}
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &resp))
+	ids := []string{}
+	for _, job := range resp.Jobs {
+		ids = append(ids, job.ID)
+	}
+	return ids
+}
+
+func (suite *ProjectTestSuite) upload(fields map[string]string) *httptest.ResponseRecorder {
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, err := writer.CreateFormFile("audio", "interview.mp3")
+	require.NoError(suite.T(), err)
+	part.Write([]byte("fake audio"))
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+	require.NoError(suite.T(), writer.Close())
+	req := suite.helper.NewRequest(suite.T(), suite.helper.TestAPIKey, http.MethodPost, "/api/v1/transcription/upload", &form)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return serve(suite.router, req)
+}
+
+func (suite *ProjectTestSuite) TestHierarchy() {
+	t := suite.T()
+	clients := suite.create(gin.H{"name": "Clients"})
+	acme := suite.create(gin.H{"name": "Acme", "parent_id": clients.ID})
+	q3 := suite.create(gin.H{"name": "Q3", "parent_id": acme.ID})
+	suite.create(gin.H{"name": "Acme"}) // Names only clash between siblings
+
+	assert.Equal(t, http.StatusConflict, suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/projects", gin.H{"name": "acme", "parent_id": clients.ID}).Code)
+	assert.Equal(t, http.StatusBadRequest, suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/projects", gin.H{"name": "Orphan", "parent_id": "missing"}).Code)
+
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/projects", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var tree struct {
+		Projects []api.ProjectNode `json:"projects"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tree))
+	roots := map[string]*api.ProjectNode{}
+	for i := range tree.Projects {
+		roots[tree.Projects[i].Name] = &tree.Projects[i]
+	}
+	require.Contains(t, roots, "Acme")
+	assert.Empty(t, roots["Acme"].Children)
+	require.Contains(t, roots, "Clients")
+	require.Len(t, roots["Clients"].Children, 1)
+	assert.Equal(t, acme.ID, roots["Clients"].Children[0].ID)
+	require.Len(t, roots["Clients"].Children[0].Children, 1)
+	assert.Equal(t, q3.ID, roots["Clients"].Children[0].Children[0].ID)
+
+	// A project can't be moved into itself or below itself
+	assert.Equal(t, http.StatusBadRequest, suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/projects/"+clients.ID+"/move", gin.H{"parent_id": q3.ID}).Code)
+	assert.Equal(t, http.StatusBadRequest, suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/projects/"+acme.ID+"/move", gin.H{"parent_id": acme.ID}).Code)
+	// Nor next to a project of the same name
+	assert.Equal(t, http.StatusConflict, suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/projects/"+acme.ID+"/move", gin.H{"parent_id": nil}).Code)
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/projects/"+q3.ID+"/move", gin.H{"parent_id": clients.ID})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/projects/"+q3.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var detail struct {
+		Path []struct {
+			Name string `json:"name"`
+		} `json:"path"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &detail))
+	require.Len(t, detail.Path, 1)
+	assert.Equal(t, "Clients", detail.Path[0].Name)
+
+	// Deleting a project moves what was in it up a level
+	job := suite.helper.CreateTestTranscriptionJob(t, "Kickoff")
+	require.Equal(t, http.StatusOK, suite.helper.Request(suite.T(), suite.router, http.MethodPut, "/api/v1/transcription/"+job.ID+"/project", gin.H{"project_id": q3.ID}).Code)
+	require.Equal(t, http.StatusOK, suite.helper.Request(suite.T(), suite.router, http.MethodDelete, "/api/v1/projects/"+q3.ID, nil).Code)
+	var moved models.TranscriptionJob
+	require.NoError(t, suite.helper.DB.First(&moved, "id = ?", job.ID).Error)
+	require.NotNil(t, moved.ProjectID)
+	assert.Equal(t, clients.ID, *moved.ProjectID)
+	assert.Equal(t, http.StatusNotFound, suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/projects/"+q3.ID, nil).Code)
+}
+
+func (suite *ProjectTestSuite) TestFilingAndListing() {
+	t := suite.T()
+	research := suite.create(gin.H{"name": "Research"})
+	interviews := suite.create(gin.H{"name": "Interviews", "parent_id": research.ID})
+	first := suite.helper.CreateTestTranscriptionJob(t, "Interview 1")
+	second := suite.helper.CreateTestTranscriptionJob(t, "Interview 2")
+	overview := suite.helper.CreateTestTranscriptionJob(t, "Overview")
+
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/projects/"+interviews.ID+"/transcriptions", gin.H{"transcription_ids": []string{first.ID, second.ID, first.ID}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"filed":2`)
+	require.Equal(t, http.StatusOK, suite.helper.Request(suite.T(), suite.router, http.MethodPut, "/api/v1/transcription/"+overview.ID+"/project", gin.H{"project_id": research.ID}).Code)
+	assert.Equal(t, http.StatusNotFound, suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/projects/"+interviews.ID+"/transcriptions", gin.H{"transcription_ids": []string{"missing"}}).Code)
+
+	assert.ElementsMatch(t, []string{first.ID, second.ID}, suite.listJobs("project="+interviews.ID))
+	assert.ElementsMatch(t, []string{overview.ID}, suite.listJobs("project="+research.ID))
+	assert.ElementsMatch(t, []string{first.ID, second.ID, overview.ID}, suite.listJobs("project="+research.ID+"&subprojects=true"))
+	assert.NotContains(t, suite.listJobs("project=none&limit=1000"), first.ID)
+
+	// Taking a transcription out of its project
+	require.Equal(t, http.StatusOK, suite.helper.Request(suite.T(), suite.router, http.MethodPut, "/api/v1/transcription/"+second.ID+"/project", gin.H{"project_id": nil}).Code)
+	assert.Contains(t, suite.listJobs("project=none&limit=1000"), second.ID)
+	assert.Equal(t, http.StatusBadRequest, suite.helper.Request(suite.T(), suite.router, http.MethodPut, "/api/v1/transcription/"+second.ID+"/project", gin.H{"project_id": "missing"}).Code)
+	assert.Equal(t, http.StatusNotFound, suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/transcription/list?project=missing", nil).Code)
+
+	// Other users' projects can't be used
+	other := models.Project{UserID: &suite.helper.TestUser.ID, Name: "Private"}
+	require.NoError(t, suite.helper.DB.Create(&other).Error)
+	assert.Equal(t, http.StatusNotFound, suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/projects/"+other.ID, nil).Code)
+	assert.Equal(t, http.StatusBadRequest, suite.helper.Request(suite.T(), suite.router, http.MethodPut, "/api/v1/transcription/"+second.ID+"/project", gin.H{"project_id": other.ID}).Code)
+}
+
+func (suite *ProjectTestSuite) TestUploadSettings() {
+	t := suite.T()
+	template := suite.helper.CreateTestSummaryTemplate(t, "Interview notes")
+	var received []notify.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event notify.Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err == nil {
+			received = append(received, event)
+		}
+	}))
+	defer server.Close()
+
+	podcast := suite.create(gin.H{
+		"name":                "Podcast",
+		"summary_template_id": template.ID,
+		"content_type":        "podcast",
+		"tags":                []string{"podcast", " Podcast "},
+		"webhook_urls":        []string{server.URL},
+	})
+	assert.Equal(t, []string{"podcast"}, podcast.Tags)
+	season := suite.create(gin.H{"name": "Season 2", "parent_id": podcast.ID, "tags": []string{"season-2"}})
+
+	// Subprojects inherit the settings they leave empty
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/projects/"+season.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var detail struct {
+		Settings models.ProjectSettings `json:"settings"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &detail))
+	assert.Equal(t, "podcast", detail.Settings.ContentType)
+	assert.Equal(t, []string{"season-2"}, detail.Settings.Tags)
+	require.NotNil(t, detail.Settings.SummaryTemplateID)
+	assert.Equal(t, template.ID, *detail.Settings.SummaryTemplateID)
+
+	w = suite.upload(map[string]string{"project_id": season.ID, "title": "Episode 1"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var job models.TranscriptionJob
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	require.NotNil(t, job.ProjectID)
+	assert.Equal(t, season.ID, *job.ProjectID)
+	assert.Equal(t, models.ContentPodcast, job.ContentType)
+	assert.Equal(t, []string{"season-2"}, job.Tags)
+
+	// Post-processing uses the project's summary template and webhooks
+	resolved, err := workflow.ResolveSummaryTemplate(&job, "")
+	require.NoError(t, err)
+	require.NotNil(t, resolved)
+	assert.Equal(t, template.ID, resolved.ID)
+	_, err = (&workflow.NotifyStep{AllowPrivateWebhooks: true}).Run(context.Background(), &workflow.RunContext{
+		Run: &models.WorkflowRun{ID: "run", Workflow: "default"},
+		Job: &job,
+	})
+	require.NoError(t, err)
+	require.Len(t, received, 1)
+	assert.Equal(t, job.ID, received[0].TranscriptionID)
+
+	// An upload's own content type wins
+	w = suite.upload(map[string]string{"project_id": podcast.ID, "content_type": "voice_memo"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, models.ContentVoiceMemo, job.ContentType)
+	assert.Equal(t, []string{"podcast"}, job.Tags)
+
+	assert.Equal(t, http.StatusBadRequest, suite.upload(map[string]string{"project_id": "missing"}).Code)
+	for _, body := range []gin.H{
+		{"name": "Bad type", "content_type": "lecture"},
+		{"name": "Bad template", "summary_template_id": "missing"},
+		{"name": "Bad webhook", "webhook_urls": []string{"ftp://example.com"}},
+		{"name": " "},
+	} {
+		w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/projects", body)
+		assert.Contains(t, []int{http.StatusBadRequest, http.StatusNotFound}, w.Code, body)
+	}
+}
+
+func TestProjectTestSuite(t *testing.T) {
+	suite.Run(t, new(ProjectTestSuite))
+}
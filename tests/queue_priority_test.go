@@ -0,0 +1,215 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type QueuePriorityTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *QueuePriorityTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "queue_priority_test.db")
+}
+
+func (suite *QueuePriorityTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// job creates a pending transcription with a priority
+func (suite *QueuePriorityTestSuite) job(title string, priority int) *models.TranscriptionJob {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), title)
+	job.Priority = priority
+	require.NoError(suite.T(), suite.helper.DB.Save(job).Error)
+	return job
+}
+
+// order returns the IDs of the queued jobs, next first
+func order(tq *queue.TaskQueue) []string {
+	var ids []string
+	for _, job := range tq.Queued() {
+		ids = append(ids, job.ID)
+	}
+	return ids
+}
+
+func (suite *QueuePriorityTestSuite) savedPriority(jobID string) int {
+	var job models.TranscriptionJob
+	require.NoError(suite.T(), suite.helper.DB.First(&job, "id = ?", jobID).Error)
+	return job.Priority
+}
+
+func (suite *QueuePriorityTestSuite) TestJobsRunByPriorityThenSubmission() {
+	t := suite.T()
+	tq := queue.NewTaskQueue(1, &MockJobProcessor{})
+	low := suite.job("Low", -1)
+	first := suite.job("First", 0)
+	second := suite.job("Second", 0)
+	urgent := suite.job("Urgent", 5)
+	for _, job := range []*models.TranscriptionJob{low, first, second, urgent} {
+		require.NoError(t, tq.EnqueueJob(job.ID))
+	}
+	require.NoError(t, tq.EnqueueJob(first.ID), "a waiting job keeps its place")
+	assert.Equal(t, []string{urgent.ID, first.ID, second.ID, low.ID}, order(tq))
+	assert.Equal(t, 4, tq.Position(low.ID))
+	assert.Zero(t, tq.Position("missing"))
+
+	require.NoError(t, tq.SetPriority(low.ID, 10))
+	assert.Equal(t, []string{low.ID, urgent.ID, first.ID, second.ID}, order(tq))
+	assert.Equal(t, 10, suite.savedPriority(low.ID))
+	assert.Error(t, tq.SetPriority(low.ID, queue.MaxPriority+1))
+}
+
+func (suite *QueuePriorityTestSuite) TestMoveTakesTheNeighboursPriority() {
+	t := suite.T()
+	tq := queue.NewTaskQueue(1, &MockJobProcessor{})
+	a := suite.job("A", 0)
+	b := suite.job("B", 0)
+	c := suite.job("C", 0)
+	top := suite.job("Top", 3)
+	for _, job := range []*models.TranscriptionJob{a, b, c, top} {
+		require.NoError(t, tq.EnqueueJob(job.ID))
+	}
+
+	moved, err := tq.Move(c.ID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, queue.QueuedJob{ID: c.ID, Priority: 0, Position: 2}, moved)
+	assert.Equal(t, []string{top.ID, c.ID, a.ID, b.ID}, order(tq))
+
+	// Dropped between jobs of a lower priority, a job takes theirs
+	moved, err = tq.Move(top.ID, 3)
+	require.NoError(t, err)
+	assert.Equal(t, queue.QueuedJob{ID: top.ID, Priority: 0, Position: 3}, moved)
+	assert.Equal(t, []string{c.ID, a.ID, top.ID, b.ID}, order(tq))
+	assert.Equal(t, 0, suite.savedPriority(top.ID))
+
+	moved, err = tq.Move(c.ID, 99)
+	require.NoError(t, err)
+	assert.Equal(t, 4, moved.Position)
+	assert.Equal(t, []string{a.ID, top.ID, b.ID, c.ID}, order(tq))
+	require.NoError(t, tq.EnqueueJob(suite.job("Later", 0).ID))
+	assert.Equal(t, 4, tq.Position(c.ID), "moved jobs keep their place as new ones are queued")
+
+	_, err = tq.Move("missing", 1)
+	assert.ErrorIs(t, err, queue.ErrNotQueued)
+}
+
+func (suite *QueuePriorityTestSuite) TestPausedQueueStartsNothing() {
+	t := suite.T()
+	job := suite.job("Paused", 0)
+	mockProcessor := &MockJobProcessor{}
+	mockProcessor.On("ProcessJobWithProcess", mock.Anything, mock.Anything).Return(nil)
+
+	tq := queue.NewTaskQueue(1, mockProcessor)
+	tq.Pause()
+	tq.Start()
+	defer tq.Stop()
+	require.NoError(t, tq.EnqueueJob(job.ID))
+
+	time.Sleep(100 * time.Millisecond)
+	mockProcessor.AssertNotCalled(t, "ProcessJobWithProcess", mock.Anything, job.ID, mock.Anything)
+	assert.Equal(t, 1, tq.Position(job.ID))
+	assert.Equal(t, true, tq.GetQueueStats()["paused"])
+
+	tq.Resume()
+	assert.Eventually(t, func() bool {
+		updated, err := tq.GetJobStatus(job.ID)
+		return err == nil && updated.Status == models.StatusCompleted
+	}, 2*time.Second, 20*time.Millisecond)
+}
+
+func (suite *QueuePriorityTestSuite) TestSetConcurrency() {
+	t := suite.T()
+	tq := queue.NewTaskQueue(1, &MockJobProcessor{})
+	tq.Start()
+	defer tq.Stop()
+
+	assert.Error(t, tq.SetConcurrency(0))
+	assert.Error(t, tq.SetConcurrency(queue.MaxConcurrency+1))
+
+	require.NoError(t, tq.SetConcurrency(3))
+	stats := tq.GetQueueStats()
+	assert.Equal(t, 3, stats["current_workers"])
+	assert.Equal(t, false, stats["auto_scale"])
+
+	require.NoError(t, tq.SetConcurrency(1))
+	assert.Equal(t, 1, tq.GetQueueStats()["current_workers"])
+}
+
+func (suite *QueuePriorityTestSuite) TestQueueEndpoints() {
+	t := suite.T()
+	tq := queue.NewTaskQueue(1, &MockJobProcessor{})
+	router := api.SetupRoutes(api.NewHandler(suite.helper.Config, suite.helper.AuthService, tq, nil, nil, nil), suite.helper.AuthService)
+
+	backlog := suite.job("Backlog", 0)
+	rush := suite.job("Rush", 0)
+	require.NoError(t, tq.EnqueueJob(backlog.ID))
+	require.NoError(t, tq.EnqueueJob(rush.ID))
+
+	w := suite.helper.Request(t, router, "PUT", "/api/v1/transcription/"+rush.ID+"/priority", map[string]int{"priority": 4})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"id":"`+rush.ID+`","priority":4,"position":1}`, w.Body.String())
+	w = suite.helper.Request(t, router, "PUT", "/api/v1/transcription/"+rush.ID+"/priority", map[string]int{"priority": 11})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = suite.helper.Request(t, router, "PUT", "/api/v1/transcription/missing/priority", map[string]int{"priority": 1})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = suite.helper.Request(t, router, "GET", "/api/v1/transcription/"+backlog.ID+"/queue", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var position struct {
+		Position int `json:"position"`
+		Queued   int `json:"queued"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &position))
+	assert.Equal(t, 2, position.Position)
+	assert.Equal(t, 2, position.Queued)
+
+	w = suite.helper.Request(t, router, "POST", "/api/v1/admin/queue/"+backlog.ID+"/move", map[string]int{"position": 1})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"id":"`+backlog.ID+`","priority":4,"position":1}`, w.Body.String())
+	w = suite.helper.Request(t, router, "POST", "/api/v1/admin/queue/missing/move", map[string]int{"position": 1})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = suite.helper.Request(t, router, "POST", "/api/v1/admin/queue/pause", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	w = suite.helper.Request(t, router, "GET", "/api/v1/admin/queue", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var listed struct {
+		Jobs []struct {
+			ID       string `json:"id"`
+			Position int    `json:"position"`
+			Title    string `json:"title"`
+		} `json:"jobs"`
+		Paused bool `json:"paused"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	assert.True(t, listed.Paused)
+	require.Len(t, listed.Jobs, 2)
+	assert.Equal(t, backlog.ID, listed.Jobs[0].ID)
+	assert.Equal(t, "Backlog", listed.Jobs[0].Title)
+	assert.Equal(t, 2, listed.Jobs[1].Position)
+
+	w = suite.helper.Request(t, router, "POST", "/api/v1/admin/queue/resume", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, tq.IsPaused())
+
+	w = suite.helper.Request(t, router, "PUT", "/api/v1/admin/queue/concurrency", map[string]int{"workers": 64})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestQueuePriorityTestSuite(t *testing.T) {
+	suite.Run(t, new(QueuePriorityTestSuite))
+}
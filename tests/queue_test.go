@@ -13,6 +13,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -119,6 +120,33 @@ func (suite *QueueTestSuite) TestJobProcessing() {
 	assert.Equal(suite.T(), models.StatusCompleted, updatedJob.Status)
 }
 
+// A job that is no longer pending when a worker gets to it, e.g. because another node's
+// worker took it, isn't processed twice
+func (suite *QueueTestSuite) TestClaimSkipsJobNoLongerPending() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Claimed Elsewhere")
+	require.NoError(suite.T(), suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status":    models.StatusProcessing,
+		"worker_id": "other-node/worker-0",
+	}).Error)
+
+	mockProcessor := &MockJobProcessor{}
+	mockProcessor.On("ProcessJobWithProcess", mock.Anything, mock.Anything).Return(nil)
+	tq := queue.NewTaskQueue(1, mockProcessor)
+	tq.Start()
+	defer tq.Stop()
+
+	require.NoError(suite.T(), tq.EnqueueJob(job.ID))
+	time.Sleep(100 * time.Millisecond)
+
+	mockProcessor.AssertNotCalled(suite.T(), "ProcessJobWithProcess", mock.Anything, job.ID)
+	assert.False(suite.T(), tq.IsJobRunning(job.ID))
+	var stored models.TranscriptionJob
+	require.NoError(suite.T(), suite.helper.DB.First(&stored, "id = ?", job.ID).Error)
+	assert.Equal(suite.T(), models.StatusProcessing, stored.Status)
+	require.NotNil(suite.T(), stored.WorkerID)
+	assert.Equal(suite.T(), "other-node/worker-0", *stored.WorkerID)
+}
+
 // Test job processing failure
 func (suite *QueueTestSuite) TestJobProcessingFailure() {
 	mockProcessor := &MockJobProcessor{}
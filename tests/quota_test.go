@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+	"scriberr/internal/quota"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type QuotaTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	router *gin.Engine
+}
+
+func (suite *QuotaTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "quota_test.db")
+	suite.helper.Config.QuotaAudioMinutes = 60
+	suite.helper.Config.RateLimitAnonymousPerMinute = 3
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, queue.NewTaskQueue(1, &MockJobProcessor{}), nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *QuotaTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// createUser creates a member with the given limits and returns it with a token for it
+func (suite *QuotaTestSuite) createUser(username string, limits gin.H) (models.User, string) {
+	t := suite.T()
+	w := suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodPost, "/api/v1/admin/users", gin.H{"username": username, "password": "password123"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var user models.User
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+	if len(limits) > 0 {
+		w = suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodPut, fmt.Sprintf("/api/v1/admin/users/%d", user.ID), limits)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+	token, err := suite.helper.AuthService.GenerateToken(&user)
+	require.NoError(t, err)
+	return user, token
+}
+
+// status returns a caller's quota status
+func (suite *QuotaTestSuite) status(credential string) quota.Status {
+	w := suite.helper.RequestAs(suite.T(), suite.router, credential, http.MethodGet, "/api/v1/usage/quota", nil)
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+	var status quota.Status
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &status))
+	return status
+}
+
+func (suite *QuotaTestSuite) TestRateLimit() {
+	t := suite.T()
+	_, token := suite.createUser("rita", gin.H{"rate_limit_per_minute": 3})
+	for i := 0; i < 3; i++ {
+		require.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, token, http.MethodGet, "/api/v1/transcription/list", nil).Code)
+	}
+	w := suite.helper.RequestAs(suite.T(), suite.router, token, http.MethodGet, "/api/v1/transcription/list", nil)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "20", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"rate_limit_per_minute":3`)
+
+	// The user's API keys share their limit
+	w = suite.helper.RequestAs(suite.T(), suite.router, token, http.MethodPost, "/api/v1/api-keys/", gin.H{"name": "Script"})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	// Admins' own requests are unlimited by default
+	for i := 0; i < 5; i++ {
+		require.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodGet, "/api/v1/transcription/list", nil).Code)
+	}
+}
+
+func (suite *QuotaTestSuite) TestAPIKeyRateLimit() {
+	t := suite.T()
+	w := suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodPost, "/api/v1/api-keys/", gin.H{"name": "Poller", "rate_limit_per_minute": 2})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var key models.APIKey
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &key))
+	require.NotNil(t, key.RateLimitPerMinute)
+
+	assert.Equal(t, 2, suite.status(key.Key).RateLimitPerMinute)
+	assert.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, key.Key, http.MethodGet, "/api/v1/transcription/list", nil).Code)
+	assert.Equal(t, http.StatusTooManyRequests, suite.helper.RequestAs(suite.T(), suite.router, key.Key, http.MethodGet, "/api/v1/transcription/list", nil).Code)
+	// The user's other credentials aren't affected
+	assert.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodGet, "/api/v1/transcription/list", nil).Code)
+
+	w = suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodPost, "/api/v1/api-keys/", gin.H{"name": "Bad", "rate_limit_per_minute": -1})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func (suite *QuotaTestSuite) TestAnonymousRateLimit() {
+	t := suite.T()
+	anonymous := func(ip, method, path string, body interface{}) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = ip + ":40000"
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+	guess := gin.H{"username": suite.helper.TestUser.Username, "password": "guess"}
+	for i := 0; i < 3; i++ {
+		require.Equal(t, http.StatusUnauthorized, anonymous("203.0.113.7", http.MethodPost, "/api/v1/auth/login", guess).Code)
+	}
+	w := anonymous("203.0.113.7", http.MethodPost, "/api/v1/auth/login", guess)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), `"rate_limit_per_minute":3`)
+	// Shared link passwords share the address's limit
+	assert.Equal(t, http.StatusTooManyRequests, anonymous("203.0.113.7", http.MethodGet, "/api/v1/shared/guess?password=guess", nil).Code)
+
+	// Other addresses, and callers with credentials, aren't affected
+	assert.Equal(t, http.StatusUnauthorized, anonymous("203.0.113.8", http.MethodPost, "/api/v1/auth/login", guess).Code)
+	assert.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodGet, "/api/v1/transcription/list", nil).Code)
+}
+
+func (suite *QuotaTestSuite) TestQuotas() {
+	t := suite.T()
+	user, token := suite.createUser("quinn", gin.H{"quota_llm_calls": 2})
+
+	status := suite.status(token)
+	require.Len(t, status.Quotas, 2)
+	assert.Equal(t, models.QuotaAudioMinutes, status.Quotas[0].Metric)
+	assert.Equal(t, 60, status.Quotas[0].Limit, "the instance default")
+	assert.Equal(t, 2, status.Quotas[1].Limit, "the user's own")
+	assert.Zero(t, status.RateLimitPerMinute)
+
+	// Transcribed audio counts against the quota
+	job := suite.helper.CreateTestTranscriptionJob(t, "Long interview")
+	require.NoError(t, suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"user_id":    user.ID,
+		"transcript": `{"segments":[{"start":0,"end":1800,"text":"First half."},{"start":1800,"end":3600,"text":"Second half."}]}`,
+	}).Error)
+	quota.RecordTranscription(job.ID)
+	status = suite.status(token)
+	assert.InDelta(t, 60, status.Quotas[0].Used, 0.001)
+	assert.True(t, status.Quotas[0].Exceeded)
+
+	w := suite.helper.RequestAs(suite.T(), suite.router, token, http.MethodPost, "/api/v1/transcription/upload", nil)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"metric":"audio_minutes"`)
+
+	// LLM requests too
+	require.NoError(t, quota.Add(user.ID, models.QuotaLLMCalls, 2))
+	w = suite.helper.RequestAs(suite.T(), suite.router, token, http.MethodPost, "/api/v1/rag/chat", gin.H{"question": "What was decided?"})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), `"metric":"llm_calls"`)
+
+	// Admins can lift a quota, or go back to the default
+	w = suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodPut, fmt.Sprintf("/api/v1/admin/users/%d", user.ID), gin.H{"quota_audio_minutes": 0, "quota_llm_calls": -1})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	status = suite.status(token)
+	assert.Zero(t, status.Quotas[0].Limit)
+	assert.Nil(t, status.Quotas[0].Remaining)
+	assert.Zero(t, status.Quotas[1].Limit, "no default LLM quota")
+	assert.Equal(t, http.StatusBadRequest, suite.helper.RequestAs(suite.T(), suite.router, token, http.MethodPost, "/api/v1/transcription/upload", nil).Code, "no file, but allowed")
+
+	// Transcriptions without an owner aren't metered
+	unowned := suite.helper.CreateTestTranscriptionJob(t, "Dropzone upload")
+	quota.RecordTranscription(unowned.ID)
+	assert.InDelta(t, 60, suite.status(token).Quotas[0].Used, 0.001)
+}
+
+func TestQuotaTestSuite(t *testing.T) {
+	suite.Run(t, new(QuotaTestSuite))
+}
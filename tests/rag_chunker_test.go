@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"scriberr/internal/embeddings"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/internal/vectordb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// agendaChunker chunks a transcript into one chunk per agenda item of its initial prompt,
+// where an item starts with the segment that names it
+type agendaChunker struct {
+	requests []rag.ChunkRequest
+	err      error
+}
+
+func (c *agendaChunker) Chunk(ctx context.Context, req rag.ChunkRequest) ([]rag.TranscriptChunk, error) {
+	c.requests = append(c.requests, req)
+	if c.err != nil {
+		return nil, c.err
+	}
+	items := strings.Split(strings.TrimPrefix(req.InitialPrompt, "Agenda: "), ", ")
+	var chunks []rag.TranscriptChunk
+	for _, segment := range req.Segments {
+		for _, item := range items {
+			if strings.Contains(segment.Text, item) {
+				chunks = append(chunks, rag.TranscriptChunk{
+					Start:    segment.Start,
+					Metadata: map[string]interface{}{"agenda_item": item, "user_id": 999},
+				})
+			}
+		}
+		last := &chunks[len(chunks)-1]
+		last.End = segment.End
+		last.Text = strings.TrimSpace(last.Text + " " + segment.Text)
+	}
+	return chunks, nil
+}
+
+type RAGChunkerTestSuite struct {
+	suite.Suite
+	helper  *TestHelper
+	store   *vectordb.MemoryStore
+	rag     *rag.RAGService
+	chunker *agendaChunker
+}
+
+func (suite *RAGChunkerTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "rag_chunker_test.db")
+}
+
+func (suite *RAGChunkerTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *RAGChunkerTestSuite) SetupTest() {
+	suite.store = vectordb.NewMemoryStore()
+	suite.rag = rag.NewRAGService(suite.store, embeddings.NewFakeEmbeddingService(), llm.NewFakeService())
+	suite.chunker = &agendaChunker{}
+	suite.rag.SetChunker(suite.chunker, true)
+}
+
+func (suite *RAGChunkerTestSuite) index(job *models.TranscriptionJob, texts ...string) error {
+	speaker := "SPEAKER_00"
+	result := interfaces.TranscriptResult{}
+	for i, text := range texts {
+		result.Segments = append(result.Segments, interfaces.TranscriptSegment{
+			Start: float64(i * 10), End: float64(i*10 + 10), Text: text, Speaker: &speaker,
+		})
+	}
+	data, err := json.Marshal(result)
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"transcript": string(data),
+		"status":     models.StatusCompleted,
+	}).Error)
+	return suite.rag.StoreSummary(context.Background(), job.ID, "", strings.Join(texts, " "))
+}
+
+func (suite *RAGChunkerTestSuite) chunkMetadata(jobID string) []map[string]interface{} {
+	var user models.User
+	require.NoError(suite.T(), suite.helper.DB.First(&user).Error)
+	docs, err := suite.store.GetDocuments(rag.CollectionName(&user.ID), vectordb.GetRequest{
+		Where: map[string]interface{}{"transcription_id": jobID, "type": "transcript_chunk"},
+	})
+	require.NoError(suite.T(), err)
+	return docs.Metadatas
+}
+
+// meeting creates a job whose initial prompt carries the agenda and indexes it
+func (suite *RAGChunkerTestSuite) meeting() *models.TranscriptionJob {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Board meeting")
+	prompt := "Agenda: Budget, Hiring"
+	require.NoError(suite.T(), suite.helper.DB.Model(job).Update("initial_prompt", prompt).Error)
+	require.NoError(suite.T(), suite.index(job, "First the Budget.", "It is tight.", "Next, Hiring.", "We need two engineers."))
+	return job
+}
+
+func (suite *RAGChunkerTestSuite) TestTranscriptsAreChunkedByTheChunker() {
+	t := suite.T()
+	job := suite.meeting()
+
+	require.Len(t, suite.chunker.requests, 1)
+	req := suite.chunker.requests[0]
+	assert.Equal(t, job.ID, req.TranscriptionID)
+	assert.Equal(t, "Board meeting", req.Title)
+	assert.Equal(t, "Agenda: Budget, Hiring", req.InitialPrompt)
+	assert.Positive(t, req.ChunkSize)
+	require.Len(t, req.Segments, 4)
+	assert.Equal(t, "SPEAKER_00", req.Segments[0].Speaker)
+
+	chunks := suite.chunkMetadata(job.ID)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, "Budget", chunks[0]["agenda_item"])
+	assert.Equal(t, "Hiring", chunks[1]["agenda_item"])
+	assert.EqualValues(t, 20, chunks[1]["start"])
+	assert.EqualValues(t, 40, chunks[1]["end"])
+	for _, metadata := range chunks {
+		assert.Equal(t, job.ID, metadata["transcription_id"])
+		assert.NotEqualValues(t, 999, metadata["user_id"], "a chunker can't set reserved metadata")
+	}
+}
+
+func (suite *RAGChunkerTestSuite) TestChunkerFailureFallsBack() {
+	t := suite.T()
+	suite.chunker.err = errors.New("segmenter is down")
+	job := suite.meeting()
+	chunks := suite.chunkMetadata(job.ID)
+	require.Len(t, chunks, 1, "the built-in chunker keeps these short segments together")
+	assert.Nil(t, chunks[0]["agenda_item"])
+
+	suite.rag.SetChunker(suite.chunker, false)
+	job = suite.helper.CreateTestTranscriptionJob(t, "Unindexed")
+	err := suite.index(job, "Hello.")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "segmenter is down")
+}
+
+func TestRAGChunkerTestSuite(t *testing.T) {
+	suite.Run(t, new(RAGChunkerTestSuite))
+}
@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"scriberr/internal/embeddings"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/vectordb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// translatingLLM names the language of questions as English, translates excerpts and keeps
+// the prompt of the final answer
+type translatingLLM struct {
+	translations int
+	detections   int
+	prompt       string
+}
+
+func (l *translatingLLM) ChatCompletion(ctx context.Context, model string, messages []llm.ChatMessage, temperature float64) (*llm.ChatResponse, error) {
+	content := messages[0].Content
+	switch {
+	case strings.HasPrefix(content, "Which language"):
+		l.detections++
+		return (&replyLLM{reply: `{"language": "English"}`}).ChatCompletion(ctx, model, messages, temperature)
+	case strings.HasPrefix(content, "Translate the following excerpt"):
+		l.translations++
+		return (&replyLLM{reply: "We lower the price in March."}).ChatCompletion(ctx, model, messages, temperature)
+	}
+	l.prompt = content
+	return (&replyLLM{reply: "The price is lowered in March."}).ChatCompletion(ctx, model, messages, temperature)
+}
+
+type RAGCrossLanguageTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *RAGCrossLanguageTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "rag_cross_language_test.db")
+}
+
+func (suite *RAGCrossLanguageTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// indexed creates and indexes a completed transcription the engine detected language in
+func (suite *RAGCrossLanguageTestSuite) indexed(ragService *rag.RAGService, language, text string) *models.TranscriptionJob {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Pricing "+language)
+	transcript := `{"language":"` + language + `","segments":[{"start":0,"end":4,"speaker":"SPEAKER_00","text":"` + text + `"}]}`
+	job.Status = models.StatusCompleted
+	job.Transcript = &transcript
+	require.NoError(suite.T(), suite.helper.DB.Save(job).Error)
+	require.NoError(suite.T(), ragService.StoreSummary(context.Background(), job.ID, "", text))
+	return job
+}
+
+func (suite *RAGCrossLanguageTestSuite) TestExcerptsInOtherLanguages() {
+	t := suite.T()
+	service := &translatingLLM{}
+	ragService := rag.NewRAGService(vectordb.NewMemoryStore(), embeddings.NewFakeEmbeddingService(), service)
+	suite.indexed(ragService, "de", "Wir senken den Preis im März.")
+	suite.indexed(ragService, "en", "The price goes down in March.")
+	query := "When does the price go down?"
+
+	// Off by default: the prompt holds the excerpts as they are
+	result, err := ragService.Chat(context.Background(), nil, query, llm.FakeModel, 0.2, rag.ChatOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, service.prompt, "[Meeting] Wir senken")
+	assert.Empty(t, result.QueryLanguage)
+
+	// Annotating marks the German excerpt without asking the LLM anything more
+	result, err = ragService.Chat(context.Background(), nil, query, llm.FakeModel, 0.2, rag.ChatOptions{CrossLanguage: rag.CrossLanguageAnnotate, QueryLanguage: "en"})
+	require.NoError(t, err)
+	assert.Contains(t, service.prompt, "[Meeting, in German] ")
+	assert.Contains(t, service.prompt, "answer in the language of the question (English)")
+	assert.NotContains(t, service.prompt, "in English]")
+	assert.Equal(t, "English", result.QueryLanguage)
+	assert.Zero(t, service.translations)
+
+	// Translating detects the question's language and translates only the German excerpts
+	result, err = ragService.Chat(context.Background(), nil, query, llm.FakeModel, 0.2, rag.ChatOptions{CrossLanguage: rag.CrossLanguageTranslate})
+	require.NoError(t, err)
+	assert.Equal(t, 1, service.detections)
+	// The German recording's summary entry and transcript chunk are both translated
+	assert.Equal(t, 2, service.translations)
+	assert.Equal(t, 2, result.TranslatedExcerpts)
+	assert.Equal(t, "English", result.QueryLanguage)
+	assert.Contains(t, service.prompt, "[Meeting, translated from German] We lower the price in March.")
+	assert.NotContains(t, service.prompt, "Wir senken")
+	assert.Contains(t, service.prompt, "The price goes down in March.")
+}
+
+func TestRAGCrossLanguageTestSuite(t *testing.T) {
+	suite.Run(t, new(RAGCrossLanguageTestSuite))
+}
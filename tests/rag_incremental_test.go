@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"scriberr/internal/embeddings"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/internal/vectordb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// countingEmbeddings counts the texts it is asked to embed
+type countingEmbeddings struct {
+	*embeddings.FakeEmbeddingService
+	calls int
+}
+
+func (e *countingEmbeddings) GenerateEmbedding(text string) ([]float32, error) {
+	e.calls++
+	return e.FakeEmbeddingService.GenerateEmbedding(text)
+}
+
+// textCountingStore counts the reads of the store that fetch document text or metadata
+type textCountingStore struct {
+	*vectordb.MemoryStore
+	textReads     int
+	metadataReads int
+}
+
+func (s *textCountingStore) GetDocuments(collection string, req vectordb.GetRequest) (*vectordb.GetResponse, error) {
+	for _, include := range req.Include {
+		switch include {
+		case "documents":
+			s.textReads++
+		case "metadatas":
+			s.metadataReads++
+		}
+	}
+	return s.MemoryStore.GetDocuments(collection, req)
+}
+
+type RAGIncrementalTestSuite struct {
+	suite.Suite
+	helper     *TestHelper
+	store      *vectordb.MemoryStore
+	embeddings *countingEmbeddings
+	rag        *rag.RAGService
+}
+
+func (suite *RAGIncrementalTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "rag_incremental_test.db")
+}
+
+func (suite *RAGIncrementalTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *RAGIncrementalTestSuite) SetupTest() {
+	suite.store = vectordb.NewMemoryStore()
+	suite.embeddings = &countingEmbeddings{FakeEmbeddingService: embeddings.NewFakeEmbeddingService()}
+	suite.rag = rag.NewRAGService(suite.store, suite.embeddings, llm.NewFakeService())
+}
+
+// indexedJob creates a diarized job with one chunk per text and indexes it
+func (suite *RAGIncrementalTestSuite) indexedJob(texts ...string) *models.TranscriptionJob {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Incremental")
+	suite.setTranscript(job, texts)
+	require.NoError(suite.T(), suite.rag.StoreSummary(context.Background(), job.ID, "", strings.Join(texts, " ")))
+	return job
+}
+
+func (suite *RAGIncrementalTestSuite) setTranscript(job *models.TranscriptionJob, texts []string) {
+	speaker := "SPEAKER_00"
+	result := interfaces.TranscriptResult{}
+	for i, text := range texts {
+		result.Segments = append(result.Segments, interfaces.TranscriptSegment{
+			Start: float64(i * 10), End: float64(i*10 + 10), Text: text, Speaker: &speaker,
+		})
+	}
+	data, err := json.Marshal(result)
+	require.NoError(suite.T(), err)
+	transcript := string(data)
+	job.Transcript = &transcript
+	job.Status = models.StatusCompleted
+	require.NoError(suite.T(), suite.helper.DB.Save(job).Error)
+}
+
+// paragraph returns a segment long enough to fill a chunk on its own
+func paragraph(word string) string {
+	return strings.TrimSpace(strings.Repeat(word+" ", 150))
+}
+
+func (suite *RAGIncrementalTestSuite) chunkMetadata(jobID string) []map[string]interface{} {
+	// Unowned jobs are indexed in the collection of the helper's only user
+	var user models.User
+	require.NoError(suite.T(), suite.helper.DB.First(&user).Error)
+	docs, err := suite.store.GetDocuments(rag.CollectionName(&user.ID), vectordb.GetRequest{
+		Where: map[string]interface{}{"transcription_id": jobID, "type": "transcript_chunk"},
+	})
+	require.NoError(suite.T(), err)
+	return docs.Metadatas
+}
+
+func (suite *RAGIncrementalTestSuite) TestUnchangedTranscriptIsNotReembedded() {
+	texts := []string{paragraph("alpha"), paragraph("bravo"), paragraph("charlie")}
+	job := suite.indexedJob(texts...)
+	assert.Equal(suite.T(), 4, suite.embeddings.calls, "summary entry and three chunks")
+
+	suite.embeddings.calls = 0
+	require.NoError(suite.T(), suite.rag.StoreSummary(context.Background(), job.ID, "", strings.Join(texts, " ")))
+	assert.Equal(suite.T(), 0, suite.embeddings.calls)
+}
+
+func (suite *RAGIncrementalTestSuite) TestOnlyChangedChunksAreReembedded() {
+	texts := []string{paragraph("alpha"), paragraph("bravo"), paragraph("charlie")}
+	job := suite.indexedJob(texts...)
+
+	texts[1] = paragraph("delta")
+	suite.setTranscript(job, texts)
+	suite.embeddings.calls = 0
+	require.NoError(suite.T(), suite.rag.StoreSummary(context.Background(), job.ID, "", strings.Join(texts, " ")))
+	assert.Equal(suite.T(), 2, suite.embeddings.calls, "summary entry and the edited chunk")
+	assert.Len(suite.T(), suite.chunkMetadata(job.ID), 3)
+}
+
+func (suite *RAGIncrementalTestSuite) TestMetadataChangesUpdateInPlace() {
+	job := suite.indexedJob(paragraph("alpha"), paragraph("bravo"))
+
+	require.NoError(suite.T(), suite.helper.DB.Model(job).Updates(map[string]interface{}{"title": "Renamed"}).Error)
+	require.NoError(suite.T(), suite.helper.DB.Model(job).Select("tags").Updates(&models.TranscriptionJob{Tags: []string{"q3", "budget"}}).Error)
+	require.NoError(suite.T(), suite.helper.DB.Create(&models.SpeakerMapping{TranscriptionJobID: job.ID, OriginalSpeaker: "speaker_00", CustomName: "Alice"}).Error)
+
+	suite.embeddings.calls = 0
+	require.NoError(suite.T(), suite.rag.UpdateMetadata(job.ID))
+	assert.Equal(suite.T(), 0, suite.embeddings.calls)
+
+	chunks := suite.chunkMetadata(job.ID)
+	require.Len(suite.T(), chunks, 2)
+	for _, metadata := range chunks {
+		assert.Equal(suite.T(), "Renamed", metadata["title"])
+		assert.Equal(suite.T(), "q3,budget", metadata["tags"])
+		assert.Equal(suite.T(), "SPEAKER_00", metadata["speaker"])
+		assert.Equal(suite.T(), "Alice", metadata["speaker_name"])
+		assert.NotEmpty(suite.T(), metadata["content_hash"])
+	}
+
+	hits, err := suite.rag.Search(suite.T().Context(), nil, "alpha", 1, []string{job.ID})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), hits, 1)
+	assert.Equal(suite.T(), "Alice", hits[0].Speaker)
+}
+
+func (suite *RAGIncrementalTestSuite) TestUpdateMetadataSkipsUnindexedTranscriptions() {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Not indexed")
+	require.NoError(suite.T(), suite.rag.UpdateMetadata(job.ID))
+}
+
+func (suite *RAGIncrementalTestSuite) TestStatsOnlyCountTheStore() {
+	suite.indexedJob(paragraph("alpha"), paragraph("bravo"))
+	var user models.User
+	require.NoError(suite.T(), suite.helper.DB.First(&user).Error)
+
+	store := &textCountingStore{MemoryStore: suite.store}
+	service := rag.NewRAGService(store, suite.embeddings, llm.NewFakeService())
+	stats, err := service.GetStats(context.Background(), &user.ID)
+	require.NoError(suite.T(), err)
+
+	count, err := suite.store.CountDocuments(rag.CollectionName(&user.ID), nil)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, count, "summary entry and two chunks")
+	assert.Equal(suite.T(), count, stats["document_count"])
+	assert.NotContains(suite.T(), stats, "chunk_count")
+	assert.NotContains(suite.T(), stats, "missing_ids")
+	assert.Equal(suite.T(), 1, stats["indexed_count"])
+	assert.Equal(suite.T(), 1, stats["indexed_transcriptions"])
+	assert.Equal(suite.T(), stats["transcript_count"].(int)-1, stats["missing_count"])
+	assert.Contains(suite.T(), stats, "last_indexed_at")
+	assert.Equal(suite.T(), count*embeddings.FakeDimensions*4, stats["index_size_bytes"])
+	assert.Zero(suite.T(), store.textReads)
+	assert.Zero(suite.T(), store.metadataReads, "stats shouldn't list the collection")
+}
+
+func TestRAGIncrementalTestSuite(t *testing.T) {
+	suite.Run(t, new(RAGIncrementalTestSuite))
+}
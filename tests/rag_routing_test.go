@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"scriberr/internal/embeddings"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/vectordb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type RAGRoutingTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *RAGRoutingTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "rag_routing_test.db")
+}
+
+func (suite *RAGRoutingTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// recording creates a completed transcription of a content type
+func (suite *RAGRoutingTestSuite) recording(title, contentType, text string) *models.TranscriptionJob {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), title)
+	transcript := `{"segments":[{"start":0,"end":4,"speaker":"SPEAKER_00","text":"` + text + `"}]}`
+	job.Status = models.StatusCompleted
+	job.Transcript = &transcript
+	job.ContentType = contentType
+	require.NoError(suite.T(), suite.helper.DB.Save(job).Error)
+	return job
+}
+
+func (suite *RAGRoutingTestSuite) TestContentTypesAreRoutedToTheirCollections() {
+	t := suite.T()
+	store := vectordb.NewMemoryStore()
+	service := &replyLLM{reply: "Answer"}
+	ragService := rag.NewRAGService(store, embeddings.NewFakeEmbeddingService(), service)
+	routes, err := rag.ParseRoutes("podcast=podcasts, voice_memo=memos")
+	require.NoError(t, err)
+	ragService.SetRoutes(routes)
+
+	meeting := suite.recording("Standup", models.ContentMeeting, "The release ships on Friday.")
+	podcast := suite.recording("Episode 12", models.ContentPodcast, "Our guest thinks the release ships on Friday.")
+	require.NoError(t, ragService.StoreSummary(context.Background(), meeting.ID, "", "The release ships on Friday."))
+	require.NoError(t, ragService.StoreSummary(context.Background(), podcast.ID, "", "Our guest thinks the release ships on Friday."))
+
+	base, err := rag.ResolveCollection(nil)
+	require.NoError(t, err)
+	count := func(route, transcriptionID string) int {
+		collection := base
+		if route != rag.DefaultRoute {
+			collection += "_" + route
+		}
+		n, err := store.CountDocuments(collection, map[string]interface{}{"transcription_id": transcriptionID})
+		require.NoError(t, err)
+		return n
+	}
+	assert.Positive(t, count(rag.DefaultRoute, meeting.ID))
+	assert.Zero(t, count("podcasts", meeting.ID))
+	assert.Positive(t, count("podcasts", podcast.ID))
+	assert.Zero(t, count(rag.DefaultRoute, podcast.ID))
+	indexed, err := ragService.IsIndexed(podcast.ID)
+	require.NoError(t, err)
+	assert.True(t, indexed)
+
+	// Every collection is searched unless the chat picks some
+	result, err := ragService.Chat(context.Background(), nil, "When does the release ship?", llm.FakeModel, 0.2, rag.ChatOptions{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{meeting.ID, podcast.ID}, result.Sources)
+	assert.Contains(t, service.prompt, "[Podcast] ")
+	assert.Contains(t, service.prompt, "[Meeting] ")
+	assert.Contains(t, service.prompt, "- Podcast: ")
+
+	result, err = ragService.Chat(context.Background(), nil, "When does the release ship?", llm.FakeModel, 0.2, rag.ChatOptions{Collections: []string{"podcasts"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{podcast.ID}, result.Sources)
+	assert.NotContains(t, service.prompt, "[Meeting] ")
+	assert.NotContains(t, service.prompt, "- Meeting: ")
+
+	// Changing the content type moves the entries on the next indexing
+	require.NoError(t, suite.helper.DB.Model(podcast).Update("content_type", models.ContentVoiceMemo).Error)
+	require.NoError(t, ragService.StoreSummary(context.Background(), podcast.ID, "", "Our guest thinks the release ships on Friday."))
+	assert.Positive(t, count("memos", podcast.ID))
+	assert.Zero(t, count("podcasts", podcast.ID))
+
+	report, err := ragService.Audit(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, report.Missing)
+	assert.Empty(t, report.Orphaned)
+	assert.Contains(t, report.Collections, base+"_memos")
+
+	require.NoError(t, ragService.DeleteTranscription(t.Context(), podcast.ID))
+	assert.Zero(t, count("memos", podcast.ID))
+}
+
+func TestRAGRoutingTestSuite(t *testing.T) {
+	suite.Run(t, new(RAGRoutingTestSuite))
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/config"
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/resources"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ResourceGuardTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *ResourceGuardTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "resources_test.db")
+}
+
+func (suite *ResourceGuardTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// router serves the API with the test config changed by configure
+func (suite *ResourceGuardTestSuite) router(configure func(cfg *config.Config)) *gin.Engine {
+	cfg := *suite.helper.Config
+	configure(&cfg)
+	handler := api.NewHandler(&cfg, suite.helper.AuthService, nil, nil, nil, nil)
+	return api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *ResourceGuardTestSuite) upload(router *gin.Engine) *httptest.ResponseRecorder {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("audio", "meeting.mp3")
+	require.NoError(suite.T(), err)
+	part.Write([]byte("not really audio"))
+	require.NoError(suite.T(), writer.Close())
+
+	req := suite.helper.NewRequest(suite.T(), suite.helper.TestAPIKey, "POST", "/api/v1/transcription/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return serve(router, req)
+}
+
+func (suite *ResourceGuardTestSuite) TestUploadRejectedWhenDiskIsLow() {
+	t := suite.T()
+	if resources.Measure(suite.helper.Config.UploadDir).DiskTotal == 0 {
+		t.Skip("disk space isn't measured on this platform")
+	}
+	var before int64
+	require.NoError(t, database.DB.Model(&models.TranscriptionJob{}).Count(&before).Error)
+
+	// Keeping a petabyte free can't be satisfied
+	router := suite.router(func(cfg *config.Config) { cfg.MinFreeDiskMB = 1 << 30 })
+	w := suite.upload(router)
+	require.Equal(t, http.StatusInsufficientStorage, w.Code, w.Body.String())
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "disk", response["resource"])
+	assert.Contains(t, response["error"], "not enough free disk space")
+
+	var after int64
+	require.NoError(t, database.DB.Model(&models.TranscriptionJob{}).Count(&after).Error)
+	assert.Equal(t, before, after, "no job is created")
+
+	w = suite.helper.Request(t, router, "GET", "/api/v1/admin/resources", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var status api.ResourceStatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.False(t, status.Accepting)
+	assert.Equal(t, uint64(1)<<50, status.MinFreeDisk)
+	assert.NotZero(t, status.DiskTotal)
+}
+
+func (suite *ResourceGuardTestSuite) TestUploadRejectedWhenMemoryIsLow() {
+	t := suite.T()
+	if resources.Measure(suite.helper.Config.UploadDir).MemoryTotal == 0 {
+		t.Skip("memory isn't measured on this platform")
+	}
+	router := suite.router(func(cfg *config.Config) { cfg.MinFreeMemoryMB = 1 << 30 })
+	w := suite.upload(router)
+	require.Equal(t, http.StatusTooManyRequests, w.Code, w.Body.String())
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "not enough free memory")
+}
+
+func (suite *ResourceGuardTestSuite) TestUploadAcceptedWithRoomToSpare() {
+	router := suite.router(func(cfg *config.Config) { cfg.MinFreeDiskMB = 1 })
+	w := suite.upload(router)
+	assert.NotEqual(suite.T(), http.StatusInsufficientStorage, w.Code, w.Body.String())
+	assert.NotEqual(suite.T(), http.StatusTooManyRequests, w.Code, w.Body.String())
+}
+
+func TestResourceGuardTestSuite(t *testing.T) {
+	suite.Run(t, new(ResourceGuardTestSuite))
+}
@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ResumableUploadTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	queue  *queue.TaskQueue
+	router *gin.Engine
+}
+
+func (suite *ResumableUploadTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "resumable_upload_test.db")
+	suite.queue = queue.NewTaskQueue(1, &MockJobProcessor{})
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, suite.queue, nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *ResumableUploadTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *ResumableUploadTestSuite) request(method, path string, body io.Reader, offset int64) *httptest.ResponseRecorder {
+	req := suite.helper.NewRequest(suite.T(), suite.helper.TestAPIKey, method, path, body)
+	if method == http.MethodPatch {
+		req.Header.Set("Upload-Offset", strconv.FormatInt(offset, 10))
+	}
+	return serve(suite.router, req)
+}
+
+func (suite *ResumableUploadTestSuite) start(size int64) api.ResumableUploadResponse {
+	body, _ := json.Marshal(gin.H{"filename": "board meeting.wav", "size": size, "title": "Board", "agenda": "Budget"})
+	w := suite.request(http.MethodPost, "/api/v1/transcription/uploads", bytes.NewReader(body), 0)
+	require.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+	var upload api.ResumableUploadResponse
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &upload))
+	return upload
+}
+
+func (suite *ResumableUploadTestSuite) part(id string, offset int64, data string) (*httptest.ResponseRecorder, api.ResumableUploadResponse) {
+	w := suite.request(http.MethodPatch, "/api/v1/transcription/uploads/"+id, bytes.NewBufferString(data), offset)
+	var upload api.ResumableUploadResponse
+	json.Unmarshal(w.Body.Bytes(), &upload)
+	return w, upload
+}
+
+// droppedReader yields data and then fails, like a connection that drops mid-request
+type droppedReader struct {
+	data *bytes.Reader
+}
+
+func (r *droppedReader) Read(p []byte) (int, error) {
+	if r.data.Len() == 0 {
+		return 0, errors.New("connection reset")
+	}
+	return r.data.Read(p)
+}
+
+func (suite *ResumableUploadTestSuite) TestPartsAreAssembledAndQueued() {
+	t := suite.T()
+	suite.helper.CreateTestProfile(t, "Default", true)
+	upload := suite.start(12)
+	assert.EqualValues(t, 0, upload.Received)
+	assert.Equal(t, models.ContentMeeting, upload.ContentType)
+
+	w, upload := suite.part(upload.ID, 0, "RIFF")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.EqualValues(t, 4, upload.Received)
+	assert.Equal(t, "4", w.Header().Get("Upload-Offset"))
+
+	// A retried part that already arrived is refused rather than written twice
+	w, _ = suite.part(upload.ID, 0, "RIFF")
+	require.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"received":4`)
+
+	// The connection drops midway; what arrived is kept
+	req := suite.helper.NewRequest(suite.T(), suite.helper.TestAPIKey, http.MethodPatch, "/api/v1/transcription/uploads/"+upload.ID, &droppedReader{bytes.NewReader([]byte("abc"))})
+	req.Header.Set("Upload-Offset", "4")
+	w = serve(suite.router, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = suite.request(http.MethodGet, "/api/v1/transcription/uploads/"+upload.ID, nil, 0)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "7", w.Header().Get("Upload-Offset"))
+
+	w, _ = suite.part(upload.ID, 7, "too many bytes")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	w, upload = suite.part(upload.ID, 7, "defgh")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NotNil(t, upload.Transcription)
+	job := upload.Transcription
+	assert.Equal(t, upload.ID, job.ID)
+	require.NotNil(t, upload.TranscriptionID)
+	assert.Equal(t, job.ID, *upload.TranscriptionID)
+	assert.Equal(t, models.StatusPending, job.Status, "queued with the default profile")
+	assert.Contains(t, order(suite.queue), job.ID)
+	require.NotNil(t, job.Title)
+	assert.Equal(t, "Board", *job.Title)
+	require.NotNil(t, job.Parameters.InitialPrompt)
+	assert.Contains(t, *job.Parameters.InitialPrompt, "Agenda: Budget.")
+	assert.Equal(t, ".wav", filepath.Ext(job.AudioPath))
+
+	data, err := os.ReadFile(job.AudioPath)
+	require.NoError(t, err)
+	assert.Equal(t, "RIFFabcdefgh", string(data))
+
+	w, _ = suite.part(upload.ID, 12, "")
+	assert.Equal(t, http.StatusConflict, w.Code, "the upload is already complete")
+}
+
+func (suite *ResumableUploadTestSuite) TestCancelAndExpiry() {
+	t := suite.T()
+	cancelled := suite.start(10)
+	w := suite.request(http.MethodDelete, "/api/v1/transcription/uploads/"+cancelled.ID, nil, 0)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusNotFound, suite.request(http.MethodGet, "/api/v1/transcription/uploads/"+cancelled.ID, nil, 0).Code)
+
+	abandoned := suite.start(10)
+	require.NoError(t, suite.helper.DB.Model(&models.UploadSession{}).Where("id = ?", abandoned.ID).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	assert.Equal(t, http.StatusNotFound, suite.request(http.MethodGet, "/api/v1/transcription/uploads/"+abandoned.ID, nil, 0).Code)
+
+	// Starting another upload sweeps the expired one
+	suite.start(10)
+	var count int64
+	suite.helper.DB.Model(&models.UploadSession{}).Where("id IN ?", []string{cancelled.ID, abandoned.ID}).Count(&count)
+	assert.Zero(t, count)
+
+	body, _ := json.Marshal(gin.H{"filename": "x.wav", "size": -1})
+	assert.Equal(t, http.StatusBadRequest, suite.request(http.MethodPost, "/api/v1/transcription/uploads", bytes.NewReader(body), 0).Code)
+}
+
+func TestResumableUploadTestSuite(t *testing.T) {
+	suite.Run(t, new(ResumableUploadTestSuite))
+}
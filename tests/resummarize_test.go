@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"scriberr/internal/embeddings"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/resummarize"
+	"scriberr/internal/vectordb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// judgingLLM writes summaries and, asked to compare two, finds the new one better
+type judgingLLM struct {
+	summaries int
+}
+
+func (l *judgingLLM) ChatCompletion(ctx context.Context, model string, messages []llm.ChatMessage, temperature float64) (*llm.ChatResponse, error) {
+	reply := "A better summary of the budget meeting."
+	comparing := false
+	for _, message := range messages {
+		comparing = comparing || strings.Contains(message.Content, "Old summary:")
+	}
+	if comparing {
+		reply = `{"verdict": "improved", "reason": "It names the decision."}`
+	} else {
+		l.summaries++
+	}
+	return (&replyLLM{reply: reply}).ChatCompletion(ctx, model, messages, temperature)
+}
+
+type ResummarizeTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *ResummarizeTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "resummarize_test.db")
+}
+
+func (suite *ResummarizeTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// summarizedJob creates a completed transcription with a summary written by model
+func (suite *ResummarizeTestSuite) summarizedJob(title string, model *string) *models.TranscriptionJob {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), title)
+	transcript := `{"segments":[{"start":0,"end":3,"text":"We approved the budget."}]}`
+	summary := "Budget meeting."
+	job.Status = models.StatusCompleted
+	job.Transcript = &transcript
+	job.Summary = &summary
+	job.SummaryModel = model
+	require.NoError(suite.T(), suite.helper.DB.Save(job).Error)
+	return job
+}
+
+func (suite *ResummarizeTestSuite) TestOutdatedSummariesAreRewritten() {
+	t := suite.T()
+	outdated := suite.summarizedJob("Outdated", stringPtr("old-model"))
+	suite.summarizedJob("Current", stringPtr("new-model"))
+	unknown := suite.summarizedJob("Unknown", nil)
+	held := suite.summarizedJob("Held", stringPtr("old-model"))
+	require.NoError(t, suite.helper.DB.Model(held).Update("legal_hold", true).Error)
+
+	service := &judgingLLM{}
+	ragService := rag.NewRAGService(vectordb.NewMemoryStore(), embeddings.NewFakeEmbeddingService(), service)
+	require.NoError(t, ragService.StoreSummary(context.Background(), outdated.ID, "Budget meeting.", "We approved the budget."))
+	resummarizer := resummarize.NewService(service, "new-model", ragService)
+
+	candidates, err := resummarizer.Candidates(10, false)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, outdated.ID, candidates[0].ID)
+	candidates, err = resummarizer.Candidates(10, true)
+	require.NoError(t, err)
+	assert.Len(t, candidates, 2)
+
+	run, err := resummarizer.Run(context.Background(), resummarize.Options{BatchSize: 10, Delay: time.Millisecond, Compare: true, Trigger: "api"})
+	require.NoError(t, err)
+	assert.Equal(t, models.ResummarizeCompleted, run.Status)
+	assert.Equal(t, 1, run.Candidates)
+	assert.Equal(t, 1, run.Resummarized)
+	assert.Equal(t, 1, run.Improved)
+	assert.Zero(t, run.Failed)
+	assert.Equal(t, 1, service.summaries)
+	require.Len(t, run.Results, 1)
+	result := run.Results[0]
+	assert.Equal(t, outdated.ID, result.TranscriptionID)
+	assert.Equal(t, "old-model", result.OldModel)
+	assert.Equal(t, "new-model", result.NewModel)
+	assert.Equal(t, len("Budget meeting."), result.OldLength)
+	assert.Equal(t, len("A better summary of the budget meeting."), result.NewLength)
+	assert.Equal(t, models.VerdictImproved, result.Verdict)
+	assert.Equal(t, "It names the decision.", result.Reason)
+
+	var saved models.TranscriptionJob
+	require.NoError(t, suite.helper.DB.First(&saved, "id = ?", outdated.ID).Error)
+	require.NotNil(t, saved.SummaryModel)
+	assert.Equal(t, "new-model", *saved.SummaryModel)
+	assert.Equal(t, "A better summary of the budget meeting.", *saved.Summary)
+
+	// The report is kept
+	var stored models.ResummarizeRun
+	require.NoError(t, suite.helper.DB.First(&stored, "id = ?", run.ID).Error)
+	assert.Equal(t, models.ResummarizeCompleted, stored.Status)
+	require.Len(t, stored.Results, 1)
+	assert.NotNil(t, stored.CompletedAt)
+
+	// Only the summary of unknown origin is left, and only when asked for
+	candidates, err = resummarizer.Candidates(10, false)
+	require.NoError(t, err)
+	assert.Empty(t, candidates)
+	candidates, err = resummarizer.Candidates(10, true)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, unknown.ID, candidates[0].ID)
+}
+
+func TestResummarizeTestSuite(t *testing.T) {
+	suite.Run(t, new(ResummarizeTestSuite))
+}
@@ -0,0 +1,199 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/embeddings"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/scheduler"
+	"scriberr/internal/vectordb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type RetentionTestSuite struct {
+	suite.Suite
+	helper    *TestHelper
+	scheduler *scheduler.Service
+	store     *vectordb.MemoryStore
+	rag       *rag.RAGService
+	router    *gin.Engine
+}
+
+func (suite *RetentionTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "retention_test.db")
+	suite.store = vectordb.NewMemoryStore()
+	suite.rag = rag.NewRAGService(suite.store, embeddings.NewFakeEmbeddingService(), llm.NewFakeService())
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, nil, nil, nil, suite.rag)
+	suite.scheduler = scheduler.NewService()
+	handler.SetScheduler(suite.scheduler)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *RetentionTestSuite) TearDownSuite() {
+	suite.scheduler.Stop()
+	suite.helper.Cleanup()
+}
+
+// project creates a project with the given settings and returns its ID
+func (suite *RetentionTestSuite) project(body gin.H) string {
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/projects", body)
+	require.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+	var project models.Project
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &project))
+	return project.ID
+}
+
+// job creates an indexed, completed transcription with audio, created daysAgo
+func (suite *RetentionTestSuite) job(title string, daysAgo int, projectID *string) *models.TranscriptionJob {
+	t := suite.T()
+	audio := filepath.Join(t.TempDir(), "audio.mp3")
+	require.NoError(t, os.WriteFile(audio, []byte("audio"), 0644))
+	transcript := `{"text": "We agreed to ship the release on Friday.", "segments": [{"start": 0, "end": 5, "text": "We agreed to ship the release on Friday."}]}`
+	job := &models.TranscriptionJob{
+		Title:      &title,
+		Status:     models.StatusCompleted,
+		AudioPath:  audio,
+		Transcript: &transcript,
+		ProjectID:  projectID,
+		CreatedAt:  time.Now().AddDate(0, 0, -daysAgo),
+	}
+	require.NoError(t, suite.helper.DB.Create(job).Error)
+	require.NoError(t, suite.rag.StoreSummary(context.Background(), job.ID, "", "We agreed to ship the release on Friday."))
+	return job
+}
+
+// cleanup runs a retention cleanup with params and returns what it reported
+func (suite *RetentionTestSuite) cleanup(params gin.H) map[string]interface{} {
+	t := suite.T()
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/admin/schedules", gin.H{
+		"name": "Retention", "action": api.ActionRetentionCleanup, "cron": "@daily", "params": params, "enabled": false,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var schedule models.Schedule
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &schedule))
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/admin/schedules/"+schedule.ID+"/run", nil)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var run models.ScheduleRun
+	require.Eventually(t, func() bool {
+		err := suite.helper.DB.Where("schedule_id = ?", schedule.ID).Order("id DESC").First(&run).Error
+		return err == nil && run.Status != models.ScheduleRunRunning && !suite.scheduler.IsRunning(schedule.ID)
+	}, 5*time.Second, 20*time.Millisecond)
+	require.Equal(t, models.ScheduleRunCompleted, run.Status, "%v", run.Error)
+	return run.Result
+}
+
+// indexed reports whether the vector store holds documents of a transcription
+func (suite *RetentionTestSuite) indexed(jobID string) bool {
+	collection, err := rag.ResolveCollection(nil)
+	require.NoError(suite.T(), err)
+	count, err := suite.store.CountDocuments(collection, map[string]interface{}{"transcription_id": jobID})
+	require.NoError(suite.T(), err)
+	return count > 0
+}
+
+// state reports whether a transcription still exists and still has its audio
+func (suite *RetentionTestSuite) state(job *models.TranscriptionJob) (exists, hasAudio bool) {
+	var current models.TranscriptionJob
+	if err := suite.helper.DB.Where("id = ?", job.ID).Limit(1).Find(&current).Error; err != nil || current.ID == "" {
+		return false, false
+	}
+	_, statErr := os.Stat(job.AudioPath)
+	return true, current.AudioPath != "" && statErr == nil
+}
+
+func (suite *RetentionTestSuite) TestRetentionRules() {
+	t := suite.T()
+	keep := suite.project(gin.H{"name": "Board meetings", "audio_retention_days": 0, "retention_days": 0})
+	short := suite.project(gin.H{"name": "Standups", "retention_days": 20})
+	// Inherits the short retention of its parent
+	nested := suite.project(gin.H{"name": "Team A", "parent_id": short})
+
+	expired := suite.job("Expired", 100, nil)
+	audioExpired := suite.job("Audio expired", 60, nil)
+	recent := suite.job("Recent", 10, nil)
+	held := suite.job("Held", 100, nil)
+	require.NoError(t, suite.helper.DB.Model(held).Update("legal_hold", true).Error)
+	kept := suite.job("Board meeting", 100, &keep)
+	standup := suite.job("Standup", 30, &nested)
+
+	result := suite.cleanup(gin.H{"audio_older_than_days": 30, "delete_older_than_days": 90})
+	assert.EqualValues(t, 1, result["audio_deleted"])
+	assert.EqualValues(t, 2, result["transcriptions_deleted"])
+	assert.EqualValues(t, 0, result["failed"])
+
+	for _, gone := range []*models.TranscriptionJob{expired, standup} {
+		exists, _ := suite.state(gone)
+		assert.False(t, exists, "%s should be deleted", *gone.Title)
+		assert.NoFileExists(t, gone.AudioPath)
+		assert.False(t, suite.indexed(gone.ID), "%s should be gone from the vector store", *gone.Title)
+	}
+
+	exists, hasAudio := suite.state(audioExpired)
+	assert.True(t, exists)
+	assert.False(t, hasAudio, "audio past its retention should be deleted")
+	assert.True(t, suite.indexed(audioExpired.ID), "the transcript stays searchable")
+	var withoutAudio models.TranscriptionJob
+	require.NoError(t, suite.helper.DB.First(&withoutAudio, "id = ?", audioExpired.ID).Error)
+	assert.NotNil(t, withoutAudio.Transcript)
+
+	for _, untouched := range []*models.TranscriptionJob{recent, held, kept} {
+		exists, hasAudio := suite.state(untouched)
+		assert.True(t, exists && hasAudio, "%s should be kept with its audio", *untouched.Title)
+		assert.True(t, suite.indexed(untouched.ID))
+	}
+}
+
+func (suite *RetentionTestSuite) TestProjectSettings() {
+	t := suite.T()
+	parent := suite.project(gin.H{"name": "Sales", "audio_retention_days": 7})
+	child := suite.project(gin.H{"name": "Calls", "parent_id": parent, "retention_days": 365})
+
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/projects/"+child, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Settings models.ProjectSettings `json:"settings"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Settings.AudioRetentionDays)
+	require.NotNil(t, resp.Settings.RetentionDays)
+	assert.Equal(t, 7, *resp.Settings.AudioRetentionDays)
+	assert.Equal(t, 365, *resp.Settings.RetentionDays)
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/projects", gin.H{"name": "Bad", "retention_days": -1})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func (suite *RetentionTestSuite) TestScheduleParams() {
+	t := suite.T()
+	for _, params := range []gin.H{
+		{},
+		{"audio_older_than_days": -5},
+		{"delete_older_than_days": "soon"},
+		{"audio_older_than_days": 0, "delete_older_than_days": 0},
+	} {
+		w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/admin/schedules", gin.H{"name": "Retention", "action": api.ActionRetentionCleanup, "cron": "@daily", "params": params})
+		assert.Equal(t, http.StatusBadRequest, w.Code, "%v", params)
+	}
+
+	// Deleting whole transcriptions alone is a rule of its own
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/admin/schedules", gin.H{"name": "Retention", "action": api.ActionRetentionCleanup, "cron": "@daily", "params": gin.H{"delete_older_than_days": 365}})
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+}
+
+func TestRetentionTestSuite(t *testing.T) {
+	suite.Run(t, new(RetentionTestSuite))
+}
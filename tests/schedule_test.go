@@ -0,0 +1,192 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/scheduler"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ScheduleTestSuite struct {
+	suite.Suite
+	helper    *TestHelper
+	scheduler *scheduler.Service
+	router    *gin.Engine
+}
+
+func (suite *ScheduleTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "schedule_test.db")
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, nil, nil, nil, nil)
+	suite.scheduler = scheduler.NewService()
+	handler.SetScheduler(suite.scheduler)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *ScheduleTestSuite) TearDownSuite() {
+	suite.scheduler.Stop()
+	suite.helper.Cleanup()
+}
+
+func (suite *ScheduleTestSuite) create(body gin.H) models.Schedule {
+	w := suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/admin/schedules", body)
+	require.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+	var schedule models.Schedule
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &schedule))
+	return schedule
+}
+
+// waitForRun waits for a schedule's latest run to finish and returns it
+func (suite *ScheduleTestSuite) waitForRun(scheduleID string) models.ScheduleRun {
+	var run models.ScheduleRun
+	require.Eventually(suite.T(), func() bool {
+		err := suite.helper.DB.Where("schedule_id = ?", scheduleID).Order("id DESC").First(&run).Error
+		return err == nil && run.Status != models.ScheduleRunRunning && !suite.scheduler.IsRunning(scheduleID)
+	}, 5*time.Second, 20*time.Millisecond)
+	return run
+}
+
+func (suite *ScheduleTestSuite) TestRetentionCleanupRunsByHand() {
+	t := suite.T()
+	dir := t.TempDir()
+	oldAudio := filepath.Join(dir, "old.mp3")
+	heldAudio := filepath.Join(dir, "held.mp3")
+	recentAudio := filepath.Join(dir, "recent.mp3")
+	for _, path := range []string{oldAudio, heldAudio, recentAudio} {
+		require.NoError(t, os.WriteFile(path, []byte("audio"), 0644))
+	}
+	longAgo := time.Now().AddDate(0, 0, -60)
+	old := models.TranscriptionJob{Status: models.StatusCompleted, AudioPath: oldAudio, CreatedAt: longAgo}
+	held := models.TranscriptionJob{Status: models.StatusCompleted, AudioPath: heldAudio, LegalHold: true, CreatedAt: longAgo}
+	recent := models.TranscriptionJob{Status: models.StatusCompleted, AudioPath: recentAudio}
+	for _, job := range []*models.TranscriptionJob{&old, &held, &recent} {
+		require.NoError(t, suite.helper.DB.Create(job).Error)
+	}
+
+	schedule := suite.create(gin.H{
+		"name":    "Drop old audio",
+		"action":  api.ActionRetentionCleanup,
+		"cron":    "@daily",
+		"params":  gin.H{"audio_older_than_days": 30},
+		"enabled": false,
+	})
+	assert.False(t, schedule.Enabled)
+	assert.Nil(t, schedule.NextRunAt, "disabled schedules aren't due")
+
+	w := suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/admin/schedules/"+schedule.ID+"/run", nil)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	run := suite.waitForRun(schedule.ID)
+	assert.Equal(t, models.ScheduleRunCompleted, run.Status)
+	assert.Equal(t, scheduler.TriggerManual, run.Trigger)
+	assert.EqualValues(t, 1, run.Result["audio_deleted"])
+
+	assert.NoFileExists(t, oldAudio)
+	assert.FileExists(t, heldAudio)
+	assert.FileExists(t, recentAudio)
+	var cleared models.TranscriptionJob
+	require.NoError(t, suite.helper.DB.First(&cleared, "id = ?", old.ID).Error)
+	assert.Empty(t, cleared.AudioPath)
+
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/admin/schedules/"+schedule.ID+"/runs", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var history struct {
+		Runs []models.ScheduleRun `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+	require.Len(t, history.Runs, 1)
+	assert.Equal(t, run.ID, history.Runs[0].ID)
+
+	var updated models.Schedule
+	require.NoError(t, suite.helper.DB.First(&updated, "id = ?", schedule.ID).Error)
+	assert.Equal(t, models.ScheduleRunCompleted, updated.LastStatus)
+	assert.NotNil(t, updated.LastRunAt)
+}
+
+func (suite *ScheduleTestSuite) TestDueSchedulesRunAndAdvance() {
+	t := suite.T()
+	schedule := suite.create(gin.H{"name": "Backfill", "action": api.ActionRAGBackfill, "cron": "0 * * * *"})
+	require.NotNil(t, schedule.NextRunAt)
+	assert.Equal(t, 0, schedule.NextRunAt.Minute())
+
+	// Not due yet
+	suite.scheduler.RunDue(time.Now())
+	var count int64
+	suite.helper.DB.Model(&models.ScheduleRun{}).Where("schedule_id = ?", schedule.ID).Count(&count)
+	assert.Zero(t, count)
+
+	due := schedule.NextRunAt.Add(time.Minute)
+	suite.scheduler.RunDue(due)
+	run := suite.waitForRun(schedule.ID)
+	assert.Equal(t, scheduler.TriggerSchedule, run.Trigger)
+	// RAG isn't configured here, so the run fails and says why
+	assert.Equal(t, models.ScheduleRunFailed, run.Status)
+	require.NotNil(t, run.Error)
+	assert.Contains(t, *run.Error, "RAG")
+
+	var advanced models.Schedule
+	require.NoError(t, suite.helper.DB.First(&advanced, "id = ?", schedule.ID).Error)
+	require.NotNil(t, advanced.NextRunAt)
+	assert.True(t, advanced.NextRunAt.After(due))
+	assert.Equal(t, models.ScheduleRunFailed, advanced.LastStatus)
+
+	require.Equal(t, http.StatusOK, suite.helper.Request(suite.T(), suite.router, "DELETE", "/api/v1/admin/schedules/"+schedule.ID, nil).Code)
+	suite.helper.DB.Model(&models.ScheduleRun{}).Where("schedule_id = ?", schedule.ID).Count(&count)
+	assert.Zero(t, count, "the run history goes with the schedule")
+}
+
+func (suite *ScheduleTestSuite) TestUpdateSchedule() {
+	t := suite.T()
+	schedule := suite.create(gin.H{"name": "Scan", "action": api.ActionDropzoneScan, "cron": "*/5 * * * *"})
+	w := suite.helper.Request(suite.T(), suite.router, "PUT", "/api/v1/admin/schedules/"+schedule.ID, gin.H{"name": "Nightly scan", "action": api.ActionDropzoneScan, "cron": "0 2 * * *"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var updated models.Schedule
+	require.NoError(t, suite.helper.DB.First(&updated, "id = ?", schedule.ID).Error)
+	assert.Equal(t, "Nightly scan", updated.Name)
+	assert.Equal(t, "0 2 * * *", updated.Cron)
+	require.NotNil(t, updated.NextRunAt)
+	assert.Equal(t, 2, updated.NextRunAt.Hour())
+}
+
+func (suite *ScheduleTestSuite) TestRejectsInvalidSchedules() {
+	t := suite.T()
+	cases := []gin.H{
+		{"name": "Bad cron", "action": api.ActionDropzoneScan, "cron": "every day"},
+		{"name": "Unknown", "action": "defragment", "cron": "@daily"},
+		{"name": "No cutoff", "action": api.ActionRetentionCleanup, "cron": "@daily"},
+		{"name": "Bad flag", "action": api.ActionRAGBackfill, "cron": "@daily", "params": gin.H{"only_missing": "yes"}},
+		{"name": "Never", "action": api.ActionDropzoneScan, "cron": "0 0 30 2 *"},
+	}
+	for _, body := range cases {
+		w := suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/admin/schedules", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "%v: %s", body["name"], w.Body.String())
+	}
+	assert.Equal(t, http.StatusNotFound, suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/admin/schedules/missing/run", nil).Code)
+}
+
+func (suite *ScheduleTestSuite) TestListsActions() {
+	w := suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/admin/schedules/actions", nil)
+	require.Equal(suite.T(), http.StatusOK, w.Code)
+	var response struct {
+		Actions []scheduler.Action `json:"actions"`
+	}
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &response))
+	names := make([]string, len(response.Actions))
+	for i, action := range response.Actions {
+		names[i] = action.Name
+	}
+	assert.Equal(suite.T(), []string{api.ActionDropzoneScan, api.ActionDuplicateScan, api.ActionRAGBackfill, api.ActionRetentionCleanup, api.ActionStorageCleanup}, names)
+}
+
+func TestScheduleTestSuite(t *testing.T) {
+	suite.Run(t, new(ScheduleTestSuite))
+}
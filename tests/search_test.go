@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+	"scriberr/internal/search"
+	"scriberr/internal/tagging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SearchTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	router *gin.Engine
+}
+
+func (suite *SearchTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "search_test.db")
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, queue.NewTaskQueue(1, &MockJobProcessor{}), nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *SearchTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// search runs a search with the given query parameters
+func (suite *SearchTestSuite) search(params url.Values) ([]search.Hit, int64, *httptest.ResponseRecorder) {
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/search?"+params.Encode(), nil)
+	var resp struct {
+		Results    []search.Hit `json:"results"`
+		Pagination struct {
+			Total int64 `json:"total"`
+		} `json:"pagination"`
+	}
+	if w.Code == http.StatusOK {
+		require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &resp))
+	}
+	return resp.Results, resp.Pagination.Total, w
+}
+
+// transcribed creates a completed test transcription with the given transcript
+func (suite *SearchTestSuite) transcribed(title, transcript string) *models.TranscriptionJob {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), title)
+	require.NoError(suite.T(), suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status": models.StatusCompleted, "transcript": transcript,
+	}).Error)
+	return job
+}
+
+func (suite *SearchTestSuite) TestSearch() {
+	t := suite.T()
+	db := suite.helper.DB
+	review := suite.transcribed("Quarterly review", `{"text":"","segments":[
+		{"start":0,"end":4,"text":"Welcome to the quarterly budget review.","speaker":"SPEAKER_00"},
+		{"start":4,"end":9,"text":"The budget for Q3 grew by 12 percent, mostly <marketing> & sales.","speaker":"SPEAKER_01"},
+		{"start":9,"end":12,"text":"Let's review the café expenses next.","speaker":"SPEAKER_00"}]}`)
+	require.NoError(t, db.Create(&models.SpeakerMapping{TranscriptionJobID: review.ID, OriginalSpeaker: "SPEAKER_01", CustomName: "Priya"}).Error)
+	_, err := tagging.SetJobTags(review, []string{"finance"})
+	require.NoError(t, err)
+	notes := suite.transcribed("Voice note", "Remember to send the budget spreadsheet.")
+
+	hits, total, w := suite.search(url.Values{"q": {"budget"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, int64(3), total)
+	require.Len(t, hits, 3)
+
+	// Phrases match words in order
+	hits, total, _ = suite.search(url.Values{"q": {`"budget review"`}})
+	require.Equal(t, int64(1), total)
+	assert.Equal(t, review.ID, hits[0].TranscriptionID)
+	require.NotNil(t, hits[0].Start)
+	assert.Equal(t, 0.0, *hits[0].Start)
+	assert.Equal(t, 4.0, *hits[0].End)
+	assert.Equal(t, "Quarterly review", *hits[0].Title)
+	assert.Equal(t, "Welcome to the quarterly <mark>budget review</mark>.", hits[0].Snippet)
+
+	// Snippets are escaped, and speakers named
+	hits, _, _ = suite.search(url.Values{"q": {"q3"}})
+	require.Len(t, hits, 1)
+	assert.Equal(t, "SPEAKER_01", hits[0].Speaker)
+	assert.Equal(t, "Priya", hits[0].SpeakerName)
+	assert.Contains(t, hits[0].Snippet, "&lt;marketing&gt; &amp; sales")
+
+	// Accents are ignored, and words can be left out
+	_, total, _ = suite.search(url.Values{"q": {"cafe -budget"}})
+	assert.Equal(t, int64(1), total)
+
+	// Filters
+	_, total, _ = suite.search(url.Values{"q": {"budget"}, "speaker": {"priya"}})
+	assert.Equal(t, int64(1), total)
+	_, total, _ = suite.search(url.Values{"q": {"budget"}, "tag": {"finance"}})
+	assert.Equal(t, int64(2), total)
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	_, total, _ = suite.search(url.Values{"q": {"budget"}, "from": {tomorrow}})
+	assert.Equal(t, int64(0), total)
+	_, total, _ = suite.search(url.Values{"q": {"budget"}, "to": {time.Now().Format("2006-01-02")}})
+	assert.Equal(t, int64(3), total)
+
+	// A transcript without segments is one untimed result
+	hits, _, _ = suite.search(url.Values{"q": {"spreadsheet"}})
+	require.Len(t, hits, 1)
+	assert.Equal(t, notes.ID, hits[0].TranscriptionID)
+	assert.Nil(t, hits[0].Start)
+
+	// The index follows edits and deletions
+	require.NoError(t, db.Model(notes).Update("transcript", `{"text":"Remember the invoices.","segments":[{"start":1,"end":2,"text":"Remember the invoices."}]}`).Error)
+	_, total, _ = suite.search(url.Values{"q": {"spreadsheet"}})
+	assert.Equal(t, int64(0), total)
+	hits, _, _ = suite.search(url.Values{"q": {"invoices"}})
+	require.Len(t, hits, 1)
+	assert.Equal(t, 1.0, *hits[0].Start)
+	require.NoError(t, db.Delete(&models.TranscriptionJob{}, "id = ?", notes.ID).Error)
+	_, total, _ = suite.search(url.Values{"q": {"invoices"}})
+	assert.Equal(t, int64(0), total)
+
+	// Other users' transcriptions aren't searched
+	require.NoError(t, db.Model(review).Update("user_id", suite.helper.TestUser.ID).Error)
+	_, total, _ = suite.search(url.Values{"q": {"budget"}})
+	assert.Equal(t, int64(0), total)
+}
+
+func (suite *SearchTestSuite) TestInvalidSearch() {
+	for _, params := range []url.Values{
+		{},
+		{"q": {"..."}},
+		{"q": {"budget"}, "limit": {"500"}},
+		{"q": {"budget"}, "from": {"last week"}},
+	} {
+		_, _, w := suite.search(params)
+		assert.Equal(suite.T(), http.StatusBadRequest, w.Code, params.Encode())
+	}
+}
+
+func TestSearchTestSuite(t *testing.T) {
+	suite.Run(t, new(SearchTestSuite))
+}
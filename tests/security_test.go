@@ -64,7 +64,7 @@ func (suite *SecurityTestSuite) SetupSuite() {
 		suite.T().Fatal("Failed to initialize quick transcription service:", err)
 	}
 	suite.taskQueue = queue.NewTaskQueue(1, suite.unifiedProcessor)
-	suite.handler = api.NewHandler(suite.config, suite.authService, suite.taskQueue, suite.unifiedProcessor, suite.quickTranscriptionService)
+	suite.handler = api.NewHandler(suite.config, suite.authService, suite.taskQueue, suite.unifiedProcessor, suite.quickTranscriptionService, nil)
 
 	// Set up router
 	suite.router = api.SetupRoutes(suite.handler, suite.authService)
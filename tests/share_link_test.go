@@ -0,0 +1,221 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ShareLinkTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	router *gin.Engine
+}
+
+func (suite *ShareLinkTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "share_link_test.db")
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, queue.NewTaskQueue(1, &MockJobProcessor{}), nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *ShareLinkTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// view opens a share link without credentials
+func (suite *ShareLinkTestSuite) view(url, password string) *httptest.ResponseRecorder {
+	return suite.viewFrom("192.0.2.1", url, password)
+}
+
+// viewFrom opens a share link without credentials from an IP address
+func (suite *ShareLinkTestSuite) viewFrom(ip, url, password string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req.RemoteAddr = ip + ":40000"
+	if password != "" {
+		req.Header.Set("X-Share-Password", password)
+	}
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+// share creates a share link for a transcription
+func (suite *ShareLinkTestSuite) share(jobID string, body gin.H) api.ShareLinkResponse {
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/transcription/"+jobID+"/shares", body)
+	require.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+	var link api.ShareLinkResponse
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &link))
+	return link
+}
+
+// completedJob creates a completed transcription with a transcript, summary and audio file
+func (suite *ShareLinkTestSuite) completedJob(title string) *models.TranscriptionJob {
+	t := suite.T()
+	job := suite.helper.CreateTestTranscriptionJob(t, title)
+	audioPath := filepath.Join(t.TempDir(), "recording.mp3")
+	require.NoError(t, os.WriteFile(audioPath, []byte("fake audio"), 0644))
+	require.NoError(t, suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status":     models.StatusCompleted,
+		"audio_path": audioPath,
+		"transcript": `{"segments":[{"start":0,"end":3,"text":"Welcome to the launch review.","speaker":"SPEAKER_00"},{"start":3,"end":6,"text":"Thanks for having me.","speaker":"SPEAKER_01"}]}`,
+	}).Error)
+	require.NoError(t, suite.helper.DB.Create(&models.Summary{TranscriptionID: job.ID, Model: "test", Content: "The launch is on track."}).Error)
+	require.NoError(t, suite.helper.DB.Create(&models.SpeakerMapping{TranscriptionJobID: job.ID, OriginalSpeaker: "SPEAKER_00", CustomName: "Dana"}).Error)
+	return job
+}
+
+func (suite *ShareLinkTestSuite) TestView() {
+	t := suite.T()
+	job := suite.completedJob("Launch review")
+	link := suite.share(job.ID, gin.H{"include_audio": true})
+	assert.Equal(t, "active", link.Status)
+	assert.False(t, link.HasPassword)
+	assert.Nil(t, link.ExpiresAt)
+
+	w := suite.view(link.URL, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "private, no-store", w.Header().Get("Cache-Control"))
+	var view api.SharedTranscriptionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &view))
+	assert.Equal(t, "Launch review", *view.Title)
+	require.Len(t, view.Segments, 2)
+	assert.Equal(t, "Dana", view.Segments[0].Speaker)
+	assert.Equal(t, "SPEAKER_01", view.Segments[1].Speaker)
+	assert.Equal(t, "The launch is on track.", view.Summary)
+
+	// The audio plays through a signed URL
+	require.NotEmpty(t, view.AudioURL)
+	w = suite.view(view.AudioURL, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "fake audio", w.Body.String())
+
+	// Views share the signed URL rather than each signing another
+	w = suite.view(link.URL, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var again api.SharedTranscriptionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &again))
+	assert.Equal(t, view.AudioURL, again.AudioURL)
+	var signed int64
+	require.NoError(t, suite.helper.DB.Model(&models.DownloadLink{}).Where("path = ?", "/api/v1/transcription/"+job.ID+"/audio").Count(&signed).Error)
+	assert.Equal(t, int64(1), signed)
+
+	// Until it is about to expire
+	require.NoError(t, suite.helper.DB.Model(&models.DownloadLink{}).Where("path = ?", "/api/v1/transcription/"+job.ID+"/audio").Update("expires_at", time.Now().Add(30*time.Second)).Error)
+	w = suite.view(link.URL, "")
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &again))
+	assert.NotEqual(t, view.AudioURL, again.AudioURL)
+	assert.Equal(t, http.StatusOK, suite.view(again.AudioURL, "").Code)
+
+	// Revoking the link stops the audio too
+	require.Equal(t, http.StatusOK, suite.helper.Request(suite.T(), suite.router, http.MethodDelete, "/api/v1/transcription/"+job.ID+"/shares/"+link.ID, nil).Code)
+	assert.Equal(t, http.StatusNotFound, suite.view(again.AudioURL, "").Code)
+
+	// Without include_audio there's none
+	w = suite.view(suite.share(job.ID, gin.H{}).URL, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "audio_url")
+
+	assert.Equal(t, http.StatusNotFound, suite.view("/api/v1/shared/not-a-token", "").Code)
+	pending := suite.helper.CreateTestTranscriptionJob(t, "Not done yet")
+	assert.Equal(t, http.StatusBadRequest, suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/transcription/"+pending.ID+"/shares", gin.H{}).Code)
+	assert.Equal(t, http.StatusBadRequest, suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/transcription/"+job.ID+"/shares", gin.H{"expires_at": time.Now().Add(-time.Hour)}).Code)
+	assert.Equal(t, http.StatusBadRequest, suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/transcription/"+job.ID+"/shares", gin.H{"password": "abc"}).Code)
+}
+
+func (suite *ShareLinkTestSuite) TestPasswordExpiryAndRevocation() {
+	t := suite.T()
+	job := suite.completedJob("Board call")
+	link := suite.share(job.ID, gin.H{"password": "open sesame", "expires_at": time.Now().Add(time.Hour)})
+	assert.True(t, link.HasPassword)
+	require.NotNil(t, link.ExpiresAt)
+
+	w := suite.view(link.URL, "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), `"password_required":true`)
+	assert.Equal(t, http.StatusUnauthorized, suite.view(link.URL, "guess").Code)
+	assert.Equal(t, http.StatusOK, suite.view(link.URL, "open sesame").Code)
+
+	// Expired links are refused
+	require.NoError(t, suite.helper.DB.Model(&models.ShareLink{}).Where("id = ?", link.ID).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	assert.Equal(t, http.StatusGone, suite.view(link.URL, "open sesame").Code)
+
+	// So are revoked ones
+	other := suite.share(job.ID, gin.H{})
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodDelete, "/api/v1/transcription/"+job.ID+"/shares/"+other.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"status":"revoked"`)
+	assert.Equal(t, http.StatusGone, suite.view(other.URL, "").Code)
+	assert.Equal(t, http.StatusNotFound, suite.helper.Request(suite.T(), suite.router, http.MethodDelete, "/api/v1/transcription/"+job.ID+"/shares/missing", nil).Code)
+
+	// Owners see their links and who opened them
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/transcription/"+job.ID+"/shares", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list struct {
+		ShareLinks []api.ShareLinkResponse `json:"share_links"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.ShareLinks, 2)
+	assert.Empty(t, list.ShareLinks[0].URL, "the URL is only returned when a link is created")
+	statuses := map[string]api.ShareLinkResponse{}
+	for _, l := range list.ShareLinks {
+		statuses[l.ID] = l
+	}
+	assert.Equal(t, "expired", statuses[link.ID].Status)
+	assert.Equal(t, 1, statuses[link.ID].ViewCount)
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/transcription/"+job.ID+"/shares/"+link.ID+"/accesses", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var log struct {
+		Accesses []models.ShareLinkAccess `json:"accesses"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &log))
+	outcomes := []string{}
+	for _, access := range log.Accesses {
+		outcomes = append(outcomes, access.Outcome)
+	}
+	assert.Equal(t, []string{models.ShareAccessExpired, models.ShareAccessViewed, models.ShareAccessWrongPassword}, outcomes)
+
+	// Deleting the transcription deletes its links
+	require.Equal(t, http.StatusOK, suite.helper.Request(suite.T(), suite.router, http.MethodDelete, "/api/v1/transcription/"+job.ID, nil).Code)
+	var count int64
+	require.NoError(t, suite.helper.DB.Model(&models.ShareLinkAccess{}).Where("share_link_id = ?", link.ID).Count(&count).Error)
+	assert.Zero(t, count)
+	assert.Equal(t, http.StatusNotFound, suite.view(other.URL, "").Code)
+}
+
+func (suite *ShareLinkTestSuite) TestPasswordAttemptsThrottled() {
+	t := suite.T()
+	job := suite.completedJob("Offsite")
+	link := suite.share(job.ID, gin.H{"password": "open sesame"})
+
+	for i := 0; i < 10; i++ {
+		require.Equal(t, http.StatusUnauthorized, suite.viewFrom("198.51.100.1", link.URL, "guess").Code)
+	}
+	w := suite.viewFrom("198.51.100.1", link.URL, "open sesame")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	// The link is throttled from every address
+	assert.Equal(t, http.StatusTooManyRequests, suite.viewFrom("198.51.100.2", link.URL, "open sesame").Code)
+
+	// And the address on every link
+	other := suite.share(job.ID, gin.H{"password": "open sesame"})
+	assert.Equal(t, http.StatusTooManyRequests, suite.viewFrom("198.51.100.1", other.URL, "open sesame").Code)
+	assert.Equal(t, http.StatusOK, suite.viewFrom("198.51.100.3", other.URL, "open sesame").Code)
+}
+
+func TestShareLinkTestSuite(t *testing.T) {
+	suite.Run(t, new(ShareLinkTestSuite))
+}
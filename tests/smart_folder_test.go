@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/database"
+	"scriberr/internal/folders"
+	"scriberr/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SmartFolderTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	router *gin.Engine
+
+	standup  *models.TranscriptionJob
+	planning *models.TranscriptionJob
+	untagged *models.TranscriptionJob
+}
+
+func (suite *SmartFolderTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "smart_folder_test.db")
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, nil, nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+
+	t := suite.T()
+	suite.standup = suite.helper.CreateTestTranscriptionJob(t, "Daily standup")
+	suite.planning = suite.helper.CreateTestTranscriptionJob(t, "Sprint planning")
+	suite.untagged = suite.helper.CreateTestTranscriptionJob(t, "Voice memo")
+
+	transcript := `{"segments":[{"start":0,"end":2,"text":"Budget review first","speaker":"SPEAKER_01"}]}`
+	require.NoError(t, database.DB.Model(suite.planning).Updates(map[string]interface{}{
+		"transcript": transcript,
+		"status":     models.StatusCompleted,
+	}).Error)
+	require.NoError(t, database.DB.Create(&models.SpeakerMapping{
+		TranscriptionJobID: suite.standup.ID,
+		OriginalSpeaker:    "SPEAKER_00",
+		CustomName:         "Alice",
+	}).Error)
+
+	// Backdate one job so date filters have something to exclude
+	require.NoError(t, database.DB.Model(suite.untagged).UpdateColumn("created_at", time.Now().AddDate(0, 0, -30)).Error)
+}
+
+func (suite *SmartFolderTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *SmartFolderTestSuite) setTags(jobID string, tags ...string) {
+	w := suite.helper.Request(suite.T(), suite.router, "PUT", "/api/v1/transcription/"+jobID+"/tags", map[string]interface{}{"tags": tags})
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+}
+
+func (suite *SmartFolderTestSuite) matching(filter models.SmartFolderFilter) []string {
+	require.NoError(suite.T(), folders.Validate(&filter))
+	ids, err := folders.MatchingJobIDs(&models.SmartFolder{Filter: filter})
+	require.NoError(suite.T(), err)
+	return ids
+}
+
+func (suite *SmartFolderTestSuite) TestUpdateTagsNormalizes() {
+	suite.setTags(suite.standup.ID, " Focus ", "focus", "#Weekly", "")
+
+	var job models.TranscriptionJob
+	require.NoError(suite.T(), database.DB.First(&job, "id = ?", suite.standup.ID).Error)
+	assert.Equal(suite.T(), []string{"Focus", "Weekly"}, job.Tags)
+
+	// Tags are shared, so a differently cased name reuses the existing tag's spelling
+	suite.setTags(suite.planning.ID, "FOCUS")
+	var planning models.TranscriptionJob
+	require.NoError(suite.T(), database.DB.First(&planning, "id = ?", suite.planning.ID).Error)
+	assert.Equal(suite.T(), []string{"Focus"}, planning.Tags)
+
+	w := suite.helper.Request(suite.T(), suite.router, "PUT", "/api/v1/transcription/missing/tags", map[string]interface{}{"tags": []string{"x"}})
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func (suite *SmartFolderTestSuite) TestFilterCriteria() {
+	suite.setTags(suite.standup.ID, "work", "daily")
+	suite.setTags(suite.planning.ID, "Work")
+
+	// Every tag must match, case-insensitively
+	assert.ElementsMatch(suite.T(), []string{suite.standup.ID, suite.planning.ID}, suite.matching(models.SmartFolderFilter{Tags: []string{"WORK"}}))
+	assert.Equal(suite.T(), []string{suite.standup.ID}, suite.matching(models.SmartFolderFilter{Tags: []string{"work", "daily"}}))
+
+	// Speakers match mapped names or raw transcript labels
+	assert.Equal(suite.T(), []string{suite.standup.ID}, suite.matching(models.SmartFolderFilter{Speakers: []string{"alice"}}))
+	assert.ElementsMatch(suite.T(), []string{suite.standup.ID, suite.planning.ID}, suite.matching(models.SmartFolderFilter{Speakers: []string{"Alice", "SPEAKER_01"}}))
+
+	// Text searches title and transcript
+	assert.Equal(suite.T(), []string{suite.planning.ID}, suite.matching(models.SmartFolderFilter{Text: "budget"}))
+	assert.Equal(suite.T(), []string{suite.untagged.ID}, suite.matching(models.SmartFolderFilter{Text: "memo"}))
+
+	weekAgo := time.Now().AddDate(0, 0, -7)
+	assert.Equal(suite.T(), []string{suite.untagged.ID}, suite.matching(models.SmartFolderFilter{CreatedBefore: &weekAgo}))
+	assert.ElementsMatch(suite.T(), []string{suite.standup.ID, suite.planning.ID}, suite.matching(models.SmartFolderFilter{CreatedAfter: &weekAgo}))
+
+	assert.Equal(suite.T(), []string{suite.planning.ID}, suite.matching(models.SmartFolderFilter{Status: models.StatusCompleted, Tags: []string{"work"}}))
+}
+
+func (suite *SmartFolderTestSuite) TestValidate() {
+	assert.Error(suite.T(), folders.Validate(&models.SmartFolderFilter{Status: "bogus"}))
+
+	after := time.Now()
+	before := after.Add(-time.Hour)
+	assert.Error(suite.T(), folders.Validate(&models.SmartFolderFilter{CreatedAfter: &after, CreatedBefore: &before}))
+}
+
+func (suite *SmartFolderTestSuite) TestFolderLifecycle() {
+	suite.setTags(suite.standup.ID, "team")
+	suite.setTags(suite.planning.ID, "team")
+
+	w := suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/folders", map[string]interface{}{
+		"name":   "Team meetings",
+		"filter": map[string]interface{}{"tags": []string{"team"}},
+	})
+	require.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+	var folder models.SmartFolder
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &folder))
+	assert.Equal(suite.T(), []string{"team"}, folder.Filter.Tags)
+
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/folders", nil)
+	require.Equal(suite.T(), http.StatusOK, w.Code)
+	var listed struct {
+		Folders []struct {
+			ID    string `json:"id"`
+			Count int64  `json:"count"`
+		} `json:"folders"`
+	}
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Len(suite.T(), listed.Folders, 1)
+	assert.Equal(suite.T(), int64(2), listed.Folders[0].Count)
+
+	// The folder acts as a filter on the job list
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/transcription/list?folder="+folder.ID, nil)
+	require.Equal(suite.T(), http.StatusOK, w.Code)
+	var jobs struct {
+		Jobs []models.TranscriptionJob `json:"jobs"`
+	}
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &jobs))
+	assert.Len(suite.T(), jobs.Jobs, 2)
+
+	w = suite.helper.Request(suite.T(), suite.router, "PUT", "/api/v1/folders/"+folder.ID, map[string]interface{}{
+		"name":   "Team standups",
+		"filter": map[string]interface{}{"tags": []string{"team"}, "text": "standup"},
+	})
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/transcription/list?folder="+folder.ID, nil)
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &jobs))
+	require.Len(suite.T(), jobs.Jobs, 1)
+	assert.Equal(suite.T(), suite.standup.ID, jobs.Jobs[0].ID)
+
+	w = suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/folders", map[string]interface{}{
+		"name":   "Broken",
+		"filter": map[string]interface{}{"status": "bogus"},
+	})
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	w = suite.helper.Request(suite.T(), suite.router, "DELETE", "/api/v1/folders/"+folder.ID, nil)
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/transcription/list?folder="+folder.ID, nil)
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func TestSmartFolderTestSuite(t *testing.T) {
+	suite.Run(t, new(SmartFolderTestSuite))
+}
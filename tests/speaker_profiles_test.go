@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+	"scriberr/internal/speakers"
+	"scriberr/internal/transcription"
+	"scriberr/internal/transcription/adapters"
+	"scriberr/internal/transcription/registry"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// SpeakerProfilesTestSuite runs diarized jobs through the fake diarizer, whose speakers sound
+// the same in every recording, and names them from enrolled speaker profiles
+type SpeakerProfilesTestSuite struct {
+	suite.Suite
+	helper    *TestHelper
+	processor *transcription.UnifiedJobProcessor
+	router    *gin.Engine
+}
+
+func (suite *SpeakerProfilesTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "speaker_profiles_test.db")
+
+	registry.ClearRegistry()
+	registry.RegisterTranscriptionAdapter("parakeet", adapters.NewFakeTranscriptionAdapter("parakeet"))
+	registry.RegisterDiarizationAdapter("pyannote", adapters.NewFakeDiarizationAdapter("pyannote"))
+	suite.processor = transcription.NewUnifiedJobProcessor()
+	require.NoError(suite.T(), suite.processor.InitEmbeddedPythonEnv())
+	suite.processor.GetUnifiedService().SetSpeakerIdentifier(speakers.NewMatcher(0))
+
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, queue.NewTaskQueue(1, &MockJobProcessor{}), nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *SpeakerProfilesTestSuite) TearDownSuite() {
+	registry.ClearRegistry()
+	suite.helper.Cleanup()
+}
+
+// transcribe runs a diarized job over 20 seconds of fake audio, two turns for each speaker
+func (suite *SpeakerProfilesTestSuite) transcribe() *models.TranscriptionJob {
+	path := filepath.Join(suite.T().TempDir(), "audio.wav")
+	require.NoError(suite.T(), os.WriteFile(path, make([]byte, 20*32000), 0644))
+
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Episode")
+	job.AudioPath = path
+	job.Parameters.ModelFamily = "nvidia_parakeet"
+	job.Parameters.Diarize = true
+	require.NoError(suite.T(), suite.helper.DB.Save(job).Error)
+	require.NoError(suite.T(), suite.processor.ProcessJob(context.Background(), job.ID))
+	return job
+}
+
+// mappings returns a job's speaker names by label
+func (suite *SpeakerProfilesTestSuite) mappings(jobID string) map[string]string {
+	var list []models.SpeakerMapping
+	require.NoError(suite.T(), suite.helper.DB.Where("transcription_job_id = ?", jobID).Find(&list).Error)
+	names := map[string]string{}
+	for _, mapping := range list {
+		names[mapping.OriginalSpeaker] = mapping.CustomName
+	}
+	return names
+}
+
+func (suite *SpeakerProfilesTestSuite) matches(jobID string) map[string]models.JobSpeaker {
+	w := suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/transcription/"+jobID+"/speaker-matches", nil)
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+	var list []models.JobSpeaker
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &list))
+	byLabel := map[string]models.JobSpeaker{}
+	for _, speaker := range list {
+		byLabel[speaker.Speaker] = speaker
+	}
+	return byLabel
+}
+
+func (suite *SpeakerProfilesTestSuite) TestEnrolledSpeakersAreNamedInLaterRecordings() {
+	t := suite.T()
+
+	// Nobody is known yet
+	first := suite.transcribe()
+	found := suite.matches(first.ID)
+	require.Len(t, found, 2)
+	assert.Equal(t, models.JobSpeakerUnmatched, found["SPEAKER_00"].Status)
+	assert.Empty(t, suite.mappings(first.ID))
+
+	w := suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/speaker-profiles", map[string]string{"name": "Alice", "transcription_id": first.ID, "speaker": "SPEAKER_00"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var alice models.SpeakerProfile
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &alice))
+	assert.Equal(t, 1, alice.SampleCount)
+	assert.Equal(t, "Alice", suite.mappings(first.ID)["SPEAKER_00"])
+
+	// The next recording names her automatically, and only her
+	second := suite.transcribe()
+	found = suite.matches(second.ID)
+	assert.Equal(t, models.JobSpeakerMatched, found["SPEAKER_00"].Status)
+	require.NotNil(t, found["SPEAKER_00"].SpeakerProfile)
+	assert.Equal(t, "Alice", found["SPEAKER_00"].SpeakerProfile.Name)
+	assert.InDelta(t, 1.0, found["SPEAKER_00"].Similarity, 1e-6)
+	assert.Equal(t, models.JobSpeakerUnmatched, found["SPEAKER_01"].Status)
+	assert.Equal(t, map[string]string{"SPEAKER_00": "Alice"}, suite.mappings(second.ID))
+
+	// Confirming teaches the profile the voice again
+	w = suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/transcription/"+second.ID+"/speaker-matches/SPEAKER_00/confirm", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, suite.helper.DB.First(&alice, "id = ?", alice.ID).Error)
+	assert.Equal(t, 2, alice.SampleCount)
+	w = suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/transcription/"+second.ID+"/speaker-matches/SPEAKER_01/confirm", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Correcting by name enrolls a new person
+	w = suite.helper.Request(suite.T(), suite.router, "PUT", "/api/v1/transcription/"+second.ID+"/speaker-matches/SPEAKER_01", map[string]string{"name": "Bob"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, map[string]string{"SPEAKER_00": "Alice", "SPEAKER_01": "Bob"}, suite.mappings(second.ID))
+
+	// Renaming a profile renames the speakers named after it
+	w = suite.helper.Request(suite.T(), suite.router, "PUT", "/api/v1/speaker-profiles/"+alice.ID, map[string]string{"name": "alice smith"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "alice smith", suite.mappings(first.ID)["SPEAKER_00"])
+	assert.Equal(t, "alice smith", suite.mappings(second.ID)["SPEAKER_00"])
+	w = suite.helper.Request(suite.T(), suite.router, "PUT", "/api/v1/speaker-profiles/"+alice.ID, map[string]string{"name": "BOB"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Names given by hand survive a re-run; automatic ones are decided again
+	w = suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/transcription/"+second.ID+"/speakers", map[string]interface{}{
+		"mappings": []map[string]string{{"original_speaker": "SPEAKER_01", "custom_name": "Host"}},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	third := suite.transcribe()
+	assert.Equal(t, map[string]string{"SPEAKER_00": "alice smith", "SPEAKER_01": "Bob"}, suite.mappings(third.ID))
+	require.NoError(t, suite.processor.ProcessJob(context.Background(), second.ID))
+	assert.Equal(t, map[string]string{"SPEAKER_00": "alice smith", "SPEAKER_01": "Host"}, suite.mappings(second.ID))
+
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/speaker-profiles", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var listed struct {
+		Profiles []models.SpeakerProfile `json:"profiles"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Len(t, listed.Profiles, 2)
+	assert.Equal(t, "Bob", listed.Profiles[0].Name)
+
+	// Deleting a profile keeps the names it gave
+	w = suite.helper.Request(suite.T(), suite.router, "DELETE", "/api/v1/speaker-profiles/"+alice.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.JobSpeakerUnmatched, suite.matches(third.ID)["SPEAKER_00"].Status)
+	assert.Equal(t, "alice smith", suite.mappings(third.ID)["SPEAKER_00"])
+}
+
+func (suite *SpeakerProfilesTestSuite) TestRequestsAreValidated() {
+	t := suite.T()
+	job := suite.transcribe()
+
+	w := suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/speaker-profiles", map[string]string{"name": "Carol", "transcription_id": job.ID, "speaker": "SPEAKER_07"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/speaker-profiles", map[string]string{"name": "  ", "transcription_id": job.ID, "speaker": "SPEAKER_00"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = suite.helper.Request(suite.T(), suite.router, "PUT", "/api/v1/transcription/"+job.ID+"/speaker-matches/SPEAKER_00", map[string]string{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = suite.helper.Request(suite.T(), suite.router, "PUT", "/api/v1/transcription/"+job.ID+"/speaker-matches/SPEAKER_00", map[string]string{"speaker_profile_id": "missing"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = suite.helper.Request(suite.T(), suite.router, "DELETE", "/api/v1/speaker-profiles/missing", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSpeakerProfilesTestSuite(t *testing.T) {
+	suite.Run(t, new(SpeakerProfilesTestSuite))
+}
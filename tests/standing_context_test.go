@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"scriberr/internal/embeddings"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/rag"
+	"scriberr/internal/vectordb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type StandingContextTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *StandingContextTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "standing_context_test.db")
+}
+
+func (suite *StandingContextTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *StandingContextTestSuite) TestStandingContextIsPrependedToChat() {
+	t := suite.T()
+	require.NoError(t, suite.helper.DB.Create(&models.StandingContext{Content: "Glossary:\nARR: annual recurring revenue\nQ3 goal: reach 2M ARR"}).Error)
+
+	service := &replyLLM{reply: "Answer"}
+	ragService := rag.NewRAGService(vectordb.NewMemoryStore(), embeddings.NewFakeEmbeddingService(), service)
+	job := suite.helper.CreateTestTranscriptionJob(t, "Revenue review")
+	require.NoError(t, ragService.StoreSummary(context.Background(), job.ID, "", "We discussed how ARR grew this quarter."))
+
+	_, err := ragService.Chat(context.Background(), nil, "How is ARR doing?", llm.FakeModel, 0.2, rag.ChatOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, service.prompt, "Standing context about the team:\nGlossary:\nARR: annual recurring revenue\nQ3 goal: reach 2M ARR")
+	assert.Less(t, strings.Index(service.prompt, "Q3 goal"), strings.Index(service.prompt, "Relevant context:"))
+
+	// Over budget, only the lines that fit are sent
+	ragService.SetStandingContextTokens(12)
+	_, err = ragService.Chat(context.Background(), nil, "How is ARR doing?", llm.FakeModel, 0.2, rag.ChatOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, service.prompt, "ARR: annual recurring revenue")
+	assert.NotContains(t, service.prompt, "Q3 goal")
+
+	ragService.SetStandingContextTokens(0)
+	_, err = ragService.Chat(context.Background(), nil, "How is ARR doing?", llm.FakeModel, 0.2, rag.ChatOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, service.prompt, "Standing context")
+}
+
+func TestStandingContextTestSuite(t *testing.T) {
+	suite.Run(t, new(StandingContextTestSuite))
+}
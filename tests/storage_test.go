@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/scheduler"
+	"scriberr/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type StorageTestSuite struct {
+	suite.Suite
+	helper    *TestHelper
+	store     *storage.MemoryStore
+	files     *storage.Files
+	scheduler *scheduler.Service
+	router    *gin.Engine
+}
+
+func (suite *StorageTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "storage_test.db")
+	suite.store = storage.NewMemoryStore()
+	suite.files = storage.NewFiles(suite.store, "scriberr")
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, nil, nil, nil, nil)
+	handler.SetFileStore(suite.files)
+	suite.scheduler = scheduler.NewService()
+	handler.SetScheduler(suite.scheduler)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *StorageTestSuite) TearDownSuite() {
+	suite.scheduler.Stop()
+	suite.helper.Cleanup()
+}
+
+func (suite *StorageTestSuite) stored(localPath string) bool {
+	body, err := suite.store.Get(context.Background(), suite.files.Key(localPath))
+	if err != nil {
+		return false
+	}
+	body.Close()
+	return true
+}
+
+func (suite *StorageTestSuite) TestUploadsAreStoredAndFetchedBack() {
+	t := suite.T()
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, err := writer.CreateFormFile("audio", "meeting.mp3")
+	require.NoError(t, err)
+	part.Write([]byte("fake audio"))
+	require.NoError(t, writer.Close())
+	req := suite.helper.NewRequest(t, suite.helper.TestAPIKey, "POST", "/api/v1/transcription/upload", &form)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := serve(suite.router, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var job models.TranscriptionJob
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.True(t, suite.stored(job.AudioPath))
+
+	// Another node doesn't have the file and fetches it from the store
+	require.NoError(t, os.Remove(job.AudioPath))
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/transcription/"+job.ID+"/audio", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "fake audio", w.Body.String())
+
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/transcription/"+job.ID+"/audio/url", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var presigned api.PresignedURLResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &presigned))
+	assert.Equal(t, http.MethodGet, presigned.Method)
+	assert.Contains(t, presigned.URL, job.ID+".mp3")
+
+	transcript := `{"text":"hello"}`
+	require.NoError(t, suite.files.SaveTranscript(context.Background(), job.ID, transcript))
+	require.Equal(t, http.StatusOK, suite.helper.Request(suite.T(), suite.router, "DELETE", "/api/v1/transcription/"+job.ID, nil).Code)
+	assert.False(t, suite.stored(job.AudioPath), "deleting the job deletes its stored audio")
+	_, err = suite.store.Get(context.Background(), suite.files.TranscriptKey(job.ID))
+	assert.ErrorIs(t, err, storage.ErrNotFound, "and its stored transcript")
+}
+
+func (suite *StorageTestSuite) TestPresignedUpload() {
+	t := suite.T()
+	w := suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/transcription/upload-url", gin.H{"filename": "call.m4a"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var presigned api.PresignedURLResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &presigned))
+	assert.Equal(t, http.MethodPut, presigned.Method)
+	assert.True(t, strings.HasSuffix(presigned.Key, presigned.ID+".m4a"))
+
+	complete := gin.H{"key": presigned.Key, "title": "Call", "content_type": models.ContentVoiceMemo}
+	assert.Equal(t, http.StatusNotFound, suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/transcription/upload-url/complete", complete).Code,
+		"nothing was uploaded yet")
+
+	// The client uploads straight to the store
+	require.NoError(t, suite.store.Put(context.Background(), presigned.Key, strings.NewReader("call audio"), 10))
+	w = suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/transcription/upload-url/complete", complete)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var job models.TranscriptionJob
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, presigned.ID, job.ID)
+	assert.Equal(t, models.StatusUploaded, job.Status)
+	assert.Equal(t, models.ContentVoiceMemo, job.ContentType)
+	data, err := os.ReadFile(job.AudioPath)
+	require.NoError(t, err)
+	assert.Equal(t, "call audio", string(data))
+
+	assert.Equal(t, http.StatusConflict, suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/transcription/upload-url/complete", complete).Code)
+	for _, key := range []string{"scriberr/elsewhere/" + presigned.ID + ".m4a", "scriberr/" + suite.helper.Config.UploadDir + "/not-a-uuid.m4a"} {
+		w = suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/transcription/upload-url/complete", gin.H{"key": key})
+		assert.Equal(t, http.StatusBadRequest, w.Code, key)
+	}
+}
+
+func (suite *StorageTestSuite) TestStorageCleanup() {
+	t := suite.T()
+	ctx := context.Background()
+	longAgo := time.Now().Add(-48 * time.Hour)
+	put := func(key string, modified time.Time) {
+		require.NoError(t, suite.store.Put(ctx, key, strings.NewReader("x"), 1))
+		suite.store.SetModified(key, modified)
+	}
+
+	kept := models.TranscriptionJob{Status: models.StatusCompleted, AudioPath: filepath.Join(suite.helper.Config.UploadDir, "kept.mp3")}
+	require.NoError(t, suite.helper.DB.Create(&kept).Error)
+	keptKey := suite.files.Key(kept.AudioPath)
+	abandonedKey := suite.files.Key(filepath.Join(suite.helper.Config.UploadDir, "abandoned.mp3"))
+	freshKey := suite.files.Key(filepath.Join(suite.helper.Config.UploadDir, "fresh.mp3"))
+	otherKey := "scriberr/backups/db.sqlite"
+	put(keptKey, longAgo)
+	put(abandonedKey, longAgo)
+	put(freshKey, time.Now())
+	put(otherKey, longAgo)
+	put(suite.files.TranscriptKey(kept.ID), longAgo)
+	put(suite.files.TranscriptKey("deleted-job"), longAgo)
+
+	w := suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/admin/schedules", gin.H{"name": "Tidy bucket", "action": api.ActionStorageCleanup, "cron": "@weekly", "enabled": false})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var schedule models.Schedule
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &schedule))
+	require.Equal(t, http.StatusAccepted, suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/admin/schedules/"+schedule.ID+"/run", nil).Code)
+
+	var run models.ScheduleRun
+	require.Eventually(t, func() bool {
+		err := suite.helper.DB.Where("schedule_id = ?", schedule.ID).First(&run).Error
+		return err == nil && run.Status != models.ScheduleRunRunning
+	}, 5*time.Second, 20*time.Millisecond)
+	require.Equal(t, models.ScheduleRunCompleted, run.Status, run.Error)
+	assert.EqualValues(t, 2, run.Result["deleted"])
+
+	listed, err := suite.store.List(ctx, "scriberr/")
+	require.NoError(t, err)
+	var keys []string
+	for _, object := range listed {
+		keys = append(keys, object.Key)
+	}
+	assert.Contains(t, keys, keptKey)
+	assert.Contains(t, keys, freshKey, "recent uploads may still be completed")
+	assert.Contains(t, keys, otherKey, "objects outside the uploads are left alone")
+	assert.Contains(t, keys, suite.files.TranscriptKey(kept.ID))
+	assert.NotContains(t, keys, abandonedKey)
+	assert.NotContains(t, keys, suite.files.TranscriptKey("deleted-job"))
+}
+
+func TestStorageTestSuite(t *testing.T) {
+	suite.Run(t, new(StorageTestSuite))
+}
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/events"
+	"scriberr/internal/models"
+	"scriberr/internal/tagging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SyncTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	router *gin.Engine
+}
+
+func (suite *SyncTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "sync_test.db")
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, nil, nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *SyncTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// sync fetches the changes since a cursor, everything when it is empty
+func (suite *SyncTestSuite) sync(cursor string, limit int) api.SyncResponse {
+	path := fmt.Sprintf("/api/v1/sync?limit=%d", limit)
+	if cursor != "" {
+		path += "&cursor=" + url.QueryEscape(cursor)
+	}
+	w := suite.helper.Request(suite.T(), suite.router, "GET", path, nil)
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+	var response api.SyncResponse
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func jobIDs(response api.SyncResponse) []string {
+	ids := make([]string, len(response.Jobs))
+	for i, job := range response.Jobs {
+		ids[i] = job.ID
+	}
+	return ids
+}
+
+func (suite *SyncTestSuite) TestDeltasSinceCursor() {
+	t := suite.T()
+	summarized := suite.helper.CreateTestTranscriptionJob(t, "Summarized")
+	summary := "The budget was approved."
+	require.NoError(t, suite.helper.DB.Model(summarized).Update("summary", summary).Error)
+	renamed := suite.helper.CreateTestTranscriptionJob(t, "Renamed")
+	removed := suite.helper.CreateTestTranscriptionJob(t, "Removed")
+	_, err := tagging.SetJobTags(renamed, []string{"budget"})
+	require.NoError(t, err)
+
+	full := suite.sync("", 100)
+	assert.True(t, full.Full)
+	assert.False(t, full.HasMore)
+	assert.Subset(t, jobIDs(full), []string{summarized.ID, renamed.ID, removed.ID})
+	assert.Contains(t, full.Summaries, api.SyncSummary{TranscriptionID: summarized.ID, Content: summary})
+	require.Len(t, full.Tags, 1)
+	tagID := full.Tags[0].ID
+	assert.Empty(t, full.Deleted.Jobs)
+
+	// Nothing changed, nothing to send
+	quiet := suite.sync(full.Cursor, 100)
+	assert.False(t, quiet.Full)
+	assert.Empty(t, quiet.Jobs)
+	assert.Empty(t, quiet.Tags)
+
+	added := suite.helper.CreateTestTranscriptionJob(t, "Added")
+	require.NoError(t, suite.helper.DB.Model(renamed).Update("title", "Budget review").Error)
+	require.Equal(t, http.StatusOK, suite.helper.Request(suite.T(), suite.router, "DELETE", "/api/v1/transcription/"+summarized.ID+"/summary", nil).Code)
+	require.Equal(t, http.StatusOK, suite.helper.Request(suite.T(), suite.router, "DELETE", "/api/v1/transcription/"+removed.ID, nil).Code)
+	require.Equal(t, http.StatusOK, suite.helper.Request(suite.T(), suite.router, "DELETE", fmt.Sprintf("/api/v1/tags/%d", tagID), nil).Code)
+
+	delta := suite.sync(quiet.Cursor, 100)
+	assert.ElementsMatch(t, []string{added.ID, renamed.ID, summarized.ID}, jobIDs(delta))
+	for _, job := range delta.Jobs {
+		assert.False(t, job.HasSummary)
+		if job.ID == renamed.ID {
+			assert.Equal(t, "Budget review", *job.Title)
+			assert.Empty(t, job.Tags, "the deleted tag is gone from the transcription")
+		}
+	}
+	assert.Equal(t, []string{removed.ID}, delta.Deleted.Jobs)
+	assert.Equal(t, []string{summarized.ID}, delta.Deleted.Summaries)
+	assert.Equal(t, []uint{tagID}, delta.Deleted.Tags)
+	assert.Empty(t, delta.Summaries)
+
+	// Summaries are sent when they are written, not with every change to their transcription
+	rewritten := "The budget was approved with changes."
+	require.NoError(t, suite.helper.DB.Model(summarized).Update("summary", rewritten).Error)
+	events.RecordForJob(models.EventSummaryReady, summarized.ID, nil)
+	next := suite.sync(delta.Cursor, 100)
+	assert.Equal(t, []api.SyncSummary{{TranscriptionID: summarized.ID, Content: rewritten}}, next.Summaries)
+	assert.Empty(t, next.Deleted.Summaries)
+
+	require.NoError(t, suite.helper.DB.Model(added).Update("title", "Renamed again").Error)
+	assert.Empty(t, suite.sync(next.Cursor, 100).Summaries)
+}
+
+func (suite *SyncTestSuite) TestPagesThroughChanges() {
+	t := suite.T()
+	for i := 0; i < 5; i++ {
+		suite.helper.CreateTestTranscriptionJob(t, fmt.Sprintf("Page %d", i))
+	}
+	all := suite.sync("", 1000)
+
+	var paged []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 100, "paging should end")
+		response := suite.sync(cursor, 2)
+		paged = append(paged, jobIDs(response)...)
+		cursor = response.Cursor
+		if !response.HasMore {
+			break
+		}
+	}
+	assert.ElementsMatch(t, jobIDs(all), paged)
+}
+
+func (suite *SyncTestSuite) TestRejectsBadParameters() {
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/sync?cursor=yesterday", nil).Code)
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/sync?limit=0", nil).Code)
+}
+
+func TestSyncTestSuite(t *testing.T) {
+	suite.Run(t, new(SyncTestSuite))
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"testing"
+
+	"scriberr/internal/models"
+	"scriberr/internal/tagging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TaggingTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *TaggingTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "tagging_test.db")
+}
+
+func (suite *TaggingTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *TaggingTestSuite) storedTags(id string) []string {
+	var job models.TranscriptionJob
+	require.NoError(suite.T(), suite.helper.DB.First(&job, "id = ?", id).Error)
+	return job.Tags
+}
+
+func (suite *TaggingTestSuite) TestRenameAndDeleteUpdateTranscriptions() {
+	t := suite.T()
+	first := suite.helper.CreateTestTranscriptionJob(t, "Standup")
+	second := suite.helper.CreateTestTranscriptionJob(t, "Retro")
+	_, err := tagging.SetJobTags(first, []string{"Team", "Sprint"})
+	require.NoError(t, err)
+	_, err = tagging.SetJobTags(second, []string{"team"})
+	require.NoError(t, err)
+
+	var tag models.Tag
+	require.NoError(t, tagging.OwnedBy(suite.helper.DB, nil).Where("name = ?", "Team").First(&tag).Error)
+
+	_, err = tagging.Rename(&tag, "sprint")
+	assert.ErrorIs(t, err, tagging.ErrTagExists)
+
+	ids, err := tagging.Rename(&tag, "Platform team")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{first.ID, second.ID}, ids)
+	assert.Equal(t, []string{"Platform team", "Sprint"}, suite.storedTags(first.ID))
+	assert.Equal(t, []string{"Platform team"}, suite.storedTags(second.ID))
+
+	ids, err = tagging.Delete(&tag)
+	require.NoError(t, err)
+	assert.Len(t, ids, 2)
+	assert.Equal(t, []string{"Sprint"}, suite.storedTags(first.ID))
+	assert.Empty(t, suite.storedTags(second.ID))
+}
+
+func (suite *TaggingTestSuite) TestMigrateJobTags() {
+	t := suite.T()
+	job := suite.helper.CreateTestTranscriptionJob(t, "Legacy")
+	job.Tags = []string{"Legacy", "Archive"}
+	require.NoError(t, suite.helper.DB.Save(job).Error)
+
+	migrated, err := tagging.MigrateJobTags()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, migrated, 1)
+
+	var count int64
+	require.NoError(t, suite.helper.DB.Model(&models.TranscriptionTag{}).Where("transcription_id = ?", job.ID).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	// Migrated transcriptions are not migrated again
+	again, err := tagging.MigrateJobTags()
+	require.NoError(t, err)
+	assert.Zero(t, again)
+}
+
+func TestTaggingTestSuite(t *testing.T) {
+	suite.Run(t, new(TaggingTestSuite))
+}
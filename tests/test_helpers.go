@@ -1,6 +1,11 @@
 package tests
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -12,6 +17,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
@@ -88,6 +94,7 @@ func (h *TestHelper) createTestCredentials(t *testing.T) {
 	user := models.User{
 		Username: "testuser",
 		Password: hashedPassword,
+		Role:     models.RoleAdmin,
 	}
 
 	result := h.DB.Create(&user)
@@ -111,6 +118,53 @@ func (h *TestHelper) createTestCredentials(t *testing.T) {
 	h.TestAPIKey = apiKey.Key
 }
 
+// NewRequest builds a request authenticated with credential: a JWT is sent as a bearer token,
+// anything else as an API key, and an empty credential sends none. An io.Reader body is sent
+// as is, any other body as JSON, and a nil body not at all.
+func (h *TestHelper) NewRequest(t *testing.T, credential, method, path string, body interface{}) *http.Request {
+	var reader io.Reader
+	contentType := ""
+	switch body := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = body
+	default:
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(data)
+		contentType = "application/json"
+	}
+	req, err := http.NewRequest(method, path, reader)
+	require.NoError(t, err)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	if _, err := h.AuthService.ValidateToken(credential); err == nil {
+		req.Header.Set("Authorization", "Bearer "+credential)
+	} else if credential != "" {
+		req.Header.Set("X-API-Key", credential)
+	}
+	return req
+}
+
+// Request sends a request to router with the test API key; see NewRequest for the body
+func (h *TestHelper) Request(t *testing.T, router http.Handler, method, path string, body interface{}) *httptest.ResponseRecorder {
+	return h.RequestAs(t, router, h.TestAPIKey, method, path, body)
+}
+
+// RequestAs sends a request to router with credential; see NewRequest
+func (h *TestHelper) RequestAs(t *testing.T, router http.Handler, credential, method, path string, body interface{}) *httptest.ResponseRecorder {
+	return serve(router, h.NewRequest(t, credential, method, path, body))
+}
+
+// serve sends req to router and records the response
+func serve(router http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
 // CreateTestTranscriptionJob creates a test transcription job
 func (h *TestHelper) CreateTestTranscriptionJob(t *testing.T, title string) *models.TranscriptionJob {
 	// Let GORM assign a unique UUID via model hook to avoid ID collisions
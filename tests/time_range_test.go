@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/transcription/interfaces"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// promptRecorder is the fake provider, keeping the last prompt it was sent
+type promptRecorder struct {
+	*llm.FakeService
+	prompt string
+}
+
+func (r *promptRecorder) ChatCompletion(ctx context.Context, model string, messages []llm.ChatMessage, temperature float64) (*llm.ChatResponse, error) {
+	r.prompt = messages[len(messages)-1].Content
+	return r.FakeService.ChatCompletion(ctx, model, messages, temperature)
+}
+
+type TimeRangeTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	llm    *promptRecorder
+	router *gin.Engine
+	job    *models.TranscriptionJob
+}
+
+func (suite *TimeRangeTestSuite) SetupSuite() {
+	t := suite.T()
+	suite.helper = NewTestHelper(t, "time_range_test.db")
+	suite.llm = &promptRecorder{FakeService: llm.NewFakeService()}
+	registry := llm.NewRegistry()
+	registry.Register("fake", suite.llm)
+	require.NoError(t, registry.Bind(llm.FeatureSummary, "fake", "summary-model"))
+	require.NoError(t, registry.Bind(llm.FeatureChat, "fake", "chat-model"))
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, nil, nil, nil, nil)
+	handler.SetLLMRegistry(registry)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+
+	// A meeting with one agenda item per ten minutes
+	alice, bob := "SPEAKER_00", "SPEAKER_01"
+	result := interfaces.TranscriptResult{Segments: []interfaces.TranscriptSegment{
+		{Start: 0, End: 600, Text: "Welcome, first the budget.", Speaker: &alice},
+		{Start: 600, End: 1200, Text: "Hiring: we need two engineers.", Speaker: &bob},
+		{Start: 1200, End: 1800, Text: "Finally, the offsite is in May.", Speaker: &alice},
+	}}
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	transcript := string(data)
+	suite.job = suite.helper.CreateTestTranscriptionJob(t, "Board meeting")
+	suite.job.Transcript = &transcript
+	suite.job.Status = models.StatusCompleted
+	summary := "The whole meeting"
+	suite.job.Summary = &summary
+	require.NoError(t, suite.helper.DB.Save(suite.job).Error)
+	require.NoError(t, suite.helper.DB.Create(&models.SpeakerMapping{TranscriptionJobID: suite.job.ID, OriginalSpeaker: bob, CustomName: "Bob"}).Error)
+}
+
+func (suite *TimeRangeTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *TimeRangeTestSuite) post(path string, body interface{}) (*httptest.ResponseRecorder, api.RangeResponse) {
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/transcription/"+suite.job.ID+path, body)
+	var response api.RangeResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+func (suite *TimeRangeTestSuite) TestSummarizeOnlyTheRange() {
+	t := suite.T()
+	w, response := suite.post("/range/summarize", gin.H{"from": 650, "to": 1100})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 1, response.Segments)
+	assert.Equal(t, "summary-model", response.Model)
+	assert.NotEmpty(t, response.Content)
+	assert.Contains(t, suite.llm.prompt, "00:10:50-00:18:20")
+	assert.Contains(t, suite.llm.prompt, "[600] Bob: Hiring: we need two engineers.")
+	assert.NotContains(t, suite.llm.prompt, "budget")
+	assert.NotContains(t, suite.llm.prompt, "offsite")
+
+	var job models.TranscriptionJob
+	require.NoError(t, suite.helper.DB.First(&job, "id = ?", suite.job.ID).Error)
+	assert.Equal(t, "The whole meeting", *job.Summary, "the transcription's summary is kept")
+}
+
+func (suite *TimeRangeTestSuite) TestAskAboutTheRangeToTheEnd() {
+	t := suite.T()
+	w, response := suite.post("/range/ask", gin.H{"from": 1200, "question": "When is the offsite?"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 1, response.Segments)
+	assert.Equal(t, "chat-model", response.Model)
+	assert.Contains(t, suite.llm.prompt, "Question: When is the offsite?")
+	assert.Contains(t, suite.llm.prompt, "offsite is in May")
+	assert.NotContains(t, suite.llm.prompt, "engineers", "a segment ending where the range starts is left out")
+}
+
+func (suite *TimeRangeTestSuite) TestInvalidRanges() {
+	t := suite.T()
+	for _, body := range []gin.H{
+		{"from": 600, "to": 300},
+		{"from": -5},
+		{"from": 5000},
+	} {
+		w, _ := suite.post("/range/summarize", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	w, _ := suite.post("/range/ask", gin.H{"from": 0})
+	assert.Equal(t, http.StatusBadRequest, w.Code, "a question is required")
+}
+
+func TestTimeRangeTestSuite(t *testing.T) {
+	suite.Run(t, new(TimeRangeTestSuite))
+}
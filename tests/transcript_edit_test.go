@@ -0,0 +1,206 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/embeddings"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+	"scriberr/internal/rag"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/internal/vectordb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// TranscriptEditTestSuite corrects segments of indexed transcripts and checks that only the
+// corrected chunks are embedded again
+type TranscriptEditTestSuite struct {
+	suite.Suite
+	helper     *TestHelper
+	embeddings *countingEmbeddings
+	rag        *rag.RAGService
+	router     *gin.Engine
+}
+
+func (suite *TranscriptEditTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "transcript_edit_test.db")
+	suite.embeddings = &countingEmbeddings{FakeEmbeddingService: embeddings.NewFakeEmbeddingService()}
+	suite.rag = rag.NewRAGService(vectordb.NewMemoryStore(), suite.embeddings, llm.NewFakeService())
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, queue.NewTaskQueue(1, &MockJobProcessor{}), nil, nil, suite.rag)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *TranscriptEditTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// job creates a completed transcript with one speaker-labelled segment per text, each with
+// the timing of its first word
+func (suite *TranscriptEditTestSuite) job(texts ...string) *models.TranscriptionJob {
+	segments := []map[string]interface{}{}
+	words := []map[string]interface{}{}
+	for i, text := range texts {
+		start := float64(i * 10)
+		word := map[string]interface{}{"start": start, "end": start + 1, "word": "first", "speaker": "SPEAKER_00"}
+		segments = append(segments, map[string]interface{}{
+			"start": start, "end": start + 10, "text": text, "speaker": "SPEAKER_00", "words": []interface{}{word},
+		})
+		words = append(words, word)
+	}
+	data, err := json.Marshal(map[string]interface{}{"text": strings.Join(texts, " "), "segments": segments, "word_segments": words})
+	require.NoError(suite.T(), err)
+	transcript := string(data)
+
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Corrections")
+	job.Transcript = &transcript
+	job.Status = models.StatusCompleted
+	require.NoError(suite.T(), suite.helper.DB.Save(job).Error)
+	return job
+}
+
+func (suite *TranscriptEditTestSuite) edit(jobID, index string, body interface{}) *httptest.ResponseRecorder {
+	return suite.helper.Request(suite.T(), suite.router, "PATCH", "/api/v1/transcription/"+jobID+"/segments/"+index, body)
+}
+
+// editedTranscript is the part of a stored transcript corrections touch
+type editedTranscript struct {
+	Text     string `json:"text"`
+	Segments []struct {
+		Text    string                      `json:"text"`
+		Speaker *string                     `json:"speaker"`
+		Words   []interfaces.TranscriptWord `json:"words"`
+	} `json:"segments"`
+	WordSegments []interfaces.TranscriptWord `json:"word_segments"`
+}
+
+func (suite *TranscriptEditTestSuite) transcript(jobID string) editedTranscript {
+	var job models.TranscriptionJob
+	require.NoError(suite.T(), suite.helper.DB.First(&job, "id = ?", jobID).Error)
+	var result editedTranscript
+	require.NoError(suite.T(), json.Unmarshal([]byte(*job.Transcript), &result))
+	return result
+}
+
+func (suite *TranscriptEditTestSuite) TestCorrectionIsSavedAndReembedded() {
+	t := suite.T()
+	texts := []string{paragraph("alpha"), paragraph("bravo"), paragraph("charlie")}
+	job := suite.job(texts...)
+	require.NoError(t, suite.rag.StoreSummary(context.Background(), job.ID, "", strings.Join(texts, " ")))
+
+	suite.embeddings.calls = 0
+	w := suite.edit(job.ID, "1", map[string]string{"text": "  " + paragraph("delta") + " "})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response struct {
+		Segment   map[string]interface{}     `json:"segment"`
+		Revision  *models.TranscriptRevision `json:"revision"`
+		Reindexed bool                       `json:"reindexed"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Reindexed)
+	assert.Equal(t, float64(1), response.Segment["index"])
+	require.NotNil(t, response.Revision)
+	assert.Equal(t, 1, response.Revision.Revision)
+	assert.Equal(t, texts[1], response.Revision.PreviousText)
+	assert.Equal(t, 2, suite.embeddings.calls, "summary entry and the corrected chunk")
+
+	result := suite.transcript(job.ID)
+	assert.Equal(t, paragraph("delta"), result.Segments[1].Text)
+	assert.Empty(t, result.Segments[1].Words, "word timings no longer match the text")
+	assert.Len(t, result.WordSegments, 2)
+	assert.Equal(t, strings.Join([]string{texts[0], paragraph("delta"), texts[2]}, " "), result.Text)
+
+	hits, err := suite.rag.Search(t.Context(), nil, "delta", 1, []string{job.ID})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Contains(t, hits[0].Snippet, "delta")
+
+	var updated models.Event
+	require.NoError(t, suite.helper.DB.Where("type = ? AND subject_id = ?", models.EventIndexUpdated, job.ID).Order("sequence DESC").First(&updated).Error)
+	assert.Equal(t, float64(2), updated.Data["embedded"])
+	assert.Equal(t, float64(2), updated.Data["reused"])
+	assert.Equal(t, int64(1), suite.count(models.EventTranscriptEdited, job.ID))
+
+	// Repeating the correction changes nothing
+	w = suite.edit(job.ID, "1", map[string]string{"text": paragraph("delta")})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Nil(t, response.Revision)
+	assert.False(t, response.Reindexed)
+}
+
+func (suite *TranscriptEditTestSuite) TestSpeakerCorrectionsKeepHistory() {
+	t := suite.T()
+	job := suite.job("Hello there.", "General Kenobi.")
+
+	w := suite.edit(job.ID, "1", map[string]string{"speaker": "SPEAKER_01"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = suite.edit(job.ID, "1", map[string]string{"text": "General Kenobi!"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = suite.edit(job.ID, "0", map[string]string{"text": "Hello there!"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	result := suite.transcript(job.ID)
+	require.NotNil(t, result.Segments[1].Speaker)
+	assert.Equal(t, "SPEAKER_01", *result.Segments[1].Speaker)
+	assert.Equal(t, "Hello there! General Kenobi!", result.Text)
+	assert.Empty(t, result.WordSegments)
+
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/transcription/"+job.ID+"/revisions?segment=1", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var listed struct {
+		Revisions []models.TranscriptRevision `json:"revisions"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Len(t, listed.Revisions, 2)
+	assert.Equal(t, 2, listed.Revisions[0].Revision)
+	assert.Equal(t, "General Kenobi.", listed.Revisions[0].PreviousText)
+	assert.Equal(t, "General Kenobi!", listed.Revisions[0].Text)
+	require.NotNil(t, listed.Revisions[1].PreviousSpeaker)
+	assert.Equal(t, "SPEAKER_00", *listed.Revisions[1].PreviousSpeaker)
+	assert.Equal(t, "SPEAKER_01", *listed.Revisions[1].Speaker)
+
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/transcription/"+job.ID+"/revisions", nil)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	assert.Len(t, listed.Revisions, 3)
+}
+
+func (suite *TranscriptEditTestSuite) TestCorrectionsAreValidated() {
+	t := suite.T()
+	job := suite.job("Only segment.")
+
+	assert.Equal(t, http.StatusBadRequest, suite.edit(job.ID, "0", map[string]string{}).Code)
+	assert.Equal(t, http.StatusBadRequest, suite.edit(job.ID, "0", map[string]string{"text": "   "}).Code)
+	assert.Equal(t, http.StatusBadRequest, suite.edit(job.ID, "0", map[string]string{"speaker": strings.Repeat("x", 51)}).Code)
+	assert.Equal(t, http.StatusBadRequest, suite.edit(job.ID, "-1", map[string]string{"text": "x"}).Code)
+	assert.Equal(t, http.StatusNotFound, suite.edit(job.ID, "1", map[string]string{"text": "x"}).Code)
+	assert.Equal(t, http.StatusNotFound, suite.edit("missing", "0", map[string]string{"text": "x"}).Code)
+
+	plain := "Just some text."
+	require.NoError(t, suite.helper.DB.Model(job).Update("transcript", plain).Error)
+	assert.Equal(t, http.StatusBadRequest, suite.edit(job.ID, "0", map[string]string{"text": "x"}).Code)
+
+	require.NoError(t, suite.helper.DB.Model(job).Update("legal_hold", true).Error)
+	assert.Equal(t, http.StatusConflict, suite.edit(job.ID, "0", map[string]string{"text": "x"}).Code)
+	assert.Equal(t, int64(0), suite.count(models.EventTranscriptEdited, job.ID))
+}
+
+func (suite *TranscriptEditTestSuite) count(eventType, subjectID string) int64 {
+	var count int64
+	require.NoError(suite.T(), suite.helper.DB.Model(&models.Event{}).Where("type = ? AND subject_id = ?", eventType, subjectID).Count(&count).Error)
+	return count
+}
+
+func TestTranscriptEditTestSuite(t *testing.T) {
+	suite.Run(t, new(TranscriptEditTestSuite))
+}
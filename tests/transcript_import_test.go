@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/database"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TranscriptImportTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	router *gin.Engine
+}
+
+func (suite *TranscriptImportTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "transcript_import_test.db")
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, queue.NewTaskQueue(1, &MockJobProcessor{}), nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *TranscriptImportTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// importTranscript posts a transcript file, and audio when it isn't nil, with form fields
+func (suite *TranscriptImportTestSuite) importTranscript(filename, content string, audio []byte, fields map[string]string) *httptest.ResponseRecorder {
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, err := writer.CreateFormFile("file", filename)
+	require.NoError(suite.T(), err)
+	part.Write([]byte(content))
+	if audio != nil {
+		part, err := writer.CreateFormFile("audio", "recording.mp3")
+		require.NoError(suite.T(), err)
+		part.Write(audio)
+	}
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+	require.NoError(suite.T(), writer.Close())
+	req := suite.helper.NewRequest(suite.T(), suite.helper.TestAPIKey, http.MethodPost, "/api/v1/transcription/import-transcript", &form)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return serve(suite.router, req)
+}
+
+func (suite *TranscriptImportTestSuite) TestImportOtter() {
+	otter := "Jane Doe  0:03\nWelcome to the review.\n\nOmar  0:10\nThanks, Jane.\n\nTranscribed by https://otter.ai\n"
+	w := suite.importTranscript("Quarterly review.txt", otter, []byte("fake audio"), map[string]string{"content_type": "voice_memo"})
+	require.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+
+	var resp api.TranscriptImportResponse
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(suite.T(), "otter", resp.Format)
+	assert.Equal(suite.T(), 2, resp.Segments)
+	assert.Equal(suite.T(), 2, resp.Speakers)
+	assert.False(suite.T(), resp.PostProcessing)
+
+	var job models.TranscriptionJob
+	require.NoError(suite.T(), database.DB.Where("id = ?", resp.Job.ID).First(&job).Error)
+	assert.Equal(suite.T(), models.StatusCompleted, job.Status)
+	assert.Equal(suite.T(), "Quarterly review", *job.Title)
+	assert.Equal(suite.T(), models.ContentVoiceMemo, job.ContentType)
+	assert.True(suite.T(), job.Diarization)
+	require.NotNil(suite.T(), job.Transcript)
+	assert.Contains(suite.T(), *job.Transcript, `"speaker":"SPEAKER_01"`)
+	audio, err := os.ReadFile(job.AudioPath)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "fake audio", string(audio))
+
+	var mappings []models.SpeakerMapping
+	require.NoError(suite.T(), database.DB.Where("transcription_job_id = ?", job.ID).Order("original_speaker ASC").Find(&mappings).Error)
+	require.Len(suite.T(), mappings, 2)
+	assert.Equal(suite.T(), "Jane Doe", mappings[0].CustomName)
+	assert.Equal(suite.T(), "Omar", mappings[1].CustomName)
+
+	var count int64
+	database.DB.Model(&models.Event{}).Where("subject_id = ? AND type = ?", job.ID, models.EventJobCompleted).Count(&count)
+	assert.Equal(suite.T(), int64(1), count)
+}
+
+func (suite *TranscriptImportTestSuite) TestImportSubtitlesRoundTrip() {
+	whisperx := `{"language": "en", "segments": [
+		{"start": 0, "end": 2, "text": "Shall we begin?", "speaker": "SPEAKER_00"},
+		{"start": 2, "end": 4, "text": "Yes, let's.", "speaker": "SPEAKER_01"}]}`
+	w := suite.importTranscript("call.json", whisperx, nil, map[string]string{"title": "Call"})
+	require.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+	var resp api.TranscriptImportResponse
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(suite.T(), "whisperx", resp.Format)
+	assert.Equal(suite.T(), "Call", *resp.Job.Title)
+	assert.Empty(suite.T(), resp.Job.AudioPath)
+	require.NotNil(suite.T(), resp.Job.DetectedLanguage)
+	assert.Equal(suite.T(), "en", *resp.Job.DetectedLanguage)
+
+	// Scriberr's own subtitles import back with their speakers
+	exported := suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/transcription/"+resp.Job.ID+"/export?format=vtt", nil)
+	require.Equal(suite.T(), http.StatusOK, exported.Code, exported.Body.String())
+
+	w = suite.importTranscript("call.vtt", exported.Body.String(), nil, nil)
+	require.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+	var reimported api.TranscriptImportResponse
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &reimported))
+	assert.Equal(suite.T(), "vtt", reimported.Format)
+	assert.Equal(suite.T(), 2, reimported.Speakers)
+	assert.Contains(suite.T(), *reimported.Job.Transcript, "Shall we begin?")
+}
+
+func (suite *TranscriptImportTestSuite) TestImportErrors() {
+	w := suite.importTranscript("notes.txt", "Some notes without any structure.", nil, nil)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "pass format as one of")
+
+	w = suite.importTranscript("call.json", `{"segments": []}`, nil, map[string]string{"format": "whisper"})
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	w = suite.importTranscript("call.srt", "1\n00:00:01,000 --> 00:00:02,000\nHi\n", nil, map[string]string{"content_type": "lecture"})
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
+func TestTranscriptImportTestSuite(t *testing.T) {
+	suite.Run(t, new(TranscriptImportTestSuite))
+}
@@ -0,0 +1,210 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/embeddings"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+	"scriberr/internal/rag"
+	"scriberr/internal/vectordb"
+	"scriberr/internal/versions"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TranscriptVersionTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	store  *vectordb.MemoryStore
+	rag    *rag.RAGService
+	router *gin.Engine
+}
+
+func (suite *TranscriptVersionTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "transcript_version_test.db")
+	suite.store = vectordb.NewMemoryStore()
+	suite.rag = rag.NewRAGService(suite.store, embeddings.NewFakeEmbeddingService(), llm.NewFakeService())
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, queue.NewTaskQueue(1, &MockJobProcessor{}), nil, nil, suite.rag)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *TranscriptVersionTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// job creates a completed, indexed transcription with a segment per text, written before
+// versions were kept
+func (suite *TranscriptVersionTestSuite) job(summary string, texts ...string) *models.TranscriptionJob {
+	t := suite.T()
+	segments := []map[string]interface{}{}
+	for i, text := range texts {
+		segments = append(segments, map[string]interface{}{"start": float64(i * 10), "end": float64(i*10 + 10), "text": text, "speaker": "SPEAKER_00"})
+	}
+	data, err := json.Marshal(map[string]interface{}{"text": strings.Join(texts, " "), "segments": segments})
+	require.NoError(t, err)
+
+	job := suite.helper.CreateTestTranscriptionJob(t, "Versions")
+	job.Status = models.StatusCompleted
+	job.Transcript = stringPtr(string(data))
+	if summary != "" {
+		job.Summary = &summary
+	}
+	require.NoError(t, suite.helper.DB.Save(job).Error)
+	require.NoError(t, suite.rag.StoreSummary(context.Background(), job.ID, summary, strings.Join(texts, " ")))
+	return job
+}
+
+// versions lists the versions of kind kept for a job
+func (suite *TranscriptVersionTestSuite) versions(jobID, kind string) []models.TranscriptVersion {
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/transcription/"+jobID+"/versions/"+kind, nil)
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Versions []models.TranscriptVersion `json:"versions"`
+	}
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Versions
+}
+
+// indexedText joins the documents the vector store holds for a transcription
+func (suite *TranscriptVersionTestSuite) indexedText(jobID string) string {
+	collection, err := rag.ResolveCollection(nil)
+	require.NoError(suite.T(), err)
+	docs, err := suite.store.GetDocuments(collection, vectordb.GetRequest{Where: map[string]interface{}{"transcription_id": jobID}})
+	require.NoError(suite.T(), err)
+	return strings.Join(docs.Documents, "\n")
+}
+
+func (suite *TranscriptVersionTestSuite) TestEditDiffAndRollback() {
+	t := suite.T()
+	job := suite.job("", "We ship on Friday.", "Bob owns the release.")
+	base := "/api/v1/transcription/" + job.ID + "/versions/transcript"
+
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPatch, "/api/v1/transcription/"+job.ID+"/segments/1", gin.H{"text": "Alice owns the release."})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	list := suite.versions(job.ID, models.VersionKindTranscript)
+	require.Len(t, list, 2)
+	assert.Equal(t, 2, list[0].Version)
+	assert.Equal(t, models.VersionSourceManual, list[0].Source)
+	assert.Equal(t, models.VersionSourceOriginal, list[1].Source, "the transcript from before the edit is kept")
+	assert.Empty(t, list[0].Content, "lists leave out the content")
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodGet, base+"/1", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var original models.TranscriptVersion
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &original))
+	assert.Contains(t, original.Content, "Bob owns the release.")
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodGet, base+"/diff", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var diff versions.Diff
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diff))
+	assert.Equal(t, 1, diff.From)
+	assert.Equal(t, 2, diff.To)
+	assert.Equal(t, []versions.DiffLine{
+		{Op: versions.DiffEqual, Text: "[00:00] SPEAKER_00: We ship on Friday."},
+		{Op: versions.DiffDelete, Text: "[00:10] SPEAKER_00: Bob owns the release."},
+		{Op: versions.DiffInsert, Text: "[00:10] SPEAKER_00: Alice owns the release."},
+	}, diff.Lines)
+	assert.Contains(t, suite.indexedText(job.ID), "Alice")
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, base+"/1/restore", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var restored api.RestoreVersionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &restored))
+	assert.Equal(t, 3, restored.Version.Version)
+	assert.Equal(t, models.VersionSourceRollback, restored.Version.Source)
+	require.NotNil(t, restored.Version.RestoredFrom)
+	assert.Equal(t, 1, *restored.Version.RestoredFrom)
+	assert.True(t, restored.Reindexed)
+
+	var current models.TranscriptionJob
+	require.NoError(t, suite.helper.DB.First(&current, "id = ?", job.ID).Error)
+	assert.Equal(t, original.Content, *current.Transcript)
+	indexed := suite.indexedText(job.ID)
+	assert.Contains(t, indexed, "Bob owns the release.", "RAG indexes the restored version")
+	assert.NotContains(t, indexed, "Alice")
+	assert.Len(t, suite.versions(job.ID, models.VersionKindTranscript), 3, "the edit stays available")
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, base+"/3/restore", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, base+"/9/restore", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Versions go with their transcription
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodDelete, "/api/v1/transcription/"+job.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var count int64
+	require.NoError(t, suite.helper.DB.Model(&models.TranscriptVersion{}).Where("transcription_id = ?", job.ID).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func (suite *TranscriptVersionTestSuite) TestSummaryVersions() {
+	t := suite.T()
+	job := suite.job("The team agreed to ship on Friday.", "We ship on Friday.")
+	base := "/api/v1/transcription/" + job.ID + "/versions/summary"
+
+	// A deleted summary can be restored
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodDelete, "/api/v1/transcription/"+job.ID+"/summary", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	list := suite.versions(job.ID, models.VersionKindSummary)
+	require.Len(t, list, 1)
+	assert.Equal(t, models.VersionSourceOriginal, list[0].Source)
+
+	model := "gpt-test"
+	_, err := versions.Save(suite.helper.DB, job.ID, models.VersionKindSummary, versions.Content{Text: "Shipping moved to Monday.", Model: &model}, models.VersionSourceSummary, nil)
+	require.NoError(t, err)
+	// Saving the same summary again is no new version
+	_, err = versions.Save(suite.helper.DB, job.ID, models.VersionKindSummary, versions.Content{Text: "Shipping moved to Monday.", Model: &model}, models.VersionSourceSummary, nil)
+	require.NoError(t, err)
+	list = suite.versions(job.ID, models.VersionKindSummary)
+	require.Len(t, list, 2)
+	require.NotNil(t, list[0].Model)
+	assert.Equal(t, model, *list[0].Model)
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodGet, base+"/diff?from=1&to=2", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var diff versions.Diff
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diff))
+	assert.Equal(t, 1, diff.Added)
+	assert.Equal(t, 1, diff.Removed)
+
+	w = suite.helper.Request(suite.T(), suite.router, http.MethodPost, base+"/1/restore", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var current models.TranscriptionJob
+	require.NoError(t, suite.helper.DB.First(&current, "id = ?", job.ID).Error)
+	require.NotNil(t, current.Summary)
+	assert.Equal(t, "The team agreed to ship on Friday.", *current.Summary)
+	assert.Contains(t, suite.indexedText(job.ID), "The team agreed to ship on Friday.")
+}
+
+func (suite *TranscriptVersionTestSuite) TestInvalidRequests() {
+	job := suite.job("", "We ship on Friday.")
+	base := "/api/v1/transcription/" + job.ID + "/versions/"
+	for path, code := range map[string]int{
+		base + "audio":                http.StatusBadRequest,
+		base + "transcript/first":     http.StatusBadRequest,
+		base + "transcript/diff?to=0": http.StatusBadRequest,
+		// Nothing was changed, so no versions are kept yet
+		base + "transcript/diff":                            http.StatusNotFound,
+		base + "transcript/1":                               http.StatusNotFound,
+		"/api/v1/transcription/missing/versions/transcript": http.StatusNotFound,
+	} {
+		w := suite.helper.Request(suite.T(), suite.router, http.MethodGet, path, nil)
+		assert.Equal(suite.T(), code, w.Code, path)
+	}
+}
+
+func TestTranscriptVersionTestSuite(t *testing.T) {
+	suite.Run(t, new(TranscriptVersionTestSuite))
+}
@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type URLImportTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	queue  *queue.TaskQueue
+	router *gin.Engine
+	remote *httptest.Server
+}
+
+func (suite *URLImportTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "url_import_test.db")
+	suite.helper.Config.URLImportAllowPrivate = true // The test server is on loopback
+	suite.helper.Config.URLImportMaxMB = 1
+	suite.queue = queue.NewTaskQueue(1, &MockJobProcessor{})
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, suite.queue, nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/calls/standup.mp3", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write([]byte("ID3 fake mp3"))
+	})
+	mux.HandleFunc("/download", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", `attachment; filename="client call.m4a"`)
+		w.Write([]byte("fake m4a"))
+	})
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html>Sign in</html>"))
+	})
+	mux.HandleFunc("/huge.wav", func(w http.ResponseWriter, r *http.Request) {
+		// No Content-Length, so the limit is enforced while downloading
+		w.Header().Set("Content-Type", "audio/wav")
+		w.(http.Flusher).Flush()
+		w.Write(bytes.Repeat([]byte("a"), 2<<20))
+	})
+	suite.remote = httptest.NewServer(mux)
+}
+
+func (suite *URLImportTestSuite) TearDownSuite() {
+	suite.remote.Close()
+	suite.helper.Cleanup()
+}
+
+// importURL submits a URL and waits for its download to finish
+func (suite *URLImportTestSuite) importURL(body gin.H) models.URLImport {
+	t := suite.T()
+	w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/transcription/from-url", body)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var urlImport models.URLImport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &urlImport))
+	assert.Equal(t, models.URLImportDownloading, urlImport.Status)
+
+	require.Eventually(t, func() bool {
+		w := suite.helper.Request(suite.T(), suite.router, http.MethodGet, "/api/v1/transcription/from-url/"+urlImport.ID, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &urlImport))
+		return urlImport.Status != models.URLImportDownloading
+	}, 5*time.Second, 20*time.Millisecond)
+	return urlImport
+}
+
+func (suite *URLImportTestSuite) TestImportIsQueued() {
+	t := suite.T()
+	suite.helper.CreateTestProfile(t, "Default", true)
+	urlImport := suite.importURL(gin.H{"url": suite.remote.URL + "/calls/standup.mp3", "agenda": "Sprint"})
+	require.Equal(t, models.URLImportCompleted, urlImport.Status, urlImport.Error)
+	require.NotNil(t, urlImport.TranscriptionID)
+	assert.Equal(t, urlImport.ID, *urlImport.TranscriptionID)
+	assert.EqualValues(t, 12, urlImport.DownloadedBytes)
+	require.NotNil(t, urlImport.TotalBytes)
+	assert.EqualValues(t, 12, *urlImport.TotalBytes)
+
+	var job models.TranscriptionJob
+	require.NoError(t, suite.helper.DB.First(&job, "id = ?", urlImport.ID).Error)
+	assert.Equal(t, models.StatusPending, job.Status, "queued with the default profile")
+	assert.Contains(t, order(suite.queue), job.ID)
+	require.NotNil(t, job.Title)
+	assert.Equal(t, "standup", *job.Title)
+	require.NotNil(t, job.Parameters.InitialPrompt)
+	assert.Contains(t, *job.Parameters.InitialPrompt, "Agenda: Sprint.")
+	data, err := os.ReadFile(job.AudioPath)
+	require.NoError(t, err)
+	assert.Equal(t, "ID3 fake mp3", string(data))
+}
+
+func (suite *URLImportTestSuite) TestFilenameFromContentDisposition() {
+	t := suite.T()
+	urlImport := suite.importURL(gin.H{"url": suite.remote.URL + "/download", "content_type": "voice_memo"})
+	require.Equal(t, models.URLImportCompleted, urlImport.Status, urlImport.Error)
+
+	var job models.TranscriptionJob
+	require.NoError(t, suite.helper.DB.First(&job, "id = ?", urlImport.ID).Error)
+	assert.Equal(t, ".m4a", filepath.Ext(job.AudioPath))
+	assert.Equal(t, "client call", *job.Title)
+	assert.Equal(t, models.ContentVoiceMemo, job.ContentType)
+}
+
+func (suite *URLImportTestSuite) TestRefusedDownloads() {
+	t := suite.T()
+	page := suite.importURL(gin.H{"url": suite.remote.URL + "/page"})
+	assert.Equal(t, models.URLImportFailed, page.Status)
+	require.NotNil(t, page.Error)
+	assert.Contains(t, *page.Error, "not an audio or video file")
+
+	huge := suite.importURL(gin.H{"url": suite.remote.URL + "/huge.wav"})
+	assert.Equal(t, models.URLImportFailed, huge.Status)
+	require.NotNil(t, huge.Error)
+	assert.Contains(t, *huge.Error, "limit")
+
+	missing := suite.importURL(gin.H{"url": suite.remote.URL + "/gone.mp3"})
+	assert.Equal(t, models.URLImportFailed, missing.Status)
+
+	var count int64
+	suite.helper.DB.Model(&models.TranscriptionJob{}).Where("id IN ?", []string{page.ID, huge.ID, missing.ID}).Count(&count)
+	assert.Zero(t, count)
+	entries, _ := os.ReadDir(suite.helper.Config.UploadDir)
+	for _, entry := range entries {
+		assert.False(t, strings.HasPrefix(entry.Name(), huge.ID), "the partial download is removed")
+	}
+
+	for _, url := range []string{"ftp://example.com/a.mp3", "file:///etc/passwd", "not a url"} {
+		w := suite.helper.Request(suite.T(), suite.router, http.MethodPost, "/api/v1/transcription/from-url", gin.H{"url": url})
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+	}
+}
+
+func (suite *URLImportTestSuite) TestPrivateAddressesAreRefused() {
+	t := suite.T()
+	suite.helper.Config.URLImportAllowPrivate = false
+	defer func() { suite.helper.Config.URLImportAllowPrivate = true }()
+
+	urlImport := suite.importURL(gin.H{"url": suite.remote.URL + "/calls/standup.mp3"})
+	assert.Equal(t, models.URLImportFailed, urlImport.Status)
+	require.NotNil(t, urlImport.Error)
+	assert.Contains(t, *urlImport.Error, "private networks")
+}
+
+func TestURLImportTestSuite(t *testing.T) {
+	suite.Run(t, new(URLImportTestSuite))
+}
@@ -0,0 +1,232 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserManagementTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	router *gin.Engine
+}
+
+func (suite *UserManagementTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "user_management_test.db")
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, queue.NewTaskQueue(1, &MockJobProcessor{}), nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *UserManagementTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// createUser creates an account through the admin API and returns it with a token for it
+func (suite *UserManagementTestSuite) createUser(username, role string) (models.User, string) {
+	w := suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodPost, "/api/v1/admin/users", gin.H{"username": username, "password": "password123", "role": role})
+	require.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+	var user models.User
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &user))
+	token, err := suite.helper.AuthService.GenerateToken(&user)
+	require.NoError(suite.T(), err)
+	return user, token
+}
+
+// ownedJob creates a test transcription owned by a user
+func (suite *UserManagementTestSuite) ownedJob(title string, userID uint) *models.TranscriptionJob {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), title)
+	require.NoError(suite.T(), suite.helper.DB.Model(job).Update("user_id", userID).Error)
+	return job
+}
+
+// listJobs returns the IDs of the transcriptions a user lists
+func (suite *UserManagementTestSuite) listJobs(token, query string) []string {
+	w := suite.helper.RequestAs(suite.T(), suite.router, token, http.MethodGet, "/api/v1/transcription/list?limit=1000&"+query, nil)
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Jobs []models.TranscriptionJob `json:"jobs"`
+	}
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &resp))
+	ids := []string{}
+	for _, job := range resp.Jobs {
+		ids = append(ids, job.ID)
+	}
+	return ids
+}
+
+func (suite *UserManagementTestSuite) TestIsolation() {
+	t := suite.T()
+	alice, aliceToken := suite.createUser("alice", models.RoleMember)
+	bob, bobToken := suite.createUser("bob", models.RoleMember)
+	aliceJob := suite.ownedJob("Alice's standup", alice.ID)
+	bobJob := suite.ownedJob("Bob's interview", bob.ID)
+	shared := suite.helper.CreateTestTranscriptionJob(t, "Dropzone upload")
+
+	assert.ElementsMatch(t, []string{aliceJob.ID}, suite.listJobs(aliceToken, ""))
+	assert.ElementsMatch(t, []string{bobJob.ID}, suite.listJobs(bobToken, "all=true"))
+	assert.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, aliceToken, http.MethodGet, "/api/v1/transcription/"+aliceJob.ID, nil).Code)
+	assert.Equal(t, http.StatusNotFound, suite.helper.RequestAs(suite.T(), suite.router, aliceToken, http.MethodGet, "/api/v1/transcription/"+bobJob.ID, nil).Code)
+	assert.Equal(t, http.StatusNotFound, suite.helper.RequestAs(suite.T(), suite.router, aliceToken, http.MethodDelete, "/api/v1/transcription/"+bobJob.ID, nil).Code)
+	assert.Equal(t, http.StatusNotFound, suite.helper.RequestAs(suite.T(), suite.router, aliceToken, http.MethodGet, "/api/v1/transcription/"+shared.ID, nil).Code)
+	assert.Equal(t, http.StatusNotFound, suite.helper.RequestAs(suite.T(), suite.router, aliceToken, http.MethodGet, "/api/v1/chat/transcriptions/"+bobJob.ID+"/sessions", nil).Code)
+
+	// Admins see their own and unowned transcriptions, and with all everyone's
+	adminJobs := suite.listJobs(suite.helper.TestToken, "")
+	assert.Contains(t, adminJobs, shared.ID)
+	assert.NotContains(t, adminJobs, aliceJob.ID)
+	assert.Subset(t, suite.listJobs(suite.helper.TestToken, "all=true"), []string{shared.ID, aliceJob.ID, bobJob.ID})
+	assert.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, suite.helper.TestToken, http.MethodGet, "/api/v1/transcription/"+bobJob.ID, nil).Code)
+
+	// Members can't administer
+	assert.Equal(t, http.StatusForbidden, suite.helper.RequestAs(suite.T(), suite.router, aliceToken, http.MethodGet, "/api/v1/admin/users", nil).Code)
+	assert.Equal(t, http.StatusForbidden, suite.helper.RequestAs(suite.T(), suite.router, aliceToken, http.MethodGet, "/api/v1/admin/queue", nil).Code)
+	for _, path := range []string{"/api/v1/rag/backfill", "/api/v1/rag/repair", "/api/v1/rag/audit?repair=true", "/api/v1/rag/topics/refresh", "/api/v1/rag/eval", "/api/v1/rag/eval/cases"} {
+		assert.Equal(t, http.StatusForbidden, suite.helper.RequestAs(suite.T(), suite.router, aliceToken, http.MethodPost, path, nil).Code, path)
+	}
+	assert.Equal(t, http.StatusForbidden, suite.helper.RequestAs(suite.T(), suite.router, aliceToken, http.MethodGet, "/api/v1/rag/eval/cases", nil).Code)
+}
+
+func (suite *UserManagementTestSuite) TestViewer() {
+	t := suite.T()
+	viewer, token := suite.createUser("victor", models.RoleViewer)
+	job := suite.ownedJob("Board meeting", viewer.ID)
+
+	assert.Equal(t, http.StatusOK, suite.helper.RequestAs(suite.T(), suite.router, token, http.MethodGet, "/api/v1/transcription/"+job.ID, nil).Code)
+	assert.Equal(t, http.StatusForbidden, suite.helper.RequestAs(suite.T(), suite.router, token, http.MethodPut, "/api/v1/transcription/"+job.ID+"/title", gin.H{"title": "Renamed"}).Code)
+	assert.Equal(t, http.StatusForbidden, suite.helper.RequestAs(suite.T(), suite.router, token, http.MethodDelete, "/api/v1/transcription/"+job.ID, nil).Code)
+	assert.Equal(t, http.StatusForbidden, suite.helper.RequestAs(suite.T(), suite.router, token, http.MethodPost, "/api/v1/projects", gin.H{"name": "Mine"}).Code)
+	// Asking questions is reading
+	assert.NotEqual(t, http.StatusForbidden, suite.helper.RequestAs(suite.T(), suite.router, token, http.MethodPost, "/api/v1/rag/search", gin.H{"query": "budget"}).Code)
+}
+
+func (suite *UserManagementTestSuite) TestUserAdministration() {
+	t := suite.T()
+	admin := suite.helper.TestToken
+
+	w := suite.helper.RequestAs(suite.T(), suite.router, admin, http.MethodPost, "/api/v1/admin/users", gin.H{"username": "testuser", "password": "password123"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = suite.helper.RequestAs(suite.T(), suite.router, admin, http.MethodPost, "/api/v1/admin/users", gin.H{"username": "owner", "password": "password123", "role": "owner"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// The last admin can't be demoted or deleted
+	self := fmt.Sprint(suite.helper.TestUser.ID)
+	assert.Equal(t, http.StatusBadRequest, suite.helper.RequestAs(suite.T(), suite.router, admin, http.MethodPut, "/api/v1/admin/users/"+self, gin.H{"role": "member"}).Code)
+	assert.Equal(t, http.StatusBadRequest, suite.helper.RequestAs(suite.T(), suite.router, admin, http.MethodDelete, "/api/v1/admin/users/"+self, nil).Code)
+
+	carol, carolToken := suite.createUser("carol", "")
+	assert.Equal(t, models.RoleMember, carol.Role)
+	w = suite.helper.RequestAs(suite.T(), suite.router, admin, http.MethodPut, fmt.Sprintf("/api/v1/admin/users/%d", carol.ID), gin.H{"role": "viewer", "username": "carol.b"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"role":"viewer"`)
+	assert.Contains(t, w.Body.String(), `"username":"carol.b"`)
+
+	// Deleting hands the user's data over and revokes their access
+	job := suite.ownedJob("Carol's notes", carol.ID)
+	template := models.SummaryTemplate{UserID: &carol.ID, Name: "Carol's template", Model: "gpt-4", Prompt: "Summarize"}
+	require.NoError(t, suite.helper.DB.Create(&template).Error)
+	dave, _ := suite.createUser("dave", models.RoleMember)
+	w = suite.helper.RequestAs(suite.T(), suite.router, admin, http.MethodDelete, fmt.Sprintf("/api/v1/admin/users/%d?transfer_to=%d", carol.ID, dave.ID), nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var moved models.TranscriptionJob
+	require.NoError(t, suite.helper.DB.First(&moved, "id = ?", job.ID).Error)
+	require.NotNil(t, moved.UserID)
+	assert.Equal(t, dave.ID, *moved.UserID)
+	require.NoError(t, suite.helper.DB.First(&template, "id = ?", template.ID).Error)
+	assert.Equal(t, dave.ID, *template.UserID)
+	assert.Equal(t, http.StatusUnauthorized, suite.helper.RequestAs(suite.T(), suite.router, carolToken, http.MethodGet, "/api/v1/transcription/list", nil).Code)
+
+	w = suite.helper.RequestAs(suite.T(), suite.router, admin, http.MethodGet, "/api/v1/admin/users", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Users []api.UserSummary `json:"users"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	counts := map[string]int64{}
+	for _, user := range resp.Users {
+		counts[user.Username] = user.TranscriptionCount
+	}
+	assert.NotContains(t, counts, "carol.b")
+	assert.Equal(t, int64(1), counts["dave"])
+}
+
+func (suite *UserManagementTestSuite) TestSignedDownloads() {
+	t := suite.T()
+	alice, aliceToken := suite.createUser("alicia", models.RoleMember)
+	_, bobToken := suite.createUser("bobby", models.RoleMember)
+	job := suite.ownedJob("Alicia's standup", alice.ID)
+	require.NoError(t, suite.helper.DB.Model(job).Updates(map[string]interface{}{"status": models.StatusCompleted, "transcript": `{"text":"Ship on Friday."}`}).Error)
+	path := "/api/v1/transcription/" + job.ID + "/export?format=markdown"
+
+	// A link can only be signed for a transcription the caller can open
+	assert.Equal(t, http.StatusNotFound, suite.helper.RequestAs(suite.T(), suite.router, bobToken, http.MethodGet, path, nil).Code)
+	assert.Equal(t, http.StatusNotFound, suite.helper.RequestAs(suite.T(), suite.router, bobToken, http.MethodPost, "/api/v1/downloads", gin.H{"path": path}).Code)
+
+	w := suite.helper.RequestAs(suite.T(), suite.router, aliceToken, http.MethodPost, "/api/v1/downloads", gin.H{"path": path})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var link api.DownloadLinkResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &link))
+	download := func(url string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+	w = download(link.URL)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "Ship on Friday.")
+
+	// A link stops working once its creator can no longer open the transcription
+	require.NoError(t, suite.helper.DB.Model(job).Update("user_id", suite.helper.TestUser.ID).Error)
+	assert.Equal(t, http.StatusNotFound, download(link.URL).Code)
+}
+
+func (suite *UserManagementTestSuite) TestEnrollOtherUsersSpeaker() {
+	t := suite.T()
+	alice, _ := suite.createUser("alina", models.RoleMember)
+	_, bobToken := suite.createUser("boris", models.RoleMember)
+	job := suite.ownedJob("Alina's interview", alice.ID)
+	speaker := models.JobSpeaker{TranscriptionJobID: job.ID, Speaker: "SPEAKER_00", Embedding: []float32{1, 0}, Status: models.JobSpeakerUnmatched}
+	require.NoError(t, suite.helper.DB.Create(&speaker).Error)
+
+	w := suite.helper.RequestAs(suite.T(), suite.router, bobToken, http.MethodPost, "/api/v1/speaker-profiles", gin.H{"name": "Alina", "transcription_id": job.ID, "speaker": "SPEAKER_00"})
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	require.NoError(t, suite.helper.DB.First(&speaker, speaker.ID).Error)
+	assert.Nil(t, speaker.SpeakerProfileID, "another user's speaker is left alone")
+	var profiles int64
+	require.NoError(t, suite.helper.DB.Model(&models.SpeakerProfile{}).Where("name = ?", "Alina").Count(&profiles).Error)
+	assert.Zero(t, profiles)
+}
+
+func (suite *UserManagementTestSuite) TestSummarizeOtherUsersTranscription() {
+	t := suite.T()
+	alice, _ := suite.createUser("alison", models.RoleMember)
+	_, bobToken := suite.createUser("bernd", models.RoleMember)
+	job := suite.ownedJob("Alison's review", alice.ID)
+	suite.helper.CreateTestLLMConfig(t, llm.ProviderFake)
+
+	w := suite.helper.RequestAs(suite.T(), suite.router, bobToken, http.MethodPost, "/api/v1/summarize/", gin.H{"model": "gpt-4", "content": "Summarize this", "transcription_id": job.ID})
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	var summaries, versions int64
+	require.NoError(t, suite.helper.DB.Model(&models.Summary{}).Where("transcription_id = ?", job.ID).Count(&summaries).Error)
+	require.NoError(t, suite.helper.DB.Model(&models.TranscriptVersion{}).Where("transcription_id = ?", job.ID).Count(&versions).Error)
+	assert.Zero(t, summaries)
+	assert.Zero(t, versions)
+}
+
+func TestUserManagementTestSuite(t *testing.T) {
+	suite.Run(t, new(UserManagementTestSuite))
+}
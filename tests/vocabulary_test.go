@@ -0,0 +1,245 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+	"scriberr/internal/transcription"
+	"scriberr/internal/transcription/adapters"
+	"scriberr/internal/transcription/interfaces"
+	"scriberr/internal/transcription/registry"
+	"scriberr/internal/workflow"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// VocabularyTestSuite passes workspace and transcription vocabularies to the fake transcriber
+// and corrects transcripts to them
+type VocabularyTestSuite struct {
+	suite.Suite
+	helper    *TestHelper
+	processor *transcription.UnifiedJobProcessor
+	router    *gin.Engine
+}
+
+func (suite *VocabularyTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "vocabulary_test.db")
+
+	registry.ClearRegistry()
+	registry.RegisterTranscriptionAdapter("parakeet", adapters.NewFakeTranscriptionAdapter("parakeet"))
+	suite.processor = transcription.NewUnifiedJobProcessor()
+	require.NoError(suite.T(), suite.processor.InitEmbeddedPythonEnv())
+
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, queue.NewTaskQueue(1, &MockJobProcessor{}), nil, nil, nil)
+	suite.router = api.SetupRoutes(handler, suite.helper.AuthService)
+}
+
+func (suite *VocabularyTestSuite) TearDownSuite() {
+	registry.ClearRegistry()
+	suite.helper.Cleanup()
+}
+
+// SetupTest empties the workspace vocabulary the tests share
+func (suite *VocabularyTestSuite) SetupTest() {
+	require.NoError(suite.T(), suite.helper.DB.Where("1 = 1").Delete(&models.VocabularyTerm{}).Error)
+}
+
+func (suite *VocabularyTestSuite) addTerm(path string, term map[string]interface{}) models.VocabularyTerm {
+	w := suite.helper.Request(suite.T(), suite.router, "POST", path, term)
+	require.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+	var created models.VocabularyTerm
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &created))
+	return created
+}
+
+// job creates a completed transcript with one segment per text
+func (suite *VocabularyTestSuite) job(texts ...string) *models.TranscriptionJob {
+	result := interfaces.TranscriptResult{}
+	for i, text := range texts {
+		result.Segments = append(result.Segments, interfaces.TranscriptSegment{Start: float64(i * 5), End: float64(i*5 + 5), Text: text})
+	}
+	data, err := json.Marshal(result)
+	require.NoError(suite.T(), err)
+	transcript := string(data)
+
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Standup")
+	job.Transcript = &transcript
+	job.Status = models.StatusCompleted
+	require.NoError(suite.T(), suite.helper.DB.Save(job).Error)
+	return job
+}
+
+func (suite *VocabularyTestSuite) segmentTexts(jobID string) []string {
+	var job models.TranscriptionJob
+	require.NoError(suite.T(), suite.helper.DB.First(&job, "id = ?", jobID).Error)
+	var result interfaces.TranscriptResult
+	require.NoError(suite.T(), json.Unmarshal([]byte(*job.Transcript), &result))
+	texts := make([]string, len(result.Segments))
+	for i, segment := range result.Segments {
+		texts[i] = segment.Text
+	}
+	return texts
+}
+
+func (suite *VocabularyTestSuite) TestVocabularyIsPassedToTheEngine() {
+	t := suite.T()
+	suite.addTerm("/api/v1/vocabulary", map[string]interface{}{"term": "kubectl", "sounds_like": []string{"cube cuddle"}})
+
+	path := filepath.Join(t.TempDir(), "audio.wav")
+	require.NoError(t, os.WriteFile(path, make([]byte, 10*32000), 0644))
+	job := suite.helper.CreateTestTranscriptionJob(t, "Standup")
+	job.AudioPath = path
+	job.Parameters.ModelFamily = "nvidia_parakeet"
+	require.NoError(t, suite.helper.DB.Save(job).Error)
+	suite.addTerm("/api/v1/transcription/"+job.ID+"/vocabulary", map[string]interface{}{"term": "Siobhan", "kind": "proper_noun"})
+
+	w := suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/transcription/"+job.ID+"/vocabulary", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var listed struct {
+		Terms []models.VocabularyTerm `json:"terms"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Len(t, listed.Terms, 2)
+	assert.Equal(t, "Siobhan", listed.Terms[0].Term, "the transcription's own terms come first")
+	assert.Nil(t, listed.Terms[1].TranscriptionID)
+
+	require.NoError(t, suite.processor.ProcessJob(context.Background(), job.ID))
+	require.NoError(t, suite.helper.DB.First(job, "id = ?", job.ID).Error)
+	var result interfaces.TranscriptResult
+	require.NoError(t, json.Unmarshal([]byte(*job.Transcript), &result))
+	assert.Equal(t, "Siobhan, kubectl", result.Metadata["hotwords"])
+	assert.Equal(t, "Vocabulary: Siobhan, kubectl.", result.Metadata["initial_prompt"])
+}
+
+func (suite *VocabularyTestSuite) TestCorrectionStepUsesHintsAndTheLLM() {
+	t := suite.T()
+	suite.addTerm("/api/v1/vocabulary", map[string]interface{}{"term": "kubectl", "sounds_like": []string{"cube cuddle"}})
+	suite.addTerm("/api/v1/vocabulary", map[string]interface{}{"term": "Siobhan", "kind": "proper_noun"})
+	job := suite.job("Run cube cuddle apply first.", "Shivon signed off on it.", "Nothing to fix here.")
+
+	service := &replyLLM{reply: `{"corrections": [{"line": 2, "text": "Siobhan signed off on it."}, {"line": 3, "text": "Something else entirely."}]}`}
+	step := &workflow.VocabularyStep{LLM: service, Model: "fake", Enabled: true}
+	rc := &workflow.RunContext{Job: job, Params: map[string]string{}}
+	output, err := step.Run(context.Background(), rc)
+	require.NoError(t, err)
+	assert.Equal(t, "2 segments corrected, 1 known mishearings replaced", output)
+	assert.Contains(t, service.prompt, "Siobhan (proper noun)")
+	assert.Contains(t, service.prompt, "1: Run kubectl apply first.")
+
+	// The LLM's rewrite of a line without any term is ignored
+	assert.Equal(t, []string{"Run kubectl apply first.", "Siobhan signed off on it.", "Nothing to fix here."}, suite.segmentTexts(job.ID))
+	assert.Contains(t, rc.Transcript, "Siobhan signed off")
+
+	var saved []models.TranscriptRevision
+	require.NoError(t, suite.helper.DB.Where("transcription_id = ?", job.ID).Order("segment_index").Find(&saved).Error)
+	require.Len(t, saved, 2)
+	assert.Equal(t, models.RevisionSourceVocabulary, saved[0].Source)
+	assert.Equal(t, "Run cube cuddle apply first.", saved[0].PreviousText)
+	assert.Nil(t, saved[1].EditedBy)
+
+	_, err = step.Run(context.Background(), &workflow.RunContext{Job: job, Params: map[string]string{"correct_vocabulary": "false"}})
+	assert.ErrorIs(t, err, workflow.ErrSkipped)
+	_, err = step.Run(context.Background(), &workflow.RunContext{Job: suite.helper.CreateTestTranscriptionJob(t, "Plain"), Params: map[string]string{}})
+	assert.ErrorIs(t, err, workflow.ErrSkipped)
+}
+
+func (suite *VocabularyTestSuite) TestCorrectingOnDemand() {
+	t := suite.T()
+	job := suite.job("The slaw is ninety nine percent.")
+
+	w := suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/transcription/"+job.ID+"/correct-vocabulary", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "no vocabulary yet")
+
+	term := suite.addTerm("/api/v1/transcription/"+job.ID+"/vocabulary", map[string]interface{}{"term": "SLA", "kind": "acronym", "sounds_like": []string{"slaw", "Slaw", " "}})
+	assert.Equal(t, []string{"slaw"}, term.SoundsLike)
+
+	var response struct {
+		Revisions []models.TranscriptRevision `json:"revisions"`
+		Replaced  int                         `json:"replaced"`
+	}
+	w = suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/transcription/"+job.ID+"/correct-vocabulary", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Revisions, 1)
+	assert.Equal(t, 1, response.Replaced)
+	assert.Equal(t, []string{"The SLA is ninety nine percent."}, suite.segmentTexts(job.ID))
+
+	w = suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/transcription/"+job.ID+"/correct-vocabulary", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Empty(t, response.Revisions)
+
+	w = suite.helper.Request(suite.T(), suite.router, "DELETE", "/api/v1/transcription/"+job.ID+"/vocabulary/"+term.ID, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = suite.helper.Request(suite.T(), suite.router, "DELETE", "/api/v1/transcription/"+job.ID+"/vocabulary/"+term.ID, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func (suite *VocabularyTestSuite) TestUploadsTakeAVocabulary() {
+	t := suite.T()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("audio", "standup.mp3")
+	require.NoError(t, err)
+	part.Write([]byte("dummy audio"))
+	writer.WriteField("vocabulary", "Siobhan, kubectl\nsiobhan")
+	require.NoError(t, writer.Close())
+
+	req := suite.helper.NewRequest(t, suite.helper.TestAPIKey, "POST", "/api/v1/transcription/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := serve(suite.router, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var job models.TranscriptionJob
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+
+	var terms []models.VocabularyTerm
+	require.NoError(t, suite.helper.DB.Where("transcription_id = ?", job.ID).Order("term").Find(&terms).Error)
+	require.Len(t, terms, 2)
+	assert.Equal(t, "Siobhan", terms[0].Term)
+	assert.Equal(t, "kubectl", terms[1].Term)
+}
+
+func (suite *VocabularyTestSuite) TestRequestsAreValidated() {
+	t := suite.T()
+	term := suite.addTerm("/api/v1/vocabulary", map[string]interface{}{"term": "  Kubernetes  "})
+	assert.Equal(t, "Kubernetes", term.Term)
+	assert.Equal(t, models.VocabularyKindTerm, term.Kind)
+
+	w := suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/vocabulary", map[string]interface{}{"term": "kubernetes"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/vocabulary", map[string]interface{}{"term": "k8s", "kind": "slang"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = suite.helper.Request(suite.T(), suite.router, "POST", "/api/v1/vocabulary", map[string]interface{}{"term": " "})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = suite.helper.Request(suite.T(), suite.router, "PUT", "/api/v1/vocabulary/"+term.ID, map[string]interface{}{"term": "Kubernetes", "sounds_like": []string{"cooper netties"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = suite.helper.Request(suite.T(), suite.router, "GET", "/api/v1/vocabulary", nil)
+	var listed struct {
+		Terms []models.VocabularyTerm `json:"terms"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Len(t, listed.Terms, 1)
+	assert.Equal(t, []string{"cooper netties"}, listed.Terms[0].SoundsLike)
+
+	w = suite.helper.Request(suite.T(), suite.router, "DELETE", "/api/v1/vocabulary/"+term.ID, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = suite.helper.Request(suite.T(), suite.router, "DELETE", "/api/v1/vocabulary/"+term.ID, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestVocabularyTestSuite(t *testing.T) {
+	suite.Run(t, new(VocabularyTestSuite))
+}
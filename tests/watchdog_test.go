@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/models"
+	"scriberr/internal/queue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type WatchdogTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *WatchdogTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "watchdog_test.db")
+}
+
+func (suite *WatchdogTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+// processing puts a job in processing on a worker that last reported it alive at heartbeat
+func (suite *WatchdogTestSuite) processing(job *models.TranscriptionJob, workerID string, heartbeat time.Time) {
+	require.NoError(suite.T(), suite.helper.DB.Model(job).Updates(map[string]interface{}{
+		"status":                models.StatusProcessing,
+		"worker_id":             workerID,
+		"processing_started_at": heartbeat.Add(-time.Minute),
+		"heartbeat_at":          heartbeat,
+	}).Error)
+}
+
+func (suite *WatchdogTestSuite) reload(jobID string) models.TranscriptionJob {
+	var job models.TranscriptionJob
+	require.NoError(suite.T(), suite.helper.DB.First(&job, "id = ?", jobID).Error)
+	return job
+}
+
+func (suite *WatchdogTestSuite) TestDeadWorkersJobsAreRequeuedThenFailed() {
+	t := suite.T()
+	tq := queue.NewTaskQueue(1, &MockJobProcessor{})
+	watchdog := queue.NewWatchdog(tq, queue.WatchdogConfig{
+		HeartbeatTimeout: 5 * time.Minute,
+		Action:           queue.StuckActionRequeue,
+		MaxRequeues:      1,
+	}, nil)
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, tq, nil, nil, nil)
+	handler.SetWatchdog(watchdog)
+	router := api.SetupRoutes(handler, suite.helper.AuthService)
+
+	dead := suite.helper.CreateTestTranscriptionJob(t, "Dead worker")
+	alive := suite.helper.CreateTestTranscriptionJob(t, "Live worker")
+	suite.processing(dead, "node-a:1/worker-0", time.Now().Add(-10*time.Minute))
+	suite.processing(alive, "node-b:1/worker-0", time.Now())
+
+	w := suite.helper.Request(t, router, "GET", "/api/v1/admin/queue/running", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var running struct {
+		Jobs   []api.RunningJob `json:"jobs"`
+		NodeID string           `json:"node_id"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &running))
+	assert.Len(t, running.Jobs, 2)
+	assert.Equal(t, tq.NodeID(), running.NodeID)
+
+	w = suite.helper.Request(t, router, "POST", "/api/v1/admin/queue/watchdog/check", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var checked struct {
+		Recovered []queue.StuckJob `json:"recovered"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &checked))
+	require.Len(t, checked.Recovered, 1)
+	assert.Equal(t, dead.ID, checked.Recovered[0].ID)
+	assert.Equal(t, queue.StuckReasonHeartbeat, checked.Recovered[0].Reason)
+	assert.Equal(t, queue.StuckActionRequeue, checked.Recovered[0].Action)
+
+	requeued := suite.reload(dead.ID)
+	assert.Equal(t, models.StatusPending, requeued.Status)
+	assert.Equal(t, 1, requeued.StuckRequeues)
+	assert.Contains(t, order(tq), dead.ID)
+	assert.Equal(t, models.StatusProcessing, suite.reload(alive.ID).Status, "a live worker's job is left alone")
+
+	var stuckEvents int64
+	suite.helper.DB.Model(&models.Event{}).Where("type = ? AND subject_id = ?", models.EventJobStuck, dead.ID).Count(&stuckEvents)
+	assert.EqualValues(t, 1, stuckEvents)
+
+	// Stuck again, it has used up its requeues
+	suite.processing(dead, "node-c:1/worker-0", time.Now().Add(-10*time.Minute))
+	recovered, err := watchdog.Check()
+	require.NoError(t, err)
+	require.Len(t, recovered, 1)
+	assert.Equal(t, queue.StuckActionFail, recovered[0].Action)
+	failed := suite.reload(dead.ID)
+	assert.Equal(t, models.StatusFailed, failed.Status)
+	require.NotNil(t, failed.ErrorMessage)
+	assert.Contains(t, *failed.ErrorMessage, "node-c:1/worker-0")
+}
+
+func (suite *WatchdogTestSuite) TestHungLocalJobIsFailed() {
+	t := suite.T()
+	processor := &MockJobProcessor{processDelay: time.Minute}
+	processor.On("ProcessJobWithProcess", mock.Anything, mock.Anything).Return(nil)
+	tq := queue.NewTaskQueue(1, processor)
+	tq.Start()
+	defer tq.Stop()
+
+	// Transcriptions usually take a second
+	previous := suite.helper.CreateTestTranscriptionJob(t, "Earlier")
+	duration := int64(1000)
+	require.NoError(t, suite.helper.DB.Create(&models.TranscriptionJobExecution{
+		TranscriptionJobID: previous.ID,
+		StartedAt:          time.Now().Add(-time.Hour),
+		ProcessingDuration: &duration,
+		Status:             models.StatusCompleted,
+	}).Error)
+	watchdog := queue.NewWatchdog(tq, queue.WatchdogConfig{
+		HeartbeatTimeout: 5 * time.Minute,
+		DurationMultiple: 3,
+		Action:           queue.StuckActionFail,
+	}, nil)
+	limit, err := watchdog.DurationLimit()
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Second, limit)
+
+	hung := suite.helper.CreateTestTranscriptionJob(t, "Hung")
+	require.NoError(t, tq.EnqueueJob(hung.ID))
+	require.Eventually(t, func() bool { return tq.IsJobRunning(hung.ID) }, 5*time.Second, 10*time.Millisecond)
+	recovered, err := watchdog.Check()
+	require.NoError(t, err)
+	assert.Empty(t, recovered, "it hasn't run long yet")
+
+	require.NoError(t, suite.helper.DB.Model(hung).Update("processing_started_at", time.Now().Add(-time.Hour)).Error)
+	recovered, err = watchdog.Check()
+	require.NoError(t, err)
+	require.Len(t, recovered, 1)
+	assert.Equal(t, queue.StuckReasonDuration, recovered[0].Reason)
+	assert.Contains(t, recovered[0].WorkerID, tq.NodeID())
+
+	require.Eventually(t, func() bool { return !tq.IsJobRunning(hung.ID) }, 5*time.Second, 10*time.Millisecond)
+	failed := suite.reload(hung.ID)
+	assert.Equal(t, models.StatusFailed, failed.Status)
+	require.NotNil(t, failed.ErrorMessage)
+	assert.Equal(t, "Transcription stopped making progress", *failed.ErrorMessage, "the stopped worker doesn't overwrite the watchdog's verdict")
+}
+
+func TestWatchdogTestSuite(t *testing.T) {
+	suite.Run(t, new(WatchdogTestSuite))
+}
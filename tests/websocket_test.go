@@ -0,0 +1,215 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"scriberr/internal/api"
+	"scriberr/internal/database"
+	"scriberr/internal/events"
+	"scriberr/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/websocket"
+)
+
+type WebSocketTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	server *httptest.Server
+}
+
+func (suite *WebSocketTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "websocket_test.db")
+	suite.helper.Config.FakeProviders = true
+	handler := api.NewHandler(suite.helper.Config, suite.helper.AuthService, nil, nil, nil, nil)
+	suite.server = httptest.NewServer(api.SetupRoutes(handler, suite.helper.AuthService))
+}
+
+func (suite *WebSocketTestSuite) TearDownSuite() {
+	suite.server.Close()
+	suite.helper.Cleanup()
+}
+
+// dial connects with the token in the query, as browsers do, and waits for the ready message
+func (suite *WebSocketTestSuite) dial(token string) (*websocket.Conn, error) {
+	location := "ws" + strings.TrimPrefix(suite.server.URL, "http") + "/api/v1/ws?token=" + url.QueryEscape(token)
+	conn, err := websocket.Dial(location, "", suite.server.URL)
+	if err != nil {
+		return nil, err
+	}
+	var ready api.SocketMessage
+	require.NoError(suite.T(), websocket.JSON.Receive(conn, &ready))
+	require.Equal(suite.T(), "ready", ready.Type)
+	return conn, nil
+}
+
+// receive reads messages until one of the given type arrives
+func (suite *WebSocketTestSuite) receive(conn *websocket.Conn, messageType string) api.SocketMessage {
+	require.NoError(suite.T(), conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for {
+		var message api.SocketMessage
+		require.NoError(suite.T(), websocket.JSON.Receive(conn, &message))
+		if message.Type == messageType {
+			return message
+		}
+	}
+}
+
+func (suite *WebSocketTestSuite) TestUpgradeRequiresToken() {
+	_, err := suite.dial("not-a-token")
+	require.Error(suite.T(), err)
+
+	conn, err := suite.dial(suite.helper.TestToken)
+	require.NoError(suite.T(), err)
+	conn.Close()
+
+	req, err := http.NewRequest("GET", suite.server.URL+"/api/v1/ws", nil)
+	require.NoError(suite.T(), err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(suite.T(), err)
+	resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (suite *WebSocketTestSuite) TestJobUpdatesAndQueuePositions() {
+	t := suite.T()
+	followed := suite.helper.CreateTestTranscriptionJob(t, "Followed")
+	other := suite.helper.CreateTestTranscriptionJob(t, "Other")
+	conn, err := suite.dial(suite.helper.TestAPIKey)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	queue := suite.receive(conn, "queue")
+	require.Contains(t, queue.Positions, followed.ID)
+	assert.Less(t, queue.Positions[followed.ID], queue.Positions[other.ID])
+
+	require.NoError(t, websocket.JSON.Send(conn, api.SocketRequest{Type: "subscribe", TranscriptionIDs: []string{other.ID}}))
+	require.NoError(t, websocket.JSON.Send(conn, api.SocketRequest{Type: "ping", ID: "p1"}))
+	assert.Equal(t, "p1", suite.receive(conn, "pong").ID)
+
+	events.RecordForJob(models.EventJobStarted, followed.ID, nil)
+	events.RecordForJob(models.EventJobProgress, other.ID, map[string]interface{}{"stage": "transcribing", "percent": 40})
+	message := suite.receive(conn, "event")
+	require.NotNil(t, message.Event)
+	assert.Equal(t, other.ID, message.Event.SubjectID)
+	assert.Equal(t, models.EventJobProgress, message.Event.Type)
+
+	// Once the job ahead leaves the queue, the followed job moves up
+	require.NoError(t, database.DB.Model(followed).Update("status", models.StatusProcessing).Error)
+	queue = suite.receive(conn, "queue")
+	assert.NotContains(t, queue.Positions, followed.ID)
+	assert.Contains(t, queue.Positions, other.ID)
+}
+
+func (suite *WebSocketTestSuite) TestChatRepliesStream() {
+	t := suite.T()
+	job := suite.helper.CreateTestTranscriptionJob(t, "Chat")
+	session := suite.helper.CreateTestChatSession(t, job.ID)
+	conn, err := suite.dial(suite.helper.TestAPIKey)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, websocket.JSON.Send(conn, api.SocketRequest{Type: "chat", ID: "c1", SessionID: session.ID, Content: "What was decided?"}))
+	var reply strings.Builder
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	tokens := 0
+	for {
+		var message api.SocketMessage
+		require.NoError(t, websocket.JSON.Receive(conn, &message))
+		if message.Type == "chat.done" {
+			assert.Equal(t, "c1", message.ID)
+			break
+		}
+		require.NotEqual(t, "error", message.Type, message.Error)
+		if message.Type == "chat.token" {
+			assert.Equal(t, "c1", message.ID)
+			reply.WriteString(message.Content)
+			tokens++
+		}
+	}
+	assert.Greater(t, tokens, 1, "the reply should arrive in parts")
+
+	var saved models.ChatMessage
+	require.NoError(t, database.DB.Where("chat_session_id = ? AND role = ?", session.ID, "assistant").First(&saved).Error)
+	assert.Equal(t, reply.String(), saved.Content)
+
+	require.NoError(t, websocket.JSON.Send(conn, api.SocketRequest{Type: "chat", ID: "c2", SessionID: "missing", Content: "Hello"}))
+	failed := suite.receive(conn, "error")
+	assert.Equal(t, "c2", failed.ID)
+	assert.Equal(t, "Chat session not found", failed.Error)
+}
+
+// chat sends a chat request and returns the message that ends it: chat.done or an error
+func (suite *WebSocketTestSuite) chat(conn *websocket.Conn, id, sessionID string) api.SocketMessage {
+	require.NoError(suite.T(), websocket.JSON.Send(conn, api.SocketRequest{Type: "chat", ID: id, SessionID: sessionID, Content: "Hello"}))
+	require.NoError(suite.T(), conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for {
+		var message api.SocketMessage
+		require.NoError(suite.T(), websocket.JSON.Receive(conn, &message))
+		if message.ID == id && (message.Type == "chat.done" || message.Type == "error") {
+			return message
+		}
+	}
+}
+
+// createUser creates an account with the given role and LLM request quota and returns a token for it
+func (suite *WebSocketTestSuite) createUser(username, role string, llmCalls int) (models.User, string) {
+	user := models.User{Username: username, Password: "unused", Role: role, QuotaLLMCalls: &llmCalls}
+	require.NoError(suite.T(), suite.helper.DB.Create(&user).Error)
+	token, err := suite.helper.AuthService.GenerateToken(&user)
+	require.NoError(suite.T(), err)
+	return user, token
+}
+
+func (suite *WebSocketTestSuite) TestChatIsHeldToTheChatRoutesRules() {
+	t := suite.T()
+	member, memberToken := suite.createUser("ws-member", models.RoleMember, 1)
+	other, _ := suite.createUser("ws-other", models.RoleMember, 0)
+	_, viewerToken := suite.createUser("ws-viewer", models.RoleViewer, 0)
+
+	mine := suite.helper.CreateTestTranscriptionJob(t, "Mine")
+	require.NoError(t, suite.helper.DB.Model(mine).Update("user_id", member.ID).Error)
+	theirs := suite.helper.CreateTestTranscriptionJob(t, "Theirs")
+	require.NoError(t, suite.helper.DB.Model(theirs).Update("user_id", other.ID).Error)
+	theirSession := suite.helper.CreateTestChatSession(t, theirs.ID)
+	mySession := models.ChatSession{ID: "ws-my-session", JobID: mine.ID, TranscriptionID: mine.ID, Title: "Mine", Model: "gpt-4", Provider: "openai", IsActive: true}
+	require.NoError(t, suite.helper.DB.Create(&mySession).Error)
+
+	conn, err := suite.dial(memberToken)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, "Chat session not found", suite.chat(conn, "c1", theirSession.ID).Error)
+	var count int64
+	require.NoError(t, database.DB.Model(&models.ChatMessage{}).Where("chat_session_id = ?", theirSession.ID).Count(&count).Error)
+	assert.Zero(t, count, "nothing is saved to another user's session")
+
+	// Each reply counts against the LLM request quota
+	done := suite.chat(conn, "c2", mySession.ID)
+	assert.Equal(t, "chat.done", done.Type, done.Error)
+	refused := suite.chat(conn, "c3", mySession.ID)
+	assert.Equal(t, "error", refused.Type)
+	assert.Contains(t, refused.Error, "quota")
+
+	viewer, err := suite.dial(viewerToken)
+	require.NoError(t, err)
+	defer viewer.Close()
+	assert.Equal(t, "Viewers have read-only access", suite.chat(viewer, "v1", theirSession.ID).Error)
+
+	key := models.APIKey{Key: "ws-read-key", Name: "Read", IsActive: true, UserID: &other.ID, Scopes: []string{models.APIKeyScopeRead}}
+	require.NoError(t, suite.helper.DB.Create(&key).Error)
+	reader, err := suite.dial(key.Key)
+	require.NoError(t, err)
+	defer reader.Close()
+	assert.Equal(t, "This API key's scopes don't allow chat", suite.chat(reader, "r1", theirSession.ID).Error)
+}
+
+func TestWebSocketTestSuite(t *testing.T) {
+	suite.Run(t, new(WebSocketTestSuite))
+}
@@ -0,0 +1,976 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"scriberr/internal/embeddings"
+	"scriberr/internal/integrations"
+	"scriberr/internal/llm"
+	"scriberr/internal/models"
+	"scriberr/internal/notify"
+	"scriberr/internal/rag"
+	"scriberr/internal/tagging"
+	"scriberr/internal/vectordb"
+	"scriberr/internal/workflow"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeStep records its calls and fails until told otherwise
+type fakeStep struct {
+	mu     sync.Mutex
+	calls  int
+	fail   bool
+	output string
+}
+
+func (s *fakeStep) Run(ctx context.Context, rc *workflow.RunContext) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.fail {
+		return "", errors.New("step failed")
+	}
+	return s.output, nil
+}
+
+func (s *fakeStep) setFail(fail bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fail = fail
+}
+
+func (s *fakeStep) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// echoStep returns the output of another step so tests can check outputs are passed along
+type echoStep struct {
+	from string
+}
+
+func (s *echoStep) Run(ctx context.Context, rc *workflow.RunContext) (string, error) {
+	return "got " + rc.Outputs[s.from], nil
+}
+
+type skipStep struct{}
+
+func (skipStep) Run(ctx context.Context, rc *workflow.RunContext) (string, error) {
+	return "", workflow.ErrSkipped
+}
+
+// replyLLM answers every completion with a fixed reply and keeps the text of the last request
+type replyLLM struct {
+	reply  string
+	prompt string
+}
+
+func (l *replyLLM) ChatCompletion(ctx context.Context, model string, messages []llm.ChatMessage, temperature float64) (*llm.ChatResponse, error) {
+	contents := make([]string, len(messages))
+	for i, message := range messages {
+		contents[i] = message.Content
+	}
+	l.prompt = strings.Join(contents, "\n\n")
+	resp := &llm.ChatResponse{Model: model}
+	resp.Choices = make([]struct {
+		Index   int `json:"index"`
+		Message struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	}, 1)
+	resp.Choices[0].Message.Content = l.reply
+	return resp, nil
+}
+
+type WorkflowTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *WorkflowTestSuite) SetupSuite() {
+	suite.helper = NewTestHelper(suite.T(), "workflow_test.db")
+}
+
+func (suite *WorkflowTestSuite) TearDownSuite() {
+	suite.helper.Cleanup()
+}
+
+func (suite *WorkflowTestSuite) completedJob() *models.TranscriptionJob {
+	job := suite.helper.CreateTestTranscriptionJob(suite.T(), "Workflow Job")
+	transcript := `{"segments":[{"start":0,"end":1,"text":"hello world"}]}`
+	job.Status = models.StatusCompleted
+	job.Transcript = &transcript
+	require.NoError(suite.T(), suite.helper.DB.Save(job).Error)
+	return job
+}
+
+// waitForRun polls until the run reaches a final status
+func (suite *WorkflowTestSuite) waitForRun(runID string) models.WorkflowRun {
+	var run models.WorkflowRun
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		require.NoError(suite.T(), suite.helper.DB.Preload("Steps").Where("id = ?", runID).First(&run).Error)
+		if run.Status == models.WorkflowCompleted || run.Status == models.WorkflowFailed {
+			return run
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	suite.T().Fatalf("workflow run %s did not finish, status %s", runID, run.Status)
+	return run
+}
+
+func stepStatuses(run models.WorkflowRun) map[string]models.WorkflowStatus {
+	statuses := make(map[string]models.WorkflowStatus, len(run.Steps))
+	for _, step := range run.Steps {
+		statuses[step.Name] = step.Status
+	}
+	return statuses
+}
+
+func (suite *WorkflowTestSuite) TestRegisterWorkflowRejectsUndeclaredDependency() {
+	engine := workflow.NewEngine("default")
+	engine.RegisterStep("a", &fakeStep{})
+	engine.RegisterStep("b", &fakeStep{})
+
+	err := engine.RegisterWorkflow(workflow.Definition{
+		Name: "bad",
+		Steps: []workflow.StepSpec{
+			{Name: "b", DependsOn: []string{"a"}},
+			{Name: "a"},
+		},
+	})
+	assert.Error(suite.T(), err)
+
+	err = engine.RegisterWorkflow(workflow.Definition{
+		Name:  "unknown",
+		Steps: []workflow.StepSpec{{Name: "missing"}},
+	})
+	assert.Error(suite.T(), err)
+}
+
+func (suite *WorkflowTestSuite) TestDependentsRunAfterDependencies() {
+	engine := workflow.NewEngine("chain")
+	engine.RegisterStep("first", &fakeStep{output: "first output"})
+	engine.RegisterStep("second", &echoStep{from: "first"})
+	engine.RegisterStep("optional", skipStep{})
+	require.NoError(suite.T(), engine.RegisterWorkflow(workflow.Definition{
+		Name: "chain",
+		Steps: []workflow.StepSpec{
+			{Name: "first"},
+			{Name: "optional"},
+			{Name: "second", DependsOn: []string{"first", "optional"}},
+		},
+	}))
+
+	job := suite.completedJob()
+	run, err := engine.Start(job.ID, "chain", nil)
+	require.NoError(suite.T(), err)
+
+	finished := suite.waitForRun(run.ID)
+	assert.Equal(suite.T(), models.WorkflowCompleted, finished.Status)
+
+	statuses := stepStatuses(finished)
+	assert.Equal(suite.T(), models.WorkflowCompleted, statuses["first"])
+	assert.Equal(suite.T(), models.WorkflowSkipped, statuses["optional"])
+	assert.Equal(suite.T(), models.WorkflowCompleted, statuses["second"])
+
+	for _, step := range finished.Steps {
+		if step.Name == "second" {
+			require.NotNil(suite.T(), step.Output)
+			assert.Equal(suite.T(), "got first output", *step.Output)
+		}
+	}
+}
+
+func (suite *WorkflowTestSuite) TestFailedStepBlocksDependentsAndCanBeRerun() {
+	flaky := &fakeStep{fail: true, output: "translated"}
+	upstream := &fakeStep{output: "summary"}
+	downstream := &fakeStep{}
+
+	engine := workflow.NewEngine("chain")
+	engine.RegisterStep("summarize", upstream)
+	engine.RegisterStep("translate", flaky)
+	engine.RegisterStep("notify", downstream)
+	require.NoError(suite.T(), engine.RegisterWorkflow(workflow.Definition{
+		Name: "chain",
+		Steps: []workflow.StepSpec{
+			{Name: "summarize"},
+			{Name: "translate"},
+			{Name: "notify", DependsOn: []string{"summarize", "translate"}},
+		},
+	}))
+
+	job := suite.completedJob()
+	run, err := engine.Start(job.ID, "chain", nil)
+	require.NoError(suite.T(), err)
+
+	finished := suite.waitForRun(run.ID)
+	assert.Equal(suite.T(), models.WorkflowFailed, finished.Status)
+	statuses := stepStatuses(finished)
+	assert.Equal(suite.T(), models.WorkflowCompleted, statuses["summarize"])
+	assert.Equal(suite.T(), models.WorkflowFailed, statuses["translate"])
+	assert.Equal(suite.T(), models.WorkflowBlocked, statuses["notify"])
+	assert.Equal(suite.T(), 0, downstream.callCount())
+
+	// Re-running the failed step also runs its dependents, but not completed upstream steps
+	flaky.setFail(false)
+	require.NoError(suite.T(), engine.RerunStep(run.ID, "translate"))
+
+	finished = suite.waitForRun(run.ID)
+	assert.Equal(suite.T(), models.WorkflowCompleted, finished.Status)
+	statuses = stepStatuses(finished)
+	assert.Equal(suite.T(), models.WorkflowCompleted, statuses["translate"])
+	assert.Equal(suite.T(), models.WorkflowCompleted, statuses["notify"])
+	assert.Equal(suite.T(), 1, upstream.callCount())
+	assert.Equal(suite.T(), 2, flaky.callCount())
+	assert.Equal(suite.T(), 1, downstream.callCount())
+
+	assert.Error(suite.T(), engine.RerunStep(run.ID, "missing"))
+}
+
+func (suite *WorkflowTestSuite) TestDisabledStepsAreSkipped() {
+	summarize := &fakeStep{output: "summary"}
+	tags := &fakeStep{output: "tags"}
+	notifyStep := &fakeStep{}
+
+	engine := workflow.NewEngine("chain")
+	engine.RegisterStep("summarize", summarize)
+	engine.RegisterStep("tags", tags)
+	engine.RegisterStep("notify", notifyStep)
+	require.NoError(suite.T(), engine.RegisterWorkflow(workflow.Definition{
+		Name: "chain",
+		Steps: []workflow.StepSpec{
+			{Name: "summarize"},
+			{Name: "tags"},
+			{Name: "notify", DependsOn: []string{"summarize", "tags"}},
+		},
+	}))
+	assert.Error(suite.T(), engine.DisableSteps([]string{"missing"}))
+	require.NoError(suite.T(), engine.DisableSteps([]string{"tags"}))
+	assert.Equal(suite.T(), []string{"tags"}, engine.DisabledSteps())
+
+	// A disabled step doesn't run, and its dependents still do
+	job := suite.completedJob()
+	run, err := engine.Start(job.ID, "chain", nil)
+	require.NoError(suite.T(), err)
+	finished := suite.waitForRun(run.ID)
+	assert.Equal(suite.T(), models.WorkflowCompleted, finished.Status)
+	statuses := stepStatuses(finished)
+	assert.Equal(suite.T(), models.WorkflowCompleted, statuses["summarize"])
+	assert.Equal(suite.T(), models.WorkflowSkipped, statuses["tags"])
+	assert.Equal(suite.T(), models.WorkflowCompleted, statuses["notify"])
+	assert.Equal(suite.T(), 0, tags.callCount())
+
+	// skip_steps turns steps off for one run
+	run, err = engine.Start(job.ID, "chain", map[string]string{"skip_steps": "summarize, notify"})
+	require.NoError(suite.T(), err)
+	finished = suite.waitForRun(run.ID)
+	statuses = stepStatuses(finished)
+	assert.Equal(suite.T(), models.WorkflowSkipped, statuses["summarize"])
+	assert.Equal(suite.T(), models.WorkflowSkipped, statuses["notify"])
+	assert.Equal(suite.T(), 1, summarize.callCount())
+	assert.Equal(suite.T(), 1, notifyStep.callCount())
+}
+
+func (suite *WorkflowTestSuite) TestFailedStepsRetryThenDeadLetter() {
+	flaky := &fakeStep{fail: true, output: "indexed"}
+	downstream := &fakeStep{}
+
+	engine := workflow.NewEngine("chain")
+	engine.RegisterStep("rag_index", flaky)
+	engine.RegisterStep("notify", downstream)
+	require.NoError(suite.T(), engine.RegisterWorkflow(workflow.Definition{
+		Name: "chain",
+		Steps: []workflow.StepSpec{
+			{Name: "rag_index"},
+			{Name: "notify", DependsOn: []string{"rag_index"}},
+		},
+	}))
+	engine.SetRetryPolicy(workflow.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+
+	step := func(run models.WorkflowRun, name string) models.WorkflowStep {
+		for _, step := range run.Steps {
+			if step.Name == name {
+				return step
+			}
+		}
+		suite.T().Fatalf("step %s not in run", name)
+		return models.WorkflowStep{}
+	}
+	retry := func(runID string) models.WorkflowRun {
+		time.Sleep(5 * time.Millisecond)
+		require.Equal(suite.T(), 1, engine.RetryDue())
+		return suite.waitForRun(runID)
+	}
+
+	// A failed step is scheduled for a retry and recovers on it
+	job := suite.completedJob()
+	run, err := engine.Start(job.ID, "chain", nil)
+	require.NoError(suite.T(), err)
+	finished := suite.waitForRun(run.ID)
+	failed := step(finished, "rag_index")
+	assert.Equal(suite.T(), models.WorkflowFailed, failed.Status)
+	require.NotNil(suite.T(), failed.NextRetryAt)
+	assert.Equal(suite.T(), models.WorkflowBlocked, step(finished, "notify").Status)
+
+	flaky.setFail(false)
+	finished = retry(run.ID)
+	assert.Equal(suite.T(), models.WorkflowCompleted, finished.Status)
+	assert.Equal(suite.T(), 2, step(finished, "rag_index").Attempts)
+	assert.Nil(suite.T(), step(finished, "rag_index").NextRetryAt)
+	assert.Equal(suite.T(), 1, downstream.callCount())
+
+	// A step failing on its last attempt is dead-lettered and no longer retried
+	flaky.setFail(true)
+	run, err = engine.Start(job.ID, "chain", nil)
+	require.NoError(suite.T(), err)
+	suite.waitForRun(run.ID)
+	retry(run.ID)
+	finished = retry(run.ID)
+	deadLettered := step(finished, "rag_index")
+	assert.Equal(suite.T(), models.WorkflowDeadLetter, deadLettered.Status)
+	assert.Equal(suite.T(), 3, deadLettered.Attempts)
+	assert.Nil(suite.T(), deadLettered.NextRetryAt)
+	assert.Equal(suite.T(), models.WorkflowFailed, finished.Status)
+	assert.Equal(suite.T(), 0, engine.RetryDue())
+
+	var event models.Event
+	require.NoError(suite.T(), suite.helper.DB.Where("type = ? AND subject_id = ?", models.EventWorkflowStepDeadLettered, job.ID).First(&event).Error)
+	assert.Equal(suite.T(), "rag_index", event.Data["step"])
+
+	// Requeueing starts over with a fresh set of attempts
+	flaky.setFail(false)
+	require.NoError(suite.T(), engine.Requeue(run.ID, "rag_index"))
+	finished = suite.waitForRun(run.ID)
+	assert.Equal(suite.T(), models.WorkflowCompleted, finished.Status)
+	assert.Equal(suite.T(), 1, step(finished, "rag_index").Attempts)
+	assert.Equal(suite.T(), models.WorkflowCompleted, step(finished, "notify").Status)
+}
+
+func (suite *WorkflowTestSuite) TestLoadDefinitions() {
+	engine := workflow.NewEngine("meetings")
+	engine.RegisterStep("summarize", &fakeStep{output: "summary"})
+	engine.RegisterStep("notify", &fakeStep{})
+	dir := suite.T().TempDir()
+
+	path := filepath.Join(dir, "workflows.json")
+	require.NoError(suite.T(), os.WriteFile(path, []byte(`[
+  {"name": "meetings", "steps": [{"name": "summarize"}, {"name": "notify", "depends_on": ["summarize"]}]}
+]`), 0644))
+	require.NoError(suite.T(), engine.LoadDefinitions(path))
+
+	job := suite.completedJob()
+	run, err := engine.Start(job.ID, "meetings", nil)
+	require.NoError(suite.T(), err)
+	finished := suite.waitForRun(run.ID)
+	assert.Equal(suite.T(), models.WorkflowCompleted, finished.Status)
+	assert.Len(suite.T(), finished.Steps, 2)
+
+	// Steps must be ordered after the steps they depend on
+	bad := filepath.Join(dir, "bad.json")
+	require.NoError(suite.T(), os.WriteFile(bad, []byte(`[
+  {"name": "backwards", "steps": [{"name": "notify", "depends_on": ["summarize"]}, {"name": "summarize"}]}
+]`), 0644))
+	assert.Error(suite.T(), engine.LoadDefinitions(bad))
+	assert.Error(suite.T(), engine.LoadDefinitions(filepath.Join(dir, "missing.json")))
+}
+
+func (suite *WorkflowTestSuite) TestStartRejectsUnknownWorkflow() {
+	engine := workflow.NewEngine("default")
+	job := suite.completedJob()
+
+	_, err := engine.Start(job.ID, "nope", nil)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *WorkflowTestSuite) TestStructuredSummary() {
+	t := suite.T()
+	job := suite.completedJob()
+	service := &replyLLM{reply: "```json\n" + `{"title":"Launch sync","tldr":"The launch moves to May.","key_points":["Beta feedback is positive"],` +
+		`"decisions":["Launch in May"],"action_items":[{"task":"Update the roadmap","owner":"Dana","due":"Friday"}]}` + "\n```"}
+	step := &workflow.SummarizeStep{LLM: service, Model: "test", Format: workflow.SummaryFormatText}
+
+	rc := &workflow.RunContext{Job: job, Transcript: "hello world", Params: map[string]string{"summary_format": workflow.SummaryFormatStructured}}
+	output, err := step.Run(context.Background(), rc)
+	require.NoError(t, err)
+	assert.Contains(t, output, "**TL;DR:** The launch moves to May.")
+	assert.Contains(t, output, "- Update the roadmap (Dana, due Friday)")
+
+	var saved models.TranscriptionJob
+	require.NoError(t, suite.helper.DB.First(&saved, "id = ?", job.ID).Error)
+	require.NotNil(t, saved.StructuredSummary)
+	assert.Equal(t, "Launch sync", saved.StructuredSummary.Title)
+	assert.Equal(t, "Dana", saved.StructuredSummary.ActionItems[0].Owner)
+	require.NotNil(t, saved.Summary)
+	assert.Equal(t, output, *saved.Summary)
+
+	// A free-text summary replaces the structured one
+	service.reply = "Plain summary"
+	_, err = step.Run(context.Background(), &workflow.RunContext{Job: job, Transcript: "hello world"})
+	require.NoError(t, err)
+	require.NoError(t, suite.helper.DB.First(&saved, "id = ?", job.ID).Error)
+	assert.Nil(t, saved.StructuredSummary)
+	assert.Equal(t, "Plain summary", *saved.Summary)
+
+	_, err = step.Run(context.Background(), &workflow.RunContext{Job: job, Params: map[string]string{"summary_format": "poem"}})
+	assert.Error(t, err)
+}
+
+func (suite *WorkflowTestSuite) TestSummaryTemplate() {
+	t := suite.T()
+	user := suite.helper.TestUser
+	minutes := &models.SummaryTemplate{UserID: &user.ID, Name: "Meeting minutes", Model: "test", Prompt: "Write meeting minutes with attendees and decisions."}
+	lecture := &models.SummaryTemplate{UserID: &user.ID, Name: "Lecture notes", Model: "test", Prompt: "Write lecture notes with key concepts."}
+	require.NoError(t, suite.helper.DB.Create(minutes).Error)
+	require.NoError(t, suite.helper.DB.Create(lecture).Error)
+	require.NoError(t, suite.helper.DB.Model(user).Update("default_summary_template_id", minutes.ID).Error)
+	defer suite.helper.DB.Model(user).Update("default_summary_template_id", nil)
+
+	job := suite.completedJob()
+	job.UserID = &user.ID
+	require.NoError(t, suite.helper.DB.Save(job).Error)
+
+	service := &replyLLM{reply: "Minutes"}
+	step := &workflow.SummarizeStep{LLM: service, Model: "test"}
+
+	// The owner's default template replaces the built-in prompt
+	_, err := step.Run(context.Background(), &workflow.RunContext{Job: job, Transcript: "hello world"})
+	require.NoError(t, err)
+	assert.Contains(t, service.prompt, "hello world")
+	assert.Contains(t, service.prompt, minutes.Prompt)
+
+	// A template chosen for the job wins over the default
+	job.SummaryTemplateID = &lecture.ID
+	_, err = step.Run(context.Background(), &workflow.RunContext{Job: job, Transcript: "hello world"})
+	require.NoError(t, err)
+	assert.Contains(t, service.prompt, lecture.Prompt)
+
+	// A deleted template falls back to the built-in prompt
+	require.NoError(t, suite.helper.DB.Delete(lecture).Error)
+	_, err = step.Run(context.Background(), &workflow.RunContext{Job: job, Transcript: "hello world"})
+	require.NoError(t, err)
+	assert.NotContains(t, service.prompt, lecture.Prompt)
+	assert.Contains(t, service.prompt, "concise summary")
+}
+
+func (suite *WorkflowTestSuite) TestPerSpeakerSummary() {
+	t := suite.T()
+	template := &models.SummaryTemplate{Name: "By participant", Model: "test", Prompt: "Focus on the budget.", Layout: models.SummaryLayoutPerSpeaker}
+	require.NoError(t, suite.helper.DB.Create(template).Error)
+
+	job := suite.helper.CreateTestTranscriptionJob(t, "Budget review")
+	transcript := `{"segments":[{"start":0,"end":5,"text":"The budget is tight.","speaker":"SPEAKER_00"},` +
+		`{"start":5,"end":9,"text":"I'll send the forecast tomorrow.","speaker":"SPEAKER_01"}]}`
+	job.Status = models.StatusCompleted
+	job.Transcript = &transcript
+	job.SummaryTemplateID = &template.ID
+	require.NoError(t, suite.helper.DB.Save(job).Error)
+	require.NoError(t, suite.helper.DB.Create(&models.SpeakerMapping{TranscriptionJobID: job.ID, OriginalSpeaker: "SPEAKER_01", CustomName: "Dana"}).Error)
+
+	service := &replyLLM{reply: `{"title":"Budget review","tldr":"The budget is tight.","speakers":[` +
+		`{"speaker":"SPEAKER_00","key_points":["The budget is tight"],"commitments":[]},` +
+		`{"speaker":"Dana","key_points":[],"commitments":["Send the forecast tomorrow"]},` +
+		`{"speaker":"Nobody","key_points":[" "],"commitments":[]}]}`}
+	step := &workflow.SummarizeStep{LLM: service, Model: "test", Format: workflow.SummaryFormatText}
+
+	output, err := step.Run(context.Background(), &workflow.RunContext{Job: job, Transcript: "The budget is tight. I'll send the forecast tomorrow."})
+	require.NoError(t, err)
+	assert.Contains(t, service.prompt, "[5] Dana: I'll send the forecast tomorrow.")
+	assert.Contains(t, service.prompt, template.Prompt)
+	assert.Contains(t, output, "### Dana\n\n**Commitments:**\n\n- Send the forecast tomorrow")
+
+	var saved models.TranscriptionJob
+	require.NoError(t, suite.helper.DB.First(&saved, "id = ?", job.ID).Error)
+	require.NotNil(t, saved.StructuredSummary)
+	require.Len(t, saved.StructuredSummary.Speakers, 2)
+	assert.Equal(t, []models.SpeakerSection{{Speaker: "Dana", KeyPoints: []string{}, Commitments: []string{"Send the forecast tomorrow"}}},
+		saved.StructuredSummary.Commitments("dana"))
+	assert.Empty(t, saved.StructuredSummary.Commitments("SPEAKER_00"))
+}
+
+func (suite *WorkflowTestSuite) TestGenerateSummaryKeepsHistory() {
+	t := suite.T()
+	user := suite.helper.TestUser
+	template := &models.SummaryTemplate{UserID: &user.ID, Name: "Standup", Model: "test", Prompt: "List what everyone is working on."}
+	require.NoError(t, suite.helper.DB.Create(template).Error)
+	job := suite.completedJob()
+
+	service := &replyLLM{reply: "First summary"}
+	_, err := workflow.GenerateSummary(context.Background(), service, job, "hello world", workflow.SummaryOptions{Model: "small", Temperature: 0.2, Source: "api"})
+	require.NoError(t, err)
+
+	service.reply = "Second summary"
+	summary, err := workflow.GenerateSummary(context.Background(), service, job, "hello world", workflow.SummaryOptions{Model: "large", Temperature: 0.2, Template: template, Source: "api"})
+	require.NoError(t, err)
+	assert.Equal(t, "Second summary", summary)
+	assert.Contains(t, service.prompt, template.Prompt)
+
+	var saved models.TranscriptionJob
+	require.NoError(t, suite.helper.DB.First(&saved, "id = ?", job.ID).Error)
+	require.NotNil(t, saved.Summary)
+	assert.Equal(t, "Second summary", *saved.Summary)
+
+	// Each regeneration is kept, newest last
+	var history []models.Summary
+	require.NoError(t, suite.helper.DB.Where("transcription_id = ?", job.ID).Order("created_at ASC").Find(&history).Error)
+	require.Len(t, history, 2)
+	assert.Equal(t, "small", history[0].Model)
+	assert.Nil(t, history[0].TemplateID)
+	assert.Equal(t, "large", history[1].Model)
+	require.NotNil(t, history[1].TemplateID)
+	assert.Equal(t, template.ID, *history[1].TemplateID)
+}
+
+func (suite *WorkflowTestSuite) TestActionItems() {
+	t := suite.T()
+	job := suite.helper.CreateTestTranscriptionJob(t, "Planning")
+	transcript := `{"segments":[{"start":0,"end":4,"text":"Let's plan the launch.","speaker":"SPEAKER_00"},` +
+		`{"start":4,"end":9,"text":"I'll update the roadmap by Friday.","speaker":"SPEAKER_01"}]}`
+	job.Status = models.StatusCompleted
+	job.Transcript = &transcript
+	require.NoError(t, suite.helper.DB.Save(job).Error)
+	require.NoError(t, suite.helper.DB.Create(&models.SpeakerMapping{TranscriptionJobID: job.ID, OriginalSpeaker: "SPEAKER_01", CustomName: "Dana"}).Error)
+
+	service := &replyLLM{reply: `{"action_items":[{"task":"Update the roadmap","owner":"Dana","due":"Friday","timestamp":4},` +
+		`{"task":"Book a room","owner":"","due":"","timestamp":0},{"task":"  ","owner":"","due":"","timestamp":0}]}`}
+	step := &workflow.ActionItemsStep{LLM: service, Model: "test"}
+
+	// Disabled unless configured or asked for
+	_, err := step.Run(context.Background(), &workflow.RunContext{Job: job})
+	assert.ErrorIs(t, err, workflow.ErrSkipped)
+
+	output, err := step.Run(context.Background(), &workflow.RunContext{Job: job, Params: map[string]string{"action_items": "true"}})
+	require.NoError(t, err)
+	assert.Contains(t, output, "- Update the roadmap (Dana)")
+	assert.Contains(t, service.prompt, "[4] Dana: I'll update the roadmap by Friday.")
+
+	var items []models.ActionItem
+	require.NoError(t, suite.helper.DB.Where("transcription_id = ?", job.ID).Order("source_time DESC").Find(&items).Error)
+	require.Len(t, items, 2)
+	assert.Equal(t, "Update the roadmap", items[0].Task)
+	assert.Equal(t, "Friday", items[0].Due)
+	require.NotNil(t, items[0].SourceTime)
+	assert.Equal(t, 4.0, *items[0].SourceTime)
+	assert.Equal(t, job.UserID, items[0].UserID)
+
+	// Extracting again replaces the items but keeps completed ones completed
+	require.NoError(t, suite.helper.DB.Model(&items[0]).Update("completed", true).Error)
+	step.Enabled = true
+	_, err = step.Run(context.Background(), &workflow.RunContext{Job: job})
+	require.NoError(t, err)
+	var again []models.ActionItem
+	require.NoError(t, suite.helper.DB.Where("transcription_id = ?", job.ID).Order("source_time DESC").Find(&again).Error)
+	require.Len(t, again, 2)
+	assert.NotEqual(t, items[0].ID, again[0].ID)
+	assert.True(t, again[0].Completed)
+	assert.False(t, again[1].Completed)
+
+	_, err = step.Run(context.Background(), &workflow.RunContext{Job: job, Params: map[string]string{"action_items": "false"}})
+	assert.ErrorIs(t, err, workflow.ErrSkipped)
+}
+
+func (suite *WorkflowTestSuite) TestGenerateTags() {
+	t := suite.T()
+	job := suite.helper.CreateTestTranscriptionJob(t, "Roadmap review")
+	job.Status = models.StatusCompleted
+	require.NoError(t, suite.helper.DB.Save(job).Error)
+	_, err := tagging.SetJobTags(job, []string{"Customer X"})
+	require.NoError(t, err)
+
+	service := &replyLLM{reply: `{"tags":["Roadmap","#planning"," roadmap ","Q3 launch"]}`}
+	step := &workflow.GenerateTagsStep{LLM: service, Model: "test", Enabled: true}
+	rc := &workflow.RunContext{Job: job, Transcript: "We reviewed the roadmap for the Q3 launch."}
+
+	output, err := step.Run(context.Background(), rc)
+	require.NoError(t, err)
+	assert.Equal(t, "Customer X, Roadmap, planning, Q3 launch", output)
+	assert.Contains(t, service.prompt, "Reuse these existing tags where they fit: Customer X.")
+
+	var stored models.TranscriptionJob
+	require.NoError(t, suite.helper.DB.First(&stored, "id = ?", job.ID).Error)
+	assert.Equal(t, []string{"Customer X", "Roadmap", "planning", "Q3 launch"}, stored.Tags)
+
+	// Generating again replaces the generated tags but keeps the ones added by hand
+	service.reply = `{"tags":["roadmap","budget","hiring"]}`
+	_, err = step.Run(context.Background(), rc)
+	require.NoError(t, err)
+	require.NoError(t, suite.helper.DB.First(&stored, "id = ?", job.ID).Error)
+	assert.ElementsMatch(t, []string{"Customer X", "Roadmap", "budget", "hiring"}, stored.Tags)
+
+	var sources []string
+	require.NoError(t, suite.helper.DB.Model(&models.TranscriptionTag{}).Where("transcription_id = ?", job.ID).
+		Order("source").Pluck("source", &sources).Error)
+	assert.Equal(t, []string{models.TagSourceAuto, models.TagSourceAuto, models.TagSourceAuto, models.TagSourceManual}, sources)
+
+	_, err = step.Run(context.Background(), &workflow.RunContext{Job: job, Params: map[string]string{"auto_tags": "false"}})
+	assert.ErrorIs(t, err, workflow.ErrSkipped)
+}
+
+func (suite *WorkflowTestSuite) TestExtractEntities() {
+	t := suite.T()
+	job := suite.helper.CreateTestTranscriptionJob(t, "Vendor call")
+	transcript := `{"segments":[{"start":0,"end":6,"text":"Acme Corp wants the Widget Pro in Berlin.","speaker":"SPEAKER_00"},` +
+		`{"start":12,"end":18,"text":"I'll ask acme corp about pricing.","speaker":"SPEAKER_01"}]}`
+	job.Status = models.StatusCompleted
+	job.Transcript = &transcript
+	require.NoError(t, suite.helper.DB.Save(job).Error)
+
+	service := &replyLLM{reply: `{"entities":[{"name":"Acme  Corp","type":"organization","timestamps":[12,0]},` +
+		`{"name":"Widget Pro","type":"Product","timestamps":[0]},{"name":"acme corp","type":"organization","timestamps":[12]},` +
+		`{"name":"Berlin","type":"location","timestamps":[]},{"name":"Tuesday","type":"date","timestamps":[0]}]}`}
+	step := &workflow.EntitiesStep{LLM: service, Model: "test"}
+
+	_, err := step.Run(context.Background(), &workflow.RunContext{Job: job})
+	assert.ErrorIs(t, err, workflow.ErrSkipped)
+
+	output, err := step.Run(context.Background(), &workflow.RunContext{Job: job, Params: map[string]string{"entities": "true"}})
+	require.NoError(t, err)
+	assert.Equal(t, "- Acme Corp (organization)\n- Widget Pro (product)\n- Berlin (location)", output)
+	assert.Contains(t, service.prompt, "[12] SPEAKER_01: I'll ask acme corp about pricing.")
+
+	var mentions []models.EntityMention
+	require.NoError(t, suite.helper.DB.Where("transcription_id = ? AND type = ?", job.ID, models.EntityOrganization).
+		Order("source_time").Find(&mentions).Error)
+	require.Len(t, mentions, 2)
+	assert.Equal(t, "Acme Corp", mentions[0].Name)
+	assert.Equal(t, 0.0, *mentions[0].SourceTime)
+	assert.Equal(t, 12.0, *mentions[1].SourceTime)
+
+	var berlin models.EntityMention
+	require.NoError(t, suite.helper.DB.Where("transcription_id = ? AND name = ?", job.ID, "Berlin").First(&berlin).Error)
+	assert.Nil(t, berlin.SourceTime)
+
+	// Extracting again replaces the mentions
+	service.reply = `{"entities":[{"name":"Acme Corp","type":"organization","timestamps":[0]}]}`
+	_, err = step.Run(context.Background(), &workflow.RunContext{Job: job, Params: map[string]string{"entities": "true"}})
+	require.NoError(t, err)
+	var count int64
+	require.NoError(t, suite.helper.DB.Model(&models.EntityMention{}).Where("transcription_id = ?", job.ID).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func (suite *WorkflowTestSuite) TestSpeakerAnalytics() {
+	t := suite.T()
+	job := suite.helper.CreateTestTranscriptionJob(t, "Sales call")
+	transcript := `{"segments":[{"start":0,"end":10,"text":"Thanks for joining, great to see you.","speaker":"SPEAKER_00"},` +
+		`{"start":9,"end":15,"text":"Honestly the price is a problem.","speaker":"SPEAKER_01"},` +
+		`{"start":15,"end":20,"text":"Let's look at options.","speaker":"SPEAKER_00"}]}`
+	job.Status = models.StatusCompleted
+	job.Transcript = &transcript
+	require.NoError(t, suite.helper.DB.Save(job).Error)
+	require.NoError(t, suite.helper.DB.Create(&models.SpeakerMapping{TranscriptionJobID: job.ID, OriginalSpeaker: "SPEAKER_01", CustomName: "Dana"}).Error)
+
+	service := &replyLLM{reply: `{"scores":[{"line":1,"score":0.8},{"line":2,"score":-0.6},{"line":9,"score":1}]}`}
+	step := &workflow.AnalyticsStep{LLM: service, Model: "test"}
+
+	_, err := step.Run(context.Background(), &workflow.RunContext{Job: job})
+	assert.ErrorIs(t, err, workflow.ErrSkipped)
+
+	output, err := step.Run(context.Background(), &workflow.RunContext{Job: job, Params: map[string]string{"analytics": "true"}})
+	require.NoError(t, err)
+	assert.Contains(t, output, "- Dana: 29% of talk time, 1 interruptions")
+	assert.Contains(t, service.prompt, "[2] Dana: Honestly the price is a problem.")
+
+	var saved models.ConversationAnalytics
+	require.NoError(t, suite.helper.DB.First(&saved, "transcription_id = ?", job.ID).Error)
+	assert.Equal(t, 20.0, saved.Duration)
+	require.Len(t, saved.Speakers, 2)
+	assert.Equal(t, 15.0, saved.Speakers[0].TalkTime)
+	assert.Equal(t, 1, saved.Speakers[0].Interrupted)
+	require.NotNil(t, saved.Speakers[1].Sentiment)
+	assert.Equal(t, -0.6, *saved.Speakers[1].Sentiment)
+	require.Len(t, saved.Segments, 3)
+	assert.Equal(t, models.SentimentPositive, saved.Segments[0].Label)
+	assert.Equal(t, models.SentimentNegative, saved.Segments[1].Label)
+	assert.Equal(t, models.SentimentNeutral, saved.Segments[2].Label, "lines left out of the reply are neutral")
+
+	// Running again replaces the stored analytics
+	service.reply = `{"scores":[]}`
+	_, err = step.Run(context.Background(), &workflow.RunContext{Job: job, Params: map[string]string{"analytics": "true"}})
+	require.NoError(t, err)
+	require.NoError(t, suite.helper.DB.First(&saved, "transcription_id = ?", job.ID).Error)
+	assert.Equal(t, 0.0, *saved.Speakers[1].Sentiment)
+}
+
+func (suite *WorkflowTestSuite) TestGenerateChapters() {
+	t := suite.T()
+	job := suite.helper.CreateTestTranscriptionJob(t, "Podcast")
+	// Eight minutes on gardening, then eight on football, one segment a minute
+	var segments []string
+	for i := 0; i < 16; i++ {
+		text := "tomatoes need compost sunlight and water in the garden beds"
+		if i >= 8 {
+			text = "the striker scored a late goal after the referee added stoppage time"
+		}
+		segments = append(segments, fmt.Sprintf(`{"start":%d,"end":%d,"text":"%s"}`, i*60, i*60+50, text))
+	}
+	transcript := `{"segments":[` + strings.Join(segments, ",") + `]}`
+	job.Status = models.StatusCompleted
+	job.Transcript = &transcript
+	require.NoError(t, suite.helper.DB.Save(job).Error)
+
+	service := &replyLLM{reply: `{"chapters":[{"number":2,"title":"Match  report","summary":"A late winner."},{"number":7,"title":"Ignored","summary":""}]}`}
+	ragService := rag.NewRAGService(vectordb.NewMemoryStore(), embeddings.NewFakeEmbeddingService(), service)
+	step := &workflow.ChaptersStep{LLM: service, Model: "test", RAG: ragService}
+
+	_, err := step.Run(context.Background(), &workflow.RunContext{Job: job})
+	assert.ErrorIs(t, err, workflow.ErrSkipped)
+	step.Enabled = true
+	output, err := step.Run(context.Background(), &workflow.RunContext{Job: job})
+	require.NoError(t, err)
+	assert.Equal(t, "00:00:00 Chapter 1\n00:08:00 Match report", output)
+	assert.Contains(t, service.prompt, "[2] 00:08:00–00:15:50: the striker scored")
+
+	var saved []models.Chapter
+	require.NoError(t, suite.helper.DB.Where("transcription_id = ?", job.ID).Order("position").Find(&saved).Error)
+	require.Len(t, saved, 2)
+	assert.Equal(t, 0.0, saved[0].Start)
+	assert.Equal(t, 480.0, saved[0].End)
+	assert.Equal(t, "A late winner.", saved[1].Summary)
+	assert.Equal(t, 950.0, saved[1].End)
+
+	// Running again replaces the stored chapters
+	_, err = step.Run(context.Background(), &workflow.RunContext{Job: job, Params: map[string]string{"chapters": "true"}})
+	require.NoError(t, err)
+	var count int64
+	require.NoError(t, suite.helper.DB.Model(&models.Chapter{}).Where("transcription_id = ?", job.ID).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
+
+func (suite *WorkflowTestSuite) TestTranslateJobIndexesTranslation() {
+	t := suite.T()
+	job := suite.completedJob()
+	service := &replyLLM{reply: "bonjour tout le monde"}
+	ragService := rag.NewRAGService(vectordb.NewMemoryStore(), embeddings.NewFakeEmbeddingService(), service)
+	require.NoError(t, ragService.StoreSummary(context.Background(), job.ID, "", "hello world"))
+
+	translation, err := workflow.TranslateJob(context.Background(), service, "test", ragService, true, job, "French")
+	require.NoError(t, err)
+	assert.True(t, translation.Indexed)
+	require.Len(t, translation.Segments, 1)
+	assert.Equal(t, "bonjour tout le monde", translation.Segments[0].Text)
+
+	hits, err := ragService.Search(context.Background(), nil, "bonjour tout le monde", 5, []string{job.ID})
+	require.NoError(t, err)
+	require.NotEmpty(t, hits)
+	assert.Equal(t, "French", hits[0].Language)
+	assert.Equal(t, "bonjour tout le monde", hits[0].Snippet)
+
+	// Translating again without indexing replaces the translation and removes it from RAG
+	_, err = workflow.TranslateJob(context.Background(), service, "test", ragService, false, job, "French")
+	require.NoError(t, err)
+	var translations []models.Translation
+	require.NoError(t, suite.helper.DB.Where("transcription_job_id = ?", job.ID).Find(&translations).Error)
+	require.Len(t, translations, 1)
+	assert.False(t, translations[0].Indexed)
+	hits, err = ragService.Search(context.Background(), nil, "bonjour tout le monde", 5, []string{job.ID})
+	require.NoError(t, err)
+	for _, hit := range hits {
+		assert.Empty(t, hit.Language)
+	}
+}
+
+func (suite *WorkflowTestSuite) TestRedactPII() {
+	t := suite.T()
+	job := suite.helper.CreateTestTranscriptionJob(t, "Support call")
+	transcript := `{"segments":[` +
+		`{"start":0,"end":4,"text":"Hi, this is Jane Doe, reach me at jane.doe@example.com."},` +
+		`{"start":4,"end":8,"text":"Jane's number is 555-123-4567 and the budget is 1,250,000."}]}`
+	job.Status = models.StatusCompleted
+	job.Transcript = &transcript
+	require.NoError(t, suite.helper.DB.Save(job).Error)
+
+	service := &replyLLM{reply: `{"names":["Jane Doe"]}`}
+	step := &workflow.RedactStep{LLM: service, Model: "test"}
+	_, err := step.Run(context.Background(), &workflow.RunContext{Job: job})
+	assert.ErrorIs(t, err, workflow.ErrSkipped)
+	output, err := step.Run(context.Background(), &workflow.RunContext{Job: job, Params: map[string]string{"redact_pii": "true"}})
+	require.NoError(t, err)
+	assert.Equal(t, "email: 1\nphone: 1\nname: 2", output)
+	assert.Contains(t, service.prompt, "Hi, this is Jane Doe")
+
+	var redaction models.Redaction
+	require.NoError(t, suite.helper.DB.Where("transcription_id = ?", job.ID).First(&redaction).Error)
+	assert.Equal(t, map[string]int{"email": 1, "phone": 1, "name": 2}, redaction.Counts)
+	assert.Contains(t, redaction.Transcript, "Hi, this is [NAME], reach me at [EMAIL].")
+	assert.Contains(t, redaction.Transcript, `"start":4`)
+	assert.NotContains(t, redaction.Transcript, "Jane")
+
+	// With redacted embedding, neither the transcript nor text derived from it is stored raw
+	ragService := rag.NewRAGService(vectordb.NewMemoryStore(), embeddings.NewFakeEmbeddingService(), service)
+	ragService.SetEmbedRedacted(true)
+	require.NoError(t, ragService.StoreSummary(context.Background(), job.ID, "Jane Doe called about the budget.", "Hi, this is Jane Doe, reach me at jane.doe@example.com."))
+	docs, err := ragService.RetrieveWithin(context.Background(), nil, "Jane Doe budget", 10, []string{job.ID})
+	require.NoError(t, err)
+	require.NotEmpty(t, docs)
+	for _, doc := range docs {
+		assert.NotContains(t, doc.Content, "Jane")
+		assert.NotContains(t, doc.Content, "example.com")
+	}
+	assert.Contains(t, docs[0].Content, "[NAME]")
+}
+
+func (suite *WorkflowTestSuite) TestScanWatchlists() {
+	t := suite.T()
+	job := suite.helper.CreateTestTranscriptionJob(t, "Sales call")
+	transcript := `{"segments":[` +
+		`{"start":0,"end":5,"speaker":"SPEAKER_00","text":"Thanks for calling, how can I help?"},` +
+		`{"start":65,"end":70,"speaker":"SPEAKER_01","text":"I would like a refnud for my order."},` +
+		`{"start":70,"end":75,"speaker":"SPEAKER_01","text":"Otherwise cancel the subscription please."}]}`
+	job.Status = models.StatusCompleted
+	job.Transcript = &transcript
+	require.NoError(t, suite.helper.DB.Save(job).Error)
+
+	step := &workflow.WatchlistStep{AllowPrivateWebhooks: true}
+	_, err := step.Run(context.Background(), &workflow.RunContext{Job: job})
+	assert.ErrorIs(t, err, workflow.ErrSkipped)
+
+	var received []notify.Event
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event notify.Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err == nil {
+			mu.Lock()
+			received = append(received, event)
+			mu.Unlock()
+		}
+	}))
+	defer server.Close()
+
+	churn := models.Watchlist{Name: "Churn", Terms: []string{"refund", "cancel my subscription"}, Fuzzy: true, Semantic: true, SimilarityThreshold: 0.5, WebhookURL: server.URL}
+	paused := models.Watchlist{Name: "Paused", Terms: []string{"thanks"}, Paused: true}
+	require.NoError(t, suite.helper.DB.Create(&churn).Error)
+	require.NoError(t, suite.helper.DB.Create(&paused).Error)
+	defer suite.helper.DB.Where("1 = 1").Delete(&models.Watchlist{})
+
+	step.RAG = rag.NewRAGService(vectordb.NewMemoryStore(), embeddings.NewFakeEmbeddingService(), &replyLLM{})
+	output, err := step.Run(context.Background(), &workflow.RunContext{Job: job})
+	require.NoError(t, err)
+	assert.Equal(t, "00:01:05 refund (Churn)\n00:01:10 cancel my subscription (Churn)", output)
+
+	var matches []models.WatchlistMatch
+	require.NoError(t, suite.helper.DB.Where("transcription_id = ?", job.ID).Order("start").Find(&matches).Error)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "fuzzy", matches[0].MatchType)
+	assert.Equal(t, "I would like a refnud for my order.", matches[0].Text)
+	assert.Equal(t, "SPEAKER_01", matches[0].Speaker)
+	assert.Equal(t, 70.0, matches[0].End)
+	assert.Equal(t, "semantic", matches[1].MatchType)
+	assert.Equal(t, churn.ID, matches[1].WatchlistID)
+
+	require.Len(t, received, 1)
+	assert.Equal(t, models.EventWatchlistMatched, received[0].Type)
+	assert.Equal(t, "Churn", received[0].Data["watchlist"])
+	assert.Equal(t, "2", received[0].Data["count"])
+	assert.Contains(t, received[0].Data["matches"], `"start":65`)
+
+	// Scanning again replaces the saved matches
+	_, err = step.Run(context.Background(), &workflow.RunContext{Job: job})
+	require.NoError(t, err)
+	var count int64
+	require.NoError(t, suite.helper.DB.Model(&models.WatchlistMatch{}).Where("transcription_id = ?", job.ID).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	// Lists' webhooks on private networks are refused unless allowed
+	step.AllowPrivateWebhooks = false
+	_, err = step.Run(context.Background(), &workflow.RunContext{Job: job})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "private networks")
+	mu.Lock()
+	assert.Len(t, received, 2)
+	mu.Unlock()
+}
+
+func (suite *WorkflowTestSuite) TestDeliverActionItems() {
+	t := suite.T()
+	job := suite.completedJob()
+	summary := "Quote revisions were agreed."
+	require.NoError(t, suite.helper.DB.Model(job).Update("summary", summary).Error)
+	job.Summary = &summary
+	at := 754.0
+	require.NoError(t, suite.helper.DB.Create(&models.ActionItem{TranscriptionID: job.ID, Task: "Send the revised quote", Owner: "Dana", SourceTime: &at}).Error)
+	require.NoError(t, suite.helper.DB.Create(&models.ActionItem{TranscriptionID: job.ID, Task: "Book the venue"}).Error)
+	require.NoError(t, suite.helper.DB.Create(&models.ActionItem{TranscriptionID: job.ID, Task: "Already done", Completed: true}).Error)
+
+	step := &workflow.DeliverActionItemsStep{PublicURL: "https://scriberr.example.com/", AllowPrivateWebhooks: true}
+	_, err := step.Run(context.Background(), &workflow.RunContext{Job: job})
+	assert.ErrorIs(t, err, workflow.ErrSkipped, "no integrations deliver automatically")
+
+	var mu sync.Mutex
+	var received []integrations.Task
+	failVenue := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Task integrations.Task `json:"task"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		mu.Lock()
+		defer mu.Unlock()
+		if payload.Task.Title == "Book the venue" && failVenue {
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+			return
+		}
+		received = append(received, payload.Task)
+		fmt.Fprintf(w, `{"id":"T-%d","url":"https://tasks.example.com/%d"}`, len(received), len(received))
+	}))
+	defer server.Close()
+
+	integration := models.TaskIntegration{Name: "Tracker", Provider: "webhook", WebhookURL: server.URL, AutoDeliver: true, Assignees: map[string]string{"dana": "U-7"}}
+	require.NoError(t, suite.helper.DB.Create(&integration).Error)
+	defer suite.helper.DB.Where("1 = 1").Delete(&models.TaskIntegration{})
+
+	_, err = step.Run(context.Background(), &workflow.RunContext{Job: job})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Tracker: webhook: error 503")
+	require.Len(t, received, 1)
+	assert.Equal(t, "Send the revised quote", received[0].Title)
+	assert.Equal(t, "U-7", received[0].Assignee)
+	assert.Equal(t, "https://scriberr.example.com/audio/"+job.ID+"?t=754", received[0].RecordingURL)
+	assert.Equal(t, "Workflow Job", received[0].RecordingTitle)
+	assert.Equal(t, summary, received[0].Summary)
+
+	// Re-running retries only the failed delivery
+	failVenue = false
+	output, err := step.Run(context.Background(), &workflow.RunContext{Job: job})
+	require.NoError(t, err)
+	assert.Equal(t, "- Tracker: T-2 https://tasks.example.com/2", output)
+	require.Len(t, received, 2)
+	assert.Equal(t, "Book the venue", received[1].Title)
+	assert.Empty(t, received[1].Assignee)
+
+	var deliveries []models.ActionItemDelivery
+	require.NoError(t, suite.helper.DB.Where("transcription_id = ?", job.ID).Order("external_id").Find(&deliveries).Error)
+	require.Len(t, deliveries, 2)
+	for _, delivery := range deliveries {
+		assert.Equal(t, models.DeliveryDelivered, delivery.Status)
+		assert.Empty(t, delivery.Error)
+	}
+
+	// Items extracted again aren't delivered twice
+	require.NoError(t, suite.helper.DB.Where("transcription_id = ?", job.ID).Delete(&models.ActionItem{}).Error)
+	require.NoError(t, suite.helper.DB.Create(&models.ActionItem{TranscriptionID: job.ID, Task: "send the revised quote "}).Error)
+	_, err = step.Run(context.Background(), &workflow.RunContext{Job: job})
+	require.NoError(t, err)
+	assert.Len(t, received, 2)
+}
+
+func TestWorkflowTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkflowTestSuite))
+}
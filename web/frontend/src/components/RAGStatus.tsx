@@ -8,6 +8,8 @@ import { useToast } from "./ui/toast";
 interface RAGStats {
 	status: string;
 	transcript_count: number;
+	indexed_count?: number;
+	missing_count?: number;
 	collection_name?: string;
 	message?: string;
 }
@@ -114,6 +116,7 @@ export function RAGStatus() {
 
 	const isActive = stats?.status === "active";
 	const transcriptCount = stats?.transcript_count || 0;
+	const missingCount = stats?.missing_count || 0;
 
 	return (
 		<Card>
@@ -171,6 +174,14 @@ export function RAGStatus() {
 								<div className="text-2xl font-bold text-gray-900 dark:text-gray-100 mt-1">
 									{transcriptCount}
 								</div>
+								{stats?.indexed_count !== undefined && (
+									<div className="text-sm text-gray-500 dark:text-gray-400 mt-1">
+										{stats.indexed_count} indexed
+										{missingCount > 0 && (
+											<span className="text-yellow-600 dark:text-yellow-400">, {missingCount} missing</span>
+										)}
+									</div>
+								)}
 							</div>
 							<Database className="h-8 w-8 text-blue-500 opacity-50" />
 						</div>